@@ -0,0 +1,110 @@
+// Command mock-platform serves fixture data over the HackerOne, BugCrowd and
+// ChaosDB APIs so full scans can be run locally without platform credentials.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/monitor-agent/internal/mockplatform"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		logrus.Errorf("Mock platform failed: %v", err)
+		os.Exit(1)
+	}
+}
+
+// run parses flags and serves the mock platform until interrupted
+func run(args []string) error {
+	fs := flag.NewFlagSet("mock-platform", flag.ExitOnError)
+	addr := fs.String("addr", ":8090", "listen address")
+	fixturesPath := fs.String("fixtures", "", "JSON fixtures file (default: built-in fixtures)")
+	printFixtures := fs.Bool("print-fixtures", false, "print the built-in fixtures as JSON and exit")
+	latency := fs.Duration("latency", 0, "delay added to every response")
+	jitter := fs.Duration("jitter", 0, "random extra delay of up to this much per response")
+	errorRate := fs.Float64("error-rate", 0, "fraction of requests (0-1) answered with --error-status")
+	errorStatus := fs.Int("error-status", http.StatusInternalServerError, "status code of injected errors (429 also sets Retry-After)")
+	apiKey := fs.String("api-key", "", "only accept this API key (default: accept any credentials)")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed for injected jitter and errors")
+	logLevel := fs.String("log-level", "info", "log level (debug logs every request)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *printFixtures {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(mockplatform.DefaultFixtures())
+	}
+
+	if *errorRate < 0 || *errorRate > 1 {
+		return fmt.Errorf("--error-rate must be between 0 and 1")
+	}
+	if *errorStatus < 400 || *errorStatus > 599 {
+		return fmt.Errorf("--error-status must be a 4xx or 5xx status code")
+	}
+
+	level, err := logrus.ParseLevel(*logLevel)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %w", err)
+	}
+	logrus.SetLevel(level)
+
+	fixtures := mockplatform.DefaultFixtures()
+	if *fixturesPath != "" {
+		if fixtures, err = mockplatform.LoadFixtures(*fixturesPath); err != nil {
+			return err
+		}
+	}
+
+	server := &http.Server{
+		Addr: *addr,
+		Handler: mockplatform.NewServer(mockplatform.Options{
+			Fixtures:    fixtures,
+			Latency:     *latency,
+			Jitter:      *jitter,
+			ErrorRate:   *errorRate,
+			ErrorStatus: *errorStatus,
+			APIKey:      *apiKey,
+			Seed:        *seed,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logrus.Infof("Mock platform listening on %s (%d HackerOne programs, %d BugCrowd programs, %d ChaosDB domains)",
+			*addr, len(fixtures.HackerOne), len(fixtures.BugCrowd), len(fixtures.Subdomains))
+		serveErr <- server.ListenAndServe()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("mock platform server failed: %w", err)
+		}
+		return nil
+	case sig := <-sigChan:
+		logrus.Infof("Received signal %v, shutting down mock platform...", sig)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down mock platform: %w", err)
+	}
+	return nil
+}
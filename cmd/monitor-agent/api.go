@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/monitor-agent/internal/api"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// runAPI dispatches the api subcommands
+func runAPI(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent api serve [flags]")
+	}
+
+	switch args[0] {
+	case "serve":
+		return runAPIServe(ctx, cfg, monitorService, args[1:])
+	default:
+		return fmt.Errorf("unknown api command: %s", args[0])
+	}
+}
+
+// runAPIServe serves the REST API until it receives SIGINT or SIGTERM
+func runAPIServe(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("api serve", flag.ExitOnError)
+	addr := fs.String("addr", cfg.HTTPAPI.ListenAddr, "listen address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cfg.HTTPAPI.Token == "" {
+		return fmt.Errorf("API_TOKEN is required to serve")
+	}
+
+	handler := api.NewServer(monitorService, cfg.HTTPAPI.Token)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logrus.Infof("API server listening on %s", *addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("API server failed: %w", err)
+		}
+		return nil
+	case sig := <-sigChan:
+		logrus.Infof("Received signal %v, shutting down API server...", sig)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down API server: %w", err)
+	}
+
+	logrus.Info("Waiting for triggered scans to finish...")
+	handler.Wait()
+	return nil
+}
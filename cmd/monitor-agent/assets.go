@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+)
+
+// maxTagLength is the longest tag asset_tags stores
+const maxTagLength = 100
+
+// dryRunSampleSize is the number of matching assets a dry run lists
+const dryRunSampleSize = 20
+
+// runAssets dispatches the assets subcommands
+func runAssets(ctx context.Context, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent assets update --query QUERY [flags]")
+	}
+
+	switch args[0] {
+	case "update":
+		return runAssetsUpdate(ctx, db, args[1:])
+	default:
+		return fmt.Errorf("unknown assets command: %s", args[0])
+	}
+}
+
+// runAssetsUpdate tags, untags, ignores or sets the status of every asset
+// matching a query
+func runAssetsUpdate(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("assets update", flag.ExitOnError)
+	queryText := fs.String("query", "", "assets to update, e.g. 'domain:*.old-acquisition.com -tag:keep'")
+	tags := fs.String("tag", "", "comma-separated tags to add")
+	untags := fs.String("untag", "", "comma-separated tags to remove")
+	ignore := fs.Bool("ignore", false, "ignore the assets: no more sweeps or reports")
+	unignore := fs.Bool("unignore", false, "stop ignoring the assets")
+	status := fs.String("status", "", "set the status: active, inactive or quarantined")
+	dryRun := fs.Bool("dry-run", false, "only show which assets match")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *queryText == "" {
+		return fmt.Errorf("--query is required (fields: %s)", strings.Join(database.AssetQueryFields(), ", "))
+	}
+	query, err := database.ParseAssetQuery(*queryText)
+	if err != nil {
+		return err
+	}
+
+	update := &database.AssetUpdate{TagSource: "manual", Status: *status}
+	if update.AddTags, err = parseTags(*tags); err != nil {
+		return err
+	}
+	if update.RemoveTags, err = parseTags(*untags); err != nil {
+		return err
+	}
+	if *ignore && *unignore {
+		return fmt.Errorf("--ignore and --unignore cannot be combined")
+	}
+	if *ignore || *unignore {
+		update.Ignored = ignore
+	}
+	if *status != "" && *status != "active" && *status != "inactive" && *status != database.AssetStatusQuarantined {
+		return fmt.Errorf("--status must be active, inactive or quarantined")
+	}
+	if !*dryRun && len(update.AddTags) == 0 && len(update.RemoveTags) == 0 && update.Ignored == nil && update.Status == "" {
+		return fmt.Errorf("nothing to update: pass --tag, --untag, --ignore, --unignore or --status")
+	}
+
+	assetRepo := database.NewAssetRepository(db)
+
+	if *dryRun {
+		count, err := assetRepo.CountAssetsByQuery(ctx, query)
+		if err != nil {
+			return err
+		}
+		assets, err := assetRepo.FindAssetsByQuery(ctx, query, dryRunSampleSize)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d assets match %q\n", count, *queryText)
+		for _, asset := range assets {
+			fmt.Printf("  - %s (%s, %s)\n", asset.URL, asset.Status, asset.ProgramURL)
+		}
+		if count > int64(len(assets)) {
+			fmt.Printf("  ... and %d more\n", count-int64(len(assets)))
+		}
+		return nil
+	}
+
+	result, err := assetRepo.UpdateAssetsByQuery(ctx, query, update)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Assets Updated ===\n")
+	fmt.Printf("Matched:      %d\n", result.Matched)
+	if len(update.AddTags) > 0 {
+		fmt.Printf("Tags added:   %d\n", result.TagsAdded)
+	}
+	if len(update.RemoveTags) > 0 {
+		fmt.Printf("Tags removed: %d\n", result.TagsRemoved)
+	}
+	if update.Status != "" || update.Ignored != nil {
+		fmt.Printf("Updated:      %d\n", result.Updated)
+	}
+
+	return nil
+}
+
+// parseTags splits a comma-separated tag list
+func parseTags(value string) ([]string, error) {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
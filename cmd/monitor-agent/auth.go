@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/probeauth"
+)
+
+// runAuth dispatches the probe auth profile subcommands
+func runAuth(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent auth <set|show|delete> [flags]")
+	}
+
+	switch args[0] {
+	case "set":
+		return runAuthSet(ctx, cfg, db, args[1:])
+	case "show":
+		return runAuthShow(ctx, cfg, db, args[1:])
+	case "delete":
+		return runAuthDelete(ctx, db, args[1:])
+	default:
+		return fmt.Errorf("unknown auth command: %s", args[0])
+	}
+}
+
+// probeAuthSealer creates the sealer for the configured PROBE_AUTH_KEY
+func probeAuthSealer(cfg *config.Config) (*probeauth.Sealer, error) {
+	if cfg.ProbeAuth.Key == "" {
+		return nil, fmt.Errorf("PROBE_AUTH_KEY is required to store or read probe auth profiles")
+	}
+
+	key, err := probeauth.ParseKey(cfg.ProbeAuth.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROBE_AUTH_KEY: %w", err)
+	}
+	return probeauth.NewSealer(key)
+}
+
+// runAuthSet stores a program's probe auth profile, replacing any previous one
+func runAuthSet(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	profile := &probeauth.Profile{}
+
+	fs := flag.NewFlagSet("auth set", flag.ExitOnError)
+	programURL := fs.String("program", "", "program URL")
+	file := fs.String("file", "", "read the profile from a JSON file with headers, cookies, basic_user and basic_password")
+	basic := fs.String("basic", "", "basic auth credentials as user:password")
+	fs.Func("header", "request header as 'Name: value' (repeatable)", func(value string) error {
+		name, headerValue, found := strings.Cut(value, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("header must be 'Name: value'")
+		}
+		if profile.Headers == nil {
+			profile.Headers = make(map[string]string)
+		}
+		profile.Headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		return nil
+	})
+	fs.Func("cookie", "cookie as name=value (repeatable)", func(value string) error {
+		name, cookieValue, found := strings.Cut(value, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("cookie must be name=value")
+		}
+		if profile.Cookies == nil {
+			profile.Cookies = make(map[string]string)
+		}
+		profile.Cookies[strings.TrimSpace(name)] = cookieValue
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file != "" {
+		if !profile.Empty() || *basic != "" {
+			return fmt.Errorf("--file cannot be combined with --header, --cookie or --basic")
+		}
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", *file, err)
+		}
+		if err := json.Unmarshal(data, profile); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", *file, err)
+		}
+	}
+	if *basic != "" {
+		user, password, found := strings.Cut(*basic, ":")
+		if !found {
+			return fmt.Errorf("--basic must be user:password")
+		}
+		profile.BasicUser, profile.BasicPassword = user, password
+	}
+	if profile.Empty() {
+		return fmt.Errorf("give at least one --header, --cookie or --basic, or a --file")
+	}
+
+	sealer, err := probeAuthSealer(cfg)
+	if err != nil {
+		return err
+	}
+
+	program, err := resolveQuotaProgram(ctx, db, *programURL)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := sealer.Seal(profile)
+	if err != nil {
+		return err
+	}
+	if err := database.NewProbeAuthRepository(db).SaveProbeAuthProfile(ctx, &database.ProbeAuthProfile{
+		ProgramID:     program.ID,
+		SealedProfile: sealed,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Stored probe auth profile for %s (%d headers, %d cookies, basic auth: %v)\n",
+		program.Name, len(profile.Headers), len(profile.Cookies), profile.BasicUser != "" || profile.BasicPassword != "")
+	return nil
+}
+
+// runAuthShow prints a program's probe auth profile with its values redacted
+func runAuthShow(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("auth show", flag.ExitOnError)
+	programURL := fs.String("program", "", "program URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	program, err := resolveQuotaProgram(ctx, db, *programURL)
+	if err != nil {
+		return err
+	}
+
+	stored, err := database.NewProbeAuthRepository(db).GetProbeAuthProfile(ctx, program.ID)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		fmt.Printf("%s has no probe auth profile\n", program.Name)
+		return nil
+	}
+
+	sealer, err := probeAuthSealer(cfg)
+	if err != nil {
+		return err
+	}
+	profile, err := sealer.Open(stored.SealedProfile)
+	if err != nil {
+		return err
+	}
+	redacted := profile.Redacted()
+
+	fmt.Printf("\n=== Probe Auth Profile: %s ===\n", program.Name)
+	fmt.Printf("Updated: %s\n", stored.UpdatedAt.Format("2006-01-02 15:04:05"))
+	for _, name := range sortedKeys(redacted.Headers) {
+		fmt.Printf("Header:  %s: %s\n", name, redacted.Headers[name])
+	}
+	for _, name := range sortedKeys(redacted.Cookies) {
+		fmt.Printf("Cookie:  %s=%s\n", name, redacted.Cookies[name])
+	}
+	if redacted.BasicUser != "" || redacted.BasicPassword != "" {
+		fmt.Printf("Basic:   %s:%s\n", redacted.BasicUser, redacted.BasicPassword)
+	}
+
+	return nil
+}
+
+// runAuthDelete removes a program's probe auth profile
+func runAuthDelete(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("auth delete", flag.ExitOnError)
+	programURL := fs.String("program", "", "program URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	program, err := resolveQuotaProgram(ctx, db, *programURL)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := database.NewProbeAuthRepository(db).DeleteProbeAuthProfile(ctx, program.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		fmt.Printf("%s has no probe auth profile\n", program.Name)
+		return nil
+	}
+
+	fmt.Printf("Deleted probe auth profile for %s\n", program.Name)
+	return nil
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
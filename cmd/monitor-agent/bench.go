@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/bench"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+)
+
+// runBench measures database write, probe and rate limiter throughput on this
+// host with the current settings and prints recommended changes
+func runBench(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	rows := fs.Int("rows", 2000, "assets to upsert in a rolled back transaction; 0 skips the database")
+	probes := fs.Int("probes", 200, "URLs of a local server to probe; 0 skips probing")
+	limiterCalls := fs.Int("limiter-calls", 100000, "rate limiter calls to time; 0 skips the rate limiter")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	result := &bench.Result{}
+
+	if *rows > 0 {
+		fmt.Printf("Upserting %d assets...\n", *rows)
+		elapsed, err := database.NewBenchRepository(db).MeasureAssetUpserts(ctx, *rows)
+		if err != nil {
+			return err
+		}
+		result.DBRows, result.DBDuration = *rows, elapsed
+	}
+
+	if *probes > 0 {
+		fmt.Printf("Probing %d local URLs...\n", *probes)
+		prober := httpx.NewClient(&httpx.ProbeConfig{
+			Timeout:         cfg.Discovery.HTTPX.Timeout,
+			Concurrency:     cfg.Discovery.HTTPX.Concurrency,
+			RateLimit:       cfg.Discovery.HTTPX.RateLimit,
+			FollowRedirects: cfg.Discovery.HTTPX.FollowRedirects,
+			MaxRedirects:    cfg.Discovery.HTTPX.MaxRedirects,
+			Debug:           cfg.Discovery.HTTPX.Debug,
+			IPVersion:       cfg.Discovery.HTTPX.IPVersion,
+			TLSChecks:       cfg.Discovery.HTTPX.TLSChecks,
+		})
+		responses, elapsed, err := bench.MeasureProbes(ctx, prober, *probes)
+		if err != nil {
+			return err
+		}
+		result.Probes, result.ProbeResponses, result.ProbeDuration = *probes, responses, elapsed
+	}
+
+	if *limiterCalls > 0 {
+		result.LimiterCalls, result.LimiterDuration = *limiterCalls, bench.MeasureLimiter(*limiterCalls)
+	}
+
+	fmt.Printf("\n=== Benchmark ===\n")
+	if result.DBRows > 0 {
+		fmt.Printf("Database upserts:    %8.0f assets/s (%d in %s)\n", result.DBRowsPerSecond(), result.DBRows, result.DBDuration.Round(time.Millisecond))
+	}
+	if result.Probes > 0 {
+		fmt.Printf("HTTPX probes:        %8.0f probes/s (%d/%d responses in %s, concurrency %d, rate limit %d/s)\n",
+			result.ProbesPerSecond(), result.ProbeResponses, result.Probes, result.ProbeDuration.Round(time.Millisecond),
+			cfg.Discovery.HTTPX.Concurrency, cfg.Discovery.HTTPX.RateLimit)
+	}
+	if result.LimiterCalls > 0 {
+		fmt.Printf("Rate limiter calls:  %8s each (%d calls)\n", result.LimiterOverhead(), result.LimiterCalls)
+	}
+
+	recommendations := bench.Recommend(bench.Settings{
+		HTTPXConcurrency: cfg.Discovery.HTTPX.Concurrency,
+		HTTPXRateLimit:   cfg.Discovery.HTTPX.RateLimit,
+		WritesPerSecond:  cfg.Database.WritesPerSecond,
+	}, result)
+
+	fmt.Printf("\n=== Recommendations ===\n")
+	if len(recommendations) == 0 {
+		fmt.Printf("The current settings suit this host\n")
+	}
+	for _, recommendation := range recommendations {
+		fmt.Printf("- %s\n", recommendation)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runCanary checks the configured canary hostnames now, failing when any of
+// them did not resolve or probe as expected
+func runCanary(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService) error {
+	if len(cfg.Canary.Targets) == 0 {
+		return fmt.Errorf("no canaries configured; set CANARY_TARGETS to hostnames you control")
+	}
+
+	results, err := monitorService.CheckCanaries(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%-40s  %-8s  %-16s  %-12s  %s\n", "HOSTNAME", "EXPECTED", "IP", "LIVENESS", "RESULT")
+	var failed int
+	for _, result := range results {
+		expected := "any"
+		if result.Target.StatusCode != 0 {
+			expected = fmt.Sprintf("%d", result.Target.StatusCode)
+		}
+		outcome := "ok"
+		if result.StatusCode != 0 {
+			outcome = fmt.Sprintf("ok (%d)", result.StatusCode)
+		}
+		if !result.Passed() {
+			failed++
+			outcome = "FAILED: " + result.Failure
+		}
+		fmt.Printf("%-40s  %-8s  %-16s  %-12s  %s\n", result.Target.Hostname, expected, result.IP, result.Liveness, outcome)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d canaries failed", failed, len(results))
+	}
+	fmt.Printf("\nAll %d canaries passed\n", len(results))
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runClusters dispatches the response cluster subcommands
+func runClusters(ctx context.Context, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent clusters <build|list|show> [flags]")
+	}
+
+	switch args[0] {
+	case "build":
+		return runClustersBuild(ctx, monitorService)
+	case "list":
+		return runClustersList(ctx, monitorService, args[1:])
+	case "show":
+		return runClustersShow(ctx, db, monitorService, args[1:])
+	default:
+		return fmt.Errorf("unknown clusters command: %s", args[0])
+	}
+}
+
+// runClustersBuild regroups live assets by their latest responses now,
+// instead of after the next full scan
+func runClustersBuild(ctx context.Context, monitorService *service.MonitorService) error {
+	result, err := monitorService.ClusterResponses(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Fingerprinted %d stored responses\n", result.Fingerprinted)
+	fmt.Printf("Clustered %d of %d live assets into %d groups of near-identical responses\n",
+		result.Clustered, result.Assets, result.Clusters)
+	return nil
+}
+
+// runClustersList lists the largest clusters with their representative asset
+func runClustersList(ctx context.Context, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("clusters list", flag.ExitOnError)
+	minSize := fs.Int("min-size", 2, "only list clusters of at least this many assets")
+	limit := fs.Int("limit", 20, "maximum number of clusters to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	clusters, err := monitorService.GetClusters(ctx, *minSize, *limit)
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		fmt.Println("No response clusters; run `monitor-agent clusters build` after a scan")
+		return nil
+	}
+
+	fmt.Printf("\n=== Response Clusters ===\n")
+	fmt.Printf("%-36s %6s %8s %6s  %s\n", "ID", "ASSETS", "PROGRAMS", "STATUS", "REPRESENTATIVE")
+	for _, c := range clusters {
+		fmt.Printf("%-36s %6d %8d %6d  %s\n", c.ID, c.Size, c.Programs, c.StatusCode, c.RepresentativeURL)
+	}
+	fmt.Printf("\nList a cluster's assets with `monitor-agent clusters show <id>`; unclustered assets match the query cluster:none\n")
+	return nil
+}
+
+// runClustersShow prints a cluster and the assets in it
+func runClustersShow(ctx context.Context, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("clusters show", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "maximum number of assets to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: monitor-agent clusters show [--limit 50] <cluster id>")
+	}
+
+	id, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid cluster ID %q: %w", fs.Arg(0), err)
+	}
+
+	c, err := monitorService.GetCluster(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	query, err := database.ParseAssetQuery("cluster:" + id.String())
+	if err != nil {
+		return err
+	}
+	assets, err := database.NewAssetRepository(db).FindAssetsByQuery(ctx, query, *limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Response Cluster %s ===\n", c.ID)
+	fmt.Printf("Representative: %s (status %d)\n", c.RepresentativeURL, c.StatusCode)
+	fmt.Printf("Assets:         %d in %d programs\n\n", c.Size, c.Programs)
+	for _, asset := range assets {
+		marker := " "
+		if asset.ID == c.RepresentativeAssetID {
+			marker = "*"
+		}
+		fmt.Printf("%s %-50s %s\n", marker, asset.URL, asset.ProgramURL)
+	}
+	if c.Size > len(assets) {
+		fmt.Printf("... and %d more\n", c.Size-len(assets))
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/cmdb"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+)
+
+// runCMDB dispatches the cmdb subcommands
+func runCMDB(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent cmdb reconcile [flags]")
+	}
+
+	switch args[0] {
+	case "reconcile":
+		return runCMDBReconcile(ctx, cfg, db, args[1:])
+	default:
+		return fmt.Errorf("unknown cmdb command: %s", args[0])
+	}
+}
+
+// runCMDBReconcile compares the active assets of active programs with the
+// company's inventory and reports the ones it does not know
+func runCMDBReconcile(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("cmdb reconcile", flag.ExitOnError)
+	programURL := fs.String("program", "", "only reconcile the assets of this program URL")
+	csvPath := fs.String("csv", cfg.CMDB.CSV, "inventory CSV export")
+	format := fs.String("format", "text", "output format: text, csv (shadow assets) or json (whole report)")
+	out := fs.String("out", "", "write the report to a file instead of stdout")
+	excludeSources := excludeSourceFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "text" && *format != "csv" && *format != "json" {
+		return fmt.Errorf("--format must be text, csv or json")
+	}
+	if *csvPath == "" && cfg.CMDB.ServiceNowURL == "" {
+		return fmt.Errorf("no inventory configured (pass --csv, or set CMDB_CSV or CMDB_SERVICENOW_URL)")
+	}
+
+	entries, err := loadInventory(ctx, cfg, *csvPath)
+	if err != nil {
+		return err
+	}
+
+	assets, err := reconcileAssets(ctx, db, *programURL)
+	if err != nil {
+		return err
+	}
+	assets = database.ExcludeSources(assets, excludeSources())
+
+	report := cmdb.Reconcile(assets, cmdb.NewInventory(entries), time.Now())
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "csv":
+		err = cmdb.WriteCSV(w, report)
+	case "json":
+		err = cmdb.WriteJSON(w, report)
+	default:
+		printReconciliation(w, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		fmt.Printf("%d of %d assets are missing from the inventory, report written to %s\n", len(report.Shadow), report.Assets, *out)
+	}
+	return nil
+}
+
+// loadInventory reads the entries of every configured inventory
+func loadInventory(ctx context.Context, cfg *config.Config, csvPath string) ([]cmdb.Entry, error) {
+	var entries []cmdb.Entry
+
+	if csvPath != "" {
+		f, err := os.Open(csvPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open inventory CSV: %w", err)
+		}
+		defer f.Close()
+
+		csvEntries, err := cmdb.ReadCSV(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", csvPath, err)
+		}
+		entries = append(entries, csvEntries...)
+	}
+
+	if cfg.CMDB.ServiceNowURL != "" {
+		client := cmdb.NewServiceNowClient(&cmdb.ServiceNowConfig{
+			URL:           cfg.CMDB.ServiceNowURL,
+			Username:      cfg.CMDB.ServiceNowUser,
+			Password:      cfg.CMDB.ServiceNowPassword,
+			Table:         cfg.CMDB.ServiceNowTable,
+			Timeout:       cfg.HTTP.Timeout,
+			RetryAttempts: cfg.HTTP.RetryAttempts,
+			RetryDelay:    cfg.HTTP.RetryDelay,
+		})
+		serviceNowEntries, err := client.Entries(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, serviceNowEntries...)
+	}
+
+	return entries, nil
+}
+
+// reconcileAssets returns the active assets of one program, or of every
+// active program, leaving out ignored ones
+func reconcileAssets(ctx context.Context, db *sqlx.DB, programURL string) ([]*database.Asset, error) {
+	var programs []*database.Program
+	if programURL != "" {
+		program, err := resolveQuotaProgram(ctx, db, programURL)
+		if err != nil {
+			return nil, err
+		}
+		programs = []*database.Program{program}
+	} else {
+		var err error
+		programs, err = database.NewProgramRepository(db).GetAllActivePrograms(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	assetRepo := database.NewAssetRepository(db)
+	var assets []*database.Asset
+	for _, program := range programs {
+		programAssets, err := assetRepo.GetAssetsByProgramID(ctx, program.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, asset := range programAssets {
+			if asset.Status == "active" && !asset.Ignored {
+				assets = append(assets, asset)
+			}
+		}
+	}
+
+	return assets, nil
+}
+
+// printReconciliation prints a reconciliation report for people
+func printReconciliation(w io.Writer, report *cmdb.Report) {
+	fmt.Fprintf(w, "\n=== CMDB Reconciliation ===\n")
+	fmt.Fprintf(w, "Inventory entries: %d\n", report.Inventory)
+	fmt.Fprintf(w, "Assets compared:   %d\n", report.Assets)
+	fmt.Fprintf(w, "Known by host:     %d\n", report.KnownByHost)
+	fmt.Fprintf(w, "Known by IP:       %d\n", report.KnownByIP)
+	fmt.Fprintf(w, "Shadow assets:     %d\n", len(report.Shadow))
+
+	if len(report.Shadow) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nAssets missing from the inventory:\n")
+	for _, asset := range report.Shadow {
+		liveness := asset.Liveness
+		if liveness == "" {
+			liveness = "unprobed"
+		}
+		fmt.Fprintf(w, "  - %s (%s, %s) first found %s by %s in %s\n",
+			asset.URL,
+			addressOrNone(asset.IP, asset.IPv6),
+			liveness,
+			asset.DiscoveredAt.Format("2006-01-02"),
+			asset.FirstSource,
+			asset.ProgramURL)
+	}
+}
+
+// addressOrNone returns the IPv4 address, else the IPv6 one, else "no address"
+func addressOrNone(ipv4, ipv6 string) string {
+	switch {
+	case ipv4 != "":
+		return ipv4
+	case ipv6 != "":
+		return ipv6
+	default:
+		return "no address"
+	}
+}
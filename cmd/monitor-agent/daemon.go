@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/cron"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// runDaemon runs the agent's background work until it receives SIGINT or SIGTERM
+func runDaemon(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	sweepBudget := fs.Int("sweep-requests-per-hour", cfg.Daemon.SweepRequestsPerHour, "probe budget of the liveness sweep (0 disables it)")
+	sweepBatch := fs.Int("sweep-batch-size", cfg.Daemon.SweepBatchSize, "assets re-probed per sweep batch")
+	watchlistInterval := fs.Duration("watchlist-interval", cfg.Daemon.WatchlistInterval, "how often watched hostnames are checked (0 disables it)")
+	scanSchedule := fs.String("schedule", cfg.Daemon.ScanSchedule, "cron expression full scans run on, e.g. '0 3 * * *' (empty disables them)")
+	watchCTLog := fs.Bool("ctlog", cfg.Discovery.CTLog.Enabled, "follow certificate transparency logs for new hostnames below program scopes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *sweepBudget < 0 || *sweepBatch <= 0 {
+		return fmt.Errorf("--sweep-requests-per-hour must not be negative and --sweep-batch-size must be greater than 0")
+	}
+	if *watchlistInterval < 0 {
+		return fmt.Errorf("--watchlist-interval must not be negative")
+	}
+	var schedule *cron.Schedule
+	if *scanSchedule != "" {
+		var err error
+		if schedule, err = cron.Parse(*scanSchedule); err != nil {
+			return fmt.Errorf("invalid --schedule: %w", err)
+		}
+	}
+	if *sweepBudget > 0 && cfg.App.Passive {
+		// Watched hostnames are still resolved, which sends nothing to targets
+		logrus.Info("Passive mode: the liveness sweep probes assets and is disabled")
+		*sweepBudget = 0
+	}
+	if *sweepBudget == 0 && *watchlistInterval == 0 && schedule == nil && !*watchCTLog {
+		return fmt.Errorf("nothing to run: the liveness sweep, watchlist checks, scheduled scans and certificate transparency watch are disabled")
+	}
+
+	defer serveMetrics(ctx, cfg, monitorService)()
+	abortStaleScans(ctx, monitorService)
+
+	// Scans interrupted by shutdown are recorded as aborted
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(service.ErrShutdown)
+
+	var workers []func(context.Context) error
+	if *sweepBudget > 0 {
+		workers = append(workers, func(ctx context.Context) error {
+			return monitorService.RunSweep(ctx, *sweepBudget, *sweepBatch)
+		})
+	}
+	if *watchlistInterval > 0 {
+		workers = append(workers, func(ctx context.Context) error {
+			return monitorService.RunWatchlist(ctx, *watchlistInterval)
+		})
+	}
+	if schedule != nil {
+		workers = append(workers, func(ctx context.Context) error {
+			return monitorService.RunScheduledScans(ctx, schedule)
+		})
+	}
+	if *watchCTLog {
+		workers = append(workers, func(ctx context.Context) error {
+			return monitorService.RunCTLogWatch(ctx)
+		})
+	}
+
+	done := make(chan error, len(workers))
+	for _, worker := range workers {
+		go func() {
+			done <- worker(ctx)
+		}()
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	var firstErr error
+	select {
+	case firstErr = <-done:
+		workers = workers[1:]
+	case sig := <-sigChan:
+		logrus.Infof("Received signal %v, shutting down daemon...", sig)
+	}
+
+	// Stop the remaining work, including a scan in progress, and wait for
+	// it to record where it stopped
+	cancel(service.ErrShutdown)
+	for range workers {
+		if err := <-done; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
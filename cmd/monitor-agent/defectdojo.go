@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/defectdojo"
+)
+
+// runDefectDojo dispatches the defectdojo subcommands
+func runDefectDojo(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent defectdojo push [flags]")
+	}
+
+	switch args[0] {
+	case "push":
+		return runDefectDojoPush(ctx, cfg, db, args[1:])
+	default:
+		return fmt.Errorf("unknown defectdojo command: %s", args[0])
+	}
+}
+
+// runDefectDojoPush exports finished scans that have not been exported yet
+func runDefectDojoPush(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("defectdojo push", flag.ExitOnError)
+	programURL := fs.String("program", "", "only export scans of this program URL")
+	limit := fs.Int("limit", 50, "maximum number of scans to export")
+	excludeSources := excludeSourceFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cfg.DefectDojo.URL == "" {
+		return fmt.Errorf("no DefectDojo instance configured (set DEFECTDOJO_URL and DEFECTDOJO_API_KEY)")
+	}
+	if *limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+
+	var programID *uuid.UUID
+	if *programURL != "" {
+		program, err := resolveQuotaProgram(ctx, db, *programURL)
+		if err != nil {
+			return err
+		}
+		programID = &program.ID
+	}
+
+	client := defectdojo.NewClient(&defectdojo.ClientConfig{
+		URL:           cfg.DefectDojo.URL,
+		APIKey:        cfg.DefectDojo.APIKey,
+		Timeout:       cfg.HTTP.Timeout,
+		RetryAttempts: cfg.HTTP.RetryAttempts,
+		RetryDelay:    cfg.HTTP.RetryDelay,
+	})
+	exporter := defectdojo.NewExporter(db, client, cfg.DefectDojo.ProductType, excludeSources())
+
+	startTime := time.Now()
+	summary, err := exporter.Export(ctx, programID, *limit)
+	if err != nil {
+		return fmt.Errorf("DefectDojo export failed after %d scans: %w", summary.Scans, err)
+	}
+
+	fmt.Printf("\n=== DefectDojo Export Complete ===\n")
+	fmt.Printf("Server:           %s\n", cfg.DefectDojo.URL)
+	fmt.Printf("Scans exported:   %d\n", summary.Scans)
+	fmt.Printf("Products created: %d\n", summary.ProductsCreated)
+	fmt.Printf("New endpoints:    %d\n", summary.Endpoints)
+	fmt.Printf("Findings sent:    %d\n", summary.Findings)
+	fmt.Printf("Duration:         %v\n", time.Since(startTime).Round(time.Millisecond))
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runDiff prints what changed in a program's assets between its latest
+// completed scan, or the given scan, and the scan before it
+func runDiff(ctx context.Context, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	scanRef := fs.String("scan", "", "scan ID to show the changes of instead of the latest completed scan")
+	change := fs.String("change", "", "only show changes of a kind: added, removed or changed")
+	asJSON := fs.Bool("json", false, "print the diff as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: monitor-agent diff [--scan ID] [--change added|removed|changed] [--json] <program handle or URL>")
+	}
+	if *change != "" && *change != database.AssetAdded && *change != database.AssetRemoved && *change != database.AssetChanged {
+		return fmt.Errorf("--change must be added, removed or changed")
+	}
+
+	scanID := uuid.Nil
+	if *scanRef != "" {
+		var err error
+		if scanID, err = uuid.Parse(*scanRef); err != nil {
+			return fmt.Errorf("invalid scan ID: %s", *scanRef)
+		}
+	}
+
+	program, err := resolveProgram(ctx, db, monitorService, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	diff, err := monitorService.GetAssetDiff(ctx, program, scanID)
+	if err != nil {
+		return err
+	}
+	if *change != "" {
+		var filtered []*database.AssetChange
+		for _, c := range diff.Changes {
+			if c.Change == *change {
+				filtered = append(filtered, c)
+			}
+		}
+		diff.Changes = filtered
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+
+	printAssetDiff(program, diff)
+	return nil
+}
+
+// printAssetDiff prints a diff's changes grouped by kind
+func printAssetDiff(program *database.Program, diff *service.AssetDiff) {
+	fmt.Printf("\n=== Asset Changes: %s ===\n", program.Name)
+	fmt.Printf("Scan:     %s (%s)\n", diff.Scan.ID, diff.Scan.StartedAt.Local().Format(time.RFC3339))
+	if diff.Previous == nil {
+		fmt.Println("\nThe scan was not compared with a previous scan: it is the program's first completed scan,")
+		fmt.Println("it continued a timed-out scan, or it ran before asset changes were recorded")
+		return
+	}
+	fmt.Printf("Compared: %s (%s)\n", diff.Previous.ID, diff.Previous.StartedAt.Local().Format(time.RFC3339))
+	fmt.Printf("Added: %d  Removed: %d  Changed: %d\n",
+		diff.Count(database.AssetAdded), diff.Count(database.AssetRemoved), diff.Count(database.AssetChanged))
+
+	if len(diff.Changes) == 0 {
+		fmt.Println("\nNo changes")
+		return
+	}
+
+	fmt.Println()
+	for _, c := range diff.Changes {
+		switch c.Change {
+		case database.AssetAdded:
+			fmt.Printf("+ %s\n", c.URL)
+		case database.AssetRemoved:
+			fmt.Printf("- %s\n", c.URL)
+		default:
+			fmt.Printf("~ %s  %s: %s -> %s\n", c.URL, c.Field, displayValue(c.OldValue), displayValue(c.NewValue))
+		}
+	}
+}
+
+// displayValue shows an empty field value as a dash
+func displayValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
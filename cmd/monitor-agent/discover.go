@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runDiscover runs discovery against ad-hoc domains without any bug bounty platform
+func runDiscover(ctx context.Context, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("discover", flag.ExitOnError)
+	programName := fs.String("program", platforms.ManualPlatformName, "name of the manual program the assets are stored under")
+	file := fs.String("file", "", "read domains from a file, one per line (# starts a comment)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	domains := fs.Args()
+	if *file != "" {
+		fileDomains, err := readDomainFile(*file)
+		if err != nil {
+			return err
+		}
+		domains = append(domains, fileDomains...)
+	}
+
+	if len(domains) == 0 {
+		return fmt.Errorf("usage: monitor-agent discover [--program NAME] [--file PATH] <domain>...")
+	}
+	if strings.TrimSpace(*programName) == "" {
+		return fmt.Errorf("--program must not be empty")
+	}
+	// Platform programs are never matched by manual discovery; a program URL
+	// here would silently create a manual program of that name
+	if _, _, err := platforms.ParseProgramURL(*programName); err == nil {
+		return fmt.Errorf("--program %s is a platform program, add it with: monitor-agent programs add %s", *programName, *programName)
+	}
+
+	scan, err := monitorService.DiscoverDomains(ctx, *programName, domains)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Discovery Complete ===\n")
+	fmt.Printf("Program:       %s (manual://%s)\n", *programName, *programName)
+	fmt.Printf("Scan:          %s\n", scan.ID)
+	fmt.Printf("Status:        %s\n", scan.Status)
+	fmt.Printf("Assets seen:   %d\n", scan.AssetsSeen)
+	fmt.Printf("Total assets:  %d\n", scan.AssetsFound)
+	if scan.Error != "" {
+		fmt.Printf("Error:         %s\n", scan.Error)
+	}
+
+	return nil
+}
+
+// readDomainFile reads domains from a file, skipping blank lines and comments
+func readDomainFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open domain file: %w", err)
+	}
+	defer f.Close()
+
+	var domains []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line != "" {
+			domains = append(domains, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read domain file: %w", err)
+	}
+
+	return domains, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/export"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runExport writes assets as JSON Lines, CSV or a URL list, to pipe them into
+// other tools or open them in a spreadsheet
+func runExport(ctx context.Context, cfg *config.Config, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "txt", "output format: json (JSON Lines), csv or txt (one URL per line)")
+	programRef := fs.String("program", "", "only export the assets of this program, by handle (acme or hackerone/acme) or program URL")
+	source := fs.String("source", "", "only export primary (in scope) or secondary (discovered) assets")
+	responses := fs.Bool("responses", false, "add the status code, final URL and response time of the latest stored response")
+	all := fs.Bool("all", false, "also export assets no longer seen (status inactive)")
+	changed := fs.Bool("changed", false, "only export assets whose content changed since their program's latest scan started")
+	out := fs.String("out", "", "write the assets to a file instead of stdout")
+	excludeSources := excludeSourceFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if !slices.Contains(export.Formats, *format) {
+		return fmt.Errorf("--format must be one of %s", strings.Join(export.Formats, ", "))
+	}
+	if *source != "" && *source != "primary" && *source != "secondary" {
+		return fmt.Errorf("--source must be primary or secondary")
+	}
+
+	filter := &database.ExportFilter{Source: *source, IncludeInactive: *all, Responses: *responses, ContentChanged: *changed}
+	if *programRef != "" {
+		program, err := resolveProgram(ctx, db, monitorService, strings.TrimSpace(*programRef))
+		if err != nil {
+			return err
+		}
+		filter.ProgramID = &program.ID
+	}
+
+	assets, err := database.NewExportRepository(db).GetExportAssets(ctx, filter)
+	if err != nil {
+		return err
+	}
+	records := export.NewRecords(export.ExcludeSources(assets, excludeSources()))
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := export.Write(w, *format, records, *responses); err != nil {
+		return err
+	}
+
+	// Stdout only carries the assets so it can be piped
+	if *out != "" {
+		fmt.Printf("Exported %d assets to %s\n", len(records), *out)
+	}
+	return nil
+}
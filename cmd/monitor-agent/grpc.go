@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/grpcapi"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// grpcEventBuffer is how many events a gRPC event stream buffers before it
+// misses events
+const grpcEventBuffer = 256
+
+// runGRPC dispatches the grpc subcommands
+func runGRPC(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent grpc serve [flags]")
+	}
+
+	switch args[0] {
+	case "serve":
+		return runGRPCServe(ctx, cfg, monitorService, args[1:])
+	default:
+		return fmt.Errorf("unknown grpc command: %s", args[0])
+	}
+}
+
+// runGRPCServe serves the gRPC API until it receives SIGINT or SIGTERM
+func runGRPCServe(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("grpc serve", flag.ExitOnError)
+	addr := fs.String("addr", cfg.GRPC.ListenAddr, "listen address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cfg.GRPC.Token == "" {
+		return fmt.Errorf("GRPC_TOKEN is required to serve")
+	}
+
+	// Scans triggered over the API emit their events to the streams as well
+	broadcaster := events.NewBroadcaster(grpcEventBuffer)
+	monitorService.AddEventPublisher(broadcaster)
+
+	api := grpcapi.NewServer(monitorService, broadcaster, cfg.GRPC.Token)
+	server := api.NewGRPCServer()
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *addr, err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logrus.Infof("gRPC server listening on %s", *addr)
+		serveErr <- server.Serve(listener)
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			return fmt.Errorf("gRPC server failed: %w", err)
+		}
+		return nil
+	case sig := <-sigChan:
+		logrus.Infof("Received signal %v, shutting down gRPC server...", sig)
+	case <-ctx.Done():
+	}
+
+	// End the event streams so graceful shutdown does not wait on them
+	broadcaster.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(30 * time.Second):
+		logrus.Warn("gRPC server did not stop within 30 seconds, forcing shutdown")
+		server.Stop()
+	}
+
+	logrus.Info("Waiting for triggered scans to finish...")
+	api.Wait()
+	return nil
+}
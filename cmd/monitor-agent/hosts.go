@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+)
+
+// runHosts dispatches the hosts subcommands
+func runHosts(ctx context.Context, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent hosts <show|shared> [flags]")
+	}
+
+	switch args[0] {
+	case "show":
+		return runHostsShow(ctx, db, args[1:])
+	case "shared":
+		return runHostsShared(ctx, db, args[1:])
+	default:
+		return fmt.Errorf("unknown hosts command: %s", args[0])
+	}
+}
+
+// runHostsShow prints the programs that include a host
+func runHostsShow(ctx context.Context, db *sqlx.DB, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: monitor-agent hosts show <host or URL>")
+	}
+
+	repo := database.NewHostRepository(db)
+	host, err := repo.GetHostByURL(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if host == nil {
+		return fmt.Errorf("no program includes %s", args[0])
+	}
+
+	programs, err := repo.GetHostPrograms(ctx, host.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== %s ===\n", host.HostKey)
+	fmt.Printf("First seen: %s\n", host.FirstSeen.Format("2006-01-02 15:04"))
+	fmt.Printf("Last seen:  %s\n", host.LastSeen.Format("2006-01-02 15:04"))
+	if host.IP != "" || host.IPv6 != "" {
+		fmt.Printf("Address:    %s\n", strings.TrimSpace(host.IP+" "+host.IPv6))
+	}
+	if host.LastProbedAt != nil {
+		fmt.Printf("Liveness:   %s (probed %s)\n", host.Liveness, host.LastProbedAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Println()
+	for _, program := range programs {
+		state := program.Status
+		if program.Ignored {
+			state += ", ignored"
+		}
+		fmt.Printf("%-12s %-30s %-45s %s  (since %s)\n", program.Platform, program.ProgramName, program.URL, state,
+			program.CreatedAt.Format("2006-01-02"))
+	}
+	fmt.Printf("\nIncluded by %d programs\n", len(programs))
+
+	return nil
+}
+
+// runHostsShared lists the hosts more than one program includes
+func runHostsShared(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("hosts shared", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "number of hosts to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+
+	repo := database.NewHostRepository(db)
+	hosts, err := repo.GetSharedHosts(ctx, *limit)
+	if err != nil {
+		return err
+	}
+	total, err := repo.CountSharedHosts(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Hosts Shared by Programs ===\n")
+	for _, host := range hosts {
+		fmt.Printf("%-50s %3d programs  last seen %s\n", host.HostKey, host.Programs, host.LastSeen.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("\n%d of %d shared hosts\n", len(hosts), total)
+
+	return nil
+}
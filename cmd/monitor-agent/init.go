@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/monitor-agent/internal/bootstrap"
+	"github.com/monitor-agent/internal/config"
+)
+
+// runInit writes the default configuration files, then verifies the database
+// connection and creates the schema. It runs before any configuration is
+// loaded, so it works on a fresh install.
+func runInit(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory to write configs/config.yaml and .env to")
+	force := fs.Bool("force", false, "overwrite existing configuration files")
+	skipDB := fs.Bool("skip-db", false, "only write the configuration files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	files, err := bootstrap.WriteDefaults(*dir, *force)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Monitor Agent Init ===\n")
+	for _, file := range files {
+		if file.Written {
+			fmt.Printf("Wrote:     %s\n", file.Path)
+		} else {
+			fmt.Printf("Kept:      %s (exists; use --force to overwrite)\n", file.Path)
+		}
+	}
+
+	if *skipDB {
+		fmt.Printf("\nNext: set DB_* and your platform API keys in %s, then run `monitor-agent init` again to create the schema\n", bootstrap.EnvPath)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration (edit %s and run init again): %w", bootstrap.EnvPath, err)
+	}
+
+	db, err := connectToDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("%w (check DB_HOST, DB_PORT, DB_NAME, DB_USER and DB_PASSWORD)", err)
+	}
+	defer db.Close()
+
+	var serverVersion string
+	if err := db.GetContext(ctx, &serverVersion, "SHOW server_version"); err != nil {
+		return fmt.Errorf("failed to query database version: %w", err)
+	}
+	fmt.Printf("Database:  %s:%d/%s (PostgreSQL %s)\n", cfg.Database.Host, cfg.Database.Port, cfg.Database.Name, serverVersion)
+
+	if err := runMigrations(cfg, db); err != nil {
+		return err
+	}
+	fmt.Printf("Schema:    up to date\n")
+
+	if platforms := cfg.GetConfiguredPlatforms(); len(platforms) > 0 {
+		fmt.Printf("Platforms: %v\n", platforms)
+	} else {
+		fmt.Printf("Platforms: none configured; set HACKERONE_*, BUGCROWD_API_KEY, INTIGRITI_API_KEY or CHAOSDB_API_KEY to scan\n")
+	}
+
+	fmt.Printf("\nNext: run `monitor-agent health`, then `monitor-agent scan`\n")
+	return nil
+}
@@ -2,26 +2,83 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // PostgreSQL driver
 	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
 	"github.com/monitor-agent/internal/service"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/monitor-agent/internal/version"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
+	// --passive, --read-only and --config can be given with any command
+	passive := globalFlag("passive")
+	readOnly := globalFlag("read-only")
+	configPath, err := configFlag()
+	if err != nil {
+		logrus.Errorf("Invalid arguments: %v", err)
+		os.Exit(1)
+	}
+
+	// The version command needs no configuration or database
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		if err := runVersion(context.Background(), os.Args[2:]); err != nil {
+			logrus.Errorf("Version check failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Alerting rules are generated without configuration or database
+	if len(os.Args) > 1 && os.Args[1] == "metrics" {
+		if err := runMetrics(os.Args[2:]); err != nil {
+			logrus.Errorf("Metrics command failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// init writes the configuration files, so it runs before any is loaded
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		if err := runInit(context.Background(), os.Args[2:]); err != nil {
+			logrus.Errorf("Init failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadFrom(configPath)
 	if err != nil {
 		logrus.Errorf("Failed to load configuration: %v", err)
 		os.Exit(1)
 	}
+	if passive {
+		cfg.App.Passive = true
+	}
+	if readOnly {
+		cfg.App.ReadOnly = true
+	}
+
+	// Probe workers only probe and need no database configuration
+	if len(os.Args) > 1 && os.Args[1] == "probe-worker" {
+		if err := runProbeWorker(context.Background(), cfg, os.Args[2:]); err != nil {
+			logrus.Errorf("Probe worker failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -36,6 +93,15 @@ func main() {
 		os.Exit(1)
 	}
 	logrus.SetLevel(level)
+	utils.SetLogFormat(cfg.App.LogFormat)
+	logrus.Infof("Monitor Agent %s", version.String())
+
+	if cfg.App.ReadOnly {
+		if err := checkReadOnlyCommand(os.Args[1:]); err != nil {
+			logrus.Error(err)
+			os.Exit(1)
+		}
+	}
 
 	// Connect to database
 	db, err := connectToDatabase(cfg)
@@ -45,20 +111,35 @@ func main() {
 	}
 	defer db.Close()
 
-	// Run database migrations
-	if err := runMigrations(db); err != nil {
-		logrus.Errorf("Failed to run database migrations: %v", err)
+	// migrate applies or checks migrations itself, under the migration lock
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), cfg, db, os.Args[2:]); err != nil {
+			logrus.Errorf("Migrate failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Run database migrations, unless MIGRATIONS_MANUAL leaves them to
+	// `migrate`, and refuse to run against a schema this binary doesn't match
+	if err := prepareSchema(cfg, db); err != nil {
+		logrus.Errorf("Database schema not ready: %v", err)
 		os.Exit(1)
 	}
 
 	// Initialize monitor service
 	monitorService := service.NewMonitorService(cfg, db)
+	defer func() {
+		if err := monitorService.Close(); err != nil {
+			logrus.Warnf("Failed to close monitor service: %v", err)
+		}
+	}()
 
 	// Log configured platforms
 	configuredPlatforms := cfg.GetConfiguredPlatforms()
 	if len(configuredPlatforms) == 0 {
 		logrus.Warn("No API keys configured. The application will start but cannot perform scans.")
-		logrus.Info("To enable scanning, set one or more of: HACKERONE_USERNAME+HACKERONE_API_KEY, BUGCROWD_API_KEY, CHAOSDB_API_KEY")
+		logrus.Info("To enable scanning, set one or more of: HACKERONE_USERNAME+HACKERONE_API_KEY, BUGCROWD_API_KEY, INTIGRITI_API_KEY, CHAOSDB_API_KEY")
 	} else {
 		logrus.Infof("Configured platforms: %v", configuredPlatforms)
 	}
@@ -67,7 +148,19 @@ func main() {
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "scan":
-			if err := runScan(context.Background(), monitorService); err != nil {
+			if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "-") {
+				if err := runScanCommand(context.Background(), monitorService, os.Args[2:]); err != nil {
+					logrus.Errorf("Scan command failed: %v", err)
+					os.Exit(1)
+				}
+				return
+			}
+			opts, err := parseScanFlags(os.Args[2:])
+			if err != nil {
+				logrus.Errorf("Scan failed: %v", err)
+				os.Exit(1)
+			}
+			if err := runScan(context.Background(), cfg, db, monitorService, opts); err != nil {
 				logrus.Errorf("Scan failed: %v", err)
 				os.Exit(1)
 			}
@@ -84,6 +177,186 @@ func main() {
 				os.Exit(1)
 			}
 			return
+		case "seed":
+			if err := runSeed(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Seed failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "sync":
+			if err := runSync(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Sync failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "grpc":
+			if err := runGRPC(context.Background(), cfg, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("gRPC command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "api":
+			if err := runAPI(context.Background(), cfg, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("API command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "defectdojo":
+			if err := runDefectDojo(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("DefectDojo command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "cmdb":
+			if err := runCMDB(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("CMDB command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "notes":
+			if err := runNotes(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Notes command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "report":
+			if err := runReport(context.Background(), cfg, db, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Report command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "programs":
+			if err := runPrograms(context.Background(), monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Programs command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "discover":
+			if err := runDiscover(context.Background(), monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Discover failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "assets":
+			if err := runAssets(context.Background(), db, os.Args[2:]); err != nil {
+				logrus.Errorf("Assets command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "hosts":
+			if err := runHosts(context.Background(), db, os.Args[2:]); err != nil {
+				logrus.Errorf("Hosts command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "tag":
+			if err := runTag(context.Background(), db, os.Args[2:]); err != nil {
+				logrus.Errorf("Tag command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "quota":
+			if err := runQuota(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Quota command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "orphans":
+			if err := runOrphans(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Orphans command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "prune":
+			if err := runPrune(context.Background(), db, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Prune failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "auth":
+			if err := runAuth(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Auth command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "quarantine":
+			if err := runQuarantine(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Quarantine command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "bench":
+			if err := runBench(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Bench command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "rules":
+			if err := runRules(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Rules command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "rescore":
+			if err := runRescore(context.Background(), cfg, db, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Rescore failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "diff":
+			if err := runDiff(context.Background(), db, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Diff command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "clusters":
+			if err := runClusters(context.Background(), db, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Clusters command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "watch":
+			if err := runWatch(context.Background(), db, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Watch command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "canary":
+			if err := runCanary(context.Background(), cfg, monitorService); err != nil {
+				logrus.Errorf("Canary check failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "daemon":
+			if err := runDaemon(context.Background(), cfg, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Daemon failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "slack-bot":
+			if err := runSlackBot(context.Background(), cfg, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Slack bot failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "responses":
+			if err := runResponses(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("Responses command failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "tui":
+			if err := runTUI(context.Background(), cfg, db, os.Args[2:]); err != nil {
+				logrus.Errorf("TUI failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "export":
+			if err := runExport(context.Background(), cfg, db, monitorService, os.Args[2:]); err != nil {
+				logrus.Errorf("Export failed: %v", err)
+				os.Exit(1)
+			}
+			return
 		case "help":
 			showHelp()
 			return
@@ -101,11 +374,11 @@ func main() {
 	logrus.Info("No command specified, running scan...")
 
 	// Run scan in a goroutine so we can handle shutdown signals
-	// Note: No overall process timeout - the scan will run until completion
-	// Individual operations have timeouts to prevent hanging
+	// The overall scan is only bounded when SCAN_TIMEOUT is set; individual
+	// programs and discovery runs always have their own nested timeouts
 	scanDone := make(chan error, 1)
 	go func() {
-		scanDone <- runScan(context.Background(), monitorService)
+		scanDone <- runScan(context.Background(), cfg, db, monitorService, &scanOptions{})
 	}()
 
 	// Wait for either scan completion or shutdown signal
@@ -130,6 +403,97 @@ func main() {
 	}
 }
 
+// globalFlag removes a boolean flag such as --passive from the command line
+// and reports whether it was given
+func globalFlag(name string) bool {
+	args := os.Args[:1]
+	given := false
+	for _, arg := range os.Args[1:] {
+		if arg == "--"+name || arg == "-"+name {
+			given = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+	return given
+}
+
+// readOnlyCommands are the commands, or their subcommands, that only change
+// data and are refused up front in read-only mode. Scans, probes and other
+// service operations are refused by the service, and anything else that
+// writes by the read-only database session.
+var readOnlyCommands = map[string][]string{
+	"seed":       nil,
+	"sync":       nil,
+	"defectdojo": nil,
+	"quota":      {"set"},
+	"auth":       {"set", "delete"},
+	"watch":      {"add", "remove"},
+	"tag":        {"add", "remove"},
+	"report":     {"share"},
+}
+
+// checkReadOnlyCommand refuses the commands of readOnlyCommands
+func checkReadOnlyCommand(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	subcommands, ok := readOnlyCommands[args[0]]
+	if !ok {
+		return nil
+	}
+	if subcommands == nil {
+		return fmt.Errorf("%s: %w", args[0], service.ErrReadOnly)
+	}
+	if len(args) > 1 && slices.Contains(subcommands, args[1]) {
+		return fmt.Errorf("%s %s: %w", args[0], args[1], service.ErrReadOnly)
+	}
+	return nil
+}
+
+// configFlag removes --config PATH (or --config=PATH) from the arguments and
+// returns the path, or "" when the config file should be looked up as usual
+func configFlag() (string, error) {
+	args := os.Args[:1]
+	path := ""
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		switch {
+		case arg == "--config" || arg == "-config":
+			if i+1 >= len(os.Args) {
+				return "", fmt.Errorf("--config needs a file path")
+			}
+			i++
+			path = os.Args[i]
+		case strings.HasPrefix(arg, "--config=") || strings.HasPrefix(arg, "-config="):
+			_, path, _ = strings.Cut(arg, "=")
+		default:
+			args = append(args, arg)
+		}
+	}
+	os.Args = args
+	return path, nil
+}
+
+// excludeSourceFlag adds the --exclude-source flag of the export commands,
+// defaulting to EXPORT_EXCLUDE_SOURCES. The returned function lists the
+// sources to leave out once the flags are parsed.
+func excludeSourceFlag(fs *flag.FlagSet, cfg *config.Config) func() []string {
+	value := fs.String("exclude-source", strings.Join(cfg.Provenance.ExcludeSources, ","),
+		"comma-separated discovery sources to leave out, e.g. chaosdb (assets another source found are kept)")
+	return func() []string {
+		var sources []string
+		for _, source := range strings.Split(*value, ",") {
+			if source = strings.TrimSpace(source); source != "" {
+				sources = append(sources, source)
+			}
+		}
+		return sources
+	}
+}
+
 // connectToDatabase connects to the PostgreSQL database
 func connectToDatabase(cfg *config.Config) (*sqlx.DB, error) {
 	dsn := cfg.GetDSN()
@@ -152,30 +516,72 @@ func connectToDatabase(cfg *config.Config) (*sqlx.DB, error) {
 	return db, nil
 }
 
-// runMigrations executes database migrations
-func runMigrations(db *sqlx.DB) error {
-	// Read migration file
-	migrationSQL, err := os.ReadFile("migrations/001_initial_schema.sql")
+// runMigrations applies the pending embedded database migrations, or those
+// in MIGRATIONS_DIR when it is set, in file name order
+func runMigrations(cfg *config.Config, db *sqlx.DB) error {
+	migrations, err := database.MigrationsFS(cfg.Database.MigrationsDir)
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
+		return err
 	}
 
-	// Execute migration
-	_, err = db.Exec(string(migrationSQL))
+	applied, err := database.Migrate(context.Background(), db, migrations, cfg.Database.MigrationLockTimeout)
 	if err != nil {
-		return fmt.Errorf("failed to execute migration: %w", err)
+		return err
 	}
 
-	logrus.Info("Database migrations completed successfully")
+	logrus.Infof("Database migrations completed successfully, %d applied", len(applied))
 	return nil
 }
 
-// runScan performs a single scan
-func runScan(ctx context.Context, monitorService *service.MonitorService) error {
-	logrus.Info("Starting scan of all bug bounty platforms...")
+// prepareSchema migrates the database on start, or with MIGRATIONS_MANUAL or
+// in read-only mode only verifies that every migration of this binary, and no
+// other, was applied
+func prepareSchema(cfg *config.Config, db *sqlx.DB) error {
+	if !cfg.Database.MigrationsManual && !cfg.App.ReadOnly {
+		return runMigrations(cfg, db)
+	}
+
+	migrations, err := database.MigrationsFS(cfg.Database.MigrationsDir)
+	if err != nil {
+		return err
+	}
+	return database.CheckSchema(context.Background(), db, migrations)
+}
+
+// runScan performs a single scan of all platforms, or of one program
+func runScan(ctx context.Context, cfg *config.Config, db *sqlx.DB, monitorService *service.MonitorService, opts *scanOptions) error {
+	defer serveMetrics(ctx, cfg, monitorService)()
+
+	// Scans a previous run left behind are aborted; this run's are aborted
+	// on SIGINT or SIGTERM rather than left running
+	abortStaleScans(ctx, monitorService)
+	ctx, stop := cancelOnSignal(ctx)
+	defer stop()
 
 	startTime := time.Now()
-	if err := monitorService.RunFullScan(ctx); err != nil {
+	if opts.retry {
+		err := runRetryFailed(ctx, monitorService)
+		refreshStatusPage(ctx, cfg, monitorService)
+		refreshNotes(ctx, cfg, db)
+		writeScanReport(ctx, cfg, db, startTime)
+		return err
+	}
+
+	if opts.program != "" {
+		err := runProgramScan(ctx, db, monitorService, opts.program)
+		refreshStatusPage(ctx, cfg, monitorService)
+		refreshNotes(ctx, cfg, db)
+		writeScanReport(ctx, cfg, db, startTime)
+		return err
+	}
+
+	logrus.Info("Starting scan of all bug bounty platforms...")
+
+	err := monitorService.RunFullScanWith(ctx, service.FullScanOptions{Resume: opts.resume, Platforms: opts.platforms})
+	refreshStatusPage(ctx, cfg, monitorService)
+	refreshNotes(ctx, cfg, db)
+	writeScanReport(ctx, cfg, db, startTime)
+	if err != nil {
 		return fmt.Errorf("scan failed: %w", err)
 	}
 
@@ -196,11 +602,126 @@ func showStats(ctx context.Context, monitorService *service.MonitorService) erro
 	fmt.Printf("Active Programs: %d\n", stats.ActivePrograms)
 	fmt.Printf("Total Assets: %d\n", stats.TotalAssets)
 
+	if freshness := stats.Freshness; freshness != nil {
+		fmt.Printf("\nFreshness SLOs:\n")
+		if freshness.ProgramScanWithin > 0 {
+			fmt.Printf("  - programs scanned within %v: %d of %d (%.1f%%)\n",
+				freshness.ProgramScanWithin,
+				freshness.Programs-freshness.ProgramsViolating,
+				freshness.Programs,
+				freshness.ProgramCompliance()*100)
+		}
+		if freshness.AssetProbeWithin > 0 {
+			fmt.Printf("  - assets probed within %v: %d of %d (%.1f%%)\n",
+				freshness.AssetProbeWithin,
+				freshness.Assets-freshness.AssetsViolating,
+				freshness.Assets,
+				freshness.AssetCompliance()*100)
+		}
+		for _, program := range freshness.Violations {
+			lastScan := "never scanned"
+			if program.LastScanAt != nil {
+				lastScan = "last scanned " + program.LastScanAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("    %s (%s): %s, %d of %d assets not probed recently\n",
+				program.ProgramName, program.Platform, lastScan, program.StaleAssets, program.Assets)
+		}
+	}
+
+	if len(stats.SourceYield) > 0 {
+		fmt.Printf("\nAssets by Discovery Source (first found):\n")
+		for _, yield := range stats.SourceYield {
+			fmt.Printf("  - %s: %d assets (%d active) across %d programs, last found %s\n",
+				yield.Source,
+				yield.Assets,
+				yield.ActiveAssets,
+				yield.Programs,
+				yield.LastFoundAt.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if len(stats.Liveness) > 0 {
+		fmt.Printf("\nAssets by Liveness:\n")
+		for _, count := range stats.Liveness {
+			fmt.Printf("  - %s: %d assets\n", count.Liveness, count.Assets)
+		}
+	}
+
+	if len(stats.ProbeErrors) > 0 {
+		fmt.Printf("\nTop Probe Errors (last 24h):\n")
+		for _, count := range stats.ProbeErrors {
+			fmt.Printf("  - %s: %d assets: %s\n", count.Liveness, count.Assets, count.Error)
+		}
+	}
+
+	if len(stats.ContentChanges) > 0 {
+		fmt.Printf("\nChanged Assets (since each program's latest scan):\n")
+		for _, count := range stats.ContentChanges {
+			fmt.Printf("  - %s (%s): %d assets\n", count.ProgramName, count.Platform, count.Assets)
+		}
+	}
+
+	if len(stats.TLSFindings) > 0 {
+		fmt.Printf("\nOpen TLS Findings:\n")
+		for _, count := range stats.TLSFindings {
+			fmt.Printf("  - %s (%s): %d assets\n", count.CheckName, count.Severity, count.Assets)
+		}
+	}
+
+	if len(stats.Geo) > 0 {
+		fmt.Printf("\nAsset Hosting by Program:\n")
+		for _, summary := range stats.Geo {
+			countries := make([]string, 0, len(summary.Countries))
+			for _, country := range summary.Countries {
+				entry := fmt.Sprintf("%s %d", country.Name, country.Assets)
+				if country.Unusual {
+					entry += " (unusual)"
+				}
+				countries = append(countries, entry)
+			}
+			providers := make([]string, 0, len(summary.Providers))
+			for _, provider := range summary.Providers {
+				providers = append(providers, fmt.Sprintf("%s %d", provider.Name, provider.Assets))
+			}
+			fmt.Printf("  - %s: %d assets\n", summary.ProgramName, summary.Assets)
+			fmt.Printf("      countries: %s\n", strings.Join(countries, ", "))
+			fmt.Printf("      providers: %s\n", strings.Join(providers, ", "))
+		}
+	}
+
+	if len(stats.Maintenance) > 0 {
+		fmt.Printf("\nPlatform Maintenance:\n")
+		for _, window := range stats.Maintenance {
+			state := "ongoing, next retry " + window.RetryAt.Format("2006-01-02 15:04:05")
+			if window.EndedAt != nil {
+				state = "ended " + window.EndedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("  - %s: %s since %s (%s)\n",
+				window.Platform,
+				window.Reason,
+				window.StartedAt.Format("2006-01-02 15:04:05"),
+				state)
+		}
+	}
+
+	if len(stats.SchemaDrift) > 0 {
+		fmt.Printf("\nPlatform Schema Drift (last 7 days):\n")
+		for _, drift := range stats.SchemaDrift {
+			fmt.Printf("  - %s %s: %s %s (first seen %s, %d runs)\n",
+				drift.Platform,
+				drift.Payload,
+				drift.Field,
+				drift.Change,
+				drift.FirstSeenAt.Format("2006-01-02 15:04:05"),
+				drift.Scans)
+		}
+	}
+
 	if len(stats.RecentScans) > 0 {
 		fmt.Printf("\nRecent Scans:\n")
 		for _, scan := range stats.RecentScans {
 			status := scan.Status
-			if scan.Error != "" {
+			if scan.Error != "" && status == "completed" {
 				status = "failed"
 			}
 			fmt.Printf("  - %s: %s (%d assets found)\n",
@@ -247,29 +768,244 @@ func showHelp() {
 Monitor Agent - Bug Bounty Program Monitor
 
 Usage:
-  monitor-agent [command]
+  monitor-agent [--passive] [--read-only] [--config FILE] [command]
+
+  --passive  Only collect from platform APIs and ChaosDB; nothing is sent to
+             targets (no HTTPX probes, TLS checks or probe workers). Same as PASSIVE_MODE=true
+  --read-only
+             Only query: scans, probes, imports and database writes are refused
+             and migrations are not applied. Same as READ_ONLY=true
+  --config   Read the YAML configuration from FILE instead of configs/config.yaml;
+             the file has to exist
 
 Commands:
   scan     Perform a scan of all platforms (default behavior)
+           [--resume]                     Continue the last scan if it was interrupted, skipping programs it processed
+           --program <handle|url>         Scan one monitored program right away, e.g. after its scope changed
+           --platforms hackerone,bugcrowd Scan only these configured platforms
+           --retry-failed                 Retry the domains whose discovery or probe failed, once due
+           cancel <scan-id>               Cancel a running scan and mark it cancelled
+  discover Discover and probe assets for ad-hoc domains without any platform
+           [--program manual] [--file PATH] <domain>...
+  programs Manage monitored programs
+           add [--file PATH] [--scan] <url>...
+                                          Add HackerOne/BugCrowd programs after checking them on the platform
+  init     Write a commented configs/config.yaml and .env, verify the database and create the schema
+           [--dir .] [--force] [--skip-db]
   stats    Show program and asset statistics
+  report   Publishable reports
+           html [--out status] [--title TEXT]
+                                          Write a static status page (index.html, status.json) without sensitive data
+           coverage [--program URL] [--scans 5]
+                                          Compare discovered, probed and resolved subdomains per program
+           --scan ID[,ID...] [--format markdown|html] [--out FILE] [--webhook URL]
+                                          Summarize scans: new programs, scope changes, new and dead assets, errors
   health   Perform health checks
+  diff     Show what changed in a program's assets since the scan before its latest completed scan
+           [--scan ID] [--change added|removed|changed] [--json] <program handle or URL>
+  seed     Populate the database with synthetic development data
+           [--programs 50] [--assets-per-program 200] [--responses-per-asset 1] [--seed N] [--force]
+  sync     Sync with a central aggregation server
+           push [--server URL] [--full]   Push local programs/assets to the central server
+           serve [--addr :8080]           Run the central server that edge agents push to
+                                          (also serves DELETE /scans/{id} to cancel a scan)
+  grpc     gRPC API for internal services (proto/monitoragent/v1/monitor_agent.proto)
+           serve [--addr :9090]           Serve asset queries, scan triggers and event streams
+  api      REST API for dashboards and other tooling
+           serve [--addr :8090]           Serve programs, assets, scan history and scan triggers
+  defectdojo  Export scans to DefectDojo: a product per program, an engagement per scan
+           push [--program URL] [--limit 50] [--exclude-source chaosdb]
+                                          Push assets and findings of scans not exported yet
+  cmdb     Compare discovered assets with the company's inventory
+           reconcile [--program URL] [--csv PATH] [--format text|csv|json] [--out PATH] [--exclude-source chaosdb]
+                                          Report shadow assets the CSV or ServiceNow inventory does not list
+  notes    Write per-program Markdown notes for Obsidian or a notes repository
+           export [--out notes] [--program URL] [--exclude-source chaosdb]
+                                          Regenerate scope, assets and notable responses, keeping personal notes
+  assets   Manage assets in bulk
+           update --query QUERY [--tag a,b] [--untag a,b] [--ignore|--unignore] [--status S] [--dry-run]
+                                          Tag, ignore or set the status of every matching asset
+  hosts    Hosts shared by the assets of several programs
+           show <host or URL>             List the programs that include a host
+           shared [--limit 50]            List the hosts most programs include
+  tag      Tag and annotate single assets, e.g. as triaged, interesting or ignored
+           add [--note TEXT] <asset-url> <tag>...
+           remove <asset-url> <tag>...
+           list <asset-url> | list --tag TAG
+  quota    Manage per-program asset quota alerts
+           set --program URL [--max-drop 30] [--max-growth 500] [--disable]
+           show --program URL             Show the bounds that apply to a program
+           alerts [--limit 20]            List recent quota alerts
+  auth     Manage per-program credentials sent when probing the program's assets
+           set --program URL [--header 'Name: value']... [--cookie name=value]... [--basic user:pass] [--file PATH]
+                                          Store the profile sealed with PROBE_AUTH_KEY, replacing the previous one
+           show --program URL             Show the profile with its values redacted
+           delete --program URL           Remove the profile
+  migrate  Manage database migrations; up and down hold an advisory lock
+           up [--check] [--lock-timeout 1m]
+                                          Apply pending migrations (the default); with --check only list them, exiting 1 when there are any
+           down [--steps 1] [--lock-timeout 1m]
+                                          Roll back the latest migrations with their down migrations
+           status                         List every migration, whether it was applied and whether it can be rolled back
+  quarantine  List assets whose scope root left their program's scope and when they are quarantined
+           [--program URL]
+  orphans  List rows whose program, scan, asset or response no longer exists
+           [--purge]                      Delete them and validate the foreign keys
+  bench    Measure database writes, HTTPX probes and rate limiter overhead on this host
+           [--rows 2000] [--probes 200] [--limiter-calls 100000]
+                                          Print the throughput and recommended setting changes
+  rules    Manage triage rules evaluated on asset responses
+           check [--file PATH]            Validate a rules file and list its rules
+           matches [--limit 20]           List recent rule matches
+  rescore  Recompute asset scores with the scoring model, e.g. after SCORING_FILE changed
+           [--program URL] [--top 10]     Rescore and list the highest scored assets
+           --check                        Validate the scoring model and print its weights
+  clusters  Group live assets whose responses are near-identical, to review one per group
+           build                          Regroup now instead of after the next full scan
+           list [--min-size 2] [--limit 20]
+                                          List the largest clusters with their representative asset
+           show [--limit 50] <cluster id> List a cluster's assets
+  export   Write assets to stdout or a file for nuclei, httpx or a spreadsheet
+           [--format txt|csv|json] [--program <handle or URL>] [--source primary|secondary] [--responses]
+           [--all] [--out PATH] [--exclude-source chaosdb]
+  responses  Browse stored HTTP responses
+           show [--history] [--body-bytes 2000] <asset id|url|host>
+                                          Show an asset's latest response or its capture history
+  tui      Browse programs, their assets and latest responses in the terminal,
+           highlighting what each program's latest completed scan found first
+           [--new]                        Start with only new programs and assets listed
+  prune    Delete stored responses outside the response retention
+           [--keep N] [--max-age 720h] [--dry-run]
+                                          Delete them, or only count them with --dry-run
+  watch    Watch specific hostnames every cycle and announce the moment they resolve or respond
+           add [--program URL] [--note TEXT] <hostname>...
+           remove <hostname>...
+           list                           List watched hostnames and their last check
+           check                          Check every watched hostname now
+  canary   Resolve and probe the CANARY_TARGETS hostnames now, exiting 1 when any failed
+  daemon   Run continuously, re-probing the stalest assets within an hourly request budget,
+           checking watched hostnames, running full scans on SCAN_SCHEDULE and following
+           certificate transparency logs
+           [--sweep-requests-per-hour 600] [--sweep-batch-size 25] [--watchlist-interval 5m]
+           [--schedule '0 3 * * *'] [--ctlog]
+  slack-bot  Answer Slack slash commands over socket mode: assets <domain>, rescan <program>, stats
+           [--command /monitor]
+  metrics  Prometheus tooling
+           rules [--out FILE] [--scan-failure-ratio 0.2] [--zero-asset-window 6h] [--platform-error-ratio 0.1]
+                 [--pool-saturation 0.9] [--pool-saturation-for 10m] [--slo-compliance 0.95]
+                                          Print recommended alerting rules for the exported metrics
+  version  Show build information
+           [--check]                      Check GitHub for a newer release
+  probe-worker  Run a remote probe worker that agents in other regions dispatch probes to
+           [--addr :8081] [--region NAME]
   help     Show this help message
 
 Environment Variables:
   DB_HOST, DB_PORT, DB_NAME, DB_USER, DB_PASSWORD (required)
-  HACKERONE_USERNAME, HACKERONE_API_KEY, BUGCROWD_API_KEY, CHAOSDB_API_KEY (optional)
-  LOG_LEVEL, ENVIRONMENT
+  DB_WRITE_BATCH_SIZE, DB_WRITES_PER_SECOND, MIGRATIONS_DIR (optional)
+  MIGRATIONS_MANUAL, MIGRATIONS_LOCK_TIMEOUT (optional)
+  HACKERONE_USERNAME, HACKERONE_API_KEY, BUGCROWD_API_KEY, INTIGRITI_API_KEY, CHAOSDB_API_KEY (optional)
+  HACKERONE_CREDENTIALS, BUGCROWD_CREDENTIALS, INTIGRITI_CREDENTIALS, CHAOSDB_DATASETS, CRTSH_ENABLED (optional)
+  HACKERONE_INCLUDE_PRIVATE (optional)
+  HACKERONE_BASE_URL, BUGCROWD_BASE_URL, INTIGRITI_BASE_URL, CHAOSDB_BASE_URL, CHAOSDB_DATASET_INDEX_URL, CRTSH_BASE_URL (optional)
+  LOG_LEVEL, LOG_FORMAT, ENVIRONMENT, PASSIVE_MODE, READ_ONLY
+  SYNC_SERVER_URL, SYNC_TOKEN, SYNC_AGENT_ID, SYNC_BATCH_SIZE, SYNC_LISTEN_ADDR (optional)
+  GRPC_LISTEN_ADDR, GRPC_TOKEN (optional)
+  API_LISTEN_ADDR, API_TOKEN (optional)
+  METRICS_ENABLED, METRICS_LISTEN_ADDR, METRICS_PATH (optional)
+  MAINTENANCE_RETRY_DELAY, MAINTENANCE_MAX_RETRIES, MAINTENANCE_MAX_WAIT (optional)
+  QUOTA_MAX_DROP_PERCENT, QUOTA_MAX_GROWTH, QUOTA_MIN_ASSETS (optional)
+  EVENTS_SOURCE, EVENTS_WEBHOOK_URL, EVENTS_WEBHOOK_SECRET (optional)
+  EVENTS_KAFKA_BROKERS, EVENTS_KAFKA_TOPIC, EVENTS_NATS_URL, EVENTS_NATS_SUBJECT (optional)
+  EVENTS_ROUTES_FILE, EVENTS_DIGEST_ATTACHMENT, EVENTS_DIGEST_ATTACHMENT_DIR, EVENTS_DIGEST_ATTACHMENT_URL (optional)
+  NOTIFY_SLACK_ENABLED, NOTIFY_SLACK_WEBHOOK_URL, NOTIFY_DISCORD_ENABLED, NOTIFY_DISCORD_WEBHOOK_URL (optional)
+  NOTIFY_WEBHOOK_ENABLED, NOTIFY_WEBHOOK_URL, NOTIFY_WEBHOOK_SECRET, NOTIFY_BATCH_SIZE, NOTIFY_BATCH_INTERVAL (optional)
+  NOTIFY_RETRY_ATTEMPTS, NOTIFY_RETRY_DELAY (optional)
+  RULES_FILE, SCORING_FILE, CLUSTER_MAX_DISTANCE, CONTENT_CHANGE_MIN_DISTANCE (optional)
+  RESPONSE_RETENTION_PER_ASSET, RESPONSE_RETENTION_MAX_AGE (optional)
+  SLO_PROGRAM_SCAN_WITHIN, SLO_ASSET_PROBE_WITHIN (optional)
+  CANARY_TARGETS, CANARY_ON_FAILURE (optional)
+  DATA_SOURCE_TERMS, EXPORT_EXCLUDE_SOURCES (optional)
+  SEARCH_URL, SEARCH_INDEX, SEARCH_USERNAME, SEARCH_PASSWORD, SEARCH_BODY_EXCERPT_BYTES (optional)
+  WHOIS_ENABLED, WHOIS_IP_LOOKUPS, WHOIS_RDAP_URL, WHOIS_NEW_DOMAIN_DAYS, WHOIS_REFRESH_INTERVAL (optional)
+  PROBE_REGION, PROBE_WORKERS, PROBE_WORKER_TOKEN, PROBE_WORKER_LISTEN_ADDR (optional)
+  HTTPX_IP_VERSION, HTTPX_TLS_CHECKS, HTTPX_METHOD (optional)
+  DNS_ENABLED, DNS_CONCURRENCY, DNS_TIMEOUT, DNS_RESOLVERS (optional)
+  CTLOG_ENABLED, CTLOG_STREAM_URL, CTLOG_FLUSH_INTERVAL, CTLOG_MAX_PENDING (optional)
+  DISCOVERY_RETRY_MAX_ATTEMPTS, DISCOVERY_RETRY_BACKOFF, DISCOVERY_RETRY_MAX_BACKOFF (optional)
+  PROGRAM_CONCURRENCY, PROGRAM_INCLUDE, PROGRAM_EXCLUDE (optional)
+  DAEMON_SWEEP_REQUESTS_PER_HOUR, DAEMON_SWEEP_BATCH_SIZE, DAEMON_SWEEP_METHOD, DAEMON_WATCHLIST_INTERVAL, SCAN_SCHEDULE (optional)
+  SLACK_APP_TOKEN, SLACK_COMMAND, SLACK_ALLOWED_USERS, SLACK_ALLOWED_CHANNELS (optional)
+  DEFECTDOJO_URL, DEFECTDOJO_API_KEY, DEFECTDOJO_PRODUCT_TYPE (optional)
+  CMDB_CSV, CMDB_SERVICENOW_URL, CMDB_SERVICENOW_USER, CMDB_SERVICENOW_PASSWORD, CMDB_SERVICENOW_TABLE (optional)
+  NOTES_VAULT_DIR (optional)
+  PROBE_AUTH_KEY (optional)
+  SCOPE_QUARANTINE_MODE, SCOPE_QUARANTINE_GRACE (optional)
+  STATUS_PAGE_DIR, STATUS_PAGE_TITLE (optional)
+  SCAN_REPORT_DIR, SCAN_REPORT_FORMAT, SCAN_REPORT_WEBHOOK_URL, SCAN_REPORT_WEBHOOK_SECRET (optional)
+  S3_BUCKET, S3_REGION, S3_ENDPOINT, S3_FORCE_PATH_STYLE, S3_PREFIX (optional)
+  S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY, S3_SESSION_TOKEN (optional, default to the AWS_ variables)
+  BODY_STORE_BACKEND, BODY_STORE_BUCKET, BODY_STORE_PREFIX, BODY_STORE_MIN_BYTES (optional)
+  GCS_CREDENTIALS_FILE, GCS_ENDPOINT (optional)
   
-  Timeout Configuration (optional):
-  PROGRAM_PROCESS_TIMEOUT - Individual program processing timeout (default: 45m)
+  Timeout Configuration (optional, each must fit inside the one above it):
+  SCAN_TIMEOUT            - Whole scan timeout (default: no limit)
+  PROGRAM_PROCESS_TIMEOUT - Individual program processing timeout (default: CHAOS_DISCOVERY_TIMEOUT + 15m)
+  PROGRAM_TIMEOUT_FACTOR  - Recent scan duration multiple long programs get (default: 1.5; 0 disables)
+  PROGRAM_TIMEOUT_MAX     - Longest extended program timeout (default: 4x PROGRAM_PROCESS_TIMEOUT)
+  PROGRAM_TIMEOUTS        - Timeouts of specific programs, e.g. acme=3h,hackerone/globex=90m
   CHAOS_DISCOVERY_TIMEOUT - ChaosDB discovery timeout (default: 30m)
-  HTTPX_TIMEOUT           - HTTPX probe per-URL timeout (default: 15s)
+  HTTPX_TIMEOUT           - HTTPX probe per-URL timeout (default: 30s)
 
 Examples:
   monitor-agent          # Run a scan (default)
   monitor-agent scan     # Explicitly run a scan
+  monitor-agent scan --resume   # Continue an interrupted scan where it stopped
+  monitor-agent scan --program hackerone/acme   # Re-scan one program without a full scan
+  monitor-agent scan --platforms bugcrowd,intigriti   # Scan some platforms, e.g. while another is rate limited
+  monitor-agent scan --retry-failed   # Retry domains ChaosDB or HTTPX failed on, e.g. hourly from cron
+  monitor-agent scan cancel 3f6c...   # Cancel a running scan
+  monitor-agent discover example.com example.org   # Scan domains under the "manual" program
+  monitor-agent programs add https://hackerone.com/acme   # Add a program that is checked on HackerOne
+  monitor-agent init --skip-db   # Generate configs/config.yaml and .env on a fresh install
   monitor-agent stats    # Show statistics
+  monitor-agent diff acme    # Show assets added, removed or changed by the latest scan of a program
+  monitor-agent canary   # Check that DNS and probing work with the canary hostnames
+  monitor-agent migrate --check   # List migrations a deploy would apply
+  monitor-agent migrate down --steps 2   # Roll back the latest two migrations before downgrading the agent
+  monitor-agent report coverage --program https://hackerone.com/acme   # Find probe gaps by domain
+  monitor-agent report --scan 3f6c... --format html --out report.html   # Regenerate the summary of a past scan
+  monitor-agent report share --scan 3f6c... --ttl 24h   # Share a scan report through a signed URL
+  monitor-agent report share --scan 3f6c... --exclude-source chaosdb   # Share it without assets only ChaosDB found
+  monitor-agent version --check   # Show the version and check for updates
+  monitor-agent metrics rules --out /etc/prometheus/monitor-agent.rules.yml
   monitor-agent health   # Health check
+  monitor-agent --read-only stats   # Query a shared database without changing it
+  monitor-agent seed --programs 50 --assets-per-program 200   # Seed a dev database
+  monitor-agent sync push  # Push new findings to the central server
+  monitor-agent grpc serve --addr :9090   # Serve the gRPC API to internal services
+  monitor-agent api serve --addr :8090   # Serve the REST API to dashboards
+  monitor-agent quota set --program https://hackerone.com/acme --max-drop 50
+  monitor-agent cmdb reconcile --csv inventory.csv --format csv --out shadow.csv
+  monitor-agent notes export --out ~/vault/bug-bounty   # Refresh the program notes in an Obsidian vault
+  monitor-agent assets update --query 'domain:*.old-acquisition.com' --tag legacy --ignore
+  monitor-agent hosts show api.example.com   # Which programs include a host
+  monitor-agent tag add --note "admin panel behind SSO" https://admin.acme.com interesting   # Mark an asset for follow-up
+  monitor-agent auth set --program https://hackerone.com/acme --header 'X-Bug-Bounty: researcher-42'
+  monitor-agent quarantine --program https://hackerone.com/acme   # Review assets leaving scope before they are quarantined
+  monitor-agent orphans --purge   # Clean up rows left by deletes without cascades
+  monitor-agent prune --keep 5 --dry-run   # Count the responses beyond the newest 5 per asset
+  monitor-agent bench --probes 500   # Tune HTTPX_CONCURRENCY and HTTPX_RATE_LIMIT for this host
+  monitor-agent rules check --file configs/rules.example.yaml
+  monitor-agent rescore --program https://hackerone.com/acme --top 20   # Apply new scoring weights
+  monitor-agent responses show api.example.com --history
+  monitor-agent export --program acme --source primary | nuclei -l -   # Scan a program's in-scope assets
+  monitor-agent export --format csv --responses --out assets.csv   # Open every asset with its latest status code in a spreadsheet
+  monitor-agent export --changed --format json   # Assets whose content changed since their program's latest scan
+  monitor-agent probe-worker --region us-east   # Serve probes from this host's region
+  monitor-agent watch add --note 'expected after launch' admin.example.com   # Announce it as soon as it comes up
+  monitor-agent daemon --sweep-requests-per-hour 1200   # Keep liveness data fresh
+  monitor-agent daemon --schedule '0 3 * * *'   # Also run a full scan every night at 03:00
 
 This application performs one-off scans of bug bounty platforms.
 API keys are optional - the application will only scan platforms with configured keys.
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// runMetrics dispatches the metrics subcommands
+func runMetrics(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent metrics rules [flags]")
+	}
+
+	switch args[0] {
+	case "rules":
+		return runMetricsRules(args[1:])
+	default:
+		return fmt.Errorf("unknown metrics command: %s", args[0])
+	}
+}
+
+// runMetricsRules writes the recommended Prometheus alerting rules
+func runMetricsRules(args []string) error {
+	thresholds := metrics.DefaultRuleThresholds()
+
+	fs := flag.NewFlagSet("metrics rules", flag.ExitOnError)
+	out := fs.String("out", "", "write the rules to this file instead of stdout")
+	fs.Float64Var(&thresholds.ScanFailureRatio, "scan-failure-ratio", thresholds.ScanFailureRatio, "alert when more than this share of program scans failed over an hour")
+	fs.DurationVar(&thresholds.ZeroAssetWindow, "zero-asset-window", thresholds.ZeroAssetWindow, "alert when a scan completed with zero assets within this window")
+	fs.Float64Var(&thresholds.PlatformErrorRatio, "platform-error-ratio", thresholds.PlatformErrorRatio, "alert when more than this share of platform API requests failed over 10 minutes")
+	fs.Float64Var(&thresholds.PoolSaturationRatio, "pool-saturation", thresholds.PoolSaturationRatio, "alert when more than this share of the database connection pool is in use")
+	fs.DurationVar(&thresholds.PoolSaturationPeriod, "pool-saturation-for", thresholds.PoolSaturationPeriod, "how long the pool has to stay saturated")
+	fs.Float64Var(&thresholds.SLOComplianceRatio, "slo-compliance", thresholds.SLOComplianceRatio, "alert when less than this share of programs or assets meets a freshness SLO for an hour")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := thresholds.Validate(); err != nil {
+		return err
+	}
+
+	data, err := metrics.RulesYAML(metrics.RecommendedRules(thresholds))
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *out, err)
+	}
+	fmt.Printf("Wrote %s; load it with rule_files in prometheus.yml\n", *out)
+	return nil
+}
+
+// serveMetrics serves the Prometheus metrics of monitorService while a scan
+// or the daemon runs, when METRICS_ENABLED is set. The returned function
+// stops the server; a listen failure only logs a warning so the work itself
+// still runs
+func serveMetrics(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService) func() {
+	m := monitorService.Metrics()
+	if m == nil {
+		return func() {}
+	}
+
+	listener, err := net.Listen("tcp", cfg.Metrics.ListenAddr)
+	if err != nil {
+		logrus.Warnf("Failed to serve metrics on %s: %v", cfg.Metrics.ListenAddr, err)
+		return func() {}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Metrics.Path, m.Handler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go monitorService.RunMetricsCollection(ctx)
+	go func() {
+		logrus.Infof("Metrics listening on %s%s", listener.Addr(), cfg.Metrics.Path)
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logrus.Warnf("Metrics server failed: %v", err)
+		}
+	}()
+
+	return func() {
+		cancel()
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelShutdown()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logrus.Warnf("Failed to shut down metrics server: %v", err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+)
+
+// runMigrate runs a migrate subcommand: up applies pending migrations, down
+// rolls back the latest ones and status lists every migration. Without a
+// subcommand it applies pending migrations, or with --check only reports
+// whether the schema matches this binary.
+func runMigrate(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	subcommand := "up"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		subcommand, args = args[0], args[1:]
+	}
+
+	migrations, err := database.MigrationsFS(cfg.Database.MigrationsDir)
+	if err != nil {
+		return err
+	}
+
+	switch subcommand {
+	case "up":
+		return runMigrateUp(ctx, cfg, db, migrations, args)
+	case "down":
+		return runMigrateDown(ctx, cfg, db, migrations, args)
+	case "status":
+		return runMigrateStatus(ctx, db, migrations, args)
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected up, down or status)", subcommand)
+	}
+}
+
+// runMigrateUp applies pending database migrations while holding the
+// migration lock, or with --check only reports whether the schema matches
+// this binary
+func runMigrateUp(ctx context.Context, cfg *config.Config, db *sqlx.DB, migrations fs.FS, args []string) error {
+	flags := flag.NewFlagSet("migrate up", flag.ExitOnError)
+	check := flags.Bool("check", false, "only report pending migrations, exiting non-zero when there are any")
+	lockTimeout := flags.Duration("lock-timeout", cfg.Database.MigrationLockTimeout, "how long to wait for another process's migrations")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *check {
+		status, err := database.GetSchemaStatus(ctx, db, migrations)
+		if err != nil {
+			return err
+		}
+		printSchemaStatus(status)
+		return status.Err()
+	}
+
+	applied, err := database.Migrate(ctx, db, migrations, *lockTimeout)
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("Schema is up to date, no migrations applied")
+		return nil
+	}
+	fmt.Printf("Applied %d migrations:\n", len(applied))
+	for _, name := range applied {
+		fmt.Printf("  %s\n", name)
+	}
+	return nil
+}
+
+// runMigrateDown rolls back the latest applied migrations with their down
+// migrations while holding the migration lock
+func runMigrateDown(ctx context.Context, cfg *config.Config, db *sqlx.DB, migrations fs.FS, args []string) error {
+	flags := flag.NewFlagSet("migrate down", flag.ExitOnError)
+	steps := flags.Int("steps", 1, "number of migrations to roll back, newest first")
+	lockTimeout := flags.Duration("lock-timeout", cfg.Database.MigrationLockTimeout, "how long to wait for another process's migrations")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	reverted, err := database.Rollback(ctx, db, migrations, *steps, *lockTimeout)
+	if err != nil {
+		return err
+	}
+
+	if len(reverted) == 0 {
+		fmt.Println("No migrations applied, nothing rolled back")
+		return nil
+	}
+	fmt.Printf("Rolled back %d migrations:\n", len(reverted))
+	for _, name := range reverted {
+		fmt.Printf("  %s\n", name)
+	}
+	if !cfg.Database.MigrationsManual {
+		fmt.Println("MIGRATIONS_MANUAL is off: the next command run by this binary applies them again")
+	}
+	return nil
+}
+
+// runMigrateStatus lists every migration and whether it was applied,
+// exiting non-zero when the schema does not match this binary
+func runMigrateStatus(ctx context.Context, db *sqlx.DB, migrations fs.FS, args []string) error {
+	flags := flag.NewFlagSet("migrate status", flag.ExitOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	list, err := database.ListMigrations(ctx, db, migrations)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Migrations ===\n")
+	fmt.Printf("%-36s %-8s %-5s %-20s %s\n", "Name", "State", "Down", "Applied", "Agent Version")
+	for _, migration := range list {
+		down := "no"
+		if migration.Reversible {
+			down = "yes"
+		}
+		applied, agentVersion := "-", "-"
+		if migration.AppliedAt != nil {
+			applied = migration.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		if migration.AgentVersion != "" {
+			agentVersion = migration.AgentVersion
+		}
+		fmt.Printf("%-36s %-8s %-5s %-20s %s\n", migration.Name, migration.State, down, applied, agentVersion)
+	}
+
+	status, err := database.GetSchemaStatus(ctx, db, migrations)
+	if err != nil {
+		return err
+	}
+	printSchemaStatus(status)
+	return status.Err()
+}
+
+// printSchemaStatus prints how the database schema compares to the binary's
+// migrations
+func printSchemaStatus(status *database.SchemaStatus) {
+	current := status.Current
+	if current == "" {
+		current = "(none recorded)"
+	}
+
+	fmt.Printf("\n=== Database Schema ===\n")
+	fmt.Printf("Current:  %s\n", current)
+	fmt.Printf("Expected: %s\n", status.Expected)
+	if len(status.Pending) > 0 {
+		fmt.Printf("Pending:  %s\n", strings.Join(status.Pending, ", "))
+	}
+	if len(status.Unknown) > 0 {
+		fmt.Printf("Unknown:  %s (applied by a newer binary)\n", strings.Join(status.Unknown, ", "))
+	}
+	if status.UpToDate() {
+		fmt.Println("Schema is up to date")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/notes"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultNotesDir is where `notes export` writes when NOTES_VAULT_DIR is not set
+const defaultNotesDir = "notes"
+
+// runNotes dispatches the notes subcommands
+func runNotes(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent notes export [flags]")
+	}
+
+	switch args[0] {
+	case "export":
+		return runNotesExport(ctx, cfg, db, args[1:])
+	default:
+		return fmt.Errorf("unknown notes command: %s", args[0])
+	}
+}
+
+// runNotesExport writes the Markdown notes of active programs
+func runNotesExport(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	dir := cfg.Notes.VaultDir
+	if dir == "" {
+		dir = defaultNotesDir
+	}
+
+	fs := flag.NewFlagSet("notes export", flag.ExitOnError)
+	out := fs.String("out", dir, "vault directory to write the notes to")
+	programURL := fs.String("program", "", "only export the note of this program URL")
+	excludeSources := excludeSourceFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var programID *uuid.UUID
+	if *programURL != "" {
+		program, err := resolveQuotaProgram(ctx, db, *programURL)
+		if err != nil {
+			return err
+		}
+		programID = &program.ID
+	}
+
+	summary, err := notes.NewExporter(db, *out, excludeSources()).Export(ctx, programID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d program notes to %s (%d new)\n", summary.Programs, *out, summary.Created)
+	return nil
+}
+
+// refreshNotes rewrites the configured notes vault after a scan. A failure is
+// only logged since the scan itself is done.
+func refreshNotes(ctx context.Context, cfg *config.Config, db *sqlx.DB) {
+	if cfg.Notes.VaultDir == "" {
+		return
+	}
+
+	if _, err := notes.NewExporter(db, cfg.Notes.VaultDir, cfg.Provenance.ExcludeSources).Export(ctx, nil); err != nil {
+		logrus.Warnf("Failed to refresh notes vault: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+)
+
+// runOrphans lists rows whose parent row no longer exists, purging them with --purge
+func runOrphans(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("orphans", flag.ExitOnError)
+	purge := fs.Bool("purge", false, "delete orphaned rows and clear dangling optional references")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	repo := database.NewOrphanRepository(db)
+
+	var counts []*database.OrphanCount
+	var err error
+	if *purge {
+		counts, err = repo.PurgeOrphans(ctx)
+	} else {
+		counts, err = repo.FindOrphans(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	title, verb := "Orphaned Rows", "Found"
+	if *purge {
+		title, verb = "Orphaned Rows Purged", "Purged"
+	}
+
+	fmt.Printf("\n=== %s ===\n", title)
+	var total int64
+	for _, count := range counts {
+		fmt.Printf("%-45s %8d  (%s)\n",
+			fmt.Sprintf("%s.%s -> %s", count.Table, count.Column, count.Parent), count.Rows, count.Action)
+		total += count.Rows
+	}
+	fmt.Printf("\n%s %d orphaned rows\n", verb, total)
+
+	if !*purge && total > 0 {
+		fmt.Printf("Run 'monitor-agent orphans --purge' to remove them\n")
+	}
+
+	return nil
+}
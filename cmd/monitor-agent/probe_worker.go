@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/discovery/probeworker"
+	"github.com/sirupsen/logrus"
+)
+
+// runProbeWorker runs a remote probe worker that agents in other regions
+// dispatch probe batches to. It needs no database.
+func runProbeWorker(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("probe-worker", flag.ExitOnError)
+	addr := fs.String("addr", cfg.Vantage.ListenAddr, "listen address")
+	region := fs.String("region", cfg.Vantage.Region, "region reported with probe results")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateProbeWorker(); err != nil {
+		return err
+	}
+
+	prober := httpx.NewClient(&httpx.ProbeConfig{
+		Timeout:         cfg.Discovery.HTTPX.Timeout,
+		Concurrency:     cfg.Discovery.HTTPX.Concurrency,
+		RateLimit:       cfg.Discovery.HTTPX.RateLimit,
+		FollowRedirects: cfg.Discovery.HTTPX.FollowRedirects,
+		MaxRedirects:    cfg.Discovery.HTTPX.MaxRedirects,
+		Debug:           cfg.Discovery.HTTPX.Debug,
+		IPVersion:       cfg.Discovery.HTTPX.IPVersion,
+		TLSChecks:       cfg.Discovery.HTTPX.TLSChecks,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle(probeworker.ProbePath, probeworker.NewServer(prober, *region, cfg.Vantage.Token))
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logrus.Infof("Probe worker for region %s listening on %s", *region, *addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("probe worker failed: %w", err)
+		}
+		return nil
+	case sig := <-sigChan:
+		logrus.Infof("Received signal %v, shutting down probe worker...", sig)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down probe worker: %w", err)
+	}
+	return nil
+}
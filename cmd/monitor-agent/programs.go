@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runPrograms dispatches the programs subcommands
+func runPrograms(ctx context.Context, monitorService *service.MonitorService, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent programs add [flags] <url>...")
+	}
+
+	switch args[0] {
+	case "add":
+		return runProgramsAdd(ctx, monitorService, args[1:])
+	default:
+		return fmt.Errorf("unknown programs command: %s", args[0])
+	}
+}
+
+// runProgramsAdd imports programs by their HackerOne or BugCrowd URL after
+// checking each one against the platform
+func runProgramsAdd(ctx context.Context, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("programs add", flag.ExitOnError)
+	file := fs.String("file", "", "read program URLs from a file, one per line (# starts a comment)")
+	scan := fs.Bool("scan", false, "scan the programs that were created right away")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	urls := fs.Args()
+	if *file != "" {
+		fileURLs, err := readDomainFile(*file)
+		if err != nil {
+			return err
+		}
+		urls = append(urls, fileURLs...)
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("usage: monitor-agent programs add [--file PATH] [--scan] <url>...")
+	}
+
+	results, err := monitorService.ImportPrograms(ctx, urls)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		switch result.Status {
+		case service.ImportCreated:
+			fmt.Printf("created    %s (%s)\n", result.Program.ProgramURL, result.Program.Name)
+		case service.ImportExists:
+			fmt.Printf("exists     %s (%s)\n", result.Program.ProgramURL, result.Program.Name)
+		case service.ImportRenamed:
+			fmt.Printf("renamed    %s is now %s (%s)\n", result.Input, result.Program.ProgramURL, result.Program.Name)
+		case service.ImportNotFound:
+			failed++
+			fmt.Printf("not found  %s", result.Input)
+			if len(result.Suggestions) > 0 {
+				fmt.Printf(", did you mean %s?", strings.Join(result.Suggestions, " or "))
+			}
+			fmt.Println()
+		default:
+			failed++
+			fmt.Printf("invalid    %s: %s\n", result.Input, result.Reason)
+		}
+	}
+
+	if *scan {
+		for _, result := range results {
+			if result.Status != service.ImportCreated {
+				continue
+			}
+			scan, err := monitorService.RescanProgram(ctx, result.Program)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("scanned    %s: %s, %d assets\n", result.Program.ProgramURL, scan.Status, scan.AssetsFound)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d program URLs were not added", failed, len(results))
+	}
+	return nil
+}
+
+// resolveProgram looks up a program by its program URL, or by its handle
+// such as "acme" or "hackerone/acme"
+func resolveProgram(ctx context.Context, db *sqlx.DB, monitorService *service.MonitorService, ref string) (*database.Program, error) {
+	if !strings.Contains(ref, "://") {
+		return monitorService.FindProgram(ctx, ref)
+	}
+
+	program, err := database.NewProgramRepository(db).ResolveProgramByURL(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if program == nil {
+		return nil, fmt.Errorf("%w: %s", service.ErrProgramNotFound, ref)
+	}
+	return program, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runPrune deletes the stored asset responses outside the retention, or only
+// counts them with --dry-run
+func runPrune(ctx context.Context, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	retention := monitorService.ResponseRetention()
+
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	keep := fs.Int("keep", retention.KeepPerAsset, "responses kept per asset and method (0 keeps all)")
+	maxAge := fs.Duration("max-age", retention.MaxAge, "delete responses older than this, e.g. 720h (0 keeps them)")
+	dryRun := fs.Bool("dry-run", false, "only count the responses that would be deleted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *keep < 0 || *maxAge < 0 {
+		return fmt.Errorf("--keep and --max-age must not be negative")
+	}
+	retention = database.ResponseRetention{KeepPerAsset: *keep, MaxAge: *maxAge}
+	if !retention.Enabled() {
+		return fmt.Errorf("no retention to prune by: set --keep or --max-age, or RESPONSE_RETENTION_PER_ASSET or RESPONSE_RETENTION_MAX_AGE")
+	}
+
+	if *dryRun {
+		count, err := database.NewAssetRepository(db).CountPrunableResponses(ctx, retention)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d asset responses are outside the retention\n", count)
+		if count > 0 {
+			fmt.Printf("Run 'monitor-agent prune' without --dry-run to delete them\n")
+		}
+		return nil
+	}
+
+	pruned, err := monitorService.PruneResponses(ctx, retention)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pruned %d asset responses\n", pruned)
+	return nil
+}
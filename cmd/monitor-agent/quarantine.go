@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+)
+
+// runQuarantine lists the assets whose scope root left their program's scope,
+// with when each one is or was quarantined
+func runQuarantine(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("quarantine", flag.ExitOnError)
+	programURL := fs.String("program", "", "only list assets of this program URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var programID *uuid.UUID
+	if *programURL != "" {
+		program, err := resolveQuotaProgram(ctx, db, *programURL)
+		if err != nil {
+			return err
+		}
+		programID = &program.ID
+	}
+
+	assets, err := database.NewAssetRepository(db).GetScopeMissingAssets(ctx, programID)
+	if err != nil {
+		return err
+	}
+
+	mode := cfg.Quarantine.Mode
+	if mode == "" {
+		mode = config.QuarantineOff
+	}
+	fmt.Printf("\n=== Assets Out of Scope (mode: %s, grace: %s) ===\n", mode, cfg.Quarantine.Grace)
+
+	var quarantined int
+	for _, asset := range assets {
+		state := "quarantine due " + asset.ScopeMissingSince.Add(cfg.Quarantine.Grace).Format("2006-01-02 15:04")
+		if asset.Status == database.AssetStatusQuarantined {
+			state = "quarantined"
+			quarantined++
+		}
+		fmt.Printf("%-50s out of scope since %s  (%s)\n", asset.URL, asset.ScopeMissingSince.Format("2006-01-02 15:04"), state)
+	}
+	fmt.Printf("\n%d assets out of scope, %d quarantined\n", len(assets), quarantined)
+
+	if mode == config.QuarantineOff && len(assets) > 0 {
+		fmt.Printf("SCOPE_QUARANTINE_MODE is off, so no further assets are marked or quarantined\n")
+	}
+
+	return nil
+}
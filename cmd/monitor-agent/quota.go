@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runQuota dispatches the quota subcommands
+func runQuota(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent quota <set|show|alerts> [flags]")
+	}
+
+	switch args[0] {
+	case "set":
+		return runQuotaSet(ctx, db, args[1:])
+	case "show":
+		return runQuotaShow(ctx, cfg, db, args[1:])
+	case "alerts":
+		return runQuotaAlerts(ctx, db, args[1:])
+	default:
+		return fmt.Errorf("unknown quota command: %s", args[0])
+	}
+}
+
+// resolveQuotaProgram looks up the program a quota command applies to
+func resolveQuotaProgram(ctx context.Context, db *sqlx.DB, programURL string) (*database.Program, error) {
+	if programURL == "" {
+		return nil, fmt.Errorf("--program is required")
+	}
+
+	program, err := database.NewProgramRepository(db).ResolveProgramByURL(ctx, programURL)
+	if err != nil {
+		return nil, err
+	}
+	if program == nil {
+		return nil, fmt.Errorf("program not found: %s", programURL)
+	}
+
+	return program, nil
+}
+
+// runQuotaSet stores asset quota bounds for a program
+func runQuotaSet(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("quota set", flag.ExitOnError)
+	programURL := fs.String("program", "", "program URL")
+	maxDrop := fs.Float64("max-drop", -1, "alert when assets seen drop by more than this percentage (-1 uses the default, 0 disables)")
+	maxGrowth := fs.Int("max-growth", -1, "alert when assets seen grow by more than this many (-1 uses the default, 0 disables)")
+	disable := fs.Bool("disable", false, "disable quota alerts for the program")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *maxDrop > 100 {
+		return fmt.Errorf("--max-drop must be at most 100")
+	}
+
+	program, err := resolveQuotaProgram(ctx, db, *programURL)
+	if err != nil {
+		return err
+	}
+
+	bounds := &database.ProgramAssetBounds{
+		ProgramID: program.ID,
+		Disabled:  *disable,
+	}
+	if *maxDrop >= 0 {
+		bounds.MaxDropPercent = maxDrop
+	}
+	if *maxGrowth >= 0 {
+		bounds.MaxGrowth = maxGrowth
+	}
+
+	if err := database.NewQuotaRepository(db).SetBounds(ctx, bounds); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated asset quota bounds for %s\n", program.Name)
+	return nil
+}
+
+// runQuotaShow prints the effective asset quota bounds for a program
+func runQuotaShow(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("quota show", flag.ExitOnError)
+	programURL := fs.String("program", "", "program URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	program, err := resolveQuotaProgram(ctx, db, *programURL)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := database.NewQuotaRepository(db).GetBounds(ctx, program.ID)
+	if err != nil {
+		return err
+	}
+
+	bounds, enabled := service.EffectiveBounds(cfg.Quota, overrides)
+
+	fmt.Printf("\n=== Asset Quota: %s ===\n", program.Name)
+	if !enabled {
+		fmt.Printf("Alerts:      disabled\n")
+		return nil
+	}
+	fmt.Printf("Max drop:    %.1f%% (when the previous scan saw at least %d assets)\n", bounds.MaxDropPercent, bounds.MinAssets)
+	fmt.Printf("Max growth:  %d assets\n", bounds.MaxGrowth)
+	if overrides == nil {
+		fmt.Printf("Source:      defaults\n")
+	} else {
+		fmt.Printf("Source:      program overrides\n")
+	}
+
+	return nil
+}
+
+// runQuotaAlerts lists recent asset quota alerts
+func runQuotaAlerts(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("quota alerts", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "number of alerts to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	alerts, err := database.NewQuotaRepository(db).GetRecentAlerts(ctx, *limit)
+	if err != nil {
+		return err
+	}
+
+	if len(alerts) == 0 {
+		fmt.Println("No asset quota alerts")
+		return nil
+	}
+
+	programRepo := database.NewProgramRepository(db)
+	names := make(map[string]string)
+
+	fmt.Printf("\n=== Asset Quota Alerts ===\n")
+	for _, alert := range alerts {
+		name, ok := names[alert.ProgramID.String()]
+		if !ok {
+			name = alert.ProgramID.String()
+			if program, err := programRepo.GetProgramByID(ctx, alert.ProgramID); err == nil && program != nil {
+				name = program.Name
+			}
+			names[alert.ProgramID.String()] = name
+		}
+
+		fmt.Printf("  - %s [%s] %s: %s\n",
+			alert.CreatedAt.Format("2006-01-02 15:04:05"),
+			alert.Kind,
+			name,
+			alert.Message)
+	}
+
+	return nil
+}
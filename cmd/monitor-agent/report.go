@@ -0,0 +1,484 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/objectstore"
+	"github.com/monitor-agent/internal/report"
+	"github.com/monitor-agent/internal/service"
+	"github.com/monitor-agent/internal/version"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultStatusPageDir is where `report html` writes when STATUS_PAGE_DIR is not set
+const defaultStatusPageDir = "status"
+
+// runReport dispatches the report subcommands
+func runReport(ctx context.Context, cfg *config.Config, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent report <html|coverage|share> [flags] | report --scan <id>[,<id>...] [flags]")
+	}
+	if strings.HasPrefix(args[0], "-") {
+		return runReportScan(ctx, cfg, db, args)
+	}
+
+	switch args[0] {
+	case "html":
+		return runReportHTML(ctx, cfg, monitorService, args[1:])
+	case "coverage":
+		return runReportCoverage(ctx, db, args[1:])
+	case "share":
+		return runReportShare(ctx, cfg, db, args[1:])
+	default:
+		return fmt.Errorf("unknown report command: %s", args[0])
+	}
+}
+
+// runReportHTML writes the static status page
+func runReportHTML(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService, args []string) error {
+	dir := cfg.StatusPage.Dir
+	if dir == "" {
+		dir = defaultStatusPageDir
+	}
+
+	fs := flag.NewFlagSet("report html", flag.ExitOnError)
+	out := fs.String("out", dir, "directory to write index.html and status.json to")
+	title := fs.String("title", cfg.StatusPage.Title, "page title")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	status, err := writeStatusPage(ctx, monitorService, *out, *title)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Status page written to %s (%s, %d programs, %d assets)\n",
+		filepath.Join(*out, report.HTMLFile), status.State, status.Programs, status.Assets)
+	return nil
+}
+
+// writeStatusPage renders the current statistics into a status page in dir
+func writeStatusPage(ctx context.Context, monitorService *service.MonitorService, dir, title string) (*report.Status, error) {
+	stats, err := monitorService.GetProgramStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	status := report.NewStatus(title, stats, time.Now())
+	if err := report.WriteFiles(dir, status); err != nil {
+		return nil, fmt.Errorf("failed to write status page: %w", err)
+	}
+
+	return status, nil
+}
+
+// refreshStatusPage rewrites the configured status page after a scan. A
+// failure is only logged since the scan itself is done.
+func refreshStatusPage(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService) {
+	if cfg.StatusPage.Dir == "" {
+		return
+	}
+
+	status, err := writeStatusPage(ctx, monitorService, cfg.StatusPage.Dir, cfg.StatusPage.Title)
+	if err != nil {
+		logrus.Warnf("Failed to refresh status page: %v", err)
+		return
+	}
+	logrus.Infof("Status page in %s refreshed (%s)", cfg.StatusPage.Dir, status.State)
+}
+
+// runReportCoverage compares, per program, the subdomains discovery found with
+// those the prober returned results for and those that exist, over the last
+// scans. With --program the program's scope domains are broken down as well.
+func runReportCoverage(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("report coverage", flag.ExitOnError)
+	programURL := fs.String("program", "", "only report this program, broken down by scope domain")
+	scans := fs.Int("scans", 5, "number of recent scans of each program to add up")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scans < 1 {
+		return fmt.Errorf("--scans must be at least 1")
+	}
+
+	var programID *uuid.UUID
+	if *programURL != "" {
+		program, err := resolveQuotaProgram(ctx, db, *programURL)
+		if err != nil {
+			return err
+		}
+		programID = &program.ID
+	}
+
+	coverageRepo := database.NewCoverageRepository(db)
+	programs, err := coverageRepo.GetProgramCoverage(ctx, programID, *scans)
+	if err != nil {
+		return err
+	}
+	if len(programs) == 0 {
+		fmt.Println("No coverage recorded yet; it is recorded by scans that probe with HTTPX")
+		return nil
+	}
+
+	fmt.Printf("\n=== Probe Coverage (last %d scans) ===\n", *scans)
+	fmt.Printf("  %-30s %5s %10s %8s %7s %8s %6s %5s\n", "PROGRAM", "SCANS", "DISCOVERED", "VALID", "PROBED", "RESOLVED", "LIVE", "GAPS")
+	for _, summary := range programs {
+		printCoverage(fmt.Sprintf("%s (%s)", summary.ProgramName, summary.Platform), summary)
+	}
+
+	if programID != nil {
+		domains, err := coverageRepo.GetDomainCoverage(ctx, *programID, *scans)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n=== Scope Domains ===\n")
+		fmt.Printf("  %-30s %5s %10s %8s %7s %8s %6s %5s\n", "DOMAIN", "SCANS", "DISCOVERED", "VALID", "PROBED", "RESOLVED", "LIVE", "GAPS")
+		for _, summary := range domains {
+			printCoverage(summary.Domain, summary)
+		}
+	}
+
+	fmt.Println("\nPROBED is the share of valid subdomains the prober returned a result for; GAPS counts scans where it")
+	fmt.Println("returned fewer. Rows marked ! came back short in every scan, a systematic gap rather than a flaky run.")
+	return nil
+}
+
+// printCoverage prints one row of the coverage report
+func printCoverage(name string, summary *database.CoverageSummary) {
+	marker := " "
+	if summary.SystematicGap() {
+		marker = "!"
+	}
+
+	fmt.Printf("%s %-30s %5d %10d %8d %6s%% %8d %6d %5d\n", marker, name, summary.Scans, summary.Discovered,
+		summary.Valid, coveragePercent(summary.Probed, summary.Valid), summary.Resolved, summary.Live, summary.GapScans)
+}
+
+// coveragePercent formats part as a percentage of total
+func coveragePercent(part, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", float64(part)*100/float64(total))
+}
+
+// runReportShare uploads the report of a scan to object storage, unless it
+// was uploaded before, and prints a presigned URL clients can download it
+// with for the given time without database or dashboard access
+func runReportShare(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("report share", flag.ExitOnError)
+	scanArg := fs.String("scan", "", "ID of the scan to share")
+	ttl := fs.Duration("ttl", 24*time.Hour, "how long the link stays valid (at most 168h)")
+	format := fs.String("format", report.ScanFormatJSON, "report format: json or csv")
+	reupload := fs.Bool("reupload", false, "upload the report again even if it was stored before")
+	excludeSources := excludeSourceFlag(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scanArg == "" {
+		return fmt.Errorf("usage: monitor-agent report share --scan <id> [--ttl 24h] [--format json|csv] [--exclude-source chaosdb]")
+	}
+	if *format != report.ScanFormatJSON && *format != report.ScanFormatCSV {
+		return fmt.Errorf("--format must be json or csv")
+	}
+	if *ttl <= 0 || *ttl > objectstore.MaxPresignTTL {
+		return fmt.Errorf("--ttl must be more than 0 and at most %s", objectstore.MaxPresignTTL)
+	}
+	if cfg.ObjectStore.Bucket == "" {
+		return fmt.Errorf("sharing reports requires S3_BUCKET")
+	}
+
+	scanID, err := uuid.Parse(*scanArg)
+	if err != nil {
+		return fmt.Errorf("invalid scan ID %q: %w", *scanArg, err)
+	}
+
+	store, err := objectstore.NewClient(objectstore.Config{
+		Bucket:          cfg.ObjectStore.Bucket,
+		Region:          cfg.ObjectStore.Region,
+		Endpoint:        cfg.ObjectStore.Endpoint,
+		PathStyle:       cfg.ObjectStore.PathStyle,
+		AccessKeyID:     cfg.ObjectStore.AccessKeyID,
+		SecretAccessKey: cfg.ObjectStore.SecretAccessKey,
+		SessionToken:    cfg.ObjectStore.SessionToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	artifactRepo := database.NewScanArtifactRepository(db)
+	artifact, err := artifactRepo.GetScanArtifact(ctx, scanID, *format)
+	if err != nil {
+		return err
+	}
+
+	// A stored report is reused as long as it is in the configured bucket;
+	// reports of a scan that is still running, and reports leaving out
+	// sources, are uploaded again each time
+	excluded := excludeSources()
+	if artifact == nil || artifact.Bucket != store.Bucket() || *reupload || len(excluded) > 0 {
+		artifact, err = uploadScanReport(ctx, cfg, db, store, scanID, *format, excluded)
+		if err != nil {
+			return err
+		}
+	} else {
+		logrus.Infof("Reusing the report of scan %s uploaded %s", scanID, artifact.UploadedAt.Format(time.RFC3339))
+	}
+
+	signedURL, err := store.PresignGet(artifact.ObjectKey, *ttl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Report of scan %s (%s, %d bytes), valid until %s:\n%s\n",
+		scanID, *format, artifact.SizeBytes, time.Now().Add(*ttl).UTC().Format(time.RFC3339), signedURL)
+	return nil
+}
+
+// uploadScanReport renders the report of a scan without the assets only the
+// excluded sources found, stores it in the object store and records where it
+// was stored
+func uploadScanReport(ctx context.Context, cfg *config.Config, db *sqlx.DB, store *objectstore.Client, scanID uuid.UUID, format string, excludeSources []string) (*database.ScanArtifact, error) {
+	scan, err := database.NewScanRepository(db).GetScanByID(ctx, scanID)
+	if err != nil {
+		return nil, err
+	}
+	if scan == nil {
+		return nil, fmt.Errorf("scan %s not found", scanID)
+	}
+
+	program, err := database.NewProgramRepository(db).GetProgramByID(ctx, scan.ProgramID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program of scan %s: %w", scanID, err)
+	}
+	if program == nil {
+		return nil, fmt.Errorf("program of scan %s not found", scanID)
+	}
+
+	newAssets, err := database.NewAssetRepository(db).GetAssetsByFirstScanID(ctx, scanID)
+	if err != nil {
+		return nil, err
+	}
+	newAssets = database.ExcludeSources(newAssets, excludeSources)
+
+	content, contentType, err := report.EncodeScanReport(format, program, scan, newAssets, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	key := report.ScanReportKey(cfg.ObjectStore.Prefix, program, scanID, format)
+	if err := store.Put(ctx, key, content, contentType); err != nil {
+		return nil, err
+	}
+	logrus.Infof("Uploaded the report of scan %s to s3://%s/%s", scanID, store.Bucket(), key)
+
+	artifact := &database.ScanArtifact{
+		ScanID:      scanID,
+		Format:      format,
+		Bucket:      store.Bucket(),
+		ObjectKey:   key,
+		ContentType: contentType,
+		SizeBytes:   int64(len(content)),
+	}
+	if scan.Status == "running" || len(excludeSources) > 0 {
+		// Not recorded, so the finished or complete report is uploaded the next time
+		return artifact, nil
+	}
+	if err := database.NewScanArtifactRepository(db).SaveScanArtifact(ctx, artifact); err != nil {
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// runReportScan regenerates the summary report of historical scans: new
+// programs, scope changes, new and dead assets, and errors
+func runReportScan(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	defaultFormat := cfg.ScanReport.Format
+	if defaultFormat == "" {
+		defaultFormat = report.SummaryFormatMarkdown
+	}
+
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	scanArg := fs.String("scan", "", "comma-separated IDs of the scans to report")
+	format := fs.String("format", defaultFormat, "report format: markdown or html")
+	out := fs.String("out", "", "file to write the report to (default stdout)")
+	webhook := fs.String("webhook", "", "also post the report to this webhook URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scanArg == "" {
+		return fmt.Errorf("usage: monitor-agent report --scan <id>[,<id>...] [--format markdown|html] [--out FILE] [--webhook URL]")
+	}
+	if *format != report.SummaryFormatMarkdown && *format != report.SummaryFormatHTML {
+		return fmt.Errorf("--format must be markdown or html")
+	}
+
+	scanRepo := database.NewScanRepository(db)
+	var scans []*database.Scan
+	for _, arg := range strings.Split(*scanArg, ",") {
+		scanID, err := uuid.Parse(strings.TrimSpace(arg))
+		if err != nil {
+			return fmt.Errorf("invalid scan ID %q: %w", arg, err)
+		}
+		scan, err := scanRepo.GetScanByID(ctx, scanID)
+		if err != nil {
+			return err
+		}
+		if scan == nil {
+			return fmt.Errorf("scan %s not found", scanID)
+		}
+		scans = append(scans, scan)
+	}
+
+	programScans, err := loadProgramScans(ctx, db, scans, cfg.Provenance.ExcludeSources)
+	if err != nil {
+		return err
+	}
+	summary := report.NewScanSummary(programScans, time.Now())
+	content, _, err := report.EncodeScanSummary(*format, summary)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(content)
+	} else if err = os.WriteFile(*out, content, 0o644); err == nil {
+		fmt.Printf("Report of %d scans written to %s\n", summary.Scans, *out)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if *webhook != "" {
+		if err := report.PostScanSummary(ctx, scanReportClient(cfg), *webhook, cfg.ScanReport.WebhookSecret, summary, *format, content); err != nil {
+			return err
+		}
+		logrus.Infof("Report of %d scans posted to %s", summary.Scans, *webhook)
+	}
+	return nil
+}
+
+// writeScanReport writes and posts the summary of the scans started since a
+// scan run began, as configured. A failure is only logged since the scans
+// themselves are done.
+func writeScanReport(ctx context.Context, cfg *config.Config, db *sqlx.DB, since time.Time) {
+	if !cfg.ScanReport.Enabled() {
+		return
+	}
+
+	scans, err := database.NewScanRepository(db).GetScansStartedSince(ctx, since)
+	if err != nil {
+		logrus.Warnf("Failed to write scan report: %v", err)
+		return
+	}
+	if len(scans) == 0 {
+		return
+	}
+
+	programScans, err := loadProgramScans(ctx, db, scans, cfg.Provenance.ExcludeSources)
+	if err != nil {
+		logrus.Warnf("Failed to write scan report: %v", err)
+		return
+	}
+
+	format := cfg.ScanReport.Format
+	if format == "" {
+		format = report.SummaryFormatMarkdown
+	}
+	summary := report.NewScanSummary(programScans, time.Now())
+	content, _, err := report.EncodeScanSummary(format, summary)
+	if err != nil {
+		logrus.Warnf("Failed to write scan report: %v", err)
+		return
+	}
+
+	if cfg.ScanReport.Dir != "" {
+		path := filepath.Join(cfg.ScanReport.Dir, report.SummaryFilename(summary, format))
+		if err := os.MkdirAll(cfg.ScanReport.Dir, 0o755); err != nil {
+			logrus.Warnf("Failed to write scan report: %v", err)
+		} else if err := os.WriteFile(path, content, 0o644); err != nil {
+			logrus.Warnf("Failed to write scan report: %v", err)
+		} else {
+			logrus.Infof("Scan report written to %s", path)
+		}
+	}
+
+	if cfg.ScanReport.WebhookURL != "" {
+		if err := report.PostScanSummary(ctx, scanReportClient(cfg), cfg.ScanReport.WebhookURL, cfg.ScanReport.WebhookSecret, summary, format, content); err != nil {
+			logrus.Warnf("Failed to post scan report: %v", err)
+		} else {
+			logrus.Info("Scan report posted to the webhook")
+		}
+	}
+}
+
+// scanReportClient returns the HTTP client scan reports are posted with
+func scanReportClient(cfg *config.Config) *resty.Client {
+	return resty.New().
+		SetTimeout(cfg.HTTP.Timeout).
+		SetRetryCount(cfg.HTTP.RetryAttempts).
+		SetRetryWaitTime(cfg.HTTP.RetryDelay).
+		SetRetryMaxWaitTime(cfg.HTTP.RetryDelay*2).
+		SetHeader("User-Agent", version.UserAgent())
+}
+
+// loadProgramScans gathers what the scans recorded for their summary: the
+// program, its scope change, the new assets without those only the excluded
+// sources found, the asset changes and the discovery failures
+func loadProgramScans(ctx context.Context, db *sqlx.DB, scans []*database.Scan, excludeSources []string) ([]*report.ProgramScan, error) {
+	programRepo := database.NewProgramRepository(db)
+	scanRepo := database.NewScanRepository(db)
+	scopeRepo := database.NewScopeRepository(db)
+	assetRepo := database.NewAssetRepository(db)
+	changeRepo := database.NewAssetChangeRepository(db)
+	failureRepo := database.NewDiscoveryFailureRepository(db)
+
+	programScans := make([]*report.ProgramScan, 0, len(scans))
+	for _, scan := range scans {
+		program, err := programRepo.GetProgramByID(ctx, scan.ProgramID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get program of scan %s: %w", scan.ID, err)
+		}
+		if program == nil {
+			return nil, fmt.Errorf("program of scan %s not found", scan.ID)
+		}
+
+		ps := &report.ProgramScan{Program: program, Scan: scan}
+		if ps.FirstScan, err = scanRepo.IsFirstProgramScan(ctx, scan); err != nil {
+			return nil, err
+		}
+		if ps.Scope, ps.PreviousScope, err = scopeRepo.GetScanScopeChange(ctx, scan.ID); err != nil {
+			return nil, err
+		}
+		if ps.NewAssets, err = assetRepo.GetAssetsByFirstScanID(ctx, scan.ID); err != nil {
+			return nil, err
+		}
+		ps.NewAssets = database.ExcludeSources(ps.NewAssets, excludeSources)
+		if ps.Changes, err = changeRepo.GetScanChanges(ctx, scan.ID); err != nil {
+			return nil, err
+		}
+
+		end := time.Now()
+		if scan.CompletedAt != nil {
+			end = *scan.CompletedAt
+		}
+		if ps.Failures, err = failureRepo.GetProgramFailuresBetween(ctx, program.ID, scan.StartedAt, end); err != nil {
+			return nil, err
+		}
+		programScans = append(programScans, ps)
+	}
+	return programScans, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/scoring"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runRescore recomputes asset scores with the configured scoring model, e.g.
+// after SCORING_FILE changed, and lists the highest scored assets
+func runRescore(ctx context.Context, cfg *config.Config, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("rescore", flag.ExitOnError)
+	programURL := fs.String("program", "", "only rescore assets of this program URL")
+	top := fs.Int("top", 10, "number of highest scored assets to list afterwards (0 lists none)")
+	check := fs.Bool("check", false, "only validate the scoring model and print its weights")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// A broken scoring file falls back to the built-in weights when scanning;
+	// rescoring with them would overwrite every score, so it is an error here
+	source := "built-in weights"
+	if cfg.Scoring.File != "" {
+		if _, err := scoring.LoadFile(cfg.Scoring.File); err != nil {
+			return err
+		}
+		source = cfg.Scoring.File
+	}
+	model := monitorService.ScoringModel()
+
+	if *check {
+		printScoringModel(model, source)
+		return nil
+	}
+
+	var programID *uuid.UUID
+	if *programURL != "" {
+		program, err := resolveQuotaProgram(ctx, db, *programURL)
+		if err != nil {
+			return err
+		}
+		programID = &program.ID
+	}
+
+	result, err := monitorService.RescoreAssets(ctx, programID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rescored %d assets with model %s (%s), %d changed\n", result.Assets, result.Model, source, result.Changed)
+
+	if *top <= 0 {
+		return nil
+	}
+
+	assets, err := database.NewScoreRepository(db).GetTopScoredAssets(ctx, programID, *top)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Highest Scored Assets ===\n")
+	for _, asset := range assets {
+		fmt.Printf("%8.2f  %-50s %s\n", asset.Score, asset.URL, asset.Liveness)
+	}
+
+	return nil
+}
+
+// printScoringModel prints a scoring model's default weights and overrides
+func printScoringModel(model *scoring.Model, source string) {
+	fmt.Printf("\n=== Scoring Model %s (%s) ===\n", model.Fingerprint(), source)
+	fmt.Printf("  default: %s\n", formatWeights(model.WeightsFor("", nil)))
+	for _, override := range model.Overrides() {
+		var targets []string
+		if len(override.Programs) > 0 {
+			targets = append(targets, "programs="+strings.Join(override.Programs, ","))
+		}
+		if len(override.Tags) > 0 {
+			targets = append(targets, "tags="+strings.Join(override.Tags, ","))
+		}
+		fmt.Printf("  - %s (%s): %s\n", override.Name, strings.Join(targets, " "), formatWeights(override.Weights))
+	}
+	fmt.Printf("%d overrides OK\n", len(model.Overrides()))
+}
+
+// formatWeights formats weights as signal=weight pairs in signal order
+func formatWeights(weights scoring.Weights) string {
+	pairs := make([]string, 0, len(weights))
+	for _, signal := range weights.Sorted() {
+		pairs = append(pairs, fmt.Sprintf("%s=%g", signal, weights[signal]))
+	}
+	return strings.Join(pairs, " ")
+}
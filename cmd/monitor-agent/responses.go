@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/objectstore"
+	"github.com/monitor-agent/internal/service"
+)
+
+// runResponses dispatches the responses subcommands
+func runResponses(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent responses show [flags] <asset>")
+	}
+
+	switch args[0] {
+	case "show":
+		return runResponsesShow(ctx, cfg, db, args[1:])
+	default:
+		return fmt.Errorf("unknown responses command: %s", args[0])
+	}
+}
+
+// runResponsesShow prints the latest stored response for an asset, or its
+// capture history. Bodies offloaded to the body store are downloaded from it.
+func runResponsesShow(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("responses show", flag.ExitOnError)
+	history := fs.Bool("history", false, "list prior captures instead of the latest response")
+	bodyBytes := fs.Int("body-bytes", 2000, "maximum body bytes to print (0 for all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: monitor-agent responses show [--history] [--body-bytes N] <asset id|url|host>")
+	}
+
+	bodyStore, err := service.NewBodyStore(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create body store: %w", err)
+	}
+
+	assetRepo := database.NewAssetRepository(db)
+	tlsFindingRepo := database.NewTLSFindingRepository(db)
+	assets, err := findAssets(ctx, assetRepo, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(assets) == 0 {
+		return fmt.Errorf("no asset found for %s", fs.Arg(0))
+	}
+
+	for _, asset := range assets {
+		fmt.Printf("\n=== %s ===\n", asset.URL)
+		fmt.Printf("Asset:          %s\n", asset.ID)
+		fmt.Printf("Program:        %s\n", asset.ProgramURL)
+		fmt.Printf("Status:         %s\n", asset.Status)
+		if asset.Liveness != "" {
+			fmt.Printf("Liveness:       %s\n", asset.Liveness)
+		}
+		if asset.LastProbeErrorAt != nil {
+			fmt.Printf("Probe error:    %s (%s)\n", asset.LastProbeError, asset.LastProbeErrorAt.Format("2006-01-02 15:04:05"))
+		}
+		fmt.Printf("First source:   %s\n", asset.FirstSource)
+		if asset.FirstScanID != nil {
+			fmt.Printf("First scan:     %s\n", asset.FirstScanID)
+		}
+		if variants, err := assetRepo.GetAssetSchemeVariants(ctx, asset.ID); err == nil && len(variants) > 0 {
+			schemes := make([]string, 0, len(variants))
+			for _, variant := range variants {
+				schemes = append(schemes, variant.Scheme)
+			}
+			fmt.Printf("Schemes:        %s\n", strings.Join(schemes, ", "))
+		}
+		if findings, err := tlsFindingRepo.GetAssetTLSFindings(ctx, asset.ID); err == nil {
+			for _, finding := range findings {
+				fmt.Printf("TLS finding:    [%s] %s: %s (since %s)\n", finding.Severity, finding.CheckName, finding.Detail,
+					finding.FirstSeen.Format("2006-01-02 15:04:05"))
+			}
+		}
+
+		if *history {
+			if err := printResponseHistory(ctx, assetRepo, asset); err != nil {
+				return err
+			}
+			continue
+		}
+
+		response, err := assetRepo.GetLatestAssetResponseByAssetID(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+		if response == nil {
+			fmt.Println("No stored responses")
+			continue
+		}
+		printResponse(ctx, bodyStore, response, *bodyBytes)
+	}
+
+	return nil
+}
+
+// findAssets resolves an asset ID, URL or bare host to the matching assets
+func findAssets(ctx context.Context, assetRepo *database.AssetRepository, target string) ([]*database.Asset, error) {
+	if id, err := uuid.Parse(target); err == nil {
+		asset, err := assetRepo.GetAssetByID(ctx, id)
+		if err != nil || asset == nil {
+			return nil, err
+		}
+		return []*database.Asset{asset}, nil
+	}
+
+	url := strings.TrimSuffix(target, "/")
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+	return assetRepo.GetAssetsByURL(ctx, url)
+}
+
+// printResponseHistory lists every stored capture for an asset, newest first
+func printResponseHistory(ctx context.Context, assetRepo *database.AssetRepository, asset *database.Asset) error {
+	responses, err := assetRepo.GetAssetResponsesByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	if len(responses) == 0 {
+		fmt.Println("No stored responses")
+		return nil
+	}
+
+	fmt.Printf("\n%-20s  %-6s  %-6s  %-8s  %-8s  %-14s  %s\n", "CAPTURED", "METHOD", "STATUS", "TIME", "BODY", "REDIRECTS", "ID")
+	for _, response := range responses {
+		fmt.Printf("%-20s  %-6s  %-6d  %-8s  %-8d  %-14s  %s\n",
+			response.CreatedAt.Format("2006-01-02 15:04:05"),
+			response.Method,
+			response.StatusCode,
+			fmt.Sprintf("%dms", response.ResponseTime),
+			bodySize(response),
+			redirectSummary(response),
+			response.ID)
+	}
+
+	return nil
+}
+
+// printResponse prints a stored response's status, headers and body snippet
+func printResponse(ctx context.Context, bodyStore objectstore.Store, response *database.AssetResponse, bodyBytes int) {
+	fmt.Printf("Captured:       %s\n", response.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Method:         %s\n", response.Method)
+	fmt.Printf("Status code:    %d\n", response.StatusCode)
+	fmt.Printf("Response time:  %dms\n", response.ResponseTime)
+	if response.RedirectStatus != "" {
+		fmt.Printf("Redirects:      %s\n", redirectSummary(response))
+		fmt.Printf("Initial status: %d\n", response.InitialStatusCode)
+		if response.FinalURL != "" {
+			fmt.Printf("Final URL:      %s\n", response.FinalURL)
+		}
+		if response.MetaRefresh != "" {
+			fmt.Printf("Meta refresh:   %s\n", response.MetaRefresh)
+		}
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(response.Headers), &headers); err == nil && len(headers) > 0 {
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Println("\nHeaders:")
+		for _, name := range names {
+			fmt.Printf("  %s: %s\n", name, headers[name])
+		}
+	}
+
+	if response.Method == http.MethodHead {
+		fmt.Println("\nBody: not captured by HEAD probes, see --history for earlier GET captures")
+		return
+	}
+	if err := service.LoadResponseBody(ctx, bodyStore, response); err != nil {
+		fmt.Printf("\nBody (%d bytes): unavailable: %v\n", bodySize(response), err)
+		return
+	}
+	fmt.Printf("\nBody (%d bytes):\n", len(response.Body))
+	if response.BodyRef != "" {
+		fmt.Printf("Stored in:      %s\n", response.BodyRef)
+	}
+	fmt.Println(bodySnippet(response.Body, bodyBytes))
+}
+
+// bodySize returns the size of a response's body, wherever it is stored
+func bodySize(response *database.AssetResponse) int {
+	if response.BodyRef != "" {
+		return response.BodySize
+	}
+	return len(response.Body)
+}
+
+// redirectSummary describes the redirects behind a response, e.g. "2 hops, loop"
+func redirectSummary(response *database.AssetResponse) string {
+	if response.RedirectStatus == "" {
+		return "-"
+	}
+	if response.RedirectHops == 1 {
+		return fmt.Sprintf("1 hop, %s", response.RedirectStatus)
+	}
+	return fmt.Sprintf("%d hops, %s", response.RedirectHops, response.RedirectStatus)
+}
+
+// bodySnippet pretty-prints JSON bodies and truncates the result to limit bytes
+func bodySnippet(body string, limit int) string {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(body), "", "  "); err == nil {
+		body = pretty.String()
+	}
+
+	body = strings.TrimSpace(body)
+	if limit > 0 && len(body) > limit {
+		return body[:limit] + fmt.Sprintf("\n... (%d more bytes, use --body-bytes 0 to print all)", len(body)-limit)
+	}
+	return body
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/rules"
+)
+
+// runRules dispatches the rules subcommands
+func runRules(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent rules <check|matches> [flags]")
+	}
+
+	switch args[0] {
+	case "check":
+		return runRulesCheck(cfg, args[1:])
+	case "matches":
+		return runRulesMatches(ctx, db, args[1:])
+	default:
+		return fmt.Errorf("unknown rules command: %s", args[0])
+	}
+}
+
+// runRulesCheck validates a rules file and prints the rules it defines
+func runRulesCheck(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("rules check", flag.ExitOnError)
+	file := fs.String("file", cfg.Rules.File, "rules file to check")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return fmt.Errorf("no rules file configured (set RULES_FILE or use --file)")
+	}
+
+	engine, err := rules.LoadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Triage Rules: %s ===\n", *file)
+	for _, rule := range engine.Rules() {
+		var actions []string
+		if len(rule.Then.Tags) > 0 {
+			actions = append(actions, "tags="+strings.Join(rule.Then.Tags, ","))
+		}
+		if rule.Then.Notify != "" {
+			actions = append(actions, "notify="+rule.Then.Notify)
+		}
+		if len(rule.Then.Enqueue) > 0 {
+			actions = append(actions, "enqueue="+strings.Join(rule.Then.Enqueue, ","))
+		}
+		fmt.Printf("  - %s: %s\n", rule.Name, strings.Join(actions, " "))
+	}
+	fmt.Printf("%d rules OK\n", len(engine.Rules()))
+
+	return nil
+}
+
+// runRulesMatches lists recent triage rule matches
+func runRulesMatches(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("rules matches", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "number of matches to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	matches, err := database.NewTagRepository(db).GetRecentRuleMatches(ctx, *limit)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		fmt.Println("No triage rule matches")
+		return nil
+	}
+
+	assetRepo := database.NewAssetRepository(db)
+
+	fmt.Printf("\n=== Triage Rule Matches ===\n")
+	for _, match := range matches {
+		target := match.AssetID.String()
+		if asset, err := assetRepo.GetAssetByID(ctx, match.AssetID); err == nil && asset != nil {
+			target = asset.URL
+		}
+
+		fmt.Printf("  - %s [%s] %s", match.CreatedAt.Format("2006-01-02 15:04:05"), match.RuleName, target)
+		if match.Notify != "" {
+			fmt.Printf(" notify=%s", match.Notify)
+		}
+		if match.Enqueue != "" {
+			fmt.Printf(" enqueue=%s", match.Enqueue)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+)
+
+// scanOptions are the flags of a scan run from the command line
+type scanOptions struct {
+	resume    bool     // continue the last full scan
+	program   string   // scan only this program, by handle or program URL
+	platforms []string // scan only these platforms
+	retry     bool     // only retry the domains whose discovery or probe failed
+}
+
+// parseScanFlags parses the flags of a scan
+func parseScanFlags(args []string) (*scanOptions, error) {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	resume := fs.Bool("resume", false, "continue the last scan if it did not complete, skipping the programs it processed")
+	program := fs.String("program", "", "scan only this program, by handle (acme or hackerone/acme) or program URL")
+	platformList := fs.String("platforms", "", "comma-separated platforms to scan, e.g. hackerone,bugcrowd; all configured platforms when empty")
+	retryFailed := fs.Bool("retry-failed", false, "only retry the domains whose discovery or probe failed in earlier scans, once their retry is due")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("usage: monitor-agent scan [--resume | --program <handle or URL> | --platforms <names> | --retry-failed]")
+	}
+
+	opts := &scanOptions{resume: *resume, program: strings.TrimSpace(*program), retry: *retryFailed}
+	for _, name := range strings.Split(*platformList, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.platforms = append(opts.platforms, name)
+		}
+	}
+	if *platformList != "" && len(opts.platforms) == 0 {
+		return nil, fmt.Errorf("--platforms lists no platforms")
+	}
+
+	switch {
+	case opts.resume && opts.program != "":
+		return nil, fmt.Errorf("--resume and --program cannot be combined")
+	case opts.resume && len(opts.platforms) > 0:
+		return nil, fmt.Errorf("--resume and --platforms cannot be combined: a resumed scan covers the platforms of the scan it continues")
+	case opts.program != "" && len(opts.platforms) > 0:
+		return nil, fmt.Errorf("--program and --platforms cannot be combined")
+	case opts.retry && (opts.resume || opts.program != "" || len(opts.platforms) > 0):
+		return nil, fmt.Errorf("--retry-failed cannot be combined with --resume, --program or --platforms")
+	}
+	return opts, nil
+}
+
+// runProgramScan scans a single monitored program right away, without
+// scanning the rest of its platform
+func runProgramScan(ctx context.Context, db *sqlx.DB, monitorService *service.MonitorService, ref string) error {
+	program, err := resolveProgram(ctx, db, monitorService, ref)
+	if err != nil {
+		if errors.Is(err, service.ErrProgramNotFound) {
+			return fmt.Errorf("%w; add a program that is not monitored yet with `monitor-agent programs add --scan <url>`", err)
+		}
+		return err
+	}
+
+	start := time.Now()
+	scan, err := monitorService.RescanProgram(ctx, program)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Scanned %s (%s) in %v\n", program.Name, program.ProgramURL, time.Since(start).Round(time.Second))
+	fmt.Printf("Scan:   %s\n", scan.ID)
+	fmt.Printf("Status: %s\n", scan.Status)
+	fmt.Printf("Assets: %d found, %d seen\n", scan.AssetsFound, scan.AssetsSeen)
+	if scan.Status == "timed_out" {
+		fmt.Println("The program ran out of time; its remaining domains are continued by the next scan")
+	}
+	return nil
+}
+
+// runRetryFailed retries the domains whose discovery or probe failed
+func runRetryFailed(ctx context.Context, monitorService *service.MonitorService) error {
+	start := time.Now()
+	result, err := monitorService.RetryFailedDiscoveries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retry failed domains: %w", err)
+	}
+
+	if result.Retried == 0 {
+		fmt.Println("No failed domains are due for a retry")
+		return nil
+	}
+	fmt.Printf("Retried %d failed domains in %v\n", result.Retried, time.Since(start).Round(time.Second))
+	fmt.Printf("Recovered: %d\n", result.Recovered)
+	fmt.Printf("Failed:    %d\n", result.Retried-result.Recovered)
+	fmt.Printf("Assets:    %d saved, %d new\n", result.Assets, result.NewAssets)
+	return nil
+}
+
+// runScanCommand dispatches the scan subcommands
+func runScanCommand(ctx context.Context, monitorService *service.MonitorService, args []string) error {
+	switch args[0] {
+	case "cancel":
+		return runScanCancel(ctx, monitorService, args[1:])
+	default:
+		return fmt.Errorf("unknown scan command: %s", args[0])
+	}
+}
+
+// runScanCancel cancels a running scan by ID
+func runScanCancel(ctx context.Context, monitorService *service.MonitorService, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: monitor-agent scan cancel <scan-id>")
+	}
+
+	scanID, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid scan id %q: %w", args[0], err)
+	}
+
+	if err := monitorService.CancelScan(ctx, scanID); err != nil {
+		if errors.Is(err, database.ErrScanNotFound) || errors.Is(err, database.ErrScanNotRunning) {
+			return fmt.Errorf("cannot cancel scan %s: %w", scanID, err)
+		}
+		return fmt.Errorf("failed to cancel scan: %w", err)
+	}
+
+	fmt.Printf("Cancel requested for scan %s; it will stop and be marked cancelled shortly\n", scanID)
+	return nil
+}
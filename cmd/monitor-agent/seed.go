@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/seed"
+	"github.com/sirupsen/logrus"
+)
+
+// runSeed populates the database with synthetic development data
+func runSeed(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	programs := fs.Int("programs", 50, "number of programs to generate")
+	assetsPerProgram := fs.Int("assets-per-program", 200, "number of assets to generate per program")
+	responsesPerAsset := fs.Int("responses-per-asset", 1, "number of HTTP responses to generate per asset")
+	seedValue := fs.Int64("seed", time.Now().UnixNano(), "random seed for reproducible data")
+	force := fs.Bool("force", false, "allow seeding when ENVIRONMENT=production")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cfg.App.Environment == "production" && !*force {
+		return fmt.Errorf("refusing to seed a production database (use --force to override)")
+	}
+
+	logrus.Infof("Seeding %d programs with %d assets each (seed %d)...", *programs, *assetsPerProgram, *seedValue)
+
+	startTime := time.Now()
+	result, err := seed.NewSeeder(db).Run(ctx, seed.Options{
+		Programs:          *programs,
+		AssetsPerProgram:  *assetsPerProgram,
+		ResponsesPerAsset: *responsesPerAsset,
+		Seed:              *seedValue,
+	})
+	if err != nil {
+		return fmt.Errorf("seed failed: %w", err)
+	}
+
+	fmt.Printf("\n=== Seed Complete ===\n")
+	fmt.Printf("Programs:  %d\n", result.Programs)
+	fmt.Printf("Assets:    %d\n", result.Assets)
+	fmt.Printf("Responses: %d\n", result.Responses)
+	fmt.Printf("Scans:     %d\n", result.Scans)
+	fmt.Printf("Duration:  %v\n", time.Since(startTime).Round(time.Millisecond))
+
+	return nil
+}
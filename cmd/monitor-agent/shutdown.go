@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// cancelOnSignal derives a context that is cancelled with service.ErrShutdown
+// on the first SIGINT or SIGTERM, so scans in progress stop and are recorded
+// as aborted. A second signal exits right away. stop must be called once the
+// work is done.
+func cancelOnSignal(ctx context.Context) (_ context.Context, stop func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigChan:
+			logrus.Infof("Received signal %v, stopping the scan (send it again to exit right away)...", sig)
+			signal.Stop(sigChan)
+			cancel(service.ErrShutdown)
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigChan)
+		cancel(nil)
+	}
+}
+
+// abortStaleScans marks the scans an agent that crashed or was killed left
+// running as aborted, so they do not stay running forever
+func abortStaleScans(ctx context.Context, monitorService *service.MonitorService) {
+	if monitorService.Writable() != nil {
+		return
+	}
+
+	aborted, err := monitorService.AbortStaleScans(ctx)
+	if err != nil {
+		logrus.Warnf("Failed to abort stale scans: %v", err)
+		return
+	}
+	if aborted > 0 {
+		logrus.Infof("Marked %d scans left running by a stopped agent as aborted", aborted)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/service"
+	"github.com/monitor-agent/internal/slackbot"
+	"github.com/sirupsen/logrus"
+)
+
+// runSlackBot answers Slack slash commands over socket mode until it receives SIGINT or SIGTERM
+func runSlackBot(ctx context.Context, cfg *config.Config, monitorService *service.MonitorService, args []string) error {
+	fs := flag.NewFlagSet("slack-bot", flag.ExitOnError)
+	command := fs.String("command", cfg.Slack.Command, "slash command the bot answers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cfg.Slack.AppToken == "" {
+		return fmt.Errorf("SLACK_APP_TOKEN is required to run the Slack bot")
+	}
+
+	bot := slackbot.NewBot(&slackbot.Config{
+		AppToken:        cfg.Slack.AppToken,
+		Command:         *command,
+		AllowedUsers:    cfg.Slack.AllowedUsers,
+		AllowedChannels: cfg.Slack.AllowedChannels,
+		Timeout:         cfg.HTTP.Timeout,
+	}, monitorService)
+
+	if len(cfg.Slack.AllowedUsers) == 0 && len(cfg.Slack.AllowedChannels) == 0 {
+		logrus.Warn("SLACK_ALLOWED_USERS and SLACK_ALLOWED_CHANNELS are empty, anyone in the workspace can run commands")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigChan:
+			logrus.Infof("Received signal %v, shutting down Slack bot...", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	logrus.Infof("Starting Slack bot for %s", *command)
+	return bot.Run(ctx)
+}
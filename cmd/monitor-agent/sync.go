@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/api"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/edgesync"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// runSync dispatches the sync subcommands
+func runSync(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent sync <push|serve> [flags]")
+	}
+
+	switch args[0] {
+	case "push":
+		return runSyncPush(ctx, cfg, db, args[1:])
+	case "serve":
+		return runSyncServe(ctx, cfg, db, args[1:])
+	default:
+		return fmt.Errorf("unknown sync command: %s", args[0])
+	}
+}
+
+// runSyncPush pushes local programs and assets to the central server
+func runSyncPush(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("sync push", flag.ExitOnError)
+	serverURL := fs.String("server", cfg.Sync.ServerURL, "central server URL")
+	full := fs.Bool("full", false, "push everything instead of changes since the last push")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *serverURL == "" {
+		return fmt.Errorf("no sync server configured (set SYNC_SERVER_URL or use --server)")
+	}
+	if cfg.Sync.Token == "" {
+		return fmt.Errorf("SYNC_TOKEN is required to push")
+	}
+
+	agentID := cfg.Sync.AgentID
+	if agentID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to determine agent id: %w", err)
+		}
+		agentID = hostname
+	}
+
+	client := edgesync.NewClient(&edgesync.ClientConfig{
+		ServerURL:     *serverURL,
+		Token:         cfg.Sync.Token,
+		Timeout:       cfg.HTTP.Timeout,
+		RetryAttempts: cfg.HTTP.RetryAttempts,
+		RetryDelay:    cfg.HTTP.RetryDelay,
+	})
+	pusher := edgesync.NewPusher(database.NewSyncRepository(db), client, agentID, cfg.Sync.BatchSize)
+
+	startTime := time.Now()
+	summary, err := pusher.Push(ctx, *full)
+	if err != nil {
+		return fmt.Errorf("sync push failed: %w", err)
+	}
+
+	fmt.Printf("\n=== Sync Push Complete ===\n")
+	fmt.Printf("Agent:            %s\n", agentID)
+	fmt.Printf("Server:           %s\n", client.ServerURL())
+	fmt.Printf("Batches:          %d\n", summary.Batches)
+	fmt.Printf("Programs sent:    %d\n", summary.Programs)
+	fmt.Printf("Assets sent:      %d\n", summary.Assets)
+	fmt.Printf("Assets created:   %d\n", summary.Result.AssetsCreated)
+	fmt.Printf("Assets updated:   %d\n", summary.Result.AssetsUpdated)
+	fmt.Printf("Assets skipped:   %d (central copy newer)\n", summary.Result.AssetsSkipped)
+	fmt.Printf("Duration:         %v\n", time.Since(startTime).Round(time.Millisecond))
+
+	return nil
+}
+
+// runSyncServe runs the central aggregation server that edge agents push to
+func runSyncServe(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("sync serve", flag.ExitOnError)
+	addr := fs.String("addr", cfg.Sync.ListenAddr, "listen address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cfg.Sync.Token == "" {
+		return fmt.Errorf("SYNC_TOKEN is required to serve")
+	}
+
+	monitorService := service.NewMonitorService(cfg, db)
+	defer monitorService.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle(edgesync.PushPath, edgesync.NewServer(database.NewSyncRepository(db), cfg.Sync.Token))
+	mux.Handle("/scans/", api.NewServer(monitorService, cfg.Sync.Token))
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logrus.Infof("Sync server listening on %s", *addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("sync server failed: %w", err)
+		}
+		return nil
+	case sig := <-sigChan:
+		logrus.Infof("Received signal %v, shutting down sync server...", sig)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down sync server: %w", err)
+	}
+	return nil
+}
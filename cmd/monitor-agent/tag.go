@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+)
+
+// runTag dispatches the tag subcommands
+func runTag(ctx context.Context, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent tag <add|remove|list> [flags]")
+	}
+
+	switch args[0] {
+	case "add":
+		return runTagAdd(ctx, db, args[1:])
+	case "remove":
+		return runTagRemove(ctx, db, args[1:])
+	case "list":
+		return runTagList(ctx, db, args[1:])
+	default:
+		return fmt.Errorf("unknown tag command: %s", args[0])
+	}
+}
+
+// tagAssets returns the assets of every program stored for a URL, matching
+// any scheme of its host
+func tagAssets(ctx context.Context, db *sqlx.DB, assetURL string) ([]*database.Asset, error) {
+	assets, err := database.NewAssetRepository(db).GetAssetsByURL(ctx, assetURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no asset found for %s", assetURL)
+	}
+	return assets, nil
+}
+
+// runTagAdd tags an asset, optionally with a note
+func runTagAdd(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("tag add", flag.ExitOnError)
+	note := fs.String("note", "", "note on the tags, e.g. what triage found")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("usage: monitor-agent tag add [--note TEXT] <asset-url> <tag>...")
+	}
+
+	tags, err := tagArgs(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	assets, err := tagAssets(ctx, db, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	repo := database.NewTagRepository(db)
+	for _, asset := range assets {
+		for _, tag := range tags {
+			added, err := repo.TagAsset(ctx, asset.ID, tag, "manual", *note)
+			if err != nil {
+				return err
+			}
+			switch {
+			case added:
+				fmt.Printf("Tagged %s (%s) %s\n", asset.URL, asset.ProgramURL, tag)
+			case *note != "":
+				fmt.Printf("Updated the note of %s on %s (%s)\n", tag, asset.URL, asset.ProgramURL)
+			default:
+				fmt.Printf("%s (%s) is already tagged %s\n", asset.URL, asset.ProgramURL, tag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runTagRemove removes tags from an asset
+func runTagRemove(ctx context.Context, db *sqlx.DB, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: monitor-agent tag remove <asset-url> <tag>...")
+	}
+
+	tags, err := tagArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	assets, err := tagAssets(ctx, db, args[0])
+	if err != nil {
+		return err
+	}
+
+	repo := database.NewTagRepository(db)
+	for _, asset := range assets {
+		removed, err := repo.RemoveAssetTags(ctx, asset.ID, tags)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d tags from %s (%s)\n", removed, asset.URL, asset.ProgramURL)
+	}
+
+	return nil
+}
+
+// runTagList prints the tags of an asset, or with --tag the assets carrying a tag
+func runTagList(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("tag list", flag.ExitOnError)
+	tag := fs.String("tag", "", "list the assets carrying this tag instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*tag == "") == (fs.NArg() == 0) {
+		return fmt.Errorf("usage: monitor-agent tag list <asset-url> | tag list --tag TAG")
+	}
+
+	repo := database.NewTagRepository(db)
+	if *tag != "" {
+		tagged, err := repo.GetTaggedAssets(ctx, *tag)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n=== Assets tagged %s ===\n", *tag)
+		for _, asset := range tagged {
+			fmt.Printf("%-50s %-20s %s", asset.URL, asset.Source, asset.CreatedAt.Format("2006-01-02 15:04"))
+			if asset.Note != "" {
+				fmt.Printf("  # %s", asset.Note)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("\n%d assets\n", len(tagged))
+		return nil
+	}
+
+	assets, err := tagAssets(ctx, db, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		tags, err := repo.GetAssetTags(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n=== %s (%s) ===\n", asset.URL, asset.ProgramURL)
+		if asset.Ignored {
+			fmt.Println("Ignored")
+		}
+		for _, assetTag := range tags {
+			fmt.Printf("%-30s %-20s %s", assetTag.Tag, assetTag.Source, assetTag.CreatedAt.Format("2006-01-02 15:04"))
+			if assetTag.Note != "" {
+				fmt.Printf("  # %s", assetTag.Note)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("%d tags\n", len(tags))
+	}
+
+	return nil
+}
+
+// tagArgs validates tags given as arguments
+func tagArgs(args []string) ([]string, error) {
+	var tags []string
+	for _, arg := range args {
+		parsed, err := parseTags(arg)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, parsed...)
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tags given")
+	}
+	return tags, nil
+}
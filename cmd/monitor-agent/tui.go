@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/service"
+	"github.com/monitor-agent/internal/tui"
+	"github.com/sirupsen/logrus"
+)
+
+// runTUI browses programs, their assets and the assets' latest responses in a
+// terminal UI, reading straight from the database
+func runTUI(ctx context.Context, cfg *config.Config, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	newOnly := fs.Bool("new", false, "start with only programs and assets new since the last scan listed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	bodyStore, err := service.NewBodyStore(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create body store: %w", err)
+	}
+
+	// Log lines would garble the screen
+	out := logrus.StandardLogger().Out
+	logrus.SetOutput(io.Discard)
+	defer logrus.SetOutput(out)
+
+	return tui.Run(ctx, tui.NewRepositorySource(db, bodyStore), *newOnly)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/monitor-agent/internal/version"
+)
+
+// runVersion prints build information and optionally checks for a newer release
+func runVersion(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	check := fs.Bool("check", false, "check GitHub for a newer release")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Printf("Monitor Agent %s\n", version.Version)
+	fmt.Printf("Commit:      %s\n", version.Commit)
+	fmt.Printf("Built:       %s\n", version.BuildDate)
+	fmt.Printf("Go:          %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	if !*check {
+		return nil
+	}
+
+	release, err := version.LatestRelease(ctx, version.ReleasesURL, 10*time.Second)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version.IsNewer(release.TagName, version.Version):
+		fmt.Printf("\nA newer release is available: %s\n%s\n", release.TagName, release.HTMLURL)
+	case version.Version == "dev":
+		fmt.Printf("\nLatest release: %s (this is a development build)\n", release.TagName)
+	default:
+		fmt.Printf("\nUp to date (latest release: %s)\n", release.TagName)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// runWatch dispatches the watchlist subcommands
+func runWatch(ctx context.Context, db *sqlx.DB, monitorService *service.MonitorService, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent watch <add|remove|list|check> [flags]")
+	}
+
+	switch args[0] {
+	case "add":
+		return runWatchAdd(ctx, db, args[1:])
+	case "remove":
+		return runWatchRemove(ctx, db, args[1:])
+	case "list":
+		return runWatchList(ctx, db)
+	case "check":
+		return runWatchCheck(ctx, monitorService)
+	default:
+		return fmt.Errorf("unknown watch command: %s", args[0])
+	}
+}
+
+// watchHostname normalizes a hostname or URL given on the command line to the
+// bare lowercase hostname that is watched
+func watchHostname(value string) (string, error) {
+	urlProcessor := utils.NewURLProcessor()
+	hostname, err := urlProcessor.ExtractDomain(strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid hostname %q: %w", value, err)
+	}
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	if strings.HasPrefix(hostname, "*.") || !urlProcessor.IsValidDomain(hostname) {
+		return "", fmt.Errorf("invalid hostname %q: watch a single hostname, not a wildcard", value)
+	}
+	return hostname, nil
+}
+
+// runWatchAdd adds hostnames to the watchlist, or updates their program and note
+func runWatchAdd(ctx context.Context, db *sqlx.DB, args []string) error {
+	fs := flag.NewFlagSet("watch add", flag.ExitOnError)
+	programURL := fs.String("program", "", "program URL the hostnames belong to")
+	note := fs.String("note", "", "why the hostnames are watched")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: monitor-agent watch add [--program URL] [--note TEXT] <hostname>...")
+	}
+
+	entry := database.WatchlistEntry{Note: *note}
+	if *programURL != "" {
+		program, err := resolveQuotaProgram(ctx, db, *programURL)
+		if err != nil {
+			return err
+		}
+		entry.ProgramID = &program.ID
+	}
+
+	repo := database.NewWatchlistRepository(db)
+	for _, value := range fs.Args() {
+		hostname, err := watchHostname(value)
+		if err != nil {
+			return err
+		}
+
+		added := entry
+		added.Hostname = hostname
+		if err := repo.AddWatchlistEntry(ctx, &added); err != nil {
+			return err
+		}
+		fmt.Printf("Watching %s (%s)\n", added.Hostname, added.State)
+	}
+
+	return nil
+}
+
+// runWatchRemove removes hostnames from the watchlist
+func runWatchRemove(ctx context.Context, db *sqlx.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: monitor-agent watch remove <hostname>...")
+	}
+
+	repo := database.NewWatchlistRepository(db)
+	for _, value := range args {
+		hostname, err := watchHostname(value)
+		if err != nil {
+			return err
+		}
+
+		removed, err := repo.RemoveWatchlistEntry(ctx, hostname)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			fmt.Printf("%s is not on the watchlist\n", hostname)
+			continue
+		}
+		fmt.Printf("Stopped watching %s\n", hostname)
+	}
+
+	return nil
+}
+
+// runWatchList prints the watchlist with each hostname's last check
+func runWatchList(ctx context.Context, db *sqlx.DB) error {
+	entries, err := database.NewWatchlistRepository(db).GetWatchlist(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== Watchlist ===\n")
+	for _, entry := range entries {
+		checked := "never checked"
+		if entry.LastCheckedAt != nil {
+			checked = "checked " + entry.LastCheckedAt.Format("2006-01-02 15:04")
+		}
+		state := entry.State
+		if entry.State == database.WatchStateResponding {
+			state = fmt.Sprintf("%s %d", entry.State, entry.StatusCode)
+		}
+		fmt.Printf("%-45s %-16s %-16s %s", entry.Hostname, state, entry.IP, checked)
+		if entry.Note != "" {
+			fmt.Printf("  # %s", entry.Note)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n%d hostnames watched\n", len(entries))
+
+	return nil
+}
+
+// runWatchCheck checks every watched hostname now
+func runWatchCheck(ctx context.Context, monitorService *service.MonitorService) error {
+	result, err := monitorService.CheckWatchlist(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checked %d watched hostnames: %d responding, %d resolving, %d came alive\n",
+		result.Checked, result.Responding, result.Resolving, result.CameAlive)
+	return nil
+}
@@ -0,0 +1,16 @@
+// Package monitoragent embeds the default configuration files shipped in the
+// repository, so the binary can generate them with `monitor-agent init`
+// without a source checkout.
+package monitoragent
+
+import _ "embed"
+
+// DefaultConfig is the commented configs/config.yaml
+//
+//go:embed configs/config.yaml
+var DefaultConfig []byte
+
+// ExampleEnv is env.example, listing every environment variable with its default
+//
+//go:embed env.example
+var ExampleEnv []byte
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/httpapi"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// assetFilters are the query parameters that filter assets by the asset
+// query field of the same name
+var assetFilters = []string{"source", "status", "liveness", "domain", "tag"}
+
+// ProgramsResponse lists programs
+type ProgramsResponse struct {
+	Programs []*database.Program `json:"programs"`
+}
+
+// AssetsResponse lists assets
+type AssetsResponse struct {
+	Assets []*database.Asset `json:"assets"`
+}
+
+// handleListPrograms lists the active programs
+func (s *Server) handleListPrograms(w http.ResponseWriter, r *http.Request) {
+	programs, err := s.service.ListPrograms(r.Context())
+	if err != nil {
+		internalError(w, "list programs", err)
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, ProgramsResponse{Programs: nonNil(programs)})
+}
+
+// handleGetProgram returns a program by ID or handle
+func (s *Server) handleGetProgram(w http.ResponseWriter, r *http.Request) {
+	program, ok := s.program(w, r)
+	if !ok {
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, program)
+}
+
+// handleListProgramAssets lists a program's assets, optionally filtered
+func (s *Server) handleListProgramAssets(w http.ResponseWriter, r *http.Request) {
+	program, ok := s.program(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := assetQuery(r)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.Terms = append(query.Terms, database.AssetQueryTerm{Field: "program", Value: program.ProgramURL})
+
+	s.writeAssets(w, r, query)
+}
+
+// handleListAssets lists the assets of all programs matching the filters
+func (s *Server) handleListAssets(w http.ResponseWriter, r *http.Request) {
+	query, err := assetQuery(r)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(query.Terms) == 0 {
+		httpapi.WriteError(w, http.StatusBadRequest, "filter the assets with q or one of: "+strings.Join(assetFilters, ", "))
+		return
+	}
+
+	s.writeAssets(w, r, query)
+}
+
+// writeAssets writes the assets matching a query, up to the request's limit
+func (s *Server) writeAssets(w http.ResponseWriter, r *http.Request, query *database.AssetQuery) {
+	limit, err := listLimit(r)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assets, err := s.service.QueryAssets(r.Context(), query, limit)
+	if err != nil {
+		internalError(w, "query assets", err)
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, AssetsResponse{Assets: nonNil(assets)})
+}
+
+// assetQuery builds an asset query from the request's filter parameters and
+// its q parameter, an asset query such as "tag:admin -liveness:dns-only"
+func assetQuery(r *http.Request) (*database.AssetQuery, error) {
+	params := r.URL.Query()
+	query := &database.AssetQuery{}
+
+	if q := strings.TrimSpace(params.Get("q")); q != "" {
+		parsed, err := database.ParseAssetQuery(q)
+		if err != nil {
+			return nil, err
+		}
+		query.Terms = parsed.Terms
+	}
+	for _, field := range assetFilters {
+		if value := params.Get(field); value != "" {
+			query.Terms = append(query.Terms, database.AssetQueryTerm{Field: field, Value: value})
+		}
+	}
+
+	return query, nil
+}
+
+// program looks up the program of the request's path, by ID or by handle
+// such as "slack", and writes the error response when there is none
+func (s *Server) program(w http.ResponseWriter, r *http.Request) (*database.Program, bool) {
+	ref := r.PathValue("program")
+
+	var program *database.Program
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		program, err = s.service.GetProgram(r.Context(), id)
+	} else {
+		program, err = s.service.FindProgram(r.Context(), ref)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrProgramNotFound):
+		httpapi.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrAmbiguousProgram):
+		httpapi.WriteError(w, http.StatusConflict, err.Error()+" or the program ID")
+	case err != nil:
+		internalError(w, "look up program", err)
+	default:
+		return program, true
+	}
+	return nil, false
+}
+
+// internalError logs a failed request and writes a generic error response
+func internalError(w http.ResponseWriter, action string, err error) {
+	logrus.Errorf("API failed to %s: %v", action, err)
+	httpapi.WriteError(w, http.StatusInternalServerError, "failed to "+action)
+}
+
+// nonNil returns an empty list instead of nil, so it is encoded as []
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
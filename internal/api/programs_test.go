@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Programs(t *testing.T) {
+	program := &database.Program{ID: uuid.New(), Name: "acme", Platform: "hackerone", ProgramURL: "https://hackerone.com/acme"}
+	svc := &fakeService{programs: []*database.Program{program}}
+	server := NewServer(svc, "secret")
+
+	rec := doRequest(t, server, http.MethodGet, "/programs", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var programs ProgramsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &programs))
+	require.Len(t, programs.Programs, 1)
+	assert.Equal(t, program.ID, programs.Programs[0].ID)
+
+	// By ID or by handle
+	for _, ref := range []string{program.ID.String(), "acme"} {
+		rec = doRequest(t, server, http.MethodGet, "/programs/"+ref, "secret")
+		require.Equal(t, http.StatusOK, rec.Code, ref)
+		var found database.Program
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &found))
+		assert.Equal(t, program.ID, found.ID)
+	}
+
+	rec = doRequest(t, server, http.MethodGet, "/programs/missing", "secret")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doRequest(t, server, http.MethodGet, "/programs/"+uuid.New().String(), "secret")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doRequest(t, server, http.MethodGet, "/programs/shared", "secret")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestServer_Assets(t *testing.T) {
+	program := &database.Program{ID: uuid.New(), Name: "acme", ProgramURL: "https://hackerone.com/acme"}
+	svc := &fakeService{
+		programs: []*database.Program{program},
+		assets:   []*database.Asset{{URL: "https://www.acme.com", Source: "chaosdb"}},
+	}
+	server := NewServer(svc, "secret")
+
+	rec := doRequest(t, server, http.MethodGet, "/programs/acme/assets?source=chaosdb&status=active&q=-liveness:dns-only&limit=5000", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var assets AssetsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &assets))
+	require.Len(t, assets.Assets, 1)
+	assert.Equal(t, "https://www.acme.com", assets.Assets[0].URL)
+	assert.Equal(t, []database.AssetQueryTerm{
+		{Field: "liveness", Value: "dns-only", Negate: true},
+		{Field: "source", Value: "chaosdb"},
+		{Field: "status", Value: "active"},
+		{Field: "program", Value: "https://hackerone.com/acme"},
+	}, svc.queries[0].Terms)
+	assert.Equal(t, MaxLimit, svc.limits[0])
+
+	rec = doRequest(t, server, http.MethodGet, "/assets?domain=*.acme.com", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []database.AssetQueryTerm{{Field: "domain", Value: "*.acme.com"}}, svc.queries[1].Terms)
+	assert.Equal(t, DefaultLimit, svc.limits[1])
+
+	// Listing every asset at once is refused
+	rec = doRequest(t, server, http.MethodGet, "/assets", "secret")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(t, server, http.MethodGet, "/assets?q=color:blue", "secret")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(t, server, http.MethodGet, "/assets?domain=acme.com&limit=0", "secret")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Len(t, svc.queries, 2)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/httpapi"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// ScansResponse lists scans, most recent first
+type ScansResponse struct {
+	Scans []*database.Scan `json:"scans"`
+}
+
+// TriggerResponse is returned after a scan of a program was started
+type TriggerResponse struct {
+	ProgramID string `json:"program_id"`
+	Status    string `json:"status"`
+}
+
+// CancelResponse is returned after a scan cancel was requested
+type CancelResponse struct {
+	ScanID string `json:"scan_id"`
+	Status string `json:"status"`
+}
+
+// handleListScans lists the most recent scans of all programs
+func (s *Server) handleListScans(w http.ResponseWriter, r *http.Request) {
+	s.writeScans(w, r, uuid.Nil)
+}
+
+// handleListProgramScans lists a program's scan history
+func (s *Server) handleListProgramScans(w http.ResponseWriter, r *http.Request) {
+	program, ok := s.program(w, r)
+	if !ok {
+		return
+	}
+	s.writeScans(w, r, program.ID)
+}
+
+// writeScans writes the most recent scans of a program, or of all programs
+// for uuid.Nil, up to the request's limit
+func (s *Server) writeScans(w http.ResponseWriter, r *http.Request, programID uuid.UUID) {
+	limit, err := listLimit(r)
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scans, err := s.service.ListScans(r.Context(), programID, limit)
+	if err != nil {
+		internalError(w, "list scans", err)
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, ScansResponse{Scans: nonNil(scans)})
+}
+
+// handleTriggerScan starts a scan of a program in the background. Clients
+// follow it through the program's scan history.
+func (s *Server) handleTriggerScan(w http.ResponseWriter, r *http.Request) {
+	// The scan runs in the background, so read-only mode is checked up front
+	if err := s.service.Writable(); err != nil {
+		httpapi.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	program, ok := s.program(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	if s.rescans[program.ID] {
+		s.mu.Unlock()
+		httpapi.WriteError(w, http.StatusConflict, "a scan of "+program.Name+" is already running")
+		return
+	}
+	s.rescans[program.ID] = true
+	s.mu.Unlock()
+
+	s.inFlight.Add(1)
+	go func() {
+		defer s.inFlight.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.rescans, program.ID)
+			s.mu.Unlock()
+		}()
+
+		// The scan outlives the request that started it
+		scan, err := s.service.RescanProgram(context.WithoutCancel(r.Context()), program)
+		if err != nil {
+			logrus.Errorf("API triggered scan of %s failed: %v", program.Name, err)
+			return
+		}
+		logrus.Infof("API triggered scan of %s %s: %d assets seen", program.Name, scan.Status, scan.AssetsSeen)
+	}()
+
+	httpapi.WriteJSON(w, http.StatusAccepted, TriggerResponse{ProgramID: program.ID.String(), Status: "scan_started"})
+}
+
+// handleCancelScan cancels a running scan
+func (s *Server) handleCancelScan(w http.ResponseWriter, r *http.Request) {
+	scanID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "invalid scan id")
+		return
+	}
+
+	err = s.service.CancelScan(r.Context(), scanID)
+	switch {
+	case errors.Is(err, database.ErrScanNotFound):
+		httpapi.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, database.ErrScanNotRunning):
+		httpapi.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrReadOnly):
+		httpapi.WriteError(w, http.StatusForbidden, err.Error())
+	case err != nil:
+		logrus.Errorf("Failed to cancel scan %s: %v", scanID, err)
+		httpapi.WriteError(w, http.StatusInternalServerError, "failed to cancel scan")
+	default:
+		httpapi.WriteJSON(w, http.StatusAccepted, CancelResponse{ScanID: scanID.String(), Status: "cancel_requested"})
+	}
+}
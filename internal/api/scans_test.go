@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Scans(t *testing.T) {
+	program := &database.Program{ID: uuid.New(), Name: "acme"}
+	svc := &fakeService{
+		programs:  []*database.Program{program},
+		scans:     []*database.Scan{{ID: uuid.New(), ProgramID: program.ID, Status: "completed", AssetsFound: 40}},
+		rescanned: make(chan *database.Program, 1),
+	}
+	server := NewServer(svc, "secret")
+
+	rec := doRequest(t, server, http.MethodGet, "/scans?limit=10", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var scans ScansResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scans))
+	require.Len(t, scans.Scans, 1)
+	assert.Equal(t, 40, scans.Scans[0].AssetsFound)
+
+	rec = doRequest(t, server, http.MethodGet, "/programs/acme/scans", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []uuid.UUID{uuid.Nil, program.ID}, svc.scanned)
+	assert.Equal(t, []int{10, DefaultLimit}, svc.limits)
+
+	rec = doRequest(t, server, http.MethodPost, "/programs/acme/scans", "secret")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Contains(t, rec.Body.String(), "scan_started")
+	select {
+	case rescanned := <-svc.rescanned:
+		assert.Equal(t, program, rescanned)
+	case <-time.After(5 * time.Second):
+		t.Fatal("scan was not started")
+	}
+	server.Wait()
+
+	rec = doRequest(t, server, http.MethodPost, "/programs/missing/scans", "secret")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Read-only mode refuses scans before they are started
+	svc.readOnly = true
+	rec = doRequest(t, server, http.MethodPost, "/programs/acme/scans", "secret")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, svc.rescanned)
+}
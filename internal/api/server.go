@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/httpapi"
+	"github.com/monitor-agent/internal/service"
+)
+
+// Asset and scan limits of list requests
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Service is the part of the monitor service the API exposes
+type Service interface {
+	ListPrograms(ctx context.Context) ([]*database.Program, error)
+	GetProgram(ctx context.Context, id uuid.UUID) (*database.Program, error)
+	FindProgram(ctx context.Context, handle string) (*database.Program, error)
+	QueryAssets(ctx context.Context, query *database.AssetQuery, limit int) ([]*database.Asset, error)
+	ListScans(ctx context.Context, programID uuid.UUID, limit int) ([]*database.Scan, error)
+	RescanProgram(ctx context.Context, program *database.Program) (*database.Scan, error)
+	CancelScan(ctx context.Context, scanID uuid.UUID) error
+	GetProgramStats(ctx context.Context) (*service.ProgramStats, error)
+	Writable() error
+}
+
+// ErrorResponse is returned when a request fails
+type ErrorResponse = httpapi.ErrorResponse
+
+// Server exposes programs, assets, scans and stats over REST
+type Server struct {
+	service Service
+	token   string
+	mux     *http.ServeMux
+
+	mu       sync.Mutex
+	rescans  map[uuid.UUID]bool // programs being scanned, by ID
+	inFlight sync.WaitGroup
+}
+
+// NewServer creates a new API handler protected by a bearer token
+func NewServer(service Service, token string) *Server {
+	s := &Server{
+		service: service,
+		token:   token,
+		mux:     http.NewServeMux(),
+		rescans: make(map[uuid.UUID]bool),
+	}
+	s.mux.HandleFunc("GET /programs", s.handleListPrograms)
+	s.mux.HandleFunc("GET /programs/{program}", s.handleGetProgram)
+	s.mux.HandleFunc("GET /programs/{program}/assets", s.handleListProgramAssets)
+	s.mux.HandleFunc("GET /programs/{program}/scans", s.handleListProgramScans)
+	s.mux.HandleFunc("POST /programs/{program}/scans", s.handleTriggerScan)
+	s.mux.HandleFunc("GET /assets", s.handleListAssets)
+	s.mux.HandleFunc("GET /scans", s.handleListScans)
+	s.mux.HandleFunc("DELETE /scans/{id}", s.handleCancelScan)
+	s.mux.HandleFunc("GET /stats", s.handleStats)
+	return s
+}
+
+// ServeHTTP authorizes and routes an API request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !httpapi.Authorized(r.Header.Get("Authorization"), s.token) {
+		httpapi.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Wait blocks until the scans triggered through the API have finished
+func (s *Server) Wait() {
+	s.inFlight.Wait()
+}
+
+// listLimit reads the limit query parameter, capped at MaxLimit
+func listLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("limit must be a positive number")
+	}
+	return min(limit, MaxLimit), nil
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeService is an in-memory Service
+type fakeService struct {
+	programs  []*database.Program
+	assets    []*database.Asset
+	scans     []*database.Scan
+	queries   []*database.AssetQuery
+	limits    []int
+	scanned   []uuid.UUID // program IDs scans were listed for
+	rescanned chan *database.Program
+	cancelled []uuid.UUID
+	stats     *service.ProgramStats
+	err       error
+	readOnly  bool
+}
+
+func (f *fakeService) ListPrograms(ctx context.Context) ([]*database.Program, error) {
+	return f.programs, nil
+}
+
+func (f *fakeService) GetProgram(ctx context.Context, id uuid.UUID) (*database.Program, error) {
+	for _, program := range f.programs {
+		if program.ID == id {
+			return program, nil
+		}
+	}
+	return nil, service.ErrProgramNotFound
+}
+
+func (f *fakeService) FindProgram(ctx context.Context, handle string) (*database.Program, error) {
+	if handle == "shared" {
+		return nil, service.ErrAmbiguousProgram
+	}
+	for _, program := range f.programs {
+		if program.Name == handle {
+			return program, nil
+		}
+	}
+	return nil, service.ErrProgramNotFound
+}
+
+func (f *fakeService) QueryAssets(ctx context.Context, query *database.AssetQuery, limit int) ([]*database.Asset, error) {
+	f.queries = append(f.queries, query)
+	f.limits = append(f.limits, limit)
+	return f.assets, nil
+}
+
+func (f *fakeService) ListScans(ctx context.Context, programID uuid.UUID, limit int) ([]*database.Scan, error) {
+	f.scanned = append(f.scanned, programID)
+	f.limits = append(f.limits, limit)
+	return f.scans, nil
+}
+
+func (f *fakeService) RescanProgram(ctx context.Context, program *database.Program) (*database.Scan, error) {
+	f.rescanned <- program
+	return &database.Scan{ProgramID: program.ID, Status: "completed"}, nil
+}
+
+func (f *fakeService) CancelScan(ctx context.Context, scanID uuid.UUID) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.cancelled = append(f.cancelled, scanID)
+	return nil
+}
+
+func (f *fakeService) GetProgramStats(ctx context.Context) (*service.ProgramStats, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.stats, nil
+}
+
+func (f *fakeService) Writable() error {
+	if f.readOnly {
+		return service.ErrReadOnly
+	}
+	return nil
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_CancelScan(t *testing.T) {
+	svc := &fakeService{}
+	server := NewServer(svc, "secret")
+	scanID := uuid.New()
+
+	rec := doRequest(t, server, http.MethodDelete, "/scans/"+scanID.String(), "secret")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, []uuid.UUID{scanID}, svc.cancelled)
+	assert.Contains(t, rec.Body.String(), "cancel_requested")
+}
+
+func TestServer_CancelScan_Errors(t *testing.T) {
+	scanID := uuid.New().String()
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		err    error
+		status int
+	}{
+		{"missing token", "/scans/" + scanID, "", nil, http.StatusUnauthorized},
+		{"wrong token", "/scans/" + scanID, "wrong", nil, http.StatusUnauthorized},
+		{"invalid id", "/scans/not-a-uuid", "secret", nil, http.StatusBadRequest},
+		{"not found", "/scans/" + scanID, "secret", database.ErrScanNotFound, http.StatusNotFound},
+		{"not running", "/scans/" + scanID, "secret", database.ErrScanNotRunning, http.StatusConflict},
+		{"read-only", "/scans/" + scanID, "secret", service.ErrReadOnly, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(&fakeService{err: tt.err}, "secret")
+			rec := doRequest(t, server, http.MethodDelete, tt.path, tt.token)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestServer_EmptyTokenRejectsAll(t *testing.T) {
+	server := NewServer(&fakeService{}, "")
+	rec := doRequest(t, server, http.MethodDelete, "/scans/"+uuid.New().String(), "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
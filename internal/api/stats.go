@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/monitor-agent/internal/httpapi"
+)
+
+// handleStats returns the program stats, broken down per platform and program
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.service.GetProgramStats(r.Context())
+	if err != nil {
+		internalError(w, "get stats", err)
+		return
+	}
+	httpapi.WriteJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/monitor-agent/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Stats(t *testing.T) {
+	svc := &fakeService{readOnly: true, stats: &service.ProgramStats{
+		TotalPrograms: 1,
+		Platforms:     []*service.PlatformCount{{Platform: "hackerone", Programs: 1, Assets: 42}},
+		Programs: []*service.ProgramBreakdown{{
+			Name:     "Acme",
+			Platform: "hackerone",
+			Assets:   42,
+			Sources:  map[string]int{"chaosdb": 40, "crtsh": 2},
+			Scans:    service.ScanOutcomes{Scans: 4, Completed: 3, Failed: 1, SuccessRate: 0.75},
+		}},
+	}}
+	server := NewServer(svc, "secret")
+
+	// Stats are read even when the agent is read-only
+	rec := doRequest(t, server, http.MethodGet, "/stats", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var stats service.ProgramStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	require.Len(t, stats.Programs, 1)
+	assert.Equal(t, 40, stats.Programs[0].Sources["chaosdb"])
+	assert.Equal(t, 0.75, stats.Programs[0].Scans.SuccessRate)
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest(t, server, http.MethodGet, "/stats", "").Code)
+
+	svc.err = errors.New("connection refused")
+	assert.Equal(t, http.StatusInternalServerError, doRequest(t, server, http.MethodGet, "/stats", "secret").Code)
+}
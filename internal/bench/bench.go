@@ -0,0 +1,180 @@
+// Package bench measures the throughput of the discovery pipeline on the
+// current host and recommends settings for it
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// URLProber probes URLs, as the HTTPX client does
+type URLProber interface {
+	ProbeURLsWithDetails(ctx context.Context, urls []string) ([]httpx.DetailedProbeResult, error)
+}
+
+// Settings are the current settings a benchmark is measured with
+type Settings struct {
+	HTTPXConcurrency int
+	HTTPXRateLimit   int // probes per second
+	WritesPerSecond  int // DB_WRITES_PER_SECOND; 0 for no limit
+}
+
+// Result holds what a benchmark measured. Counts of zero mean the part was
+// skipped.
+type Result struct {
+	DBRows     int
+	DBDuration time.Duration
+
+	Probes         int
+	ProbeResponses int // probes that got a response
+	ProbeDuration  time.Duration
+
+	LimiterCalls    int
+	LimiterDuration time.Duration
+}
+
+// DBRowsPerSecond returns the measured asset upsert rate
+func (r *Result) DBRowsPerSecond() float64 {
+	return rate(r.DBRows, r.DBDuration)
+}
+
+// ProbesPerSecond returns the measured probe rate
+func (r *Result) ProbesPerSecond() float64 {
+	return rate(r.Probes, r.ProbeDuration)
+}
+
+// LimiterOverhead returns the time a rate limiter call takes when a token is
+// available
+func (r *Result) LimiterOverhead() time.Duration {
+	if r.LimiterCalls == 0 {
+		return 0
+	}
+	return r.LimiterDuration / time.Duration(r.LimiterCalls)
+}
+
+func rate(count int, elapsed time.Duration) float64 {
+	if count == 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(count) / elapsed.Seconds()
+}
+
+// MeasureProbes probes count URLs of a server listening on the loopback
+// interface, so only the prober and the host are measured, never a target
+func MeasureProbes(ctx context.Context, prober URLProber, count int) (responses int, elapsed time.Duration, err error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to listen for probes: %w", err)
+	}
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html><head><title>bench</title></head><body>ok</body></html>"))
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	urls := make([]string, count)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("http://%s/bench/%d", listener.Addr(), i)
+	}
+
+	start := time.Now()
+	results, err := prober.ProbeURLsWithDetails(ctx, urls)
+	elapsed = time.Since(start)
+	if err != nil {
+		return 0, elapsed, err
+	}
+
+	for _, result := range results {
+		if result.StatusCode > 0 {
+			responses++
+		}
+	}
+	return responses, elapsed, nil
+}
+
+// MeasureLimiter times calls to a rate limiter whose bucket holds a token for
+// every call, so only the limiter's own overhead is measured
+func MeasureLimiter(calls int) time.Duration {
+	limiter := utils.NewRateLimiter(calls, time.Hour)
+
+	start := time.Now()
+	for i := 0; i < calls; i++ {
+		limiter.Wait()
+	}
+	return time.Since(start)
+}
+
+// Thresholds the recommendations are based on
+const (
+	// rateLimitedShare is the share of HTTPX_RATE_LIMIT above which probing
+	// counts as held back by the rate limit
+	rateLimitedShare = 0.8
+	// maxHTTPXConcurrency is the highest HTTPX_CONCURRENCY the config accepts
+	maxHTTPXConcurrency = 100
+	// slowLimiterCall is the limiter overhead above which the host is short of CPU
+	slowLimiterCall = 100 * time.Microsecond
+)
+
+// Recommend returns setting changes suggested by a benchmark result
+func Recommend(settings Settings, result *Result) []string {
+	var recommendations []string
+
+	probeRate := result.ProbesPerSecond()
+	if result.Probes > 0 {
+		switch {
+		case result.ProbeResponses < result.Probes:
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Only %d of %d local probes got a response; the host may be out of file descriptors or ports, lower HTTPX_CONCURRENCY",
+				result.ProbeResponses, result.Probes))
+		case probeRate >= rateLimitedShare*float64(settings.HTTPXRateLimit):
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Probing is held back by HTTPX_RATE_LIMIT=%d (%.0f probes/s measured); raise it if the programs allow more requests per second",
+				settings.HTTPXRateLimit, probeRate))
+		case settings.HTTPXConcurrency < maxHTTPXConcurrency:
+			suggested := int(math.Ceil(float64(settings.HTTPXConcurrency) * float64(settings.HTTPXRateLimit) / probeRate))
+			if suggested > maxHTTPXConcurrency {
+				suggested = maxHTTPXConcurrency
+			}
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Probing reached %.0f of the %d probes/s HTTPX_RATE_LIMIT allows; raise HTTPX_CONCURRENCY from %d to %d",
+				probeRate, settings.HTTPXRateLimit, settings.HTTPXConcurrency, suggested))
+		default:
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Probing reached %.0f probes/s at the highest HTTPX_CONCURRENCY; the host's CPU or network is the limit, not the settings",
+				probeRate))
+		}
+	}
+
+	if dbRate := result.DBRowsPerSecond(); result.DBRows > 0 && probeRate > 0 {
+		switch {
+		case settings.WritesPerSecond > 0 && float64(settings.WritesPerSecond) < probeRate:
+			recommendations = append(recommendations, fmt.Sprintf(
+				"DB_WRITES_PER_SECOND=%d writes fewer assets than are probed (%.0f/s); raise it towards the %.0f rows/s the database sustained",
+				settings.WritesPerSecond, probeRate, dbRate))
+		case dbRate < probeRate:
+			recommendations = append(recommendations, fmt.Sprintf(
+				"The database upserts %.0f assets/s, fewer than are probed (%.0f/s); it will hold back large programs, move it closer to the agent or give it more resources",
+				dbRate, probeRate))
+		}
+	}
+
+	if overhead := result.LimiterOverhead(); overhead > slowLimiterCall {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"Rate limiter calls take %s each; the host is short of CPU, lower HTTPX_CONCURRENCY",
+			overhead))
+	}
+
+	return recommendations
+}
@@ -0,0 +1,133 @@
+package bench
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getProber probes URLs with plain GET requests
+type getProber struct{}
+
+func (getProber) ProbeURLsWithDetails(ctx context.Context, urls []string) ([]httpx.DetailedProbeResult, error) {
+	var results []httpx.DetailedProbeResult
+	for _, url := range urls {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			results = append(results, httpx.DetailedProbeResult{URL: url, Error: err.Error()})
+			continue
+		}
+		resp.Body.Close()
+		results = append(results, httpx.DetailedProbeResult{URL: url, StatusCode: resp.StatusCode})
+	}
+	return results, nil
+}
+
+func TestMeasureProbes(t *testing.T) {
+	responses, elapsed, err := MeasureProbes(context.Background(), getProber{}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 5, responses)
+	assert.Positive(t, elapsed)
+}
+
+func TestMeasureLimiter(t *testing.T) {
+	// Every call finds a token, so the calls never wait for a refill
+	assert.Less(t, MeasureLimiter(1000), time.Second)
+}
+
+func TestResult_Rates(t *testing.T) {
+	result := &Result{DBRows: 500, DBDuration: 2 * time.Second, Probes: 30, ProbeDuration: 3 * time.Second, LimiterCalls: 4, LimiterDuration: 2 * time.Microsecond}
+	assert.Equal(t, 250.0, result.DBRowsPerSecond())
+	assert.Equal(t, 10.0, result.ProbesPerSecond())
+	assert.Equal(t, 500*time.Nanosecond, result.LimiterOverhead())
+
+	assert.Zero(t, (&Result{}).ProbesPerSecond())
+	assert.Zero(t, (&Result{}).LimiterOverhead())
+}
+
+func TestRecommend(t *testing.T) {
+	settings := Settings{HTTPXConcurrency: 25, HTTPXRateLimit: 50}
+
+	tests := []struct {
+		name     string
+		settings Settings
+		result   Result
+		want     []string
+	}{
+		{
+			name:     "nothing measured",
+			settings: settings,
+			result:   Result{},
+			want:     nil,
+		},
+		{
+			name:     "held back by the rate limit",
+			settings: settings,
+			result:   Result{Probes: 100, ProbeResponses: 100, ProbeDuration: 2 * time.Second},
+			want:     []string{"Probing is held back by HTTPX_RATE_LIMIT=50 (50 probes/s measured); raise it if the programs allow more requests per second"},
+		},
+		{
+			name:     "concurrency too low",
+			settings: settings,
+			result:   Result{Probes: 100, ProbeResponses: 100, ProbeDuration: 10 * time.Second},
+			want:     []string{"Probing reached 10 of the 50 probes/s HTTPX_RATE_LIMIT allows; raise HTTPX_CONCURRENCY from 25 to 100"},
+		},
+		{
+			name:     "concurrency suggestion below the maximum",
+			settings: settings,
+			result:   Result{Probes: 100, ProbeResponses: 100, ProbeDuration: 4 * time.Second},
+			want:     []string{"Probing reached 25 of the 50 probes/s HTTPX_RATE_LIMIT allows; raise HTTPX_CONCURRENCY from 25 to 50"},
+		},
+		{
+			name:     "host bound",
+			settings: Settings{HTTPXConcurrency: 100, HTTPXRateLimit: 500},
+			result:   Result{Probes: 100, ProbeResponses: 100, ProbeDuration: time.Second},
+			want:     []string{"Probing reached 100 probes/s at the highest HTTPX_CONCURRENCY; the host's CPU or network is the limit, not the settings"},
+		},
+		{
+			name:     "missing responses",
+			settings: settings,
+			result:   Result{Probes: 100, ProbeResponses: 60, ProbeDuration: 2 * time.Second},
+			want:     []string{"Only 60 of 100 local probes got a response; the host may be out of file descriptors or ports, lower HTTPX_CONCURRENCY"},
+		},
+		{
+			name:     "write budget below the probe rate",
+			settings: Settings{HTTPXConcurrency: 25, HTTPXRateLimit: 50, WritesPerSecond: 20},
+			result:   Result{Probes: 100, ProbeResponses: 100, ProbeDuration: 2 * time.Second, DBRows: 1000, DBDuration: time.Second},
+			want: []string{
+				"Probing is held back by HTTPX_RATE_LIMIT=50 (50 probes/s measured); raise it if the programs allow more requests per second",
+				"DB_WRITES_PER_SECOND=20 writes fewer assets than are probed (50/s); raise it towards the 1000 rows/s the database sustained",
+			},
+		},
+		{
+			name:     "slow database",
+			settings: settings,
+			result:   Result{Probes: 100, ProbeResponses: 100, ProbeDuration: 2 * time.Second, DBRows: 100, DBDuration: 5 * time.Second},
+			want: []string{
+				"Probing is held back by HTTPX_RATE_LIMIT=50 (50 probes/s measured); raise it if the programs allow more requests per second",
+				"The database upserts 20 assets/s, fewer than are probed (50/s); it will hold back large programs, move it closer to the agent or give it more resources",
+			},
+		},
+		{
+			name:     "slow rate limiter",
+			settings: settings,
+			result:   Result{LimiterCalls: 10, LimiterDuration: 10 * time.Millisecond},
+			want:     []string{"Rate limiter calls take 1ms each; the host is short of CPU, lower HTTPX_CONCURRENCY"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Recommend(tt.settings, &tt.result))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	monitoragent "github.com/monitor-agent"
+)
+
+// Paths of the generated files, relative to the target directory. The agent
+// reads configs/config.yaml and .env from its working directory.
+const (
+	ConfigPath = "configs/config.yaml"
+	EnvPath    = ".env"
+)
+
+// File is a generated configuration file and what happened to it
+type File struct {
+	Path    string // path the file was or would have been written to
+	Written bool   // false when an existing file was kept
+}
+
+// defaultFiles maps each generated path to its embedded content
+func defaultFiles() []struct {
+	path    string
+	content []byte
+} {
+	return []struct {
+		path    string
+		content []byte
+	}{
+		{ConfigPath, monitoragent.DefaultConfig},
+		{EnvPath, monitoragent.ExampleEnv},
+	}
+}
+
+// WriteDefaults writes the default config.yaml and .env under dir. Existing
+// files are kept unless force is set.
+func WriteDefaults(dir string, force bool) ([]File, error) {
+	var files []File
+	for _, file := range defaultFiles() {
+		path := filepath.Join(dir, file.path)
+
+		if !force {
+			if _, err := os.Stat(path); err == nil {
+				files = append(files, File{Path: path})
+				continue
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return files, fmt.Errorf("failed to check %s: %w", path, err)
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return files, fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		// .env holds credentials once filled in
+		if err := os.WriteFile(path, file.content, 0o600); err != nil {
+			return files, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		files = append(files, File{Path: path, Written: true})
+	}
+
+	return files, nil
+}
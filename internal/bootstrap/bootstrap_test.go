@@ -0,0 +1,53 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	monitoragent "github.com/monitor-agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDefaults(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := WriteDefaults(dir, false)
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{Path: filepath.Join(dir, ConfigPath), Written: true},
+		{Path: filepath.Join(dir, EnvPath), Written: true},
+	}, files)
+
+	config, err := os.ReadFile(filepath.Join(dir, ConfigPath))
+	require.NoError(t, err)
+	assert.Equal(t, monitoragent.DefaultConfig, config)
+
+	env, err := os.ReadFile(filepath.Join(dir, EnvPath))
+	require.NoError(t, err)
+	assert.Contains(t, string(env), "DB_HOST=")
+}
+
+func TestWriteDefaults_KeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, EnvPath)
+	require.NoError(t, os.WriteFile(envPath, []byte("DB_HOST=db\n"), 0o600))
+
+	files, err := WriteDefaults(dir, false)
+	require.NoError(t, err)
+	assert.True(t, files[0].Written)
+	assert.False(t, files[1].Written)
+
+	env, err := os.ReadFile(envPath)
+	require.NoError(t, err)
+	assert.Equal(t, "DB_HOST=db\n", string(env))
+
+	files, err = WriteDefaults(dir, true)
+	require.NoError(t, err)
+	assert.True(t, files[1].Written)
+
+	env, err = os.ReadFile(envPath)
+	require.NoError(t, err)
+	assert.Equal(t, monitoragent.ExampleEnv, env)
+}
@@ -0,0 +1,178 @@
+// Package cluster groups near-identical HTTP response bodies, so a hunter can
+// review one representative of thousands of identical marketing or parking
+// pages. Bodies are fingerprinted with a 64-bit simhash of their word
+// shingles, which changes little when a page differs only in a nonce, a date
+// or a hostname; bodies whose fingerprints differ in at most a few bits are
+// grouped together.
+package cluster
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxDistance is the number of differing fingerprint bits up to which
+// two bodies are considered the same page
+const DefaultMaxDistance = 3
+
+// MaxDistance is the largest supported distance; every band of the index is
+// then 4 bits wide
+const MaxDistance = 15
+
+// shingleSize is the number of consecutive words hashed together
+const shingleSize = 3
+
+// Fingerprint returns the simhash of a response body. Words are lowercased,
+// and numbers and long hex strings (timestamps, CSRF tokens, cache busters)
+// are dropped, so pages differing only in those have the same fingerprint.
+// An empty body has fingerprint 0.
+func Fingerprint(body string) uint64 {
+	words := normalizedWords(body)
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	if len(words) < shingleSize {
+		add(strings.Join(words, " "))
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		add(strings.Join(words[i:i+shingleSize], " "))
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// Distance returns the number of bits two fingerprints differ in
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Group groups fingerprints that are within maxDistance bits of each other,
+// directly or through other fingerprints, and returns the indexes of each
+// group's fingerprints in ascending order. Every fingerprint is in exactly one
+// group, so fingerprints without near duplicates form groups of one.
+func Group(fingerprints []uint64, maxDistance int) [][]int {
+	maxDistance = min(max(maxDistance, 0), MaxDistance)
+
+	// Identical fingerprints are the common case, so only distinct ones are compared
+	var distinct []uint64
+	position := make(map[uint64]int)
+	for _, fingerprint := range fingerprints {
+		if _, seen := position[fingerprint]; !seen {
+			position[fingerprint] = len(distinct)
+			distinct = append(distinct, fingerprint)
+		}
+	}
+
+	parent := make([]int, len(distinct))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Split fingerprints into maxDistance+1 bands: two fingerprints within
+	// maxDistance bits differ in at most maxDistance bands, so they agree on
+	// at least one and only fingerprints sharing a band need comparing
+	bands := maxDistance + 1
+	width := 64 / bands
+	for band := 0; band < bands; band++ {
+		shift := band * width
+		mask := uint64(1)<<width - 1
+		if band == bands-1 {
+			mask = ^uint64(0) >> shift
+		}
+
+		buckets := make(map[uint64][]int)
+		for i, fingerprint := range distinct {
+			key := fingerprint >> shift & mask
+			buckets[key] = append(buckets[key], i)
+		}
+
+		for _, bucket := range buckets {
+			for x := 0; x < len(bucket); x++ {
+				for y := x + 1; y < len(bucket); y++ {
+					a, b := bucket[x], bucket[y]
+					if find(a) != find(b) && Distance(distinct[a], distinct[b]) <= maxDistance {
+						parent[find(a)] = find(b)
+					}
+				}
+			}
+		}
+	}
+
+	// Order groups by their first fingerprint, and members by index
+	byRoot := make(map[int]int)
+	var groups [][]int
+	for i, fingerprint := range fingerprints {
+		root := find(position[fingerprint])
+		group, ok := byRoot[root]
+		if !ok {
+			group = len(groups)
+			byRoot[root] = group
+			groups = append(groups, nil)
+		}
+		groups[group] = append(groups[group], i)
+	}
+
+	return groups
+}
+
+// normalizedWords splits a body into lowercased words, dropping those that
+// are mostly digits or long hex strings
+func normalizedWords(body string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := fields[:0]
+	for _, word := range fields {
+		if isNoise(word) {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// isNoise reports whether a word is a number or a long hex string that
+// differs between otherwise identical pages
+func isNoise(word string) bool {
+	digits, hex := 0, true
+	for _, r := range word {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			hex = false
+		}
+	}
+	return digits*2 >= len(word) || (hex && len(word) >= 16)
+}
@@ -0,0 +1,50 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const landingPage = `<html><head><title>Acme - Build faster</title></head><body>
+<nav><a href="/pricing">Pricing</a><a href="/customers">Customers</a><a href="/login">Log in</a></nav>
+<h1>Ship your product faster with Acme</h1>
+<p>Teams around the world trust Acme to deploy, monitor and scale their applications.
+Start your free trial today and see why thousands of companies switched.</p>
+<footer>Copyright Acme Inc. All rights reserved. <a href="/privacy">Privacy</a></footer>
+<script nonce="%s">window.__build = "%s";</script></body></html>`
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint(fmt.Sprintf(landingPage, "9f86d081884c7d659a2feaa0c55ad015", "1715342400"))
+	b := Fingerprint(fmt.Sprintf(landingPage, "3c59dc048e8850243be8079a5c74d079", "1715428800"))
+	assert.Equal(t, a, b, "nonces and timestamps are ignored")
+
+	edited := Fingerprint(strings.Replace(fmt.Sprintf(landingPage, "x", "1"), "Start your free trial today", "Book a demo today", 1))
+	assert.LessOrEqual(t, Distance(a, edited), 16)
+
+	login := Fingerprint(`<html><body><form action="/session"><input name="username"><input type="password" name="password">
+<button>Sign in to the admin console</button></form></body></html>`)
+	assert.Greater(t, Distance(a, login), DefaultMaxDistance)
+
+	assert.Zero(t, Fingerprint(""))
+	assert.Zero(t, Fingerprint(" 12345 "))
+	assert.NotZero(t, Fingerprint("ok"))
+}
+
+func TestGroup(t *testing.T) {
+	fingerprints := []uint64{
+		0xffff_0000_ffff_0000,
+		0x1234_5678_9abc_def0,
+		0xffff_0000_ffff_0000,        // identical to 0
+		0xffff_0000_ffff_0007,        // 3 bits from 0
+		0xffff_0000_ffff_003f,        // 3 bits from 3, 6 from 0
+		0x1234_5678_9abc_def0 ^ 0xf0, // 4 bits from 1
+	}
+
+	assert.Equal(t, [][]int{{0, 2, 3, 4}, {1}, {5}}, Group(fingerprints, DefaultMaxDistance))
+	assert.Equal(t, [][]int{{0, 2, 3, 4}, {1, 5}}, Group(fingerprints, 4))
+	assert.Equal(t, [][]int{{0, 2}, {1}, {3}, {4}, {5}}, Group(fingerprints, 0))
+	assert.Empty(t, Group(nil, DefaultMaxDistance))
+}
@@ -0,0 +1,85 @@
+package cmdb
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Column names recognized in an inventory CSV, case-insensitively
+var (
+	csvHostColumns = []string{"host", "hostname", "host_name", "fqdn", "dns_name", "domain", "url", "name"}
+	csvIPColumns   = []string{"ip", "ip_address", "address", "cidr", "network"}
+	csvIDColumns   = []string{"id", "asset_id", "sys_id", "ci"}
+)
+
+// ReadCSV reads inventory entries from a CSV export. The first row names the
+// columns; the first recognized host, IP and ID columns are used, so exports
+// of most inventories can be read without editing.
+func ReadCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("inventory CSV is empty")
+		}
+		return nil, fmt.Errorf("failed to read inventory CSV header: %w", err)
+	}
+
+	hostCol := csvColumn(header, csvHostColumns)
+	ipCol := csvColumn(header, csvIPColumns)
+	idCol := csvColumn(header, csvIDColumns)
+	if hostCol < 0 && ipCol < 0 {
+		return nil, fmt.Errorf("inventory CSV has no host column (%s) or IP column (%s)",
+			strings.Join(csvHostColumns, ", "), strings.Join(csvIPColumns, ", "))
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inventory CSV: %w", err)
+		}
+
+		entry := Entry{
+			ID:     csvField(record, idCol),
+			Host:   csvField(record, hostCol),
+			IP:     csvField(record, ipCol),
+			Source: "csv",
+		}
+		if entry.Host == "" && entry.IP == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// csvColumn returns the index of the first header in names, or -1
+func csvColumn(header []string, names []string) int {
+	for _, name := range names {
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// csvField returns a record's trimmed field, or "" when the column is missing
+func csvField(record []string, column int) string {
+	if column < 0 || column >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[column])
+}
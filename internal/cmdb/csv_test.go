@@ -0,0 +1,34 @@
+package cmdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	input := `Asset_ID,Owner,FQDN,IP Address,IP
+a-1,web team,www.example.com,,203.0.113.10
+a-2,infra,,,198.51.100.0/24
+a-3,nobody,,,
+a-4,"platform, core",*.corp.example.com
+`
+	entries, err := ReadCSV(strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Entry{
+		{ID: "a-1", Host: "www.example.com", IP: "203.0.113.10", Source: "csv"},
+		{ID: "a-2", IP: "198.51.100.0/24", Source: "csv"},
+		{ID: "a-4", Host: "*.corp.example.com", Source: "csv"},
+	}, entries)
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader(""))
+	assert.ErrorContains(t, err, "empty")
+
+	_, err = ReadCSV(strings.NewReader("owner,location\nweb team,dc1\n"))
+	assert.ErrorContains(t, err, "no host column")
+}
@@ -0,0 +1,132 @@
+package cmdb
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Entry is one item of a company's asset inventory. Host may be a hostname
+// or a wildcard such as *.corp.example.com, and IP an address or a CIDR
+// range; either can be empty.
+type Entry struct {
+	ID     string `json:"id,omitempty"` // identifier in the inventory, e.g. a ServiceNow sys_id
+	Host   string `json:"host,omitempty"`
+	IP     string `json:"ip,omitempty"`
+	Source string `json:"source"` // csv or servicenow
+}
+
+// Inventory indexes inventory entries for matching discovered assets
+type Inventory struct {
+	entries   int
+	hosts     map[string]*Entry
+	wildcards map[string]*Entry // parent domain of each *. entry
+	ips       map[string]*Entry
+	networks  []inventoryNetwork
+}
+
+// inventoryNetwork is a CIDR range of the inventory
+type inventoryNetwork struct {
+	network *net.IPNet
+	entry   *Entry
+}
+
+// NewInventory indexes entries by host, wildcard, address and range.
+// Values that are neither are ignored.
+func NewInventory(entries []Entry) *Inventory {
+	inventory := &Inventory{
+		entries:   len(entries),
+		hosts:     make(map[string]*Entry),
+		wildcards: make(map[string]*Entry),
+		ips:       make(map[string]*Entry),
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+
+		if host := NormalizeHost(entry.Host); host != "" {
+			if parent, ok := strings.CutPrefix(host, "*."); ok {
+				inventory.wildcards[parent] = entry
+			} else {
+				inventory.hosts[host] = entry
+			}
+		}
+
+		value := strings.TrimSpace(entry.IP)
+		if _, network, err := net.ParseCIDR(value); err == nil {
+			inventory.networks = append(inventory.networks, inventoryNetwork{network: network, entry: entry})
+		} else if ip := net.ParseIP(value); ip != nil {
+			inventory.ips[ip.String()] = entry
+		}
+	}
+
+	return inventory
+}
+
+// Len returns the number of entries the inventory was built from
+func (i *Inventory) Len() int {
+	return i.entries
+}
+
+// MatchHost returns the entry that lists host itself, or a wildcard covering it
+func (i *Inventory) MatchHost(host string) *Entry {
+	host = NormalizeHost(host)
+	if host == "" {
+		return nil
+	}
+	if entry, ok := i.hosts[host]; ok {
+		return entry
+	}
+
+	for parent := host; ; {
+		_, rest, ok := strings.Cut(parent, ".")
+		if !ok {
+			return nil
+		}
+		if entry, ok := i.wildcards[rest]; ok {
+			return entry
+		}
+		parent = rest
+	}
+}
+
+// MatchIP returns the entry that lists an address, or a range containing it
+func (i *Inventory) MatchIP(value string) *Entry {
+	ip := net.ParseIP(strings.TrimSpace(value))
+	if ip == nil {
+		return nil
+	}
+	if entry, ok := i.ips[ip.String()]; ok {
+		return entry
+	}
+	for _, network := range i.networks {
+		if network.network.Contains(ip) {
+			return network.entry
+		}
+	}
+	return nil
+}
+
+// NormalizeHost reduces an inventory or asset value to a lowercase hostname,
+// dropping any scheme, path, port and trailing dot
+func NormalizeHost(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		value = u.Hostname()
+	} else {
+		value, _, _ = strings.Cut(value, "/")
+		if host, _, err := net.SplitHostPort(value); err == nil {
+			value = host
+		}
+	}
+
+	return strings.TrimSuffix(value, ".")
+}
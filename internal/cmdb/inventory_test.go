@@ -0,0 +1,60 @@
+package cmdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventory_Match(t *testing.T) {
+	inventory := NewInventory([]Entry{
+		{ID: "1", Host: "www.example.com"},
+		{ID: "2", Host: "*.corp.example.com"},
+		{ID: "3", Host: "https://API.example.com:8443/v1"},
+		{ID: "4", IP: "203.0.113.10"},
+		{ID: "5", IP: "198.51.100.0/24"},
+		{ID: "6", IP: "2001:db8::1"},
+		{ID: "7", Host: "not a host", IP: "not an ip"},
+	})
+	assert.Equal(t, 7, inventory.Len())
+
+	hosts := map[string]string{
+		"www.example.com":          "1",
+		"WWW.example.com.":         "1",
+		"vpn.corp.example.com":     "2",
+		"a.b.corp.example.com":     "2",
+		"api.example.com":          "3",
+		"https://www.example.com/": "1",
+	}
+	for host, id := range hosts {
+		if entry := inventory.MatchHost(host); assert.NotNil(t, entry, host) {
+			assert.Equal(t, id, entry.ID, host)
+		}
+	}
+
+	// The wildcard covers subdomains, not the domain itself
+	assert.Nil(t, inventory.MatchHost("corp.example.com"))
+	assert.Nil(t, inventory.MatchHost("shop.example.com"))
+	assert.Nil(t, inventory.MatchHost(""))
+
+	assert.Equal(t, "4", inventory.MatchIP("203.0.113.10").ID)
+	assert.Equal(t, "5", inventory.MatchIP("198.51.100.77").ID)
+	assert.Equal(t, "6", inventory.MatchIP("2001:0db8:0000::0001").ID)
+	assert.Nil(t, inventory.MatchIP("192.0.2.1"))
+	assert.Nil(t, inventory.MatchIP(""))
+}
+
+func TestNormalizeHost(t *testing.T) {
+	tests := map[string]string{
+		"Example.COM":                  "example.com",
+		"example.com.":                 "example.com",
+		"https://example.com:8443/a?b": "example.com",
+		"example.com:8080":             "example.com",
+		"example.com/path":             "example.com",
+		"*.Example.com":                "*.example.com",
+		"  ":                           "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, NormalizeHost(input), input)
+	}
+}
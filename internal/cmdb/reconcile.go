@@ -0,0 +1,110 @@
+package cmdb
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+)
+
+// Report is the outcome of reconciling discovered assets with an inventory
+type Report struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Inventory   int            `json:"inventory_entries"`
+	Assets      int            `json:"assets"`
+	KnownByHost int            `json:"known_by_host"` // assets whose host, or a wildcard covering it, is listed
+	KnownByIP   int            `json:"known_by_ip"`   // assets only matched through their address
+	Shadow      []*ShadowAsset `json:"shadow"`        // assets the inventory does not know
+}
+
+// ShadowAsset is a discovered asset missing from the inventory
+type ShadowAsset struct {
+	ProgramURL   string    `json:"program_url"`
+	URL          string    `json:"url"`
+	Host         string    `json:"host"`
+	IP           string    `json:"ip,omitempty"`
+	IPv6         string    `json:"ipv6,omitempty"`
+	Liveness     string    `json:"liveness,omitempty"`
+	FirstSource  string    `json:"first_source,omitempty"`
+	Provenance   []string  `json:"provenance,omitempty"` // every discovery source that found the asset
+	DataTerms    []string  `json:"data_terms,omitempty"` // usage terms the data of those sources comes with
+	DiscoveredAt time.Time `json:"discovered_at"`
+}
+
+// Reconcile compares discovered assets with the inventory. An asset is known
+// when its host is listed or covered by a wildcard, or when its IPv4 or IPv6
+// address is listed or in a listed range; the rest are shadow assets.
+func Reconcile(assets []*database.Asset, inventory *Inventory, now time.Time) *Report {
+	report := &Report{GeneratedAt: now, Inventory: inventory.Len(), Assets: len(assets)}
+
+	for _, asset := range assets {
+		host := NormalizeHost(asset.URL)
+		switch {
+		case inventory.MatchHost(host) != nil:
+			report.KnownByHost++
+		case inventory.MatchIP(asset.IP) != nil || inventory.MatchIP(asset.IPv6) != nil:
+			report.KnownByIP++
+		default:
+			report.Shadow = append(report.Shadow, &ShadowAsset{
+				ProgramURL:   asset.ProgramURL,
+				URL:          asset.URL,
+				Host:         host,
+				IP:           asset.IP,
+				IPv6:         asset.IPv6,
+				Liveness:     asset.Liveness,
+				FirstSource:  asset.FirstSource,
+				Provenance:   asset.Sources(),
+				DataTerms:    asset.DataTerms,
+				DiscoveredAt: asset.CreatedAt,
+			})
+		}
+	}
+
+	return report
+}
+
+// WriteJSON writes the whole report as JSON
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode reconciliation report: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the shadow assets of a report as CSV
+func WriteCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"program_url", "url", "host", "ip", "ipv6", "liveness", "first_source", "discovered_at", "provenance", "data_terms"}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, asset := range report.Shadow {
+		record := []string{
+			asset.ProgramURL,
+			asset.URL,
+			asset.Host,
+			asset.IP,
+			asset.IPv6,
+			asset.Liveness,
+			asset.FirstSource,
+			asset.DiscoveredAt.UTC().Format(time.RFC3339),
+			strings.Join(asset.Provenance, ";"),
+			strings.Join(asset.DataTerms, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	inventory := NewInventory([]Entry{
+		{Host: "www.example.com"},
+		{Host: "*.corp.example.com"},
+		{IP: "198.51.100.0/24"},
+		{IP: "2001:db8::1"},
+	})
+	discovered := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	assets := []*database.Asset{
+		{ProgramURL: "https://hackerone.com/acme", URL: "https://www.example.com"},
+		{ProgramURL: "https://hackerone.com/acme", URL: "https://vpn.corp.example.com"},
+		{ProgramURL: "https://hackerone.com/acme", URL: "https://cdn.example.com", IP: "198.51.100.20"},
+		{ProgramURL: "https://hackerone.com/acme", URL: "https://v6.example.com", IPv6: "2001:db8::1"},
+		{ProgramURL: "https://hackerone.com/acme", URL: "https://staging.example.com:8443", IP: "192.0.2.5",
+			Liveness: "live", FirstSource: "chaosdb", DataTerms: pq.StringArray{"ProjectDiscovery Chaos terms of use"}, CreatedAt: discovered},
+	}
+
+	report := Reconcile(assets, inventory, discovered)
+	assert.Equal(t, 4, report.Inventory)
+	assert.Equal(t, 5, report.Assets)
+	assert.Equal(t, 2, report.KnownByHost)
+	assert.Equal(t, 2, report.KnownByIP)
+	require.Len(t, report.Shadow, 1)
+	assert.Equal(t, &ShadowAsset{
+		ProgramURL:   "https://hackerone.com/acme",
+		URL:          "https://staging.example.com:8443",
+		Host:         "staging.example.com",
+		IP:           "192.0.2.5",
+		Liveness:     "live",
+		FirstSource:  "chaosdb",
+		Provenance:   []string{"chaosdb"},
+		DataTerms:    []string{"ProjectDiscovery Chaos terms of use"},
+		DiscoveredAt: discovered,
+	}, report.Shadow[0])
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, report))
+	assert.Equal(t, "program_url,url,host,ip,ipv6,liveness,first_source,discovered_at,provenance,data_terms\n"+
+		"https://hackerone.com/acme,https://staging.example.com:8443,staging.example.com,192.0.2.5,,live,chaosdb,2026-03-01T12:00:00Z,chaosdb,ProjectDiscovery Chaos terms of use\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteJSON(&buf, report))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded.Shadow, 1)
+	assert.Equal(t, 2, decoded.KnownByIP)
+}
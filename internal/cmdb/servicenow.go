@@ -0,0 +1,130 @@
+package cmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+)
+
+// serviceNowPageSize is the number of records requested per Table API page
+const serviceNowPageSize = 1000
+
+// serviceNowFields are the configuration item fields read from the table
+const serviceNowFields = "sys_id,fqdn,host_name,name,ip_address"
+
+// ServiceNowClient reads configuration items from the ServiceNow Table API
+type ServiceNowClient struct {
+	httpClient *resty.Client
+	baseURL    string
+	table      string
+}
+
+// ServiceNowConfig holds configuration for the ServiceNow client
+type ServiceNowConfig struct {
+	URL           string // instance URL, e.g. https://example.service-now.com
+	Username      string
+	Password      string
+	Table         string // CMDB table, e.g. cmdb_ci_server
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// serviceNowRecord is a configuration item as returned by the Table API
+type serviceNowRecord struct {
+	SysID     string `json:"sys_id"`
+	FQDN      string `json:"fqdn"`
+	HostName  string `json:"host_name"`
+	Name      string `json:"name"`
+	IPAddress string `json:"ip_address"`
+}
+
+// serviceNowResponse is a page of Table API results
+type serviceNowResponse struct {
+	Result []serviceNowRecord `json:"result"`
+}
+
+// NewServiceNowClient creates a new ServiceNow client
+func NewServiceNowClient(config *ServiceNowConfig) *ServiceNowClient {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+	client.SetBasicAuth(config.Username, config.Password)
+	client.SetHeaders(map[string]string{
+		"Accept":     "application/json",
+		"User-Agent": version.UserAgent(),
+	})
+
+	return &ServiceNowClient{
+		httpClient: client,
+		baseURL:    strings.TrimRight(config.URL, "/"),
+		table:      config.Table,
+	}
+}
+
+// Entries reads every configuration item of the table, page by page. The
+// fully qualified name is preferred over the host name and the display name.
+func (c *ServiceNowClient) Entries(ctx context.Context) ([]Entry, error) {
+	var entries []Entry
+	for offset := 0; ; offset += serviceNowPageSize {
+		resp, err := c.httpClient.R().
+			SetContext(ctx).
+			SetQueryParams(map[string]string{
+				"sysparm_fields":                 serviceNowFields,
+				"sysparm_limit":                  strconv.Itoa(serviceNowPageSize),
+				"sysparm_offset":                 strconv.Itoa(offset),
+				"sysparm_exclude_reference_link": "true",
+			}).
+			Get(c.baseURL + "/api/now/table/" + c.table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query ServiceNow table %s: %w", c.table, err)
+		}
+
+		switch {
+		case resp.StatusCode() == http.StatusUnauthorized || resp.StatusCode() == http.StatusForbidden:
+			return nil, fmt.Errorf("ServiceNow rejected the credentials (status %d), check CMDB_SERVICENOW_USER and CMDB_SERVICENOW_PASSWORD", resp.StatusCode())
+		case resp.IsError():
+			return nil, fmt.Errorf("ServiceNow returned status %d for table %s: %s", resp.StatusCode(), c.table, truncate(resp.String(), 200))
+		}
+
+		var page serviceNowResponse
+		if err := json.Unmarshal(resp.Body(), &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ServiceNow response: %w", err)
+		}
+
+		for _, record := range page.Result {
+			entry := Entry{ID: record.SysID, IP: record.IPAddress, Source: "servicenow"}
+			for _, host := range []string{record.FQDN, record.HostName, record.Name} {
+				if strings.Contains(host, ".") {
+					entry.Host = host
+					break
+				}
+			}
+			if entry.Host == "" && entry.IP == "" {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+
+		if len(page.Result) < serviceNowPageSize {
+			return entries, nil
+		}
+	}
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
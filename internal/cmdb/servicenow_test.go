@@ -0,0 +1,67 @@
+package cmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceNowClient_Entries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "svc", user)
+		assert.Equal(t, "secret", password)
+		assert.Equal(t, "/api/now/table/cmdb_ci_server", r.URL.Path)
+		assert.Equal(t, serviceNowFields, r.URL.Query().Get("sysparm_fields"))
+
+		// A full first page, then a short one
+		var records []serviceNowRecord
+		offset, _ := strconv.Atoi(r.URL.Query().Get("sysparm_offset"))
+		switch offset {
+		case 0:
+			for i := 0; i < serviceNowPageSize; i++ {
+				records = append(records, serviceNowRecord{SysID: strconv.Itoa(i), FQDN: fmt.Sprintf("host%d.example.com", i)})
+			}
+		case serviceNowPageSize:
+			records = []serviceNowRecord{
+				{SysID: "a", HostName: "db01.example.com", Name: "db01"},
+				{SysID: "b", Name: "web01", IPAddress: "203.0.113.10"},
+				{SysID: "c", Name: "printer"},
+			}
+		default:
+			t.Errorf("unexpected offset %d", offset)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(serviceNowResponse{Result: records}))
+	}))
+	defer server.Close()
+
+	client := NewServiceNowClient(&ServiceNowConfig{URL: server.URL + "/", Username: "svc", Password: "secret", Table: "cmdb_ci_server", Timeout: 5 * time.Second})
+	entries, err := client.Entries(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, entries, serviceNowPageSize+2)
+	assert.Equal(t, Entry{ID: "0", Host: "host0.example.com", Source: "servicenow"}, entries[0])
+	assert.Equal(t, Entry{ID: "a", Host: "db01.example.com", Source: "servicenow"}, entries[serviceNowPageSize])
+	// A bare display name is not a hostname, but the address still matches
+	assert.Equal(t, Entry{ID: "b", IP: "203.0.113.10", Source: "servicenow"}, entries[serviceNowPageSize+1])
+}
+
+func TestServiceNowClient_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewServiceNowClient(&ServiceNowConfig{URL: server.URL, Table: "cmdb_ci_server", Timeout: 5 * time.Second})
+	_, err := client.Entries(context.Background())
+	assert.ErrorContains(t, err, "CMDB_SERVICENOW_USER")
+}
@@ -293,6 +293,13 @@ func (akm *APIKeyManager) LoadKeysFromConfig(cfg *Config) error {
 		}
 	}
 
+	// Load Intigriti keys
+	if cfg.APIs.Intigriti.APIKey != "" {
+		if err := akm.AddKey("intigriti", cfg.APIs.Intigriti.APIKey, nil); err != nil {
+			return fmt.Errorf("failed to add Intigriti key: %w", err)
+		}
+	}
+
 	// Load ChaosDB keys
 	if cfg.APIs.ChaosDB.APIKey != "" {
 		if err := akm.AddKey("chaosdb", cfg.APIs.ChaosDB.APIKey, nil); err != nil {
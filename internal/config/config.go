@@ -2,23 +2,58 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/monitor-agent/internal/cron"
+	"github.com/monitor-agent/internal/probeauth"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Database  DatabaseConfig
-	APIs      APIConfig
-	App       AppConfig
-	HTTP      HTTPConfig
-	Discovery DiscoveryConfig
+	Database    DatabaseConfig
+	APIs        APIConfig
+	App         AppConfig
+	HTTP        HTTPConfig
+	Discovery   DiscoveryConfig
+	Programs    ProgramFilterConfig
+	Sync        SyncConfig
+	GRPC        GRPCConfig
+	HTTPAPI     HTTPAPIConfig
+	Maintenance MaintenanceConfig
+	Quota       QuotaConfig
+	Events      EventsConfig
+	Notify      NotifyConfig
+	Rules       RulesConfig
+	Scoring     ScoringConfig
+	Clustering  ClusteringConfig
+	Content     ContentChangeConfig
+	Retention   RetentionConfig
+	SLO         SLOConfig
+	Vantage     VantageConfig
+	Canary      CanaryConfig
+	Provenance  ProvenanceConfig
+	Search      SearchConfig
+	Whois       WhoisConfig
+	Daemon      DaemonConfig
+	Slack       SlackConfig
+	DefectDojo  DefectDojoConfig
+	CMDB        CMDBConfig
+	Notes       NotesConfig
+	ProbeAuth   ProbeAuthConfig
+	Quarantine  QuarantineConfig
+	StatusPage  StatusPageConfig
+	ScanReport  ScanReportConfig
+	ObjectStore ObjectStoreConfig
+	BodyStore   BodyStoreConfig
+	Metrics     MetricsConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -36,38 +71,85 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	WriteBatchSize  int    // rows per insert batch during discovery; 0 writes each set in one batch
+	WritesPerSecond int    // soft limit on rows written per second; 0 disables throttling
+	MigrationsDir   string // directory of *.sql migrations applied instead of the embedded ones
+	// MigrationsManual leaves applying migrations to `monitor-agent migrate`;
+	// other commands only check that the schema matches the binary
+	MigrationsManual     bool
+	MigrationLockTimeout time.Duration // how long to wait for another process's migrations; 0 uses the default
 }
 
 // APIConfig holds API configuration
 type APIConfig struct {
 	HackerOne HackerOneConfig
 	BugCrowd  BugCrowdConfig
+	Intigriti IntigritiConfig
 	ChaosDB   ChaosDBConfig
+	CRTSh     CRTShConfig
 }
 
 // HackerOneConfig holds HackerOne API configuration
 type HackerOneConfig struct {
-	APIKey    string
-	Username  string
-	RateLimit int
+	APIKey      string
+	Username    string
+	RateLimit   int
+	RateBurst   int                  // most requests sent in a burst; 0 uses RateLimit
+	Credentials []PlatformCredential // additional accounts rotated through on quota or auth failures
+	BaseURL     string               // overrides the API URL, e.g. to point at cmd/mock-platform
+
+	IncludePrivate bool // also monitors the private programs the account was invited to
 }
 
 // BugCrowdConfig holds BugCrowd API configuration
 type BugCrowdConfig struct {
-	APIKey    string
-	RateLimit int
+	APIKey      string
+	RateLimit   int
+	RateBurst   int                  // most requests sent in a burst; 0 uses RateLimit
+	Credentials []PlatformCredential // additional accounts rotated through on quota or auth failures
+	BaseURL     string               // overrides the API URL, e.g. to point at cmd/mock-platform
+}
+
+// IntigritiConfig holds Intigriti API configuration
+type IntigritiConfig struct {
+	APIKey      string
+	RateLimit   int
+	RateBurst   int                  // most requests sent in a burst; 0 uses RateLimit
+	Credentials []PlatformCredential // additional accounts rotated through on quota or auth failures
+	BaseURL     string               // overrides the API URL
+}
+
+// PlatformCredential is an additional named platform account, e.g. one per workspace
+type PlatformCredential struct {
+	Name     string
+	Username string // HackerOne only
+	APIKey   string
 }
 
 // ChaosDBConfig holds ChaosDB API configuration
 type ChaosDBConfig struct {
-	APIKey    string
-	RateLimit int
+	APIKey          string
+	RateLimit       int
+	RateBurst       int    // most requests sent in a burst; 0 uses RateLimit
+	Datasets        bool   // use the bulk dataset download when ChaosDB publishes one for the program
+	BaseURL         string // overrides the API URL, e.g. to point at cmd/mock-platform
+	DatasetIndexURL string // overrides the bulk dataset index URL
+}
+
+// CRTShConfig holds configuration of crt.sh certificate transparency
+// discovery, which needs no API key
+type CRTShConfig struct {
+	Enabled bool
+	BaseURL string // overrides the crt.sh URL
 }
 
 // AppConfig holds application configuration
 type AppConfig struct {
 	LogLevel    string
+	LogFormat   string // text or json; empty is text
 	Environment string
+	Passive     bool // only collect from platform APIs and ChaosDB, never send probes to targets
+	ReadOnly    bool // only query: no scans, probes or database writes
 }
 
 // HTTPConfig holds HTTP client configuration
@@ -79,9 +161,73 @@ type HTTPConfig struct {
 
 // DiscoveryConfig holds discovery configuration
 type DiscoveryConfig struct {
-	BulkSize int
-	HTTPX    HTTPXConfig
-	Timeouts TimeoutConfig
+	BulkSize       int
+	PipelineDepth  int // discovered domains queued ahead of probing; 0 discovers and probes each domain in turn
+	ScopeChunkSize int // scope assets fetched and saved per chunk; 0 uses the platform's page size
+	HTTPX          HTTPXConfig
+	DNS            DNSConfig
+	CTLog          CTLogConfig
+	Retry          DiscoveryRetryConfig
+	Timeouts       TimeoutConfig
+
+	ProgramConcurrency int // programs processed at once across all platforms of a scan; 0 or 1 processes one at a time
+}
+
+// ProgramFilterConfig holds the glob patterns selecting which programs scans
+// process. Patterns match a program's handle, platform/handle, name or URL,
+// ignoring case.
+type ProgramFilterConfig struct {
+	Include []string // only programs matching one of these are scanned; empty scans every program
+	Exclude []string // programs matching one of these are skipped, even when included
+}
+
+// Selects reports whether a program known by any of names is scanned
+func (p ProgramFilterConfig) Selects(names ...string) bool {
+	if len(p.Include) > 0 && !matchesAnyPattern(p.Include, names) {
+		return false
+	}
+	return !matchesAnyPattern(p.Exclude, names)
+}
+
+// matchesAnyPattern reports whether any name matches one of the patterns,
+// ignoring case
+func matchesAnyPattern(patterns, names []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		for _, name := range names {
+			if matched, _ := path.Match(pattern, strings.ToLower(name)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DiscoveryRetryConfig holds the retries of scope domains whose discovery or
+// probe failed during a scan
+type DiscoveryRetryConfig struct {
+	MaxAttempts int           // failed attempts after which a domain is no longer retried; 0 records no failures
+	Backoff     time.Duration // delay before the first retry, doubled after every failed attempt
+	MaxBackoff  time.Duration // longest delay between retries
+}
+
+// CTLogConfig holds the certificate transparency watch of the daemon, which
+// probes and saves new hostnames below program scopes as certificates are
+// logged for them
+type CTLogConfig struct {
+	Enabled       bool
+	StreamURL     string        // CertStream websocket URL; the public CertStream server when empty
+	FlushInterval time.Duration // how long hostnames are collected before they are probed
+	MaxPending    int           // hostnames held until the next flush; further ones are dropped
+}
+
+// DNSConfig holds the DNS resolution of asset hostnames, which records their
+// addresses and CNAME chains and flags those that no longer resolve
+type DNSConfig struct {
+	Enabled     bool
+	Concurrency int
+	Timeout     time.Duration // per query and resolver
+	Resolvers   []string      // host or host:port, tried in order; the system resolvers when empty
 }
 
 // HTTPXConfig holds HTTPX probe configuration
@@ -92,24 +238,371 @@ type HTTPXConfig struct {
 	RateLimit       int
 	FollowRedirects bool
 	MaxRedirects    int
-	Debug           bool // Enable debug logging for HTTPX probes
+	Debug           bool   // Enable debug logging for HTTPX probes
+	IPVersion       string // ipv4 (default), ipv6 or dual
+	TLSChecks       bool   // Record TLS misconfigurations (expired, self-signed, mismatched, legacy protocol) as findings
+	ScanMethod      string // GET (default) or HEAD; HEAD probes record no body
 }
 
-// TimeoutConfig holds program-level timeouts
+// TimeoutConfig holds scan and program-level timeouts.
+//
+// Timeouts nest from outermost to innermost, and each inner timeout must fit
+// inside the one enclosing it:
+//
+//	Scan (optional)  >= ProgramMax and ProgramOverrides
+//	ProgramMax       >= ProgramProcess
+//	ProgramProcess   >= ChaosDiscovery + ProgramTimeoutBuffer
+//	ChaosDiscovery   >= HTTPX.Timeout and the worst-case HTTP request time
+//	                    (HTTP.Timeout per attempt, plus retries and retry delays)
+//
+// A program's own timeout is ProgramProcess, extended to ProgramFactor times
+// its longest recent scan, up to ProgramMax, unless ProgramOverrides sets it.
 type TimeoutConfig struct {
-	ProgramProcess time.Duration
+	Scan           time.Duration // whole scan; 0 means no limit
+	ProgramProcess time.Duration // defaults to ChaosDiscovery + ProgramTimeoutBuffer
 	ChaosDiscovery time.Duration
+
+	ProgramFactor    float64                  // recent scan duration multiple a program's timeout is extended to; 0 disables extending
+	ProgramMax       time.Duration            // longest extended program timeout; defaults to 4x ProgramProcess, within Scan
+	ProgramOverrides map[string]time.Duration // timeouts by lowercase handle or platform/handle, used instead of ProgramProcess
+}
+
+// ProgramTimeout returns the processing timeout of a program, given its handle
+// on platform and the longest of its recent scans (0 when it has none)
+func (t TimeoutConfig) ProgramTimeout(platform, handle string, longest time.Duration) time.Duration {
+	if timeout, ok := t.ProgramOverrides[strings.ToLower(platform+"/"+handle)]; ok {
+		return timeout
+	}
+	if timeout, ok := t.ProgramOverrides[strings.ToLower(handle)]; ok {
+		return timeout
+	}
+
+	timeout := t.ProgramProcess
+	if extended := time.Duration(float64(longest) * t.ProgramFactor).Round(time.Second); extended > timeout {
+		timeout = min(extended, max(t.ProgramMax, t.ProgramProcess))
+	}
+	return timeout
+}
+
+const (
+	// DefaultChaosDiscoveryTimeout is used when CHAOS_DISCOVERY_TIMEOUT is not set
+	DefaultChaosDiscoveryTimeout = 30 * time.Minute
+
+	// ProgramTimeoutBuffer is the headroom a program needs beyond ChaosDB discovery
+	// for fetching scope and saving results
+	ProgramTimeoutBuffer = 15 * time.Minute
+)
+
+// SyncConfig holds edge-to-central sync configuration
+type SyncConfig struct {
+	ServerURL  string // central server edge agents push to
+	Token      string // shared bearer token for push and serve
+	AgentID    string // defaults to the hostname when empty
+	BatchSize  int    // maximum assets per push request
+	ListenAddr string // address for `sync serve`
+}
+
+// GRPCConfig holds the gRPC API served to internal services
+type GRPCConfig struct {
+	ListenAddr string // address for `grpc serve`
+	Token      string // bearer token clients must send
+}
+
+// HTTPAPIConfig holds the REST API served to dashboards and other tooling
+type HTTPAPIConfig struct {
+	ListenAddr string // address for `api serve`
+	Token      string // bearer token clients must send
+}
+
+// MaintenanceConfig controls how scans react to platform maintenance windows
+type MaintenanceConfig struct {
+	RetryDelay time.Duration // wait before retrying when the platform gives no Retry-After
+	MaxRetries int           // retries within one scan before deferring the platform to the next scan
+	MaxWait    time.Duration // longest a scan waits in-process for a platform to come back
+}
+
+// QuotaConfig holds the default asset quota bounds checked after each program scan.
+// Programs can override them; a zero value disables that check.
+type QuotaConfig struct {
+	MaxDropPercent float64 // alert when assets seen drop by more than this percentage
+	MaxGrowth      int     // alert when assets seen grow by more than this many
+	MinAssets      int     // skip drop checks when the previous scan saw fewer assets
+}
+
+// EventsConfig controls delivery of CloudEvents for program, asset and scope changes
+type EventsConfig struct {
+	Source        string // CloudEvents source attribute identifying this agent
+	WebhookURL    string // events are POSTed here when set
+	WebhookSecret string // signs webhook bodies with HMAC-SHA256 when set
+
+	KafkaBrokers []string // events are written to Kafka when set
+	KafkaTopic   string
+	NATSURL      string // events are published to NATS when set
+	NATSSubject  string // subject prefix; the event type is appended
+
+	RoutesFile string // YAML file routing program and tag events to their own channels
+
+	DigestAttachment    string // csv or json to attach a scan's new assets to its scan.digest event; none when empty
+	DigestAttachmentDir string // attachments are written here and linked instead of sent inline when set
+	DigestAttachmentURL string // base URL DigestAttachmentDir is served under
+}
+
+// NotifyConfig controls chat and webhook notifications of newly discovered
+// programs and assets. Each sink is sent to only when it is enabled.
+type NotifyConfig struct {
+	SlackEnabled      bool
+	SlackWebhookURL   string // Slack incoming webhook
+	DiscordEnabled    bool
+	DiscordWebhookURL string // Discord channel webhook
+	WebhookEnabled    bool
+	WebhookURL        string // receives batches as JSON documents
+	WebhookSecret     string // signs generic webhook bodies with HMAC-SHA256 when set
+
+	BatchSize     int           // notifications sent in one message at most
+	BatchInterval time.Duration // pending notifications are sent at least this often; 0 waits for a full batch or the end of the program's scan
+	RetryAttempts int           // retries of a failed delivery, including rate limited ones
+	RetryDelay    time.Duration
+}
+
+// RulesConfig holds the triage rules evaluated on asset responses
+type RulesConfig struct {
+	File string // YAML rules file; rules are disabled when empty
+}
+
+// ScoringConfig holds the model assets are scored with
+type ScoringConfig struct {
+	File string // YAML scoring file; the built-in weights are used when empty
+}
+
+// ClusteringConfig holds how live assets with near-identical responses are
+// grouped for triage
+type ClusteringConfig struct {
+	MaxDistance int // differing body fingerprint bits up to which responses are grouped; 0 groups identical bodies only
+}
+
+// ContentChangeConfig holds when a new capture of an asset counts as a
+// content change from the previous one
+type ContentChangeConfig struct {
+	MinDistance int // body fingerprint bits that must differ for a changed body to count; 0 counts every changed byte
+}
+
+// RetentionConfig holds how long stored asset responses are kept. Responses
+// outside it are pruned after each full scan and by the prune command.
+type RetentionConfig struct {
+	ResponsesPerAsset int           // responses kept per asset and method; 0 keeps all
+	ResponseMaxAge    time.Duration // responses older than this are pruned; 0 keeps them
+}
+
+// SLOConfig holds the scan freshness service-level objectives. Programs and
+// assets violating them are reported by stats and scheduled first.
+type SLOConfig struct {
+	ProgramScanWithin time.Duration // every active program completes a scan within this; 0 disables the objective
+	AssetProbeWithin  time.Duration // every swept asset is probed within this; 0 disables the objective
+}
+
+// SearchConfig holds the optional OpenSearch/Elasticsearch mirror of asset
+// responses; Postgres remains the source of truth
+type SearchConfig struct {
+	URL              string // leave empty to disable the mirror
+	Index            string
+	Username         string
+	Password         string
+	BodyExcerptBytes int // body bytes kept per document; 0 keeps the whole body
+}
+
+// WhoisConfig holds the registration data (RDAP/WHOIS) enrichment of apex
+// domains and asset IPs
+type WhoisConfig struct {
+	Enabled         bool
+	IPLookups       bool          // look up the country and provider of asset IPs
+	ServerURL       string        // RDAP server or bootstrap service
+	NewDomainDays   int           // domains registered within this many days are flagged
+	RefreshInterval time.Duration // how long a lookup is reused before it is repeated
+}
+
+// DaemonConfig holds the background work of `monitor-agent daemon`
+type DaemonConfig struct {
+	SweepRequestsPerHour int           // probe budget of the incremental liveness sweep; 0 disables the sweep
+	SweepBatchSize       int           // assets re-probed per sweep batch; batches are spread evenly over the hour
+	SweepMethod          string        // GET (default) or HEAD; HEAD sweeps refresh liveness, status and headers only
+	WatchlistInterval    time.Duration // how often watched hostnames are checked; 0 disables the checks
+	ScanSchedule         string        // cron expression full scans run on, in local time; empty disables scheduled scans
+}
+
+// SlackConfig holds the Slack bot run by `monitor-agent slack-bot`
+type SlackConfig struct {
+	AppToken        string   // app-level token (xapp-) with connections:write, used for socket mode
+	Command         string   // slash command the bot answers
+	AllowedUsers    []string // user IDs allowed to run commands; everyone in the workspace when empty
+	AllowedChannels []string // channel IDs commands are accepted in; all channels when empty
+}
+
+// DefectDojoConfig holds the DefectDojo instance `monitor-agent defectdojo push` exports to
+type DefectDojoConfig struct {
+	URL         string // DefectDojo base URL; export is disabled when empty
+	APIKey      string // API v2 key of the user the export runs as
+	ProductType string // product type products are created under
+}
+
+// CMDBConfig holds the inventory `monitor-agent cmdb reconcile` compares assets with
+type CMDBConfig struct {
+	CSV                string // path of an inventory CSV export
+	ServiceNowURL      string // ServiceNow instance URL; the ServiceNow inventory is disabled when empty
+	ServiceNowUser     string
+	ServiceNowPassword string
+	ServiceNowTable    string // CMDB table configuration items are read from
+}
+
+// NotesConfig holds the Markdown notes vault written by `monitor-agent notes export`
+type NotesConfig struct {
+	VaultDir string // directory notes are written to after each scan; disabled when empty
+}
+
+// ProbeAuthConfig holds the key per-program probe auth profiles are sealed with
+type ProbeAuthConfig struct {
+	Key string // base64 encoded 32 byte AES key; profiles cannot be set or used without it
+}
+
+// Scope quarantine modes
+const (
+	QuarantineOff    = "off"     // assets outside the current scope are left alone
+	QuarantineDryRun = "dry-run" // assets that would be quarantined are only logged
+	QuarantineOn     = "on"      // assets outside the scope for the grace period are quarantined
+)
+
+// QuarantineConfig holds the quarantine of assets whose scope root was removed
+type QuarantineConfig struct {
+	Mode  string        // off, dry-run or on; empty is off
+	Grace time.Duration // how long an asset is outside the scope before it is quarantined
+}
+
+// StatusPageConfig holds the static status page written by `monitor-agent report html`
+type StatusPageConfig struct {
+	Dir   string // directory the page is written to after each scan; disabled when empty
+	Title string // page title
+}
+
+// Scan report formats
+const (
+	ScanReportMarkdown = "markdown"
+	ScanReportHTML     = "html"
+)
+
+// ScanReportConfig holds the report of new programs, scope changes, new and
+// dead assets and errors written or posted after each scan, and regenerated
+// by `monitor-agent report --scan`
+type ScanReportConfig struct {
+	Dir           string // directory a report of each scan is written to; disabled when empty
+	Format        string // markdown or html; empty is markdown
+	WebhookURL    string // the report of each scan is posted here; disabled when empty
+	WebhookSecret string // signs the posted body like EVENTS_WEBHOOK_SECRET
+}
+
+// Enabled reports whether a report is written or posted after each scan
+func (c ScanReportConfig) Enabled() bool {
+	return c.Dir != "" || c.WebhookURL != ""
+}
+
+// MetricsConfig holds the Prometheus endpoint served while scans and the
+// daemon run
+type MetricsConfig struct {
+	Enabled    bool
+	ListenAddr string // address the endpoint listens on
+	Path       string // path metrics are served under
+}
+
+// ObjectStoreConfig holds the S3 bucket `monitor-agent report share` uploads
+// scan reports to before handing out presigned URLs
+type ObjectStoreConfig struct {
+	Bucket          string // bucket reports are stored in; sharing is disabled when empty
+	Region          string
+	Endpoint        string // S3-compatible endpoint such as MinIO; AWS when empty
+	PathStyle       bool   // address the bucket in the URL path, as most S3-compatible stores need
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // set for temporary credentials
+	Prefix          string // key prefix reports are stored under, for lifecycle rules
+}
+
+// BodyStoreConfig holds the bucket response bodies are stored in instead of
+// asset_responses. S3 buckets use the region, endpoint and credentials of
+// ObjectStoreConfig.
+type BodyStoreConfig struct {
+	Backend            string // s3 or gcs
+	Bucket             string // bodies are stored in the database when empty
+	Prefix             string // key prefix bodies are stored under
+	MinBytes           int    // smaller bodies are stored in the database
+	GCSCredentialsFile string // service account key file for gcs
+	GCSEndpoint        string // GCS emulator endpoint; Google when empty
+}
+
+// VantageConfig holds the remote probe workers probe batches are dispatched to,
+// and the settings for running this agent as a worker
+type VantageConfig struct {
+	Region     string          // region this agent probes from
+	Workers    []VantageWorker // remote workers in other regions
+	Token      string          // bearer token shared by agents and workers
+	ListenAddr string          // address for `probe-worker`
+}
+
+// VantageWorker is a remote probe worker in another region
+type VantageWorker struct {
+	Region string
+	URL    string
+}
+
+// CanaryConfig holds the canary hostnames every scan checks before it probes
+// any target, telling a broken pipeline apart from targets that went dead
+type CanaryConfig struct {
+	Targets   []CanaryTarget // hostnames the user controls; none disables the checks
+	OnFailure string         // abort (default) skips the scan when a canary fails; alert only reports it
+}
+
+// CanaryTarget is a canary hostname and the status code probing it must return
+type CanaryTarget struct {
+	Hostname   string
+	StatusCode int // 0 accepts any HTTP answer
+}
+
+// ProvenanceConfig holds the usage terms of each discovery source, recorded
+// with the assets it finds, and the sources exports leave out
+type ProvenanceConfig struct {
+	SourceTerms    map[string]string // terms by discovery source, e.g. chaosdb
+	ExcludeSources []string          // exports leave out assets only these sources found
+}
+
+// DefaultDataSourceTerms is used when DATA_SOURCE_TERMS is not set: ChaosDB
+// data is shared under ProjectDiscovery's terms of use
+const DefaultDataSourceTerms = "chaosdb=ProjectDiscovery Chaos terms of use"
+
+// TermsOf returns the usage terms of a discovery source; empty when it has none
+func (p ProvenanceConfig) TermsOf(source string) string {
+	return p.SourceTerms[source]
 }
 
 // Load loads configuration from YAML config file and environment variables
 func Load() (*Config, error) {
+	return LoadFrom("")
+}
+
+// LoadFrom loads configuration like Load, reading the YAML config file at
+// configPath instead of looking for configs/config.yaml. A config file that
+// was asked for explicitly has to exist and parse.
+func LoadFrom(configPath string) (*Config, error) {
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
 		logrus.Debug("No .env file found, using environment variables")
 	}
 
 	// Try to load from config file first
-	config, err := loadFromConfigFile()
+	explicit := configPath != ""
+	if !explicit {
+		configPath = getConfigPath()
+	}
+	config, err := loadFromConfigFile(configPath)
+	if err != nil && explicit {
+		return nil, err
+	}
 	if err != nil {
 		logrus.Debugf("Failed to load from config file, using environment variables: %v", err)
 		config = &Config{}
@@ -117,6 +610,7 @@ func Load() (*Config, error) {
 		logrus.Info("Configuration loaded from config file")
 		// Still load sensitive values from environment variables
 		loadSensitiveFromEnv(config)
+		config.Discovery.Timeouts.applyDefaults()
 		return config, nil
 	}
 
@@ -147,6 +641,26 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid DB_CONN_MAX_LIFETIME: %w", err)
 	}
 
+	writeBatchSize, err := strconv.Atoi(getEnv("DB_WRITE_BATCH_SIZE", "500"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_WRITE_BATCH_SIZE: %w", err)
+	}
+
+	writesPerSecond, err := strconv.Atoi(getEnv("DB_WRITES_PER_SECOND", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_WRITES_PER_SECOND: %w", err)
+	}
+
+	migrationsManual, err := strconv.ParseBool(getEnv("MIGRATIONS_MANUAL", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MIGRATIONS_MANUAL: %w", err)
+	}
+
+	migrationLockTimeout, err := time.ParseDuration(getEnv("MIGRATIONS_LOCK_TIMEOUT", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MIGRATIONS_LOCK_TIMEOUT: %w", err)
+	}
+
 	config.Database = DatabaseConfig{
 		Host:            getEnv("DB_HOST", "localhost"),
 		Port:            dbPort,
@@ -161,6 +675,12 @@ func Load() (*Config, error) {
 		MaxOpenConns:    maxOpenConns,
 		MaxIdleConns:    maxIdleConns,
 		ConnMaxLifetime: connMaxLifetime,
+		WriteBatchSize:  writeBatchSize,
+		WritesPerSecond: writesPerSecond,
+		MigrationsDir:   getEnv("MIGRATIONS_DIR", ""),
+
+		MigrationsManual:     migrationsManual,
+		MigrationLockTimeout: migrationLockTimeout,
 	}
 
 	// API configuration
@@ -169,36 +689,102 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid HACKERONE_RATE_LIMIT: %w", err)
 	}
 
+	hackerOneRateBurst, err := strconv.Atoi(getEnv("HACKERONE_RATE_BURST", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HACKERONE_RATE_BURST: %w", err)
+	}
+
 	bugCrowdRateLimit, err := strconv.Atoi(getEnv("BUGCROWD_RATE_LIMIT", "55"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid BUGCROWD_RATE_LIMIT: %w", err)
 	}
 
+	bugCrowdRateBurst, err := strconv.Atoi(getEnv("BUGCROWD_RATE_BURST", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BUGCROWD_RATE_BURST: %w", err)
+	}
+
+	intigritiRateLimit, err := strconv.Atoi(getEnv("INTIGRITI_RATE_LIMIT", "55"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INTIGRITI_RATE_LIMIT: %w", err)
+	}
+
+	intigritiRateBurst, err := strconv.Atoi(getEnv("INTIGRITI_RATE_BURST", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INTIGRITI_RATE_BURST: %w", err)
+	}
+
+	hackerOneCredentials, err := parsePlatformCredentials("HACKERONE_CREDENTIALS", getEnv("HACKERONE_CREDENTIALS", ""), true)
+	if err != nil {
+		return nil, err
+	}
+
+	bugCrowdCredentials, err := parsePlatformCredentials("BUGCROWD_CREDENTIALS", getEnv("BUGCROWD_CREDENTIALS", ""), false)
+	if err != nil {
+		return nil, err
+	}
+
+	intigritiCredentials, err := parsePlatformCredentials("INTIGRITI_CREDENTIALS", getEnv("INTIGRITI_CREDENTIALS", ""), false)
+	if err != nil {
+		return nil, err
+	}
+
 	chaosDBRateLimit, err := strconv.Atoi(getEnv("CHAOSDB_RATE_LIMIT", "55"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid CHAOSDB_RATE_LIMIT: %w", err)
 	}
 
+	chaosDBRateBurst, err := strconv.Atoi(getEnv("CHAOSDB_RATE_BURST", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHAOSDB_RATE_BURST: %w", err)
+	}
+
 	config.APIs = APIConfig{
 		HackerOne: HackerOneConfig{
-			APIKey:    getEnv("HACKERONE_API_KEY", ""),
-			Username:  getEnv("HACKERONE_USERNAME", ""),
-			RateLimit: hackerOneRateLimit,
+			APIKey:      getEnv("HACKERONE_API_KEY", ""),
+			Username:    getEnv("HACKERONE_USERNAME", ""),
+			RateLimit:   hackerOneRateLimit,
+			RateBurst:   hackerOneRateBurst,
+			Credentials: hackerOneCredentials,
+			BaseURL:     getEnv("HACKERONE_BASE_URL", ""),
+
+			IncludePrivate: getEnv("HACKERONE_INCLUDE_PRIVATE", "false") == "true",
 		},
 		BugCrowd: BugCrowdConfig{
-			APIKey:    getEnv("BUGCROWD_API_KEY", ""),
-			RateLimit: bugCrowdRateLimit,
+			APIKey:      getEnv("BUGCROWD_API_KEY", ""),
+			RateLimit:   bugCrowdRateLimit,
+			RateBurst:   bugCrowdRateBurst,
+			Credentials: bugCrowdCredentials,
+			BaseURL:     getEnv("BUGCROWD_BASE_URL", ""),
+		},
+		Intigriti: IntigritiConfig{
+			APIKey:      getEnv("INTIGRITI_API_KEY", ""),
+			RateLimit:   intigritiRateLimit,
+			RateBurst:   intigritiRateBurst,
+			Credentials: intigritiCredentials,
+			BaseURL:     getEnv("INTIGRITI_BASE_URL", ""),
 		},
 		ChaosDB: ChaosDBConfig{
-			APIKey:    getEnv("CHAOSDB_API_KEY", ""),
-			RateLimit: chaosDBRateLimit,
+			APIKey:          getEnv("CHAOSDB_API_KEY", ""),
+			RateLimit:       chaosDBRateLimit,
+			RateBurst:       chaosDBRateBurst,
+			Datasets:        getEnv("CHAOSDB_DATASETS", "true") == "true",
+			BaseURL:         getEnv("CHAOSDB_BASE_URL", ""),
+			DatasetIndexURL: getEnv("CHAOSDB_DATASET_INDEX_URL", ""),
+		},
+		CRTSh: CRTShConfig{
+			Enabled: getEnv("CRTSH_ENABLED", "false") == "true",
+			BaseURL: getEnv("CRTSH_BASE_URL", ""),
 		},
 	}
 
 	// Application configuration
 	config.App = AppConfig{
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		LogFormat:   getEnv("LOG_FORMAT", "text"),
 		Environment: getEnv("ENVIRONMENT", "development"),
+		Passive:     getEnv("PASSIVE_MODE", "false") == "true",
+		ReadOnly:    getEnv("READ_ONLY", "false") == "true",
 	}
 
 	// HTTP configuration
@@ -256,18 +842,101 @@ func Load() (*Config, error) {
 
 	httpxDebug := getEnv("HTTPX_DEBUG", "false") == "true"
 
-	programProcessTimeout, err := time.ParseDuration(getEnv("PROGRAM_PROCESS_TIMEOUT", "45m"))
+	httpxIPVersion := getEnv("HTTPX_IP_VERSION", "ipv4")
+
+	httpxTLSChecks := getEnv("HTTPX_TLS_CHECKS", "true") == "true"
+
+	httpxMethod := strings.ToUpper(getEnv("HTTPX_METHOD", "GET"))
+
+	// DNS resolution configuration
+	dnsConcurrency, err := strconv.Atoi(getEnv("DNS_CONCURRENCY", "50"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNS_CONCURRENCY: %w", err)
+	}
+
+	dnsTimeout, err := time.ParseDuration(getEnv("DNS_TIMEOUT", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNS_TIMEOUT: %w", err)
+	}
+
+	// Certificate transparency watch configuration
+	ctlogFlushInterval, err := time.ParseDuration(getEnv("CTLOG_FLUSH_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CTLOG_FLUSH_INTERVAL: %w", err)
+	}
+
+	ctlogMaxPending, err := strconv.Atoi(getEnv("CTLOG_MAX_PENDING", "10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CTLOG_MAX_PENDING: %w", err)
+	}
+
+	// Discovery retry configuration
+	retryMaxAttempts, err := strconv.Atoi(getEnv("DISCOVERY_RETRY_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISCOVERY_RETRY_MAX_ATTEMPTS: %w", err)
+	}
+
+	retryBackoff, err := time.ParseDuration(getEnv("DISCOVERY_RETRY_BACKOFF", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISCOVERY_RETRY_BACKOFF: %w", err)
+	}
+
+	retryMaxBackoff, err := time.ParseDuration(getEnv("DISCOVERY_RETRY_MAX_BACKOFF", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISCOVERY_RETRY_MAX_BACKOFF: %w", err)
+	}
+
+	scanTimeout, err := parseOptionalDuration("SCAN_TIMEOUT")
+	if err != nil {
+		return nil, err
+	}
+
+	programProcessTimeout, err := parseOptionalDuration("PROGRAM_PROCESS_TIMEOUT")
+	if err != nil {
+		return nil, err
+	}
+
+	chaosDiscoveryTimeout, err := parseOptionalDuration("CHAOS_DISCOVERY_TIMEOUT")
+	if err != nil {
+		return nil, err
+	}
+
+	programTimeoutFactor, err := strconv.ParseFloat(getEnv("PROGRAM_TIMEOUT_FACTOR", "1.5"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROGRAM_TIMEOUT_FACTOR: %w", err)
+	}
+
+	programTimeoutMax, err := parseOptionalDuration("PROGRAM_TIMEOUT_MAX")
+	if err != nil {
+		return nil, err
+	}
+
+	programTimeouts, err := parseProgramTimeouts(getEnv("PROGRAM_TIMEOUTS", ""))
 	if err != nil {
-		return nil, fmt.Errorf("invalid PROGRAM_PROCESS_TIMEOUT: %w", err)
+		return nil, err
 	}
 
-	chaosDiscoveryTimeout, err := time.ParseDuration(getEnv("CHAOS_DISCOVERY_TIMEOUT", "30m"))
+	pipelineDepth, err := strconv.Atoi(getEnv("DISCOVERY_PIPELINE_DEPTH", "2"))
 	if err != nil {
-		return nil, fmt.Errorf("invalid CHAOS_DISCOVERY_TIMEOUT: %w", err)
+		return nil, fmt.Errorf("invalid DISCOVERY_PIPELINE_DEPTH: %w", err)
+	}
+
+	scopeChunkSize, err := strconv.Atoi(getEnv("SCOPE_CHUNK_SIZE", "500"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCOPE_CHUNK_SIZE: %w", err)
+	}
+
+	programConcurrency, err := strconv.Atoi(getEnv("PROGRAM_CONCURRENCY", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROGRAM_CONCURRENCY: %w", err)
 	}
 
 	config.Discovery = DiscoveryConfig{
-		BulkSize: bulkSize,
+		BulkSize:       bulkSize,
+		PipelineDepth:  pipelineDepth,
+		ScopeChunkSize: scopeChunkSize,
+
+		ProgramConcurrency: programConcurrency,
 		HTTPX: HTTPXConfig{
 			Enabled:         httpxEnabled,
 			Timeout:         httpxTimeout,
@@ -276,286 +945,1755 @@ func Load() (*Config, error) {
 			FollowRedirects: httpxFollowRedirects,
 			MaxRedirects:    httpxMaxRedirects,
 			Debug:           httpxDebug,
+			IPVersion:       httpxIPVersion,
+			TLSChecks:       httpxTLSChecks,
+			ScanMethod:      httpxMethod,
+		},
+		DNS: DNSConfig{
+			Enabled:     getEnv("DNS_ENABLED", "true") == "true",
+			Concurrency: dnsConcurrency,
+			Timeout:     dnsTimeout,
+			Resolvers:   splitList(getEnv("DNS_RESOLVERS", "")),
+		},
+		CTLog: CTLogConfig{
+			Enabled:       getEnv("CTLOG_ENABLED", "false") == "true",
+			StreamURL:     getEnv("CTLOG_STREAM_URL", ""),
+			FlushInterval: ctlogFlushInterval,
+			MaxPending:    ctlogMaxPending,
+		},
+		Retry: DiscoveryRetryConfig{
+			MaxAttempts: retryMaxAttempts,
+			Backoff:     retryBackoff,
+			MaxBackoff:  retryMaxBackoff,
 		},
 		Timeouts: TimeoutConfig{
+			Scan:           scanTimeout,
 			ProgramProcess: programProcessTimeout,
 			ChaosDiscovery: chaosDiscoveryTimeout,
+
+			ProgramFactor:    programTimeoutFactor,
+			ProgramMax:       programTimeoutMax,
+			ProgramOverrides: programTimeouts,
 		},
 	}
+	config.Discovery.Timeouts.applyDefaults()
 
-	return config, nil
-}
-
-// loadFromConfigFile loads configuration from YAML config file
-func loadFromConfigFile() (*Config, error) {
-	configPath := getConfigPath()
-
-	data, err := os.ReadFile(configPath)
+	// Sync configuration
+	syncBatchSize, err := strconv.Atoi(getEnv("SYNC_BATCH_SIZE", "500"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+		return nil, fmt.Errorf("invalid SYNC_BATCH_SIZE: %w", err)
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	config.Sync = SyncConfig{
+		ServerURL:  getEnv("SYNC_SERVER_URL", ""),
+		Token:      getEnv("SYNC_TOKEN", ""),
+		AgentID:    getEnv("SYNC_AGENT_ID", ""),
+		BatchSize:  syncBatchSize,
+		ListenAddr: getEnv("SYNC_LISTEN_ADDR", ":8080"),
 	}
 
-	return &config, nil
-}
+	// gRPC API configuration
+	config.GRPC = GRPCConfig{
+		ListenAddr: getEnv("GRPC_LISTEN_ADDR", ":9090"),
+		Token:      getEnv("GRPC_TOKEN", ""),
+	}
 
-// getConfigPath returns the path to the config file
-func getConfigPath() string {
-	// Check for config file in current directory first
-	if _, err := os.Stat("configs/config.yaml"); err == nil {
-		return "configs/config.yaml"
+	// REST API configuration
+	config.HTTPAPI = HTTPAPIConfig{
+		ListenAddr: getEnv("API_LISTEN_ADDR", ":8090"),
+		Token:      getEnv("API_TOKEN", ""),
 	}
 
-	// Check for config file in parent directory
-	if _, err := os.Stat("../configs/config.yaml"); err == nil {
-		return "../configs/config.yaml"
+	// Platform maintenance configuration
+	maintenanceRetryDelay, err := time.ParseDuration(getEnv("MAINTENANCE_RETRY_DELAY", "10m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_RETRY_DELAY: %w", err)
 	}
 
-	// Default to current directory
-	return "configs/config.yaml"
-}
+	maintenanceMaxRetries, err := strconv.Atoi(getEnv("MAINTENANCE_MAX_RETRIES", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_MAX_RETRIES: %w", err)
+	}
 
-// loadSensitiveFromEnv loads sensitive configuration values from environment variables
-func loadSensitiveFromEnv(config *Config) {
-	// Database password
-	if password := os.Getenv("DB_PASSWORD"); password != "" {
-		config.Database.Password = password
+	maintenanceMaxWait, err := time.ParseDuration(getEnv("MAINTENANCE_MAX_WAIT", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAINTENANCE_MAX_WAIT: %w", err)
 	}
 
-	// API keys
-	if apiKey := os.Getenv("HACKERONE_API_KEY"); apiKey != "" {
-		config.APIs.HackerOne.APIKey = apiKey
+	config.Maintenance = MaintenanceConfig{
+		RetryDelay: maintenanceRetryDelay,
+		MaxRetries: maintenanceMaxRetries,
+		MaxWait:    maintenanceMaxWait,
 	}
-	if username := os.Getenv("HACKERONE_USERNAME"); username != "" {
-		config.APIs.HackerOne.Username = username
+
+	// Asset quota configuration
+	quotaMaxDropPercent, err := strconv.ParseFloat(getEnv("QUOTA_MAX_DROP_PERCENT", "30"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_MAX_DROP_PERCENT: %w", err)
 	}
-	if apiKey := os.Getenv("BUGCROWD_API_KEY"); apiKey != "" {
-		config.APIs.BugCrowd.APIKey = apiKey
+
+	quotaMaxGrowth, err := strconv.Atoi(getEnv("QUOTA_MAX_GROWTH", "500"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_MAX_GROWTH: %w", err)
 	}
-	if apiKey := os.Getenv("CHAOSDB_API_KEY"); apiKey != "" {
-		config.APIs.ChaosDB.APIKey = apiKey
+
+	quotaMinAssets, err := strconv.Atoi(getEnv("QUOTA_MIN_ASSETS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_MIN_ASSETS: %w", err)
 	}
-}
 
-// Validate validates the configuration
-func (c *Config) Validate() error {
-	var errors []string
+	config.Quota = QuotaConfig{
+		MaxDropPercent: quotaMaxDropPercent,
+		MaxGrowth:      quotaMaxGrowth,
+		MinAssets:      quotaMinAssets,
+	}
+
+	// Event configuration
+	config.Events = EventsConfig{
+		Source:        getEnv("EVENTS_SOURCE", "monitor-agent"),
+		WebhookURL:    getEnv("EVENTS_WEBHOOK_URL", ""),
+		WebhookSecret: getEnv("EVENTS_WEBHOOK_SECRET", ""),
+		KafkaBrokers:  splitList(getEnv("EVENTS_KAFKA_BROKERS", "")),
+		KafkaTopic:    getEnv("EVENTS_KAFKA_TOPIC", "monitor-agent.events"),
+		NATSURL:       getEnv("EVENTS_NATS_URL", ""),
+		NATSSubject:   getEnv("EVENTS_NATS_SUBJECT", "monitor-agent.events"),
+		RoutesFile:    getEnv("EVENTS_ROUTES_FILE", ""),
+
+		DigestAttachment:    strings.ToLower(getEnv("EVENTS_DIGEST_ATTACHMENT", "")),
+		DigestAttachmentDir: getEnv("EVENTS_DIGEST_ATTACHMENT_DIR", ""),
+		DigestAttachmentURL: getEnv("EVENTS_DIGEST_ATTACHMENT_URL", ""),
+	}
+
+	// Notification configuration
+	notifySlackEnabled, err := strconv.ParseBool(getEnv("NOTIFY_SLACK_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_SLACK_ENABLED: %w", err)
+	}
+
+	notifyDiscordEnabled, err := strconv.ParseBool(getEnv("NOTIFY_DISCORD_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_DISCORD_ENABLED: %w", err)
+	}
+
+	notifyWebhookEnabled, err := strconv.ParseBool(getEnv("NOTIFY_WEBHOOK_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_WEBHOOK_ENABLED: %w", err)
+	}
+
+	notifyBatchSize, err := strconv.Atoi(getEnv("NOTIFY_BATCH_SIZE", "25"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_BATCH_SIZE: %w", err)
+	}
+
+	notifyBatchInterval, err := time.ParseDuration(getEnv("NOTIFY_BATCH_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_BATCH_INTERVAL: %w", err)
+	}
+
+	notifyRetryAttempts, err := strconv.Atoi(getEnv("NOTIFY_RETRY_ATTEMPTS", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_RETRY_ATTEMPTS: %w", err)
+	}
+
+	notifyRetryDelay, err := time.ParseDuration(getEnv("NOTIFY_RETRY_DELAY", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_RETRY_DELAY: %w", err)
+	}
+
+	config.Notify = NotifyConfig{
+		SlackEnabled:      notifySlackEnabled,
+		SlackWebhookURL:   getEnv("NOTIFY_SLACK_WEBHOOK_URL", ""),
+		DiscordEnabled:    notifyDiscordEnabled,
+		DiscordWebhookURL: getEnv("NOTIFY_DISCORD_WEBHOOK_URL", ""),
+		WebhookEnabled:    notifyWebhookEnabled,
+		WebhookURL:        getEnv("NOTIFY_WEBHOOK_URL", ""),
+		WebhookSecret:     getEnv("NOTIFY_WEBHOOK_SECRET", ""),
+		BatchSize:         notifyBatchSize,
+		BatchInterval:     notifyBatchInterval,
+		RetryAttempts:     notifyRetryAttempts,
+		RetryDelay:        notifyRetryDelay,
+	}
+
+	// Triage rules configuration
+	config.Rules = RulesConfig{
+		File: getEnv("RULES_FILE", ""),
+	}
+
+	// Asset scoring configuration
+	config.Scoring = ScoringConfig{
+		File: getEnv("SCORING_FILE", ""),
+	}
+
+	// Response clustering configuration
+	clusterMaxDistance, err := strconv.Atoi(getEnv("CLUSTER_MAX_DISTANCE", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CLUSTER_MAX_DISTANCE: %w", err)
+	}
+
+	config.Clustering = ClusteringConfig{
+		MaxDistance: clusterMaxDistance,
+	}
+
+	// Program filter configuration
+	config.Programs = ProgramFilterConfig{
+		Include: splitList(getEnv("PROGRAM_INCLUDE", "")),
+		Exclude: splitList(getEnv("PROGRAM_EXCLUDE", "")),
+	}
+
+	// Content change configuration
+	contentMinDistance, err := strconv.Atoi(getEnv("CONTENT_CHANGE_MIN_DISTANCE", "4"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CONTENT_CHANGE_MIN_DISTANCE: %w", err)
+	}
+
+	config.Content = ContentChangeConfig{
+		MinDistance: contentMinDistance,
+	}
+
+	// Response retention configuration
+	responsesPerAsset, err := strconv.Atoi(getEnv("RESPONSE_RETENTION_PER_ASSET", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_RETENTION_PER_ASSET: %w", err)
+	}
+
+	responseMaxAge, err := time.ParseDuration(getEnv("RESPONSE_RETENTION_MAX_AGE", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_RETENTION_MAX_AGE: %w", err)
+	}
+
+	config.Retention = RetentionConfig{
+		ResponsesPerAsset: responsesPerAsset,
+		ResponseMaxAge:    responseMaxAge,
+	}
+
+	// Freshness SLO configuration
+	sloProgramScanWithin, err := time.ParseDuration(getEnv("SLO_PROGRAM_SCAN_WITHIN", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SLO_PROGRAM_SCAN_WITHIN: %w", err)
+	}
+
+	sloAssetProbeWithin, err := time.ParseDuration(getEnv("SLO_ASSET_PROBE_WITHIN", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SLO_ASSET_PROBE_WITHIN: %w", err)
+	}
+
+	config.SLO = SLOConfig{
+		ProgramScanWithin: sloProgramScanWithin,
+		AssetProbeWithin:  sloAssetProbeWithin,
+	}
+
+	// Search mirror configuration
+	bodyExcerptBytes, err := strconv.Atoi(getEnv("SEARCH_BODY_EXCERPT_BYTES", "4096"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEARCH_BODY_EXCERPT_BYTES: %w", err)
+	}
+
+	config.Search = SearchConfig{
+		URL:              getEnv("SEARCH_URL", ""),
+		Index:            getEnv("SEARCH_INDEX", "monitor-agent-responses"),
+		Username:         getEnv("SEARCH_USERNAME", ""),
+		Password:         getEnv("SEARCH_PASSWORD", ""),
+		BodyExcerptBytes: bodyExcerptBytes,
+	}
+
+	// Registration data enrichment configuration
+	whoisNewDomainDays, err := strconv.Atoi(getEnv("WHOIS_NEW_DOMAIN_DAYS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WHOIS_NEW_DOMAIN_DAYS: %w", err)
+	}
+
+	whoisRefreshInterval, err := time.ParseDuration(getEnv("WHOIS_REFRESH_INTERVAL", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WHOIS_REFRESH_INTERVAL: %w", err)
+	}
+
+	config.Whois = WhoisConfig{
+		Enabled:         getEnv("WHOIS_ENABLED", "false") == "true",
+		IPLookups:       getEnv("WHOIS_IP_LOOKUPS", "false") == "true",
+		ServerURL:       getEnv("WHOIS_RDAP_URL", "https://rdap.org"),
+		NewDomainDays:   whoisNewDomainDays,
+		RefreshInterval: whoisRefreshInterval,
+	}
+
+	// Daemon configuration
+	sweepRequestsPerHour, err := strconv.Atoi(getEnv("DAEMON_SWEEP_REQUESTS_PER_HOUR", "600"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DAEMON_SWEEP_REQUESTS_PER_HOUR: %w", err)
+	}
+
+	sweepBatchSize, err := strconv.Atoi(getEnv("DAEMON_SWEEP_BATCH_SIZE", "25"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DAEMON_SWEEP_BATCH_SIZE: %w", err)
+	}
+
+	watchlistInterval, err := time.ParseDuration(getEnv("DAEMON_WATCHLIST_INTERVAL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DAEMON_WATCHLIST_INTERVAL: %w", err)
+	}
+
+	config.Daemon = DaemonConfig{
+		SweepRequestsPerHour: sweepRequestsPerHour,
+		SweepBatchSize:       sweepBatchSize,
+		SweepMethod:          strings.ToUpper(getEnv("DAEMON_SWEEP_METHOD", "GET")),
+		WatchlistInterval:    watchlistInterval,
+		ScanSchedule:         strings.TrimSpace(getEnv("SCAN_SCHEDULE", "")),
+	}
+
+	// Slack bot configuration
+	config.Slack = SlackConfig{
+		AppToken:        getEnv("SLACK_APP_TOKEN", ""),
+		Command:         getEnv("SLACK_COMMAND", "/monitor"),
+		AllowedUsers:    splitList(getEnv("SLACK_ALLOWED_USERS", "")),
+		AllowedChannels: splitList(getEnv("SLACK_ALLOWED_CHANNELS", "")),
+	}
+
+	// DefectDojo export configuration
+	config.DefectDojo = DefectDojoConfig{
+		URL:         getEnv("DEFECTDOJO_URL", ""),
+		APIKey:      getEnv("DEFECTDOJO_API_KEY", ""),
+		ProductType: getEnv("DEFECTDOJO_PRODUCT_TYPE", "Bug Bounty"),
+	}
+
+	// CMDB inventory configuration
+	config.CMDB = CMDBConfig{
+		CSV:                getEnv("CMDB_CSV", ""),
+		ServiceNowURL:      getEnv("CMDB_SERVICENOW_URL", ""),
+		ServiceNowUser:     getEnv("CMDB_SERVICENOW_USER", ""),
+		ServiceNowPassword: getEnv("CMDB_SERVICENOW_PASSWORD", ""),
+		ServiceNowTable:    getEnv("CMDB_SERVICENOW_TABLE", "cmdb_ci_server"),
+	}
+
+	// Notes vault configuration
+	config.Notes = NotesConfig{
+		VaultDir: getEnv("NOTES_VAULT_DIR", ""),
+	}
+
+	// Probe auth configuration
+	config.ProbeAuth = ProbeAuthConfig{
+		Key: getEnv("PROBE_AUTH_KEY", ""),
+	}
+
+	// Scope quarantine configuration
+	quarantineGrace, err := time.ParseDuration(getEnv("SCOPE_QUARANTINE_GRACE", "72h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCOPE_QUARANTINE_GRACE: %w", err)
+	}
+
+	config.Quarantine = QuarantineConfig{
+		Mode:  getEnv("SCOPE_QUARANTINE_MODE", QuarantineOff),
+		Grace: quarantineGrace,
+	}
+
+	// Status page configuration
+	config.StatusPage = StatusPageConfig{
+		Dir:   getEnv("STATUS_PAGE_DIR", ""),
+		Title: getEnv("STATUS_PAGE_TITLE", "Monitor Agent Status"),
+	}
+
+	// Scan report configuration
+	config.ScanReport = ScanReportConfig{
+		Dir:           getEnv("SCAN_REPORT_DIR", ""),
+		Format:        getEnv("SCAN_REPORT_FORMAT", ScanReportMarkdown),
+		WebhookURL:    getEnv("SCAN_REPORT_WEBHOOK_URL", ""),
+		WebhookSecret: getEnv("SCAN_REPORT_WEBHOOK_SECRET", ""),
+	}
+
+	// Remote probe worker configuration
+	probeWorkers, err := parseVantageWorkers(getEnv("PROBE_WORKERS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	config.Vantage = VantageConfig{
+		Region:     getEnv("PROBE_REGION", "local"),
+		Workers:    probeWorkers,
+		Token:      getEnv("PROBE_WORKER_TOKEN", ""),
+		ListenAddr: getEnv("PROBE_WORKER_LISTEN_ADDR", ":8081"),
+	}
+
+	// Canary configuration
+	canaryTargets, err := parseCanaryTargets(getEnv("CANARY_TARGETS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	config.Canary = CanaryConfig{
+		Targets:   canaryTargets,
+		OnFailure: getEnv("CANARY_ON_FAILURE", "abort"),
+	}
+
+	// Provenance configuration
+	sourceTerms, err := parseSourceTerms(getEnv("DATA_SOURCE_TERMS", DefaultDataSourceTerms))
+	if err != nil {
+		return nil, err
+	}
+
+	config.Provenance = ProvenanceConfig{
+		SourceTerms:    sourceTerms,
+		ExcludeSources: splitList(getEnv("EXPORT_EXCLUDE_SOURCES", "")),
+	}
+
+	// Object store configuration, falling back to the standard AWS credential variables
+	config.ObjectStore = ObjectStoreConfig{
+		Bucket:          getEnv("S3_BUCKET", ""),
+		Region:          getEnv("S3_REGION", "us-east-1"),
+		Endpoint:        getEnv("S3_ENDPOINT", ""),
+		PathStyle:       getEnv("S3_FORCE_PATH_STYLE", "false") == "true",
+		AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", os.Getenv("AWS_ACCESS_KEY_ID")),
+		SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		SessionToken:    getEnv("S3_SESSION_TOKEN", os.Getenv("AWS_SESSION_TOKEN")),
+		Prefix:          getEnv("S3_PREFIX", "monitor-agent/"),
+	}
+
+	// Response body store configuration
+	bodyStoreMinBytes, err := strconv.Atoi(getEnv("BODY_STORE_MIN_BYTES", "4096"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BODY_STORE_MIN_BYTES: %w", err)
+	}
+
+	config.BodyStore = BodyStoreConfig{
+		Backend:            getEnv("BODY_STORE_BACKEND", "s3"),
+		Bucket:             getEnv("BODY_STORE_BUCKET", ""),
+		Prefix:             getEnv("BODY_STORE_PREFIX", "monitor-agent/bodies/"),
+		MinBytes:           bodyStoreMinBytes,
+		GCSCredentialsFile: getEnv("GCS_CREDENTIALS_FILE", os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")),
+		GCSEndpoint:        getEnv("GCS_ENDPOINT", ""),
+	}
+
+	// Metrics configuration
+	config.Metrics = MetricsConfig{
+		Enabled:    getEnv("METRICS_ENABLED", "false") == "true",
+		ListenAddr: getEnv("METRICS_LISTEN_ADDR", ":9091"),
+		Path:       getEnv("METRICS_PATH", "/metrics"),
+	}
+
+	return config, nil
+}
+
+// parseVantageWorkers parses PROBE_WORKERS entries of the form region=url
+func parseVantageWorkers(value string) ([]VantageWorker, error) {
+	var workers []VantageWorker
+	for _, entry := range splitList(value) {
+		region, url, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid PROBE_WORKERS entry %q: expected region=url", entry)
+		}
+		workers = append(workers, VantageWorker{
+			Region: strings.TrimSpace(region),
+			URL:    strings.TrimSpace(url),
+		})
+	}
+	return workers, nil
+}
+
+// parseCanaryTargets parses CANARY_TARGETS entries of the form hostname or
+// hostname=status
+func parseCanaryTargets(value string) ([]CanaryTarget, error) {
+	var targets []CanaryTarget
+	for _, entry := range splitList(value) {
+		hostname, status, hasStatus := strings.Cut(entry, "=")
+		target := CanaryTarget{Hostname: strings.TrimSpace(hostname)}
+		if hasStatus {
+			statusCode, err := strconv.Atoi(strings.TrimSpace(status))
+			if err != nil || statusCode < 100 || statusCode > 599 {
+				return nil, fmt.Errorf("invalid CANARY_TARGETS entry %q: expected hostname or hostname=status", entry)
+			}
+			target.StatusCode = statusCode
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// parseProgramTimeouts parses PROGRAM_TIMEOUTS entries of the form
+// handle=duration or platform/handle=duration; nil when there are none
+func parseProgramTimeouts(value string) (map[string]time.Duration, error) {
+	var timeouts map[string]time.Duration
+	for _, entry := range splitList(value) {
+		handle, duration, ok := strings.Cut(entry, "=")
+		handle = strings.ToLower(strings.TrimSpace(handle))
+		timeout, err := time.ParseDuration(strings.TrimSpace(duration))
+		if !ok || handle == "" || err != nil {
+			return nil, fmt.Errorf("invalid PROGRAM_TIMEOUTS entry %q: expected handle=duration", entry)
+		}
+		if timeouts == nil {
+			timeouts = make(map[string]time.Duration)
+		}
+		timeouts[handle] = timeout
+	}
+	return timeouts, nil
+}
+
+// parseSourceTerms parses DATA_SOURCE_TERMS entries of the form source=terms
+func parseSourceTerms(value string) (map[string]string, error) {
+	terms := make(map[string]string)
+	for _, entry := range splitList(value) {
+		source, sourceTerms, ok := strings.Cut(entry, "=")
+		source, sourceTerms = strings.TrimSpace(source), strings.TrimSpace(sourceTerms)
+		if !ok || source == "" || sourceTerms == "" {
+			return nil, fmt.Errorf("invalid DATA_SOURCE_TERMS entry %q: expected source=terms", entry)
+		}
+		terms[source] = sourceTerms
+	}
+	return terms, nil
+}
+
+// parsePlatformCredentials parses credential entries of the form name=username:apikey
+// (withUsername) or name=apikey
+func parsePlatformCredentials(key, value string, withUsername bool) ([]PlatformCredential, error) {
+	var credentials []PlatformCredential
+	for _, entry := range splitList(value) {
+		name, secret, ok := strings.Cut(entry, "=")
+		if !ok {
+			if withUsername {
+				return nil, fmt.Errorf("invalid %s entry: expected name=username:apikey", key)
+			}
+			return nil, fmt.Errorf("invalid %s entry: expected name=apikey", key)
+		}
+
+		credential := PlatformCredential{Name: strings.TrimSpace(name), APIKey: strings.TrimSpace(secret)}
+		if withUsername {
+			username, apiKey, ok := strings.Cut(secret, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry for %s: expected name=username:apikey", key, credential.Name)
+			}
+			credential.Username = strings.TrimSpace(username)
+			credential.APIKey = strings.TrimSpace(apiKey)
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+// splitList splits a comma-separated environment value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseOptionalDuration parses a duration environment variable, returning 0 when it is unset
+func parseOptionalDuration(key string) (time.Duration, error) {
+	value := getEnv(key, "")
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return duration, nil
+}
+
+// applyDefaults fills in timeouts that were not configured, deriving outer
+// timeouts from inner ones so the hierarchy always holds
+func (t *TimeoutConfig) applyDefaults() {
+	if t.ChaosDiscovery == 0 {
+		t.ChaosDiscovery = DefaultChaosDiscoveryTimeout
+	}
+	if t.ProgramProcess == 0 {
+		t.ProgramProcess = t.ChaosDiscovery + ProgramTimeoutBuffer
+	}
+	if t.ProgramMax == 0 {
+		t.ProgramMax = 4 * t.ProgramProcess
+		if t.Scan > 0 {
+			t.ProgramMax = max(min(t.ProgramMax, t.Scan), t.ProgramProcess)
+		}
+	}
+}
+
+// loadFromConfigFile loads configuration from YAML config file
+func loadFromConfigFile(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return &config, nil
+}
+
+// getConfigPath returns the path to the config file
+func getConfigPath() string {
+	// Check for config file in current directory first
+	if _, err := os.Stat("configs/config.yaml"); err == nil {
+		return "configs/config.yaml"
+	}
+
+	// Check for config file in parent directory
+	if _, err := os.Stat("../configs/config.yaml"); err == nil {
+		return "../configs/config.yaml"
+	}
+
+	// Default to current directory
+	return "configs/config.yaml"
+}
+
+// loadSensitiveFromEnv loads sensitive configuration values from environment variables
+func loadSensitiveFromEnv(config *Config) {
+	// Database password
+	if password := os.Getenv("DB_PASSWORD"); password != "" {
+		config.Database.Password = password
+	}
+
+	// API keys
+	if apiKey := os.Getenv("HACKERONE_API_KEY"); apiKey != "" {
+		config.APIs.HackerOne.APIKey = apiKey
+	}
+	if username := os.Getenv("HACKERONE_USERNAME"); username != "" {
+		config.APIs.HackerOne.Username = username
+	}
+	if apiKey := os.Getenv("BUGCROWD_API_KEY"); apiKey != "" {
+		config.APIs.BugCrowd.APIKey = apiKey
+	}
+	if apiKey := os.Getenv("INTIGRITI_API_KEY"); apiKey != "" {
+		config.APIs.Intigriti.APIKey = apiKey
+	}
+	if apiKey := os.Getenv("CHAOSDB_API_KEY"); apiKey != "" {
+		config.APIs.ChaosDB.APIKey = apiKey
+	}
+
+	// Sync token
+	if token := os.Getenv("SYNC_TOKEN"); token != "" {
+		config.Sync.Token = token
+	}
+
+	// gRPC API token
+	if token := os.Getenv("GRPC_TOKEN"); token != "" {
+		config.GRPC.Token = token
+	}
+
+	// REST API token
+	if token := os.Getenv("API_TOKEN"); token != "" {
+		config.HTTPAPI.Token = token
+	}
+
+	// Search mirror password
+	if password := os.Getenv("SEARCH_PASSWORD"); password != "" {
+		config.Search.Password = password
+	}
+
+	// Probe worker token
+	if token := os.Getenv("PROBE_WORKER_TOKEN"); token != "" {
+		config.Vantage.Token = token
+	}
+
+	// DefectDojo API key
+	if apiKey := os.Getenv("DEFECTDOJO_API_KEY"); apiKey != "" {
+		config.DefectDojo.APIKey = apiKey
+	}
+
+	// ServiceNow password
+	if password := os.Getenv("CMDB_SERVICENOW_PASSWORD"); password != "" {
+		config.CMDB.ServiceNowPassword = password
+	}
+
+	// Event webhook secret
+	if secret := os.Getenv("EVENTS_WEBHOOK_SECRET"); secret != "" {
+		config.Events.WebhookSecret = secret
+	}
+
+	// Notification webhooks; chat webhook URLs carry their own credentials
+	if webhookURL := os.Getenv("NOTIFY_SLACK_WEBHOOK_URL"); webhookURL != "" {
+		config.Notify.SlackWebhookURL = webhookURL
+	}
+	if webhookURL := os.Getenv("NOTIFY_DISCORD_WEBHOOK_URL"); webhookURL != "" {
+		config.Notify.DiscordWebhookURL = webhookURL
+	}
+	if secret := os.Getenv("NOTIFY_WEBHOOK_SECRET"); secret != "" {
+		config.Notify.WebhookSecret = secret
+	}
+
+	// Scan report webhook secret
+	if secret := os.Getenv("SCAN_REPORT_WEBHOOK_SECRET"); secret != "" {
+		config.ScanReport.WebhookSecret = secret
+	}
+
+	// Object store secret access key
+	if secret := os.Getenv("S3_SECRET_ACCESS_KEY"); secret != "" {
+		config.ObjectStore.SecretAccessKey = secret
+	}
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	var errors []string
 
 	// Database validation
 	if err := c.validateDatabase(); err != nil {
 		errors = append(errors, fmt.Sprintf("database: %v", err))
 	}
 
-	// API validation
-	if err := c.validateAPIs(); err != nil {
-		errors = append(errors, fmt.Sprintf("APIs: %v", err))
+	// API validation
+	if err := c.validateAPIs(); err != nil {
+		errors = append(errors, fmt.Sprintf("APIs: %v", err))
+	}
+
+	// Application validation
+	if err := c.validateApp(); err != nil {
+		errors = append(errors, fmt.Sprintf("application: %v", err))
+	}
+
+	// HTTP validation
+	if err := c.validateHTTP(); err != nil {
+		errors = append(errors, fmt.Sprintf("HTTP: %v", err))
+	}
+
+	// Discovery validation
+	if err := c.validateDiscovery(); err != nil {
+		errors = append(errors, fmt.Sprintf("discovery: %v", err))
+	}
+
+	// Timeout hierarchy validation
+	if err := c.validateTimeouts(); err != nil {
+		errors = append(errors, fmt.Sprintf("timeouts: %v", err))
+	}
+
+	// Sync validation
+	if err := c.validateSync(); err != nil {
+		errors = append(errors, fmt.Sprintf("sync: %v", err))
+	}
+
+	// Maintenance validation
+	if err := c.validateMaintenance(); err != nil {
+		errors = append(errors, fmt.Sprintf("maintenance: %v", err))
+	}
+
+	// Quota validation
+	if err := c.validateQuota(); err != nil {
+		errors = append(errors, fmt.Sprintf("quota: %v", err))
+	}
+
+	// Events validation
+	if err := c.validateEvents(); err != nil {
+		errors = append(errors, fmt.Sprintf("events: %v", err))
+	}
+
+	// Notification validation
+	if err := c.validateNotify(); err != nil {
+		errors = append(errors, fmt.Sprintf("notify: %v", err))
+	}
+
+	// Rules validation
+	if err := c.validateRules(); err != nil {
+		errors = append(errors, fmt.Sprintf("rules: %v", err))
+	}
+
+	// Scoring validation
+	if err := c.validateScoring(); err != nil {
+		errors = append(errors, fmt.Sprintf("scoring: %v", err))
+	}
+
+	// Clustering validation
+	if c.Clustering.MaxDistance < 0 || c.Clustering.MaxDistance > 15 {
+		errors = append(errors, "clustering: CLUSTER_MAX_DISTANCE must be between 0 and 15")
+	}
+
+	// Program filter validation
+	if err := validateProgramPatterns("PROGRAM_INCLUDE", c.Programs.Include); err != nil {
+		errors = append(errors, fmt.Sprintf("programs: %v", err))
+	}
+	if err := validateProgramPatterns("PROGRAM_EXCLUDE", c.Programs.Exclude); err != nil {
+		errors = append(errors, fmt.Sprintf("programs: %v", err))
+	}
+
+	// Content change validation
+	if c.Content.MinDistance < 0 || c.Content.MinDistance > 64 {
+		errors = append(errors, "content: CONTENT_CHANGE_MIN_DISTANCE must be between 0 and 64")
+	}
+
+	// Retention validation
+	if c.Retention.ResponsesPerAsset < 0 || c.Retention.ResponseMaxAge < 0 {
+		errors = append(errors, "retention: RESPONSE_RETENTION_PER_ASSET and RESPONSE_RETENTION_MAX_AGE must not be negative")
+	}
+
+	// Freshness SLO validation
+	if c.SLO.ProgramScanWithin < 0 || c.SLO.AssetProbeWithin < 0 {
+		errors = append(errors, "slo: SLO_PROGRAM_SCAN_WITHIN and SLO_ASSET_PROBE_WITHIN must not be negative")
+	}
+
+	// Search validation
+	if err := c.validateSearch(); err != nil {
+		errors = append(errors, fmt.Sprintf("search: %v", err))
+	}
+
+	// Registration data validation
+	if err := c.validateWhois(); err != nil {
+		errors = append(errors, fmt.Sprintf("whois: %v", err))
+	}
+
+	// Daemon validation
+	if err := c.validateDaemon(); err != nil {
+		errors = append(errors, fmt.Sprintf("daemon: %v", err))
+	}
+
+	// Slack validation
+	if err := c.validateSlack(); err != nil {
+		errors = append(errors, fmt.Sprintf("slack: %v", err))
+	}
+
+	// DefectDojo validation
+	if err := c.validateDefectDojo(); err != nil {
+		errors = append(errors, fmt.Sprintf("defectdojo: %v", err))
+	}
+
+	// CMDB validation
+	if err := c.validateCMDB(); err != nil {
+		errors = append(errors, fmt.Sprintf("cmdb: %v", err))
+	}
+
+	// Notes validation
+	if err := c.validateNotes(); err != nil {
+		errors = append(errors, fmt.Sprintf("notes: %v", err))
+	}
+
+	// Probe auth validation
+	if err := c.validateProbeAuth(); err != nil {
+		errors = append(errors, fmt.Sprintf("probe auth: %v", err))
+	}
+
+	// Quarantine validation
+	if err := c.validateQuarantine(); err != nil {
+		errors = append(errors, fmt.Sprintf("quarantine: %v", err))
+	}
+
+	// Status page validation
+	if err := c.validateStatusPage(); err != nil {
+		errors = append(errors, fmt.Sprintf("status page: %v", err))
+	}
+
+	// Scan report validation
+	if err := c.validateScanReport(); err != nil {
+		errors = append(errors, fmt.Sprintf("scan report: %v", err))
+	}
+
+	// Vantage validation
+	if err := c.validateVantage(); err != nil {
+		errors = append(errors, fmt.Sprintf("vantage: %v", err))
+	}
+
+	// Canary validation
+	if err := c.validateCanary(); err != nil {
+		errors = append(errors, fmt.Sprintf("canary: %v", err))
+	}
+
+	// Object store validation
+	if err := c.validateObjectStore(); err != nil {
+		errors = append(errors, fmt.Sprintf("object store: %v", err))
+	}
+
+	// Body store validation
+	if err := c.validateBodyStore(); err != nil {
+		errors = append(errors, fmt.Sprintf("body store: %v", err))
+	}
+
+	// Metrics validation
+	if err := c.validateMetrics(); err != nil {
+		errors = append(errors, fmt.Sprintf("metrics: %v", err))
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("configuration validation failed: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+// validateDatabase validates database configuration
+func (c *Config) validateDatabase() error {
+	if c.Database.Password == "" {
+		return fmt.Errorf("DB_PASSWORD is required")
+	}
+
+	if c.Database.Host == "" {
+		return fmt.Errorf("DB_HOST is required")
+	}
+
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		return fmt.Errorf("DB_PORT must be between 1 and 65535")
+	}
+
+	if c.Database.Name == "" {
+		return fmt.Errorf("DB_NAME is required")
+	}
+
+	if c.Database.User == "" {
+		return fmt.Errorf("DB_USER is required")
+	}
+
+	// Validate SSL mode
+	validSSLModes := []string{"disable", "require", "verify-ca", "verify-full"}
+	validSSLMode := false
+	for _, mode := range validSSLModes {
+		if c.Database.SSLMode == mode {
+			validSSLMode = true
+			break
+		}
+	}
+	if !validSSLMode {
+		return fmt.Errorf("DB_SSL_MODE must be one of: %s", strings.Join(validSSLModes, ", "))
+	}
+
+	if c.Database.WriteBatchSize < 0 {
+		return fmt.Errorf("DB_WRITE_BATCH_SIZE must not be negative")
+	}
+	if c.Database.WritesPerSecond < 0 {
+		return fmt.Errorf("DB_WRITES_PER_SECOND must not be negative")
+	}
+	if c.Database.MigrationLockTimeout < 0 {
+		return fmt.Errorf("MIGRATIONS_LOCK_TIMEOUT must not be negative")
+	}
+
+	// Validate SSL certificates if using verify-full
+	if c.Database.SSLMode == "verify-full" {
+		if c.Database.SSLCert == "" {
+			return fmt.Errorf("DB_SSL_CERT is required when DB_SSL_MODE is verify-full")
+		}
+		if c.Database.SSLKey == "" {
+			return fmt.Errorf("DB_SSL_KEY is required when DB_SSL_MODE is verify-full")
+		}
+		if c.Database.SSLRootCert == "" {
+			return fmt.Errorf("DB_SSL_ROOT_CERT is required when DB_SSL_MODE is verify-full")
+		}
+
+		// Check if SSL certificate files exist
+		if _, err := os.Stat(c.Database.SSLCert); os.IsNotExist(err) {
+			return fmt.Errorf("SSL certificate file does not exist: %s", c.Database.SSLCert)
+		}
+		if _, err := os.Stat(c.Database.SSLKey); os.IsNotExist(err) {
+			return fmt.Errorf("SSL key file does not exist: %s", c.Database.SSLKey)
+		}
+		if _, err := os.Stat(c.Database.SSLRootCert); os.IsNotExist(err) {
+			return fmt.Errorf("SSL root certificate file does not exist: %s", c.Database.SSLRootCert)
+		}
+	}
+
+	// Validate connection pool settings
+	if c.Database.MaxOpenConns <= 0 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS must be greater than 0")
+	}
+	if c.Database.MaxIdleConns <= 0 {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS must be greater than 0")
+	}
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS cannot be greater than DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.ConnMaxLifetime <= 0 {
+		return fmt.Errorf("DB_CONN_MAX_LIFETIME must be greater than 0")
+	}
+	if c.Database.ConnectTimeout <= 0 {
+		return fmt.Errorf("DB_CONNECT_TIMEOUT must be greater than 0")
+	}
+
+	return nil
+}
+
+// validateAPIs validates API configuration
+func (c *Config) validateAPIs() error {
+	// Validate HackerOne configuration (only if API key is provided)
+	if c.APIs.HackerOne.APIKey != "" || len(c.APIs.HackerOne.Credentials) > 0 {
+		if c.APIs.HackerOne.RateLimit <= 0 || c.APIs.HackerOne.RateLimit > 600 {
+			return fmt.Errorf("HACKERONE_RATE_LIMIT must be between 1 and 600")
+		}
+	}
+	if err := validatePlatformCredentials("HACKERONE_CREDENTIALS", c.APIs.HackerOne.Credentials, true); err != nil {
+		return err
+	}
+
+	// Validate BugCrowd configuration (only if API key is provided)
+	if c.APIs.BugCrowd.APIKey != "" || len(c.APIs.BugCrowd.Credentials) > 0 {
+		if c.APIs.BugCrowd.RateLimit <= 0 || c.APIs.BugCrowd.RateLimit > 60 {
+			return fmt.Errorf("BUGCROWD_RATE_LIMIT must be between 1 and 60")
+		}
+	}
+	if err := validatePlatformCredentials("BUGCROWD_CREDENTIALS", c.APIs.BugCrowd.Credentials, false); err != nil {
+		return err
+	}
+
+	// Validate Intigriti configuration (only if API key is provided)
+	if c.APIs.Intigriti.APIKey != "" || len(c.APIs.Intigriti.Credentials) > 0 {
+		if c.APIs.Intigriti.RateLimit <= 0 || c.APIs.Intigriti.RateLimit > 60 {
+			return fmt.Errorf("INTIGRITI_RATE_LIMIT must be between 1 and 60")
+		}
+	}
+	if err := validatePlatformCredentials("INTIGRITI_CREDENTIALS", c.APIs.Intigriti.Credentials, false); err != nil {
+		return err
+	}
+
+	// Validate ChaosDB configuration (only if API key is provided)
+	if c.APIs.ChaosDB.APIKey != "" {
+		if c.APIs.ChaosDB.RateLimit <= 0 || c.APIs.ChaosDB.RateLimit > 60 {
+			return fmt.Errorf("CHAOSDB_RATE_LIMIT must be between 1 and 60")
+		}
+	}
+
+	for key, burst := range map[string]int{
+		"HACKERONE_RATE_BURST": c.APIs.HackerOne.RateBurst,
+		"BUGCROWD_RATE_BURST":  c.APIs.BugCrowd.RateBurst,
+		"INTIGRITI_RATE_BURST": c.APIs.Intigriti.RateBurst,
+		"CHAOSDB_RATE_BURST":   c.APIs.ChaosDB.RateBurst,
+	} {
+		if burst < 0 {
+			return fmt.Errorf("%s must not be negative", key)
+		}
+	}
+
+	// Base URL overrides are only used for local development and testing
+	for key, value := range map[string]string{
+		"HACKERONE_BASE_URL":        c.APIs.HackerOne.BaseURL,
+		"BUGCROWD_BASE_URL":         c.APIs.BugCrowd.BaseURL,
+		"INTIGRITI_BASE_URL":        c.APIs.Intigriti.BaseURL,
+		"CHAOSDB_BASE_URL":          c.APIs.ChaosDB.BaseURL,
+		"CHAOSDB_DATASET_INDEX_URL": c.APIs.ChaosDB.DatasetIndexURL,
+		"CRTSH_BASE_URL":            c.APIs.CRTSh.BaseURL,
+	} {
+		if value != "" && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			return fmt.Errorf("%s must be an http or https URL", key)
+		}
+	}
+
+	return nil
+}
+
+// validateProgramPatterns checks that program filter patterns are valid globs
+func validateProgramPatterns(key string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s has an invalid pattern %q: %w", key, pattern, err)
+		}
+	}
+	return nil
+}
+
+// validatePlatformCredentials checks that additional platform credentials are complete and uniquely named
+func validatePlatformCredentials(key string, credentials []PlatformCredential, withUsername bool) error {
+	names := map[string]bool{"default": true}
+	for _, credential := range credentials {
+		if credential.Name == "" {
+			return fmt.Errorf("%s entries need a name", key)
+		}
+		if names[credential.Name] {
+			return fmt.Errorf("%s name %s is reserved or used more than once", key, credential.Name)
+		}
+		names[credential.Name] = true
+
+		if credential.APIKey == "" || (withUsername && credential.Username == "") {
+			return fmt.Errorf("%s entry %s is missing its credentials", key, credential.Name)
+		}
+	}
+	return nil
+}
+
+// validateApp validates application configuration
+func (c *Config) validateApp() error {
+	// Validate log level
+	validLogLevels := []string{"debug", "info", "warn", "error", "fatal"}
+	validLogLevel := false
+	for _, level := range validLogLevels {
+		if c.App.LogLevel == level {
+			validLogLevel = true
+			break
+		}
+	}
+	if !validLogLevel {
+		return fmt.Errorf("LOG_LEVEL must be one of: %s", strings.Join(validLogLevels, ", "))
+	}
+
+	// Validate log format
+	if c.App.LogFormat != "" && c.App.LogFormat != "text" && c.App.LogFormat != "json" {
+		return fmt.Errorf("LOG_FORMAT must be one of: text, json")
+	}
+
+	// Validate environment
+	validEnvironments := []string{"development", "staging", "production"}
+	validEnvironment := false
+	for _, env := range validEnvironments {
+		if c.App.Environment == env {
+			validEnvironment = true
+			break
+		}
+	}
+	if !validEnvironment {
+		return fmt.Errorf("ENVIRONMENT must be one of: %s", strings.Join(validEnvironments, ", "))
+	}
+
+	return nil
+}
+
+// validateHTTP validates HTTP configuration
+func (c *Config) validateHTTP() error {
+	if c.HTTP.Timeout <= 0 {
+		return fmt.Errorf("HTTP_TIMEOUT must be greater than 0")
+	}
+	if c.HTTP.RetryAttempts < 0 || c.HTTP.RetryAttempts > 10 {
+		return fmt.Errorf("HTTP_RETRY_ATTEMPTS must be between 0 and 10")
+	}
+	if c.HTTP.RetryDelay <= 0 {
+		return fmt.Errorf("HTTP_RETRY_DELAY must be greater than 0")
+	}
+
+	return nil
+}
+
+// validateDiscovery validates discovery configuration
+func (c *Config) validateDiscovery() error {
+	if c.Discovery.BulkSize <= 0 || c.Discovery.BulkSize > 1000 {
+		return fmt.Errorf("CHAOSDB_BULK_SIZE must be between 1 and 1000")
+	}
+	if c.Discovery.PipelineDepth < 0 || c.Discovery.PipelineDepth > 100 {
+		return fmt.Errorf("DISCOVERY_PIPELINE_DEPTH must be between 0 and 100")
+	}
+	if c.Discovery.ScopeChunkSize < 0 || c.Discovery.ScopeChunkSize > 10000 {
+		return fmt.Errorf("SCOPE_CHUNK_SIZE must be between 0 and 10000")
+	}
+	if c.Discovery.ProgramConcurrency < 0 || c.Discovery.ProgramConcurrency > 50 {
+		return fmt.Errorf("PROGRAM_CONCURRENCY must be between 0 and 50")
+	}
+
+	// Validate HTTPX configuration if enabled
+	if c.Discovery.HTTPX.Enabled {
+		if c.Discovery.HTTPX.Timeout <= 0 {
+			return fmt.Errorf("HTTPX_TIMEOUT must be greater than 0")
+		}
+		if c.Discovery.HTTPX.Concurrency <= 0 || c.Discovery.HTTPX.Concurrency > 100 {
+			return fmt.Errorf("HTTPX_CONCURRENCY must be between 1 and 100")
+		}
+		if c.Discovery.HTTPX.RateLimit <= 0 {
+			return fmt.Errorf("HTTPX_RATE_LIMIT must be greater than 0")
+		}
+		if c.Discovery.HTTPX.MaxRedirects < 0 || c.Discovery.HTTPX.MaxRedirects > 10 {
+			return fmt.Errorf("HTTPX_MAX_REDIRECTS must be between 0 and 10")
+		}
+		switch c.Discovery.HTTPX.IPVersion {
+		case "", "ipv4", "ipv6", "dual":
+		default:
+			return fmt.Errorf("HTTPX_IP_VERSION must be one of: ipv4, ipv6, dual")
+		}
+		switch c.Discovery.HTTPX.ScanMethod {
+		case "", "GET", "HEAD":
+		default:
+			return fmt.Errorf("HTTPX_METHOD must be one of: GET, HEAD")
+		}
+	}
+
+	if c.Discovery.DNS.Enabled {
+		if err := c.validateDNS(); err != nil {
+			return err
+		}
+	}
+
+	if c.Discovery.CTLog.Enabled {
+		if err := c.validateCTLog(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.validateDiscoveryRetry(); err != nil {
+		return err
+	}
+
+	// Validate timeouts
+	if c.Discovery.Timeouts.ProgramProcess <= 0 {
+		return fmt.Errorf("PROGRAM_PROCESS_TIMEOUT must be greater than 0")
+	}
+	if c.Discovery.Timeouts.ChaosDiscovery <= 0 {
+		return fmt.Errorf("CHAOS_DISCOVERY_TIMEOUT must be greater than 0")
+	}
+
+	return nil
+}
+
+// validateCTLog validates the certificate transparency watch
+func (c *Config) validateCTLog() error {
+	ctlog := c.Discovery.CTLog
+	if ctlog.FlushInterval <= 0 {
+		return fmt.Errorf("CTLOG_FLUSH_INTERVAL must be greater than 0")
+	}
+	if ctlog.MaxPending <= 0 {
+		return fmt.Errorf("CTLOG_MAX_PENDING must be greater than 0")
+	}
+	if ctlog.StreamURL != "" && !strings.HasPrefix(ctlog.StreamURL, "ws://") && !strings.HasPrefix(ctlog.StreamURL, "wss://") {
+		return fmt.Errorf("CTLOG_STREAM_URL must be a ws or wss URL")
+	}
+	return nil
+}
+
+// validateDiscoveryRetry validates the retries of failed scope domains
+func (c *Config) validateDiscoveryRetry() error {
+	retry := c.Discovery.Retry
+	if retry.MaxAttempts < 0 {
+		return fmt.Errorf("DISCOVERY_RETRY_MAX_ATTEMPTS must not be negative")
+	}
+	if retry.MaxAttempts == 0 {
+		return nil
+	}
+	if retry.Backoff <= 0 {
+		return fmt.Errorf("DISCOVERY_RETRY_BACKOFF must be greater than 0")
+	}
+	if retry.MaxBackoff < retry.Backoff {
+		return fmt.Errorf("DISCOVERY_RETRY_MAX_BACKOFF must not be shorter than DISCOVERY_RETRY_BACKOFF")
+	}
+	return nil
+}
+
+// validateDNS validates the DNS resolution of asset hostnames
+func (c *Config) validateDNS() error {
+	dns := c.Discovery.DNS
+	if dns.Concurrency <= 0 || dns.Concurrency > 500 {
+		return fmt.Errorf("DNS_CONCURRENCY must be between 1 and 500")
+	}
+	if dns.Timeout <= 0 {
+		return fmt.Errorf("DNS_TIMEOUT must be greater than 0")
+	}
+	for _, resolver := range dns.Resolvers {
+		host, port, err := net.SplitHostPort(resolver)
+		if err != nil {
+			host, port = resolver, "" // no port, or a bare IPv6 address
+		}
+		if strings.Trim(host, "[]") == "" {
+			return fmt.Errorf("DNS_RESOLVERS entry %q has no host", resolver)
+		}
+		if port != "" {
+			if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+				return fmt.Errorf("DNS_RESOLVERS entry %q has an invalid port", resolver)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTimeouts checks that each timeout fits inside the one enclosing it
+// (see TimeoutConfig for the hierarchy). Non-positive values are reported by
+// validateHTTP and validateDiscovery, so they are skipped here.
+func (c *Config) validateTimeouts() error {
+	t := c.Discovery.Timeouts
+	if t.Scan < 0 {
+		return fmt.Errorf("SCAN_TIMEOUT must not be negative")
+	}
+	if t.ProgramProcess <= 0 || t.ChaosDiscovery <= 0 {
+		return nil
+	}
+
+	if t.Scan > 0 && t.Scan < t.ProgramProcess {
+		return fmt.Errorf("SCAN_TIMEOUT (%v) must be at least PROGRAM_PROCESS_TIMEOUT (%v)", t.Scan, t.ProgramProcess)
+	}
+	if t.ProgramProcess < t.ChaosDiscovery+ProgramTimeoutBuffer {
+		return fmt.Errorf("PROGRAM_PROCESS_TIMEOUT (%v) must be at least CHAOS_DISCOVERY_TIMEOUT (%v) plus %v",
+			t.ProgramProcess, t.ChaosDiscovery, ProgramTimeoutBuffer)
+	}
+	if t.ProgramFactor != 0 && (t.ProgramFactor < 1 || t.ProgramFactor > 10) {
+		return fmt.Errorf("PROGRAM_TIMEOUT_FACTOR must be 0 or between 1 and 10")
+	}
+	if t.ProgramMax != 0 && t.ProgramMax < t.ProgramProcess {
+		return fmt.Errorf("PROGRAM_TIMEOUT_MAX (%v) must be at least PROGRAM_PROCESS_TIMEOUT (%v)", t.ProgramMax, t.ProgramProcess)
+	}
+	if t.Scan > 0 && t.ProgramMax > t.Scan {
+		return fmt.Errorf("SCAN_TIMEOUT (%v) must be at least PROGRAM_TIMEOUT_MAX (%v)", t.Scan, t.ProgramMax)
+	}
+	for handle, timeout := range t.ProgramOverrides {
+		if timeout < t.ChaosDiscovery+ProgramTimeoutBuffer {
+			return fmt.Errorf("PROGRAM_TIMEOUTS timeout of %s (%v) must be at least CHAOS_DISCOVERY_TIMEOUT (%v) plus %v",
+				handle, timeout, t.ChaosDiscovery, ProgramTimeoutBuffer)
+		}
+		if t.Scan > 0 && timeout > t.Scan {
+			return fmt.Errorf("SCAN_TIMEOUT (%v) must be at least the PROGRAM_TIMEOUTS timeout of %s (%v)", t.Scan, handle, timeout)
+		}
+	}
+	if c.Discovery.HTTPX.Enabled && c.Discovery.HTTPX.Timeout > t.ChaosDiscovery {
+		return fmt.Errorf("HTTPX_TIMEOUT (%v) must not exceed CHAOS_DISCOVERY_TIMEOUT (%v)", c.Discovery.HTTPX.Timeout, t.ChaosDiscovery)
+	}
+	if c.HTTP.Timeout > 0 && c.HTTP.RetryAttempts >= 0 {
+		worstCase := c.HTTP.MaxRequestDuration()
+		if worstCase > t.ChaosDiscovery {
+			return fmt.Errorf("HTTP_TIMEOUT with %d retries can take up to %v, which exceeds CHAOS_DISCOVERY_TIMEOUT (%v)",
+				c.HTTP.RetryAttempts, worstCase, t.ChaosDiscovery)
+		}
+	}
+
+	return nil
+}
+
+// MaxRequestDuration returns the longest a single HTTP request can take
+// including all retry attempts and the delays between them
+func (h HTTPConfig) MaxRequestDuration() time.Duration {
+	attempts := time.Duration(h.RetryAttempts + 1)
+	return h.Timeout*attempts + h.RetryDelay*time.Duration(h.RetryAttempts)
+}
+
+// validateSync validates sync configuration
+func (c *Config) validateSync() error {
+	if c.Sync.ServerURL == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(c.Sync.ServerURL, "http://") && !strings.HasPrefix(c.Sync.ServerURL, "https://") {
+		return fmt.Errorf("SYNC_SERVER_URL must start with http:// or https://")
+	}
+	if c.Sync.Token == "" {
+		return fmt.Errorf("SYNC_TOKEN is required when SYNC_SERVER_URL is set")
+	}
+	if c.Sync.BatchSize < 0 || c.Sync.BatchSize > 10000 {
+		return fmt.Errorf("SYNC_BATCH_SIZE must be between 1 and 10000")
+	}
+
+	return nil
+}
+
+// validateMaintenance validates platform maintenance configuration
+func (c *Config) validateMaintenance() error {
+	if c.Maintenance.RetryDelay < 0 {
+		return fmt.Errorf("MAINTENANCE_RETRY_DELAY must not be negative")
+	}
+	if c.Maintenance.MaxRetries < 0 || c.Maintenance.MaxRetries > 10 {
+		return fmt.Errorf("MAINTENANCE_MAX_RETRIES must be between 0 and 10")
+	}
+	if c.Maintenance.MaxWait < 0 {
+		return fmt.Errorf("MAINTENANCE_MAX_WAIT must not be negative")
 	}
 
-	// Application validation
-	if err := c.validateApp(); err != nil {
-		errors = append(errors, fmt.Sprintf("application: %v", err))
+	return nil
+}
+
+// validateQuota validates asset quota configuration
+func (c *Config) validateQuota() error {
+	if c.Quota.MaxDropPercent < 0 || c.Quota.MaxDropPercent > 100 {
+		return fmt.Errorf("QUOTA_MAX_DROP_PERCENT must be between 0 and 100")
+	}
+	if c.Quota.MaxGrowth < 0 {
+		return fmt.Errorf("QUOTA_MAX_GROWTH must not be negative")
+	}
+	if c.Quota.MinAssets < 0 {
+		return fmt.Errorf("QUOTA_MIN_ASSETS must not be negative")
 	}
 
-	// HTTP validation
-	if err := c.validateHTTP(); err != nil {
-		errors = append(errors, fmt.Sprintf("HTTP: %v", err))
+	return nil
+}
+
+// validateEvents validates event delivery configuration
+func (c *Config) validateEvents() error {
+	if c.Events.WebhookURL != "" && !strings.HasPrefix(c.Events.WebhookURL, "http://") && !strings.HasPrefix(c.Events.WebhookURL, "https://") {
+		return fmt.Errorf("EVENTS_WEBHOOK_URL must start with http:// or https://")
+	}
+	if len(c.Events.KafkaBrokers) > 0 && c.Events.KafkaTopic == "" {
+		return fmt.Errorf("EVENTS_KAFKA_TOPIC is required when EVENTS_KAFKA_BROKERS is set")
+	}
+	if c.Events.NATSURL != "" {
+		if !strings.HasPrefix(c.Events.NATSURL, "nats://") && !strings.HasPrefix(c.Events.NATSURL, "tls://") {
+			return fmt.Errorf("EVENTS_NATS_URL must start with nats:// or tls://")
+		}
+		if c.Events.NATSSubject == "" {
+			return fmt.Errorf("EVENTS_NATS_SUBJECT is required when EVENTS_NATS_URL is set")
+		}
+	}
+	switch c.Events.DigestAttachment {
+	case "", "csv", "json":
+	default:
+		return fmt.Errorf("EVENTS_DIGEST_ATTACHMENT must be csv or json")
+	}
+	if (c.Events.DigestAttachmentDir == "") != (c.Events.DigestAttachmentURL == "") {
+		return fmt.Errorf("EVENTS_DIGEST_ATTACHMENT_DIR and EVENTS_DIGEST_ATTACHMENT_URL must be set together")
+	}
+	if c.Events.DigestAttachmentURL != "" && !strings.HasPrefix(c.Events.DigestAttachmentURL, "http://") && !strings.HasPrefix(c.Events.DigestAttachmentURL, "https://") {
+		return fmt.Errorf("EVENTS_DIGEST_ATTACHMENT_URL must start with http:// or https://")
 	}
 
-	// Discovery validation
-	if err := c.validateDiscovery(); err != nil {
-		errors = append(errors, fmt.Sprintf("discovery: %v", err))
+	return nil
+}
+
+// validateNotify validates notification sink configuration
+func (c *Config) validateNotify() error {
+	if !c.Notify.SlackEnabled && !c.Notify.DiscordEnabled && !c.Notify.WebhookEnabled {
+		return nil
+	}
+
+	for _, sink := range []struct {
+		enabled bool
+		url     string
+		key     string
+	}{
+		{c.Notify.SlackEnabled, c.Notify.SlackWebhookURL, "NOTIFY_SLACK_WEBHOOK_URL"},
+		{c.Notify.DiscordEnabled, c.Notify.DiscordWebhookURL, "NOTIFY_DISCORD_WEBHOOK_URL"},
+		{c.Notify.WebhookEnabled, c.Notify.WebhookURL, "NOTIFY_WEBHOOK_URL"},
+	} {
+		if !sink.enabled {
+			continue
+		}
+		if sink.url == "" {
+			return fmt.Errorf("%s is required when its sink is enabled", sink.key)
+		}
+		if !strings.HasPrefix(sink.url, "http://") && !strings.HasPrefix(sink.url, "https://") {
+			return fmt.Errorf("%s must start with http:// or https://", sink.key)
+		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("configuration validation failed: %s", strings.Join(errors, "; "))
+	if c.Notify.BatchSize < 1 || c.Notify.BatchSize > 100 {
+		return fmt.Errorf("NOTIFY_BATCH_SIZE must be between 1 and 100")
+	}
+	if c.Notify.BatchInterval < 0 {
+		return fmt.Errorf("NOTIFY_BATCH_INTERVAL must not be negative")
+	}
+	if c.Notify.RetryAttempts < 0 {
+		return fmt.Errorf("NOTIFY_RETRY_ATTEMPTS must not be negative")
+	}
+	if c.Notify.RetryDelay < 0 {
+		return fmt.Errorf("NOTIFY_RETRY_DELAY must not be negative")
+	}
+	return nil
+}
+
+// validateRules validates triage rules configuration
+func (c *Config) validateRules() error {
+	if c.Rules.File == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(c.Rules.File); err != nil {
+		return fmt.Errorf("RULES_FILE %s: %w", c.Rules.File, err)
 	}
 
 	return nil
 }
 
-// validateDatabase validates database configuration
-func (c *Config) validateDatabase() error {
-	if c.Database.Password == "" {
-		return fmt.Errorf("DB_PASSWORD is required")
+// validateScoring validates the asset scoring configuration
+func (c *Config) validateScoring() error {
+	if c.Scoring.File == "" {
+		return nil
 	}
 
-	if c.Database.Host == "" {
-		return fmt.Errorf("DB_HOST is required")
+	if _, err := os.Stat(c.Scoring.File); err != nil {
+		return fmt.Errorf("SCORING_FILE %s: %w", c.Scoring.File, err)
 	}
 
-	if c.Database.Port <= 0 || c.Database.Port > 65535 {
-		return fmt.Errorf("DB_PORT must be between 1 and 65535")
+	return nil
+}
+
+// validateSearch validates search mirror configuration
+func (c *Config) validateSearch() error {
+	if c.Search.BodyExcerptBytes < 0 {
+		return fmt.Errorf("SEARCH_BODY_EXCERPT_BYTES must not be negative")
+	}
+	if c.Search.URL == "" {
+		return nil
 	}
 
-	if c.Database.Name == "" {
-		return fmt.Errorf("DB_NAME is required")
+	if !strings.HasPrefix(c.Search.URL, "http://") && !strings.HasPrefix(c.Search.URL, "https://") {
+		return fmt.Errorf("SEARCH_URL must start with http:// or https://")
+	}
+	if c.Search.Index == "" {
+		return fmt.Errorf("SEARCH_INDEX is required when SEARCH_URL is set")
+	}
+	if c.Search.Index != strings.ToLower(c.Search.Index) {
+		return fmt.Errorf("SEARCH_INDEX must be lowercase")
 	}
 
-	if c.Database.User == "" {
-		return fmt.Errorf("DB_USER is required")
+	return nil
+}
+
+// validateWhois validates registration data enrichment configuration
+func (c *Config) validateWhois() error {
+	if c.Whois.NewDomainDays < 0 {
+		return fmt.Errorf("WHOIS_NEW_DOMAIN_DAYS must not be negative")
+	}
+	if c.Whois.RefreshInterval < 0 {
+		return fmt.Errorf("WHOIS_REFRESH_INTERVAL must not be negative")
+	}
+	if !c.Whois.Enabled && !c.Whois.IPLookups {
+		return nil
 	}
 
-	// Validate SSL mode
-	validSSLModes := []string{"disable", "require", "verify-ca", "verify-full"}
-	validSSLMode := false
-	for _, mode := range validSSLModes {
-		if c.Database.SSLMode == mode {
-			validSSLMode = true
-			break
+	if !strings.HasPrefix(c.Whois.ServerURL, "http://") && !strings.HasPrefix(c.Whois.ServerURL, "https://") {
+		return fmt.Errorf("WHOIS_RDAP_URL must start with http:// or https://")
+	}
+
+	return nil
+}
+
+// validateDaemon validates daemon configuration
+func (c *Config) validateDaemon() error {
+	if c.Daemon.SweepRequestsPerHour < 0 {
+		return fmt.Errorf("DAEMON_SWEEP_REQUESTS_PER_HOUR must not be negative")
+	}
+	if c.Daemon.SweepRequestsPerHour > 0 && c.Daemon.SweepBatchSize <= 0 {
+		return fmt.Errorf("DAEMON_SWEEP_BATCH_SIZE must be greater than 0")
+	}
+	switch c.Daemon.SweepMethod {
+	case "", "GET", "HEAD":
+	default:
+		return fmt.Errorf("DAEMON_SWEEP_METHOD must be one of: GET, HEAD")
+	}
+	if c.Daemon.WatchlistInterval < 0 {
+		return fmt.Errorf("DAEMON_WATCHLIST_INTERVAL must not be negative")
+	}
+	if c.Daemon.ScanSchedule != "" {
+		if _, err := cron.Parse(c.Daemon.ScanSchedule); err != nil {
+			return fmt.Errorf("invalid SCAN_SCHEDULE: %w", err)
 		}
 	}
-	if !validSSLMode {
-		return fmt.Errorf("DB_SSL_MODE must be one of: %s", strings.Join(validSSLModes, ", "))
+	return nil
+}
+
+// validateSlack validates Slack bot configuration
+func (c *Config) validateSlack() error {
+	if c.Slack.AppToken == "" {
+		return nil
 	}
 
-	// Validate SSL certificates if using verify-full
-	if c.Database.SSLMode == "verify-full" {
-		if c.Database.SSLCert == "" {
-			return fmt.Errorf("DB_SSL_CERT is required when DB_SSL_MODE is verify-full")
-		}
-		if c.Database.SSLKey == "" {
-			return fmt.Errorf("DB_SSL_KEY is required when DB_SSL_MODE is verify-full")
-		}
-		if c.Database.SSLRootCert == "" {
-			return fmt.Errorf("DB_SSL_ROOT_CERT is required when DB_SSL_MODE is verify-full")
-		}
+	if !strings.HasPrefix(c.Slack.AppToken, "xapp-") {
+		return fmt.Errorf("SLACK_APP_TOKEN must be an app-level token starting with xapp-")
+	}
+	if len(c.Slack.Command) < 2 || !strings.HasPrefix(c.Slack.Command, "/") || strings.ContainsAny(c.Slack.Command, " \t") {
+		return fmt.Errorf("SLACK_COMMAND must be a slash command such as /monitor")
+	}
+	return nil
+}
 
-		// Check if SSL certificate files exist
-		if _, err := os.Stat(c.Database.SSLCert); os.IsNotExist(err) {
-			return fmt.Errorf("SSL certificate file does not exist: %s", c.Database.SSLCert)
-		}
-		if _, err := os.Stat(c.Database.SSLKey); os.IsNotExist(err) {
-			return fmt.Errorf("SSL key file does not exist: %s", c.Database.SSLKey)
-		}
-		if _, err := os.Stat(c.Database.SSLRootCert); os.IsNotExist(err) {
-			return fmt.Errorf("SSL root certificate file does not exist: %s", c.Database.SSLRootCert)
-		}
+// validateDefectDojo validates DefectDojo export configuration
+func (c *Config) validateDefectDojo() error {
+	if c.DefectDojo.URL == "" {
+		return nil
 	}
 
-	// Validate connection pool settings
-	if c.Database.MaxOpenConns <= 0 {
-		return fmt.Errorf("DB_MAX_OPEN_CONNS must be greater than 0")
+	if !strings.HasPrefix(c.DefectDojo.URL, "http://") && !strings.HasPrefix(c.DefectDojo.URL, "https://") {
+		return fmt.Errorf("DEFECTDOJO_URL must start with http:// or https://")
 	}
-	if c.Database.MaxIdleConns <= 0 {
-		return fmt.Errorf("DB_MAX_IDLE_CONNS must be greater than 0")
+	if c.DefectDojo.APIKey == "" {
+		return fmt.Errorf("DEFECTDOJO_API_KEY is required when DEFECTDOJO_URL is set")
 	}
-	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
-		return fmt.Errorf("DB_MAX_IDLE_CONNS cannot be greater than DB_MAX_OPEN_CONNS")
+	if strings.TrimSpace(c.DefectDojo.ProductType) == "" {
+		return fmt.Errorf("DEFECTDOJO_PRODUCT_TYPE must not be empty")
 	}
-	if c.Database.ConnMaxLifetime <= 0 {
-		return fmt.Errorf("DB_CONN_MAX_LIFETIME must be greater than 0")
+	return nil
+}
+
+// validateCMDB validates CMDB inventory configuration
+func (c *Config) validateCMDB() error {
+	if c.CMDB.ServiceNowURL == "" {
+		return nil
 	}
-	if c.Database.ConnectTimeout <= 0 {
-		return fmt.Errorf("DB_CONNECT_TIMEOUT must be greater than 0")
+
+	if !strings.HasPrefix(c.CMDB.ServiceNowURL, "http://") && !strings.HasPrefix(c.CMDB.ServiceNowURL, "https://") {
+		return fmt.Errorf("CMDB_SERVICENOW_URL must start with http:// or https://")
+	}
+	if c.CMDB.ServiceNowUser == "" || c.CMDB.ServiceNowPassword == "" {
+		return fmt.Errorf("CMDB_SERVICENOW_USER and CMDB_SERVICENOW_PASSWORD are required when CMDB_SERVICENOW_URL is set")
+	}
+	if strings.TrimSpace(c.CMDB.ServiceNowTable) == "" {
+		return fmt.Errorf("CMDB_SERVICENOW_TABLE must not be empty")
+	}
+	return nil
+}
+
+// validateNotes validates notes vault configuration
+func (c *Config) validateNotes() error {
+	if c.Notes.VaultDir == "" {
+		return nil
 	}
 
+	if info, err := os.Stat(c.Notes.VaultDir); err == nil && !info.IsDir() {
+		return fmt.Errorf("NOTES_VAULT_DIR %s is not a directory", c.Notes.VaultDir)
+	}
 	return nil
 }
 
-// validateAPIs validates API configuration
-func (c *Config) validateAPIs() error {
-	// Validate HackerOne configuration (only if API key is provided)
-	if c.APIs.HackerOne.APIKey != "" {
-		if c.APIs.HackerOne.RateLimit <= 0 || c.APIs.HackerOne.RateLimit > 600 {
-			return fmt.Errorf("HACKERONE_RATE_LIMIT must be between 1 and 600")
-		}
+// validateProbeAuth validates probe auth configuration
+func (c *Config) validateProbeAuth() error {
+	if c.ProbeAuth.Key == "" {
+		return nil
 	}
 
-	// Validate BugCrowd configuration (only if API key is provided)
-	if c.APIs.BugCrowd.APIKey != "" {
-		if c.APIs.BugCrowd.RateLimit <= 0 || c.APIs.BugCrowd.RateLimit > 60 {
-			return fmt.Errorf("BUGCROWD_RATE_LIMIT must be between 1 and 60")
-		}
+	if _, err := probeauth.ParseKey(c.ProbeAuth.Key); err != nil {
+		return fmt.Errorf("invalid PROBE_AUTH_KEY: %w", err)
 	}
+	return nil
+}
 
-	// Validate ChaosDB configuration (only if API key is provided)
-	if c.APIs.ChaosDB.APIKey != "" {
-		if c.APIs.ChaosDB.RateLimit <= 0 || c.APIs.ChaosDB.RateLimit > 60 {
-			return fmt.Errorf("CHAOSDB_RATE_LIMIT must be between 1 and 60")
-		}
+// validateQuarantine validates scope quarantine configuration
+func (c *Config) validateQuarantine() error {
+	switch c.Quarantine.Mode {
+	case "", QuarantineOff, QuarantineDryRun, QuarantineOn:
+	default:
+		return fmt.Errorf("SCOPE_QUARANTINE_MODE must be off, dry-run or on")
 	}
 
+	if c.Quarantine.Grace < 0 {
+		return fmt.Errorf("SCOPE_QUARANTINE_GRACE must not be negative")
+	}
 	return nil
 }
 
-// validateApp validates application configuration
-func (c *Config) validateApp() error {
-	// Validate log level
-	validLogLevels := []string{"debug", "info", "warn", "error", "fatal"}
-	validLogLevel := false
-	for _, level := range validLogLevels {
-		if c.App.LogLevel == level {
-			validLogLevel = true
-			break
-		}
+// validateStatusPage validates status page configuration
+func (c *Config) validateStatusPage() error {
+	if c.StatusPage.Dir == "" {
+		return nil
 	}
-	if !validLogLevel {
-		return fmt.Errorf("LOG_LEVEL must be one of: %s", strings.Join(validLogLevels, ", "))
+
+	if strings.TrimSpace(c.StatusPage.Title) == "" {
+		return fmt.Errorf("STATUS_PAGE_TITLE must not be empty")
 	}
+	if info, err := os.Stat(c.StatusPage.Dir); err == nil && !info.IsDir() {
+		return fmt.Errorf("STATUS_PAGE_DIR %s is not a directory", c.StatusPage.Dir)
+	}
+	return nil
+}
 
-	// Validate environment
-	validEnvironments := []string{"development", "staging", "production"}
-	validEnvironment := false
-	for _, env := range validEnvironments {
-		if c.App.Environment == env {
-			validEnvironment = true
-			break
-		}
+// validateScanReport validates scan report configuration
+func (c *Config) validateScanReport() error {
+	switch c.ScanReport.Format {
+	case "", ScanReportMarkdown, ScanReportHTML:
+	default:
+		return fmt.Errorf("SCAN_REPORT_FORMAT must be one of: markdown, html")
 	}
-	if !validEnvironment {
-		return fmt.Errorf("ENVIRONMENT must be one of: %s", strings.Join(validEnvironments, ", "))
+	if url := c.ScanReport.WebhookURL; url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return fmt.Errorf("SCAN_REPORT_WEBHOOK_URL must start with http:// or https://")
+	}
+	if info, err := os.Stat(c.ScanReport.Dir); c.ScanReport.Dir != "" && err == nil && !info.IsDir() {
+		return fmt.Errorf("SCAN_REPORT_DIR %s is not a directory", c.ScanReport.Dir)
 	}
+	return nil
+}
 
+// validateCanary validates canary configuration
+func (c *Config) validateCanary() error {
+	switch c.Canary.OnFailure {
+	case "", "abort", "alert":
+	default:
+		return fmt.Errorf("CANARY_ON_FAILURE must be one of: abort, alert")
+	}
+	for _, target := range c.Canary.Targets {
+		if target.Hostname == "" || strings.ContainsAny(target.Hostname, "/: ") {
+			return fmt.Errorf("CANARY_TARGETS entry %q must be a bare hostname", target.Hostname)
+		}
+	}
 	return nil
 }
 
-// validateHTTP validates HTTP configuration
-func (c *Config) validateHTTP() error {
-	if c.HTTP.Timeout <= 0 {
-		return fmt.Errorf("HTTP_TIMEOUT must be greater than 0")
+// validateVantage validates remote probe worker configuration
+func (c *Config) validateVantage() error {
+	if len(c.Vantage.Workers) == 0 {
+		return nil
 	}
-	if c.HTTP.RetryAttempts < 0 || c.HTTP.RetryAttempts > 10 {
-		return fmt.Errorf("HTTP_RETRY_ATTEMPTS must be between 0 and 10")
+
+	if c.Vantage.Token == "" {
+		return fmt.Errorf("PROBE_WORKER_TOKEN is required when PROBE_WORKERS is set")
 	}
-	if c.HTTP.RetryDelay <= 0 {
-		return fmt.Errorf("HTTP_RETRY_DELAY must be greater than 0")
+	if !c.Discovery.HTTPX.Enabled {
+		return fmt.Errorf("PROBE_WORKERS requires HTTPX_ENABLED")
+	}
+
+	regions := map[string]bool{c.Vantage.Region: true}
+	for _, worker := range c.Vantage.Workers {
+		if worker.Region == "" {
+			return fmt.Errorf("PROBE_WORKERS entries need a region")
+		}
+		if regions[worker.Region] {
+			return fmt.Errorf("PROBE_WORKERS region %s is used more than once", worker.Region)
+		}
+		regions[worker.Region] = true
+
+		if !strings.HasPrefix(worker.URL, "http://") && !strings.HasPrefix(worker.URL, "https://") {
+			return fmt.Errorf("PROBE_WORKERS URL for %s must start with http:// or https://", worker.Region)
+		}
 	}
 
 	return nil
 }
 
-// validateDiscovery validates discovery configuration
-func (c *Config) validateDiscovery() error {
-	if c.Discovery.BulkSize <= 0 || c.Discovery.BulkSize > 1000 {
-		return fmt.Errorf("CHAOSDB_BULK_SIZE must be between 1 and 1000")
+// validateObjectStore validates the object store configuration
+func (c *Config) validateObjectStore() error {
+	if c.ObjectStore.Bucket == "" {
+		return nil
 	}
 
-	// Validate HTTPX configuration if enabled
-	if c.Discovery.HTTPX.Enabled {
-		if c.Discovery.HTTPX.Timeout <= 0 {
-			return fmt.Errorf("HTTPX_TIMEOUT must be greater than 0")
+	if c.ObjectStore.AccessKeyID == "" || c.ObjectStore.SecretAccessKey == "" {
+		return fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when S3_BUCKET is set")
+	}
+	if c.ObjectStore.Endpoint != "" && !strings.HasPrefix(c.ObjectStore.Endpoint, "http://") && !strings.HasPrefix(c.ObjectStore.Endpoint, "https://") {
+		return fmt.Errorf("S3_ENDPOINT must start with http:// or https://")
+	}
+	if strings.TrimSpace(c.ObjectStore.Region) == "" {
+		return fmt.Errorf("S3_REGION must not be empty")
+	}
+	if strings.HasPrefix(c.ObjectStore.Prefix, "/") {
+		return fmt.Errorf("S3_PREFIX must not start with /")
+	}
+	return nil
+}
+
+// validateBodyStore validates the response body store configuration
+func (c *Config) validateBodyStore() error {
+	if c.BodyStore.MinBytes < 0 {
+		return fmt.Errorf("BODY_STORE_MIN_BYTES must not be negative")
+	}
+	if c.BodyStore.Bucket == "" {
+		return nil
+	}
+	if strings.HasPrefix(c.BodyStore.Prefix, "/") {
+		return fmt.Errorf("BODY_STORE_PREFIX must not start with /")
+	}
+
+	switch c.BodyStore.Backend {
+	case "s3":
+		if c.ObjectStore.AccessKeyID == "" || c.ObjectStore.SecretAccessKey == "" {
+			return fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when BODY_STORE_BUCKET is set")
 		}
-		if c.Discovery.HTTPX.Concurrency <= 0 || c.Discovery.HTTPX.Concurrency > 100 {
-			return fmt.Errorf("HTTPX_CONCURRENCY must be between 1 and 100")
+		if c.ObjectStore.Endpoint != "" && !strings.HasPrefix(c.ObjectStore.Endpoint, "http://") && !strings.HasPrefix(c.ObjectStore.Endpoint, "https://") {
+			return fmt.Errorf("S3_ENDPOINT must start with http:// or https://")
 		}
-		if c.Discovery.HTTPX.RateLimit <= 0 {
-			return fmt.Errorf("HTTPX_RATE_LIMIT must be greater than 0")
+	case "gcs":
+		if c.BodyStore.GCSCredentialsFile == "" && c.BodyStore.GCSEndpoint == "" {
+			return fmt.Errorf("GCS_CREDENTIALS_FILE is required when BODY_STORE_BUCKET is set")
 		}
-		if c.Discovery.HTTPX.MaxRedirects < 0 || c.Discovery.HTTPX.MaxRedirects > 10 {
-			return fmt.Errorf("HTTPX_MAX_REDIRECTS must be between 0 and 10")
+		if c.BodyStore.GCSEndpoint != "" && !strings.HasPrefix(c.BodyStore.GCSEndpoint, "http://") && !strings.HasPrefix(c.BodyStore.GCSEndpoint, "https://") {
+			return fmt.Errorf("GCS_ENDPOINT must start with http:// or https://")
 		}
+	default:
+		return fmt.Errorf("BODY_STORE_BACKEND must be s3 or gcs, got %q", c.BodyStore.Backend)
+	}
+	return nil
+}
+
+// validateMetrics validates metrics configuration
+func (c *Config) validateMetrics() error {
+	if !c.Metrics.Enabled {
+		return nil
 	}
 
-	// Validate timeouts
-	if c.Discovery.Timeouts.ProgramProcess <= 0 {
-		return fmt.Errorf("PROGRAM_PROCESS_TIMEOUT must be greater than 0")
+	if strings.TrimSpace(c.Metrics.ListenAddr) == "" {
+		return fmt.Errorf("METRICS_LISTEN_ADDR must not be empty")
 	}
-	if c.Discovery.Timeouts.ChaosDiscovery <= 0 {
-		return fmt.Errorf("CHAOS_DISCOVERY_TIMEOUT must be greater than 0")
+	if !strings.HasPrefix(c.Metrics.Path, "/") {
+		return fmt.Errorf("METRICS_PATH must start with /")
+	}
+	return nil
+}
+
+// ValidateProbeWorker validates the configuration needed to run as a probe
+// worker, which has no database
+func (c *Config) ValidateProbeWorker() error {
+	if c.App.Passive {
+		return fmt.Errorf("a probe worker probes targets and cannot run in passive mode")
+	}
+	if c.App.ReadOnly {
+		return fmt.Errorf("a probe worker probes targets and cannot run in read-only mode")
+	}
+	if c.Vantage.Token == "" {
+		return fmt.Errorf("PROBE_WORKER_TOKEN is required to run a probe worker")
+	}
+	if !c.Discovery.HTTPX.Enabled {
+		return fmt.Errorf("HTTPX_ENABLED is required to run a probe worker")
+	}
+	if err := c.validateDiscovery(); err != nil {
+		return fmt.Errorf("discovery: %w", err)
 	}
 
 	return nil
@@ -578,6 +2716,11 @@ func (c *Config) GetDSN() string {
 		dsn += fmt.Sprintf(" sslrootcert=%s", c.Database.SSLRootCert)
 	}
 
+	// In read-only mode every session refuses writes, whatever the command
+	if c.App.ReadOnly {
+		dsn += " default_transaction_read_only=on"
+	}
+
 	return dsn
 }
 
@@ -591,12 +2734,17 @@ func getEnv(key, defaultValue string) string {
 
 // HasHackerOneConfig returns true if HackerOne is configured with an API key and username
 func (c *Config) HasHackerOneConfig() bool {
-	return c.APIs.HackerOne.APIKey != "" && c.APIs.HackerOne.Username != ""
+	return (c.APIs.HackerOne.APIKey != "" && c.APIs.HackerOne.Username != "") || len(c.APIs.HackerOne.Credentials) > 0
 }
 
 // HasBugCrowdConfig returns true if BugCrowd is configured with an API key
 func (c *Config) HasBugCrowdConfig() bool {
-	return c.APIs.BugCrowd.APIKey != ""
+	return c.APIs.BugCrowd.APIKey != "" || len(c.APIs.BugCrowd.Credentials) > 0
+}
+
+// HasIntigritiConfig returns true if Intigriti is configured with an API key
+func (c *Config) HasIntigritiConfig() bool {
+	return c.APIs.Intigriti.APIKey != "" || len(c.APIs.Intigriti.Credentials) > 0
 }
 
 // HasChaosDBConfig returns true if ChaosDB is configured with an API key
@@ -604,6 +2752,11 @@ func (c *Config) HasChaosDBConfig() bool {
 	return c.APIs.ChaosDB.APIKey != ""
 }
 
+// HasDiscoverySources returns true if any source of secondary subdomains is configured
+func (c *Config) HasDiscoverySources() bool {
+	return c.HasChaosDBConfig() || c.APIs.CRTSh.Enabled
+}
+
 // GetConfiguredPlatforms returns a list of platform names that have API keys configured
 func (c *Config) GetConfiguredPlatforms() []string {
 	var platforms []string
@@ -613,6 +2766,9 @@ func (c *Config) GetConfiguredPlatforms() []string {
 	if c.HasBugCrowdConfig() {
 		platforms = append(platforms, "bugcrowd")
 	}
+	if c.HasIntigritiConfig() {
+		platforms = append(platforms, "intigriti")
+	}
 	if c.HasChaosDBConfig() {
 		platforms = append(platforms, "chaosdb")
 	}
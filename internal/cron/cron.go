@@ -0,0 +1,210 @@
+// Package cron parses standard five-field cron expressions, e.g. "0 3 * * *",
+// and computes when they next fire.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	spec     string
+	minutes  bits
+	hours    bits
+	days     bits // days of the month
+	months   bits
+	weekdays bits // 0 is Sunday
+
+	// Restricting both the day of the month and the weekday matches either
+	anyDay     bool
+	anyWeekday bool
+}
+
+// bits is a set of the values 0 to 63
+type bits uint64
+
+func (b bits) has(value int) bool {
+	return b&(1<<uint(value)) != 0
+}
+
+// field is the range of values a cron field accepts
+type field struct {
+	name     string
+	min, max int
+	names    []string // names of the values from min on, e.g. months
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day of month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// macros are the named schedules accepted in place of five fields
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxLookahead bounds the search for the next time a schedule fires; it
+// covers a leap day
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+// Parse parses a cron expression of five fields (minute, hour, day of month,
+// month, day of week) or one of @yearly, @monthly, @weekly, @daily and
+// @hourly. Fields take *, values, ranges (1-5), steps (*/15, 0-30/10), lists
+// (1,15) and, for months and weekdays, three-letter names. Both 0 and 7 are
+// Sunday.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	expr := spec
+	if strings.HasPrefix(expr, "@") {
+		var ok bool
+		if expr, ok = macros[strings.ToLower(expr)]; !ok {
+			return nil, fmt.Errorf("unknown cron macro %q", spec)
+		}
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day-of-month month day-of-week), got %d", spec, len(fields))
+	}
+
+	s := &Schedule{
+		spec:       spec,
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}
+	var err error
+	if s.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hours, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.days, err = dayField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.months, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.weekdays, err = weekdayField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if s.weekdays.has(7) {
+		s.weekdays |= 1 << 0
+	}
+
+	// e.g. "0 0 30 2 *" is valid field by field but never fires
+	if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never fires", spec)
+	}
+	return s, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time after t the schedule fires, in t's location.
+// It returns the zero time when the schedule does not fire within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	// Schedules fire on whole minutes
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Add(maxLookahead)
+
+	for t.Before(limit) {
+		switch {
+		case !s.months.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hours.has(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.minutes.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the schedule fires on t's day. As in cron, a
+// day matches either field when both the day of the month and the weekday
+// are restricted.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	day := s.days.has(t.Day())
+	weekday := s.weekdays.has(int(t.Weekday()))
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// parse parses a comma-separated list of a field's values, ranges and steps
+func (f field) parse(expr string) (bits, error) {
+	var set bits
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field %q", stepExpr, f.name, expr)
+			}
+		}
+
+		low, high := f.min, f.max
+		if rangeExpr != "*" {
+			lowExpr, highExpr, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if low, err = f.value(lowExpr); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if high, err = f.value(highExpr); err != nil {
+					return 0, err
+				}
+			case !hasStep:
+				high = low
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeExpr, f.name)
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	return set, nil
+}
+
+// value parses a single value or name of a field
+func (f field) value(expr string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(expr, name) {
+			return f.min + i, nil
+		}
+	}
+
+	value, err := strconv.Atoi(expr)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid %s %q: must be between %d and %d", f.name, expr, f.min, f.max)
+	}
+	return value, nil
+}
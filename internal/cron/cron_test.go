@@ -0,0 +1,81 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, 10, 14, 2, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 3 * * *", time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2026, 10, 14, 2, 40, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2026, 10, 15, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * mon-fri", time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * sun", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either the day of the month or the weekday
+		{"0 0 20 * fri", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+			assert.Equal(t, tt.spec, schedule.String())
+		})
+	}
+}
+
+func TestSchedule_NextInLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	schedule, err := Parse("0 3 * * *")
+	require.NoError(t, err)
+
+	// 03:00 local time, not UTC
+	next := schedule.Next(time.Date(2026, 10, 14, 12, 0, 0, 0, berlin))
+	assert.Equal(t, time.Date(2026, 10, 15, 3, 0, 0, 0, berlin), next)
+
+	// Clocks jump from 02:00 to 03:00 on the last Sunday of March
+	schedule, err = Parse("30 2 * * *")
+	require.NoError(t, err)
+	next = schedule.Next(time.Date(2026, 3, 28, 12, 0, 0, 0, berlin))
+	assert.Equal(t, time.Date(2026, 3, 30, 2, 30, 0, 0, berlin), next)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"0 3 * *",
+		"0 3 * * * *",
+		"60 * * * *",
+		"0 24 * * *",
+		"0 0 0 * *",
+		"0 0 * 13 *",
+		"0 0 * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"0 0 * foo *",
+		"0 0 30 2 *",
+		"@fortnightly",
+	} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// APISchemaRepository handles API schema database operations
+type APISchemaRepository struct {
+	*Repository
+}
+
+// NewAPISchemaRepository creates a new API schema repository
+func NewAPISchemaRepository(db *sqlx.DB) *APISchemaRepository {
+	return &APISchemaRepository{Repository: NewRepository(db)}
+}
+
+// SaveSchema creates or replaces an asset's schema of the same kind together
+// with its endpoints
+func (r *APISchemaRepository) SaveSchema(ctx context.Context, schema *APISchema, endpoints []*APIEndpoint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Track if we've committed the transaction
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	now := time.Now()
+	schema.EndpointCount = len(endpoints)
+	schema.CreatedAt = now
+	schema.UpdatedAt = now
+
+	schemaQuery := `
+		INSERT INTO api_schemas (id, asset_id, response_id, kind, version, title, endpoint_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (asset_id, kind) DO UPDATE SET
+			response_id = EXCLUDED.response_id,
+			version = EXCLUDED.version,
+			title = EXCLUDED.title,
+			endpoint_count = EXCLUDED.endpoint_count,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRowxContext(ctx, schemaQuery, uuid.New(), schema.AssetID, schema.ResponseID, schema.Kind,
+		schema.Version, schema.Title, schema.EndpointCount, schema.CreatedAt, schema.UpdatedAt).Scan(&schema.ID, &schema.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save api schema: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM api_endpoints WHERE schema_id = $1`, schema.ID); err != nil {
+		return fmt.Errorf("failed to clear api endpoints: %w", err)
+	}
+
+	endpointQuery := `
+		INSERT INTO api_endpoints (id, schema_id, method, path, summary)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (schema_id, method, path) DO NOTHING
+	`
+
+	for _, endpoint := range endpoints {
+		endpoint.ID = uuid.New()
+		endpoint.SchemaID = schema.ID
+		if _, err := tx.ExecContext(ctx, endpointQuery, endpoint.ID, endpoint.SchemaID, endpoint.Method, endpoint.Path, endpoint.Summary); err != nil {
+			return fmt.Errorf("failed to save api endpoint %s %s: %w", endpoint.Method, endpoint.Path, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return nil
+}
+
+// GetSchemasByAssetID retrieves the API schemas found on an asset
+func (r *APISchemaRepository) GetSchemasByAssetID(ctx context.Context, assetID uuid.UUID) ([]*APISchema, error) {
+	var schemas []*APISchema
+	query := `SELECT * FROM api_schemas WHERE asset_id = $1 ORDER BY kind`
+
+	err := r.db.SelectContext(ctx, &schemas, query, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api schemas: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// GetEndpoints retrieves the endpoints of an API schema
+func (r *APISchemaRepository) GetEndpoints(ctx context.Context, schemaID uuid.UUID) ([]*APIEndpoint, error) {
+	var endpoints []*APIEndpoint
+	query := `SELECT * FROM api_endpoints WHERE schema_id = $1 ORDER BY path, method`
+
+	err := r.db.SelectContext(ctx, &endpoints, query, schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// AssetChangeRepository handles the asset changes recorded between scans
+type AssetChangeRepository struct {
+	*Repository
+}
+
+// NewAssetChangeRepository creates a new asset change repository
+func NewAssetChangeRepository(db *sqlx.DB) *AssetChangeRepository {
+	return &AssetChangeRepository{Repository: NewRepository(db)}
+}
+
+// GetAssetStatesSince retrieves the state of a program's assets last found or
+// confirmed by a scan of the program started at or after since, leaving out
+// the given scan. With since set to the previous completed scan's start, these
+// are the assets the previous scan saw, including any a later scan that did
+// not complete confirmed.
+func (r *AssetChangeRepository) GetAssetStatesSince(ctx context.Context, programID uuid.UUID, since time.Time, excludeScanID uuid.UUID) ([]*AssetState, error) {
+	var states []*AssetState
+	query := `
+		SELECT a.id, a.url, a.status, h.liveness, h.ip, h.ipv6, h.content_hash
+		FROM assets a
+		JOIN hosts h ON h.id = a.host_id
+		JOIN scans s ON s.id = a.last_scan_id
+		WHERE a.program_id = $1 AND s.program_id = $1 AND s.started_at >= $2 AND s.id <> $3
+	`
+
+	err := r.db.SelectContext(ctx, &states, query, programID, since, excludeScanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset states: %w", err)
+	}
+
+	return states, nil
+}
+
+// GetScanAssetStates retrieves the state of the assets a scan found or confirmed
+func (r *AssetChangeRepository) GetScanAssetStates(ctx context.Context, programID, scanID uuid.UUID) ([]*AssetState, error) {
+	var states []*AssetState
+	query := `
+		SELECT a.id, a.url, a.status, h.liveness, h.ip, h.ipv6, h.content_hash
+		FROM assets a JOIN hosts h ON h.id = a.host_id
+		WHERE a.program_id = $1 AND a.last_scan_id = $2
+	`
+
+	err := r.db.SelectContext(ctx, &states, query, programID, scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan asset states: %w", err)
+	}
+
+	return states, nil
+}
+
+// SaveAssetChanges records a scan's asset changes and the scan they were
+// computed against in one transaction
+func (r *AssetChangeRepository) SaveAssetChanges(ctx context.Context, scanID, comparedScanID uuid.UUID, changes []*AssetChange) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	if len(changes) > 0 {
+		stmt, err := tx.PrepareNamedContext(ctx, `
+			INSERT INTO asset_changes (id, scan_id, program_id, asset_id, url, change, field, old_value, new_value, created_at)
+			VALUES (:id, :scan_id, :program_id, :asset_id, :url, :change, :field, :old_value, :new_value, :created_at)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare asset change insert: %w", err)
+		}
+		defer stmt.Close()
+
+		now := time.Now()
+		for _, change := range changes {
+			change.ID = uuid.New()
+			change.ScanID = scanID
+			change.CreatedAt = now
+			if _, err := stmt.ExecContext(ctx, change); err != nil {
+				return fmt.Errorf("failed to record asset change of %s: %w", change.URL, err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE scans SET compared_scan_id = $2 WHERE id = $1`, scanID, comparedScanID); err != nil {
+		return fmt.Errorf("failed to record compared scan: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return nil
+}
+
+// GetScanChanges retrieves the asset changes a scan recorded, added assets
+// first, then removed and changed ones, each by URL
+func (r *AssetChangeRepository) GetScanChanges(ctx context.Context, scanID uuid.UUID) ([]*AssetChange, error) {
+	var changes []*AssetChange
+	query := `
+		SELECT * FROM asset_changes
+		WHERE scan_id = $1
+		ORDER BY CASE change WHEN 'added' THEN 0 WHEN 'removed' THEN 1 ELSE 2 END, url, field
+	`
+
+	err := r.db.SelectContext(ctx, &changes, query, scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset changes: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,29 @@
+package database
+
+import (
+	"net/url"
+	"strings"
+)
+
+// defaultPorts are dropped from host keys so https://x and https://x:443 are one asset
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// AssetHostKey returns the canonical identity of an asset URL: its lowercased
+// host and any non-default port. http://x and https://x share a host key, so
+// they are stored as one asset with both scheme variants recorded underneath.
+// The 012 migration backfills existing rows with the same rules.
+func AssetHostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(rawURL)
+	}
+
+	host := strings.ToLower(u.Host)
+	if port, ok := defaultPorts[strings.ToLower(u.Scheme)]; ok {
+		host = strings.TrimSuffix(host, ":"+port)
+	}
+	return host
+}
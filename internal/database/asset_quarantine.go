@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AssetStatusQuarantined is the status of an asset whose scope root stayed out
+// of its program's scope for longer than the quarantine grace period
+const AssetStatusQuarantined = "quarantined"
+
+// MarkAssetsScopeMissing records that the scope root of the given assets left
+// their program's scope. Assets already marked keep their original time.
+func (r *AssetRepository) MarkAssetsScopeMissing(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE assets SET scope_missing_since = NOW(), updated_at = NOW()
+		WHERE id = ANY($1) AND scope_missing_since IS NULL
+	`, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark assets missing from scope: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ClearAssetsScopeMissing records that the given assets are back in their
+// program's scope, reactivating the ones that were quarantined
+func (r *AssetRepository) ClearAssetsScopeMissing(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE assets SET
+			scope_missing_since = NULL,
+			status = CASE WHEN status = $2 THEN 'active' ELSE status END,
+			updated_at = NOW()
+		WHERE id = ANY($1) AND scope_missing_since IS NOT NULL
+	`, pq.Array(ids), AssetStatusQuarantined)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear assets missing from scope: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// QuarantineAssets sets the given assets' status to quarantined
+func (r *AssetRepository) QuarantineAssets(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE assets SET status = $2, updated_at = NOW()
+		WHERE id = ANY($1) AND status <> $2
+	`, pq.Array(ids), AssetStatusQuarantined)
+	if err != nil {
+		return 0, fmt.Errorf("failed to quarantine assets: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetScopeMissingAssets retrieves the assets whose scope root left their
+// program's scope, oldest first, optionally limited to one program
+func (r *AssetRepository) GetScopeMissingAssets(ctx context.Context, programID *uuid.UUID) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + `
+		WHERE a.scope_missing_since IS NOT NULL AND ($1::uuid IS NULL OR a.program_id = $1)
+		ORDER BY a.scope_missing_since, a.url
+	`
+
+	err := r.db.SelectContext(ctx, &assets, query, programID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets missing from scope: %w", err)
+	}
+
+	return assets, nil
+}
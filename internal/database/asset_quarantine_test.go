@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetRepository_MarkAssetsScopeMissing(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectExec("UPDATE assets SET scope_missing_since = NOW\\(\\).*scope_missing_since IS NULL").
+		WithArgs(pq.Array(ids)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	marked, err := repo.MarkAssetsScopeMissing(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), marked)
+
+	// Nothing to mark does not touch the database
+	marked, err = repo.MarkAssetsScopeMissing(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Zero(t, marked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_ClearAssetsScopeMissing(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	ids := []uuid.UUID{uuid.New()}
+
+	mock.ExpectExec("UPDATE assets SET\\s+scope_missing_since = NULL").
+		WithArgs(pq.Array(ids), AssetStatusQuarantined).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	cleared, err := repo.ClearAssetsScopeMissing(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), cleared)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_QuarantineAssets(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectExec("UPDATE assets SET status = \\$2").
+		WithArgs(pq.Array(ids), AssetStatusQuarantined).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	quarantined, err := repo.QuarantineAssets(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), quarantined)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// AssetQuery selects assets by a list of field:value terms, all of which must
+// match. A term prefixed with - must not match. Text values may contain *
+// wildcards and are matched case-insensitively, e.g.
+// "domain:*.old-acquisition.com -tag:keep liveness:dns-only".
+type AssetQuery struct {
+	Terms []AssetQueryTerm
+}
+
+// AssetQueryTerm is one field:value condition of an asset query
+type AssetQueryTerm struct {
+	Field  string
+	Value  string
+	Negate bool
+}
+
+// assetQueryFields maps query fields to the asset column, or the column of
+// the asset's host, they match on. Fields without a column are matched by
+// their own condition.
+var assetQueryFields = map[string]string{
+	"domain":   "split_part(a.host_key, ':', 1)",
+	"host":     "split_part(a.host_key, ':', 1)",
+	"url":      "a.url",
+	"apex":     "a.domain",
+	"status":   "a.status",
+	"liveness": "(SELECT h.liveness FROM hosts h WHERE h.id = a.host_id)",
+	"source":   "a.first_source",
+	"program":  "",
+	"tag":      "",
+	"ip":       "",
+	"ignored":  "",
+	"redirect": "",
+	"cluster":  "",
+}
+
+// ParseAssetQuery parses a space-separated list of field:value terms
+func ParseAssetQuery(query string) (*AssetQuery, error) {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty asset query")
+	}
+
+	parsed := &AssetQuery{}
+	for _, field := range fields {
+		term := AssetQueryTerm{}
+		field, term.Negate = strings.CutPrefix(field, "-")
+
+		name, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("query term %q must be field:value", field)
+		}
+		term.Field = strings.ToLower(name)
+		term.Value = value
+
+		if _, known := assetQueryFields[term.Field]; !known {
+			return nil, fmt.Errorf("unknown query field %q (use %s)", name, strings.Join(AssetQueryFields(), ", "))
+		}
+		switch term.Field {
+		case "ip":
+			if _, _, err := net.ParseCIDR(value); err != nil && net.ParseIP(value) == nil {
+				return nil, fmt.Errorf("ip:%s is not an address or CIDR range", value)
+			}
+		case "ignored":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("ignored:%s must be true or false", value)
+			}
+		case "cluster":
+			if _, err := uuid.Parse(value); err != nil && !strings.EqualFold(value, "none") {
+				return nil, fmt.Errorf("cluster:%s must be a cluster ID or none", value)
+			}
+		}
+
+		parsed.Terms = append(parsed.Terms, term)
+	}
+
+	return parsed, nil
+}
+
+// AssetQueryFields returns the fields an asset query can match on
+func AssetQueryFields() []string {
+	return []string{"domain", "host", "url", "apex", "program", "status", "liveness", "source", "tag", "ip", "ignored", "redirect", "cluster"}
+}
+
+// where builds the SQL condition of the query on assets aliased as a, with
+// its arguments numbered from $firstArg
+func (q *AssetQuery) where(firstArg int) (string, []any) {
+	conditions := make([]string, 0, len(q.Terms))
+	args := make([]any, 0, len(q.Terms))
+
+	for _, term := range q.Terms {
+		n := firstArg + len(args)
+		var condition string
+
+		switch term.Field {
+		case "program":
+			// A program URL, or the handle at the end of one
+			pattern := likePattern(term.Value)
+			if !strings.Contains(term.Value, "://") {
+				pattern = "%/" + pattern
+			}
+			condition = fmt.Sprintf("a.program_url ILIKE $%d", n)
+			args = append(args, pattern)
+		case "tag":
+			condition = fmt.Sprintf("EXISTS (SELECT 1 FROM asset_tags t WHERE t.asset_id = a.id AND t.tag ILIKE $%d)", n)
+			args = append(args, likePattern(term.Value))
+		case "ip":
+			// A bare address is a range of one
+			condition = fmt.Sprintf("EXISTS (SELECT 1 FROM hosts h WHERE h.id = a.host_id AND (NULLIF(h.ip, '')::inet <<= $%[1]d::inet OR NULLIF(h.ipv6, '')::inet <<= $%[1]d::inet))", n)
+			args = append(args, term.Value)
+		case "ignored":
+			ignored, _ := strconv.ParseBool(term.Value)
+			condition = fmt.Sprintf("a.ignored = $%d", n)
+			args = append(args, ignored)
+		case "redirect":
+			// How the redirects behind the latest response stored for the host ended
+			condition = fmt.Sprintf("(SELECT r.redirect_status FROM asset_responses r WHERE r.host_id = a.host_id ORDER BY r.created_at DESC LIMIT 1) ILIKE $%d", n)
+			args = append(args, likePattern(term.Value))
+		case "cluster":
+			// A cluster of near-identical responses, or none for unclustered assets
+			if strings.EqualFold(term.Value, "none") {
+				condition = "a.cluster_id IS NULL"
+				break
+			}
+			condition = fmt.Sprintf("a.cluster_id = $%d::uuid", n)
+			args = append(args, term.Value)
+		default:
+			condition = fmt.Sprintf("%s ILIKE $%d", assetQueryFields[term.Field], n)
+			args = append(args, likePattern(term.Value))
+		}
+
+		if term.Negate {
+			condition = fmt.Sprintf("NOT COALESCE(%s, false)", condition)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// likePattern turns a value with * wildcards into a LIKE pattern, escaping
+// the characters LIKE treats specially
+func likePattern(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+	return strings.ReplaceAll(value, "*", "%")
+}
+
+// AssetUpdate is a change applied to every asset matching a query
+type AssetUpdate struct {
+	AddTags    []string
+	RemoveTags []string
+	TagSource  string // recorded on added tags
+	Status     string // unchanged when empty
+	Ignored    *bool  // unchanged when nil
+}
+
+// AssetUpdateResult counts the rows a batch update changed
+type AssetUpdateResult struct {
+	Matched     int64
+	TagsAdded   int64
+	TagsRemoved int64
+	Updated     int64 // assets whose status or ignored flag changed
+}
+
+// CountAssetsByQuery counts the assets matching a query
+func (r *AssetRepository) CountAssetsByQuery(ctx context.Context, query *AssetQuery) (int64, error) {
+	where, args := query.where(1)
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM assets a WHERE `+where, args...); err != nil {
+		return 0, fmt.Errorf("failed to count assets by query: %w", err)
+	}
+
+	return count, nil
+}
+
+// FindAssetsByQuery retrieves up to limit assets matching a query, ordered by URL
+func (r *AssetRepository) FindAssetsByQuery(ctx context.Context, query *AssetQuery, limit int) ([]*Asset, error) {
+	where, args := query.where(2)
+
+	var assets []*Asset
+	err := r.db.SelectContext(ctx, &assets, selectAssets+` WHERE `+where+` ORDER BY a.url LIMIT $1`,
+		append([]any{limit}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assets by query: %w", err)
+	}
+
+	return assets, nil
+}
+
+// UpdateAssetsByQuery applies an update to every asset matching a query with
+// one statement per kind of change, in a single transaction
+func (r *AssetRepository) UpdateAssetsByQuery(ctx context.Context, query *AssetQuery, update *AssetUpdate) (*AssetUpdateResult, error) {
+	if update.Ignored == nil && (slices.Contains(update.AddTags, IgnoredTag) || slices.Contains(update.RemoveTags, IgnoredTag)) {
+		// Adding or removing the ignored tag sets the ignored flag as well
+		ignored := slices.Contains(update.AddTags, IgnoredTag)
+		changed := *update
+		changed.Ignored = &ignored
+		update = &changed
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	result := &AssetUpdateResult{}
+	where, args := query.where(1)
+	if err := tx.GetContext(ctx, &result.Matched, `SELECT COUNT(*) FROM assets a WHERE `+where, args...); err != nil {
+		return nil, fmt.Errorf("failed to count assets by query: %w", err)
+	}
+
+	if len(update.AddTags) > 0 {
+		where, args := query.where(3)
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO asset_tags (asset_id, tag, source, created_at)
+			SELECT a.id, t.tag, $1, NOW() FROM assets a CROSS JOIN unnest($2::text[]) AS t(tag)
+			WHERE `+where+`
+			ON CONFLICT (asset_id, tag) DO NOTHING
+		`, append([]any{update.TagSource, pq.Array(update.AddTags)}, args...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to tag assets: %w", err)
+		}
+		result.TagsAdded, _ = res.RowsAffected()
+	}
+
+	if len(update.RemoveTags) > 0 {
+		where, args := query.where(2)
+		res, err := tx.ExecContext(ctx, `
+			DELETE FROM asset_tags
+			WHERE tag = ANY($1) AND asset_id IN (SELECT a.id FROM assets a WHERE `+where+`)
+		`, append([]any{pq.Array(update.RemoveTags)}, args...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to untag assets: %w", err)
+		}
+		result.TagsRemoved, _ = res.RowsAffected()
+	}
+
+	if update.Status != "" || update.Ignored != nil {
+		where, args := query.where(3)
+		res, err := tx.ExecContext(ctx, `
+			UPDATE assets a SET
+				status = COALESCE(NULLIF($1, ''), a.status),
+				ignored = COALESCE($2, a.ignored),
+				updated_at = NOW()
+			WHERE `+where+`
+			AND (($1 <> '' AND a.status <> $1) OR ($2::boolean IS NOT NULL AND a.ignored <> $2))
+		`, append([]any{update.Status, update.Ignored}, args...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update assets: %w", err)
+		}
+		result.Updated, _ = res.RowsAffected()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssetQuery(t *testing.T) {
+	query, err := ParseAssetQuery("domain:*.old-acquisition.com  -Tag:keep ip:198.51.100.0/24")
+	require.NoError(t, err)
+	assert.Equal(t, []AssetQueryTerm{
+		{Field: "domain", Value: "*.old-acquisition.com"},
+		{Field: "tag", Value: "keep", Negate: true},
+		{Field: "ip", Value: "198.51.100.0/24"},
+	}, query.Terms)
+
+	invalid := map[string]string{
+		"":                    "empty",
+		"old-acquisition.com": "must be field:value",
+		"domain:":             "must be field:value",
+		"owner:web":           "unknown query field",
+		"ip:10.0.0.0/33":      "not an address",
+		"ignored:maybe":       "true or false",
+		"cluster:marketing":   "cluster ID or none",
+	}
+	for input, want := range invalid {
+		_, err := ParseAssetQuery(input)
+		assert.ErrorContains(t, err, want, input)
+	}
+}
+
+func TestAssetQuery_Where(t *testing.T) {
+	query, err := ParseAssetQuery("domain:*.old_acq.com program:acme -tag:keep ignored:false ip:203.0.113.7 redirect:loop")
+	require.NoError(t, err)
+
+	where, args := query.where(3)
+	assert.Equal(t, "split_part(a.host_key, ':', 1) ILIKE $3"+
+		" AND a.program_url ILIKE $4"+
+		" AND NOT COALESCE(EXISTS (SELECT 1 FROM asset_tags t WHERE t.asset_id = a.id AND t.tag ILIKE $5), false)"+
+		" AND a.ignored = $6"+
+		" AND EXISTS (SELECT 1 FROM hosts h WHERE h.id = a.host_id AND (NULLIF(h.ip, '')::inet <<= $7::inet OR NULLIF(h.ipv6, '')::inet <<= $7::inet))"+
+		" AND (SELECT r.redirect_status FROM asset_responses r WHERE r.host_id = a.host_id ORDER BY r.created_at DESC LIMIT 1) ILIKE $8", where)
+	assert.Equal(t, []any{`%.old\_acq.com`, "%/acme", "keep", false, "203.0.113.7", "loop"}, args)
+
+	query, err = ParseAssetQuery("cluster:6f1c2a9e-3d4b-4c5a-9e8f-7a6b5c4d3e2f -cluster:none")
+	require.NoError(t, err)
+	where, args = query.where(1)
+	assert.Equal(t, "a.cluster_id = $1::uuid AND NOT COALESCE(a.cluster_id IS NULL, false)", where)
+	assert.Equal(t, []any{"6f1c2a9e-3d4b-4c5a-9e8f-7a6b5c4d3e2f"}, args)
+}
+
+func TestAssetRepository_UpdateAssetsByQuery(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	query, err := ParseAssetQuery("domain:*.old-acquisition.com")
+	require.NoError(t, err)
+	ignored := true
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM assets a WHERE").
+		WithArgs("%.old-acquisition.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(40))
+	mock.ExpectExec("INSERT INTO asset_tags .* CROSS JOIN unnest").
+		WithArgs("manual", pq.Array([]string{"legacy"}), "%.old-acquisition.com").
+		WillReturnResult(sqlmock.NewResult(0, 38))
+	mock.ExpectExec("DELETE FROM asset_tags").
+		WithArgs(pq.Array([]string{"triage"}), "%.old-acquisition.com").
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec("UPDATE assets a SET").
+		WithArgs("", &ignored, "%.old-acquisition.com").
+		WillReturnResult(sqlmock.NewResult(0, 40))
+	mock.ExpectCommit()
+
+	result, err := repo.UpdateAssetsByQuery(context.Background(), query, &AssetUpdate{
+		AddTags:    []string{"legacy"},
+		RemoveTags: []string{"triage"},
+		TagSource:  "manual",
+		Ignored:    &ignored,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &AssetUpdateResult{Matched: 40, TagsAdded: 38, TagsRemoved: 5, Updated: 40}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_UpdateAssetsByQueryIgnoredTag(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	query, err := ParseAssetQuery("domain:*.old-acquisition.com")
+	require.NoError(t, err)
+	ignored := true
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM assets a WHERE").
+		WithArgs("%.old-acquisition.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec("INSERT INTO asset_tags").
+		WithArgs("manual", pq.Array([]string{IgnoredTag}), "%.old-acquisition.com").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("UPDATE assets a SET").
+		WithArgs("", &ignored, "%.old-acquisition.com").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	update := &AssetUpdate{AddTags: []string{IgnoredTag}, TagSource: "manual"}
+	result, err := repo.UpdateAssetsByQuery(context.Background(), query, update)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Updated, "the ignored tag sets the ignored flag")
+	assert.Nil(t, update.Ignored, "the caller's update is left as is")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_UpdateAssetsByQueryRollsBack(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	query, err := ParseAssetQuery("tag:legacy")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec("UPDATE assets a SET").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	_, err = repo.UpdateAssetsByQuery(context.Background(), query, &AssetUpdate{Status: "inactive"})
+	assert.ErrorContains(t, err, "failed to update assets")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// BenchRepository measures database write throughput for `monitor-agent bench`
+type BenchRepository struct {
+	*Repository
+}
+
+// NewBenchRepository creates a new bench repository
+func NewBenchRepository(db *sqlx.DB) *BenchRepository {
+	return &BenchRepository{Repository: NewRepository(db)}
+}
+
+// MeasureAssetUpserts upserts rows synthetic assets under a throwaway program
+// with the statement discovery writes assets with, and returns how long the
+// upserts took. Everything runs in one transaction that is rolled back, so the
+// database is left as it was; the commit is not measured.
+func (r *BenchRepository) MeasureAssetUpserts(ctx context.Context, rows int) (time.Duration, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			logrus.Errorf("Failed to rollback transaction: %v", err)
+		}
+	}()
+
+	program := &Program{
+		ID:          uuid.New(),
+		Name:        "monitor-agent bench",
+		Platform:    "bench",
+		URL:         "https://bench.invalid",
+		ProgramURL:  "https://bench.invalid/program",
+		IsActive:    false,
+		LastUpdated: time.Now(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if _, err := tx.NamedExecContext(ctx, `
+		INSERT INTO programs (id, name, platform, platform_id, url, program_url, is_active, last_updated, created_at, updated_at)
+		VALUES (:id, :name, :platform, :platform_id, :url, :program_url, :is_active, :last_updated, :created_at, :updated_at)
+	`, program); err != nil {
+		return 0, fmt.Errorf("failed to create bench program: %w", err)
+	}
+
+	stmt, err := tx.PrepareNamedContext(ctx, upsertAssetQuery)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare asset upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	start := time.Now()
+	for i := 0; i < rows; i++ {
+		host := fmt.Sprintf("host-%d.bench.invalid", i)
+		asset := &Asset{
+			ProgramID:  program.ID,
+			ProgramURL: program.ProgramURL,
+			URL:        "https://" + host,
+			Domain:     "bench.invalid",
+			Subdomain:  host,
+			Status:     "active",
+			Source:     "bench",
+		}
+		prepareAsset(asset)
+
+		if err := stmt.GetContext(ctx, &asset.ID, asset); err != nil {
+			return 0, fmt.Errorf("failed to upsert bench asset: %w", err)
+		}
+	}
+
+	return time.Since(start), nil
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchRepository_MeasureAssetUpserts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewBenchRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO programs").WillReturnResult(sqlmock.NewResult(0, 1))
+	prepared := mock.ExpectPrepare("INSERT INTO assets")
+	for i := 0; i < 3; i++ {
+		prepared.ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	}
+	mock.ExpectRollback()
+
+	elapsed, err := repo.MeasureAssetUpserts(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Positive(t, elapsed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
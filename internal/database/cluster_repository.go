@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// ClusterRepository handles response cluster database operations
+type ClusterRepository struct {
+	*Repository
+}
+
+// NewClusterRepository creates a new cluster repository
+func NewClusterRepository(db *sqlx.DB) *ClusterRepository {
+	return &ClusterRepository{Repository: NewRepository(db)}
+}
+
+// GetUnfingerprintedResponses retrieves up to limit latest GET responses of
+// live assets whose body fingerprint was not computed yet, e.g. responses
+// stored before fingerprints were
+func (r *ClusterRepository) GetUnfingerprintedResponses(ctx context.Context, limit int) ([]*ResponseBody, error) {
+	var bodies []*ResponseBody
+	query := `
+		SELECT r.id, r.body
+		FROM assets a
+		JOIN LATERAL (
+			SELECT id, body, body_simhash FROM asset_responses
+			WHERE host_id = a.host_id AND method = 'GET'
+			ORDER BY created_at DESC LIMIT 1
+		) r ON true
+		JOIN hosts h ON h.id = a.host_id
+		WHERE h.liveness = 'live' AND r.body_simhash IS NULL
+		LIMIT $1
+	`
+
+	err := r.db.SelectContext(ctx, &bodies, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unfingerprinted responses: %w", err)
+	}
+
+	return bodies, nil
+}
+
+// SetResponseFingerprints stores computed body fingerprints
+func (r *ClusterRepository) SetResponseFingerprints(ctx context.Context, fingerprints []ResponseFingerprint) error {
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(fingerprints))
+	hashes := make([]int64, len(fingerprints))
+	for i, fingerprint := range fingerprints {
+		ids[i] = fingerprint.ResponseID.String()
+		hashes[i] = fingerprint.Simhash
+	}
+
+	query := `
+		UPDATE asset_responses r SET body_simhash = f.simhash
+		FROM unnest($1::uuid[], $2::bigint[]) AS f(id, simhash)
+		WHERE r.id = f.id
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(ids), pq.Array(hashes)); err != nil {
+		return fmt.Errorf("failed to set response fingerprints: %w", err)
+	}
+
+	return nil
+}
+
+// GetClusterCandidates retrieves the live assets that are not ignored with
+// the body fingerprint of their latest GET response; HEAD responses have no
+// body. Assets whose latest body is empty (fingerprint 0) are left out.
+func (r *ClusterRepository) GetClusterCandidates(ctx context.Context) ([]*ClusterCandidate, error) {
+	var candidates []*ClusterCandidate
+	query := `
+		SELECT a.id AS asset_id, a.created_at, r.body_simhash, r.status_code
+		FROM assets a
+		JOIN LATERAL (
+			SELECT body_simhash, status_code FROM asset_responses
+			WHERE host_id = a.host_id AND method = 'GET'
+			ORDER BY created_at DESC LIMIT 1
+		) r ON true
+		JOIN hosts h ON h.id = a.host_id
+		WHERE h.liveness = 'live' AND NOT a.ignored AND r.body_simhash IS NOT NULL AND r.body_simhash <> 0
+		ORDER BY a.created_at, a.id
+	`
+
+	err := r.db.SelectContext(ctx, &candidates, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// ReplaceClusters replaces every cluster, and the cluster of every asset,
+// with the result of a clustering pass in one transaction. The assets'
+// updated_at is left alone, so clustering does not make assets look changed
+// to sync.
+func (r *ClusterRepository) ReplaceClusters(ctx context.Context, clusters []*ResponseCluster) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE assets SET cluster_id = NULL WHERE cluster_id IS NOT NULL`); err != nil {
+		return fmt.Errorf("failed to clear asset clusters: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM response_clusters`); err != nil {
+		return fmt.Errorf("failed to delete response clusters: %w", err)
+	}
+
+	if len(clusters) > 0 {
+		var (
+			ids, representatives []string
+			hashes               []int64
+			sizes, statusCodes   []int64
+			memberIDs, memberOf  []string
+		)
+		for _, cluster := range clusters {
+			ids = append(ids, cluster.ID.String())
+			representatives = append(representatives, cluster.RepresentativeAssetID.String())
+			hashes = append(hashes, cluster.Simhash)
+			sizes = append(sizes, int64(cluster.Size))
+			statusCodes = append(statusCodes, int64(cluster.StatusCode))
+			for _, assetID := range cluster.AssetIDs {
+				memberIDs = append(memberIDs, assetID.String())
+				memberOf = append(memberOf, cluster.ID.String())
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO response_clusters (id, representative_asset_id, simhash, size, status_code, created_at)
+			SELECT c.id, c.representative_asset_id, c.simhash, c.size, c.status_code, NOW()
+			FROM unnest($1::uuid[], $2::uuid[], $3::bigint[], $4::int[], $5::int[])
+				AS c(id, representative_asset_id, simhash, size, status_code)
+		`, pq.Array(ids), pq.Array(representatives), pq.Array(hashes), pq.Array(sizes), pq.Array(statusCodes))
+		if err != nil {
+			return fmt.Errorf("failed to insert response clusters: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE assets a SET cluster_id = m.cluster_id
+			FROM unnest($1::uuid[], $2::uuid[]) AS m(asset_id, cluster_id)
+			WHERE a.id = m.asset_id
+		`, pq.Array(memberIDs), pq.Array(memberOf))
+		if err != nil {
+			return fmt.Errorf("failed to set asset clusters: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return nil
+}
+
+// GetClusters retrieves up to limit clusters of at least minSize assets,
+// largest first
+func (r *ClusterRepository) GetClusters(ctx context.Context, minSize, limit int) ([]*ResponseClusterSummary, error) {
+	var clusters []*ResponseClusterSummary
+	query := `
+		SELECT c.*, a.url AS representative_url,
+			(SELECT COUNT(DISTINCT m.program_id) FROM assets m WHERE m.cluster_id = c.id) AS programs
+		FROM response_clusters c
+		JOIN assets a ON a.id = c.representative_asset_id
+		WHERE c.size >= $1
+		ORDER BY c.size DESC, a.url
+		LIMIT $2
+	`
+
+	err := r.db.SelectContext(ctx, &clusters, query, minSize, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response clusters: %w", err)
+	}
+
+	return clusters, nil
+}
+
+// GetCluster retrieves a cluster by ID, or nil when it does not exist
+func (r *ClusterRepository) GetCluster(ctx context.Context, id uuid.UUID) (*ResponseClusterSummary, error) {
+	var cluster ResponseClusterSummary
+	query := `
+		SELECT c.*, a.url AS representative_url,
+			(SELECT COUNT(DISTINCT m.program_id) FROM assets m WHERE m.cluster_id = c.id) AS programs
+		FROM response_clusters c
+		JOIN assets a ON a.id = c.representative_asset_id
+		WHERE c.id = $1
+	`
+
+	err := r.db.GetContext(ctx, &cluster, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get response cluster: %w", err)
+	}
+
+	return &cluster, nil
+}
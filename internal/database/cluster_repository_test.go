@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterRepository_ReplaceClusters(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewClusterRepository(db)
+	clusterID, representative, member := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE assets SET cluster_id = NULL").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM response_clusters").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO response_clusters").
+		WithArgs(pq.Array([]string{clusterID.String()}), pq.Array([]string{representative.String()}),
+			pq.Array([]int64{-42}), pq.Array([]int64{2}), pq.Array([]int64{200})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE assets a SET cluster_id = m.cluster_id").
+		WithArgs(pq.Array([]string{representative.String(), member.String()}), pq.Array([]string{clusterID.String(), clusterID.String()})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.ReplaceClusters(context.Background(), []*ResponseCluster{{
+		ID: clusterID, RepresentativeAssetID: representative, Simhash: -42, Size: 2, StatusCode: 200,
+		AssetIDs: []uuid.UUID{representative, member},
+	}})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClusterRepository_ReplaceClustersRollsBack(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE assets SET cluster_id = NULL").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM response_clusters").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	err := NewClusterRepository(db).ReplaceClusters(context.Background(), nil)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
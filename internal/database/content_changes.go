@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ContentChangeCount is the number of a program's assets whose content
+// changed since its latest scan started
+type ContentChangeCount struct {
+	ProgramName string `db:"program_name" json:"program_name"`
+	Platform    string `db:"platform" json:"platform"`
+	Assets      int    `db:"assets" json:"assets"`
+}
+
+// GetLatestResponseHashes gets the hashes of the latest hashed response
+// captured from the host of each of the given assets; assets without one are
+// left out
+func (r *AssetRepository) GetLatestResponseHashes(ctx context.Context, assetIDs []uuid.UUID) (map[uuid.UUID]*ResponseHashes, error) {
+	defer r.observe("select", TableAssetResponses, time.Now())
+
+	hashes := make(map[uuid.UUID]*ResponseHashes, len(assetIDs))
+	if len(assetIDs) == 0 {
+		return hashes, nil
+	}
+
+	var rows []*ResponseHashes
+	query := `
+		SELECT DISTINCT ON (a.id) a.id AS asset_id, r.body_hash, r.header_hash, r.body_simhash
+		FROM assets a JOIN asset_responses r ON r.host_id = a.host_id
+		WHERE a.id = ANY($1) AND r.body_hash <> ''
+		ORDER BY a.id, r.created_at DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(assetIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get latest response hashes: %w", err)
+	}
+
+	for _, row := range rows {
+		hashes[row.AssetID] = row
+	}
+	return hashes, nil
+}
+
+// UpdateAssetContent sets the content hash of an asset's host, and marks its
+// content as changed now when changed is set
+func (r *AssetRepository) UpdateAssetContent(ctx context.Context, assetID uuid.UUID, contentHash string, changed bool) error {
+	defer r.observe("update", TableAssets, time.Now())
+
+	query := `
+		UPDATE hosts
+		SET content_hash = $2, content_changed_at = CASE WHEN $3 THEN NOW() ELSE content_changed_at END
+		WHERE id = (SELECT host_id FROM assets WHERE id = $1)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, assetID, contentHash, changed); err != nil {
+		return fmt.Errorf("failed to update asset content: %w", err)
+	}
+	return nil
+}
+
+// GetContentChangeCounts gets the programs with the most assets whose content
+// changed since the program's latest scan started
+func (r *AssetRepository) GetContentChangeCounts(ctx context.Context, limit int) ([]*ContentChangeCount, error) {
+	var counts []*ContentChangeCount
+	query := `
+		SELECT p.name AS program_name, p.platform, COUNT(*) AS assets
+		FROM assets a
+		JOIN hosts h ON h.id = a.host_id
+		JOIN programs p ON p.id = a.program_id
+		WHERE h.content_changed_at >= (SELECT MAX(s.started_at) FROM scans s WHERE s.program_id = a.program_id)
+		GROUP BY p.name, p.platform
+		ORDER BY assets DESC, p.name
+		LIMIT $1
+	`
+
+	if err := r.db.SelectContext(ctx, &counts, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get content change counts: %w", err)
+	}
+	return counts, nil
+}
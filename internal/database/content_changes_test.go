@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetRepository_GetLatestResponseHashes(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	hashed, unhashed := uuid.New(), uuid.New()
+	simhash := int64(42)
+
+	mock.ExpectQuery("SELECT DISTINCT ON \\(a.id\\) a.id AS asset_id, r.body_hash, r.header_hash, r.body_simhash\\s+FROM assets a JOIN asset_responses r ON r.host_id = a.host_id\\s+WHERE a.id = ANY\\(\\$1\\) AND r.body_hash <> ''").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"asset_id", "body_hash", "header_hash", "body_simhash"}).
+			AddRow(hashed, "body", "headers", simhash))
+
+	hashes, err := repo.GetLatestResponseHashes(context.Background(), []uuid.UUID{hashed, unhashed})
+	require.NoError(t, err)
+	require.Len(t, hashes, 1)
+	assert.Equal(t, "body", hashes[hashed].BodyHash)
+	assert.Equal(t, &simhash, hashes[hashed].BodySimhash)
+	assert.NotContains(t, hashes, unhashed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Nothing is queried without assets
+	hashes, err = repo.GetLatestResponseHashes(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, hashes)
+}
+
+func TestAssetRepository_UpdateAssetContent(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	assetID := uuid.New()
+
+	mock.ExpectExec("UPDATE hosts\\s+SET content_hash = \\$2, content_changed_at = CASE WHEN \\$3 THEN NOW\\(\\) ELSE content_changed_at END\\s+WHERE id = \\(SELECT host_id FROM assets WHERE id = \\$1\\)").
+		WithArgs(assetID, "hash", true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.UpdateAssetContent(context.Background(), assetID, "hash", true))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_GetContentChangeCounts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+
+	mock.ExpectQuery("WHERE h.content_changed_at >= \\(SELECT MAX\\(s.started_at\\) FROM scans s WHERE s.program_id = a.program_id\\)").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"program_name", "platform", "assets"}).
+			AddRow("Acme", "hackerone", 3))
+
+	counts, err := repo.GetContentChangeCounts(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*ContentChangeCount{{ProgramName: "Acme", Platform: "hackerone", Assets: 3}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ContinuationRepository handles program continuation database operations
+type ContinuationRepository struct {
+	*Repository
+}
+
+// NewContinuationRepository creates a new continuation repository
+func NewContinuationRepository(db *sqlx.DB) *ContinuationRepository {
+	return &ContinuationRepository{Repository: NewRepository(db)}
+}
+
+// GetContinuation retrieves a program's pending continuation, or nil if it has none
+func (r *ContinuationRepository) GetContinuation(ctx context.Context, programID uuid.UUID) (*ProgramContinuation, error) {
+	var continuation ProgramContinuation
+	query := `SELECT * FROM program_continuations WHERE program_id = $1`
+
+	err := r.db.GetContext(ctx, &continuation, query, programID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get program continuation: %w", err)
+	}
+
+	return &continuation, nil
+}
+
+// SaveContinuation records where a program stopped. Attempts counts how many
+// times in a row the program stopped on the same domain.
+func (r *ContinuationRepository) SaveContinuation(ctx context.Context, continuation *ProgramContinuation) error {
+	query := `
+		INSERT INTO program_continuations (program_id, scan_id, stage, domain, remaining_domains, attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 1, NOW(), NOW())
+		ON CONFLICT (program_id) DO UPDATE SET
+			scan_id = EXCLUDED.scan_id,
+			stage = EXCLUDED.stage,
+			domain = EXCLUDED.domain,
+			remaining_domains = EXCLUDED.remaining_domains,
+			attempts = CASE WHEN program_continuations.domain = EXCLUDED.domain
+				THEN program_continuations.attempts + 1 ELSE 1 END,
+			updated_at = NOW()
+		RETURNING attempts, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, continuation.ProgramID, continuation.ScanID, continuation.Stage,
+		continuation.Domain, continuation.RemainingDomains).
+		Scan(&continuation.Attempts, &continuation.CreatedAt, &continuation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save program continuation: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteContinuation removes a program's continuation once its remaining domains were processed
+func (r *ContinuationRepository) DeleteContinuation(ctx context.Context, programID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM program_continuations WHERE program_id = $1`, programID)
+	if err != nil {
+		return fmt.Errorf("failed to delete program continuation: %w", err)
+	}
+
+	return nil
+}
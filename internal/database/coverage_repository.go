@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// CoverageRepository handles scan coverage database operations
+type CoverageRepository struct {
+	*Repository
+}
+
+// NewCoverageRepository creates a new coverage repository
+func NewCoverageRepository(db *sqlx.DB) *CoverageRepository {
+	return &CoverageRepository{Repository: NewRepository(db)}
+}
+
+// recentCoverageScans selects the last $1 scans with coverage of each program
+const recentCoverageScans = `
+	WITH recent AS (
+		SELECT id FROM (
+			SELECT s.id, ROW_NUMBER() OVER (PARTITION BY s.program_id ORDER BY s.started_at DESC) AS n
+			FROM scans s
+			WHERE EXISTS (SELECT 1 FROM scan_coverage c WHERE c.scan_id = s.id)
+		) ranked
+		WHERE n <= $1
+	)
+`
+
+// SaveCoverage records the coverage of a scope domain in a scan. A domain
+// processed twice in one scan, e.g. by a continuation, keeps its last counts.
+func (r *CoverageRepository) SaveCoverage(ctx context.Context, coverage *ScanCoverage) error {
+	query := `
+		INSERT INTO scan_coverage (scan_id, program_id, domain, discovered, valid, probed, resolved, live, probe_error, recorded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		ON CONFLICT (scan_id, domain) DO UPDATE SET
+			discovered = EXCLUDED.discovered,
+			valid = EXCLUDED.valid,
+			probed = EXCLUDED.probed,
+			resolved = EXCLUDED.resolved,
+			live = EXCLUDED.live,
+			probe_error = EXCLUDED.probe_error,
+			recorded_at = EXCLUDED.recorded_at
+		RETURNING recorded_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, coverage.ScanID, coverage.ProgramID, coverage.Domain,
+		coverage.Discovered, coverage.Valid, coverage.Probed, coverage.Resolved, coverage.Live, coverage.ProbeError).
+		Scan(&coverage.RecordedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save scan coverage: %w", err)
+	}
+
+	return nil
+}
+
+// GetProgramCoverage sums the coverage of each program over its last scans,
+// optionally for one program, lowest share of probed hosts first
+func (r *CoverageRepository) GetProgramCoverage(ctx context.Context, programID *uuid.UUID, scans int) ([]*CoverageSummary, error) {
+	query := recentCoverageScans + `
+		SELECT p.id AS program_id, p.name AS program_name, p.platform,
+			COUNT(DISTINCT c.scan_id) AS scans,
+			SUM(c.discovered) AS discovered,
+			SUM(c.valid) AS valid,
+			SUM(c.probed) AS probed,
+			SUM(c.resolved) AS resolved,
+			SUM(c.live) AS live,
+			COUNT(DISTINCT c.scan_id) FILTER (WHERE c.probed < c.valid) AS gap_scans
+		FROM scan_coverage c
+		JOIN recent ON recent.id = c.scan_id
+		JOIN programs p ON p.id = c.program_id
+		WHERE $2::uuid IS NULL OR c.program_id = $2
+		GROUP BY p.id, p.name, p.platform
+		ORDER BY SUM(c.probed)::float / NULLIF(SUM(c.valid), 0) ASC NULLS LAST, p.name
+	`
+
+	var summaries []*CoverageSummary
+	if err := r.db.SelectContext(ctx, &summaries, query, scans, programID); err != nil {
+		return nil, fmt.Errorf("failed to get program coverage: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// GetDomainCoverage sums the coverage of each scope domain of a program over
+// the program's last scans, domains with the most scans short of results first
+func (r *CoverageRepository) GetDomainCoverage(ctx context.Context, programID uuid.UUID, scans int) ([]*CoverageSummary, error) {
+	query := recentCoverageScans + `
+		SELECT p.id AS program_id, p.name AS program_name, p.platform, c.domain,
+			COUNT(*) AS scans,
+			SUM(c.discovered) AS discovered,
+			SUM(c.valid) AS valid,
+			SUM(c.probed) AS probed,
+			SUM(c.resolved) AS resolved,
+			SUM(c.live) AS live,
+			COUNT(*) FILTER (WHERE c.probed < c.valid) AS gap_scans
+		FROM scan_coverage c
+		JOIN recent ON recent.id = c.scan_id
+		JOIN programs p ON p.id = c.program_id
+		WHERE c.program_id = $2
+		GROUP BY p.id, p.name, p.platform, c.domain
+		ORDER BY gap_scans DESC, SUM(c.valid) - SUM(c.probed) DESC, c.domain
+	`
+
+	var summaries []*CoverageSummary
+	if err := r.db.SelectContext(ctx, &summaries, query, scans, programID); err != nil {
+		return nil, fmt.Errorf("failed to get domain coverage: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageRepository_SaveCoverage(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewCoverageRepository(db)
+	coverage := &ScanCoverage{
+		ScanID:     uuid.New(),
+		ProgramID:  uuid.New(),
+		Domain:     "example.com",
+		Discovered: 120,
+		Valid:      118,
+		Probed:     90,
+		Resolved:   80,
+		Live:       40,
+	}
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO scan_coverage").
+		WithArgs(coverage.ScanID, coverage.ProgramID, "example.com", 120, 118, 90, 80, 40, "").
+		WillReturnRows(sqlmock.NewRows([]string{"recorded_at"}).AddRow(now))
+
+	require.NoError(t, repo.SaveCoverage(context.Background(), coverage))
+	assert.Equal(t, now, coverage.RecordedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCoverageRepository_GetProgramCoverage(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewCoverageRepository(db)
+	programID := uuid.New()
+
+	mock.ExpectQuery("WITH recent AS").
+		WithArgs(5, &programID).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "program_name", "platform", "scans", "discovered", "valid", "probed", "resolved", "live", "gap_scans"}).
+			AddRow(programID, "Acme", "hackerone", 3, 300, 297, 210, 200, 150, 3))
+
+	summaries, err := repo.GetProgramCoverage(context.Background(), &programID, 5)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 210, summaries[0].Probed)
+	assert.True(t, summaries[0].SystematicGap())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCoverageSummary_SystematicGap(t *testing.T) {
+	assert.True(t, (&CoverageSummary{Scans: 4, GapScans: 4}).SystematicGap())
+	assert.False(t, (&CoverageSummary{Scans: 4, GapScans: 3}).SystematicGap())
+	// A single short scan is not a pattern yet
+	assert.False(t, (&CoverageSummary{Scans: 1, GapScans: 1}).SystematicGap())
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// DefectDojoRepository handles DefectDojo export database operations
+type DefectDojoRepository struct {
+	*Repository
+}
+
+// NewDefectDojoRepository creates a new DefectDojo export repository
+func NewDefectDojoRepository(db *sqlx.DB) *DefectDojoRepository {
+	return &DefectDojoRepository{Repository: NewRepository(db)}
+}
+
+// GetUnexportedScans retrieves up to limit finished scans that have not been
+// exported yet, oldest first, optionally only those of one program. Timed out
+// scans are included since what they found was saved.
+func (r *DefectDojoRepository) GetUnexportedScans(ctx context.Context, programID *uuid.UUID, limit int) ([]*Scan, error) {
+	var scans []*Scan
+	query := `
+		SELECT s.* FROM scans s
+		WHERE s.status IN ('completed', 'timed_out')
+		  AND s.completed_at IS NOT NULL
+		  AND ($1::uuid IS NULL OR s.program_id = $1)
+		  AND NOT EXISTS (SELECT 1 FROM defectdojo_exports e WHERE e.scan_id = s.id)
+		ORDER BY s.started_at
+		LIMIT $2
+	`
+
+	err := r.db.SelectContext(ctx, &scans, query, programID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unexported scans: %w", err)
+	}
+
+	return scans, nil
+}
+
+// HasProgramExport reports whether any scan of a program has been exported
+func (r *DefectDojoRepository) HasProgramExport(ctx context.Context, programID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM defectdojo_exports WHERE program_id = $1)`
+
+	err := r.db.GetContext(ctx, &exists, query, programID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check DefectDojo exports: %w", err)
+	}
+
+	return exists, nil
+}
+
+// SaveExport records an exported scan
+func (r *DefectDojoRepository) SaveExport(ctx context.Context, export *DefectDojoExport) error {
+	query := `
+		INSERT INTO defectdojo_exports (scan_id, program_id, product_id, engagement_id, endpoints, findings, exported_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (scan_id) DO UPDATE SET
+			product_id = EXCLUDED.product_id,
+			engagement_id = EXCLUDED.engagement_id,
+			endpoints = EXCLUDED.endpoints,
+			findings = EXCLUDED.findings,
+			exported_at = EXCLUDED.exported_at
+		RETURNING exported_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, export.ScanID, export.ProgramID, export.ProductID,
+		export.EngagementID, export.Endpoints, export.Findings).Scan(&export.ExportedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save DefectDojo export: %w", err)
+	}
+
+	return nil
+}
+
+// GetProgramTLSFindings retrieves the open TLS findings of a program's assets
+func (r *DefectDojoRepository) GetProgramTLSFindings(ctx context.Context, programID uuid.UUID) ([]*TLSFinding, error) {
+	var findings []*TLSFinding
+	query := `
+		SELECT f.* FROM tls_findings f
+		JOIN assets a ON a.id = f.asset_id
+		WHERE a.program_id = $1 AND f.resolved_at IS NULL
+		ORDER BY f.first_seen
+	`
+
+	err := r.db.SelectContext(ctx, &findings, query, programID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program TLS findings: %w", err)
+	}
+
+	return findings, nil
+}
+
+// GetProgramRuleMatches retrieves the rule matches on a program's assets
+// recorded between from and to
+func (r *DefectDojoRepository) GetProgramRuleMatches(ctx context.Context, programID uuid.UUID, from, to time.Time) ([]*RuleMatch, error) {
+	var matches []*RuleMatch
+	query := `
+		SELECT m.* FROM rule_matches m
+		JOIN assets a ON a.id = m.asset_id
+		WHERE a.program_id = $1 AND m.created_at BETWEEN $2 AND $3
+		ORDER BY m.created_at
+	`
+
+	err := r.db.SelectContext(ctx, &matches, query, programID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program rule matches: %w", err)
+	}
+
+	return matches, nil
+}
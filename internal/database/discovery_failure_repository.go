@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// DiscoveryFailureRepository handles the discovery failures waiting to be retried
+type DiscoveryFailureRepository struct {
+	*Repository
+}
+
+// NewDiscoveryFailureRepository creates a new discovery failure repository
+func NewDiscoveryFailureRepository(db *sqlx.DB) *DiscoveryFailureRepository {
+	return &DiscoveryFailureRepository{Repository: NewRepository(db)}
+}
+
+// RecordFailure records that a domain failed again. Its next retry is due
+// backoff after the first failure, twice as long after each further one,
+// and never more than maxBackoff later.
+func (r *DiscoveryFailureRepository) RecordFailure(ctx context.Context, failure *DiscoveryFailure, backoff, maxBackoff time.Duration) error {
+	query := `
+		INSERT INTO discovery_failures (program_id, domain, stage, source, error, attempts, first_failed_at, last_failed_at, next_retry_at)
+		VALUES ($1, $2, $3, $4, $5, 1, NOW(), NOW(), NOW() + LEAST($6::float8, $7::float8) * INTERVAL '1 second')
+		ON CONFLICT (program_id, domain) DO UPDATE SET
+			stage = EXCLUDED.stage,
+			source = EXCLUDED.source,
+			error = EXCLUDED.error,
+			attempts = discovery_failures.attempts + 1,
+			last_failed_at = EXCLUDED.last_failed_at,
+			next_retry_at = EXCLUDED.last_failed_at + LEAST($6::float8 * POWER(2, discovery_failures.attempts), $7::float8) * INTERVAL '1 second'
+		RETURNING attempts, first_failed_at, last_failed_at, next_retry_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, failure.ProgramID, failure.Domain, failure.Stage, failure.Source, failure.Error,
+		backoff.Seconds(), maxBackoff.Seconds()).
+		Scan(&failure.Attempts, &failure.FirstFailedAt, &failure.LastFailedAt, &failure.NextRetryAt)
+	if err != nil {
+		return fmt.Errorf("failed to record discovery failure: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveFailure removes a domain's failure once it was processed without errors
+func (r *DiscoveryFailureRepository) ResolveFailure(ctx context.Context, programID uuid.UUID, domain string) error {
+	query := `DELETE FROM discovery_failures WHERE program_id = $1 AND domain = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, programID, domain); err != nil {
+		return fmt.Errorf("failed to resolve discovery failure: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueFailures retrieves the failures of active programs whose retry is
+// due and that failed fewer than maxAttempts times, by program
+func (r *DiscoveryFailureRepository) GetDueFailures(ctx context.Context, maxAttempts int) ([]*DiscoveryFailure, error) {
+	var failures []*DiscoveryFailure
+	query := `
+		SELECT f.*, p.name AS program_name, p.program_url
+		FROM discovery_failures f
+		JOIN programs p ON p.id = f.program_id
+		WHERE p.is_active AND f.next_retry_at <= NOW() AND f.attempts < $1
+		ORDER BY p.name, f.domain
+	`
+
+	if err := r.db.SelectContext(ctx, &failures, query, maxAttempts); err != nil {
+		return nil, fmt.Errorf("failed to get due discovery failures: %w", err)
+	}
+
+	return failures, nil
+}
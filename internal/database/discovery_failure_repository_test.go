@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveryFailureRepository_RecordFailure(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewDiscoveryFailureRepository(db)
+	failure := &DiscoveryFailure{
+		ProgramID: uuid.New(),
+		Domain:    "example.com",
+		Stage:     DiscoveryStageProbe,
+		Source:    "httpx",
+		Error:     "context deadline exceeded",
+	}
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO discovery_failures").
+		WithArgs(failure.ProgramID, "example.com", "probe", "httpx", "context deadline exceeded", 1800.0, 86400.0).
+		WillReturnRows(sqlmock.NewRows([]string{"attempts", "first_failed_at", "last_failed_at", "next_retry_at"}).
+			AddRow(2, now.Add(-time.Hour), now, now.Add(time.Hour)))
+
+	require.NoError(t, repo.RecordFailure(context.Background(), failure, 30*time.Minute, 24*time.Hour))
+	assert.Equal(t, 2, failure.Attempts)
+	assert.Equal(t, now.Add(time.Hour), failure.NextRetryAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDiscoveryFailureRepository_ResolveFailure(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewDiscoveryFailureRepository(db)
+	programID := uuid.New()
+
+	mock.ExpectExec("DELETE FROM discovery_failures").
+		WithArgs(programID, "example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.ResolveFailure(context.Background(), programID, "example.com"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDiscoveryFailureRepository_GetDueFailures(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewDiscoveryFailureRepository(db)
+	programID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("FROM discovery_failures f").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "domain", "stage", "source", "error", "attempts", "first_failed_at", "last_failed_at", "next_retry_at", "program_name", "program_url"}).
+			AddRow(programID, "example.com", "discovery", "chaosdb", "HTTP 502", 1, now, now, now, "Acme", "https://hackerone.com/acme"))
+
+	failures, err := repo.GetDueFailures(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "chaosdb", failures[0].Source)
+	assert.Equal(t, "https://hackerone.com/acme", failures[0].ProgramURL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// DNSRepository handles the DNS resolution of asset hostnames
+type DNSRepository struct {
+	*Repository
+}
+
+// NewDNSRepository creates a new DNS repository
+func NewDNSRepository(db *sqlx.DB) *DNSRepository {
+	return &DNSRepository{Repository: NewRepository(db)}
+}
+
+// GetAssetsToResolve retrieves the assets of a program whose hostnames are
+// resolved, leaving out ignored assets
+func (r *DNSRepository) GetAssetsToResolve(ctx context.Context, programID uuid.UUID) ([]*AssetHost, error) {
+	var assets []*AssetHost
+	query := `SELECT id, url FROM assets WHERE program_id = $1 AND NOT ignored ORDER BY url`
+
+	if err := r.db.SelectContext(ctx, &assets, query, programID); err != nil {
+		return nil, fmt.Errorf("failed to get assets to resolve: %w", err)
+	}
+
+	return assets, nil
+}
+
+// SaveResolution records what the hostname shared by some assets resolved to
+// on their hosts
+func (r *DNSRepository) SaveResolution(ctx context.Context, assetIDs []uuid.UUID, resolution *AssetResolution) error {
+	cnames := pq.StringArray(resolution.CNAMEs)
+	if cnames == nil {
+		cnames = pq.StringArray{}
+	}
+
+	query := `
+		UPDATE hosts SET
+			ip = CASE WHEN $5 THEN ip ELSE $2 END,
+			ipv6 = CASE WHEN $5 THEN ipv6 ELSE $3 END,
+			cnames = $4,
+			dns_dead = $5,
+			resolved_at = NOW()
+		WHERE id IN (SELECT host_id FROM assets WHERE id = ANY($1))
+	`
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(assetIDs), resolution.IP, resolution.IPv6, cnames, resolution.DNSDead)
+	if err != nil {
+		return fmt.Errorf("failed to save DNS resolution: %w", err)
+	}
+
+	return nil
+}
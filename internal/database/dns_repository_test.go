@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSRepository_GetAssetsToResolve(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewDNSRepository(db)
+	programID := uuid.New()
+	assetID := uuid.New()
+
+	mock.ExpectQuery("SELECT id, url FROM assets WHERE program_id = \\$1 AND NOT ignored").WithArgs(programID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url"}).AddRow(assetID, "https://api.acme.com"))
+
+	assets, err := repo.GetAssetsToResolve(context.Background(), programID)
+	require.NoError(t, err)
+	assert.Equal(t, []*AssetHost{{ID: assetID, URL: "https://api.acme.com"}}, assets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDNSRepository_SaveResolution(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewDNSRepository(db)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	// The resolution is stored on the hosts of the assets
+	mock.ExpectExec("UPDATE hosts SET .* WHERE id IN \\(SELECT host_id FROM assets WHERE id = ANY\\(\\$1\\)\\)").
+		WithArgs(pq.Array(ids), "198.51.100.7", "", pq.StringArray{"acme.cdn.example"}, false).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE hosts SET").
+		WithArgs(pq.Array(ids), "", "", pq.StringArray{}, true).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	require.NoError(t, repo.SaveResolution(context.Background(), ids, &AssetResolution{IP: "198.51.100.7", CNAMEs: []string{"acme.cdn.example"}}))
+	require.NoError(t, repo.SaveResolution(context.Background(), ids, &AssetResolution{DNSDead: true}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExportRepository handles the queries behind the asset export
+type ExportRepository struct {
+	*Repository
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(db *sqlx.DB) *ExportRepository {
+	return &ExportRepository{Repository: NewRepository(db)}
+}
+
+// GetExportAssets retrieves the assets a filter selects, ordered by program
+// and URL. Ignored and quarantined assets are never exported.
+func (r *ExportRepository) GetExportAssets(ctx context.Context, filter *ExportFilter) ([]*ExportAsset, error) {
+	conditions := []string{"NOT a.ignored", "a.status <> $1"}
+	args := []interface{}{AssetStatusQuarantined}
+
+	if filter.ProgramID != nil {
+		args = append(args, *filter.ProgramID)
+		conditions = append(conditions, fmt.Sprintf("a.program_id = $%d", len(args)))
+	} else {
+		conditions = append(conditions, "p.is_active")
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		conditions = append(conditions, fmt.Sprintf("a.source = $%d", len(args)))
+	}
+	if !filter.IncludeInactive {
+		conditions = append(conditions, "a.status = 'active'")
+	}
+	if filter.ContentChanged {
+		conditions = append(conditions, "h.content_changed_at >= (SELECT MAX(s.started_at) FROM scans s WHERE s.program_id = a.program_id)")
+	}
+
+	columns, join := "", ""
+	if filter.Responses {
+		columns = ", r.status_code, r.final_url, r.response_time, r.created_at AS responded_at"
+		join = `
+		LEFT JOIN LATERAL (
+			SELECT status_code, final_url, response_time, created_at FROM asset_responses
+			WHERE host_id = a.host_id
+			ORDER BY created_at DESC LIMIT 1
+		) r ON true`
+	}
+
+	query := `
+		SELECT a.*, ` + hostStateColumns + `, p.name AS program_name` + columns + `
+		FROM assets a
+		JOIN hosts h ON h.id = a.host_id
+		JOIN programs p ON p.id = a.program_id` + join + `
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY p.name, a.url
+	`
+
+	var assets []*ExportAsset
+	if err := r.db.SelectContext(ctx, &assets, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get assets to export: %w", err)
+	}
+
+	return assets, nil
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRepository_GetExportAssets(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewExportRepository(db)
+	programID, assetID := uuid.New(), uuid.New()
+	responded := time.Now()
+
+	mock.ExpectQuery("SELECT a.\\*, h.ip, .*, h.content_changed_at, p.name AS program_name, r.status_code, r.final_url, r.response_time, r.created_at AS responded_at").
+		WithArgs(AssetStatusQuarantined, programID, "primary").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "url", "source", "program_name", "status_code", "final_url", "response_time", "responded_at"}).
+			AddRow(assetID, programID, "https://www.example.com", "primary", "Acme", 200, "https://www.example.com/login", 120, responded).
+			AddRow(uuid.New(), programID, "https://new.example.com", "primary", "Acme", nil, nil, nil, nil))
+
+	assets, err := repo.GetExportAssets(context.Background(), &ExportFilter{ProgramID: &programID, Source: "primary", Responses: true})
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	assert.Equal(t, assetID, assets[0].ID)
+	assert.Equal(t, "Acme", assets[0].ProgramName)
+	require.NotNil(t, assets[0].StatusCode)
+	assert.Equal(t, 200, *assets[0].StatusCode)
+	assert.Equal(t, "https://www.example.com/login", *assets[0].FinalURL)
+	assert.Nil(t, assets[1].StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRepository_GetExportAssetsOfActivePrograms(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewExportRepository(db)
+
+	// Without responses the latest response is not joined
+	mock.ExpectQuery("SELECT a.\\*, h.ip, .*, h.content_changed_at, p.name AS program_name\\s+FROM assets a\\s+JOIN hosts h ON h.id = a.host_id\\s+JOIN programs p ON p.id = a.program_id\\s+WHERE NOT a.ignored AND a.status <> \\$1 AND p.is_active AND a.status = 'active'").
+		WithArgs(AssetStatusQuarantined).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "program_name"}).
+			AddRow(uuid.New(), "https://www.example.com", "Acme"))
+
+	assets, err := repo.GetExportAssets(context.Background(), &ExportFilter{})
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Nil(t, assets[0].StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRepository_GetExportAssetsContentChanged(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewExportRepository(db)
+
+	mock.ExpectQuery("a.status = 'active' AND h.content_changed_at >= \\(SELECT MAX\\(s.started_at\\) FROM scans s WHERE s.program_id = a.program_id\\)").
+		WithArgs(AssetStatusQuarantined).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "program_name", "content_changed_at"}).
+			AddRow(uuid.New(), "https://www.example.com", "Acme", time.Now()))
+
+	assets, err := repo.GetExportAssets(context.Background(), &ExportFilter{ContentChanged: true})
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.NotNil(t, assets[0].ContentChangedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// FreshnessRepository handles the queries behind the scan freshness SLOs
+type FreshnessRepository struct {
+	*Repository
+}
+
+// NewFreshnessRepository creates a new freshness repository
+func NewFreshnessRepository(db *sqlx.DB) *FreshnessRepository {
+	return &FreshnessRepository{Repository: NewRepository(db)}
+}
+
+// GetProgramFreshness returns the last completed scan of every active
+// program and how many of its assets were not probed since probedSince,
+// never-scanned and least recently scanned programs first. Assets are counted
+// the way the liveness sweep selects them: ignored and quarantined assets are
+// left out.
+func (r *FreshnessRepository) GetProgramFreshness(ctx context.Context, probedSince time.Time) ([]*ProgramFreshness, error) {
+	var freshness []*ProgramFreshness
+	query := `
+		SELECT p.id AS program_id, p.name AS program_name, p.platform, p.program_url,
+			(SELECT MAX(s.completed_at) FROM scans s WHERE s.program_id = p.id AND s.status = 'completed') AS last_scan_at,
+			COUNT(a.id) AS assets,
+			COUNT(a.id) FILTER (WHERE h.last_probed_at IS NULL OR h.last_probed_at < $1) AS stale_assets
+		FROM programs p
+		LEFT JOIN assets a ON a.program_id = p.id AND NOT a.ignored AND a.status <> 'quarantined'
+		LEFT JOIN hosts h ON h.id = a.host_id
+		WHERE p.is_active = true
+		GROUP BY p.id
+		ORDER BY last_scan_at NULLS FIRST, p.name
+	`
+
+	err := r.db.SelectContext(ctx, &freshness, query, probedSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program freshness: %w", err)
+	}
+
+	return freshness, nil
+}
+
+// GetLastScanTimes returns when each program of a platform last completed a
+// scan, keyed by program URL; programs that never did are left out
+func (r *FreshnessRepository) GetLastScanTimes(ctx context.Context, platform string) (map[string]time.Time, error) {
+	var rows []struct {
+		ProgramURL string    `db:"program_url"`
+		LastScanAt time.Time `db:"last_scan_at"`
+	}
+	query := `
+		SELECT p.program_url, MAX(s.completed_at) AS last_scan_at
+		FROM programs p
+		JOIN scans s ON s.program_id = p.id AND s.status = 'completed' AND s.completed_at IS NOT NULL
+		WHERE p.platform = $1
+		GROUP BY p.program_url
+	`
+
+	if err := r.db.SelectContext(ctx, &rows, query, platform); err != nil {
+		return nil, fmt.Errorf("failed to get last scan times: %w", err)
+	}
+
+	lastScans := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		lastScans[row.ProgramURL] = row.LastScanAt
+	}
+	return lastScans, nil
+}
+
+// GetLastScanTime returns when a program last completed a scan, or nil when
+// it never did
+func (r *FreshnessRepository) GetLastScanTime(ctx context.Context, programID uuid.UUID) (*time.Time, error) {
+	var lastScanAt *time.Time
+	query := `SELECT MAX(completed_at) FROM scans WHERE program_id = $1 AND status = 'completed'`
+
+	if err := r.db.GetContext(ctx, &lastScanAt, query, programID); err != nil {
+		return nil, fmt.Errorf("failed to get last scan time: %w", err)
+	}
+
+	return lastScanAt, nil
+}
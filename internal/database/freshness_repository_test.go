@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreshnessRepository_GetProgramFreshness(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewFreshnessRepository(db)
+	probedSince := time.Now().Add(-7 * 24 * time.Hour)
+	lastScan := time.Now().Add(-2 * time.Hour)
+	neverScanned, scanned := uuid.New(), uuid.New()
+
+	mock.ExpectQuery("SELECT p.id AS program_id").
+		WithArgs(probedSince).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "program_name", "platform", "program_url", "last_scan_at", "assets", "stale_assets"}).
+			AddRow(neverScanned, "new", "hackerone", "https://hackerone.com/new", nil, 0, 0).
+			AddRow(scanned, "acme", "hackerone", "https://hackerone.com/acme", lastScan, 40, 3))
+
+	freshness, err := repo.GetProgramFreshness(context.Background(), probedSince)
+	require.NoError(t, err)
+	require.Len(t, freshness, 2)
+	assert.Nil(t, freshness[0].LastScanAt)
+	assert.Equal(t, lastScan, *freshness[1].LastScanAt)
+	assert.Equal(t, 3, freshness[1].StaleAssets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFreshnessRepository_GetLastScanTimes(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewFreshnessRepository(db)
+	lastScan := time.Now().Add(-30 * time.Hour)
+
+	mock.ExpectQuery("SELECT p.program_url, MAX\\(s.completed_at\\)").
+		WithArgs("bugcrowd").
+		WillReturnRows(sqlmock.NewRows([]string{"program_url", "last_scan_at"}).
+			AddRow("https://bugcrowd.com/acme", lastScan))
+
+	lastScans, err := repo.GetLastScanTimes(context.Background(), "bugcrowd")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"https://bugcrowd.com/acme": lastScan}, lastScans)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
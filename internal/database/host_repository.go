@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// HostRepository handles the hosts shared by the assets of several programs
+type HostRepository struct {
+	*Repository
+}
+
+// NewHostRepository creates a new host repository
+func NewHostRepository(db *sqlx.DB) *HostRepository {
+	return &HostRepository{Repository: NewRepository(db)}
+}
+
+// GetHostByURL retrieves the host of a URL or host name, matching any scheme
+// variant; nil when no program includes it
+func (r *HostRepository) GetHostByURL(ctx context.Context, url string) (*Host, error) {
+	var host Host
+	query := `SELECT * FROM hosts WHERE host_key = $1`
+
+	err := r.db.GetContext(ctx, &host, query, AssetHostKey(url))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get host: %w", err)
+	}
+
+	return &host, nil
+}
+
+// GetHostPrograms retrieves the programs that include a host with their
+// assets of it, the program that found it first first
+func (r *HostRepository) GetHostPrograms(ctx context.Context, hostID uuid.UUID) ([]*HostProgram, error) {
+	var programs []*HostProgram
+	query := `
+		SELECT p.id AS program_id, p.name AS program_name, p.platform, p.program_url,
+			a.id AS asset_id, a.url, a.status, a.ignored, a.created_at
+		FROM assets a JOIN programs p ON p.id = a.program_id
+		WHERE a.host_id = $1
+		ORDER BY a.created_at, p.name
+	`
+
+	err := r.db.SelectContext(ctx, &programs, query, hostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host programs: %w", err)
+	}
+
+	return programs, nil
+}
+
+// GetSharedHosts retrieves up to limit hosts more than one program includes,
+// those included by the most programs first
+func (r *HostRepository) GetSharedHosts(ctx context.Context, limit int) ([]*SharedHost, error) {
+	var hosts []*SharedHost
+	query := `
+		SELECT h.*, COUNT(DISTINCT a.program_id) AS programs
+		FROM hosts h JOIN assets a ON a.host_id = h.id
+		GROUP BY h.id
+		HAVING COUNT(DISTINCT a.program_id) > 1
+		ORDER BY programs DESC, h.host_key
+		LIMIT $1
+	`
+
+	err := r.db.SelectContext(ctx, &hosts, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared hosts: %w", err)
+	}
+
+	return hosts, nil
+}
+
+// CountSharedHosts counts the hosts more than one program includes
+func (r *HostRepository) CountSharedHosts(ctx context.Context) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM (
+			SELECT host_id FROM assets
+			GROUP BY host_id
+			HAVING COUNT(DISTINCT program_id) > 1
+		) shared
+	`
+
+	if err := r.db.GetContext(ctx, &count, query); err != nil {
+		return 0, fmt.Errorf("failed to count shared hosts: %w", err)
+	}
+
+	return count, nil
+}
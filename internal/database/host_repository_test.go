@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostRepository_GetHostByURL(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewHostRepository(db)
+	id, now := uuid.New(), time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM hosts WHERE host_key = \\$1").
+		WithArgs("api.example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_key", "first_seen", "last_seen"}).
+			AddRow(id, "api.example.com", now, now))
+
+	host, err := repo.GetHostByURL(context.Background(), "https://API.example.com:443/v1")
+	require.NoError(t, err)
+	require.NotNil(t, host)
+	assert.Equal(t, id, host.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHostRepository_GetHostByURLNotFound(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewHostRepository(db)
+
+	mock.ExpectQuery("SELECT \\* FROM hosts WHERE host_key = \\$1").
+		WithArgs("api.example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	host, err := repo.GetHostByURL(context.Background(), "api.example.com")
+	require.NoError(t, err)
+	assert.Nil(t, host)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHostRepository_GetHostPrograms(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewHostRepository(db)
+	hostID, now := uuid.New(), time.Now()
+	parent, acquired := uuid.New(), uuid.New()
+
+	mock.ExpectQuery("FROM assets a JOIN programs p ON p.id = a.program_id WHERE a.host_id = \\$1").
+		WithArgs(hostID).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "program_name", "platform", "program_url", "asset_id", "url", "status", "ignored", "created_at"}).
+			AddRow(parent, "Example", "hackerone", "https://hackerone.com/example", uuid.New(), "https://api.example.com", "active", false, now.Add(-time.Hour)).
+			AddRow(acquired, "Acquired", "bugcrowd", "https://bugcrowd.com/acquired", uuid.New(), "http://api.example.com", "active", true, now))
+
+	programs, err := repo.GetHostPrograms(context.Background(), hostID)
+	require.NoError(t, err)
+	require.Len(t, programs, 2)
+	assert.Equal(t, parent, programs[0].ProgramID)
+	assert.Equal(t, "Acquired", programs[1].ProgramName)
+	assert.True(t, programs[1].Ignored)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHostRepository_GetSharedHosts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewHostRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("HAVING COUNT\\(DISTINCT a.program_id\\) > 1 ORDER BY programs DESC, h.host_key LIMIT \\$1").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_key", "first_seen", "last_seen", "programs"}).
+			AddRow(uuid.New(), "cdn.example.com", now, now, 3).
+			AddRow(uuid.New(), "api.example.com", now, now, 2))
+
+	hosts, err := repo.GetSharedHosts(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+	assert.Equal(t, "cdn.example.com", hosts[0].HostKey)
+	assert.Equal(t, 3, hosts[0].Programs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHostRepository_CountSharedHosts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewHostRepository(db)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM \\( SELECT host_id FROM assets").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	count, err := repo.CountSharedHosts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
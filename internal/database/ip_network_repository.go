@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// IPNetworkRepository handles IP network enrichment database operations
+type IPNetworkRepository struct {
+	*Repository
+}
+
+// NewIPNetworkRepository creates a new IP network repository
+func NewIPNetworkRepository(db *sqlx.DB) *IPNetworkRepository {
+	return &IPNetworkRepository{Repository: NewRepository(db)}
+}
+
+// GetIPsToEnrich retrieves the distinct IPs of a program's assets that were
+// never looked up or were last looked up before the given time
+func (r *IPNetworkRepository) GetIPsToEnrich(ctx context.Context, programID uuid.UUID, checkedBefore time.Time) ([]string, error) {
+	var ips []string
+	query := `
+		SELECT DISTINCT h.ip FROM assets a
+		JOIN hosts h ON h.id = a.host_id
+		LEFT JOIN ip_networks n ON n.ip = h.ip
+		WHERE a.program_id = $1 AND h.ip <> ''
+		  AND (n.ip IS NULL OR n.checked_at < $2)
+		ORDER BY h.ip
+	`
+
+	err := r.db.SelectContext(ctx, &ips, query, programID, checkedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IPs to enrich: %w", err)
+	}
+
+	return ips, nil
+}
+
+// SaveIPNetwork creates or refreshes the network data of an IP
+func (r *IPNetworkRepository) SaveIPNetwork(ctx context.Context, network *IPNetwork) error {
+	network.CheckedAt = time.Now()
+
+	query := `
+		INSERT INTO ip_networks (ip, country, provider, network_name, lookup_error, checked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (ip) DO UPDATE SET
+			country = EXCLUDED.country,
+			provider = EXCLUDED.provider,
+			network_name = EXCLUDED.network_name,
+			lookup_error = EXCLUDED.lookup_error,
+			checked_at = EXCLUDED.checked_at
+		RETURNING created_at
+	`
+
+	err := r.db.GetContext(ctx, &network.CreatedAt, query, network.IP, network.Country, network.Provider,
+		network.NetworkName, network.LookupError, network.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save IP network: %w", err)
+	}
+
+	return nil
+}
+
+// GetGeoDistribution counts the assets of active programs per country and
+// provider, largest first within each program. Assets whose IP was not
+// looked up successfully are left out.
+func (r *IPNetworkRepository) GetGeoDistribution(ctx context.Context) ([]*GeoCount, error) {
+	var counts []*GeoCount
+	query := `
+		SELECT p.id AS program_id, p.name AS program_name, n.country, n.provider, COUNT(*) AS assets
+		FROM assets a
+		JOIN programs p ON p.id = a.program_id
+		JOIN hosts h ON h.id = a.host_id
+		JOIN ip_networks n ON n.ip = h.ip
+		WHERE p.is_active = true AND n.lookup_error = ''
+		GROUP BY p.id, p.name, n.country, n.provider
+		ORDER BY p.name, p.id, assets DESC, n.country, n.provider
+	`
+
+	err := r.db.SelectContext(ctx, &counts, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get geo distribution: %w", err)
+	}
+
+	return counts, nil
+}
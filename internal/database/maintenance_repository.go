@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MaintenanceRepository handles platform maintenance window database operations
+type MaintenanceRepository struct {
+	*Repository
+}
+
+// NewMaintenanceRepository creates a new maintenance repository
+func NewMaintenanceRepository(db *sqlx.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{Repository: NewRepository(db)}
+}
+
+// RecordMaintenance opens a maintenance window for a platform, or extends the
+// platform's open window when one already exists
+func (r *MaintenanceRepository) RecordMaintenance(ctx context.Context, platform, reason string, statusCode int, retryAt time.Time) error {
+	query := `
+		INSERT INTO platform_maintenance (id, platform, reason, status_code, started_at, last_seen_at, retry_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), $5, NOW(), NOW())
+		ON CONFLICT (platform) WHERE ended_at IS NULL DO UPDATE SET
+			reason = EXCLUDED.reason,
+			status_code = EXCLUDED.status_code,
+			last_seen_at = NOW(),
+			retry_at = EXCLUDED.retry_at,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, uuid.New(), platform, reason, statusCode, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to record maintenance for %s: %w", platform, err)
+	}
+
+	return nil
+}
+
+// GetActiveMaintenance retrieves a platform's open maintenance window if its
+// retry time has not passed yet
+func (r *MaintenanceRepository) GetActiveMaintenance(ctx context.Context, platform string) (*PlatformMaintenance, error) {
+	var window PlatformMaintenance
+	query := `SELECT * FROM platform_maintenance WHERE platform = $1 AND ended_at IS NULL AND retry_at > NOW()`
+
+	err := r.db.GetContext(ctx, &window, query, platform)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active maintenance: %w", err)
+	}
+
+	return &window, nil
+}
+
+// EndMaintenance closes a platform's open maintenance window once it responds normally
+func (r *MaintenanceRepository) EndMaintenance(ctx context.Context, platform string) error {
+	query := `UPDATE platform_maintenance SET ended_at = NOW(), updated_at = NOW() WHERE platform = $1 AND ended_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, platform)
+	if err != nil {
+		return fmt.Errorf("failed to end maintenance for %s: %w", platform, err)
+	}
+
+	return nil
+}
+
+// GetRecentMaintenance retrieves the most recent maintenance windows across platforms
+func (r *MaintenanceRepository) GetRecentMaintenance(ctx context.Context, limit int) ([]*PlatformMaintenance, error) {
+	var windows []*PlatformMaintenance
+	query := `SELECT * FROM platform_maintenance ORDER BY started_at DESC LIMIT $1`
+
+	err := r.db.SelectContext(ctx, &windows, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent maintenance: %w", err)
+	}
+
+	return windows, nil
+}
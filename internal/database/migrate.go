@@ -0,0 +1,500 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/version"
+	"github.com/sirupsen/logrus"
+)
+
+// embeddedMigrations are the schema migrations compiled into the binary, so
+// they are found regardless of the working directory
+//
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
+
+// MigrationsFS returns the migrations to apply: the *.sql files of dir when it
+// is set, for custom schemas, or the embedded migrations otherwise
+func MigrationsFS(dir string) (fs.FS, error) {
+	if dir == "" {
+		return fs.Sub(embeddedMigrations, "migrations")
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("migrations path %s is not a directory", dir)
+	}
+	return os.DirFS(dir), nil
+}
+
+// MigrationNames returns the *.sql files of a migrations FS in the order they
+// are applied, leaving out the *.down.sql files that revert them
+func MigrationNames(migrations fs.FS) ([]string, error) {
+	files, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migration files: %w", err)
+	}
+
+	var names []string
+	for _, name := range files {
+		if !strings.HasSuffix(name, downMigrationSuffix) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no migration files found")
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// downMigrationSuffix ends the file reverting a migration: 036_scan_runs.sql
+// is reverted by 036_scan_runs.down.sql
+const downMigrationSuffix = ".down.sql"
+
+// DownMigrationName returns the name of the file reverting a migration
+func DownMigrationName(name string) string {
+	return strings.TrimSuffix(name, ".sql") + downMigrationSuffix
+}
+
+// migrationLockID is the key of the advisory lock held while migrating, so
+// only one process changes the schema at a time
+const migrationLockID int64 = 7_301_829_104
+
+// migrationLockPoll is how often a waiting process retries the migration lock
+const migrationLockPoll = time.Second
+
+// DefaultMigrationLockTimeout is how long Migrate waits for another process's
+// migrations when no timeout is given
+const DefaultMigrationLockTimeout = time.Minute
+
+// noTransactionDirective marks a migration that has to run outside a
+// transaction, e.g. one that runs CREATE INDEX CONCURRENTLY
+const noTransactionDirective = "-- migrate:no-transaction"
+
+var (
+	// ErrSchemaBehind is returned when migrations of the binary were not applied yet
+	ErrSchemaBehind = errors.New("database schema is behind this binary")
+	// ErrSchemaAhead is returned when the database has migrations the binary does not know
+	ErrSchemaAhead = errors.New("database schema is newer than this binary")
+	// ErrMigrationLocked is returned when another process kept the migration lock
+	ErrMigrationLocked = errors.New("another process is migrating the database")
+	// ErrIrreversibleMigration is returned when rolling back a migration without a down migration
+	ErrIrreversibleMigration = errors.New("migration cannot be rolled back")
+)
+
+// createSchemaMigrations creates the table recording applied migrations
+const createSchemaMigrations = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		name VARCHAR(255) PRIMARY KEY,
+		checksum VARCHAR(64) NOT NULL,
+		agent_version VARCHAR(100) NOT NULL DEFAULT '',
+		applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	)
+`
+
+// SchemaStatus compares the migrations applied to a database with those of
+// the binary
+type SchemaStatus struct {
+	Current  string   // latest applied migration; empty when none were recorded
+	Expected string   // latest migration of the binary
+	Pending  []string // migrations not applied yet, or changed since they were
+	Unknown  []string // applied migrations the binary does not have, written by a newer binary
+}
+
+// UpToDate reports whether the schema matches the binary
+func (s *SchemaStatus) UpToDate() bool {
+	return len(s.Pending) == 0 && len(s.Unknown) == 0
+}
+
+// Err explains why the schema does not match the binary, or returns nil
+func (s *SchemaStatus) Err() error {
+	switch {
+	case len(s.Unknown) > 0:
+		return fmt.Errorf("%w: it has %s applied, this binary knows up to %s; upgrade the binary", ErrSchemaAhead, strings.Join(s.Unknown, ", "), s.Expected)
+	case len(s.Pending) > 0:
+		return fmt.Errorf("%w: %d migrations pending (%s); run `monitor-agent migrate up`", ErrSchemaBehind, len(s.Pending), strings.Join(s.Pending, ", "))
+	default:
+		return nil
+	}
+}
+
+// GetSchemaStatus compares the migrations recorded in the database with a
+// migrations FS
+func GetSchemaStatus(ctx context.Context, db sqlx.QueryerContext, migrations fs.FS) (*SchemaStatus, error) {
+	names, err := MigrationNames(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SchemaStatus{Expected: names[len(names)-1]}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+
+		checksum, err := migrationChecksum(migrations, name)
+		if err != nil {
+			return nil, err
+		}
+		if applied[name] != checksum {
+			status.Pending = append(status.Pending, name)
+		}
+	}
+
+	for name := range applied {
+		if !known[name] {
+			status.Unknown = append(status.Unknown, name)
+		}
+		if name > status.Current {
+			status.Current = name
+		}
+	}
+	sort.Strings(status.Unknown)
+
+	return status, nil
+}
+
+// Migration states reported by ListMigrations
+const (
+	MigrationApplied = "applied"
+	MigrationPending = "pending"
+	MigrationChanged = "changed" // applied, but the file changed since; applied again by Migrate
+	MigrationUnknown = "unknown" // applied by a binary with migrations this one does not have
+)
+
+// MigrationInfo is the state of one migration in a database
+type MigrationInfo struct {
+	Name         string
+	State        string
+	Reversible   bool       // whether it has a down migration
+	AgentVersion string     // version of the agent that applied it
+	AppliedAt    *time.Time // nil when it was not applied
+}
+
+// ListMigrations returns the state of every migration of a migrations FS,
+// and of every applied migration it does not have, in file name order
+func ListMigrations(ctx context.Context, db sqlx.QueryerContext, migrations fs.FS) ([]MigrationInfo, error) {
+	names, err := MigrationNames(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, db, &exists, "SELECT to_regclass('schema_migrations') IS NOT NULL"); err != nil {
+		return nil, fmt.Errorf("failed to check for schema_migrations table: %w", err)
+	}
+
+	type record struct {
+		Name         string    `db:"name"`
+		Checksum     string    `db:"checksum"`
+		AgentVersion string    `db:"agent_version"`
+		AppliedAt    time.Time `db:"applied_at"`
+	}
+	var records []record
+	if exists {
+		query := `SELECT name, checksum, agent_version, applied_at FROM schema_migrations`
+		if err := sqlx.SelectContext(ctx, db, &records, query); err != nil {
+			return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+		}
+	}
+	applied := make(map[string]record, len(records))
+	for _, r := range records {
+		applied[r.Name] = r
+	}
+
+	known := make(map[string]bool, len(names))
+	var list []MigrationInfo
+	for _, name := range names {
+		known[name] = true
+
+		info := MigrationInfo{Name: name, State: MigrationPending}
+		if _, err := fs.Stat(migrations, DownMigrationName(name)); err == nil {
+			info.Reversible = true
+		}
+		if r, ok := applied[name]; ok {
+			checksum, err := migrationChecksum(migrations, name)
+			if err != nil {
+				return nil, err
+			}
+			info.State = MigrationApplied
+			if r.Checksum != checksum {
+				info.State = MigrationChanged
+			}
+			info.AgentVersion = r.AgentVersion
+			info.AppliedAt = &r.AppliedAt
+		}
+		list = append(list, info)
+	}
+
+	for _, r := range records {
+		if !known[r.Name] {
+			list = append(list, MigrationInfo{Name: r.Name, State: MigrationUnknown, AgentVersion: r.AgentVersion, AppliedAt: &r.AppliedAt})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list, nil
+}
+
+// Migrate applies the pending migrations in file name order while holding
+// the migration lock, waiting up to lockTimeout for another process to finish
+// migrating. Each migration runs in a transaction together with its record in
+// schema_migrations, unless it starts with "-- migrate:no-transaction". It
+// returns the names of the applied migrations.
+func Migrate(ctx context.Context, db *sqlx.DB, migrations fs.FS, lockTimeout time.Duration) ([]string, error) {
+	var applied []string
+	err := withMigrationLock(ctx, db, lockTimeout, func(conn *sqlx.Conn) error {
+		// Another process may have migrated while this one waited for the lock
+		status, err := GetSchemaStatus(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+		if len(status.Unknown) > 0 {
+			return status.Err()
+		}
+
+		for _, name := range status.Pending {
+			if err := applyMigration(ctx, conn, migrations, name); err != nil {
+				return err
+			}
+			applied = append(applied, name)
+			logrus.Infof("Applied migration %s", name)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Rollback reverts the latest steps applied migrations, newest first, with
+// their down migrations while holding the migration lock. Nothing is reverted
+// unless every one of them has a down migration. Each down migration runs in
+// a transaction together with the removal of its migration's record, unless
+// it starts with "-- migrate:no-transaction". It returns the names of the
+// reverted migrations.
+func Rollback(ctx context.Context, db *sqlx.DB, migrations fs.FS, steps int, lockTimeout time.Duration) ([]string, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	var reverted []string
+	err := withMigrationLock(ctx, db, lockTimeout, func(conn *sqlx.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(applied))
+		for name := range applied {
+			names = append(names, name)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(names)))
+		if len(names) > steps {
+			names = names[:steps]
+		}
+
+		for _, name := range names {
+			if _, err := fs.Stat(migrations, DownMigrationName(name)); err != nil {
+				return fmt.Errorf("%w: %s has no %s", ErrIrreversibleMigration, name, DownMigrationName(name))
+			}
+		}
+
+		for _, name := range names {
+			if err := revertMigration(ctx, conn, migrations, name); err != nil {
+				return err
+			}
+			reverted = append(reverted, name)
+			logrus.Infof("Rolled back migration %s", name)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// withMigrationLock runs fn on a connection holding the migration lock,
+// after creating the schema_migrations table
+func withMigrationLock(ctx context.Context, db *sqlx.DB, lockTimeout time.Duration, fn func(conn *sqlx.Conn) error) error {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := acquireMigrationLock(ctx, conn, lockTimeout); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			logrus.Warnf("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// CheckSchema returns an error unless every migration of the binary, and no
+// other, was applied to the database
+func CheckSchema(ctx context.Context, db *sqlx.DB, migrations fs.FS) error {
+	status, err := GetSchemaStatus(ctx, db, migrations)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
+
+// acquireMigrationLock takes the migration lock on a connection, retrying
+// until timeout while another process holds it
+func acquireMigrationLock(ctx context.Context, conn *sqlx.Conn, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultMigrationLockTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var locked bool
+		if err := conn.GetContext(ctx, &locked, "SELECT pg_try_advisory_lock($1)", migrationLockID); err != nil {
+			return fmt.Errorf("failed to take migration lock: %w", err)
+		}
+		if locked {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: gave up after %s", ErrMigrationLocked, timeout)
+		}
+		logrus.Infof("Waiting for another process to finish migrating the database...")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(migrationLockPoll):
+		}
+	}
+}
+
+// applyMigration executes a migration and records it
+func applyMigration(ctx context.Context, conn *sqlx.Conn, migrations fs.FS, name string) error {
+	migrationSQL, err := fs.ReadFile(migrations, name)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file %s: %w", name, err)
+	}
+
+	record := `
+		INSERT INTO schema_migrations (name, checksum, agent_version, applied_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (name) DO UPDATE SET checksum = EXCLUDED.checksum, agent_version = EXCLUDED.agent_version, applied_at = NOW()
+	`
+
+	return execMigration(ctx, conn, name, migrationSQL, record, name, checksumOf(migrationSQL), version.Version)
+}
+
+// revertMigration executes the down migration of a migration and removes its record
+func revertMigration(ctx context.Context, conn *sqlx.Conn, migrations fs.FS, name string) error {
+	downName := DownMigrationName(name)
+	migrationSQL, err := fs.ReadFile(migrations, downName)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file %s: %w", downName, err)
+	}
+
+	return execMigration(ctx, conn, downName, migrationSQL, "DELETE FROM schema_migrations WHERE name = $1", name)
+}
+
+// execMigration executes a migration file and the statement updating
+// schema_migrations for it, in one transaction unless the file starts with
+// "-- migrate:no-transaction"
+func execMigration(ctx context.Context, conn *sqlx.Conn, name string, migrationSQL []byte, record string, args ...any) error {
+	if strings.HasPrefix(strings.TrimSpace(string(migrationSQL)), noTransactionDirective) {
+		if _, err := conn.ExecContext(ctx, string(migrationSQL)); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", name, err)
+		}
+		if _, err := conn.ExecContext(ctx, record, args...); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", name, err)
+		}
+		return nil
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", name, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(migrationSQL)); err != nil {
+		return fmt.Errorf("failed to execute migration %s: %w", name, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", name, err)
+	}
+	return nil
+}
+
+// appliedMigrations returns the checksums of the recorded migrations by name,
+// or none when the schema_migrations table does not exist yet
+func appliedMigrations(ctx context.Context, db sqlx.QueryerContext) (map[string]string, error) {
+	var exists bool
+	if err := sqlx.GetContext(ctx, db, &exists, "SELECT to_regclass('schema_migrations') IS NOT NULL"); err != nil {
+		return nil, fmt.Errorf("failed to check for schema_migrations table: %w", err)
+	}
+
+	applied := make(map[string]string)
+	if !exists {
+		return applied, nil
+	}
+
+	var rows []struct {
+		Name     string `db:"name"`
+		Checksum string `db:"checksum"`
+	}
+	if err := sqlx.SelectContext(ctx, db, &rows, "SELECT name, checksum FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	for _, row := range rows {
+		applied[row.Name] = row.Checksum
+	}
+
+	return applied, nil
+}
+
+// migrationChecksum returns the checksum of a migration file
+func migrationChecksum(migrations fs.FS, name string) (string, error) {
+	migrationSQL, err := fs.ReadFile(migrations, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read migration file %s: %w", name, err)
+	}
+	return checksumOf(migrationSQL), nil
+}
+
+// checksumOf returns the hex SHA-256 of a migration's contents
+func checksumOf(migrationSQL []byte) string {
+	sum := sha256.Sum256(migrationSQL)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,263 @@
+package database
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationsFS_Embedded(t *testing.T) {
+	migrations, err := MigrationsFS("")
+	require.NoError(t, err)
+
+	names, err := MigrationNames(migrations)
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+	assert.Equal(t, "001_initial_schema.sql", names[0])
+	assert.IsIncreasing(t, names)
+}
+
+func TestMigrationsFS_Directory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "002_b.sql"), []byte("SELECT 2;"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001_a.sql"), []byte("SELECT 1;"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001_a.down.sql"), []byte("SELECT -1;"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a migration"), 0o600))
+
+	migrations, err := MigrationsFS(dir)
+	require.NoError(t, err)
+
+	names, err := MigrationNames(migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"001_a.sql", "002_b.sql"}, names)
+}
+
+func TestMigrationsFS_EmbeddedDownMigrations(t *testing.T) {
+	migrations, err := MigrationsFS("")
+	require.NoError(t, err)
+
+	names, err := MigrationNames(migrations)
+	require.NoError(t, err)
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[DownMigrationName(name)] = true
+	}
+
+	downs, err := fs.Glob(migrations, "*"+downMigrationSuffix)
+	require.NoError(t, err)
+	require.NotEmpty(t, downs)
+	for _, down := range downs {
+		assert.True(t, known[down], "%s does not revert a migration", down)
+	}
+}
+
+// writeMigrations writes migration files to a directory and returns them as a FS
+func writeMigrations(t *testing.T, files map[string]string) fs.FS {
+	dir := t.TempDir()
+	for name, contents := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+	}
+
+	migrations, err := MigrationsFS(dir)
+	require.NoError(t, err)
+	return migrations
+}
+
+// expectApplied expects the schema_migrations lookup to return migrations
+func expectApplied(mock sqlmock.Sqlmock, applied map[string]string) {
+	mock.ExpectQuery("SELECT to_regclass").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	rows := sqlmock.NewRows([]string{"name", "checksum"})
+	for name, contents := range applied {
+		rows.AddRow(name, checksumOf([]byte(contents)))
+	}
+	mock.ExpectQuery("SELECT name, checksum FROM schema_migrations").WillReturnRows(rows)
+}
+
+func TestMigrate(t *testing.T) {
+	migrations := writeMigrations(t, map[string]string{
+		"001_a.sql": "SELECT 1;",
+		"002_b.sql": "SELECT 2;",
+		"003_c.sql": "-- migrate:no-transaction\nSELECT 3;",
+	})
+
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(migrationLockID).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock, map[string]string{"001_a.sql": "SELECT 1;"})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT 2;").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs("002_b.sql", checksumOf([]byte("SELECT 2;")), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectExec("SELECT 3;").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs("003_c.sql", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := Migrate(context.Background(), db, migrations, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"002_b.sql", "003_c.sql"}, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrate_Locked(t *testing.T) {
+	migrations := writeMigrations(t, map[string]string{"001_a.sql": "SELECT 1;"})
+
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+
+	_, err := Migrate(context.Background(), db, migrations, time.Nanosecond)
+	assert.ErrorIs(t, err, ErrMigrationLocked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRollback(t *testing.T) {
+	migrations := writeMigrations(t, map[string]string{
+		"001_a.sql":      "SELECT 1;",
+		"002_b.sql":      "SELECT 2;",
+		"002_b.down.sql": "SELECT -2;",
+		"003_c.sql":      "SELECT 3;",
+		"003_c.down.sql": "-- migrate:no-transaction\nSELECT -3;",
+	})
+
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(migrationLockID).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock, map[string]string{"001_a.sql": "SELECT 1;", "002_b.sql": "SELECT 2;", "003_c.sql": "SELECT 3;"})
+
+	mock.ExpectExec("SELECT -3;").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs("003_c.sql").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT -2;").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs("002_b.sql").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := Rollback(context.Background(), db, migrations, 2, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"003_c.sql", "002_b.sql"}, reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRollback_Irreversible(t *testing.T) {
+	migrations := writeMigrations(t, map[string]string{
+		"001_a.sql":      "SELECT 1;",
+		"002_b.sql":      "SELECT 2;",
+		"002_b.down.sql": "SELECT -2;",
+	})
+
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	// 001_a.sql has no down migration, so not even 002_b.sql is reverted
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock, map[string]string{"001_a.sql": "SELECT 1;", "002_b.sql": "SELECT 2;"})
+	mock.ExpectExec("SELECT pg_advisory_unlock").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := Rollback(context.Background(), db, migrations, 5, time.Second)
+	assert.ErrorIs(t, err, ErrIrreversibleMigration)
+	assert.EqualError(t, err, "migration cannot be rolled back: 001_a.sql has no 001_a.down.sql")
+	assert.Empty(t, reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = Rollback(context.Background(), db, migrations, 0, time.Second)
+	assert.EqualError(t, err, "steps must be positive, got 0")
+}
+
+func TestListMigrations(t *testing.T) {
+	migrations := writeMigrations(t, map[string]string{
+		"001_a.sql":      "SELECT 1;",
+		"002_b.sql":      "SELECT 2;",
+		"002_b.down.sql": "SELECT -2;",
+		"003_c.sql":      "SELECT 3;",
+	})
+
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	appliedAt := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT to_regclass").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT name, checksum, agent_version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "checksum", "agent_version", "applied_at"}).
+			AddRow("001_a.sql", checksumOf([]byte("SELECT 1;")), "v1.2.0", appliedAt).
+			AddRow("002_b.sql", checksumOf([]byte("SELECT 'old';")), "v1.2.0", appliedAt).
+			AddRow("004_d.sql", checksumOf([]byte("SELECT 4;")), "v1.3.0", appliedAt))
+
+	list, err := ListMigrations(context.Background(), db, migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationInfo{
+		{Name: "001_a.sql", State: MigrationApplied, AgentVersion: "v1.2.0", AppliedAt: &appliedAt},
+		{Name: "002_b.sql", State: MigrationChanged, Reversible: true, AgentVersion: "v1.2.0", AppliedAt: &appliedAt},
+		{Name: "003_c.sql", State: MigrationPending},
+		{Name: "004_d.sql", State: MigrationUnknown, AgentVersion: "v1.3.0", AppliedAt: &appliedAt},
+	}, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSchemaStatus(t *testing.T) {
+	migrations := writeMigrations(t, map[string]string{
+		"001_a.sql": "SELECT 1;",
+		"002_b.sql": "SELECT 2;",
+	})
+
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Before the first migration nothing is recorded
+	mock.ExpectQuery("SELECT to_regclass").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	status, err := GetSchemaStatus(ctx, db, migrations)
+	require.NoError(t, err)
+	assert.Equal(t, &SchemaStatus{Expected: "002_b.sql", Pending: []string{"001_a.sql", "002_b.sql"}}, status)
+	assert.ErrorIs(t, status.Err(), ErrSchemaBehind)
+
+	// A changed migration is pending again
+	expectApplied(mock, map[string]string{"001_a.sql": "SELECT 1;", "002_b.sql": "SELECT 'old';"})
+	status, err = GetSchemaStatus(ctx, db, migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"002_b.sql"}, status.Pending)
+	assert.Equal(t, "002_b.sql", status.Current)
+
+	expectApplied(mock, map[string]string{"001_a.sql": "SELECT 1;", "002_b.sql": "SELECT 2;"})
+	status, err = GetSchemaStatus(ctx, db, migrations)
+	require.NoError(t, err)
+	assert.True(t, status.UpToDate())
+	assert.NoError(t, status.Err())
+
+	// A newer binary applied a migration this one does not have
+	expectApplied(mock, map[string]string{"001_a.sql": "SELECT 1;", "002_b.sql": "SELECT 2;", "003_c.sql": "SELECT 3;"})
+	status, err = GetSchemaStatus(ctx, db, migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"003_c.sql"}, status.Unknown)
+	assert.Equal(t, "003_c.sql", status.Current)
+	assert.ErrorIs(t, status.Err(), ErrSchemaAhead)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrationsFS_Missing(t *testing.T) {
+	_, err := MigrationsFS(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	migrations, err := MigrationsFS(t.TempDir())
+	require.NoError(t, err)
+	_, err = MigrationNames(migrations)
+	assert.EqualError(t, err, "no migration files found")
+}
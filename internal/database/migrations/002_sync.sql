@@ -0,0 +1,30 @@
+-- Track the last successful push to each central sync server
+CREATE TABLE IF NOT EXISTS sync_state (
+    server_url VARCHAR(500) PRIMARY KEY,
+    last_synced_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Record which edge agents have reported each asset to the central server
+CREATE TABLE IF NOT EXISTS asset_sightings (
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    agent_id VARCHAR(255) NOT NULL,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, agent_id)
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_asset_sightings_agent_id') THEN
+        CREATE INDEX idx_asset_sightings_agent_id ON asset_sightings(agent_id);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_programs_updated_at') THEN
+        CREATE INDEX idx_programs_updated_at ON programs(updated_at);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_assets_updated_at') THEN
+        CREATE INDEX idx_assets_updated_at ON assets(updated_at);
+    END IF;
+END $$;
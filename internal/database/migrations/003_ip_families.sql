@@ -0,0 +1,18 @@
+-- Record per-family addresses and reachability for dual-stack probing
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'ipv6') THEN
+        ALTER TABLE assets ADD COLUMN ipv6 VARCHAR(45);
+        RAISE NOTICE 'Added ipv6 column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'ipv4_reachable') THEN
+        ALTER TABLE assets ADD COLUMN ipv4_reachable BOOLEAN;
+        RAISE NOTICE 'Added ipv4_reachable column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'ipv6_reachable') THEN
+        ALTER TABLE assets ADD COLUMN ipv6_reachable BOOLEAN;
+        RAISE NOTICE 'Added ipv6_reachable column to assets table';
+    END IF;
+END $$;
@@ -0,0 +1,28 @@
+-- Record the scan and discovery source that first created each asset
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'first_source') THEN
+        ALTER TABLE assets ADD COLUMN first_source VARCHAR(50);
+
+        -- Backfill from the asset tier; secondary assets have only ever come from ChaosDB
+        UPDATE assets SET first_source = CASE source WHEN 'secondary' THEN 'chaosdb' ELSE source END;
+        RAISE NOTICE 'Added first_source column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'first_scan_id') THEN
+        ALTER TABLE assets ADD COLUMN first_scan_id UUID REFERENCES scans(id) ON DELETE SET NULL;
+
+        -- Backfill with the most recent scan of the program that started before the asset was created
+        UPDATE assets a SET first_scan_id = (
+            SELECT s.id FROM scans s
+            WHERE s.program_id = a.program_id AND s.started_at <= a.created_at
+            ORDER BY s.started_at DESC
+            LIMIT 1
+        );
+        RAISE NOTICE 'Added first_scan_id column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_assets_first_source') THEN
+        CREATE INDEX idx_assets_first_source ON assets(first_source);
+    END IF;
+END $$;
@@ -0,0 +1,29 @@
+-- Track each program's stable platform-side identifier so handle renames can be detected
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'programs' AND column_name = 'platform_id') THEN
+        ALTER TABLE programs ADD COLUMN platform_id VARCHAR(255) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added platform_id column to programs table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_programs_platform_id') THEN
+        CREATE INDEX idx_programs_platform_id ON programs(platform, platform_id) WHERE platform_id <> '';
+    END IF;
+END $$;
+
+-- Previous program URLs of renamed programs, so old URLs still resolve
+CREATE TABLE IF NOT EXISTS program_aliases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    program_id UUID NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    platform VARCHAR(255) NOT NULL,
+    program_url VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(platform, program_url)
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_program_aliases_program_id') THEN
+        CREATE INDEX idx_program_aliases_program_id ON program_aliases(program_id);
+    END IF;
+END $$;
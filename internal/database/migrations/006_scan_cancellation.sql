@@ -0,0 +1,8 @@
+-- Let a running scan be cancelled from another process (CLI or API)
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'scans' AND column_name = 'cancel_requested_at') THEN
+        ALTER TABLE scans ADD COLUMN cancel_requested_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added cancel_requested_at column to scans table';
+    END IF;
+END $$;
@@ -0,0 +1,25 @@
+-- Platform maintenance windows detected during scans
+CREATE TABLE IF NOT EXISTS platform_maintenance (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    platform VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    retry_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    -- At most one open window per platform
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_platform_maintenance_open') THEN
+        CREATE UNIQUE INDEX idx_platform_maintenance_open ON platform_maintenance(platform) WHERE ended_at IS NULL;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_platform_maintenance_started_at') THEN
+        CREATE INDEX idx_platform_maintenance_started_at ON platform_maintenance(started_at);
+    END IF;
+END $$;
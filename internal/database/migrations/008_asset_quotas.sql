@@ -0,0 +1,42 @@
+-- Number of assets confirmed by each scan, compared between scans for quota alerts
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'scans' AND column_name = 'assets_seen') THEN
+        ALTER TABLE scans ADD COLUMN assets_seen INTEGER NOT NULL DEFAULT 0;
+        RAISE NOTICE 'Added assets_seen column to scans table';
+    END IF;
+END $$;
+
+-- Per-program overrides of the asset quota bounds; NULL falls back to the global default
+CREATE TABLE IF NOT EXISTS program_asset_bounds (
+    program_id UUID PRIMARY KEY REFERENCES programs(id) ON DELETE CASCADE,
+    max_drop_percent DOUBLE PRECISION,
+    max_growth INTEGER,
+    disabled BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Alerts raised when a scan's asset count moves outside its program's bounds
+CREATE TABLE IF NOT EXISTS asset_quota_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    program_id UUID NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    scan_id UUID REFERENCES scans(id) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL,
+    previous_count INTEGER NOT NULL,
+    current_count INTEGER NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_asset_quota_alerts_program_id') THEN
+        CREATE INDEX idx_asset_quota_alerts_program_id ON asset_quota_alerts(program_id);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_asset_quota_alerts_created_at') THEN
+        CREATE INDEX idx_asset_quota_alerts_created_at ON asset_quota_alerts(created_at);
+    END IF;
+END $$;
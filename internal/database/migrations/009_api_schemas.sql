@@ -0,0 +1,34 @@
+-- API schemas (OpenAPI/Swagger, GraphQL introspection, WADL) exposed by assets
+CREATE TABLE IF NOT EXISTS api_schemas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    response_id UUID REFERENCES asset_responses(id) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL,
+    version VARCHAR(50) NOT NULL DEFAULT '',
+    title TEXT NOT NULL DEFAULT '',
+    endpoint_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(asset_id, kind)
+);
+
+-- Endpoints parsed from an API schema; replaced whenever the schema is parsed again
+CREATE TABLE IF NOT EXISTS api_endpoints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    schema_id UUID NOT NULL REFERENCES api_schemas(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    path TEXT NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    UNIQUE(schema_id, method, path)
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_api_schemas_asset_id') THEN
+        CREATE INDEX idx_api_schemas_asset_id ON api_schemas(asset_id);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_api_endpoints_schema_id') THEN
+        CREATE INDEX idx_api_endpoints_schema_id ON api_endpoints(schema_id);
+    END IF;
+END $$;
@@ -0,0 +1,35 @@
+-- Tags attached to assets, e.g. by triage rules
+CREATE TABLE IF NOT EXISTS asset_tags (
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    tag VARCHAR(100) NOT NULL,
+    source VARCHAR(255) NOT NULL DEFAULT '', -- what attached the tag, e.g. rule:grafana
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, tag)
+);
+
+-- Triage rules that matched an asset response and the actions they asked for
+CREATE TABLE IF NOT EXISTS rule_matches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    response_id UUID REFERENCES asset_responses(id) ON DELETE SET NULL,
+    rule_name VARCHAR(255) NOT NULL,
+    tags TEXT NOT NULL DEFAULT '', -- comma-separated
+    notify VARCHAR(255) NOT NULL DEFAULT '',
+    enqueue TEXT NOT NULL DEFAULT '', -- comma-separated
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_asset_tags_tag') THEN
+        CREATE INDEX idx_asset_tags_tag ON asset_tags(tag);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_rule_matches_asset_id') THEN
+        CREATE INDEX idx_rule_matches_asset_id ON rule_matches(asset_id);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_rule_matches_created_at') THEN
+        CREATE INDEX idx_rule_matches_created_at ON rule_matches(created_at);
+    END IF;
+END $$;
@@ -0,0 +1,8 @@
+-- Version of the agent that ran each scan, so data provenance is traceable across upgrades
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'scans' AND column_name = 'agent_version') THEN
+        ALTER TABLE scans ADD COLUMN agent_version VARCHAR(100) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added agent_version column to scans table';
+    END IF;
+END $$;
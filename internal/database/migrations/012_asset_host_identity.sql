@@ -0,0 +1,87 @@
+-- Canonical asset identity: one asset per program and host[:port], with the
+-- http/https variants that were seen recorded underneath it
+CREATE TABLE IF NOT EXISTS asset_scheme_variants (
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    scheme VARCHAR(20) NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, scheme)
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'host_key') THEN
+        ALTER TABLE assets ADD COLUMN host_key VARCHAR(500);
+        RAISE NOTICE 'Added host_key column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_assets_program_host_key') THEN
+        -- Backfill the lowercased host[:port], dropping the scheme's default port
+        -- (must match database.AssetHostKey)
+        UPDATE assets SET host_key = CASE
+            WHEN position('://' IN url) = 0 OR split_part(split_part(split_part(split_part(url, '://', 2), '/', 1), '?', 1), '#', 1) = '' THEN lower(url)
+            ELSE regexp_replace(
+                lower(split_part(split_part(split_part(split_part(url, '://', 2), '/', 1), '?', 1), '#', 1)),
+                CASE lower(split_part(url, '://', 1)) WHEN 'http' THEN ':80$' WHEN 'https' THEN ':443$' ELSE '$^' END,
+                '')
+        END
+        WHERE host_key IS NULL;
+
+        -- Pick one asset per program and host, preferring https and then the oldest row
+        CREATE TEMP TABLE asset_merge AS
+        SELECT id AS duplicate_id,
+               first_value(id) OVER (
+                   PARTITION BY program_id, host_key
+                   ORDER BY (url LIKE 'https://%') DESC, created_at, id
+               ) AS keeper_id
+        FROM assets;
+
+        -- Record every scheme variant under the kept asset
+        INSERT INTO asset_scheme_variants (asset_id, scheme, url, first_seen, last_seen)
+        SELECT m.keeper_id, lower(split_part(a.url, '://', 1)), a.url, a.created_at, a.updated_at
+        FROM assets a JOIN asset_merge m ON m.duplicate_id = a.id
+        WHERE position('://' IN a.url) > 0
+        ON CONFLICT (asset_id, scheme) DO NOTHING;
+
+        -- The kept asset inherits the earliest first-seen details
+        UPDATE assets k SET created_at = e.created_at, first_scan_id = e.first_scan_id, first_source = e.first_source
+        FROM (
+            SELECT DISTINCT ON (m.keeper_id) m.keeper_id, a.created_at, a.first_scan_id, a.first_source
+            FROM asset_merge m JOIN assets a ON a.id = m.duplicate_id
+            ORDER BY m.keeper_id, a.created_at
+        ) e
+        WHERE k.id = e.keeper_id AND e.created_at < k.created_at;
+
+        -- Move history to the kept asset; rows that would collide are dropped with the duplicate
+        UPDATE asset_responses r SET asset_id = m.keeper_id
+        FROM asset_merge m WHERE r.asset_id = m.duplicate_id AND m.duplicate_id <> m.keeper_id;
+
+        UPDATE rule_matches r SET asset_id = m.keeper_id
+        FROM asset_merge m WHERE r.asset_id = m.duplicate_id AND m.duplicate_id <> m.keeper_id;
+
+        UPDATE api_schemas s SET asset_id = m.keeper_id
+        FROM asset_merge m
+        WHERE s.asset_id = m.duplicate_id AND m.duplicate_id <> m.keeper_id
+          AND NOT EXISTS (SELECT 1 FROM api_schemas k WHERE k.asset_id = m.keeper_id AND k.kind = s.kind);
+
+        UPDATE asset_tags t SET asset_id = m.keeper_id
+        FROM asset_merge m
+        WHERE t.asset_id = m.duplicate_id AND m.duplicate_id <> m.keeper_id
+          AND NOT EXISTS (SELECT 1 FROM asset_tags k WHERE k.asset_id = m.keeper_id AND k.tag = t.tag);
+
+        UPDATE asset_sightings s SET asset_id = m.keeper_id
+        FROM asset_merge m
+        WHERE s.asset_id = m.duplicate_id AND m.duplicate_id <> m.keeper_id
+          AND NOT EXISTS (SELECT 1 FROM asset_sightings k WHERE k.asset_id = m.keeper_id AND k.agent_id = s.agent_id);
+
+        DELETE FROM assets a USING asset_merge m
+        WHERE a.id = m.duplicate_id AND m.duplicate_id <> m.keeper_id;
+
+        DROP TABLE asset_merge;
+
+        ALTER TABLE assets ALTER COLUMN host_key SET NOT NULL;
+        CREATE UNIQUE INDEX idx_assets_program_host_key ON assets(program_id, host_key);
+        RAISE NOTICE 'Merged http/https duplicate assets by host';
+    END IF;
+END $$;
@@ -0,0 +1,18 @@
+-- Registration data (RDAP/WHOIS) of apex domains, shared by every program
+-- that has the domain in scope
+CREATE TABLE IF NOT EXISTS domain_registrations (
+    domain VARCHAR(255) PRIMARY KEY,
+    registrar TEXT NOT NULL DEFAULT '',
+    registered_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    lookup_error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_domain_registrations_registered_at') THEN
+        CREATE INDEX idx_domain_registrations_registered_at ON domain_registrations(registered_at);
+    END IF;
+END $$;
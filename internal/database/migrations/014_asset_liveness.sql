@@ -0,0 +1,13 @@
+-- Liveness state of each asset's latest probe (live, dns-only, timed-out,
+-- refused, waf-blocked, error); empty when the asset was never probed
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'liveness') THEN
+        ALTER TABLE assets ADD COLUMN liveness VARCHAR(20) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added liveness column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_assets_program_liveness') THEN
+        CREATE INDEX idx_assets_program_liveness ON assets(program_id, liveness);
+    END IF;
+END $$;
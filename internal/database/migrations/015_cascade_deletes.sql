@@ -0,0 +1,55 @@
+-- Guarantee that deleting a program, asset, scan or response cleans up the
+-- rows that reference it. Databases created before a table's foreign key was
+-- added (or by hand) may lack it or have it without the ON DELETE action, so
+-- each relation is checked and its key (re)created. Keys are added NOT VALID
+-- so existing orphans do not block startup; `monitor-agent orphans --purge`
+-- removes them and validates the keys.
+DO $$
+DECLARE
+    rel RECORD;
+    existing RECORD;
+    wanted "char";
+BEGIN
+    FOR rel IN SELECT * FROM (VALUES
+        ('assets', 'program_id', 'programs', 'CASCADE'),
+        ('assets', 'first_scan_id', 'scans', 'SET NULL'),
+        ('scans', 'program_id', 'programs', 'CASCADE'),
+        ('program_aliases', 'program_id', 'programs', 'CASCADE'),
+        ('program_asset_bounds', 'program_id', 'programs', 'CASCADE'),
+        ('asset_quota_alerts', 'program_id', 'programs', 'CASCADE'),
+        ('asset_quota_alerts', 'scan_id', 'scans', 'SET NULL'),
+        ('asset_responses', 'asset_id', 'assets', 'CASCADE'),
+        ('asset_sightings', 'asset_id', 'assets', 'CASCADE'),
+        ('asset_scheme_variants', 'asset_id', 'assets', 'CASCADE'),
+        ('asset_tags', 'asset_id', 'assets', 'CASCADE'),
+        ('rule_matches', 'asset_id', 'assets', 'CASCADE'),
+        ('rule_matches', 'response_id', 'asset_responses', 'SET NULL'),
+        ('api_schemas', 'asset_id', 'assets', 'CASCADE'),
+        ('api_schemas', 'response_id', 'asset_responses', 'SET NULL'),
+        ('api_endpoints', 'schema_id', 'api_schemas', 'CASCADE')
+    ) AS r(child, child_column, parent, on_delete)
+    LOOP
+        wanted := CASE rel.on_delete WHEN 'CASCADE' THEN 'c' ELSE 'n' END;
+
+        SELECT c.conname, c.confdeltype INTO existing
+        FROM pg_constraint c
+        JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = ANY (c.conkey)
+        WHERE c.contype = 'f'
+          AND c.conrelid = rel.child::regclass
+          AND c.confrelid = rel.parent::regclass
+          AND a.attname = rel.child_column
+        LIMIT 1;
+
+        IF FOUND AND existing.confdeltype = wanted THEN
+            CONTINUE;
+        END IF;
+
+        IF FOUND THEN
+            EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', rel.child, existing.conname);
+        END IF;
+
+        EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I FOREIGN KEY (%I) REFERENCES %I(id) ON DELETE %s NOT VALID',
+            rel.child, 'fk_' || rel.child || '_' || rel.child_column, rel.child_column, rel.parent, rel.on_delete);
+        RAISE NOTICE 'Added ON DELETE % foreign key on %.%', rel.on_delete, rel.child, rel.child_column;
+    END LOOP;
+END $$;
@@ -0,0 +1,18 @@
+-- Last probe error of each asset and when it happened, kept after later
+-- successful probes so systematic failures can be analyzed from the data
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'last_probe_error') THEN
+        ALTER TABLE assets ADD COLUMN last_probe_error TEXT NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added last_probe_error column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'last_probe_error_at') THEN
+        ALTER TABLE assets ADD COLUMN last_probe_error_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added last_probe_error_at column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_assets_last_probe_error_at') THEN
+        CREATE INDEX idx_assets_last_probe_error_at ON assets(last_probe_error_at) WHERE last_probe_error_at IS NOT NULL;
+    END IF;
+END $$;
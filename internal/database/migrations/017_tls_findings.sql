@@ -0,0 +1,22 @@
+-- TLS misconfigurations found while probing (expired or self-signed
+-- certificates, hostname mismatches, legacy protocol versions). One row per
+-- asset and check; resolved_at is set once a later probe no longer finds it.
+CREATE TABLE IF NOT EXISTS tls_findings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    check_name VARCHAR(50) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    url VARCHAR(500) NOT NULL DEFAULT '',
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (asset_id, check_name)
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_tls_findings_open') THEN
+        CREATE INDEX idx_tls_findings_open ON tls_findings(check_name, severity) WHERE resolved_at IS NULL;
+    END IF;
+END $$;
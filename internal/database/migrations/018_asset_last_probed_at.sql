@@ -0,0 +1,20 @@
+-- When each asset was last probed, so incremental sweeps can re-probe the
+-- stalest assets first. Assets never probed since this column was added are
+-- backfilled from their latest stored response and otherwise left NULL,
+-- which sweeps treat as the oldest.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'last_probed_at') THEN
+        ALTER TABLE assets ADD COLUMN last_probed_at TIMESTAMP WITH TIME ZONE;
+
+        UPDATE assets a SET last_probed_at = r.last_response_at
+        FROM (SELECT asset_id, MAX(created_at) AS last_response_at FROM asset_responses GROUP BY asset_id) r
+        WHERE r.asset_id = a.id;
+
+        RAISE NOTICE 'Added last_probed_at column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_assets_last_probed_at') THEN
+        CREATE INDEX idx_assets_last_probed_at ON assets(last_probed_at NULLS FIRST);
+    END IF;
+END $$;
@@ -0,0 +1,18 @@
+-- Network allocation data (RDAP) of asset IPs, shared by every asset on the
+-- address; used for the country and provider breakdown of each program
+CREATE TABLE IF NOT EXISTS ip_networks (
+    ip VARCHAR(45) PRIMARY KEY,
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    provider TEXT NOT NULL DEFAULT '',
+    network_name TEXT NOT NULL DEFAULT '',
+    lookup_error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_assets_ip') THEN
+        CREATE INDEX idx_assets_ip ON assets(ip);
+    END IF;
+END $$;
@@ -0,0 +1,12 @@
+-- Where a program's discovery stopped when it ran out of time, so the next
+-- attempt picks up the remaining domains instead of starting over
+CREATE TABLE IF NOT EXISTS program_continuations (
+    program_id UUID PRIMARY KEY REFERENCES programs(id) ON DELETE CASCADE,
+    scan_id UUID REFERENCES scans(id) ON DELETE SET NULL,
+    stage VARCHAR(20) NOT NULL,
+    domain VARCHAR(255) NOT NULL DEFAULT '',
+    remaining_domains TEXT[] NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
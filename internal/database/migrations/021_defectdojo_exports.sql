@@ -0,0 +1,18 @@
+-- Scans pushed to DefectDojo, one engagement per scan, so each scan is only
+-- exported once
+CREATE TABLE IF NOT EXISTS defectdojo_exports (
+    scan_id UUID PRIMARY KEY REFERENCES scans(id) ON DELETE CASCADE,
+    program_id UUID NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL,
+    engagement_id INTEGER NOT NULL,
+    endpoints INTEGER NOT NULL DEFAULT 0,
+    findings INTEGER NOT NULL DEFAULT 0,
+    exported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_defectdojo_exports_program_id') THEN
+        CREATE INDEX idx_defectdojo_exports_program_id ON defectdojo_exports(program_id);
+    END IF;
+END $$;
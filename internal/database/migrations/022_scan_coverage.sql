@@ -0,0 +1,22 @@
+-- How many subdomains each scan discovered, sent to the prober and got back
+-- per scope domain, so probe gaps that repeat across scans can be tracked
+CREATE TABLE IF NOT EXISTS scan_coverage (
+    scan_id UUID NOT NULL REFERENCES scans(id) ON DELETE CASCADE,
+    program_id UUID NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    domain VARCHAR(255) NOT NULL,
+    discovered INTEGER NOT NULL DEFAULT 0,
+    valid INTEGER NOT NULL DEFAULT 0,
+    probed INTEGER NOT NULL DEFAULT 0,
+    resolved INTEGER NOT NULL DEFAULT 0,
+    live INTEGER NOT NULL DEFAULT 0,
+    probe_error TEXT NOT NULL DEFAULT '',
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scan_id, domain)
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_scan_coverage_program_id') THEN
+        CREATE INDEX idx_scan_coverage_program_id ON scan_coverage(program_id);
+    END IF;
+END $$;
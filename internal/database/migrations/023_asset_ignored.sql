@@ -0,0 +1,9 @@
+-- Assets marked as ignored, e.g. hosts of a sold-off acquisition, stay
+-- stored but are no longer re-probed or reported
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'ignored') THEN
+        ALTER TABLE assets ADD COLUMN ignored BOOLEAN NOT NULL DEFAULT FALSE;
+        RAISE NOTICE 'Added ignored column to assets table';
+    END IF;
+END $$;
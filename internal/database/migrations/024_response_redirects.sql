@@ -0,0 +1,34 @@
+-- Redirect behavior behind each stored response: the status of the first
+-- response, how many redirects were followed, where they ended and whether the
+-- chain looped, hit the redirect limit or continued with a meta refresh
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'initial_status_code') THEN
+        ALTER TABLE asset_responses ADD COLUMN initial_status_code INTEGER NOT NULL DEFAULT 0;
+        RAISE NOTICE 'Added initial_status_code column to asset_responses table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'redirect_hops') THEN
+        ALTER TABLE asset_responses ADD COLUMN redirect_hops INTEGER NOT NULL DEFAULT 0;
+        RAISE NOTICE 'Added redirect_hops column to asset_responses table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'final_url') THEN
+        ALTER TABLE asset_responses ADD COLUMN final_url TEXT NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added final_url column to asset_responses table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'redirect_status') THEN
+        ALTER TABLE asset_responses ADD COLUMN redirect_status VARCHAR(20) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added redirect_status column to asset_responses table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'meta_refresh') THEN
+        ALTER TABLE asset_responses ADD COLUMN meta_refresh TEXT NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added meta_refresh column to asset_responses table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_asset_responses_redirect_status') THEN
+        CREATE INDEX idx_asset_responses_redirect_status ON asset_responses(redirect_status) WHERE redirect_status <> '';
+    END IF;
+END $$;
@@ -0,0 +1,9 @@
+-- Credentials sent when probing a program's assets: headers, cookies and basic
+-- auth, sealed with AES-256-GCM under PROBE_AUTH_KEY so they are never stored
+-- in the clear
+CREATE TABLE IF NOT EXISTS probe_auth_profiles (
+    program_id UUID PRIMARY KEY REFERENCES programs(id) ON DELETE CASCADE,
+    sealed_profile BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
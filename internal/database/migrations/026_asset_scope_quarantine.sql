@@ -0,0 +1,12 @@
+-- When an asset's scope root was removed from its program's scope. Assets
+-- outside the scope for the grace period are quarantined so downstream
+-- scanners are no longer pointed at targets that are no longer authorized.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'scope_missing_since') THEN
+        ALTER TABLE assets ADD COLUMN scope_missing_since TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added scope_missing_since column to assets table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_assets_scope_missing_since ON assets(scope_missing_since) WHERE scope_missing_since IS NOT NULL;
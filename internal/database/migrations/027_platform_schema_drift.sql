@@ -0,0 +1,14 @@
+-- Fields of platform API payloads that appeared or disappeared compared to the
+-- structs they are decoded into, one row per platform, payload, field and change
+CREATE TABLE IF NOT EXISTS platform_schema_drift (
+    platform VARCHAR(50) NOT NULL,
+    payload VARCHAR(100) NOT NULL,
+    field TEXT NOT NULL,
+    change VARCHAR(20) NOT NULL,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    scans INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (platform, payload, field, change)
+);
+
+CREATE INDEX IF NOT EXISTS idx_platform_schema_drift_last_seen_at ON platform_schema_drift(last_seen_at);
@@ -0,0 +1,18 @@
+-- Hostnames watched every cycle whether or not they are alive yet, e.g. an
+-- admin host that is expected to come up. State is dead, resolving or
+-- responding; a move to a more alive state is announced immediately.
+CREATE TABLE IF NOT EXISTS watchlist (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hostname VARCHAR(255) NOT NULL UNIQUE,
+    program_id UUID REFERENCES programs(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    state VARCHAR(20) NOT NULL DEFAULT 'dead',
+    status_code INTEGER NOT NULL DEFAULT 0,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    last_checked_at TIMESTAMP WITH TIME ZONE,
+    state_changed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_program_id ON watchlist(program_id) WHERE program_id IS NOT NULL;
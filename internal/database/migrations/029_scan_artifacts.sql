@@ -0,0 +1,13 @@
+-- Scan reports uploaded to object storage by `report share`, so sharing a
+-- scan again reuses the stored object instead of uploading it again. Objects
+-- are expired by the bucket's lifecycle rules, not by the agent.
+CREATE TABLE IF NOT EXISTS scan_artifacts (
+    scan_id UUID NOT NULL REFERENCES scans(id) ON DELETE CASCADE,
+    format VARCHAR(10) NOT NULL,
+    bucket VARCHAR(255) NOT NULL,
+    object_key TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    uploaded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scan_id, format)
+);
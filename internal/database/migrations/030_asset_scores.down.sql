@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_assets_program_score;
+
+ALTER TABLE assets
+    DROP COLUMN IF EXISTS score,
+    DROP COLUMN IF EXISTS score_model,
+    DROP COLUMN IF EXISTS scored_at;
@@ -0,0 +1,20 @@
+-- How interesting each asset is to test under the configured scoring model.
+-- score_model is the fingerprint of the model the score was computed with, so
+-- scores left over from an earlier configuration can be told apart.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'score') THEN
+        ALTER TABLE assets ADD COLUMN score DOUBLE PRECISION NOT NULL DEFAULT 0;
+        RAISE NOTICE 'Added score column to assets table';
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'score_model') THEN
+        ALTER TABLE assets ADD COLUMN score_model VARCHAR(64) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added score_model column to assets table';
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'scored_at') THEN
+        ALTER TABLE assets ADD COLUMN scored_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added scored_at column to assets table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_assets_program_score ON assets(program_id, score DESC);
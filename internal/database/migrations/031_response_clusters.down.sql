@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_assets_cluster_id;
+ALTER TABLE assets DROP COLUMN IF EXISTS cluster_id;
+
+DROP TABLE IF EXISTS response_clusters;
+
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS body_simhash;
@@ -0,0 +1,32 @@
+-- Groups of live assets whose latest responses are near-identical, e.g.
+-- thousands of hosts serving the same marketing page. body_simhash is the
+-- fingerprint of a response body the clusters are computed from; clusters are
+-- replaced by every clustering pass, and assets.cluster_id points to the
+-- cluster of each clustered asset.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'body_simhash') THEN
+        ALTER TABLE asset_responses ADD COLUMN body_simhash BIGINT;
+        RAISE NOTICE 'Added body_simhash column to asset_responses table';
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS response_clusters (
+    id UUID PRIMARY KEY,
+    representative_asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    simhash BIGINT NOT NULL,
+    size INTEGER NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'cluster_id') THEN
+        ALTER TABLE assets ADD COLUMN cluster_id UUID REFERENCES response_clusters(id) ON DELETE SET NULL;
+        RAISE NOTICE 'Added cluster_id column to assets table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_assets_cluster_id ON assets(cluster_id) WHERE cluster_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_response_clusters_size ON response_clusters(size DESC);
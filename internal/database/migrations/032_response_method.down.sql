@@ -0,0 +1 @@
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS method;
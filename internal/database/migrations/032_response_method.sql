@@ -0,0 +1,10 @@
+-- Request method each response was captured with. HEAD responses (light
+-- liveness sweeps) carry no body, so body-based triage only looks at GET
+-- responses.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'method') THEN
+        ALTER TABLE asset_responses ADD COLUMN method VARCHAR(10) NOT NULL DEFAULT 'GET';
+        RAISE NOTICE 'Added method column to asset_responses table';
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_assets_provenance;
+
+ALTER TABLE assets
+    DROP COLUMN IF EXISTS provenance,
+    DROP COLUMN IF EXISTS data_terms;
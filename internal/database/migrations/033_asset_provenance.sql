@@ -0,0 +1,24 @@
+-- Where each asset's data came from and the usage terms that came with it.
+-- provenance lists every discovery source that found the asset, in the order
+-- they found it; data_terms the terms of those sources (DATA_SOURCE_TERMS)
+-- when they were recorded. Exports carry both and can leave out the assets
+-- only some sources found.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'provenance') THEN
+        ALTER TABLE assets ADD COLUMN provenance TEXT[] NOT NULL DEFAULT '{}';
+        RAISE NOTICE 'Added provenance column to assets table';
+
+        -- Existing assets only recorded the source that found them first
+        UPDATE assets SET provenance = ARRAY[first_source] WHERE first_source <> '';
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'data_terms') THEN
+        ALTER TABLE assets ADD COLUMN data_terms TEXT[] NOT NULL DEFAULT '{}';
+        RAISE NOTICE 'Added data_terms column to assets table';
+
+        -- The default DATA_SOURCE_TERMS for ChaosDB data found before terms were recorded
+        UPDATE assets SET data_terms = ARRAY['ProjectDiscovery Chaos terms of use'] WHERE first_source = 'chaosdb';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_assets_provenance ON assets USING GIN (provenance);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scans_scheduled_at;
+ALTER TABLE scans DROP COLUMN IF EXISTS scheduled_at;
@@ -0,0 +1,12 @@
+-- The SCAN_SCHEDULE time that started each scan of `monitor-agent daemon`;
+-- NULL for scans run on demand. The last scheduled run whose scans all
+-- completed is where the daemon picks the schedule up after a restart.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'scans' AND column_name = 'scheduled_at') THEN
+        ALTER TABLE scans ADD COLUMN scheduled_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added scheduled_at column to scans table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_scans_scheduled_at ON scans (scheduled_at) WHERE scheduled_at IS NOT NULL;
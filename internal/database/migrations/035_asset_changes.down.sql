@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS asset_changes;
+
+DROP INDEX IF EXISTS idx_assets_program_last_scan;
+ALTER TABLE assets DROP COLUMN IF EXISTS last_scan_id;
+ALTER TABLE scans DROP COLUMN IF EXISTS compared_scan_id;
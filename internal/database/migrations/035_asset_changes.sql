@@ -0,0 +1,35 @@
+-- The last scan that found or confirmed each asset, and what changed in a
+-- program's assets from one completed scan to the next: one row per added or
+-- removed asset, and one per changed field of an asset seen by both scans.
+-- scans.compared_scan_id is the scan a scan's changes were computed against;
+-- NULL when they were not, e.g. for a program's first scan.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'last_scan_id') THEN
+        ALTER TABLE assets ADD COLUMN last_scan_id UUID REFERENCES scans(id) ON DELETE SET NULL;
+        RAISE NOTICE 'Added last_scan_id column to assets table';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'scans' AND column_name = 'compared_scan_id') THEN
+        ALTER TABLE scans ADD COLUMN compared_scan_id UUID REFERENCES scans(id) ON DELETE SET NULL;
+        RAISE NOTICE 'Added compared_scan_id column to scans table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_assets_program_last_scan ON assets (program_id, last_scan_id);
+
+CREATE TABLE IF NOT EXISTS asset_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scan_id UUID NOT NULL REFERENCES scans(id) ON DELETE CASCADE,
+    program_id UUID NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    asset_id UUID REFERENCES assets(id) ON DELETE SET NULL,
+    url VARCHAR(500) NOT NULL,
+    change VARCHAR(20) NOT NULL,
+    field VARCHAR(50) NOT NULL DEFAULT '',
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_changes_scan_id ON asset_changes (scan_id);
+CREATE INDEX IF NOT EXISTS idx_asset_changes_program_created_at ON asset_changes (program_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scan_run_programs;
+DROP TABLE IF EXISTS scan_run_platforms;
+DROP TABLE IF EXISTS scan_runs;
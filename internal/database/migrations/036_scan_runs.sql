@@ -0,0 +1,31 @@
+-- Full scans of every platform and how far they got: the platforms whose
+-- programs were all processed and every program processed so far. A run that
+-- did not complete, because the agent was stopped, crashed or the scan timed
+-- out, is picked up by `monitor-agent scan --resume`, which skips what it
+-- already processed.
+CREATE TABLE IF NOT EXISTS scan_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    resumes INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scan_runs_started_at ON scan_runs (started_at DESC);
+
+CREATE TABLE IF NOT EXISTS scan_run_platforms (
+    run_id UUID NOT NULL REFERENCES scan_runs(id) ON DELETE CASCADE,
+    platform VARCHAR(50) NOT NULL,
+    programs INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (run_id, platform)
+);
+
+CREATE TABLE IF NOT EXISTS scan_run_programs (
+    run_id UUID NOT NULL REFERENCES scan_runs(id) ON DELETE CASCADE,
+    platform VARCHAR(50) NOT NULL,
+    program_url VARCHAR(500) NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (run_id, platform, program_url)
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_assets_program_dns_dead;
+
+ALTER TABLE assets
+    DROP COLUMN IF EXISTS cnames,
+    DROP COLUMN IF EXISTS dns_dead,
+    DROP COLUMN IF EXISTS resolved_at;
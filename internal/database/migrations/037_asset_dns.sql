@@ -0,0 +1,22 @@
+-- What each asset's hostname resolved to when it was last resolved: the CNAME
+-- chain it went through, in the order it was followed, and whether it was
+-- dns_dead, i.e. the name did not exist or had no addresses. assets.ip and
+-- assets.ipv6 hold its first A and AAAA records; a dns_dead asset keeps the
+-- addresses it last resolved to.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'cnames') THEN
+        ALTER TABLE assets ADD COLUMN cnames TEXT[] NOT NULL DEFAULT '{}';
+        RAISE NOTICE 'Added cnames column to assets table';
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'dns_dead') THEN
+        ALTER TABLE assets ADD COLUMN dns_dead BOOLEAN NOT NULL DEFAULT FALSE;
+        RAISE NOTICE 'Added dns_dead column to assets table';
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'resolved_at') THEN
+        ALTER TABLE assets ADD COLUMN resolved_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added resolved_at column to assets table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_assets_program_dns_dead ON assets (program_id) WHERE dns_dead;
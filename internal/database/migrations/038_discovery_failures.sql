@@ -0,0 +1,19 @@
+-- Scope domains whose subdomain discovery or HTTPX probe failed during a
+-- scan, one row per program and domain with the last failure. They are
+-- reprocessed by `monitor-agent scan --retry-failed` once next_retry_at has
+-- passed, the delay doubling with every failed attempt, and removed as soon
+-- as a scan or retry processes the domain without errors.
+CREATE TABLE IF NOT EXISTS discovery_failures (
+    program_id UUID NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    domain VARCHAR(255) NOT NULL,
+    stage VARCHAR(20) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    first_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    next_retry_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (program_id, domain)
+);
+
+CREATE INDEX IF NOT EXISTS idx_discovery_failures_next_retry_at ON discovery_failures (next_retry_at);
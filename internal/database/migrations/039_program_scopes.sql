@@ -0,0 +1,18 @@
+-- Snapshots of each program's scope as scans fetched it: its in-scope targets
+-- and its out-of-scope entries as '<type> <target>', sorted, and a SHA-256
+-- hash of them. The latest snapshot's out-of-scope entries are what
+-- hostnames found outside a scan are checked against. A snapshot is
+-- only added when its hash differs from the program's latest one, so the
+-- rows of a program are the history of its scope; each added or removed
+-- in-scope target is announced with a scope.changed event.
+CREATE TABLE IF NOT EXISTS program_scopes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    program_id UUID NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    scan_id UUID REFERENCES scans(id) ON DELETE SET NULL,
+    content_hash CHAR(64) NOT NULL,
+    in_scope TEXT[] NOT NULL DEFAULT '{}',
+    out_of_scope TEXT[] NOT NULL DEFAULT '{}',
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_program_scopes_program_id ON program_scopes (program_id, recorded_at DESC);
@@ -0,0 +1 @@
+ALTER TABLE programs DROP COLUMN IF EXISTS visibility;
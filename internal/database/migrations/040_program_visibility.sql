@@ -0,0 +1,9 @@
+-- Whether a program is public or a private program the platform account was
+-- invited to; programs recorded before are public
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'programs' AND column_name = 'visibility') THEN
+        ALTER TABLE programs ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public';
+        RAISE NOTICE 'Added visibility column to programs table';
+    END IF;
+END $$;
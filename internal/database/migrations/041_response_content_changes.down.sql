@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_asset_responses_content;
+DROP INDEX IF EXISTS idx_assets_content_changed_at;
+ALTER TABLE assets DROP COLUMN IF EXISTS content_changed_at;
+ALTER TABLE assets DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS header_hash;
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS body_hash;
@@ -0,0 +1,40 @@
+-- Hashes of each GET response's body and of its status code and significant
+-- headers, compared with the asset's previous capture to detect content
+-- changes. Responses captured over another scheme than the asset's URL keep
+-- empty hashes.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'body_hash') THEN
+        ALTER TABLE asset_responses ADD COLUMN body_hash VARCHAR(64) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added body_hash column to asset_responses table';
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'header_hash') THEN
+        ALTER TABLE asset_responses ADD COLUMN header_hash VARCHAR(64) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added header_hash column to asset_responses table';
+    END IF;
+END $$;
+
+-- Hash of the capture an asset's content last changed to, and when it did;
+-- content_changed_at stays NULL until a change after the first capture
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'content_hash') THEN
+        ALTER TABLE assets ADD COLUMN content_hash VARCHAR(64) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added content_hash column to assets table';
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'content_changed_at') THEN
+        ALTER TABLE assets ADD COLUMN content_changed_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added content_changed_at column to assets table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_assets_content_changed_at ON assets (content_changed_at) WHERE content_changed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_asset_responses_content ON asset_responses (asset_id, created_at DESC) WHERE body_hash <> '';
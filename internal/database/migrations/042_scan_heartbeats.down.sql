@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scans_running;
+ALTER TABLE scans DROP COLUMN IF EXISTS heartbeat_at;
@@ -0,0 +1,12 @@
+-- When the process running a scan last reported it alive. Scans left running
+-- by a process that stopped stop sending heartbeats, which is how the next
+-- start finds and aborts them.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'scans' AND column_name = 'heartbeat_at') THEN
+        ALTER TABLE scans ADD COLUMN heartbeat_at TIMESTAMPTZ;
+        RAISE NOTICE 'Added heartbeat_at column to scans table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_scans_running ON scans (started_at) WHERE status = 'running';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_asset_responses_body_ref;
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS body_size;
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS body_sha256;
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS body_ref;
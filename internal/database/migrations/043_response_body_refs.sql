@@ -0,0 +1,30 @@
+-- Bodies offloaded to a bucket (BODY_STORE_BUCKET) keep an empty body column
+-- and record where the object is, the SHA-256 it is stored under and its
+-- size. Bodies are stored by content, so identical bodies share one object.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'body_ref') THEN
+        ALTER TABLE asset_responses ADD COLUMN body_ref TEXT NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added body_ref column to asset_responses table';
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'body_sha256') THEN
+        ALTER TABLE asset_responses ADD COLUMN body_sha256 VARCHAR(64) NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added body_sha256 column to asset_responses table';
+    END IF;
+END $$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'body_size') THEN
+        ALTER TABLE asset_responses ADD COLUMN body_size INTEGER NOT NULL DEFAULT 0;
+        RAISE NOTICE 'Added body_size column to asset_responses table';
+    END IF;
+END $$;
+
+-- Pruning checks whether other responses still reference an object before
+-- deleting it
+CREATE INDEX IF NOT EXISTS idx_asset_responses_body_ref ON asset_responses (body_ref) WHERE body_ref <> '';
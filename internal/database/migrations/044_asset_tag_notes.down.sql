@@ -0,0 +1 @@
+ALTER TABLE asset_tags DROP COLUMN IF EXISTS note;
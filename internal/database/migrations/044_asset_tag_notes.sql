@@ -0,0 +1,9 @@
+-- Tags hunters attach by hand can carry a note, e.g. why an asset is
+-- interesting or what triage found
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_tags' AND column_name = 'note') THEN
+        ALTER TABLE asset_tags ADD COLUMN note TEXT NOT NULL DEFAULT '';
+        RAISE NOTICE 'Added note column to asset_tags table';
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS set_assets_host_id ON assets;
+DROP FUNCTION IF EXISTS set_asset_host_id();
+DROP INDEX IF EXISTS idx_assets_host_id;
+ALTER TABLE assets DROP COLUMN IF EXISTS host_id;
+DROP TABLE IF EXISTS hosts;
@@ -0,0 +1,57 @@
+-- Global identity of assets: one row per host[:port] across programs. The
+-- same subdomain often belongs to several programs, e.g. after an acquisition
+-- or on shared infrastructure; each program keeps its own asset, with its
+-- status, tags and scans, and every one of them references the shared host.
+CREATE TABLE IF NOT EXISTS hosts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    host_key VARCHAR(500) NOT NULL UNIQUE,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'host_id') THEN
+        ALTER TABLE assets ADD COLUMN host_id UUID REFERENCES hosts(id) ON DELETE SET NULL;
+        RAISE NOTICE 'Added host_id column to assets table';
+    END IF;
+END $$;
+
+-- Backfill a host for every host key the assets have
+INSERT INTO hosts (host_key, first_seen, last_seen)
+SELECT host_key, MIN(created_at), MAX(updated_at)
+FROM assets
+WHERE host_key IS NOT NULL
+GROUP BY host_key
+ON CONFLICT (host_key) DO NOTHING;
+
+UPDATE assets a SET host_id = h.id
+FROM hosts h
+WHERE h.host_key = a.host_key AND a.host_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_assets_host_id ON assets(host_id);
+
+-- Every write path (scans, sync, seed) stores assets by host key, so the
+-- host is looked up, or created, as the asset is written
+CREATE OR REPLACE FUNCTION set_asset_host_id()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.host_key IS NULL THEN
+        NEW.host_id = NULL;
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO hosts (host_key) VALUES (NEW.host_key)
+    ON CONFLICT (host_key) DO UPDATE SET last_seen = NOW()
+    RETURNING id INTO NEW.host_id;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'set_assets_host_id') THEN
+        CREATE TRIGGER set_assets_host_id BEFORE INSERT OR UPDATE OF host_key ON assets
+            FOR EACH ROW EXECUTE FUNCTION set_asset_host_id();
+    END IF;
+END $$;
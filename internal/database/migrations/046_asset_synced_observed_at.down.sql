@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS clear_assets_synced_observed_at ON assets;
+DROP FUNCTION IF EXISTS clear_asset_synced_observed_at();
+ALTER TABLE assets DROP COLUMN IF EXISTS synced_observed_at;
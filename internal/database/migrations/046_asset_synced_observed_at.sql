@@ -0,0 +1,29 @@
+-- When the edge agent that last wrote an asset through sync observed it, on
+-- the edge's clock. Synced writes are compared on it instead of updated_at,
+-- which the update trigger stamps with the server's apply time. Any other
+-- write clears it, so a later local write is compared on its updated_at.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'synced_observed_at') THEN
+        ALTER TABLE assets ADD COLUMN synced_observed_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added synced_observed_at column to assets table';
+    END IF;
+END $$;
+
+CREATE OR REPLACE FUNCTION clear_asset_synced_observed_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.synced_observed_at IS NOT DISTINCT FROM OLD.synced_observed_at THEN
+        NEW.synced_observed_at = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'clear_assets_synced_observed_at') THEN
+        CREATE TRIGGER clear_assets_synced_observed_at BEFORE UPDATE ON assets
+            FOR EACH ROW EXECUTE FUNCTION clear_asset_synced_observed_at();
+    END IF;
+END $$;
@@ -0,0 +1,65 @@
+DROP INDEX IF EXISTS idx_asset_responses_host_content;
+DROP INDEX IF EXISTS idx_asset_responses_host_latest;
+ALTER TABLE asset_responses DROP COLUMN IF EXISTS host_id;
+
+ALTER TABLE assets ALTER COLUMN host_id DROP NOT NULL;
+
+ALTER TABLE assets
+    ADD COLUMN IF NOT EXISTS ip VARCHAR(45),
+    ADD COLUMN IF NOT EXISTS ipv6 VARCHAR(45),
+    ADD COLUMN IF NOT EXISTS ipv4_reachable BOOLEAN,
+    ADD COLUMN IF NOT EXISTS ipv6_reachable BOOLEAN,
+    ADD COLUMN IF NOT EXISTS liveness VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_probe_error TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_probe_error_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS last_probed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS cnames TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS dns_dead BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS content_changed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE assets a SET
+    ip = h.ip,
+    ipv6 = h.ipv6,
+    ipv4_reachable = h.ipv4_reachable,
+    ipv6_reachable = h.ipv6_reachable,
+    liveness = h.liveness,
+    last_probe_error = h.last_probe_error,
+    last_probe_error_at = h.last_probe_error_at,
+    last_probed_at = h.last_probed_at,
+    cnames = h.cnames,
+    dns_dead = h.dns_dead,
+    resolved_at = h.resolved_at,
+    content_hash = h.content_hash,
+    content_changed_at = h.content_changed_at
+FROM hosts h
+WHERE h.id = a.host_id;
+
+CREATE INDEX IF NOT EXISTS idx_assets_program_liveness ON assets(program_id, liveness);
+CREATE INDEX IF NOT EXISTS idx_assets_last_probe_error_at ON assets(last_probe_error_at) WHERE last_probe_error_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_assets_last_probed_at ON assets(last_probed_at NULLS FIRST);
+CREATE INDEX IF NOT EXISTS idx_assets_ip ON assets(ip);
+CREATE INDEX IF NOT EXISTS idx_assets_program_dns_dead ON assets (program_id) WHERE dns_dead;
+CREATE INDEX IF NOT EXISTS idx_assets_content_changed_at ON assets (content_changed_at) WHERE content_changed_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_hosts_content_changed_at;
+DROP INDEX IF EXISTS idx_hosts_last_probe_error_at;
+DROP INDEX IF EXISTS idx_hosts_last_probed_at;
+DROP INDEX IF EXISTS idx_hosts_ip;
+DROP INDEX IF EXISTS idx_hosts_liveness;
+
+ALTER TABLE hosts
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS ipv6,
+    DROP COLUMN IF EXISTS ipv4_reachable,
+    DROP COLUMN IF EXISTS ipv6_reachable,
+    DROP COLUMN IF EXISTS liveness,
+    DROP COLUMN IF EXISTS last_probe_error,
+    DROP COLUMN IF EXISTS last_probe_error_at,
+    DROP COLUMN IF EXISTS last_probed_at,
+    DROP COLUMN IF EXISTS cnames,
+    DROP COLUMN IF EXISTS dns_dead,
+    DROP COLUMN IF EXISTS resolved_at,
+    DROP COLUMN IF EXISTS content_hash,
+    DROP COLUMN IF EXISTS content_changed_at;
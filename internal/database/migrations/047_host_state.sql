@@ -0,0 +1,112 @@
+-- What a host resolved to, how it answered its latest probe and its latest
+-- content belong to the host, not to the program that found it: they move to
+-- hosts, shared by the assets of every program that includes the host. Each
+-- asset keeps what is its program's own: status, tags, score, ignored and
+-- its scans.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'hosts' AND column_name = 'liveness') THEN
+        ALTER TABLE hosts
+            ADD COLUMN ip VARCHAR(45) NOT NULL DEFAULT '',
+            ADD COLUMN ipv6 VARCHAR(45) NOT NULL DEFAULT '',
+            ADD COLUMN ipv4_reachable BOOLEAN,
+            ADD COLUMN ipv6_reachable BOOLEAN,
+            ADD COLUMN liveness VARCHAR(20) NOT NULL DEFAULT '',
+            ADD COLUMN last_probe_error TEXT NOT NULL DEFAULT '',
+            ADD COLUMN last_probe_error_at TIMESTAMP WITH TIME ZONE,
+            ADD COLUMN last_probed_at TIMESTAMP WITH TIME ZONE,
+            ADD COLUMN cnames TEXT[] NOT NULL DEFAULT '{}',
+            ADD COLUMN dns_dead BOOLEAN NOT NULL DEFAULT FALSE,
+            ADD COLUMN resolved_at TIMESTAMP WITH TIME ZONE,
+            ADD COLUMN content_hash VARCHAR(64) NOT NULL DEFAULT '',
+            ADD COLUMN content_changed_at TIMESTAMP WITH TIME ZONE;
+        RAISE NOTICE 'Added resolution, probe and content columns to hosts table';
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'assets' AND column_name = 'liveness') THEN
+        -- A host shared by several programs takes the probe state of its most
+        -- recently probed asset, the resolution of its most recently resolved
+        -- one and the content of the one whose content changed last
+        UPDATE hosts h SET
+            ip = COALESCE(a.ip, ''),
+            ipv6 = COALESCE(a.ipv6, ''),
+            ipv4_reachable = a.ipv4_reachable,
+            ipv6_reachable = a.ipv6_reachable,
+            liveness = a.liveness,
+            last_probe_error = a.last_probe_error,
+            last_probe_error_at = a.last_probe_error_at,
+            last_probed_at = a.last_probed_at
+        FROM (
+            SELECT DISTINCT ON (host_id) * FROM assets
+            WHERE host_id IS NOT NULL
+            ORDER BY host_id, last_probed_at DESC NULLS LAST, updated_at DESC
+        ) a
+        WHERE a.host_id = h.id;
+
+        UPDATE hosts h SET
+            cnames = a.cnames,
+            dns_dead = a.dns_dead,
+            resolved_at = a.resolved_at
+        FROM (
+            SELECT DISTINCT ON (host_id) * FROM assets
+            WHERE host_id IS NOT NULL AND resolved_at IS NOT NULL
+            ORDER BY host_id, resolved_at DESC
+        ) a
+        WHERE a.host_id = h.id;
+
+        UPDATE hosts h SET
+            content_hash = a.content_hash,
+            content_changed_at = a.content_changed_at
+        FROM (
+            SELECT DISTINCT ON (host_id) * FROM assets
+            WHERE host_id IS NOT NULL AND content_hash <> ''
+            ORDER BY host_id, content_changed_at DESC NULLS LAST, updated_at DESC
+        ) a
+        WHERE a.host_id = h.id;
+
+        ALTER TABLE assets
+            DROP COLUMN ip,
+            DROP COLUMN ipv6,
+            DROP COLUMN ipv4_reachable,
+            DROP COLUMN ipv6_reachable,
+            DROP COLUMN liveness,
+            DROP COLUMN last_probe_error,
+            DROP COLUMN last_probe_error_at,
+            DROP COLUMN last_probed_at,
+            DROP COLUMN cnames,
+            DROP COLUMN dns_dead,
+            DROP COLUMN resolved_at,
+            DROP COLUMN content_hash,
+            DROP COLUMN content_changed_at;
+        RAISE NOTICE 'Moved resolution, probe and content columns from assets to hosts';
+    END IF;
+END $$;
+
+-- Every asset reads its state from its host, which the set_assets_host_id
+-- trigger of migration 045 creates as the asset is written
+ALTER TABLE assets ALTER COLUMN host_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_hosts_liveness ON hosts(liveness);
+CREATE INDEX IF NOT EXISTS idx_hosts_ip ON hosts(ip);
+CREATE INDEX IF NOT EXISTS idx_hosts_last_probed_at ON hosts(last_probed_at NULLS FIRST);
+CREATE INDEX IF NOT EXISTS idx_hosts_last_probe_error_at ON hosts(last_probe_error_at) WHERE last_probe_error_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_hosts_content_changed_at ON hosts(content_changed_at) WHERE content_changed_at IS NOT NULL;
+
+-- Responses are captured from the host, so the latest response of an asset
+-- is the latest one of its host, whichever program's scan captured it
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'asset_responses' AND column_name = 'host_id') THEN
+        ALTER TABLE asset_responses ADD COLUMN host_id UUID REFERENCES hosts(id) ON DELETE CASCADE;
+
+        UPDATE asset_responses r SET host_id = a.host_id
+        FROM assets a
+        WHERE a.id = r.asset_id;
+
+        ALTER TABLE asset_responses ALTER COLUMN host_id SET NOT NULL;
+        RAISE NOTICE 'Added host_id column to asset_responses table';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_asset_responses_host_latest ON asset_responses(host_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_asset_responses_host_content ON asset_responses(host_id, created_at DESC) WHERE body_hash <> '';
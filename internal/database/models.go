@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Program represents a bug bounty program
@@ -11,38 +12,291 @@ type Program struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
 	Platform    string    `db:"platform" json:"platform"`
+	PlatformID  string    `db:"platform_id" json:"platform_id"` // stable platform-side ID, empty if unknown
 	URL         string    `db:"url" json:"url"`
 	ProgramURL  string    `db:"program_url" json:"program_url"`
 	IsActive    bool      `db:"is_active" json:"is_active"`
+	Visibility  string    `db:"visibility" json:"visibility"` // public, or private for programs the platform account was invited to
 	LastUpdated time.Time `db:"last_updated" json:"last_updated"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
 
-// Asset represents a discovered asset (subdomain/URL)
+// Program visibilities
+const (
+	ProgramVisibilityPublic  = "public"
+	ProgramVisibilityPrivate = "private"
+)
+
+// Asset represents a discovered asset (subdomain/URL) of a program. Its
+// address, probe, DNS and content fields are those of its host, which the
+// assets of other programs with the same host key share, see Host.
 type Asset struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	ProgramID  uuid.UUID `db:"program_id" json:"program_id"`
-	ProgramURL string    `db:"program_url" json:"program_url"`
-	URL        string    `db:"url" json:"url"`
-	Domain     string    `db:"domain" json:"domain"`
-	Subdomain  string    `db:"subdomain" json:"subdomain"`
-	IP         string    `db:"ip" json:"ip"`
-	Status     string    `db:"status" json:"status"` // active, inactive, etc.
-	Source     string    `db:"source" json:"source"` // chaosdb, direct, etc.
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	ID                uuid.UUID      `db:"id" json:"id"`
+	ProgramID         uuid.UUID      `db:"program_id" json:"program_id"`
+	ProgramURL        string         `db:"program_url" json:"program_url"`
+	URL               string         `db:"url" json:"url"`
+	HostKey           string         `db:"host_key" json:"host_key"` // host[:port] shared by the http and https variants
+	HostID            uuid.UUID      `db:"host_id" json:"host_id"`   // host shared with the assets of other programs with the same host key
+	Domain            string         `db:"domain" json:"domain"`
+	Subdomain         string         `db:"subdomain" json:"subdomain"`
+	IP                string         `db:"ip" json:"ip"` // IPv4 address
+	IPv6              string         `db:"ipv6" json:"ipv6"`
+	IPv4Reachable     *bool          `db:"ipv4_reachable" json:"ipv4_reachable"`           // nil when not probed over IPv4
+	IPv6Reachable     *bool          `db:"ipv6_reachable" json:"ipv6_reachable"`           // nil when not probed over IPv6
+	Liveness          string         `db:"liveness" json:"liveness"`                       // latest probe's liveness state; empty when never probed
+	LastProbeError    string         `db:"last_probe_error" json:"last_probe_error"`       // error of the most recent failed probe
+	LastProbeErrorAt  *time.Time     `db:"last_probe_error_at" json:"last_probe_error_at"` // when the most recent failed probe ran
+	LastProbedAt      *time.Time     `db:"last_probed_at" json:"last_probed_at"`           // when the asset was last probed; nil when never
+	Status            string         `db:"status" json:"status"`                           // active, inactive, quarantined, etc.
+	Source            string         `db:"source" json:"source"`                           // chaosdb, direct, etc.
+	FirstScanID       *uuid.UUID     `db:"first_scan_id" json:"first_scan_id"`             // scan that first created the asset
+	FirstSource       string         `db:"first_source" json:"first_source"`               // discovery source that first found the asset
+	LastScanID        *uuid.UUID     `db:"last_scan_id" json:"last_scan_id"`               // last scan that found or confirmed the asset
+	Ignored           bool           `db:"ignored" json:"ignored"`                         // excluded from sweeps and reports
+	ScopeMissingSince *time.Time     `db:"scope_missing_since" json:"scope_missing_since"` // when the asset's scope root left the program's scope; nil while in scope
+	Score             float64        `db:"score" json:"score"`                             // how interesting the asset is to test, see internal/scoring
+	ScoreModel        string         `db:"score_model" json:"score_model"`                 // fingerprint of the scoring model the score was computed with
+	ScoredAt          *time.Time     `db:"scored_at" json:"scored_at"`
+	ClusterID         *uuid.UUID     `db:"cluster_id" json:"cluster_id"` // cluster of near-identical responses the asset belongs to; nil when unclustered
+	Provenance        pq.StringArray `db:"provenance" json:"provenance"` // every discovery source that found the asset, first one first
+	DataTerms         pq.StringArray `db:"data_terms" json:"data_terms"` // usage terms of the data of those sources, see DATA_SOURCE_TERMS
+	CNAMEs            pq.StringArray `db:"cnames" json:"cnames"`         // CNAME chain the hostname resolved through
+	DNSDead           bool           `db:"dns_dead" json:"dns_dead"`     // the hostname did not exist or had no addresses when last resolved
+	ResolvedAt        *time.Time     `db:"resolved_at" json:"resolved_at"`
+	ContentHash       string         `db:"content_hash" json:"content_hash"`             // hash of the capture the content last changed to; empty until first captured
+	ContentChangedAt  *time.Time     `db:"content_changed_at" json:"content_changed_at"` // when the content last changed; nil when it never did
+	SyncedObservedAt  *time.Time     `db:"synced_observed_at" json:"-"`                  // when the edge agent that last synced the asset observed it; nil after a local write
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// Host is a host[:port] with what it resolved to, how it answered its latest
+// probe and its latest content. It is shared by the assets of every program
+// that includes it, one asset per program, which read these through the host.
+type Host struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	HostKey          string         `db:"host_key" json:"host_key"`
+	FirstSeen        time.Time      `db:"first_seen" json:"first_seen"`
+	LastSeen         time.Time      `db:"last_seen" json:"last_seen"` // when an asset of the host was last written
+	IP               string         `db:"ip" json:"ip"`               // IPv4 address
+	IPv6             string         `db:"ipv6" json:"ipv6"`
+	IPv4Reachable    *bool          `db:"ipv4_reachable" json:"ipv4_reachable"`
+	IPv6Reachable    *bool          `db:"ipv6_reachable" json:"ipv6_reachable"`
+	Liveness         string         `db:"liveness" json:"liveness"` // latest probe's liveness state; empty when never probed
+	LastProbeError   string         `db:"last_probe_error" json:"last_probe_error"`
+	LastProbeErrorAt *time.Time     `db:"last_probe_error_at" json:"last_probe_error_at"`
+	LastProbedAt     *time.Time     `db:"last_probed_at" json:"last_probed_at"`
+	CNAMEs           pq.StringArray `db:"cnames" json:"cnames"`
+	DNSDead          bool           `db:"dns_dead" json:"dns_dead"`
+	ResolvedAt       *time.Time     `db:"resolved_at" json:"resolved_at"`
+	ContentHash      string         `db:"content_hash" json:"content_hash"`
+	ContentChangedAt *time.Time     `db:"content_changed_at" json:"content_changed_at"`
+}
+
+// HostProgram is a program that includes a host, with its asset of the host
+type HostProgram struct {
+	ProgramID   uuid.UUID `db:"program_id" json:"program_id"`
+	ProgramName string    `db:"program_name" json:"program_name"`
+	Platform    string    `db:"platform" json:"platform"`
+	ProgramURL  string    `db:"program_url" json:"program_url"`
+	AssetID     uuid.UUID `db:"asset_id" json:"asset_id"`
+	URL         string    `db:"url" json:"url"`
+	Status      string    `db:"status" json:"status"`
+	Ignored     bool      `db:"ignored" json:"ignored"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"` // when the program's asset was created
+}
+
+// SharedHost is a host included by more than one program
+type SharedHost struct {
+	Host
+	Programs int `db:"programs" json:"programs"`
+}
+
+// AssetScoreInput is what an asset is scored on: its own state and counts of
+// what was found on it
+type AssetScoreInput struct {
+	AssetID     uuid.UUID      `db:"id"`
+	ProgramURL  string         `db:"program_url"`
+	URL         string         `db:"url"`
+	Liveness    string         `db:"liveness"`
+	Ignored     bool           `db:"ignored"`
+	CreatedAt   time.Time      `db:"created_at"`
+	Score       float64        `db:"score"`
+	ScoreModel  string         `db:"score_model"`
+	StatusCode  int            `db:"status_code"` // of the latest stored response; 0 when there is none
+	Tags        pq.StringArray `db:"tags"`
+	TLSFindings int            `db:"tls_findings"` // open TLS findings
+	RuleMatches int            `db:"rule_matches"` // distinct triage rules that matched
+	APISchemas  int            `db:"api_schemas"`
+}
+
+// AssetScore is a computed score of an asset
+type AssetScore struct {
+	AssetID uuid.UUID
+	Score   float64
+}
+
+// ResponseBody is a stored response body whose fingerprint is not computed yet
+type ResponseBody struct {
+	ID   uuid.UUID `db:"id"`
+	Body string    `db:"body"`
+}
+
+// ResponseFingerprint is the computed body fingerprint of a stored response
+type ResponseFingerprint struct {
+	ResponseID uuid.UUID
+	Simhash    int64
+}
+
+// ClusterCandidate is a live asset with the fingerprint of its latest response
+type ClusterCandidate struct {
+	AssetID    uuid.UUID `db:"asset_id"`
+	Simhash    int64     `db:"body_simhash"`
+	StatusCode int       `db:"status_code"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// ResponseCluster is a group of live assets whose latest responses are
+// near-identical, reviewed through one representative asset
+type ResponseCluster struct {
+	ID                    uuid.UUID   `db:"id" json:"id"`
+	RepresentativeAssetID uuid.UUID   `db:"representative_asset_id" json:"representative_asset_id"`
+	Simhash               int64       `db:"simhash" json:"simhash"`
+	Size                  int         `db:"size" json:"size"`               // assets in the cluster
+	StatusCode            int         `db:"status_code" json:"status_code"` // of the representative's latest response
+	CreatedAt             time.Time   `db:"created_at" json:"created_at"`
+	AssetIDs              []uuid.UUID `db:"-" json:"-"` // assets in the cluster, set when clusters are replaced
+}
+
+// ResponseClusterSummary is a cluster with its representative's URL and the
+// number of programs its assets belong to
+type ResponseClusterSummary struct {
+	ResponseCluster
+	RepresentativeURL string `db:"representative_url" json:"representative_url"`
+	Programs          int    `db:"programs" json:"programs"`
+}
+
+// AssetSchemeVariant is a scheme an asset was seen with, e.g. both http and
+// https for the same host
+type AssetSchemeVariant struct {
+	AssetID   uuid.UUID `db:"asset_id" json:"asset_id"`
+	Scheme    string    `db:"scheme" json:"scheme"`
+	URL       string    `db:"url" json:"url"`
+	FirstSeen time.Time `db:"first_seen" json:"first_seen"`
+	LastSeen  time.Time `db:"last_seen" json:"last_seen"`
 }
 
 // AssetResponse represents HTTP response information for an asset
 type AssetResponse struct {
 	ID           uuid.UUID `db:"id" json:"id"`
 	AssetID      uuid.UUID `db:"asset_id" json:"asset_id"`
+	HostID       uuid.UUID `db:"host_id" json:"host_id"` // host the response was captured from, shared by the assets of every program that includes it
+	Method       string    `db:"method" json:"method"`   // GET, or HEAD for responses captured without a body
 	StatusCode   int       `db:"status_code" json:"status_code"`
 	Headers      string    `db:"headers" json:"headers"` // JSON encoded headers
 	Body         string    `db:"body" json:"body"`
 	ResponseTime int64     `db:"response_time" json:"response_time"` // in milliseconds
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+
+	// Redirects behind the response: the status of the first response, the
+	// redirects followed, the URL they ended at, how the chain ended and the
+	// target of a meta refresh in the body
+	InitialStatusCode int    `db:"initial_status_code" json:"initial_status_code"`
+	RedirectHops      int    `db:"redirect_hops" json:"redirect_hops"`
+	FinalURL          string `db:"final_url" json:"final_url"`
+	RedirectStatus    string `db:"redirect_status" json:"redirect_status"` // followed, limit, loop, not-followed, meta-refresh; empty when not redirected
+	MetaRefresh       string `db:"meta_refresh" json:"meta_refresh"`
+
+	// BodySimhash is the fingerprint of the body responses are clustered by,
+	// see internal/cluster; nil until it is computed
+	BodySimhash *int64 `db:"body_simhash" json:"body_simhash"`
+
+	// Hashes of the body and of the status code and significant headers,
+	// compared with the previous capture to detect content changes; empty for
+	// HEAD responses and responses over another scheme than the asset's URL
+	BodyHash   string `db:"body_hash" json:"body_hash"`
+	HeaderHash string `db:"header_hash" json:"header_hash"`
+
+	// Where a body offloaded to the body store is, the SHA-256 it is stored
+	// under and its size; Body is empty for these and the fields are empty
+	// for bodies stored in the database
+	BodyRef    string `db:"body_ref" json:"body_ref,omitempty"`
+	BodySHA256 string `db:"body_sha256" json:"body_sha256,omitempty"`
+	BodySize   int    `db:"body_size" json:"body_size,omitempty"`
+
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// ResponseHashes are the content hashes of an asset's latest hashed response
+type ResponseHashes struct {
+	AssetID     uuid.UUID `db:"asset_id"`
+	BodyHash    string    `db:"body_hash"`
+	HeaderHash  string    `db:"header_hash"`
+	BodySimhash *int64    `db:"body_simhash"`
+}
+
+// APISchema is an API schema (OpenAPI/Swagger, GraphQL introspection or WADL)
+// found in an asset's HTTP response
+type APISchema struct {
+	ID            uuid.UUID  `db:"id" json:"id"`
+	AssetID       uuid.UUID  `db:"asset_id" json:"asset_id"`
+	ResponseID    *uuid.UUID `db:"response_id" json:"response_id"` // response the schema was parsed from
+	Kind          string     `db:"kind" json:"kind"`               // openapi, swagger, graphql, wadl
+	Version       string     `db:"version" json:"version"`
+	Title         string     `db:"title" json:"title"`
+	EndpointCount int        `db:"endpoint_count" json:"endpoint_count"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// APIEndpoint is a single operation listed by an API schema
+type APIEndpoint struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	SchemaID uuid.UUID `db:"schema_id" json:"schema_id"`
+	Method   string    `db:"method" json:"method"` // HTTP method, or QUERY/MUTATION/SUBSCRIPTION for GraphQL
+	Path     string    `db:"path" json:"path"`
+	Summary  string    `db:"summary" json:"summary"`
+}
+
+// AssetTag is a label attached to an asset
+type AssetTag struct {
+	AssetID   uuid.UUID `db:"asset_id" json:"asset_id"`
+	Tag       string    `db:"tag" json:"tag"`
+	Source    string    `db:"source" json:"source"` // what attached the tag, e.g. rule:grafana
+	Note      string    `db:"note" json:"note,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// TaggedAsset is a tag with the URL of the asset it is attached to
+type TaggedAsset struct {
+	AssetTag
+	URL     string `db:"url" json:"url"`
+	Ignored bool   `db:"ignored" json:"ignored"`
+}
+
+// RuleMatch records a triage rule that matched an asset response
+type RuleMatch struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	AssetID    uuid.UUID  `db:"asset_id" json:"asset_id"`
+	ResponseID *uuid.UUID `db:"response_id" json:"response_id"`
+	RuleName   string     `db:"rule_name" json:"rule_name"`
+	Tags       string     `db:"tags" json:"tags"` // comma-separated
+	Notify     string     `db:"notify" json:"notify"`
+	Enqueue    string     `db:"enqueue" json:"enqueue"` // comma-separated
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+}
+
+// TLSFinding records a TLS misconfiguration found while probing an asset
+type TLSFinding struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	AssetID    uuid.UUID  `db:"asset_id" json:"asset_id"`
+	CheckName  string     `db:"check_name" json:"check_name"` // e.g. expired-certificate, legacy-protocol
+	Severity   string     `db:"severity" json:"severity"`     // low or medium
+	Detail     string     `db:"detail" json:"detail"`
+	URL        string     `db:"url" json:"url"` // probed URL the finding was seen on
+	FirstSeen  time.Time  `db:"first_seen" json:"first_seen"`
+	LastSeen   time.Time  `db:"last_seen" json:"last_seen"`
+	ResolvedAt *time.Time `db:"resolved_at" json:"resolved_at,omitempty"` // set once a later probe no longer finds it
 }
 
 // PlatformEntity represents a bug bounty platform entity in the database
@@ -57,22 +311,486 @@ type PlatformEntity struct {
 
 // Scan represents a discovery scan session
 type Scan struct {
+	ID           uuid.UUID  `db:"id" json:"id"`
+	ProgramID    uuid.UUID  `db:"program_id" json:"program_id"`
+	Status       string     `db:"status" json:"status"` // running, completed, failed, cancelled, deferred, timed_out, aborted
+	AssetsFound  int        `db:"assets_found" json:"assets_found"`
+	AssetsSeen   int        `db:"assets_seen" json:"assets_seen"`     // assets confirmed by this scan
+	AgentVersion string     `db:"agent_version" json:"agent_version"` // version of the agent that ran the scan
+	StartedAt    time.Time  `db:"started_at" json:"started_at"`
+	CompletedAt  *time.Time `db:"completed_at" json:"completed_at"`
+	Error        string     `db:"error" json:"error"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+
+	CancelRequestedAt *time.Time `db:"cancel_requested_at" json:"cancel_requested_at,omitempty"`
+	HeartbeatAt       *time.Time `db:"heartbeat_at" json:"heartbeat_at,omitempty"`         // when the process running the scan last reported it alive
+	ScheduledAt       *time.Time `db:"scheduled_at" json:"scheduled_at,omitempty"`         // SCAN_SCHEDULE time that started the scan
+	ComparedScanID    *uuid.UUID `db:"compared_scan_id" json:"compared_scan_id,omitempty"` // scan the asset changes were computed against
+}
+
+// Asset change kinds
+const (
+	AssetAdded   = "added"
+	AssetRemoved = "removed"
+	AssetChanged = "changed"
+)
+
+// AssetChange is an asset a scan added or no longer found, or a field of an
+// asset that changed since the program's previous completed scan
+type AssetChange struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	ScanID    uuid.UUID  `db:"scan_id" json:"scan_id"`
+	ProgramID uuid.UUID  `db:"program_id" json:"program_id"`
+	AssetID   *uuid.UUID `db:"asset_id" json:"asset_id"` // nil once the asset is deleted
+	URL       string     `db:"url" json:"url"`
+	Change    string     `db:"change" json:"change"`                 // added, removed, changed
+	Field     string     `db:"field" json:"field,omitempty"`         // changed field, e.g. liveness
+	OldValue  string     `db:"old_value" json:"old_value,omitempty"` // of a changed field
+	NewValue  string     `db:"new_value" json:"new_value,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// AssetState is the part of an asset that is compared between scans
+type AssetState struct {
+	ID          uuid.UUID `db:"id"`
+	URL         string    `db:"url"`
+	Status      string    `db:"status"`
+	Liveness    string    `db:"liveness"`
+	IP          string    `db:"ip"`
+	IPv6        string    `db:"ipv6"`
+	ContentHash string    `db:"content_hash"` // empty until the asset's content was first captured
+}
+
+// Scan run statuses
+const (
+	ScanRunRunning     = "running"
+	ScanRunCompleted   = "completed"
+	ScanRunFailed      = "failed"      // finished with platform errors
+	ScanRunInterrupted = "interrupted" // stopped or timed out before finishing
+)
+
+// ScanRun is a full scan of every platform. Runs that did not complete can be
+// resumed; a run left running after the agent crashed is one of them.
+type ScanRun struct {
 	ID          uuid.UUID  `db:"id" json:"id"`
-	ProgramID   uuid.UUID  `db:"program_id" json:"program_id"`
-	Status      string     `db:"status" json:"status"` // running, completed, failed
-	AssetsFound int        `db:"assets_found" json:"assets_found"`
+	Status      string     `db:"status" json:"status"`
+	Resumes     int        `db:"resumes" json:"resumes"` // times the run was resumed
 	StartedAt   time.Time  `db:"started_at" json:"started_at"`
-	CompletedAt *time.Time `db:"completed_at" json:"completed_at"`
-	Error       string     `db:"error" json:"error"`
-	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// ScanRunPlatform is the progress of a scan run on a platform
+type ScanRunPlatform struct {
+	RunID       uuid.UUID  `db:"run_id" json:"run_id"`
+	Platform    string     `db:"platform" json:"platform"`
+	Programs    int        `db:"programs" json:"programs"`                   // programs the platform listed
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"` // set once every program was processed
+}
+
+// ScanRunProgram is a program a scan run processed
+type ScanRunProgram struct {
+	RunID       uuid.UUID `db:"run_id" json:"run_id"`
+	Platform    string    `db:"platform" json:"platform"`
+	ProgramURL  string    `db:"program_url" json:"program_url"`
+	ProcessedAt time.Time `db:"processed_at" json:"processed_at"`
+}
+
+// PlatformMaintenance is a maintenance window or outage detected on a platform API
+type PlatformMaintenance struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	Platform   string     `db:"platform" json:"platform"`
+	Reason     string     `db:"reason" json:"reason"`
+	StatusCode int        `db:"status_code" json:"status_code"`
+	StartedAt  time.Time  `db:"started_at" json:"started_at"`
+	LastSeenAt time.Time  `db:"last_seen_at" json:"last_seen_at"`
+	RetryAt    time.Time  `db:"retry_at" json:"retry_at"` // scans skip the platform until this time
+	EndedAt    *time.Time `db:"ended_at" json:"ended_at"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// SchemaDrift is a field of a platform API payload that appeared or
+// disappeared compared to the struct it is decoded into
+type SchemaDrift struct {
+	Platform    string    `db:"platform" json:"platform"`
+	Payload     string    `db:"payload" json:"payload"`
+	Field       string    `db:"field" json:"field"`
+	Change      string    `db:"change" json:"change"` // added or missing
+	FirstSeenAt time.Time `db:"first_seen_at" json:"first_seen_at"`
+	LastSeenAt  time.Time `db:"last_seen_at" json:"last_seen_at"`
+	Scans       int       `db:"scans" json:"scans"` // runs of the agent that saw the drift
+}
+
+// Watchlist states, from least to most alive
+const (
+	WatchStateDead       = "dead"       // the hostname does not resolve
+	WatchStateResolving  = "resolving"  // the hostname resolves but does not answer HTTP
+	WatchStateResponding = "responding" // the hostname answers HTTP
+)
+
+// WatchlistEntry is a hostname of interest that is checked every cycle,
+// whether or not it is alive yet
+type WatchlistEntry struct {
+	ID             uuid.UUID  `db:"id" json:"id"`
+	Hostname       string     `db:"hostname" json:"hostname"`
+	ProgramID      *uuid.UUID `db:"program_id" json:"program_id,omitempty"`
+	Note           string     `db:"note" json:"note"`
+	State          string     `db:"state" json:"state"`
+	StatusCode     int        `db:"status_code" json:"status_code"`
+	IP             string     `db:"ip" json:"ip"`
+	LastCheckedAt  *time.Time `db:"last_checked_at" json:"last_checked_at,omitempty"`
+	StateChangedAt *time.Time `db:"state_changed_at" json:"state_changed_at,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// ProgramAssetBounds overrides the asset quota bounds for one program; nil
+// fields fall back to the configured defaults
+type ProgramAssetBounds struct {
+	ProgramID      uuid.UUID `db:"program_id" json:"program_id"`
+	MaxDropPercent *float64  `db:"max_drop_percent" json:"max_drop_percent"`
+	MaxGrowth      *int      `db:"max_growth" json:"max_growth"`
+	Disabled       bool      `db:"disabled" json:"disabled"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// AssetQuotaAlert is raised when a scan's asset count leaves its program's bounds
+type AssetQuotaAlert struct {
+	ID            uuid.UUID  `db:"id" json:"id"`
+	ProgramID     uuid.UUID  `db:"program_id" json:"program_id"`
+	ScanID        *uuid.UUID `db:"scan_id" json:"scan_id"`
+	Kind          string     `db:"kind" json:"kind"` // drop, growth
+	PreviousCount int        `db:"previous_count" json:"previous_count"`
+	CurrentCount  int        `db:"current_count" json:"current_count"`
+	Threshold     float64    `db:"threshold" json:"threshold"`
+	Message       string     `db:"message" json:"message"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ProgramAlias records a previous program URL of a renamed program
+type ProgramAlias struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	ProgramID  uuid.UUID `db:"program_id" json:"program_id"`
+	Platform   string    `db:"platform" json:"platform"`
+	ProgramURL string    `db:"program_url" json:"program_url"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+// SourceYield summarizes how many assets a discovery source has found first
+type SourceYield struct {
+	Source       string    `db:"source" json:"source"`
+	Assets       int       `db:"assets" json:"assets"`
+	ActiveAssets int       `db:"active_assets" json:"active_assets"`
+	Programs     int       `db:"programs" json:"programs"`
+	LastFoundAt  time.Time `db:"last_found_at" json:"last_found_at"`
+}
+
+// OrphanCount is the number of rows in a table whose parent row no longer exists
+type OrphanCount struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Parent string `json:"parent"`
+	Action string `json:"action"` // delete, or nullify for optional references
+	Rows   int64  `json:"rows"`
+}
+
+// LivenessCount is the number of assets in one liveness state
+type LivenessCount struct {
+	Liveness string `db:"liveness" json:"liveness"`
+	Assets   int    `db:"assets" json:"assets"`
+}
+
+// ProbeErrorCount is the number of assets whose latest probe error was the same
+type ProbeErrorCount struct {
+	Liveness string `db:"liveness" json:"liveness"`
+	Error    string `db:"error" json:"error"`
+	Assets   int    `db:"assets" json:"assets"`
+}
+
+// TLSFindingCount is the number of open TLS findings for one check and severity
+type TLSFindingCount struct {
+	CheckName string `db:"check_name" json:"check_name"`
+	Severity  string `db:"severity" json:"severity"`
+	Assets    int    `db:"assets" json:"assets"`
+}
+
+// AssetSighting records an edge agent that reported an asset to the central server
+type AssetSighting struct {
+	AssetID   uuid.UUID `db:"asset_id" json:"asset_id"`
+	AgentID   string    `db:"agent_id" json:"agent_id"`
+	FirstSeen time.Time `db:"first_seen" json:"first_seen"`
+	LastSeen  time.Time `db:"last_seen" json:"last_seen"`
+}
+
+// SyncApplyResult summarizes how a synced program was merged into the central database
+type SyncApplyResult struct {
+	ProgramID      uuid.UUID `json:"program_id"`
+	ProgramUpdated bool      `json:"program_updated"`
+	AssetsCreated  int       `json:"assets_created"`
+	AssetsUpdated  int       `json:"assets_updated"`
+	AssetsSkipped  int       `json:"assets_skipped"` // older than the central copy
+}
+
+// DomainRegistration holds the registration data of an apex domain
+type DomainRegistration struct {
+	Domain       string     `db:"domain" json:"domain"`
+	Registrar    string     `db:"registrar" json:"registrar"`
+	RegisteredAt *time.Time `db:"registered_at" json:"registered_at"` // nil when the registry does not publish it
+	ExpiresAt    *time.Time `db:"expires_at" json:"expires_at"`
+	LookupError  string     `db:"lookup_error" json:"lookup_error,omitempty"`
+	CheckedAt    time.Time  `db:"checked_at" json:"checked_at"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+}
+
+// AssetHost is an asset and the URL its hostname is taken from
+type AssetHost struct {
+	ID  uuid.UUID `db:"id"`
+	URL string    `db:"url"`
+}
+
+// AssetResolution is what an asset's hostname resolved to
+type AssetResolution struct {
+	IP      string   // first A record
+	IPv6    string   // first AAAA record
+	CNAMEs  []string // CNAME chain, in the order it was followed
+	DNSDead bool     // the name did not exist or had no addresses; the asset keeps its previous addresses
+}
+
+// IPNetwork holds the network allocation data of an asset IP
+type IPNetwork struct {
+	IP          string    `db:"ip" json:"ip"`
+	Country     string    `db:"country" json:"country"` // ISO 3166 code; empty when unknown
+	Provider    string    `db:"provider" json:"provider"`
+	NetworkName string    `db:"network_name" json:"network_name"`
+	LookupError string    `db:"lookup_error" json:"lookup_error,omitempty"`
+	CheckedAt   time.Time `db:"checked_at" json:"checked_at"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// GeoCount is the number of a program's assets hosted in one country by one provider
+type GeoCount struct {
+	ProgramID   uuid.UUID `db:"program_id" json:"program_id"`
+	ProgramName string    `db:"program_name" json:"program_name"`
+	Country     string    `db:"country" json:"country"`
+	Provider    string    `db:"provider" json:"provider"`
+	Assets      int       `db:"assets" json:"assets"`
+}
+
+// Program discovery stages recorded when a program runs out of time
+const (
+	StageScope     = "scope"
+	StageDiscovery = "discovery"
+	StageProbe     = "probe"
+)
+
+// ProgramContinuation records where a program's discovery stopped when it ran
+// out of time, so the next attempt only processes the remaining domains
+type ProgramContinuation struct {
+	ProgramID        uuid.UUID      `db:"program_id" json:"program_id"`
+	ScanID           *uuid.UUID     `db:"scan_id" json:"scan_id,omitempty"`
+	Stage            string         `db:"stage" json:"stage"`   // scope, discovery or probe
+	Domain           string         `db:"domain" json:"domain"` // domain being processed when time ran out
+	RemainingDomains pq.StringArray `db:"remaining_domains" json:"remaining_domains"`
+	Attempts         int            `db:"attempts" json:"attempts"` // consecutive timeouts on Domain
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// ProbeAuthProfile holds a program's sealed probe credentials. The profile is
+// encrypted by the probeauth package; the database never sees it in the clear.
+type ProbeAuthProfile struct {
+	ProgramID     uuid.UUID `db:"program_id" json:"program_id"`
+	SealedProfile []byte    `db:"sealed_profile" json:"-"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// DefectDojoExport records a scan pushed to DefectDojo as an engagement of
+// its program's product
+type DefectDojoExport struct {
+	ScanID       uuid.UUID `db:"scan_id" json:"scan_id"`
+	ProgramID    uuid.UUID `db:"program_id" json:"program_id"`
+	ProductID    int       `db:"product_id" json:"product_id"`
+	EngagementID int       `db:"engagement_id" json:"engagement_id"`
+	Endpoints    int       `db:"endpoints" json:"endpoints"` // assets pushed as endpoints
+	Findings     int       `db:"findings" json:"findings"`
+	ExportedAt   time.Time `db:"exported_at" json:"exported_at"`
+}
+
+// ScanArtifact records a scan report stored in object storage for sharing
+type ScanArtifact struct {
+	ScanID      uuid.UUID `db:"scan_id" json:"scan_id"`
+	Format      string    `db:"format" json:"format"` // json or csv
+	Bucket      string    `db:"bucket" json:"bucket"`
+	ObjectKey   string    `db:"object_key" json:"object_key"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	SizeBytes   int64     `db:"size_bytes" json:"size_bytes"`
+	UploadedAt  time.Time `db:"uploaded_at" json:"uploaded_at"`
+}
+
+// ScanCoverage counts the subdomains of one scope domain at each step of a
+// scan: discovered, sent to the prober, returned by it and found to exist
+type ScanCoverage struct {
+	ScanID     uuid.UUID `db:"scan_id" json:"scan_id"`
+	ProgramID  uuid.UUID `db:"program_id" json:"program_id"`
+	Domain     string    `db:"domain" json:"domain"`
+	Discovered int       `db:"discovered" json:"discovered"`   // subdomains from discovery
+	Valid      int       `db:"valid" json:"valid"`             // valid hostnames sent to the prober
+	Probed     int       `db:"probed" json:"probed"`           // hosts the prober returned a result for
+	Resolved   int       `db:"resolved" json:"resolved"`       // hosts that exist, answering or not
+	Live       int       `db:"live" json:"live"`               // hosts that answered over HTTP
+	ProbeError string    `db:"probe_error" json:"probe_error"` // set when the probe failed as a whole
+	RecordedAt time.Time `db:"recorded_at" json:"recorded_at"`
+}
+
+// CoverageSummary adds up the coverage of a program, or of one of its scope
+// domains, over its most recent scans
+type CoverageSummary struct {
+	ProgramID   uuid.UUID `db:"program_id" json:"program_id"`
+	ProgramName string    `db:"program_name" json:"program_name"`
+	Platform    string    `db:"platform" json:"platform"`
+	Domain      string    `db:"domain" json:"domain,omitempty"` // empty for a whole program
+	Scans       int       `db:"scans" json:"scans"`
+	Discovered  int       `db:"discovered" json:"discovered"`
+	Valid       int       `db:"valid" json:"valid"`
+	Probed      int       `db:"probed" json:"probed"`
+	Resolved    int       `db:"resolved" json:"resolved"`
+	Live        int       `db:"live" json:"live"`
+	GapScans    int       `db:"gap_scans" json:"gap_scans"` // scans where the prober returned fewer hosts than it was given
+}
+
+// SystematicGap reports whether the prober came back short in every scan
+func (c *CoverageSummary) SystematicGap() bool {
+	return c.Scans > 1 && c.GapScans == c.Scans
+}
+
+// ProgramFreshness is how recently an active program was scanned and how many
+// of its assets were not probed recently, for freshness SLO tracking
+type ProgramFreshness struct {
+	ProgramID   uuid.UUID  `db:"program_id" json:"program_id"`
+	ProgramName string     `db:"program_name" json:"program_name"`
+	Platform    string     `db:"platform" json:"platform"`
+	ProgramURL  string     `db:"program_url" json:"program_url"`
+	LastScanAt  *time.Time `db:"last_scan_at" json:"last_scan_at"` // last completed scan; nil when never scanned
+	Assets      int        `db:"assets" json:"assets"`             // assets the liveness sweep probes
+	StaleAssets int        `db:"stale_assets" json:"stale_assets"` // of them, not probed since the cutoff
+}
+
+// NotableResponse is the latest response of an asset that stands out: it
+// matched a triage rule, has open TLS findings, failed with a server error or
+// ended in a redirect loop, at the redirect limit or in a meta refresh
+type NotableResponse struct {
+	AssetID        uuid.UUID `db:"asset_id" json:"asset_id"`
+	URL            string    `db:"url" json:"url"`
+	StatusCode     int       `db:"status_code" json:"status_code"`
+	RedirectStatus string    `db:"redirect_status" json:"redirect_status"`
+	FinalURL       string    `db:"final_url" json:"final_url"`
+	MetaRefresh    string    `db:"meta_refresh" json:"meta_refresh"`
+	Rules          string    `db:"rules" json:"rules"` // comma-separated names of the rules that matched
+	TLSFindings    int       `db:"tls_findings" json:"tls_findings"`
+	CapturedAt     time.Time `db:"captured_at" json:"captured_at"`
+}
+
+// ExportAsset is an asset as `export` writes it, with the metadata of its
+// latest stored response when responses are exported
+type ExportAsset struct {
+	Asset
+	ProgramName  string     `db:"program_name" json:"program_name"`
+	StatusCode   *int       `db:"status_code" json:"status_code"` // nil without responses or when none is stored
+	FinalURL     *string    `db:"final_url" json:"final_url"`
+	ResponseTime *int64     `db:"response_time" json:"response_time"` // in milliseconds
+	RespondedAt  *time.Time `db:"responded_at" json:"responded_at"`   // when the response was captured
+}
+
+// ExportFilter selects the assets `export` writes
+type ExportFilter struct {
+	ProgramID       *uuid.UUID // nil for the assets of every active program
+	Source          string     // primary or secondary; empty for both
+	IncludeInactive bool       // also export assets no longer seen
+	Responses       bool       // join the latest stored response of each asset
+	ContentChanged  bool       // only export assets whose content changed since their program's latest scan started
 }
 
 // Table names
 const (
-	TablePrograms       = "programs"
-	TableAssets         = "assets"
-	TableAssetResponses = "asset_responses"
-	TablePlatforms      = "platforms"
-	TableScans          = "scans"
+	TablePrograms            = "programs"
+	TableAssets              = "assets"
+	TableAssetResponses      = "asset_responses"
+	TablePlatforms           = "platforms"
+	TableScans               = "scans"
+	TableSyncState           = "sync_state"
+	TableAssetSightings      = "asset_sightings"
+	TableProgramAliases      = "program_aliases"
+	TablePlatformMaintenance = "platform_maintenance"
+	TableProgramAssetBounds  = "program_asset_bounds"
+	TableAssetQuotaAlerts    = "asset_quota_alerts"
+	TableAPISchemas          = "api_schemas"
+	TableAPIEndpoints        = "api_endpoints"
+	TableAssetTags           = "asset_tags"
+	TableRuleMatches         = "rule_matches"
+	TableDomainRegistrations = "domain_registrations"
+	TableAssetSchemeVariants = "asset_scheme_variants"
+	TableTLSFindings         = "tls_findings"
+	TableIPNetworks          = "ip_networks"
+	TableContinuations       = "program_continuations"
+	TableDefectDojoExports   = "defectdojo_exports"
+	TableScanCoverage        = "scan_coverage"
+	TableProbeAuthProfiles   = "probe_auth_profiles"
+	TableSchemaDrift         = "platform_schema_drift"
+	TableWatchlist           = "watchlist"
+	TableScanArtifacts       = "scan_artifacts"
+	TableResponseClusters    = "response_clusters"
+	TableAssetChanges        = "asset_changes"
+	TableScanRuns            = "scan_runs"
+	TableScanRunPlatforms    = "scan_run_platforms"
+	TableScanRunPrograms     = "scan_run_programs"
+	TableDiscoveryFailures   = "discovery_failures"
+	TableProgramScopes       = "program_scopes"
+	TableHosts               = "hosts"
 )
+
+// Stages of a scope domain's processing a discovery failure is recorded for
+const (
+	DiscoveryStageDiscovery = "discovery" // a subdomain discovery source failed
+	DiscoveryStageProbe     = "probe"     // the HTTPX probe failed as a whole
+)
+
+// DiscoveryFailure is a scope domain whose discovery or probe failed, waiting
+// to be retried
+type DiscoveryFailure struct {
+	ProgramID     uuid.UUID `db:"program_id" json:"program_id"`
+	ProgramName   string    `db:"program_name" json:"program_name"` // read only
+	ProgramURL    string    `db:"program_url" json:"program_url"`   // read only
+	Domain        string    `db:"domain" json:"domain"`
+	Stage         string    `db:"stage" json:"stage"`   // discovery or probe
+	Source        string    `db:"source" json:"source"` // the failed discovery sources, or httpx
+	Error         string    `db:"error" json:"error"`
+	Attempts      int       `db:"attempts" json:"attempts"` // failed attempts, the scan's included
+	FirstFailedAt time.Time `db:"first_failed_at" json:"first_failed_at"`
+	LastFailedAt  time.Time `db:"last_failed_at" json:"last_failed_at"`
+	NextRetryAt   time.Time `db:"next_retry_at" json:"next_retry_at"`
+}
+
+// ProgramScope is a snapshot of a program's scope, recorded when it differs
+// from the previous one
+type ProgramScope struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	ProgramID   uuid.UUID      `db:"program_id" json:"program_id"`
+	ScanID      *uuid.UUID     `db:"scan_id" json:"scan_id,omitempty"` // the scan that fetched it
+	ContentHash string         `db:"content_hash" json:"content_hash"` // SHA-256 of the sorted targets
+	InScope     pq.StringArray `db:"in_scope" json:"in_scope"`         // in-scope targets of every type, sorted
+	OutOfScope  pq.StringArray `db:"out_of_scope" json:"out_of_scope"` // out-of-scope entries of every type as "<type> <target>", sorted
+	RecordedAt  time.Time      `db:"recorded_at" json:"recorded_at"`
+}
+
+// ScopeTarget is an in-scope primary asset of an active program with a
+// recorded scope snapshot, with the program it belongs to
+type ScopeTarget struct {
+	ProgramID   uuid.UUID `db:"program_id"`
+	ProgramName string    `db:"program_name"`
+	ProgramURL  string    `db:"program_url"`
+	URL         string    `db:"url"`
+}
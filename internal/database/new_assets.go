@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// GetProgramNewAssetCounts counts, per program, the assets first found by the
+// program's latest completed scan. Programs without new assets are left out.
+func (r *AssetRepository) GetProgramNewAssetCounts(ctx context.Context) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		ProgramID uuid.UUID `db:"program_id"`
+		Assets    int       `db:"assets"`
+	}
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (program_id) id, program_id
+			FROM scans
+			WHERE status = 'completed'
+			ORDER BY program_id, started_at DESC
+		)
+		SELECT latest.program_id, COUNT(a.id) AS assets
+		FROM latest
+		JOIN assets a ON a.first_scan_id = latest.id
+		GROUP BY latest.program_id
+	`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to get new asset counts: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.ProgramID] = row.Assets
+	}
+	return counts, nil
+}
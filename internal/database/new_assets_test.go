@@ -0,0 +1,27 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetRepository_GetProgramNewAssetCounts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	acme, globex := uuid.New(), uuid.New()
+
+	mock.ExpectQuery("SELECT DISTINCT ON \\(program_id\\) id, program_id.+JOIN assets a ON a.first_scan_id = latest.id").
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "assets"}).AddRow(acme, 5).AddRow(globex, 1))
+
+	counts, err := repo.GetProgramNewAssetCounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int{acme: 5, globex: 1}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// NotesRepository handles the queries behind the Markdown notes export
+type NotesRepository struct {
+	*Repository
+}
+
+// NewNotesRepository creates a new notes repository
+func NewNotesRepository(db *sqlx.DB) *NotesRepository {
+	return &NotesRepository{Repository: NewRepository(db)}
+}
+
+// GetNotableResponses retrieves the latest response of each of a program's
+// assets that stands out, ordered by URL
+func (r *NotesRepository) GetNotableResponses(ctx context.Context, programID uuid.UUID) ([]*NotableResponse, error) {
+	var responses []*NotableResponse
+	query := `
+		SELECT * FROM (
+			SELECT DISTINCT ON (a.id)
+				a.id AS asset_id, a.url, r.status_code, r.redirect_status, r.final_url, r.meta_refresh,
+				COALESCE((SELECT string_agg(DISTINCT m.rule_name, ',') FROM rule_matches m WHERE m.asset_id = a.id), '') AS rules,
+				(SELECT COUNT(*) FROM tls_findings f WHERE f.asset_id = a.id AND f.resolved_at IS NULL) AS tls_findings,
+				r.created_at AS captured_at
+			FROM assets a
+			JOIN asset_responses r ON r.host_id = a.host_id
+			WHERE a.program_id = $1 AND NOT a.ignored
+			ORDER BY a.id, r.created_at DESC
+		) latest
+		WHERE rules <> '' OR tls_findings > 0 OR status_code >= 500
+			OR redirect_status IN ('loop', 'limit', 'meta-refresh')
+		ORDER BY url
+	`
+
+	err := r.db.SelectContext(ctx, &responses, query, programID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notable responses: %w", err)
+	}
+
+	return responses, nil
+}
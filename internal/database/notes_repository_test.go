@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotesRepository_GetNotableResponses(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewNotesRepository(db)
+	programID, assetID := uuid.New(), uuid.New()
+	captured := time.Now()
+
+	mock.ExpectQuery("SELECT DISTINCT ON \\(a.id\\).*FROM assets a\\s+JOIN asset_responses r ON r.host_id = a.host_id").
+		WithArgs(programID).
+		WillReturnRows(sqlmock.NewRows([]string{"asset_id", "url", "status_code", "redirect_status", "final_url", "meta_refresh", "rules", "tls_findings", "captured_at"}).
+			AddRow(assetID, "https://grafana.example.com", 200, "", "", "", "grafana", 1, captured))
+
+	responses, err := repo.GetNotableResponses(context.Background(), programID)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	assert.Equal(t, "grafana", responses[0].Rules)
+	assert.Equal(t, 1, responses[0].TLSFindings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// orphanRelation is a reference from a child table to its parent's id
+type orphanRelation struct {
+	table   string
+	column  string
+	parent  string
+	nullify bool // optional references are cleared instead of deleting the row
+}
+
+// orphanRelations lists every parent reference, parents before children so
+// that purging an orphaned row also cascades to the rows below it
+var orphanRelations = []orphanRelation{
+	{TableAssets, "program_id", TablePrograms, false},
+	{TableScans, "program_id", TablePrograms, false},
+	{TableProgramAliases, "program_id", TablePrograms, false},
+	{TableProgramAssetBounds, "program_id", TablePrograms, false},
+	{TableAssetQuotaAlerts, "program_id", TablePrograms, false},
+	{TableAssets, "first_scan_id", TableScans, true},
+	{TableAssets, "last_scan_id", TableScans, true},
+	{TableScans, "compared_scan_id", TableScans, true},
+	{TableAssetChanges, "program_id", TablePrograms, false},
+	{TableAssetChanges, "scan_id", TableScans, false},
+	{TableAssetChanges, "asset_id", TableAssets, true},
+	{TableAssetQuotaAlerts, "scan_id", TableScans, true},
+	{TableContinuations, "program_id", TablePrograms, false},
+	{TableContinuations, "scan_id", TableScans, true},
+	{TableDefectDojoExports, "program_id", TablePrograms, false},
+	{TableDefectDojoExports, "scan_id", TableScans, false},
+	{TableScanCoverage, "program_id", TablePrograms, false},
+	{TableScanCoverage, "scan_id", TableScans, false},
+	{TableScanArtifacts, "scan_id", TableScans, false},
+	{TableProbeAuthProfiles, "program_id", TablePrograms, false},
+	{TableScanRunPlatforms, "run_id", TableScanRuns, false},
+	{TableScanRunPrograms, "run_id", TableScanRuns, false},
+	{TableDiscoveryFailures, "program_id", TablePrograms, false},
+	{TableProgramScopes, "program_id", TablePrograms, false},
+	{TableProgramScopes, "scan_id", TableScans, true},
+	{TableWatchlist, "program_id", TablePrograms, true},
+	{TableAssetResponses, "asset_id", TableAssets, false},
+	{TableAssetSightings, "asset_id", TableAssets, false},
+	{TableAssetSchemeVariants, "asset_id", TableAssets, false},
+	{TableAssetTags, "asset_id", TableAssets, false},
+	{TableTLSFindings, "asset_id", TableAssets, false},
+	{TableRuleMatches, "asset_id", TableAssets, false},
+	{TableRuleMatches, "response_id", TableAssetResponses, true},
+	{TableAPISchemas, "asset_id", TableAssets, false},
+	{TableAPISchemas, "response_id", TableAssetResponses, true},
+	{TableAPIEndpoints, "schema_id", TableAPISchemas, false},
+}
+
+// orphanCondition matches rows whose reference points at a missing parent
+func (o orphanRelation) orphanCondition() string {
+	return fmt.Sprintf("%s IS NOT NULL AND NOT EXISTS (SELECT 1 FROM %s p WHERE p.id = %s.%s)",
+		o.column, o.parent, o.table, o.column)
+}
+
+// count builds the OrphanCount for this relation
+func (o orphanRelation) count(rows int64) *OrphanCount {
+	action := "delete"
+	if o.nullify {
+		action = "nullify"
+	}
+	return &OrphanCount{Table: o.table, Column: o.column, Parent: o.parent, Action: action, Rows: rows}
+}
+
+// OrphanRepository finds and purges rows left dangling by deletes that ran
+// without cascading foreign keys
+type OrphanRepository struct {
+	*Repository
+}
+
+// NewOrphanRepository creates a new orphan repository
+func NewOrphanRepository(db *sqlx.DB) *OrphanRepository {
+	return &OrphanRepository{Repository: NewRepository(db)}
+}
+
+// FindOrphans counts the orphaned rows of every parent reference
+func (r *OrphanRepository) FindOrphans(ctx context.Context) ([]*OrphanCount, error) {
+	counts := make([]*OrphanCount, 0, len(orphanRelations))
+	for _, relation := range orphanRelations {
+		var rows int64
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", relation.table, relation.orphanCondition())
+		if err := r.db.GetContext(ctx, &rows, query); err != nil {
+			return nil, fmt.Errorf("failed to count orphaned %s.%s rows: %w", relation.table, relation.column, err)
+		}
+		counts = append(counts, relation.count(rows))
+	}
+
+	return counts, nil
+}
+
+// PurgeOrphans deletes orphaned rows, or clears optional references to
+// missing parents, in one transaction and then validates the foreign keys
+// that migration 015 added without checking existing rows
+func (r *OrphanRepository) PurgeOrphans(ctx context.Context) ([]*OrphanCount, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Track if we've committed the transaction
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	counts := make([]*OrphanCount, 0, len(orphanRelations))
+	for _, relation := range orphanRelations {
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s", relation.table, relation.orphanCondition())
+		if relation.nullify {
+			query = fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s", relation.table, relation.column, relation.orphanCondition())
+		}
+
+		result, err := tx.ExecContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge orphaned %s.%s rows: %w", relation.table, relation.column, err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		counts = append(counts, relation.count(rows))
+	}
+
+	var unvalidated []struct {
+		Table      string `db:"table_name"`
+		Constraint string `db:"constraint_name"`
+	}
+	err = tx.SelectContext(ctx, &unvalidated, `
+		SELECT conrelid::regclass::text AS table_name, conname AS constraint_name
+		FROM pg_constraint
+		WHERE contype = 'f' AND NOT convalidated AND conname LIKE 'fk\_%'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unvalidated foreign keys: %w", err)
+	}
+
+	for _, key := range unvalidated {
+		query := fmt.Sprintf(`ALTER TABLE %q VALIDATE CONSTRAINT %q`, key.Table, key.Constraint)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return nil, fmt.Errorf("failed to validate foreign key %s: %w", key.Constraint, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return counts, nil
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrphanRepository_FindOrphans(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewOrphanRepository(db)
+
+	for i, relation := range orphanRelations {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM " + relation.table + " WHERE " + relation.column + " IS NOT NULL AND NOT EXISTS").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(i)))
+	}
+
+	counts, err := repo.FindOrphans(context.Background())
+	require.NoError(t, err)
+	require.Len(t, counts, len(orphanRelations))
+
+	assert.Equal(t, &OrphanCount{Table: TableAssets, Column: "program_id", Parent: TablePrograms, Action: "delete", Rows: 0}, counts[0])
+	assert.Equal(t, &OrphanCount{Table: TableAssets, Column: "first_scan_id", Parent: TableScans, Action: "nullify", Rows: 5}, counts[5])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrphanRepository_PurgeOrphans(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewOrphanRepository(db)
+
+	mock.ExpectBegin()
+	for _, relation := range orphanRelations {
+		query := "DELETE FROM " + relation.table + " WHERE"
+		if relation.nullify {
+			query = "UPDATE " + relation.table + " SET " + relation.column + " = NULL WHERE"
+		}
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 2))
+	}
+	mock.ExpectQuery("SELECT conrelid::regclass::text AS table_name").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "constraint_name"}).AddRow("assets", "fk_assets_program_id"))
+	mock.ExpectExec(`ALTER TABLE "assets" VALIDATE CONSTRAINT "fk_assets_program_id"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	counts, err := repo.PurgeOrphans(context.Background())
+	require.NoError(t, err)
+	require.Len(t, counts, len(orphanRelations))
+	for _, count := range counts {
+		assert.Equal(t, int64(2), count.Rows)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrphanRepository_PurgeOrphans_RollsBackOnError(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewOrphanRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM assets WHERE").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	_, err := repo.PurgeOrphans(context.Background())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ProbeAuthRepository handles probe auth profile database operations
+type ProbeAuthRepository struct {
+	*Repository
+}
+
+// NewProbeAuthRepository creates a new probe auth repository
+func NewProbeAuthRepository(db *sqlx.DB) *ProbeAuthRepository {
+	return &ProbeAuthRepository{Repository: NewRepository(db)}
+}
+
+// GetProbeAuthProfile retrieves a program's probe auth profile, or nil if it has none
+func (r *ProbeAuthRepository) GetProbeAuthProfile(ctx context.Context, programID uuid.UUID) (*ProbeAuthProfile, error) {
+	var profile ProbeAuthProfile
+	query := `SELECT * FROM probe_auth_profiles WHERE program_id = $1`
+
+	err := r.db.GetContext(ctx, &profile, query, programID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get probe auth profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// SaveProbeAuthProfile creates or replaces a program's probe auth profile
+func (r *ProbeAuthRepository) SaveProbeAuthProfile(ctx context.Context, profile *ProbeAuthProfile) error {
+	query := `
+		INSERT INTO probe_auth_profiles (program_id, sealed_profile, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (program_id) DO UPDATE SET
+			sealed_profile = EXCLUDED.sealed_profile,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, profile.ProgramID, profile.SealedProfile).
+		Scan(&profile.CreatedAt, &profile.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save probe auth profile: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProbeAuthProfile removes a program's probe auth profile. It reports
+// whether the program had one.
+func (r *ProbeAuthRepository) DeleteProbeAuthProfile(ctx context.Context, programID uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM probe_auth_profiles WHERE program_id = $1`, programID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete probe auth profile: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete probe auth profile: %w", err)
+	}
+
+	return rows > 0, nil
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeAuthRepository_GetProbeAuthProfile(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewProbeAuthRepository(db)
+	programID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM probe_auth_profiles WHERE program_id = \\$1").
+		WithArgs(programID).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "sealed_profile", "created_at", "updated_at"}).
+			AddRow(programID, []byte("sealed"), now, now))
+
+	profile, err := repo.GetProbeAuthProfile(context.Background(), programID)
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.Equal(t, []byte("sealed"), profile.SealedProfile)
+
+	mock.ExpectQuery("SELECT \\* FROM probe_auth_profiles").
+		WithArgs(programID).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "sealed_profile", "created_at", "updated_at"}))
+
+	profile, err = repo.GetProbeAuthProfile(context.Background(), programID)
+	require.NoError(t, err)
+	assert.Nil(t, profile)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProbeAuthRepository_SaveProbeAuthProfile(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewProbeAuthRepository(db)
+	profile := &ProbeAuthProfile{ProgramID: uuid.New(), SealedProfile: []byte("sealed")}
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO probe_auth_profiles").
+		WithArgs(profile.ProgramID, []byte("sealed")).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+
+	require.NoError(t, repo.SaveProbeAuthProfile(context.Background(), profile))
+	assert.Equal(t, now, profile.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProbeAuthRepository_DeleteProbeAuthProfile(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewProbeAuthRepository(db)
+	programID := uuid.New()
+
+	mock.ExpectExec("DELETE FROM probe_auth_profiles WHERE program_id = \\$1").
+		WithArgs(programID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	deleted, err := repo.DeleteProbeAuthProfile(context.Background(), programID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,38 @@
+package database
+
+// Sources returns the discovery sources that found the asset. Assets saved
+// before provenance was recorded only know the source that found them first.
+func (a *Asset) Sources() []string {
+	if len(a.Provenance) > 0 {
+		return a.Provenance
+	}
+	if a.FirstSource != "" {
+		return []string{a.FirstSource}
+	}
+	return nil
+}
+
+// ExcludeSources leaves out the assets that only excluded sources found, so
+// exports do not pass on data under those sources' terms. An asset another
+// source found as well is kept.
+func ExcludeSources(assets []*Asset, excluded []string) []*Asset {
+	if len(excluded) == 0 {
+		return assets
+	}
+
+	skip := make(map[string]bool, len(excluded))
+	for _, source := range excluded {
+		skip[source] = true
+	}
+
+	kept := make([]*Asset, 0, len(assets))
+	for _, asset := range assets {
+		for _, source := range asset.Sources() {
+			if !skip[source] {
+				kept = append(kept, asset)
+				break
+			}
+		}
+	}
+	return kept
+}
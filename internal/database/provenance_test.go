@@ -0,0 +1,21 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcludeSources(t *testing.T) {
+	chaos := &Asset{URL: "https://dev.example.com", Provenance: pq.StringArray{"chaosdb"}}
+	both := &Asset{URL: "https://www.example.com", Provenance: pq.StringArray{"chaosdb", "hackerone"}}
+	legacy := &Asset{URL: "https://old.example.com", FirstSource: "chaosdb"}
+	scope := &Asset{URL: "https://example.com", FirstSource: "hackerone"}
+	assets := []*Asset{chaos, both, legacy, scope}
+
+	assert.Equal(t, assets, ExcludeSources(assets, nil))
+	assert.Equal(t, []*Asset{both, scope}, ExcludeSources(assets, []string{"chaosdb"}))
+	assert.Empty(t, ExcludeSources(assets, []string{"chaosdb", "hackerone"}))
+	assert.Equal(t, []string{"chaosdb"}, legacy.Sources())
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// QuotaRepository handles asset quota bounds and alert database operations
+type QuotaRepository struct {
+	*Repository
+}
+
+// NewQuotaRepository creates a new quota repository
+func NewQuotaRepository(db *sqlx.DB) *QuotaRepository {
+	return &QuotaRepository{Repository: NewRepository(db)}
+}
+
+// GetBounds retrieves a program's asset quota overrides, or nil if it has none
+func (r *QuotaRepository) GetBounds(ctx context.Context, programID uuid.UUID) (*ProgramAssetBounds, error) {
+	var bounds ProgramAssetBounds
+	query := `SELECT * FROM program_asset_bounds WHERE program_id = $1`
+
+	err := r.db.GetContext(ctx, &bounds, query, programID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get asset bounds: %w", err)
+	}
+
+	return &bounds, nil
+}
+
+// SetBounds creates or replaces a program's asset quota overrides
+func (r *QuotaRepository) SetBounds(ctx context.Context, bounds *ProgramAssetBounds) error {
+	now := time.Now()
+	bounds.CreatedAt = now
+	bounds.UpdatedAt = now
+
+	query := `
+		INSERT INTO program_asset_bounds (program_id, max_drop_percent, max_growth, disabled, created_at, updated_at)
+		VALUES (:program_id, :max_drop_percent, :max_growth, :disabled, :created_at, :updated_at)
+		ON CONFLICT (program_id) DO UPDATE SET
+			max_drop_percent = EXCLUDED.max_drop_percent,
+			max_growth = EXCLUDED.max_growth,
+			disabled = EXCLUDED.disabled,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, bounds)
+	if err != nil {
+		return fmt.Errorf("failed to set asset bounds: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAlert records an asset quota alert
+func (r *QuotaRepository) CreateAlert(ctx context.Context, alert *AssetQuotaAlert) error {
+	alert.ID = uuid.New()
+	alert.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO asset_quota_alerts (id, program_id, scan_id, kind, previous_count, current_count, threshold, message, created_at)
+		VALUES (:id, :program_id, :scan_id, :kind, :previous_count, :current_count, :threshold, :message, :created_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, alert)
+	if err != nil {
+		return fmt.Errorf("failed to create quota alert: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecentAlerts retrieves the most recent asset quota alerts
+func (r *QuotaRepository) GetRecentAlerts(ctx context.Context, limit int) ([]*AssetQuotaAlert, error) {
+	var alerts []*AssetQuotaAlert
+	query := `SELECT * FROM asset_quota_alerts ORDER BY created_at DESC LIMIT $1`
+
+	err := r.db.SelectContext(ctx, &alerts, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota alerts: %w", err)
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RegistrationRepository handles domain registration database operations
+type RegistrationRepository struct {
+	*Repository
+}
+
+// NewRegistrationRepository creates a new registration repository
+func NewRegistrationRepository(db *sqlx.DB) *RegistrationRepository {
+	return &RegistrationRepository{Repository: NewRepository(db)}
+}
+
+// GetRegistration retrieves the stored registration data of a domain, or nil if it was never looked up
+func (r *RegistrationRepository) GetRegistration(ctx context.Context, domain string) (*DomainRegistration, error) {
+	var registration DomainRegistration
+	query := `SELECT * FROM domain_registrations WHERE domain = $1`
+
+	err := r.db.GetContext(ctx, &registration, query, domain)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get domain registration: %w", err)
+	}
+
+	return &registration, nil
+}
+
+// SaveRegistration creates or refreshes the registration data of a domain
+func (r *RegistrationRepository) SaveRegistration(ctx context.Context, registration *DomainRegistration) error {
+	registration.CheckedAt = time.Now()
+
+	query := `
+		INSERT INTO domain_registrations (domain, registrar, registered_at, expires_at, lookup_error, checked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (domain) DO UPDATE SET
+			registrar = EXCLUDED.registrar,
+			registered_at = EXCLUDED.registered_at,
+			expires_at = EXCLUDED.expires_at,
+			lookup_error = EXCLUDED.lookup_error,
+			checked_at = EXCLUDED.checked_at
+		RETURNING created_at
+	`
+
+	err := r.db.GetContext(ctx, &registration.CreatedAt, query, registration.Domain, registration.Registrar,
+		registration.RegisteredAt, registration.ExpiresAt, registration.LookupError, registration.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save domain registration: %w", err)
+	}
+
+	return nil
+}
+
+// GetRegistrationsSince retrieves domains registered at or after the given time, newest first
+func (r *RegistrationRepository) GetRegistrationsSince(ctx context.Context, since time.Time) ([]*DomainRegistration, error) {
+	var registrations []*DomainRegistration
+	query := `SELECT * FROM domain_registrations WHERE registered_at >= $1 ORDER BY registered_at DESC`
+
+	err := r.db.SelectContext(ctx, &registrations, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent domain registrations: %w", err)
+	}
+
+	return registrations, nil
+}
@@ -3,17 +3,31 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/version"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	// ErrScanNotFound is returned when a scan does not exist
+	ErrScanNotFound = errors.New("scan not found")
+	// ErrScanNotRunning is returned when cancelling a scan that already finished
+	ErrScanNotRunning = errors.New("scan is not running")
+)
+
 // Repository provides database operations
 type Repository struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	metrics *metrics.Metrics // records the duration of the scan's hot-path operations; nil disables it
 }
 
 // NewRepository creates a new repository instance
@@ -26,6 +40,17 @@ func (r *Repository) GetDB() *sqlx.DB {
 	return r.db
 }
 
+// SetMetrics records the repository's operations in m
+func (r *Repository) SetMetrics(m *metrics.Metrics) {
+	r.metrics = m
+}
+
+// observe records an operation on a table that started at start; deferred
+// at the top of the operations that are measured
+func (r *Repository) observe(operation, table string, start time.Time) {
+	r.metrics.RecordDatabaseOperation(operation, table, time.Since(start))
+}
+
 // ProgramRepository provides program-specific database operations
 type ProgramRepository struct {
 	*Repository
@@ -60,14 +85,19 @@ func NewScanRepository(db *sqlx.DB) *ScanRepository {
 
 // CreateProgram creates a new program
 func (r *ProgramRepository) CreateProgram(ctx context.Context, program *Program) error {
+	defer r.observe("insert", TablePrograms, time.Now())
+
 	program.ID = uuid.New()
 	program.CreatedAt = time.Now()
 	program.UpdatedAt = time.Now()
 	program.LastUpdated = time.Now()
+	if program.Visibility == "" {
+		program.Visibility = ProgramVisibilityPublic
+	}
 
 	query := `
-		INSERT INTO programs (id, name, platform, url, program_url, is_active, last_updated, created_at, updated_at)
-		VALUES (:id, :name, :platform, :url, :program_url, :is_active, :last_updated, :created_at, :updated_at)
+		INSERT INTO programs (id, name, platform, platform_id, url, program_url, is_active, last_updated, created_at, updated_at, visibility)
+		VALUES (:id, :name, :platform, :platform_id, :url, :program_url, :is_active, :last_updated, :created_at, :updated_at, :visibility)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, program)
@@ -126,6 +156,129 @@ func (r *ProgramRepository) GetProgramByPlatformAndProgramURL(ctx context.Contex
 	return &program, nil
 }
 
+// GetProgramByPlatformID retrieves a program by platform and its stable platform-side ID
+func (r *ProgramRepository) GetProgramByPlatformID(ctx context.Context, platform, platformID string) (*Program, error) {
+	if platformID == "" {
+		return nil, nil
+	}
+
+	var program Program
+	query := `SELECT * FROM programs WHERE platform = $1 AND platform_id = $2 ORDER BY updated_at DESC LIMIT 1`
+
+	err := r.db.GetContext(ctx, &program, query, platform, platformID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get program by platform id: %w", err)
+	}
+
+	return &program, nil
+}
+
+// ResolveProgramByURL retrieves a program by its current program URL, falling
+// back to the URLs it was known by before being renamed
+func (r *ProgramRepository) ResolveProgramByURL(ctx context.Context, programURL string) (*Program, error) {
+	var program Program
+	query := `
+		SELECT p.* FROM programs p WHERE p.program_url = $1
+		UNION ALL
+		SELECT p.* FROM programs p
+		JOIN program_aliases pa ON pa.program_id = p.id
+		WHERE pa.program_url = $1
+		LIMIT 1
+	`
+
+	err := r.db.GetContext(ctx, &program, query, programURL)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve program: %w", err)
+	}
+
+	return &program, nil
+}
+
+// GetProgramAliases retrieves the previous program URLs of a program
+func (r *ProgramRepository) GetProgramAliases(ctx context.Context, programID uuid.UUID) ([]*ProgramAlias, error) {
+	var aliases []*ProgramAlias
+	query := `SELECT * FROM program_aliases WHERE program_id = $1 ORDER BY created_at`
+
+	err := r.db.SelectContext(ctx, &aliases, query, programID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program aliases: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// RenameProgram moves a program to a new program URL in place, keeping the old
+// URL as an alias so existing assets and history stay attached to the program
+func (r *ProgramRepository) RenameProgram(ctx context.Context, program *Program, newProgramURL string) error {
+	oldProgramURL := program.ProgramURL
+	if oldProgramURL == newProgramURL {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Track if we've committed the transaction
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO program_aliases (id, program_id, platform, program_url, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (platform, program_url) DO UPDATE SET program_id = EXCLUDED.program_id
+	`, uuid.New(), program.ID, program.Platform, oldProgramURL)
+	if err != nil {
+		return fmt.Errorf("failed to record program alias: %w", err)
+	}
+
+	// A program renamed back to an earlier handle no longer needs that alias
+	_, err = tx.ExecContext(ctx, `DELETE FROM program_aliases WHERE platform = $1 AND program_url = $2`,
+		program.Platform, newProgramURL)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale program alias: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE programs
+		SET program_url = $1, url = CASE WHEN url = $2 THEN $1 ELSE url END, updated_at = NOW()
+		WHERE id = $3
+	`, newProgramURL, oldProgramURL, program.ID)
+	if err != nil {
+		return fmt.Errorf("failed to rename program: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE assets SET program_url = $1, updated_at = NOW() WHERE program_id = $2`,
+		newProgramURL, program.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update asset program urls: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	if program.URL == oldProgramURL {
+		program.URL = newProgramURL
+	}
+	program.ProgramURL = newProgramURL
+	return nil
+}
+
 // GetAllActivePrograms retrieves all active programs
 func (r *ProgramRepository) GetAllActivePrograms(ctx context.Context) ([]*Program, error) {
 	var programs []*Program
@@ -152,15 +305,37 @@ func (r *ProgramRepository) GetProgramsByPlatform(ctx context.Context, platform
 	return programs, nil
 }
 
+// FindProgramsByHandle retrieves active programs whose name or program URL
+// handle (the last path segment, e.g. "slack" for hackerone.com/slack)
+// matches, case-insensitively
+func (r *ProgramRepository) FindProgramsByHandle(ctx context.Context, handle string) ([]*Program, error) {
+	var programs []*Program
+	query := `
+		SELECT * FROM programs
+		WHERE is_active = true
+		  AND (LOWER(name) = LOWER($1) OR LOWER(regexp_replace(rtrim(program_url, '/'), '^.*/', '')) = LOWER($1))
+		ORDER BY platform, name
+	`
+
+	err := r.db.SelectContext(ctx, &programs, query, handle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find programs by handle: %w", err)
+	}
+
+	return programs, nil
+}
+
 // UpdateProgram updates a program
 func (r *ProgramRepository) UpdateProgram(ctx context.Context, program *Program) error {
+	defer r.observe("update", TablePrograms, time.Now())
+
 	program.UpdatedAt = time.Now()
 	program.LastUpdated = time.Now()
 
 	query := `
 		UPDATE programs 
-		SET name = :name, platform = :platform, url = :url, program_url = :program_url, 
-		    is_active = :is_active, last_updated = :last_updated, updated_at = :updated_at
+		SET name = :name, platform = :platform, platform_id = :platform_id, url = :url, program_url = :program_url, 
+		    is_active = :is_active, visibility = :visibility, last_updated = :last_updated, updated_at = :updated_at
 		WHERE id = :id
 	`
 
@@ -202,7 +377,9 @@ func (r *ProgramRepository) MarkProgramInactive(ctx context.Context, id uuid.UUI
 	return nil
 }
 
-// DeleteProgram deletes a program and all associated assets
+// DeleteProgram deletes a program together with its assets, scans and
+// everything that references them, through the ON DELETE CASCADE keys that
+// migration 015 guarantees
 func (r *ProgramRepository) DeleteProgram(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM programs WHERE id = $1`
 
@@ -225,35 +402,113 @@ func (r *ProgramRepository) DeleteProgram(ctx context.Context, id uuid.UUID) err
 
 // Asset Operations
 
-// CreateAsset creates a new asset
-func (r *AssetRepository) CreateAsset(ctx context.Context, asset *Asset) error {
-	asset.ID = uuid.New()
-	asset.CreatedAt = time.Now()
-	asset.UpdatedAt = time.Now()
-
-	query := `
-		INSERT INTO assets (id, program_id, program_url, url, domain, subdomain, ip, status, source, created_at, updated_at)
-		VALUES (:id, :program_id, :program_url, :url, :domain, :subdomain, :ip, :status, :source, :created_at, :updated_at)
-		ON CONFLICT (program_id, url) DO UPDATE SET
+// hostStateColumns are the columns of an asset that are its host's: what it
+// resolved to, how it answered its latest probe and its latest content,
+// selected from hosts aliased as h
+const hostStateColumns = `h.ip, h.ipv6, h.ipv4_reachable, h.ipv6_reachable, h.liveness, h.last_probe_error, h.last_probe_error_at,
+	h.last_probed_at, h.cnames, h.dns_dead, h.resolved_at, h.content_hash, h.content_changed_at`
+
+// selectAssets selects assets, aliased as a, with the state of their host
+const selectAssets = `SELECT a.*, ` + hostStateColumns + ` FROM assets a JOIN hosts h ON h.id = a.host_id`
+
+// upsertAssetQuery inserts an asset or updates the existing asset with the same
+// host, preferring the https URL, and records the URL's scheme variant. The
+// source that found the asset this time (first_source of the incoming asset)
+// and its data terms are added to the asset's provenance, and the scan that
+// found it (first_scan_id of the incoming asset) becomes its last scan. It
+// returns the ID of the stored asset. The state of its host is written by
+// upsertHostsQuery. Literal colons are written as :: so sqlx does not read
+// them as named parameters.
+const upsertAssetQuery = `
+	WITH upserted AS (
+		INSERT INTO assets (id, program_id, program_url, url, host_key, domain, subdomain, status, source, first_scan_id, last_scan_id, first_source, provenance, data_terms, created_at, updated_at)
+		VALUES (:id, :program_id, :program_url, :url, :host_key, :domain, :subdomain, :status, :source, :first_scan_id, :first_scan_id, :first_source, ARRAY[:first_source], :data_terms, :created_at, :updated_at)
+		ON CONFLICT (program_id, host_key) DO UPDATE SET
 			program_url = EXCLUDED.program_url,
+			url = CASE WHEN EXCLUDED.url LIKE 'https:://%' THEN EXCLUDED.url ELSE assets.url END,
 			domain = EXCLUDED.domain,
 			subdomain = EXCLUDED.subdomain,
-			ip = EXCLUDED.ip,
 			status = EXCLUDED.status,
 			source = EXCLUDED.source,
+			last_scan_id = COALESCE(EXCLUDED.last_scan_id, assets.last_scan_id),
+			provenance = CASE WHEN EXCLUDED.first_source = ANY(assets.provenance) THEN assets.provenance ELSE array_append(assets.provenance, EXCLUDED.first_source::::text) END,
+			data_terms = ARRAY(SELECT DISTINCT term FROM unnest(assets.data_terms || EXCLUDED.data_terms) AS term ORDER BY term),
 			updated_at = NOW()
-	`
+		RETURNING id
+	), variant AS (
+		INSERT INTO asset_scheme_variants (asset_id, scheme, url, first_seen, last_seen)
+		SELECT id, lower(split_part(:url, ':://', 1)), :url, NOW(), NOW() FROM upserted
+		ON CONFLICT (asset_id, scheme) DO UPDATE SET
+			url = EXCLUDED.url,
+			last_seen = NOW()
+	)
+	SELECT id FROM upserted
+`
+
+// hostUpsertParams is the number of parameters bound for each host of
+// upsertHostsQuery
+const hostUpsertParams = 9
+
+// upsertHostsQuery builds the statement writing the address and probe state
+// rows assets with distinct host keys were found with to their hosts,
+// creating the hosts no program included yet. A probe that did not run keeps
+// the host's liveness, and a host keeps its last probe error when the asset
+// has none.
+func upsertHostsQuery(rows int) string {
+	values := make([]string, rows)
+	for i := range values {
+		params := make([]string, hostUpsertParams)
+		for j := range params {
+			params[j] = fmt.Sprintf("$%d", i*hostUpsertParams+j+1)
+		}
+		values[i] = "(" + strings.Join(params, ", ") + ")"
+	}
 
-	_, err := r.db.NamedExecContext(ctx, query, asset)
-	if err != nil {
-		return fmt.Errorf("failed to create asset: %w", err)
+	return fmt.Sprintf(`
+	INSERT INTO hosts (host_key, ip, ipv6, ipv4_reachable, ipv6_reachable, liveness, last_probe_error, last_probe_error_at, last_probed_at)
+	VALUES %s
+	ON CONFLICT (host_key) DO UPDATE SET
+		ip = EXCLUDED.ip,
+		ipv6 = EXCLUDED.ipv6,
+		ipv4_reachable = EXCLUDED.ipv4_reachable,
+		ipv6_reachable = EXCLUDED.ipv6_reachable,
+		liveness = CASE WHEN EXCLUDED.liveness <> '' THEN EXCLUDED.liveness ELSE hosts.liveness END,
+		last_probe_error = CASE WHEN EXCLUDED.last_probe_error_at IS NOT NULL THEN EXCLUDED.last_probe_error ELSE hosts.last_probe_error END,
+		last_probe_error_at = COALESCE(EXCLUDED.last_probe_error_at, hosts.last_probe_error_at),
+		last_probed_at = COALESCE(EXCLUDED.last_probed_at, hosts.last_probed_at),
+		last_seen = NOW()
+`, strings.Join(values, ",\n\t\t"))
+}
+
+// upsertHostArgs returns the parameters of an asset's host in
+// upsertHostsQuery
+func upsertHostArgs(asset *Asset) []interface{} {
+	return []interface{}{
+		asset.HostKey, asset.IP, asset.IPv6, asset.IPv4Reachable, asset.IPv6Reachable, asset.Liveness,
+		asset.LastProbeError, asset.LastProbeErrorAt, asset.LastProbedAt,
 	}
+}
 
-	return nil
+// prepareAsset sets the fields an asset needs before it is upserted
+func prepareAsset(asset *Asset) {
+	asset.ID = uuid.New()
+	asset.HostKey = AssetHostKey(asset.URL)
+	asset.CreatedAt = time.Now()
+	asset.UpdatedAt = time.Now()
+	if asset.FirstSource == "" {
+		asset.FirstSource = asset.Source
+	}
+	if asset.DataTerms == nil {
+		asset.DataTerms = pq.StringArray{}
+	}
 }
 
-// CreateAssets creates multiple assets in a transaction
-func (r *AssetRepository) CreateAssets(ctx context.Context, assets []*Asset) error {
+// CreateAsset creates a new asset, or updates the existing asset with the same host
+func (r *AssetRepository) CreateAsset(ctx context.Context, asset *Asset) error {
+	defer r.observe("upsert", TableAssets, time.Now())
+
+	prepareAsset(asset)
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -269,27 +524,145 @@ func (r *AssetRepository) CreateAssets(ctx context.Context, assets []*Asset) err
 		}
 	}()
 
-	query := `
-		INSERT INTO assets (id, program_id, program_url, url, domain, subdomain, ip, status, source, created_at, updated_at)
-		VALUES (:id, :program_id, :program_url, :url, :domain, :subdomain, :ip, :status, :source, :created_at, :updated_at)
-		ON CONFLICT (program_id, url) DO UPDATE SET
+	if _, err := tx.ExecContext(ctx, upsertHostsQuery(1), upsertHostArgs(asset)...); err != nil {
+		return fmt.Errorf("failed to update host of asset: %w", err)
+	}
+
+	stmt, err := tx.PrepareNamedContext(ctx, upsertAssetQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare asset upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	// On conflict the stored asset keeps its original ID
+	if err := stmt.GetContext(ctx, &asset.ID, asset); err != nil {
+		return fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return nil
+}
+
+// maxAssetUpsertRows caps the assets written by one multi-row upsert, keeping
+// its parameters well below the Postgres limit of 65535
+const maxAssetUpsertRows = 1000
+
+// assetUpsertParams is the number of parameters bound for each asset of a
+// multi-row upsert
+const assetUpsertParams = 14
+
+// upsertAssetsQuery builds the multi-row form of upsertAssetQuery for rows
+// assets with distinct host keys, whose hosts are written first by
+// upsertHostsQuery. The URL each asset was found with is passed
+// again as two arrays, by host key, to record its scheme variant. It returns
+// the ID and host key of every stored asset.
+func upsertAssetsQuery(rows int) string {
+	values := make([]string, rows)
+	for i := range values {
+		n := i * assetUpsertParams
+		p := func(offset int) string { return fmt.Sprintf("$%d", n+offset) }
+		values[i] = "(" + strings.Join([]string{
+			p(1), p(2), p(3), p(4), p(5), p(6), p(7), p(8), p(9),
+			p(10), p(10), p(11), "ARRAY[" + p(11) + "]", p(12), p(13), p(14),
+		}, ", ") + ")"
+	}
+	hostKeys := rows*assetUpsertParams + 1
+
+	return fmt.Sprintf(`
+	WITH upserted AS (
+		INSERT INTO assets (id, program_id, program_url, url, host_key, domain, subdomain, status, source, first_scan_id, last_scan_id, first_source, provenance, data_terms, created_at, updated_at)
+		VALUES %s
+		ON CONFLICT (program_id, host_key) DO UPDATE SET
 			program_url = EXCLUDED.program_url,
+			url = CASE WHEN EXCLUDED.url LIKE 'https://%%' THEN EXCLUDED.url ELSE assets.url END,
 			domain = EXCLUDED.domain,
 			subdomain = EXCLUDED.subdomain,
-			ip = EXCLUDED.ip,
 			status = EXCLUDED.status,
 			source = EXCLUDED.source,
+			last_scan_id = COALESCE(EXCLUDED.last_scan_id, assets.last_scan_id),
+			provenance = CASE WHEN EXCLUDED.first_source = ANY(assets.provenance) THEN assets.provenance ELSE array_append(assets.provenance, EXCLUDED.first_source::text) END,
+			data_terms = ARRAY(SELECT DISTINCT term FROM unnest(assets.data_terms || EXCLUDED.data_terms) AS term ORDER BY term),
 			updated_at = NOW()
-	`
+		RETURNING id, host_key
+	), variant AS (
+		INSERT INTO asset_scheme_variants (asset_id, scheme, url, first_seen, last_seen)
+		SELECT upserted.id, lower(split_part(found.url, '://', 1)), found.url, NOW(), NOW()
+		FROM upserted JOIN unnest($%d::text[], $%d::text[]) AS found (host_key, url) ON found.host_key = upserted.host_key
+		ON CONFLICT (asset_id, scheme) DO UPDATE SET
+			url = EXCLUDED.url,
+			last_seen = NOW()
+	)
+	SELECT id, host_key FROM upserted
+`, strings.Join(values, ",\n\t\t\t"), hostKeys, hostKeys+1)
+}
+
+// upsertAssetArgs returns the parameters of an asset in a multi-row upsert,
+// in the order of upsertAssetsQuery
+func upsertAssetArgs(asset *Asset) []interface{} {
+	return []interface{}{
+		asset.ID, asset.ProgramID, asset.ProgramURL, asset.URL, asset.HostKey, asset.Domain, asset.Subdomain,
+		asset.Status, asset.Source, asset.FirstScanID, asset.FirstSource, asset.DataTerms, asset.CreatedAt, asset.UpdatedAt,
+	}
+}
+
+// splitAssetUpserts splits assets into the rows of successive multi-row
+// upserts. Assets are matched to the rows a statement returns by host key, and
+// a host can only be written once per statement, so a repeated host starts a
+// new statement and updates the asset the earlier one stored, as writing the
+// assets one at a time would.
+func splitAssetUpserts(assets []*Asset) [][]*Asset {
+	var statements [][]*Asset
+	var current []*Asset
+	hosts := make(map[string]bool)
 
 	for _, asset := range assets {
-		asset.ID = uuid.New()
-		asset.CreatedAt = time.Now()
-		asset.UpdatedAt = time.Now()
+		if hosts[asset.HostKey] || len(current) == maxAssetUpsertRows {
+			statements = append(statements, current)
+			current = nil
+			hosts = make(map[string]bool)
+		}
+		hosts[asset.HostKey] = true
+		current = append(current, asset)
+	}
+	if len(current) > 0 {
+		statements = append(statements, current)
+	}
 
-		_, err := tx.NamedExecContext(ctx, query, asset)
-		if err != nil {
-			return fmt.Errorf("failed to create asset %s: %w", asset.URL, err)
+	return statements
+}
+
+// CreateAssets creates multiple assets in a transaction, or updates the
+// existing assets with the same hosts. Assets are written with multi-row
+// upserts of up to maxAssetUpsertRows assets each.
+func (r *AssetRepository) CreateAssets(ctx context.Context, assets []*Asset) error {
+	defer r.observe("upsert", TableAssets, time.Now())
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Track if we've committed the transaction
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	for _, asset := range assets {
+		prepareAsset(asset)
+	}
+
+	for _, rows := range splitAssetUpserts(assets) {
+		if err := upsertAssetRows(ctx, tx, rows); err != nil {
+			return err
 		}
 	}
 
@@ -301,10 +674,84 @@ func (r *AssetRepository) CreateAssets(ctx context.Context, assets []*Asset) err
 	return nil
 }
 
+// upsertAssetRows writes the hosts of assets with distinct host keys, and
+// then the assets, in one statement each and sets the ID each asset is stored
+// under; on conflict the stored asset keeps its original ID
+func upsertAssetRows(ctx context.Context, tx *sqlx.Tx, assets []*Asset) error {
+	hostArgs := make([]interface{}, 0, len(assets)*hostUpsertParams)
+	args := make([]interface{}, 0, len(assets)*assetUpsertParams+2)
+	hostKeys := make([]string, len(assets))
+	urls := make([]string, len(assets))
+	byHost := make(map[string]*Asset, len(assets))
+	for i, asset := range assets {
+		hostArgs = append(hostArgs, upsertHostArgs(asset)...)
+		args = append(args, upsertAssetArgs(asset)...)
+		hostKeys[i] = asset.HostKey
+		urls[i] = asset.URL
+		byHost[asset.HostKey] = asset
+	}
+	args = append(args, pq.Array(hostKeys), pq.Array(urls))
+
+	if _, err := tx.ExecContext(ctx, upsertHostsQuery(len(assets)), hostArgs...); err != nil {
+		return fmt.Errorf("failed to update hosts of %d assets starting with %s: %w", len(assets), assets[0].URL, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, upsertAssetsQuery(len(assets)), args...)
+	if err != nil {
+		return fmt.Errorf("failed to create %d assets starting with %s: %w", len(assets), assets[0].URL, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var hostKey string
+		if err := rows.Scan(&id, &hostKey); err != nil {
+			return fmt.Errorf("failed to scan stored asset: %w", err)
+		}
+		if asset, ok := byHost[hostKey]; ok {
+			asset.ID = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to create %d assets starting with %s: %w", len(assets), assets[0].URL, err)
+	}
+
+	return nil
+}
+
+// GetAssetSchemeVariants retrieves the schemes an asset has been seen with
+func (r *AssetRepository) GetAssetSchemeVariants(ctx context.Context, assetID uuid.UUID) ([]*AssetSchemeVariant, error) {
+	var variants []*AssetSchemeVariant
+	query := `SELECT * FROM asset_scheme_variants WHERE asset_id = $1 ORDER BY scheme`
+
+	err := r.db.SelectContext(ctx, &variants, query, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset scheme variants: %w", err)
+	}
+
+	return variants, nil
+}
+
+// GetAssetByID retrieves an asset by ID
+func (r *AssetRepository) GetAssetByID(ctx context.Context, id uuid.UUID) (*Asset, error) {
+	var asset Asset
+	query := selectAssets + ` WHERE a.id = $1`
+
+	err := r.db.GetContext(ctx, &asset, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+
+	return &asset, nil
+}
+
 // GetAssetsByProgramID retrieves assets by program ID
 func (r *AssetRepository) GetAssetsByProgramID(ctx context.Context, programID uuid.UUID) ([]*Asset, error) {
 	var assets []*Asset
-	query := `SELECT * FROM assets WHERE program_id = $1 ORDER BY created_at DESC`
+	query := selectAssets + ` WHERE a.program_id = $1 ORDER BY a.created_at DESC`
 
 	err := r.db.SelectContext(ctx, &assets, query, programID)
 	if err != nil {
@@ -316,8 +763,10 @@ func (r *AssetRepository) GetAssetsByProgramID(ctx context.Context, programID uu
 
 // GetAssetsByProgramIDAndSource retrieves assets by program ID and source
 func (r *AssetRepository) GetAssetsByProgramIDAndSource(ctx context.Context, programID uuid.UUID, source string) ([]*Asset, error) {
+	defer r.observe("select", TableAssets, time.Now())
+
 	var assets []*Asset
-	query := `SELECT * FROM assets WHERE program_id = $1 AND source = $2 ORDER BY created_at DESC`
+	query := selectAssets + ` WHERE a.program_id = $1 AND a.source = $2 ORDER BY a.created_at DESC`
 
 	err := r.db.SelectContext(ctx, &assets, query, programID, source)
 	if err != nil {
@@ -327,10 +776,37 @@ func (r *AssetRepository) GetAssetsByProgramIDAndSource(ctx context.Context, pro
 	return assets, nil
 }
 
+// GetAssetsByFirstScanID retrieves the assets a scan found for the first time
+func (r *AssetRepository) GetAssetsByFirstScanID(ctx context.Context, scanID uuid.UUID) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + ` WHERE a.first_scan_id = $1 ORDER BY a.created_at`
+
+	err := r.db.SelectContext(ctx, &assets, query, scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets by first scan ID: %w", err)
+	}
+
+	return assets, nil
+}
+
+// GetAssetsByURL retrieves the assets for a URL across all programs, matching
+// any scheme variant of its host
+func (r *AssetRepository) GetAssetsByURL(ctx context.Context, url string) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + ` WHERE a.host_key = $1 ORDER BY a.created_at DESC`
+
+	err := r.db.SelectContext(ctx, &assets, query, AssetHostKey(url))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets by URL: %w", err)
+	}
+
+	return assets, nil
+}
+
 // GetAssetsByDomain retrieves assets by domain
 func (r *AssetRepository) GetAssetsByDomain(ctx context.Context, domain string) ([]*Asset, error) {
 	var assets []*Asset
-	query := `SELECT * FROM assets WHERE domain = $1 ORDER BY created_at DESC`
+	query := selectAssets + ` WHERE a.domain = $1 ORDER BY a.created_at DESC`
 
 	err := r.db.SelectContext(ctx, &assets, query, domain)
 	if err != nil {
@@ -340,10 +816,45 @@ func (r *AssetRepository) GetAssetsByDomain(ctx context.Context, domain string)
 	return assets, nil
 }
 
+// FindAssetsByHost retrieves the assets of a host and its subdomains, or of
+// an apex domain, ordered by host
+func (r *AssetRepository) FindAssetsByHost(ctx context.Context, host string, limit int) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + `
+		WHERE a.host_key = $1 OR a.host_key LIKE '%.' || $1 OR a.domain = $1
+		ORDER BY a.host_key
+		LIMIT $2
+	`
+
+	err := r.db.SelectContext(ctx, &assets, query, host, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assets by host: %w", err)
+	}
+
+	return assets, nil
+}
+
+// GetExistingHostKeys returns which of the host keys a program already has
+// assets for
+func (r *AssetRepository) GetExistingHostKeys(ctx context.Context, programID uuid.UUID, hostKeys []string) (map[string]bool, error) {
+	var existing []string
+	query := `SELECT host_key FROM assets WHERE program_id = $1 AND host_key = ANY($2)`
+
+	if err := r.db.SelectContext(ctx, &existing, query, programID, pq.Array(hostKeys)); err != nil {
+		return nil, fmt.Errorf("failed to get existing host keys: %w", err)
+	}
+
+	keys := make(map[string]bool, len(existing))
+	for _, key := range existing {
+		keys[key] = true
+	}
+	return keys, nil
+}
+
 // GetAssetsByStatus retrieves assets by status
 func (r *AssetRepository) GetAssetsByStatus(ctx context.Context, status string) ([]*Asset, error) {
 	var assets []*Asset
-	query := `SELECT * FROM assets WHERE status = $1 ORDER BY created_at DESC`
+	query := selectAssets + ` WHERE a.status = $1 ORDER BY a.created_at DESC`
 
 	err := r.db.SelectContext(ctx, &assets, query, status)
 	if err != nil {
@@ -375,14 +886,19 @@ func (r *AssetRepository) DeleteAssetsByProgramID(ctx context.Context, programID
 
 // CreateScan creates a new scan
 func (r *ScanRepository) CreateScan(ctx context.Context, scan *Scan) error {
+	defer r.observe("insert", TableScans, time.Now())
+
 	scan.ID = uuid.New()
 	scan.CreatedAt = time.Now()
 	scan.UpdatedAt = time.Now()
 	scan.StartedAt = time.Now()
+	if scan.AgentVersion == "" {
+		scan.AgentVersion = version.Version
+	}
 
 	query := `
-		INSERT INTO scans (id, program_id, status, assets_found, started_at, created_at, updated_at)
-		VALUES (:id, :program_id, :status, :assets_found, :started_at, :created_at, :updated_at)
+		INSERT INTO scans (id, program_id, status, assets_found, agent_version, scheduled_at, started_at, created_at, updated_at)
+		VALUES (:id, :program_id, :status, :assets_found, :agent_version, :scheduled_at, :started_at, :created_at, :updated_at)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, scan)
@@ -395,11 +911,13 @@ func (r *ScanRepository) CreateScan(ctx context.Context, scan *Scan) error {
 
 // UpdateScan updates a scan
 func (r *ScanRepository) UpdateScan(ctx context.Context, scan *Scan) error {
+	defer r.observe("update", TableScans, time.Now())
+
 	scan.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE scans 
-		SET status = :status, assets_found = :assets_found, completed_at = :completed_at, 
+		SET status = :status, assets_found = :assets_found, assets_seen = :assets_seen, completed_at = :completed_at, 
 		    error = :error, updated_at = :updated_at
 		WHERE id = :id
 	`
@@ -437,6 +955,96 @@ func (r *ScanRepository) GetScanByID(ctx context.Context, id uuid.UUID) (*Scan,
 	return &scan, nil
 }
 
+// RequestScanCancel flags a running scan for cancellation. The process running
+// the scan polls this flag and cancels the scan's context when it is set.
+func (r *ScanRepository) RequestScanCancel(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE scans SET cancel_requested_at = COALESCE(cancel_requested_at, NOW()), updated_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to request scan cancel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		scan, err := r.GetScanByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if scan == nil {
+			return ErrScanNotFound
+		}
+		return ErrScanNotRunning
+	}
+
+	return nil
+}
+
+// HeartbeatScan records that a running scan is alive and reports whether
+// cancellation was requested for it
+func (r *ScanRepository) HeartbeatScan(ctx context.Context, id uuid.UUID) (bool, error) {
+	var requested bool
+	query := `UPDATE scans SET heartbeat_at = NOW() WHERE id = $1 RETURNING cancel_requested_at IS NOT NULL`
+
+	err := r.db.GetContext(ctx, &requested, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to record scan heartbeat: %w", err)
+	}
+
+	return requested, nil
+}
+
+// AbortStaleScans marks the running scans whose last heartbeat, or start when
+// they sent none, is older than staleSince as aborted with the given error.
+// It returns the scans it aborted.
+func (r *ScanRepository) AbortStaleScans(ctx context.Context, staleSince time.Time, reason string) ([]*Scan, error) {
+	defer r.observe("update", TableScans, time.Now())
+
+	var scans []*Scan
+	query := `
+		UPDATE scans SET status = 'aborted', error = $2, completed_at = NOW(), updated_at = NOW()
+		WHERE status = 'running' AND COALESCE(heartbeat_at, started_at) < $1
+		RETURNING *
+	`
+
+	if err := r.db.SelectContext(ctx, &scans, query, staleSince, reason); err != nil {
+		return nil, fmt.Errorf("failed to abort stale scans: %w", err)
+	}
+
+	return scans, nil
+}
+
+// GetPreviousCompletedScan retrieves a program's latest completed scan other than the given one
+func (r *ScanRepository) GetPreviousCompletedScan(ctx context.Context, programID, excludeScanID uuid.UUID) (*Scan, error) {
+	var scan Scan
+	query := `
+		SELECT * FROM scans
+		WHERE program_id = $1 AND id <> $2 AND status = 'completed'
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.GetContext(ctx, &scan, query, programID, excludeScanID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get previous scan: %w", err)
+	}
+
+	return &scan, nil
+}
+
 // GetScansByProgramID retrieves scans by program ID
 func (r *ScanRepository) GetScansByProgramID(ctx context.Context, programID uuid.UUID) ([]*Scan, error) {
 	var scans []*Scan
@@ -450,6 +1058,19 @@ func (r *ScanRepository) GetScansByProgramID(ctx context.Context, programID uuid
 	return scans, nil
 }
 
+// GetRecentProgramScans retrieves up to limit of a program's most recent scans
+func (r *ScanRepository) GetRecentProgramScans(ctx context.Context, programID uuid.UUID, limit int) ([]*Scan, error) {
+	var scans []*Scan
+	query := `SELECT * FROM scans WHERE program_id = $1 ORDER BY started_at DESC LIMIT $2`
+
+	err := r.db.SelectContext(ctx, &scans, query, programID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent program scans: %w", err)
+	}
+
+	return scans, nil
+}
+
 // GetRecentScans retrieves recent scans
 func (r *ScanRepository) GetRecentScans(ctx context.Context, limit int) ([]*Scan, error) {
 	var scans []*Scan
@@ -465,28 +1086,47 @@ func (r *ScanRepository) GetRecentScans(ctx context.Context, limit int) ([]*Scan
 
 // AssetResponse Operations
 
-// CreateAssetResponse creates a new asset response record
+// CreateAssetResponse creates a new asset response record under the asset
+// and its host
 func (r *AssetRepository) CreateAssetResponse(ctx context.Context, assetResponse *AssetResponse) error {
+	defer r.observe("insert", TableAssetResponses, time.Now())
+
 	assetResponse.ID = uuid.New()
 	assetResponse.CreatedAt = time.Now()
+	if assetResponse.Method == "" {
+		assetResponse.Method = http.MethodGet
+	}
 
 	query := `
-		INSERT INTO asset_responses (id, asset_id, status_code, headers, body, response_time, created_at)
-		VALUES (:id, :asset_id, :status_code, :headers, :body, :response_time, :created_at)
+		INSERT INTO asset_responses (id, asset_id, host_id, method, status_code, headers, body, response_time,
+			initial_status_code, redirect_hops, final_url, redirect_status, meta_refresh, body_simhash, body_hash, header_hash,
+			body_ref, body_sha256, body_size, created_at)
+		SELECT :id, :asset_id, a.host_id, :method, :status_code, :headers, :body, :response_time,
+			:initial_status_code, :redirect_hops, :final_url, :redirect_status, :meta_refresh, :body_simhash,
+			:body_hash, :header_hash, :body_ref, :body_sha256, :body_size, :created_at
+		FROM assets a WHERE a.id = :asset_id
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, assetResponse)
+	result, err := r.db.NamedExecContext(ctx, query, assetResponse)
 	if err != nil {
 		return fmt.Errorf("failed to create asset response: %w", err)
 	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return fmt.Errorf("failed to create asset response: asset %s not found", assetResponse.AssetID)
+	}
 
 	return nil
 }
 
-// GetAssetResponsesByAssetID retrieves asset responses by asset ID
+// GetAssetResponsesByAssetID retrieves the responses captured from an
+// asset's host, by the scans of every program that includes it, newest first
 func (r *AssetRepository) GetAssetResponsesByAssetID(ctx context.Context, assetID uuid.UUID) ([]*AssetResponse, error) {
 	var responses []*AssetResponse
-	query := `SELECT * FROM asset_responses WHERE asset_id = $1 ORDER BY created_at DESC`
+	query := `
+		SELECT r.* FROM asset_responses r JOIN assets a ON a.host_id = r.host_id
+		WHERE a.id = $1
+		ORDER BY r.created_at DESC
+	`
 
 	err := r.db.SelectContext(ctx, &responses, query, assetID)
 	if err != nil {
@@ -496,10 +1136,16 @@ func (r *AssetRepository) GetAssetResponsesByAssetID(ctx context.Context, assetI
 	return responses, nil
 }
 
-// GetLatestAssetResponseByAssetID retrieves the latest asset response for an asset
+// GetLatestAssetResponseByAssetID retrieves the latest response captured from
+// an asset's host, whichever program's scan captured it
 func (r *AssetRepository) GetLatestAssetResponseByAssetID(ctx context.Context, assetID uuid.UUID) (*AssetResponse, error) {
 	var response AssetResponse
-	query := `SELECT * FROM asset_responses WHERE asset_id = $1 ORDER BY created_at DESC LIMIT 1`
+	query := `
+		SELECT r.* FROM asset_responses r JOIN assets a ON a.host_id = r.host_id
+		WHERE a.id = $1
+		ORDER BY r.created_at DESC
+		LIMIT 1
+	`
 
 	err := r.db.GetContext(ctx, &response, query, assetID)
 	if err != nil {
@@ -564,6 +1210,11 @@ func (r *AssetRepository) GetAssetResponsesWithAssetInfo(ctx context.Context, li
 			ar.headers as "asset_response.headers",
 			ar.body as "asset_response.body",
 			ar.response_time as "asset_response.response_time",
+			ar.initial_status_code as "asset_response.initial_status_code",
+			ar.redirect_hops as "asset_response.redirect_hops",
+			ar.final_url as "asset_response.final_url",
+			ar.redirect_status as "asset_response.redirect_status",
+			ar.meta_refresh as "asset_response.meta_refresh",
 			ar.created_at as "asset_response.created_at",
 			a.id as "asset.id",
 			a.program_id as "asset.program_id",
@@ -571,13 +1222,14 @@ func (r *AssetRepository) GetAssetResponsesWithAssetInfo(ctx context.Context, li
 			a.url as "asset.url",
 			a.domain as "asset.domain",
 			a.subdomain as "asset.subdomain",
-			a.ip as "asset.ip",
+			h.ip as "asset.ip",
 			a.status as "asset.status",
 			a.source as "asset.source",
 			a.created_at as "asset.created_at",
 			a.updated_at as "asset.updated_at"
 		FROM asset_responses ar
 		JOIN assets a ON ar.asset_id = a.id
+		JOIN hosts h ON h.id = a.host_id
 		ORDER BY ar.created_at DESC
 		LIMIT $1
 	`
@@ -628,6 +1280,169 @@ func (r *AssetRepository) GetAssetCountByProgramID(ctx context.Context, programI
 	return count, nil
 }
 
+// GetAssetCountSeenSince gets the number of a program's assets created or confirmed since the given time
+func (r *AssetRepository) GetAssetCountSeenSince(ctx context.Context, programID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM assets WHERE program_id = $1 AND updated_at >= $2`
+
+	err := r.db.GetContext(ctx, &count, query, programID, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get seen asset count: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetSourceYield gets asset counts grouped by the discovery source that first found them
+func (r *AssetRepository) GetSourceYield(ctx context.Context) ([]*SourceYield, error) {
+	var yields []*SourceYield
+	query := `
+		SELECT COALESCE(NULLIF(first_source, ''), source) AS source,
+		       COUNT(*) AS assets,
+		       COUNT(*) FILTER (WHERE status = 'active') AS active_assets,
+		       COUNT(DISTINCT program_id) AS programs,
+		       MAX(created_at) AS last_found_at
+		FROM assets
+		GROUP BY 1
+		ORDER BY assets DESC
+	`
+
+	err := r.db.SelectContext(ctx, &yields, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source yield: %w", err)
+	}
+
+	return yields, nil
+}
+
+// GetScanSourceYield gets the counts of the assets a scan found first,
+// grouped by the discovery source that found them
+func (r *AssetRepository) GetScanSourceYield(ctx context.Context, scanID uuid.UUID) (map[string]int, error) {
+	var rows []struct {
+		Source string `db:"source"`
+		Assets int    `db:"assets"`
+	}
+	query := `
+		SELECT COALESCE(NULLIF(first_source, ''), source) AS source, COUNT(*) AS assets
+		FROM assets
+		WHERE first_scan_id = $1
+		GROUP BY 1
+	`
+
+	if err := r.db.SelectContext(ctx, &rows, query, scanID); err != nil {
+		return nil, fmt.Errorf("failed to get scan source yield: %w", err)
+	}
+
+	yields := make(map[string]int, len(rows))
+	for _, row := range rows {
+		yields[row.Source] = row.Assets
+	}
+	return yields, nil
+}
+
+// GetLivenessCounts gets asset counts grouped by the liveness state of their latest probe
+func (r *AssetRepository) GetLivenessCounts(ctx context.Context) ([]*LivenessCount, error) {
+	var counts []*LivenessCount
+	query := `
+		SELECT COALESCE(NULLIF(h.liveness, ''), 'unprobed') AS liveness, COUNT(*) AS assets
+		FROM assets a JOIN hosts h ON h.id = a.host_id
+		GROUP BY 1
+		ORDER BY assets DESC
+	`
+
+	err := r.db.SelectContext(ctx, &counts, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get liveness counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetProbeErrorCounts gets the most common probe errors recorded since the given time
+func (r *AssetRepository) GetProbeErrorCounts(ctx context.Context, since time.Time, limit int) ([]*ProbeErrorCount, error) {
+	var counts []*ProbeErrorCount
+	query := `
+		SELECT h.liveness, h.last_probe_error AS error, COUNT(*) AS assets
+		FROM assets a JOIN hosts h ON h.id = a.host_id
+		WHERE h.last_probe_error_at >= $1
+		GROUP BY h.liveness, h.last_probe_error
+		ORDER BY assets DESC
+		LIMIT $2
+	`
+
+	err := r.db.SelectContext(ctx, &counts, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get probe error counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetAssetsByProgramIDAndLiveness retrieves a program's assets in the given liveness state
+func (r *AssetRepository) GetAssetsByProgramIDAndLiveness(ctx context.Context, programID uuid.UUID, liveness string) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + ` WHERE a.program_id = $1 AND h.liveness = $2 ORDER BY a.created_at DESC`
+
+	err := r.db.SelectContext(ctx, &assets, query, programID, liveness)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets by liveness: %w", err)
+	}
+
+	return assets, nil
+}
+
+// GetStalestProbedAssets retrieves the assets of active programs that were
+// probed longest ago, never-probed assets first. Ignored and quarantined
+// assets are skipped.
+func (r *AssetRepository) GetStalestProbedAssets(ctx context.Context, limit int) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + `
+		JOIN programs p ON p.id = a.program_id
+		WHERE p.is_active = true AND NOT a.ignored AND a.status <> 'quarantined'
+		ORDER BY h.last_probed_at NULLS FIRST, a.id
+		LIMIT $1
+	`
+
+	err := r.db.SelectContext(ctx, &assets, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stalest probed assets: %w", err)
+	}
+
+	return assets, nil
+}
+
+// UpdateAssetProbe stores the outcome of re-probing an existing asset on its
+// host, shared by the assets of the other programs that include it, and marks
+// the asset updated. The last probe error is kept when the asset's
+// LastProbeErrorAt is nil.
+func (r *AssetRepository) UpdateAssetProbe(ctx context.Context, asset *Asset) error {
+	defer r.observe("update", TableAssets, time.Now())
+
+	query := `
+		WITH probed AS (
+			UPDATE hosts SET
+				ip = $2,
+				ipv6 = $3,
+				ipv4_reachable = $4,
+				ipv6_reachable = $5,
+				liveness = CASE WHEN $6 <> '' THEN $6 ELSE liveness END,
+				last_probe_error = CASE WHEN $8::timestamptz IS NOT NULL THEN $7 ELSE last_probe_error END,
+				last_probe_error_at = COALESCE($8, last_probe_error_at),
+				last_probed_at = $9
+			WHERE id = (SELECT host_id FROM assets WHERE id = $1)
+		)
+		UPDATE assets SET updated_at = NOW() WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, asset.ID, asset.IP, asset.IPv6, asset.IPv4Reachable, asset.IPv6Reachable,
+		asset.Liveness, asset.LastProbeError, asset.LastProbeErrorAt, asset.LastProbedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update asset probe: %w", err)
+	}
+
+	return nil
+}
+
 // GetProgramsWithAssetCount gets programs with their asset counts
 func (r *ProgramRepository) GetProgramsWithAssetCount(ctx context.Context) ([]struct {
 	Program    *Program `db:"program"`
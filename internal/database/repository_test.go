@@ -3,12 +3,17 @@ package database
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,7 +44,7 @@ func TestProgramRepository_CreateProgram(t *testing.T) {
 	}
 
 	mock.ExpectExec("INSERT INTO programs").
-		WithArgs(sqlmock.AnyArg(), program.Name, program.Platform, program.URL, program.ProgramURL, program.IsActive, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), program.Name, program.Platform, program.PlatformID, program.URL, program.ProgramURL, program.IsActive, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), ProgramVisibilityPublic).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := repo.CreateProgram(ctx, program)
@@ -147,6 +152,69 @@ func TestProgramRepository_GetAllActivePrograms(t *testing.T) {
 	assert.Equal(t, expectedPrograms[1].Name, programs[1].Name)
 }
 
+func TestProgramRepository_GetProgramByPlatformID_Empty(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewProgramRepository(db)
+
+	program, err := repo.GetProgramByPlatformID(context.Background(), "hackerone", "")
+	assert.NoError(t, err)
+	assert.Nil(t, program)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProgramRepository_RenameProgram(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewProgramRepository(db)
+	ctx := context.Background()
+
+	program := &Program{
+		ID:         uuid.New(),
+		Platform:   "hackerone",
+		PlatformID: "13",
+		URL:        "https://hackerone.com/old-handle",
+		ProgramURL: "https://hackerone.com/old-handle",
+	}
+	newURL := "https://hackerone.com/new-handle"
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO program_aliases").
+		WithArgs(sqlmock.AnyArg(), program.ID, program.Platform, "https://hackerone.com/old-handle").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM program_aliases").
+		WithArgs(program.Platform, newURL).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE programs").
+		WithArgs(newURL, "https://hackerone.com/old-handle", program.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE assets SET program_url").
+		WithArgs(newURL, program.ID).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectCommit()
+
+	err := repo.RenameProgram(ctx, program, newURL)
+	assert.NoError(t, err)
+	assert.Equal(t, newURL, program.ProgramURL)
+	assert.Equal(t, newURL, program.URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProgramRepository_RenameProgram_SameURL(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewProgramRepository(db)
+
+	program := &Program{ID: uuid.New(), Platform: "bugcrowd", ProgramURL: "https://bugcrowd.com/acme"}
+
+	err := repo.RenameProgram(context.Background(), program, "https://bugcrowd.com/acme")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestAssetRepository_CreateAsset(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
@@ -165,13 +233,23 @@ func TestAssetRepository_CreateAsset(t *testing.T) {
 		Source:     "chaosdb",
 	}
 
-	mock.ExpectExec("INSERT INTO assets").
-		WithArgs(sqlmock.AnyArg(), asset.ProgramID, asset.ProgramURL, asset.URL, asset.Domain, asset.Subdomain, asset.IP, asset.Status, asset.Source, sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	// The host's state is written first, then the program's asset
+	storedID := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO hosts \\(host_key, ip, ipv6, ipv4_reachable, ipv6_reachable, liveness, last_probe_error, last_probe_error_at, last_probed_at\\)").
+		WithArgs("subdomain.example.com", asset.IP, asset.IPv6, asset.IPv4Reachable, asset.IPv6Reachable, asset.Liveness, asset.LastProbeError, asset.LastProbeErrorAt, asset.LastProbedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("INSERT INTO assets").
+		ExpectQuery().
+		WithArgs(sqlmock.AnyArg(), asset.ProgramID, asset.ProgramURL, asset.URL, "subdomain.example.com", asset.Domain, asset.Subdomain, asset.Status, asset.Source, asset.FirstScanID, asset.FirstScanID, asset.Source, asset.Source, "{}", sqlmock.AnyArg(), sqlmock.AnyArg(), asset.URL, asset.URL).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(storedID))
+	mock.ExpectCommit()
 
 	err := repo.CreateAsset(ctx, asset)
 	assert.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, asset.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, storedID, asset.ID)
+	assert.Equal(t, "subdomain.example.com", asset.HostKey)
 	assert.False(t, asset.CreatedAt.IsZero())
 	assert.False(t, asset.UpdatedAt.IsZero())
 }
@@ -205,16 +283,93 @@ func TestAssetRepository_CreateAssets(t *testing.T) {
 		},
 	}
 
-	mock.ExpectBegin()
-	for i := 0; i < 2; i++ {
-		mock.ExpectExec("INSERT INTO assets").
-			WithArgs(sqlmock.AnyArg(), programID, assets[i].ProgramURL, assets[i].URL, assets[i].Domain, assets[i].Subdomain, assets[i].IP, assets[i].Status, assets[i].Source, sqlmock.AnyArg(), sqlmock.AnyArg()).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+	// Both hosts are written by one statement, then both assets by another
+	var hostArgs, args []driver.Value
+	for _, asset := range assets {
+		hostArgs = append(hostArgs, AssetHostKey(asset.URL), asset.IP, asset.IPv6, asset.IPv4Reachable, asset.IPv6Reachable, asset.Liveness, asset.LastProbeError, asset.LastProbeErrorAt, asset.LastProbedAt)
+		args = append(args, sqlmock.AnyArg(), programID, asset.ProgramURL, asset.URL, AssetHostKey(asset.URL), asset.Domain, asset.Subdomain, asset.Status, asset.Source, asset.FirstScanID, asset.Source, "{}", sqlmock.AnyArg(), sqlmock.AnyArg())
 	}
+	args = append(args, pq.Array([]string{"sub1.example.com", "sub2.example.com"}), pq.Array([]string{assets[0].URL, assets[1].URL}))
+
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO hosts").WithArgs(hostArgs...).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("INSERT INTO assets").WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_key"}).AddRow(ids[1], "sub2.example.com").AddRow(ids[0], "sub1.example.com"))
 	mock.ExpectCommit()
 
 	err := repo.CreateAssets(ctx, assets)
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, ids[0], assets[0].ID)
+	assert.Equal(t, ids[1], assets[1].ID)
+}
+
+func TestAssetRepository_CreateAssets_RepeatedHost(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	programID := uuid.New()
+	assets := []*Asset{
+		{ProgramID: programID, URL: "https://a.example.com", Status: "active", Source: "chaosdb"},
+		{ProgramID: programID, URL: "http://a.example.com", Status: "active", Source: "crtsh"},
+		{ProgramID: programID, URL: "https://b.example.com", Status: "active", Source: "chaosdb"},
+	}
+
+	// The second write of a host starts a new statement that updates the stored asset
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO hosts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO assets").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_key"}).AddRow(id, "a.example.com"))
+	mock.ExpectExec("INSERT INTO hosts").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("INSERT INTO assets").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_key"}).AddRow(id, "a.example.com").AddRow(uuid.New(), "b.example.com"))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.CreateAssets(context.Background(), assets))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, id, assets[0].ID)
+	assert.Equal(t, id, assets[1].ID)
+}
+
+func TestAssetRepository_CreateAssets_Failure(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	assets := []*Asset{{ProgramID: uuid.New(), URL: "https://a.example.com", Status: "active", Source: "chaosdb"}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO hosts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO assets").WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err := repo.CreateAssets(context.Background(), assets)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "https://a.example.com")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSplitAssetUpserts(t *testing.T) {
+	var assets []*Asset
+	for i := 0; i < maxAssetUpsertRows+2; i++ {
+		assets = append(assets, &Asset{HostKey: fmt.Sprintf("a%d.example.com", i)})
+	}
+	assets = append(assets, &Asset{HostKey: "a0.example.com"})
+
+	// A full statement, then the rest until the first host repeats
+	statements := splitAssetUpserts(assets)
+	require.Len(t, statements, 2)
+	assert.Len(t, statements[0], maxAssetUpsertRows)
+	assert.Len(t, statements[1], 3)
+
+	assets = append(assets, &Asset{HostKey: "a1.example.com"}, &Asset{HostKey: "a1.example.com"})
+	statements = splitAssetUpserts(assets)
+	require.Len(t, statements, 3)
+	assert.Len(t, statements[1], 4)
+	assert.Len(t, statements[2], 1)
 }
 
 func TestAssetRepository_GetAssetsByProgramID(t *testing.T) {
@@ -257,7 +412,8 @@ func TestAssetRepository_GetAssetsByProgramID(t *testing.T) {
 		rows.AddRow(a.ID, a.ProgramID, a.ProgramURL, a.URL, a.Domain, a.Subdomain, a.IP, a.Status, a.Source, a.CreatedAt, a.UpdatedAt)
 	}
 
-	mock.ExpectQuery("SELECT \\* FROM assets WHERE program_id = \\$1 ORDER BY created_at DESC").
+	// The address is read from the asset's host
+	mock.ExpectQuery("SELECT a.\\*, h.ip, .* FROM assets a JOIN hosts h ON h.id = a.host_id WHERE a.program_id = \\$1 ORDER BY a.created_at DESC").
 		WithArgs(programID).
 		WillReturnRows(rows)
 
@@ -268,6 +424,27 @@ func TestAssetRepository_GetAssetsByProgramID(t *testing.T) {
 	assert.Equal(t, expectedAssets[1].URL, assets[1].URL)
 }
 
+func TestAssetRepository_GetAssetsByURL(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	ctx := context.Background()
+
+	assetID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "program_id", "url", "domain", "status", "source"}).
+		AddRow(assetID, uuid.New(), "https://api.example.com", "example.com", "active", "secondary")
+
+	mock.ExpectQuery("FROM assets a JOIN hosts h ON h.id = a.host_id WHERE a.host_key = \\$1 ORDER BY a.created_at DESC").
+		WithArgs("api.example.com").
+		WillReturnRows(rows)
+
+	assets, err := repo.GetAssetsByURL(ctx, "http://api.example.com")
+	assert.NoError(t, err)
+	assert.Len(t, assets, 1)
+	assert.Equal(t, assetID, assets[0].ID)
+}
+
 func TestAssetRepository_DeleteAssetsByProgramID(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
@@ -326,7 +503,7 @@ func TestAssetRepository_GetAssetsByProgramIDAndSource(t *testing.T) {
 		rows.AddRow(a.ID, a.ProgramID, a.ProgramURL, a.URL, a.Domain, a.Subdomain, a.IP, a.Status, a.Source, a.CreatedAt, a.UpdatedAt)
 	}
 
-	mock.ExpectQuery("SELECT \\* FROM assets WHERE program_id = \\$1 AND source = \\$2 ORDER BY created_at DESC").
+	mock.ExpectQuery("FROM assets a JOIN hosts h ON h.id = a.host_id WHERE a.program_id = \\$1 AND a.source = \\$2 ORDER BY a.created_at DESC").
 		WithArgs(programID, source).
 		WillReturnRows(rows)
 
@@ -354,11 +531,12 @@ func TestScanRepository_CreateScan(t *testing.T) {
 	}
 
 	mock.ExpectExec("INSERT INTO scans").
-		WithArgs(sqlmock.AnyArg(), scan.ProgramID, scan.Status, scan.AssetsFound, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), scan.ProgramID, scan.Status, scan.AssetsFound, version.Version, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := repo.CreateScan(ctx, scan)
 	assert.NoError(t, err)
+	assert.Equal(t, version.Version, scan.AgentVersion)
 	assert.NotEqual(t, uuid.Nil, scan.ID)
 	assert.False(t, scan.CreatedAt.IsZero())
 	assert.False(t, scan.UpdatedAt.IsZero())
@@ -384,7 +562,7 @@ func TestScanRepository_UpdateScan(t *testing.T) {
 	}
 
 	mock.ExpectExec("UPDATE scans").
-		WithArgs(scan.Status, scan.AssetsFound, scan.CompletedAt, scan.Error, sqlmock.AnyArg(), scanID).
+		WithArgs(scan.Status, scan.AssetsFound, scan.AssetsSeen, scan.CompletedAt, scan.Error, sqlmock.AnyArg(), scanID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	err := repo.UpdateScan(ctx, scan)
@@ -492,16 +670,29 @@ func TestAssetRepository_CreateAssetResponse(t *testing.T) {
 		Headers:      `{"Server": "nginx", "Content-Type": "text/html"}`,
 		Body:         "<html><body>Hello World</body></html>",
 		ResponseTime: 150,
+
+		InitialStatusCode: 301,
+		RedirectHops:      1,
+		FinalURL:          "https://www.example.com/",
+		RedirectStatus:    "followed",
 	}
 
-	mock.ExpectExec("INSERT INTO asset_responses").
-		WithArgs(sqlmock.AnyArg(), assetResponse.AssetID, assetResponse.StatusCode, assetResponse.Headers, assetResponse.Body, assetResponse.ResponseTime, sqlmock.AnyArg()).
+	// The response is stored under the asset's host as well
+	mock.ExpectExec("INSERT INTO asset_responses .* SELECT .*, a.host_id, .* FROM assets a WHERE a.id = ").
+		WithArgs(sqlmock.AnyArg(), assetResponse.AssetID, "GET", assetResponse.StatusCode, assetResponse.Headers, assetResponse.Body, assetResponse.ResponseTime,
+			301, 1, "https://www.example.com/", "followed", "", nil, "", "", "", "", 0, sqlmock.AnyArg(), assetResponse.AssetID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := repo.CreateAssetResponse(ctx, assetResponse)
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, assetResponse.ID)
 	assert.False(t, assetResponse.CreatedAt.IsZero())
+
+	// Nothing is stored for an asset that does not exist
+	mock.ExpectExec("INSERT INTO asset_responses").WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.CreateAssetResponse(ctx, &AssetResponse{AssetID: uuid.New()})
+	assert.ErrorContains(t, err, "not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestAssetRepository_GetAssetResponsesByAssetID(t *testing.T) {
@@ -538,7 +729,7 @@ func TestAssetRepository_GetAssetResponsesByAssetID(t *testing.T) {
 		rows.AddRow(resp.ID, resp.AssetID, resp.StatusCode, resp.Headers, resp.Body, resp.ResponseTime, resp.CreatedAt)
 	}
 
-	mock.ExpectQuery("SELECT \\* FROM asset_responses WHERE asset_id = \\$1 ORDER BY created_at DESC").
+	mock.ExpectQuery("SELECT r.\\* FROM asset_responses r JOIN assets a ON a.host_id = r.host_id\\s+WHERE a.id = \\$1\\s+ORDER BY r.created_at DESC").
 		WithArgs(assetID).
 		WillReturnRows(rows)
 
@@ -570,7 +761,7 @@ func TestAssetRepository_GetLatestAssetResponseByAssetID(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "asset_id", "status_code", "headers", "body", "response_time", "created_at"}).
 		AddRow(expectedResponse.ID, expectedResponse.AssetID, expectedResponse.StatusCode, expectedResponse.Headers, expectedResponse.Body, expectedResponse.ResponseTime, expectedResponse.CreatedAt)
 
-	mock.ExpectQuery("SELECT \\* FROM asset_responses WHERE asset_id = \\$1 ORDER BY created_at DESC LIMIT 1").
+	mock.ExpectQuery("SELECT r.\\* FROM asset_responses r JOIN assets a ON a.host_id = r.host_id\\s+WHERE a.id = \\$1\\s+ORDER BY r.created_at DESC\\s+LIMIT 1").
 		WithArgs(assetID).
 		WillReturnRows(rows)
 
@@ -684,3 +875,201 @@ func TestAssetRepository_SearchAssetResponsesByStatusCode(t *testing.T) {
 	assert.Equal(t, expectedResponses[0].ID, responses[0].ID)
 	assert.Equal(t, statusCode, responses[0].StatusCode)
 }
+
+func TestAssetRepository_GetSourceYield(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"source", "assets", "active_assets", "programs", "last_found_at"}).
+		AddRow("chaosdb", 120, 100, 4, now).
+		AddRow("hackerone", 12, 12, 4, now)
+	mock.ExpectQuery("SELECT COALESCE\\(NULLIF\\(first_source").WillReturnRows(rows)
+
+	yields, err := repo.GetSourceYield(context.Background())
+	require.NoError(t, err)
+	require.Len(t, yields, 2)
+	assert.Equal(t, "chaosdb", yields[0].Source)
+	assert.Equal(t, 120, yields[0].Assets)
+	assert.Equal(t, 100, yields[0].ActiveAssets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_GetScanSourceYield(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	scanID := uuid.New()
+
+	mock.ExpectQuery("SELECT COALESCE\\(NULLIF\\(first_source, ''\\), source\\) AS source, COUNT\\(\\*\\) AS assets").
+		WithArgs(scanID).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "assets"}).AddRow("chaosdb", 7).AddRow("hackerone", 2))
+
+	yields, err := repo.GetScanSourceYield(context.Background(), scanID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"chaosdb": 7, "hackerone": 2}, yields)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRepository_RequestScanCancel(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	ctx := context.Background()
+	scanID := uuid.New()
+
+	mock.ExpectExec("UPDATE scans SET cancel_requested_at").
+		WithArgs(scanID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RequestScanCancel(ctx, scanID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRepository_RequestScanCancel_NotRunning(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	ctx := context.Background()
+	scanID := uuid.New()
+
+	mock.ExpectExec("UPDATE scans SET cancel_requested_at").
+		WithArgs(scanID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM scans WHERE id = \\$1").
+		WithArgs(scanID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "status", "assets_found", "started_at", "completed_at", "error", "created_at", "updated_at", "cancel_requested_at"}).
+			AddRow(scanID, uuid.New(), "completed", 3, time.Now(), time.Now(), "", time.Now(), time.Now(), nil))
+
+	err := repo.RequestScanCancel(ctx, scanID)
+	assert.ErrorIs(t, err, ErrScanNotRunning)
+}
+
+func TestScanRepository_RequestScanCancel_NotFound(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	ctx := context.Background()
+	scanID := uuid.New()
+
+	mock.ExpectExec("UPDATE scans SET cancel_requested_at").
+		WithArgs(scanID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM scans WHERE id = \\$1").
+		WithArgs(scanID).
+		WillReturnError(sql.ErrNoRows)
+
+	err := repo.RequestScanCancel(ctx, scanID)
+	assert.ErrorIs(t, err, ErrScanNotFound)
+}
+
+func TestScanRepository_HeartbeatScan(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	ctx := context.Background()
+	scanID := uuid.New()
+
+	mock.ExpectQuery("UPDATE scans SET heartbeat_at = NOW\\(\\) WHERE id = \\$1 RETURNING cancel_requested_at IS NOT NULL").
+		WithArgs(scanID).
+		WillReturnRows(sqlmock.NewRows([]string{"requested"}).AddRow(true))
+	mock.ExpectQuery("UPDATE scans SET heartbeat_at").
+		WithArgs(scanID).
+		WillReturnError(sql.ErrNoRows)
+
+	requested, err := repo.HeartbeatScan(ctx, scanID)
+	require.NoError(t, err)
+	assert.True(t, requested)
+
+	// A scan that no longer exists is not cancelled
+	requested, err = repo.HeartbeatScan(ctx, scanID)
+	require.NoError(t, err)
+	assert.False(t, requested)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRepository_AbortStaleScans(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	staleSince := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	scanID := uuid.New()
+
+	mock.ExpectQuery("UPDATE scans SET status = 'aborted'.+WHERE status = 'running' AND COALESCE\\(heartbeat_at, started_at\\) < \\$1").
+		WithArgs(staleSince, "agent stopped").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "status", "error"}).
+			AddRow(scanID, uuid.New(), "aborted", "agent stopped"))
+
+	scans, err := repo.AbortStaleScans(context.Background(), staleSince, "agent stopped")
+	require.NoError(t, err)
+	require.Len(t, scans, 1)
+	assert.Equal(t, scanID, scans[0].ID)
+	assert.Equal(t, "aborted", scans[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetHostKey(t *testing.T) {
+	tests := map[string]string{
+		"https://API.example.com":           "api.example.com",
+		"http://api.example.com":            "api.example.com",
+		"https://api.example.com:443/login": "api.example.com",
+		"http://api.example.com:80":         "api.example.com",
+		"http://api.example.com:443":        "api.example.com:443",
+		"https://api.example.com:8443?x=1":  "api.example.com:8443",
+		"https://[2001:db8::1]:443":         "[2001:db8::1]",
+		"api.example.com":                   "api.example.com",
+	}
+
+	for rawURL, want := range tests {
+		assert.Equal(t, want, AssetHostKey(rawURL), rawURL)
+	}
+}
+
+func TestAssetRepository_GetLivenessCounts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+
+	rows := sqlmock.NewRows([]string{"liveness", "assets"}).
+		AddRow("live", 80).
+		AddRow("waf-blocked", 12).
+		AddRow("unprobed", 5)
+	mock.ExpectQuery("SELECT COALESCE\\(NULLIF\\(h.liveness").WillReturnRows(rows)
+
+	counts, err := repo.GetLivenessCounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, counts, 3)
+	assert.Equal(t, "waf-blocked", counts[1].Liveness)
+	assert.Equal(t, 12, counts[1].Assets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_GetProbeErrorCounts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	since := time.Now().Add(-24 * time.Hour)
+
+	rows := sqlmock.NewRows([]string{"liveness", "error", "assets"}).
+		AddRow("refused", "connection refused", 40).
+		AddRow("timed-out", "context deadline exceeded", 7)
+	mock.ExpectQuery("SELECT h.liveness, h.last_probe_error AS error").
+		WithArgs(since, 5).
+		WillReturnRows(rows)
+
+	counts, err := repo.GetProbeErrorCounts(context.Background(), since, 5)
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, &ProbeErrorCount{Liveness: "refused", Error: "connection refused", Assets: 40}, counts[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pruneResponsesBatch is the number of responses deleted per statement, so
+// pruning a large table never holds its locks for long
+const pruneResponsesBatch = 5000
+
+// ResponseRetention limits the responses kept per asset. The latest response
+// of each asset and method is always kept, as it is the one exports,
+// clustering and triage read.
+type ResponseRetention struct {
+	KeepPerAsset int           // responses kept per asset and method; 0 keeps all
+	MaxAge       time.Duration // responses older than this are deleted; 0 keeps them
+}
+
+// Enabled reports whether the retention deletes any responses
+func (r ResponseRetention) Enabled() bool {
+	return r.KeepPerAsset > 0 || r.MaxAge > 0
+}
+
+// prunableResponsesQuery selects the responses outside a retention: $1 is the
+// number kept per asset and method (0 for all) and $2 the oldest capture time
+// kept (NULL for all). Responses are ranked newest first, so the first is
+// never selected.
+const prunableResponsesQuery = `
+	SELECT id FROM (
+		SELECT id, created_at, ROW_NUMBER() OVER (PARTITION BY asset_id, method ORDER BY created_at DESC, id DESC) AS position
+		FROM asset_responses
+	) ranked
+	WHERE position > 1
+	  AND (($1::int > 0 AND position > $1::int) OR ($2::timestamptz IS NOT NULL AND created_at < $2::timestamptz))
+`
+
+// args returns the parameters of prunableResponsesQuery
+func (r ResponseRetention) args(now time.Time) []interface{} {
+	var oldest *time.Time
+	if r.MaxAge > 0 {
+		t := now.Add(-r.MaxAge)
+		oldest = &t
+	}
+	return []interface{}{r.KeepPerAsset, oldest}
+}
+
+// CountPrunableResponses counts the responses a retention would delete
+func (r *AssetRepository) CountPrunableResponses(ctx context.Context, retention ResponseRetention) (int64, error) {
+	if !retention.Enabled() {
+		return 0, nil
+	}
+
+	var count int64
+	query := `SELECT COUNT(*) FROM (` + prunableResponsesQuery + `) prunable`
+	if err := r.db.GetContext(ctx, &count, query, retention.args(time.Now())...); err != nil {
+		return 0, fmt.Errorf("failed to count prunable responses: %w", err)
+	}
+
+	return count, nil
+}
+
+// PruneResponses deletes the responses outside a retention in batches of
+// pruneResponsesBatch and returns how many were deleted, with the body
+// references of the deleted responses whose bodies were offloaded. Rule
+// matches and API schemas of a deleted response keep their rows with the
+// reference cleared.
+func (r *AssetRepository) PruneResponses(ctx context.Context, retention ResponseRetention) (int64, []string, error) {
+	if !retention.Enabled() {
+		return 0, nil, nil
+	}
+	defer r.observe("delete", TableAssetResponses, time.Now())
+
+	query := `
+		WITH deleted AS (
+			DELETE FROM asset_responses WHERE id IN (` + prunableResponsesQuery + ` LIMIT $3)
+			RETURNING body_ref
+		)
+		SELECT COUNT(*), COALESCE(array_agg(DISTINCT body_ref) FILTER (WHERE body_ref <> ''), '{}') FROM deleted
+	`
+	args := append(retention.args(time.Now()), pruneResponsesBatch)
+
+	var total int64
+	seen := make(map[string]bool)
+	var bodyRefs []string
+	for {
+		var rows int64
+		var refs pq.StringArray
+		if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&rows, &refs); err != nil {
+			return total, bodyRefs, fmt.Errorf("failed to prune responses: %w", err)
+		}
+		total += rows
+		for _, ref := range refs {
+			if !seen[ref] {
+				seen[ref] = true
+				bodyRefs = append(bodyRefs, ref)
+			}
+		}
+
+		if rows < pruneResponsesBatch {
+			return total, bodyRefs, nil
+		}
+	}
+}
+
+// GetUnreferencedBodyRefs returns the body references no response refers to
+// anymore, whose objects can be deleted from the body store
+func (r *AssetRepository) GetUnreferencedBodyRefs(ctx context.Context, refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	var unreferenced []string
+	query := `
+		SELECT ref FROM unnest($1::text[]) AS ref
+		WHERE NOT EXISTS (SELECT 1 FROM asset_responses WHERE body_ref = ref)
+	`
+	if err := r.db.SelectContext(ctx, &unreferenced, query, pq.Array(refs)); err != nil {
+		return nil, fmt.Errorf("failed to get unreferenced body references: %w", err)
+	}
+
+	return unreferenced, nil
+}
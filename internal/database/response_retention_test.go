@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseRetention_Enabled(t *testing.T) {
+	assert.False(t, ResponseRetention{}.Enabled())
+	assert.True(t, ResponseRetention{KeepPerAsset: 3}.Enabled())
+	assert.True(t, ResponseRetention{MaxAge: time.Hour}.Enabled())
+}
+
+func TestAssetRepository_PruneResponses(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	retention := ResponseRetention{KeepPerAsset: 3, MaxAge: 30 * 24 * time.Hour}
+
+	// Full batches are followed by another until one comes back short, and
+	// each offloaded body is reported once
+	mock.ExpectQuery("DELETE FROM asset_responses .+ RETURNING body_ref").WithArgs(3, sqlmock.AnyArg(), pruneResponsesBatch).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "refs"}).AddRow(pruneResponsesBatch, "{s3://bodies/a,s3://bodies/b}"))
+	mock.ExpectQuery("DELETE FROM asset_responses").WithArgs(3, sqlmock.AnyArg(), pruneResponsesBatch).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "refs"}).AddRow(12, "{s3://bodies/b}"))
+
+	pruned, refs, err := repo.PruneResponses(context.Background(), retention)
+	require.NoError(t, err)
+	assert.Equal(t, int64(pruneResponsesBatch+12), pruned)
+	assert.Equal(t, []string{"s3://bodies/a", "s3://bodies/b"}, refs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Without a retention nothing is deleted
+	pruned, refs, err = repo.PruneResponses(context.Background(), ResponseRetention{})
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+	assert.Empty(t, refs)
+}
+
+func TestAssetRepository_GetUnreferencedBodyRefs(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+
+	mock.ExpectQuery("SELECT ref FROM unnest").WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"ref"}).AddRow("s3://bodies/a"))
+
+	refs, err := repo.GetUnreferencedBodyRefs(context.Background(), []string{"s3://bodies/a", "s3://bodies/b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3://bodies/a"}, refs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	refs, err = repo.GetUnreferencedBodyRefs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+}
+
+func TestAssetRepository_CountPrunableResponses(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+
+	// Only a count per asset keeps every response regardless of age
+	mock.ExpectQuery("SELECT COUNT").WithArgs(5, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repo.CountPrunableResponses(context.Background(), ResponseRetention{KeepPerAsset: 5})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ScanArtifactRepository handles the records of scan reports stored in object storage
+type ScanArtifactRepository struct {
+	*Repository
+}
+
+// NewScanArtifactRepository creates a new scan artifact repository
+func NewScanArtifactRepository(db *sqlx.DB) *ScanArtifactRepository {
+	return &ScanArtifactRepository{Repository: NewRepository(db)}
+}
+
+// GetScanArtifact retrieves the stored report of a scan in a format, or nil
+// when it has not been uploaded
+func (r *ScanArtifactRepository) GetScanArtifact(ctx context.Context, scanID uuid.UUID, format string) (*ScanArtifact, error) {
+	var artifact ScanArtifact
+	query := `SELECT * FROM scan_artifacts WHERE scan_id = $1 AND format = $2`
+
+	err := r.db.GetContext(ctx, &artifact, query, scanID, format)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get scan artifact: %w", err)
+	}
+
+	return &artifact, nil
+}
+
+// SaveScanArtifact records an uploaded scan report, replacing the record of
+// an earlier upload in the same format
+func (r *ScanArtifactRepository) SaveScanArtifact(ctx context.Context, artifact *ScanArtifact) error {
+	query := `
+		INSERT INTO scan_artifacts (scan_id, format, bucket, object_key, content_type, size_bytes, uploaded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (scan_id, format) DO UPDATE SET
+			bucket = EXCLUDED.bucket,
+			object_key = EXCLUDED.object_key,
+			content_type = EXCLUDED.content_type,
+			size_bytes = EXCLUDED.size_bytes,
+			uploaded_at = EXCLUDED.uploaded_at
+		RETURNING uploaded_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, artifact.ScanID, artifact.Format, artifact.Bucket,
+		artifact.ObjectKey, artifact.ContentType, artifact.SizeBytes).Scan(&artifact.UploadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save scan artifact: %w", err)
+	}
+
+	return nil
+}
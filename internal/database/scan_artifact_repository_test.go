@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanArtifactRepository_GetScanArtifact(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanArtifactRepository(db)
+	scanID, now := uuid.New(), time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM scan_artifacts WHERE scan_id = \\$1 AND format = \\$2").
+		WithArgs(scanID, "json").
+		WillReturnRows(sqlmock.NewRows([]string{"scan_id", "format", "bucket", "object_key", "content_type", "size_bytes", "uploaded_at"}).
+			AddRow(scanID, "json", "reports", "monitor-agent/scans/x.json", "application/json", 512, now))
+	mock.ExpectQuery("SELECT \\* FROM scan_artifacts").
+		WithArgs(scanID, "csv").
+		WillReturnRows(sqlmock.NewRows([]string{"scan_id"}))
+
+	artifact, err := repo.GetScanArtifact(context.Background(), scanID, "json")
+	require.NoError(t, err)
+	require.NotNil(t, artifact)
+	assert.Equal(t, "monitor-agent/scans/x.json", artifact.ObjectKey)
+	assert.Equal(t, int64(512), artifact.SizeBytes)
+
+	artifact, err = repo.GetScanArtifact(context.Background(), scanID, "csv")
+	require.NoError(t, err)
+	assert.Nil(t, artifact, "a report that was never uploaded is not an error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanArtifactRepository_SaveScanArtifact(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanArtifactRepository(db)
+	artifact := &ScanArtifact{
+		ScanID:      uuid.New(),
+		Format:      "csv",
+		Bucket:      "reports",
+		ObjectKey:   "monitor-agent/scans/x.csv",
+		ContentType: "text/csv",
+		SizeBytes:   128,
+	}
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO scan_artifacts").
+		WithArgs(artifact.ScanID, "csv", "reports", "monitor-agent/scans/x.csv", "text/csv", int64(128)).
+		WillReturnRows(sqlmock.NewRows([]string{"uploaded_at"}).AddRow(now))
+
+	require.NoError(t, repo.SaveScanArtifact(context.Background(), artifact))
+	assert.Equal(t, now, artifact.UploadedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
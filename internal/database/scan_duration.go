@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetLongestScanDurations returns how long the longest of the latest recent
+// scans of each program of a platform ran, counting scans that completed or
+// timed out, keyed by program URL; programs without such scans are left out
+func (r *ScanRepository) GetLongestScanDurations(ctx context.Context, platform string, recent int) (map[string]time.Duration, error) {
+	var rows []struct {
+		ProgramURL string  `db:"program_url"`
+		Seconds    float64 `db:"seconds"`
+	}
+	query := `
+		SELECT program_url, MAX(seconds) AS seconds
+		FROM (
+			SELECT p.program_url, EXTRACT(EPOCH FROM s.completed_at - s.started_at) AS seconds,
+				ROW_NUMBER() OVER (PARTITION BY s.program_id ORDER BY s.started_at DESC) AS n
+			FROM scans s
+			JOIN programs p ON p.id = s.program_id
+			WHERE p.platform = $1 AND s.status IN ('completed', 'timed_out') AND s.completed_at IS NOT NULL
+		) recent
+		WHERE n <= $2
+		GROUP BY program_url
+	`
+
+	if err := r.db.SelectContext(ctx, &rows, query, platform, recent); err != nil {
+		return nil, fmt.Errorf("failed to get scan durations: %w", err)
+	}
+
+	durations := make(map[string]time.Duration, len(rows))
+	for _, row := range rows {
+		durations[row.ProgramURL] = time.Duration(row.Seconds * float64(time.Second))
+	}
+	return durations, nil
+}
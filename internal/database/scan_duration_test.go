@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanRepository_GetLongestScanDurations(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+
+	mock.ExpectQuery("SELECT program_url, MAX\\(seconds\\)").WithArgs("hackerone", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"program_url", "seconds"}).
+			AddRow("https://hackerone.com/acme", 5400.5).
+			AddRow("https://hackerone.com/globex", 600.0))
+
+	durations, err := repo.GetLongestScanDurations(context.Background(), "hackerone", 5)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"https://hackerone.com/acme":   90*time.Minute + 500*time.Millisecond,
+		"https://hackerone.com/globex": 10 * time.Minute,
+	}, durations)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
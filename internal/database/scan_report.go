@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetScansStartedSince retrieves the program scans started at or after since,
+// oldest first, e.g. those of a scan run that just finished
+func (r *ScanRepository) GetScansStartedSince(ctx context.Context, since time.Time) ([]*Scan, error) {
+	var scans []*Scan
+	query := `SELECT * FROM scans WHERE started_at >= $1 ORDER BY started_at`
+
+	if err := r.db.SelectContext(ctx, &scans, query, since); err != nil {
+		return nil, fmt.Errorf("failed to get scans started since %s: %w", since.Format(time.RFC3339), err)
+	}
+
+	return scans, nil
+}
+
+// IsFirstProgramScan reports whether no scan of the program started before
+// the given one, so the program was new to the scan
+func (r *ScanRepository) IsFirstProgramScan(ctx context.Context, scan *Scan) (bool, error) {
+	var first bool
+	query := `SELECT NOT EXISTS (SELECT 1 FROM scans WHERE program_id = $1 AND started_at < $2 AND id <> $3)`
+
+	if err := r.db.GetContext(ctx, &first, query, scan.ProgramID, scan.StartedAt, scan.ID); err != nil {
+		return false, fmt.Errorf("failed to check for earlier scans: %w", err)
+	}
+
+	return first, nil
+}
+
+// GetScanScopeChange retrieves the scope snapshot a scan recorded and the
+// snapshot before it. A scan records a snapshot only when the scope changed,
+// so current is nil when it did not; previous is nil for a program's first
+// snapshot.
+func (r *ScopeRepository) GetScanScopeChange(ctx context.Context, scanID uuid.UUID) (current, previous *ProgramScope, err error) {
+	var scope ProgramScope
+	query := `SELECT * FROM program_scopes WHERE scan_id = $1 ORDER BY recorded_at DESC LIMIT 1`
+
+	if err := r.db.GetContext(ctx, &scope, query, scanID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get scope snapshot of scan: %w", err)
+	}
+
+	var before ProgramScope
+	query = `
+		SELECT * FROM program_scopes
+		WHERE program_id = $1 AND recorded_at < $2
+		ORDER BY recorded_at DESC
+		LIMIT 1
+	`
+
+	if err := r.db.GetContext(ctx, &before, query, scope.ProgramID, scope.RecordedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return &scope, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get previous scope snapshot: %w", err)
+	}
+
+	return &scope, &before, nil
+}
+
+// GetProgramFailuresBetween retrieves the discovery failures of a program
+// last recorded between from and to, by domain. Failures are removed once
+// their domain is processed again without errors, so a scan's failures are
+// only found until then.
+func (r *DiscoveryFailureRepository) GetProgramFailuresBetween(ctx context.Context, programID uuid.UUID, from, to time.Time) ([]*DiscoveryFailure, error) {
+	var failures []*DiscoveryFailure
+	query := `
+		SELECT f.*, p.name AS program_name, p.program_url
+		FROM discovery_failures f
+		JOIN programs p ON p.id = f.program_id
+		WHERE f.program_id = $1 AND f.last_failed_at BETWEEN $2 AND $3
+		ORDER BY f.domain
+	`
+
+	if err := r.db.SelectContext(ctx, &failures, query, programID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get discovery failures of program: %w", err)
+	}
+
+	return failures, nil
+}
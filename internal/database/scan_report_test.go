@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanRepository_GetScansStartedSince(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	since := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery("SELECT \\* FROM scans WHERE started_at >= \\$1 ORDER BY started_at").
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "status"}).
+			AddRow(uuid.New(), uuid.New(), "completed").
+			AddRow(uuid.New(), uuid.New(), "failed"))
+
+	scans, err := repo.GetScansStartedSince(context.Background(), since)
+	require.NoError(t, err)
+	require.Len(t, scans, 2)
+	assert.Equal(t, "failed", scans[1].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRepository_IsFirstProgramScan(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	scan := &Scan{ID: uuid.New(), ProgramID: uuid.New(), StartedAt: time.Now()}
+
+	mock.ExpectQuery("SELECT NOT EXISTS").
+		WithArgs(scan.ProgramID, scan.StartedAt, scan.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"not_exists"}).AddRow(true))
+
+	first, err := repo.IsFirstProgramScan(context.Background(), scan)
+	require.NoError(t, err)
+	assert.True(t, first)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScopeRepository_GetScanScopeChange(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScopeRepository(db)
+	programID := uuid.New()
+	scanID := uuid.New()
+	now := time.Now()
+	columns := []string{"id", "program_id", "scan_id", "content_hash", "in_scope", "out_of_scope", "recorded_at"}
+
+	mock.ExpectQuery("SELECT \\* FROM program_scopes WHERE scan_id = \\$1").
+		WithArgs(scanID).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), programID, scanID, "new", "{*.acme.com,api.acme.com}", "{}", now))
+	mock.ExpectQuery("WHERE program_id = \\$1 AND recorded_at < \\$2").
+		WithArgs(programID, now).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), programID, nil, "old", "{*.acme.com}", "{}", now.Add(-24*time.Hour)))
+
+	current, previous, err := repo.GetScanScopeChange(context.Background(), scanID)
+	require.NoError(t, err)
+	require.NotNil(t, current)
+	require.NotNil(t, previous)
+	assert.Equal(t, pq.StringArray{"*.acme.com", "api.acme.com"}, current.InScope)
+	assert.Equal(t, pq.StringArray{"*.acme.com"}, previous.InScope)
+
+	mock.ExpectQuery("SELECT \\* FROM program_scopes WHERE scan_id = \\$1").
+		WithArgs(scanID).
+		WillReturnRows(sqlmock.NewRows(columns))
+	current, previous, err = repo.GetScanScopeChange(context.Background(), scanID)
+	require.NoError(t, err)
+	assert.Nil(t, current, "the scope did not change")
+	assert.Nil(t, previous)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDiscoveryFailureRepository_GetProgramFailuresBetween(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewDiscoveryFailureRepository(db)
+	programID := uuid.New()
+	now := time.Now()
+	from := now.Add(-time.Hour)
+
+	mock.ExpectQuery("WHERE f.program_id = \\$1 AND f.last_failed_at BETWEEN \\$2 AND \\$3").
+		WithArgs(programID, from, now).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "domain", "stage", "source", "error", "attempts", "first_failed_at", "last_failed_at", "next_retry_at", "program_name", "program_url"}).
+			AddRow(programID, "example.com", "probe", "httpx", "timeout", 2, from, now, now, "Acme", "https://hackerone.com/acme"))
+
+	failures, err := repo.GetProgramFailuresBetween(context.Background(), programID, from, now)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "timeout", failures[0].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ScanRunRepository handles full scan runs and their checkpoints
+type ScanRunRepository struct {
+	*Repository
+}
+
+// NewScanRunRepository creates a new scan run repository
+func NewScanRunRepository(db *sqlx.DB) *ScanRunRepository {
+	return &ScanRunRepository{Repository: NewRepository(db)}
+}
+
+// CreateScanRun records the start of a full scan
+func (r *ScanRunRepository) CreateScanRun(ctx context.Context) (*ScanRun, error) {
+	now := time.Now()
+	run := &ScanRun{ID: uuid.New(), Status: ScanRunRunning, StartedAt: now, UpdatedAt: now}
+
+	query := `
+		INSERT INTO scan_runs (id, status, resumes, started_at, updated_at)
+		VALUES (:id, :status, :resumes, :started_at, :updated_at)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, run); err != nil {
+		return nil, fmt.Errorf("failed to create scan run: %w", err)
+	}
+
+	return run, nil
+}
+
+// GetLatestScanRun retrieves the most recently started scan run, or nil if
+// there is none
+func (r *ScanRunRepository) GetLatestScanRun(ctx context.Context) (*ScanRun, error) {
+	var run ScanRun
+	query := `SELECT * FROM scan_runs ORDER BY started_at DESC LIMIT 1`
+
+	err := r.db.GetContext(ctx, &run, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest scan run: %w", err)
+	}
+
+	return &run, nil
+}
+
+// ResumeScanRun marks a run that did not complete as running again
+func (r *ScanRunRepository) ResumeScanRun(ctx context.Context, run *ScanRun) error {
+	query := `
+		UPDATE scan_runs SET status = $2, resumes = resumes + 1, updated_at = NOW()
+		WHERE id = $1
+		RETURNING resumes, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, run.ID, ScanRunRunning).Scan(&run.Resumes, &run.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to resume scan run: %w", err)
+	}
+
+	run.Status = ScanRunRunning
+	return nil
+}
+
+// FinishScanRun records how a run ended; completed runs get a completion time
+func (r *ScanRunRepository) FinishScanRun(ctx context.Context, run *ScanRun, status string) error {
+	now := time.Now()
+	run.Status = status
+	run.UpdatedAt = now
+	if status == ScanRunCompleted {
+		run.CompletedAt = &now
+	}
+
+	query := `UPDATE scan_runs SET status = $2, updated_at = $3, completed_at = $4 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, run.ID, run.Status, run.UpdatedAt, run.CompletedAt); err != nil {
+		return fmt.Errorf("failed to finish scan run: %w", err)
+	}
+
+	return nil
+}
+
+// StartPlatform records how many programs a platform listed for a run
+func (r *ScanRunRepository) StartPlatform(ctx context.Context, runID uuid.UUID, platform string, programs int) error {
+	query := `
+		INSERT INTO scan_run_platforms (run_id, platform, programs)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (run_id, platform) DO UPDATE SET programs = EXCLUDED.programs
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, runID, platform, programs); err != nil {
+		return fmt.Errorf("failed to record scan run platform: %w", err)
+	}
+
+	return nil
+}
+
+// CompletePlatform records that every program of a platform was processed
+func (r *ScanRunRepository) CompletePlatform(ctx context.Context, runID uuid.UUID, platform string) error {
+	query := `
+		INSERT INTO scan_run_platforms (run_id, platform, completed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (run_id, platform) DO UPDATE SET completed_at = EXCLUDED.completed_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, runID, platform); err != nil {
+		return fmt.Errorf("failed to complete scan run platform: %w", err)
+	}
+
+	return nil
+}
+
+// MarkProgramProcessed records that a run processed a program
+func (r *ScanRunRepository) MarkProgramProcessed(ctx context.Context, runID uuid.UUID, platform, programURL string) error {
+	query := `
+		INSERT INTO scan_run_programs (run_id, platform, program_url, processed_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (run_id, platform, program_url) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, runID, platform, programURL); err != nil {
+		return fmt.Errorf("failed to record processed program: %w", err)
+	}
+
+	return nil
+}
+
+// GetPlatforms retrieves the progress of a run on each platform it reached
+func (r *ScanRunRepository) GetPlatforms(ctx context.Context, runID uuid.UUID) ([]*ScanRunPlatform, error) {
+	var platforms []*ScanRunPlatform
+	query := `SELECT * FROM scan_run_platforms WHERE run_id = $1 ORDER BY platform`
+
+	if err := r.db.SelectContext(ctx, &platforms, query, runID); err != nil {
+		return nil, fmt.Errorf("failed to get scan run platforms: %w", err)
+	}
+
+	return platforms, nil
+}
+
+// GetProcessedPrograms retrieves the programs a run processed
+func (r *ScanRunRepository) GetProcessedPrograms(ctx context.Context, runID uuid.UUID) ([]*ScanRunProgram, error) {
+	var programs []*ScanRunProgram
+	query := `SELECT * FROM scan_run_programs WHERE run_id = $1 ORDER BY processed_at`
+
+	if err := r.db.SelectContext(ctx, &programs, query, runID); err != nil {
+		return nil, fmt.Errorf("failed to get processed programs: %w", err)
+	}
+
+	return programs, nil
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanRunRepository_GetLatestScanRun(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRunRepository(db)
+	runID := uuid.New()
+	startedAt := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT \\* FROM scan_runs ORDER BY started_at DESC LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "resumes", "started_at", "updated_at", "completed_at"}).
+			AddRow(runID, ScanRunInterrupted, 1, startedAt, startedAt, nil))
+	mock.ExpectQuery("SELECT \\* FROM scan_runs ORDER BY started_at DESC LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "resumes", "started_at", "updated_at", "completed_at"}))
+
+	run, err := repo.GetLatestScanRun(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &ScanRun{ID: runID, Status: ScanRunInterrupted, Resumes: 1, StartedAt: startedAt, UpdatedAt: startedAt}, run)
+
+	run, err = repo.GetLatestScanRun(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, run)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRunRepository_ResumeAndFinish(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRunRepository(db)
+	run := &ScanRun{ID: uuid.New(), Status: ScanRunInterrupted}
+	updatedAt := time.Now()
+
+	mock.ExpectQuery("UPDATE scan_runs SET status = \\$2, resumes = resumes \\+ 1").
+		WithArgs(run.ID, ScanRunRunning).
+		WillReturnRows(sqlmock.NewRows([]string{"resumes", "updated_at"}).AddRow(1, updatedAt))
+	mock.ExpectExec("UPDATE scan_runs SET status = \\$2, updated_at = \\$3, completed_at = \\$4").
+		WithArgs(run.ID, ScanRunCompleted, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.ResumeScanRun(context.Background(), run))
+	assert.Equal(t, ScanRunRunning, run.Status)
+	assert.Equal(t, 1, run.Resumes)
+
+	require.NoError(t, repo.FinishScanRun(context.Background(), run, ScanRunCompleted))
+	assert.Equal(t, ScanRunCompleted, run.Status)
+	assert.NotNil(t, run.CompletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRunRepository_Checkpoints(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRunRepository(db)
+	runID := uuid.New()
+	ctx := context.Background()
+
+	mock.ExpectExec("INSERT INTO scan_run_platforms \\(run_id, platform, programs\\)").
+		WithArgs(runID, "hackerone", 12).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO scan_run_programs").
+		WithArgs(runID, "hackerone", "https://hackerone.com/acme").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO scan_run_platforms \\(run_id, platform, completed_at\\)").
+		WithArgs(runID, "hackerone").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT \\* FROM scan_run_programs WHERE run_id = \\$1").WithArgs(runID).
+		WillReturnRows(sqlmock.NewRows([]string{"run_id", "platform", "program_url", "processed_at"}).
+			AddRow(runID, "hackerone", "https://hackerone.com/acme", time.Now()))
+
+	require.NoError(t, repo.StartPlatform(ctx, runID, "hackerone", 12))
+	require.NoError(t, repo.MarkProgramProcessed(ctx, runID, "hackerone", "https://hackerone.com/acme"))
+	require.NoError(t, repo.CompletePlatform(ctx, runID, "hackerone"))
+
+	programs, err := repo.GetProcessedPrograms(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+	assert.Equal(t, "https://hackerone.com/acme", programs[0].ProgramURL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
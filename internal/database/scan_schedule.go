@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// scheduleLockID is the key of the advisory lock held while a scheduled scan
+// runs, so daemons sharing a database never run the schedule twice at once
+const scheduleLockID int64 = 7_301_829_105
+
+// GetLastScheduledRun returns the schedule time of the latest scheduled run
+// whose scans all completed, or nil when none did
+func (r *ScanRepository) GetLastScheduledRun(ctx context.Context) (*time.Time, error) {
+	var scheduledAt time.Time
+	query := `
+		SELECT scheduled_at FROM scans
+		WHERE scheduled_at IS NOT NULL
+		GROUP BY scheduled_at
+		HAVING bool_and(status = 'completed')
+		ORDER BY scheduled_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.GetContext(ctx, &scheduledAt, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last scheduled run: %w", err)
+	}
+
+	return &scheduledAt, nil
+}
+
+// TryLockSchedule takes the schedule lock without waiting. It reports false
+// when another process holds it; otherwise unlock has to be called once the
+// scheduled scan is done.
+func (r *ScanRepository) TryLockSchedule(ctx context.Context) (unlock func(), locked bool, err error) {
+	// Advisory locks belong to a session, so the lock keeps its connection
+	conn, err := r.db.Connx(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get schedule lock connection: %w", err)
+	}
+
+	if err := conn.GetContext(ctx, &locked, "SELECT pg_try_advisory_lock($1)", scheduleLockID); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to take schedule lock: %w", err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock = func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", scheduleLockID); err != nil {
+			logrus.Warnf("Failed to release schedule lock: %v", err)
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
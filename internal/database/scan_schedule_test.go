@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanRepository_GetLastScheduledRun(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	scheduledAt := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT scheduled_at FROM scans").
+		WillReturnRows(sqlmock.NewRows([]string{"scheduled_at"}).AddRow(scheduledAt))
+	mock.ExpectQuery("SELECT scheduled_at FROM scans").
+		WillReturnRows(sqlmock.NewRows([]string{"scheduled_at"}))
+
+	last, err := repo.GetLastScheduledRun(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, scheduledAt, *last)
+
+	last, err = repo.GetLastScheduledRun(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, last)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRepository_TryLockSchedule(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(scheduleLockID).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(scheduleLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(scheduleLockID).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+
+	unlock, locked, err := repo.TryLockSchedule(context.Background())
+	require.NoError(t, err)
+	require.True(t, locked)
+	unlock()
+
+	// Another daemon holds the lock
+	_, locked, err = repo.TryLockSchedule(context.Background())
+	require.NoError(t, err)
+	assert.False(t, locked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
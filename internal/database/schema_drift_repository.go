@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SchemaDriftRepository handles platform payload schema drift database operations
+type SchemaDriftRepository struct {
+	*Repository
+}
+
+// NewSchemaDriftRepository creates a new schema drift repository
+func NewSchemaDriftRepository(db *sqlx.DB) *SchemaDriftRepository {
+	return &SchemaDriftRepository{Repository: NewRepository(db)}
+}
+
+// RecordSchemaDrift stores drift found in a platform payload, or counts
+// another sighting of drift recorded before
+func (r *SchemaDriftRepository) RecordSchemaDrift(ctx context.Context, drift *SchemaDrift) error {
+	query := `
+		INSERT INTO platform_schema_drift (platform, payload, field, change, first_seen_at, last_seen_at, scans)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), 1)
+		ON CONFLICT (platform, payload, field, change) DO UPDATE SET
+			last_seen_at = NOW(),
+			scans = platform_schema_drift.scans + 1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, drift.Platform, drift.Payload, drift.Field, drift.Change)
+	if err != nil {
+		return fmt.Errorf("failed to record schema drift: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecentSchemaDrift retrieves the drift seen since a time, most recent first
+func (r *SchemaDriftRepository) GetRecentSchemaDrift(ctx context.Context, since time.Time, limit int) ([]*SchemaDrift, error) {
+	var drifts []*SchemaDrift
+	query := `
+		SELECT * FROM platform_schema_drift
+		WHERE last_seen_at >= $1
+		ORDER BY last_seen_at DESC, platform, payload, field
+		LIMIT $2
+	`
+
+	err := r.db.SelectContext(ctx, &drifts, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent schema drift: %w", err)
+	}
+
+	return drifts, nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDriftRepository_RecordSchemaDrift(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewSchemaDriftRepository(db)
+	drift := &SchemaDrift{Platform: "hackerone", Payload: "programs", Field: "data[].attributes.handle", Change: "missing"}
+
+	mock.ExpectExec("INSERT INTO platform_schema_drift .* ON CONFLICT \\(platform, payload, field, change\\) DO UPDATE").
+		WithArgs("hackerone", "programs", "data[].attributes.handle", "missing").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.RecordSchemaDrift(context.Background(), drift))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSchemaDriftRepository_GetRecentSchemaDrift(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewSchemaDriftRepository(db)
+	since := time.Now().Add(-7 * 24 * time.Hour)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM platform_schema_drift").
+		WithArgs(since, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"platform", "payload", "field", "change", "first_seen_at", "last_seen_at", "scans"}).
+			AddRow("bugcrowd", "targets", "targets[].category", "added", now, now, 3))
+
+	drifts, err := repo.GetRecentSchemaDrift(context.Background(), since, 10)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, "targets[].category", drifts[0].Field)
+	assert.Equal(t, 3, drifts[0].Scans)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
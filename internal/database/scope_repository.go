@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ScopeRepository handles the recorded scope of programs, which lets
+// hostnames found outside a scan be checked against it
+type ScopeRepository struct {
+	*Repository
+}
+
+// NewScopeRepository creates a new scope repository
+func NewScopeRepository(db *sqlx.DB) *ScopeRepository {
+	return &ScopeRepository{Repository: NewRepository(db)}
+}
+
+// GetScopeTargets retrieves the in-scope primary assets of active programs
+// with a recorded scope snapshot, leaving out ignored and quarantined assets
+func (r *ScopeRepository) GetScopeTargets(ctx context.Context) ([]*ScopeTarget, error) {
+	var targets []*ScopeTarget
+	query := `
+		SELECT a.program_id, p.name AS program_name, p.program_url, a.url
+		FROM assets a
+		JOIN programs p ON p.id = a.program_id
+		WHERE a.source = 'primary' AND NOT a.ignored AND a.status <> $1
+			AND p.is_active AND EXISTS (SELECT 1 FROM program_scopes s WHERE s.program_id = p.id)
+		ORDER BY p.name, a.url
+	`
+
+	if err := r.db.SelectContext(ctx, &targets, query, AssetStatusQuarantined); err != nil {
+		return nil, fmt.Errorf("failed to get scope targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// GetLatestScope retrieves the latest scope snapshot of a program, or nil
+// when none was recorded yet
+func (r *ScopeRepository) GetLatestScope(ctx context.Context, programID uuid.UUID) (*ProgramScope, error) {
+	var scope ProgramScope
+	query := `SELECT * FROM program_scopes WHERE program_id = $1 ORDER BY recorded_at DESC LIMIT 1`
+
+	err := r.db.GetContext(ctx, &scope, query, programID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest scope: %w", err)
+	}
+
+	return &scope, nil
+}
+
+// GetLatestScopes retrieves the latest scope snapshot of each of programs;
+// programs without one are left out
+func (r *ScopeRepository) GetLatestScopes(ctx context.Context, programIDs []uuid.UUID) ([]*ProgramScope, error) {
+	var scopes []*ProgramScope
+	query := `
+		SELECT DISTINCT ON (program_id) * FROM program_scopes
+		WHERE program_id = ANY($1)
+		ORDER BY program_id, recorded_at DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &scopes, query, pq.Array(programIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get latest scopes: %w", err)
+	}
+
+	return scopes, nil
+}
+
+// CreateScope records a scope snapshot of a program
+func (r *ScopeRepository) CreateScope(ctx context.Context, scope *ProgramScope) error {
+	query := `
+		INSERT INTO program_scopes (program_id, scan_id, content_hash, in_scope, out_of_scope, recorded_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, recorded_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, scope.ProgramID, scope.ScanID, scope.ContentHash, scope.InScope, scope.OutOfScope).
+		Scan(&scope.ID, &scope.RecordedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create scope snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetScopeHistory retrieves the scope snapshots of a program, newest first
+func (r *ScopeRepository) GetScopeHistory(ctx context.Context, programID uuid.UUID, limit int) ([]*ProgramScope, error) {
+	var scopes []*ProgramScope
+	query := `SELECT * FROM program_scopes WHERE program_id = $1 ORDER BY recorded_at DESC LIMIT $2`
+
+	if err := r.db.SelectContext(ctx, &scopes, query, programID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get scope history: %w", err)
+	}
+
+	return scopes, nil
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeRepository_GetScopeTargets(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScopeRepository(db)
+	programID := uuid.New()
+
+	mock.ExpectQuery("SELECT a.program_id, p.name AS program_name, p.program_url, a.url FROM assets a").
+		WithArgs(AssetStatusQuarantined).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "program_name", "program_url", "url"}).
+			AddRow(programID, "Acme", "https://hackerone.com/acme", "*.acme.com"))
+
+	targets, err := repo.GetScopeTargets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*ScopeTarget{{ProgramID: programID, ProgramName: "Acme", ProgramURL: "https://hackerone.com/acme", URL: "*.acme.com"}}, targets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScopeRepository_GetLatestScope(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScopeRepository(db)
+	programID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM program_scopes WHERE program_id = \\$1 ORDER BY recorded_at DESC LIMIT 1").
+		WithArgs(programID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "scan_id", "content_hash", "in_scope", "out_of_scope", "recorded_at"}).
+			AddRow(uuid.New(), programID, nil, "abc", "{*.acme.com,acme.com}", "{}", now))
+
+	scope, err := repo.GetLatestScope(context.Background(), programID)
+	require.NoError(t, err)
+	require.NotNil(t, scope)
+	assert.Equal(t, pq.StringArray{"*.acme.com", "acme.com"}, scope.InScope)
+	assert.Nil(t, scope.ScanID)
+
+	mock.ExpectQuery("SELECT \\* FROM program_scopes").WithArgs(programID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	scope, err = repo.GetLatestScope(context.Background(), programID)
+	require.NoError(t, err)
+	assert.Nil(t, scope, "no snapshot recorded yet")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScopeRepository_GetLatestScopes(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScopeRepository(db)
+	programIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectQuery("SELECT DISTINCT ON \\(program_id\\) \\* FROM program_scopes\\s+WHERE program_id = ANY\\(\\$1\\)\\s+ORDER BY program_id, recorded_at DESC").
+		WithArgs(pq.Array(programIDs)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "scan_id", "content_hash", "in_scope", "out_of_scope", "recorded_at"}).
+			AddRow(uuid.New(), programIDs[0], nil, "abc", "{*.acme.com}", "{\"url https://admin.acme.com\"}", time.Now()))
+
+	scopes, err := repo.GetLatestScopes(context.Background(), programIDs)
+	require.NoError(t, err)
+	require.Len(t, scopes, 1, "programs without a snapshot are left out")
+	assert.Equal(t, programIDs[0], scopes[0].ProgramID)
+	assert.Equal(t, pq.StringArray{"url https://admin.acme.com"}, scopes[0].OutOfScope)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScopeRepository_CreateScope(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScopeRepository(db)
+	scanID := uuid.New()
+	scope := &ProgramScope{
+		ProgramID:   uuid.New(),
+		ScanID:      &scanID,
+		ContentHash: "abc",
+		InScope:     pq.StringArray{"*.acme.com"},
+		OutOfScope:  pq.StringArray{},
+	}
+	id, now := uuid.New(), time.Now()
+
+	mock.ExpectQuery("INSERT INTO program_scopes").
+		WithArgs(scope.ProgramID, scope.ScanID, "abc", scope.InScope, scope.OutOfScope).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recorded_at"}).AddRow(id, now))
+
+	require.NoError(t, repo.CreateScope(context.Background(), scope))
+	assert.Equal(t, id, scope.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_GetExistingHostKeys(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	programID := uuid.New()
+	hostKeys := []string{"www.acme.com", "new.acme.com"}
+
+	mock.ExpectQuery("SELECT host_key FROM assets WHERE program_id = \\$1 AND host_key = ANY\\(\\$2\\)").
+		WithArgs(programID, pq.Array(hostKeys)).
+		WillReturnRows(sqlmock.NewRows([]string{"host_key"}).AddRow("www.acme.com"))
+
+	existing, err := repo.GetExistingHostKeys(context.Background(), programID, hostKeys)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"www.acme.com": true}, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ScoreRepository handles asset score database operations
+type ScoreRepository struct {
+	*Repository
+}
+
+// NewScoreRepository creates a new score repository
+func NewScoreRepository(db *sqlx.DB) *ScoreRepository {
+	return &ScoreRepository{Repository: NewRepository(db)}
+}
+
+// GetAssetScoreInputs retrieves what up to limit assets are scored on, in ID
+// order starting after afterID, optionally only the assets of one program.
+// Pass uuid.Nil to start from the first asset.
+func (r *ScoreRepository) GetAssetScoreInputs(ctx context.Context, programID *uuid.UUID, afterID uuid.UUID, limit int) ([]*AssetScoreInput, error) {
+	var inputs []*AssetScoreInput
+	query := `
+		SELECT a.id, a.program_url, a.url, h.liveness, a.ignored, a.created_at, a.score, a.score_model,
+			COALESCE((SELECT r.status_code FROM asset_responses r WHERE r.host_id = a.host_id ORDER BY r.created_at DESC LIMIT 1), 0) AS status_code,
+			COALESCE((SELECT array_agg(t.tag) FROM asset_tags t WHERE t.asset_id = a.id), '{}') AS tags,
+			(SELECT COUNT(*) FROM tls_findings f WHERE f.asset_id = a.id AND f.resolved_at IS NULL) AS tls_findings,
+			(SELECT COUNT(DISTINCT m.rule_name) FROM rule_matches m WHERE m.asset_id = a.id) AS rule_matches,
+			(SELECT COUNT(*) FROM api_schemas s WHERE s.asset_id = a.id) AS api_schemas
+		FROM assets a
+		JOIN hosts h ON h.id = a.host_id
+		WHERE a.id > $1 AND ($2::uuid IS NULL OR a.program_id = $2)
+		ORDER BY a.id
+		LIMIT $3
+	`
+
+	err := r.db.SelectContext(ctx, &inputs, query, afterID, programID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset score inputs: %w", err)
+	}
+
+	return inputs, nil
+}
+
+// UpdateAssetScores stores computed scores with the fingerprint of the model
+// they were computed with. The assets' updated_at is left alone, so
+// rescoring does not make assets look changed to sync.
+func (r *ScoreRepository) UpdateAssetScores(ctx context.Context, scores []AssetScore, model string) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(scores))
+	values := make([]float64, len(scores))
+	for i, score := range scores {
+		ids[i] = score.AssetID.String()
+		values[i] = score.Score
+	}
+
+	query := `
+		UPDATE assets a SET score = s.score, score_model = $3, scored_at = NOW()
+		FROM unnest($1::uuid[], $2::double precision[]) AS s(id, score)
+		WHERE a.id = s.id
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(ids), pq.Array(values), model); err != nil {
+		return fmt.Errorf("failed to update asset scores: %w", err)
+	}
+
+	return nil
+}
+
+// GetTopScoredAssets retrieves the limit highest scored assets that are not
+// ignored, optionally only those of one program
+func (r *ScoreRepository) GetTopScoredAssets(ctx context.Context, programID *uuid.UUID, limit int) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + `
+		WHERE NOT a.ignored AND ($1::uuid IS NULL OR a.program_id = $1)
+		ORDER BY a.score DESC, a.url
+		LIMIT $2
+	`
+
+	err := r.db.SelectContext(ctx, &assets, query, programID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top scored assets: %w", err)
+	}
+
+	return assets, nil
+}
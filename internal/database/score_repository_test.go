@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreRepository_GetAssetScoreInputs(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScoreRepository(db)
+	programID, assetID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery("SELECT a.id, a.program_url, a.url, h.liveness").
+		WithArgs(uuid.Nil, &programID, 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_url", "url", "liveness", "ignored", "created_at", "score", "score_model",
+			"status_code", "tags", "tls_findings", "rule_matches", "api_schemas"}).
+			AddRow(assetID, "https://hackerone.com/acme", "https://admin.acme.com", "live", false, time.Now(), 0.0, "",
+				401, "{admin-panel,grafana}", 1, 2, 0))
+
+	inputs, err := repo.GetAssetScoreInputs(context.Background(), &programID, uuid.Nil, 500)
+	require.NoError(t, err)
+	require.Len(t, inputs, 1)
+	assert.Equal(t, assetID, inputs[0].AssetID)
+	assert.Equal(t, 401, inputs[0].StatusCode)
+	assert.Equal(t, pq.StringArray{"admin-panel", "grafana"}, inputs[0].Tags)
+	assert.Equal(t, 2, inputs[0].RuleMatches)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScoreRepository_UpdateAssetScores(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScoreRepository(db)
+	first, second := uuid.New(), uuid.New()
+
+	mock.ExpectExec("UPDATE assets a SET score = s.score, score_model = \\$3").
+		WithArgs(pq.Array([]string{first.String(), second.String()}), pq.Array([]float64{25, 3.5}), "abc123").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := repo.UpdateAssetScores(context.Background(), []AssetScore{{AssetID: first, Score: 25}, {AssetID: second, Score: 3.5}}, "abc123")
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateAssetScores(context.Background(), nil, "abc123"), "nothing to update runs no query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProgramSourceCount is the number of a program's assets first found by a
+// discovery source
+type ProgramSourceCount struct {
+	ProgramID uuid.UUID `db:"program_id"`
+	Source    string    `db:"source"`
+	Assets    int       `db:"assets"`
+}
+
+// ProgramScanOutcomes counts a program's scans by how they ended
+type ProgramScanOutcomes struct {
+	ProgramID uuid.UUID `db:"program_id"`
+	Scans     int       `db:"scans"`
+	Completed int       `db:"completed"`
+	Failed    int       `db:"failed"`
+	TimedOut  int       `db:"timed_out"`
+}
+
+// ProgramDailyAssets is the number of assets a program gained on a day
+type ProgramDailyAssets struct {
+	ProgramID uuid.UUID `db:"program_id"`
+	Day       time.Time `db:"day"`
+	Assets    int       `db:"assets"`
+}
+
+// GetProgramSourceCounts gets the asset counts of each program grouped by the
+// discovery source that first found them
+func (r *AssetRepository) GetProgramSourceCounts(ctx context.Context) ([]*ProgramSourceCount, error) {
+	var counts []*ProgramSourceCount
+	query := `
+		SELECT program_id, COALESCE(NULLIF(first_source, ''), source) AS source, COUNT(*) AS assets
+		FROM assets
+		GROUP BY 1, 2
+	`
+
+	if err := r.db.SelectContext(ctx, &counts, query); err != nil {
+		return nil, fmt.Errorf("failed to get program source counts: %w", err)
+	}
+	return counts, nil
+}
+
+// GetProgramDailyAssets gets how many assets each program gained per day
+// since a time, oldest day first
+func (r *AssetRepository) GetProgramDailyAssets(ctx context.Context, since time.Time) ([]*ProgramDailyAssets, error) {
+	var counts []*ProgramDailyAssets
+	query := `
+		SELECT program_id, date_trunc('day', created_at) AS day, COUNT(*) AS assets
+		FROM assets
+		WHERE created_at >= $1
+		GROUP BY 1, 2
+		ORDER BY 2
+	`
+
+	if err := r.db.SelectContext(ctx, &counts, query, since); err != nil {
+		return nil, fmt.Errorf("failed to get program daily assets: %w", err)
+	}
+	return counts, nil
+}
+
+// GetProgramScanOutcomes counts the scans each program started since a time
+// by how they ended
+func (r *ScanRepository) GetProgramScanOutcomes(ctx context.Context, since time.Time) ([]*ProgramScanOutcomes, error) {
+	var outcomes []*ProgramScanOutcomes
+	query := `
+		SELECT program_id, COUNT(*) AS scans,
+		       COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+		       COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+		       COUNT(*) FILTER (WHERE status = 'timed_out') AS timed_out
+		FROM scans
+		WHERE started_at >= $1
+		GROUP BY program_id
+	`
+
+	if err := r.db.SelectContext(ctx, &outcomes, query, since); err != nil {
+		return nil, fmt.Errorf("failed to get program scan outcomes: %w", err)
+	}
+	return outcomes, nil
+}
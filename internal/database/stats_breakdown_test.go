@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetRepository_GetProgramSourceCounts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	programID := uuid.New()
+
+	mock.ExpectQuery("SELECT program_id, COALESCE\\(NULLIF\\(first_source, ''\\), source\\) AS source, COUNT\\(\\*\\) AS assets\\s+FROM assets").
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "source", "assets"}).
+			AddRow(programID, "chaosdb", 40).
+			AddRow(programID, "crtsh", 2))
+
+	counts, err := repo.GetProgramSourceCounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, &ProgramSourceCount{ProgramID: programID, Source: "crtsh", Assets: 2}, counts[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetRepository_GetProgramDailyAssets(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewAssetRepository(db)
+	programID := uuid.New()
+	since := time.Date(2026, 9, 17, 0, 0, 0, 0, time.UTC)
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT program_id, date_trunc\\('day', created_at\\) AS day").WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "day", "assets"}).AddRow(programID, day, 7))
+
+	counts, err := repo.GetProgramDailyAssets(context.Background(), since)
+	require.NoError(t, err)
+	assert.Equal(t, []*ProgramDailyAssets{{ProgramID: programID, Day: day, Assets: 7}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanRepository_GetProgramScanOutcomes(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewScanRepository(db)
+	programID := uuid.New()
+	since := time.Date(2026, 9, 17, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT program_id, COUNT\\(\\*\\) AS scans").WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"program_id", "scans", "completed", "failed", "timed_out"}).
+			AddRow(programID, 10, 7, 1, 2))
+
+	outcomes, err := repo.GetProgramScanOutcomes(context.Background(), since)
+	require.NoError(t, err)
+	assert.Equal(t, []*ProgramScanOutcomes{{ProgramID: programID, Scans: 10, Completed: 7, Failed: 1, TimedOut: 2}}, outcomes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,253 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// SyncRepository handles edge-to-central sync database operations
+type SyncRepository struct {
+	*Repository
+}
+
+// NewSyncRepository creates a new sync repository
+func NewSyncRepository(db *sqlx.DB) *SyncRepository {
+	return &SyncRepository{Repository: NewRepository(db)}
+}
+
+// GetSyncCursor returns the time of the last successful push to a server
+func (r *SyncRepository) GetSyncCursor(ctx context.Context, serverURL string) (time.Time, error) {
+	var lastSyncedAt time.Time
+	query := `SELECT last_synced_at FROM sync_state WHERE server_url = $1`
+
+	err := r.db.GetContext(ctx, &lastSyncedAt, query, serverURL)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get sync cursor: %w", err)
+	}
+
+	return lastSyncedAt, nil
+}
+
+// UpdateSyncCursor records the time of a successful push to a server
+func (r *SyncRepository) UpdateSyncCursor(ctx context.Context, serverURL string, syncedAt time.Time) error {
+	query := `
+		INSERT INTO sync_state (server_url, last_synced_at, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (server_url) DO UPDATE SET
+			last_synced_at = EXCLUDED.last_synced_at,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, serverURL, syncedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update sync cursor: %w", err)
+	}
+
+	return nil
+}
+
+// GetProgramsChangedSince retrieves programs that changed, or whose assets changed, after the given time
+func (r *SyncRepository) GetProgramsChangedSince(ctx context.Context, since time.Time) ([]*Program, error) {
+	var programs []*Program
+	query := `
+		SELECT p.* FROM programs p
+		WHERE p.updated_at > $1
+		   OR EXISTS (SELECT 1 FROM assets a WHERE a.program_id = p.id AND a.updated_at > $1)
+		ORDER BY p.updated_at
+	`
+
+	err := r.db.SelectContext(ctx, &programs, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed programs: %w", err)
+	}
+
+	return programs, nil
+}
+
+// GetAssetsChangedSince retrieves a program's assets that changed after the given time
+func (r *SyncRepository) GetAssetsChangedSince(ctx context.Context, programID uuid.UUID, since time.Time) ([]*Asset, error) {
+	var assets []*Asset
+	query := selectAssets + ` WHERE a.program_id = $1 AND a.updated_at > $2 ORDER BY a.updated_at`
+
+	err := r.db.SelectContext(ctx, &assets, query, programID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed assets: %w", err)
+	}
+
+	return assets, nil
+}
+
+// ApplySyncedProgram merges a program and its assets pushed by an edge agent.
+// Conflicts are resolved last-write-wins: programs by last_updated and assets
+// by the time the edge observed them, so a stale edge can never overwrite
+// newer central data. An asset last written on the server itself is compared
+// on its updated_at.
+func (r *SyncRepository) ApplySyncedProgram(ctx context.Context, agentID string, program *Program, assets []*Asset) (*SyncApplyResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Track if we've committed the transaction
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	result := &SyncApplyResult{}
+
+	programQuery := `
+		INSERT INTO programs (id, name, platform, platform_id, url, program_url, is_active, last_updated, created_at, updated_at, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW(), $9)
+		ON CONFLICT (platform, program_url) DO UPDATE SET
+			name = EXCLUDED.name,
+			platform_id = EXCLUDED.platform_id,
+			url = EXCLUDED.url,
+			is_active = EXCLUDED.is_active,
+			visibility = EXCLUDED.visibility,
+			last_updated = EXCLUDED.last_updated
+		WHERE programs.last_updated < EXCLUDED.last_updated
+	`
+
+	// Edge agents older than program visibility send none
+	visibility := program.Visibility
+	if visibility == "" {
+		visibility = ProgramVisibilityPublic
+	}
+
+	res, err := tx.ExecContext(ctx, programQuery, uuid.New(), program.Name, program.Platform, program.PlatformID, program.URL,
+		program.ProgramURL, program.IsActive, program.LastUpdated, visibility)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert program %s: %w", program.ProgramURL, err)
+	}
+	if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected > 0 {
+		result.ProgramUpdated = true
+	}
+
+	err = tx.GetContext(ctx, &result.ProgramID,
+		`SELECT id FROM programs WHERE platform = $1 AND program_url = $2`, program.Platform, program.ProgramURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program id for %s: %w", program.ProgramURL, err)
+	}
+
+	assetQuery := `
+		INSERT INTO assets (id, program_id, program_url, url, host_key, domain, subdomain, status, source, first_source, created_at, updated_at, synced_observed_at, provenance, data_terms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12, $13, $14)
+		ON CONFLICT (program_id, host_key) DO UPDATE SET
+			program_url = EXCLUDED.program_url,
+			domain = EXCLUDED.domain,
+			subdomain = EXCLUDED.subdomain,
+			status = EXCLUDED.status,
+			source = EXCLUDED.source,
+			synced_observed_at = EXCLUDED.synced_observed_at,
+			provenance = (
+				SELECT array_agg(source ORDER BY first_ord)
+				FROM (
+					SELECT source, MIN(ord) AS first_ord
+					FROM unnest(assets.provenance || EXCLUDED.provenance) WITH ORDINALITY AS p(source, ord)
+					GROUP BY source
+				) sources
+			),
+			data_terms = ARRAY(SELECT DISTINCT term FROM unnest(assets.data_terms || EXCLUDED.data_terms) AS term ORDER BY term)
+		WHERE COALESCE(assets.synced_observed_at, assets.updated_at) < EXCLUDED.synced_observed_at
+		RETURNING id, (xmax = 0) AS inserted
+	`
+
+	// The host takes the address and liveness the edge observed with the
+	// asset, when the asset itself was not skipped as stale
+	hostQuery := `
+		UPDATE hosts SET ip = $2, ipv6 = $3, ipv4_reachable = $4, ipv6_reachable = $5, liveness = $6
+		WHERE host_key = $1
+	`
+
+	sightingQuery := `
+		INSERT INTO asset_sightings (asset_id, agent_id, first_seen, last_seen)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (asset_id, agent_id) DO UPDATE SET
+			last_seen = GREATEST(asset_sightings.last_seen, EXCLUDED.last_seen)
+	`
+
+	variantQuery := `
+		INSERT INTO asset_scheme_variants (asset_id, scheme, url, first_seen, last_seen)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (asset_id, scheme) DO UPDATE SET
+			last_seen = GREATEST(asset_scheme_variants.last_seen, EXCLUDED.last_seen)
+	`
+
+	for _, asset := range assets {
+		hostKey := AssetHostKey(asset.URL)
+		provenance, dataTerms := pq.StringArray(asset.Sources()), asset.DataTerms
+		if provenance == nil {
+			provenance = pq.StringArray{}
+		}
+		if dataTerms == nil {
+			dataTerms = pq.StringArray{}
+		}
+
+		var row struct {
+			ID       uuid.UUID `db:"id"`
+			Inserted bool      `db:"inserted"`
+		}
+
+		err := tx.GetContext(ctx, &row, assetQuery, uuid.New(), result.ProgramID, program.ProgramURL, asset.URL, hostKey,
+			asset.Domain, asset.Subdomain, asset.Status, asset.Source, asset.FirstSource, asset.CreatedAt, asset.UpdatedAt,
+			provenance, dataTerms)
+		skipped := false
+		switch {
+		case err == sql.ErrNoRows:
+			// The central copy is newer; keep it but still record the sighting
+			err = tx.GetContext(ctx, &row.ID,
+				`SELECT id FROM assets WHERE program_id = $1 AND host_key = $2`, result.ProgramID, hostKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get asset id for %s: %w", asset.URL, err)
+			}
+			skipped = true
+			result.AssetsSkipped++
+		case err != nil:
+			return nil, fmt.Errorf("failed to upsert asset %s: %w", asset.URL, err)
+		case row.Inserted:
+			result.AssetsCreated++
+		default:
+			result.AssetsUpdated++
+		}
+
+		if !skipped {
+			_, err := tx.ExecContext(ctx, hostQuery, hostKey, asset.IP, asset.IPv6, asset.IPv4Reachable, asset.IPv6Reachable, asset.Liveness)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update host of %s: %w", asset.URL, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, sightingQuery, row.ID, agentID, asset.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to record sighting for %s: %w", asset.URL, err)
+		}
+
+		if scheme, _, ok := strings.Cut(asset.URL, "://"); ok {
+			if _, err := tx.ExecContext(ctx, variantQuery, row.ID, strings.ToLower(scheme), asset.URL, asset.UpdatedAt); err != nil {
+				return nil, fmt.Errorf("failed to record scheme variant for %s: %w", asset.URL, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncRepository_GetSyncCursor_NoRows(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewSyncRepository(db)
+
+	mock.ExpectQuery("SELECT last_synced_at FROM sync_state").
+		WithArgs("https://central.example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	cursor, err := repo.GetSyncCursor(context.Background(), "https://central.example.com")
+	assert.NoError(t, err)
+	assert.True(t, cursor.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncRepository_ApplySyncedProgram(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewSyncRepository(db)
+	programID := uuid.New()
+	existingID := uuid.New()
+	now := time.Now()
+
+	program := &Program{Name: "Example", Platform: "hackerone", ProgramURL: "https://hackerone.com/example", IsActive: true, LastUpdated: now}
+	assets := []*Asset{
+		{URL: "https://new.example.com", Domain: "example.com", IP: "203.0.113.7", Liveness: "live", Status: "active", Source: "secondary", UpdatedAt: now},
+		{URL: "https://stale.example.com", Domain: "example.com", Status: "active", Source: "secondary", UpdatedAt: now.Add(-time.Hour)},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO programs").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id FROM programs").
+		WithArgs("hackerone", "https://hackerone.com/example").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(programID))
+
+	// New asset is inserted, and its host takes what the edge observed
+	mock.ExpectQuery("INSERT INTO assets").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inserted"}).AddRow(uuid.New(), true))
+	mock.ExpectExec("UPDATE hosts SET").
+		WithArgs("new.example.com", "203.0.113.7", "", nil, nil, "live").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_sightings").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_scheme_variants").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Stale asset loses the conflict and is only recorded as a sighting,
+	// leaving its host alone
+	mock.ExpectQuery("INSERT INTO assets").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id FROM assets").
+		WithArgs(programID, "stale.example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(existingID))
+	mock.ExpectExec("INSERT INTO asset_sightings").
+		WithArgs(existingID, "edge-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_scheme_variants").
+		WithArgs(existingID, "https", "https://stale.example.com", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.ApplySyncedProgram(context.Background(), "edge-1", program, assets)
+	require.NoError(t, err)
+	assert.Equal(t, programID, result.ProgramID)
+	assert.True(t, result.ProgramUpdated)
+	assert.Equal(t, 1, result.AssetsCreated)
+	assert.Equal(t, 1, result.AssetsSkipped)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncRepository_ApplySyncedProgram_EdgesOutOfOrder(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewSyncRepository(db)
+	programID, assetID := uuid.New(), uuid.New()
+	observed := time.Now().Add(-time.Hour)
+
+	program := &Program{Name: "Example", Platform: "hackerone", ProgramURL: "https://hackerone.com/example", IsActive: true, LastUpdated: observed}
+	newer := &Asset{URL: "https://api.example.com", Domain: "example.com", Liveness: "live", Status: "active", Source: "secondary", UpdatedAt: observed.Add(10 * time.Minute)}
+	older := &Asset{URL: "https://api.example.com", Domain: "example.com", Liveness: "refused", Status: "inactive", Source: "secondary", UpdatedAt: observed}
+
+	// The guard compares the edge's observation time, which the upsert stores,
+	// and not updated_at, which the server stamps with its own clock
+	upsert := `INSERT INTO assets .+ synced_observed_at = EXCLUDED.synced_observed_at,.+WHERE COALESCE\(assets.synced_observed_at, assets.updated_at\) < EXCLUDED.synced_observed_at`
+	upsertArgs := func(asset *Asset) []driver.Value {
+		args := make([]driver.Value, 14)
+		for i := range args {
+			args[i] = sqlmock.AnyArg()
+		}
+		args[11] = asset.UpdatedAt
+		return args
+	}
+	expectProgram := func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO programs").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT id FROM programs").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(programID))
+	}
+
+	// The edge that observed the asset last pushes first and updates it
+	expectProgram()
+	mock.ExpectQuery(upsert).WithArgs(upsertArgs(newer)...).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inserted"}).AddRow(assetID, false))
+	mock.ExpectExec("UPDATE hosts SET").WithArgs("api.example.com", "", "", nil, nil, "live").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_sightings").WithArgs(assetID, "edge-2", newer.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_scheme_variants").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// The edge that observed it earlier pushes after it and loses, however
+	// late the server applies its push, and does not touch the host
+	expectProgram()
+	mock.ExpectQuery(upsert).WithArgs(upsertArgs(older)...).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id FROM assets").WithArgs(programID, "api.example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(assetID))
+	mock.ExpectExec("INSERT INTO asset_sightings").WithArgs(assetID, "edge-1", older.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_scheme_variants").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.ApplySyncedProgram(context.Background(), "edge-2", program, []*Asset{newer})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.AssetsUpdated)
+
+	result, err = repo.ApplySyncedProgram(context.Background(), "edge-1", program, []*Asset{older})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.AssetsUpdated)
+	assert.Equal(t, 1, result.AssetsSkipped)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
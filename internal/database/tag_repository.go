@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// IgnoredTag is the tag hunters mark assets as ignored with. TagAsset,
+// RemoveAssetTags and UpdateAssetsByQuery keep the asset's ignored flag, which
+// leaves it out of sweeps and reports, in step with it.
+const IgnoredTag = "ignored"
+
+// TagRepository handles asset tag and rule match database operations
+type TagRepository struct {
+	*Repository
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *sqlx.DB) *TagRepository {
+	return &TagRepository{Repository: NewRepository(db)}
+}
+
+// AddAssetTags attaches tags to an asset, keeping the original source of tags it already has
+func (r *TagRepository) AddAssetTags(ctx context.Context, assetID uuid.UUID, tags []string, source string) error {
+	query := `
+		INSERT INTO asset_tags (asset_id, tag, source, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (asset_id, tag) DO NOTHING
+	`
+
+	for _, tag := range tags {
+		if _, err := r.db.ExecContext(ctx, query, assetID, tag, source); err != nil {
+			return fmt.Errorf("failed to add asset tag %s: %w", tag, err)
+		}
+	}
+
+	return nil
+}
+
+// GetAssetTags retrieves the tags attached to an asset
+func (r *TagRepository) GetAssetTags(ctx context.Context, assetID uuid.UUID) ([]*AssetTag, error) {
+	var tags []*AssetTag
+	query := `SELECT * FROM asset_tags WHERE asset_id = $1 ORDER BY tag`
+
+	err := r.db.SelectContext(ctx, &tags, query, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// TagAsset attaches a tag to an asset, or replaces the note of the tag it
+// already has when a note is given; the original source is kept. It reports
+// whether the tag is new.
+func (r *TagRepository) TagAsset(ctx context.Context, assetID uuid.UUID, tag, source, note string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	// xmax is 0 for a row the statement inserted rather than updated
+	var added bool
+	err = tx.GetContext(ctx, &added, `
+		INSERT INTO asset_tags (asset_id, tag, source, note, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (asset_id, tag) DO UPDATE SET
+			note = CASE WHEN EXCLUDED.note <> '' THEN EXCLUDED.note ELSE asset_tags.note END
+		RETURNING xmax = 0
+	`, assetID, tag, source, note)
+	if err != nil {
+		return false, fmt.Errorf("failed to tag asset: %w", err)
+	}
+
+	if tag == IgnoredTag {
+		if _, err := tx.ExecContext(ctx, `UPDATE assets SET ignored = TRUE, updated_at = NOW() WHERE id = $1`, assetID); err != nil {
+			return false, fmt.Errorf("failed to ignore asset: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	return added, nil
+}
+
+// RemoveAssetTags detaches tags from an asset and returns how many it had
+func (r *TagRepository) RemoveAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	var removed []string
+	err = tx.SelectContext(ctx, &removed, `
+		DELETE FROM asset_tags WHERE asset_id = $1 AND tag = ANY($2)
+		RETURNING tag
+	`, assetID, pq.Array(tags))
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove asset tags: %w", err)
+	}
+
+	for _, tag := range removed {
+		if tag != IgnoredTag {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE assets SET ignored = FALSE, updated_at = NOW() WHERE id = $1`, assetID); err != nil {
+			return 0, fmt.Errorf("failed to unignore asset: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	return int64(len(removed)), nil
+}
+
+// GetTaggedAssets retrieves the assets carrying a tag, ordered by URL
+func (r *TagRepository) GetTaggedAssets(ctx context.Context, tag string) ([]*TaggedAsset, error) {
+	var tagged []*TaggedAsset
+	query := `
+		SELECT t.*, a.url, a.ignored
+		FROM asset_tags t JOIN assets a ON a.id = t.asset_id
+		WHERE t.tag = $1
+		ORDER BY a.url
+	`
+
+	err := r.db.SelectContext(ctx, &tagged, query, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tagged assets: %w", err)
+	}
+
+	return tagged, nil
+}
+
+// CreateRuleMatch records a triage rule match
+func (r *TagRepository) CreateRuleMatch(ctx context.Context, match *RuleMatch) error {
+	match.ID = uuid.New()
+
+	query := `
+		INSERT INTO rule_matches (id, asset_id, response_id, rule_name, tags, notify, enqueue, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, match.ID, match.AssetID, match.ResponseID, match.RuleName,
+		match.Tags, match.Notify, match.Enqueue).Scan(&match.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create rule match: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecentRuleMatches retrieves the most recent triage rule matches
+func (r *TagRepository) GetRecentRuleMatches(ctx context.Context, limit int) ([]*RuleMatch, error) {
+	var matches []*RuleMatch
+	query := `SELECT * FROM rule_matches ORDER BY created_at DESC LIMIT $1`
+
+	err := r.db.SelectContext(ctx, &matches, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule matches: %w", err)
+	}
+
+	return matches, nil
+}
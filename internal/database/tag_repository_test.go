@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagRepository_TagAsset(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+	assetID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO asset_tags .* ON CONFLICT \\(asset_id, tag\\) DO UPDATE SET").
+		WithArgs(assetID, "interesting", "manual", "admin panel behind SSO").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(false))
+	mock.ExpectCommit()
+
+	added, err := repo.TagAsset(context.Background(), assetID, "interesting", "manual", "admin panel behind SSO")
+	require.NoError(t, err)
+	assert.False(t, added, "an existing tag only gets the new note")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_TagAssetIgnored(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+	assetID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO asset_tags").
+		WithArgs(assetID, IgnoredTag, "manual", "").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(true))
+	mock.ExpectExec("UPDATE assets SET ignored = TRUE").
+		WithArgs(assetID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	added, err := repo.TagAsset(context.Background(), assetID, IgnoredTag, "manual", "")
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_TagAssetRollsBack(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+	assetID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO asset_tags").
+		WithArgs(assetID, IgnoredTag, "manual", "").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(true))
+	mock.ExpectExec("UPDATE assets SET ignored = TRUE").
+		WithArgs(assetID).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	_, err := repo.TagAsset(context.Background(), assetID, IgnoredTag, "manual", "")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_RemoveAssetTags(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+	assetID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("DELETE FROM asset_tags WHERE asset_id = \\$1 AND tag = ANY\\(\\$2\\)").
+		WithArgs(assetID, pq.Array([]string{"triaged", IgnoredTag, "missing"})).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}).AddRow("triaged").AddRow(IgnoredTag))
+	mock.ExpectExec("UPDATE assets SET ignored = FALSE").
+		WithArgs(assetID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	removed, err := repo.RemoveAssetTags(context.Background(), assetID, []string{"triaged", IgnoredTag, "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_GetTaggedAssets(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTagRepository(db)
+	assetID, now := uuid.New(), time.Now()
+
+	mock.ExpectQuery("SELECT t.\\*, a.url, a.ignored FROM asset_tags t JOIN assets a").
+		WithArgs("interesting").
+		WillReturnRows(sqlmock.NewRows([]string{"asset_id", "tag", "source", "note", "created_at", "url", "ignored"}).
+			AddRow(assetID, "interesting", "manual", "admin panel", now, "https://admin.example.com", false))
+
+	tagged, err := repo.GetTaggedAssets(context.Background(), "interesting")
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	assert.Equal(t, assetID, tagged[0].AssetID)
+	assert.Equal(t, "admin panel", tagged[0].Note)
+	assert.Equal(t, "https://admin.example.com", tagged[0].URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
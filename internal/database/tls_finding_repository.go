@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// TLSFindingRepository handles TLS finding database operations
+type TLSFindingRepository struct {
+	*Repository
+}
+
+// NewTLSFindingRepository creates a new TLS finding repository
+func NewTLSFindingRepository(db *sqlx.DB) *TLSFindingRepository {
+	return &TLSFindingRepository{Repository: NewRepository(db)}
+}
+
+// RecordTLSFindings stores the TLS findings of an asset's latest probe and
+// resolves its open findings that the probe no longer reports. It returns the
+// findings that were newly opened, including previously resolved ones that
+// came back.
+func (r *TLSFindingRepository) RecordTLSFindings(ctx context.Context, assetID uuid.UUID, findings []*TLSFinding) ([]*TLSFinding, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Track if we've committed the transaction
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	// A new or reopened finding gets first_seen = last_seen = NOW(), which
+	// tells it apart from one that was already open
+	upsertQuery := `
+		INSERT INTO tls_findings (id, asset_id, check_name, severity, detail, url, first_seen, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		ON CONFLICT (asset_id, check_name) DO UPDATE SET
+			severity = EXCLUDED.severity,
+			detail = EXCLUDED.detail,
+			url = EXCLUDED.url,
+			first_seen = CASE WHEN tls_findings.resolved_at IS NULL THEN tls_findings.first_seen ELSE NOW() END,
+			last_seen = NOW(),
+			resolved_at = NULL
+		RETURNING id, first_seen, last_seen
+	`
+
+	var opened []*TLSFinding
+	checks := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		finding.AssetID = assetID
+		err := tx.QueryRowxContext(ctx, upsertQuery, uuid.New(), assetID, finding.CheckName, finding.Severity,
+			finding.Detail, finding.URL).Scan(&finding.ID, &finding.FirstSeen, &finding.LastSeen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record TLS finding %s: %w", finding.CheckName, err)
+		}
+
+		checks = append(checks, finding.CheckName)
+		if finding.FirstSeen.Equal(finding.LastSeen) {
+			opened = append(opened, finding)
+		}
+	}
+
+	resolveQuery := `
+		UPDATE tls_findings SET resolved_at = NOW()
+		WHERE asset_id = $1 AND resolved_at IS NULL AND NOT (check_name = ANY($2))
+	`
+
+	if _, err := tx.ExecContext(ctx, resolveQuery, assetID, pq.Array(checks)); err != nil {
+		return nil, fmt.Errorf("failed to resolve TLS findings: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	committed = true
+	return opened, nil
+}
+
+// GetAssetTLSFindings retrieves an asset's open TLS findings
+func (r *TLSFindingRepository) GetAssetTLSFindings(ctx context.Context, assetID uuid.UUID) ([]*TLSFinding, error) {
+	var findings []*TLSFinding
+	query := `SELECT * FROM tls_findings WHERE asset_id = $1 AND resolved_at IS NULL ORDER BY severity, check_name`
+
+	err := r.db.SelectContext(ctx, &findings, query, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset TLS findings: %w", err)
+	}
+
+	return findings, nil
+}
+
+// GetOpenTLSFindingCounts gets the number of assets with each open TLS finding
+func (r *TLSFindingRepository) GetOpenTLSFindingCounts(ctx context.Context) ([]*TLSFindingCount, error) {
+	var counts []*TLSFindingCount
+	query := `
+		SELECT check_name, severity, COUNT(*) AS assets
+		FROM tls_findings
+		WHERE resolved_at IS NULL
+		GROUP BY check_name, severity
+		ORDER BY assets DESC
+	`
+
+	err := r.db.SelectContext(ctx, &counts, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TLS finding counts: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSFindingRepository_RecordTLSFindings(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTLSFindingRepository(db)
+	assetID := uuid.New()
+	now := time.Now()
+	earlier := now.Add(-24 * time.Hour)
+
+	findings := []*TLSFinding{
+		{CheckName: "expired-certificate", Severity: "medium", Detail: "certificate for example.com expired", URL: "https://example.com"},
+		{CheckName: "legacy-protocol", Severity: "low", Detail: "server negotiated TLS 1.0", URL: "https://example.com"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO tls_findings").
+		WithArgs(sqlmock.AnyArg(), assetID, "expired-certificate", "medium", findings[0].Detail, "https://example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "first_seen", "last_seen"}).AddRow(uuid.New(), now, now))
+	mock.ExpectQuery("INSERT INTO tls_findings").
+		WithArgs(sqlmock.AnyArg(), assetID, "legacy-protocol", "low", findings[1].Detail, "https://example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "first_seen", "last_seen"}).AddRow(uuid.New(), earlier, now))
+	mock.ExpectExec("UPDATE tls_findings SET resolved_at = NOW\\(\\)").
+		WithArgs(assetID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	opened, err := repo.RecordTLSFindings(context.Background(), assetID, findings)
+	require.NoError(t, err)
+	require.Len(t, opened, 1)
+	assert.Equal(t, "expired-certificate", opened[0].CheckName)
+	assert.Equal(t, assetID, opened[0].AssetID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTLSFindingRepository_RecordTLSFindings_ResolvesAll(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTLSFindingRepository(db)
+	assetID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE tls_findings SET resolved_at = NOW\\(\\)").
+		WithArgs(assetID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	opened, err := repo.RecordTLSFindings(context.Background(), assetID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, opened)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTLSFindingRepository_GetOpenTLSFindingCounts(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewTLSFindingRepository(db)
+
+	mock.ExpectQuery("SELECT check_name, severity, COUNT\\(\\*\\) AS assets FROM tls_findings WHERE resolved_at IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"check_name", "severity", "assets"}).
+			AddRow("self-signed-certificate", "low", 4).
+			AddRow("expired-certificate", "medium", 2))
+
+	counts, err := repo.GetOpenTLSFindingCounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, &TLSFindingCount{CheckName: "self-signed-certificate", Severity: "low", Assets: 4}, counts[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
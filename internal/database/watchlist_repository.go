@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WatchlistRepository handles watchlist database operations
+type WatchlistRepository struct {
+	*Repository
+}
+
+// NewWatchlistRepository creates a new watchlist repository
+func NewWatchlistRepository(db *sqlx.DB) *WatchlistRepository {
+	return &WatchlistRepository{Repository: NewRepository(db)}
+}
+
+// AddWatchlistEntry adds a hostname to the watchlist, or updates the program
+// and note of a hostname already on it. The entry's state is left as it was.
+func (r *WatchlistRepository) AddWatchlistEntry(ctx context.Context, entry *WatchlistEntry) error {
+	query := `
+		INSERT INTO watchlist (hostname, program_id, note, state, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (hostname) DO UPDATE SET
+			program_id = EXCLUDED.program_id,
+			note = EXCLUDED.note,
+			updated_at = NOW()
+		RETURNING id, state, status_code, ip, last_checked_at, state_changed_at, created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, query, entry.Hostname, entry.ProgramID, entry.Note, WatchStateDead).
+		Scan(&entry.ID, &entry.State, &entry.StatusCode, &entry.IP, &entry.LastCheckedAt, &entry.StateChangedAt, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add watchlist entry: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveWatchlistEntry removes a hostname from the watchlist. It reports
+// whether the hostname was on it.
+func (r *WatchlistRepository) RemoveWatchlistEntry(ctx context.Context, hostname string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM watchlist WHERE hostname = $1`, hostname)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove watchlist entry: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove watchlist entry: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// GetWatchlist retrieves every watched hostname in name order
+func (r *WatchlistRepository) GetWatchlist(ctx context.Context) ([]*WatchlistEntry, error) {
+	var entries []*WatchlistEntry
+	query := `SELECT * FROM watchlist ORDER BY hostname`
+
+	err := r.db.SelectContext(ctx, &entries, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+
+	return entries, nil
+}
+
+// UpdateWatchlistCheck stores the outcome of checking a watched hostname
+func (r *WatchlistRepository) UpdateWatchlistCheck(ctx context.Context, entry *WatchlistEntry) error {
+	query := `
+		UPDATE watchlist SET
+			state = $2,
+			status_code = $3,
+			ip = $4,
+			last_checked_at = $5,
+			state_changed_at = $6,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, entry.ID, entry.State, entry.StatusCode, entry.IP, entry.LastCheckedAt, entry.StateChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update watchlist check: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchlistRepository_AddWatchlistEntry(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewWatchlistRepository(db)
+	programID := uuid.New()
+	entry := &WatchlistEntry{Hostname: "admin.example.com", ProgramID: &programID, Note: "expected after launch"}
+	id, now := uuid.New(), time.Now()
+
+	mock.ExpectQuery("INSERT INTO watchlist").
+		WithArgs("admin.example.com", &programID, "expected after launch", WatchStateDead).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "state", "status_code", "ip", "last_checked_at", "state_changed_at", "created_at", "updated_at"}).
+			AddRow(id, WatchStateResolving, 0, "192.0.2.10", now, now, now, now))
+
+	require.NoError(t, repo.AddWatchlistEntry(context.Background(), entry))
+	assert.Equal(t, id, entry.ID)
+	assert.Equal(t, WatchStateResolving, entry.State, "re-adding keeps the state")
+	assert.Equal(t, "192.0.2.10", entry.IP)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWatchlistRepository_RemoveWatchlistEntry(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewWatchlistRepository(db)
+
+	mock.ExpectExec("DELETE FROM watchlist WHERE hostname = \\$1").
+		WithArgs("admin.example.com").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	removed, err := repo.RemoveWatchlistEntry(context.Background(), "admin.example.com")
+	require.NoError(t, err)
+	assert.False(t, removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWatchlistRepository_UpdateWatchlistCheck(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	repo := NewWatchlistRepository(db)
+	now := time.Now()
+	entry := &WatchlistEntry{ID: uuid.New(), State: WatchStateResponding, StatusCode: 200, IP: "192.0.2.10", LastCheckedAt: &now, StateChangedAt: &now}
+
+	mock.ExpectExec("UPDATE watchlist SET").
+		WithArgs(entry.ID, WatchStateResponding, 200, "192.0.2.10", &now, &now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.UpdateWatchlistCheck(context.Background(), entry))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
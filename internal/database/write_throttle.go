@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WriteThrottle is a soft rate limit on database writes. Large programs can
+// otherwise insert tens of thousands of rows in seconds and starve other
+// queries; callers wait on the throttle before each write, which pushes back
+// on the discovery pipeline instead of on Postgres.
+type WriteThrottle struct {
+	batchSize     int
+	rowsPerSecond float64
+
+	mu     sync.Mutex
+	next   time.Time     // when the budget is next free
+	waited time.Duration // total time callers have been held back
+}
+
+// NewWriteThrottle creates a write throttle. A batch size of 0 writes
+// everything in one batch and a rate of 0 disables the per-second budget.
+func NewWriteThrottle(batchSize, rowsPerSecond int) *WriteThrottle {
+	return &WriteThrottle{
+		batchSize:     batchSize,
+		rowsPerSecond: float64(rowsPerSecond),
+	}
+}
+
+// BatchSize returns the number of rows to write per batch, or 0 for no limit
+func (t *WriteThrottle) BatchSize() int {
+	if t == nil {
+		return 0
+	}
+	return t.batchSize
+}
+
+// Wait blocks until the per-second budget allows writing rows, or ctx is done.
+// Up to one second of unused budget may be spent in a burst.
+func (t *WriteThrottle) Wait(ctx context.Context, rows int) error {
+	if t == nil || t.rowsPerSecond <= 0 || rows <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	if earliest := now.Add(-time.Second); t.next.Before(earliest) {
+		t.next = earliest
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(time.Duration(float64(rows) / t.rowsPerSecond * float64(time.Second)))
+	if delay > 0 {
+		t.waited += delay
+	}
+	t.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Waited returns the total time writers have been held back
+func (t *WriteThrottle) Waited() time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.waited
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteThrottle_Disabled(t *testing.T) {
+	var nilThrottle *WriteThrottle
+	assert.NoError(t, nilThrottle.Wait(context.Background(), 1000))
+	assert.Equal(t, 0, nilThrottle.BatchSize())
+
+	throttle := NewWriteThrottle(500, 0)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, throttle.Wait(context.Background(), 1000))
+	}
+	assert.Equal(t, 500, throttle.BatchSize())
+	assert.Zero(t, throttle.Waited())
+}
+
+func TestWriteThrottle_Budget(t *testing.T) {
+	throttle := NewWriteThrottle(0, 10)
+	ctx := context.Background()
+
+	// One second of burst budget is available immediately
+	start := time.Now()
+	assert.NoError(t, throttle.Wait(ctx, 10))
+	assert.NoError(t, throttle.Wait(ctx, 10))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// The budget is spent, so the next write has to wait about a second
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, throttle.Wait(waitCtx, 1), context.DeadlineExceeded)
+	assert.Greater(t, throttle.Waited(), 500*time.Millisecond)
+}
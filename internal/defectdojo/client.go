@@ -0,0 +1,220 @@
+package defectdojo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+)
+
+// Client talks to the DefectDojo v2 API
+type Client struct {
+	httpClient *resty.Client
+	baseURL    string
+}
+
+// ClientConfig holds configuration for the DefectDojo client
+type ClientConfig struct {
+	URL           string // DefectDojo base URL, e.g. https://defectdojo.example.com
+	APIKey        string
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// NewClient creates a new DefectDojo client
+func NewClient(config *ClientConfig) *Client {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+
+	client.SetHeaders(map[string]string{
+		"Accept":        "application/json",
+		"Authorization": fmt.Sprintf("Token %s", config.APIKey),
+		"User-Agent":    version.UserAgent(),
+	})
+
+	return &Client{
+		httpClient: client,
+		baseURL:    strings.TrimRight(config.URL, "/") + "/api/v2",
+	}
+}
+
+// EnsureProductType returns the ID of the product type with the given name,
+// creating it when it does not exist
+func (c *Client) EnsureProductType(ctx context.Context, name string) (int, error) {
+	var page listResponse[ProductType]
+	if err := c.get(ctx, "/product_types/", map[string]string{"name": name}, &page); err != nil {
+		return 0, fmt.Errorf("failed to look up product type %q: %w", name, err)
+	}
+	for _, productType := range page.Results {
+		if strings.EqualFold(productType.Name, name) {
+			return productType.ID, nil
+		}
+	}
+
+	var created ProductType
+	if err := c.post(ctx, "/product_types/", ProductType{Name: name}, &created); err != nil {
+		return 0, fmt.Errorf("failed to create product type %q: %w", name, err)
+	}
+	return created.ID, nil
+}
+
+// EnsureProduct returns the product with the given name, creating it when it
+// does not exist. created reports whether it was created.
+func (c *Client) EnsureProduct(ctx context.Context, product *Product) (existing *Product, created bool, err error) {
+	var page listResponse[Product]
+	if err := c.get(ctx, "/products/", map[string]string{"name": product.Name}, &page); err != nil {
+		return nil, false, fmt.Errorf("failed to look up product %q: %w", product.Name, err)
+	}
+	for i := range page.Results {
+		if strings.EqualFold(page.Results[i].Name, product.Name) {
+			return &page.Results[i], false, nil
+		}
+	}
+
+	var result Product
+	if err := c.post(ctx, "/products/", product, &result); err != nil {
+		return nil, false, fmt.Errorf("failed to create product %q: %w", product.Name, err)
+	}
+	return &result, true, nil
+}
+
+// CreateEngagement creates an engagement in a product
+func (c *Client) CreateEngagement(ctx context.Context, engagement *Engagement) (*Engagement, error) {
+	var result Engagement
+	if err := c.post(ctx, "/engagements/", engagement, &result); err != nil {
+		return nil, fmt.Errorf("failed to create engagement %q: %w", engagement.Name, err)
+	}
+	return &result, nil
+}
+
+// CreateEndpoint adds an endpoint to a product. created is false when the
+// product already has the endpoint.
+func (c *Client) CreateEndpoint(ctx context.Context, endpoint *Endpoint) (created bool, err error) {
+	err = c.post(ctx, "/endpoints/", endpoint, nil)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest && strings.Contains(apiErr.Body, "already exists") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create endpoint %s: %w", endpoint.Host, err)
+	}
+	return true, nil
+}
+
+// ImportFindings imports a generic findings report into an engagement. Open
+// findings of the product that the report no longer contains are closed.
+func (c *Client) ImportFindings(ctx context.Context, engagementID int, scanDate time.Time, report *GenericReport) (*ImportResult, error) {
+	file, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal findings: %w", err)
+	}
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetMultipartFormData(map[string]string{
+			"scan_type":                        GenericScanType,
+			"engagement":                       strconv.Itoa(engagementID),
+			"scan_date":                        scanDate.Format(time.DateOnly),
+			"minimum_severity":                 SeverityInfo,
+			"active":                           "true",
+			"verified":                         "false",
+			"close_old_findings":               "true",
+			"close_old_findings_product_scope": "true",
+		}).
+		SetFileReader("file", "monitor-agent-findings.json", bytes.NewReader(file)).
+		Post(c.baseURL + "/import-scan/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to import findings: %w", err)
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, fmt.Errorf("failed to import findings: %w", err)
+	}
+
+	var result ImportResult
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal import response: %w", err)
+	}
+	return &result, nil
+}
+
+// APIError is a DefectDojo API error response
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("DefectDojo returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("DefectDojo returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// get fetches a resource and unmarshals it into result
+func (c *Client) get(ctx context.Context, path string, params map[string]string, result any) error {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		Get(c.baseURL + path)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(resp.Body(), result); err != nil {
+		return fmt.Errorf("failed to unmarshal DefectDojo response: %w", err)
+	}
+	return nil
+}
+
+// post creates a resource and unmarshals the response into result, if given
+func (c *Client) post(ctx context.Context, path string, body, result any) error {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		Post(c.baseURL + path)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body(), result); err != nil {
+		return fmt.Errorf("failed to unmarshal DefectDojo response: %w", err)
+	}
+	return nil
+}
+
+// checkResponse turns an unsuccessful response into an APIError
+func checkResponse(resp *resty.Response) error {
+	if resp.StatusCode() == http.StatusUnauthorized || resp.StatusCode() == http.StatusForbidden {
+		return fmt.Errorf("DefectDojo unauthorized - please check DEFECTDOJO_API_KEY")
+	}
+	if resp.IsError() {
+		body := strings.TrimSpace(string(resp.Body()))
+		if len(body) > 500 {
+			body = body[:500]
+		}
+		return &APIError{StatusCode: resp.StatusCode(), Body: body}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package defectdojo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(server *httptest.Server) *Client {
+	return NewClient(&ClientConfig{URL: server.URL + "/", APIKey: "secret", Timeout: 5 * time.Second})
+}
+
+func TestClient_EnsureProductType(t *testing.T) {
+	created := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Token secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "/api/v2/product_types/", r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			// The name filter is a substring match, so the exact name is picked
+			assert.Equal(t, "Bug Bounty", r.URL.Query().Get("name"))
+			fmt.Fprint(w, `{"count":1,"results":[{"id":3,"name":"Bug Bounty Archive"}]}`)
+		case http.MethodPost:
+			var productType ProductType
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&productType))
+			assert.Equal(t, "Bug Bounty", productType.Name)
+			created = true
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":7,"name":"Bug Bounty"}`)
+		}
+	}))
+	defer server.Close()
+
+	id, err := newTestClient(server).EnsureProductType(context.Background(), "Bug Bounty")
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.True(t, created)
+}
+
+func TestClient_EnsureProductExisting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method, "an existing product must not be created again")
+		fmt.Fprint(w, `{"count":1,"results":[{"id":12,"name":"Slack (hackerone)","prod_type":7}]}`)
+	}))
+	defer server.Close()
+
+	product, created, err := newTestClient(server).EnsureProduct(context.Background(), &Product{Name: "Slack (hackerone)", ProductType: 7})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, 12, product.ID)
+}
+
+func TestClient_CreateEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var endpoint Endpoint
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&endpoint))
+
+		switch endpoint.Host {
+		case "new.example.com":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":1}`)
+		case "old.example.com":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"non_field_errors":["It appears as though an endpoint with this data already exists for this product."]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"product":["Invalid pk"]}`)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(server)
+
+	created, err := client.CreateEndpoint(context.Background(), &Endpoint{Host: "new.example.com", Product: 1})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = client.CreateEndpoint(context.Background(), &Endpoint{Host: "old.example.com", Product: 1})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	_, err = client.CreateEndpoint(context.Background(), &Endpoint{Host: "bad.example.com", Product: 99})
+	assert.ErrorContains(t, err, "Invalid pk")
+}
+
+func TestClient_ImportFindings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/import-scan/", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, GenericScanType, r.FormValue("scan_type"))
+		assert.Equal(t, "42", r.FormValue("engagement"))
+		assert.Equal(t, "2025-03-01", r.FormValue("scan_date"))
+		assert.Equal(t, "true", r.FormValue("close_old_findings"))
+
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		body, err := io.ReadAll(file)
+		require.NoError(t, err)
+
+		var report GenericReport
+		require.NoError(t, json.Unmarshal(body, &report))
+		require.Len(t, report.Findings, 1)
+		assert.Equal(t, "tls:expired-certificate:1", report.Findings[0].UniqueIDFromTool)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"test":5,"engagement":42,"scan_type":"Generic Findings Import"}`)
+	}))
+	defer server.Close()
+
+	report := &GenericReport{Findings: []GenericFinding{{Title: "TLS expired-certificate", Severity: SeverityMedium, UniqueIDFromTool: "tls:expired-certificate:1"}}}
+	result, err := newTestClient(server).ImportFindings(context.Background(), 42, time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC), report)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Test)
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).EnsureProductType(context.Background(), "Bug Bounty")
+	assert.ErrorContains(t, err, "DEFECTDOJO_API_KEY")
+}
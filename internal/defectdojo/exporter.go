@@ -0,0 +1,330 @@
+package defectdojo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/sirupsen/logrus"
+)
+
+// Exporter pushes finished scans to DefectDojo: each program becomes a
+// product, each scan an engagement of it, assets become endpoints and TLS
+// findings and triage rule matches become findings
+type Exporter struct {
+	client         *Client
+	repo           *database.DefectDojoRepository
+	programRepo    *database.ProgramRepository
+	assetRepo      *database.AssetRepository
+	productType    string
+	excludeSources []string
+}
+
+// NewExporter creates a new exporter. Products are created under productType;
+// assets only excludeSources found, and their findings, are not exported.
+func NewExporter(db *sqlx.DB, client *Client, productType string, excludeSources []string) *Exporter {
+	return &Exporter{
+		client:         client,
+		repo:           database.NewDefectDojoRepository(db),
+		programRepo:    database.NewProgramRepository(db),
+		assetRepo:      database.NewAssetRepository(db),
+		productType:    productType,
+		excludeSources: excludeSources,
+	}
+}
+
+// Export pushes up to limit scans that have not been exported yet, oldest
+// first, optionally only those of one program. Each scan is recorded once it
+// is exported, so a failed run can simply be repeated. The summary counts
+// what was exported before an error.
+func (e *Exporter) Export(ctx context.Context, programID *uuid.UUID, limit int) (*ExportSummary, error) {
+	summary := &ExportSummary{}
+	scans, err := e.repo.GetUnexportedScans(ctx, programID, limit)
+	if err != nil {
+		return summary, err
+	}
+
+	if len(scans) == 0 {
+		return summary, nil
+	}
+
+	productTypeID, err := e.client.EnsureProductType(ctx, e.productType)
+	if err != nil {
+		return summary, err
+	}
+
+	logrus.Infof("Exporting %d scans to DefectDojo", len(scans))
+
+	products := make(map[uuid.UUID]*Product)
+	for _, scan := range scans {
+		program, err := e.programRepo.GetProgramByID(ctx, scan.ProgramID)
+		if err != nil {
+			return summary, err
+		}
+
+		product, ok := products[program.ID]
+		if !ok {
+			var created bool
+			product, created, err = e.client.EnsureProduct(ctx, &Product{
+				Name:        ProductName(program),
+				Description: fmt.Sprintf("%s program %s, exported by Monitor Agent", program.Platform, program.ProgramURL),
+				ProductType: productTypeID,
+			})
+			if err != nil {
+				return summary, err
+			}
+			if created {
+				summary.ProductsCreated++
+			}
+			products[program.ID] = product
+		}
+
+		export, err := e.exportScan(ctx, program, product, scan)
+		if err != nil {
+			return summary, fmt.Errorf("failed to export scan %s of %s: %w", scan.ID, program.Name, err)
+		}
+
+		summary.Scans++
+		summary.Endpoints += export.Endpoints
+		summary.Findings += export.Findings
+	}
+
+	return summary, nil
+}
+
+// exportScan pushes one scan as an engagement of the program's product
+func (e *Exporter) exportScan(ctx context.Context, program *database.Program, product *Product, scan *database.Scan) (*database.DefectDojoExport, error) {
+	// The first export of a program pushes all of its assets; later ones only
+	// the assets each scan found for the first time
+	exported, err := e.repo.HasProgramExport(ctx, program.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var assets []*database.Asset
+	if exported {
+		assets, err = e.assetRepo.GetAssetsByFirstScanID(ctx, scan.ID)
+	} else {
+		assets, err = e.assetRepo.GetAssetsByProgramID(ctx, program.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	assets = database.ExcludeSources(assets, e.excludeSources)
+
+	endpoints := 0
+	for _, asset := range assets {
+		endpoint, err := AssetEndpoint(asset)
+		if err != nil {
+			logrus.Debugf("Skipping asset %s for DefectDojo: %v", asset.URL, err)
+			continue
+		}
+		endpoint.Product = product.ID
+		created, err := e.client.CreateEndpoint(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			endpoints++
+		}
+	}
+
+	tlsFindings, err := e.repo.GetProgramTLSFindings(ctx, program.ID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := e.repo.GetProgramRuleMatches(ctx, program.ID, scan.StartedAt, *scan.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	programAssets, err := e.assetRepo.GetAssetsByProgramID(ctx, program.ID)
+	if err != nil {
+		return nil, err
+	}
+	assetURLs := make(map[uuid.UUID]string, len(programAssets))
+	for _, asset := range database.ExcludeSources(programAssets, e.excludeSources) {
+		assetURLs[asset.ID] = asset.URL
+	}
+	tlsFindings, matches = exportedFindings(tlsFindings, matches, assetURLs)
+
+	report := BuildReport(tlsFindings, matches, assetURLs)
+
+	engagement, err := e.client.CreateEngagement(ctx, &Engagement{
+		Name:           fmt.Sprintf("Scan %s", scan.StartedAt.UTC().Format("2006-01-02 15:04")),
+		Description:    fmt.Sprintf("Monitor Agent scan %s (%s): %d assets seen, %d assets in total", scan.ID, scan.Status, scan.AssetsSeen, scan.AssetsFound),
+		Product:        product.ID,
+		TargetStart:    scan.StartedAt.UTC().Format(time.DateOnly),
+		TargetEnd:      scan.CompletedAt.UTC().Format(time.DateOnly),
+		Status:         "Completed",
+		EngagementType: "CI/CD",
+		Version:        scan.AgentVersion,
+		BuildID:        scan.ID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The report is imported even when empty so that findings resolved since
+	// the previous export are closed
+	if _, err := e.client.ImportFindings(ctx, engagement.ID, *scan.CompletedAt, report); err != nil {
+		return nil, err
+	}
+
+	export := &database.DefectDojoExport{
+		ScanID:       scan.ID,
+		ProgramID:    program.ID,
+		ProductID:    product.ID,
+		EngagementID: engagement.ID,
+		Endpoints:    endpoints,
+		Findings:     len(report.Findings),
+	}
+	if err := e.repo.SaveExport(ctx, export); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Exported scan %s of %s to DefectDojo engagement %d: %d new endpoints, %d findings",
+		scan.ID, program.Name, engagement.ID, endpoints, len(report.Findings))
+
+	return export, nil
+}
+
+// exportedFindings leaves out the findings of assets that are not exported,
+// i.e. missing from assetURLs
+func exportedFindings(tlsFindings []*database.TLSFinding, matches []*database.RuleMatch, assetURLs map[uuid.UUID]string) ([]*database.TLSFinding, []*database.RuleMatch) {
+	var keptFindings []*database.TLSFinding
+	for _, finding := range tlsFindings {
+		if _, ok := assetURLs[finding.AssetID]; ok {
+			keptFindings = append(keptFindings, finding)
+		}
+	}
+
+	var keptMatches []*database.RuleMatch
+	for _, match := range matches {
+		if _, ok := assetURLs[match.AssetID]; ok {
+			keptMatches = append(keptMatches, match)
+		}
+	}
+	return keptFindings, keptMatches
+}
+
+// ProductName is the name of a program's product. Product names are unique
+// in DefectDojo, so the platform is included to keep programs with the same
+// name on different platforms apart.
+func ProductName(program *database.Program) string {
+	return fmt.Sprintf("%s (%s)", program.Name, program.Platform)
+}
+
+// AssetEndpoint converts an asset URL into a DefectDojo endpoint, tagged with
+// the sources that found the asset
+func AssetEndpoint(asset *database.Asset) (*Endpoint, error) {
+	raw := asset.URL
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset URL: %w", err)
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("asset URL has no host")
+	}
+
+	endpoint := &Endpoint{
+		Protocol: parsed.Scheme,
+		Host:     parsed.Hostname(),
+		Path:     strings.TrimPrefix(parsed.Path, "/"),
+	}
+	for _, source := range asset.Sources() {
+		endpoint.Tags = append(endpoint.Tags, "source:"+source)
+	}
+	if port := parsed.Port(); port != "" {
+		endpoint.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset port: %w", err)
+		}
+	}
+
+	return endpoint, nil
+}
+
+// BuildReport converts open TLS findings and triage rule matches into a
+// generic findings report. A rule that matched an asset several times is
+// reported once. assetURLs maps asset IDs to their URL.
+func BuildReport(tlsFindings []*database.TLSFinding, matches []*database.RuleMatch, assetURLs map[uuid.UUID]string) *GenericReport {
+	report := &GenericReport{Findings: []GenericFinding{}}
+
+	for _, finding := range tlsFindings {
+		report.Findings = append(report.Findings, GenericFinding{
+			Title:            fmt.Sprintf("TLS %s on %s", finding.CheckName, hostOf(finding.URL)),
+			Description:      finding.Detail,
+			Severity:         tlsSeverity(finding.Severity),
+			Date:             finding.FirstSeen.UTC().Format(time.DateOnly),
+			UniqueIDFromTool: fmt.Sprintf("tls:%s:%s", finding.CheckName, finding.AssetID),
+			VulnIDFromTool:   finding.CheckName,
+			Endpoints:        []string{finding.URL},
+			Tags:             []string{"monitor-agent", "tls"},
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		uniqueID := fmt.Sprintf("rule:%s:%s", match.RuleName, match.AssetID)
+		if seen[uniqueID] {
+			continue
+		}
+		seen[uniqueID] = true
+
+		assetURL := assetURLs[match.AssetID]
+		finding := GenericFinding{
+			Title:            fmt.Sprintf("Triage rule %s matched %s", match.RuleName, hostOf(assetURL)),
+			Description:      fmt.Sprintf("The response of %s matched the triage rule %s.", assetURL, match.RuleName),
+			Severity:         SeverityInfo,
+			Date:             match.CreatedAt.UTC().Format(time.DateOnly),
+			UniqueIDFromTool: uniqueID,
+			VulnIDFromTool:   match.RuleName,
+			Tags:             append([]string{"monitor-agent", "rule"}, splitTags(match.Tags)...),
+		}
+		if assetURL != "" {
+			finding.Endpoints = []string{assetURL}
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	return report
+}
+
+// tlsSeverity maps a TLS finding severity onto a DefectDojo severity
+func tlsSeverity(severity string) string {
+	if severity == "medium" {
+		return SeverityMedium
+	}
+	return SeverityLow
+}
+
+// hostOf returns the host of a URL, or the URL itself when it has none
+func hostOf(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return rawURL
+}
+
+// splitTags splits comma-separated rule tags, sorted and without blanks
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
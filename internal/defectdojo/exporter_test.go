@@ -0,0 +1,92 @@
+package defectdojo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetEndpoint(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Endpoint
+	}{
+		{"https://api.example.com", Endpoint{Protocol: "https", Host: "api.example.com"}},
+		{"http://example.com:8080/admin", Endpoint{Protocol: "http", Host: "example.com", Port: 8080, Path: "admin"}},
+		{"example.com", Endpoint{Protocol: "https", Host: "example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			endpoint, err := AssetEndpoint(&database.Asset{URL: tt.url})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *endpoint)
+		})
+	}
+
+	endpoint, err := AssetEndpoint(&database.Asset{URL: "https://dev.example.com", Provenance: pq.StringArray{"hackerone", "chaosdb"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"source:hackerone", "source:chaosdb"}, endpoint.Tags)
+
+	_, err = AssetEndpoint(&database.Asset{URL: "https://"})
+	assert.Error(t, err)
+}
+
+func TestBuildReport(t *testing.T) {
+	assetID := uuid.New()
+	seen := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tlsFindings := []*database.TLSFinding{{
+		AssetID:   assetID,
+		CheckName: "expired-certificate",
+		Severity:  "medium",
+		Detail:    "certificate for example.com expired on 2025-02-01",
+		URL:       "https://example.com",
+		FirstSeen: seen,
+	}}
+	matches := []*database.RuleMatch{
+		{AssetID: assetID, RuleName: "exposed-admin", Tags: "admin, exposed", CreatedAt: seen},
+		{AssetID: assetID, RuleName: "exposed-admin", Tags: "admin, exposed", CreatedAt: seen.Add(time.Hour)},
+	}
+
+	report := BuildReport(tlsFindings, matches, map[uuid.UUID]string{assetID: "https://example.com"})
+	require.Len(t, report.Findings, 2, "repeated rule matches are reported once")
+
+	tls := report.Findings[0]
+	assert.Equal(t, "TLS expired-certificate on example.com", tls.Title)
+	assert.Equal(t, SeverityMedium, tls.Severity)
+	assert.Equal(t, "2025-03-01", tls.Date)
+	assert.Equal(t, "tls:expired-certificate:"+assetID.String(), tls.UniqueIDFromTool)
+	assert.Equal(t, []string{"https://example.com"}, tls.Endpoints)
+
+	rule := report.Findings[1]
+	assert.Equal(t, "Triage rule exposed-admin matched example.com", rule.Title)
+	assert.Equal(t, SeverityInfo, rule.Severity)
+	assert.Equal(t, []string{"monitor-agent", "rule", "admin", "exposed"}, rule.Tags)
+}
+
+func TestBuildReport_Empty(t *testing.T) {
+	report := BuildReport(nil, nil, nil)
+	assert.NotNil(t, report.Findings, "an empty report still closes resolved findings")
+	assert.Empty(t, report.Findings)
+}
+
+func TestExportedFindings(t *testing.T) {
+	exported, excluded := uuid.New(), uuid.New()
+	tlsFindings := []*database.TLSFinding{{AssetID: exported}, {AssetID: excluded}}
+	matches := []*database.RuleMatch{{AssetID: excluded}}
+
+	tlsFindings, matches = exportedFindings(tlsFindings, matches, map[uuid.UUID]string{exported: "https://example.com"})
+	require.Len(t, tlsFindings, 1)
+	assert.Equal(t, exported, tlsFindings[0].AssetID)
+	assert.Empty(t, matches)
+}
+
+func TestProductName(t *testing.T) {
+	assert.Equal(t, "Slack (hackerone)", ProductName(&database.Program{Name: "Slack", Platform: "hackerone"}))
+}
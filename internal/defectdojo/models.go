@@ -0,0 +1,92 @@
+package defectdojo
+
+// GenericScanType is the DefectDojo parser findings are imported with
+const GenericScanType = "Generic Findings Import"
+
+// DefectDojo severities
+const (
+	SeverityCritical = "Critical"
+	SeverityHigh     = "High"
+	SeverityMedium   = "Medium"
+	SeverityLow      = "Low"
+	SeverityInfo     = "Info"
+)
+
+// ProductType groups products in DefectDojo
+type ProductType struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// Product is a DefectDojo product; each program is exported as one
+type Product struct {
+	ID          int    `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ProductType int    `json:"prod_type"`
+}
+
+// Engagement is a DefectDojo engagement; each scan is exported as one
+type Engagement struct {
+	ID             int    `json:"id,omitempty"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Product        int    `json:"product"`
+	TargetStart    string `json:"target_start"` // YYYY-MM-DD
+	TargetEnd      string `json:"target_end"`
+	Status         string `json:"status"`
+	EngagementType string `json:"engagement_type"`
+	Version        string `json:"version,omitempty"`
+	BuildID        string `json:"build_id,omitempty"`
+}
+
+// Endpoint is a host or URL of a product; each asset is exported as one
+type Endpoint struct {
+	ID       int      `json:"id,omitempty"`
+	Protocol string   `json:"protocol,omitempty"`
+	Host     string   `json:"host"`
+	Port     int      `json:"port,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Product  int      `json:"product"`
+	Tags     []string `json:"tags,omitempty"` // source:<discovery source> per source that found the asset
+}
+
+// GenericReport is the file format of the Generic Findings Import parser
+type GenericReport struct {
+	Findings []GenericFinding `json:"findings"`
+}
+
+// GenericFinding is one finding of a generic report. DefectDojo deduplicates
+// findings on their title and description, so neither may change between
+// exports of the same finding.
+type GenericFinding struct {
+	Title            string   `json:"title"`
+	Description      string   `json:"description"`
+	Severity         string   `json:"severity"`
+	Date             string   `json:"date"` // YYYY-MM-DD
+	UniqueIDFromTool string   `json:"unique_id_from_tool"`
+	VulnIDFromTool   string   `json:"vuln_id_from_tool,omitempty"`
+	Endpoints        []string `json:"endpoints,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+}
+
+// ImportResult is DefectDojo's response to a scan import
+type ImportResult struct {
+	Test       int    `json:"test"`
+	Engagement int    `json:"engagement"`
+	ScanType   string `json:"scan_type"`
+}
+
+// ExportSummary totals the results of an export run
+type ExportSummary struct {
+	Scans           int
+	ProductsCreated int
+	Endpoints       int
+	Findings        int
+}
+
+// listResponse is a page of DefectDojo API results
+type listResponse[T any] struct {
+	Count   int `json:"count"`
+	Results []T `json:"results"`
+}
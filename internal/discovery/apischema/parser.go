@@ -0,0 +1,254 @@
+package apischema
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// Schema kinds detected in response bodies
+const (
+	KindOpenAPI = "openapi"
+	KindSwagger = "swagger"
+	KindGraphQL = "graphql"
+	KindWADL    = "wadl"
+)
+
+// maxBodySize bounds how much of a response body is parsed
+const maxBodySize = 5 * 1024 * 1024
+
+// Schema is the API surface parsed from a response body
+type Schema struct {
+	Kind      string
+	Version   string
+	Title     string
+	Endpoints []Endpoint
+}
+
+// Endpoint is a single operation exposed by an API schema. GraphQL fields use
+// QUERY, MUTATION or SUBSCRIPTION as the method and the field name as the path.
+type Endpoint struct {
+	Method  string
+	Path    string
+	Summary string
+}
+
+// httpMethods are the operation keys of an OpenAPI/Swagger path item
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Parse detects an OpenAPI/Swagger document, GraphQL introspection result or
+// WADL document in a response body and returns its API surface, or nil when
+// the body is not a recognized schema
+func Parse(body, contentType string) *Schema {
+	if len(body) > maxBodySize {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		if schema := parseOpenAPI(trimmed); schema != nil {
+			return schema
+		}
+		return parseGraphQL(trimmed)
+	case strings.HasPrefix(trimmed, "<") && (strings.Contains(contentType, "xml") || strings.Contains(trimmed, "wadl")):
+		return parseWADL(trimmed)
+	}
+
+	return nil
+}
+
+// openAPIDocument holds the fields shared by Swagger 2.0 and OpenAPI 3.x
+type openAPIDocument struct {
+	Swagger string `json:"swagger"`
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title string `json:"title"`
+	} `json:"info"`
+	BasePath string                                `json:"basePath"`
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// parseOpenAPI parses a Swagger 2.0 or OpenAPI 3.x JSON document
+func parseOpenAPI(body string) *Schema {
+	var doc openAPIDocument
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return nil
+	}
+
+	schema := &Schema{Title: doc.Info.Title}
+	switch {
+	case doc.OpenAPI != "":
+		schema.Kind = KindOpenAPI
+		schema.Version = doc.OpenAPI
+	case doc.Swagger != "":
+		schema.Kind = KindSwagger
+		schema.Version = doc.Swagger
+	default:
+		return nil
+	}
+
+	basePath := strings.TrimSuffix(doc.BasePath, "/")
+	for path, item := range doc.Paths {
+		for _, method := range httpMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+
+			var operation struct {
+				Summary     string `json:"summary"`
+				OperationID string `json:"operationId"`
+			}
+			// A malformed operation still exposes the endpoint
+			_ = json.Unmarshal(raw, &operation)
+
+			summary := operation.Summary
+			if summary == "" {
+				summary = operation.OperationID
+			}
+
+			schema.Endpoints = append(schema.Endpoints, Endpoint{
+				Method:  strings.ToUpper(method),
+				Path:    basePath + path,
+				Summary: summary,
+			})
+		}
+	}
+
+	sortEndpoints(schema.Endpoints)
+	return schema
+}
+
+// graphQLType is a type in a GraphQL introspection result
+type graphQLType struct {
+	Name   string `json:"name"`
+	Fields []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"fields"`
+}
+
+// graphQLIntrospection is the shape of an introspection query result
+type graphQLIntrospection struct {
+	Data struct {
+		Schema *struct {
+			QueryType        *struct{ Name string } `json:"queryType"`
+			MutationType     *struct{ Name string } `json:"mutationType"`
+			SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+			Types            []graphQLType          `json:"types"`
+		} `json:"__schema"`
+	} `json:"data"`
+}
+
+// parseGraphQL parses a GraphQL introspection result, listing the fields of
+// the query, mutation and subscription root types
+func parseGraphQL(body string) *Schema {
+	var result graphQLIntrospection
+	if err := json.Unmarshal([]byte(body), &result); err != nil || result.Data.Schema == nil {
+		return nil
+	}
+
+	introspection := result.Data.Schema
+	roots := make(map[string]string)
+	if introspection.QueryType != nil {
+		roots[introspection.QueryType.Name] = "QUERY"
+	}
+	if introspection.MutationType != nil {
+		roots[introspection.MutationType.Name] = "MUTATION"
+	}
+	if introspection.SubscriptionType != nil {
+		roots[introspection.SubscriptionType.Name] = "SUBSCRIPTION"
+	}
+
+	schema := &Schema{Kind: KindGraphQL}
+	for _, t := range introspection.Types {
+		operation, ok := roots[t.Name]
+		if !ok {
+			continue
+		}
+		for _, field := range t.Fields {
+			schema.Endpoints = append(schema.Endpoints, Endpoint{
+				Method:  operation,
+				Path:    field.Name,
+				Summary: field.Description,
+			})
+		}
+	}
+
+	sortEndpoints(schema.Endpoints)
+	return schema
+}
+
+// wadlResource is a (possibly nested) resource in a WADL document
+type wadlResource struct {
+	Path    string `xml:"path,attr"`
+	Methods []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"id,attr"`
+	} `xml:"method"`
+	Resources []wadlResource `xml:"resource"`
+}
+
+// wadlApplication is the root element of a WADL document
+type wadlApplication struct {
+	XMLName   xml.Name `xml:"application"`
+	Resources []struct {
+		Base      string         `xml:"base,attr"`
+		Resources []wadlResource `xml:"resource"`
+	} `xml:"resources"`
+}
+
+// parseWADL parses a WADL document, joining nested resource paths
+func parseWADL(body string) *Schema {
+	var app wadlApplication
+	if err := xml.Unmarshal([]byte(body), &app); err != nil {
+		return nil
+	}
+	if !strings.Contains(app.XMLName.Space, "wadl") {
+		return nil
+	}
+
+	schema := &Schema{Kind: KindWADL}
+	var walk func(prefix string, resources []wadlResource)
+	walk = func(prefix string, resources []wadlResource) {
+		for _, resource := range resources {
+			path := joinPath(prefix, resource.Path)
+			for _, method := range resource.Methods {
+				schema.Endpoints = append(schema.Endpoints, Endpoint{
+					Method:  strings.ToUpper(method.Name),
+					Path:    path,
+					Summary: method.ID,
+				})
+			}
+			walk(path, resource.Resources)
+		}
+	}
+
+	for _, resources := range app.Resources {
+		walk("", resources.Resources)
+	}
+
+	sortEndpoints(schema.Endpoints)
+	return schema
+}
+
+// joinPath joins two URL path segments with a single slash
+func joinPath(prefix, path string) string {
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// sortEndpoints orders endpoints by path and method so results are stable
+func sortEndpoints(endpoints []Endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+}
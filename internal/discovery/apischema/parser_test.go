@@ -0,0 +1,110 @@
+package apischema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_OpenAPI(t *testing.T) {
+	body := `{
+		"openapi": "3.0.1",
+		"info": {"title": "Pet Store"},
+		"paths": {
+			"/pets": {
+				"get": {"summary": "List pets"},
+				"post": {"operationId": "createPet"},
+				"parameters": []
+			},
+			"/pets/{id}": {"delete": {}}
+		}
+	}`
+
+	schema := Parse(body, "application/json")
+	require.NotNil(t, schema)
+	assert.Equal(t, KindOpenAPI, schema.Kind)
+	assert.Equal(t, "3.0.1", schema.Version)
+	assert.Equal(t, "Pet Store", schema.Title)
+	assert.Equal(t, []Endpoint{
+		{Method: "GET", Path: "/pets", Summary: "List pets"},
+		{Method: "POST", Path: "/pets", Summary: "createPet"},
+		{Method: "DELETE", Path: "/pets/{id}"},
+	}, schema.Endpoints)
+}
+
+func TestParse_Swagger(t *testing.T) {
+	body := `{"swagger": "2.0", "basePath": "/api/v1/", "paths": {"/users": {"get": {}}}}`
+
+	schema := Parse(body, "application/json")
+	require.NotNil(t, schema)
+	assert.Equal(t, KindSwagger, schema.Kind)
+	assert.Equal(t, "2.0", schema.Version)
+	assert.Equal(t, []Endpoint{{Method: "GET", Path: "/api/v1/users"}}, schema.Endpoints)
+}
+
+func TestParse_GraphQL(t *testing.T) {
+	body := `{"data": {"__schema": {
+		"queryType": {"name": "Query"},
+		"mutationType": {"name": "Mutation"},
+		"subscriptionType": null,
+		"types": [
+			{"name": "Query", "fields": [{"name": "user", "description": "Fetch a user"}]},
+			{"name": "Mutation", "fields": [{"name": "deleteUser"}]},
+			{"name": "User", "fields": [{"name": "email"}]}
+		]
+	}}}`
+
+	schema := Parse(body, "application/json")
+	require.NotNil(t, schema)
+	assert.Equal(t, KindGraphQL, schema.Kind)
+	assert.Equal(t, []Endpoint{
+		{Method: "MUTATION", Path: "deleteUser"},
+		{Method: "QUERY", Path: "user", Summary: "Fetch a user"},
+	}, schema.Endpoints)
+}
+
+func TestParse_WADL(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<application xmlns="http://wadl.dev.java.net/2009/02">
+  <resources base="https://api.example.com/">
+    <resource path="orders">
+      <method name="GET" id="listOrders"/>
+      <resource path="{orderId}">
+        <method name="get"/>
+        <method name="PUT"/>
+      </resource>
+    </resource>
+  </resources>
+</application>`
+
+	schema := Parse(body, "application/vnd.sun.wadl+xml")
+	require.NotNil(t, schema)
+	assert.Equal(t, KindWADL, schema.Kind)
+	assert.Equal(t, []Endpoint{
+		{Method: "GET", Path: "/orders", Summary: "listOrders"},
+		{Method: "GET", Path: "/orders/{orderId}"},
+		{Method: "PUT", Path: "/orders/{orderId}"},
+	}, schema.Endpoints)
+}
+
+func TestParse_NotASchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+	}{
+		{"empty", "", ""},
+		{"html", "<html><body>Hello</body></html>", "text/html"},
+		{"plain json", `{"status": "ok"}`, "application/json"},
+		{"graphql error", `{"errors": [{"message": "introspection disabled"}]}`, "application/json"},
+		{"other xml", `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"></feed>`, "application/xml"},
+		{"invalid json", `{"openapi": `, "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, Parse(tt.body, tt.contentType))
+		})
+	}
+}
@@ -6,15 +6,23 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/schemadrift"
 	"github.com/monitor-agent/internal/utils"
+	"github.com/monitor-agent/internal/version"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	baseURL = "https://dns.projectdiscovery.io/dns"
+	defaultBaseURL = "https://dns.projectdiscovery.io/dns"
+
+	// Rate limiter endpoints of the API; dataset downloads are not rate limited
+	subdomainsEndpoint = "/dns/{domain}/subdomains"
+	healthEndpoint     = "/dns"
 )
 
 // Client represents a ChaosDB API client
@@ -23,15 +31,31 @@ type Client struct {
 	apiKey       string
 	rateLimiter  *utils.RateLimiter
 	urlProcessor *utils.URLProcessor
+	baseURL      string
+
+	datasetIndexURL   string
+	datasetMu         sync.Mutex
+	datasets          []*Dataset
+	datasetsFetchedAt time.Time
 }
 
 // ClientConfig holds configuration for the ChaosDB client
 type ClientConfig struct {
 	APIKey        string
 	RateLimit     int
+	RateBurst     int // most requests sent in a burst; 0 uses RateLimit
 	Timeout       time.Duration
 	RetryAttempts int
 	RetryDelay    time.Duration
+
+	// BaseURL overrides the default API URL
+	BaseURL string
+
+	// DatasetIndexURL overrides DefaultDatasetIndexURL
+	DatasetIndexURL string
+
+	// Metrics reports the rate limiter's state; nil disables it
+	Metrics *metrics.Metrics
 }
 
 // NewClient creates a new ChaosDB client
@@ -46,7 +70,7 @@ func NewClient(config *ClientConfig) *Client {
 	client.SetHeaders(map[string]string{
 		"Accept":       "application/json",
 		"Content-Type": "application/json",
-		"User-Agent":   "Monitor-Agent/1.0",
+		"User-Agent":   version.UserAgent(),
 	})
 
 	// Add API key if provided
@@ -54,17 +78,35 @@ func NewClient(config *ClientConfig) *Client {
 		client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", config.APIKey))
 	}
 
+	datasetIndexURL := config.DatasetIndexURL
+	if datasetIndexURL == "" {
+		datasetIndexURL = DefaultDatasetIndexURL
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	rateLimiter := utils.NewRateLimiter(config.RateLimit, time.Minute)
+	rateLimiter.SetBurst(config.RateBurst)
+	config.Metrics.InstrumentRateLimiter("chaosdb", rateLimiter)
+
 	return &Client{
-		httpClient:   client,
-		apiKey:       config.APIKey,
-		rateLimiter:  utils.NewRateLimiter(config.RateLimit, time.Minute),
-		urlProcessor: utils.NewURLProcessor(),
+		httpClient:      client,
+		apiKey:          config.APIKey,
+		rateLimiter:     rateLimiter,
+		urlProcessor:    utils.NewURLProcessor(),
+		baseURL:         baseURL,
+		datasetIndexURL: datasetIndexURL,
 	}
 }
 
 // DiscoverDomain discovers subdomains for a single domain
 func (c *Client) DiscoverDomain(ctx context.Context, domain string) (*DiscoveryResult, error) {
-	c.rateLimiter.Wait()
+	if err := c.rateLimiter.WaitEndpoint(ctx, subdomainsEndpoint); err != nil {
+		return nil, err
+	}
 
 	// Clean and normalize domain
 	cleanDomain, err := c.urlProcessor.ExtractDomain(domain)
@@ -74,11 +116,12 @@ func (c *Client) DiscoverDomain(ctx context.Context, domain string) (*DiscoveryR
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
-		Get(fmt.Sprintf("%s/%s/subdomains", baseURL, cleanDomain))
+		Get(fmt.Sprintf("%s/%s/subdomains", c.baseURL, cleanDomain))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request for domain %s: %w", cleanDomain, err)
 	}
+	c.rateLimiter.Observe(subdomainsEndpoint, resp.StatusCode(), resp.Header())
 
 	if resp.StatusCode() == http.StatusNotFound {
 		// Domain not found in ChaosDB, return empty result
@@ -107,6 +150,7 @@ func (c *Client) DiscoverDomain(ctx context.Context, domain string) (*DiscoveryR
 	if err := json.Unmarshal(resp.Body(), &chaosResp); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response for domain %s: %w", cleanDomain, err)
 	}
+	schemadrift.Check("chaosdb", "subdomains", resp.Body(), chaosResp)
 
 	// Check if there was an error in the response
 	if chaosResp.Error != "" {
@@ -246,16 +290,19 @@ func (c *Client) DiscoverDomainsConcurrent(ctx context.Context, domains []string
 
 // IsHealthy checks if the ChaosDB API is healthy
 func (c *Client) IsHealthy(ctx context.Context) error {
-	c.rateLimiter.Wait()
+	if err := c.rateLimiter.WaitEndpoint(ctx, healthEndpoint); err != nil {
+		return err
+	}
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
 		SetQueryParam("domain", "example.com").
-		Get(baseURL)
+		Get(c.baseURL)
 
 	if err != nil {
 		return fmt.Errorf("failed to check ChaosDB API health: %w", err)
 	}
+	c.rateLimiter.Observe(healthEndpoint, resp.StatusCode(), resp.Header())
 
 	if resp.StatusCode() != http.StatusOK {
 		return fmt.Errorf("ChaosDB API returned status %d", resp.StatusCode())
@@ -273,3 +320,21 @@ func (c *Client) GetRateLimit() int {
 func (c *Client) UpdateRateLimit(newRate int) {
 	c.rateLimiter.UpdateRate(newRate)
 }
+
+// Name identifies ChaosDB as a discovery source
+func (c *Client) Name() string {
+	return "chaosdb"
+}
+
+// Subdomains returns the subdomains ChaosDB knows of for a domain, making the
+// client a discovery.Source
+func (c *Client) Subdomains(ctx context.Context, domain string) ([]string, error) {
+	result, err := c.DiscoverDomain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("ChaosDB error for domain %s: %s", result.Domain, result.Error)
+	}
+	return result.Subdomains, nil
+}
@@ -0,0 +1,171 @@
+package chaosdb
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultDatasetIndexURL lists the bulk subdomain datasets ChaosDB publishes per program
+	DefaultDatasetIndexURL = "https://chaos-data.projectdiscovery.io/index.json"
+
+	// datasetIndexTTL is how long the dataset index is reused before it is fetched again
+	datasetIndexTTL = time.Hour
+)
+
+// Dataset is a downloadable zip of the subdomains ChaosDB knows for one program
+type Dataset struct {
+	Name        string    `json:"name"`
+	ProgramURL  string    `json:"program_url"`
+	URL         string    `json:"URL"`
+	Count       int       `json:"count"`
+	Platform    string    `json:"platform"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// ListDatasets retrieves the dataset index, reusing it for up to an hour
+func (c *Client) ListDatasets(ctx context.Context) ([]*Dataset, error) {
+	c.datasetMu.Lock()
+	defer c.datasetMu.Unlock()
+
+	if c.datasets != nil && time.Since(c.datasetsFetchedAt) < datasetIndexTTL {
+		return c.datasets, nil
+	}
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(c.datasetIndexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ChaosDB dataset index: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("ChaosDB dataset index returned status %d", resp.StatusCode())
+	}
+
+	var datasets []*Dataset
+	if err := json.Unmarshal(resp.Body(), &datasets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ChaosDB dataset index: %w", err)
+	}
+
+	c.datasets = datasets
+	c.datasetsFetchedAt = time.Now()
+	return datasets, nil
+}
+
+// FindDataset returns the dataset published for a program, or nil when there is none
+func (c *Client) FindDataset(ctx context.Context, programURL string) (*Dataset, error) {
+	datasets, err := c.ListDatasets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	want := normalizeProgramURL(programURL)
+	for _, dataset := range datasets {
+		if dataset.URL != "" && normalizeProgramURL(dataset.ProgramURL) == want {
+			return dataset, nil
+		}
+	}
+	return nil, nil
+}
+
+// DownloadDataset downloads a dataset zip and returns its subdomains keyed by
+// root domain. Each file in the zip is named <domain>.txt and lists one
+// subdomain per line.
+func (c *Client) DownloadDataset(ctx context.Context, dataset *Dataset) (map[string][]string, error) {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/zip").
+		Get(dataset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download ChaosDB dataset %s: %w", dataset.Name, err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("ChaosDB dataset %s returned status %d", dataset.Name, resp.StatusCode())
+	}
+
+	subdomains, err := parseDataset(resp.Body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ChaosDB dataset %s: %w", dataset.Name, err)
+	}
+
+	logrus.Infof("Downloaded ChaosDB dataset %s with %d domains", dataset.Name, len(subdomains))
+	return subdomains, nil
+}
+
+// parseDataset reads the per-domain subdomain lists out of a dataset zip
+func parseDataset(data []byte) (map[string][]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	subdomains := make(map[string][]string)
+	for _, file := range reader.File {
+		name := path.Base(file.Name)
+		if file.FileInfo().IsDir() || !strings.HasSuffix(name, ".txt") {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimSuffix(name, ".txt"))
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+		}
+
+		scanner := bufio.NewScanner(rc)
+		for scanner.Scan() {
+			if line := strings.ToLower(strings.TrimSpace(scanner.Text())); line != "" {
+				subdomains[domain] = append(subdomains[domain], line)
+			}
+		}
+		err = scanner.Err()
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+	}
+
+	return subdomains, nil
+}
+
+// DatasetSubdomains returns the subdomains a dataset lists under a domain,
+// looking in the dataset of a parent domain when the domain has none of its own
+func DatasetSubdomains(dataset map[string][]string, domain string) ([]string, bool) {
+	domain = strings.ToLower(domain)
+	if subdomains, ok := dataset[domain]; ok {
+		return subdomains, true
+	}
+
+	for root, subdomains := range dataset {
+		if !strings.HasSuffix(domain, "."+root) {
+			continue
+		}
+		var matched []string
+		for _, subdomain := range subdomains {
+			if subdomain == domain || strings.HasSuffix(subdomain, "."+domain) {
+				matched = append(matched, subdomain)
+			}
+		}
+		return matched, true
+	}
+	return nil, false
+}
+
+// normalizeProgramURL makes program URLs from the index and the platforms comparable
+func normalizeProgramURL(programURL string) string {
+	normalized := strings.ToLower(strings.TrimSpace(programURL))
+	normalized = strings.TrimPrefix(normalized, "https://")
+	normalized = strings.TrimPrefix(normalized, "http://")
+	normalized = strings.TrimPrefix(normalized, "www.")
+	return strings.TrimRight(normalized, "/")
+}
@@ -0,0 +1,84 @@
+package chaosdb
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildDatasetZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestClient_Datasets(t *testing.T) {
+	archive := buildDatasetZip(t, map[string]string{
+		"example.com.txt": "www.example.com\napi.example.com\n\nEU.API.example.com\n",
+		"example.io.txt":  "example.io\n",
+		"README.md":       "ignored",
+	})
+
+	indexRequests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.json":
+			indexRequests++
+			fmt.Fprintf(w, `[{"name":"Example","program_url":"https://hackerone.com/example","URL":"%s/example.zip","count":4,"platform":"hackerone"}]`, server.URL)
+		case "/example.zip":
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{RateLimit: 60, Timeout: 5 * time.Second, DatasetIndexURL: server.URL + "/index.json"})
+	ctx := context.Background()
+
+	dataset, err := client.FindDataset(ctx, "https://HackerOne.com/example/")
+	require.NoError(t, err)
+	require.NotNil(t, dataset)
+	assert.Equal(t, "Example", dataset.Name)
+
+	missing, err := client.FindDataset(ctx, "https://hackerone.com/other")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+	assert.Equal(t, 1, indexRequests, "index is reused")
+
+	subdomains, err := client.DownloadDataset(ctx, dataset)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"example.com": {"www.example.com", "api.example.com", "eu.api.example.com"},
+		"example.io":  {"example.io"},
+	}, subdomains)
+
+	listed, ok := DatasetSubdomains(subdomains, "example.com")
+	assert.True(t, ok)
+	assert.Len(t, listed, 3)
+
+	listed, ok = DatasetSubdomains(subdomains, "api.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"api.example.com", "eu.api.example.com"}, listed)
+
+	_, ok = DatasetSubdomains(subdomains, "example.net")
+	assert.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package chaosdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/schemadrift"
+	"github.com/monitor-agent/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DiscoverDomain_Golden(t *testing.T) {
+	payload := testutil.ReadTestdata(t, "subdomains.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+
+	result, err := client.DiscoverDomain(context.Background(), "https://ACME.example/")
+	require.NoError(t, err)
+	result.DiscoveredAt = time.Time{}
+	testutil.AssertGolden(t, "subdomains", result)
+}
+
+func TestClient_DownloadDataset_Golden(t *testing.T) {
+	entries, err := os.ReadDir(filepath.Join("testdata", "dataset"))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		files["acme/"+entry.Name()] = string(testutil.ReadTestdata(t, filepath.Join("dataset", entry.Name())))
+	}
+	archive := buildDatasetZip(t, files)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{RateLimit: 6000, Timeout: 5 * time.Second})
+
+	subdomains, err := client.DownloadDataset(context.Background(), &Dataset{Name: "Acme", URL: server.URL + "/acme.zip"})
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "dataset", subdomains)
+}
+
+func TestClient_DiscoverDomain_HugeResponse(t *testing.T) {
+	const size = 100000
+
+	response := ChaosDBResponse{Domain: "acme.example", Count: size}
+	for i := 0; i < size; i++ {
+		response.Subdomains = append(response.Subdomains, fmt.Sprintf("host-%d", i))
+	}
+	payload, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+
+	result, err := client.DiscoverDomain(context.Background(), "acme.example")
+	require.NoError(t, err)
+	assert.Equal(t, size, result.Count)
+	require.Len(t, result.Subdomains, size)
+	assert.Equal(t, "host-99999", result.Subdomains[size-1])
+}
+
+func TestPayloads_NoSchemaDrift(t *testing.T) {
+	drifts, err := schemadrift.Compare(testutil.ReadTestdata(t, "subdomains.json"), ChaosDBResponse{})
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+}
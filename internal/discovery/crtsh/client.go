@@ -0,0 +1,120 @@
+// Package crtsh finds subdomains in certificate transparency logs through
+// crt.sh, which indexes the names of every logged certificate. Certificates
+// are issued for hosts long before they show up in passive DNS, so it finds
+// names ChaosDB does not have yet.
+package crtsh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+)
+
+// DefaultBaseURL is the public crt.sh service
+const DefaultBaseURL = "https://crt.sh"
+
+// Client queries crt.sh for the certificates issued under a domain
+type Client struct {
+	httpClient *resty.Client
+	baseURL    string
+}
+
+// ClientConfig holds configuration for the crt.sh client
+type ClientConfig struct {
+	BaseURL       string // DefaultBaseURL when empty
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// NewClient creates a new crt.sh client
+func NewClient(config *ClientConfig) *Client {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+	client.SetHeaders(map[string]string{
+		"Accept":     "application/json",
+		"User-Agent": version.UserAgent(),
+	})
+
+	// crt.sh answers 502 and 503 when its database is overloaded
+	client.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		return resp != nil && resp.StatusCode() >= http.StatusInternalServerError
+	})
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		httpClient: client,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Name identifies crt.sh as a discovery source
+func (c *Client) Name() string {
+	return "crtsh"
+}
+
+// Subdomains returns the names below a domain that certificates were issued
+// for, lowercased, without wildcard labels and sorted
+func (c *Client) Subdomains(ctx context.Context, domain string) ([]string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" {
+		return nil, fmt.Errorf("no domain given")
+	}
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{"q": "%." + domain, "output": "json"}).
+		Get(c.baseURL + "/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query crt.sh for domain %s: %w", domain, err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("crt.sh returned status %d for domain %s", resp.StatusCode(), domain)
+	}
+
+	var certificates []certificate
+	if err := json.Unmarshal(resp.Body(), &certificates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal crt.sh response for domain %s: %w", domain, err)
+	}
+
+	return subdomainsOf(domain, certificates), nil
+}
+
+// subdomainsOf collects the distinct names below domain from certificates.
+// Names outside the domain, e.g. other SANs of a shared certificate, and
+// email addresses are dropped.
+func subdomainsOf(domain string, certificates []certificate) []string {
+	suffix := "." + domain
+	seen := make(map[string]bool)
+	var subdomains []string
+	for _, cert := range certificates {
+		for _, name := range strings.Split(cert.NameValue, "\n") {
+			name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+			for strings.HasPrefix(name, "*.") {
+				name = strings.TrimPrefix(name, "*.")
+			}
+			if !strings.HasSuffix(name, suffix) || strings.ContainsAny(name, "@* ") || seen[name] {
+				continue
+			}
+			seen[name] = true
+			subdomains = append(subdomains, name)
+		}
+	}
+
+	sort.Strings(subdomains)
+	return subdomains
+}
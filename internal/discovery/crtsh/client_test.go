@@ -0,0 +1,56 @@
+package crtsh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const crtshResponse = `[
+	{"common_name": "acme.com", "name_value": "acme.com\nwww.acme.com"},
+	{"common_name": "*.api.acme.com", "name_value": "*.api.acme.com\nAPI.acme.com"},
+	{"common_name": "shared.cdn.example", "name_value": "shared.cdn.example\nstatic.acme.com\nstatic.acme.com.evil.example"},
+	{"common_name": "security@acme.com", "name_value": "security@acme.com"},
+	{"common_name": "www.acme.com", "name_value": "www.acme.com"}
+]`
+
+func TestClient_Subdomains(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(crtshResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+	assert.Equal(t, "crtsh", client.Name())
+
+	subdomains, err := client.Subdomains(context.Background(), "ACME.com")
+	require.NoError(t, err)
+	assert.Equal(t, "output=json&q=%25.acme.com", query)
+	assert.Equal(t, []string{"api.acme.com", "static.acme.com", "www.acme.com"}, subdomains)
+}
+
+func TestClient_Subdomains_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") == "%.broken.com" {
+			_, _ = w.Write([]byte("<html>"))
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Timeout: 5 * time.Second, RetryAttempts: 1, RetryDelay: time.Millisecond})
+
+	_, err := client.Subdomains(context.Background(), "acme.com")
+	assert.ErrorContains(t, err, "status 502")
+
+	_, err = client.Subdomains(context.Background(), "broken.com")
+	assert.ErrorContains(t, err, "unmarshal")
+}
@@ -0,0 +1,7 @@
+package crtsh
+
+// certificate is the subset of a crt.sh JSON result that is used
+type certificate struct {
+	CommonName string `json:"common_name"`
+	NameValue  string `json:"name_value"` // the certificate's names, one per line
+}
@@ -0,0 +1,139 @@
+// Package ctlog watches certificate transparency logs for new hostnames. It
+// follows a CertStream server, which relays every certificate as it is added
+// to the logs, so hosts show up when their certificate is issued instead of
+// at the next scan. Historic certificates are searched by the crtsh source.
+package ctlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/monitor-agent/internal/version"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// DefaultStreamURL is the public CertStream server's domains-only stream,
+// which leaves out the certificates and is a fraction of the full stream
+const DefaultStreamURL = "wss://certstream.calidog.io/domains-only"
+
+// defaultReconnectDelay is how long the client waits before reconnecting a
+// stream that dropped
+const defaultReconnectDelay = 5 * time.Second
+
+// Client follows a CertStream server
+type Client struct {
+	streamURL      string
+	reconnectDelay time.Duration
+}
+
+// ClientConfig holds configuration for the CertStream client
+type ClientConfig struct {
+	StreamURL      string        // DefaultStreamURL when empty
+	ReconnectDelay time.Duration // defaultReconnectDelay when zero
+}
+
+// NewClient creates a new CertStream client
+func NewClient(config *ClientConfig) *Client {
+	streamURL := config.StreamURL
+	if streamURL == "" {
+		streamURL = DefaultStreamURL
+	}
+	reconnectDelay := config.ReconnectDelay
+	if reconnectDelay <= 0 {
+		reconnectDelay = defaultReconnectDelay
+	}
+
+	return &Client{
+		streamURL:      streamURL,
+		reconnectDelay: reconnectDelay,
+	}
+}
+
+// StreamURL returns the CertStream URL followed
+func (c *Client) StreamURL() string {
+	return c.streamURL
+}
+
+// Watch passes the hostnames of each newly logged certificate to handle until
+// ctx is done, reconnecting when the stream drops. Hostnames are passed as
+// logged, which may include wildcards. It only returns ctx's error.
+func (c *Client) Watch(ctx context.Context, handle func(hostnames []string)) error {
+	for {
+		err := c.watchOnce(ctx, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logrus.Warnf("Certificate transparency stream %s dropped, reconnecting in %v: %v", c.streamURL, c.reconnectDelay, err)
+
+		select {
+		case <-time.After(c.reconnectDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// watchOnce follows the stream until it drops or ctx is done
+func (c *Client) watchOnce(ctx context.Context, handle func(hostnames []string)) error {
+	config, err := websocket.NewConfig(c.streamURL, "http://localhost/")
+	if err != nil {
+		return fmt.Errorf("invalid stream URL %s: %w", c.streamURL, err)
+	}
+	config.Header.Set("User-Agent", version.UserAgent())
+
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+	logrus.Infof("Following certificate transparency stream %s", c.streamURL)
+
+	// Unblock the read below when ctx is done
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(conn, &data); err != nil {
+			return err
+		}
+
+		hostnames, err := parseMessage(data)
+		if err != nil {
+			logrus.Debugf("Skipping unreadable certificate transparency message: %v", err)
+			continue
+		}
+		if len(hostnames) > 0 {
+			handle(hostnames)
+		}
+	}
+}
+
+// parseMessage returns the hostnames of a CertStream message; none for
+// heartbeats and other messages without certificates
+func parseMessage(data []byte) ([]string, error) {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+
+	switch msg.MessageType {
+	case messageCertificateUpdate:
+		var update certificateUpdate
+		if err := json.Unmarshal(msg.Data, &update); err != nil {
+			return nil, err
+		}
+		return update.LeafCert.AllDomains, nil
+	case messageDNSEntries:
+		var hostnames []string
+		if err := json.Unmarshal(msg.Data, &hostnames); err != nil {
+			return nil, err
+		}
+		return hostnames, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,78 @@
+package ctlog
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// startStream runs a CertStream server sending messages to each connection
+// and then closing it
+func startStream(t *testing.T, messages ...string) (string, func() int) {
+	var mu sync.Mutex
+	connections := 0
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		mu.Lock()
+		connections++
+		mu.Unlock()
+		for _, msg := range messages {
+			_ = websocket.Message.Send(conn, msg)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http"), func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return connections
+	}
+}
+
+func TestClient_Watch(t *testing.T) {
+	streamURL, connections := startStream(t,
+		`{"message_type": "heartbeat", "timestamp": 1700000000}`,
+		`{"message_type": "certificate_update", "data": {"leaf_cert": {"all_domains": ["acme.com", "*.acme.com"]}}}`,
+		`not json`,
+		`{"message_type": "dns_entries", "data": ["new.acme.com"]}`,
+	)
+
+	client := NewClient(&ClientConfig{StreamURL: streamURL, ReconnectDelay: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received [][]string
+	err := client.Watch(ctx, func(hostnames []string) {
+		received = append(received, hostnames)
+		// The server closes each stream after its messages, so the second
+		// batch of a reconnected stream ends the test
+		if len(received) == 4 {
+			cancel()
+		}
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	require.Len(t, received, 4)
+	assert.Equal(t, []string{"acme.com", "*.acme.com"}, received[0])
+	assert.Equal(t, []string{"new.acme.com"}, received[1])
+	assert.GreaterOrEqual(t, connections(), 2)
+}
+
+func TestParseMessage(t *testing.T) {
+	hostnames, err := parseMessage([]byte(`{"message_type": "certificate_update", "data": {"leaf_cert": {"all_domains": ["www.acme.com"]}, "seen": 1700000000.5}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"www.acme.com"}, hostnames)
+
+	hostnames, err = parseMessage([]byte(`{"message_type": "heartbeat"}`))
+	require.NoError(t, err)
+	assert.Empty(t, hostnames)
+
+	_, err = parseMessage([]byte(`{"message_type": "dns_entries", "data": {"unexpected": true}}`))
+	assert.Error(t, err)
+}
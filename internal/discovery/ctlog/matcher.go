@@ -0,0 +1,54 @@
+package ctlog
+
+import "strings"
+
+// Matcher finds the watched root domain a hostname is at or below
+type Matcher struct {
+	roots map[string]bool
+}
+
+// NewMatcher creates a matcher of root domains
+func NewMatcher(roots []string) *Matcher {
+	m := &Matcher{roots: make(map[string]bool, len(roots))}
+	for _, root := range roots {
+		if root = Normalize(root); root != "" {
+			m.roots[root] = true
+		}
+	}
+	return m
+}
+
+// Len returns the number of root domains matched
+func (m *Matcher) Len() int {
+	return len(m.roots)
+}
+
+// Match returns the closest root domain a normalized hostname is at or
+// below, checking the hostname and then each of its parents
+func (m *Matcher) Match(hostname string) (string, bool) {
+	for name := hostname; name != ""; {
+		if m.roots[name] {
+			return name, true
+		}
+		dot := strings.IndexByte(name, '.')
+		if dot < 0 {
+			break
+		}
+		name = name[dot+1:]
+	}
+	return "", false
+}
+
+// Normalize lowercases a logged hostname and strips its wildcard labels and
+// trailing dot, returning "" for names that are not hostnames, such as the
+// email addresses some certificates carry
+func Normalize(hostname string) string {
+	hostname = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+	for strings.HasPrefix(hostname, "*.") {
+		hostname = strings.TrimPrefix(hostname, "*.")
+	}
+	if hostname == "" || strings.ContainsAny(hostname, "@*/: ") || !strings.Contains(hostname, ".") {
+		return ""
+	}
+	return hostname
+}
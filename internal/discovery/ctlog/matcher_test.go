@@ -0,0 +1,42 @@
+package ctlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	matcher := NewMatcher([]string{"acme.com", "*.api.acme.com", "Example.ORG.", ""})
+	assert.Equal(t, 3, matcher.Len())
+
+	tests := []struct {
+		hostname string
+		root     string
+		ok       bool
+	}{
+		{"acme.com", "acme.com", true},
+		{"www.acme.com", "acme.com", true},
+		{"v2.api.acme.com", "api.acme.com", true},
+		{"deep.staging.example.org", "example.org", true},
+		{"notacme.com", "", false},
+		{"acme.com.evil.example", "", false},
+		{"com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			root, ok := matcher.Match(tt.hostname)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.root, root)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "www.acme.com", Normalize(" WWW.Acme.com. "))
+	assert.Equal(t, "acme.com", Normalize("*.*.acme.com"))
+	assert.Equal(t, "", Normalize("security@acme.com"))
+	assert.Equal(t, "", Normalize("localhost"))
+	assert.Equal(t, "", Normalize("*"))
+}
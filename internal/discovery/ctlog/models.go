@@ -0,0 +1,23 @@
+package ctlog
+
+import "encoding/json"
+
+// CertStream message types
+const (
+	messageCertificateUpdate = "certificate_update" // sent by the full stream
+	messageDNSEntries        = "dns_entries"        // sent by the domains-only stream
+)
+
+// message is a CertStream message. Full stream messages carry a
+// certificateUpdate in data, domains-only messages the hostnames themselves.
+type message struct {
+	MessageType string          `json:"message_type"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// certificateUpdate is the subset of a logged certificate that is used
+type certificateUpdate struct {
+	LeafCert struct {
+		AllDomains []string `json:"all_domains"` // subject common name and SANs
+	} `json:"leaf_cert"`
+}
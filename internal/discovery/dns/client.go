@@ -0,0 +1,219 @@
+// Package dns resolves the A, AAAA and CNAME records of asset hostnames. It
+// queries recursive resolvers directly instead of going through the system
+// resolver, so the CNAME chain a hostname went through is kept and a name
+// that does not exist is told apart from a resolver that failed.
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+)
+
+// resolvConf is where the system resolvers are read from when none are configured
+const resolvConf = "/etc/resolv.conf"
+
+// maxCNAMEs bounds the CNAME chain that is followed, against CNAME loops
+const maxCNAMEs = 10
+
+// ErrResolverFailed is returned when no resolver answered a query, or every
+// one answered with an error such as SERVFAIL
+var ErrResolverFailed = errors.New("resolver failed")
+
+// Client resolves hostnames against a list of recursive resolvers, trying
+// them in order until one answers
+type Client struct {
+	udp     *miekgdns.Client
+	tcp     *miekgdns.Client // for answers too large for UDP
+	servers []string         // host:port
+}
+
+// ClientConfig holds configuration for the DNS client
+type ClientConfig struct {
+	Servers []string // resolvers as host or host:port; those of /etc/resolv.conf when empty
+	Timeout time.Duration
+}
+
+// NewClient creates a new DNS client
+func NewClient(config *ClientConfig) (*Client, error) {
+	servers := make([]string, 0, len(config.Servers))
+	for _, server := range config.Servers {
+		servers = append(servers, ServerAddress(server))
+	}
+
+	if len(servers) == 0 {
+		system, err := miekgdns.ClientConfigFromFile(resolvConf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read system resolvers: %w", err)
+		}
+		for _, server := range system.Servers {
+			servers = append(servers, net.JoinHostPort(server, system.Port))
+		}
+		if len(servers) == 0 {
+			return nil, fmt.Errorf("no resolvers found in %s", resolvConf)
+		}
+	}
+
+	return &Client{
+		udp:     &miekgdns.Client{Net: "udp", Timeout: config.Timeout},
+		tcp:     &miekgdns.Client{Net: "tcp", Timeout: config.Timeout},
+		servers: servers,
+	}, nil
+}
+
+// ServerAddress returns a resolver address as host:port, adding the DNS port
+// when it has none
+func ServerAddress(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+}
+
+// Servers returns the resolvers queried, in the order they are tried
+func (c *Client) Servers() []string {
+	return c.servers
+}
+
+// Resolve looks up the A and AAAA records of a hostname and the CNAME chain
+// leading to them. A name that does not exist or has no addresses is
+// returned as dead; an error is only returned when the resolvers failed.
+func (c *Client) Resolve(ctx context.Context, host string) (*Result, error) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	result := &Result{Host: host}
+
+	ipv4, cnames, exists, err := c.lookup(ctx, host, miekgdns.TypeA)
+	if err != nil {
+		return nil, err
+	}
+	result.IPv4 = ipv4
+	result.CNAMEs = cnames
+
+	if exists {
+		ipv6, _, _, err := c.lookup(ctx, host, miekgdns.TypeAAAA)
+		if err != nil && len(ipv4) == 0 {
+			return nil, err
+		}
+		result.IPv6 = ipv6
+	}
+
+	result.Dead = len(result.IPv4) == 0 && len(result.IPv6) == 0
+	return result, nil
+}
+
+// ResolveAll resolves hostnames with up to concurrency lookups at a time,
+// returning one result per hostname in the same order. The results of
+// hostnames the resolvers failed on carry the error.
+func (c *Client) ResolveAll(ctx context.Context, hosts []string, concurrency int) []*Result {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]*Result, len(hosts))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(hosts)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				result, err := c.Resolve(ctx, hosts[i])
+				if err != nil {
+					result = &Result{Host: hosts[i], Err: err}
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+	for i := range hosts {
+		if ctx.Err() != nil {
+			results[i] = &Result{Host: hosts[i], Err: ctx.Err()}
+			continue
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
+
+// lookup queries the records of a type, returning the addresses, the CNAME
+// chain and whether the name exists
+func (c *Client) lookup(ctx context.Context, host string, qtype uint16) ([]string, []string, bool, error) {
+	response, err := c.exchange(ctx, host, qtype)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// Follow the chain from the queried name; recursive resolvers answer
+	// with every CNAME on the way to the addresses
+	name := miekgdns.Fqdn(host)
+	var cnames []string
+	for len(cnames) < maxCNAMEs {
+		target := ""
+		for _, rr := range response.Answer {
+			if cname, ok := rr.(*miekgdns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, name) {
+				target = cname.Target
+				break
+			}
+		}
+		if target == "" {
+			break
+		}
+		cnames = append(cnames, strings.TrimSuffix(strings.ToLower(target), "."))
+		name = target
+	}
+
+	var addresses []string
+	for _, rr := range response.Answer {
+		switch record := rr.(type) {
+		case *miekgdns.A:
+			addresses = append(addresses, record.A.String())
+		case *miekgdns.AAAA:
+			addresses = append(addresses, record.AAAA.String())
+		}
+	}
+
+	return addresses, cnames, response.Rcode != miekgdns.RcodeNameError, nil
+}
+
+// exchange sends a query to each resolver in turn until one answers with
+// NOERROR or NXDOMAIN
+func (c *Client) exchange(ctx context.Context, host string, qtype uint16) (*miekgdns.Msg, error) {
+	query := new(miekgdns.Msg)
+	query.SetQuestion(miekgdns.Fqdn(host), qtype)
+	query.SetEdns0(4096, false)
+
+	var lastErr error
+	for _, server := range c.servers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		response, _, err := c.udp.ExchangeContext(ctx, query, server)
+		if err == nil && response.Truncated {
+			response, _, err = c.tcp.ExchangeContext(ctx, query, server)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", server, err)
+			continue
+		}
+
+		switch response.Rcode {
+		case miekgdns.RcodeSuccess, miekgdns.RcodeNameError:
+			return response, nil
+		default:
+			lastErr = fmt.Errorf("%s answered %s", server, miekgdns.RcodeToString[response.Rcode])
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %s: %v", ErrResolverFailed, miekgdns.TypeToString[qtype], host, lastErr)
+}
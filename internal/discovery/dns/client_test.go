@@ -0,0 +1,153 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer runs a DNS server answering from a zone of resource records in
+// presentation format, or with rcode for names listed in failing
+func startServer(t *testing.T, zone []string, failing map[string]int) string {
+	records := make(map[string][]miekgdns.RR)
+	for _, line := range zone {
+		rr, err := miekgdns.NewRR(line)
+		require.NoError(t, err)
+		records[rr.Header().Name] = append(records[rr.Header().Name], rr)
+	}
+
+	handler := miekgdns.HandlerFunc(func(w miekgdns.ResponseWriter, r *miekgdns.Msg) {
+		response := new(miekgdns.Msg)
+		response.SetReply(r)
+		question := r.Question[0]
+
+		if rcode, ok := failing[question.Name]; ok {
+			response.Rcode = rcode
+			_ = w.WriteMsg(response)
+			return
+		}
+
+		// Answer like a recursive resolver: the CNAME chain, then the
+		// addresses of the name it ends at
+		name := question.Name
+		for {
+			rrs, ok := records[name]
+			if !ok {
+				if name == question.Name {
+					response.Rcode = miekgdns.RcodeNameError
+				}
+				break
+			}
+			next := ""
+			for _, rr := range rrs {
+				switch record := rr.(type) {
+				case *miekgdns.CNAME:
+					response.Answer = append(response.Answer, rr)
+					next = record.Target
+				default:
+					if rr.Header().Rrtype == question.Qtype {
+						response.Answer = append(response.Answer, rr)
+					}
+				}
+			}
+			if next == "" {
+				break
+			}
+			name = next
+		}
+		_ = w.WriteMsg(response)
+	})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &miekgdns.Server{PacketConn: conn, Handler: handler}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return conn.LocalAddr().String()
+}
+
+func newTestClient(t *testing.T, servers ...string) *Client {
+	client, err := NewClient(&ClientConfig{Servers: servers, Timeout: time.Second})
+	require.NoError(t, err)
+	return client
+}
+
+func TestResolve(t *testing.T) {
+	server := startServer(t, []string{
+		"api.acme.com. 60 IN A 192.0.2.10",
+		"api.acme.com. 60 IN AAAA 2001:db8::10",
+		"www.acme.com. 60 IN CNAME acme.cdn.example.",
+		"acme.cdn.example. 60 IN CNAME edge.cdn.example.",
+		"edge.cdn.example. 60 IN A 198.51.100.7",
+		"old.acme.com. 60 IN CNAME acme.herokuapp.example.",
+		"empty.acme.com. 60 IN TXT \"no addresses\"",
+	}, nil)
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	result, err := client.Resolve(ctx, "API.acme.com.")
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Host: "api.acme.com", IPv4: []string{"192.0.2.10"}, IPv6: []string{"2001:db8::10"}}, result)
+
+	result, err = client.Resolve(ctx, "www.acme.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme.cdn.example", "edge.cdn.example"}, result.CNAMEs)
+	assert.Equal(t, []string{"198.51.100.7"}, result.IPv4)
+	assert.False(t, result.Dead)
+
+	// A dangling CNAME keeps its chain, since it is what makes it interesting
+	result, err = client.Resolve(ctx, "old.acme.com")
+	require.NoError(t, err)
+	assert.True(t, result.Dead)
+	assert.Equal(t, []string{"acme.herokuapp.example"}, result.CNAMEs)
+
+	result, err = client.Resolve(ctx, "missing.acme.com")
+	require.NoError(t, err)
+	assert.True(t, result.Dead)
+
+	result, err = client.Resolve(ctx, "empty.acme.com")
+	require.NoError(t, err)
+	assert.True(t, result.Dead)
+}
+
+func TestResolve_FallsBackToNextResolver(t *testing.T) {
+	failing := startServer(t, nil, map[string]int{"api.acme.com.": miekgdns.RcodeServerFailure})
+	working := startServer(t, []string{"api.acme.com. 60 IN A 192.0.2.10"}, nil)
+
+	result, err := newTestClient(t, failing, working).Resolve(context.Background(), "api.acme.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.10"}, result.IPv4)
+
+	_, err = newTestClient(t, failing).Resolve(context.Background(), "api.acme.com")
+	assert.ErrorIs(t, err, ErrResolverFailed)
+	assert.Contains(t, err.Error(), "SERVFAIL")
+}
+
+func TestResolveAll(t *testing.T) {
+	server := startServer(t, []string{
+		"a.acme.com. 60 IN A 192.0.2.1",
+		"b.acme.com. 60 IN A 192.0.2.2",
+	}, map[string]int{"c.acme.com.": miekgdns.RcodeRefused})
+
+	results := newTestClient(t, server).ResolveAll(context.Background(), []string{"a.acme.com", "b.acme.com", "c.acme.com", "d.acme.com"}, 2)
+	require.Len(t, results, 4)
+	assert.Equal(t, []string{"192.0.2.1"}, results[0].IPv4)
+	assert.Equal(t, []string{"192.0.2.2"}, results[1].IPv4)
+	assert.ErrorIs(t, results[2].Err, ErrResolverFailed)
+	assert.Equal(t, "c.acme.com", results[2].Host)
+	assert.True(t, results[3].Dead)
+	assert.NoError(t, results[3].Err)
+}
+
+func TestServerAddress(t *testing.T) {
+	assert.Equal(t, "1.1.1.1:53", ServerAddress("1.1.1.1"))
+	assert.Equal(t, "1.1.1.1:5353", ServerAddress("1.1.1.1:5353"))
+	assert.Equal(t, "[2606:4700:4700::1111]:53", ServerAddress("2606:4700:4700::1111"))
+	assert.Equal(t, "[2606:4700:4700::1111]:53", ServerAddress("[2606:4700:4700::1111]"))
+}
@@ -0,0 +1,11 @@
+package dns
+
+// Result holds what a hostname resolved to
+type Result struct {
+	Host   string   `json:"host"`
+	IPv4   []string `json:"ipv4,omitempty"`
+	IPv6   []string `json:"ipv6,omitempty"`
+	CNAMEs []string `json:"cnames,omitempty"` // CNAME chain from the host to the name holding its addresses, in the order it was followed
+	Dead   bool     `json:"dead"`             // the name does not exist (NXDOMAIN) or has no A or AAAA records
+	Err    error    `json:"-"`                // set by ResolveAll when the resolvers failed; nothing is known about the host then
+}
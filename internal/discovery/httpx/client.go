@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/monitor-agent/internal/utils"
+	"github.com/monitor-agent/internal/version"
+	"github.com/projectdiscovery/httpx/common/customheader"
 	"github.com/projectdiscovery/httpx/runner"
 	"github.com/sirupsen/logrus"
 )
@@ -24,8 +27,10 @@ type ProbeResult struct {
 // DetailedProbeResult represents a detailed probe result with full response information
 type DetailedProbeResult struct {
 	URL          string            `json:"url"`
+	Method       string            `json:"method,omitempty"` // request method; GET when empty
 	StatusCode   int               `json:"status_code"`
-	Exists       bool              `json:"exists"`
+	Exists       bool              `json:"exists"` // returned an HTTP response (live or waf-blocked)
+	Liveness     string            `json:"liveness"`
 	Error        string            `json:"error,omitempty"`
 	Headers      map[string]string `json:"headers,omitempty"`
 	Body         string            `json:"body,omitempty"`
@@ -34,8 +39,47 @@ type DetailedProbeResult struct {
 	Server       string            `json:"server,omitempty"`
 	Title        string            `json:"title,omitempty"`
 	Technologies []string          `json:"technologies,omitempty"`
+	TLS          *TLSInfo          `json:"tls,omitempty"` // nil for plain http or when TLS checks are disabled
+
+	// Redirects behind the final response: the status of the first response,
+	// the number of redirects followed, the URL they ended at and how the chain
+	// ended (see the Redirect* states). MetaRefresh is the target of a meta
+	// refresh tag in the final body.
+	InitialStatusCode int    `json:"initial_status_code,omitempty"`
+	RedirectHops      int    `json:"redirect_hops,omitempty"`
+	FinalURL          string `json:"final_url,omitempty"`
+	RedirectStatus    string `json:"redirect_status,omitempty"`
+	MetaRefresh       string `json:"meta_refresh,omitempty"`
+
+	// IP is the address this result was probed over and IPFamily is its family
+	IP       string `json:"ip,omitempty"`
+	IPFamily string `json:"ip_family,omitempty"`
+
+	// Per-family addresses and reachability; nil reachability means the family was not probed
+	IPv4          string `json:"ipv4,omitempty"`
+	IPv6          string `json:"ipv6,omitempty"`
+	IPv4Reachable *bool  `json:"ipv4_reachable,omitempty"`
+	IPv6Reachable *bool  `json:"ipv6_reachable,omitempty"`
+
+	// Region is the vantage point this result was probed from and ReachableFrom
+	// lists every region that reached the URL; both are empty for local-only probes
+	Region        string   `json:"region,omitempty"`
+	ReachableFrom []string `json:"reachable_from,omitempty"`
 }
 
+// IP versions supported by the probe
+const (
+	IPVersion4    = "ipv4" // Probe whichever address the dialer picks (default)
+	IPVersion6    = "ipv6" // Probe every AAAA record; only IPv6 reachability counts
+	IPVersionDual = "dual" // Probe every A and AAAA record and record both families
+)
+
+// IP families reported on probe results
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
 // ProbeConfig holds configuration for the HTTPX probe
 type ProbeConfig struct {
 	Timeout         time.Duration // Per-URL timeout
@@ -45,6 +89,8 @@ type ProbeConfig struct {
 	FollowRedirects bool
 	MaxRedirects    int
 	Debug           bool
+	IPVersion       string // ipv4, ipv6 or dual
+	TLSChecks       bool   // grab the TLS handshake of https probes for TLSInfo
 }
 
 // Client represents an HTTPX probe client
@@ -60,7 +106,7 @@ func NewClient(config *ProbeConfig) *Client {
 			Timeout:         30 * time.Second,
 			Concurrency:     25,
 			RateLimit:       50,
-			UserAgent:       "Monitor-Agent/1.0",
+			UserAgent:       version.UserAgent(),
 			FollowRedirects: true,
 			MaxRedirects:    3,
 			Debug:           false,
@@ -96,6 +142,7 @@ func (c *Client) ProbeDomains(ctx context.Context, domains []string) ([]ProbeRes
 	// Create HTTPX runner options with more conservative settings for reliability
 	options := &runner.Options{
 		InputTargetHost: urls,
+		CustomHeaders:   customheader.CustomHeaders(HeadersFromContext(ctx)),
 		RateLimit:       c.config.RateLimit,
 		Threads:         c.config.Concurrency,
 		Timeout:         int(c.config.Timeout.Seconds()),
@@ -210,20 +257,38 @@ func (c *Client) ProbeDomainsWithDetails(ctx context.Context, domains []string)
 		return []DetailedProbeResult{}, nil
 	}
 
+	return c.ProbeURLsWithDetails(ctx, urls)
+}
+
+// ProbeURLsWithDetails probes URLs as given, without the domain validation of
+// ProbeDomainsWithDetails, so URLs with ports and paths can be probed
+func (c *Client) ProbeURLsWithDetails(ctx context.Context, urls []string) ([]DetailedProbeResult, error) {
+	if len(urls) == 0 {
+		return []DetailedProbeResult{}, nil
+	}
+
 	// Collect results as they arrive
 	var results []DetailedProbeResult
 	var mu sync.Mutex // Protect concurrent access to results slice
 
 	logrus.Infof("Creating HTTPX runner with %d URLs for detailed probing", len(urls))
 
+	// HEAD probes come back without a body
+	method := MethodFromContext(ctx)
+	handle := ResultHandlerFromContext(ctx)
+
 	// Create HTTPX runner options with more conservative settings for reliability
 	options := &runner.Options{
 		InputTargetHost: urls,
+		Methods:         method,
+		CustomHeaders:   customheader.CustomHeaders(HeadersFromContext(ctx)),
 		RateLimit:       c.config.RateLimit,
 		Threads:         c.config.Concurrency,
 		Timeout:         int(c.config.Timeout.Seconds()),
 		FollowRedirects: c.config.FollowRedirects,
 		MaxRedirects:    c.config.MaxRedirects,
+		ProbeAllIPS:     c.probesAllFamilies(),
+		TLSGrab:         c.config.TLSChecks,
 		Silent:          true,
 		NoColor:         true,
 		JSONOutput:      false,
@@ -236,8 +301,11 @@ func (c *Client) ProbeDomainsWithDetails(ctx context.Context, domains []string)
 			// Process result immediately as it arrives
 			detailedResult := DetailedProbeResult{
 				URL:        result.URL,
+				Method:     method,
 				Exists:     result.StatusCode > 0,
 				StatusCode: result.StatusCode,
+				IP:         result.Host,
+				IPFamily:   ipFamily(result.Host),
 			}
 
 			if result.StatusCode > 0 {
@@ -269,6 +337,8 @@ func (c *Client) ProbeDomainsWithDetails(ctx context.Context, domains []string)
 				detailedResult.Server = result.WebServer
 				detailedResult.Title = result.Title
 				detailedResult.Technologies = result.Technologies
+				detailedResult.TLS = newTLSInfo(result.TLSData)
+				c.applyRedirects(&detailedResult, result.ChainStatusCodes, result.FinalURL, result.Location)
 
 				// Log basic result information
 				if c.config.Debug {
@@ -284,6 +354,7 @@ func (c *Client) ProbeDomainsWithDetails(ctx context.Context, domains []string)
 					detailedResult.Error = "Domain does not exist or is unreachable"
 				}
 			}
+			detailedResult.Liveness = ClassifyLiveness(&detailedResult, result.Host != "" || len(result.A) > 0 || len(result.AAAA) > 0)
 
 			// Thread-safe append to results
 			mu.Lock()
@@ -291,6 +362,10 @@ func (c *Client) ProbeDomainsWithDetails(ctx context.Context, domains []string)
 			currentCount := len(results)
 			mu.Unlock()
 
+			if handle != nil {
+				handle(detailedResult)
+			}
+
 			// Log progress in real-time (use debug level for individual results)
 			if c.config.Debug {
 				logrus.Debugf("Detailed result %d/%d: %s (status: %d, exists: %v)",
@@ -348,10 +423,130 @@ func (c *Client) ProbeDomainsWithDetails(ctx context.Context, domains []string)
 		}
 	}
 
-	logrus.Infof("Detailed HTTPX probe completed: %d/%d domains exist (collected %d results)",
-		existingCount, len(domains), len(results))
+	logrus.Infof("Detailed HTTPX probe completed: %d/%d URLs exist (collected %d results)",
+		existingCount, len(urls), len(results))
 
-	return results, nil
+	mu.Lock()
+	merged := mergeFamilyResults(results, c.config.IPVersion)
+	mu.Unlock()
+
+	return merged, nil
+}
+
+// probesAllFamilies reports whether every resolved address should be probed
+func (c *Client) probesAllFamilies() bool {
+	return c.config.IPVersion == IPVersion6 || c.config.IPVersion == IPVersionDual
+}
+
+// ipFamily returns the address family of an IP, or "" if it is not an IP
+func ipFamily(ip string) string {
+	parsed := net.ParseIP(strings.Trim(ip, "[]"))
+	switch {
+	case parsed == nil:
+		return ""
+	case parsed.To4() != nil:
+		return FamilyIPv4
+	default:
+		return FamilyIPv6
+	}
+}
+
+// mergeFamilyResults collapses per-address results into one result per URL,
+// recording the address and reachability of each family. The representative
+// result is the first reachable one in the preferred family for ipVersion.
+func mergeFamilyResults(results []DetailedProbeResult, ipVersion string) []DetailedProbeResult {
+	var order []string
+	grouped := make(map[string][]DetailedProbeResult)
+	for _, result := range results {
+		if _, ok := grouped[result.URL]; !ok {
+			order = append(order, result.URL)
+		}
+		grouped[result.URL] = append(grouped[result.URL], result)
+	}
+
+	preferred := FamilyIPv4
+	if ipVersion == IPVersion6 {
+		preferred = FamilyIPv6
+	}
+
+	merged := make([]DetailedProbeResult, 0, len(order))
+	for _, url := range order {
+		group := grouped[url]
+
+		var ipv4, ipv6 string
+		var ipv4Reachable, ipv6Reachable *bool
+		for _, result := range group {
+			reachable := result.Exists
+			switch result.IPFamily {
+			case FamilyIPv4:
+				if ipv4 == "" || (reachable && (ipv4Reachable == nil || !*ipv4Reachable)) {
+					ipv4 = result.IP
+				}
+				ipv4Reachable = orBool(ipv4Reachable, reachable)
+			case FamilyIPv6:
+				if ipv6 == "" || (reachable && (ipv6Reachable == nil || !*ipv6Reachable)) {
+					ipv6 = result.IP
+				}
+				ipv6Reachable = orBool(ipv6Reachable, reachable)
+			}
+		}
+
+		best := pickRepresentative(group, preferred, ipVersion == IPVersion6)
+		best.IPv4 = ipv4
+		best.IPv6 = ipv6
+		best.IPv4Reachable = ipv4Reachable
+		best.IPv6Reachable = ipv6Reachable
+		merged = append(merged, best)
+	}
+
+	return merged
+}
+
+// pickRepresentative chooses the result that stands for a URL. When
+// onlyPreferred is set, results outside the preferred family never count as reachable.
+func pickRepresentative(group []DetailedProbeResult, preferred string, onlyPreferred bool) DetailedProbeResult {
+	for _, result := range group {
+		if result.Exists && result.IPFamily == preferred {
+			return result
+		}
+	}
+
+	if !onlyPreferred {
+		for _, result := range group {
+			if result.Exists {
+				return result
+			}
+		}
+		return mostAlive(group)
+	}
+
+	best := mostAlive(group)
+	if best.Exists {
+		best = DetailedProbeResult{URL: best.URL, IP: best.IP, IPFamily: best.IPFamily, Liveness: LivenessError}
+	}
+	best.Exists = false
+	best.Error = "not reachable over " + preferred
+	return best
+}
+
+// mostAlive returns the first result with the most alive liveness state
+func mostAlive(group []DetailedProbeResult) DetailedProbeResult {
+	best := group[0]
+	for _, result := range group[1:] {
+		if MoreAlive(result.Liveness, best.Liveness) {
+			best = result
+		}
+	}
+	return best
+}
+
+// orBool combines a reachability observation with any previous one
+func orBool(current *bool, value bool) *bool {
+	result := value
+	if current != nil {
+		result = *current || value
+	}
+	return &result
 }
 
 // ToJSON converts a DetailedProbeResult to JSON string
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/monitor-agent/internal/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -23,7 +24,7 @@ func TestNewClient(t *testing.T) {
 					Timeout:         30 * time.Second,
 					Concurrency:     25,
 					RateLimit:       50,
-					UserAgent:       "Monitor-Agent/1.0",
+					UserAgent:       version.UserAgent(),
 					FollowRedirects: true,
 					MaxRedirects:    3,
 				},
@@ -444,3 +445,68 @@ func TestProbeDomainsWithDetails_ContextCancellation(t *testing.T) {
 		assert.NotNil(t, results)
 	}
 }
+
+func TestIPFamily(t *testing.T) {
+	assert.Equal(t, FamilyIPv4, ipFamily("93.184.216.34"))
+	assert.Equal(t, FamilyIPv6, ipFamily("2606:2800:220:1:248:1893:25c8:1946"))
+	assert.Equal(t, FamilyIPv6, ipFamily("[2001:db8::1]"))
+	assert.Equal(t, "", ipFamily("example.com"))
+	assert.Equal(t, "", ipFamily(""))
+}
+
+func TestMergeFamilyResults(t *testing.T) {
+	results := []DetailedProbeResult{
+		{URL: "https://a.example.com", Exists: true, StatusCode: 200, IP: "192.0.2.1", IPFamily: FamilyIPv4},
+		{URL: "https://a.example.com", Exists: false, IP: "2001:db8::1", IPFamily: FamilyIPv6, Error: "timeout"},
+		{URL: "https://b.example.com", Exists: false, IP: "192.0.2.2", IPFamily: FamilyIPv4},
+		{URL: "https://b.example.com", Exists: true, StatusCode: 403, IP: "2001:db8::2", IPFamily: FamilyIPv6},
+		{URL: "https://c.example.com", Exists: false, Error: "no such host"},
+	}
+
+	t.Run("dual stack", func(t *testing.T) {
+		merged := mergeFamilyResults(results, IPVersionDual)
+		require.Len(t, merged, 3)
+
+		a := merged[0]
+		assert.True(t, a.Exists)
+		assert.Equal(t, 200, a.StatusCode)
+		assert.Equal(t, "192.0.2.1", a.IPv4)
+		assert.Equal(t, "2001:db8::1", a.IPv6)
+		require.NotNil(t, a.IPv4Reachable)
+		require.NotNil(t, a.IPv6Reachable)
+		assert.True(t, *a.IPv4Reachable)
+		assert.False(t, *a.IPv6Reachable)
+
+		// Only reachable over IPv6, which still counts in dual mode
+		b := merged[1]
+		assert.True(t, b.Exists)
+		assert.Equal(t, 403, b.StatusCode)
+		assert.False(t, *b.IPv4Reachable)
+		assert.True(t, *b.IPv6Reachable)
+
+		c := merged[2]
+		assert.False(t, c.Exists)
+		assert.Nil(t, c.IPv4Reachable)
+		assert.Nil(t, c.IPv6Reachable)
+	})
+
+	t.Run("ipv6 only", func(t *testing.T) {
+		merged := mergeFamilyResults(results, IPVersion6)
+		require.Len(t, merged, 3)
+
+		// Reachable only over IPv4, so it does not exist for an IPv6-only probe
+		assert.False(t, merged[0].Exists)
+		assert.True(t, *merged[0].IPv4Reachable)
+		assert.Contains(t, merged[0].Error, "ipv6")
+
+		assert.True(t, merged[1].Exists)
+		assert.Equal(t, "2001:db8::2", merged[1].IP)
+	})
+
+	t.Run("single ipv4 result", func(t *testing.T) {
+		merged := mergeFamilyResults(results[:1], IPVersion4)
+		require.Len(t, merged, 1)
+		assert.True(t, *merged[0].IPv4Reachable)
+		assert.Nil(t, merged[0].IPv6Reachable)
+	})
+}
@@ -0,0 +1,22 @@
+package httpx
+
+import "context"
+
+// headersKey is the context key of the extra request headers of a probe
+type headersKey struct{}
+
+// WithHeaders returns a context whose probes send extra "Name: value" request
+// headers, such as a program's auth profile. Only local probes send them;
+// remote probe workers are never given the headers.
+func WithHeaders(ctx context.Context, headers []string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// HeadersFromContext returns the extra request headers set with WithHeaders
+func HeadersFromContext(ctx context.Context) []string {
+	headers, _ := ctx.Value(headersKey{}).([]string)
+	return headers
+}
@@ -0,0 +1,120 @@
+package httpx
+
+import (
+	"strings"
+)
+
+// Liveness states of a probed URL. Only live and waf-blocked URLs returned an
+// HTTP response; the others distinguish hosts that are truly dead from those
+// that exist but did not answer our prober.
+const (
+	LivenessLive       = "live"        // returned an HTTP response
+	LivenessWAFBlocked = "waf-blocked" // a WAF or bot protection answered instead of the application
+	LivenessRefused    = "refused"     // the connection was refused or reset
+	LivenessTimedOut   = "timed-out"   // the connection or response timed out
+	LivenessDNSOnly    = "dns-only"    // the name resolves but nothing answered over HTTP
+	LivenessError      = "error"       // any other failure, including names that do not resolve
+)
+
+// livenessRank orders states from most to least alive
+var livenessRank = map[string]int{
+	LivenessLive:       6,
+	LivenessWAFBlocked: 5,
+	LivenessRefused:    4,
+	LivenessTimedOut:   3,
+	LivenessDNSOnly:    2,
+	LivenessError:      1,
+}
+
+// MoreAlive reports whether liveness state a is more alive than b
+func MoreAlive(a, b string) bool {
+	return livenessRank[a] > livenessRank[b]
+}
+
+// HostExists reports whether the result shows the host exists, even if it did
+// not answer. Results without a liveness state, e.g. from older probe workers,
+// fall back to Exists.
+func (r *DetailedProbeResult) HostExists() bool {
+	if r.Liveness == "" {
+		return r.Exists
+	}
+	return r.Liveness != LivenessError
+}
+
+// wafStatusCodes are the status codes WAFs and bot protection block with
+var wafStatusCodes = map[int]bool{403: true, 405: true, 406: true, 429: true, 503: true}
+
+// wafHeaderMarkers are lowercase "name: value" fragments set by WAF block pages
+var wafHeaderMarkers = []string{
+	"cf-mitigated: challenge",
+	"server: cloudflare",
+	"server: akamaighost",
+	"server: awselb",
+	"x-sucuri-id:",
+	"x-iinfo:",
+	"x-datadome:",
+	"server: ddos-guard",
+}
+
+// wafBodyMarkers are lowercase fragments of WAF block and challenge pages
+var wafBodyMarkers = []string{
+	"attention required! | cloudflare",
+	"/cdn-cgi/challenge-platform",
+	"incapsula incident id",
+	"request unsuccessful. incapsula",
+	"access denied</title>",
+	"the requested url was rejected",
+	"sucuri website firewall",
+	"request blocked",
+	"captcha-delivery.com",
+	"aws waf",
+}
+
+// ClassifyLiveness derives the liveness state of a probe result. resolved
+// reports whether the host's name resolved, when the result has no response.
+func ClassifyLiveness(result *DetailedProbeResult, resolved bool) string {
+	if result.StatusCode > 0 {
+		if isWAFBlocked(result) {
+			return LivenessWAFBlocked
+		}
+		return LivenessLive
+	}
+
+	errText := strings.ToLower(result.Error)
+	switch {
+	case strings.Contains(errText, "timeout") || strings.Contains(errText, "deadline exceeded") || strings.Contains(errText, "timed out"):
+		return LivenessTimedOut
+	case strings.Contains(errText, "connection refused") || strings.Contains(errText, "connection reset"):
+		return LivenessRefused
+	case strings.Contains(errText, "no such host") || strings.Contains(errText, "no address"):
+		return LivenessError
+	case resolved:
+		return LivenessDNSOnly
+	default:
+		return LivenessError
+	}
+}
+
+// isWAFBlocked reports whether a response looks like a WAF block or challenge page
+func isWAFBlocked(result *DetailedProbeResult) bool {
+	if !wafStatusCodes[result.StatusCode] {
+		return false
+	}
+
+	for name, value := range result.Headers {
+		line := strings.ToLower(name + ": " + value)
+		for _, marker := range wafHeaderMarkers {
+			if strings.HasPrefix(line, marker) {
+				return true
+			}
+		}
+	}
+
+	body := strings.ToLower(result.Body)
+	for _, marker := range wafBodyMarkers {
+		if strings.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
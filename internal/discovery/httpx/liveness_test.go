@@ -0,0 +1,43 @@
+package httpx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyLiveness(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   DetailedProbeResult
+		resolved bool
+		want     string
+	}{
+		{"live", DetailedProbeResult{StatusCode: 200}, true, LivenessLive},
+		{"plain forbidden", DetailedProbeResult{StatusCode: 403, Headers: map[string]string{"Server": "nginx"}}, true, LivenessLive},
+		{"cloudflare challenge", DetailedProbeResult{StatusCode: 403, Headers: map[string]string{"Server": "cloudflare", "Cf-Mitigated": "challenge"}}, true, LivenessWAFBlocked},
+		{"akamai block page", DetailedProbeResult{StatusCode: 403, Body: "<HTML><HEAD><TITLE>Access Denied</TITLE></HEAD>"}, true, LivenessWAFBlocked},
+		{"cloudflare 200", DetailedProbeResult{StatusCode: 200, Headers: map[string]string{"Server": "cloudflare"}}, true, LivenessLive},
+		{"timeout", DetailedProbeResult{Error: "context deadline exceeded (Client.Timeout exceeded while awaiting headers)"}, true, LivenessTimedOut},
+		{"refused", DetailedProbeResult{Error: "dial tcp 192.0.2.1:443: connect: connection refused"}, true, LivenessRefused},
+		{"nxdomain", DetailedProbeResult{Error: "dial tcp: lookup x.example.com: no such host"}, false, LivenessError},
+		{"resolved without answer", DetailedProbeResult{Error: "Domain does not exist or is unreachable"}, true, LivenessDNSOnly},
+		{"unknown", DetailedProbeResult{Error: "Domain does not exist or is unreachable"}, false, LivenessError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyLiveness(&tt.result, tt.resolved))
+		})
+	}
+}
+
+func TestMoreAlive(t *testing.T) {
+	assert.True(t, MoreAlive(LivenessLive, LivenessWAFBlocked))
+	assert.True(t, MoreAlive(LivenessTimedOut, LivenessError))
+	assert.False(t, MoreAlive(LivenessError, LivenessDNSOnly))
+
+	assert.False(t, (&DetailedProbeResult{Liveness: LivenessError}).HostExists())
+	assert.True(t, (&DetailedProbeResult{Liveness: LivenessTimedOut}).HostExists())
+	assert.True(t, (&DetailedProbeResult{Exists: true}).HostExists())
+}
@@ -0,0 +1,46 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Request methods probes can be sent with. GET captures the response body for
+// triage; HEAD only establishes liveness, status and headers, so it is the
+// lighter choice for frequent sweeps.
+const (
+	MethodGET  = http.MethodGet
+	MethodHEAD = http.MethodHead
+)
+
+// methodKey is the context key of the request method of a probe
+type methodKey struct{}
+
+// ValidMethod reports whether a probe method is supported; empty means GET
+func ValidMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "", MethodGET, MethodHEAD:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithMethod returns a context whose probes use a request method, GET or
+// HEAD. Remote probe workers are sent the method along with the batch.
+func WithMethod(ctx context.Context, method string) context.Context {
+	method = strings.ToUpper(method)
+	if method == "" || method == MethodGET {
+		return ctx
+	}
+	return context.WithValue(ctx, methodKey{}, method)
+}
+
+// MethodFromContext returns the probe method set with WithMethod, GET by default
+func MethodFromContext(ctx context.Context) string {
+	if method, ok := ctx.Value(methodKey{}).(string); ok {
+		return method
+	}
+	return MethodGET
+}
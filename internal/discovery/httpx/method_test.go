@@ -0,0 +1,20 @@
+package httpx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMethod(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, MethodGET, MethodFromContext(ctx))
+	assert.Equal(t, ctx, WithMethod(ctx, ""))
+	assert.Equal(t, ctx, WithMethod(ctx, "get"))
+	assert.Equal(t, MethodHEAD, MethodFromContext(WithMethod(ctx, "head")))
+
+	assert.True(t, ValidMethod(""))
+	assert.True(t, ValidMethod("Head"))
+	assert.False(t, ValidMethod("POST"))
+}
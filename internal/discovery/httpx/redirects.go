@@ -0,0 +1,147 @@
+package httpx
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redirect states of a probe result, describing how the redirects in front of
+// the final response ended. Results that were not redirected have none.
+const (
+	RedirectFollowed    = "followed"     // redirects were followed to a final response
+	RedirectLimit       = "limit"        // the prober stopped at the redirect limit while still redirecting
+	RedirectLoop        = "loop"         // the chain points back to a URL it already visited
+	RedirectNotFollowed = "not-followed" // a redirect response, with redirect following disabled
+	RedirectMetaRefresh = "meta-refresh" // the final response redirects with a meta refresh tag
+)
+
+var (
+	// metaTagPattern matches HTML meta tags
+	metaTagPattern = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	// metaRefreshPattern matches the http-equiv attribute of a meta refresh tag
+	metaRefreshPattern = regexp.MustCompile(`(?i)http-equiv\s*=\s*["']?\s*refresh\b`)
+	// metaContentPattern captures the content attribute of a meta tag
+	metaContentPattern = regexp.MustCompile(`(?i)\bcontent\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// applyRedirects records the redirects behind a result's final response. chain
+// holds the status code of every response in the redirect chain, first to
+// last, finalURL is where the chain ended and location is the Location header
+// of the final response.
+func (c *Client) applyRedirects(result *DetailedProbeResult, chain []int, finalURL, location string) {
+	if result.StatusCode <= 0 {
+		return
+	}
+
+	result.InitialStatusCode = result.StatusCode
+	if len(chain) > 1 {
+		result.InitialStatusCode = chain[0]
+		result.RedirectHops = len(chain) - 1
+		result.FinalURL = finalURL
+	}
+
+	current := result.URL
+	if result.FinalURL != "" {
+		current = result.FinalURL
+	}
+	visited := map[string]bool{normalizeRedirectURL(result.URL): true, normalizeRedirectURL(current): true}
+
+	switch {
+	case isRedirectStatus(result.StatusCode) && location != "":
+		target := resolveRedirect(current, location)
+		switch {
+		case !c.config.FollowRedirects:
+			result.RedirectStatus = RedirectNotFollowed
+		case visited[normalizeRedirectURL(target)]:
+			result.RedirectStatus = RedirectLoop
+		default:
+			result.RedirectStatus = RedirectLimit
+		}
+	default:
+		if target := MetaRefreshTarget(result.Body); target != "" {
+			result.MetaRefresh = resolveRedirect(current, target)
+			if visited[normalizeRedirectURL(result.MetaRefresh)] {
+				result.RedirectStatus = RedirectLoop
+			} else {
+				result.RedirectStatus = RedirectMetaRefresh
+			}
+		} else if result.RedirectHops > 0 {
+			result.RedirectStatus = RedirectFollowed
+		}
+	}
+}
+
+// MetaRefreshTarget returns the URL a page redirects to with a meta refresh
+// tag, or "" if it has none. Refreshes without a URL reload the page itself
+// and are not redirects.
+func MetaRefreshTarget(body string) string {
+	for _, tag := range metaTagPattern.FindAllString(body, -1) {
+		if !metaRefreshPattern.MatchString(tag) {
+			continue
+		}
+
+		match := metaContentPattern.FindStringSubmatch(tag)
+		if match == nil {
+			continue
+		}
+		content := match[1] + match[2] + match[3]
+
+		// content is "<delay>; url=<target>", with the url= prefix optional
+		_, target, found := strings.Cut(content, ";")
+		if !found {
+			_, target, found = strings.Cut(content, ",")
+		}
+		if !found {
+			continue
+		}
+		target = strings.TrimSpace(target)
+		if len(target) >= 4 && strings.EqualFold(target[:3], "url") {
+			if rest := strings.TrimSpace(target[3:]); strings.HasPrefix(rest, "=") {
+				target = strings.TrimSpace(rest[1:])
+			}
+		}
+		target = strings.Trim(target, `"'`)
+		if target != "" {
+			return target
+		}
+	}
+	return ""
+}
+
+// isRedirectStatus reports whether a status code redirects with a Location header
+func isRedirectStatus(statusCode int) bool {
+	switch statusCode {
+	case 301, 302, 303, 307, 308:
+		return true
+	}
+	return false
+}
+
+// resolveRedirect resolves a possibly relative redirect target against the URL
+// it was returned for
+func resolveRedirect(base, target string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return target
+	}
+	targetURL, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return target
+	}
+	return baseURL.ResolveReference(targetURL).String()
+}
+
+// normalizeRedirectURL makes URLs in a redirect chain comparable
+func normalizeRedirectURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.ToLower(rawURL)
+	}
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Fragment = ""
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+	return parsed.String()
+}
@@ -0,0 +1,126 @@
+package httpx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_applyRedirects(t *testing.T) {
+	following := &Client{config: &ProbeConfig{FollowRedirects: true, MaxRedirects: 3}}
+	notFollowing := &Client{config: &ProbeConfig{}}
+
+	tests := []struct {
+		name     string
+		client   *Client
+		result   DetailedProbeResult
+		chain    []int
+		finalURL string
+		location string
+		want     DetailedProbeResult
+	}{
+		{
+			name:   "no redirect",
+			client: following,
+			result: DetailedProbeResult{URL: "https://example.com", StatusCode: 200},
+			want:   DetailedProbeResult{InitialStatusCode: 200},
+		},
+		{
+			name:     "followed",
+			client:   following,
+			result:   DetailedProbeResult{URL: "https://example.com", StatusCode: 200},
+			chain:    []int{301, 302, 200},
+			finalURL: "https://www.example.com/login",
+			want:     DetailedProbeResult{InitialStatusCode: 301, RedirectHops: 2, FinalURL: "https://www.example.com/login", RedirectStatus: RedirectFollowed},
+		},
+		{
+			name:     "limit",
+			client:   following,
+			result:   DetailedProbeResult{URL: "https://example.com", StatusCode: 302},
+			chain:    []int{302, 302, 302, 302},
+			finalURL: "https://example.com/c",
+			location: "/d",
+			want:     DetailedProbeResult{InitialStatusCode: 302, RedirectHops: 3, FinalURL: "https://example.com/c", RedirectStatus: RedirectLimit},
+		},
+		{
+			name:     "loop back to the start",
+			client:   following,
+			result:   DetailedProbeResult{URL: "https://example.com", StatusCode: 302},
+			chain:    []int{302, 302, 302, 302},
+			finalURL: "https://example.com/login",
+			location: "https://EXAMPLE.com/",
+			want:     DetailedProbeResult{InitialStatusCode: 302, RedirectHops: 3, FinalURL: "https://example.com/login", RedirectStatus: RedirectLoop},
+		},
+		{
+			name:     "self loop",
+			client:   following,
+			result:   DetailedProbeResult{URL: "https://example.com/a", StatusCode: 307},
+			chain:    []int{307, 307, 307, 307},
+			finalURL: "https://example.com/a",
+			location: "/a",
+			want:     DetailedProbeResult{InitialStatusCode: 307, RedirectHops: 3, FinalURL: "https://example.com/a", RedirectStatus: RedirectLoop},
+		},
+		{
+			name:     "not followed",
+			client:   notFollowing,
+			result:   DetailedProbeResult{URL: "http://example.com", StatusCode: 301},
+			location: "https://example.com/",
+			want:     DetailedProbeResult{InitialStatusCode: 301, RedirectStatus: RedirectNotFollowed},
+		},
+		{
+			name:   "meta refresh",
+			client: following,
+			result: DetailedProbeResult{URL: "https://example.com", StatusCode: 200,
+				Body: `<html><head><meta http-equiv="refresh" content="0; URL='/portal/'"></head></html>`},
+			want: DetailedProbeResult{InitialStatusCode: 200, MetaRefresh: "https://example.com/portal/", RedirectStatus: RedirectMetaRefresh},
+		},
+		{
+			name:     "meta refresh loop",
+			client:   following,
+			result:   DetailedProbeResult{URL: "https://example.com", StatusCode: 200, Body: `<meta content="1;url=https://example.com/start" http-equiv=Refresh>`},
+			chain:    []int{302, 200},
+			finalURL: "https://example.com/start",
+			want:     DetailedProbeResult{InitialStatusCode: 302, RedirectHops: 1, FinalURL: "https://example.com/start", MetaRefresh: "https://example.com/start", RedirectStatus: RedirectLoop},
+		},
+		{
+			name:   "no response",
+			client: following,
+			result: DetailedProbeResult{URL: "https://example.com", Error: "connection refused"},
+			want:   DetailedProbeResult{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.result
+			tt.client.applyRedirects(&result, tt.chain, tt.finalURL, tt.location)
+
+			assert.Equal(t, tt.want.InitialStatusCode, result.InitialStatusCode)
+			assert.Equal(t, tt.want.RedirectHops, result.RedirectHops)
+			assert.Equal(t, tt.want.FinalURL, result.FinalURL)
+			assert.Equal(t, tt.want.RedirectStatus, result.RedirectStatus)
+			assert.Equal(t, tt.want.MetaRefresh, result.MetaRefresh)
+		})
+	}
+}
+
+func TestMetaRefreshTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"double quoted", `<meta http-equiv="refresh" content="5;url=https://example.com/next">`, "https://example.com/next"},
+		{"content first", `<META CONTENT='0; URL=/home' HTTP-EQUIV='Refresh'>`, "/home"},
+		{"without url prefix", `<meta http-equiv="refresh" content="0; /landing">`, "/landing"},
+		{"reload only", `<meta http-equiv="refresh" content="30">`, ""},
+		{"other meta", `<meta name="viewport" content="width=device-width; initial-scale=1">`, ""},
+		{"no meta", `<html><body>refresh</body></html>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MetaRefreshTarget(tt.body))
+		})
+	}
+}
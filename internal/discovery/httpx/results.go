@@ -0,0 +1,21 @@
+package httpx
+
+import "context"
+
+// resultHandlerKey is the context key of the handler probe results are streamed to
+type resultHandlerKey struct{}
+
+// WithResultHandler returns a context whose probes hand each result to handle
+// as soon as it arrives, while the probe is still running; the probe returns
+// every result as well. Results may arrive concurrently and, when the probe is
+// cancelled, shortly after it returns. Only local probes stream results, and
+// a nil handler stops streaming.
+func WithResultHandler(ctx context.Context, handle func(DetailedProbeResult)) context.Context {
+	return context.WithValue(ctx, resultHandlerKey{}, handle)
+}
+
+// ResultHandlerFromContext returns the handler set with WithResultHandler, or nil
+func ResultHandlerFromContext(ctx context.Context) func(DetailedProbeResult) {
+	handle, _ := ctx.Value(resultHandlerKey{}).(func(DetailedProbeResult))
+	return handle
+}
@@ -0,0 +1,20 @@
+package httpx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithResultHandler(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, ResultHandlerFromContext(ctx))
+
+	var handled []string
+	ctx = WithResultHandler(ctx, func(result DetailedProbeResult) { handled = append(handled, result.URL) })
+	ResultHandlerFromContext(ctx)(DetailedProbeResult{URL: "https://a.example.com"})
+	assert.Equal(t, []string{"https://a.example.com"}, handled)
+
+	assert.Nil(t, ResultHandlerFromContext(WithResultHandler(ctx, nil)))
+}
@@ -0,0 +1,134 @@
+package httpx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
+)
+
+// TLS checks run on the handshake of every https probe
+const (
+	TLSCheckExpired          = "expired-certificate"
+	TLSCheckSelfSigned       = "self-signed-certificate"
+	TLSCheckHostnameMismatch = "hostname-mismatch"
+	TLSCheckLegacyProtocol   = "legacy-protocol"
+)
+
+// Severities of TLS findings
+const (
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// maxCertificateDomains caps the certificate names kept per result
+const maxCertificateDomains = 20
+
+// legacyProtocols maps protocol versions that should no longer be offered to
+// their display name and severity
+var legacyProtocols = map[string]struct {
+	name     string
+	severity string
+}{
+	"ssl30": {"SSL 3.0", SeverityMedium},
+	"tls10": {"TLS 1.0", SeverityLow},
+	"tls11": {"TLS 1.1", SeverityLow},
+}
+
+// TLSInfo is the TLS handshake and leaf certificate of an https probe
+type TLSInfo struct {
+	Version    string    `json:"version"` // ssl30, tls10, tls11, tls12 or tls13
+	Cipher     string    `json:"cipher,omitempty"`
+	SubjectCN  string    `json:"subject_cn,omitempty"`
+	IssuerCN   string    `json:"issuer_cn,omitempty"`
+	Domains    []string  `json:"domains,omitempty"` // subject CN and SANs
+	NotAfter   time.Time `json:"not_after"`
+	Expired    bool      `json:"expired,omitempty"`
+	SelfSigned bool      `json:"self_signed,omitempty"`
+	Mismatched bool      `json:"mismatched,omitempty"` // certificate does not cover the probed host
+}
+
+// TLSFinding is a TLS misconfiguration found on a probe
+type TLSFinding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Detail   string `json:"detail"`
+}
+
+// newTLSInfo converts the TLS data httpx grabbed, returning nil when there is none
+func newTLSInfo(data *clients.Response) *TLSInfo {
+	if data == nil || !data.ProbeStatus || data.CertificateResponse == nil {
+		return nil
+	}
+
+	domains := data.Domains
+	if len(domains) == 0 {
+		domains = append([]string{data.SubjectCN}, data.SubjectAN...)
+	}
+	if len(domains) > maxCertificateDomains {
+		domains = domains[:maxCertificateDomains]
+	}
+
+	return &TLSInfo{
+		Version:    data.Version,
+		Cipher:     data.Cipher,
+		SubjectCN:  data.SubjectCN,
+		IssuerCN:   data.IssuerCN,
+		Domains:    domains,
+		NotAfter:   data.NotAfter,
+		Expired:    data.Expired,
+		SelfSigned: data.SelfSigned,
+		Mismatched: data.MisMatched,
+	}
+}
+
+// CheckTLS returns the misconfigurations in a probe's TLS handshake
+func CheckTLS(info *TLSInfo) []TLSFinding {
+	if info == nil {
+		return nil
+	}
+
+	var findings []TLSFinding
+	if info.Expired {
+		findings = append(findings, TLSFinding{
+			Check:    TLSCheckExpired,
+			Severity: SeverityMedium,
+			Detail:   fmt.Sprintf("certificate for %s expired on %s", certificateName(info), info.NotAfter.UTC().Format("2006-01-02")),
+		})
+	}
+	if info.SelfSigned {
+		findings = append(findings, TLSFinding{
+			Check:    TLSCheckSelfSigned,
+			Severity: SeverityLow,
+			Detail:   fmt.Sprintf("certificate for %s is self-signed", certificateName(info)),
+		})
+	}
+	if info.Mismatched {
+		findings = append(findings, TLSFinding{
+			Check:    TLSCheckHostnameMismatch,
+			Severity: SeverityLow,
+			Detail:   fmt.Sprintf("certificate is issued for %s", strings.Join(info.Domains, ", ")),
+		})
+	}
+	if legacy, ok := legacyProtocols[info.Version]; ok {
+		findings = append(findings, TLSFinding{
+			Check:    TLSCheckLegacyProtocol,
+			Severity: legacy.severity,
+			Detail:   fmt.Sprintf("server negotiated %s (cipher %s)", legacy.name, info.Cipher),
+		})
+	}
+
+	return findings
+}
+
+// certificateName names a certificate by its subject CN or first domain
+func certificateName(info *TLSInfo) string {
+	if info.SubjectCN != "" {
+		return info.SubjectCN
+	}
+	if len(info.Domains) > 0 {
+		return info.Domains[0]
+	}
+	return "unknown subject"
+}
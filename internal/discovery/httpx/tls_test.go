@@ -0,0 +1,80 @@
+package httpx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSInfo(t *testing.T) {
+	assert.Nil(t, newTLSInfo(nil))
+	assert.Nil(t, newTLSInfo(&clients.Response{ProbeStatus: false}))
+
+	notAfter := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	info := newTLSInfo(&clients.Response{
+		ProbeStatus: true,
+		Version:     "tls12",
+		Cipher:      "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		CertificateResponse: &clients.CertificateResponse{
+			SubjectCN:  "example.com",
+			SubjectAN:  []string{"www.example.com"},
+			IssuerCN:   "Example CA",
+			NotAfter:   notAfter,
+			Expired:    true,
+			MisMatched: true,
+		},
+	})
+
+	require.NotNil(t, info)
+	assert.Equal(t, "tls12", info.Version)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, info.Domains)
+	assert.Equal(t, notAfter, info.NotAfter)
+	assert.True(t, info.Expired)
+	assert.False(t, info.SelfSigned)
+	assert.True(t, info.Mismatched)
+}
+
+func TestCheckTLS(t *testing.T) {
+	tests := []struct {
+		name string
+		info *TLSInfo
+		want []string // check names
+	}{
+		{name: "no handshake", info: nil, want: nil},
+		{name: "healthy", info: &TLSInfo{Version: "tls13", SubjectCN: "example.com"}, want: nil},
+		{
+			name: "expired and self-signed",
+			info: &TLSInfo{Version: "tls12", SubjectCN: "example.com", Expired: true, SelfSigned: true},
+			want: []string{TLSCheckExpired, TLSCheckSelfSigned},
+		},
+		{
+			name: "mismatch on legacy protocol",
+			info: &TLSInfo{Version: "tls10", Domains: []string{"other.example.org"}, Mismatched: true},
+			want: []string{TLSCheckHostnameMismatch, TLSCheckLegacyProtocol},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, finding := range CheckTLS(tt.info) {
+				got = append(got, finding.Check)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckTLS_LegacySeverity(t *testing.T) {
+	findings := CheckTLS(&TLSInfo{Version: "ssl30", Cipher: "TLS_RSA_WITH_RC4_128_SHA"})
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityMedium, findings[0].Severity)
+	assert.Contains(t, findings[0].Detail, "SSL 3.0")
+
+	findings = CheckTLS(&TLSInfo{Version: "tls11"})
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityLow, findings[0].Severity)
+}
@@ -0,0 +1,90 @@
+package probeworker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/version"
+)
+
+// Client sends probe batches to a remote worker
+type Client struct {
+	httpClient *resty.Client
+	region     string
+	workerURL  string
+}
+
+// ClientConfig holds configuration for a worker client
+type ClientConfig struct {
+	Region  string
+	URL     string
+	Token   string
+	Timeout time.Duration
+}
+
+// NewClient creates a new worker client
+func NewClient(config *ClientConfig) *Client {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+
+	client.SetHeaders(map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+		"User-Agent":   version.UserAgent(),
+	})
+
+	if config.Token != "" {
+		client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", config.Token))
+	}
+
+	return &Client{
+		httpClient: client,
+		region:     config.Region,
+		workerURL:  strings.TrimRight(config.URL, "/"),
+	}
+}
+
+// Region returns the region the worker probes from
+func (c *Client) Region() string {
+	return c.region
+}
+
+// ProbeDomainsWithDetails probes domains from the worker's region
+func (c *Client) ProbeDomainsWithDetails(ctx context.Context, domains []string) ([]httpx.DetailedProbeResult, error) {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetBody(&ProbeRequest{Domains: domains, Method: httpx.MethodFromContext(ctx)}).
+		Post(c.workerURL + ProbePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send probe batch to %s: %w", c.region, err)
+	}
+
+	if resp.StatusCode() == http.StatusUnauthorized {
+		return nil, fmt.Errorf("probe worker %s unauthorized - please check PROBE_WORKER_TOKEN", c.region)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		var errorResp ErrorResponse
+		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil && errorResp.Error != "" {
+			return nil, fmt.Errorf("probe worker %s error: %s", c.region, errorResp.Error)
+		}
+		return nil, fmt.Errorf("probe worker %s returned status %d", c.region, resp.StatusCode())
+	}
+
+	var probeResp ProbeResponse
+	if err := json.Unmarshal(resp.Body(), &probeResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal probe response: %w", err)
+	}
+
+	for i := range probeResp.Results {
+		probeResp.Results[i].Region = c.region
+	}
+
+	return probeResp.Results, nil
+}
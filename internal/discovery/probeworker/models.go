@@ -0,0 +1,24 @@
+package probeworker
+
+import (
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/httpapi"
+)
+
+// ProbePath is the worker endpoint that accepts probe batches
+const ProbePath = "/api/v1/probe"
+
+// ProbeRequest is a batch of domains sent to a worker
+type ProbeRequest struct {
+	Domains []string `json:"domains"`
+	Method  string   `json:"method,omitempty"` // GET or HEAD; GET when empty
+}
+
+// ProbeResponse is returned by a worker after probing a batch
+type ProbeResponse struct {
+	Region  string                      `json:"region"`
+	Results []httpx.DetailedProbeResult `json:"results"`
+}
+
+// ErrorResponse is returned by a worker when a request fails
+type ErrorResponse = httpapi.ErrorResponse
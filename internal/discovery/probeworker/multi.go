@@ -0,0 +1,111 @@
+package probeworker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/sirupsen/logrus"
+)
+
+// MultiProber probes every batch locally and through remote workers in other
+// regions, so assets that are only reachable from some regions are not
+// reported as dead
+type MultiProber struct {
+	local       Prober
+	localRegion string
+	workers     []*Client
+}
+
+// NewMultiProber creates a prober that fans batches out to the local prober and workers
+func NewMultiProber(local Prober, localRegion string, workers []*Client) *MultiProber {
+	return &MultiProber{
+		local:       local,
+		localRegion: localRegion,
+		workers:     workers,
+	}
+}
+
+// regionResults holds the results from one vantage point
+type regionResults struct {
+	region  string
+	results []httpx.DetailedProbeResult
+	err     error
+}
+
+// ProbeDomainsWithDetails probes domains from every vantage point and merges the
+// results. Failing workers are logged and skipped; the probe only fails when
+// the local probe fails.
+func (m *MultiProber) ProbeDomainsWithDetails(ctx context.Context, domains []string) ([]httpx.DetailedProbeResult, error) {
+	all := make([]regionResults, len(m.workers)+1)
+
+	var wg sync.WaitGroup
+	for i, worker := range m.workers {
+		wg.Add(1)
+		go func(i int, worker *Client) {
+			defer wg.Done()
+			results, err := worker.ProbeDomainsWithDetails(ctx, domains)
+			all[i+1] = regionResults{region: worker.Region(), results: results, err: err}
+		}(i, worker)
+	}
+
+	// Results are only final once every region has answered, so the local
+	// probe does not stream them
+	results, err := m.local.ProbeDomainsWithDetails(httpx.WithResultHandler(ctx, nil), domains)
+	for i := range results {
+		results[i].Region = m.localRegion
+	}
+	all[0] = regionResults{region: m.localRegion, results: results, err: err}
+	wg.Wait()
+
+	if all[0].err != nil {
+		return nil, all[0].err
+	}
+
+	var merged []regionResults
+	for _, r := range all {
+		if r.err != nil {
+			logrus.Warnf("Probe from region %s failed, continuing without it: %v", r.region, r.err)
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return mergeResults(merged), nil
+}
+
+// mergeResults combines per-region results by URL. A URL exists when any
+// region reached it; the representative result comes from the first region
+// that reached it, in vantage order with the local region first. URLs no
+// region reached keep the most alive state any region saw.
+func mergeResults(all []regionResults) []httpx.DetailedProbeResult {
+	var order []string
+	byURL := make(map[string]*httpx.DetailedProbeResult)
+
+	for _, r := range all {
+		for _, result := range r.results {
+			current, seen := byURL[result.URL]
+			if !seen {
+				order = append(order, result.URL)
+				merged := result
+				merged.ReachableFrom = nil
+				byURL[result.URL] = &merged
+				current = &merged
+			} else if (result.Exists && !current.Exists) || (!current.Exists && httpx.MoreAlive(result.Liveness, current.Liveness)) {
+				reachable := current.ReachableFrom
+				*current = result
+				current.ReachableFrom = reachable
+			}
+
+			if result.Exists {
+				current.ReachableFrom = append(current.ReachableFrom, r.region)
+			}
+		}
+	}
+
+	results := make([]httpx.DetailedProbeResult, 0, len(order))
+	for _, url := range order {
+		results = append(results, *byURL[url])
+	}
+	return results
+}
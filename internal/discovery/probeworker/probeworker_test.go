@@ -0,0 +1,110 @@
+package probeworker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProber reports the configured URLs as reachable
+type fakeProber struct {
+	reachable map[string]bool
+	err       error
+}
+
+func (f *fakeProber) ProbeDomainsWithDetails(ctx context.Context, domains []string) ([]httpx.DetailedProbeResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var results []httpx.DetailedProbeResult
+	for _, domain := range domains {
+		url := "https://" + domain
+		result := httpx.DetailedProbeResult{URL: url, Method: httpx.MethodFromContext(ctx)}
+		if f.reachable[domain] {
+			result.Exists = true
+			result.StatusCode = 200
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func newWorker(t *testing.T, region string, prober Prober) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle(ProbePath, NewServer(prober, region, "secret"))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return NewClient(&ClientConfig{Region: region, URL: server.URL, Token: "secret", Timeout: 5 * time.Second})
+}
+
+func TestClient_ProbeThroughServer(t *testing.T) {
+	client := newWorker(t, "us-east", &fakeProber{reachable: map[string]bool{"us.example.com": true}})
+
+	results, err := client.ProbeDomainsWithDetails(context.Background(), []string{"us.example.com", "eu.example.com"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Exists)
+	assert.Equal(t, "us-east", results[0].Region)
+	assert.False(t, results[1].Exists)
+	assert.Equal(t, httpx.MethodGET, results[0].Method)
+
+	// The worker probes with the agent's method
+	results, err = client.ProbeDomainsWithDetails(httpx.WithMethod(context.Background(), httpx.MethodHEAD), []string{"us.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, httpx.MethodHEAD, results[0].Method)
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(ProbePath, NewServer(&fakeProber{}, "us-east", "secret"))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{Region: "us-east", URL: server.URL, Token: "wrong", Timeout: time.Second})
+	_, err := client.ProbeDomainsWithDetails(context.Background(), []string{"example.com"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
+
+func TestMultiProber_MergesRegions(t *testing.T) {
+	local := &fakeProber{reachable: map[string]bool{"www.example.com": true}}
+	us := newWorker(t, "us-east", &fakeProber{reachable: map[string]bool{"www.example.com": true, "geo.example.com": true}})
+	broken := newWorker(t, "ap-south", &fakeProber{err: fmt.Errorf("boom")})
+
+	prober := NewMultiProber(local, "eu-west", []*Client{us, broken})
+	results, err := prober.ProbeDomainsWithDetails(context.Background(), []string{"www.example.com", "geo.example.com", "dead.example.com"})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "https://www.example.com", results[0].URL)
+	assert.True(t, results[0].Exists)
+	assert.Equal(t, "eu-west", results[0].Region)
+	assert.Equal(t, []string{"eu-west", "us-east"}, results[0].ReachableFrom)
+
+	assert.Equal(t, "https://geo.example.com", results[1].URL)
+	assert.True(t, results[1].Exists)
+	assert.Equal(t, "us-east", results[1].Region)
+	assert.Equal(t, []string{"us-east"}, results[1].ReachableFrom)
+
+	assert.False(t, results[2].Exists)
+	assert.Empty(t, results[2].ReachableFrom)
+}
+
+func TestMultiProber_LocalFailure(t *testing.T) {
+	us := newWorker(t, "us-east", &fakeProber{})
+	prober := NewMultiProber(&fakeProber{err: fmt.Errorf("httpx failed")}, "local", []*Client{us})
+
+	_, err := prober.ProbeDomainsWithDetails(context.Background(), []string{"example.com"})
+	assert.Error(t, err)
+}
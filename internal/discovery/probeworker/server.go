@@ -0,0 +1,81 @@
+package probeworker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/httpapi"
+	"github.com/sirupsen/logrus"
+)
+
+// maxBatchBytes caps the size of a single probe request
+const maxBatchBytes = 8 << 20
+
+// maxBatchDomains caps the number of domains probed per request
+const maxBatchDomains = 10000
+
+// Prober probes domains and returns detailed results
+type Prober interface {
+	ProbeDomainsWithDetails(ctx context.Context, domains []string) ([]httpx.DetailedProbeResult, error)
+}
+
+// Server probes batches of domains on behalf of remote agents
+type Server struct {
+	prober Prober
+	region string
+	token  string
+}
+
+// NewServer creates a new probe worker handler
+func NewServer(prober Prober, region, token string) *Server {
+	return &Server{
+		prober: prober,
+		region: region,
+		token:  token,
+	}
+}
+
+// ServeHTTP handles a probe batch from an agent
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httpapi.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if !httpapi.Authorized(r.Header.Get("Authorization"), s.token) {
+		httpapi.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ProbeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes)).Decode(&req); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "invalid probe request: "+err.Error())
+		return
+	}
+
+	if len(req.Domains) > maxBatchDomains {
+		httpapi.WriteError(w, http.StatusRequestEntityTooLarge, "too many domains in one batch")
+		return
+	}
+
+	if !httpx.ValidMethod(req.Method) {
+		httpapi.WriteError(w, http.StatusBadRequest, "unsupported probe method: "+req.Method)
+		return
+	}
+
+	results, err := s.prober.ProbeDomainsWithDetails(httpx.WithMethod(r.Context(), req.Method), req.Domains)
+	if err != nil {
+		logrus.Errorf("Probe worker failed to probe %d domains: %v", len(req.Domains), err)
+		httpapi.WriteError(w, http.StatusInternalServerError, "probe failed")
+		return
+	}
+
+	for i := range results {
+		results[i].Region = s.region
+	}
+
+	logrus.Infof("Probe worker %s probed %d domains, returning %d results", s.region, len(req.Domains), len(results))
+	httpapi.WriteJSON(w, http.StatusOK, ProbeResponse{Region: s.region, Results: results})
+}
@@ -0,0 +1,16 @@
+// Package discovery holds what the subdomain discovery sources have in
+// common; each source lives in its own subpackage.
+package discovery
+
+import "context"
+
+// Source finds subdomains of an in-scope domain. Its name is recorded as the
+// first_source and provenance of the assets it finds.
+type Source interface {
+	// Name identifies the source, e.g. chaosdb or crtsh
+	Name() string
+
+	// Subdomains returns the subdomains the source knows of for a domain.
+	// They may repeat or carry wildcards; callers clean them up.
+	Subdomains(ctx context.Context, domain string) ([]string, error)
+}
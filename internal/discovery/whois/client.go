@@ -0,0 +1,139 @@
+// Package whois looks up domain registration data. It queries RDAP, the
+// JSON successor to port-43 WHOIS that registries are required to serve, so
+// responses are structured instead of per-registry free text.
+package whois
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+	"golang.org/x/net/publicsuffix"
+)
+
+// DefaultServerURL is the RDAP bootstrap service, which redirects each query
+// to the registry responsible for the domain's TLD
+const DefaultServerURL = "https://rdap.org"
+
+// ErrNotFound is returned when the registry has no record of a domain
+var ErrNotFound = errors.New("domain not found")
+
+// Client looks up domain registration data over RDAP
+type Client struct {
+	httpClient *resty.Client
+	serverURL  string
+}
+
+// ClientConfig holds configuration for the registration data client
+type ClientConfig struct {
+	ServerURL     string
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// NewClient creates a new registration data client
+func NewClient(config *ClientConfig) *Client {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+	client.SetHeaders(map[string]string{
+		"Accept":     "application/rdap+json, application/json",
+		"User-Agent": version.UserAgent(),
+	})
+
+	serverURL := config.ServerURL
+	if serverURL == "" {
+		serverURL = DefaultServerURL
+	}
+
+	return &Client{
+		httpClient: client,
+		serverURL:  strings.TrimRight(serverURL, "/"),
+	}
+}
+
+// Lookup retrieves the registration data of a domain
+func (c *Client) Lookup(ctx context.Context, domain string) (*Registration, error) {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("%s/domain/%s", c.serverURL, domain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up registration for %s: %w", domain, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", domain, ErrNotFound)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("registration lookup for %s returned status %d", domain, resp.StatusCode())
+	}
+
+	var rdap rdapDomain
+	if err := json.Unmarshal(resp.Body(), &rdap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal registration for %s: %w", domain, err)
+	}
+
+	registration := &Registration{Domain: domain, Registrar: entityName(rdap.Entities, "registrar")}
+	for _, event := range rdap.Events {
+		date, err := time.Parse(time.RFC3339, event.Date)
+		if err != nil {
+			continue
+		}
+		switch event.Action {
+		case "registration":
+			registration.RegisteredAt = &date
+		case "expiration":
+			registration.ExpiresAt = &date
+		}
+	}
+
+	return registration, nil
+}
+
+// entityName returns the formatted name of the first entity with the given role
+func entityName(entities []rdapEntity, role string) string {
+	for _, entity := range entities {
+		hasRole := false
+		for _, entityRole := range entity.Roles {
+			if entityRole == role {
+				hasRole = true
+				break
+			}
+		}
+		if !hasRole || len(entity.VCardArray) < 2 {
+			continue
+		}
+
+		properties, _ := entity.VCardArray[1].([]any)
+		for _, property := range properties {
+			fields, _ := property.([]any)
+			if len(fields) < 4 || fields[0] != "fn" {
+				continue
+			}
+			if name, ok := fields[3].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// ApexDomain returns the registrable domain (eTLD+1) of a host, e.g.
+// example.co.uk for api.example.co.uk
+func ApexDomain(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	apex, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return "", fmt.Errorf("failed to find apex domain of %s: %w", host, err)
+	}
+	return apex, nil
+}
@@ -0,0 +1,116 @@
+package whois
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rdapResponse = `{
+	"objectClassName": "domain",
+	"ldhName": "EXAMPLE.COM",
+	"events": [
+		{"eventAction": "registration", "eventDate": "2026-10-01T12:00:00Z"},
+		{"eventAction": "expiration", "eventDate": "2027-10-01T12:00:00Z"},
+		{"eventAction": "last update of RDAP database", "eventDate": "not a date"}
+	],
+	"entities": [
+		{"roles": ["registrant"], "vcardArray": ["vcard", [["fn", {}, "text", "Someone"]]]},
+		{"roles": ["registrar"], "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]}
+	]
+}`
+
+func TestClient_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/domain/example.com":
+			_, _ = w.Write([]byte(rdapResponse))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{ServerURL: server.URL + "/", Timeout: 5 * time.Second})
+
+	registration, err := client.Lookup(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", registration.Domain)
+	assert.Equal(t, "Example Registrar, Inc.", registration.Registrar)
+	require.NotNil(t, registration.RegisteredAt)
+	assert.Equal(t, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), *registration.RegisteredAt)
+	require.NotNil(t, registration.ExpiresAt)
+
+	age, ok := registration.Age(time.Date(2026, 10, 11, 12, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, 10*24*time.Hour, age)
+
+	_, err = client.Lookup(context.Background(), "missing.com")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestApexDomain(t *testing.T) {
+	tests := map[string]string{
+		"api.example.com":        "example.com",
+		"Example.COM.":           "example.com",
+		"a.b.example.co.uk":      "example.co.uk",
+		"shop.example.github.io": "example.github.io",
+	}
+	for host, want := range tests {
+		apex, err := ApexDomain(host)
+		require.NoError(t, err, host)
+		assert.Equal(t, want, apex, host)
+	}
+
+	_, err := ApexDomain("com")
+	assert.Error(t, err)
+}
+
+const rdapIPResponse = `{
+	"objectClassName": "ip network",
+	"name": "EXAMPLE-NET",
+	"country": "de",
+	"startAddress": "192.0.2.0",
+	"endAddress": "192.0.2.255",
+	"entities": [
+		{"roles": ["abuse"], "vcardArray": ["vcard", [["fn", {}, "text", "Abuse Desk"]]]},
+		{"roles": ["registrant"], "vcardArray": ["vcard", [["fn", {}, "text", "Example Hosting GmbH"]]]}
+	]
+}`
+
+func TestClient_LookupIP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ip/192.0.2.10":
+			_, _ = w.Write([]byte(rdapIPResponse))
+		case "/ip/198.51.100.1":
+			_, _ = w.Write([]byte(`{"name": "DOC-NET", "country": "US"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{ServerURL: server.URL, Timeout: 5 * time.Second})
+
+	network, err := client.LookupIP(context.Background(), "192.0.2.10")
+	require.NoError(t, err)
+	assert.Equal(t, "DE", network.Country)
+	assert.Equal(t, "Example Hosting GmbH", network.Provider)
+	assert.Equal(t, "EXAMPLE-NET", network.Name)
+	assert.Equal(t, "192.0.2.0 - 192.0.2.255", network.Range)
+
+	// Without a registrant the network name stands in for the provider
+	network, err = client.LookupIP(context.Background(), "198.51.100.1")
+	require.NoError(t, err)
+	assert.Equal(t, "DOC-NET", network.Provider)
+
+	_, err = client.LookupIP(context.Background(), "203.0.113.1")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
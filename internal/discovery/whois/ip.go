@@ -0,0 +1,50 @@
+package whois
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// LookupIP retrieves the network an IP address is allocated to, including its
+// country and the organization (usually the hosting provider) holding it
+func (c *Client) LookupIP(ctx context.Context, ip string) (*Network, error) {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("%s/ip/%s", c.serverURL, ip))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up network for %s: %w", ip, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", ip, ErrNotFound)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("network lookup for %s returned status %d", ip, resp.StatusCode())
+	}
+
+	var rdap rdapIPNetwork
+	if err := json.Unmarshal(resp.Body(), &rdap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal network for %s: %w", ip, err)
+	}
+
+	network := &Network{
+		IP:      ip,
+		Country: strings.ToUpper(rdap.Country),
+		Name:    rdap.Name,
+	}
+	if rdap.StartAddress != "" && rdap.EndAddress != "" {
+		network.Range = rdap.StartAddress + " - " + rdap.EndAddress
+	}
+
+	// The registrant is the organization the block is allocated to; fall
+	// back to the network name when the registry does not publish it
+	network.Provider = entityName(rdap.Entities, "registrant")
+	if network.Provider == "" {
+		network.Provider = rdap.Name
+	}
+
+	return network, nil
+}
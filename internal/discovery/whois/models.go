@@ -0,0 +1,60 @@
+package whois
+
+import (
+	"time"
+)
+
+// rdapDomain is the subset of an RDAP domain response that is used
+type rdapDomain struct {
+	LDHName  string       `json:"ldhName"`
+	Events   []rdapEvent  `json:"events"`
+	Entities []rdapEntity `json:"entities"`
+}
+
+// rdapIPNetwork is the subset of an RDAP IP network response that is used
+type rdapIPNetwork struct {
+	Name         string       `json:"name"`
+	Country      string       `json:"country"`
+	StartAddress string       `json:"startAddress"`
+	EndAddress   string       `json:"endAddress"`
+	Entities     []rdapEntity `json:"entities"`
+}
+
+// rdapEvent is a dated lifecycle event such as registration or expiration
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+// rdapEntity is a contact attached to a domain or network; it carries its
+// name in a jCard ("vcard", [[name, params, type, value], ...])
+type rdapEntity struct {
+	Roles      []string `json:"roles"`
+	VCardArray []any    `json:"vcardArray"`
+}
+
+// Registration holds the registration data of an apex domain
+type Registration struct {
+	Domain       string     `json:"domain"`
+	Registrar    string     `json:"registrar"`
+	RegisteredAt *time.Time `json:"registered_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// Age returns how long ago the domain was registered, or false when the
+// registration date is unknown
+func (r *Registration) Age(now time.Time) (time.Duration, bool) {
+	if r.RegisteredAt == nil {
+		return 0, false
+	}
+	return now.Sub(*r.RegisteredAt), true
+}
+
+// Network holds the allocation data of the network an IP address belongs to
+type Network struct {
+	IP       string `json:"ip"`
+	Country  string `json:"country"`  // ISO 3166 code; empty when the registry does not publish it
+	Provider string `json:"provider"` // organization the network is allocated to
+	Name     string `json:"name"`     // registry network name, e.g. AMAZON-IAD
+	Range    string `json:"range,omitempty"`
+}
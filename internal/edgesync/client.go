@@ -0,0 +1,88 @@
+package edgesync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+)
+
+// Client pushes batches to a central Monitor-Agent server
+type Client struct {
+	httpClient *resty.Client
+	serverURL  string
+}
+
+// ClientConfig holds configuration for the sync client
+type ClientConfig struct {
+	ServerURL     string
+	Token         string
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// NewClient creates a new sync client
+func NewClient(config *ClientConfig) *Client {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+
+	// Set default headers
+	client.SetHeaders(map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+		"User-Agent":   version.UserAgent(),
+	})
+
+	if config.Token != "" {
+		client.SetHeader("Authorization", fmt.Sprintf("Bearer %s", config.Token))
+	}
+
+	return &Client{
+		httpClient: client,
+		serverURL:  strings.TrimRight(config.ServerURL, "/"),
+	}
+}
+
+// ServerURL returns the central server URL this client pushes to
+func (c *Client) ServerURL() string {
+	return c.serverURL
+}
+
+// Push sends a batch to the central server
+func (c *Client) Push(ctx context.Context, batch *Batch) (*PushResponse, error) {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetBody(batch).
+		Post(c.serverURL + PushPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to push sync batch: %w", err)
+	}
+
+	if resp.StatusCode() == http.StatusUnauthorized {
+		return nil, fmt.Errorf("sync server unauthorized - please check SYNC_TOKEN")
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		var errorResp ErrorResponse
+		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil && errorResp.Error != "" {
+			return nil, fmt.Errorf("sync server error: %s", errorResp.Error)
+		}
+		return nil, fmt.Errorf("sync server returned status %d", resp.StatusCode())
+	}
+
+	var pushResp PushResponse
+	if err := json.Unmarshal(resp.Body(), &pushResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync response: %w", err)
+	}
+
+	return &pushResp, nil
+}
@@ -0,0 +1,53 @@
+package edgesync
+
+import (
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/httpapi"
+)
+
+// PushPath is the central server endpoint that accepts sync batches
+const PushPath = "/api/v1/sync/push"
+
+// Batch is a set of programs and assets pushed by an edge agent in one request
+type Batch struct {
+	AgentID  string          `json:"agent_id"`
+	SentAt   time.Time       `json:"sent_at"`
+	Programs []ProgramRecord `json:"programs"`
+}
+
+// ProgramRecord is a program together with the assets being synced for it
+type ProgramRecord struct {
+	Program database.Program  `json:"program"`
+	Assets  []*database.Asset `json:"assets"`
+}
+
+// PushResponse is returned by the central server after applying a batch
+type PushResponse struct {
+	Programs       int `json:"programs"`
+	ProgramUpdates int `json:"program_updates"`
+	AssetsCreated  int `json:"assets_created"`
+	AssetsUpdated  int `json:"assets_updated"`
+	AssetsSkipped  int `json:"assets_skipped"`
+}
+
+// ErrorResponse is returned by the central server when a request fails
+type ErrorResponse = httpapi.ErrorResponse
+
+// PushSummary totals the results of a full push run
+type PushSummary struct {
+	Batches  int
+	Programs int
+	Assets   int
+	Result   PushResponse
+}
+
+// add accumulates a server response into the summary
+func (s *PushSummary) add(resp *PushResponse) {
+	s.Result.Programs += resp.Programs
+	s.Result.ProgramUpdates += resp.ProgramUpdates
+	s.Result.AssetsCreated += resp.AssetsCreated
+	s.Result.AssetsUpdated += resp.AssetsUpdated
+	s.Result.AssetsSkipped += resp.AssetsSkipped
+}
@@ -0,0 +1,119 @@
+package edgesync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/sirupsen/logrus"
+)
+
+// Pusher sends locally discovered programs and assets to a central server
+type Pusher struct {
+	repo      *database.SyncRepository
+	client    *Client
+	agentID   string
+	batchSize int
+}
+
+// NewPusher creates a new pusher. batchSize is the maximum number of assets per request.
+func NewPusher(repo *database.SyncRepository, client *Client, agentID string, batchSize int) *Pusher {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &Pusher{
+		repo:      repo,
+		client:    client,
+		agentID:   agentID,
+		batchSize: batchSize,
+	}
+}
+
+// Push sends everything changed since the last successful push. When full is
+// true the cursor is ignored and all programs and assets are sent.
+func (p *Pusher) Push(ctx context.Context, full bool) (*PushSummary, error) {
+	since := time.Time{}
+	if !full {
+		cursor, err := p.repo.GetSyncCursor(ctx, p.client.ServerURL())
+		if err != nil {
+			return nil, err
+		}
+		since = cursor
+	}
+
+	// Capture the new cursor before reading so changes made during the push are picked up next time
+	startedAt := time.Now()
+
+	programs, err := p.repo.GetProgramsChangedSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Pushing %d changed programs to %s", len(programs), p.client.ServerURL())
+
+	summary := &PushSummary{}
+	batch := p.newBatch()
+	pending := 0
+
+	flush := func() error {
+		if len(batch.Programs) == 0 {
+			return nil
+		}
+		batch.SentAt = time.Now()
+		resp, err := p.client.Push(ctx, batch)
+		if err != nil {
+			return err
+		}
+		summary.Batches++
+		summary.add(resp)
+		batch = p.newBatch()
+		pending = 0
+		return nil
+	}
+
+	for _, program := range programs {
+		assets, err := p.repo.GetAssetsChangedSince(ctx, program.ID, since)
+		if err != nil {
+			return summary, err
+		}
+
+		summary.Programs++
+		summary.Assets += len(assets)
+
+		// Split large programs across batches; the server upsert is idempotent
+		for {
+			take := p.batchSize - pending
+			if take > len(assets) {
+				take = len(assets)
+			}
+			batch.Programs = append(batch.Programs, ProgramRecord{Program: *program, Assets: assets[:take]})
+			pending += take
+			assets = assets[take:]
+
+			if pending >= p.batchSize {
+				if err := flush(); err != nil {
+					return summary, fmt.Errorf("failed to push batch %d: %w", summary.Batches+1, err)
+				}
+			}
+			if len(assets) == 0 {
+				break
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return summary, fmt.Errorf("failed to push batch %d: %w", summary.Batches+1, err)
+	}
+
+	if err := p.repo.UpdateSyncCursor(ctx, p.client.ServerURL(), startedAt); err != nil {
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+// newBatch creates an empty batch for this agent
+func (p *Pusher) newBatch() *Batch {
+	return &Batch{AgentID: p.agentID}
+}
@@ -0,0 +1,104 @@
+package edgesync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/httpapi"
+	"github.com/sirupsen/logrus"
+)
+
+// maxBatchBytes caps the size of a single pushed batch
+const maxBatchBytes = 32 << 20
+
+// Store applies synced programs to the central database
+type Store interface {
+	ApplySyncedProgram(ctx context.Context, agentID string, program *database.Program, assets []*database.Asset) (*database.SyncApplyResult, error)
+}
+
+// Server receives batches from edge agents
+type Server struct {
+	store Store
+	token string
+}
+
+// NewServer creates a new sync server handler
+func NewServer(store Store, token string) *Server {
+	return &Server{
+		store: store,
+		token: token,
+	}
+}
+
+// ServeHTTP handles a push from an edge agent
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httpapi.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if !httpapi.Authorized(r.Header.Get("Authorization"), s.token) {
+		httpapi.WriteError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var batch Batch
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes)).Decode(&batch); err != nil {
+		httpapi.WriteError(w, http.StatusBadRequest, "invalid batch: "+err.Error())
+		return
+	}
+
+	if batch.AgentID == "" {
+		httpapi.WriteError(w, http.StatusBadRequest, "agent_id is required")
+		return
+	}
+
+	resp := &PushResponse{}
+	for i := range batch.Programs {
+		record := &batch.Programs[i]
+		if record.Program.Platform == "" || record.Program.ProgramURL == "" {
+			httpapi.WriteError(w, http.StatusBadRequest, "program platform and program_url are required")
+			return
+		}
+		normalizeRecord(record)
+
+		result, err := s.store.ApplySyncedProgram(r.Context(), batch.AgentID, &record.Program, record.Assets)
+		if err != nil {
+			logrus.Errorf("Failed to apply synced program %s from agent %s: %v", record.Program.ProgramURL, batch.AgentID, err)
+			httpapi.WriteError(w, http.StatusInternalServerError, "failed to apply batch")
+			return
+		}
+
+		resp.Programs++
+		if result.ProgramUpdated {
+			resp.ProgramUpdates++
+		}
+		resp.AssetsCreated += result.AssetsCreated
+		resp.AssetsUpdated += result.AssetsUpdated
+		resp.AssetsSkipped += result.AssetsSkipped
+	}
+
+	logrus.Infof("Applied sync batch from agent %s: %d programs, %d assets created, %d updated, %d skipped",
+		batch.AgentID, resp.Programs, resp.AssetsCreated, resp.AssetsUpdated, resp.AssetsSkipped)
+
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+// normalizeRecord fills in timestamps missing from older agents
+func normalizeRecord(record *ProgramRecord) {
+	now := time.Now()
+	if record.Program.LastUpdated.IsZero() {
+		record.Program.LastUpdated = now
+	}
+	for _, asset := range record.Assets {
+		if asset.UpdatedAt.IsZero() {
+			asset.UpdatedAt = now
+		}
+		if asset.CreatedAt.IsZero() {
+			asset.CreatedAt = asset.UpdatedAt
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package edgesync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	agents   []string
+	programs []database.Program
+	assets   int
+	err      error
+}
+
+func (f *fakeStore) ApplySyncedProgram(ctx context.Context, agentID string, program *database.Program, assets []*database.Asset) (*database.SyncApplyResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.agents = append(f.agents, agentID)
+	f.programs = append(f.programs, *program)
+	f.assets += len(assets)
+	return &database.SyncApplyResult{
+		ProgramID:      uuid.New(),
+		ProgramUpdated: true,
+		AssetsCreated:  len(assets),
+	}, nil
+}
+
+func newTestServer(t *testing.T, store Store, token string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle(PushPath, NewServer(store, token))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestClient(serverURL, token string) *Client {
+	return NewClient(&ClientConfig{
+		ServerURL:  serverURL,
+		Token:      token,
+		Timeout:    5 * time.Second,
+		RetryDelay: 10 * time.Millisecond,
+	})
+}
+
+func testBatch() *Batch {
+	return &Batch{
+		AgentID: "edge-eu-1",
+		Programs: []ProgramRecord{
+			{
+				Program: database.Program{Name: "Example", Platform: "hackerone", ProgramURL: "https://hackerone.com/example"},
+				Assets: []*database.Asset{
+					{URL: "https://a.example.com", Domain: "example.com", Status: "active", Source: "secondary"},
+					{URL: "https://b.example.com", Domain: "example.com", Status: "active", Source: "secondary"},
+				},
+			},
+		},
+	}
+}
+
+func TestServer_Push(t *testing.T) {
+	store := &fakeStore{}
+	server := newTestServer(t, store, "secret")
+
+	resp, err := newTestClient(server.URL, "secret").Push(context.Background(), testBatch())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, resp.Programs)
+	assert.Equal(t, 1, resp.ProgramUpdates)
+	assert.Equal(t, 2, resp.AssetsCreated)
+	assert.Equal(t, []string{"edge-eu-1"}, store.agents)
+	assert.Equal(t, 2, store.assets)
+	assert.False(t, store.programs[0].LastUpdated.IsZero(), "missing timestamps should be filled in")
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	store := &fakeStore{}
+	server := newTestServer(t, store, "secret")
+
+	_, err := newTestClient(server.URL, "wrong").Push(context.Background(), testBatch())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+	assert.Empty(t, store.agents)
+}
+
+func TestServer_EmptyTokenRejectsAll(t *testing.T) {
+	server := newTestServer(t, &fakeStore{}, "")
+
+	_, err := newTestClient(server.URL, "").Push(context.Background(), testBatch())
+	assert.Error(t, err)
+}
+
+func TestServer_InvalidRequests(t *testing.T) {
+	server := newTestServer(t, &fakeStore{}, "secret")
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+	}{
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"invalid json", http.MethodPost, "{", http.StatusBadRequest},
+		{"missing agent", http.MethodPost, `{"programs":[]}`, http.StatusBadRequest},
+		{"missing program url", http.MethodPost, `{"agent_id":"a","programs":[{"program":{"platform":"hackerone"}}]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+PushPath, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer secret")
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestServer_StoreError(t *testing.T) {
+	server := newTestServer(t, &fakeStore{err: fmt.Errorf("db down")}, "secret")
+
+	_, err := newTestClient(server.URL, "secret").Push(context.Background(), testBatch())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply batch")
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Broadcaster hands events to in-process subscribers, such as gRPC event
+// streams. A subscriber that falls behind misses events rather than holding
+// up the scan that emitted them.
+type Broadcaster struct {
+	buffer int
+
+	mu          sync.Mutex
+	subscribers map[*subscription]bool
+	closed      bool
+}
+
+// subscription is a subscriber's channel and the event types it wants
+type subscription struct {
+	types  []string
+	events chan *Event
+}
+
+// NewBroadcaster creates a broadcaster whose subscribers each buffer up to
+// buffer events
+func NewBroadcaster(buffer int) *Broadcaster {
+	return &Broadcaster{
+		buffer:      buffer,
+		subscribers: make(map[*subscription]bool),
+	}
+}
+
+// Name returns the publisher name used in logs
+func (b *Broadcaster) Name() string {
+	return "broadcast"
+}
+
+// Subscribe returns a channel receiving the events of the given types, or of
+// every type when none are given, and a function that ends the subscription.
+// The channel is closed when the subscription ends or the broadcaster closes.
+func (b *Broadcaster) Subscribe(types ...string) (<-chan *Event, func()) {
+	sub := &subscription{
+		types:  types,
+		events: make(chan *Event, b.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub.events)
+		return sub.events, func() {}
+	}
+	b.subscribers[sub] = true
+
+	return sub.events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.subscribers[sub] {
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Publish hands an event to every subscriber that wants its type. Subscribers
+// whose buffer is full miss the event.
+func (b *Broadcaster) Publish(ctx context.Context, event *Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var dropped int
+	for sub := range b.subscribers {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, event.Type) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			dropped++
+		}
+	}
+
+	if dropped > 0 {
+		return fmt.Errorf("%d subscribers fell behind and missed the event", dropped)
+	}
+	return nil
+}
+
+// Close ends every subscription
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+	b.closed = true
+
+	return nil
+}
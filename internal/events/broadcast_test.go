@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcaster(t *testing.T) {
+	broadcaster := NewBroadcaster(1)
+	emitter := NewEmitter("test")
+	emitter.AddPublisher(broadcaster)
+	require.True(t, emitter.Enabled())
+
+	all, unsubscribeAll := broadcaster.Subscribe()
+	assets, unsubscribeAssets := broadcaster.Subscribe(TypeAssetDiscovered)
+	defer unsubscribeAssets()
+
+	emitter.Emit(context.Background(), TypeProgramCreated, "https://hackerone.com/acme", nil)
+	event := <-all
+	assert.Equal(t, TypeProgramCreated, event.Type)
+	assert.Empty(t, assets, "subscribers only get the types they asked for")
+
+	emitter.Emit(context.Background(), TypeAssetDiscovered, "https://api.acme.com", nil)
+	event = <-assets
+	assert.Equal(t, "https://api.acme.com", event.Subject)
+
+	// The first subscriber's buffer is full, so it misses the next event
+	err := broadcaster.Publish(context.Background(), New("test", TypeScanDigest, "", nil))
+	assert.Error(t, err)
+	assert.Equal(t, TypeAssetDiscovered, (<-all).Type)
+
+	unsubscribeAll()
+	_, open := <-all
+	assert.False(t, open, "unsubscribing closes the channel")
+
+	require.NoError(t, broadcaster.Close())
+	_, open = <-assets
+	assert.False(t, open, "closing the broadcaster ends every subscription")
+
+	late, _ := broadcaster.Subscribe()
+	_, open = <-late
+	assert.False(t, open)
+}
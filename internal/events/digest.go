@@ -0,0 +1,101 @@
+package events
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+)
+
+// Attachment formats of scan digests
+const (
+	DigestFormatCSV  = "csv"
+	DigestFormatJSON = "json"
+)
+
+// ScanDigestData is the payload of scan.digest events, emitted once per
+// program scan that found new assets. The new assets are attached inline or,
+// when attachments are published elsewhere, linked.
+type ScanDigestData struct {
+	Program     ProgramData `json:"program"`
+	ScanID      uuid.UUID   `json:"scan_id"`
+	Status      string      `json:"status"`
+	NewAssets   int         `json:"new_assets"`
+	AssetsSeen  int         `json:"assets_seen"`
+	AssetsFound int         `json:"assets_found"`
+	Attachment  *Attachment `json:"attachment,omitempty"`
+}
+
+// Attachment is a file of a digest. Content holds it inline; URL links to it
+// instead when it was published.
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     string `json:"content,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+func (d ScanDigestData) routeScope() routeScope { return d.Program.routeScope() }
+
+// assetColumns are the CSV columns of an asset attachment
+var assetColumns = []string{"url", "domain", "subdomain", "ip", "ipv6", "liveness", "status", "first_source", "discovered_at", "provenance", "data_terms"}
+
+// EncodeAssets renders assets as a CSV or JSON attachment and returns it
+// with its content type
+func EncodeAssets(format string, assets []*database.Asset) ([]byte, string, error) {
+	switch format {
+	case DigestFormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(assetColumns); err != nil {
+			return nil, "", err
+		}
+		for _, asset := range assets {
+			record := []string{asset.URL, asset.Domain, asset.Subdomain, asset.IP, asset.IPv6, asset.Liveness,
+				asset.Status, asset.FirstSource, asset.CreatedAt.UTC().Format(time.RFC3339),
+				strings.Join(asset.Sources(), ";"), strings.Join(asset.DataTerms, ";")}
+			if err := w.Write(record); err != nil {
+				return nil, "", err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, "", fmt.Errorf("failed to write CSV: %w", err)
+		}
+		return buf.Bytes(), "text/csv", nil
+	case DigestFormatJSON:
+		data := make([]AssetData, len(assets))
+		for i, asset := range assets {
+			data[i] = NewAssetData(asset)
+		}
+		content, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal assets: %w", err)
+		}
+		return content, "application/json", nil
+	default:
+		return nil, "", fmt.Errorf("unknown attachment format %q", format)
+	}
+}
+
+// DigestFilename is the file name of a digest attachment, e.g.
+// hackerone-acme-<scan id>.csv
+func DigestFilename(program ProgramData, scanID uuid.UUID, format string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, program.Platform+"-"+programHandle(program.ProgramURL))
+
+	return fmt.Sprintf("%s-%s.%s", strings.Trim(name, "-"), scanID, format)
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func digestAssets() []*database.Asset {
+	created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	return []*database.Asset{
+		{URL: "https://api.example.com", Domain: "example.com", Subdomain: "api", IP: "192.0.2.1", Liveness: "live", Status: "active", FirstSource: "chaosdb",
+			Provenance: pq.StringArray{"chaosdb", "hackerone"}, DataTerms: pq.StringArray{"ProjectDiscovery Chaos terms of use"}, CreatedAt: created},
+		{URL: "https://a,b.example.com", Domain: "example.com", Status: "active", CreatedAt: created},
+	}
+}
+
+func TestEncodeAssets_CSV(t *testing.T) {
+	content, contentType, err := EncodeAssets(DigestFormatCSV, digestAssets())
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", contentType)
+
+	records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, assetColumns, records[0])
+	assert.Equal(t, []string{"https://api.example.com", "example.com", "api", "192.0.2.1", "", "live", "active", "chaosdb", "2025-03-01T10:00:00Z",
+		"chaosdb;hackerone", "ProjectDiscovery Chaos terms of use"}, records[1])
+	assert.Equal(t, "https://a,b.example.com", records[2][0])
+}
+
+func TestEncodeAssets_JSON(t *testing.T) {
+	content, contentType, err := EncodeAssets(DigestFormatJSON, digestAssets())
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+
+	var assets []AssetData
+	require.NoError(t, json.Unmarshal(content, &assets))
+	require.Len(t, assets, 2)
+	assert.Equal(t, "https://api.example.com", assets[0].URL)
+
+	_, _, err = EncodeAssets("xlsx", digestAssets())
+	assert.Error(t, err)
+}
+
+func TestDigestFilename(t *testing.T) {
+	scanID := uuid.MustParse("6f1c5f0e-8f3a-4c55-9a4e-0d2b7c1e9a10")
+	program := ProgramData{Platform: "hackerone", ProgramURL: "https://hackerone.com/Acme Corp/"}
+
+	assert.Equal(t, "hackerone-acme-corp-6f1c5f0e-8f3a-4c55-9a4e-0d2b7c1e9a10.csv", DigestFilename(program, scanID, DigestFormatCSV))
+}
+
+func TestRouter_RoutesDigests(t *testing.T) {
+	file, err := ParseRoutes([]byte(testRoutes))
+	require.NoError(t, err)
+	router := newRouter(file, nil)
+
+	digest := ScanDigestData{Program: ProgramData{ProgramURL: "https://hackerone.com/acme"}}
+	assert.Equal(t, []string{"client-a"}, router.Channels(TypeScanDigest, digest))
+}
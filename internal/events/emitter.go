@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Publisher delivers events to a single transport
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, event *Event) error
+	Close() error
+}
+
+// Emitter builds events and fans them out to every configured publisher.
+// A nil Emitter or one without publishers drops events.
+type Emitter struct {
+	source     string
+	publishers []Publisher
+}
+
+// NewEmitter creates a new emitter for the given source
+func NewEmitter(source string, publishers ...Publisher) *Emitter {
+	if source == "" {
+		source = DefaultSource
+	}
+
+	return &Emitter{
+		source:     source,
+		publishers: publishers,
+	}
+}
+
+// AddPublisher adds a publisher to the emitter. Publishers must be added
+// before events are emitted.
+func (e *Emitter) AddPublisher(publisher Publisher) {
+	e.publishers = append(e.publishers, publisher)
+}
+
+// Enabled reports whether emitted events are delivered anywhere
+func (e *Emitter) Enabled() bool {
+	return e != nil && len(e.publishers) > 0
+}
+
+// Emit publishes an event to every publisher. Delivery failures are logged
+// rather than returned so they never fail a scan.
+func (e *Emitter) Emit(ctx context.Context, eventType, subject string, data any) {
+	if !e.Enabled() {
+		return
+	}
+
+	event := New(e.source, eventType, subject, data)
+	for _, publisher := range e.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			logrus.Warnf("Failed to publish %s event %s to %s: %v", eventType, event.ID, publisher.Name(), err)
+		}
+	}
+}
+
+// Close closes every publisher
+func (e *Emitter) Close() error {
+	if e == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, publisher := range e.publishers {
+		errs = append(errs, publisher.Close())
+	}
+
+	return errors.Join(errs...)
+}
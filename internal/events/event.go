@@ -0,0 +1,187 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+)
+
+// SpecVersion is the CloudEvents specification version events conform to
+const SpecVersion = "1.0"
+
+// ContentType is the media type of an event in CloudEvents structured mode
+const ContentType = "application/cloudevents+json"
+
+// Event types. These and their data payloads are a stable schema; new fields
+// may be added but existing ones are never renamed or removed.
+const (
+	TypeProgramCreated  = "program.created"
+	TypeAssetDiscovered = "asset.discovered"
+	TypeScopeChanged    = "scope.changed"
+	TypeRuleMatched     = "rule.matched"
+	TypeDomainNew       = "domain.newly_registered"
+	TypeTLSFinding      = "tls.finding"
+	TypeScanDigest      = "scan.digest"
+	TypeWatchlistAlive  = "watchlist.alive"
+	TypeCanaryFailed    = "canary.failed"
+)
+
+// DefaultSource is the event source used when none is configured
+const DefaultSource = "monitor-agent"
+
+// Event is a CloudEvents 1.0 event in structured JSON form
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            any       `json:"data"`
+}
+
+// New creates an event with a fresh ID and the current time
+func New(source, eventType, subject string, data any) *Event {
+	if source == "" {
+		source = DefaultSource
+	}
+
+	return &Event{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.New().String(),
+		Source:          source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// ProgramData is the payload of program events
+type ProgramData struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Platform   string    `json:"platform"`
+	ProgramURL string    `json:"program_url"`
+	Visibility string    `json:"visibility,omitempty"` // public or private
+}
+
+// NewProgramData builds a program payload from a database program
+func NewProgramData(program *database.Program) ProgramData {
+	return ProgramData{
+		ID:         program.ID,
+		Name:       program.Name,
+		Platform:   program.Platform,
+		ProgramURL: program.ProgramURL,
+		Visibility: program.Visibility,
+	}
+}
+
+// AssetData is the payload of asset events
+type AssetData struct {
+	ID          uuid.UUID  `json:"id"`
+	ProgramID   uuid.UUID  `json:"program_id"`
+	ProgramURL  string     `json:"program_url"`
+	ProgramName string     `json:"program_name,omitempty"` // set on asset.discovered events
+	URL         string     `json:"url"`
+	Domain      string     `json:"domain"`
+	Subdomain   string     `json:"subdomain"`
+	IP          string     `json:"ip,omitempty"`
+	IPv6        string     `json:"ipv6,omitempty"`
+	Liveness    string     `json:"liveness,omitempty"`
+	Status      string     `json:"status"`
+	Source      string     `json:"source"`
+	FirstSource string     `json:"first_source"`
+	Provenance  []string   `json:"provenance"`           // every discovery source that found the asset
+	DataTerms   []string   `json:"data_terms,omitempty"` // usage terms the data of those sources comes with
+	ScanID      *uuid.UUID `json:"scan_id,omitempty"`
+}
+
+// NewAssetData builds an asset payload from a database asset
+func NewAssetData(asset *database.Asset) AssetData {
+	return AssetData{
+		ID:          asset.ID,
+		ProgramID:   asset.ProgramID,
+		ProgramURL:  asset.ProgramURL,
+		URL:         asset.URL,
+		Domain:      asset.Domain,
+		Subdomain:   asset.Subdomain,
+		IP:          asset.IP,
+		IPv6:        asset.IPv6,
+		Liveness:    asset.Liveness,
+		Status:      asset.Status,
+		Source:      asset.Source,
+		FirstSource: asset.FirstSource,
+		Provenance:  asset.Sources(),
+		DataTerms:   asset.DataTerms,
+		ScanID:      asset.FirstScanID,
+	}
+}
+
+// ScopeChangedData is the payload of scope.changed events; Added and Removed
+// hold the URLs of in-scope targets
+type ScopeChangedData struct {
+	Program ProgramData `json:"program"`
+	Added   []string    `json:"added"`
+	Removed []string    `json:"removed"`
+}
+
+// RuleMatchedData is the payload of rule.matched events; consumers route it
+// by Notify and run the Enqueue jobs
+type RuleMatchedData struct {
+	Rule       string    `json:"rule"`
+	Asset      AssetData `json:"asset"`
+	StatusCode int       `json:"status_code"`
+	Title      string    `json:"title"`
+	Tags       []string  `json:"tags"`
+	Notify     string    `json:"notify,omitempty"`
+	Enqueue    []string  `json:"enqueue"`
+}
+
+// DomainRegistrationData is the payload of domain.newly_registered events
+type DomainRegistrationData struct {
+	Program      ProgramData `json:"program"`
+	Domain       string      `json:"domain"`
+	Registrar    string      `json:"registrar"`
+	RegisteredAt time.Time   `json:"registered_at"`
+	AgeDays      int         `json:"age_days"`
+}
+
+// TLSFindingData is the payload of tls.finding events, emitted when a TLS
+// misconfiguration is first found on an asset or comes back after being resolved
+type TLSFindingData struct {
+	Asset    AssetData `json:"asset"`
+	URL      string    `json:"url"`
+	Check    string    `json:"check"`
+	Severity string    `json:"severity"`
+	Detail   string    `json:"detail"`
+}
+
+// WatchlistAliveData is the payload of watchlist.alive events, emitted the
+// moment a watched hostname starts resolving or starts responding. Program is
+// set when the hostname was watched for a program.
+type WatchlistAliveData struct {
+	Hostname      string       `json:"hostname"`
+	Program       *ProgramData `json:"program,omitempty"`
+	Note          string       `json:"note,omitempty"`
+	PreviousState string       `json:"previous_state"`
+	State         string       `json:"state"`
+	IP            string       `json:"ip,omitempty"`
+	StatusCode    int          `json:"status_code,omitempty"`
+}
+
+// CanaryFailedData is the payload of canary.failed events, emitted when a
+// canary hostname did not resolve or probe as expected before a scan, which
+// points at the pipeline itself (DNS egress, probe configuration) rather than
+// the targets
+type CanaryFailedData struct {
+	Hostname           string `json:"hostname"`
+	Reason             string `json:"reason"`
+	ExpectedStatusCode int    `json:"expected_status_code,omitempty"`
+	StatusCode         int    `json:"status_code,omitempty"`
+	IP                 string `json:"ip,omitempty"`
+	ScanAborted        bool   `json:"scan_aborted"`
+}
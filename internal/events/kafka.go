@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher writes events to a Kafka topic in CloudEvents structured mode.
+// Messages are keyed by the event subject so changes to one program or asset
+// stay ordered within a partition.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// KafkaConfig holds configuration for the Kafka publisher
+type KafkaConfig struct {
+	Brokers []string
+	Topic   string
+	Timeout time.Duration
+}
+
+// NewKafkaPublisher creates a new Kafka publisher
+func NewKafkaPublisher(config *KafkaConfig) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(config.Brokers...),
+			Topic:                  config.Topic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireOne,
+			WriteTimeout:           config.Timeout,
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+// Name returns the publisher name used in logs
+func (p *KafkaPublisher) Name() string {
+	return "kafka"
+}
+
+// Publish writes a single event to the topic
+func (p *KafkaPublisher) Publish(ctx context.Context, event *Event) error {
+	message, err := kafkaMessage(event)
+	if err != nil {
+		return err
+	}
+
+	if err := p.writer.WriteMessages(ctx, message); err != nil {
+		return fmt.Errorf("failed to write event to kafka: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes pending messages and closes the writer
+func (p *KafkaPublisher) Close() error {
+	if err := p.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close kafka writer: %w", err)
+	}
+	return nil
+}
+
+// kafkaMessage encodes an event as a Kafka message
+func kafkaMessage(event *Event) (kafka.Message, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return kafka.Message{
+		Key:   []byte(event.Subject),
+		Value: body,
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte(ContentType)},
+		},
+	}, nil
+}
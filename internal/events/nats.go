@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes events to NATS in CloudEvents structured mode. Each
+// event type gets its own subject under the configured prefix, e.g.
+// monitor-agent.events.asset.discovered, so consumers can subscribe to one type
+// or to all of them with monitor-agent.events.>
+type NATSPublisher struct {
+	conn    *nats.Conn
+	subject string
+	timeout time.Duration
+}
+
+// NATSConfig holds configuration for the NATS publisher
+type NATSConfig struct {
+	URL     string
+	Subject string
+	Timeout time.Duration
+}
+
+// NewNATSPublisher connects to NATS and creates a new publisher
+func NewNATSPublisher(config *NATSConfig) (*NATSPublisher, error) {
+	conn, err := nats.Connect(config.URL, nats.Name("monitor-agent"), nats.Timeout(config.Timeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	return &NATSPublisher{
+		conn:    conn,
+		subject: config.Subject,
+		timeout: config.Timeout,
+	}, nil
+}
+
+// Name returns the publisher name used in logs
+func (p *NATSPublisher) Name() string {
+	return "nats"
+}
+
+// Publish publishes a single event on the subject for its type
+func (p *NATSPublisher) Publish(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	msg := nats.NewMsg(natsSubject(p.subject, event.Type))
+	msg.Header.Set("Content-Type", ContentType)
+	msg.Data = body
+
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("failed to publish event to nats: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes pending events and closes the connection
+func (p *NATSPublisher) Close() error {
+	defer p.conn.Close()
+
+	if err := p.conn.FlushTimeout(p.timeout); err != nil {
+		return fmt.Errorf("failed to flush nats connection: %w", err)
+	}
+	return nil
+}
+
+// natsSubject returns the subject an event type is published on
+func natsSubject(prefix, eventType string) string {
+	if prefix == "" {
+		return eventType
+	}
+	return prefix + "." + eventType
+}
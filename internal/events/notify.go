@@ -0,0 +1,352 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+	"github.com/sirupsen/logrus"
+)
+
+// discordMessageLimit is the most characters Discord accepts in a message
+const discordMessageLimit = 2000
+
+// Notification is a newly discovered program or asset, or a program's scope
+// change, as notification sinks receive it
+type Notification struct {
+	Type       string    `json:"type"` // program.created, asset.discovered or scope.changed
+	Program    string    `json:"program"`
+	Platform   string    `json:"platform,omitempty"`
+	ProgramURL string    `json:"program_url"`
+	AssetURL   string    `json:"asset_url,omitempty"`
+	Source     string    `json:"source,omitempty"`  // discovery source that found the asset first
+	Added      []string  `json:"added,omitempty"`   // in-scope targets the program added
+	Removed    []string  `json:"removed,omitempty"` // in-scope targets the program removed
+	Time       time.Time `json:"time"`
+}
+
+// NotifierConfig holds configuration for the notifier. A sink is enabled by
+// setting its URL.
+type NotifierConfig struct {
+	SlackWebhookURL   string
+	DiscordWebhookURL string
+	WebhookURL        string
+	WebhookSecret     string // signs generic webhook bodies like EVENTS_WEBHOOK_SECRET
+
+	BatchSize     int           // notifications sent in one message at most
+	BatchInterval time.Duration // buffered notifications are sent at least this often; 0 waits for a full batch or the end of the scan
+
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// notificationSink delivers batches of notifications to one destination
+type notificationSink struct {
+	name   string
+	url    string
+	secret string
+	encode func(batch []Notification) []any // request bodies a batch is sent as
+}
+
+// Notifier is a publisher that turns program.created, asset.discovered and
+// scope.changed events into human-readable notifications for Slack, Discord and generic
+// webhooks. Notifications are batched: a batch is sent when it is full, when
+// a program's scan ends with its scan.digest event, when the batch interval
+// passes and when the notifier closes.
+type Notifier struct {
+	httpClient *resty.Client
+	sinks      []*notificationSink
+	batchSize  int
+	interval   time.Duration
+
+	mu      sync.Mutex
+	pending []Notification
+	timer   *time.Timer
+}
+
+// NewNotifier creates a notifier with a sink for every configured URL
+func NewNotifier(config *NotifierConfig) *Notifier {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 4)
+	// Chat webhooks answer rate limits and outages with a status rather than an error
+	client.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		return err != nil || resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= 500
+	})
+
+	client.SetHeaders(map[string]string{
+		"Content-Type": "application/json",
+		"User-Agent":   version.UserAgent(),
+	})
+
+	var sinks []*notificationSink
+	if config.SlackWebhookURL != "" {
+		sinks = append(sinks, &notificationSink{name: "slack", url: config.SlackWebhookURL, encode: slackMessages})
+	}
+	if config.DiscordWebhookURL != "" {
+		sinks = append(sinks, &notificationSink{name: "discord", url: config.DiscordWebhookURL, encode: discordMessages})
+	}
+	if config.WebhookURL != "" {
+		sinks = append(sinks, &notificationSink{name: "webhook", url: config.WebhookURL, secret: config.WebhookSecret, encode: webhookBatches})
+	}
+
+	batchSize := config.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	return &Notifier{
+		httpClient: client,
+		sinks:      sinks,
+		batchSize:  batchSize,
+		interval:   config.BatchInterval,
+	}
+}
+
+// Name returns the publisher name used in logs
+func (n *Notifier) Name() string {
+	return "notify"
+}
+
+// Sinks returns the names of the enabled sinks
+func (n *Notifier) Sinks() []string {
+	names := make([]string, len(n.sinks))
+	for i, sink := range n.sinks {
+		names[i] = sink.name
+	}
+	return names
+}
+
+// Publish queues a notification for program.created, asset.discovered and
+// scope.changed events and sends the queued ones on scan.digest events. Other events are
+// ignored.
+func (n *Notifier) Publish(ctx context.Context, event *Event) error {
+	switch event.Type {
+	case TypeProgramCreated, TypeAssetDiscovered, TypeScopeChanged:
+		notification, ok := newNotification(event)
+		if !ok {
+			return nil
+		}
+		if batch := n.queue(notification); batch != nil {
+			return n.send(ctx, batch)
+		}
+		return nil
+	case TypeScanDigest:
+		return n.Flush(ctx)
+	default:
+		return nil
+	}
+}
+
+// queue adds a notification to the pending batch and returns the batch once
+// it is full
+func (n *Notifier) queue(notification Notification) []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.pending = append(n.pending, notification)
+	if len(n.pending) >= n.batchSize {
+		return n.takeLocked()
+	}
+	if n.timer == nil && n.interval > 0 {
+		n.timer = time.AfterFunc(n.interval, func() {
+			if err := n.Flush(context.Background()); err != nil {
+				logrus.Warnf("Failed to send notifications: %v", err)
+			}
+		})
+	}
+	return nil
+}
+
+// takeLocked empties the pending batch and stops its timer; n.mu must be held
+func (n *Notifier) takeLocked() []Notification {
+	batch := n.pending
+	n.pending = nil
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	return batch
+}
+
+// Flush sends the pending notifications
+func (n *Notifier) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	batch := n.takeLocked()
+	n.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return n.send(ctx, batch)
+}
+
+// send delivers a batch to every sink
+func (n *Notifier) send(ctx context.Context, batch []Notification) error {
+	var errs []error
+	for _, sink := range n.sinks {
+		if err := n.post(ctx, sink, batch); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// post sends a batch to a sink as one or more requests
+func (n *Notifier) post(ctx context.Context, sink *notificationSink, batch []Notification) error {
+	for _, payload := range sink.encode(batch) {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notification: %w", err)
+		}
+
+		req := n.httpClient.R().SetContext(ctx).SetBody(body)
+		if sink.secret != "" {
+			req.SetHeader(SignatureHeader, "sha256="+Sign(sink.secret, body))
+		}
+
+		resp, err := req.Post(sink.url)
+		if err != nil {
+			return fmt.Errorf("failed to post notification: %w", err)
+		}
+		if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+			return fmt.Errorf("webhook returned status %d", resp.StatusCode())
+		}
+	}
+
+	return nil
+}
+
+// Close sends the pending notifications
+func (n *Notifier) Close() error {
+	return n.Flush(context.Background())
+}
+
+// newNotification builds the notification of a program.created,
+// asset.discovered or scope.changed event
+func newNotification(event *Event) (Notification, bool) {
+	switch data := event.Data.(type) {
+	case ProgramData:
+		return Notification{
+			Type:       event.Type,
+			Program:    data.Name,
+			Platform:   data.Platform,
+			ProgramURL: data.ProgramURL,
+			Time:       event.Time,
+		}, true
+	case AssetData:
+		program := data.ProgramName
+		if program == "" {
+			program = programHandle(data.ProgramURL)
+		}
+		source := data.FirstSource
+		if source == "" {
+			source = data.Source
+		}
+		return Notification{
+			Type:       event.Type,
+			Program:    program,
+			ProgramURL: data.ProgramURL,
+			AssetURL:   data.URL,
+			Source:     source,
+			Time:       event.Time,
+		}, true
+	case ScopeChangedData:
+		if len(data.Added) == 0 && len(data.Removed) == 0 {
+			return Notification{}, false
+		}
+		return Notification{
+			Type:       event.Type,
+			Program:    data.Program.Name,
+			Platform:   data.Program.Platform,
+			ProgramURL: data.Program.ProgramURL,
+			Added:      data.Added,
+			Removed:    data.Removed,
+			Time:       event.Time,
+		}, true
+	default:
+		return Notification{}, false
+	}
+}
+
+// notificationLine describes a notification in one line of a chat message
+func notificationLine(n Notification) string {
+	if n.Type == TypeProgramCreated {
+		if n.Platform != "" {
+			return fmt.Sprintf("New program: %s (%s) %s", n.Program, n.Platform, n.ProgramURL)
+		}
+		return fmt.Sprintf("New program: %s %s", n.Program, n.ProgramURL)
+	}
+	if n.Type == TypeScopeChanged {
+		var changes []string
+		if len(n.Added) > 0 {
+			changes = append(changes, fmt.Sprintf("%d added (%s)", len(n.Added), strings.Join(n.Added, ", ")))
+		}
+		if len(n.Removed) > 0 {
+			changes = append(changes, fmt.Sprintf("%d removed (%s)", len(n.Removed), strings.Join(n.Removed, ", ")))
+		}
+		return fmt.Sprintf("Scope of %s changed: %s", n.Program, strings.Join(changes, ", "))
+	}
+
+	line := fmt.Sprintf("New asset in %s: %s", n.Program, n.AssetURL)
+	if n.Source != "" {
+		line += " (source: " + n.Source + ")"
+	}
+	return line
+}
+
+// notificationHeader is the first line of a chat message
+func notificationHeader(batch []Notification) string {
+	if len(batch) == 1 {
+		return "monitor-agent: 1 new discovery"
+	}
+	return fmt.Sprintf("monitor-agent: %d new discoveries", len(batch))
+}
+
+// slackEscaper escapes the characters Slack's mrkdwn treats as control characters
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackMessages sends a batch as one Slack incoming webhook message
+func slackMessages(batch []Notification) []any {
+	lines := []string{"*" + notificationHeader(batch) + "*"}
+	for _, n := range batch {
+		lines = append(lines, "• "+slackEscaper.Replace(notificationLine(n)))
+	}
+	return []any{map[string]string{"text": strings.Join(lines, "\n")}}
+}
+
+// discordMessages sends a batch as Discord webhook messages, split to stay
+// within Discord's message length limit
+func discordMessages(batch []Notification) []any {
+	var messages []any
+	current := "**" + notificationHeader(batch) + "**"
+	for _, n := range batch {
+		line := "- " + notificationLine(n)
+		if len(line) > discordMessageLimit {
+			line = line[:discordMessageLimit]
+		}
+		if len(current)+1+len(line) > discordMessageLimit {
+			messages = append(messages, map[string]string{"content": current})
+			current = line
+			continue
+		}
+		current += "\n" + line
+	}
+	return append(messages, map[string]string{"content": current})
+}
+
+// webhookBatches sends a batch as one JSON document for generic webhooks
+func webhookBatches(batch []Notification) []any {
+	return []any{map[string][]Notification{"notifications": batch}}
+}
@@ -0,0 +1,226 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink is a webhook endpoint recording the bodies posted to it
+type recordingSink struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	bodies []map[string]any
+	status []int // statuses answered in order; 200 once exhausted
+}
+
+func newRecordingSink(t *testing.T, status ...int) *recordingSink {
+	sink := &recordingSink{status: status}
+	sink.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		if len(sink.status) > 0 {
+			code := sink.status[0]
+			sink.status = sink.status[1:]
+			if code != http.StatusOK {
+				w.WriteHeader(code)
+				return
+			}
+		}
+
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(body, &decoded))
+		sink.bodies = append(sink.bodies, decoded)
+	}))
+	t.Cleanup(sink.server.Close)
+	return sink
+}
+
+func (s *recordingSink) received() []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]any(nil), s.bodies...)
+}
+
+func assetEvent(url, source string) *Event {
+	return New("", TypeAssetDiscovered, url, AssetData{
+		ID:          uuid.New(),
+		ProgramURL:  "https://hackerone.com/acme",
+		ProgramName: "Acme",
+		URL:         url,
+		Source:      "discovery",
+		FirstSource: source,
+	})
+}
+
+func TestNotifier_BatchesUntilScanDigest(t *testing.T) {
+	slack := newRecordingSink(t)
+	discord := newRecordingSink(t)
+	webhook := newRecordingSink(t)
+
+	notifier := NewNotifier(&NotifierConfig{
+		SlackWebhookURL:   slack.server.URL,
+		DiscordWebhookURL: discord.server.URL,
+		WebhookURL:        webhook.server.URL,
+		BatchSize:         10,
+		Timeout:           time.Second,
+	})
+	assert.Equal(t, []string{"slack", "discord", "webhook"}, notifier.Sinks())
+
+	ctx := context.Background()
+	require.NoError(t, notifier.Publish(ctx, New("", TypeProgramCreated, "https://hackerone.com/acme", ProgramData{
+		ID:         uuid.New(),
+		Name:       "Acme",
+		Platform:   "hackerone",
+		ProgramURL: "https://hackerone.com/acme",
+	})))
+	require.NoError(t, notifier.Publish(ctx, assetEvent("https://api.acme.com", "chaosdb")))
+	require.NoError(t, notifier.Publish(ctx, New("", TypeScopeChanged, "https://hackerone.com/acme", ScopeChangedData{})))
+	assert.Empty(t, webhook.received(), "notifications are held until the batch is sent")
+
+	require.NoError(t, notifier.Publish(ctx, New("", TypeScanDigest, "https://hackerone.com/acme", ScanDigestData{})))
+
+	require.Len(t, slack.received(), 1)
+	assert.Equal(t, "*monitor-agent: 2 new discoveries*\n"+
+		"• New program: Acme (hackerone) https://hackerone.com/acme\n"+
+		"• New asset in Acme: https://api.acme.com (source: chaosdb)", slack.received()[0]["text"])
+
+	require.Len(t, discord.received(), 1)
+	assert.Equal(t, "**monitor-agent: 2 new discoveries**\n"+
+		"- New program: Acme (hackerone) https://hackerone.com/acme\n"+
+		"- New asset in Acme: https://api.acme.com (source: chaosdb)", discord.received()[0]["content"])
+
+	require.Len(t, webhook.received(), 1)
+	notifications := webhook.received()[0]["notifications"].([]any)
+	require.Len(t, notifications, 2)
+	asset := notifications[1].(map[string]any)
+	assert.Equal(t, "asset.discovered", asset["type"])
+	assert.Equal(t, "Acme", asset["program"])
+	assert.Equal(t, "https://api.acme.com", asset["asset_url"])
+	assert.Equal(t, "chaosdb", asset["source"])
+
+	// Nothing is pending after the digest
+	require.NoError(t, notifier.Close())
+	assert.Len(t, webhook.received(), 1)
+}
+
+func TestNotifier_SendsFullBatches(t *testing.T) {
+	webhook := newRecordingSink(t)
+	notifier := NewNotifier(&NotifierConfig{WebhookURL: webhook.server.URL, BatchSize: 2, Timeout: time.Second})
+
+	ctx := context.Background()
+	for _, url := range []string{"https://a.acme.com", "https://b.acme.com", "https://c.acme.com"} {
+		require.NoError(t, notifier.Publish(ctx, assetEvent(url, "crtsh")))
+	}
+	require.Len(t, webhook.received(), 1)
+	assert.Len(t, webhook.received()[0]["notifications"], 2)
+
+	require.NoError(t, notifier.Close())
+	require.Len(t, webhook.received(), 2)
+	assert.Len(t, webhook.received()[1]["notifications"], 1)
+}
+
+func TestNotifier_SendsAfterBatchInterval(t *testing.T) {
+	webhook := newRecordingSink(t)
+	notifier := NewNotifier(&NotifierConfig{WebhookURL: webhook.server.URL, BatchSize: 10, BatchInterval: 20 * time.Millisecond, Timeout: time.Second})
+	defer notifier.Close()
+
+	require.NoError(t, notifier.Publish(context.Background(), assetEvent("https://a.acme.com", "crtsh")))
+
+	assert.Eventually(t, func() bool { return len(webhook.received()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestNotifier_RetriesFailedDeliveries(t *testing.T) {
+	webhook := newRecordingSink(t, http.StatusTooManyRequests, http.StatusBadGateway)
+	notifier := NewNotifier(&NotifierConfig{WebhookURL: webhook.server.URL, BatchSize: 1, Timeout: time.Second, RetryAttempts: 2, RetryDelay: time.Millisecond})
+
+	require.NoError(t, notifier.Publish(context.Background(), assetEvent("https://a.acme.com", "crtsh")))
+	assert.Len(t, webhook.received(), 1)
+}
+
+func TestNotifier_ReportsFailedSink(t *testing.T) {
+	failing := newRecordingSink(t, http.StatusNotFound)
+	webhook := newRecordingSink(t)
+	notifier := NewNotifier(&NotifierConfig{SlackWebhookURL: failing.server.URL, WebhookURL: webhook.server.URL, BatchSize: 1, Timeout: time.Second})
+
+	err := notifier.Publish(context.Background(), assetEvent("https://a.acme.com", "crtsh"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "slack: webhook returned status 404")
+	assert.Len(t, webhook.received(), 1, "other sinks still receive the batch")
+}
+
+func TestNotifier_SignsGenericWebhook(t *testing.T) {
+	var signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(&NotifierConfig{WebhookURL: server.URL, WebhookSecret: "secret", BatchSize: 1, Timeout: time.Second})
+	require.NoError(t, notifier.Publish(context.Background(), assetEvent("https://a.acme.com", "crtsh")))
+
+	assert.Equal(t, "sha256="+Sign("secret", body), signature)
+}
+
+func TestNewNotification_FallsBackToProgramHandle(t *testing.T) {
+	notification, ok := newNotification(New("", TypeAssetDiscovered, "https://a.acme.com", AssetData{
+		ProgramURL: "https://hackerone.com/acme",
+		URL:        "https://a.acme.com",
+		Source:     "discovery",
+	}))
+	require.True(t, ok)
+	assert.Equal(t, "acme", notification.Program)
+	assert.Equal(t, "discovery", notification.Source)
+}
+
+func TestNotificationLine_ScopeChanged(t *testing.T) {
+	event := New("", TypeScopeChanged, "https://hackerone.com/acme", ScopeChangedData{
+		Program: ProgramData{Name: "Acme", ProgramURL: "https://hackerone.com/acme"},
+		Added:   []string{"*.acme.io", "api.acme.com"},
+		Removed: []string{"legacy.acme.com"},
+	})
+
+	notification, ok := newNotification(event)
+	require.True(t, ok)
+	assert.Equal(t, "Scope of Acme changed: 2 added (*.acme.io, api.acme.com), 1 removed (legacy.acme.com)", notificationLine(notification))
+}
+
+func TestSlackMessages_EscapesControlCharacters(t *testing.T) {
+	messages := slackMessages([]Notification{{Type: TypeProgramCreated, Program: "A&B <Labs>", ProgramURL: "https://hackerone.com/ab"}})
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0].(map[string]string)["text"], "New program: A&amp;B &lt;Labs&gt; https://hackerone.com/ab")
+}
+
+func TestDiscordMessages_SplitsLongBatches(t *testing.T) {
+	var batch []Notification
+	for i := 0; i < 40; i++ {
+		batch = append(batch, Notification{Type: TypeAssetDiscovered, Program: "Acme", AssetURL: "https://" + strings.Repeat("a", 80) + ".acme.com"})
+	}
+
+	messages := discordMessages(batch)
+	require.Greater(t, len(messages), 1)
+
+	lines := 0
+	for _, message := range messages {
+		content := message.(map[string]string)["content"]
+		assert.LessOrEqual(t, len(content), discordMessageLimit)
+		lines += strings.Count(content, "- New asset in Acme")
+	}
+	assert.Equal(t, 40, lines)
+}
@@ -0,0 +1,262 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RoutesFile is the YAML document notification routes are loaded from. Each
+// route sends the events of the programs or tags it lists to its channels
+// only, so unrelated programs monitored from one deployment stay separate.
+type RoutesFile struct {
+	Channels map[string]*Channel `yaml:"channels"`
+	Routes   []*Route            `yaml:"routes"`
+	Default  []string            `yaml:"default"` // channels for events that match no route
+}
+
+// Channel is a notification destination events are POSTed to in CloudEvents
+// structured mode, like EVENTS_WEBHOOK_URL
+type Channel struct {
+	WebhookURL string `yaml:"webhook_url"`
+	Secret     string `yaml:"secret"` // signs bodies like EVENTS_WEBHOOK_SECRET; ${VAR} is expanded
+}
+
+// Route sends matching events to its channels. An event matches when it
+// belongs to one of the listed programs or carries one of the listed tags,
+// and its type is listed (all types when Types is empty).
+type Route struct {
+	Name     string   `yaml:"name"`
+	Programs []string `yaml:"programs"` // program URLs or handles; * and ? wildcards allowed
+	Tags     []string `yaml:"tags"`     // asset tags attached by triage rules (rule.matched events)
+	Types    []string `yaml:"types"`
+	Channels []string `yaml:"channels"`
+}
+
+// LoadRoutesFile loads and validates notification routes from a YAML file
+func LoadRoutesFile(filePath string) (*RoutesFile, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes file %s: %w", filePath, err)
+	}
+
+	return ParseRoutes(data)
+}
+
+// ParseRoutes parses and validates notification routes from YAML
+func ParseRoutes(data []byte) (*RoutesFile, error) {
+	var file RoutesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse routes: %w", err)
+	}
+
+	if err := file.validate(); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// validate checks that every route and channel is usable
+func (f *RoutesFile) validate() error {
+	for name, channel := range f.Channels {
+		if channel == nil {
+			return fmt.Errorf("channel %q has no webhook_url", name)
+		}
+		channel.WebhookURL = os.ExpandEnv(channel.WebhookURL)
+		channel.Secret = os.ExpandEnv(channel.Secret)
+		if !strings.HasPrefix(channel.WebhookURL, "http://") && !strings.HasPrefix(channel.WebhookURL, "https://") {
+			return fmt.Errorf("channel %q webhook_url must start with http:// or https://", name)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, route := range f.Routes {
+		if route.Name == "" {
+			return fmt.Errorf("route %d has no name", i+1)
+		}
+		if names[route.Name] {
+			return fmt.Errorf("duplicate route name %q", route.Name)
+		}
+		names[route.Name] = true
+
+		if len(route.Programs) == 0 && len(route.Tags) == 0 {
+			return fmt.Errorf("route %q lists no programs or tags", route.Name)
+		}
+		for _, pattern := range route.Programs {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("route %q has an invalid program pattern %q: %w", route.Name, pattern, err)
+			}
+		}
+		if len(route.Channels) == 0 {
+			return fmt.Errorf("route %q has no channels", route.Name)
+		}
+		if err := f.checkChannels(route.Channels); err != nil {
+			return fmt.Errorf("route %q: %w", route.Name, err)
+		}
+	}
+
+	if err := f.checkChannels(f.Default); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	return nil
+}
+
+// checkChannels reports channel names that are not defined
+func (f *RoutesFile) checkChannels(names []string) error {
+	for _, name := range names {
+		if _, ok := f.Channels[name]; !ok {
+			return fmt.Errorf("unknown channel %q", name)
+		}
+	}
+	return nil
+}
+
+// matches reports whether an event of the given type and scope matches the route
+func (r *Route) matches(eventType string, scope routeScope) bool {
+	if len(r.Types) > 0 && !slices.Contains(r.Types, eventType) {
+		return false
+	}
+
+	for _, pattern := range r.Programs {
+		if scope.programURL == "" {
+			break
+		}
+		if matched, _ := path.Match(pattern, scope.programURL); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, programHandle(scope.programURL)); matched {
+			return true
+		}
+	}
+
+	return slices.ContainsFunc(r.Tags, func(tag string) bool {
+		return slices.Contains(scope.tags, tag)
+	})
+}
+
+// programHandle returns the last path segment of a program URL, e.g. acme for
+// https://hackerone.com/acme
+func programHandle(programURL string) string {
+	return path.Base(strings.TrimSuffix(programURL, "/"))
+}
+
+// routeScope is what routes match an event on
+type routeScope struct {
+	programURL string
+	tags       []string
+}
+
+// routable is implemented by event payloads that belong to a program
+type routable interface {
+	routeScope() routeScope
+}
+
+func (d ProgramData) routeScope() routeScope { return routeScope{programURL: d.ProgramURL} }
+
+func (d AssetData) routeScope() routeScope { return routeScope{programURL: d.ProgramURL} }
+
+func (d ScopeChangedData) routeScope() routeScope { return d.Program.routeScope() }
+
+func (d RuleMatchedData) routeScope() routeScope {
+	return routeScope{programURL: d.Asset.ProgramURL, tags: d.Tags}
+}
+
+func (d DomainRegistrationData) routeScope() routeScope { return d.Program.routeScope() }
+
+func (d TLSFindingData) routeScope() routeScope { return d.Asset.routeScope() }
+
+func (d WatchlistAliveData) routeScope() routeScope {
+	if d.Program == nil {
+		return routeScope{}
+	}
+	return d.Program.routeScope()
+}
+
+// Router is a publisher that delivers each event to the channels of the
+// routes it matches, or to the default channels when it matches none
+type Router struct {
+	routes   []*Route
+	defaults []string
+	channels map[string]Publisher
+}
+
+// NewRouter creates a router with a webhook publisher per channel; timeouts
+// and retries are taken from webhook
+func NewRouter(file *RoutesFile, webhook WebhookConfig) *Router {
+	channels := make(map[string]Publisher, len(file.Channels))
+	for name, channel := range file.Channels {
+		config := webhook
+		config.URL = channel.WebhookURL
+		config.Secret = channel.Secret
+		channels[name] = NewWebhookPublisher(&config)
+	}
+
+	return newRouter(file, channels)
+}
+
+// newRouter creates a router over existing channel publishers
+func newRouter(file *RoutesFile, channels map[string]Publisher) *Router {
+	return &Router{
+		routes:   file.Routes,
+		defaults: file.Default,
+		channels: channels,
+	}
+}
+
+// Name returns the publisher name used in logs
+func (r *Router) Name() string {
+	return "router"
+}
+
+// Channels returns the channels an event is routed to, in route order
+func (r *Router) Channels(eventType string, data any) []string {
+	var scope routeScope
+	if payload, ok := data.(routable); ok {
+		scope = payload.routeScope()
+	}
+
+	var channels []string
+	for _, route := range r.routes {
+		if !route.matches(eventType, scope) {
+			continue
+		}
+		for _, channel := range route.Channels {
+			if !slices.Contains(channels, channel) {
+				channels = append(channels, channel)
+			}
+		}
+	}
+
+	if len(channels) == 0 {
+		return r.defaults
+	}
+	return channels
+}
+
+// Publish delivers an event to every channel it is routed to
+func (r *Router) Publish(ctx context.Context, event *Event) error {
+	var errs []error
+	for _, name := range r.Channels(event.Type, event.Data) {
+		if err := r.channels[name].Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes every channel publisher
+func (r *Router) Close() error {
+	var errs []error
+	for _, channel := range r.channels {
+		errs = append(errs, channel.Close())
+	}
+
+	return errors.Join(errs...)
+}
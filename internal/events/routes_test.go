@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRoutes = `
+channels:
+  client-a:
+    webhook_url: https://hooks.example.com/a
+  client-b:
+    webhook_url: https://hooks.example.com/b
+    secret: ${TEST_ROUTES_SECRET}
+  triage:
+    webhook_url: https://hooks.example.com/triage
+routes:
+  - name: client-a
+    programs: [acme, "https://bugcrowd.com/acme-*"]
+    channels: [client-a]
+  - name: client-b
+    programs: ["https://hackerone.com/globex"]
+    types: [asset.discovered, scope.changed]
+    channels: [client-b]
+  - name: critical
+    tags: [grafana, jenkins]
+    channels: [triage, client-a]
+default: [triage]
+`
+
+func TestParseRoutes(t *testing.T) {
+	t.Setenv("TEST_ROUTES_SECRET", "s3cret")
+
+	file, err := ParseRoutes([]byte(testRoutes))
+	require.NoError(t, err)
+	assert.Len(t, file.Routes, 3)
+	assert.Equal(t, "s3cret", file.Channels["client-b"].Secret)
+	assert.Equal(t, []string{"triage"}, file.Default)
+}
+
+func TestParseRoutes_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{
+			name: "unknown channel",
+			yaml: "routes:\n  - name: a\n    programs: [acme]\n    channels: [missing]\n",
+			want: `unknown channel "missing"`,
+		},
+		{
+			name: "no programs or tags",
+			yaml: "channels:\n  a:\n    webhook_url: https://hooks.example.com\nroutes:\n  - name: a\n    channels: [a]\n",
+			want: "lists no programs or tags",
+		},
+		{
+			name: "bad webhook url",
+			yaml: "channels:\n  a:\n    webhook_url: hooks.example.com\n",
+			want: "must start with http:// or https://",
+		},
+		{
+			name: "bad pattern",
+			yaml: "channels:\n  a:\n    webhook_url: https://hooks.example.com\nroutes:\n  - name: a\n    programs: [\"[\"]\n    channels: [a]\n",
+			want: "invalid program pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRoutes([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestRouter_Channels(t *testing.T) {
+	file, err := ParseRoutes([]byte(testRoutes))
+	require.NoError(t, err)
+	router := newRouter(file, nil)
+
+	acme := AssetData{ProgramURL: "https://hackerone.com/acme"}
+	globex := AssetData{ProgramURL: "https://hackerone.com/globex"}
+
+	assert.Equal(t, []string{"client-a"}, router.Channels(TypeAssetDiscovered, acme))
+	assert.Equal(t, []string{"client-a"}, router.Channels(TypeProgramCreated, ProgramData{ProgramURL: "https://bugcrowd.com/acme-corp"}))
+	assert.Equal(t, []string{"client-b"}, router.Channels(TypeAssetDiscovered, globex))
+	assert.Equal(t, []string{"triage"}, router.Channels(TypeTLSFinding, TLSFindingData{Asset: globex}), "type not routed for client-b")
+	assert.Equal(t, []string{"triage", "client-a"},
+		router.Channels(TypeRuleMatched, RuleMatchedData{Asset: globex, Tags: []string{"grafana"}}))
+	assert.Equal(t, []string{"triage"}, router.Channels(TypeAssetDiscovered, AssetData{ProgramURL: "https://hackerone.com/initech"}))
+	assert.Equal(t, []string{"triage"}, router.Channels("custom", nil))
+}
+
+func TestRouter_Publish(t *testing.T) {
+	file, err := ParseRoutes([]byte(testRoutes))
+	require.NoError(t, err)
+
+	channels := map[string]Publisher{}
+	for _, name := range []string{"client-a", "client-b", "triage"} {
+		channels[name] = &recordingPublisher{}
+	}
+	router := newRouter(file, channels)
+
+	event := New("", TypeScopeChanged, "https://hackerone.com/acme", ScopeChangedData{
+		Program: ProgramData{ProgramURL: "https://hackerone.com/acme"},
+	})
+	require.NoError(t, router.Publish(context.Background(), event))
+
+	assert.Len(t, channels["client-a"].(*recordingPublisher).events, 1)
+	assert.Empty(t, channels["client-b"].(*recordingPublisher).events)
+	assert.Empty(t, channels["triage"].(*recordingPublisher).events)
+}
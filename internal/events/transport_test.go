@@ -0,0 +1,31 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaMessage(t *testing.T) {
+	event := New("edge-1", TypeAssetDiscovered, "https://api.example.com", AssetData{URL: "https://api.example.com"})
+
+	message, err := kafkaMessage(event)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.example.com", string(message.Key))
+	require.Len(t, message.Headers, 1)
+	assert.Equal(t, "content-type", message.Headers[0].Key)
+	assert.Equal(t, ContentType, string(message.Headers[0].Value))
+
+	var decoded Event
+	require.NoError(t, json.Unmarshal(message.Value, &decoded))
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, TypeAssetDiscovered, decoded.Type)
+}
+
+func TestNATSSubject(t *testing.T) {
+	assert.Equal(t, "monitor-agent.events.scope.changed", natsSubject("monitor-agent.events", TypeScopeChanged))
+	assert.Equal(t, "program.created", natsSubject("", TypeProgramCreated))
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body when a webhook secret is set
+const SignatureHeader = "X-Monitor-Agent-Signature"
+
+// WebhookPublisher posts events to an HTTP endpoint in CloudEvents structured mode
+type WebhookPublisher struct {
+	httpClient *resty.Client
+	url        string
+	secret     string
+}
+
+// WebhookConfig holds configuration for the webhook publisher
+type WebhookConfig struct {
+	URL           string
+	Secret        string
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// NewWebhookPublisher creates a new webhook publisher
+func NewWebhookPublisher(config *WebhookConfig) *WebhookPublisher {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+
+	client.SetHeaders(map[string]string{
+		"Content-Type": ContentType,
+		"User-Agent":   version.UserAgent(),
+	})
+
+	return &WebhookPublisher{
+		httpClient: client,
+		url:        config.URL,
+		secret:     config.Secret,
+	}
+}
+
+// Name returns the publisher name used in logs
+func (p *WebhookPublisher) Name() string {
+	return "webhook"
+}
+
+// Publish posts a single event to the webhook
+func (p *WebhookPublisher) Publish(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req := p.httpClient.R().SetContext(ctx).SetBody(body)
+	if p.secret != "" {
+		req.SetHeader(SignatureHeader, "sha256="+Sign(p.secret, body))
+	}
+
+	resp, err := req.Post(p.url)
+	if err != nil {
+		return fmt.Errorf("failed to post event: %w", err)
+	}
+
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode())
+	}
+
+	return nil
+}
+
+// Close releases the publisher; webhooks hold no connections open
+func (p *WebhookPublisher) Close() error {
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of a body so receivers can verify the sender
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	var received map[string]any
+	var signature, contentType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+
+		signature = r.Header.Get(SignatureHeader)
+		contentType = r.Header.Get("Content-Type")
+		assert.Equal(t, "sha256="+Sign("secret", body), signature)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(&WebhookConfig{URL: server.URL, Secret: "secret", Timeout: time.Second})
+	programID := uuid.New()
+	event := New("edge-1", TypeProgramCreated, "https://hackerone.com/acme", ProgramData{
+		ID:         programID,
+		Name:       "Acme",
+		Platform:   "hackerone",
+		ProgramURL: "https://hackerone.com/acme",
+	})
+
+	require.NoError(t, publisher.Publish(context.Background(), event))
+
+	assert.Equal(t, ContentType, contentType)
+	assert.Equal(t, "1.0", received["specversion"])
+	assert.Equal(t, "edge-1", received["source"])
+	assert.Equal(t, "program.created", received["type"])
+	assert.Equal(t, event.ID, received["id"])
+	assert.Equal(t, "application/json", received["datacontenttype"])
+
+	data := received["data"].(map[string]any)
+	assert.Equal(t, programID.String(), data["id"])
+	assert.Equal(t, "https://hackerone.com/acme", data["program_url"])
+}
+
+func TestWebhookPublisher_PublishErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(&WebhookConfig{URL: server.URL, Timeout: time.Second})
+	err := publisher.Publish(context.Background(), New("", TypeScopeChanged, "", ScopeChangedData{}))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 500")
+}
+
+type recordingPublisher struct {
+	events []*Event
+}
+
+func (p *recordingPublisher) Name() string { return "recording" }
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+func TestEmitter_Emit(t *testing.T) {
+	var nilEmitter *Emitter
+	assert.False(t, nilEmitter.Enabled())
+	nilEmitter.Emit(context.Background(), TypeAssetDiscovered, "", nil)
+
+	publisher := &recordingPublisher{}
+	emitter := NewEmitter("", publisher)
+	require.True(t, emitter.Enabled())
+
+	emitter.Emit(context.Background(), TypeAssetDiscovered, "https://api.example.com", AssetData{URL: "https://api.example.com"})
+
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, DefaultSource, publisher.events[0].Source)
+	assert.Equal(t, TypeAssetDiscovered, publisher.events[0].Type)
+	assert.Equal(t, "https://api.example.com", publisher.events[0].Subject)
+	assert.NoError(t, emitter.Close())
+}
@@ -0,0 +1,211 @@
+// Package export writes assets in formats other tools read: JSON Lines,
+// CSV for spreadsheets and plain URL lists for scanners such as nuclei and
+// httpx.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+)
+
+// Formats are the formats assets can be written in
+var Formats = []string{"json", "csv", "txt"}
+
+// Record is an exported asset
+type Record struct {
+	ProgramName  string    `json:"program_name"`
+	ProgramURL   string    `json:"program_url"`
+	URL          string    `json:"url"`
+	Host         string    `json:"host"`
+	Domain       string    `json:"domain"`
+	IP           string    `json:"ip,omitempty"`
+	IPv6         string    `json:"ipv6,omitempty"`
+	CNAMEs       []string  `json:"cnames,omitempty"`
+	Status       string    `json:"status"`
+	Liveness     string    `json:"liveness,omitempty"`
+	Source       string    `json:"source"`                 // primary or secondary
+	FirstSource  string    `json:"first_source,omitempty"` // discovery source that found the asset first
+	Provenance   []string  `json:"provenance,omitempty"`   // every discovery source that found the asset
+	DataTerms    []string  `json:"data_terms,omitempty"`   // usage terms the data of those sources comes with
+	Score        float64   `json:"score"`
+	DiscoveredAt time.Time `json:"discovered_at"`
+
+	ContentChangedAt *time.Time `json:"content_changed_at,omitempty"` // when the asset's content last changed; nil when it never did
+	Response         *Response  `json:"response,omitempty"`           // latest stored response; nil when not exported or none is stored
+}
+
+// Response is the metadata of the latest stored response of an asset
+type Response struct {
+	StatusCode   int       `json:"status_code"`
+	FinalURL     string    `json:"final_url,omitempty"`
+	ResponseTime int64     `json:"response_time_ms"`
+	CapturedAt   time.Time `json:"captured_at"`
+}
+
+// NewRecords converts the assets read for an export
+func NewRecords(assets []*database.ExportAsset) []*Record {
+	records := make([]*Record, 0, len(assets))
+	for _, asset := range assets {
+		record := &Record{
+			ProgramName:  asset.ProgramName,
+			ProgramURL:   asset.ProgramURL,
+			URL:          asset.URL,
+			Host:         asset.HostKey,
+			Domain:       asset.Domain,
+			IP:           asset.IP,
+			IPv6:         asset.IPv6,
+			CNAMEs:       asset.CNAMEs,
+			Status:       asset.Status,
+			Liveness:     asset.Liveness,
+			Source:       asset.Source,
+			FirstSource:  asset.FirstSource,
+			Provenance:   asset.Sources(),
+			DataTerms:    asset.DataTerms,
+			Score:        asset.Score,
+			DiscoveredAt: asset.CreatedAt,
+
+			ContentChangedAt: asset.ContentChangedAt,
+		}
+		if asset.StatusCode != nil {
+			record.Response = &Response{StatusCode: *asset.StatusCode}
+			if asset.FinalURL != nil {
+				record.Response.FinalURL = *asset.FinalURL
+			}
+			if asset.ResponseTime != nil {
+				record.Response.ResponseTime = *asset.ResponseTime
+			}
+			if asset.RespondedAt != nil {
+				record.Response.CapturedAt = *asset.RespondedAt
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// ExcludeSources leaves out the assets that only excluded sources found, as
+// database.ExcludeSources does
+func ExcludeSources(assets []*database.ExportAsset, excluded []string) []*database.ExportAsset {
+	if len(excluded) == 0 {
+		return assets
+	}
+
+	plain := make([]*database.Asset, 0, len(assets))
+	for _, asset := range assets {
+		plain = append(plain, &asset.Asset)
+	}
+	kept := make(map[*database.Asset]bool, len(assets))
+	for _, asset := range database.ExcludeSources(plain, excluded) {
+		kept[asset] = true
+	}
+
+	result := make([]*database.ExportAsset, 0, len(kept))
+	for _, asset := range assets {
+		if kept[&asset.Asset] {
+			result = append(result, asset)
+		}
+	}
+	return result
+}
+
+// Write writes records in one of Formats. responses adds the response
+// columns to CSV output.
+func Write(w io.Writer, format string, records []*Record, responses bool) error {
+	switch format {
+	case "json":
+		return WriteJSON(w, records)
+	case "csv":
+		return WriteCSV(w, records, responses)
+	case "txt":
+		return WriteText(w, records)
+	default:
+		return fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// WriteJSON writes one JSON object per record (JSON Lines), which jq and most
+// tools read as a stream
+func WriteJSON(w io.Writer, records []*Record) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to encode asset: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the records as CSV with a header row
+func WriteCSV(w io.Writer, records []*Record, responses bool) error {
+	writer := csv.NewWriter(w)
+	header := []string{"program_name", "program_url", "url", "host", "domain", "ip", "ipv6", "cnames", "status", "liveness", "source", "first_source", "provenance", "data_terms", "score", "discovered_at", "content_changed_at"}
+	if responses {
+		header = append(header, "status_code", "final_url", "response_time_ms", "captured_at")
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, record := range records {
+		row := []string{
+			record.ProgramName,
+			record.ProgramURL,
+			record.URL,
+			record.Host,
+			record.Domain,
+			record.IP,
+			record.IPv6,
+			strings.Join(record.CNAMEs, ";"),
+			record.Status,
+			record.Liveness,
+			record.Source,
+			record.FirstSource,
+			strings.Join(record.Provenance, ";"),
+			strings.Join(record.DataTerms, ";"),
+			strconv.FormatFloat(record.Score, 'f', -1, 64),
+			record.DiscoveredAt.UTC().Format(time.RFC3339),
+			"",
+		}
+		if record.ContentChangedAt != nil {
+			row[len(row)-1] = record.ContentChangedAt.UTC().Format(time.RFC3339)
+		}
+		if responses {
+			if response := record.Response; response != nil {
+				row = append(row,
+					strconv.Itoa(response.StatusCode),
+					response.FinalURL,
+					strconv.FormatInt(response.ResponseTime, 10),
+					response.CapturedAt.UTC().Format(time.RFC3339))
+			} else {
+				row = append(row, "", "", "", "")
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteText writes one URL per line, the input list nuclei -l and httpx -l
+// take
+func WriteText(w io.Writer, records []*Record) error {
+	for _, record := range records {
+		if _, err := fmt.Fprintln(w, record.URL); err != nil {
+			return fmt.Errorf("failed to write URL list: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAssets() []*database.ExportAsset {
+	discovered := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	changed := time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC)
+	statusCode, finalURL, responseTime := 200, "https://www.example.com/login", int64(120)
+	return []*database.ExportAsset{
+		{
+			Asset: database.Asset{
+				ProgramURL: "https://hackerone.com/acme", URL: "https://www.example.com", HostKey: "www.example.com",
+				Domain: "example.com", IP: "192.0.2.5", Status: "active", Liveness: "live", Source: "primary",
+				FirstSource: "crtsh", Provenance: pq.StringArray{"crtsh", "chaosdb"},
+				DataTerms: pq.StringArray{"ProjectDiscovery Chaos terms of use"}, CNAMEs: pq.StringArray{"acme.cdn.example.net"},
+				Score: 4.5, ContentChangedAt: &changed, CreatedAt: discovered,
+			},
+			ProgramName: "Acme", StatusCode: &statusCode, FinalURL: &finalURL, ResponseTime: &responseTime, RespondedAt: &discovered,
+		},
+		{
+			Asset: database.Asset{
+				ProgramURL: "https://hackerone.com/acme", URL: "https://dev.example.com", HostKey: "dev.example.com",
+				Domain: "example.com", Status: "active", Source: "secondary", FirstSource: "chaosdb", CreatedAt: discovered,
+			},
+			ProgramName: "Acme",
+		},
+	}
+}
+
+func TestNewRecords(t *testing.T) {
+	records := NewRecords(testAssets())
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "www.example.com", records[0].Host)
+	assert.Equal(t, []string{"crtsh", "chaosdb"}, records[0].Provenance)
+	assert.Equal(t, &Response{StatusCode: 200, FinalURL: "https://www.example.com/login", ResponseTime: 120, CapturedAt: records[0].DiscoveredAt}, records[0].Response)
+
+	// Assets without provenance know their first source
+	assert.Equal(t, []string{"chaosdb"}, records[1].Provenance)
+	assert.Nil(t, records[1].Response)
+}
+
+func TestExcludeSources(t *testing.T) {
+	assets := ExcludeSources(testAssets(), []string{"chaosdb"})
+	require.Len(t, assets, 1)
+	assert.Equal(t, "https://www.example.com", assets[0].URL)
+}
+
+func TestWrite(t *testing.T) {
+	records := NewRecords(testAssets())
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "txt", records, false))
+	assert.Equal(t, "https://www.example.com\nhttps://dev.example.com\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, "csv", records, true))
+	assert.Equal(t, "program_name,program_url,url,host,domain,ip,ipv6,cnames,status,liveness,source,first_source,provenance,data_terms,score,discovered_at,content_changed_at,status_code,final_url,response_time_ms,captured_at\n"+
+		"Acme,https://hackerone.com/acme,https://www.example.com,www.example.com,example.com,192.0.2.5,,acme.cdn.example.net,active,live,primary,crtsh,crtsh;chaosdb,ProjectDiscovery Chaos terms of use,4.5,2026-03-01T12:00:00Z,2026-03-02T08:30:00Z,200,https://www.example.com/login,120,2026-03-01T12:00:00Z\n"+
+		"Acme,https://hackerone.com/acme,https://dev.example.com,dev.example.com,example.com,,,,active,,secondary,chaosdb,chaosdb,,0,2026-03-01T12:00:00Z,,,,,\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, "csv", records[1:], false))
+	assert.Equal(t, "program_name,program_url,url,host,domain,ip,ipv6,cnames,status,liveness,source,first_source,provenance,data_terms,score,discovered_at,content_changed_at\n"+
+		"Acme,https://hackerone.com/acme,https://dev.example.com,dev.example.com,example.com,,,,active,,secondary,chaosdb,chaosdb,,0,2026-03-01T12:00:00Z,\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, "json", records, true))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var decoded Record
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+	assert.Equal(t, "https://www.example.com", decoded.URL)
+	require.NotNil(t, decoded.Response)
+	assert.Equal(t, 200, decoded.Response.StatusCode)
+	assert.NotContains(t, lines[1], `"response"`)
+	assert.Contains(t, lines[0], `"content_changed_at":"2026-03-02T08:30:00Z"`)
+	assert.NotContains(t, lines[1], `"content_changed_at"`)
+
+	assert.Error(t, Write(&buf, "xml", records, false))
+}
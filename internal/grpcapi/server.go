@@ -0,0 +1,426 @@
+// Package grpcapi serves the MonitorAgent gRPC API defined in
+// proto/monitoragent/v1, so internal services can query assets, trigger
+// scans and stream events with generated clients instead of polling.
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/httpapi"
+	"github.com/monitor-agent/internal/service"
+	monitoragentv1 "github.com/monitor-agent/proto/monitoragent/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Limits of the assets returned by one call
+const (
+	DefaultAssetLimit = 100
+	MaxAssetLimit     = 1000
+)
+
+// Service is the part of the monitor service the API exposes
+type Service interface {
+	ListPrograms(ctx context.Context) ([]*database.Program, error)
+	QueryAssets(ctx context.Context, query *database.AssetQuery, limit int) ([]*database.Asset, error)
+	FindAssets(ctx context.Context, host string, limit int) ([]*database.Asset, error)
+	GetClusters(ctx context.Context, minSize, limit int) ([]*database.ResponseClusterSummary, error)
+	FindProgram(ctx context.Context, handle string) (*database.Program, error)
+	RescanProgram(ctx context.Context, program *database.Program) (*database.Scan, error)
+	GetProgramStats(ctx context.Context) (*service.ProgramStats, error)
+	CancelScan(ctx context.Context, scanID uuid.UUID) error
+	Writable() error
+}
+
+// Server implements the MonitorAgent gRPC service
+type Server struct {
+	monitoragentv1.UnimplementedMonitorAgentServer
+
+	service     Service
+	broadcaster *events.Broadcaster
+	token       string
+
+	mu       sync.Mutex
+	rescans  map[uuid.UUID]bool // programs being scanned, by ID
+	inFlight sync.WaitGroup
+}
+
+// NewServer creates the API protected by a bearer token. Events are streamed
+// from the broadcaster, which has to be one of the service's publishers.
+func NewServer(service Service, broadcaster *events.Broadcaster, token string) *Server {
+	return &Server{
+		service:     service,
+		broadcaster: broadcaster,
+		token:       token,
+		rescans:     make(map[uuid.UUID]bool),
+	}
+}
+
+// NewGRPCServer creates a gRPC server that authorizes every call and serves
+// the API
+func (s *Server) NewGRPCServer(options ...grpc.ServerOption) *grpc.Server {
+	options = append(options,
+		grpc.UnaryInterceptor(s.authorizeUnary),
+		grpc.StreamInterceptor(s.authorizeStream))
+
+	server := grpc.NewServer(options...)
+	monitoragentv1.RegisterMonitorAgentServer(server, s)
+	return server
+}
+
+// Wait blocks until triggered scans have finished
+func (s *Server) Wait() {
+	s.inFlight.Wait()
+}
+
+// ListPrograms lists the active programs
+func (s *Server) ListPrograms(ctx context.Context, req *monitoragentv1.ListProgramsRequest) (*monitoragentv1.ListProgramsResponse, error) {
+	programs, err := s.service.ListPrograms(ctx)
+	if err != nil {
+		return nil, internalError("list programs", err)
+	}
+
+	resp := &monitoragentv1.ListProgramsResponse{Programs: make([]*monitoragentv1.Program, len(programs))}
+	for i, program := range programs {
+		resp.Programs[i] = programMessage(program)
+	}
+	return resp, nil
+}
+
+// ListAssets lists the assets matching an asset query
+func (s *Server) ListAssets(ctx context.Context, req *monitoragentv1.ListAssetsRequest) (*monitoragentv1.ListAssetsResponse, error) {
+	query, err := database.ParseAssetQuery(req.GetQuery())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	limit, err := assetLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := s.service.QueryAssets(ctx, query, limit)
+	if err != nil {
+		return nil, internalError("query assets", err)
+	}
+	return assetsResponse(assets), nil
+}
+
+// FindAssets lists the assets of a host and its subdomains
+func (s *Server) FindAssets(ctx context.Context, req *monitoragentv1.FindAssetsRequest) (*monitoragentv1.ListAssetsResponse, error) {
+	limit, err := assetLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := s.service.FindAssets(ctx, req.GetHost(), limit)
+	if errors.Is(err, service.ErrInvalidDomain) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, internalError("find assets", err)
+	}
+	return assetsResponse(assets), nil
+}
+
+// ListClusters lists groups of live assets with near-identical responses,
+// largest first
+func (s *Server) ListClusters(ctx context.Context, req *monitoragentv1.ListClustersRequest) (*monitoragentv1.ListClustersResponse, error) {
+	minSize := int(req.GetMinSize())
+	if minSize == 0 {
+		minSize = 2
+	}
+	limit, err := assetLimit(req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := s.service.GetClusters(ctx, minSize, limit)
+	if err != nil {
+		return nil, internalError("list clusters", err)
+	}
+
+	resp := &monitoragentv1.ListClustersResponse{Clusters: make([]*monitoragentv1.ResponseCluster, len(clusters))}
+	for i, cluster := range clusters {
+		resp.Clusters[i] = &monitoragentv1.ResponseCluster{
+			Id:                    cluster.ID.String(),
+			RepresentativeAssetId: cluster.RepresentativeAssetID.String(),
+			RepresentativeUrl:     cluster.RepresentativeURL,
+			Size:                  int32(cluster.Size),
+			Programs:              int32(cluster.Programs),
+			StatusCode:            int32(cluster.StatusCode),
+			CreatedAt:             timestamppb.New(cluster.CreatedAt),
+		}
+	}
+	return resp, nil
+}
+
+// GetStats summarizes programs, assets and recent scans
+func (s *Server) GetStats(ctx context.Context, req *monitoragentv1.GetStatsRequest) (*monitoragentv1.Stats, error) {
+	stats, err := s.service.GetProgramStats(ctx)
+	if err != nil {
+		return nil, internalError("get stats", err)
+	}
+
+	resp := &monitoragentv1.Stats{
+		TotalPrograms:  int32(stats.TotalPrograms),
+		ActivePrograms: int32(stats.ActivePrograms),
+		TotalAssets:    int32(stats.TotalAssets),
+	}
+	for _, count := range stats.Liveness {
+		resp.Liveness = append(resp.Liveness, &monitoragentv1.LivenessCount{Liveness: count.Liveness, Assets: int32(count.Assets)})
+	}
+	for _, scan := range stats.RecentScans {
+		resp.RecentScans = append(resp.RecentScans, scanMessage(scan))
+	}
+	return resp, nil
+}
+
+// TriggerScan starts a scan of one program in the background. Clients follow
+// it with StreamEvents or GetStats.
+func (s *Server) TriggerScan(ctx context.Context, req *monitoragentv1.TriggerScanRequest) (*monitoragentv1.TriggerScanResponse, error) {
+	// The scan runs in the background, so read-only mode is checked up front
+	if err := s.service.Writable(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	program, err := s.service.FindProgram(ctx, req.GetProgram())
+	switch {
+	case errors.Is(err, service.ErrProgramNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrAmbiguousProgram):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, internalError("look up program", err)
+	}
+
+	s.mu.Lock()
+	if s.rescans[program.ID] {
+		s.mu.Unlock()
+		return nil, status.Errorf(codes.AlreadyExists, "a scan of %s is already running", program.Name)
+	}
+	s.rescans[program.ID] = true
+	s.mu.Unlock()
+
+	s.inFlight.Add(1)
+	go func() {
+		defer s.inFlight.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.rescans, program.ID)
+			s.mu.Unlock()
+		}()
+
+		// The scan outlives the call that started it
+		scan, err := s.service.RescanProgram(context.WithoutCancel(ctx), program)
+		if err != nil {
+			logrus.Errorf("gRPC triggered scan of %s failed: %v", program.Name, err)
+			return
+		}
+		logrus.Infof("gRPC triggered scan of %s %s: %d assets seen", program.Name, scan.Status, scan.AssetsSeen)
+	}()
+
+	return &monitoragentv1.TriggerScanResponse{Program: programMessage(program)}, nil
+}
+
+// CancelScan cancels a running scan
+func (s *Server) CancelScan(ctx context.Context, req *monitoragentv1.CancelScanRequest) (*monitoragentv1.CancelScanResponse, error) {
+	scanID, err := uuid.Parse(req.GetScanId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid scan id")
+	}
+
+	err = s.service.CancelScan(ctx, scanID)
+	switch {
+	case errors.Is(err, database.ErrScanNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, database.ErrScanNotRunning), errors.Is(err, service.ErrReadOnly):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, internalError("cancel scan", err)
+	}
+
+	return &monitoragentv1.CancelScanResponse{ScanId: scanID.String(), Status: "cancel_requested"}, nil
+}
+
+// StreamEvents streams the events emitted from now on until the client goes
+// away or the server stops
+func (s *Server) StreamEvents(req *monitoragentv1.StreamEventsRequest, stream grpc.ServerStreamingServer[monitoragentv1.Event]) error {
+	if s.broadcaster == nil {
+		return status.Error(codes.Unavailable, "event streaming is not enabled")
+	}
+
+	subscription, unsubscribe := s.broadcaster.Subscribe(req.GetTypes()...)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscription:
+			if !ok {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+
+			message, err := eventMessage(event)
+			if err != nil {
+				logrus.Warnf("Failed to convert %s event %s for gRPC: %v", event.Type, event.ID, err)
+				continue
+			}
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// authorizeUnary rejects unary calls without the bearer token
+func (s *Server) authorizeUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !s.authorized(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return handler(ctx, req)
+}
+
+// authorizeStream rejects streams without the bearer token
+func (s *Server) authorizeStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !s.authorized(stream.Context()) {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return handler(srv, stream)
+}
+
+// authorized checks the bearer token of the request metadata
+func (s *Server) authorized(ctx context.Context) bool {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return false
+	}
+	return httpapi.Authorized(values[0], s.token)
+}
+
+// assetLimit applies the default and maximum to a requested asset limit
+func assetLimit(limit int32) (int, error) {
+	switch {
+	case limit < 0:
+		return 0, status.Error(codes.InvalidArgument, "limit must not be negative")
+	case limit == 0:
+		return DefaultAssetLimit, nil
+	case limit > MaxAssetLimit:
+		return MaxAssetLimit, nil
+	default:
+		return int(limit), nil
+	}
+}
+
+// internalError logs a failed call and hides its details from the client
+func internalError(action string, err error) error {
+	logrus.Errorf("gRPC API failed to %s: %v", action, err)
+	return status.Errorf(codes.Internal, "failed to %s", action)
+}
+
+// programMessage converts a program to its protobuf message
+func programMessage(program *database.Program) *monitoragentv1.Program {
+	return &monitoragentv1.Program{
+		Id:          program.ID.String(),
+		Name:        program.Name,
+		Platform:    program.Platform,
+		ProgramUrl:  program.ProgramURL,
+		IsActive:    program.IsActive,
+		LastUpdated: timestamppb.New(program.LastUpdated),
+	}
+}
+
+// assetsResponse converts assets to a response message
+func assetsResponse(assets []*database.Asset) *monitoragentv1.ListAssetsResponse {
+	resp := &monitoragentv1.ListAssetsResponse{Assets: make([]*monitoragentv1.Asset, len(assets))}
+	for i, asset := range assets {
+		resp.Assets[i] = &monitoragentv1.Asset{
+			Id:           asset.ID.String(),
+			ProgramId:    asset.ProgramID.String(),
+			ProgramUrl:   asset.ProgramURL,
+			Url:          asset.URL,
+			Domain:       asset.Domain,
+			Subdomain:    asset.Subdomain,
+			Ip:           asset.IP,
+			Ipv6:         asset.IPv6,
+			Liveness:     asset.Liveness,
+			Status:       asset.Status,
+			FirstSource:  asset.FirstSource,
+			Ignored:      asset.Ignored,
+			Score:        asset.Score,
+			LastProbedAt: optionalTimestamp(asset.LastProbedAt),
+			CreatedAt:    timestamppb.New(asset.CreatedAt),
+			Provenance:   asset.Sources(),
+			DataTerms:    asset.DataTerms,
+		}
+		if asset.ClusterID != nil {
+			resp.Assets[i].ClusterId = asset.ClusterID.String()
+		}
+	}
+	return resp
+}
+
+// scanMessage converts a scan to its protobuf message
+func scanMessage(scan *database.Scan) *monitoragentv1.Scan {
+	return &monitoragentv1.Scan{
+		Id:          scan.ID.String(),
+		ProgramId:   scan.ProgramID.String(),
+		Status:      scan.Status,
+		AssetsFound: int32(scan.AssetsFound),
+		AssetsSeen:  int32(scan.AssetsSeen),
+		Error:       scan.Error,
+		StartedAt:   timestamppb.New(scan.StartedAt),
+		CompletedAt: optionalTimestamp(scan.CompletedAt),
+	}
+}
+
+// eventMessage converts an event to its protobuf message. The data payload
+// keeps the JSON shape the other transports deliver.
+func eventMessage(event *events.Event) (*monitoragentv1.Event, error) {
+	message := &monitoragentv1.Event{
+		Id:      event.ID,
+		Source:  event.Source,
+		Type:    event.Type,
+		Subject: event.Subject,
+		Time:    timestamppb.New(event.Time),
+	}
+
+	if event.Data == nil {
+		return message, nil
+	}
+
+	body, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	message.Data, err = structpb.NewStruct(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// optionalTimestamp converts an optional time, keeping nil as unset
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,257 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/service"
+	monitoragentv1 "github.com/monitor-agent/proto/monitoragent/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeService struct {
+	programs  []*database.Program
+	assets    []*database.Asset
+	clusters  []*database.ResponseClusterSummary
+	queries   []*database.AssetQuery
+	limits    []int
+	rescanned chan *database.Program
+	cancelErr error
+	readOnly  bool
+}
+
+func (f *fakeService) ListPrograms(ctx context.Context) ([]*database.Program, error) {
+	return f.programs, nil
+}
+
+func (f *fakeService) QueryAssets(ctx context.Context, query *database.AssetQuery, limit int) ([]*database.Asset, error) {
+	f.queries = append(f.queries, query)
+	f.limits = append(f.limits, limit)
+	return f.assets, nil
+}
+
+func (f *fakeService) FindAssets(ctx context.Context, host string, limit int) ([]*database.Asset, error) {
+	if host == "not a domain" {
+		return nil, service.ErrInvalidDomain
+	}
+	f.limits = append(f.limits, limit)
+	return f.assets, nil
+}
+
+func (f *fakeService) GetClusters(ctx context.Context, minSize, limit int) ([]*database.ResponseClusterSummary, error) {
+	f.limits = append(f.limits, minSize, limit)
+	return f.clusters, nil
+}
+
+func (f *fakeService) FindProgram(ctx context.Context, handle string) (*database.Program, error) {
+	for _, program := range f.programs {
+		if program.Name == handle {
+			return program, nil
+		}
+	}
+	return nil, service.ErrProgramNotFound
+}
+
+func (f *fakeService) RescanProgram(ctx context.Context, program *database.Program) (*database.Scan, error) {
+	f.rescanned <- program
+	return &database.Scan{ProgramID: program.ID, Status: "completed"}, nil
+}
+
+func (f *fakeService) GetProgramStats(ctx context.Context) (*service.ProgramStats, error) {
+	return &service.ProgramStats{
+		TotalPrograms:  2,
+		ActivePrograms: 1,
+		TotalAssets:    40,
+		Liveness:       []*database.LivenessCount{{Liveness: "live", Assets: 30}},
+		RecentScans:    []*database.Scan{{ID: uuid.New(), Status: "completed", AssetsFound: 40}},
+	}, nil
+}
+
+func (f *fakeService) CancelScan(ctx context.Context, scanID uuid.UUID) error {
+	return f.cancelErr
+}
+
+func (f *fakeService) Writable() error {
+	if f.readOnly {
+		return service.ErrReadOnly
+	}
+	return nil
+}
+
+// startServer serves the API over an in-memory listener and returns a client
+func startServer(t *testing.T, svc Service, broadcaster *events.Broadcaster) monitoragentv1.MonitorAgentClient {
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(svc, broadcaster, "secret")
+	grpcServer := server.NewGRPCServer()
+	go grpcServer.Serve(listener)
+	t.Cleanup(func() {
+		grpcServer.Stop()
+		server.Wait()
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return monitoragentv1.NewMonitorAgentClient(conn)
+}
+
+func authorized(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_Unauthenticated(t *testing.T) {
+	client := startServer(t, &fakeService{}, events.NewBroadcaster(1))
+
+	_, err := client.ListPrograms(context.Background(), &monitoragentv1.ListProgramsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.ListPrograms(authorized("wrong"), &monitoragentv1.ListProgramsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.StreamEvents(authorized("wrong"), &monitoragentv1.StreamEventsRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServer_Queries(t *testing.T) {
+	programID, clusterID := uuid.New(), uuid.New()
+	svc := &fakeService{
+		programs: []*database.Program{{ID: programID, Name: "acme", Platform: "hackerone", ProgramURL: "https://hackerone.com/acme", IsActive: true}},
+		assets: []*database.Asset{{ID: uuid.New(), ProgramID: programID, URL: "https://api.acme.com", Liveness: "live", Score: 18, ClusterID: &clusterID,
+			FirstSource: "chaosdb", DataTerms: pq.StringArray{"ProjectDiscovery Chaos terms of use"}}},
+		clusters: []*database.ResponseClusterSummary{{
+			ResponseCluster:   database.ResponseCluster{ID: clusterID, Size: 250, StatusCode: 200},
+			RepresentativeURL: "https://www.acme.com",
+			Programs:          1,
+		}},
+	}
+	client := startServer(t, svc, nil)
+	ctx := authorized("secret")
+
+	programs, err := client.ListPrograms(ctx, &monitoragentv1.ListProgramsRequest{})
+	require.NoError(t, err)
+	require.Len(t, programs.Programs, 1)
+	assert.Equal(t, "https://hackerone.com/acme", programs.Programs[0].ProgramUrl)
+
+	assets, err := client.ListAssets(ctx, &monitoragentv1.ListAssetsRequest{Query: "program:acme liveness:live"})
+	require.NoError(t, err)
+	require.Len(t, assets.Assets, 1)
+	assert.Equal(t, "https://api.acme.com", assets.Assets[0].Url)
+	assert.Equal(t, 18.0, assets.Assets[0].Score)
+	assert.Nil(t, assets.Assets[0].LastProbedAt)
+	assert.Equal(t, clusterID.String(), assets.Assets[0].ClusterId)
+	assert.Equal(t, []string{"chaosdb"}, assets.Assets[0].Provenance)
+	assert.Equal(t, []string{"ProjectDiscovery Chaos terms of use"}, assets.Assets[0].DataTerms)
+	require.Len(t, svc.queries, 1)
+	assert.Len(t, svc.queries[0].Terms, 2)
+
+	_, err = client.ListAssets(ctx, &monitoragentv1.ListAssetsRequest{Query: "color:blue"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.FindAssets(ctx, &monitoragentv1.FindAssetsRequest{Host: "acme.com", Limit: 5000})
+	require.NoError(t, err)
+	_, err = client.FindAssets(ctx, &monitoragentv1.FindAssetsRequest{Host: "not a domain"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, []int{DefaultAssetLimit, MaxAssetLimit}, svc.limits)
+
+	clusters, err := client.ListClusters(ctx, &monitoragentv1.ListClustersRequest{})
+	require.NoError(t, err)
+	require.Len(t, clusters.Clusters, 1)
+	assert.Equal(t, int32(250), clusters.Clusters[0].Size)
+	assert.Equal(t, "https://www.acme.com", clusters.Clusters[0].RepresentativeUrl)
+	assert.Equal(t, []int{DefaultAssetLimit, MaxAssetLimit, 2, DefaultAssetLimit}, svc.limits)
+
+	stats, err := client.GetStats(ctx, &monitoragentv1.GetStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(40), stats.TotalAssets)
+	assert.Equal(t, "live", stats.Liveness[0].Liveness)
+	assert.Equal(t, int32(40), stats.RecentScans[0].AssetsFound)
+}
+
+func TestServer_Scans(t *testing.T) {
+	program := &database.Program{ID: uuid.New(), Name: "acme", Platform: "hackerone"}
+	svc := &fakeService{programs: []*database.Program{program}, rescanned: make(chan *database.Program, 1)}
+	client := startServer(t, svc, nil)
+	ctx := authorized("secret")
+
+	resp, err := client.TriggerScan(ctx, &monitoragentv1.TriggerScanRequest{Program: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, program.ID.String(), resp.Program.Id)
+	select {
+	case rescanned := <-svc.rescanned:
+		assert.Equal(t, program, rescanned)
+	case <-time.After(5 * time.Second):
+		t.Fatal("scan was not started")
+	}
+
+	_, err = client.TriggerScan(ctx, &monitoragentv1.TriggerScanRequest{Program: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	cancelled, err := client.CancelScan(ctx, &monitoragentv1.CancelScanRequest{ScanId: uuid.New().String()})
+	require.NoError(t, err)
+	assert.Equal(t, "cancel_requested", cancelled.Status)
+
+	_, err = client.CancelScan(ctx, &monitoragentv1.CancelScanRequest{ScanId: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	svc.cancelErr = database.ErrScanNotRunning
+	_, err = client.CancelScan(ctx, &monitoragentv1.CancelScanRequest{ScanId: uuid.New().String()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Read-only mode refuses scans before they are started
+	svc.readOnly = true
+	_, err = client.TriggerScan(ctx, &monitoragentv1.TriggerScanRequest{Program: "acme"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Empty(t, svc.rescanned)
+}
+
+func TestServer_StreamEvents(t *testing.T) {
+	broadcaster := events.NewBroadcaster(10)
+	emitter := events.NewEmitter("test", broadcaster)
+	client := startServer(t, &fakeService{}, broadcaster)
+
+	ctx, cancel := context.WithCancel(authorized("secret"))
+	defer cancel()
+	stream, err := client.StreamEvents(ctx, &monitoragentv1.StreamEventsRequest{Types: []string{events.TypeAssetDiscovered}})
+	require.NoError(t, err)
+
+	// The subscription starts once the call reaches the server, so keep
+	// emitting until the stream delivers
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				emitter.Emit(context.Background(), events.TypeProgramCreated, "https://hackerone.com/acme", nil)
+				emitter.Emit(context.Background(), events.TypeAssetDiscovered, "https://api.acme.com",
+					events.NewAssetData(&database.Asset{URL: "https://api.acme.com", Liveness: "live"}))
+			}
+		}
+	}()
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, events.TypeAssetDiscovered, event.Type)
+	assert.Equal(t, "https://api.acme.com", event.Subject)
+	assert.Equal(t, "https://api.acme.com", event.Data.Fields["url"].GetStringValue())
+}
@@ -0,0 +1,41 @@
+// Package httpapi holds what the agent's API servers share: bearer token
+// authentication and JSON responses
+package httpapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorResponse is returned when a request fails
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Authorized checks the bearer token of an Authorization header value using a
+// constant-time comparison. Nothing is authorized when token is empty.
+func Authorized(authorization, token string) bool {
+	if token == "" {
+		return false
+	}
+	bearer := strings.TrimPrefix(authorization, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
+// WriteJSON writes a JSON response
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Errorf("Failed to write JSON response: %v", err)
+	}
+}
+
+// WriteError writes a JSON error response
+func WriteError(w http.ResponseWriter, status int, message string) {
+	WriteJSON(w, status, ErrorResponse{Error: message})
+}
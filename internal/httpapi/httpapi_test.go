@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorized(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		token         string
+		want          bool
+	}{
+		{"matching bearer token", "Bearer secret", "secret", true},
+		{"wrong token", "Bearer wrong", "secret", false},
+		{"missing header", "", "secret", false},
+		{"no token configured", "Bearer ", "", false},
+		{"no token configured and no header", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Authorized(tt.authorization, tt.token))
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	WriteError(recorder, http.StatusBadRequest, "invalid request")
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"invalid request"}`, recorder.Body.String())
+}
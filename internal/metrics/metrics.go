@@ -2,15 +2,23 @@ package metrics
 
 import (
 	"context"
+	"net/http"
 	"runtime"
+	"strconv"
 	"time"
 
+	"github.com/monitor-agent/internal/utils"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// Metrics holds all application metrics
+// Metrics holds all application metrics. Every method is a no-op on a nil
+// *Metrics, so code paths run without metrics configured need no checks.
 type Metrics struct {
+	registry *prometheus.Registry
+
 	// API metrics
 	apiRequestsTotal   *prometheus.CounterVec
 	apiRequestDuration *prometheus.HistogramVec
@@ -20,12 +28,16 @@ type Metrics struct {
 	dbOperationsTotal   *prometheus.CounterVec
 	dbOperationDuration *prometheus.HistogramVec
 	dbConnectionPool    *prometheus.GaugeVec
+	dbRowsPruned        *prometheus.CounterVec
 
 	// Business metrics
 	programsDiscovered *prometheus.CounterVec
 	assetsDiscovered   *prometheus.CounterVec
 	scansCompleted     *prometheus.CounterVec
 	scansFailed        *prometheus.CounterVec
+	scansZeroAssets    *prometheus.CounterVec
+	sloCompliance      *prometheus.GaugeVec
+	sloViolations      *prometheus.GaugeVec
 
 	// System metrics
 	memoryUsage         *prometheus.GaugeVec
@@ -36,20 +48,33 @@ type Metrics struct {
 	platformRequestsTotal   *prometheus.CounterVec
 	platformRequestDuration *prometheus.HistogramVec
 	platformErrors          *prometheus.CounterVec
+	rateLimiters            *rateLimiterCollector
 }
 
-// NewMetrics creates a new metrics instance
+// NewMetrics creates a new metrics instance with its own registry, which
+// also collects the Go runtime and process metrics
 func NewMetrics() *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	factory := promauto.With(registry)
+	rateLimiters := &rateLimiterCollector{limiters: make(map[string][]*utils.RateLimiter)}
+	registry.MustRegister(rateLimiters)
+
 	return &Metrics{
+		registry: registry,
+
 		// API metrics
-		apiRequestsTotal: promauto.NewCounterVec(
+		apiRequestsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_api_requests_total",
 				Help: "Total number of API requests",
 			},
 			[]string{"method", "endpoint", "status_code"},
 		),
-		apiRequestDuration: promauto.NewHistogramVec(
+		apiRequestDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "monitor_agent_api_request_duration_seconds",
 				Help:    "API request duration in seconds",
@@ -57,7 +82,7 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"method", "endpoint"},
 		),
-		apiRequestErrors: promauto.NewCounterVec(
+		apiRequestErrors: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_api_request_errors_total",
 				Help: "Total number of API request errors",
@@ -66,14 +91,14 @@ func NewMetrics() *Metrics {
 		),
 
 		// Database metrics
-		dbOperationsTotal: promauto.NewCounterVec(
+		dbOperationsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_db_operations_total",
 				Help: "Total number of database operations",
 			},
 			[]string{"operation", "table"},
 		),
-		dbOperationDuration: promauto.NewHistogramVec(
+		dbOperationDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "monitor_agent_db_operation_duration_seconds",
 				Help:    "Database operation duration in seconds",
@@ -81,60 +106,88 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"operation", "table"},
 		),
-		dbConnectionPool: promauto.NewGaugeVec(
+		dbConnectionPool: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "monitor_agent_db_connection_pool",
 				Help: "Database connection pool status",
 			},
 			[]string{"status"},
 		),
+		dbRowsPruned: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "monitor_agent_db_rows_pruned_total",
+				Help: "Total number of rows deleted by retention policies",
+			},
+			[]string{"table"},
+		),
 
 		// Business metrics
-		programsDiscovered: promauto.NewCounterVec(
+		programsDiscovered: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_programs_discovered_total",
 				Help: "Total number of programs discovered",
 			},
 			[]string{"platform"},
 		),
-		assetsDiscovered: promauto.NewCounterVec(
+		assetsDiscovered: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_assets_discovered_total",
 				Help: "Total number of assets discovered",
 			},
 			[]string{"platform", "source"},
 		),
-		scansCompleted: promauto.NewCounterVec(
+		scansCompleted: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_scans_completed_total",
 				Help: "Total number of scans completed",
 			},
 			[]string{"platform"},
 		),
-		scansFailed: promauto.NewCounterVec(
+		scansFailed: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_scans_failed_total",
 				Help: "Total number of scans failed",
 			},
 			[]string{"platform", "error_type"},
 		),
+		scansZeroAssets: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "monitor_agent_scans_zero_assets_total",
+				Help: "Total number of completed program scans that found no assets",
+			},
+			[]string{"platform"},
+		),
+		sloCompliance: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "monitor_agent_slo_compliance_ratio",
+				Help: "Share of programs or assets meeting a freshness SLO",
+			},
+			[]string{"objective"},
+		),
+		sloViolations: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "monitor_agent_slo_violations",
+				Help: "Number of programs or assets violating a freshness SLO",
+			},
+			[]string{"objective"},
+		),
 
 		// System metrics
-		memoryUsage: promauto.NewGaugeVec(
+		memoryUsage: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "monitor_agent_memory_usage_bytes",
 				Help: "Memory usage in bytes",
 			},
 			[]string{"type"},
 		),
-		goroutineCount: promauto.NewGaugeVec(
+		goroutineCount: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "monitor_agent_goroutines_total",
 				Help: "Number of goroutines",
 			},
 			[]string{},
 		),
-		circuitBreakerState: promauto.NewGaugeVec(
+		circuitBreakerState: factory.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "monitor_agent_circuit_breaker_state",
 				Help: "Circuit breaker state (0=closed, 1=half-open, 2=open)",
@@ -143,14 +196,14 @@ func NewMetrics() *Metrics {
 		),
 
 		// Platform-specific metrics
-		platformRequestsTotal: promauto.NewCounterVec(
+		platformRequestsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_platform_requests_total",
 				Help: "Total number of platform API requests",
 			},
 			[]string{"platform", "endpoint", "status_code"},
 		),
-		platformRequestDuration: promauto.NewHistogramVec(
+		platformRequestDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "monitor_agent_platform_request_duration_seconds",
 				Help:    "Platform API request duration in seconds",
@@ -158,61 +211,136 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"platform", "endpoint"},
 		),
-		platformErrors: promauto.NewCounterVec(
+		platformErrors: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "monitor_agent_platform_errors_total",
 				Help: "Total number of platform API errors",
 			},
 			[]string{"platform", "error_type"},
 		),
+		rateLimiters: rateLimiters,
 	}
 }
 
 // RecordAPIRequest records an API request
 func (m *Metrics) RecordAPIRequest(method, endpoint string, statusCode int, duration time.Duration) {
-	m.apiRequestsTotal.WithLabelValues(method, endpoint, string(rune(statusCode))).Inc()
+	if m == nil {
+		return
+	}
+	m.apiRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
 	m.apiRequestDuration.WithLabelValues(method, endpoint).Observe(duration.Seconds())
 }
 
 // RecordAPIError records an API error
 func (m *Metrics) RecordAPIError(method, endpoint, errorType string) {
+	if m == nil {
+		return
+	}
 	m.apiRequestErrors.WithLabelValues(method, endpoint, errorType).Inc()
 }
 
 // RecordDatabaseOperation records a database operation
 func (m *Metrics) RecordDatabaseOperation(operation, table string, duration time.Duration) {
+	if m == nil {
+		return
+	}
 	m.dbOperationsTotal.WithLabelValues(operation, table).Inc()
 	m.dbOperationDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
 }
 
 // UpdateConnectionPool updates connection pool metrics
 func (m *Metrics) UpdateConnectionPool(open, idle int) {
+	if m == nil {
+		return
+	}
 	m.dbConnectionPool.WithLabelValues("open").Set(float64(open))
 	m.dbConnectionPool.WithLabelValues("idle").Set(float64(idle))
 }
 
+// UpdateConnectionPoolLimit records the most connections the pool may open,
+// so the share of the pool in use can be alerted on
+func (m *Metrics) UpdateConnectionPoolLimit(maxOpen int) {
+	if m == nil {
+		return
+	}
+	m.dbConnectionPool.WithLabelValues("max_open").Set(float64(maxOpen))
+}
+
+// RecordRowsPruned records rows a retention policy deleted from a table
+func (m *Metrics) RecordRowsPruned(table string, rows int64) {
+	if m == nil {
+		return
+	}
+	m.dbRowsPruned.WithLabelValues(table).Add(float64(rows))
+}
+
 // RecordProgramDiscovered records a discovered program
 func (m *Metrics) RecordProgramDiscovered(platform string) {
+	if m == nil {
+		return
+	}
 	m.programsDiscovered.WithLabelValues(platform).Inc()
 }
 
 // RecordAssetDiscovered records a discovered asset
 func (m *Metrics) RecordAssetDiscovered(platform, source string) {
+	if m == nil {
+		return
+	}
 	m.assetsDiscovered.WithLabelValues(platform, source).Inc()
 }
 
+// RecordAssetsDiscovered records assets a source found for the first time
+func (m *Metrics) RecordAssetsDiscovered(platform, source string, count int) {
+	if m == nil {
+		return
+	}
+	m.assetsDiscovered.WithLabelValues(platform, source).Add(float64(count))
+}
+
 // RecordScanCompleted records a completed scan
 func (m *Metrics) RecordScanCompleted(platform string) {
+	if m == nil {
+		return
+	}
 	m.scansCompleted.WithLabelValues(platform).Inc()
 }
 
 // RecordScanFailed records a failed scan
 func (m *Metrics) RecordScanFailed(platform, errorType string) {
+	if m == nil {
+		return
+	}
 	m.scansFailed.WithLabelValues(platform, errorType).Inc()
 }
 
+// RecordZeroAssetScan records a completed program scan that found no assets
+func (m *Metrics) RecordZeroAssetScan(platform string) {
+	if m == nil {
+		return
+	}
+	m.scansZeroAssets.WithLabelValues(platform).Inc()
+}
+
+// UpdateFreshnessSLO records the compliance with a freshness SLO, e.g.
+// program_scan or asset_probe, given the tracked and violating counts
+func (m *Metrics) UpdateFreshnessSLO(objective string, total, violating int) {
+	if m == nil {
+		return
+	}
+	ratio := 1.0
+	if total > 0 {
+		ratio = float64(total-violating) / float64(total)
+	}
+	m.sloCompliance.WithLabelValues(objective).Set(ratio)
+	m.sloViolations.WithLabelValues(objective).Set(float64(violating))
+}
+
 // UpdateSystemMetrics updates system metrics
 func (m *Metrics) UpdateSystemMetrics() {
+	if m == nil {
+		return
+	}
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
 
@@ -226,6 +354,9 @@ func (m *Metrics) UpdateSystemMetrics() {
 
 // UpdateCircuitBreakerState updates circuit breaker state
 func (m *Metrics) UpdateCircuitBreakerState(service, state string) {
+	if m == nil {
+		return
+	}
 	var stateValue float64
 	switch state {
 	case "closed":
@@ -240,17 +371,33 @@ func (m *Metrics) UpdateCircuitBreakerState(service, state string) {
 
 // RecordPlatformRequest records a platform API request
 func (m *Metrics) RecordPlatformRequest(platform, endpoint string, statusCode int, duration time.Duration) {
-	m.platformRequestsTotal.WithLabelValues(platform, endpoint, string(rune(statusCode))).Inc()
+	if m == nil {
+		return
+	}
+	m.platformRequestsTotal.WithLabelValues(platform, endpoint, strconv.Itoa(statusCode)).Inc()
 	m.platformRequestDuration.WithLabelValues(platform, endpoint).Observe(duration.Seconds())
 }
 
 // RecordPlatformError records a platform API error
 func (m *Metrics) RecordPlatformError(platform, errorType string) {
+	if m == nil {
+		return
+	}
 	m.platformErrors.WithLabelValues(platform, errorType).Inc()
 }
 
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
 // StartMetricsCollection starts periodic metrics collection
 func (m *Metrics) StartMetricsCollection(ctx context.Context) {
+	if m == nil {
+		return
+	}
+	m.UpdateSystemMetrics()
+
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
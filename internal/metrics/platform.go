@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// InstrumentPlatformClient records every request a platform API client
+// sends, each retry included, with its status code and duration. Failed
+// requests are counted as platform errors as well.
+func (m *Metrics) InstrumentPlatformClient(client *resty.Client, platform string) {
+	if m == nil {
+		return
+	}
+
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		if resp.Request.RawRequest == nil {
+			return nil
+		}
+		m.RecordPlatformRequest(platform, EndpointLabel(resp.Request.RawRequest.URL.Path), resp.StatusCode(), resp.Time())
+		if errorType := statusErrorType(resp.StatusCode()); errorType != "" {
+			m.RecordPlatformError(platform, errorType)
+		}
+		return nil
+	})
+	client.OnError(func(req *resty.Request, err error) {
+		// Responses that arrived were recorded above; only record requests that got none
+		var responseErr *resty.ResponseError
+		if errors.As(err, &responseErr) || req.RawRequest == nil {
+			return
+		}
+		m.RecordPlatformRequest(platform, EndpointLabel(req.RawRequest.URL.Path), 0, time.Since(req.Time))
+		m.RecordPlatformError(platform, transportErrorType(err))
+	})
+}
+
+// EndpointLabel turns a request path into a label of bounded cardinality by
+// replacing the program handle or ID that follows a programs segment, e.g.
+// /v1/hackers/programs/acme/structured_scopes becomes
+// /v1/hackers/programs/{program}/structured_scopes
+func EndpointLabel(path string) string {
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		if segments[i-1] == "programs" && segments[i] != "" {
+			segments[i] = "{program}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// statusErrorType classifies a failed response; empty for a successful one
+func statusErrorType(statusCode int) string {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return "auth"
+	case statusCode >= 500:
+		return "server_error"
+	case statusCode >= 400:
+		return "client_error"
+	default:
+		return ""
+	}
+}
+
+// transportErrorType classifies a request that got no response
+func transportErrorType(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	default:
+		return "transport"
+	}
+}
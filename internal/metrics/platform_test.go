@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointLabel(t *testing.T) {
+	assert.Equal(t, "/v1/hackers/programs/{program}/structured_scopes", EndpointLabel("/v1/hackers/programs/acme/structured_scopes"))
+	assert.Equal(t, "/v1/hackers/programs", EndpointLabel("/v1/hackers/programs"))
+	assert.Equal(t, "/v1/hackers/programs/", EndpointLabel("/v1/hackers/programs/"))
+	assert.Equal(t, "/external/researcher/v1/programs/{program}", EndpointLabel("/external/researcher/v1/programs/0d7e"))
+}
+
+func TestStatusErrorType(t *testing.T) {
+	assert.Equal(t, "", statusErrorType(http.StatusOK))
+	assert.Equal(t, "rate_limited", statusErrorType(http.StatusTooManyRequests))
+	assert.Equal(t, "auth", statusErrorType(http.StatusForbidden))
+	assert.Equal(t, "server_error", statusErrorType(http.StatusBadGateway))
+	assert.Equal(t, "client_error", statusErrorType(http.StatusNotFound))
+}
+
+func TestInstrumentPlatformClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/programs/acme" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := NewMetrics()
+	client := resty.New().SetBaseURL(server.URL)
+	m.InstrumentPlatformClient(client, "hackerone")
+
+	_, err := client.R().Get("/programs")
+	require.NoError(t, err)
+	_, err = client.R().Get("/programs/acme")
+	require.NoError(t, err)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `monitor_agent_platform_requests_total{endpoint="/programs",platform="hackerone",status_code="200"} 1`)
+	assert.Contains(t, body, `monitor_agent_platform_requests_total{endpoint="/programs/{program}",platform="hackerone",status_code="429"} 1`)
+	assert.Contains(t, body, `monitor_agent_platform_errors_total{error_type="rate_limited",platform="hackerone"} 1`)
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.RecordScanCompleted("hackerone")
+		m.RecordDatabaseOperation("insert", "programs", 0)
+		m.InstrumentPlatformClient(resty.New(), "hackerone")
+		m.InstrumentRateLimiter("hackerone", nil)
+	})
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
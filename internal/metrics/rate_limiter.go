@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+
+	"github.com/monitor-agent/internal/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	rateLimiterTokensDesc = prometheus.NewDesc(
+		"monitor_agent_rate_limiter_tokens",
+		"Requests a platform's rate limiters allow right now",
+		[]string{"platform"}, nil,
+	)
+	rateLimiterRateDesc = prometheus.NewDesc(
+		"monitor_agent_rate_limiter_rate",
+		"Requests per second a platform's rate limiters refill",
+		[]string{"platform"}, nil,
+	)
+	rateLimiterPausedDesc = prometheus.NewDesc(
+		"monitor_agent_rate_limiter_paused_seconds",
+		"Seconds until a platform's Retry-After pause ends",
+		[]string{"platform"}, nil,
+	)
+	rateLimiterWaitDesc = prometheus.NewDesc(
+		"monitor_agent_rate_limiter_wait_seconds_total",
+		"Total seconds requests waited for a platform's rate limiters",
+		[]string{"platform"}, nil,
+	)
+	rateLimiterRemainingDesc = prometheus.NewDesc(
+		"monitor_agent_rate_limiter_remaining",
+		"Requests an endpoint's X-RateLimit-Remaining header last reported",
+		[]string{"platform", "endpoint"}, nil,
+	)
+	rateLimiterResetDesc = prometheus.NewDesc(
+		"monitor_agent_rate_limiter_reset_seconds",
+		"Seconds until an endpoint's rate limit window resets",
+		[]string{"platform", "endpoint"}, nil,
+	)
+)
+
+// rateLimiterCollector reports the state of the registered rate limiters
+// whenever the metrics are scraped. A platform with several credentials has
+// a limiter for each; their tokens, rates and waits add up, and the longest
+// pause and the lowest remaining count are reported.
+type rateLimiterCollector struct {
+	mu       sync.Mutex
+	limiters map[string][]*utils.RateLimiter
+}
+
+// InstrumentRateLimiter reports the state of a platform's rate limiter
+func (m *Metrics) InstrumentRateLimiter(platform string, limiter *utils.RateLimiter) {
+	if m == nil {
+		return
+	}
+	m.rateLimiters.mu.Lock()
+	defer m.rateLimiters.mu.Unlock()
+	m.rateLimiters.limiters[platform] = append(m.rateLimiters.limiters[platform], limiter)
+}
+
+// Describe implements prometheus.Collector
+func (c *rateLimiterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- rateLimiterTokensDesc
+	ch <- rateLimiterRateDesc
+	ch <- rateLimiterPausedDesc
+	ch <- rateLimiterWaitDesc
+	ch <- rateLimiterRemainingDesc
+	ch <- rateLimiterResetDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *rateLimiterCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for platform, limiters := range c.limiters {
+		var tokens, rate, paused, waited float64
+		remaining := make(map[string]utils.EndpointLimitState)
+		for _, limiter := range limiters {
+			state := limiter.State()
+			tokens += state.Tokens
+			rate += state.Rate
+			paused = math.Max(paused, state.PausedFor.Seconds())
+			waited += state.Waited.Seconds()
+			for endpoint, limit := range state.Endpoints {
+				if current, ok := remaining[endpoint]; !ok || limit.Remaining < current.Remaining {
+					remaining[endpoint] = limit
+				}
+			}
+		}
+
+		ch <- prometheus.MustNewConstMetric(rateLimiterTokensDesc, prometheus.GaugeValue, tokens, platform)
+		ch <- prometheus.MustNewConstMetric(rateLimiterRateDesc, prometheus.GaugeValue, rate, platform)
+		ch <- prometheus.MustNewConstMetric(rateLimiterPausedDesc, prometheus.GaugeValue, paused, platform)
+		ch <- prometheus.MustNewConstMetric(rateLimiterWaitDesc, prometheus.CounterValue, waited, platform)
+		for endpoint, limit := range remaining {
+			ch <- prometheus.MustNewConstMetric(rateLimiterRemainingDesc, prometheus.GaugeValue, float64(limit.Remaining), platform, endpoint)
+			ch <- prometheus.MustNewConstMetric(rateLimiterResetDesc, prometheus.GaugeValue, limit.ResetIn.Seconds(), platform, endpoint)
+		}
+	}
+}
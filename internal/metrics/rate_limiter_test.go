@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentRateLimiter(t *testing.T) {
+	m := NewMetrics()
+	first := utils.NewRateLimiter(60, time.Minute)
+	second := utils.NewRateLimiter(60, time.Minute)
+	m.InstrumentRateLimiter("hackerone", first)
+	m.InstrumentRateLimiter("hackerone", second)
+
+	first.Observe("/programs", http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"7"}})
+	second.Observe("/programs", http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"3"}})
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `monitor_agent_rate_limiter_rate{platform="hackerone"} 2`)
+	assert.Contains(t, body, `monitor_agent_rate_limiter_remaining{endpoint="/programs",platform="hackerone"} 3`)
+	assert.Contains(t, body, `monitor_agent_rate_limiter_paused_seconds{platform="hackerone"} 0`)
+	assert.Contains(t, body, `monitor_agent_rate_limiter_wait_seconds_total{platform="hackerone"} 0`)
+	assert.Contains(t, body, `monitor_agent_rate_limiter_tokens{platform="hackerone"}`)
+}
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleThresholds are the thresholds of the recommended alerting rules
+type RuleThresholds struct {
+	ScanFailureRatio     float64       // share of program scans that failed over an hour
+	ZeroAssetWindow      time.Duration // window in which a completed scan finding no assets alerts
+	PlatformErrorRatio   float64       // share of platform API requests that failed over 10 minutes
+	PoolSaturationRatio  float64       // share of the database connection pool in use
+	PoolSaturationPeriod time.Duration // how long the pool has to stay saturated
+	SLOComplianceRatio   float64       // share of programs or assets that has to meet a freshness SLO
+}
+
+// DefaultRuleThresholds returns the thresholds used when none are given
+func DefaultRuleThresholds() RuleThresholds {
+	return RuleThresholds{
+		ScanFailureRatio:     0.2,
+		ZeroAssetWindow:      6 * time.Hour,
+		PlatformErrorRatio:   0.1,
+		PoolSaturationRatio:  0.9,
+		PoolSaturationPeriod: 10 * time.Minute,
+		SLOComplianceRatio:   0.95,
+	}
+}
+
+// Validate checks that the thresholds are usable
+func (t RuleThresholds) Validate() error {
+	ratios := []struct {
+		name  string
+		value float64
+	}{
+		{"scan failure ratio", t.ScanFailureRatio},
+		{"platform error ratio", t.PlatformErrorRatio},
+		{"pool saturation ratio", t.PoolSaturationRatio},
+		{"SLO compliance ratio", t.SLOComplianceRatio},
+	}
+	for _, ratio := range ratios {
+		if ratio.value <= 0 || ratio.value > 1 {
+			return fmt.Errorf("%s must be above 0 and at most 1, got %g", ratio.name, ratio.value)
+		}
+	}
+	if t.ZeroAssetWindow < time.Minute {
+		return fmt.Errorf("zero asset window must be at least 1m, got %s", t.ZeroAssetWindow)
+	}
+	if t.PoolSaturationPeriod < 0 {
+		return fmt.Errorf("pool saturation period cannot be negative")
+	}
+	return nil
+}
+
+// AlertingRule is a Prometheus alerting rule
+type AlertingRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// RuleGroup is a named group of Prometheus alerting rules
+type RuleGroup struct {
+	Name  string         `yaml:"name"`
+	Rules []AlertingRule `yaml:"rules"`
+}
+
+// RecommendedRules returns the recommended alerting rules for the metrics
+// exposed by the agent
+func RecommendedRules(t RuleThresholds) []RuleGroup {
+	return []RuleGroup{{
+		Name: "monitor-agent",
+		Rules: []AlertingRule{
+			{
+				Alert: "MonitorAgentScanFailureRate",
+				Expr: fmt.Sprintf(`sum by (platform) (increase(monitor_agent_scans_failed_total[1h]))
+  / (sum by (platform) (increase(monitor_agent_scans_failed_total[1h])) + sum by (platform) (increase(monitor_agent_scans_completed_total[1h])))
+  > %g`, t.ScanFailureRatio),
+				For:    "15m",
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "Program scans on {{ $labels.platform }} are failing",
+					"description": fmt.Sprintf("More than %g%% of the program scans on {{ $labels.platform }} failed in the last hour.", t.ScanFailureRatio*100),
+				},
+			},
+			{
+				Alert:  "MonitorAgentZeroAssetScans",
+				Expr:   fmt.Sprintf(`sum by (platform) (increase(monitor_agent_scans_zero_assets_total[%s])) > 0`, promDuration(t.ZeroAssetWindow)),
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "Scans on {{ $labels.platform }} completed without finding any assets",
+					"description": fmt.Sprintf("{{ $value }} program scans on {{ $labels.platform }} completed with zero assets in the last %s, which usually means a broken discovery source or API credentials rather than an empty program.", promDuration(t.ZeroAssetWindow)),
+				},
+			},
+			{
+				Alert: "MonitorAgentPlatformErrorSpike",
+				Expr: fmt.Sprintf(`sum by (platform) (rate(monitor_agent_platform_errors_total[10m]))
+  / sum by (platform) (rate(monitor_agent_platform_requests_total[10m]))
+  > %g`, t.PlatformErrorRatio),
+				For:    "10m",
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "{{ $labels.platform }} API errors are spiking",
+					"description": fmt.Sprintf("More than %g%% of the requests to the {{ $labels.platform }} API failed over the last 10 minutes.", t.PlatformErrorRatio*100),
+				},
+			},
+			{
+				Alert: "MonitorAgentDBPoolSaturated",
+				Expr: fmt.Sprintf(`(max(monitor_agent_db_connection_pool{status="open"}) - max(monitor_agent_db_connection_pool{status="idle"}))
+  / max(monitor_agent_db_connection_pool{status="max_open"})
+  > %g`, t.PoolSaturationRatio),
+				For:    promDuration(t.PoolSaturationPeriod),
+				Labels: map[string]string{"severity": "critical"},
+				Annotations: map[string]string{
+					"summary":     "The database connection pool is saturated",
+					"description": fmt.Sprintf("More than %g%% of the database connections have been in use for %s, so queries wait for a connection. Raise DB_MAX_OPEN_CONNS.", t.PoolSaturationRatio*100, promDuration(t.PoolSaturationPeriod)),
+				},
+			},
+			{
+				Alert:  "MonitorAgentFreshnessSLOViolated",
+				Expr:   fmt.Sprintf(`min by (objective) (monitor_agent_slo_compliance_ratio) < %g`, t.SLOComplianceRatio),
+				For:    "1h",
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "The {{ $labels.objective }} freshness SLO is violated",
+					"description": fmt.Sprintf("Less than %g%% of the tracked programs or assets met the {{ $labels.objective }} freshness SLO for an hour. Check for failing scans or raise DAEMON_SWEEP_REQUESTS_PER_HOUR.", t.SLOComplianceRatio*100),
+				},
+			},
+		},
+	}}
+}
+
+// RulesYAML renders rule groups as a Prometheus rules file
+func RulesYAML(groups []RuleGroup) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err := encoder.Encode(struct {
+		Groups []RuleGroup `yaml:"groups"`
+	}{Groups: groups})
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render alerting rules: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// promDuration formats a duration the way Prometheus writes them, e.g. 6h or 90s
+func promDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "0s"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
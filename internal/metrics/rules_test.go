@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRecommendedRules(t *testing.T) {
+	thresholds := DefaultRuleThresholds()
+	require.NoError(t, thresholds.Validate())
+
+	data, err := RulesYAML(RecommendedRules(thresholds))
+	require.NoError(t, err)
+
+	var file struct {
+		Groups []RuleGroup `yaml:"groups"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &file))
+	require.Len(t, file.Groups, 1)
+
+	rules := make(map[string]AlertingRule)
+	for _, rule := range file.Groups[0].Rules {
+		rules[rule.Alert] = rule
+		assert.Contains(t, rule.Expr, "monitor_agent_", rule.Alert)
+		assert.NotEmpty(t, rule.Annotations["summary"], rule.Alert)
+		assert.NotEmpty(t, rule.Labels["severity"], rule.Alert)
+	}
+
+	assert.Contains(t, rules["MonitorAgentScanFailureRate"].Expr, "> 0.2")
+	assert.Contains(t, rules["MonitorAgentZeroAssetScans"].Expr, "monitor_agent_scans_zero_assets_total[6h]")
+	assert.Contains(t, rules["MonitorAgentPlatformErrorSpike"].Expr, "> 0.1")
+	assert.Contains(t, rules["MonitorAgentDBPoolSaturated"].Expr, `status="max_open"`)
+	assert.Equal(t, "10m", rules["MonitorAgentDBPoolSaturated"].For)
+	assert.Contains(t, rules["MonitorAgentFreshnessSLOViolated"].Expr, "< 0.95")
+}
+
+func TestRuleThresholds_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*RuleThresholds)
+		valid  bool
+	}{
+		{"defaults", func(*RuleThresholds) {}, true},
+		{"zero failure ratio", func(th *RuleThresholds) { th.ScanFailureRatio = 0 }, false},
+		{"ratio above one", func(th *RuleThresholds) { th.PoolSaturationRatio = 1.5 }, false},
+		{"short zero asset window", func(th *RuleThresholds) { th.ZeroAssetWindow = time.Second }, false},
+		{"negative saturation period", func(th *RuleThresholds) { th.PoolSaturationPeriod = -time.Minute }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thresholds := DefaultRuleThresholds()
+			tt.modify(&thresholds)
+			err := thresholds.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	assert.Equal(t, "6h", promDuration(6*time.Hour))
+	assert.Equal(t, "90m", promDuration(90*time.Minute))
+	assert.Equal(t, "45s", promDuration(45*time.Second))
+	assert.Equal(t, "0s", promDuration(0))
+}
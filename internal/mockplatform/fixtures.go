@@ -0,0 +1,142 @@
+package mockplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// Fixtures is the data the mock platform serves
+type Fixtures struct {
+	HackerOne []Program `json:"hackerone"`
+	BugCrowd  []Program `json:"bugcrowd"`
+
+	// Subdomains maps a root domain to the hostnames ChaosDB returns for it
+	Subdomains map[string][]string `json:"subdomains"`
+}
+
+// Program is a bug bounty program served by the mock platform
+type Program struct {
+	Handle  string   `json:"handle"` // HackerOne handle or BugCrowd code
+	Name    string   `json:"name"`
+	Website string   `json:"website"`
+	Private bool     `json:"private"` // listed, but filtered out by the clients like a private program
+	Dataset bool     `json:"dataset"` // publish a ChaosDB bulk dataset for the program
+	Scope   []Target `json:"scope"`
+}
+
+// Target is a scope entry, typed as the platform types it
+// (URL/WILDCARD/CIDR on HackerOne, website/wildcard on BugCrowd)
+type Target struct {
+	Identifier string `json:"identifier"`
+	Type       string `json:"type"`
+	OutOfScope bool   `json:"out_of_scope"`
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures %s: %w", path, err)
+	}
+
+	if err := fixtures.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fixtures %s: %w", path, err)
+	}
+
+	return &fixtures, nil
+}
+
+// Validate checks that every program has a unique handle
+func (f *Fixtures) Validate() error {
+	for platform, programs := range map[string][]Program{"hackerone": f.HackerOne, "bugcrowd": f.BugCrowd} {
+		seen := make(map[string]bool)
+		for _, program := range programs {
+			if program.Handle == "" {
+				return fmt.Errorf("%s program %q has no handle", platform, program.Name)
+			}
+			if seen[program.Handle] {
+				return fmt.Errorf("duplicate %s program handle %q", platform, program.Handle)
+			}
+			seen[program.Handle] = true
+		}
+	}
+	return nil
+}
+
+// DefaultFixtures returns a small set of programs covering the scope types
+// the agent handles, used when no fixtures file is given
+func DefaultFixtures() *Fixtures {
+	return &Fixtures{
+		HackerOne: []Program{
+			{
+				Handle:  "acme",
+				Name:    "Acme Corp",
+				Website: "https://acme.example",
+				Dataset: true,
+				Scope: []Target{
+					{Identifier: "*.acme.example", Type: "WILDCARD"},
+					{Identifier: "https://shop.acme.example", Type: "URL"},
+					{Identifier: "192.0.2.0/28", Type: "CIDR"},
+					{Identifier: "legacy.acme.example", Type: "URL", OutOfScope: true},
+				},
+			},
+			{
+				Handle:  "globex",
+				Name:    "Globex",
+				Website: "https://globex.example",
+				Scope: []Target{
+					{Identifier: "*.globex.example", Type: "WILDCARD"},
+				},
+			},
+			{
+				Handle:  "initech-private",
+				Name:    "Initech (private)",
+				Website: "https://initech.example",
+				Private: true,
+				Scope: []Target{
+					{Identifier: "*.initech.example", Type: "WILDCARD"},
+				},
+			},
+		},
+		BugCrowd: []Program{
+			{
+				Handle:  "hooli",
+				Name:    "Hooli",
+				Website: "https://hooli.example",
+				Scope: []Target{
+					{Identifier: "*.hooli.example", Type: "wildcard"},
+					{Identifier: "https://www.hooli.example", Type: "website"},
+				},
+			},
+		},
+		Subdomains: map[string][]string{
+			"acme.example":   {"www.acme.example", "api.acme.example", "staging.acme.example", "legacy.acme.example"},
+			"globex.example": {"www.globex.example", "mail.globex.example"},
+			"hooli.example":  {"www.hooli.example", "api.hooli.example", "*.dev.hooli.example"},
+		},
+	}
+}
+
+// scopeDomain returns the root domain of a scope identifier, or "" for
+// identifiers that are not domains
+func scopeDomain(identifier string) string {
+	domain := strings.ToLower(strings.TrimSpace(identifier))
+	domain = strings.TrimPrefix(domain, "https://")
+	domain = strings.TrimPrefix(domain, "http://")
+	domain = strings.TrimPrefix(domain, "*.")
+	if idx := strings.IndexAny(domain, "/:"); idx != -1 {
+		domain = domain[:idx]
+	}
+	if !strings.Contains(domain, ".") || strings.Contains(domain, "*") || net.ParseIP(domain) != nil {
+		return ""
+	}
+	return domain
+}
@@ -0,0 +1,415 @@
+package mockplatform
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/monitor-agent/internal/discovery/chaosdb"
+	"github.com/monitor-agent/internal/httpapi"
+	"github.com/monitor-agent/internal/platforms/bugcrowd"
+	"github.com/monitor-agent/internal/platforms/hackerone"
+	"github.com/sirupsen/logrus"
+)
+
+// Path prefixes the emulated APIs are served under. Point the agent at them
+// with HACKERONE_BASE_URL, BUGCROWD_BASE_URL, CHAOSDB_BASE_URL and
+// CHAOSDB_DATASET_INDEX_URL.
+const (
+	HackerOnePath       = "/hackerone/v1"
+	BugCrowdPath        = "/bugcrowd"
+	ChaosDBPath         = "/chaosdb/dns"
+	DatasetIndexPath    = "/chaosdb/index.json"
+	datasetDownloadPath = "/chaosdb/datasets/"
+)
+
+// Options controls the mock platform's behaviour
+type Options struct {
+	Fixtures *Fixtures
+
+	// Latency is added to every response, plus a random delay of up to Jitter
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// ErrorRate is the fraction of requests (0-1) answered with ErrorStatus
+	ErrorRate   float64
+	ErrorStatus int
+
+	// APIKey, when set, is the only credential accepted; other requests get a 401
+	APIKey string
+
+	// Seed makes injected jitter and errors reproducible
+	Seed int64
+}
+
+// Server emulates the HackerOne, BugCrowd and ChaosDB APIs
+type Server struct {
+	options  Options
+	fixtures *Fixtures
+	mux      *http.ServeMux
+
+	rngMu sync.Mutex
+	rng   *rand.Rand
+}
+
+// NewServer creates a new mock platform handler
+func NewServer(options Options) *Server {
+	fixtures := options.Fixtures
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	if options.ErrorStatus == 0 {
+		options.ErrorStatus = http.StatusInternalServerError
+	}
+
+	s := &Server{
+		options:  options,
+		fixtures: fixtures,
+		mux:      http.NewServeMux(),
+		rng:      rand.New(rand.NewSource(options.Seed)),
+	}
+
+	s.mux.HandleFunc("GET "+HackerOnePath+"/hackers/programs", s.handleHackerOnePrograms)
+	s.mux.HandleFunc("GET "+HackerOnePath+"/hackers/programs/{handle}/structured_scopes", s.handleHackerOneScope)
+	s.mux.HandleFunc("GET "+BugCrowdPath+"/programs", s.handleBugCrowdPrograms)
+	s.mux.HandleFunc("GET "+BugCrowdPath+"/programs/{code}/targets", s.handleBugCrowdTargets)
+	s.mux.HandleFunc("GET "+ChaosDBPath, s.handleChaosDBHealth)
+	s.mux.HandleFunc("GET "+ChaosDBPath+"/{domain}/subdomains", s.handleChaosDBSubdomains)
+	s.mux.HandleFunc("GET "+DatasetIndexPath, s.handleDatasetIndex)
+	s.mux.HandleFunc("GET "+datasetDownloadPath+"{name}", s.handleDatasetDownload)
+	return s
+}
+
+// ServeHTTP applies the configured latency, errors and authentication, then routes the request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logrus.Debugf("%s %s", r.Method, r.URL.RequestURI())
+
+	delay, fail := s.inject()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fail {
+		if s.options.ErrorStatus == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		writePlatformError(w, r, s.options.ErrorStatus, "injected error")
+		return
+	}
+
+	if !s.authorized(r) {
+		writePlatformError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// inject decides the delay and whether to fail the current request
+func (s *Server) inject() (time.Duration, bool) {
+	s.rngMu.Lock()
+	defer s.rngMu.Unlock()
+
+	delay := s.options.Latency
+	if s.options.Jitter > 0 {
+		delay += time.Duration(s.rng.Int63n(int64(s.options.Jitter)))
+	}
+	fail := s.options.ErrorRate > 0 && s.rng.Float64() < s.options.ErrorRate
+	return delay, fail
+}
+
+// authorized accepts HackerOne basic auth, BugCrowd "Token" and ChaosDB
+// "Bearer" credentials carrying the configured API key
+func (s *Server) authorized(r *http.Request) bool {
+	if s.options.APIKey == "" {
+		return true
+	}
+
+	key := r.Header.Get("Authorization")
+	if _, password, ok := r.BasicAuth(); ok {
+		key = password
+	} else {
+		key = strings.TrimPrefix(strings.TrimPrefix(key, "Token "), "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(s.options.APIKey)) == 1
+}
+
+// handleHackerOnePrograms lists programs using HackerOne's page[number]/page[size] pagination
+func (s *Server) handleHackerOnePrograms(w http.ResponseWriter, r *http.Request) {
+	page := queryInt(r, "page[number]", 1)
+	size := queryInt(r, "page[size]", 25)
+	start, end, pageCount := pageBounds(len(s.fixtures.HackerOne), page, size)
+
+	resp := hackerone.HackerOneResponse{
+		Data: []hackerone.HackerOneProgram{},
+		Meta: hackerone.ResponseMeta{TotalCount: len(s.fixtures.HackerOne), PageCount: pageCount, PageSize: size, Page: page},
+	}
+	for _, program := range s.fixtures.HackerOne[start:end] {
+		state := "public_mode"
+		if program.Private {
+			state = "soft_launched"
+		}
+		resp.Data = append(resp.Data, hackerone.HackerOneProgram{
+			ID:   program.Handle,
+			Type: "program",
+			Attributes: hackerone.ProgramAttributes{
+				Name:           program.Name,
+				Handle:         program.Handle,
+				URL:            "https://hackerone.com/" + program.Handle,
+				Website:        program.Website,
+				State:          state,
+				OffersBounties: true,
+			},
+		})
+	}
+	if page < pageCount {
+		resp.Links.Next = fmt.Sprintf("%s%s/hackers/programs?page[number]=%d&page[size]=%d", requestOrigin(r), HackerOnePath, page+1, size)
+	}
+
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+// handleHackerOneScope returns a program's structured scopes
+func (s *Server) handleHackerOneScope(w http.ResponseWriter, r *http.Request) {
+	program := findProgram(s.fixtures.HackerOne, r.PathValue("handle"))
+	if program == nil {
+		writePlatformError(w, r, http.StatusNotFound, "program not found")
+		return
+	}
+
+	resp := hackerone.ScopeResponse{Data: []hackerone.HackerOneScope{}}
+	for i, target := range program.Scope {
+		resp.Data = append(resp.Data, hackerone.HackerOneScope{
+			ID:   fmt.Sprintf("%s-%d", program.Handle, i),
+			Type: "structured-scope",
+			Attributes: hackerone.ScopeAttributes{
+				AssetIdentifier:       target.Identifier,
+				AssetType:             target.Type,
+				EligibleForBounty:     !target.OutOfScope,
+				EligibleForSubmission: !target.OutOfScope,
+			},
+		})
+	}
+
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+// handleBugCrowdPrograms lists programs using BugCrowd's page/per_page pagination
+func (s *Server) handleBugCrowdPrograms(w http.ResponseWriter, r *http.Request) {
+	page := queryInt(r, "page", 1)
+	size := queryInt(r, "per_page", 25)
+	start, end, pageCount := pageBounds(len(s.fixtures.BugCrowd), page, size)
+
+	resp := bugcrowd.BugCrowdResponse{
+		Programs: []bugcrowd.BugCrowdProgram{},
+		Meta:     bugcrowd.ResponseMeta{TotalCount: len(s.fixtures.BugCrowd), PageCount: pageCount, PageSize: size, Page: page},
+	}
+	for _, program := range s.fixtures.BugCrowd[start:end] {
+		status := "public"
+		if program.Private {
+			status = "private"
+		}
+		resp.Programs = append(resp.Programs, bugcrowd.BugCrowdProgram{
+			UUID:   program.Handle,
+			Name:   program.Name,
+			Code:   program.Handle,
+			URL:    program.Website,
+			Status: status,
+			Type:   "bug_bounty",
+		})
+	}
+
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+// handleBugCrowdTargets returns a program's targets
+func (s *Server) handleBugCrowdTargets(w http.ResponseWriter, r *http.Request) {
+	program := findProgram(s.fixtures.BugCrowd, r.PathValue("code"))
+	if program == nil {
+		writePlatformError(w, r, http.StatusNotFound, "program not found")
+		return
+	}
+
+	resp := bugcrowd.ScopeResponse{Targets: []bugcrowd.BugCrowdScope{}}
+	for i, target := range program.Scope {
+		resp.Targets = append(resp.Targets, bugcrowd.BugCrowdScope{
+			UUID:       fmt.Sprintf("%s-%d", program.Handle, i),
+			Target:     target.Identifier,
+			Type:       target.Type,
+			Eligible:   !target.OutOfScope,
+			Ineligible: target.OutOfScope,
+		})
+	}
+	resp.Meta = bugcrowd.ResponseMeta{TotalCount: len(resp.Targets), PageCount: 1, PageSize: len(resp.Targets), Page: 1}
+
+	httpapi.WriteJSON(w, http.StatusOK, resp)
+}
+
+// handleChaosDBHealth answers the ChaosDB health check
+func (s *Server) handleChaosDBHealth(w http.ResponseWriter, r *http.Request) {
+	httpapi.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleChaosDBSubdomains returns the subdomains known for a domain
+func (s *Server) handleChaosDBSubdomains(w http.ResponseWriter, r *http.Request) {
+	domain := strings.ToLower(r.PathValue("domain"))
+	subdomains, ok := s.fixtures.Subdomains[domain]
+	if !ok {
+		writePlatformError(w, r, http.StatusNotFound, "domain not found")
+		return
+	}
+
+	httpapi.WriteJSON(w, http.StatusOK, chaosdb.ChaosDBResponse{
+		Domain:     domain,
+		Subdomains: subdomains,
+		Count:      len(subdomains),
+	})
+}
+
+// handleDatasetIndex lists the bulk datasets of programs that publish one
+func (s *Server) handleDatasetIndex(w http.ResponseWriter, r *http.Request) {
+	datasets := []*chaosdb.Dataset{}
+	for platform, programs := range map[string][]Program{"hackerone": s.fixtures.HackerOne, "bugcrowd": s.fixtures.BugCrowd} {
+		for _, program := range programs {
+			if !program.Dataset {
+				continue
+			}
+			datasets = append(datasets, &chaosdb.Dataset{
+				Name:        program.Handle,
+				ProgramURL:  programURL(platform, program.Handle),
+				URL:         requestOrigin(r) + datasetDownloadPath + program.Handle + ".zip",
+				Count:       len(s.datasetSubdomains(program)),
+				Platform:    platform,
+				LastUpdated: time.Now().UTC(),
+			})
+		}
+	}
+	sort.Slice(datasets, func(i, j int) bool { return datasets[i].ProgramURL < datasets[j].ProgramURL })
+
+	httpapi.WriteJSON(w, http.StatusOK, datasets)
+}
+
+// handleDatasetDownload serves a program's dataset as a zip of <domain>.txt files
+func (s *Server) handleDatasetDownload(w http.ResponseWriter, r *http.Request) {
+	handle := strings.TrimSuffix(r.PathValue("name"), ".zip")
+	program := findProgram(s.fixtures.HackerOne, handle)
+	if program == nil {
+		program = findProgram(s.fixtures.BugCrowd, handle)
+	}
+	if program == nil || !program.Dataset {
+		writePlatformError(w, r, http.StatusNotFound, "dataset not found")
+		return
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for domain, subdomains := range s.datasetSubdomains(*program) {
+		file, err := archive.Create(domain + ".txt")
+		if err != nil {
+			writePlatformError(w, r, http.StatusInternalServerError, "failed to build dataset")
+			return
+		}
+		if _, err := file.Write([]byte(strings.Join(subdomains, "\n") + "\n")); err != nil {
+			writePlatformError(w, r, http.StatusInternalServerError, "failed to build dataset")
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		writePlatformError(w, r, http.StatusInternalServerError, "failed to build dataset")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logrus.Errorf("Failed to write dataset %s: %v", handle, err)
+	}
+}
+
+// datasetSubdomains returns the subdomains of every in-scope domain of a program
+func (s *Server) datasetSubdomains(program Program) map[string][]string {
+	subdomains := make(map[string][]string)
+	for _, target := range program.Scope {
+		domain := scopeDomain(target.Identifier)
+		if target.OutOfScope || domain == "" {
+			continue
+		}
+		if known, ok := s.fixtures.Subdomains[domain]; ok {
+			subdomains[domain] = known
+		}
+	}
+	return subdomains
+}
+
+// findProgram looks up a program by handle
+func findProgram(programs []Program, handle string) *Program {
+	for i := range programs {
+		if strings.EqualFold(programs[i].Handle, handle) {
+			return &programs[i]
+		}
+	}
+	return nil
+}
+
+// programURL builds the program URL the platform clients produce
+func programURL(platform, handle string) string {
+	if platform == "bugcrowd" {
+		return "https://bugcrowd.com/" + handle
+	}
+	return "https://hackerone.com/" + handle
+}
+
+// pageBounds returns the slice bounds of a 1-based page and the number of pages
+func pageBounds(total, page, size int) (int, int, int) {
+	pageCount := (total + size - 1) / size
+	start := (page - 1) * size
+	if start > total {
+		start = total
+	}
+	end := start + size
+	if end > total {
+		end = total
+	}
+	return start, end, pageCount
+}
+
+// queryInt reads a positive integer query parameter
+func queryInt(r *http.Request, key string, fallback int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// requestOrigin returns the scheme and host the request was made to
+func requestOrigin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// writePlatformError writes an error in the format of the API the request was made to
+func writePlatformError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if strings.HasPrefix(r.URL.Path, HackerOnePath+"/") {
+		httpapi.WriteJSON(w, status, hackerone.ErrorResponse{
+			Errors: []hackerone.ErrorDetail{{Status: strconv.Itoa(status), Title: http.StatusText(status), Detail: message}},
+		})
+		return
+	}
+	httpapi.WriteJSON(w, status, bugcrowd.BugCrowdError{Error: http.StatusText(status), Message: message, Code: status})
+}
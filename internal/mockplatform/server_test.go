@@ -0,0 +1,184 @@
+package mockplatform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/discovery/chaosdb"
+	"github.com/monitor-agent/internal/platforms/bugcrowd"
+	"github.com/monitor-agent/internal/platforms/hackerone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, options Options) *httptest.Server {
+	server := httptest.NewServer(NewServer(options))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestServer_HackerOne(t *testing.T) {
+	server := newTestServer(t, Options{})
+	client := hackerone.NewHackerOneClient(&hackerone.PlatformConfig{
+		APIKey:    "key",
+		Username:  "user",
+		RateLimit: 6000,
+		Timeout:   5 * time.Second,
+		BaseURL:   server.URL + HackerOnePath,
+	})
+
+	require.NoError(t, client.IsHealthy(context.Background()))
+
+	programs, err := client.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	require.Len(t, programs, 2, "private programs are filtered out")
+	assert.Equal(t, "https://hackerone.com/acme", programs[0].ProgramURL)
+
+	scope, err := client.GetProgramScope(context.Background(), "https://hackerone.com/acme")
+	require.NoError(t, err)
+	require.Len(t, scope, 4)
+	assert.Equal(t, "wildcard", scope[0].Type)
+	assert.Equal(t, "acme.example", scope[0].Domain)
+	assert.False(t, scope[3].EligibleForSubmission)
+
+	_, err = client.GetProgramScope(context.Background(), "https://hackerone.com/unknown")
+	assert.ErrorContains(t, err, "program not found")
+}
+
+func TestServer_HackerOnePagination(t *testing.T) {
+	fixtures := &Fixtures{}
+	for _, handle := range []string{"a", "b", "c"} {
+		fixtures.HackerOne = append(fixtures.HackerOne, Program{Handle: handle, Name: handle})
+	}
+	server := newTestServer(t, Options{Fixtures: fixtures})
+
+	resp, err := http.Get(server.URL + HackerOnePath + "/hackers/programs?page[number]=1&page[size]=2")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var page hackerone.HackerOneResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	assert.Len(t, page.Data, 2)
+	assert.Equal(t, server.URL+HackerOnePath+"/hackers/programs?page[number]=2&page[size]=2", page.Links.Next)
+}
+
+func TestServer_BugCrowd(t *testing.T) {
+	server := newTestServer(t, Options{})
+	client := bugcrowd.NewBugCrowdClient(&bugcrowd.PlatformConfig{
+		APIKey:    "key",
+		RateLimit: 6000,
+		Timeout:   5 * time.Second,
+		BaseURL:   server.URL + BugCrowdPath,
+	})
+
+	programs, err := client.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+	assert.Equal(t, "https://bugcrowd.com/hooli", programs[0].ProgramURL)
+
+	scope, err := client.GetProgramScope(context.Background(), programs[0].ProgramURL)
+	require.NoError(t, err)
+	assert.Len(t, scope, 2)
+}
+
+func TestServer_ChaosDB(t *testing.T) {
+	server := newTestServer(t, Options{})
+	client := chaosdb.NewClient(&chaosdb.ClientConfig{
+		APIKey:          "key",
+		RateLimit:       6000,
+		Timeout:         5 * time.Second,
+		BaseURL:         server.URL + ChaosDBPath,
+		DatasetIndexURL: server.URL + DatasetIndexPath,
+	})
+
+	require.NoError(t, client.IsHealthy(context.Background()))
+
+	result, err := client.DiscoverDomain(context.Background(), "globex.example")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"www.globex.example", "mail.globex.example"}, result.Subdomains)
+
+	result, err = client.DiscoverDomain(context.Background(), "unknown.example")
+	require.NoError(t, err)
+	assert.Empty(t, result.Subdomains)
+
+	dataset, err := client.FindDataset(context.Background(), "https://hackerone.com/acme")
+	require.NoError(t, err)
+	require.NotNil(t, dataset)
+
+	subdomains, err := client.DownloadDataset(context.Background(), dataset)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"acme.example": {"www.acme.example", "api.acme.example", "staging.acme.example", "legacy.acme.example"},
+	}, subdomains)
+
+	dataset, err = client.FindDataset(context.Background(), "https://hackerone.com/globex")
+	require.NoError(t, err)
+	assert.Nil(t, dataset)
+}
+
+func TestServer_InjectsErrors(t *testing.T) {
+	server := newTestServer(t, Options{ErrorRate: 1, ErrorStatus: http.StatusTooManyRequests})
+
+	resp, err := http.Get(server.URL + BugCrowdPath + "/programs")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+}
+
+func TestServer_InjectsLatency(t *testing.T) {
+	server := newTestServer(t, Options{Latency: 50 * time.Millisecond})
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + ChaosDBPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestServer_RequiresAPIKey(t *testing.T) {
+	server := newTestServer(t, Options{APIKey: "secret"})
+
+	wrong := hackerone.NewHackerOneClient(&hackerone.PlatformConfig{
+		APIKey: "wrong", Username: "user", RateLimit: 6000, Timeout: 5 * time.Second, BaseURL: server.URL + HackerOnePath,
+	})
+	assert.Error(t, wrong.IsHealthy(context.Background()))
+
+	right := bugcrowd.NewBugCrowdClient(&bugcrowd.PlatformConfig{
+		APIKey: "secret", RateLimit: 6000, Timeout: 5 * time.Second, BaseURL: server.URL + BugCrowdPath,
+	})
+	assert.NoError(t, right.IsHealthy(context.Background()))
+}
+
+func TestLoadFixtures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"hackerone": [{"handle": "acme", "name": "Acme", "scope": [{"identifier": "*.acme.example", "type": "WILDCARD"}]}],
+		"subdomains": {"acme.example": ["www.acme.example"]}
+	}`), 0o600))
+
+	fixtures, err := LoadFixtures(path)
+	require.NoError(t, err)
+	assert.Len(t, fixtures.HackerOne, 1)
+	assert.Equal(t, []string{"www.acme.example"}, fixtures.Subdomains["acme.example"])
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"bugcrowd": [{"handle": "a"}, {"handle": "a"}]}`), 0o600))
+	_, err = LoadFixtures(path)
+	assert.ErrorContains(t, err, "duplicate bugcrowd program handle")
+}
+
+func TestScopeDomain(t *testing.T) {
+	assert.Equal(t, "acme.example", scopeDomain("*.acme.example"))
+	assert.Equal(t, "shop.acme.example", scopeDomain("https://shop.acme.example/path"))
+	assert.Equal(t, "", scopeDomain("192.0.2.0/28"))
+	assert.Equal(t, "", scopeDomain("com.acme.*"))
+}
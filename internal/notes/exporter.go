@@ -0,0 +1,172 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/sirupsen/logrus"
+)
+
+// IndexFile is the note listing every exported program
+const IndexFile = "index.md"
+
+// ExportSummary counts the notes an export wrote
+type ExportSummary struct {
+	Programs int // program notes written
+	Created  int // program notes that did not exist yet
+}
+
+// Exporter writes one Markdown note per program into a directory laid out as
+// an Obsidian vault or notes repository: <platform>/<handle>.md plus an
+// index. Exports only replace the generated sections of existing notes, so
+// notes written around them survive every export.
+type Exporter struct {
+	dir            string
+	excludeSources []string
+	programRepo    *database.ProgramRepository
+	assetRepo      *database.AssetRepository
+	coverageRepo   *database.CoverageRepository
+	notesRepo      *database.NotesRepository
+}
+
+// NewExporter creates a new exporter writing into dir. Assets only
+// excludeSources found are left out of the notes.
+func NewExporter(db *sqlx.DB, dir string, excludeSources []string) *Exporter {
+	return &Exporter{
+		dir:            dir,
+		excludeSources: excludeSources,
+		programRepo:    database.NewProgramRepository(db),
+		assetRepo:      database.NewAssetRepository(db),
+		coverageRepo:   database.NewCoverageRepository(db),
+		notesRepo:      database.NewNotesRepository(db),
+	}
+}
+
+// Export writes the notes of every active program, or only of one program,
+// and rewrites the index
+func (e *Exporter) Export(ctx context.Context, programID *uuid.UUID) (*ExportSummary, error) {
+	summary := &ExportSummary{}
+
+	programs, err := e.programRepo.GetAllActivePrograms(ctx)
+	if err != nil {
+		return summary, err
+	}
+
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		return summary, fmt.Errorf("failed to create notes directory: %w", err)
+	}
+
+	for _, program := range programs {
+		if programID != nil && program.ID != *programID {
+			continue
+		}
+
+		note, err := e.programNote(ctx, program)
+		if err != nil {
+			return summary, err
+		}
+
+		created, err := e.writeNote(NotePath(program), func(existing string) string {
+			return RenderProgram(existing, note)
+		})
+		if err != nil {
+			return summary, err
+		}
+		summary.Programs++
+		if created {
+			summary.Created++
+		}
+	}
+
+	if _, err := e.writeNote(IndexFile, func(existing string) string {
+		return RenderIndex(existing, programs)
+	}); err != nil {
+		return summary, err
+	}
+
+	logrus.Infof("Exported %d program notes to %s (%d new)", summary.Programs, e.dir, summary.Created)
+	return summary, nil
+}
+
+// programNote gathers the data of a program's note
+func (e *Exporter) programNote(ctx context.Context, program *database.Program) (*ProgramNote, error) {
+	assets, err := e.assetRepo.GetAssetsByProgramID(ctx, program.ID)
+	if err != nil {
+		return nil, err
+	}
+	assets = database.ExcludeSources(assets, e.excludeSources)
+
+	responses, err := e.notesRepo.GetNotableResponses(ctx, program.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The scope domains of the latest scan with coverage, or the apex domains
+	// of the assets when no scan has recorded coverage
+	domains, err := e.coverageRepo.GetDomainCoverage(ctx, program.ID, 1)
+	if err != nil {
+		return nil, err
+	}
+	scope := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		scope = append(scope, domain.Domain)
+	}
+	if len(scope) == 0 {
+		seen := make(map[string]bool)
+		for _, asset := range assets {
+			if asset.Domain != "" && !seen[asset.Domain] {
+				seen[asset.Domain] = true
+				scope = append(scope, asset.Domain)
+			}
+		}
+	}
+	sort.Strings(scope)
+
+	return &ProgramNote{Program: program, Scope: scope, Assets: assets, Responses: responses}, nil
+}
+
+// writeNote renders a note from its current contents and replaces it
+// atomically. It reports whether the note is new.
+func (e *Exporter) writeNote(name string, render func(existing string) string) (bool, error) {
+	path := filepath.Join(e.dir, filepath.FromSlash(name))
+
+	existing, err := os.ReadFile(path)
+	created := errors.Is(err, os.ErrNotExist)
+	if err != nil && !created {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(render(string(existing))); err != nil {
+		tmp.Close()
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return created, nil
+}
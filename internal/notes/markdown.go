@@ -0,0 +1,269 @@
+package notes
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/monitor-agent/internal/database"
+)
+
+// Names of the generated sections of a program note
+const (
+	SectionSummary   = "summary"
+	SectionScope     = "scope"
+	SectionAssets    = "assets"
+	SectionResponses = "responses"
+	SectionPrograms  = "programs" // the program list of the index note
+)
+
+// ProgramNote is the data a program's note is generated from
+type ProgramNote struct {
+	Program   *database.Program
+	Scope     []string // scope domains
+	Assets    []*database.Asset
+	Responses []*database.NotableResponse
+}
+
+// section is a generated part of a note, kept between begin and end markers
+// so it can be replaced without touching anything written around it
+type section struct {
+	name    string
+	heading string // heading the section is added under when it is missing; empty for none
+	content string
+}
+
+// beginMarker and endMarker delimit a generated section. They are HTML
+// comments, so Markdown renderers and Obsidian hide them.
+func beginMarker(name string) string { return "<!-- monitor-agent:begin " + name + " -->" }
+func endMarker(name string) string   { return "<!-- monitor-agent:end " + name + " -->" }
+
+// NotePath returns the path of a program's note relative to the vault, e.g.
+// hackerone/acme.md
+func NotePath(program *database.Program) string {
+	handle := path.Base(strings.TrimRight(program.ProgramURL, "/"))
+	if handle == "." || handle == "/" || handle == "" {
+		handle = program.Name
+	}
+	return path.Join(safeName(program.Platform), safeName(handle)+".md")
+}
+
+// safeName replaces the characters Obsidian and common file systems reject in
+// file names
+func safeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|#^[]`, r) || r < ' ' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
+
+// blockID is the Obsidian block reference of an asset's line, e.g. linked to
+// as [[acme#^<asset id>]]. It is the asset ID, so it never changes between
+// exports.
+func blockID(asset *database.Asset) string {
+	return "^" + asset.ID.String()
+}
+
+// programSections renders the generated sections of a program note
+func programSections(note *ProgramNote) []section {
+	return []section{
+		{name: SectionSummary, content: renderSummary(note)},
+		{name: SectionScope, heading: "Scope", content: renderScope(note)},
+		{name: SectionAssets, heading: "Assets", content: renderAssets(note)},
+		{name: SectionResponses, heading: "Notable Responses", content: renderResponses(note)},
+	}
+}
+
+// RenderProgram merges a program's generated sections into its existing note.
+// Text outside the generated sections, such as personal notes, is kept as
+// is; a new note gets front matter and an empty Notes heading.
+func RenderProgram(existing string, note *ProgramNote) string {
+	sections := programSections(note)
+	if strings.TrimSpace(existing) != "" {
+		return mergeSections(existing, sections)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\nplatform: %s\nprogram_url: %s\ntags:\n  - bug-bounty\n  - %s\n---\n\n",
+		note.Program.Platform, note.Program.ProgramURL, safeName(note.Program.Platform))
+	fmt.Fprintf(&b, "# %s\n\n", strings.TrimSpace(note.Program.Name))
+	for _, s := range sections {
+		if s.heading != "" {
+			fmt.Fprintf(&b, "## %s\n\n", s.heading)
+		}
+		b.WriteString(block(s))
+		b.WriteString("\n")
+	}
+	b.WriteString("## Notes\n\n")
+	return b.String()
+}
+
+// RenderIndex merges the program list into the existing index note
+func RenderIndex(existing string, programs []*database.Program) string {
+	sorted := append([]*database.Program(nil), programs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Platform != sorted[j].Platform {
+			return sorted[i].Platform < sorted[j].Platform
+		}
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+
+	var b strings.Builder
+	for _, program := range sorted {
+		link := strings.TrimSuffix(NotePath(program), ".md")
+		fmt.Fprintf(&b, "- [[%s|%s]] (%s)\n", link, escapeText(strings.TrimSpace(program.Name)), program.Platform)
+	}
+	if len(sorted) == 0 {
+		b.WriteString("No active programs\n")
+	}
+
+	programsSection := section{name: SectionPrograms, heading: "Programs", content: b.String()}
+	if strings.TrimSpace(existing) != "" {
+		return mergeSections(existing, []section{programsSection})
+	}
+	return "# Bug Bounty Programs\n\n## Programs\n\n" + block(programsSection) + "\n## Notes\n\n"
+}
+
+// mergeSections replaces each section between its markers in an existing
+// note, and appends sections the note does not have yet
+func mergeSections(existing string, sections []section) string {
+	for _, s := range sections {
+		begin, end := beginMarker(s.name), endMarker(s.name)
+		start := strings.Index(existing, begin)
+		stop := strings.Index(existing, end)
+		if start >= 0 && stop > start {
+			existing = existing[:start] + strings.TrimSuffix(block(s), "\n") + existing[stop+len(end):]
+			continue
+		}
+
+		existing = strings.TrimRight(existing, "\n") + "\n\n"
+		if s.heading != "" {
+			existing += "## " + s.heading + "\n\n"
+		}
+		existing += block(s)
+	}
+	return existing
+}
+
+// block wraps a section's content in its markers
+func block(s section) string {
+	return beginMarker(s.name) + "\n" + strings.TrimRight(s.content, "\n") + "\n" + endMarker(s.name) + "\n"
+}
+
+// renderSummary renders the program's platform, links, asset counts and the
+// usage terms of the assets' data
+func renderSummary(note *ProgramNote) string {
+	live := 0
+	var terms []string
+	seenTerms := make(map[string]bool)
+	for _, asset := range note.Assets {
+		if asset.Liveness == "live" {
+			live++
+		}
+		for _, term := range asset.DataTerms {
+			if !seenTerms[term] {
+				seenTerms[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	sort.Strings(terms)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "- Platform: %s\n", note.Program.Platform)
+	fmt.Fprintf(&b, "- Program: %s\n", note.Program.ProgramURL)
+	if note.Program.URL != "" && note.Program.URL != note.Program.ProgramURL {
+		fmt.Fprintf(&b, "- Website: %s\n", note.Program.URL)
+	}
+	fmt.Fprintf(&b, "- Assets: %d (%d live)\n", len(note.Assets), live)
+	fmt.Fprintf(&b, "- Notable responses: %d\n", len(note.Responses))
+	if len(terms) > 0 {
+		fmt.Fprintf(&b, "- Data terms: %s\n", strings.Join(terms, "; "))
+	}
+	return b.String()
+}
+
+// renderScope lists the program's scope domains
+func renderScope(note *ProgramNote) string {
+	if len(note.Scope) == 0 {
+		return "No scope domains recorded yet\n"
+	}
+
+	var b strings.Builder
+	for _, domain := range note.Scope {
+		fmt.Fprintf(&b, "- `%s`\n", domain)
+	}
+	return b.String()
+}
+
+// renderAssets lists the program's assets, each with a stable block ID
+func renderAssets(note *ProgramNote) string {
+	if len(note.Assets) == 0 {
+		return "No assets discovered yet\n"
+	}
+
+	assets := append([]*database.Asset(nil), note.Assets...)
+	sort.Slice(assets, func(i, j int) bool { return assets[i].URL < assets[j].URL })
+
+	var b strings.Builder
+	for _, asset := range assets {
+		liveness := asset.Liveness
+		if liveness == "" {
+			liveness = "unprobed"
+		}
+		details := []string{liveness, asset.Status}
+		if asset.Ignored {
+			details = append(details, "ignored")
+		}
+		if sources := asset.Sources(); len(sources) > 0 {
+			details = append(details, "via "+strings.Join(sources, "/"))
+		}
+		fmt.Fprintf(&b, "- %s (%s) %s\n", asset.URL, strings.Join(details, ", "), blockID(asset))
+	}
+	return b.String()
+}
+
+// renderResponses lists the assets whose latest response stands out
+func renderResponses(note *ProgramNote) string {
+	if len(note.Responses) == 0 {
+		return "Nothing notable\n"
+	}
+
+	var b strings.Builder
+	for _, response := range note.Responses {
+		var reasons []string
+		if response.StatusCode >= 500 {
+			reasons = append(reasons, fmt.Sprintf("status %d", response.StatusCode))
+		}
+		if response.Rules != "" {
+			reasons = append(reasons, "rules: "+strings.ReplaceAll(response.Rules, ",", ", "))
+		}
+		if response.TLSFindings > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d open TLS findings", response.TLSFindings))
+		}
+		switch response.RedirectStatus {
+		case "loop":
+			reasons = append(reasons, "redirect loop")
+		case "limit":
+			reasons = append(reasons, "redirect limit reached at "+response.FinalURL)
+		case "meta-refresh":
+			reasons = append(reasons, "meta refresh to "+response.MetaRefresh)
+		}
+
+		fmt.Fprintf(&b, "- %s: %s (captured %s, [[#^%s|asset]])\n",
+			response.URL, strings.Join(reasons, "; "), response.CapturedAt.UTC().Format("2006-01-02"), response.AssetID)
+	}
+	return b.String()
+}
+
+// escapeText keeps a name from breaking a wiki link
+func escapeText(text string) string {
+	return strings.NewReplacer("|", "-", "[", "(", "]", ")").Replace(text)
+}
@@ -0,0 +1,103 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNote() *ProgramNote {
+	assetID := uuid.MustParse("6f1c2b7e-4a1d-4f0e-9c3b-2a7d5e8f9a10")
+	return &ProgramNote{
+		Program: &database.Program{Name: "Acme", Platform: "hackerone", ProgramURL: "https://hackerone.com/acme", URL: "https://acme.example"},
+		Scope:   []string{"acme.example"},
+		Assets: []*database.Asset{
+			{ID: assetID, URL: "https://www.acme.example", Liveness: "live", Status: "active"},
+			{ID: uuid.New(), URL: "https://api.acme.example", Status: "active", Ignored: true,
+				Provenance: pq.StringArray{"chaosdb", "hackerone"}, DataTerms: pq.StringArray{"ProjectDiscovery Chaos terms of use"}},
+		},
+		Responses: []*database.NotableResponse{{
+			AssetID:        assetID,
+			URL:            "https://www.acme.example",
+			StatusCode:     302,
+			RedirectStatus: "loop",
+			Rules:          "grafana,jenkins",
+			CapturedAt:     time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		}},
+	}
+}
+
+func TestNotePath(t *testing.T) {
+	assert.Equal(t, "hackerone/acme.md", NotePath(&database.Program{Platform: "hackerone", ProgramURL: "https://hackerone.com/acme/"}))
+	assert.Equal(t, "bugcrowd/a-b.md", NotePath(&database.Program{Platform: "bugcrowd", ProgramURL: "https://bugcrowd.com/a:b"}))
+	assert.Equal(t, "manual/Acme Corp.md", NotePath(&database.Program{Platform: "manual", Name: "Acme Corp"}))
+}
+
+func TestRenderProgram_New(t *testing.T) {
+	note := RenderProgram("", testNote())
+
+	assert.True(t, strings.HasPrefix(note, "---\nplatform: hackerone\n"))
+	assert.Contains(t, note, "# Acme\n")
+	assert.Contains(t, note, "- Assets: 2 (1 live)")
+	assert.Contains(t, note, "- `acme.example`")
+	assert.Contains(t, note, "- https://www.acme.example (live, active) ^6f1c2b7e-4a1d-4f0e-9c3b-2a7d5e8f9a10")
+	assert.Contains(t, note, "- https://api.acme.example (unprobed, active, ignored, via chaosdb/hackerone)")
+	assert.Contains(t, note, "- Data terms: ProjectDiscovery Chaos terms of use\n")
+	assert.Contains(t, note, "- https://www.acme.example: rules: grafana, jenkins; redirect loop (captured 2025-03-01, [[#^6f1c2b7e-4a1d-4f0e-9c3b-2a7d5e8f9a10|asset]])")
+	assert.True(t, strings.HasSuffix(note, "## Notes\n\n"))
+
+	// Assets are listed by URL
+	assert.Less(t, strings.Index(note, "https://api.acme.example"), strings.Index(note, "https://www.acme.example ("))
+}
+
+func TestRenderProgram_KeepsPersonalNotes(t *testing.T) {
+	note := testNote()
+	first := RenderProgram("", note)
+
+	edited := strings.Replace(first, "## Scope", "My triage plan for Acme.\n\n## Scope", 1) +
+		"Tried the login redirect, see [[acme#^6f1c2b7e-4a1d-4f0e-9c3b-2a7d5e8f9a10]].\n"
+
+	note.Assets = note.Assets[:1]
+	note.Responses = nil
+	updated := RenderProgram(edited, note)
+
+	assert.Contains(t, updated, "My triage plan for Acme.\n\n## Scope")
+	assert.Contains(t, updated, "## Notes\n\nTried the login redirect")
+	assert.Contains(t, updated, "- Assets: 1 (1 live)")
+	assert.NotContains(t, updated, "https://api.acme.example")
+	assert.Contains(t, updated, beginMarker(SectionResponses)+"\nNothing notable\n"+endMarker(SectionResponses))
+
+	// Exporting again without changes leaves the note as is
+	assert.Equal(t, updated, RenderProgram(updated, note))
+}
+
+func TestRenderProgram_RestoresMissingSections(t *testing.T) {
+	existing := "# Acme\n\nOnly my own notes here.\n"
+
+	updated := RenderProgram(existing, testNote())
+	require.True(t, strings.HasPrefix(updated, existing))
+	assert.Contains(t, updated, "## Assets\n\n"+beginMarker(SectionAssets))
+	assert.Contains(t, updated, "## Notable Responses\n\n"+beginMarker(SectionResponses))
+}
+
+func TestRenderIndex(t *testing.T) {
+	programs := []*database.Program{
+		{Name: "Zeta", Platform: "hackerone", ProgramURL: "https://hackerone.com/zeta"},
+		{Name: "Acme [EU]", Platform: "hackerone", ProgramURL: "https://hackerone.com/acme"},
+		{Name: "Beta", Platform: "bugcrowd", ProgramURL: "https://bugcrowd.com/beta"},
+	}
+
+	index := RenderIndex("", programs)
+	assert.Contains(t, index, "- [[bugcrowd/beta|Beta]] (bugcrowd)\n- [[hackerone/acme|Acme (EU)]] (hackerone)\n- [[hackerone/zeta|Zeta]] (hackerone)\n")
+
+	withNotes := index + "Focus on Acme this month.\n"
+	updated := RenderIndex(withNotes, programs[:1])
+	assert.Contains(t, updated, "Focus on Acme this month.")
+	assert.NotContains(t, updated, "bugcrowd/beta")
+}
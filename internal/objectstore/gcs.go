@@ -0,0 +1,324 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcsEndpoint     = "https://storage.googleapis.com"
+	gcsTokenURI     = "https://oauth2.googleapis.com/token"
+	gcsScope        = "https://www.googleapis.com/auth/devstorage.read_write"
+	gcsTokenGrant   = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	gcsTokenRefresh = time.Minute // tokens are refreshed this long before they expire
+)
+
+// GCSConfig holds the Google Cloud Storage bucket objects are stored in and
+// the service account used
+type GCSConfig struct {
+	Bucket          string
+	CredentialsFile string // service account key in JSON; requests are unauthenticated when empty, for emulators
+	Endpoint        string // e.g. http://localhost:4443 for fake-gcs-server; Google when empty
+	Timeout         time.Duration
+}
+
+// GCSClient stores objects in one Google Cloud Storage bucket through the
+// JSON API
+type GCSClient struct {
+	config     GCSConfig
+	endpoint   string
+	account    *serviceAccount
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// serviceAccount is the part of a service account key file used to sign
+// token requests
+type serviceAccount struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+
+	key *rsa.PrivateKey
+}
+
+// NewGCSClient creates a client for the configured bucket
+func NewGCSClient(config GCSConfig) (*GCSClient, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("GCS bucket is required")
+	}
+	if config.CredentialsFile == "" && config.Endpoint == "" {
+		return nil, fmt.Errorf("GCS credentials file is required")
+	}
+
+	endpoint := strings.TrimRight(config.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = gcsEndpoint
+	}
+	if u, err := url.Parse(endpoint); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid GCS endpoint %q", config.Endpoint)
+	}
+
+	var account *serviceAccount
+	if config.CredentialsFile != "" {
+		var err error
+		if account, err = loadServiceAccount(config.CredentialsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	return &GCSClient{
+		config:     config,
+		endpoint:   endpoint,
+		account:    account,
+		httpClient: &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}, nil
+}
+
+// loadServiceAccount reads a service account key file
+func loadServiceAccount(path string) (*serviceAccount, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GCS credentials: %w", err)
+	}
+	return parseServiceAccount(data)
+}
+
+// parseServiceAccount parses a service account key file and its private key
+func parseServiceAccount(data []byte) (*serviceAccount, error) {
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse GCS credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("GCS credentials must be a service account key with client_email and private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = gcsTokenURI
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("GCS credentials private_key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("GCS credentials private_key is not an RSA key")
+		}
+		account.key = rsaKey
+	} else if account.key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+		return nil, fmt.Errorf("failed to parse GCS credentials private_key: %w", err)
+	}
+
+	return &account, nil
+}
+
+// Bucket returns the name of the bucket objects are stored in
+func (c *GCSClient) Bucket() string {
+	return c.config.Bucket
+}
+
+// Ref returns the gs:// reference of the object under key
+func (c *GCSClient) Ref(key string) string {
+	return "gs://" + c.config.Bucket + "/" + strings.TrimLeft(key, "/")
+}
+
+// Put stores an object under key, replacing any object already there
+func (c *GCSClient) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	query := url.Values{"uploadType": {"media"}, "name": {strings.TrimLeft(key, "/")}}
+	uploadURL := c.endpoint + "/upload/storage/v1/b/" + url.PathEscape(c.config.Bucket) + "/o?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload %s: %w", key, gcsStatusError(resp))
+	}
+
+	return nil
+}
+
+// Get returns the object under key, or ErrNotFound
+func (c *GCSClient) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.objectURL(key)+"?alt=media", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("failed to download %s: %w", key, ErrNotFound)
+	default:
+		return nil, fmt.Errorf("failed to download %s: %w", key, gcsStatusError(resp))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	return body, nil
+}
+
+// Delete deletes the object under key; deleting a missing object succeeds
+func (c *GCSClient) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete %s: %w", key, gcsStatusError(resp))
+	}
+
+	return nil
+}
+
+// objectURL returns the JSON API URL of an object, with the key escaped as
+// one path segment
+func (c *GCSClient) objectURL(key string) string {
+	return c.endpoint + "/storage/v1/b/" + url.PathEscape(c.config.Bucket) + "/o/" + url.PathEscape(strings.TrimLeft(key, "/"))
+}
+
+// do sends a request with the service account's access token
+func (c *GCSClient) do(req *http.Request) (*http.Response, error) {
+	if c.account != nil {
+		token, err := c.accessToken(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// accessToken returns a cached access token, exchanging a signed assertion
+// for a new one when it is about to expire
+func (c *GCSClient) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.token != "" && now.Add(gcsTokenRefresh).Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	assertion, err := c.account.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{"grant_type": {gcsTokenGrant}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get GCS access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get GCS access token: %w", gcsStatusError(resp))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode GCS access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("GCS token endpoint returned no access token")
+	}
+
+	c.token = token.AccessToken
+	c.tokenExpiry = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// assertion returns a JWT signed with the service account's key, requesting
+// read-write access to storage for an hour
+func (a *serviceAccount) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": a.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   a.ClientEmail,
+		"scope": gcsScope,
+		"aud":   a.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GCS token request: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// gcsStatusError describes an unexpected response of the JSON API
+func gcsStatusError(resp *http.Response) error {
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("GCS returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+}
@@ -0,0 +1,159 @@
+package objectstore
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGCS serves the token endpoint and the JSON API calls the client makes
+type fakeGCS struct {
+	t       *testing.T
+	key     *rsa.PublicKey
+	mu      sync.Mutex
+	objects map[string]string
+	tokens  int
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		require.NoError(f.t, r.ParseForm())
+		assert.Equal(f.t, gcsTokenGrant, r.PostForm.Get("grant_type"))
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		require.Len(f.t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(f.t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(f.t, rsa.VerifyPKCS1v15(f.key, crypto.SHA256, digest[:], signature), "assertion is signed with the account key")
+
+		f.tokens++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token-1", "expires_in": 3600})
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer token-1" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/bodies/o":
+		assert.Equal(f.t, "media", r.URL.Query().Get("uploadType"))
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Query().Get("name")] = string(data)
+		_, _ = w.Write([]byte("{}"))
+	case strings.HasPrefix(r.URL.EscapedPath(), "/storage/v1/b/bodies/o/"):
+		name := strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bodies/o/")
+		assert.NotContains(f.t, strings.TrimPrefix(r.URL.EscapedPath(), "/storage/v1/b/bodies/o/"), "/", "the key is one path segment")
+		body, ok := f.objects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(f.objects, name)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		assert.Equal(f.t, "media", r.URL.Query().Get("alt"))
+		_, _ = w.Write([]byte(body))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// writeServiceAccount writes a service account key file whose token URI is
+// the fake server
+func writeServiceAccount(t *testing.T, key *rsa.PrivateKey, tokenURI string) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "monitor-agent@example.iam.gserviceaccount.com",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":      tokenURI,
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestGCSClient(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	fake := &fakeGCS{t: t, key: &key.PublicKey, objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewGCSClient(GCSConfig{
+		Bucket:          "bodies",
+		CredentialsFile: writeServiceAccount(t, key, server.URL+"/token"),
+		Endpoint:        server.URL,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, client.Put(ctx, "monitor-agent/ab/abcd", []byte("<html>"), "text/html"))
+	assert.Equal(t, map[string]string{"monitor-agent/ab/abcd": "<html>"}, fake.objects)
+
+	body, err := client.Get(ctx, "monitor-agent/ab/abcd")
+	require.NoError(t, err)
+	assert.Equal(t, "<html>", string(body))
+
+	require.NoError(t, client.Delete(ctx, "monitor-agent/ab/abcd"))
+	require.NoError(t, client.Delete(ctx, "monitor-agent/ab/abcd"), "deleting a missing object succeeds")
+	_, err = client.Get(ctx, "monitor-agent/ab/abcd")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, 1, fake.tokens, "the access token is cached")
+	assert.Equal(t, "gs://bodies/monitor-agent/ab/abcd", client.Ref("monitor-agent/ab/abcd"))
+}
+
+func TestNewGCSClient(t *testing.T) {
+	_, err := NewGCSClient(GCSConfig{CredentialsFile: "credentials.json"})
+	assert.Error(t, err, "bucket is required")
+
+	_, err = NewGCSClient(GCSConfig{Bucket: "bodies"})
+	assert.Error(t, err, "credentials are required outside an emulator")
+
+	_, err = NewGCSClient(GCSConfig{Bucket: "bodies", CredentialsFile: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+
+	client, err := NewGCSClient(GCSConfig{Bucket: "bodies", Endpoint: "http://localhost:4443"})
+	require.NoError(t, err, "emulators need no credentials")
+	assert.Equal(t, "http://localhost:4443/storage/v1/b/bodies/o/a%2Fb.html", client.objectURL("a/b.html"))
+}
+
+func TestParseServiceAccount(t *testing.T) {
+	_, err := parseServiceAccount([]byte(`{"type":"authorized_user"}`))
+	assert.Error(t, err, "user credentials are not supported")
+
+	_, err = parseServiceAccount([]byte(`{"client_email":"a@b","private_key":"not pem"}`))
+	assert.Error(t, err)
+}
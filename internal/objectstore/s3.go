@@ -0,0 +1,334 @@
+// Package objectstore stores files in S3, an S3-compatible object store or
+// Google Cloud Storage, and shares S3 objects through presigned URLs. S3
+// requests are signed with AWS Signature Version 4 and GCS requests use a
+// service account token, so no SDK is needed.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxPresignTTL is the longest a presigned URL can stay valid
+const MaxPresignTTL = 7 * 24 * time.Hour
+
+const (
+	service         = "s3"
+	algorithm       = "AWS4-HMAC-SHA256"
+	timeFormat      = "20060102T150405Z"
+	dateFormat      = "20060102"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// emptyPayload is the SHA-256 of an empty request body
+	emptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Config holds the bucket objects are stored in and the credentials used
+type Config struct {
+	Bucket          string
+	Region          string
+	Endpoint        string // S3-compatible endpoint, e.g. https://minio.internal:9000; AWS when empty
+	PathStyle       bool   // address the bucket in the path instead of the host name
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // set for temporary credentials
+	Timeout         time.Duration
+}
+
+// Client stores objects in one bucket
+type Client struct {
+	config     Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient creates a client for the configured bucket
+func NewClient(config Config) (*Client, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("object store bucket is required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("object store access key ID and secret access key are required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	rawEndpoint := config.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+	endpoint, err := url.Parse(strings.TrimRight(rawEndpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid object store endpoint %q", config.Endpoint)
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	return &Client{
+		config:     config,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}, nil
+}
+
+// Bucket returns the name of the bucket objects are stored in
+func (c *Client) Bucket() string {
+	return c.config.Bucket
+}
+
+// Put stores an object under key, replacing any object already there
+func (c *Client) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	objectURL := c.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", contentType)
+
+	payloadHash := sha256.Sum256(body)
+	c.signHeaders(req, hex.EncodeToString(payloadHash[:]))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("failed to upload %s: object store returned status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
+
+// Get returns the object under key, or ErrNotFound
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+	c.signHeaders(req, emptyPayload)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("failed to download %s: %w", key, ErrNotFound)
+	default:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to download %s: object store returned status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	return body, nil
+}
+
+// Delete deletes the object under key; deleting a missing object succeeds
+func (c *Client) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.objectURL(key).String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+	c.signHeaders(req, emptyPayload)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("failed to delete %s: object store returned status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
+
+// Ref returns the s3:// reference of the object under key
+func (c *Client) Ref(key string) string {
+	return "s3://" + c.config.Bucket + "/" + strings.TrimLeft(key, "/")
+}
+
+// PresignGet returns a URL anyone can download the object under key with
+// until ttl passes, without credentials of their own
+func (c *Client) PresignGet(key string, ttl time.Duration) (string, error) {
+	if ttl <= 0 || ttl > MaxPresignTTL {
+		return "", fmt.Errorf("presigned URLs must be valid for more than 0 and at most %s", MaxPresignTTL)
+	}
+
+	now := c.now().UTC()
+	objectURL := c.objectURL(key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", algorithm)
+	query.Set("X-Amz-Credential", c.config.AccessKeyID+"/"+c.scope(now))
+	query.Set("X-Amz-Date", now.Format(timeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if c.config.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", c.config.SessionToken)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		objectURL.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + objectURL.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", c.signature(now, canonicalRequest))
+	objectURL.RawQuery = canonicalQuery(query)
+	return objectURL.String(), nil
+}
+
+// objectURL returns the URL of an object, virtual-hosted unless path style
+// addressing is configured
+func (c *Client) objectURL(key string) *url.URL {
+	u := *c.endpoint
+	key = strings.TrimLeft(key, "/")
+	path, rawPath := "/"+key, "/"+escapePath(key)
+	if c.config.PathStyle {
+		path, rawPath = "/"+c.config.Bucket+path, "/"+escape(c.config.Bucket)+rawPath
+	} else {
+		u.Host = c.config.Bucket + "." + u.Host
+	}
+	u.Path, u.RawPath = c.endpoint.Path+path, c.endpoint.EscapedPath()+rawPath
+	return &u
+}
+
+// signHeaders signs a request in the Authorization header
+func (c *Client) signHeaders(req *http.Request, payloadHash string) {
+	now := c.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(timeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.config.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, c.config.AccessKeyID, c.scope(now), signedHeaders, c.signature(now, canonicalRequest)))
+}
+
+// scope is the credential scope of a request signed at t
+func (c *Client) scope(t time.Time) string {
+	return strings.Join([]string{t.Format(dateFormat), c.config.Region, service, "aws4_request"}, "/")
+}
+
+// signature signs a canonical request made at t
+func (c *Client) signature(t time.Time, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		algorithm,
+		t.Format(timeFormat),
+		c.scope(t),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.config.SecretAccessKey), t.Format(dateFormat))
+	key = hmacSHA256(key, c.config.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters sorted by name with SigV4 escaping
+func canonicalQuery(values url.Values) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		for _, value := range values[name] {
+			pairs = append(pairs, escape(name)+"="+escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escapePath escapes each segment of an object path, keeping the slashes
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escape percent-encodes everything but the unreserved characters, as SigV4 requires
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ('A' <= ch && ch <= 'Z') || ('a' <= ch && ch <= 'z') || ('0' <= ch && ch <= '9') ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
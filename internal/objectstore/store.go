@@ -0,0 +1,41 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("object not found")
+
+// Store stores objects in one bucket. Client stores them in S3 or an
+// S3-compatible store, GCSClient in Google Cloud Storage.
+type Store interface {
+	// Bucket returns the name of the bucket objects are stored in
+	Bucket() string
+	// Put stores an object under key, replacing any object already there
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Get returns the object under key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete deletes the object under key; deleting a missing object succeeds
+	Delete(ctx context.Context, key string) error
+	// Ref returns the reference recorded for the object under key, such as
+	// s3://bucket/key
+	Ref(key string) string
+}
+
+var (
+	_ Store = (*Client)(nil)
+	_ Store = (*GCSClient)(nil)
+)
+
+// KeyFromRef returns the key of an object reference made by a store's Ref,
+// or false when the reference points to another store or bucket
+func KeyFromRef(store Store, ref string) (string, bool) {
+	prefix := store.Ref("")
+	if !strings.HasPrefix(ref, prefix) || len(ref) == len(prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, prefix), true
+}
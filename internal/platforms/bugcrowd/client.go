@@ -10,12 +10,15 @@ import (
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/schemadrift"
 	"github.com/monitor-agent/internal/utils"
+	"github.com/monitor-agent/internal/version"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	baseURL = "https://api.bugcrowd.com"
+	defaultBaseURL = "https://api.bugcrowd.com"
 )
 
 // Client represents a BugCrowd API client
@@ -24,6 +27,7 @@ type Client struct {
 	config       *PlatformConfig
 	rateLimiter  *utils.RateLimiter
 	urlProcessor *utils.URLProcessor // Added URLProcessor field
+	baseURL      string
 }
 
 // NewBugCrowdClient creates a new BugCrowd client
@@ -33,12 +37,17 @@ func NewBugCrowdClient(config *PlatformConfig) *Client {
 	client.SetRetryCount(config.RetryAttempts)
 	client.SetRetryWaitTime(config.RetryDelay)
 	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+	config.Metrics.InstrumentPlatformClient(client, "bugcrowd")
+	rateLimiter := utils.NewRateLimiter(config.RateLimit, time.Minute)
+	rateLimiter.SetBurst(config.RateBurst)
+	rateLimiter.Instrument(client, metrics.EndpointLabel)
+	config.Metrics.InstrumentRateLimiter("bugcrowd", rateLimiter)
 
 	// Set default headers
 	client.SetHeaders(map[string]string{
 		"Accept":       "application/vnd.bugcrowd+json",
 		"Content-Type": "application/json",
-		"User-Agent":   "Monitor-Agent/1.0",
+		"User-Agent":   version.UserAgent(),
 	})
 
 	// Add authentication
@@ -46,11 +55,17 @@ func NewBugCrowdClient(config *PlatformConfig) *Client {
 		client.SetHeader("Authorization", fmt.Sprintf("Token %s", config.APIKey))
 	}
 
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
 	return &Client{
 		httpClient:   client,
 		config:       config,
-		rateLimiter:  utils.NewRateLimiter(config.RateLimit, time.Minute),
+		rateLimiter:  rateLimiter,
 		urlProcessor: utils.NewURLProcessor(), // Initialize URLProcessor
+		baseURL:      baseURL,
 	}
 }
 
@@ -61,16 +76,22 @@ func (c *Client) GetName() string {
 
 // IsHealthy checks if the BugCrowd API is healthy
 func (c *Client) IsHealthy(ctx context.Context) error {
-	c.rateLimiter.Wait()
-
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
-		Get(fmt.Sprintf("%s/programs", baseURL))
+		Get(fmt.Sprintf("%s/programs", c.baseURL))
 
 	if err != nil {
 		return fmt.Errorf("failed to check BugCrowd API health: %w", err)
 	}
 
+	if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), resp.Body()); merr != nil {
+		return merr
+	}
+
+	if cerr := utils.DetectCredentialFailure(c.GetName(), resp.StatusCode(), resp.Header()); cerr != nil {
+		return cerr
+	}
+
 	if resp.StatusCode() != http.StatusOK {
 		return fmt.Errorf("BugCrowd API returned status %d", resp.StatusCode())
 	}
@@ -85,8 +106,6 @@ func (c *Client) GetPublicPrograms(ctx context.Context) ([]*Program, error) {
 	pageSize := 100
 
 	for {
-		c.rateLimiter.Wait()
-
 		programs, hasMore, err := c.getProgramsPage(ctx, page, pageSize)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get programs page %d: %w", page, err)
@@ -113,12 +132,20 @@ func (c *Client) getProgramsPage(ctx context.Context, page, pageSize int) ([]*Pr
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
-		Get(fmt.Sprintf("%s/programs?%s", baseURL, params.Encode()))
+		Get(fmt.Sprintf("%s/programs?%s", c.baseURL, params.Encode()))
 
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to make request: %w", err)
 	}
 
+	if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), resp.Body()); merr != nil {
+		return nil, false, merr
+	}
+
+	if cerr := utils.DetectCredentialFailure(c.GetName(), resp.StatusCode(), resp.Header()); cerr != nil {
+		return nil, false, cerr
+	}
+
 	if resp.StatusCode() != http.StatusOK {
 		var errorResp BugCrowdError
 		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil {
@@ -131,12 +158,14 @@ func (c *Client) getProgramsPage(ctx context.Context, page, pageSize int) ([]*Pr
 	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
 		return nil, false, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	schemadrift.Check(c.GetName(), "programs", resp.Body(), apiResp)
 
 	var programs []*Program
 	for _, program := range apiResp.Programs {
 		// Only include public programs
 		if program.Status == "public" {
 			platformProgram := &Program{
+				PlatformID:  program.UUID,
 				Name:        program.Name,
 				Platform:    "bugcrowd",
 				URL:         program.URL,
@@ -154,7 +183,11 @@ func (c *Client) getProgramsPage(ctx context.Context, page, pageSize int) ([]*Pr
 	return programs, hasMore, nil
 }
 
-// GetProgramScope retrieves the in-scope assets for a specific program
+// GetProgramScope retrieves the scope assets of a specific program. Eligible
+// targets are in scope; ineligible ones are returned with
+// EligibleForSubmission unset so they can be used for out-of-scope filtering.
+// Programs that organize their scope in target groups list no targets of
+// their own, so their groups are fetched instead.
 func (c *Client) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
 	// Extract program code from URL
 	code, err := c.extractCodeFromURL(programURL)
@@ -162,49 +195,86 @@ func (c *Client) GetProgramScope(ctx context.Context, programURL string) ([]*Sco
 		return nil, fmt.Errorf("failed to extract code from URL: %w", err)
 	}
 
-	c.rateLimiter.Wait()
+	var scopeResp ScopeResponse
+	if _, err := c.getScope(ctx, code, "targets", &scopeResp); err != nil {
+		return nil, err
+	}
+
+	var scopeAssets []*ScopeAsset
+	for _, target := range scopeResp.Targets {
+		if asset := c.parseScopeAsset(target, target.Eligible && !target.Ineligible); asset != nil {
+			scopeAssets = append(scopeAssets, asset)
+		}
+	}
+
+	if len(scopeResp.Targets) == 0 {
+		var groupsResp TargetGroupsResponse
+		found, err := c.getScope(ctx, code, "target_groups", &groupsResp)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			for _, group := range groupsResp.Groups {
+				for _, target := range group.Targets {
+					// A target of an out-of-scope group is out of scope whatever its own flags say
+					eligible := group.InScope && target.Eligible && !target.Ineligible
+					if asset := c.parseScopeAsset(target, eligible); asset != nil {
+						scopeAssets = append(scopeAssets, asset)
+					}
+				}
+			}
+			logrus.Debugf("Program %s organizes its scope in %d target groups", code, len(groupsResp.Groups))
+		}
+	}
+
+	logrus.Infof("Retrieved %d scope assets for program %s", len(scopeAssets), code)
+	return scopeAssets, nil
+}
 
+// getScope fetches a scope endpoint of a program into out. It reports false
+// when the program does not have the endpoint.
+func (c *Client) getScope(ctx context.Context, code, endpoint string, out any) (bool, error) {
 	params := url.Values{}
 	params.Set("per_page", "100")
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
-		Get(fmt.Sprintf("%s/programs/%s/targets?%s", baseURL, code, params.Encode()))
+		Get(fmt.Sprintf("%s/programs/%s/%s?%s", c.baseURL, code, endpoint, params.Encode()))
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return false, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), resp.Body()); merr != nil {
+		return false, merr
+	}
+
+	if cerr := utils.DetectCredentialFailure(c.GetName(), resp.StatusCode(), resp.Header()); cerr != nil {
+		return false, cerr
+	}
+
+	if resp.StatusCode() == http.StatusNotFound && endpoint == "target_groups" {
+		return false, nil
 	}
 
 	if resp.StatusCode() != http.StatusOK {
 		var errorResp BugCrowdError
 		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil {
-			return nil, fmt.Errorf("BugCrowd API error: %s", errorResp.Message)
+			return false, fmt.Errorf("BugCrowd API error: %s", errorResp.Message)
 		}
-		return nil, fmt.Errorf("BugCrowd API returned status %d", resp.StatusCode())
+		return false, fmt.Errorf("BugCrowd API returned status %d", resp.StatusCode())
 	}
 
-	var scopeResp ScopeResponse
-	if err := json.Unmarshal(resp.Body(), &scopeResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := json.Unmarshal(resp.Body(), out); err != nil {
+		return false, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	schemadrift.Check(c.GetName(), endpoint, resp.Body(), out)
 
-	var scopeAssets []*ScopeAsset
-	for _, target := range scopeResp.Targets {
-		// Only include eligible targets
-		if target.Eligible && !target.Ineligible {
-			asset := c.parseScopeAsset(target)
-			if asset != nil {
-				scopeAssets = append(scopeAssets, asset)
-			}
-		}
-	}
-
-	logrus.Infof("Retrieved %d scope assets for program %s", len(scopeAssets), code)
-	return scopeAssets, nil
+	return true, nil
 }
 
 // parseScopeAsset parses a scope target into a ScopeAsset
-func (c *Client) parseScopeAsset(target BugCrowdScope) *ScopeAsset {
+func (c *Client) parseScopeAsset(target BugCrowdScope, eligible bool) *ScopeAsset {
 	targetStr := strings.TrimSpace(target.Target)
 	if targetStr == "" {
 		return nil
@@ -225,9 +295,10 @@ func (c *Client) parseScopeAsset(target BugCrowdScope) *ScopeAsset {
 	switch target.Type {
 	case "website":
 		return &ScopeAsset{
-			URL:    normalizedURL,
-			Domain: c.extractDomain(normalizedURL),
-			Type:   "url",
+			URL:                   normalizedURL,
+			Domain:                c.extractDomain(normalizedURL),
+			Type:                  "url",
+			EligibleForSubmission: eligible,
 		}
 	case "wildcard":
 		// Convert wildcard to base domain for ChaosDB discovery
@@ -242,21 +313,25 @@ func (c *Client) parseScopeAsset(target BugCrowdScope) *ScopeAsset {
 			}
 		}
 		return &ScopeAsset{
-			URL:    normalizedDomain,
-			Domain: domain,
-			Type:   "wildcard",
+			URL:                   normalizedDomain,
+			Domain:                domain,
+			Type:                  "wildcard",
+			EligibleForSubmission: eligible,
+			OriginalPattern:       targetStr, // Store original wildcard pattern
 		}
 	case "ip":
 		return &ScopeAsset{
-			URL:    targetStr,
-			Domain: targetStr,
-			Type:   "ip",
+			URL:                   targetStr,
+			Domain:                targetStr,
+			Type:                  "ip",
+			EligibleForSubmission: eligible,
 		}
 	default:
 		return &ScopeAsset{
-			URL:    normalizedURL,
-			Domain: c.extractDomain(normalizedURL),
-			Type:   target.Type,
+			URL:                   normalizedURL,
+			Domain:                c.extractDomain(normalizedURL),
+			Type:                  target.Type,
+			EligibleForSubmission: eligible,
 		}
 	}
 }
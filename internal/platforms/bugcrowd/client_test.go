@@ -57,7 +57,7 @@ func TestClient_parseScopeAsset_URLNormalization(t *testing.T) {
 				Target: tt.target,
 			}
 
-			asset := client.parseScopeAsset(target)
+			asset := client.parseScopeAsset(target, true)
 			assert.NotNil(t, asset)
 			assert.Equal(t, tt.expectedURL, asset.URL)
 		})
@@ -0,0 +1,111 @@
+package bugcrowd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/schemadrift"
+	"github.com/monitor-agent/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGoldenClient serves testdata payloads for the program list and the
+// targets and target groups of the acme program; nil payloads are not found
+func newGoldenClient(t *testing.T, targets, groups []byte) *Client {
+	t.Helper()
+
+	programs := testutil.ReadTestdata(t, "programs.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/programs":
+			_, _ = w.Write(programs)
+		case "/programs/acme/targets":
+			_, _ = w.Write(targets)
+		case "/programs/acme/target_groups":
+			if groups == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(groups)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewBugCrowdClient(&PlatformConfig{BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+}
+
+func TestClient_GetPublicPrograms_Golden(t *testing.T) {
+	client := newGoldenClient(t, nil, nil)
+
+	programs, err := client.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "programs", programs)
+}
+
+func TestClient_GetProgramScope_Golden(t *testing.T) {
+	client := newGoldenClient(t, testutil.ReadTestdata(t, "targets.json"), nil)
+
+	assets, err := client.GetProgramScope(context.Background(), "https://bugcrowd.com/acme")
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "targets", assets)
+}
+
+func TestClient_GetProgramScope_TargetGroups(t *testing.T) {
+	client := newGoldenClient(t, []byte(`{"targets":[],"meta":{"page_count":1}}`), testutil.ReadTestdata(t, "target_groups.json"))
+
+	assets, err := client.GetProgramScope(context.Background(), "https://bugcrowd.com/acme")
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "target_groups", assets)
+}
+
+func TestClient_GetProgramScope_NoTargetGroups(t *testing.T) {
+	client := newGoldenClient(t, []byte(`{"targets":[],"meta":{"page_count":1}}`), nil)
+
+	assets, err := client.GetProgramScope(context.Background(), "https://bugcrowd.com/acme")
+	require.NoError(t, err)
+	assert.Empty(t, assets)
+}
+
+func TestClient_GetProgramScope_HugeScope(t *testing.T) {
+	const size = 5000
+
+	targets := make([]string, size)
+	for i := range targets {
+		targetType, target := "website", fmt.Sprintf("host-%d.acme.example", i)
+		if i%2 == 1 {
+			targetType, target = "wildcard", fmt.Sprintf("*.zone-%d.acme.example", i)
+		}
+		targets[i] = fmt.Sprintf(`{"uuid":"%d","target":%q,"type":%q,"eligible":true}`, i, target, targetType)
+	}
+	client := newGoldenClient(t, []byte(`{"targets":[`+strings.Join(targets, ",")+`],"meta":{"page_count":1}}`), nil)
+
+	assets, err := client.GetProgramScope(context.Background(), "https://bugcrowd.com/acme")
+	require.NoError(t, err)
+	require.Len(t, assets, size)
+
+	assert.Equal(t, "https://host-0.acme.example", assets[0].URL)
+	assert.Equal(t, "zone-4999.acme.example", assets[size-1].Domain)
+	assert.Equal(t, "wildcard", assets[size-1].Type)
+}
+
+func TestPayloads_NoSchemaDrift(t *testing.T) {
+	payloads := map[string]any{
+		"programs.json":      BugCrowdResponse{},
+		"targets.json":       ScopeResponse{},
+		"target_groups.json": TargetGroupsResponse{},
+	}
+
+	for name, expected := range payloads {
+		drifts, err := schemadrift.Compare(testutil.ReadTestdata(t, name), expected)
+		require.NoError(t, err, name)
+		assert.Empty(t, drifts, name)
+	}
+}
@@ -19,15 +19,16 @@ type BugCrowdProgram struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// BugCrowdScope represents the scope of a BugCrowd program
+// BugCrowdScope represents the scope of a BugCrowd program. Targets are not
+// always sent with timestamps.
 type BugCrowdScope struct {
 	UUID       string    `json:"uuid"`
 	Target     string    `json:"target"`
 	Type       string    `json:"type"`
 	Eligible   bool      `json:"eligible"`
 	Ineligible bool      `json:"ineligible"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
 }
 
 // BugCrowdResponse represents a generic BugCrowd API response
@@ -50,6 +51,21 @@ type ScopeResponse struct {
 	Meta    ResponseMeta    `json:"meta"`
 }
 
+// TargetGroupsResponse represents a BugCrowd target groups API response
+type TargetGroupsResponse struct {
+	Groups []BugCrowdTargetGroup `json:"groups"`
+	Meta   ResponseMeta          `json:"meta"`
+}
+
+// BugCrowdTargetGroup is a named group of targets that is in or out of scope
+// as a whole
+type BugCrowdTargetGroup struct {
+	UUID    string          `json:"uuid"`
+	Name    string          `json:"name"`
+	InScope bool            `json:"in_scope"`
+	Targets []BugCrowdScope `json:"targets"`
+}
+
 // BugCrowdError represents a BugCrowd API error
 type BugCrowdError struct {
 	Error   string `json:"error"`
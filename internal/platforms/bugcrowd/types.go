@@ -3,6 +3,8 @@ package bugcrowd
 import (
 	"context"
 	"time"
+
+	"github.com/monitor-agent/internal/metrics"
 )
 
 // Platform represents a bug bounty platform
@@ -22,6 +24,7 @@ type Platform interface {
 
 // Program represents a bug bounty program
 type Program struct {
+	PlatformID  string    `json:"platform_id"` // stable platform-side ID that survives handle renames
 	Name        string    `json:"name"`
 	Platform    string    `json:"platform"`
 	URL         string    `json:"url"`
@@ -44,7 +47,10 @@ type ScopeAsset struct {
 type PlatformConfig struct {
 	APIKey        string
 	RateLimit     int
+	RateBurst     int // most requests sent in a burst; 0 uses RateLimit
 	Timeout       time.Duration
 	RetryAttempts int
 	RetryDelay    time.Duration
+	BaseURL       string           // overrides the default API URL
+	Metrics       *metrics.Metrics // records the requests sent; nil disables it
 }
@@ -10,12 +10,21 @@ import (
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/schemadrift"
 	"github.com/monitor-agent/internal/utils"
+	"github.com/monitor-agent/internal/version"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	baseURL = "https://api.hackerone.com/v1"
+	defaultBaseURL = "https://api.hackerone.com/v1"
+)
+
+// Program states of the programs API; private programs are soft launched
+const (
+	statePublic  = "public_mode"
+	statePrivate = "soft_launched"
 )
 
 // Client represents a HackerOne API client
@@ -24,6 +33,7 @@ type Client struct {
 	config       *PlatformConfig
 	rateLimiter  *utils.RateLimiter
 	urlProcessor *utils.URLProcessor // Added URLProcessor field
+	baseURL      string
 }
 
 // NewHackerOneClient creates a new HackerOne client
@@ -33,12 +43,17 @@ func NewHackerOneClient(config *PlatformConfig) *Client {
 	client.SetRetryCount(config.RetryAttempts)
 	client.SetRetryWaitTime(config.RetryDelay)
 	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+	config.Metrics.InstrumentPlatformClient(client, "hackerone")
+	rateLimiter := utils.NewRateLimiter(config.RateLimit, time.Minute)
+	rateLimiter.SetBurst(config.RateBurst)
+	rateLimiter.Instrument(client, metrics.EndpointLabel)
+	config.Metrics.InstrumentRateLimiter("hackerone", rateLimiter)
 
 	// Set default headers
 	client.SetHeaders(map[string]string{
 		"Accept":       "application/json",
 		"Content-Type": "application/json",
-		"User-Agent":   "Monitor-Agent/1.0",
+		"User-Agent":   version.UserAgent(),
 	})
 
 	// Add authentication
@@ -50,11 +65,17 @@ func NewHackerOneClient(config *PlatformConfig) *Client {
 		client.SetBasicAuth("", config.APIKey)
 	}
 
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
 	return &Client{
 		httpClient:   client,
 		config:       config,
-		rateLimiter:  utils.NewRateLimiter(config.RateLimit, time.Minute),
+		rateLimiter:  rateLimiter,
 		urlProcessor: utils.NewURLProcessor(), // Initialize URLProcessor
+		baseURL:      baseURL,
 	}
 }
 
@@ -65,16 +86,22 @@ func (c *Client) GetName() string {
 
 // IsHealthy checks if the HackerOne API is healthy
 func (c *Client) IsHealthy(ctx context.Context) error {
-	c.rateLimiter.Wait()
-
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
-		Get(fmt.Sprintf("%s/hackers/programs", baseURL))
+		Get(fmt.Sprintf("%s/hackers/programs", c.baseURL))
 
 	if err != nil {
 		return fmt.Errorf("failed to check HackerOne API health: %w", err)
 	}
 
+	if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), resp.Body()); merr != nil {
+		return merr
+	}
+
+	if cerr := utils.DetectCredentialFailure(c.GetName(), resp.StatusCode(), resp.Header()); cerr != nil {
+		return cerr
+	}
+
 	if resp.StatusCode() != http.StatusOK {
 		return fmt.Errorf("HackerOne API returned status %d", resp.StatusCode())
 	}
@@ -82,15 +109,14 @@ func (c *Client) IsHealthy(ctx context.Context) error {
 	return nil
 }
 
-// GetPublicPrograms retrieves all public bug bounty programs from HackerOne
+// GetPublicPrograms retrieves all public bug bounty programs from HackerOne,
+// and the private ones the account was invited to when IncludePrivate is set
 func (c *Client) GetPublicPrograms(ctx context.Context) ([]*Program, error) {
 	var allPrograms []*Program
 	page := 1
 	pageSize := 100
 
 	for {
-		c.rateLimiter.Wait()
-
 		programs, hasMore, err := c.getProgramsPage(ctx, page, pageSize)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get programs page %d: %w", page, err)
@@ -116,15 +142,23 @@ func (c *Client) getProgramsPage(ctx context.Context, page, pageSize int) ([]*Pr
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
-		Get(fmt.Sprintf("%s/hackers/programs?%s", baseURL, params.Encode()))
+		Get(fmt.Sprintf("%s/hackers/programs?%s", c.baseURL, params.Encode()))
 
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to make request: %w", err)
 	}
 
+	if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), resp.Body()); merr != nil {
+		return nil, false, merr
+	}
+
+	if cerr := utils.DetectCredentialFailure(c.GetName(), resp.StatusCode(), resp.Header()); cerr != nil {
+		return nil, false, cerr
+	}
+
 	if resp.StatusCode() != http.StatusOK {
 		var errorResp ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil {
+		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil && len(errorResp.Errors) > 0 {
 			return nil, false, fmt.Errorf("HackerOne API error: %s", errorResp.Errors[0].Detail)
 		}
 		return nil, false, fmt.Errorf("HackerOne API returned status %d", resp.StatusCode())
@@ -134,20 +168,24 @@ func (c *Client) getProgramsPage(ctx context.Context, page, pageSize int) ([]*Pr
 	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
 		return nil, false, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	schemadrift.Check(c.GetName(), "programs", resp.Body(), apiResp)
 
 	var programs []*Program
 	for _, program := range apiResp.Data {
-		// Include programs that are in public mode and offer bounties
-		if program.Attributes.State == "public_mode" && program.Attributes.OffersBounties {
+		// Include programs that offer bounties and are public, or private when enabled
+		visibility, ok := c.programVisibility(program.Attributes.State)
+		if ok && program.Attributes.OffersBounties {
 			// Construct the program URL using the handle
 			programURL := fmt.Sprintf("https://hackerone.com/%s", program.Attributes.Handle)
 
 			platformProgram := &Program{
+				PlatformID:  program.ID,
 				Name:        program.Attributes.Name,
 				Platform:    "hackerone",
 				URL:         program.Attributes.Website,
 				ProgramURL:  programURL,
 				IsActive:    true,
+				Visibility:  visibility,
 				LastUpdated: program.Attributes.UpdatedAt,
 			}
 			programs = append(programs, platformProgram)
@@ -160,55 +198,29 @@ func (c *Client) getProgramsPage(ctx context.Context, page, pageSize int) ([]*Pr
 	return programs, hasMore, nil
 }
 
-// GetProgramScope retrieves the in-scope assets for a specific program
-func (c *Client) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
-	// Extract program handle from URL
-	handle, err := c.extractHandleFromURL(programURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract handle from URL: %w", err)
-	}
-
-	logrus.Debugf("Extracted handle '%s' from URL '%s'", handle, programURL)
-
-	c.rateLimiter.Wait()
-
-	params := url.Values{}
-	params.Set("page[size]", "100")
-
-	scopeURL := fmt.Sprintf("%s/hackers/programs/%s/structured_scopes?%s", baseURL, handle, params.Encode())
-	logrus.Debugf("Making scope request to: %s", scopeURL)
-
-	resp, err := c.httpClient.R().
-		SetContext(ctx).
-		Get(scopeURL)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-
-	if resp.StatusCode() != http.StatusOK {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil {
-			return nil, fmt.Errorf("HackerOne API error: %s", errorResp.Errors[0].Detail)
-		}
-		return nil, fmt.Errorf("HackerOne API returned status %d", resp.StatusCode())
-	}
-
-	var scopeResp ScopeResponse
-	if err := json.Unmarshal(resp.Body(), &scopeResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+// programVisibility returns the visibility of a program in the given state
+// and whether programs in that state are listed
+func (c *Client) programVisibility(state string) (string, bool) {
+	switch state {
+	case statePublic:
+		return "public", true
+	case statePrivate:
+		return "private", c.config.IncludePrivate
+	default:
+		return "", false
 	}
+}
 
+// GetProgramScope retrieves the in-scope assets for a specific program
+func (c *Client) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
 	var scopeAssets []*ScopeAsset
-	for _, scope := range scopeResp.Data {
-		// Include both in-scope and out-of-scope assets
-		asset := c.parseScopeAsset(scope.Attributes)
-		if asset != nil {
-			scopeAssets = append(scopeAssets, asset)
-		}
+	err := c.StreamProgramScope(ctx, programURL, scopePageSize, func(chunk []*ScopeAsset) error {
+		scopeAssets = append(scopeAssets, chunk...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	logrus.Infof("Retrieved %d scope assets for program %s", len(scopeAssets), handle)
 	return scopeAssets, nil
 }
 
@@ -259,7 +271,7 @@ func (c *Client) parseScopeAsset(attr ScopeAttributes) *ScopeAsset {
 			OriginalPattern:       assetIdentifier, // Store original wildcard pattern
 		}
 	case "CIDR":
-		// Handle CIDR ranges (for future implementation)
+		// CIDR ranges are matched against the addresses hosts resolve to
 		return &ScopeAsset{
 			URL:                   assetIdentifier,
 			Domain:                assetIdentifier,
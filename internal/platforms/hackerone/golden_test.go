@@ -0,0 +1,263 @@
+package hackerone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/schemadrift"
+	"github.com/monitor-agent/internal/testutil"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGoldenClient serves testdata payloads for the program list and scope of
+// the acme program
+func newGoldenClient(t *testing.T, scope []byte) *Client {
+	t.Helper()
+
+	programs := testutil.ReadTestdata(t, "programs.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hackers/programs":
+			_, _ = w.Write(programs)
+		case "/hackers/programs/acme/structured_scopes":
+			_, _ = w.Write(scope)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewHackerOneClient(&PlatformConfig{BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+}
+
+func TestClient_GetPublicPrograms_Golden(t *testing.T) {
+	client := newGoldenClient(t, nil)
+
+	programs, err := client.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "programs", programs)
+}
+
+func TestClient_GetPublicPrograms_IncludePrivate(t *testing.T) {
+	client := newGoldenClient(t, nil)
+	client.config.IncludePrivate = true
+
+	programs, err := client.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	require.Len(t, programs, 5)
+
+	visibility := make(map[string]string, len(programs))
+	for _, program := range programs {
+		visibility[program.ProgramURL] = program.Visibility
+	}
+	assert.Equal(t, "private", visibility["https://hackerone.com/quiet-launch"])
+	assert.Equal(t, "public", visibility["https://hackerone.com/acme"])
+}
+
+func TestClient_GetProgramScope_Golden(t *testing.T) {
+	client := newGoldenClient(t, testutil.ReadTestdata(t, "structured_scopes.json"))
+
+	assets, err := client.GetProgramScope(context.Background(), "https://hackerone.com/acme")
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "structured_scopes", assets)
+}
+
+func TestClient_GetProgramScope_HugeScope(t *testing.T) {
+	const size = 5000
+
+	entries := make([]string, size)
+	for i := range entries {
+		assetType, identifier := "URL", fmt.Sprintf("host-%d.acme.example", i)
+		if i%2 == 1 {
+			assetType, identifier = "WILDCARD", fmt.Sprintf("*.zone-%d.acme.example", i)
+		}
+		entries[i] = fmt.Sprintf(`{"id":"%d","type":"structured-scope","attributes":{"asset_type":%q,"asset_identifier":%q,"eligible_for_submission":true}}`,
+			i, assetType, identifier)
+	}
+	client := newGoldenClient(t, []byte(`{"data":[`+strings.Join(entries, ",")+`],"links":{}}`))
+
+	assets, err := client.GetProgramScope(context.Background(), "https://hackerone.com/acme")
+	require.NoError(t, err)
+	require.Len(t, assets, size)
+
+	assert.Equal(t, "https://host-0.acme.example", assets[0].URL)
+	assert.Equal(t, "zone-4999.acme.example", assets[size-1].Domain)
+	assert.Equal(t, "*.zone-4999.acme.example", assets[size-1].OriginalPattern)
+}
+
+func TestClient_StreamProgramScope_Paginated(t *testing.T) {
+	const pages = 3
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page[number]")
+		requested = append(requested, page)
+
+		number := 0
+		_, _ = fmt.Sscanf(page, "%d", &number)
+		entries := make([]string, scopePageSize)
+		for i := range entries {
+			entries[i] = fmt.Sprintf(`{"id":"%d","type":"structured-scope","attributes":{"asset_type":"URL","asset_identifier":"host-%d-%d.acme.example","eligible_for_submission":true}}`,
+				i, number, i)
+		}
+		links := `{}`
+		if number < pages {
+			links = fmt.Sprintf(`{"next":"%s?page[number]=%d"}`, r.URL.Path, number+1)
+		}
+		_, _ = fmt.Fprintf(w, `{"data":[%s],"links":%s}`, strings.Join(entries, ","), links)
+	}))
+	t.Cleanup(server.Close)
+	client := NewHackerOneClient(&PlatformConfig{BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+
+	var chunkSizes []int
+	var last *ScopeAsset
+	err := client.StreamProgramScope(context.Background(), "https://hackerone.com/acme", 128, func(chunk []*ScopeAsset) error {
+		chunkSizes = append(chunkSizes, len(chunk))
+		last = chunk[len(chunk)-1]
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1", "2", "3"}, requested)
+	assert.Equal(t, []int{128, 128, 44}, chunkSizes)
+	assert.Equal(t, "https://host-3-99.acme.example", last.URL)
+}
+
+// largeScopePage builds a page of a large structured scope from the sanitized
+// sample scope, repeated: every copy after the first is moved to its own
+// acme-<copy>.example domain so its entries stay distinct
+func largeScopePage(sample []json.RawMessage, page, pages int) []byte {
+	entries := make([]string, scopePageSize)
+	for i := range entries {
+		index := (page-1)*scopePageSize + i
+		entry := string(sample[index%len(sample)])
+		if round := index / len(sample); round > 0 {
+			entry = strings.ReplaceAll(entry, "acme.example", fmt.Sprintf("acme-%d.example", round))
+		}
+		entries[i] = entry
+	}
+
+	links := `{}`
+	if page < pages {
+		links = fmt.Sprintf(`{"next":"/hackers/programs/acme/structured_scopes?page[number]=%d"}`, page+1)
+	}
+	return []byte(fmt.Sprintf(`{"data":[%s],"links":%s}`, strings.Join(entries, ","), links))
+}
+
+func TestClient_StreamProgramScope_LargeScope(t *testing.T) {
+	const pages, chunkSize = 30, 64
+
+	var sample struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(testutil.ReadTestdata(t, "structured_scopes.json"), &sample))
+
+	var requested atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested.Add(1)
+		page := 0
+		_, _ = fmt.Sscanf(r.URL.Query().Get("page[number]"), "%d", &page)
+		_, _ = w.Write(largeScopePage(sample.Data, page, pages))
+	}))
+	t.Cleanup(server.Close)
+	client := NewHackerOneClient(&PlatformConfig{BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+
+	var streamed []*ScopeAsset
+	var chunkSizes []int
+	pagesBeforeFirstChunk := int32(0)
+	err := client.StreamProgramScope(context.Background(), "https://hackerone.com/acme", chunkSize, func(chunk []*ScopeAsset) error {
+		if pagesBeforeFirstChunk == 0 {
+			pagesBeforeFirstChunk = requested.Load()
+		}
+		chunkSizes = append(chunkSizes, len(chunk))
+		streamed = append(streamed, chunk...)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Chunks are handed over while later pages are still to be fetched
+	assert.Equal(t, int32(pages), requested.Load())
+	assert.Equal(t, int32(1), pagesBeforeFirstChunk)
+	for i, size := range chunkSizes[:len(chunkSizes)-1] {
+		assert.Equal(t, chunkSize, size, "chunk %d", i)
+	}
+	assert.LessOrEqual(t, chunkSizes[len(chunkSizes)-1], chunkSize)
+
+	// Streaming parses the same assets as decoding every page whole
+	var expected []*ScopeAsset
+	for page := 1; page <= pages; page++ {
+		var response ScopeResponse
+		require.NoError(t, json.Unmarshal(largeScopePage(sample.Data, page, pages), &response))
+		for _, scope := range response.Data {
+			if asset := client.parseScopeAsset(scope.Attributes); asset != nil {
+				expected = append(expected, asset)
+			}
+		}
+	}
+	require.Len(t, streamed, len(expected))
+	assert.Equal(t, expected, streamed)
+
+	// The first copy of the sample parses like the sample itself
+	sampleAssets := 0
+	for _, entry := range sample.Data {
+		var scope HackerOneScope
+		require.NoError(t, json.Unmarshal(entry, &scope))
+		if client.parseScopeAsset(scope.Attributes) != nil {
+			sampleAssets++
+		}
+	}
+	testutil.AssertGolden(t, "structured_scopes", streamed[:sampleAssets])
+}
+
+func TestClient_StreamProgramScope_StopsOnError(t *testing.T) {
+	client := newGoldenClient(t, testutil.ReadTestdata(t, "structured_scopes.json"))
+
+	chunks := 0
+	stop := fmt.Errorf("disk full")
+	err := client.StreamProgramScope(context.Background(), "https://hackerone.com/acme", 5, func(chunk []*ScopeAsset) error {
+		chunks++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, chunks)
+}
+
+func TestClient_StreamProgramScope_MaintenancePage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("<html><title>Down for maintenance</title></html>"))
+	}))
+	t.Cleanup(server.Close)
+	client := NewHackerOneClient(&PlatformConfig{BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+
+	err := client.StreamProgramScope(context.Background(), "https://hackerone.com/acme", 10, func([]*ScopeAsset) error {
+		t.Fatal("no chunk expected")
+		return nil
+	})
+	merr, ok := utils.AsMaintenanceError(err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, "Down for maintenance", merr.Reason)
+}
+
+func TestPayloads_NoSchemaDrift(t *testing.T) {
+	payloads := map[string]any{
+		"programs.json":          HackerOneResponse{},
+		"structured_scopes.json": ScopeResponse{},
+	}
+
+	for name, expected := range payloads {
+		drifts, err := schemadrift.Compare(testutil.ReadTestdata(t, name), expected)
+		require.NoError(t, err, name)
+		assert.Empty(t, drifts, name)
+	}
+}
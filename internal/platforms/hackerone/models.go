@@ -9,24 +9,38 @@ type HackerOneProgram struct {
 	ID         string            `json:"id"`
 	Type       string            `json:"type"`
 	Attributes ProgramAttributes `json:"attributes"`
-	Links      ProgramLinks      `json:"links"`
+	Links      ProgramLinks      `json:"links,omitempty"`
 }
 
-// ProgramAttributes contains program details
+// ProgramAttributes contains program details. Fields with omitempty are not
+// sent for every program; the others are expected by schema drift detection.
 type ProgramAttributes struct {
-	Name                         string    `json:"name"`
-	Handle                       string    `json:"handle"`
-	URL                          string    `json:"url"`
-	Website                      string    `json:"website"`
-	SubmissionState              string    `json:"submission_state"`
-	State                        string    `json:"state"`
-	OffersBounties               bool      `json:"offers_bounties"`
-	OffersSwag                   bool      `json:"offers_swag"`
-	AllowsDisclosure             bool      `json:"allows_disclosure"`
-	AllowsPrivateDisclosure      bool      `json:"allows_private_disclosure"`
-	ResponseEfficiencyPercentage int       `json:"response_efficiency_percentage"`
-	CreatedAt                    time.Time `json:"created_at"`
-	UpdatedAt                    time.Time `json:"updated_at"`
+	Name                            string     `json:"name"`
+	Handle                          string     `json:"handle"`
+	URL                             string     `json:"url,omitempty"`
+	Website                         string     `json:"website,omitempty"`
+	Currency                        string     `json:"currency,omitempty"`
+	ProfilePicture                  string     `json:"profile_picture,omitempty"`
+	SubmissionState                 string     `json:"submission_state"`
+	TriageActive                    *bool      `json:"triage_active,omitempty"`
+	State                           string     `json:"state"`
+	StartedAcceptingAt              *time.Time `json:"started_accepting_at,omitempty"`
+	NumberOfReportsForUser          int        `json:"number_of_reports_for_user,omitempty"`
+	NumberOfValidReportsForUser     int        `json:"number_of_valid_reports_for_user,omitempty"`
+	BountyEarnedForUser             float64    `json:"bounty_earned_for_user,omitempty"`
+	LastInvitationAcceptedAtForUser *time.Time `json:"last_invitation_accepted_at_for_user,omitempty"`
+	Bookmarked                      bool       `json:"bookmarked,omitempty"`
+	AllowsBountySplitting           bool       `json:"allows_bounty_splitting,omitempty"`
+	OffersBounties                  bool       `json:"offers_bounties"`
+	OffersSwag                      bool       `json:"offers_swag,omitempty"`
+	OpenScope                       bool       `json:"open_scope,omitempty"`
+	FastPayments                    bool       `json:"fast_payments,omitempty"`
+	GoldStandardSafeHarbor          bool       `json:"gold_standard_safe_harbor,omitempty"`
+	AllowsDisclosure                bool       `json:"allows_disclosure,omitempty"`
+	AllowsPrivateDisclosure         bool       `json:"allows_private_disclosure,omitempty"`
+	ResponseEfficiencyPercentage    int        `json:"response_efficiency_percentage,omitempty"`
+	CreatedAt                       time.Time  `json:"created_at"`
+	UpdatedAt                       time.Time  `json:"updated_at"`
 }
 
 // ProgramLinks contains program links
@@ -42,33 +56,39 @@ type HackerOneScope struct {
 	Attributes ScopeAttributes `json:"attributes"`
 }
 
-// ScopeAttributes contains scope details
+// ScopeAttributes contains scope details. Fields with omitempty are not sent
+// for every scope; the others are expected by schema drift detection.
 type ScopeAttributes struct {
-	AssetIdentifier       string    `json:"asset_identifier"`
-	AssetType             string    `json:"asset_type"`
-	EligibleForBounty     bool      `json:"eligible_for_bounty"`
-	EligibleForSubmission bool      `json:"eligible_for_submission"`
-	Instruction           string    `json:"instruction"`
-	Reference             string    `json:"reference"`
-	Confidentiality       string    `json:"confidentiality"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	AssetIdentifier            string    `json:"asset_identifier"`
+	AssetType                  string    `json:"asset_type"`
+	EligibleForBounty          bool      `json:"eligible_for_bounty"`
+	EligibleForSubmission      bool      `json:"eligible_for_submission"`
+	Instruction                string    `json:"instruction,omitempty"`
+	Reference                  string    `json:"reference,omitempty"`
+	MaxSeverity                string    `json:"max_severity,omitempty"`
+	Confidentiality            string    `json:"confidentiality,omitempty"`
+	ConfidentialityRequirement string    `json:"confidentiality_requirement,omitempty"`
+	IntegrityRequirement       string    `json:"integrity_requirement,omitempty"`
+	AvailabilityRequirement    string    `json:"availability_requirement,omitempty"`
+	CreatedAt                  time.Time `json:"created_at"`
+	UpdatedAt                  time.Time `json:"updated_at"`
 }
 
 // HackerOneResponse represents a generic HackerOne API response
 type HackerOneResponse struct {
 	Data  []HackerOneProgram `json:"data"`
 	Links ResponseLinks      `json:"links"`
-	Meta  ResponseMeta       `json:"meta"`
+	Meta  ResponseMeta       `json:"meta,omitempty"`
 }
 
-// ResponseLinks contains pagination links
+// ResponseLinks contains pagination links; only the links that apply to a
+// page are sent
 type ResponseLinks struct {
-	First string `json:"first"`
-	Last  string `json:"last"`
-	Next  string `json:"next"`
-	Prev  string `json:"prev"`
-	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Self  string `json:"self,omitempty"`
 }
 
 // ResponseMeta contains response metadata
@@ -82,8 +102,8 @@ type ResponseMeta struct {
 // ScopeResponse represents a HackerOne scope API response
 type ScopeResponse struct {
 	Data  []HackerOneScope `json:"data"`
-	Links ResponseLinks    `json:"links"`
-	Meta  ResponseMeta     `json:"meta"`
+	Links ResponseLinks    `json:"links,omitempty"`
+	Meta  ResponseMeta     `json:"meta,omitempty"`
 }
 
 // ErrorResponse represents a HackerOne API error
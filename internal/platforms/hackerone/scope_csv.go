@@ -0,0 +1,254 @@
+package hackerone
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// sparseScopeThreshold is the structured scope size below which policy
+	// attachments are checked for a published scope CSV
+	sparseScopeThreshold = 3
+
+	// ScopeSourceCSV marks scope assets parsed from a policy CSV attachment
+	ScopeSourceCSV = "hackerone-csv"
+)
+
+// ProgramDetailResponse represents a HackerOne program detail API response
+type ProgramDetailResponse struct {
+	ID            string               `json:"id"`
+	Type          string               `json:"type"`
+	Attributes    ProgramAttributes    `json:"attributes"`
+	Relationships ProgramRelationships `json:"relationships"`
+}
+
+// ProgramRelationships contains the program's related resources
+type ProgramRelationships struct {
+	Attachments AttachmentList `json:"attachments"`
+}
+
+// AttachmentList contains the policy attachments of a program
+type AttachmentList struct {
+	Data []Attachment `json:"data"`
+}
+
+// Attachment represents a file attached to a program policy
+type Attachment struct {
+	ID         string               `json:"id"`
+	Type       string               `json:"type"`
+	Attributes AttachmentAttributes `json:"attributes"`
+}
+
+// AttachmentAttributes contains attachment details
+type AttachmentAttributes struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	ExpiringURL string `json:"expiring_url"`
+	FileSize    int64  `json:"file_size"`
+}
+
+// isScopeCSV reports whether an attachment looks like a scope CSV
+func (a Attachment) isScopeCSV() bool {
+	name := strings.ToLower(a.Attributes.FileName)
+	contentType := strings.ToLower(a.Attributes.ContentType)
+	return strings.HasSuffix(name, ".csv") || strings.HasPrefix(contentType, "text/csv")
+}
+
+// getAttachmentScope downloads the program's CSV policy attachments and
+// parses them into scope assets
+func (c *Client) getAttachmentScope(ctx context.Context, handle string) ([]*ScopeAsset, error) {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("%s/hackers/programs/%s", c.baseURL, handle))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), resp.Body()); merr != nil {
+		return nil, merr
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("HackerOne API returned status %d", resp.StatusCode())
+	}
+
+	var detail ProgramDetailResponse
+	if err := json.Unmarshal(resp.Body(), &detail); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal program detail: %w", err)
+	}
+
+	var assets []*ScopeAsset
+	for _, attachment := range detail.Relationships.Attachments.Data {
+		if !attachment.isScopeCSV() || attachment.Attributes.ExpiringURL == "" {
+			continue
+		}
+
+		parsed, err := c.downloadScopeCSV(ctx, attachment.Attributes.ExpiringURL)
+		if err != nil {
+			logrus.Warnf("Skipping scope attachment %s for program %s: %v", attachment.Attributes.FileName, handle, err)
+			continue
+		}
+
+		logrus.Debugf("Parsed %d scope assets from attachment %s for program %s", len(parsed), attachment.Attributes.FileName, handle)
+		assets = append(assets, parsed...)
+	}
+
+	return assets, nil
+}
+
+// downloadScopeCSV fetches and parses a single scope CSV attachment
+func (c *Client) downloadScopeCSV(ctx context.Context, attachmentURL string) ([]*ScopeAsset, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attachmentURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Attachment URLs are pre-signed, so they are fetched with the bare HTTP
+	// client to keep the API credentials off the storage request
+	resp, err := c.httpClient.GetClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("attachment download returned status %d", resp.StatusCode)
+	}
+
+	return c.ParseScopeCSV(resp.Body)
+}
+
+// ParseScopeCSV parses a scope CSV in the format of HackerOne's scope export
+// (identifier, asset_type, eligible_for_submission, ...). Columns are matched
+// by header name; rows without an identifier are skipped.
+func (c *Client) ParseScopeCSV(r io.Reader) ([]*ScopeAsset, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[strings.ReplaceAll(name, " ", "_")] = i
+	}
+
+	identifierCol, ok := columns["identifier"]
+	if !ok {
+		if identifierCol, ok = columns["asset_identifier"]; !ok {
+			return nil, fmt.Errorf("CSV has no identifier column")
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var assets []*ScopeAsset
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+		if identifierCol >= len(record) {
+			continue
+		}
+
+		assetType := strings.ToUpper(field(record, "asset_type"))
+		if assetType == "" {
+			assetType = "URL"
+			if strings.HasPrefix(strings.TrimSpace(record[identifierCol]), "*.") {
+				assetType = "WILDCARD"
+			}
+		}
+
+		// Rows without an explicit eligibility are treated as in scope
+		eligible := true
+		if value := field(record, "eligible_for_submission"); value != "" {
+			eligible = parseCSVBool(value)
+		}
+
+		asset := c.parseScopeAsset(ScopeAttributes{
+			AssetIdentifier:       record[identifierCol],
+			AssetType:             assetType,
+			EligibleForSubmission: eligible,
+			EligibleForBounty:     parseCSVBool(field(record, "eligible_for_bounty")),
+			Instruction:           field(record, "instruction"),
+		})
+		if asset == nil {
+			continue
+		}
+		asset.Source = ScopeSourceCSV
+		assets = append(assets, asset)
+	}
+
+	return assets, nil
+}
+
+// parseCSVBool parses the boolean spellings used in scope exports
+func parseCSVBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// scopeKey identifies a scope entry for reconciliation
+func scopeKey(asset *ScopeAsset) string {
+	identifier := asset.OriginalPattern
+	if identifier == "" {
+		identifier = asset.URL
+	}
+	return asset.Type + "|" + strings.ToLower(strings.TrimSuffix(identifier, "/"))
+}
+
+// reconcileScope merges CSV scope entries into the structured scope. The API
+// data is authoritative: a CSV entry for a target the API already lists is
+// dropped, so only targets missing from the API are added.
+func reconcileScope(apiAssets, csvAssets []*ScopeAsset) []*ScopeAsset {
+	seen := make(map[string]bool, len(apiAssets)+len(csvAssets))
+	merged := make([]*ScopeAsset, 0, len(apiAssets)+len(csvAssets))
+
+	for _, asset := range apiAssets {
+		seen[scopeKey(asset)] = true
+		merged = append(merged, asset)
+	}
+
+	for _, asset := range csvAssets {
+		key := scopeKey(asset)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, asset)
+	}
+
+	return merged
+}
@@ -0,0 +1,120 @@
+package hackerone
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScopeCSV = "\ufeffidentifier,asset_type,instruction,eligible_for_bounty,eligible_for_submission,max_severity\n" +
+	"*.example.com,WILDCARD,,true,true,critical\n" +
+	"app.example.com,URL,Main app,true,true,critical\n" +
+	"blog.example.com,URL,,false,false,none\n" +
+	",URL,,true,true,critical\n"
+
+func TestClient_ParseScopeCSV(t *testing.T) {
+	client := &Client{urlProcessor: utils.NewURLProcessor()}
+
+	assets, err := client.ParseScopeCSV(strings.NewReader(testScopeCSV))
+	require.NoError(t, err)
+	require.Len(t, assets, 3)
+
+	assert.Equal(t, "wildcard", assets[0].Type)
+	assert.Equal(t, "*.example.com", assets[0].OriginalPattern)
+	assert.True(t, assets[0].EligibleForSubmission)
+
+	assert.Equal(t, "url", assets[1].Type)
+	assert.Equal(t, "https://app.example.com", assets[1].URL)
+
+	assert.False(t, assets[2].EligibleForSubmission)
+
+	for _, asset := range assets {
+		assert.Equal(t, ScopeSourceCSV, asset.Source)
+	}
+}
+
+func TestClient_ParseScopeCSV_MinimalColumns(t *testing.T) {
+	client := &Client{urlProcessor: utils.NewURLProcessor()}
+
+	assets, err := client.ParseScopeCSV(strings.NewReader("Identifier\n*.example.org\nshop.example.org\n"))
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+
+	assert.Equal(t, "wildcard", assets[0].Type)
+	assert.Equal(t, "url", assets[1].Type)
+	assert.True(t, assets[1].EligibleForSubmission, "rows without eligibility default to in scope")
+}
+
+func TestClient_ParseScopeCSV_NoIdentifierColumn(t *testing.T) {
+	client := &Client{urlProcessor: utils.NewURLProcessor()}
+
+	_, err := client.ParseScopeCSV(strings.NewReader("name,type\nfoo,URL\n"))
+	assert.Error(t, err)
+}
+
+func TestReconcileScope(t *testing.T) {
+	apiAssets := []*ScopeAsset{
+		{URL: "https://app.example.com", Type: "url", EligibleForSubmission: false},
+	}
+	csvAssets := []*ScopeAsset{
+		{URL: "https://app.example.com", Type: "url", EligibleForSubmission: true, Source: ScopeSourceCSV},
+		{URL: "https://example.com", Type: "wildcard", OriginalPattern: "*.example.com", EligibleForSubmission: true, Source: ScopeSourceCSV},
+		{URL: "https://example.com", Type: "wildcard", OriginalPattern: "*.EXAMPLE.com", EligibleForSubmission: true, Source: ScopeSourceCSV},
+	}
+
+	merged := reconcileScope(apiAssets, csvAssets)
+	require.Len(t, merged, 2)
+
+	// The API entry wins over the CSV entry for the same target
+	assert.Same(t, apiAssets[0], merged[0])
+	assert.False(t, merged[0].EligibleForSubmission)
+	assert.Equal(t, "*.example.com", merged[1].OriginalPattern)
+}
+
+func TestClient_GetProgramScope_CSVAttachment(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/hackers/programs/acme/structured_scopes":
+			fmt.Fprint(w, `{"data":[{"id":"1","type":"structured-scope","attributes":{"asset_identifier":"app.example.com","asset_type":"URL","eligible_for_submission":true}}]}`)
+		case "/v1/hackers/programs/acme":
+			fmt.Fprintf(w, `{"id":"42","type":"program","relationships":{"attachments":{"data":[
+				{"id":"7","type":"attachment","attributes":{"file_name":"scope.csv","content_type":"text/csv","expiring_url":"%[1]s/files/scope.csv"}},
+				{"id":"8","type":"attachment","attributes":{"file_name":"logo.png","content_type":"image/png","expiring_url":"%[1]s/files/logo.png"}}
+			]}}}`, server.URL)
+		case "/files/scope.csv":
+			assert.Empty(t, r.Header.Get("Authorization"), "credentials must not be sent to attachment storage")
+			fmt.Fprint(w, testScopeCSV)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHackerOneClient(&PlatformConfig{
+		APIKey:    "key",
+		Username:  "user",
+		RateLimit: 1000,
+		Timeout:   5 * time.Second,
+		BaseURL:   server.URL + "/v1",
+	})
+
+	assets, err := client.GetProgramScope(context.Background(), "https://hackerone.com/acme")
+	require.NoError(t, err)
+	require.Len(t, assets, 3)
+
+	assert.Equal(t, "https://app.example.com", assets[0].URL)
+	assert.Empty(t, assets[0].Source, "structured scope entries keep their API provenance")
+	assert.Equal(t, "*.example.com", assets[1].OriginalPattern)
+	assert.Equal(t, ScopeSourceCSV, assets[1].Source)
+	assert.Equal(t, "https://blog.example.com", assets[2].URL)
+}
@@ -0,0 +1,236 @@
+package hackerone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/monitor-agent/internal/schemadrift"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// scopePageSize is the number of structured scopes requested per page,
+	// the maximum the API allows
+	scopePageSize = 100
+
+	// maxErrorBodySize bounds how much of an error or HTML response is read
+	// to detect maintenance pages and API errors
+	maxErrorBodySize = 1 << 20
+)
+
+// StreamProgramScope retrieves the scope of a program page by page and passes
+// it to fn in chunks of at most chunkSize assets, so programs with thousands of
+// structured scopes are never held in memory at once. Each page is decoded one
+// scope at a time. An error returned by fn stops the stream and is returned;
+// the chunks fn already handled stay handled.
+func (c *Client) StreamProgramScope(ctx context.Context, programURL string, chunkSize int, fn func([]*ScopeAsset) error) error {
+	handle, err := c.extractHandleFromURL(programURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract handle from URL: %w", err)
+	}
+	logrus.Debugf("Extracted handle '%s' from URL '%s'", handle, programURL)
+
+	if chunkSize <= 0 {
+		chunkSize = scopePageSize
+	}
+
+	comparison := schemadrift.NewComparison()
+	defer func() {
+		schemadrift.Report(c.GetName(), "structured_scopes", comparison.Drifts())
+	}()
+
+	// A sparse scope is completed from the CSV attachments before it is passed
+	// on, so nothing is flushed until the scope is known not to be sparse
+	total := 0
+	chunk := make([]*ScopeAsset, 0, chunkSize)
+	emit := func(asset *ScopeAsset) error {
+		chunk = append(chunk, asset)
+		total++
+		if len(chunk) < chunkSize || total < sparseScopeThreshold {
+			return nil
+		}
+		full := chunk
+		chunk = make([]*ScopeAsset, 0, chunkSize)
+		return fn(full)
+	}
+
+	for page := 1; ; page++ {
+		found, hasMore, err := c.streamScopePage(ctx, handle, page, comparison, emit)
+		if err != nil {
+			return err
+		}
+		if !hasMore || found == 0 {
+			break
+		}
+	}
+
+	// Some programs publish their scope as a CSV policy attachment and keep the
+	// structured scope sparse, so fall back to the attachment in that case
+	if total < sparseScopeThreshold {
+		csvAssets, err := c.getAttachmentScope(ctx, handle)
+		if err != nil {
+			if _, ok := utils.AsMaintenanceError(err); ok {
+				return err
+			}
+			logrus.Warnf("Failed to get scope attachments for program %s: %v", handle, err)
+		} else if len(csvAssets) > 0 {
+			merged := reconcileScope(chunk, csvAssets)
+			logrus.Infof("Added %d scope assets from CSV attachments for program %s", len(merged)-len(chunk), handle)
+			total += len(merged) - len(chunk)
+			chunk = merged
+		}
+	}
+
+	if len(chunk) > 0 {
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+
+	logrus.Infof("Retrieved %d scope assets for program %s", total, handle)
+	return nil
+}
+
+// streamScopePage decodes one page of structured scopes, passing each parsed
+// asset to emit as soon as it is decoded. It returns the number of scopes on
+// the page and whether the API links a next page.
+func (c *Client) streamScopePage(ctx context.Context, handle string, page int, comparison *schemadrift.Comparison, emit func(*ScopeAsset) error) (int, bool, error) {
+	params := url.Values{}
+	params.Set("page[number]", fmt.Sprintf("%d", page))
+	params.Set("page[size]", fmt.Sprintf("%d", scopePageSize))
+
+	scopeURL := fmt.Sprintf("%s/hackers/programs/%s/structured_scopes?%s", c.baseURL, handle, params.Encode())
+	logrus.Debugf("Making scope request to: %s", scopeURL)
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(scopeURL)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to make request: %w", err)
+	}
+	rawBody := resp.RawBody()
+	defer rawBody.Close()
+
+	// Error and maintenance pages are small and needed whole; only a
+	// successful JSON page is streamed
+	var body io.Reader = rawBody
+	isHTML := strings.Contains(strings.ToLower(resp.Header().Get("Content-Type")), "text/html")
+	if resp.StatusCode() != http.StatusOK || isHTML {
+		data, err := io.ReadAll(io.LimitReader(rawBody, maxErrorBodySize))
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), data); merr != nil {
+			return 0, false, merr
+		}
+
+		if cerr := utils.DetectCredentialFailure(c.GetName(), resp.StatusCode(), resp.Header()); cerr != nil {
+			return 0, false, cerr
+		}
+
+		if resp.StatusCode() != http.StatusOK {
+			var errorResp ErrorResponse
+			if err := json.Unmarshal(data, &errorResp); err == nil && len(errorResp.Errors) > 0 {
+				return 0, false, fmt.Errorf("HackerOne API error: %s", errorResp.Errors[0].Detail)
+			}
+			return 0, false, fmt.Errorf("HackerOne API returned status %d", resp.StatusCode())
+		}
+		body = bytes.NewReader(data)
+	}
+
+	found, links, err := c.decodeScopePage(body, comparison, emit)
+	if err != nil {
+		return found, false, err
+	}
+	return found, links.Next != "", nil
+}
+
+// decodeScopePage decodes a structured scopes response one data element at a
+// time. The envelope and every element are added to the schema comparison.
+func (c *Client) decodeScopePage(body io.Reader, comparison *schemadrift.Comparison, emit func(*ScopeAsset) error) (int, ResponseLinks, error) {
+	var links ResponseLinks
+	dec := json.NewDecoder(body)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return 0, links, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	found := 0
+	envelope := make(map[string]json.RawMessage)
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return found, links, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		key, _ := token.(string)
+
+		if key != "data" {
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return found, links, fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+			envelope[key] = value
+			if key == "links" {
+				if err := json.Unmarshal(value, &links); err != nil {
+					return found, links, fmt.Errorf("failed to unmarshal response links: %w", err)
+				}
+			}
+			continue
+		}
+
+		// The elements are compared on their own, so the envelope only
+		// records that the data array was there
+		envelope[key] = json.RawMessage("[]")
+		if err := expectDelim(dec, '['); err != nil {
+			return found, links, fmt.Errorf("failed to unmarshal response data: %w", err)
+		}
+		for dec.More() {
+			var element json.RawMessage
+			if err := dec.Decode(&element); err != nil {
+				return found, links, fmt.Errorf("failed to unmarshal scope: %w", err)
+			}
+			found++
+			_ = comparison.Add("data[]", element, HackerOneScope{})
+
+			var scope HackerOneScope
+			if err := json.Unmarshal(element, &scope); err != nil {
+				return found, links, fmt.Errorf("failed to unmarshal scope: %w", err)
+			}
+			// Include both in-scope and out-of-scope assets
+			if asset := c.parseScopeAsset(scope.Attributes); asset != nil {
+				if err := emit(asset); err != nil {
+					return found, links, err
+				}
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return found, links, fmt.Errorf("failed to unmarshal response data: %w", err)
+		}
+	}
+
+	if envelopeJSON, err := json.Marshal(envelope); err == nil {
+		_ = comparison.Add("", envelopeJSON, ScopeResponse{})
+	}
+	return found, links, nil
+}
+
+// expectDelim reads the next JSON token and checks it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if got, ok := token.(json.Delim); !ok || got != delim {
+		return fmt.Errorf("expected %q, got %v", delim, token)
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package hackerone
 import (
 	"context"
 	"time"
+
+	"github.com/monitor-agent/internal/metrics"
 )
 
 // Platform represents a bug bounty platform
@@ -22,11 +24,13 @@ type Platform interface {
 
 // Program represents a bug bounty program
 type Program struct {
+	PlatformID  string    `json:"platform_id"` // stable platform-side ID that survives handle renames
 	Name        string    `json:"name"`
 	Platform    string    `json:"platform"`
 	URL         string    `json:"url"`
 	ProgramURL  string    `json:"program_url"`
 	IsActive    bool      `json:"is_active"`
+	Visibility  string    `json:"visibility,omitempty"` // public or private; empty for platforms that only list public programs
 	LastUpdated time.Time `json:"last_updated"`
 }
 
@@ -38,14 +42,19 @@ type ScopeAsset struct {
 	Type                  string `json:"type"` // url, wildcard, etc.
 	EligibleForSubmission bool   `json:"eligible_for_submission"`
 	OriginalPattern       string `json:"original_pattern,omitempty"` // Original pattern for wildcards
+	Source                string `json:"source,omitempty"`           // provenance when not the structured scope API, e.g. hackerone-csv
 }
 
 // PlatformConfig holds configuration for a platform
 type PlatformConfig struct {
-	APIKey        string
-	Username      string
-	RateLimit     int
-	Timeout       time.Duration
-	RetryAttempts int
-	RetryDelay    time.Duration
+	APIKey         string
+	Username       string
+	RateLimit      int
+	RateBurst      int // most requests sent in a burst; 0 uses RateLimit
+	Timeout        time.Duration
+	RetryAttempts  int
+	RetryDelay     time.Duration
+	BaseURL        string           // overrides the default API URL
+	IncludePrivate bool             // also lists the private programs the account was invited to
+	Metrics        *metrics.Metrics // records the requests sent; nil disables it
 }
@@ -0,0 +1,356 @@
+package intigriti
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/schemadrift"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/monitor-agent/internal/version"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultBaseURL = "https://api.intigriti.com/external/researcher/v1"
+
+	// programURLPrefix is where program pages live when the API sends no web link
+	programURLPrefix = "https://app.intigriti.com/programs/"
+
+	pageSize = 500
+)
+
+// Client represents an Intigriti API client
+type Client struct {
+	httpClient   *resty.Client
+	config       *PlatformConfig
+	rateLimiter  *utils.RateLimiter
+	urlProcessor *utils.URLProcessor
+	baseURL      string
+
+	// programIDs maps program handles to the IDs the scope endpoint needs,
+	// since programs are stored by URL
+	mu         sync.Mutex
+	programIDs map[string]string
+}
+
+// NewIntigritiClient creates a new Intigriti client
+func NewIntigritiClient(config *PlatformConfig) *Client {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+	config.Metrics.InstrumentPlatformClient(client, "intigriti")
+	rateLimiter := utils.NewRateLimiter(config.RateLimit, time.Minute)
+	rateLimiter.SetBurst(config.RateBurst)
+	rateLimiter.Instrument(client, metrics.EndpointLabel)
+	config.Metrics.InstrumentRateLimiter("intigriti", rateLimiter)
+
+	// Set default headers
+	client.SetHeaders(map[string]string{
+		"Accept":     "application/json",
+		"User-Agent": version.UserAgent(),
+	})
+
+	// Add authentication
+	if config.APIKey != "" {
+		client.SetAuthToken(config.APIKey)
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &Client{
+		httpClient:   client,
+		config:       config,
+		rateLimiter:  rateLimiter,
+		urlProcessor: utils.NewURLProcessor(),
+		baseURL:      baseURL,
+		programIDs:   make(map[string]string),
+	}
+}
+
+// GetName returns the platform name
+func (c *Client) GetName() string {
+	return "intigriti"
+}
+
+// IsHealthy checks if the Intigriti API is healthy
+func (c *Client) IsHealthy(ctx context.Context) error {
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("%s/programs?limit=1", c.baseURL))
+
+	if err != nil {
+		return fmt.Errorf("failed to check Intigriti API health: %w", err)
+	}
+
+	return c.checkResponse(resp)
+}
+
+// GetPublicPrograms retrieves all open public bug bounty programs from Intigriti
+func (c *Client) GetPublicPrograms(ctx context.Context) ([]*Program, error) {
+	var allPrograms []*Program
+	offset := 0
+
+	for {
+		programs, fetched, hasMore, err := c.getProgramsPage(ctx, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get programs at offset %d: %w", offset, err)
+		}
+
+		allPrograms = append(allPrograms, programs...)
+
+		if !hasMore || fetched == 0 {
+			break
+		}
+		offset += fetched
+	}
+
+	logrus.Infof("Retrieved %d programs from Intigriti", len(allPrograms))
+	return allPrograms, nil
+}
+
+// getProgramsPage retrieves a single page of programs and remembers their IDs.
+// It returns the number of records on the page, which includes programs that
+// are filtered out.
+func (c *Client) getProgramsPage(ctx context.Context, offset int) ([]*Program, int, bool, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", pageSize))
+	params.Set("offset", fmt.Sprintf("%d", offset))
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("%s/programs?%s", c.baseURL, params.Encode()))
+
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	if err := c.checkResponse(resp); err != nil {
+		return nil, 0, false, err
+	}
+
+	var apiResp ProgramsResponse
+	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
+		return nil, 0, false, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	schemadrift.Check(c.GetName(), "programs", resp.Body(), apiResp)
+
+	var programs []*Program
+	c.mu.Lock()
+	for _, program := range apiResp.Records {
+		// Only include open public programs
+		if program.ConfidentialityLevel.Value != "Public" || program.Status.Value != "Open" {
+			continue
+		}
+
+		programURL := program.WebLinks.Detail
+		if programURL == "" {
+			programURL = programURLPrefix + program.Handle + "/detail"
+		}
+		c.programIDs[program.Handle] = program.ID
+
+		programs = append(programs, &Program{
+			PlatformID: program.ID,
+			Name:       program.Name,
+			Platform:   "intigriti",
+			URL:        programURL,
+			ProgramURL: programURL,
+			IsActive:   true,
+		})
+	}
+	c.mu.Unlock()
+
+	hasMore := offset+len(apiResp.Records) < apiResp.MaxCount
+
+	return programs, len(apiResp.Records), hasMore, nil
+}
+
+// GetProgramScope retrieves the in-scope assets for a specific program
+func (c *Client) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
+	handle, err := c.extractHandleFromURL(programURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract handle from URL: %w", err)
+	}
+
+	programID, err := c.programID(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("%s/programs/%s", c.baseURL, url.PathEscape(programID)))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	if err := c.checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var detail ProgramDetail
+	if err := json.Unmarshal(resp.Body(), &detail); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	schemadrift.Check(c.GetName(), "program", resp.Body(), detail)
+
+	var scopeAssets []*ScopeAsset
+	for _, domain := range detail.Domains.Content {
+		// Out of scope entries are listed alongside the in-scope ones
+		if strings.EqualFold(domain.Tier.Value, "Out Of Scope") {
+			continue
+		}
+		if asset := c.parseScopeAsset(domain); asset != nil {
+			scopeAssets = append(scopeAssets, asset)
+		}
+	}
+
+	logrus.Infof("Retrieved %d scope assets for program %s", len(scopeAssets), handle)
+	return scopeAssets, nil
+}
+
+// programID returns the ID of a program handle, listing the programs when
+// the handle has not been seen yet
+func (c *Client) programID(ctx context.Context, handle string) (string, error) {
+	c.mu.Lock()
+	id, ok := c.programIDs[handle]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	if _, err := c.GetPublicPrograms(ctx); err != nil {
+		return "", fmt.Errorf("failed to look up program %s: %w", handle, err)
+	}
+
+	c.mu.Lock()
+	id, ok = c.programIDs[handle]
+	c.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("Intigriti program %s not found", handle)
+	}
+	return id, nil
+}
+
+// checkResponse turns maintenance, credential and other failed responses into errors
+func (c *Client) checkResponse(resp *resty.Response) error {
+	if merr := utils.DetectMaintenance(c.GetName(), resp.StatusCode(), resp.Header(), resp.Body()); merr != nil {
+		return merr
+	}
+
+	if cerr := utils.DetectCredentialFailure(c.GetName(), resp.StatusCode(), resp.Header()); cerr != nil {
+		return cerr
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		var errorResp IntigritiError
+		if err := json.Unmarshal(resp.Body(), &errorResp); err == nil && errorResp.Message != "" {
+			return fmt.Errorf("Intigriti API error: %s", errorResp.Message)
+		}
+		return fmt.Errorf("Intigriti API returned status %d", resp.StatusCode())
+	}
+
+	return nil
+}
+
+// parseScopeAsset parses a scope domain into a ScopeAsset. Mobile apps,
+// devices and other entries without a host are skipped.
+func (c *Client) parseScopeAsset(domain Domain) *ScopeAsset {
+	endpoint := strings.TrimSpace(domain.Endpoint)
+	if endpoint == "" {
+		return nil
+	}
+
+	// Tiers pay a bounty, "No Bounty" entries may be tested but are not rewarded
+	eligible := !strings.EqualFold(domain.Tier.Value, "No Bounty")
+
+	switch strings.ToLower(domain.Type.Value) {
+	case "url":
+		normalizedURL, err := c.urlProcessor.NormalizeURL(endpoint)
+		if err != nil {
+			normalizedURL = withScheme(endpoint)
+		}
+		return &ScopeAsset{
+			URL:                   normalizedURL,
+			Domain:                c.extractDomain(normalizedURL),
+			Type:                  "url",
+			EligibleForSubmission: eligible,
+		}
+	case "wildcard":
+		// Convert wildcard to base domain for ChaosDB discovery
+		base := c.urlProcessor.ConvertWildcardToDomain(endpoint)
+		normalizedDomain, err := c.urlProcessor.NormalizeURL(base)
+		if err != nil {
+			normalizedDomain = withScheme(base)
+		}
+		return &ScopeAsset{
+			URL:                   normalizedDomain,
+			Domain:                base,
+			Type:                  "wildcard",
+			EligibleForSubmission: eligible,
+			OriginalPattern:       endpoint,
+		}
+	case "ip range":
+		return &ScopeAsset{
+			URL:                   endpoint,
+			Domain:                endpoint,
+			Type:                  "ip",
+			EligibleForSubmission: eligible,
+		}
+	default:
+		logrus.Debugf("Skipping Intigriti %s scope entry %s", domain.Type.Value, endpoint)
+		return nil
+	}
+}
+
+// extractHandleFromURL extracts the program handle from an Intigriti program URL
+func (c *Client) extractHandleFromURL(programURL string) (string, error) {
+	// Expected format: https://app.intigriti.com/programs/company/handle/detail
+	parsed, err := url.Parse(programURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid Intigriti program URL: %s", programURL)
+	}
+
+	segments := strings.FieldsFunc(parsed.Path, func(r rune) bool { return r == '/' })
+	if len(segments) > 0 && segments[len(segments)-1] == "detail" {
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) < 2 || !strings.Contains(parsed.Path, "/programs/") {
+		return "", fmt.Errorf("invalid Intigriti program URL: %s", programURL)
+	}
+	return segments[len(segments)-1], nil
+}
+
+// extractDomain extracts the domain from a URL
+func (c *Client) extractDomain(urlStr string) string {
+	domain, err := c.urlProcessor.ExtractDomain(urlStr)
+	if err != nil {
+		urlStr = strings.TrimPrefix(strings.TrimPrefix(urlStr, "http://"), "https://")
+		if idx := strings.IndexAny(urlStr, "/:"); idx != -1 {
+			urlStr = urlStr[:idx]
+		}
+		return urlStr
+	}
+	return domain
+}
+
+// withScheme prefixes https:// unless the URL already has a scheme
+func withScheme(urlStr string) string {
+	if strings.HasPrefix(urlStr, "http://") || strings.HasPrefix(urlStr, "https://") {
+		return urlStr
+	}
+	return "https://" + urlStr
+}
@@ -0,0 +1,94 @@
+package intigriti
+
+import (
+	"testing"
+
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_parseScopeAsset(t *testing.T) {
+	client := &Client{
+		urlProcessor: utils.NewURLProcessor(),
+	}
+
+	tests := []struct {
+		name         string
+		domainType   string
+		endpoint     string
+		tier         string
+		expectedURL  string
+		expectedType string
+		eligible     bool
+	}{
+		{
+			name:         "url without protocol gets https://",
+			domainType:   "Url",
+			endpoint:     "example.com",
+			tier:         "Tier 1",
+			expectedURL:  "https://example.com",
+			expectedType: "url",
+			eligible:     true,
+		},
+		{
+			name:         "url with http:// gets converted to https://",
+			domainType:   "Url",
+			endpoint:     "http://example.com",
+			tier:         "Tier 2",
+			expectedURL:  "https://example.com",
+			expectedType: "url",
+			eligible:     true,
+		},
+		{
+			name:         "wildcard gets converted to its base domain",
+			domainType:   "Wildcard",
+			endpoint:     "*.example.com",
+			tier:         "Tier 3",
+			expectedURL:  "https://example.com",
+			expectedType: "wildcard",
+			eligible:     true,
+		},
+		{
+			name:         "no bounty tier is not eligible",
+			domainType:   "Url",
+			endpoint:     "blog.example.com",
+			tier:         "No Bounty",
+			expectedURL:  "https://blog.example.com",
+			expectedType: "url",
+			eligible:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset := client.parseScopeAsset(Domain{
+				Type:     TypedValue{Value: tt.domainType},
+				Endpoint: tt.endpoint,
+				Tier:     TypedValue{Value: tt.tier},
+			})
+			require.NotNil(t, asset)
+			assert.Equal(t, tt.expectedURL, asset.URL)
+			assert.Equal(t, tt.expectedType, asset.Type)
+			assert.Equal(t, tt.eligible, asset.EligibleForSubmission)
+		})
+	}
+
+	// Mobile apps have no host to monitor
+	assert.Nil(t, client.parseScopeAsset(Domain{Type: TypedValue{Value: "iOS"}, Endpoint: "id1234567"}))
+}
+
+func TestClient_extractHandleFromURL(t *testing.T) {
+	client := &Client{}
+
+	handle, err := client.extractHandleFromURL("https://app.intigriti.com/programs/acmecorp/acme/detail")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", handle)
+
+	handle, err = client.extractHandleFromURL("https://app.intigriti.com/researcher/programs/acmecorp/acme")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", handle)
+
+	_, err = client.extractHandleFromURL("https://app.intigriti.com/")
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package intigriti
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/schemadrift"
+	"github.com/monitor-agent/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGoldenClient serves testdata payloads for the program list and the
+// acme program, counting program list requests
+func newGoldenClient(t *testing.T) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	programs := testutil.ReadTestdata(t, "programs.json")
+	program := testutil.ReadTestdata(t, "program.json")
+	var listed atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/programs":
+			listed.Add(1)
+			_, _ = w.Write(programs)
+		case "/programs/8d5c1f3a-0b6e-4f7a-9c2d-1e4b7a9f0c31":
+			_, _ = w.Write(program)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewIntigritiClient(&PlatformConfig{APIKey: "secret", BaseURL: server.URL, RateLimit: 6000, Timeout: 5 * time.Second})
+	return client, &listed
+}
+
+func TestClient_GetPublicPrograms_Golden(t *testing.T) {
+	client, _ := newGoldenClient(t)
+
+	programs, err := client.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "programs", programs)
+}
+
+func TestClient_GetProgramScope_Golden(t *testing.T) {
+	client, listed := newGoldenClient(t)
+
+	// The program ID is looked up from the program list on first use
+	assets, err := client.GetProgramScope(context.Background(), "https://app.intigriti.com/programs/acmecorp/acme/detail")
+	require.NoError(t, err)
+	testutil.AssertGolden(t, "program", assets)
+	assert.Equal(t, int32(1), listed.Load())
+
+	_, err = client.GetProgramScope(context.Background(), "https://app.intigriti.com/programs/acmecorp/acme/detail")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), listed.Load())
+
+	_, err = client.GetProgramScope(context.Background(), "https://app.intigriti.com/programs/initech/initech/detail")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestClient_IsHealthy(t *testing.T) {
+	client, _ := newGoldenClient(t)
+	require.NoError(t, client.IsHealthy(context.Background()))
+
+	client.httpClient.SetAuthToken("wrong")
+	assert.Error(t, client.IsHealthy(context.Background()))
+}
+
+func TestPayloads_NoSchemaDrift(t *testing.T) {
+	payloads := map[string]any{
+		"programs.json": ProgramsResponse{},
+		"program.json":  ProgramDetail{},
+	}
+
+	for name, expected := range payloads {
+		drifts, err := schemadrift.Compare(testutil.ReadTestdata(t, name), expected)
+		require.NoError(t, err, name)
+		assert.Empty(t, drifts, name)
+	}
+}
@@ -0,0 +1,68 @@
+package intigriti
+
+// IntigritiProgram represents an Intigriti program in the program list
+type IntigritiProgram struct {
+	ID                   string       `json:"id"`
+	Handle               string       `json:"handle"`
+	Name                 string       `json:"name"`
+	Following            bool         `json:"following"`
+	MinBounty            Bounty       `json:"minBounty"`
+	MaxBounty            Bounty       `json:"maxBounty"`
+	ConfidentialityLevel TypedValue   `json:"confidentialityLevel"`
+	Status               TypedValue   `json:"status"`
+	Type                 TypedValue   `json:"type"`
+	WebLinks             ProgramLinks `json:"webLinks"`
+}
+
+// Bounty is an amount in a currency
+type Bounty struct {
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+}
+
+// TypedValue is an Intigriti enumeration value, e.g. {"id": 4, "value": "Public"}
+type TypedValue struct {
+	ID    int    `json:"id"`
+	Value string `json:"value"`
+}
+
+// ProgramLinks holds the web pages of a program
+type ProgramLinks struct {
+	Detail string `json:"detail"`
+}
+
+// ProgramsResponse represents an Intigriti program list API response
+type ProgramsResponse struct {
+	MaxCount int                `json:"maxCount"`
+	Records  []IntigritiProgram `json:"records"`
+}
+
+// ProgramDetail represents an Intigriti program with its scope
+type ProgramDetail struct {
+	ID      string        `json:"id"`
+	Handle  string        `json:"handle"`
+	Name    string        `json:"name"`
+	Domains DomainVersion `json:"domains"`
+}
+
+// DomainVersion is the current version of a program's scope. Its createdAt
+// is sent as a unix timestamp.
+type DomainVersion struct {
+	ID        string   `json:"id"`
+	CreatedAt int64    `json:"createdAt"`
+	Content   []Domain `json:"content"`
+}
+
+// Domain represents a scope entry of an Intigriti program
+type Domain struct {
+	ID          string     `json:"id"`
+	Type        TypedValue `json:"type"`
+	Endpoint    string     `json:"endpoint"`
+	Tier        TypedValue `json:"tier"`
+	Description string     `json:"description"`
+}
+
+// IntigritiError represents an Intigriti API error
+type IntigritiError struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,56 @@
+package intigriti
+
+import (
+	"context"
+	"time"
+
+	"github.com/monitor-agent/internal/metrics"
+)
+
+// Platform represents a bug bounty platform
+type Platform interface {
+	// GetName returns the platform name
+	GetName() string
+
+	// GetPublicPrograms retrieves all public bug bounty programs from the platform
+	GetPublicPrograms(ctx context.Context) ([]*Program, error)
+
+	// GetProgramScope retrieves the in-scope assets for a specific program
+	GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error)
+
+	// IsHealthy checks if the platform API is healthy
+	IsHealthy(ctx context.Context) error
+}
+
+// Program represents a bug bounty program
+type Program struct {
+	PlatformID  string    `json:"platform_id"` // stable platform-side ID that survives handle renames
+	Name        string    `json:"name"`
+	Platform    string    `json:"platform"`
+	URL         string    `json:"url"`
+	ProgramURL  string    `json:"program_url"`
+	IsActive    bool      `json:"is_active"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// ScopeAsset represents a scope asset for a bug bounty program (both in-scope and out-of-scope)
+type ScopeAsset struct {
+	URL                   string `json:"url"`
+	Domain                string `json:"domain"`
+	Subdomain             string `json:"subdomain,omitempty"`
+	Type                  string `json:"type"` // url, wildcard, etc.
+	EligibleForSubmission bool   `json:"eligible_for_submission"`
+	OriginalPattern       string `json:"original_pattern,omitempty"` // Original pattern for wildcards
+}
+
+// PlatformConfig holds configuration for a platform
+type PlatformConfig struct {
+	APIKey        string
+	RateLimit     int
+	RateBurst     int // most requests sent in a burst; 0 uses RateLimit
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+	BaseURL       string           // overrides the default API URL
+	Metrics       *metrics.Metrics // records the requests sent; nil disables it
+}
@@ -0,0 +1,116 @@
+package platforms
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ManualPlatformName is the platform of programs created from ad-hoc domain lists
+const ManualPlatformName = "manual"
+
+// ManualPlatform exposes a fixed list of domains as the scope of a synthetic
+// program, so discovery can run without any bug bounty platform configured
+type ManualPlatform struct {
+	domains      []string
+	urlProcessor *utils.URLProcessor
+}
+
+// NewManualPlatform creates a manual platform for the given domains. Domains may
+// be bare (example.com), wildcards (*.example.com) or URLs (https://example.com/).
+func NewManualPlatform(domains []string) (*ManualPlatform, error) {
+	urlProcessor := utils.NewURLProcessor()
+
+	seen := make(map[string]bool)
+	var cleaned []string
+	for _, raw := range domains {
+		domain := urlProcessor.ConvertWildcardToDomain(hostOf(strings.TrimSpace(raw)))
+		domain = strings.ToLower(domain)
+		if domain == "" || seen[domain] {
+			continue
+		}
+		if !urlProcessor.IsValidDomain(domain) {
+			return nil, fmt.Errorf("invalid domain: %s", raw)
+		}
+		seen[domain] = true
+		cleaned = append(cleaned, domain)
+	}
+
+	if len(cleaned) == 0 {
+		return nil, fmt.Errorf("no domains given")
+	}
+
+	return &ManualPlatform{
+		domains:      cleaned,
+		urlProcessor: urlProcessor,
+	}, nil
+}
+
+// hostOf returns the host of a URL, or the input unchanged when it has no scheme
+func hostOf(raw string) string {
+	if !strings.Contains(raw, "://") {
+		return strings.SplitN(raw, "/", 2)[0]
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return parsed.Hostname()
+}
+
+// ManualProgram returns the synthetic program that manual domains are stored under
+func ManualProgram(name string) *Program {
+	programURL := fmt.Sprintf("%s://%s", ManualPlatformName, name)
+	return &Program{
+		Name:        name,
+		Platform:    ManualPlatformName,
+		URL:         programURL,
+		ProgramURL:  programURL,
+		IsActive:    true,
+		LastUpdated: time.Now(),
+	}
+}
+
+// Domains returns the cleaned, de-duplicated domains
+func (m *ManualPlatform) Domains() []string {
+	return m.domains
+}
+
+// GetName returns the platform name
+func (m *ManualPlatform) GetName() string {
+	return ManualPlatformName
+}
+
+// GetPublicPrograms returns no programs; manual programs are created by `discover`
+func (m *ManualPlatform) GetPublicPrograms(ctx context.Context) ([]*Program, error) {
+	return nil, nil
+}
+
+// GetProgramScope returns every domain as an in-scope wildcard so its
+// subdomains are discovered as well
+func (m *ManualPlatform) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
+	assets := make([]*ScopeAsset, 0, len(m.domains))
+	for _, domain := range m.domains {
+		assetURL, err := m.urlProcessor.NormalizeURL(domain)
+		if err != nil {
+			assetURL = "https://" + domain
+		}
+		assets = append(assets, &ScopeAsset{
+			URL:                   assetURL,
+			Domain:                domain,
+			Type:                  "wildcard",
+			EligibleForSubmission: true,
+			OriginalPattern:       "*." + domain,
+		})
+	}
+	return assets, nil
+}
+
+// IsHealthy always succeeds; there is no API behind a manual platform
+func (m *ManualPlatform) IsHealthy(ctx context.Context) error {
+	return nil
+}
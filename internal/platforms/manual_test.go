@@ -0,0 +1,47 @@
+package platforms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManualPlatform(t *testing.T) {
+	platform, err := NewManualPlatform([]string{
+		"example.com",
+		"*.example.org",
+		"https://Example.net/login",
+		"example.com",
+		"  ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "example.org", "example.net"}, platform.Domains())
+
+	_, err = NewManualPlatform([]string{"not a domain"})
+	assert.Error(t, err)
+
+	_, err = NewManualPlatform(nil)
+	assert.Error(t, err)
+}
+
+func TestManualPlatform_GetProgramScope(t *testing.T) {
+	platform, err := NewManualPlatform([]string{"example.com"})
+	require.NoError(t, err)
+
+	scope, err := platform.GetProgramScope(context.Background(), "manual://manual")
+	require.NoError(t, err)
+	require.Len(t, scope, 1)
+	assert.Equal(t, "https://example.com", scope[0].URL)
+	assert.Equal(t, "example.com", scope[0].Domain)
+	assert.Equal(t, "wildcard", scope[0].Type)
+	assert.True(t, scope[0].EligibleForSubmission)
+}
+
+func TestManualProgram(t *testing.T) {
+	program := ManualProgram("recon")
+	assert.Equal(t, ManualPlatformName, program.Platform)
+	assert.Equal(t, "manual://recon", program.ProgramURL)
+	assert.True(t, program.IsActive)
+}
@@ -3,11 +3,14 @@ package platforms
 import (
 	"context"
 	"errors"
+	"sort"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/monitor-agent/internal/database"
 	"github.com/monitor-agent/internal/platforms/bugcrowd"
 	"github.com/monitor-agent/internal/platforms/hackerone"
+	"github.com/monitor-agent/internal/platforms/intigriti"
 )
 
 var (
@@ -33,11 +36,13 @@ func (a *HackerOneAdapter) GetPublicPrograms(ctx context.Context) ([]*Program, e
 	programs := make([]*Program, len(h1Programs))
 	for i, h1Program := range h1Programs {
 		programs[i] = &Program{
+			PlatformID:  h1Program.PlatformID,
 			Name:        h1Program.Name,
 			Platform:    h1Program.Platform,
 			URL:         h1Program.URL,
 			ProgramURL:  h1Program.ProgramURL,
 			IsActive:    h1Program.IsActive,
+			Visibility:  h1Program.Visibility,
 			LastUpdated: h1Program.LastUpdated,
 		}
 	}
@@ -49,7 +54,11 @@ func (a *HackerOneAdapter) GetProgramScope(ctx context.Context, programURL strin
 	if err != nil {
 		return nil, err
 	}
+	return convertHackerOneScope(h1Assets), nil
+}
 
+// convertHackerOneScope converts HackerOne scope assets to platform scope assets
+func convertHackerOneScope(h1Assets []*hackerone.ScopeAsset) []*ScopeAsset {
 	assets := make([]*ScopeAsset, len(h1Assets))
 	for i, h1Asset := range h1Assets {
 		assets[i] = &ScopeAsset{
@@ -59,9 +68,16 @@ func (a *HackerOneAdapter) GetProgramScope(ctx context.Context, programURL strin
 			Type:                  h1Asset.Type,
 			EligibleForSubmission: h1Asset.EligibleForSubmission,
 			OriginalPattern:       h1Asset.OriginalPattern,
+			Source:                h1Asset.Source,
 		}
 	}
-	return assets, nil
+	return assets
+}
+
+func (a *HackerOneAdapter) StreamProgramScope(ctx context.Context, programURL string, chunkSize int, fn func([]*ScopeAsset) error) error {
+	return a.client.StreamProgramScope(ctx, programURL, chunkSize, func(h1Assets []*hackerone.ScopeAsset) error {
+		return fn(convertHackerOneScope(h1Assets))
+	})
 }
 
 func (a *HackerOneAdapter) IsHealthy(ctx context.Context) error {
@@ -86,6 +102,7 @@ func (a *BugCrowdAdapter) GetPublicPrograms(ctx context.Context) ([]*Program, er
 	programs := make([]*Program, len(bcPrograms))
 	for i, bcProgram := range bcPrograms {
 		programs[i] = &Program{
+			PlatformID:  bcProgram.PlatformID,
 			Name:        bcProgram.Name,
 			Platform:    bcProgram.Platform,
 			URL:         bcProgram.URL,
@@ -121,15 +138,74 @@ func (a *BugCrowdAdapter) IsHealthy(ctx context.Context) error {
 	return a.client.IsHealthy(ctx)
 }
 
+// IntigritiAdapter adapts intigriti.Client to the main Platform interface
+type IntigritiAdapter struct {
+	client *intigriti.Client
+}
+
+func (a *IntigritiAdapter) GetName() string {
+	return a.client.GetName()
+}
+
+func (a *IntigritiAdapter) GetPublicPrograms(ctx context.Context) ([]*Program, error) {
+	itPrograms, err := a.client.GetPublicPrograms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	programs := make([]*Program, len(itPrograms))
+	for i, itProgram := range itPrograms {
+		programs[i] = &Program{
+			PlatformID:  itProgram.PlatformID,
+			Name:        itProgram.Name,
+			Platform:    itProgram.Platform,
+			URL:         itProgram.URL,
+			ProgramURL:  itProgram.ProgramURL,
+			IsActive:    itProgram.IsActive,
+			LastUpdated: itProgram.LastUpdated,
+		}
+	}
+	return programs, nil
+}
+
+func (a *IntigritiAdapter) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
+	itAssets, err := a.client.GetProgramScope(ctx, programURL)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]*ScopeAsset, len(itAssets))
+	for i, itAsset := range itAssets {
+		assets[i] = &ScopeAsset{
+			URL:                   itAsset.URL,
+			Domain:                itAsset.Domain,
+			Subdomain:             itAsset.Subdomain,
+			Type:                  itAsset.Type,
+			EligibleForSubmission: itAsset.EligibleForSubmission,
+			OriginalPattern:       itAsset.OriginalPattern,
+		}
+	}
+	return assets, nil
+}
+
+func (a *IntigritiAdapter) IsHealthy(ctx context.Context) error {
+	return a.client.IsHealthy(ctx)
+}
+
 // PlatformFactory creates platform instances
 type PlatformFactory struct {
 	configs map[string]*PlatformConfig
+
+	// rotating platforms are kept so disabled credentials stay disabled across scans
+	mu       sync.Mutex
+	rotating map[string]*RotatingPlatform
 }
 
 // NewPlatformFactory creates a new platform factory
 func NewPlatformFactory() *PlatformFactory {
 	return &PlatformFactory{
-		configs: make(map[string]*PlatformConfig),
+		configs:  make(map[string]*PlatformConfig),
+		rotating: make(map[string]*RotatingPlatform),
 	}
 }
 
@@ -145,28 +221,91 @@ func (f *PlatformFactory) GetPlatform(name string) (Platform, error) {
 		return nil, ErrPlatformNotSupported
 	}
 
+	credentials := config.allCredentials()
+	if len(credentials) <= 1 {
+		return newPlatformClient(name, config, credentials[0])
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if platform, ok := f.rotating[name]; ok {
+		return platform, nil
+	}
+
+	names := make([]string, len(credentials))
+	clients := make([]Platform, len(credentials))
+	for i, credential := range credentials {
+		client, err := newPlatformClient(name, config, credential)
+		if err != nil {
+			return nil, err
+		}
+		names[i] = credential.Name
+		clients[i] = client
+	}
+
+	platform := NewRotatingPlatform(name, names, clients)
+	f.rotating[name] = platform
+	return platform, nil
+}
+
+// allCredentials returns the primary credential followed by any additional ones
+func (c *PlatformConfig) allCredentials() []Credential {
+	var credentials []Credential
+	if c.APIKey != "" {
+		credentials = append(credentials, Credential{Name: "default", Username: c.Username, APIKey: c.APIKey})
+	}
+	credentials = append(credentials, c.Credentials...)
+	if len(credentials) == 0 {
+		credentials = append(credentials, Credential{Name: "default"})
+	}
+	return credentials
+}
+
+// newPlatformClient creates the client for one platform credential
+func newPlatformClient(name string, config *PlatformConfig, credential Credential) (Platform, error) {
 	switch name {
 	case "hackerone":
 		// Convert config to hackerone.PlatformConfig
 		h1Config := &hackerone.PlatformConfig{
-			APIKey:        config.APIKey,
-			Username:      config.Username,
-			RateLimit:     config.RateLimit,
-			Timeout:       config.Timeout,
-			RetryAttempts: config.RetryAttempts,
-			RetryDelay:    config.RetryDelay,
+			APIKey:         credential.APIKey,
+			Username:       credential.Username,
+			RateLimit:      config.RateLimit,
+			RateBurst:      config.RateBurst,
+			Timeout:        config.Timeout,
+			RetryAttempts:  config.RetryAttempts,
+			RetryDelay:     config.RetryDelay,
+			BaseURL:        config.BaseURL,
+			IncludePrivate: config.IncludePrivate,
+			Metrics:        config.Metrics,
 		}
 		return &HackerOneAdapter{client: hackerone.NewHackerOneClient(h1Config)}, nil
 	case "bugcrowd":
 		// Convert config to bugcrowd.PlatformConfig
 		bcConfig := &bugcrowd.PlatformConfig{
-			APIKey:        config.APIKey,
+			APIKey:        credential.APIKey,
 			RateLimit:     config.RateLimit,
+			RateBurst:     config.RateBurst,
 			Timeout:       config.Timeout,
 			RetryAttempts: config.RetryAttempts,
 			RetryDelay:    config.RetryDelay,
+			BaseURL:       config.BaseURL,
+			Metrics:       config.Metrics,
 		}
 		return &BugCrowdAdapter{client: bugcrowd.NewBugCrowdClient(bcConfig)}, nil
+	case "intigriti":
+		// Convert config to intigriti.PlatformConfig
+		itConfig := &intigriti.PlatformConfig{
+			APIKey:        credential.APIKey,
+			RateLimit:     config.RateLimit,
+			RateBurst:     config.RateBurst,
+			Timeout:       config.Timeout,
+			RetryAttempts: config.RetryAttempts,
+			RetryDelay:    config.RetryDelay,
+			BaseURL:       config.BaseURL,
+			Metrics:       config.Metrics,
+		}
+		return &IntigritiAdapter{client: intigriti.NewIntigritiClient(itConfig)}, nil
 	default:
 		return nil, ErrPlatformNotSupported
 	}
@@ -183,14 +322,31 @@ func (f *PlatformFactory) GetAllPlatforms() []Platform {
 	return platforms
 }
 
+// PlatformNames returns the names of the registered platforms, sorted
+func (f *PlatformFactory) PlatformNames() []string {
+	names := make([]string, 0, len(f.configs))
+	for name := range f.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ConvertToDatabaseProgram converts a platform Program to a database Program
 func (p *Program) ConvertToDatabaseProgram() *database.Program {
+	visibility := p.Visibility
+	if visibility == "" {
+		visibility = database.ProgramVisibilityPublic
+	}
+
 	return &database.Program{
+		PlatformID:  p.PlatformID,
 		Name:        p.Name,
 		Platform:    p.Platform,
 		URL:         p.URL,
 		ProgramURL:  p.ProgramURL,
 		IsActive:    p.IsActive,
+		Visibility:  visibility,
 		LastUpdated: p.LastUpdated,
 	}
 }
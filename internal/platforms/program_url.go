@@ -0,0 +1,64 @@
+package platforms
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// programHosts maps the web hosts of platforms to the platform name
+var programHosts = map[string]string{
+	"hackerone.com": "hackerone",
+	"bugcrowd.com":  "bugcrowd",
+}
+
+// handlePattern matches valid program handles and codes
+var handlePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ParseProgramURL extracts the platform and program handle from a HackerOne
+// or Bugcrowd program URL. The scheme may be omitted, and sub-pages such as
+// https://hackerone.com/acme/policy_scopes and Bugcrowd engagement URLs
+// (https://bugcrowd.com/engagements/acme) are accepted.
+func ParseProgramURL(raw string) (platform, handle string, err error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid program URL %q: %w", raw, err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	platform, ok := programHosts[host]
+	if !ok {
+		return "", "", fmt.Errorf("%s is not a HackerOne or Bugcrowd program URL", raw)
+	}
+
+	segments := strings.FieldsFunc(parsed.Path, func(r rune) bool { return r == '/' })
+	if platform == "bugcrowd" && len(segments) > 1 && segments[0] == "engagements" {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return "", "", fmt.Errorf("%s has no program handle", raw)
+	}
+
+	handle = segments[0]
+	if !handlePattern.MatchString(handle) {
+		return "", "", fmt.Errorf("%q is not a valid program handle", handle)
+	}
+
+	return platform, handle, nil
+}
+
+// ProgramURL builds the program URL programs of a platform are stored under
+func ProgramURL(platform, handle string) string {
+	for host, name := range programHosts {
+		if name == platform {
+			return fmt.Sprintf("https://%s/%s", host, handle)
+		}
+	}
+	return fmt.Sprintf("%s://%s", platform, handle)
+}
@@ -0,0 +1,48 @@
+package platforms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProgramURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		platform string
+		handle   string
+	}{
+		{"https://hackerone.com/security", "hackerone", "security"},
+		{"hackerone.com/security/", "hackerone", "security"},
+		{"https://www.hackerone.com/security/policy_scopes?type=team", "hackerone", "security"},
+		{"https://bugcrowd.com/tesla", "bugcrowd", "tesla"},
+		{"https://bugcrowd.com/engagements/tesla-mbb-og", "bugcrowd", "tesla-mbb-og"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			platform, handle, err := ParseProgramURL(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.platform, platform)
+			assert.Equal(t, tt.handle, handle)
+		})
+	}
+}
+
+func TestParseProgramURL_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"https://example.com/security",
+		"https://hackerone.com/",
+		"https://hackerone.com/%3Cscript%3E",
+		"manual",
+	} {
+		_, _, err := ParseProgramURL(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestProgramURL(t *testing.T) {
+	assert.Equal(t, "https://hackerone.com/security", ProgramURL("hackerone", "security"))
+	assert.Equal(t, "https://bugcrowd.com/tesla", ProgramURL("bugcrowd", "tesla"))
+}
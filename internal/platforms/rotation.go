@@ -0,0 +1,164 @@
+package platforms
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultQuotaCooldown is how long a credential that hit its quota is skipped
+// when the platform sends no Retry-After header
+const DefaultQuotaCooldown = 15 * time.Minute
+
+// Credential is one account's API credential for a platform
+type Credential struct {
+	Name     string // workspace or account name used in logs
+	Username string
+	APIKey   string
+}
+
+// credentialMember is a platform client bound to one credential
+type credentialMember struct {
+	name          string
+	platform      Platform
+	disabledUntil time.Time
+}
+
+// RotatingPlatform spreads a platform's requests over several credentials.
+// Requests use the current credential until the platform rejects it; a
+// credential that hits its quota is skipped until its Retry-After (or
+// DefaultQuotaCooldown) passes, and one that is unauthorized is skipped for
+// the rest of the process.
+type RotatingPlatform struct {
+	name    string
+	members []*credentialMember
+	now     func() time.Time
+
+	mu      sync.Mutex
+	current int
+}
+
+// NewRotatingPlatform creates a platform that rotates over the given clients,
+// keyed by credential name in the order they should be tried
+func NewRotatingPlatform(name string, names []string, platforms []Platform) *RotatingPlatform {
+	members := make([]*credentialMember, len(platforms))
+	for i, platform := range platforms {
+		members[i] = &credentialMember{name: names[i], platform: platform}
+	}
+
+	return &RotatingPlatform{
+		name:    name,
+		members: members,
+		now:     time.Now,
+	}
+}
+
+// GetName returns the platform name
+func (r *RotatingPlatform) GetName() string {
+	return r.name
+}
+
+// GetPublicPrograms retrieves public programs, rotating credentials on rejection
+func (r *RotatingPlatform) GetPublicPrograms(ctx context.Context) ([]*Program, error) {
+	var programs []*Program
+	err := r.do(func(p Platform) error {
+		var err error
+		programs, err = p.GetPublicPrograms(ctx)
+		return err
+	})
+	return programs, err
+}
+
+// GetProgramScope retrieves a program's scope, rotating credentials on rejection
+func (r *RotatingPlatform) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
+	var assets []*ScopeAsset
+	err := r.do(func(p Platform) error {
+		var err error
+		assets, err = p.GetProgramScope(ctx, programURL)
+		return err
+	})
+	return assets, err
+}
+
+// StreamProgramScope streams a program's scope with the first usable
+// credential. A credential rejected part way through restarts the stream with
+// the next one, so chunks may be passed to fn again.
+func (r *RotatingPlatform) StreamProgramScope(ctx context.Context, programURL string, chunkSize int, fn func([]*ScopeAsset) error) error {
+	return r.do(func(p Platform) error {
+		return StreamScope(ctx, p, programURL, chunkSize, fn)
+	})
+}
+
+// IsHealthy checks the platform API with the first usable credential
+func (r *RotatingPlatform) IsHealthy(ctx context.Context) error {
+	return r.do(func(p Platform) error {
+		return p.IsHealthy(ctx)
+	})
+}
+
+// do runs fn with the current credential, moving on to the next usable one
+// each time the platform rejects a credential
+func (r *RotatingPlatform) do(fn func(Platform) error) error {
+	var lastErr error
+	for attempt := 0; attempt < len(r.members); attempt++ {
+		member, ok := r.next()
+		if !ok {
+			break
+		}
+
+		err := fn(member.platform)
+		cerr, rejected := utils.AsCredentialError(err)
+		if !rejected {
+			return err
+		}
+
+		lastErr = err
+		r.disable(member, cerr)
+	}
+
+	if lastErr == nil {
+		return fmt.Errorf("all %d %s credentials are unavailable", len(r.members), r.name)
+	}
+	return fmt.Errorf("all %d %s credentials are unavailable: %w", len(r.members), r.name, lastErr)
+}
+
+// next returns the first usable credential starting from the current one
+func (r *RotatingPlatform) next() (*credentialMember, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for i := 0; i < len(r.members); i++ {
+		index := (r.current + i) % len(r.members)
+		if member := r.members[index]; !now.Before(member.disabledUntil) {
+			r.current = index
+			return member, true
+		}
+	}
+	return nil, false
+}
+
+// disable takes a rejected credential out of rotation
+func (r *RotatingPlatform) disable(member *credentialMember, cerr *utils.CredentialError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cerr.QuotaExceeded() {
+		cooldown := cerr.RetryAfter
+		if cooldown <= 0 {
+			cooldown = DefaultQuotaCooldown
+		}
+		member.disabledUntil = r.now().Add(cooldown)
+		logrus.Warnf("%s credential %s hit its quota; skipping it for %v", r.name, member.name, cooldown)
+	} else {
+		// Unauthorized credentials won't recover without operator action
+		member.disabledUntil = r.now().Add(100 * 365 * 24 * time.Hour)
+		logrus.Errorf("%s credential %s was rejected (%s); disabling it", r.name, member.name, cerr.Reason)
+	}
+
+	r.current = (r.current + 1) % len(r.members)
+}
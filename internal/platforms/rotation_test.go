@@ -0,0 +1,134 @@
+package platforms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlatform returns a fixed error from every call and counts calls
+type fakePlatform struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (f *fakePlatform) GetName() string { return "hackerone" }
+
+func (f *fakePlatform) GetPublicPrograms(ctx context.Context) ([]*Program, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []*Program{{Name: f.name}}, nil
+}
+
+func (f *fakePlatform) GetProgramScope(ctx context.Context, programURL string) ([]*ScopeAsset, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func (f *fakePlatform) IsHealthy(ctx context.Context) error {
+	f.calls++
+	return f.err
+}
+
+func TestRotatingPlatform_FailsOverOnQuota(t *testing.T) {
+	quota := &utils.CredentialError{Platform: "hackerone", StatusCode: 429, RetryAfter: time.Minute}
+	first := &fakePlatform{name: "team-a", err: quota}
+	second := &fakePlatform{name: "team-b"}
+
+	now := time.Now()
+	r := NewRotatingPlatform("hackerone", []string{"team-a", "team-b"}, []Platform{first, second})
+	r.now = func() time.Time { return now }
+
+	programs, err := r.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "team-b", programs[0].Name)
+
+	// team-a stays out of rotation until its Retry-After passes
+	first.err = nil
+	programs, err = r.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "team-b", programs[0].Name)
+	assert.Equal(t, 1, first.calls)
+
+	// Once team-b is rejected and team-a has cooled down, team-a is used again
+	now = now.Add(2 * time.Minute)
+	second.err = &utils.CredentialError{Platform: "hackerone", StatusCode: 401, Reason: "unauthorized"}
+	programs, err = r.GetPublicPrograms(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", programs[0].Name)
+}
+
+func TestRotatingPlatform_AllCredentialsRejected(t *testing.T) {
+	unauthorized := &utils.CredentialError{Platform: "hackerone", StatusCode: 401, Reason: "unauthorized"}
+	r := NewRotatingPlatform("hackerone", []string{"a", "b"}, []Platform{
+		&fakePlatform{err: unauthorized},
+		&fakePlatform{err: unauthorized},
+	})
+
+	err := r.IsHealthy(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "all 2 hackerone credentials are unavailable")
+
+	// Nothing is retried once every credential is disabled
+	err = r.IsHealthy(context.Background())
+	require.Error(t, err)
+	_, rejected := utils.AsCredentialError(err)
+	assert.False(t, rejected)
+}
+
+func TestRotatingPlatform_OtherErrorsDoNotRotate(t *testing.T) {
+	first := &fakePlatform{err: errors.New("HackerOne API returned status 500")}
+	second := &fakePlatform{}
+	r := NewRotatingPlatform("hackerone", []string{"a", "b"}, []Platform{first, second})
+
+	_, err := r.GetProgramScope(context.Background(), "https://hackerone.com/acme")
+	assert.EqualError(t, err, "HackerOne API returned status 500")
+	assert.Equal(t, 0, second.calls)
+}
+
+func TestPlatformFactory_RotatesMultipleCredentials(t *testing.T) {
+	factory := NewPlatformFactory()
+	factory.RegisterPlatform("hackerone", &PlatformConfig{
+		APIKey:    "key-1",
+		Username:  "user-1",
+		RateLimit: 60,
+		Credentials: []Credential{
+			{Name: "team-b", Username: "user-2", APIKey: "key-2"},
+		},
+	})
+	factory.RegisterPlatform("bugcrowd", &PlatformConfig{APIKey: "key", RateLimit: 60})
+
+	platform, err := factory.GetPlatform("hackerone")
+	require.NoError(t, err)
+	rotating, ok := platform.(*RotatingPlatform)
+	require.True(t, ok)
+	assert.Len(t, rotating.members, 2)
+	assert.Equal(t, "default", rotating.members[0].name)
+	assert.Equal(t, "team-b", rotating.members[1].name)
+
+	// The same rotating platform is returned so disabled credentials stay disabled
+	again, err := factory.GetPlatform("hackerone")
+	require.NoError(t, err)
+	assert.Same(t, rotating, again)
+
+	platform, err = factory.GetPlatform("bugcrowd")
+	require.NoError(t, err)
+	assert.IsType(t, &BugCrowdAdapter{}, platform)
+}
+
+func TestPlatformFactory_PlatformNames(t *testing.T) {
+	factory := NewPlatformFactory()
+	assert.Empty(t, factory.PlatformNames())
+
+	factory.RegisterPlatform("intigriti", &PlatformConfig{APIKey: "key", RateLimit: 55})
+	factory.RegisterPlatform("bugcrowd", &PlatformConfig{APIKey: "key", RateLimit: 60})
+	assert.Equal(t, []string{"bugcrowd", "intigriti"}, factory.PlatformNames())
+}
@@ -3,6 +3,8 @@ package platforms
 import (
 	"context"
 	"time"
+
+	"github.com/monitor-agent/internal/metrics"
 )
 
 // Platform represents a bug bounty platform
@@ -20,13 +22,47 @@ type Platform interface {
 	IsHealthy(ctx context.Context) error
 }
 
+// ScopeStreamer is implemented by platforms that can pass a program's scope on
+// in chunks while it is fetched, so huge scopes are never held in memory
+type ScopeStreamer interface {
+	// StreamProgramScope calls fn with chunks of at most chunkSize scope
+	// assets. An error returned by fn stops the stream and is returned.
+	StreamProgramScope(ctx context.Context, programURL string, chunkSize int, fn func([]*ScopeAsset) error) error
+}
+
+// StreamScope passes a program's scope to fn in chunks of at most chunkSize
+// assets, streaming it when the platform supports that and otherwise
+// splitting the scope GetProgramScope returns
+func StreamScope(ctx context.Context, platform Platform, programURL string, chunkSize int, fn func([]*ScopeAsset) error) error {
+	if streamer, ok := platform.(ScopeStreamer); ok {
+		return streamer.StreamProgramScope(ctx, programURL, chunkSize, fn)
+	}
+
+	assets, err := platform.GetProgramScope(ctx, programURL)
+	if err != nil {
+		return err
+	}
+	if chunkSize <= 0 {
+		chunkSize = len(assets)
+	}
+	for start := 0; start < len(assets); start += chunkSize {
+		end := min(start+chunkSize, len(assets))
+		if err := fn(assets[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Program represents a bug bounty program
 type Program struct {
+	PlatformID  string    `json:"platform_id"` // stable platform-side ID that survives handle renames
 	Name        string    `json:"name"`
 	Platform    string    `json:"platform"`
 	URL         string    `json:"url"`
 	ProgramURL  string    `json:"program_url"`
 	IsActive    bool      `json:"is_active"`
+	Visibility  string    `json:"visibility,omitempty"` // public or private; empty for platforms that only list public programs
 	LastUpdated time.Time `json:"last_updated"`
 }
 
@@ -38,14 +74,20 @@ type ScopeAsset struct {
 	Type                  string `json:"type"` // url, wildcard, etc.
 	EligibleForSubmission bool   `json:"eligible_for_submission"`
 	OriginalPattern       string `json:"original_pattern,omitempty"` // Original pattern for wildcards
+	Source                string `json:"source,omitempty"`           // provenance when not the structured scope API, e.g. hackerone-csv
 }
 
 // PlatformConfig holds configuration for a platform
 type PlatformConfig struct {
-	APIKey        string
-	Username      string
-	RateLimit     int
-	Timeout       time.Duration
-	RetryAttempts int
-	RetryDelay    time.Duration
+	APIKey         string
+	Username       string
+	Credentials    []Credential // additional credentials rotated through after APIKey/Username
+	RateLimit      int
+	RateBurst      int // most requests sent in a burst; 0 uses RateLimit
+	Timeout        time.Duration
+	RetryAttempts  int
+	RetryDelay     time.Duration
+	BaseURL        string           // overrides the platform's API URL
+	IncludePrivate bool             // also lists the private programs the account was invited to (HackerOne)
+	Metrics        *metrics.Metrics // records the requests sent; nil disables it
 }
@@ -0,0 +1,36 @@
+package platforms
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamScope_ChunksPlatformsThatDoNotStream(t *testing.T) {
+	platform, err := NewManualPlatform([]string{"a.example", "b.example", "c.example", "d.example", "e.example"})
+	require.NoError(t, err)
+
+	var chunks [][]string
+	err = StreamScope(context.Background(), platform, "manual://manual", 2, func(chunk []*ScopeAsset) error {
+		var domains []string
+		for _, asset := range chunk {
+			domains = append(domains, asset.Domain)
+		}
+		chunks = append(chunks, domains)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a.example", "b.example"}, {"c.example", "d.example"}, {"e.example"}}, chunks)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = StreamScope(context.Background(), platform, "manual://manual", 2, func([]*ScopeAsset) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,158 @@
+package probeauth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// KeySize is the size of the AES-256 key profiles are sealed with
+const KeySize = 32
+
+// Profile holds the credentials sent when probing a program's assets, such as
+// a test account the program provides
+type Profile struct {
+	Headers       map[string]string `json:"headers,omitempty"` // header name to value
+	Cookies       map[string]string `json:"cookies,omitempty"` // cookie name to value
+	BasicUser     string            `json:"basic_user,omitempty"`
+	BasicPassword string            `json:"basic_password,omitempty"`
+}
+
+// Empty reports whether the profile sends nothing
+func (p *Profile) Empty() bool {
+	return len(p.Headers) == 0 && len(p.Cookies) == 0 && p.BasicUser == "" && p.BasicPassword == ""
+}
+
+// HeaderLines returns the profile as "Name: value" request headers, sorted by
+// name: its headers, a Cookie header with its cookies and an Authorization
+// header for basic auth. Basic auth replaces an Authorization header and the
+// cookies are added to a Cookie header.
+func (p *Profile) HeaderLines() []string {
+	headers := make(map[string]string, len(p.Headers)+2)
+	for name, value := range p.Headers {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	if len(p.Cookies) > 0 {
+		names := make([]string, 0, len(p.Cookies))
+		for name := range p.Cookies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		cookies := make([]string, 0, len(names)+1)
+		if existing := headers["Cookie"]; existing != "" {
+			cookies = append(cookies, existing)
+		}
+		for _, name := range names {
+			cookies = append(cookies, name+"="+p.Cookies[name])
+		}
+		headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+
+	if p.BasicUser != "" || p.BasicPassword != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(p.BasicUser+":"+p.BasicPassword))
+	}
+
+	lines := make([]string, 0, len(headers))
+	for name, value := range headers {
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// Redacted returns a copy of the profile with every value hidden, for display
+func (p *Profile) Redacted() *Profile {
+	redacted := &Profile{BasicUser: p.BasicUser}
+	if p.BasicPassword != "" {
+		redacted.BasicPassword = redactedValue
+	}
+	if len(p.Headers) > 0 {
+		redacted.Headers = make(map[string]string, len(p.Headers))
+		for name := range p.Headers {
+			redacted.Headers[name] = redactedValue
+		}
+	}
+	if len(p.Cookies) > 0 {
+		redacted.Cookies = make(map[string]string, len(p.Cookies))
+		for name := range p.Cookies {
+			redacted.Cookies[name] = redactedValue
+		}
+	}
+	return redacted
+}
+
+// redactedValue replaces secret values in Redacted profiles
+const redactedValue = "********"
+
+// ParseKey decodes a base64 encoded AES-256 key
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// Sealer encrypts and decrypts profiles with AES-256-GCM, so credentials are
+// never stored in the database in the clear
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// NewSealer creates a sealer for a key from ParseKey
+func NewSealer(key []byte) (*Sealer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+// Seal encrypts a profile. The result starts with the random nonce it was
+// sealed with.
+func (s *Sealer) Seal(profile *Profile) ([]byte, error) {
+	plaintext, err := json.Marshal(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile: %w", err)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return s.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts a profile sealed with Seal
+func (s *Sealer) Open(sealed []byte) (*Profile, error) {
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, fmt.Errorf("sealed profile is too short")
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt profile (was PROBE_AUTH_KEY changed?): %w", err)
+	}
+
+	var profile Profile
+	if err := json.Unmarshal(plaintext, &profile); err != nil {
+		return nil, fmt.Errorf("failed to decode profile: %w", err)
+	}
+	return &profile, nil
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+)
+
+// Formats of a shared scan report
+const (
+	ScanFormatJSON = events.DigestFormatJSON
+	ScanFormatCSV  = events.DigestFormatCSV
+)
+
+// ScanReport is what a client receives when a scan is shared with them: the
+// scan's outcome and the assets it found for the first time. It names the
+// program the scan belongs to but nothing about other programs.
+type ScanReport struct {
+	Program     events.ProgramData `json:"program"`
+	ScanID      uuid.UUID          `json:"scan_id"`
+	Status      string             `json:"status"`
+	StartedAt   time.Time          `json:"started_at"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	AssetsFound int                `json:"assets_found"`
+	AssetsSeen  int                `json:"assets_seen"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	NewAssets   []events.AssetData `json:"new_assets"`
+}
+
+// NewScanReport builds the report of a scan from the assets it found first
+func NewScanReport(program *database.Program, scan *database.Scan, newAssets []*database.Asset, now time.Time) *ScanReport {
+	report := &ScanReport{
+		Program:     events.NewProgramData(program),
+		ScanID:      scan.ID,
+		Status:      scan.Status,
+		StartedAt:   scan.StartedAt,
+		CompletedAt: scan.CompletedAt,
+		AssetsFound: scan.AssetsFound,
+		AssetsSeen:  scan.AssetsSeen,
+		GeneratedAt: now.UTC(),
+		NewAssets:   make([]events.AssetData, len(newAssets)),
+	}
+	for i, asset := range newAssets {
+		report.NewAssets[i] = events.NewAssetData(asset)
+	}
+	return report
+}
+
+// EncodeScanReport renders the report of a scan and returns it with its
+// content type. JSON holds the whole report; CSV only lists the new assets,
+// in the columns of digest attachments.
+func EncodeScanReport(format string, program *database.Program, scan *database.Scan, newAssets []*database.Asset, now time.Time) ([]byte, string, error) {
+	switch format {
+	case ScanFormatJSON:
+		content, err := json.MarshalIndent(NewScanReport(program, scan, newAssets, now), "", "  ")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal scan report: %w", err)
+		}
+		return content, "application/json", nil
+	case ScanFormatCSV:
+		return events.EncodeAssets(events.DigestFormatCSV, newAssets)
+	default:
+		return nil, "", fmt.Errorf("unknown scan report format %q (json or csv)", format)
+	}
+}
+
+// ScanReportKey is the object key the report of a scan is stored under, e.g.
+// monitor-agent/scans/hackerone-acme-<scan id>.json
+func ScanReportKey(prefix string, program *database.Program, scanID uuid.UUID, format string) string {
+	return path.Join(prefix, "scans", events.DigestFilename(events.NewProgramData(program), scanID, format))
+}
@@ -0,0 +1,375 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/version"
+)
+
+// Formats of a scan summary
+const (
+	SummaryFormatMarkdown = "markdown"
+	SummaryFormatHTML     = "html"
+)
+
+// ProgramScan is what one program scan recorded, as a scan summary reads it
+type ProgramScan struct {
+	Program       *database.Program
+	Scan          *database.Scan
+	FirstScan     bool                    // no scan of the program started before this one
+	Scope         *database.ProgramScope  // snapshot the scan recorded; nil when the scope did not change
+	PreviousScope *database.ProgramScope  // snapshot before Scope; nil for the program's first
+	NewAssets     []*database.Asset       // assets the scan found first
+	Changes       []*database.AssetChange // changes against the program's previous completed scan
+	Failures      []*database.DiscoveryFailure
+}
+
+// ScanSummary is the human-readable report of one or more program scans,
+// e.g. those of a scan run: new programs, scope changes, and per program the
+// new and dead assets and the errors
+type ScanSummary struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	StartedAt   time.Time        `json:"started_at"` // of the earliest scan
+	Version     string           `json:"version"`
+	ScanIDs     []uuid.UUID      `json:"scan_ids"`
+	Scans       int              `json:"scans"`
+	Completed   int              `json:"completed"`
+	Failed      int              `json:"failed"` // failed, timed out or aborted
+	NewAssets   int              `json:"new_assets"`
+	DeadAssets  int              `json:"dead_assets"`
+	Errors      int              `json:"errors"`
+	NewPrograms []ProgramRef     `json:"new_programs"`
+	Programs    []ProgramSummary `json:"programs"`  // those with anything to report
+	Unchanged   int              `json:"unchanged"` // programs scanned without anything to report
+}
+
+// ProgramRef names a program
+type ProgramRef struct {
+	Name     string `json:"name"`
+	Platform string `json:"platform"`
+	URL      string `json:"url"`
+}
+
+// ProgramSummary is what a scan found in one program
+type ProgramSummary struct {
+	ProgramRef
+	ScanID       uuid.UUID    `json:"scan_id"`
+	Status       string       `json:"status"`
+	Duration     string       `json:"duration,omitempty"`
+	ScopeAdded   []string     `json:"scope_added,omitempty"`
+	ScopeRemoved []string     `json:"scope_removed,omitempty"`
+	NewAssets    []AssetEntry `json:"new_assets,omitempty"`
+	DeadAssets   []AssetEntry `json:"dead_assets,omitempty"`
+	Errors       []string     `json:"errors,omitempty"`
+}
+
+// AssetEntry is an asset of a program summary with a note on it, e.g. the
+// source that found a new asset or why an asset counts as dead
+type AssetEntry struct {
+	URL  string `json:"url"`
+	Note string `json:"note,omitempty"`
+}
+
+// empty reports whether the program has nothing to report
+func (p *ProgramSummary) empty() bool {
+	return len(p.ScopeAdded) == 0 && len(p.ScopeRemoved) == 0 && len(p.NewAssets) == 0 &&
+		len(p.DeadAssets) == 0 && len(p.Errors) == 0
+}
+
+// NewScanSummary builds the summary of program scans, listing programs with
+// errors first and then by name
+func NewScanSummary(scans []*ProgramScan, now time.Time) *ScanSummary {
+	summary := &ScanSummary{
+		GeneratedAt: now.UTC(),
+		Version:     version.Version,
+		ScanIDs:     []uuid.UUID{},
+		NewPrograms: []ProgramRef{},
+		Programs:    []ProgramSummary{},
+	}
+
+	for _, ps := range scans {
+		scan := ps.Scan
+		summary.Scans++
+		summary.ScanIDs = append(summary.ScanIDs, scan.ID)
+		if summary.StartedAt.IsZero() || scan.StartedAt.Before(summary.StartedAt) {
+			summary.StartedAt = scan.StartedAt.UTC()
+		}
+		switch scan.Status {
+		case "completed":
+			summary.Completed++
+		case "failed", "timed_out", "aborted":
+			summary.Failed++
+		}
+
+		ref := ProgramRef{Name: ps.Program.Name, Platform: ps.Program.Platform, URL: ps.Program.ProgramURL}
+		if ps.FirstScan {
+			summary.NewPrograms = append(summary.NewPrograms, ref)
+		}
+
+		program := newProgramSummary(ref, ps)
+		summary.NewAssets += len(program.NewAssets)
+		summary.DeadAssets += len(program.DeadAssets)
+		summary.Errors += len(program.Errors)
+		if program.empty() {
+			summary.Unchanged++
+			continue
+		}
+		summary.Programs = append(summary.Programs, program)
+	}
+
+	sort.SliceStable(summary.Programs, func(i, j int) bool {
+		a, b := summary.Programs[i], summary.Programs[j]
+		if (len(a.Errors) > 0) != (len(b.Errors) > 0) {
+			return len(a.Errors) > 0
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return summary
+}
+
+// newProgramSummary summarizes the scan of one program
+func newProgramSummary(ref ProgramRef, ps *ProgramScan) ProgramSummary {
+	scan := ps.Scan
+	program := ProgramSummary{ProgramRef: ref, ScanID: scan.ID, Status: scan.Status}
+	if scan.CompletedAt != nil {
+		program.Duration = scan.CompletedAt.Sub(scan.StartedAt).Round(time.Second).String()
+	}
+
+	// A program's first snapshot is only a baseline
+	if ps.Scope != nil && ps.PreviousScope != nil {
+		program.ScopeAdded = difference(ps.Scope.InScope, ps.PreviousScope.InScope)
+		program.ScopeRemoved = difference(ps.PreviousScope.InScope, ps.Scope.InScope)
+	}
+
+	for _, asset := range ps.NewAssets {
+		program.NewAssets = append(program.NewAssets, AssetEntry{URL: asset.URL, Note: strings.Join(asset.Sources(), ", ")})
+	}
+
+	for _, change := range ps.Changes {
+		switch {
+		case change.Change == database.AssetRemoved:
+			program.DeadAssets = append(program.DeadAssets, AssetEntry{URL: change.URL, Note: "no longer found"})
+		case change.Change == database.AssetChanged && change.Field == "liveness" &&
+			answered(change.OldValue) && !answered(change.NewValue):
+			program.DeadAssets = append(program.DeadAssets, AssetEntry{URL: change.URL, Note: change.OldValue + " → " + change.NewValue})
+		}
+	}
+
+	if scan.Error != "" {
+		program.Errors = append(program.Errors, scan.Error)
+	}
+	for _, failure := range ps.Failures {
+		program.Errors = append(program.Errors, fmt.Sprintf("%s: %s failed (%s): %s", failure.Domain, failure.Stage, failure.Source, failure.Error))
+	}
+	return program
+}
+
+// answered reports whether a liveness state means the asset responded
+func answered(liveness string) bool {
+	return liveness == httpx.LivenessLive || liveness == httpx.LivenessWAFBlocked
+}
+
+// difference returns the values of a that are not in b
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, value := range b {
+		in[value] = true
+	}
+	var out []string
+	for _, value := range a {
+		if !in[value] {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+// EncodeScanSummary renders a scan summary as Markdown or as a
+// self-contained HTML page and returns it with its content type
+func EncodeScanSummary(format string, summary *ScanSummary) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case SummaryFormatMarkdown:
+		if err := markdownTemplate.Execute(&buf, summary); err != nil {
+			return nil, "", fmt.Errorf("failed to render scan report: %w", err)
+		}
+		return buf.Bytes(), "text/markdown; charset=utf-8", nil
+	case SummaryFormatHTML:
+		if err := htmlSummaryTemplate.Execute(&buf, summary); err != nil {
+			return nil, "", fmt.Errorf("failed to render scan report: %w", err)
+		}
+		return buf.Bytes(), "text/html; charset=utf-8", nil
+	default:
+		return nil, "", fmt.Errorf("unknown scan report format %q (markdown or html)", format)
+	}
+}
+
+// SummaryFilename is the file name of a scan summary, after the start of its
+// earliest scan, e.g. scan-report-20260102T150405Z.md
+func SummaryFilename(summary *ScanSummary, format string) string {
+	extension := ".md"
+	if format == SummaryFormatHTML {
+		extension = ".html"
+	}
+	return "scan-report-" + summary.StartedAt.UTC().Format("20060102T150405Z") + extension
+}
+
+// SummaryPayload is the JSON body a scan summary is posted to a webhook as.
+// Text holds the rendered report, which chat webhooks such as Slack's or
+// Mattermost's show as the message.
+type SummaryPayload struct {
+	Text        string      `json:"text"`
+	Format      string      `json:"format"`
+	Filename    string      `json:"filename"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	ScanIDs     []uuid.UUID `json:"scan_ids"`
+}
+
+// PostScanSummary posts a rendered scan summary to a webhook, signed like
+// event webhooks when a secret is set
+func PostScanSummary(ctx context.Context, client *resty.Client, url, secret string, summary *ScanSummary, format string, content []byte) error {
+	body, err := json.Marshal(SummaryPayload{
+		Text:        string(content),
+		Format:      format,
+		Filename:    SummaryFilename(summary, format),
+		GeneratedAt: summary.GeneratedAt,
+		ScanIDs:     summary.ScanIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan report: %w", err)
+	}
+
+	req := client.R().SetContext(ctx).SetHeader("Content-Type", "application/json").SetBody(body)
+	if secret != "" {
+		req.SetHeader(events.SignatureHeader, "sha256="+events.Sign(secret, body))
+	}
+
+	resp, err := req.Post(url)
+	if err != nil {
+		return fmt.Errorf("failed to post scan report: %w", err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("scan report webhook returned status %d", resp.StatusCode())
+	}
+	return nil
+}
+
+// summaryFuncs are the helpers of both summary templates
+var summaryFuncs = map[string]any{
+	"timestamp": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+	// code quotes text for a Markdown code span, which has no escapes
+	"code": func(text string) string { return "`" + strings.ReplaceAll(text, "`", "'") + "`" },
+	// line keeps text on one Markdown line
+	"line": func(text string) string { return strings.Join(strings.Fields(text), " ") },
+}
+
+// markdownTemplate is the Markdown scan report
+var markdownTemplate = template.Must(template.New("markdown").Funcs(summaryFuncs).Parse(`# Scan Report
+
+{{.Scans}} program scans started {{timestamp .StartedAt}}: {{.Completed}} completed, {{.Failed}} failed.
+{{.NewAssets}} new assets, {{.DeadAssets}} dead assets, {{.Errors}} errors.
+{{if .NewPrograms}}
+## New Programs
+{{range .NewPrograms}}
+- [{{line .Name}}]({{.URL}}) ({{.Platform}}){{end}}
+{{end}}{{range .Programs}}
+## {{line .Name}} ({{.Platform}})
+
+Scan {{code .ScanID.String}}: {{.Status}}{{if .Duration}} in {{.Duration}}{{end}}
+{{if or .ScopeAdded .ScopeRemoved}}
+### Scope Changes
+{{range .ScopeAdded}}
+- Added {{code .}}{{end}}{{range .ScopeRemoved}}
+- Removed {{code .}}{{end}}
+{{end}}{{if .NewAssets}}
+### New Assets ({{len .NewAssets}})
+{{range .NewAssets}}
+- {{code .URL}}{{if .Note}} ({{.Note}}){{end}}{{end}}
+{{end}}{{if .DeadAssets}}
+### Dead Assets ({{len .DeadAssets}})
+{{range .DeadAssets}}
+- {{code .URL}} ({{.Note}}){{end}}
+{{end}}{{if .Errors}}
+### Errors
+{{range .Errors}}
+- {{line .}}{{end}}
+{{end}}{{end}}{{if .Unchanged}}
+{{.Unchanged}} other programs were scanned without changes or errors.
+{{end}}
+_Generated {{timestamp .GeneratedAt}} by Monitor Agent {{.Version}}_
+`))
+
+// htmlSummaryTemplate is the HTML scan report. It has no external assets so
+// it can be mailed or published as is.
+var htmlSummaryTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(summaryFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Scan Report {{timestamp .StartedAt}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 52rem; padding: 0 1rem; color: #222; }
+.summary { display: flex; gap: 2rem; margin: 1.5rem 0; }
+.summary div { font-size: 0.9rem; color: #555; }
+.summary strong { display: block; font-size: 1.6rem; color: #222; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 0.25rem; }
+code { background: #f4f4f4; padding: 0 0.2rem; }
+.errors li { color: #b71c1c; }
+footer { font-size: 0.8rem; color: #777; }
+</style>
+</head>
+<body>
+<h1>Scan Report</h1>
+<p>{{.Scans}} program scans started {{timestamp .StartedAt}}: {{.Completed}} completed, {{.Failed}} failed.</p>
+
+<div class="summary">
+<div><strong>{{len .NewPrograms}}</strong>new programs</div>
+<div><strong>{{.NewAssets}}</strong>new assets</div>
+<div><strong>{{.DeadAssets}}</strong>dead assets</div>
+<div><strong>{{.Errors}}</strong>errors</div>
+</div>
+
+{{if .NewPrograms}}<h2>New programs</h2>
+<ul>
+{{range .NewPrograms}}<li><a href="{{.URL}}">{{.Name}}</a> ({{.Platform}})</li>
+{{end}}</ul>
+{{end}}
+{{range .Programs}}<h2>{{.Name}} ({{.Platform}})</h2>
+<p>Scan <code>{{.ScanID}}</code>: {{.Status}}{{if .Duration}} in {{.Duration}}{{end}}</p>
+{{if or .ScopeAdded .ScopeRemoved}}<h3>Scope changes</h3>
+<ul>
+{{range .ScopeAdded}}<li>Added <code>{{.}}</code></li>
+{{end}}{{range .ScopeRemoved}}<li>Removed <code>{{.}}</code></li>
+{{end}}</ul>
+{{end}}{{if .NewAssets}}<h3>New assets ({{len .NewAssets}})</h3>
+<ul>
+{{range .NewAssets}}<li><code>{{.URL}}</code>{{if .Note}} ({{.Note}}){{end}}</li>
+{{end}}</ul>
+{{end}}{{if .DeadAssets}}<h3>Dead assets ({{len .DeadAssets}})</h3>
+<ul>
+{{range .DeadAssets}}<li><code>{{.URL}}</code> ({{.Note}})</li>
+{{end}}</ul>
+{{end}}{{if .Errors}}<h3>Errors</h3>
+<ul class="errors">
+{{range .Errors}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{end}}
+{{if .Unchanged}}<p>{{.Unchanged}} other programs were scanned without changes or errors.</p>
+{{end}}
+<footer>Generated {{timestamp .GeneratedAt}} by Monitor Agent {{.Version}}</footer>
+</body>
+</html>
+`))
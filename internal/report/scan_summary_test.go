@@ -0,0 +1,166 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProgramScans() []*ProgramScan {
+	acme, scan, assets := testScanReport()
+	assets[0].FirstSource = "hackerone"
+	assets[1].Provenance = pq.StringArray{"chaosdb", "crtsh"}
+
+	globex := &database.Program{ID: uuid.New(), Name: "Globex", Platform: "bugcrowd", ProgramURL: "https://bugcrowd.com/globex"}
+	failed := &database.Scan{ID: uuid.New(), ProgramID: globex.ID, Status: "failed", Error: "scope\nfetch failed", StartedAt: scan.StartedAt.Add(time.Minute)}
+
+	initech := &database.Program{ID: uuid.New(), Name: "Initech", Platform: "intigriti", ProgramURL: "https://intigriti.com/initech"}
+	quiet := &database.Scan{ID: uuid.New(), ProgramID: initech.ID, Status: "completed", StartedAt: scan.StartedAt.Add(-time.Minute)}
+
+	return []*ProgramScan{
+		{
+			Program:       acme,
+			Scan:          scan,
+			Scope:         &database.ProgramScope{InScope: pq.StringArray{"*.acme.com", "api.acme.com"}},
+			PreviousScope: &database.ProgramScope{InScope: pq.StringArray{"*.acme.com", "old.acme.com"}},
+			NewAssets:     assets,
+			Changes: []*database.AssetChange{
+				{URL: "https://api.acme.com", Change: database.AssetAdded},
+				{URL: "https://gone.acme.com", Change: database.AssetRemoved},
+				{URL: "https://down.acme.com", Change: database.AssetChanged, Field: "liveness", OldValue: "live", NewValue: "refused"},
+				{URL: "https://up.acme.com", Change: database.AssetChanged, Field: "liveness", OldValue: "timed-out", NewValue: "live"},
+				{URL: "https://moved.acme.com", Change: database.AssetChanged, Field: "ip", OldValue: "10.0.0.1", NewValue: "10.0.0.2"},
+			},
+		},
+		{
+			Program:   globex,
+			Scan:      failed,
+			FirstScan: true,
+			Failures:  []*database.DiscoveryFailure{{Domain: "globex.com", Stage: "discovery", Source: "chaosdb", Error: "HTTP 502"}},
+		},
+		{Program: initech, Scan: quiet},
+	}
+}
+
+func TestNewScanSummary(t *testing.T) {
+	now := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	summary := NewScanSummary(testProgramScans(), now)
+
+	assert.Equal(t, 3, summary.Scans)
+	assert.Equal(t, 2, summary.Completed)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, time.Date(2025, 3, 1, 9, 59, 0, 0, time.UTC), summary.StartedAt, "the earliest scan's start")
+	assert.Equal(t, 2, summary.NewAssets)
+	assert.Equal(t, 2, summary.DeadAssets)
+	assert.Equal(t, 2, summary.Errors)
+	assert.Equal(t, 1, summary.Unchanged)
+	assert.Equal(t, []ProgramRef{{Name: "Globex", Platform: "bugcrowd", URL: "https://bugcrowd.com/globex"}}, summary.NewPrograms)
+
+	require.Len(t, summary.Programs, 2)
+	assert.Equal(t, "Globex", summary.Programs[0].Name, "programs with errors come first")
+	assert.Equal(t, []string{"scope\nfetch failed", "globex.com: discovery failed (chaosdb): HTTP 502"}, summary.Programs[0].Errors)
+
+	acme := summary.Programs[1]
+	assert.Equal(t, "10m0s", acme.Duration)
+	assert.Equal(t, []string{"api.acme.com"}, acme.ScopeAdded)
+	assert.Equal(t, []string{"old.acme.com"}, acme.ScopeRemoved)
+	assert.Equal(t, []AssetEntry{{URL: "https://api.acme.com", Note: "hackerone"}, {URL: "https://new.acme.com", Note: "chaosdb, crtsh"}}, acme.NewAssets)
+	assert.Equal(t, []AssetEntry{{URL: "https://gone.acme.com", Note: "no longer found"}, {URL: "https://down.acme.com", Note: "live → refused"}}, acme.DeadAssets)
+}
+
+func TestNewScanSummary_FirstScopeIsBaseline(t *testing.T) {
+	scans := testProgramScans()[:1]
+	scans[0].PreviousScope = nil
+
+	summary := NewScanSummary(scans, time.Now())
+	require.Len(t, summary.Programs, 1)
+	assert.Empty(t, summary.Programs[0].ScopeAdded)
+	assert.Empty(t, summary.Programs[0].ScopeRemoved)
+}
+
+func TestEncodeScanSummary_Markdown(t *testing.T) {
+	summary := NewScanSummary(testProgramScans(), time.Now())
+
+	content, contentType, err := EncodeScanSummary(SummaryFormatMarkdown, summary)
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown; charset=utf-8", contentType)
+
+	report := string(content)
+	assert.Contains(t, report, "3 program scans started 2025-03-01 09:59 UTC: 2 completed, 1 failed.")
+	assert.Contains(t, report, "## New Programs\n\n- [Globex](https://bugcrowd.com/globex) (bugcrowd)")
+	assert.Contains(t, report, "## Acme (hackerone)")
+	assert.Contains(t, report, "- Added `api.acme.com`")
+	assert.Contains(t, report, "### New Assets (2)\n\n- `https://api.acme.com` (hackerone)")
+	assert.Contains(t, report, "- `https://down.acme.com` (live → refused)")
+	assert.Contains(t, report, "- scope fetch failed", "errors stay on one line")
+	assert.Contains(t, report, "1 other programs were scanned without changes or errors.")
+	assert.NotContains(t, report, "Initech")
+}
+
+func TestEncodeScanSummary_HTML(t *testing.T) {
+	scans := testProgramScans()
+	scans[0].Program.Name = "<script>alert(1)</script>"
+	summary := NewScanSummary(scans, time.Now())
+
+	content, contentType, err := EncodeScanSummary(SummaryFormatHTML, summary)
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", contentType)
+
+	page := string(content)
+	assert.Contains(t, page, `<a href="https://bugcrowd.com/globex">Globex</a>`)
+	assert.Contains(t, page, "<li>Removed <code>old.acme.com</code></li>")
+	assert.NotContains(t, page, "<script>", "program names are escaped")
+	assert.NotContains(t, page, "http://", "no external assets")
+
+	_, _, err = EncodeScanSummary("pdf", summary)
+	assert.Error(t, err)
+}
+
+func TestSummaryFilename(t *testing.T) {
+	summary := &ScanSummary{StartedAt: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	assert.Equal(t, "scan-report-20250301T100000Z.md", SummaryFilename(summary, SummaryFormatMarkdown))
+	assert.Equal(t, "scan-report-20250301T100000Z.html", SummaryFilename(summary, SummaryFormatHTML))
+}
+
+func TestPostScanSummary(t *testing.T) {
+	var payload SummaryPayload
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(events.SignatureHeader)
+		assert.Equal(t, "sha256="+events.Sign("secret", body), signature)
+		require.NoError(t, json.Unmarshal(body, &payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	summary := NewScanSummary(testProgramScans(), time.Now())
+	content, _, err := EncodeScanSummary(SummaryFormatMarkdown, summary)
+	require.NoError(t, err)
+
+	err = PostScanSummary(context.Background(), resty.New(), server.URL, "secret", summary, SummaryFormatMarkdown, content)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(payload.Text, "# Scan Report"))
+	assert.Equal(t, SummaryFormatMarkdown, payload.Format)
+	assert.Equal(t, summary.ScanIDs, payload.ScanIDs)
+	assert.NotEmpty(t, signature)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	assert.Error(t, PostScanSummary(context.Background(), resty.New(), failing.URL, "", summary, SummaryFormatMarkdown, content))
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testScanReport() (*database.Program, *database.Scan, []*database.Asset) {
+	program := &database.Program{ID: uuid.New(), Name: "Acme", Platform: "hackerone", ProgramURL: "https://hackerone.com/acme"}
+	started := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	completed := started.Add(10 * time.Minute)
+	scan := &database.Scan{ID: uuid.New(), ProgramID: program.ID, Status: "completed", AssetsFound: 2, AssetsSeen: 40, StartedAt: started, CompletedAt: &completed}
+	assets := []*database.Asset{
+		{ID: uuid.New(), ProgramID: program.ID, URL: "https://api.acme.com", Domain: "acme.com", Subdomain: "api", Status: "active", CreatedAt: completed},
+		{ID: uuid.New(), ProgramID: program.ID, URL: "https://new.acme.com", Domain: "acme.com", Subdomain: "new", Status: "active", CreatedAt: completed},
+	}
+	return program, scan, assets
+}
+
+func TestEncodeScanReport_JSON(t *testing.T) {
+	program, scan, assets := testScanReport()
+	now := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	content, contentType, err := EncodeScanReport(ScanFormatJSON, program, scan, assets, now)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+
+	var decoded ScanReport
+	require.NoError(t, json.Unmarshal(content, &decoded))
+	assert.Equal(t, scan.ID, decoded.ScanID)
+	assert.Equal(t, "Acme", decoded.Program.Name)
+	assert.Equal(t, 40, decoded.AssetsSeen)
+	assert.Equal(t, now, decoded.GeneratedAt)
+	require.Len(t, decoded.NewAssets, 2)
+	assert.Equal(t, "https://new.acme.com", decoded.NewAssets[1].URL)
+}
+
+func TestEncodeScanReport_CSV(t *testing.T) {
+	program, scan, assets := testScanReport()
+
+	content, contentType, err := EncodeScanReport(ScanFormatCSV, program, scan, assets, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", contentType)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 3, "header and one row per new asset")
+	assert.True(t, strings.HasPrefix(lines[1], "https://api.acme.com,acme.com,api,"))
+
+	_, _, err = EncodeScanReport("xml", program, scan, assets, time.Now())
+	assert.Error(t, err)
+}
+
+func TestScanReportKey(t *testing.T) {
+	program, scan, _ := testScanReport()
+
+	assert.Equal(t, "monitor-agent/scans/hackerone-acme-"+scan.ID.String()+".csv", ScanReportKey("monitor-agent/", program, scan.ID, ScanFormatCSV))
+	assert.Equal(t, "scans/hackerone-acme-"+scan.ID.String()+".json", ScanReportKey("", program, scan.ID, ScanFormatJSON))
+}
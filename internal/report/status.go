@@ -0,0 +1,261 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/monitor-agent/internal/service"
+	"github.com/monitor-agent/internal/version"
+)
+
+// File names of a generated status page
+const (
+	HTMLFile = "index.html"
+	JSONFile = "status.json"
+)
+
+// Overall states of a status page
+const (
+	StateOK       = "ok"       // the latest program scans completed
+	StateDegraded = "degraded" // some of the latest program scans failed or timed out
+	StateUnknown  = "unknown"  // no scans have run yet
+)
+
+// Status is the summary published on a status page. It only holds counts and
+// scan times: no program names, hosts, URLs or error messages, so it is safe
+// to publish beyond the team running the agent.
+type Status struct {
+	Title           string           `json:"title"`
+	State           string           `json:"state"`
+	GeneratedAt     time.Time        `json:"generated_at"`
+	Version         string           `json:"version"`
+	Programs        int              `json:"programs"` // active programs monitored
+	Assets          int              `json:"assets"`
+	Platforms       []PlatformStatus `json:"platforms"`
+	Liveness        []LivenessStatus `json:"liveness"`
+	OpenTLSFindings int              `json:"open_tls_findings"`
+	LastScanAt      *time.Time       `json:"last_scan_at,omitempty"`
+	LastCompleteAt  *time.Time       `json:"last_completed_at,omitempty"`
+	RecentScans     []ScanStatus     `json:"recent_scans"`
+}
+
+// PlatformStatus is the number of programs and assets monitored on a platform
+type PlatformStatus struct {
+	Platform string `json:"platform"`
+	Programs int    `json:"programs"`
+	Assets   int    `json:"assets"`
+}
+
+// LivenessStatus is the number of assets in a liveness state
+type LivenessStatus struct {
+	Liveness string `json:"liveness"`
+	Assets   int    `json:"assets"`
+}
+
+// ScanStatus is the outcome of one program scan, without the program
+type ScanStatus struct {
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Duration    string     `json:"duration,omitempty"`
+	AssetsSeen  int        `json:"assets_seen"`
+}
+
+// NewStatus builds a status summary from the program statistics
+func NewStatus(title string, stats *service.ProgramStats, generatedAt time.Time) *Status {
+	status := &Status{
+		Title:       title,
+		State:       StateUnknown,
+		GeneratedAt: generatedAt.UTC(),
+		Version:     version.Version,
+		Programs:    stats.ActivePrograms,
+		Assets:      stats.TotalAssets,
+		Platforms:   []PlatformStatus{},
+		Liveness:    []LivenessStatus{},
+		RecentScans: []ScanStatus{},
+	}
+
+	for _, platform := range stats.Platforms {
+		status.Platforms = append(status.Platforms, PlatformStatus{
+			Platform: platform.Platform,
+			Programs: platform.Programs,
+			Assets:   platform.Assets,
+		})
+	}
+
+	for _, count := range stats.Liveness {
+		liveness := count.Liveness
+		if liveness == "" {
+			liveness = "unprobed"
+		}
+		status.Liveness = append(status.Liveness, LivenessStatus{Liveness: liveness, Assets: count.Assets})
+	}
+
+	for _, count := range stats.TLSFindings {
+		status.OpenTLSFindings += count.Assets
+	}
+
+	for _, scan := range stats.RecentScans {
+		entry := ScanStatus{
+			Status:     scan.Status,
+			StartedAt:  scan.StartedAt.UTC(),
+			AssetsSeen: scan.AssetsSeen,
+		}
+		if scan.CompletedAt != nil {
+			completedAt := scan.CompletedAt.UTC()
+			entry.CompletedAt = &completedAt
+			entry.Duration = completedAt.Sub(entry.StartedAt).Round(time.Second).String()
+
+			if scan.Status == "completed" && (status.LastCompleteAt == nil || completedAt.After(*status.LastCompleteAt)) {
+				status.LastCompleteAt = &completedAt
+			}
+		}
+		if status.LastScanAt == nil || entry.StartedAt.After(*status.LastScanAt) {
+			startedAt := entry.StartedAt
+			status.LastScanAt = &startedAt
+		}
+		status.RecentScans = append(status.RecentScans, entry)
+	}
+
+	status.State = scanState(status.RecentScans)
+	return status
+}
+
+// scanState derives the overall state from the recent program scans. Running,
+// cancelled and deferred scans do not count either way.
+func scanState(scans []ScanStatus) string {
+	state := StateUnknown
+	for _, scan := range scans {
+		switch scan.Status {
+		case "completed":
+			if state == StateUnknown {
+				state = StateOK
+			}
+		case "failed", "timed_out":
+			return StateDegraded
+		}
+	}
+	return state
+}
+
+// WriteHTML renders the status as a self-contained HTML page
+func WriteHTML(w io.Writer, status *Status) error {
+	return pageTemplate.Execute(w, status)
+}
+
+// WriteFiles writes the HTML page and its JSON counterpart into dir. Each file
+// is replaced atomically, so a web server never serves a half-written page.
+func WriteFiles(dir string, status *Status) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create status page directory: %w", err)
+	}
+
+	if err := writeAtomic(filepath.Join(dir, HTMLFile), func(w io.Writer) error {
+		return WriteHTML(w, status)
+	}); err != nil {
+		return err
+	}
+
+	return writeAtomic(filepath.Join(dir, JSONFile), func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(status)
+	})
+}
+
+// writeAtomic writes a file through a temporary file in the same directory
+func writeAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// pageTemplate is the status page. It has no external assets so it can be
+// published from any static file host.
+var pageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"timestamp": func(t *time.Time) string {
+		if t == nil {
+			return "never"
+		}
+		return t.Format("2006-01-02 15:04 UTC")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 52rem; padding: 0 1rem; color: #222; }
+h1 { margin-bottom: 0.25rem; }
+.state { display: inline-block; padding: 0.2rem 0.6rem; border-radius: 0.3rem; color: #fff; font-weight: bold; }
+.state-ok { background: #2e7d32; }
+.state-degraded { background: #e65100; }
+.state-unknown { background: #616161; }
+.summary { display: flex; gap: 2rem; margin: 1.5rem 0; }
+.summary div { font-size: 0.9rem; color: #555; }
+.summary strong { display: block; font-size: 1.6rem; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5rem; }
+th, td { text-align: left; padding: 0.35rem 0.5rem; border-bottom: 1px solid #ddd; }
+footer { font-size: 0.8rem; color: #777; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><span class="state state-{{.State}}">{{.State}}</span></p>
+
+<div class="summary">
+<div><strong>{{.Programs}}</strong>programs monitored</div>
+<div><strong>{{.Assets}}</strong>assets</div>
+<div><strong>{{timestamp .LastScanAt}}</strong>last scan</div>
+<div><strong>{{timestamp .LastCompleteAt}}</strong>last completed scan</div>
+</div>
+
+{{if .Platforms}}<h2>Platforms</h2>
+<table>
+<tr><th>Platform</th><th>Programs</th><th>Assets</th></tr>
+{{range .Platforms}}<tr><td>{{.Platform}}</td><td>{{.Programs}}</td><td>{{.Assets}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Liveness}}<h2>Asset liveness</h2>
+<table>
+<tr><th>State</th><th>Assets</th></tr>
+{{range .Liveness}}<tr><td>{{.Liveness}}</td><td>{{.Assets}}</td></tr>
+{{end}}</table>
+{{end}}
+<p>Open TLS findings: {{.OpenTLSFindings}}</p>
+
+{{if .RecentScans}}<h2>Recent program scans</h2>
+<table>
+<tr><th>Started</th><th>Status</th><th>Duration</th><th>Assets seen</th></tr>
+{{range .RecentScans}}<tr><td>{{.StartedAt.Format "2006-01-02 15:04 UTC"}}</td><td>{{.Status}}</td><td>{{.Duration}}</td><td>{{.AssetsSeen}}</td></tr>
+{{end}}</table>
+{{end}}
+<footer>Generated {{.GeneratedAt.Format "2006-01-02 15:04 UTC"}} by Monitor Agent {{.Version}}</footer>
+</body>
+</html>
+`))
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStats() *service.ProgramStats {
+	started := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	completed := started.Add(90 * time.Second)
+
+	return &service.ProgramStats{
+		TotalPrograms:  3,
+		ActivePrograms: 3,
+		TotalAssets:    120,
+		Platforms: []*service.PlatformCount{
+			{Platform: "bugcrowd", Programs: 1, Assets: 20},
+			{Platform: "hackerone", Programs: 2, Assets: 100},
+		},
+		Liveness:    []*database.LivenessCount{{Liveness: "live", Assets: 80}, {Liveness: "", Assets: 40}},
+		TLSFindings: []*database.TLSFindingCount{{CheckName: "expired-certificate", Severity: "medium", Assets: 2}, {CheckName: "self-signed-certificate", Severity: "low", Assets: 1}},
+		RecentScans: []*database.Scan{
+			{Status: "running", StartedAt: started.Add(time.Hour), Error: "secret.example.com"},
+			{Status: "completed", StartedAt: started, CompletedAt: &completed, AssetsSeen: 50},
+		},
+		Geo: []*service.GeoSummary{{ProgramName: "Secret Program"}},
+	}
+}
+
+func TestNewStatus(t *testing.T) {
+	status := NewStatus("Status", testStats(), time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, StateOK, status.State)
+	assert.Equal(t, 3, status.Programs)
+	assert.Equal(t, 120, status.Assets)
+	assert.Len(t, status.Platforms, 2)
+	assert.Equal(t, []LivenessStatus{{"live", 80}, {"unprobed", 40}}, status.Liveness)
+	assert.Equal(t, 3, status.OpenTLSFindings)
+	require.NotNil(t, status.LastScanAt)
+	assert.Equal(t, "2025-03-01T11:00:00Z", status.LastScanAt.Format(time.RFC3339))
+	require.NotNil(t, status.LastCompleteAt)
+	assert.Equal(t, "2025-03-01T10:01:30Z", status.LastCompleteAt.Format(time.RFC3339))
+	assert.Equal(t, "1m30s", status.RecentScans[1].Duration)
+}
+
+func TestNewStatus_State(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		want     string
+	}{
+		{"no scans", nil, StateUnknown},
+		{"only running", []string{"running"}, StateUnknown},
+		{"completed", []string{"completed", "cancelled"}, StateOK},
+		{"timed out", []string{"completed", "timed_out"}, StateDegraded},
+		{"failed", []string{"failed"}, StateDegraded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &service.ProgramStats{}
+			for _, s := range tt.statuses {
+				stats.RecentScans = append(stats.RecentScans, &database.Scan{Status: s, StartedAt: time.Now()})
+			}
+			assert.Equal(t, tt.want, NewStatus("Status", stats, time.Now()).State)
+		})
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "status")
+	status := NewStatus("Acme <Security>", testStats(), time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	require.NoError(t, WriteFiles(dir, status))
+
+	page, err := os.ReadFile(filepath.Join(dir, HTMLFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "Acme &lt;Security&gt;")
+	assert.Contains(t, string(page), "<td>hackerone</td><td>2</td><td>100</td>")
+	assert.Contains(t, string(page), "2025-03-01 11:00 UTC")
+
+	data, err := os.ReadFile(filepath.Join(dir, JSONFile))
+	require.NoError(t, err)
+	var decoded Status
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, StateOK, decoded.State)
+
+	// Nothing identifying a program or host is published
+	for _, content := range [][]byte{page, data} {
+		assert.False(t, bytes.Contains(content, []byte("secret")), "status page leaks scan errors")
+		assert.False(t, bytes.Contains(content, []byte("Secret Program")), "status page leaks program names")
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "temporary files are cleaned up")
+}
@@ -0,0 +1,185 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the YAML document rules are loaded from
+type File struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Rule runs its actions when every condition it sets matches
+type Rule struct {
+	Name string    `yaml:"name"`
+	When Condition `yaml:"when"`
+	Then Actions   `yaml:"then"`
+
+	urlPattern *regexp.Regexp
+}
+
+// Condition lists the checks a rule makes; unset fields always match and
+// string comparisons are case-insensitive
+type Condition struct {
+	TitleContains  string   `yaml:"title_contains"`
+	BodyContains   string   `yaml:"body_contains"`
+	HeaderContains string   `yaml:"header_contains"` // matched against "Name: value" lines
+	URLMatches     string   `yaml:"url_matches"`     // regular expression
+	Technology     string   `yaml:"technology"`      // matches when any detected technology contains it
+	Status         []int    `yaml:"status"`
+	Sources        []string `yaml:"sources"`
+}
+
+// Actions are what a matching rule asks for
+type Actions struct {
+	Tags    []string `yaml:"tags"`
+	Notify  string   `yaml:"notify"`  // notification channel consumers route the match to
+	Enqueue []string `yaml:"enqueue"` // follow-up jobs, e.g. scanner templates
+}
+
+// Input is the asset and response a rule set is evaluated against
+type Input struct {
+	URL          string
+	Source       string
+	StatusCode   int
+	Title        string
+	Body         string
+	Headers      map[string]string
+	Technologies []string
+}
+
+// Engine evaluates a rule set
+type Engine struct {
+	rules []*Rule
+}
+
+// LoadFile loads and compiles rules from a YAML file
+func LoadFile(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file %s: %w", path, err)
+	}
+
+	return Parse(data)
+}
+
+// Parse compiles rules from YAML
+func Parse(data []byte) (*Engine, error) {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	return NewEngine(file.Rules)
+}
+
+// NewEngine validates and compiles rules
+func NewEngine(rules []*Rule) (*Engine, error) {
+	names := make(map[string]bool)
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.When.isEmpty() {
+			return nil, fmt.Errorf("rule %q has no conditions", rule.Name)
+		}
+		if len(rule.Then.Tags) == 0 && rule.Then.Notify == "" && len(rule.Then.Enqueue) == 0 {
+			return nil, fmt.Errorf("rule %q has no actions", rule.Name)
+		}
+
+		if rule.When.URLMatches != "" {
+			pattern, err := regexp.Compile(rule.When.URLMatches)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q has an invalid url_matches: %w", rule.Name, err)
+			}
+			rule.urlPattern = pattern
+		}
+	}
+
+	return &Engine{rules: rules}, nil
+}
+
+// Rules returns the loaded rules
+func (e *Engine) Rules() []*Rule {
+	if e == nil {
+		return nil
+	}
+	return e.rules
+}
+
+// Evaluate returns the rules whose conditions all match the input
+func (e *Engine) Evaluate(input *Input) []*Rule {
+	if e == nil {
+		return nil
+	}
+
+	var matched []*Rule
+	for _, rule := range e.rules {
+		if rule.matches(input) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// isEmpty reports whether a condition sets no checks
+func (c *Condition) isEmpty() bool {
+	return c.TitleContains == "" && c.BodyContains == "" && c.HeaderContains == "" &&
+		c.URLMatches == "" && c.Technology == "" && len(c.Status) == 0 && len(c.Sources) == 0
+}
+
+// matches reports whether every condition of the rule matches the input
+func (r *Rule) matches(input *Input) bool {
+	when := r.When
+
+	if len(when.Status) > 0 && !slices.Contains(when.Status, input.StatusCode) {
+		return false
+	}
+	if len(when.Sources) > 0 && !slices.ContainsFunc(when.Sources, func(source string) bool {
+		return strings.EqualFold(source, input.Source)
+	}) {
+		return false
+	}
+	if !containsFold(input.Title, when.TitleContains) || !containsFold(input.Body, when.BodyContains) {
+		return false
+	}
+	if when.HeaderContains != "" && !headersContain(input.Headers, when.HeaderContains) {
+		return false
+	}
+	if r.urlPattern != nil && !r.urlPattern.MatchString(input.URL) {
+		return false
+	}
+	if when.Technology != "" && !slices.ContainsFunc(input.Technologies, func(technology string) bool {
+		return containsFold(technology, when.Technology)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// headersContain reports whether any "Name: value" header line contains substr
+func headersContain(headers map[string]string, substr string) bool {
+	for name, value := range headers {
+		if containsFold(name+": "+value, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether s contains substr, ignoring case; an empty substr always matches
+func containsFold(s, substr string) bool {
+	return substr == "" || strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exampleRules = `
+rules:
+  - name: grafana
+    when:
+      title_contains: grafana
+      status: [200]
+    then:
+      tags: [grafana]
+      notify: slack-critical
+      enqueue: [nuclei-grafana]
+  - name: staging
+    when:
+      url_matches: '(?i)(stg|staging)\.'
+    then:
+      tags: [staging]
+  - name: exposed-jenkins
+    when:
+      header_contains: "x-jenkins"
+      sources: [chaosdb]
+    then:
+      notify: slack-critical
+`
+
+func TestParse_Evaluate(t *testing.T) {
+	engine, err := Parse([]byte(exampleRules))
+	require.NoError(t, err)
+	require.Len(t, engine.Rules(), 3)
+
+	tests := []struct {
+		name  string
+		input *Input
+		want  []string
+	}{
+		{
+			name:  "grafana login",
+			input: &Input{URL: "https://grafana.example.com", StatusCode: 200, Title: "Grafana"},
+			want:  []string{"grafana"},
+		},
+		{
+			name:  "grafana behind auth",
+			input: &Input{URL: "https://grafana.example.com", StatusCode: 401, Title: "Grafana"},
+			want:  nil,
+		},
+		{
+			name:  "staging grafana",
+			input: &Input{URL: "https://grafana.STG.example.com", StatusCode: 200, Title: "Welcome to Grafana"},
+			want:  []string{"grafana", "staging"},
+		},
+		{
+			name: "jenkins from chaosdb",
+			input: &Input{URL: "https://ci.example.com", Source: "ChaosDB", StatusCode: 403,
+				Headers: map[string]string{"X-Jenkins": "2.414"}},
+			want: []string{"exposed-jenkins"},
+		},
+		{
+			name: "jenkins from scope",
+			input: &Input{URL: "https://ci.example.com", Source: "primary", StatusCode: 403,
+				Headers: map[string]string{"X-Jenkins": "2.414"}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, rule := range engine.Evaluate(tt.input) {
+				names = append(names, rule.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestEvaluate_Technology(t *testing.T) {
+	engine, err := NewEngine([]*Rule{{
+		Name: "wordpress",
+		When: Condition{Technology: "wordpress"},
+		Then: Actions{Tags: []string{"cms"}},
+	}})
+	require.NoError(t, err)
+
+	assert.Len(t, engine.Evaluate(&Input{Technologies: []string{"PHP", "WordPress:6.4"}}), 1)
+	assert.Empty(t, engine.Evaluate(&Input{Technologies: []string{"Nginx"}}))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"missing name", "rules:\n  - when: {status: [200]}\n    then: {tags: [a]}\n"},
+		{"duplicate name", "rules:\n  - name: a\n    when: {status: [200]}\n    then: {tags: [a]}\n  - name: a\n    when: {status: [200]}\n    then: {tags: [a]}\n"},
+		{"no conditions", "rules:\n  - name: a\n    then: {tags: [a]}\n"},
+		{"no actions", "rules:\n  - name: a\n    when: {status: [200]}\n"},
+		{"bad regex", "rules:\n  - name: a\n    when: {url_matches: '('}\n    then: {tags: [a]}\n"},
+		{"bad yaml", "rules: ["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNilEngine(t *testing.T) {
+	var engine *Engine
+	assert.Nil(t, engine.Evaluate(&Input{}))
+	assert.Nil(t, engine.Rules())
+}
@@ -0,0 +1,255 @@
+// Package schemadrift detects when platform API payloads stop matching the
+// structs they are decoded into. encoding/json silently drops unknown fields
+// and leaves missing ones zero, so a changed payload shape otherwise only
+// shows up as less data.
+package schemadrift
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of drift
+const (
+	ChangeAdded   = "added"   // the payload has a field the struct does not decode
+	ChangeMissing = "missing" // a field the struct decodes is absent from every payload object
+)
+
+// Drift is a field of a platform payload that changed shape
+type Drift struct {
+	Platform string `json:"platform"`
+	Payload  string `json:"payload"` // name of the endpoint's payload, e.g. structured_scopes
+	Field    string `json:"field"`   // dotted field path; [] marks array elements, e.g. data[].attributes.handle
+	Change   string `json:"change"`
+}
+
+// Compare returns the fields of a JSON payload that were added or are missing
+// compared to the struct expected decodes into. Fields whose json tag has
+// omitempty, and pointer fields, are optional and never reported missing. Map
+// and json.RawMessage fields accept any content.
+func Compare(body []byte, expected any) ([]Drift, error) {
+	comparison := NewComparison()
+	if err := comparison.Add("", body, expected); err != nil {
+		return nil, err
+	}
+	return comparison.Drifts(), nil
+}
+
+// Comparison compares a payload that is decoded in parts, such as a response
+// whose array elements are streamed one at a time, so the payload never has
+// to be held in memory. It only keeps the field paths it has seen.
+type Comparison struct {
+	w *walker
+}
+
+// NewComparison creates an empty comparison
+func NewComparison() *Comparison {
+	return &Comparison{w: &walker{added: make(map[string]bool), present: make(map[string]bool), absent: make(map[string]bool)}}
+}
+
+// Add compares a part of the payload found at path, e.g. data[] for an element
+// of the data array, with the struct expected decodes into
+func (c *Comparison) Add(path string, body []byte, expected any) error {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return err
+	}
+	c.w.walk(payload, reflect.TypeOf(expected), path)
+	return nil
+}
+
+// Drifts returns the drift of the parts added so far
+func (c *Comparison) Drifts() []Drift {
+	var drifts []Drift
+	for field := range c.w.added {
+		drifts = append(drifts, Drift{Field: field, Change: ChangeAdded})
+	}
+	for field := range c.w.absent {
+		if !c.w.present[field] {
+			drifts = append(drifts, Drift{Field: field, Change: ChangeMissing})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Field != drifts[j].Field {
+			return drifts[i].Field < drifts[j].Field
+		}
+		return drifts[i].Change < drifts[j].Change
+	})
+	return drifts
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// walker walks a decoded payload alongside the type it is decoded into
+type walker struct {
+	added   map[string]bool
+	present map[string]bool // expected fields found in at least one object
+	absent  map[string]bool // expected fields not found in at least one object
+}
+
+func (w *walker) walk(value any, t reflect.Type, path string) {
+	if t == nil {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType || t == rawMessageType {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		known := make(map[string]bool)
+		for _, field := range jsonFields(t) {
+			known[field.name] = true
+			fieldPath := joinPath(path, field.name)
+			child, found := object[field.name]
+			if !found {
+				if !field.optional {
+					w.absent[fieldPath] = true
+				}
+				continue
+			}
+			w.present[fieldPath] = true
+			w.walk(child, field.typ, fieldPath)
+		}
+		for key := range object {
+			if !known[key] {
+				w.added[joinPath(path, key)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			w.walk(item, t.Elem(), path+"[]")
+		}
+	}
+}
+
+// jsonField is a field of a struct as encoding/json sees it
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	optional bool
+}
+
+// jsonFields returns the fields encoding/json decodes into a struct type,
+// including the fields of embedded structs
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			embedded := fieldType
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, jsonFields(embedded)...)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{
+			name:     name,
+			typ:      fieldType,
+			optional: strings.Contains(","+options+",", ",omitempty,") || fieldType.Kind() == reflect.Pointer,
+		})
+	}
+	return fields
+}
+
+// joinPath appends a field name to a dotted path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Detector reports drift in platform payloads. Each drift is logged once and
+// kept until it is drained, so a scan can record it.
+type Detector struct {
+	mu      sync.Mutex
+	seen    map[Drift]bool
+	pending []Drift
+}
+
+// NewDetector creates a detector
+func NewDetector() *Detector {
+	return &Detector{seen: make(map[Drift]bool)}
+}
+
+// Default is the detector the platform clients report to
+var Default = NewDetector()
+
+// Check compares a payload received from a platform with the struct it is
+// decoded into. Payloads that are not JSON are left to the decoder to reject.
+func (d *Detector) Check(platform, payload string, body []byte, expected any) {
+	drifts, err := Compare(body, expected)
+	if err != nil {
+		return
+	}
+	d.Report(platform, payload, drifts)
+}
+
+// Report records drift found by a Comparison of a platform payload
+func (d *Detector) Report(platform, payload string, drifts []Drift) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, drift := range drifts {
+		drift.Platform, drift.Payload = platform, payload
+		if d.seen[drift] {
+			continue
+		}
+		d.seen[drift] = true
+		d.pending = append(d.pending, drift)
+		logrus.Warnf("Schema drift in %s %s payload: field %s is %s", platform, payload, drift.Field, drift.Change)
+	}
+}
+
+// Drain returns the drift found since the last drain
+func (d *Detector) Drain() []Drift {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	drifts := d.pending
+	d.pending = nil
+	return drifts
+}
+
+// Check reports a payload to the default detector
+func Check(platform, payload string, body []byte, expected any) {
+	Default.Check(platform, payload, body, expected)
+}
+
+// Report records drift with the default detector
+func Report(platform, payload string, drifts []Drift) {
+	Default.Report(platform, payload, drifts)
+}
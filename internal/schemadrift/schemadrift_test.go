@@ -0,0 +1,111 @@
+package schemadrift
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testResponse struct {
+	Data  []testItem `json:"data"`
+	Links struct {
+		Next string `json:"next,omitempty"`
+	} `json:"links"`
+}
+
+type testItem struct {
+	ID         string            `json:"id"`
+	Attributes testAttributes    `json:"attributes"`
+	Extra      map[string]string `json:"extra,omitempty"`
+	Raw        json.RawMessage   `json:"raw,omitempty"`
+}
+
+type testAttributes struct {
+	Handle    string    `json:"handle"`
+	Website   *string   `json:"website"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected []Drift
+	}{
+		{
+			name:    "matching payload",
+			payload: `{"data":[{"id":"1","attributes":{"handle":"acme","updated_at":"2024-01-01T00:00:00Z"},"extra":{"any":"thing"},"raw":{"x":1}}],"links":{}}`,
+		},
+		{
+			name:    "added fields are reported at the first unknown key",
+			payload: `{"data":[{"id":"1","attributes":{"handle":"acme","updated_at":"2024-01-01T00:00:00Z","bounty":{"min":1}}}],"links":{},"meta":{}}`,
+			expected: []Drift{
+				{Field: "data[].attributes.bounty", Change: ChangeAdded},
+				{Field: "meta", Change: ChangeAdded},
+			},
+		},
+		{
+			name:    "missing fields are reported when no element has them",
+			payload: `{"data":[{"id":"1","attributes":{"updated_at":"2024-01-01T00:00:00Z"}},{"attributes":{"updated_at":"2024-01-01T00:00:00Z"}}]}`,
+			expected: []Drift{
+				{Field: "data[].attributes.handle", Change: ChangeMissing},
+				{Field: "links", Change: ChangeMissing},
+			},
+		},
+		{
+			name:    "renamed field",
+			payload: `{"data":[{"id":"1","attributes":{"handle_name":"acme","updated_at":"2024-01-01T00:00:00Z"}}],"links":{}}`,
+			expected: []Drift{
+				{Field: "data[].attributes.handle", Change: ChangeMissing},
+				{Field: "data[].attributes.handle_name", Change: ChangeAdded},
+			},
+		},
+		{
+			name:    "empty arrays say nothing about their elements",
+			payload: `{"data":[],"links":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drifts, err := Compare([]byte(tt.payload), testResponse{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, drifts)
+		})
+	}
+
+	_, err := Compare([]byte("<html>"), testResponse{})
+	assert.Error(t, err)
+}
+
+func TestComparison_Parts(t *testing.T) {
+	whole := `{"data":[{"id":"1","attributes":{"updated_at":"2024-01-01T00:00:00Z","bounty":1}},{"attributes":{"handle":"acme","updated_at":"2024-01-01T00:00:00Z"}}],"meta":{}}`
+	expected, err := Compare([]byte(whole), testResponse{})
+	require.NoError(t, err)
+
+	// The envelope and each element compared on their own find the same drift
+	comparison := NewComparison()
+	require.NoError(t, comparison.Add("", []byte(`{"data":[],"meta":{}}`), testResponse{}))
+	require.NoError(t, comparison.Add("data[]", []byte(`{"id":"1","attributes":{"updated_at":"2024-01-01T00:00:00Z","bounty":1}}`), testItem{}))
+	require.NoError(t, comparison.Add("data[]", []byte(`{"attributes":{"handle":"acme","updated_at":"2024-01-01T00:00:00Z"}}`), testItem{}))
+
+	assert.Equal(t, expected, comparison.Drifts())
+	assert.Error(t, comparison.Add("data[]", []byte("<html>"), testItem{}))
+}
+
+func TestDetector_Check(t *testing.T) {
+	detector := NewDetector()
+	payload := []byte(`{"data":[{"id":"1","attributes":{"handle":"acme","updated_at":"2024-01-01T00:00:00Z","new":true}}],"links":{}}`)
+
+	detector.Check("hackerone", "programs", payload, testResponse{})
+	detector.Check("hackerone", "programs", payload, testResponse{})
+
+	assert.Equal(t, []Drift{{Platform: "hackerone", Payload: "programs", Field: "data[].attributes.new", Change: ChangeAdded}}, detector.Drain())
+	assert.Empty(t, detector.Drain(), "drift is reported once")
+
+	detector.Check("bugcrowd", "programs", []byte("not json"), testResponse{})
+	assert.Empty(t, detector.Drain())
+}
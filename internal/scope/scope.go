@@ -0,0 +1,242 @@
+// Package scope matches hosts and addresses against the scope entries of a
+// bug bounty program: exact URLs, wildcards and IP ranges. The same matcher
+// classifies assets as in scope and filters out-of-scope hosts, so both
+// agree on what an entry covers.
+package scope
+
+import (
+	"net"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/monitor-agent/internal/platforms"
+)
+
+// Scope entry types a matcher understands; entries of other types, such as
+// mobile apps or source code, cover no host
+const (
+	TypeURL      = "url"
+	TypeWildcard = "wildcard"
+	TypeCIDR     = "cidr" // HackerOne CIDR ranges
+	TypeIP       = "ip"   // BugCrowd and Intigriti addresses and ranges
+)
+
+// Matcher matches hosts and their resolved addresses against scope entries
+type Matcher struct {
+	hosts     map[string]bool // hosts of URL entries
+	wildcards []wildcard
+	networks  []*net.IPNet // IP range entries, and URL entries of a bare address
+
+	// wildcardRoots makes *.example.com also cover example.com
+	wildcardRoots bool
+}
+
+// wildcard is a wildcard pattern split into its labels
+type wildcard struct {
+	labels   []string // labels after a leading *, or every label
+	anyDepth bool     // the pattern starts with *., which covers one or more labels
+}
+
+// NewMatcher creates a matcher of scope entries for filtering out-of-scope
+// hosts.
+//
+// A URL entry covers its host and every subdomain of it, whatever its path.
+// A wildcard starting with *. covers the subdomains of the rest at any depth,
+// *.example.com covering api.example.com and a.b.example.com but not
+// example.com; any other * matches within one label, so api-*.example.com
+// covers api-v2.example.com but not api.v2.example.com. A CIDR or IP entry
+// covers an address literal in the host and hosts resolving into it.
+func NewMatcher(entries []*platforms.ScopeAsset) *Matcher {
+	m := &Matcher{hosts: make(map[string]bool)}
+
+	for _, entry := range entries {
+		switch entry.Type {
+		case TypeURL:
+			host := normalizeHost(entry.URL)
+			if ip := net.ParseIP(host); ip != nil {
+				m.networks = append(m.networks, addressNetwork(ip))
+			} else if host != "" {
+				m.hosts[host] = true
+			}
+		case TypeWildcard:
+			if w, ok := parseWildcard(entry); ok {
+				m.wildcards = append(m.wildcards, w)
+			}
+		case TypeCIDR, TypeIP:
+			if network := parseNetwork(entry.URL); network != nil {
+				m.networks = append(m.networks, network)
+			}
+		}
+	}
+
+	return m
+}
+
+// NewInScopeMatcher creates a matcher of in-scope entries. It differs from
+// NewMatcher in that a wildcard also covers its root: discovery enumerates
+// *.example.com from example.com and keeps example.com as a primary asset.
+func NewInScopeMatcher(entries []*platforms.ScopeAsset) *Matcher {
+	m := NewMatcher(entries)
+	m.wildcardRoots = true
+	return m
+}
+
+// Empty reports whether no entry covers any host
+func (m *Matcher) Empty() bool {
+	return len(m.hosts) == 0 && len(m.wildcards) == 0 && len(m.networks) == 0
+}
+
+// Match reports whether an entry covers target, a URL or host, or one of the
+// addresses it resolved to
+func (m *Matcher) Match(target string, ips ...string) bool {
+	host := normalizeHost(target)
+	if ip := net.ParseIP(host); ip != nil {
+		return m.matchIP(ip)
+	}
+	if host != "" && (m.matchHost(host) || m.matchWildcard(host)) {
+		return true
+	}
+
+	for _, value := range ips {
+		if ip := net.ParseIP(strings.TrimSpace(value)); ip != nil && m.matchIP(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHost reports whether a URL entry lists host or a parent domain of it
+func (m *Matcher) matchHost(host string) bool {
+	for parent := host; ; {
+		if m.hosts[parent] {
+			return true
+		}
+		_, rest, ok := strings.Cut(parent, ".")
+		if !ok {
+			return false
+		}
+		parent = rest
+	}
+}
+
+// matchWildcard reports whether a wildcard entry covers host
+func (m *Matcher) matchWildcard(host string) bool {
+	labels := strings.Split(host, ".")
+	for _, w := range m.wildcards {
+		if w.matches(labels, m.wildcardRoots) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIP reports whether an IP entry contains ip
+func (m *Matcher) matchIP(ip net.IP) bool {
+	for _, network := range m.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the labels of a host match the pattern; root
+// makes a *. pattern also match the rest of it
+func (w wildcard) matches(labels []string, root bool) bool {
+	if w.anyDepth {
+		if len(labels) < len(w.labels) || (len(labels) == len(w.labels) && !root) {
+			return false
+		}
+		labels = labels[len(labels)-len(w.labels):]
+	} else if len(labels) != len(w.labels) {
+		return false
+	}
+
+	for i, pattern := range w.labels {
+		if !matchLabel(pattern, labels[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLabel matches one host label against one pattern label, in which *
+// matches any run of characters
+func matchLabel(pattern, label string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == label
+	}
+	matched, err := path.Match(pattern, label)
+	return err == nil && matched
+}
+
+// parseWildcard reads the pattern of a wildcard entry. Platforms keep the
+// pattern as published in OriginalPattern and its base domain in URL; an
+// entry without a pattern covers the subdomains of its base domain.
+func parseWildcard(entry *platforms.ScopeAsset) (wildcard, bool) {
+	pattern := normalizeHost(entry.OriginalPattern)
+	if pattern == "" {
+		pattern = normalizeHost(entry.URL)
+		if pattern == "" {
+			return wildcard{}, false
+		}
+		if !strings.HasPrefix(pattern, "*") {
+			pattern = "*." + pattern
+		}
+	}
+
+	rest, anyDepth := strings.CutPrefix(pattern, "*.")
+	if rest == "" {
+		return wildcard{}, false
+	}
+	return wildcard{labels: strings.Split(rest, "."), anyDepth: anyDepth}, true
+}
+
+// parseNetwork reads a CIDR range or a single address
+func parseNetwork(value string) *net.IPNet {
+	value = strings.TrimSpace(value)
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		return network
+	}
+	if ip := net.ParseIP(value); ip != nil {
+		return addressNetwork(ip)
+	}
+	return nil
+}
+
+// addressNetwork returns the network of one address
+func addressNetwork(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// normalizeHost reduces a URL, host or pattern to a lowercase hostname,
+// dropping any scheme, path, port, IPv6 brackets and trailing dot
+func normalizeHost(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+
+	if strings.Contains(value, "://") {
+		// Wildcards are not valid URL hosts, so they are cut out by hand
+		if !strings.Contains(value, "*") {
+			u, err := url.Parse(value)
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSuffix(u.Hostname(), ".")
+		}
+		_, value, _ = strings.Cut(value, "://")
+	}
+
+	value, _, _ = strings.Cut(value, "/")
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	return strings.TrimSuffix(value, ".")
+}
@@ -0,0 +1,71 @@
+package scope
+
+import (
+	"testing"
+
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	m := NewMatcher([]*platforms.ScopeAsset{
+		{URL: "https://forbidden.example.com/admin", Type: TypeURL},
+		{URL: "https://192.0.2.10", Type: TypeURL},
+		{URL: "https://sub.domain.com", Type: TypeWildcard, OriginalPattern: "*.sub.domain.com"},
+		{URL: "https://example.org", Type: TypeWildcard, OriginalPattern: "api-*.example.org"},
+		{URL: "https://example.net", Type: TypeWildcard},
+		{URL: "198.51.100.0/24", Type: TypeCIDR},
+		{URL: "2001:db8::/32", Type: TypeIP},
+		{URL: "203.0.113.7", Type: TypeIP},
+		{URL: "com.example.app", Type: "GOOGLE_PLAY_APP_ID"},
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		ips    []string
+		want   bool
+	}{
+		{"URL entry host", "https://forbidden.example.com", nil, true},
+		{"URL entry subdomain", "nested.forbidden.example.com", nil, true},
+		{"URL entry sibling", "allowed.example.com", nil, false},
+		{"URL entry of an address", "http://192.0.2.10:8080/", nil, true},
+		{"wildcard direct subdomain", "test.sub.domain.com", nil, true},
+		{"wildcard nested subdomain", "https://test.test.sub.domain.com", nil, true},
+		{"wildcard root", "https://sub.domain.com", nil, false},
+		{"wildcard lookalike", "evilsub.domain.com", nil, false},
+		{"label wildcard", "api-v2.example.org", nil, true},
+		{"label wildcard spans one label", "api.v2.example.org", nil, false},
+		{"wildcard without a pattern", "www.example.net", nil, true},
+		{"CIDR address literal", "https://198.51.100.20", nil, true},
+		{"CIDR resolved address", "cdn.example.io", []string{"10.0.0.1", "198.51.100.99"}, true},
+		{"CIDR address outside", "cdn.example.io", []string{"198.51.101.1"}, false},
+		{"IPv6 range", "https://[2001:db8::1]:443", nil, true},
+		{"single address", "mail.example.io", []string{"203.0.113.7"}, true},
+		{"no resolved address", "cdn.example.io", nil, false},
+		{"app entries cover no host", "com.example.app", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, m.Match(tt.target, tt.ips...))
+		})
+	}
+}
+
+func TestNewInScopeMatcher(t *testing.T) {
+	entries := []*platforms.ScopeAsset{
+		{URL: "https://example.com", Type: TypeWildcard, OriginalPattern: "*.example.com"},
+	}
+
+	// Discovery keeps a wildcard's root, so it is in scope but not excluded by an out-of-scope wildcard
+	assert.True(t, NewInScopeMatcher(entries).Match("https://example.com"))
+	assert.True(t, NewInScopeMatcher(entries).Match("https://a.b.example.com"))
+	assert.False(t, NewMatcher(entries).Match("https://example.com"))
+}
+
+func TestMatcher_Empty(t *testing.T) {
+	assert.True(t, NewMatcher(nil).Empty())
+	assert.True(t, NewMatcher([]*platforms.ScopeAsset{{URL: "not a range", Type: TypeCIDR}}).Empty())
+	assert.False(t, NewMatcher([]*platforms.ScopeAsset{{URL: "10.0.0.0/8", Type: TypeCIDR}}).Empty())
+}
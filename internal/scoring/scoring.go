@@ -0,0 +1,316 @@
+// Package scoring ranks assets by how interesting they are to test. A model
+// gives each signal of an asset (being live, answering 401, carrying a tag
+// and so on) a weight; overrides change the weights for some programs or for
+// assets with some tags, e.g. weighting exposed admin panels higher for
+// fintech programs.
+package scoring
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Signals an asset can score on. Every tag is a signal of its own, named
+// tag:<tag>.
+const (
+	SignalLive        = "live"         // the latest probe got an HTTP answer
+	SignalWAFBlocked  = "waf_blocked"  // the latest probe was answered by a WAF or bot challenge
+	SignalAuth        = "auth"         // the latest response was 401 or 403
+	SignalServerError = "server_error" // the latest response was a 5xx
+	SignalTLSFinding  = "tls_finding"  // per open TLS finding
+	SignalRuleMatch   = "rule_match"   // per triage rule that matched
+	SignalAPISchema   = "api_schema"   // per API schema found
+	SignalNew         = "new"          // first found within new_days
+	SignalIgnored     = "ignored"      // excluded with assets update --ignore
+
+	tagSignalPrefix = "tag:"
+)
+
+// DefaultNewDays is how long an asset counts as new when a model does not say
+const DefaultNewDays = 7
+
+// Signals returns the built-in signals, without the tag signals
+func Signals() []string {
+	return []string{SignalLive, SignalWAFBlocked, SignalAuth, SignalServerError, SignalTLSFinding,
+		SignalRuleMatch, SignalAPISchema, SignalNew, SignalIgnored}
+}
+
+// TagSignal is the signal of an asset tag
+func TagSignal(tag string) string {
+	return tagSignalPrefix + strings.ToLower(tag)
+}
+
+// DefaultWeights is the model used when no scoring file is configured
+func DefaultWeights() Weights {
+	return Weights{
+		SignalLive:        10,
+		SignalWAFBlocked:  2,
+		SignalAuth:        8,
+		SignalServerError: 4,
+		SignalTLSFinding:  3,
+		SignalRuleMatch:   6,
+		SignalAPISchema:   8,
+		SignalNew:         5,
+		SignalIgnored:     -100,
+	}
+}
+
+// Weights maps signals to the points an asset gets for each
+type Weights map[string]float64
+
+// File is the YAML document a scoring model is loaded from
+type File struct {
+	NewDays   int         `yaml:"new_days" json:"new_days"`
+	Weights   Weights     `yaml:"weights" json:"weights"`     // replace the default weights they name
+	Overrides []*Override `yaml:"overrides" json:"overrides"` // applied in order, later ones winning
+}
+
+// Override changes weights for the assets of some programs or the assets
+// carrying some tags. An override that lists both applies to assets matching
+// either.
+type Override struct {
+	Name     string   `yaml:"name" json:"name"`
+	Programs []string `yaml:"programs" json:"programs"` // program URLs or handles
+	Tags     []string `yaml:"tags" json:"tags"`
+	Weights  Weights  `yaml:"weights" json:"weights"`
+}
+
+// Input is what an asset is scored on
+type Input struct {
+	ProgramURL  string
+	Liveness    string
+	StatusCode  int // of the latest stored response; 0 when there is none
+	Tags        []string
+	TLSFindings int
+	RuleMatches int
+	APISchemas  int
+	AgeDays     int // days since the asset was first found
+	Ignored     bool
+}
+
+// Model scores assets
+type Model struct {
+	newDays     int
+	weights     Weights
+	overrides   []*Override
+	fingerprint string
+}
+
+// LoadFile loads a scoring model from a YAML file
+func LoadFile(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scoring file %s: %w", path, err)
+	}
+
+	return Parse(data)
+}
+
+// Parse loads a scoring model from YAML
+func Parse(data []byte) (*Model, error) {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse scoring file: %w", err)
+	}
+
+	return NewModel(&file)
+}
+
+// Default returns the built-in model
+func Default() *Model {
+	model, _ := NewModel(&File{})
+	return model
+}
+
+// NewModel validates a scoring file and builds its model on top of the
+// default weights
+func NewModel(file *File) (*Model, error) {
+	if file.NewDays < 0 {
+		return nil, fmt.Errorf("new_days must not be negative")
+	}
+	if err := validateWeights(file.Weights); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for i, override := range file.Overrides {
+		if override.Name == "" {
+			return nil, fmt.Errorf("override %d has no name", i+1)
+		}
+		if names[override.Name] {
+			return nil, fmt.Errorf("duplicate override name %q", override.Name)
+		}
+		names[override.Name] = true
+
+		if len(override.Programs) == 0 && len(override.Tags) == 0 {
+			return nil, fmt.Errorf("override %q applies to no programs or tags", override.Name)
+		}
+		if len(override.Weights) == 0 {
+			return nil, fmt.Errorf("override %q sets no weights", override.Name)
+		}
+		if err := validateWeights(override.Weights); err != nil {
+			return nil, fmt.Errorf("override %q: %w", override.Name, err)
+		}
+	}
+
+	model := &Model{
+		newDays:   file.NewDays,
+		weights:   DefaultWeights().merge(file.Weights),
+		overrides: file.Overrides,
+	}
+	if model.newDays == 0 {
+		model.newDays = DefaultNewDays
+	}
+
+	// The fingerprint tells scores computed with this model apart from those
+	// of an earlier configuration
+	canonical, err := json.Marshal(struct {
+		NewDays   int         `json:"new_days"`
+		Weights   Weights     `json:"weights"`
+		Overrides []*Override `json:"overrides"`
+	}{model.newDays, model.weights, model.overrides})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint scoring model: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	model.fingerprint = hex.EncodeToString(sum[:])[:16]
+
+	return model, nil
+}
+
+// Fingerprint identifies the model's configuration
+func (m *Model) Fingerprint() string {
+	return m.fingerprint
+}
+
+// Overrides returns the model's overrides
+func (m *Model) Overrides() []*Override {
+	return m.overrides
+}
+
+// WeightsFor returns the weights used for an asset of a program carrying tags
+func (m *Model) WeightsFor(programURL string, tags []string) Weights {
+	weights := m.weights
+	for _, override := range m.overrides {
+		if override.applies(programURL, tags) {
+			weights = weights.merge(override.Weights)
+		}
+	}
+	return weights
+}
+
+// Score scores an asset. Scores are rounded to two decimals.
+func (m *Model) Score(input *Input) float64 {
+	weights := m.WeightsFor(input.ProgramURL, input.Tags)
+
+	var score float64
+	add := func(signal string, times int) {
+		score += weights[signal] * float64(times)
+	}
+
+	switch input.Liveness {
+	case "live":
+		add(SignalLive, 1)
+	case "waf-blocked":
+		add(SignalWAFBlocked, 1)
+	}
+	switch {
+	case input.StatusCode == 401 || input.StatusCode == 403:
+		add(SignalAuth, 1)
+	case input.StatusCode >= 500 && input.StatusCode < 600:
+		add(SignalServerError, 1)
+	}
+	add(SignalTLSFinding, input.TLSFindings)
+	add(SignalRuleMatch, input.RuleMatches)
+	add(SignalAPISchema, input.APISchemas)
+	if input.AgeDays < m.newDays {
+		add(SignalNew, 1)
+	}
+	if input.Ignored {
+		add(SignalIgnored, 1)
+	}
+	for _, tag := range uniqueTags(input.Tags) {
+		add(TagSignal(tag), 1)
+	}
+
+	return math.Round(score*100) / 100
+}
+
+// merge returns a copy of the weights with the other weights set over them
+func (w Weights) merge(other Weights) Weights {
+	merged := make(Weights, len(w)+len(other))
+	for signal, weight := range w {
+		merged[signal] = weight
+	}
+	for signal, weight := range other {
+		merged[strings.ToLower(signal)] = weight
+	}
+	return merged
+}
+
+// Sorted returns the weighted signals in name order
+func (w Weights) Sorted() []string {
+	signals := make([]string, 0, len(w))
+	for signal := range w {
+		signals = append(signals, signal)
+	}
+	sort.Strings(signals)
+	return signals
+}
+
+// applies reports whether an override applies to an asset of a program
+// carrying tags
+func (o *Override) applies(programURL string, tags []string) bool {
+	program := strings.TrimRight(strings.ToLower(programURL), "/")
+	handle := program[strings.LastIndex(program, "/")+1:]
+	for _, value := range o.Programs {
+		value = strings.TrimRight(strings.ToLower(value), "/")
+		if value == program || (!strings.Contains(value, "/") && value == handle) {
+			return true
+		}
+	}
+
+	return slices.ContainsFunc(o.Tags, func(tag string) bool {
+		return slices.ContainsFunc(tags, func(assetTag string) bool {
+			return strings.EqualFold(tag, assetTag)
+		})
+	})
+}
+
+// validateWeights checks every weighted signal is known
+func validateWeights(weights Weights) error {
+	for signal := range weights {
+		lower := strings.ToLower(signal)
+		if tag, ok := strings.CutPrefix(lower, tagSignalPrefix); ok {
+			if tag == "" {
+				return fmt.Errorf("signal %q names no tag", signal)
+			}
+			continue
+		}
+		if !slices.Contains(Signals(), lower) {
+			return fmt.Errorf("unknown signal %q (use %s or tag:<tag>)", signal, strings.Join(Signals(), ", "))
+		}
+	}
+	return nil
+}
+
+// uniqueTags lowercases tags and drops duplicates, so a tag scores once
+func uniqueTags(tags []string) []string {
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if !slices.Contains(unique, tag) {
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
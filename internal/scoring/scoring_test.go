@@ -0,0 +1,125 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exampleModel = `
+new_days: 14
+weights:
+  live: 5
+  tag:admin-panel: 20
+overrides:
+  - name: fintech
+    programs: [https://hackerone.com/acme-bank, paybuddy]
+    weights:
+      tag:admin-panel: 50
+  - name: internal
+    tags: [vpn]
+    weights:
+      live: 15
+`
+
+func TestParse_Score(t *testing.T) {
+	model, err := Parse([]byte(exampleModel))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input *Input
+		want  float64
+	}{
+		{
+			name:  "live admin panel",
+			input: &Input{ProgramURL: "https://hackerone.com/shop", Liveness: "live", Tags: []string{"admin-panel"}, AgeDays: 30},
+			want:  5 + 20,
+		},
+		{
+			name:  "admin panel of a fintech program",
+			input: &Input{ProgramURL: "https://hackerone.com/acme-bank/", Liveness: "live", Tags: []string{"Admin-Panel"}, AgeDays: 30},
+			want:  5 + 50,
+		},
+		{
+			name:  "fintech program by handle",
+			input: &Input{ProgramURL: "https://bugcrowd.com/paybuddy", Tags: []string{"admin-panel", "admin-panel"}, AgeDays: 30},
+			want:  50,
+		},
+		{
+			name:  "tag override",
+			input: &Input{ProgramURL: "https://hackerone.com/shop", Liveness: "live", Tags: []string{"vpn"}, AgeDays: 30},
+			want:  15,
+		},
+		{
+			name:  "default weights fill in",
+			input: &Input{StatusCode: 401, TLSFindings: 2, RuleMatches: 1, APISchemas: 1, AgeDays: 3},
+			want:  8 + 2*3 + 6 + 8 + 5,
+		},
+		{
+			name:  "server error",
+			input: &Input{StatusCode: 503, AgeDays: 14},
+			want:  4,
+		},
+		{
+			name:  "ignored",
+			input: &Input{Liveness: "live", Ignored: true, AgeDays: 30},
+			want:  5 - 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, model.Score(tt.input))
+		})
+	}
+}
+
+func TestNewModel_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"unknown signal", "weights: {alive: 5}"},
+		{"empty tag", "weights: {'tag:': 5}"},
+		{"negative new days", "new_days: -1"},
+		{"unnamed override", "overrides: [{programs: [acme], weights: {live: 1}}]"},
+		{"duplicate override", "overrides: [{name: a, tags: [x], weights: {live: 1}}, {name: a, tags: [y], weights: {live: 1}}]"},
+		{"override without target", "overrides: [{name: a, weights: {live: 1}}]"},
+		{"override without weights", "overrides: [{name: a, tags: [x]}]"},
+		{"override with unknown signal", "overrides: [{name: a, tags: [x], weights: {alive: 1}}]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestModel_Fingerprint(t *testing.T) {
+	model, err := Parse([]byte(exampleModel))
+	require.NoError(t, err)
+	again, err := Parse([]byte(exampleModel))
+	require.NoError(t, err)
+
+	assert.Len(t, model.Fingerprint(), 16)
+	assert.Equal(t, model.Fingerprint(), again.Fingerprint(), "the same configuration keeps its fingerprint")
+	assert.NotEqual(t, model.Fingerprint(), Default().Fingerprint())
+
+	changed, err := Parse([]byte(exampleModel + "\n  - name: extra\n    tags: [x]\n    weights: {live: 1}\n"))
+	require.NoError(t, err)
+	assert.NotEqual(t, model.Fingerprint(), changed.Fingerprint())
+}
+
+func TestModel_WeightsFor(t *testing.T) {
+	model, err := Parse([]byte(exampleModel))
+	require.NoError(t, err)
+
+	weights := model.WeightsFor("https://hackerone.com/acme-bank", []string{"vpn"})
+	assert.Equal(t, 50.0, weights[TagSignal("admin-panel")])
+	assert.Equal(t, 15.0, weights[SignalLive])
+	assert.Equal(t, DefaultWeights()[SignalAuth], weights[SignalAuth])
+}
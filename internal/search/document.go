@@ -0,0 +1,99 @@
+package search
+
+import (
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+)
+
+// Document is the search mirror of an asset and its latest response.
+// Documents are keyed by asset ID, so each asset has one document that is
+// replaced whenever a new response is captured.
+type Document struct {
+	AssetID      uuid.UUID `json:"asset_id"`
+	ResponseID   uuid.UUID `json:"response_id"`
+	ProgramID    uuid.UUID `json:"program_id"`
+	ProgramURL   string    `json:"program_url"`
+	URL          string    `json:"url"`
+	Domain       string    `json:"domain"`
+	Subdomain    string    `json:"subdomain,omitempty"`
+	IP           string    `json:"ip,omitempty"`
+	IPv6         string    `json:"ipv6,omitempty"`
+	Status       string    `json:"status"`
+	Source       string    `json:"source"`
+	Liveness     string    `json:"liveness,omitempty"`
+	StatusCode   int       `json:"status_code"`
+	Title        string    `json:"title,omitempty"`
+	Server       string    `json:"server,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Technologies []string  `json:"technologies,omitempty"`
+	Headers      []string  `json:"headers,omitempty"` // "Name: value", sorted
+	BodyExcerpt  string    `json:"body_excerpt,omitempty"`
+	ResponseTime int64     `json:"response_time"`
+	CapturedAt   time.Time `json:"captured_at"`
+}
+
+// NewDocument builds the search document for a saved response, keeping at
+// most excerptBytes of the body
+func NewDocument(asset *database.Asset, response *database.AssetResponse, result *httpx.DetailedProbeResult, excerptBytes int) *Document {
+	capturedAt := response.CreatedAt
+	if capturedAt.IsZero() {
+		capturedAt = time.Now()
+	}
+
+	return &Document{
+		AssetID:      asset.ID,
+		ResponseID:   response.ID,
+		ProgramID:    asset.ProgramID,
+		ProgramURL:   asset.ProgramURL,
+		URL:          asset.URL,
+		Domain:       asset.Domain,
+		Subdomain:    asset.Subdomain,
+		IP:           asset.IP,
+		IPv6:         asset.IPv6,
+		Status:       asset.Status,
+		Source:       asset.Source,
+		Liveness:     result.Liveness,
+		StatusCode:   response.StatusCode,
+		Title:        result.Title,
+		Server:       result.Server,
+		ContentType:  result.ContentType,
+		Technologies: result.Technologies,
+		Headers:      headerLines(result.Headers),
+		BodyExcerpt:  excerpt(response.Body, excerptBytes),
+		ResponseTime: response.ResponseTime,
+		CapturedAt:   capturedAt,
+	}
+}
+
+// headerLines flattens headers to sorted "Name: value" lines, which keeps the
+// index mapping fixed no matter which headers servers send
+func headerLines(headers map[string]string) []string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(headers))
+	for name, value := range headers {
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// excerpt truncates body to at most limit bytes without splitting a UTF-8 rune
+func excerpt(body string, limit int) string {
+	if limit <= 0 || len(body) <= limit {
+		return body
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut]
+}
@@ -0,0 +1,201 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+)
+
+// indexMapping keeps identifiers and enumerations as keywords and the
+// recon-relevant text as full-text fields
+const indexMapping = `{
+  "mappings": {
+    "properties": {
+      "asset_id":      {"type": "keyword"},
+      "response_id":   {"type": "keyword"},
+      "program_id":    {"type": "keyword"},
+      "program_url":   {"type": "keyword"},
+      "url":           {"type": "keyword"},
+      "domain":        {"type": "keyword"},
+      "subdomain":     {"type": "keyword"},
+      "ip":            {"type": "keyword"},
+      "ipv6":          {"type": "keyword"},
+      "status":        {"type": "keyword"},
+      "source":        {"type": "keyword"},
+      "liveness":      {"type": "keyword"},
+      "status_code":   {"type": "integer"},
+      "title":         {"type": "text", "fields": {"raw": {"type": "keyword", "ignore_above": 256}}},
+      "server":        {"type": "keyword"},
+      "content_type":  {"type": "keyword"},
+      "technologies":  {"type": "keyword"},
+      "headers":       {"type": "text", "fields": {"raw": {"type": "keyword", "ignore_above": 512}}},
+      "body_excerpt":  {"type": "text"},
+      "response_time": {"type": "long"},
+      "captured_at":   {"type": "date"}
+    }
+  }
+}`
+
+// Indexer mirrors response documents into an OpenSearch or Elasticsearch
+// index. Postgres remains the source of truth; the index can be dropped and
+// rebuilt at any time.
+type Indexer struct {
+	httpClient *resty.Client
+	url        string
+	index      string
+}
+
+// Config holds configuration for the indexer
+type Config struct {
+	URL           string
+	Index         string
+	Username      string
+	Password      string
+	Timeout       time.Duration
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// NewIndexer creates a new indexer
+func NewIndexer(config *Config) *Indexer {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetRetryCount(config.RetryAttempts)
+	client.SetRetryWaitTime(config.RetryDelay)
+	client.SetRetryMaxWaitTime(config.RetryDelay * 2)
+
+	client.SetHeaders(map[string]string{
+		"Accept":     "application/json",
+		"User-Agent": version.UserAgent(),
+	})
+	if config.Username != "" {
+		client.SetBasicAuth(config.Username, config.Password)
+	}
+
+	return &Indexer{
+		httpClient: client,
+		url:        strings.TrimSuffix(config.URL, "/"),
+		index:      config.Index,
+	}
+}
+
+// Index returns the name of the index documents are written to
+func (i *Indexer) Index() string {
+	return i.index
+}
+
+// EnsureIndex creates the index with its mapping if it does not exist yet
+func (i *Indexer) EnsureIndex(ctx context.Context) error {
+	resp, err := i.httpClient.R().SetContext(ctx).Head(i.url + "/" + i.index)
+	if err != nil {
+		return fmt.Errorf("failed to check index %s: %w", i.index, err)
+	}
+	if resp.StatusCode() == 200 {
+		return nil
+	}
+
+	resp, err = i.httpClient.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(indexMapping).
+		Put(i.url + "/" + i.index)
+	if err != nil {
+		return fmt.Errorf("failed to create index %s: %w", i.index, err)
+	}
+
+	// Another agent may have created the index in the meantime
+	if resp.StatusCode() == 400 && strings.Contains(resp.String(), "resource_already_exists_exception") {
+		return nil
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("failed to create index %s: status %d: %s", i.index, resp.StatusCode(), resp.String())
+	}
+
+	return nil
+}
+
+// bulkResponse is the part of the _bulk response needed to detect failed items
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// IndexDocuments writes documents with a single _bulk request
+func (i *Indexer) IndexDocuments(ctx context.Context, docs []*Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	body, err := bulkBody(i.index, docs)
+	if err != nil {
+		return err
+	}
+
+	resp, err := i.httpClient.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/x-ndjson").
+		SetBody(body).
+		Post(i.url + "/_bulk")
+	if err != nil {
+		return fmt.Errorf("failed to index documents: %w", err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("bulk index returned status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result bulkResponse
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	failed := 0
+	var firstErr string
+	for _, item := range result.Items {
+		for _, action := range item {
+			if action.Error != nil {
+				if failed == 0 {
+					firstErr = fmt.Sprintf("%s: %s: %s", action.ID, action.Error.Type, action.Error.Reason)
+				}
+				failed++
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to index %d/%d documents (first error %s)", failed, len(docs), firstErr)
+}
+
+// bulkBody encodes documents as _bulk index actions keyed by asset ID
+func bulkBody(index string, docs []*Document) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+
+	for _, doc := range docs {
+		action := map[string]map[string]string{
+			"index": {"_index": index, "_id": doc.AssetID.String()},
+		}
+		if err := encoder.Encode(action); err != nil {
+			return nil, fmt.Errorf("failed to encode bulk action: %w", err)
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode document %s: %w", doc.URL, err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
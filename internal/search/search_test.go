@@ -0,0 +1,121 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocument(t *testing.T) {
+	asset := &database.Asset{
+		ID:         uuid.New(),
+		ProgramID:  uuid.New(),
+		ProgramURL: "https://hackerone.com/acme",
+		URL:        "https://admin.acme.com",
+		Domain:     "acme.com",
+		Subdomain:  "admin",
+		Status:     "active",
+		Source:     "secondary",
+	}
+	response := &database.AssetResponse{
+		ID:         uuid.New(),
+		StatusCode: 200,
+		Body:       "<html><title>Grafana</title>héllo</html>",
+		CreatedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	result := &httpx.DetailedProbeResult{
+		Title:   "Grafana",
+		Headers: map[string]string{"Server": "nginx", "Content-Type": "text/html"},
+	}
+
+	doc := NewDocument(asset, response, result, 30)
+	assert.Equal(t, asset.ID, doc.AssetID)
+	assert.Equal(t, "Grafana", doc.Title)
+	assert.Equal(t, []string{"Content-Type: text/html", "Server: nginx"}, doc.Headers)
+	// The excerpt is cut before the two-byte é rather than inside it
+	assert.Equal(t, "<html><title>Grafana</title>h", doc.BodyExcerpt)
+	assert.Equal(t, response.CreatedAt, doc.CapturedAt)
+}
+
+func TestIndexer_IndexDocuments(t *testing.T) {
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "elastic", user)
+		assert.Equal(t, "secret", pass)
+
+		body, _ := io.ReadAll(r.Body)
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		w.Write([]byte(`{"errors": false, "items": []}`))
+	}))
+	defer server.Close()
+
+	indexer := NewIndexer(&Config{URL: server.URL + "/", Index: "responses", Username: "elastic", Password: "secret", Timeout: 5 * time.Second})
+	doc := &Document{AssetID: uuid.New(), URL: "https://admin.acme.com"}
+
+	require.NoError(t, indexer.IndexDocuments(context.Background(), []*Document{doc}))
+	require.Len(t, lines, 2)
+
+	var action map[string]map[string]string
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &action))
+	assert.Equal(t, "responses", action["index"]["_index"])
+	assert.Equal(t, doc.AssetID.String(), action["index"]["_id"])
+	assert.Contains(t, lines[1], `"url":"https://admin.acme.com"`)
+}
+
+func TestIndexer_IndexDocuments_ItemErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors": true, "items": [
+			{"index": {"_id": "a", "status": 201}},
+			{"index": {"_id": "b", "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "bad field"}}}
+		]}`))
+	}))
+	defer server.Close()
+
+	indexer := NewIndexer(&Config{URL: server.URL, Index: "responses", Timeout: 5 * time.Second})
+	docs := []*Document{{AssetID: uuid.New()}, {AssetID: uuid.New()}}
+
+	err := indexer.IndexDocuments(context.Background(), docs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1/2")
+	assert.Contains(t, err.Error(), "mapper_parsing_exception")
+}
+
+func TestIndexer_EnsureIndex(t *testing.T) {
+	created := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/responses", r.URL.Path)
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case http.MethodPut:
+			created = true
+			body, _ := io.ReadAll(r.Body)
+			assert.Contains(t, string(body), `"body_excerpt"`)
+			w.Write([]byte(`{"acknowledged": true}`))
+		}
+	}))
+	defer server.Close()
+
+	indexer := NewIndexer(&Config{URL: server.URL, Index: "responses", Timeout: 5 * time.Second})
+	require.NoError(t, indexer.EnsureIndex(context.Background()))
+	assert.True(t, created)
+}
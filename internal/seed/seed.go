@@ -0,0 +1,368 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/sirupsen/logrus"
+)
+
+// Options controls how much synthetic data is generated
+type Options struct {
+	Programs          int
+	AssetsPerProgram  int
+	ResponsesPerAsset int
+	Seed              int64
+}
+
+// Result summarizes what a seed run wrote to the database
+type Result struct {
+	Programs  int
+	Assets    int
+	Responses int
+	Scans     int
+}
+
+// ProgramData holds the synthetic records generated for a single program
+type ProgramData struct {
+	Program   *database.Program
+	Assets    []*database.Asset
+	Responses [][]*database.AssetResponse // indexed like Assets
+	Scan      *database.Scan
+}
+
+var (
+	platforms = []string{"hackerone", "bugcrowd"}
+
+	companyPrefixes = []string{
+		"acme", "globex", "initech", "umbrella", "hooli", "stark", "wayne", "wonka",
+		"cyberdyne", "tyrell", "soylent", "vandelay", "aperture", "oscorp", "monarch",
+		"massive", "pied", "dunder", "gringotts", "nakatomi",
+	}
+
+	companySuffixes = []string{"corp", "labs", "systems", "cloud", "pay", "health", "media", "games", "bank", "io"}
+
+	tlds = []string{"com", "io", "net", "co", "dev", "app"}
+
+	subdomainWords = []string{
+		"www", "api", "app", "admin", "dev", "staging", "test", "mail", "portal", "auth",
+		"login", "sso", "cdn", "static", "assets", "img", "docs", "blog", "shop", "status",
+		"vpn", "git", "jira", "grafana", "kibana", "jenkins", "ci", "internal", "beta", "m",
+	}
+
+	environments = []string{"", "dev", "stg", "qa", "uat", "prod", "eu", "us", "ap"}
+
+	servers = []string{"nginx", "cloudflare", "Apache", "AmazonS3", "Microsoft-IIS/10.0", "envoy", "gunicorn"}
+
+	titles = []string{"Home", "Login", "Dashboard", "404 Not Found", "Welcome", "API Documentation", "Sign in", "Access Denied"}
+)
+
+// statusWeights approximates the status code distribution seen in real probes
+var statusWeights = []struct {
+	code   int
+	weight int
+}{
+	{200, 55}, {301, 10}, {302, 10}, {401, 5}, {403, 10}, {404, 6}, {500, 2}, {502, 1}, {503, 1},
+}
+
+// Generator produces realistic synthetic programs, assets, responses and scans
+type Generator struct {
+	rng   *rand.Rand
+	runID string
+	now   time.Time
+}
+
+// NewGenerator creates a new deterministic generator for the given seed
+func NewGenerator(seed int64) *Generator {
+	rng := rand.New(rand.NewSource(seed))
+	return &Generator{
+		rng:   rng,
+		runID: fmt.Sprintf("%06x", rng.Intn(1<<24)),
+		now:   time.Now(),
+	}
+}
+
+// GenerateProgram builds the synthetic records for the program at the given index
+func (g *Generator) GenerateProgram(index, assetCount, responsesPerAsset int) *ProgramData {
+	prefix := companyPrefixes[g.rng.Intn(len(companyPrefixes))]
+	suffix := companySuffixes[g.rng.Intn(len(companySuffixes))]
+	platform := platforms[g.rng.Intn(len(platforms))]
+	handle := fmt.Sprintf("seed-%s-%s-%s-%d", g.runID, prefix, suffix, index)
+	rootDomain := fmt.Sprintf("%s%s-%s-%d.%s", prefix, suffix, g.runID, index, tlds[g.rng.Intn(len(tlds))])
+
+	programURL := fmt.Sprintf("https://hackerone.com/%s", handle)
+	if platform == "bugcrowd" {
+		programURL = fmt.Sprintf("https://bugcrowd.com/%s", handle)
+	}
+
+	program := &database.Program{
+		Name:       fmt.Sprintf("%s %s", titleCase(prefix), titleCase(suffix)),
+		Platform:   platform,
+		URL:        programURL,
+		ProgramURL: programURL,
+		IsActive:   g.rng.Intn(10) != 0, // ~10% inactive programs
+	}
+
+	data := &ProgramData{Program: program}
+	for _, asset := range g.generateAssets(program, rootDomain, assetCount) {
+		data.Assets = append(data.Assets, asset)
+		data.Responses = append(data.Responses, g.generateResponses(asset, responsesPerAsset))
+	}
+
+	data.Scan = g.generateScan(len(data.Assets))
+	return data
+}
+
+// generateAssets builds primary scope assets followed by discovered secondary assets
+func (g *Generator) generateAssets(program *database.Program, rootDomain string, count int) []*database.Asset {
+	assets := make([]*database.Asset, 0, count)
+	seen := make(map[string]bool)
+
+	add := func(host, source, firstSource string) {
+		url := "https://" + host
+		if seen[url] || len(assets) >= count {
+			return
+		}
+		seen[url] = true
+
+		subdomain := ""
+		if host != rootDomain {
+			subdomain = strings.TrimSuffix(host, "."+rootDomain)
+		}
+
+		status := "active"
+		if g.rng.Intn(8) == 0 {
+			status = "inactive"
+		}
+
+		assets = append(assets, &database.Asset{
+			ProgramURL:  program.ProgramURL,
+			URL:         url,
+			Domain:      rootDomain,
+			Subdomain:   subdomain,
+			IP:          g.randomIP(),
+			Status:      status,
+			Source:      source,
+			FirstSource: firstSource,
+		})
+	}
+
+	// Primary assets mirror what a platform scope would list
+	add(rootDomain, "primary", program.Platform)
+	add("www."+rootDomain, "primary", program.Platform)
+	add("api."+rootDomain, "primary", program.Platform)
+
+	// Secondary assets mirror subdomains found through discovery
+	for attempts := 0; len(assets) < count && attempts < count*20; attempts++ {
+		add(g.randomSubdomain()+"."+rootDomain, "secondary", "chaosdb")
+	}
+
+	return assets
+}
+
+// randomSubdomain builds a plausible subdomain label such as api-stg or eu.portal2
+func (g *Generator) randomSubdomain() string {
+	word := subdomainWords[g.rng.Intn(len(subdomainWords))]
+	env := environments[g.rng.Intn(len(environments))]
+
+	switch g.rng.Intn(4) {
+	case 0:
+		return word
+	case 1:
+		if env == "" {
+			return fmt.Sprintf("%s%d", word, g.rng.Intn(20)+1)
+		}
+		return fmt.Sprintf("%s-%s", word, env)
+	case 2:
+		if env == "" {
+			return word
+		}
+		return fmt.Sprintf("%s.%s", env, word)
+	default:
+		return fmt.Sprintf("%s%d", word, g.rng.Intn(100)+1)
+	}
+}
+
+// randomIP returns an address from the RFC 5737 documentation ranges
+func (g *Generator) randomIP() string {
+	ranges := []string{"192.0.2", "198.51.100", "203.0.113"}
+	return fmt.Sprintf("%s.%d", ranges[g.rng.Intn(len(ranges))], g.rng.Intn(254)+1)
+}
+
+// generateResponses builds probe responses for an asset
+func (g *Generator) generateResponses(asset *database.Asset, count int) []*database.AssetResponse {
+	responses := make([]*database.AssetResponse, 0, count)
+	for i := 0; i < count; i++ {
+		statusCode := g.randomStatusCode()
+		server := servers[g.rng.Intn(len(servers))]
+		title := titles[g.rng.Intn(len(titles))]
+
+		headers := map[string]string{
+			"Content-Type": "text/html; charset=utf-8",
+			"Server":       server,
+			"Date":         g.now.Add(-time.Duration(g.rng.Intn(30*24)) * time.Hour).UTC().Format(time.RFC1123),
+		}
+		if statusCode == 301 || statusCode == 302 {
+			headers["Location"] = fmt.Sprintf("https://www.%s/", asset.Domain)
+		}
+		if g.rng.Intn(3) == 0 {
+			headers["Strict-Transport-Security"] = "max-age=31536000; includeSubDomains"
+		}
+
+		headersJSON, err := json.Marshal(headers)
+		if err != nil {
+			logrus.Warnf("Failed to marshal seed headers: %v", err)
+			headersJSON = []byte("{}")
+		}
+
+		responses = append(responses, &database.AssetResponse{
+			StatusCode:   statusCode,
+			Headers:      string(headersJSON),
+			Body:         fmt.Sprintf("<html><head><title>%s</title></head><body><h1>%s</h1><p>%s</p></body></html>", title, title, asset.URL),
+			ResponseTime: int64(g.rng.Intn(1500) + 20),
+		})
+	}
+	return responses
+}
+
+// randomStatusCode picks a status code using statusWeights
+func (g *Generator) randomStatusCode() int {
+	total := 0
+	for _, w := range statusWeights {
+		total += w.weight
+	}
+
+	n := g.rng.Intn(total)
+	for _, w := range statusWeights {
+		if n < w.weight {
+			return w.code
+		}
+		n -= w.weight
+	}
+	return 200
+}
+
+// generateScan builds a finished scan record for a program
+func (g *Generator) generateScan(assetsFound int) *database.Scan {
+	scan := &database.Scan{
+		Status:      "completed",
+		AssetsFound: assetsFound,
+		AssetsSeen:  assetsFound,
+	}
+	if g.rng.Intn(15) == 0 {
+		scan.Status = "failed"
+		scan.Error = "context deadline exceeded"
+		scan.AssetsFound = 0
+		scan.AssetsSeen = 0
+	}
+	return scan
+}
+
+// titleCase upper-cases the first letter of a word
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Seeder writes generated data to the database through the repositories
+type Seeder struct {
+	programRepo *database.ProgramRepository
+	assetRepo   *database.AssetRepository
+	scanRepo    *database.ScanRepository
+}
+
+// NewSeeder creates a new seeder
+func NewSeeder(db *sqlx.DB) *Seeder {
+	return &Seeder{
+		programRepo: database.NewProgramRepository(db),
+		assetRepo:   database.NewAssetRepository(db),
+		scanRepo:    database.NewScanRepository(db),
+	}
+}
+
+// Run generates and persists synthetic data according to opts
+func (s *Seeder) Run(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Programs <= 0 {
+		return nil, fmt.Errorf("programs must be greater than 0")
+	}
+	if opts.AssetsPerProgram < 0 {
+		return nil, fmt.Errorf("assets per program must not be negative")
+	}
+	if opts.ResponsesPerAsset < 0 {
+		return nil, fmt.Errorf("responses per asset must not be negative")
+	}
+
+	generator := NewGenerator(opts.Seed)
+	result := &Result{}
+
+	for i := 0; i < opts.Programs; i++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		data := generator.GenerateProgram(i, opts.AssetsPerProgram, opts.ResponsesPerAsset)
+		if err := s.persist(ctx, data, result); err != nil {
+			return result, fmt.Errorf("failed to seed program %s: %w", data.Program.Name, err)
+		}
+
+		logrus.Debugf("Seeded program %s (%s) with %d assets", data.Program.Name, data.Program.Platform, len(data.Assets))
+	}
+
+	return result, nil
+}
+
+// persist writes a single program's synthetic data
+func (s *Seeder) persist(ctx context.Context, data *ProgramData, result *Result) error {
+	if err := s.programRepo.CreateProgram(ctx, data.Program); err != nil {
+		return err
+	}
+	result.Programs++
+
+	if !data.Program.IsActive {
+		if err := s.programRepo.MarkProgramInactive(ctx, data.Program.ID); err != nil {
+			return err
+		}
+	}
+
+	data.Scan.ProgramID = data.Program.ID
+	if err := s.scanRepo.CreateScan(ctx, data.Scan); err != nil {
+		return err
+	}
+	completedAt := time.Now()
+	data.Scan.CompletedAt = &completedAt
+	if err := s.scanRepo.UpdateScan(ctx, data.Scan); err != nil {
+		return err
+	}
+	result.Scans++
+
+	for _, asset := range data.Assets {
+		asset.ProgramID = data.Program.ID
+		asset.FirstScanID = &data.Scan.ID
+	}
+	if len(data.Assets) > 0 {
+		if err := s.assetRepo.CreateAssets(ctx, data.Assets); err != nil {
+			return err
+		}
+		result.Assets += len(data.Assets)
+	}
+
+	for i, asset := range data.Assets {
+		for _, response := range data.Responses[i] {
+			response.AssetID = asset.ID
+			if err := s.assetRepo.CreateAssetResponse(ctx, response); err != nil {
+				return err
+			}
+			result.Responses++
+		}
+	}
+
+	return nil
+}
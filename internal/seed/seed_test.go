@@ -0,0 +1,68 @@
+package seed
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_GenerateProgram(t *testing.T) {
+	g := NewGenerator(42)
+	data := g.GenerateProgram(0, 50, 2)
+
+	require.NotNil(t, data.Program)
+	assert.Contains(t, []string{"hackerone", "bugcrowd"}, data.Program.Platform)
+	assert.True(t, strings.HasPrefix(data.Program.ProgramURL, "https://"))
+	assert.Len(t, data.Assets, 50)
+	assert.Len(t, data.Responses, 50)
+	require.NotNil(t, data.Scan)
+
+	seen := make(map[string]bool)
+	primary := 0
+	for i, asset := range data.Assets {
+		assert.False(t, seen[asset.URL], "duplicate asset URL %s", asset.URL)
+		seen[asset.URL] = true
+		assert.True(t, strings.HasSuffix(strings.TrimPrefix(asset.URL, "https://"), asset.Domain))
+		assert.Equal(t, data.Program.ProgramURL, asset.ProgramURL)
+		assert.Len(t, data.Responses[i], 2)
+		if asset.Source == "primary" {
+			primary++
+		} else {
+			assert.Equal(t, "secondary", asset.Source)
+		}
+	}
+	assert.Equal(t, 3, primary)
+}
+
+func TestGenerator_Deterministic(t *testing.T) {
+	a := NewGenerator(7).GenerateProgram(3, 20, 1)
+	b := NewGenerator(7).GenerateProgram(3, 20, 1)
+
+	assert.Equal(t, a.Program.ProgramURL, b.Program.ProgramURL)
+	for i := range a.Assets {
+		assert.Equal(t, a.Assets[i].URL, b.Assets[i].URL)
+		assert.Equal(t, a.Responses[i][0].StatusCode, b.Responses[i][0].StatusCode)
+	}
+}
+
+func TestGenerator_SmallAssetCount(t *testing.T) {
+	data := NewGenerator(1).GenerateProgram(0, 1, 0)
+	assert.Len(t, data.Assets, 1)
+	assert.Empty(t, data.Responses[0])
+
+	data = NewGenerator(1).GenerateProgram(0, 0, 0)
+	assert.Empty(t, data.Assets)
+}
+
+func TestSeeder_Run_InvalidOptions(t *testing.T) {
+	s := &Seeder{}
+
+	_, err := s.Run(context.Background(), Options{Programs: 0})
+	assert.Error(t, err)
+
+	_, err = s.Run(context.Background(), Options{Programs: 1, AssetsPerProgram: -1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/apischema"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// saveAPISchema parses an asset response for an exposed API schema and stores
+// its endpoints linked to the asset. Failures are logged and never fail the scan.
+func (s *MonitorService) saveAPISchema(ctx context.Context, asset *database.Asset, response *database.AssetResponse, contentType string) {
+	if s.apiSchemaRepo == nil {
+		return
+	}
+
+	parsed := apischema.Parse(response.Body, contentType)
+	if parsed == nil {
+		return
+	}
+
+	schema := &database.APISchema{
+		AssetID:    asset.ID,
+		ResponseID: &response.ID,
+		Kind:       parsed.Kind,
+		Version:    parsed.Version,
+		Title:      parsed.Title,
+	}
+
+	endpoints := make([]*database.APIEndpoint, 0, len(parsed.Endpoints))
+	for _, endpoint := range parsed.Endpoints {
+		endpoints = append(endpoints, &database.APIEndpoint{
+			Method:  endpoint.Method,
+			Path:    endpoint.Path,
+			Summary: endpoint.Summary,
+		})
+	}
+
+	if err := s.apiSchemaRepo.SaveSchema(ctx, schema, endpoints); err != nil {
+		utils.Log(ctx).Warnf("Failed to save %s schema for %s: %v", parsed.Kind, asset.URL, err)
+		return
+	}
+
+	utils.Log(ctx).Infof("Found %s schema on %s with %d endpoints", parsed.Kind, asset.URL, len(endpoints))
+}
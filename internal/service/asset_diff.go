@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ErrNoCompletedScan is returned when a program has no completed scan to show the changes of
+var ErrNoCompletedScan = errors.New("program has no completed scan")
+
+// assetBaseline is the state of the assets a program's previous completed
+// scan saw, taken before a scan changes them
+type assetBaseline struct {
+	previous *database.Scan
+	states   map[uuid.UUID]*database.AssetState
+}
+
+// AssetDiff is what changed in a program's assets between two scans
+type AssetDiff struct {
+	Scan     *database.Scan          `json:"scan"`
+	Previous *database.Scan          `json:"previous"` // nil when the scan was not compared with another
+	Changes  []*database.AssetChange `json:"changes"`
+}
+
+// Count returns the number of changes of a kind
+func (d *AssetDiff) Count(change string) int {
+	count := 0
+	for _, c := range d.Changes {
+		if c.Change == change {
+			count++
+		}
+	}
+	return count
+}
+
+// loadAssetBaseline records the assets the program's previous completed scan
+// saw, or returns nil when there is nothing to compare the scan with
+func (s *MonitorService) loadAssetBaseline(ctx context.Context, program *database.Program, scan *database.Scan) *assetBaseline {
+	if s.changeRepo == nil {
+		return nil
+	}
+
+	previous, err := s.scanRepo.GetPreviousCompletedScan(ctx, program.ID, scan.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get previous scan of %s to compare assets with: %v", program.Name, err)
+		return nil
+	}
+	if previous == nil {
+		return nil
+	}
+
+	states, err := s.changeRepo.GetAssetStatesSince(ctx, program.ID, previous.StartedAt, scan.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get asset states of %s: %v", program.Name, err)
+		return nil
+	}
+	// Scans from before asset changes were tracked did not record which assets they saw
+	if len(states) == 0 && previous.AssetsSeen > 0 {
+		utils.Log(ctx).Infof("Previous scan of %s predates asset change tracking; changes are recorded from the next scan", program.Name)
+		return nil
+	}
+
+	baseline := &assetBaseline{previous: previous, states: make(map[uuid.UUID]*database.AssetState, len(states))}
+	for _, state := range states {
+		baseline.states[state.ID] = state
+	}
+	return baseline
+}
+
+// recordAssetChanges compares the assets a completed scan saw with the
+// baseline and records what was added, removed and changed
+func (s *MonitorService) recordAssetChanges(ctx context.Context, program *database.Program, scan *database.Scan, baseline *assetBaseline) {
+	current, err := s.changeRepo.GetScanAssetStates(ctx, program.ID, scan.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get asset states of %s: %v", program.Name, err)
+		return
+	}
+
+	changes := diffAssetStates(baseline.states, current)
+	for _, change := range changes {
+		change.ProgramID = program.ID
+	}
+	if err := s.changeRepo.SaveAssetChanges(ctx, scan.ID, baseline.previous.ID, changes); err != nil {
+		utils.Log(ctx).Errorf("Failed to record asset changes of %s: %v", program.Name, err)
+		return
+	}
+	scan.ComparedScanID = &baseline.previous.ID
+
+	diff := &AssetDiff{Changes: changes}
+	utils.Log(ctx).Infof("Assets of %s since the previous scan: %d added, %d removed, %d changed",
+		program.Name, diff.Count(database.AssetAdded), diff.Count(database.AssetRemoved), diff.Count(database.AssetChanged))
+}
+
+// diffAssetStates returns the assets added to and removed from previous, and
+// a change for every compared field of an asset in both that differs
+func diffAssetStates(previous map[uuid.UUID]*database.AssetState, current []*database.AssetState) []*database.AssetChange {
+	var changes []*database.AssetChange
+	seen := make(map[uuid.UUID]bool, len(current))
+
+	for _, state := range current {
+		seen[state.ID] = true
+		old, ok := previous[state.ID]
+		if !ok {
+			changes = append(changes, newAssetChange(state, database.AssetAdded))
+			continue
+		}
+
+		// An asset's first capture is its baseline, not a change
+		oldContent := old.ContentHash
+		if oldContent == "" {
+			oldContent = state.ContentHash
+		}
+
+		for _, field := range []struct {
+			name     string
+			old, new string
+		}{
+			{"url", old.URL, state.URL},
+			{"status", old.Status, state.Status},
+			{"liveness", old.Liveness, state.Liveness},
+			{"ip", old.IP, state.IP},
+			{"ipv6", old.IPv6, state.IPv6},
+			{"content", oldContent, state.ContentHash},
+		} {
+			if field.old != field.new {
+				change := newAssetChange(state, database.AssetChanged)
+				change.Field, change.OldValue, change.NewValue = field.name, field.old, field.new
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	for id, state := range previous {
+		if !seen[id] {
+			changes = append(changes, newAssetChange(state, database.AssetRemoved))
+		}
+	}
+
+	// Removed assets come from a map, so sort for a stable order
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Change != changes[j].Change {
+			return changeOrder[changes[i].Change] < changeOrder[changes[j].Change]
+		}
+		return changes[i].URL < changes[j].URL
+	})
+	return changes
+}
+
+// changeOrder lists added assets first, then removed and changed ones
+var changeOrder = map[string]int{database.AssetAdded: 0, database.AssetRemoved: 1, database.AssetChanged: 2}
+
+// newAssetChange creates a change of an asset
+func newAssetChange(state *database.AssetState, change string) *database.AssetChange {
+	id := state.ID
+	return &database.AssetChange{AssetID: &id, URL: state.URL, Change: change}
+}
+
+// GetAssetDiff returns the asset changes a scan of a program recorded, of its
+// latest completed scan when scanID is uuid.Nil
+func (s *MonitorService) GetAssetDiff(ctx context.Context, program *database.Program, scanID uuid.UUID) (*AssetDiff, error) {
+	var scan *database.Scan
+	var err error
+	if scanID == uuid.Nil {
+		scan, err = s.scanRepo.GetPreviousCompletedScan(ctx, program.ID, uuid.Nil)
+		if err == nil && scan == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNoCompletedScan, program.Name)
+		}
+	} else {
+		scan, err = s.scanRepo.GetScanByID(ctx, scanID)
+		if err == nil && (scan == nil || scan.ProgramID != program.ID) {
+			return nil, fmt.Errorf("%w: %s", database.ErrScanNotFound, scanID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &AssetDiff{Scan: scan}
+	if scan.ComparedScanID == nil {
+		return diff, nil
+	}
+
+	if diff.Previous, err = s.scanRepo.GetScanByID(ctx, *scan.ComparedScanID); err != nil {
+		return nil, err
+	}
+	if diff.Changes, err = s.changeRepo.GetScanChanges(ctx, scan.ID); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
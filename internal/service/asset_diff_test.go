@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAssetStates(t *testing.T) {
+	kept := &database.AssetState{ID: uuid.New(), URL: "https://www.acme.com", Status: "active", Liveness: "live", IP: "192.0.2.1"}
+	moved := &database.AssetState{ID: uuid.New(), URL: "https://api.acme.com", Status: "active", Liveness: "live", IP: "192.0.2.2"}
+	gone := &database.AssetState{ID: uuid.New(), URL: "https://old.acme.com", Status: "active"}
+	edited := &database.AssetState{ID: uuid.New(), URL: "https://blog.acme.com", Status: "active", ContentHash: "aaa"}
+	captured := &database.AssetState{ID: uuid.New(), URL: "https://shop.acme.com", Status: "active"}
+	previous := map[uuid.UUID]*database.AssetState{kept.ID: kept, moved.ID: moved, gone.ID: gone, edited.ID: edited, captured.ID: captured}
+
+	movedNow := *moved
+	movedNow.IP, movedNow.Liveness = "198.51.100.7", "dns-only"
+	added := &database.AssetState{ID: uuid.New(), URL: "https://new.acme.com", Status: "active"}
+	editedNow := *edited
+	editedNow.ContentHash = "bbb"
+	// A first capture is the asset's baseline, not a content change
+	capturedNow := *captured
+	capturedNow.ContentHash = "ccc"
+
+	changes := diffAssetStates(previous, []*database.AssetState{kept, &movedNow, added, &editedNow, &capturedNow})
+
+	type change struct{ kind, url, field, old, new string }
+	got := make([]change, len(changes))
+	for i, c := range changes {
+		got[i] = change{c.Change, c.URL, c.Field, c.OldValue, c.NewValue}
+	}
+	assert.Equal(t, []change{
+		{database.AssetAdded, "https://new.acme.com", "", "", ""},
+		{database.AssetRemoved, "https://old.acme.com", "", "", ""},
+		{database.AssetChanged, "https://api.acme.com", "liveness", "live", "dns-only"},
+		{database.AssetChanged, "https://api.acme.com", "ip", "192.0.2.2", "198.51.100.7"},
+		{database.AssetChanged, "https://blog.acme.com", "content", "aaa", "bbb"},
+	}, got)
+	assert.Equal(t, gone.ID, *changes[1].AssetID)
+
+	assert.Empty(t, diffAssetStates(previous, []*database.AssetState{kept, moved, gone, edited, captured}))
+}
+
+func TestRecordAssetChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	s := &MonitorService{
+		scanRepo:   database.NewScanRepository(sqlxDB),
+		changeRepo: database.NewAssetChangeRepository(sqlxDB),
+	}
+	program := &database.Program{ID: uuid.New(), Name: "acme"}
+	scan := &database.Scan{ID: uuid.New(), ProgramID: program.ID}
+	previousID := uuid.New()
+	kept, gone := uuid.New(), uuid.New()
+	columns := []string{"id", "url", "status", "liveness", "ip", "ipv6"}
+
+	mock.ExpectQuery("SELECT \\* FROM scans").
+		WithArgs(program.ID, scan.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "status", "assets_seen", "started_at"}).
+			AddRow(previousID, program.ID, "completed", 2, time.Now().Add(-24*time.Hour)))
+	mock.ExpectQuery("FROM assets a").
+		WithArgs(program.ID, sqlmock.AnyArg(), scan.ID).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(kept, "https://www.acme.com", "active", "live", "", "").
+			AddRow(gone, "https://old.acme.com", "active", "live", "", ""))
+
+	baseline := s.loadAssetBaseline(context.Background(), program, scan)
+	require.NotNil(t, baseline)
+	assert.Len(t, baseline.states, 2)
+
+	mock.ExpectQuery("WHERE a.program_id = \\$1 AND a.last_scan_id = \\$2").
+		WithArgs(program.ID, scan.ID).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(kept, "https://www.acme.com", "active", "live", "", ""))
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO asset_changes")
+	mock.ExpectExec("INSERT INTO asset_changes").
+		WithArgs(sqlmock.AnyArg(), scan.ID, program.ID, gone, "https://old.acme.com", database.AssetRemoved, "", "", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE scans SET compared_scan_id").
+		WithArgs(scan.ID, previousID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	s.recordAssetChanges(context.Background(), program, scan, baseline)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, previousID, *scan.ComparedScanID)
+}
+
+func TestLoadAssetBaseline_BeforeTracking(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	s := &MonitorService{
+		scanRepo:   database.NewScanRepository(sqlxDB),
+		changeRepo: database.NewAssetChangeRepository(sqlxDB),
+	}
+	program := &database.Program{ID: uuid.New(), Name: "acme"}
+	scan := &database.Scan{ID: uuid.New(), ProgramID: program.ID}
+
+	// The previous scan saw assets, but none of them record it
+	mock.ExpectQuery("SELECT \\* FROM scans").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "status", "assets_seen", "started_at"}).
+			AddRow(uuid.New(), program.ID, "completed", 40, time.Now()))
+	mock.ExpectQuery("FROM assets a").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "status", "liveness", "ip", "ipv6"}))
+
+	assert.Nil(t, s.loadAssetBaseline(context.Background(), program, scan))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
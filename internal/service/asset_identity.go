@@ -0,0 +1,44 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+)
+
+// mergeSchemeVariants collapses probe results for the same host (e.g. http://x
+// and https://x) into one, so a host probed over both schemes becomes a single
+// asset with a single saved response. Results that exist win over those that
+// don't, then https wins over http. The first-seen order is kept.
+func mergeSchemeVariants(results []httpx.DetailedProbeResult) []httpx.DetailedProbeResult {
+	merged := make([]httpx.DetailedProbeResult, 0, len(results))
+	index := make(map[string]int, len(results))
+
+	for _, result := range results {
+		key := database.AssetHostKey(result.URL)
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(merged)
+			merged = append(merged, result)
+			continue
+		}
+
+		if preferResult(result, merged[i]) {
+			merged[i] = result
+		}
+	}
+
+	return merged
+}
+
+// preferResult reports whether candidate should replace current
+func preferResult(candidate, current httpx.DetailedProbeResult) bool {
+	if candidate.Exists != current.Exists {
+		return candidate.Exists
+	}
+	if candidate.Liveness != current.Liveness && !current.Exists {
+		return httpx.MoreAlive(candidate.Liveness, current.Liveness)
+	}
+	return strings.HasPrefix(candidate.URL, "https://") && !strings.HasPrefix(current.URL, "https://")
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSchemeVariants(t *testing.T) {
+	results := []httpx.DetailedProbeResult{
+		{URL: "http://a.example.com", Exists: true, StatusCode: 200},
+		{URL: "https://b.example.com", Exists: false},
+		{URL: "https://a.example.com", Exists: true, StatusCode: 301},
+		{URL: "http://b.example.com", Exists: true, StatusCode: 200},
+		{URL: "https://c.example.com:8443", Exists: true},
+		{URL: "https://c.example.com", Exists: true},
+	}
+
+	merged := mergeSchemeVariants(results)
+	assert.Equal(t, []httpx.DetailedProbeResult{
+		{URL: "https://a.example.com", Exists: true, StatusCode: 301},
+		{URL: "http://b.example.com", Exists: true, StatusCode: 200},
+		{URL: "https://c.example.com:8443", Exists: true},
+		{URL: "https://c.example.com", Exists: true},
+	}, merged)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// assetStream saves the assets of a domain in batches while HTTPX is still
+// probing it, so large programs are written as hosts answer instead of all at
+// once after the probe. Only hosts that answered over https are streamed, as
+// no later result for the host is preferred over them (see preferResult); the
+// other hosts, and batches that failed to save, are saved after the probe.
+type assetStream struct {
+	service   *MonitorService
+	ctx       context.Context
+	batchSize int
+	build     func(result httpx.DetailedProbeResult) *database.Asset // nil when the host is not saved
+
+	mu       sync.Mutex
+	closed   bool
+	pending  []*database.Asset
+	results  []httpx.DetailedProbeResult
+	saved    []*database.Asset
+	savedKey map[string]*database.Asset // host key to saved asset
+}
+
+// newAssetStream creates a stream that saves assets built by build in batches
+// of batchSize
+func (s *MonitorService) newAssetStream(ctx context.Context, batchSize int, build func(result httpx.DetailedProbeResult) *database.Asset) *assetStream {
+	return &assetStream{
+		service:   s,
+		ctx:       ctx,
+		batchSize: batchSize,
+		build:     build,
+		savedKey:  make(map[string]*database.Asset),
+	}
+}
+
+// handle queues the asset of a probe result and saves the queue once it holds
+// a full batch. It is the result handler of the probe.
+func (st *assetStream) handle(result httpx.DetailedProbeResult) {
+	if !result.Exists || !strings.HasPrefix(result.URL, "https://") {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	// Results can still arrive after a cancelled probe has returned
+	if st.closed {
+		return
+	}
+
+	asset := st.build(result)
+	if asset == nil {
+		return
+	}
+	if _, ok := st.savedKey[database.AssetHostKey(asset.URL)]; ok {
+		return
+	}
+
+	st.pending = append(st.pending, asset)
+	st.results = append(st.results, result)
+	if len(st.pending) >= st.batchSize {
+		st.flush()
+	}
+}
+
+// flush saves the queued assets and their responses. Assets that fail to save
+// are left to be saved after the probe.
+func (st *assetStream) flush() {
+	if len(st.pending) == 0 {
+		return
+	}
+
+	if err := st.service.createAssets(st.ctx, st.pending); err != nil {
+		utils.Log(st.ctx).Warnf("Failed to save %d probed assets early, saving them after the probe: %v", len(st.pending), err)
+	} else {
+		st.service.saveDetailedResponses(st.ctx, st.pending, st.results)
+		for _, asset := range st.pending {
+			st.saved = append(st.saved, asset)
+			st.savedKey[database.AssetHostKey(asset.URL)] = asset
+		}
+		utils.Log(st.ctx).Debugf("Saved %d probed assets while probing", len(st.pending))
+	}
+
+	st.pending = nil
+	st.results = nil
+}
+
+// close saves the assets still queued and stops the stream. It returns the
+// saved assets in the order they were saved.
+func (st *assetStream) close() []*database.Asset {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.flush()
+	st.closed = true
+	return st.saved
+}
+
+// savedAsset returns the saved asset of a host, if the stream saved it
+func (st *assetStream) savedAsset(url string) (*database.Asset, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	asset, ok := st.savedKey[database.AssetHostKey(url)]
+	return asset, ok
+}
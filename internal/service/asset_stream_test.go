@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetStream(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	s := &MonitorService{
+		config:        &config.Config{},
+		assetRepo:     database.NewAssetRepository(sqlxDB),
+		writeThrottle: database.NewWriteThrottle(2, 0),
+	}
+	stream := s.newAssetStream(context.Background(), 2, func(result httpx.DetailedProbeResult) *database.Asset {
+		if result.URL == "https://out.example.com" {
+			return nil
+		}
+		return &database.Asset{URL: result.URL, Status: "active", Source: "secondary"}
+	})
+
+	expectBatch := func(hosts ...string) {
+		rows := sqlmock.NewRows([]string{"id", "host_key"})
+		for _, host := range hosts {
+			rows.AddRow(uuid.New(), host)
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO hosts").WillReturnResult(sqlmock.NewResult(0, int64(len(hosts))))
+		mock.ExpectQuery("INSERT INTO assets").WillReturnRows(rows)
+		mock.ExpectCommit()
+		for range hosts {
+			mock.ExpectExec("INSERT INTO asset_responses").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+
+	// A full batch is saved while probing, the rest when the stream closes
+	expectBatch("a.example.com", "d.example.com")
+	expectBatch("e.example.com")
+
+	for _, result := range []httpx.DetailedProbeResult{
+		{URL: "https://a.example.com", Exists: true, StatusCode: 200},
+		{URL: "http://b.example.com", Exists: true, StatusCode: 200},
+		{URL: "https://c.example.com", Liveness: httpx.LivenessTimedOut},
+		{URL: "https://out.example.com", Exists: true, StatusCode: 200},
+		{URL: "https://d.example.com", Exists: true, StatusCode: 200},
+		{URL: "https://e.example.com", Exists: true, StatusCode: 200},
+	} {
+		stream.handle(result)
+	}
+
+	saved := stream.close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, saved, 3)
+	assert.Equal(t, "https://a.example.com", saved[0].URL)
+	assert.NotEqual(t, uuid.Nil, saved[0].ID)
+
+	// http answers wait for the probe's full results
+	_, ok := stream.savedAsset("http://d.example.com")
+	assert.True(t, ok)
+	_, ok = stream.savedAsset("https://b.example.com")
+	assert.False(t, ok)
+
+	// Results arriving after the probe returned are ignored
+	stream.handle(httpx.DetailedProbeResult{URL: "https://f.example.com", Exists: true, StatusCode: 200})
+	assert.Len(t, stream.close(), 3)
+}
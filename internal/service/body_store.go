@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/objectstore"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// NewBodyStore creates the store response bodies are offloaded to, or nil
+// when BODY_STORE_BUCKET is empty and bodies are stored in the database
+func NewBodyStore(cfg *config.Config) (objectstore.Store, error) {
+	if cfg.BodyStore.Bucket == "" {
+		return nil, nil
+	}
+
+	switch cfg.BodyStore.Backend {
+	case "gcs":
+		client, err := objectstore.NewGCSClient(objectstore.GCSConfig{
+			Bucket:          cfg.BodyStore.Bucket,
+			CredentialsFile: cfg.BodyStore.GCSCredentialsFile,
+			Endpoint:        cfg.BodyStore.GCSEndpoint,
+			Timeout:         cfg.HTTP.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case "s3":
+		client, err := objectstore.NewClient(objectstore.Config{
+			Bucket:          cfg.BodyStore.Bucket,
+			Region:          cfg.ObjectStore.Region,
+			Endpoint:        cfg.ObjectStore.Endpoint,
+			PathStyle:       cfg.ObjectStore.PathStyle,
+			AccessKeyID:     cfg.ObjectStore.AccessKeyID,
+			SecretAccessKey: cfg.ObjectStore.SecretAccessKey,
+			SessionToken:    cfg.ObjectStore.SessionToken,
+			Timeout:         cfg.HTTP.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unknown body store backend %q", cfg.BodyStore.Backend)
+	}
+}
+
+// newBodyStore creates the configured body store, falling back to storing
+// bodies in the database when it cannot be created
+func newBodyStore(cfg *config.Config) objectstore.Store {
+	store, err := NewBodyStore(cfg)
+	if err != nil {
+		logrus.Warnf("Body store disabled, storing response bodies in the database: %v", err)
+		return nil
+	}
+	if store != nil {
+		logrus.Infof("Body store configured: bodies of at least %d bytes are stored in %s", cfg.BodyStore.MinBytes, store.Ref(cfg.BodyStore.Prefix))
+	}
+	return store
+}
+
+// bodyKey returns the key a body with the given SHA-256 is stored under.
+// Keys are spread over directories by the first byte of the hash.
+func bodyKey(prefix, digest string) string {
+	return prefix + digest[:2] + "/" + digest
+}
+
+// offloadBody stores a response's body in the body store and keeps only its
+// reference, hash and size on the response. Bodies under
+// BODY_STORE_MIN_BYTES, and bodies the store fails to take, stay in the
+// database.
+func (s *MonitorService) offloadBody(ctx context.Context, response *database.AssetResponse) {
+	if s.bodyStore == nil || response.Body == "" || len(response.Body) < s.config.BodyStore.MinBytes {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(response.Body))
+	digest := hex.EncodeToString(sum[:])
+	key := bodyKey(s.config.BodyStore.Prefix, digest)
+	if err := s.bodyStore.Put(ctx, key, []byte(response.Body), "application/octet-stream"); err != nil {
+		utils.Log(ctx).Warnf("Storing a body of %d bytes in the database: %v", len(response.Body), err)
+		return
+	}
+
+	response.BodyRef = s.bodyStore.Ref(key)
+	response.BodySHA256 = digest
+	response.BodySize = len(response.Body)
+	response.Body = ""
+}
+
+// LoadResponseBody fills in the body of a response offloaded to the body
+// store, checking it against the hash it was stored under. Responses whose
+// body is in the database are left as they are.
+func LoadResponseBody(ctx context.Context, store objectstore.Store, response *database.AssetResponse) error {
+	if response.BodyRef == "" || response.Body != "" {
+		return nil
+	}
+	if store == nil {
+		return fmt.Errorf("body is stored in %s but no body store is configured", response.BodyRef)
+	}
+
+	key, ok := objectstore.KeyFromRef(store, response.BodyRef)
+	if !ok {
+		return fmt.Errorf("body is stored in %s, outside the configured body store", response.BodyRef)
+	}
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	if hex.EncodeToString(sum[:]) != response.BodySHA256 {
+		return fmt.Errorf("body stored in %s does not match its hash", response.BodyRef)
+	}
+	response.Body = string(body)
+	return nil
+}
+
+// deleteBodies deletes the offloaded bodies of pruned responses that no
+// remaining response refers to. Identical bodies share one object, so an
+// object is only deleted once its last response is. Failures are logged and
+// leave the object behind.
+func (s *MonitorService) deleteBodies(ctx context.Context, refs []string) {
+	if s.bodyStore == nil || len(refs) == 0 {
+		return
+	}
+
+	unreferenced, err := s.assetRepo.GetUnreferencedBodyRefs(ctx, refs)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to find offloaded bodies to delete: %v", err)
+		return
+	}
+
+	deleted := 0
+	for _, ref := range unreferenced {
+		key, ok := objectstore.KeyFromRef(s.bodyStore, ref)
+		if !ok {
+			utils.Log(ctx).Warnf("Leaving body %s outside the configured body store", ref)
+			continue
+		}
+		if err := s.bodyStore.Delete(ctx, key); err != nil {
+			utils.Log(ctx).Warnf("Failed to delete body %s: %v", ref, err)
+			continue
+		}
+		deleted++
+	}
+	utils.Log(ctx).Infof("Deleted %d offloaded bodies of pruned responses", deleted)
+}
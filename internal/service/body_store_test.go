@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/objectstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an objectstore.Store kept in memory
+type memoryStore struct {
+	objects map[string][]byte
+	putErr  error
+	deleted []string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{objects: map[string][]byte{}}
+}
+
+func (m *memoryStore) Bucket() string { return "bodies" }
+
+func (m *memoryStore) Ref(key string) string { return "s3://bodies/" + key }
+
+func (m *memoryStore) Put(_ context.Context, key string, body []byte, _ string) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+	m.objects[key] = body
+	return nil
+}
+
+func (m *memoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	body, ok := m.objects[key]
+	if !ok {
+		return nil, objectstore.ErrNotFound
+	}
+	return body, nil
+}
+
+func (m *memoryStore) Delete(_ context.Context, key string) error {
+	delete(m.objects, key)
+	m.deleted = append(m.deleted, key)
+	return nil
+}
+
+func TestOffloadBody(t *testing.T) {
+	store := newMemoryStore()
+	s := &MonitorService{
+		config:    &config.Config{BodyStore: config.BodyStoreConfig{Prefix: "bodies/", MinBytes: 10}},
+		bodyStore: store,
+	}
+	ctx := context.Background()
+	body := strings.Repeat("<p>hello</p>", 10)
+
+	// Small bodies stay in the database
+	small := &database.AssetResponse{Body: "<p>hi</p>"}
+	s.offloadBody(ctx, small)
+	assert.Equal(t, "<p>hi</p>", small.Body)
+	assert.Empty(t, small.BodyRef)
+
+	response := &database.AssetResponse{Body: body}
+	s.offloadBody(ctx, response)
+	assert.Empty(t, response.Body)
+	assert.Len(t, response.BodySHA256, 64)
+	assert.Equal(t, "s3://bodies/bodies/"+response.BodySHA256[:2]+"/"+response.BodySHA256, response.BodyRef)
+	assert.Equal(t, len(body), response.BodySize)
+
+	require.NoError(t, LoadResponseBody(ctx, store, response))
+	assert.Equal(t, body, response.Body)
+
+	// Identical bodies share one object
+	s.offloadBody(ctx, &database.AssetResponse{Body: body})
+	assert.Len(t, store.objects, 1)
+
+	// Bodies the store fails to take stay in the database
+	store.putErr = errors.New("access denied")
+	failed := &database.AssetResponse{Body: body + "!"}
+	s.offloadBody(ctx, failed)
+	assert.Equal(t, body+"!", failed.Body)
+	assert.Empty(t, failed.BodyRef)
+
+	// Without a store every body stays in the database
+	s.bodyStore = nil
+	kept := &database.AssetResponse{Body: body}
+	s.offloadBody(ctx, kept)
+	assert.Equal(t, body, kept.Body)
+}
+
+func TestLoadResponseBody(t *testing.T) {
+	store := newMemoryStore()
+	s := &MonitorService{config: &config.Config{BodyStore: config.BodyStoreConfig{Prefix: "bodies/"}}, bodyStore: store}
+	ctx := context.Background()
+
+	response := &database.AssetResponse{Body: "<html>"}
+	s.offloadBody(ctx, response)
+
+	// Bodies in the database need no store
+	inDatabase := &database.AssetResponse{Body: "<html>"}
+	require.NoError(t, LoadResponseBody(ctx, nil, inDatabase))
+	assert.Equal(t, "<html>", inDatabase.Body)
+
+	assert.Error(t, LoadResponseBody(ctx, nil, &database.AssetResponse{BodyRef: response.BodyRef}), "no store configured")
+	assert.Error(t, LoadResponseBody(ctx, store, &database.AssetResponse{BodyRef: "gs://other/key"}), "another store")
+
+	tampered := *response
+	store.objects[bodyKey("bodies/", response.BodySHA256)] = []byte("<html>changed")
+	assert.Error(t, LoadResponseBody(ctx, store, &tampered), "hash mismatch")
+
+	delete(store.objects, bodyKey("bodies/", response.BodySHA256))
+	assert.ErrorIs(t, LoadResponseBody(ctx, store, response), objectstore.ErrNotFound)
+}
+
+func TestDeleteBodies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	store := newMemoryStore()
+	store.objects["bodies/aa/aaaa"] = []byte("a")
+	store.objects["bodies/bb/bbbb"] = []byte("b")
+	s := &MonitorService{
+		config:    &config.Config{},
+		assetRepo: database.NewAssetRepository(sqlxDB),
+		bodyStore: store,
+	}
+
+	// Only objects no remaining response refers to are deleted
+	mock.ExpectQuery("SELECT ref FROM unnest").
+		WillReturnRows(sqlmock.NewRows([]string{"ref"}).AddRow("s3://bodies/bodies/aa/aaaa").AddRow("gs://elsewhere/bodies/cc/cccc"))
+
+	s.deleteBodies(context.Background(), []string{"s3://bodies/bodies/aa/aaaa", "s3://bodies/bodies/bb/bbbb", "gs://elsewhere/bodies/cc/cccc"})
+	assert.Equal(t, []string{"bodies/aa/aaaa"}, store.deleted)
+	assert.Contains(t, store.objects, "bodies/bb/bbbb")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewBodyStore(t *testing.T) {
+	store, err := NewBodyStore(&config.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, store, "bodies are stored in the database without a bucket")
+
+	store, err = NewBodyStore(&config.Config{
+		BodyStore:   config.BodyStoreConfig{Backend: "s3", Bucket: "bodies"},
+		ObjectStore: config.ObjectStoreConfig{Region: "us-east-1", AccessKeyID: "id", SecretAccessKey: "secret"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "s3://bodies/key", store.Ref("key"))
+
+	store, err = NewBodyStore(&config.Config{BodyStore: config.BodyStoreConfig{Backend: "gcs", Bucket: "bodies", GCSEndpoint: "http://localhost:4443"}})
+	require.NoError(t, err)
+	assert.Equal(t, "gs://bodies/key", store.Ref("key"))
+
+	_, err = NewBodyStore(&config.Config{BodyStore: config.BodyStoreConfig{Backend: "gcs", Bucket: "bodies"}})
+	assert.Error(t, err)
+}
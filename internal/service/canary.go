@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ErrCanaryFailed is returned when a canary failed and CANARY_ON_FAILURE is
+// abort, so the scan does not record live targets as dead
+var ErrCanaryFailed = errors.New("canary check failed")
+
+// CanaryResult is the outcome of checking one canary hostname
+type CanaryResult struct {
+	Target     config.CanaryTarget
+	IP         string // first resolved address; empty when it did not resolve
+	Liveness   string // liveness of the probe; empty when it was not probed
+	StatusCode int
+	Failure    string // why the canary failed; empty when it passed
+}
+
+// Passed reports whether the canary resolved and probed as expected
+func (r *CanaryResult) Passed() bool {
+	return r.Failure == ""
+}
+
+// CheckCanaries resolves and probes every configured canary hostname the way
+// a scan probes targets and reports which did not behave as expected.
+// Without a prober (passive mode or HTTPX disabled) canaries are only resolved.
+func (s *MonitorService) CheckCanaries(ctx context.Context) ([]*CanaryResult, error) {
+	targets := s.config.Canary.Targets
+	results := make([]*CanaryResult, len(targets))
+
+	var resolving []string
+	for i, target := range targets {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		results[i] = &CanaryResult{Target: target}
+
+		addrs, err := s.lookupHost(ctx, target.Hostname)
+		if err != nil || len(addrs) == 0 {
+			results[i].Failure = fmt.Sprintf("does not resolve: %v", err)
+			continue
+		}
+		results[i].IP = addrs[0]
+		resolving = append(resolving, target.Hostname)
+	}
+
+	if s.prober == nil || len(resolving) == 0 {
+		return results, nil
+	}
+
+	probeCtx := httpx.WithMethod(ctx, s.config.Discovery.HTTPX.ScanMethod)
+	probeResults, err := s.prober.ProbeDomainsWithDetails(probeCtx, resolving)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe canaries: %w", err)
+	}
+
+	probes := make(map[string]httpx.DetailedProbeResult, len(probeResults))
+	for _, probeResult := range mergeSchemeVariants(probeResults) {
+		probes[database.AssetHostKey(probeResult.URL)] = probeResult
+	}
+
+	for _, result := range results {
+		if result.IP == "" {
+			continue
+		}
+
+		probe, ok := probes[database.AssetHostKey(result.Target.Hostname)]
+		switch {
+		case !ok:
+			result.Failure = "the prober returned no result"
+		case !probe.Exists || probe.Liveness != httpx.LivenessLive:
+			result.Liveness = probe.Liveness
+			result.Failure = fmt.Sprintf("did not answer over HTTP (%s)", probe.Liveness)
+			if probe.Error != "" {
+				result.Failure += ": " + probe.Error
+			}
+		default:
+			result.Liveness, result.StatusCode = probe.Liveness, probe.StatusCode
+			if expected := result.Target.StatusCode; expected != 0 && probe.StatusCode != expected {
+				result.Failure = fmt.Sprintf("answered %d, expected %d", probe.StatusCode, expected)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// checkCanaries runs the canary checks before a scan, emitting a
+// canary.failed event per failed canary. It returns ErrCanaryFailed when a
+// canary failed and the scan should not go ahead.
+func (s *MonitorService) checkCanaries(ctx context.Context) error {
+	if len(s.config.Canary.Targets) == 0 {
+		return nil
+	}
+
+	results, err := s.CheckCanaries(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCanaryFailed, err)
+	}
+
+	abort := s.config.Canary.OnFailure != "alert"
+	var failed int
+	for _, result := range results {
+		if result.Passed() {
+			continue
+		}
+		failed++
+		utils.Log(ctx).Errorf("Canary %s failed: %s", result.Target.Hostname, result.Failure)
+		s.events.Emit(ctx, events.TypeCanaryFailed, result.Target.Hostname, events.CanaryFailedData{
+			Hostname:           result.Target.Hostname,
+			Reason:             result.Failure,
+			ExpectedStatusCode: result.Target.StatusCode,
+			StatusCode:         result.StatusCode,
+			IP:                 result.IP,
+			ScanAborted:        abort,
+		})
+	}
+
+	if failed == 0 {
+		utils.Log(ctx).Infof("All %d canaries passed", len(results))
+		return nil
+	}
+	if !abort {
+		utils.Log(ctx).Warnf("%d of %d canaries failed, scanning anyway (CANARY_ON_FAILURE=alert)", failed, len(results))
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d canaries failed, skipping the scan so targets are not recorded as dead", ErrCanaryFailed, failed, len(results))
+}
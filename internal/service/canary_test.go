@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCanaries(t *testing.T) {
+	publisher := &capturePublisher{}
+	prober := &staticProber{results: []httpx.DetailedProbeResult{
+		{URL: "https://ok.canary.example", StatusCode: 200, Exists: true, Liveness: httpx.LivenessLive},
+		{URL: "https://moved.canary.example", StatusCode: 503, Exists: true, Liveness: httpx.LivenessLive},
+		{URL: "https://quiet.canary.example", Liveness: httpx.LivenessTimedOut, Error: "context deadline exceeded"},
+	}}
+	cfg := &config.Config{Canary: config.CanaryConfig{
+		Targets: []config.CanaryTarget{
+			{Hostname: "ok.canary.example", StatusCode: 200},
+			{Hostname: "moved.canary.example", StatusCode: 200},
+			{Hostname: "quiet.canary.example"},
+			{Hostname: "gone.canary.example"},
+		},
+		OnFailure: "abort",
+	}}
+	s := &MonitorService{
+		config: cfg,
+		prober: prober,
+		events: events.NewEmitter("", publisher),
+		resolveHost: func(_ context.Context, hostname string) ([]string, error) {
+			if hostname == "gone.canary.example" {
+				return nil, errors.New("no such host")
+			}
+			return []string{"192.0.2.1"}, nil
+		},
+	}
+
+	results, err := s.CheckCanaries(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.True(t, results[0].Passed())
+	assert.Equal(t, "answered 503, expected 200", results[1].Failure)
+	assert.Contains(t, results[2].Failure, "did not answer over HTTP (timed-out)")
+	assert.Contains(t, results[3].Failure, "does not resolve")
+	assert.Equal(t, []string{"ok.canary.example", "moved.canary.example", "quiet.canary.example"}, prober.probed)
+
+	err = s.checkCanaries(context.Background())
+	assert.ErrorIs(t, err, ErrCanaryFailed)
+	require.Len(t, publisher.events, 3)
+	assert.Equal(t, events.TypeCanaryFailed, publisher.events[0].Type)
+	data := publisher.events[0].Data.(events.CanaryFailedData)
+	assert.Equal(t, "moved.canary.example", data.Hostname)
+	assert.True(t, data.ScanAborted)
+
+	// Alerting only lets the scan go ahead
+	cfg.Canary.OnFailure = "alert"
+	assert.NoError(t, s.checkCanaries(context.Background()))
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/monitor-agent/internal/utils"
+)
+
+// prefetchChaosDataset starts downloading the program's ChaosDB dataset in the
+// background and returns a function that waits for it. The download is bounded
+// by the ChaosDB discovery timeout.
+func (s *MonitorService) prefetchChaosDataset(ctx context.Context, programURL string) func() map[string][]string {
+	if s.chaosDBClient == nil || !s.config.APIs.ChaosDB.Datasets {
+		return func() map[string][]string { return nil }
+	}
+
+	result := make(chan map[string][]string, 1)
+	go func() {
+		datasetCtx, cancel := context.WithTimeout(ctx, s.config.Discovery.Timeouts.ChaosDiscovery)
+		defer cancel()
+		result <- s.chaosDataset(datasetCtx, programURL)
+	}()
+
+	return sync.OnceValue(func() map[string][]string { return <-result })
+}
+
+// chaosDataset downloads the ChaosDB dataset published for a program, keyed by
+// root domain. It returns nil when datasets are disabled, none is published
+// for the program, or the download fails, so discovery falls back to
+// per-domain queries.
+func (s *MonitorService) chaosDataset(ctx context.Context, programURL string) map[string][]string {
+	if s.chaosDBClient == nil || !s.config.APIs.ChaosDB.Datasets {
+		return nil
+	}
+
+	dataset, err := s.chaosDBClient.FindDataset(ctx, programURL)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to check for a ChaosDB dataset for %s: %v", programURL, err)
+		return nil
+	}
+	if dataset == nil {
+		utils.Log(ctx).Debugf("No ChaosDB dataset published for %s", programURL)
+		return nil
+	}
+
+	subdomains, err := s.chaosDBClient.DownloadDataset(ctx, dataset)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to download ChaosDB dataset for %s, querying per domain: %v", programURL, err)
+		return nil
+	}
+
+	return subdomains
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/cluster"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// fingerprintBatchSize is how many response bodies are fingerprinted per query
+const fingerprintBatchSize = 200
+
+// clusterNamespace derives cluster IDs from their representative asset, so a
+// cluster keeps its ID across passes while its representative stays the same
+var clusterNamespace = uuid.MustParse("5b0f7c1e-8a2d-4f3b-9c6e-1d7a4e2b8f90")
+
+// ErrClusterNotFound is returned when no response cluster has an ID
+var ErrClusterNotFound = errors.New("response cluster not found")
+
+// ClusterResult summarizes a clustering pass
+type ClusterResult struct {
+	Fingerprinted int // stored responses whose fingerprint was computed during the pass
+	Assets        int // live assets with a response body
+	Clusters      int // groups of two or more assets
+	Clustered     int // assets in those groups
+}
+
+// bodySimhash returns the fingerprint stored with a response body
+func bodySimhash(body string) *int64 {
+	simhash := int64(cluster.Fingerprint(body))
+	return &simhash
+}
+
+// ClusterResponses groups the live assets whose latest responses are
+// near-identical and replaces the stored clusters. Each cluster is
+// represented by its oldest asset, so hunters can review one asset per
+// cluster instead of thousands of identical pages.
+func (s *MonitorService) ClusterResponses(ctx context.Context) (*ClusterResult, error) {
+	if err := s.checkWritable("cluster responses"); err != nil {
+		return nil, err
+	}
+
+	result := &ClusterResult{}
+
+	// Responses stored before fingerprints were need one first
+	for {
+		bodies, err := s.clusterRepo.GetUnfingerprintedResponses(ctx, fingerprintBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(bodies) == 0 {
+			break
+		}
+
+		fingerprints := make([]database.ResponseFingerprint, len(bodies))
+		for i, body := range bodies {
+			fingerprints[i] = database.ResponseFingerprint{ResponseID: body.ID, Simhash: *bodySimhash(body.Body)}
+		}
+		if err := s.clusterRepo.SetResponseFingerprints(ctx, fingerprints); err != nil {
+			return result, err
+		}
+		result.Fingerprinted += len(bodies)
+	}
+
+	candidates, err := s.clusterRepo.GetClusterCandidates(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Assets = len(candidates)
+
+	clusters := groupCandidates(candidates, s.config.Clustering.MaxDistance)
+	for _, c := range clusters {
+		result.Clustered += c.Size
+	}
+	result.Clusters = len(clusters)
+
+	if err := s.clusterRepo.ReplaceClusters(ctx, clusters); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// GetClusters returns up to limit clusters of at least minSize assets, largest first
+func (s *MonitorService) GetClusters(ctx context.Context, minSize, limit int) ([]*database.ResponseClusterSummary, error) {
+	return s.clusterRepo.GetClusters(ctx, minSize, limit)
+}
+
+// GetCluster returns a cluster by ID
+func (s *MonitorService) GetCluster(ctx context.Context, id uuid.UUID) (*database.ResponseClusterSummary, error) {
+	found, err := s.clusterRepo.GetCluster(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrClusterNotFound, id)
+	}
+	return found, nil
+}
+
+// clusterAfterScan regroups responses after a full scan, logging failures
+// instead of failing the scan
+func (s *MonitorService) clusterAfterScan(ctx context.Context) {
+	if s.clusterRepo == nil {
+		return
+	}
+
+	result, err := s.ClusterResponses(ctx)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to cluster responses: %v", err)
+		return
+	}
+
+	utils.Log(ctx).Infof("Clustered %d of %d live assets into %d groups of near-identical responses",
+		result.Clustered, result.Assets, result.Clusters)
+}
+
+// groupCandidates groups candidates by their fingerprints and returns the
+// groups of two or more assets. Candidates come oldest first, so the first
+// asset of a group is its representative.
+func groupCandidates(candidates []*database.ClusterCandidate, maxDistance int) []*database.ResponseCluster {
+	fingerprints := make([]uint64, len(candidates))
+	for i, candidate := range candidates {
+		fingerprints[i] = uint64(candidate.Simhash)
+	}
+
+	var clusters []*database.ResponseCluster
+	for _, group := range cluster.Group(fingerprints, maxDistance) {
+		if len(group) < 2 {
+			continue
+		}
+
+		representative := candidates[group[0]]
+		c := &database.ResponseCluster{
+			ID:                    uuid.NewSHA1(clusterNamespace, representative.AssetID[:]),
+			RepresentativeAssetID: representative.AssetID,
+			Simhash:               representative.Simhash,
+			Size:                  len(group),
+			StatusCode:            representative.StatusCode,
+		}
+		for _, i := range group {
+			c.AssetIDs = append(c.AssetIDs, candidates[i].AssetID)
+		}
+		clusters = append(clusters, c)
+	}
+
+	return clusters
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/cluster"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupCandidates(t *testing.T) {
+	created := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	marketing := int64(cluster.Fingerprint("Ship your product faster with Acme. Start your free trial today."))
+	login := int64(cluster.Fingerprint("Sign in to the Acme admin console with your corporate account"))
+
+	// Candidates come oldest first
+	oldest := &database.ClusterCandidate{AssetID: uuid.New(), Simhash: marketing, StatusCode: 200, CreatedAt: created}
+	admin := &database.ClusterCandidate{AssetID: uuid.New(), Simhash: login, StatusCode: 401, CreatedAt: created.Add(time.Hour)}
+	copy1 := &database.ClusterCandidate{AssetID: uuid.New(), Simhash: marketing, StatusCode: 200, CreatedAt: created.Add(2 * time.Hour)}
+	copy2 := &database.ClusterCandidate{AssetID: uuid.New(), Simhash: marketing ^ 0b101, StatusCode: 200, CreatedAt: created.Add(3 * time.Hour)}
+
+	clusters := groupCandidates([]*database.ClusterCandidate{oldest, admin, copy1, copy2}, cluster.DefaultMaxDistance)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, oldest.AssetID, clusters[0].RepresentativeAssetID)
+	assert.Equal(t, []uuid.UUID{oldest.AssetID, copy1.AssetID, copy2.AssetID}, clusters[0].AssetIDs)
+	assert.Equal(t, 3, clusters[0].Size)
+	assert.Equal(t, 200, clusters[0].StatusCode)
+
+	// The ID follows the representative, so it is stable across passes
+	again := groupCandidates([]*database.ClusterCandidate{oldest, copy1}, cluster.DefaultMaxDistance)
+	require.Len(t, again, 1)
+	assert.Equal(t, clusters[0].ID, again[0].ID)
+
+	assert.Empty(t, groupCandidates([]*database.ClusterCandidate{oldest, admin}, cluster.DefaultMaxDistance))
+}
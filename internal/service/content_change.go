@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/cluster"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// contentHeaders are the response headers whose values make up a capture's
+// header hash. Headers that change with every request, such as Date, ETag,
+// Set-Cookie or request IDs, are left out so they never count as a change.
+var contentHeaders = map[string]bool{
+	"access-control-allow-origin": true,
+	"content-security-policy":     true,
+	"content-type":                true,
+	"location":                    true,
+	"server":                      true,
+	"strict-transport-security":   true,
+	"www-authenticate":            true,
+	"x-aspnet-version":            true,
+	"x-frame-options":             true,
+	"x-powered-by":                true,
+}
+
+// hashString returns the hex SHA-256 of s
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// headerHash returns the hash of a response's status code and significant
+// headers. Header names are compared case-insensitively, with the
+// underscores HTTPX reports them with read as dashes.
+func headerHash(statusCode int, headers map[string]string) string {
+	lines := []string{fmt.Sprintf("status: %d", statusCode)}
+	for name, value := range headers {
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+		if contentHeaders[name] {
+			lines = append(lines, name+": "+strings.TrimSpace(value))
+		}
+	}
+	sort.Strings(lines[1:])
+	return hashString(strings.Join(lines, "\n"))
+}
+
+// contentHash returns the hash of a capture as a whole, stored on the asset
+func contentHash(response *database.AssetResponse) string {
+	return hashString(response.BodyHash + response.HeaderHash)
+}
+
+// hashResponse sets the content hashes of a GET response captured over the
+// scheme of its asset's URL. The http variant of an https asset often only
+// redirects, so its captures are not compared with the asset's.
+func hashResponse(asset *database.Asset, response *database.AssetResponse, result *httpx.DetailedProbeResult) bool {
+	if result.Method == httpx.MethodHEAD || !sameScheme(asset.URL, result.URL) {
+		return false
+	}
+	response.BodyHash = hashString(response.Body)
+	response.HeaderHash = headerHash(response.StatusCode, result.Headers)
+	return true
+}
+
+// sameScheme reports whether two URLs have the same scheme
+func sameScheme(a, b string) bool {
+	schemeA, _, _ := strings.Cut(a, "://")
+	schemeB, _, _ := strings.Cut(b, "://")
+	return strings.EqualFold(schemeA, schemeB)
+}
+
+// contentChanged reports whether a capture differs meaningfully from the
+// previous one: its significant headers or status changed, or its body did
+// with fingerprints at least minDistance bits apart. Bodies that differ only
+// in a nonce, a date or a build ID get the same fingerprint.
+func contentChanged(previous *database.ResponseHashes, response *database.AssetResponse, minDistance int) bool {
+	if previous.HeaderHash != response.HeaderHash {
+		return true
+	}
+	if previous.BodyHash == response.BodyHash {
+		return false
+	}
+	if previous.BodySimhash == nil || response.BodySimhash == nil {
+		return true
+	}
+	return cluster.Distance(uint64(*previous.BodySimhash), uint64(*response.BodySimhash)) >= minDistance
+}
+
+// previousResponseHashes gets the hashes of the latest hashed response of
+// each asset, before new captures of them are stored
+func (s *MonitorService) previousResponseHashes(ctx context.Context, assets []*database.Asset) map[uuid.UUID]*database.ResponseHashes {
+	ids := make([]uuid.UUID, 0, len(assets))
+	for _, asset := range assets {
+		if asset.ID != uuid.Nil {
+			ids = append(ids, asset.ID)
+		}
+	}
+
+	hashes, err := s.assetRepo.GetLatestResponseHashes(ctx, ids)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get previous response hashes, content changes are not detected: %v", err)
+		return nil
+	}
+	return hashes
+}
+
+// recordContentChange compares a stored capture of an asset with its
+// previous one and marks the asset's content changed when it differs. The
+// first capture of an asset only records its content hash.
+func (s *MonitorService) recordContentChange(ctx context.Context, asset *database.Asset, previous map[uuid.UUID]*database.ResponseHashes, response *database.AssetResponse) {
+	if previous == nil {
+		return
+	}
+
+	last, ok := previous[asset.ID]
+	changed := ok && contentChanged(last, response, s.config.Content.MinDistance)
+
+	// Later captures in the same batch compare with this one
+	previous[asset.ID] = &database.ResponseHashes{
+		AssetID:     asset.ID,
+		BodyHash:    response.BodyHash,
+		HeaderHash:  response.HeaderHash,
+		BodySimhash: response.BodySimhash,
+	}
+	if ok && !changed {
+		return
+	}
+
+	hash := contentHash(response)
+	if err := s.assetRepo.UpdateAssetContent(ctx, asset.ID, hash, changed); err != nil {
+		utils.Log(ctx).Warnf("Failed to record content of %s: %v", asset.URL, err)
+		return
+	}
+	asset.ContentHash = hash
+	if changed {
+		utils.Log(ctx).Infof("Content of %s changed since its previous capture", asset.URL)
+	}
+}
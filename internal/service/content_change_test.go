@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderHash(t *testing.T) {
+	base := headerHash(200, map[string]string{"Server": "nginx", "content_type": "text/html"})
+
+	// Volatile headers, name case and HTTPX's underscores do not matter
+	assert.Equal(t, base, headerHash(200, map[string]string{"server": "nginx", "Content-Type": "text/html", "date": "Mon, 02 Mar 2026 08:30:00 GMT", "set_cookie": "sid=1"}))
+
+	assert.NotEqual(t, base, headerHash(302, map[string]string{"Server": "nginx", "content_type": "text/html"}))
+	assert.NotEqual(t, base, headerHash(200, map[string]string{"Server": "Apache", "content_type": "text/html"}))
+	assert.NotEqual(t, base, headerHash(200, map[string]string{"Server": "nginx", "content_type": "text/html", "x_powered_by": "PHP/8.3"}))
+}
+
+func TestContentChanged(t *testing.T) {
+	near, far := int64(0b1), int64(0b1111)
+	zero := int64(0)
+	previous := &database.ResponseHashes{BodyHash: "a", HeaderHash: "h", BodySimhash: &zero}
+
+	tests := []struct {
+		name     string
+		response *database.AssetResponse
+		want     bool
+	}{
+		{"same capture", &database.AssetResponse{BodyHash: "a", HeaderHash: "h", BodySimhash: &zero}, false},
+		{"headers changed", &database.AssetResponse{BodyHash: "a", HeaderHash: "x", BodySimhash: &zero}, true},
+		{"body changed slightly", &database.AssetResponse{BodyHash: "b", HeaderHash: "h", BodySimhash: &near}, false},
+		{"body changed", &database.AssetResponse{BodyHash: "b", HeaderHash: "h", BodySimhash: &far}, true},
+		{"body without fingerprint", &database.AssetResponse{BodyHash: "b", HeaderHash: "h"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, contentChanged(previous, tt.response, 4))
+		})
+	}
+
+	// Without a minimum distance every changed byte counts
+	assert.True(t, contentChanged(previous, tests[2].response, 0))
+}
+
+func TestSaveDetailedResponses_ContentChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	s := &MonitorService{
+		config:        &config.Config{Content: config.ContentChangeConfig{MinDistance: 4}},
+		assetRepo:     database.NewAssetRepository(sqlxDB),
+		writeThrottle: database.NewWriteThrottle(0, 0),
+	}
+	known := &database.Asset{ID: uuid.New(), URL: "https://www.example.com"}
+	fresh := &database.Asset{ID: uuid.New(), URL: "https://new.example.com"}
+
+	simhash := *bodySimhash("Welcome")
+	mock.ExpectQuery("SELECT DISTINCT ON \\(a.id\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"asset_id", "body_hash", "header_hash", "body_simhash"}).
+			AddRow(known.ID, hashString("Welcome"), headerHash(200, nil), simhash))
+
+	// The known asset now redirects: a change. The new asset's first capture
+	// is its baseline, and its http variant is not compared.
+	mock.ExpectExec("INSERT INTO asset_responses").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE hosts").WithArgs(known.ID, sqlmock.AnyArg(), true).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_responses").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE hosts").WithArgs(fresh.ID, sqlmock.AnyArg(), false).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO asset_responses").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.saveDetailedResponses(context.Background(), []*database.Asset{known, fresh}, []httpx.DetailedProbeResult{
+		{URL: "https://www.example.com", Exists: true, StatusCode: 302, Body: "Welcome"},
+		{URL: "https://new.example.com", Exists: true, StatusCode: 200, Body: "Hello"},
+		{URL: "http://new.example.com", Exists: true, StatusCode: 301},
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NotEmpty(t, known.ContentHash)
+	assert.NotEmpty(t, fresh.ContentHash)
+}
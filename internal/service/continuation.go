@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrProgramTimedOut is returned when a program ran out of time before all of
+// its domains were processed; the remaining domains are kept as a continuation
+var ErrProgramTimedOut = errors.New("program timed out")
+
+// maxContinuationAttempts is how many times in a row a program may time out
+// on the same domain before that domain is skipped, so one domain that never
+// finishes cannot hold back the rest of the program forever
+const maxContinuationAttempts = 3
+
+// discoveryProgress tracks which stage each of a program's domains is in.
+// Discovery and probing run concurrently when pipelined, so it is safe for
+// concurrent use; a nil progress ignores updates.
+type discoveryProgress struct {
+	mu      sync.Mutex
+	domains []string
+	stages  map[string]string
+	done    map[string]bool
+}
+
+// newDiscoveryProgress creates progress tracking for a program
+func newDiscoveryProgress() *discoveryProgress {
+	return &discoveryProgress{stages: make(map[string]string), done: make(map[string]bool)}
+}
+
+// setDomains records the domains the program will process, in order
+func (p *discoveryProgress) setDomains(domains []string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.domains = make([]string, len(domains))
+	copy(p.domains, domains)
+}
+
+// enter records that a domain reached a stage
+func (p *discoveryProgress) enter(domain, stage string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stages[domain] = stage
+}
+
+// complete records that a domain was probed and saved
+func (p *discoveryProgress) complete(domain string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done[domain] = true
+}
+
+// stoppedAt returns the stage and domain of the earliest unfinished domain.
+// Before the domains are known the program is still fetching its scope.
+func (p *discoveryProgress) stoppedAt() (stage, domain string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.domains == nil {
+		return database.StageScope, ""
+	}
+	for _, d := range p.domains {
+		if p.done[d] {
+			continue
+		}
+		if stage, ok := p.stages[d]; ok {
+			return stage, d
+		}
+		return database.StageDiscovery, d
+	}
+	return "", ""
+}
+
+// remaining returns the domains that were not completed, in order
+func (p *discoveryProgress) remaining() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var remaining []string
+	for _, d := range p.domains {
+		if !p.done[d] {
+			remaining = append(remaining, d)
+		}
+	}
+	return remaining
+}
+
+// resumeDomains narrows a program's domains to those its continuation left
+// unprocessed. Domains no longer in scope are dropped, and the domain the
+// program repeatedly timed out on is skipped after maxContinuationAttempts.
+func resumeDomains(domains []string, continuation *database.ProgramContinuation) []string {
+	if continuation == nil {
+		return domains
+	}
+
+	pending := make(map[string]bool, len(continuation.RemainingDomains))
+	for _, domain := range continuation.RemainingDomains {
+		pending[domain] = true
+	}
+	if continuation.Attempts >= maxContinuationAttempts && continuation.Domain != "" {
+		logrus.Warnf("Skipping domain %s after %d timed-out attempts", continuation.Domain, continuation.Attempts)
+		delete(pending, continuation.Domain)
+	}
+
+	var resumed []string
+	for _, domain := range domains {
+		if pending[domain] {
+			resumed = append(resumed, domain)
+		}
+	}
+	return resumed
+}
+
+// programContinuation returns a program's pending continuation; lookup
+// failures are logged and treated as none, so the program is processed in full
+func (s *MonitorService) programContinuation(ctx context.Context, program *database.Program) *database.ProgramContinuation {
+	if s.continuations == nil {
+		return nil
+	}
+
+	continuation, err := s.continuations.GetContinuation(ctx, program.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get continuation for program %s: %v", program.Name, err)
+		return nil
+	}
+	return continuation
+}
+
+// finishProgress records how far a program's discovery got. When it ran out
+// of time with domains left, the stage and domain it stopped on are recorded
+// on the scan and the remaining domains are saved as a continuation; when all
+// domains were processed, any previous continuation is cleared.
+func (s *MonitorService) finishProgress(ctx context.Context, program *database.Program, scan *database.Scan, progress *discoveryProgress, discoveryErr error, hadContinuation bool) error {
+	if s.continuations == nil {
+		return nil
+	}
+	saveCtx := context.WithoutCancel(ctx)
+
+	remaining := progress.remaining()
+	if len(remaining) == 0 {
+		if hadContinuation {
+			if err := s.continuations.DeleteContinuation(saveCtx, program.ID); err != nil {
+				utils.Log(ctx).Warnf("Failed to clear continuation for program %s: %v", program.Name, err)
+			} else {
+				utils.Log(ctx).Infof("Program %s finished the domains left by its previous attempt", program.Name)
+			}
+		}
+		return nil
+	}
+
+	// Only time running out is continued; cancelled scans start over
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(discoveryErr, context.DeadlineExceeded) {
+		if hadContinuation && ctx.Err() == nil {
+			// Discovery finished without running out of time, e.g. because
+			// no discovery source is configured any more, so there is nothing to continue
+			if err := s.continuations.DeleteContinuation(saveCtx, program.ID); err != nil {
+				utils.Log(ctx).Warnf("Failed to clear continuation for program %s: %v", program.Name, err)
+			}
+		}
+		return nil
+	}
+
+	stage, domain := progress.stoppedAt()
+	continuation := &database.ProgramContinuation{
+		ProgramID:        program.ID,
+		ScanID:           &scan.ID,
+		Stage:            stage,
+		Domain:           domain,
+		RemainingDomains: remaining,
+	}
+	if err := s.continuations.SaveContinuation(saveCtx, continuation); err != nil {
+		utils.Log(ctx).Warnf("Failed to save continuation for program %s: %v", program.Name, err)
+	}
+
+	scan.Status = "timed_out"
+	scan.Error = fmt.Sprintf("timed out during %s of %s; %d of %d domains left for the next attempt",
+		stage, domain, len(remaining), len(progress.domains))
+	utils.Log(ctx).Warnf("Program %s %s", program.Name, scan.Error)
+
+	return fmt.Errorf("%w: %s", ErrProgramTimedOut, scan.Error)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newContinuationTestService(t *testing.T) (*MonitorService, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	return &MonitorService{continuations: database.NewContinuationRepository(sqlxDB)}, mock
+}
+
+func TestDiscoveryProgress(t *testing.T) {
+	progress := newDiscoveryProgress()
+
+	stage, domain := progress.stoppedAt()
+	assert.Equal(t, database.StageScope, stage)
+	assert.Empty(t, domain)
+
+	progress.setDomains([]string{"a.com", "b.com", "c.com"})
+	progress.enter("a.com", database.StageProbe)
+	progress.complete("a.com")
+	progress.enter("b.com", database.StageProbe)
+	progress.enter("c.com", database.StageDiscovery)
+
+	stage, domain = progress.stoppedAt()
+	assert.Equal(t, database.StageProbe, stage)
+	assert.Equal(t, "b.com", domain)
+	assert.Equal(t, []string{"b.com", "c.com"}, progress.remaining())
+
+	// A nil progress ignores updates
+	var none *discoveryProgress
+	none.enter("a.com", database.StageProbe)
+	none.complete("a.com")
+}
+
+func TestResumeDomains(t *testing.T) {
+	domains := []string{"a.com", "b.com", "c.com", "d.com"}
+
+	assert.Equal(t, domains, resumeDomains(domains, nil))
+
+	continuation := &database.ProgramContinuation{
+		Domain:           "b.com",
+		RemainingDomains: []string{"b.com", "c.com", "gone.com"},
+		Attempts:         1,
+	}
+	assert.Equal(t, []string{"b.com", "c.com"}, resumeDomains(domains, continuation))
+
+	// The domain the program keeps timing out on is eventually skipped
+	continuation.Attempts = maxContinuationAttempts
+	assert.Equal(t, []string{"c.com"}, resumeDomains(domains, continuation))
+}
+
+func TestFinishProgress_SavesContinuationOnTimeout(t *testing.T) {
+	s, mock := newContinuationTestService(t)
+	program := &database.Program{ID: uuid.New(), Name: "Acme"}
+	scan := &database.Scan{ID: uuid.New(), Status: "running"}
+
+	progress := newDiscoveryProgress()
+	progress.setDomains([]string{"a.com", "b.com", "c.com"})
+	progress.complete("a.com")
+	progress.enter("b.com", database.StageProbe)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	mock.ExpectQuery("INSERT INTO program_continuations").
+		WithArgs(program.ID, &scan.ID, database.StageProbe, "b.com", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"attempts", "created_at", "updated_at"}).AddRow(1, time.Now(), time.Now()))
+
+	err := s.finishProgress(ctx, program, scan, progress, nil, false)
+	assert.True(t, errors.Is(err, ErrProgramTimedOut))
+	assert.Equal(t, "timed_out", scan.Status)
+	assert.Contains(t, scan.Error, "probe of b.com")
+	assert.Contains(t, scan.Error, "2 of 3 domains")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFinishProgress_ClearsFinishedContinuation(t *testing.T) {
+	s, mock := newContinuationTestService(t)
+	program := &database.Program{ID: uuid.New(), Name: "Acme"}
+	scan := &database.Scan{ID: uuid.New(), Status: "running"}
+
+	progress := newDiscoveryProgress()
+	progress.setDomains([]string{"b.com"})
+	progress.complete("b.com")
+
+	mock.ExpectExec("DELETE FROM program_continuations").
+		WithArgs(program.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := s.finishProgress(context.Background(), program, scan, progress, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "running", scan.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFinishProgress_CancelledScanIsNotContinued(t *testing.T) {
+	s, mock := newContinuationTestService(t)
+	program := &database.Program{ID: uuid.New(), Name: "Acme"}
+	scan := &database.Scan{ID: uuid.New(), Status: "running"}
+
+	progress := newDiscoveryProgress()
+	progress.setDomains([]string{"a.com", "b.com"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := s.finishProgress(ctx, program, scan, progress, context.Canceled, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "running", scan.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
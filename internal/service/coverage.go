@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// recordCoverage stores how many of a domain's subdomains made it through
+// each step of the scan, so probe gaps can be compared across scans. Nothing
+// is recorded without a prober, since every host would look unprobed, or
+// outside a scan.
+func (s *MonitorService) recordCoverage(ctx context.Context, scanID, programID uuid.UUID, discovered *discoveredDomain, results []httpx.DetailedProbeResult, probeErr error) {
+	if s.coverageRepo == nil || s.prober == nil || scanID == uuid.Nil {
+		return
+	}
+
+	coverage := newScanCoverage(scanID, programID, discovered, results, probeErr)
+	if err := s.coverageRepo.SaveCoverage(context.WithoutCancel(ctx), coverage); err != nil {
+		utils.Log(ctx).Warnf("Failed to record coverage for domain %s: %v", discovered.domain, err)
+	}
+}
+
+// newScanCoverage counts a domain's subdomains at each step from its
+// discovery and probe results, one result per host
+func newScanCoverage(scanID, programID uuid.UUID, discovered *discoveredDomain, results []httpx.DetailedProbeResult, probeErr error) *database.ScanCoverage {
+	coverage := &database.ScanCoverage{
+		ScanID:     scanID,
+		ProgramID:  programID,
+		Domain:     discovered.domain,
+		Discovered: len(discovered.subdomains),
+		Valid:      len(discovered.clean),
+	}
+
+	if probeErr != nil {
+		coverage.ProbeError = truncateProbeError(probeErr.Error())
+		return coverage
+	}
+
+	coverage.Probed = len(results)
+	for i := range results {
+		if results[i].HostExists() {
+			coverage.Resolved++
+		}
+		if results[i].Exists {
+			coverage.Live++
+		}
+	}
+
+	return coverage
+}
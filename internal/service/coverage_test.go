@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScanCoverage(t *testing.T) {
+	discovered := &discoveredDomain{
+		domain:     "example.com",
+		subdomains: []string{"*.dev.example.com", "www.example.com", "api.example.com", "old.example.com", "bad_host.example.com"},
+		clean:      []string{"dev.example.com", "www.example.com", "api.example.com", "old.example.com"},
+	}
+	results := []httpx.DetailedProbeResult{
+		{URL: "https://www.example.com", Exists: true, Liveness: httpx.LivenessLive},
+		{URL: "https://api.example.com", Liveness: httpx.LivenessTimedOut},
+		{URL: "https://old.example.com", Liveness: httpx.LivenessError},
+	}
+
+	coverage := newScanCoverage(uuid.New(), uuid.New(), discovered, results, nil)
+	assert.Equal(t, "example.com", coverage.Domain)
+	assert.Equal(t, 5, coverage.Discovered)
+	assert.Equal(t, 4, coverage.Valid)
+	assert.Equal(t, 3, coverage.Probed)
+	assert.Equal(t, 2, coverage.Resolved)
+	assert.Equal(t, 1, coverage.Live)
+	assert.Empty(t, coverage.ProbeError)
+
+	// A failed probe returns nothing for any host
+	coverage = newScanCoverage(uuid.New(), uuid.New(), discovered, nil, errors.New("httpx: context deadline exceeded"))
+	assert.Equal(t, 4, coverage.Valid)
+	assert.Zero(t, coverage.Probed)
+	assert.Equal(t, "httpx: context deadline exceeded", coverage.ProbeError)
+}
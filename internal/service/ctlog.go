@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/ctlog"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ctlogSource is the discovery source recorded on assets found in
+// certificate transparency logs
+const ctlogSource = "ctlog"
+
+// ctlogTargets is the scope certificate transparency hostnames are matched
+// against
+type ctlogTargets struct {
+	matcher    *ctlog.Matcher
+	programs   map[string][]*database.ScopeTarget    // root domain to one scope target per program that has it in scope
+	exclusions map[uuid.UUID][]*platforms.ScopeAsset // out-of-scope entries by program
+}
+
+// loadCTLogTargets reads the recorded scope of the active programs. Programs
+// are only watched once a scan has recorded a snapshot of their scope, so no
+// hostname their out-of-scope entries exclude is ever probed.
+func (s *MonitorService) loadCTLogTargets(ctx context.Context) (*ctlogTargets, error) {
+	scopeTargets, err := s.scopeRepo.GetScopeTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := &ctlogTargets{programs: make(map[string][]*database.ScopeTarget)}
+	var roots []string
+	var programIDs []uuid.UUID
+	watched := make(map[uuid.UUID]bool)
+	added := make(map[string]bool)
+	for _, target := range scopeTargets {
+		root := scopeRoot(target.URL)
+		if root == "" || added[root+" "+target.ProgramID.String()] {
+			continue
+		}
+		added[root+" "+target.ProgramID.String()] = true
+
+		if _, ok := targets.programs[root]; !ok {
+			roots = append(roots, root)
+		}
+		targets.programs[root] = append(targets.programs[root], target)
+		if !watched[target.ProgramID] {
+			watched[target.ProgramID] = true
+			programIDs = append(programIDs, target.ProgramID)
+		}
+	}
+	targets.matcher = ctlog.NewMatcher(roots)
+
+	if targets.exclusions, err = s.loadScopeExclusions(ctx, programIDs); err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// loadScopeExclusions reads the out-of-scope entries of the latest scope
+// snapshot of programs
+func (s *MonitorService) loadScopeExclusions(ctx context.Context, programIDs []uuid.UUID) (map[uuid.UUID][]*platforms.ScopeAsset, error) {
+	exclusions := make(map[uuid.UUID][]*platforms.ScopeAsset)
+	if len(programIDs) == 0 {
+		return exclusions, nil
+	}
+
+	scopes, err := s.scopeRepo.GetLatestScopes(ctx, programIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, recorded := range scopes {
+		for _, entry := range recorded.OutOfScope {
+			if asset, ok := parseOutOfScopeEntry(entry); ok {
+				exclusions[recorded.ProgramID] = append(exclusions[recorded.ProgramID], asset)
+			}
+		}
+	}
+
+	return exclusions, nil
+}
+
+// scopeRoot returns the hostname whose subdomains a scope entry covers, with
+// its wildcard labels stripped; empty for entries that are not hostnames
+func scopeRoot(rawURL string) string {
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		rawURL = rawURL[i+3:]
+	}
+	for strings.HasPrefix(rawURL, "*.") {
+		rawURL = strings.TrimPrefix(rawURL, "*.")
+	}
+
+	host := assetHostname(rawURL)
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+	return ctlog.Normalize(host)
+}
+
+// ctlogWatch collects the hostnames the certificate transparency stream
+// matched to program scopes until they are flushed
+type ctlogWatch struct {
+	mu         sync.Mutex
+	targets    *ctlogTargets
+	pending    map[string]string // hostname to the root domain it matched
+	seen       map[string]bool   // hostnames already flushed, which are not queued again
+	maxPending int
+	dropped    int
+}
+
+// newCTLogWatch creates a watch holding up to maxPending hostnames
+func newCTLogWatch(targets *ctlogTargets, maxPending int) *ctlogWatch {
+	return &ctlogWatch{
+		targets:    targets,
+		pending:    make(map[string]string),
+		seen:       make(map[string]bool),
+		maxPending: maxPending,
+	}
+}
+
+// add queues the hostnames of a logged certificate that are below a watched
+// root domain. Certificates are logged again on renewal and by several logs,
+// so hostnames already flushed are skipped.
+func (w *ctlogWatch) add(hostnames []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, hostname := range hostnames {
+		hostname = ctlog.Normalize(hostname)
+		if hostname == "" || w.seen[hostname] {
+			continue
+		}
+		if _, ok := w.pending[hostname]; ok {
+			continue
+		}
+		root, ok := w.targets.matcher.Match(hostname)
+		if !ok {
+			continue
+		}
+		if len(w.pending) >= w.maxPending {
+			w.dropped++
+			continue
+		}
+		w.pending[hostname] = root
+	}
+}
+
+// take returns the queued hostnames, the targets they were matched against
+// and how many were dropped since the last flush, starting a new batch
+func (w *ctlogWatch) take() (map[string]string, *ctlogTargets, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pending, targets, dropped := w.pending, w.targets, w.dropped
+	w.pending, w.dropped = make(map[string]string), 0
+
+	// Bound the memory of the hostnames remembered
+	if len(w.seen)+len(pending) > 10*w.maxPending {
+		w.seen = make(map[string]bool)
+	}
+	for hostname := range pending {
+		w.seen[hostname] = true
+	}
+	return pending, targets, dropped
+}
+
+// setTargets replaces the scope hostnames are matched against
+func (w *ctlogWatch) setTargets(targets *ctlogTargets) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.targets = targets
+}
+
+// RunCTLogWatch follows the certificate transparency stream until ctx is
+// done. Hostnames of newly logged certificates that are below a program's
+// scope and that it has no assets for are collected, and every flush
+// interval they are filtered against the program's out-of-scope entries,
+// probed and saved like subdomains found by a scan. The watched scope is
+// reloaded at each flush.
+func (s *MonitorService) RunCTLogWatch(ctx context.Context) error {
+	if err := s.checkWritable("certificate transparency watch"); err != nil {
+		return err
+	}
+
+	cfg := s.config.Discovery.CTLog
+	if cfg.FlushInterval <= 0 || cfg.MaxPending <= 0 {
+		return fmt.Errorf("the certificate transparency watch requires a positive flush interval and pending limit")
+	}
+
+	targets, err := s.loadCTLogTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the scope to watch: %w", err)
+	}
+	utils.Log(ctx).Infof("Certificate transparency watch started: %d root domains, flushed every %v", targets.matcher.Len(), cfg.FlushInterval)
+
+	watch := newCTLogWatch(targets, cfg.MaxPending)
+	client := ctlog.NewClient(&ctlog.ClientConfig{StreamURL: cfg.StreamURL})
+	streamDone := make(chan struct{})
+	go func() {
+		defer close(streamDone)
+		_ = client.Watch(ctx, watch.add)
+	}()
+
+	ticker := time.NewTicker(cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			<-streamDone
+			utils.Log(ctx).Info("Certificate transparency watch stopped")
+			return nil
+		case <-ticker.C:
+		}
+
+		s.flushCTLog(ctx, watch)
+
+		if targets, err := s.loadCTLogTargets(ctx); err != nil {
+			utils.Log(ctx).Warnf("Failed to reload the scope of the certificate transparency watch, keeping the previous one: %v", err)
+		} else {
+			watch.setTargets(targets)
+		}
+	}
+}
+
+// ctlogBatch is the hostnames a flush found below one root domain of a program
+type ctlogBatch struct {
+	target    *database.ScopeTarget
+	root      string
+	hostnames []string
+}
+
+// flushCTLog probes and saves the hostnames collected since the last flush
+func (s *MonitorService) flushCTLog(ctx context.Context, watch *ctlogWatch) {
+	pending, targets, dropped := watch.take()
+	if dropped > 0 {
+		utils.Log(ctx).Warnf("Certificate transparency watch dropped %d hostnames over its limit of %d", dropped, s.config.Discovery.CTLog.MaxPending)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	// A root domain in the scope of several programs is an asset of each
+	batches := make(map[string]*ctlogBatch)
+	var keys []string
+	for hostname, root := range pending {
+		for _, target := range targets.programs[root] {
+			key := target.ProgramID.String() + " " + root
+			batch, ok := batches[key]
+			if !ok {
+				batch = &ctlogBatch{target: target, root: root}
+				batches[key] = batch
+				keys = append(keys, key)
+			}
+			batch.hostnames = append(batch.hostnames, hostname)
+		}
+	}
+	sort.Strings(keys)
+
+	saved := 0
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return
+		}
+		batch := batches[key]
+		sort.Strings(batch.hostnames)
+
+		assets, err := s.ingestCTLogHostnames(ctx, batch, targets.exclusions[batch.target.ProgramID])
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to save certificate transparency hostnames of program %s: %v", batch.target.ProgramName, err)
+			continue
+		}
+		saved += len(assets)
+	}
+
+	utils.Log(ctx).Infof("Certificate transparency watch flushed %d hostnames: %d new assets", len(pending), saved)
+}
+
+// ingestCTLogHostnames probes and saves the hostnames of a batch that its
+// program has no assets for and does not exclude, announcing each saved
+// asset with an asset.discovered event
+func (s *MonitorService) ingestCTLogHostnames(ctx context.Context, batch *ctlogBatch, outOfScope []*platforms.ScopeAsset) ([]*database.Asset, error) {
+	existing, err := s.assetRepo.GetExistingHostKeys(ctx, batch.target.ProgramID, batch.hostnames)
+	if err != nil {
+		return nil, err
+	}
+
+	var hostnames []string
+	for _, hostname := range batch.hostnames {
+		if !existing[hostname] && s.urlProcessor.IsValidDomain(hostname) {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	// Out-of-scope hostnames are dropped before probing, not after, so they are never probed
+	if len(outOfScope) > 0 {
+		hostnames = s.filterOutOfScopeSubdomains(hostnames, outOfScope)
+	}
+	if len(hostnames) == 0 {
+		return nil, nil
+	}
+
+	utils.Log(ctx).Infof("Certificate transparency watch found %d new hostnames below %s for program %s", len(hostnames), batch.root, batch.target.ProgramName)
+	discovered := &discoveredDomain{
+		domain:     batch.root,
+		subdomains: hostnames,
+		clean:      hostnames,
+		sources:    map[string]string{"": ctlogSource},
+	}
+	assets, err := s.probeDiscoveredDomain(ctx, uuid.Nil, batch.target.ProgramID, batch.target.ProgramURL, discovered, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.events.Enabled() {
+		for _, asset := range database.ExcludeSources(assets, s.config.Provenance.ExcludeSources) {
+			data := events.NewAssetData(asset)
+			data.ProgramName = batch.target.ProgramName
+			s.events.Emit(ctx, events.TypeAssetDiscovered, asset.URL, data)
+		}
+	}
+
+	return assets, nil
+}
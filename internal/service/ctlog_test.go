@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/ctlog"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeRoot(t *testing.T) {
+	assert.Equal(t, "acme.com", scopeRoot("*.acme.com"))
+	assert.Equal(t, "api.acme.com", scopeRoot("https://API.acme.com:8443/v1"))
+	assert.Equal(t, "acme.com", scopeRoot("https://*.acme.com"))
+	assert.Equal(t, "", scopeRoot("https://192.0.2.1"))
+	assert.Equal(t, "", scopeRoot("*"))
+}
+
+func TestLoadCTLogTargets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	s := &MonitorService{scopeRepo: database.NewScopeRepository(sqlxDB)}
+	acme, other := uuid.New(), uuid.New()
+
+	mock.ExpectQuery("SELECT a.program_id").WillReturnRows(sqlmock.NewRows([]string{"program_id", "program_name", "program_url", "url"}).
+		AddRow(acme, "Acme", "https://hackerone.com/acme", "*.acme.com").
+		AddRow(acme, "Acme", "https://hackerone.com/acme", "https://acme.com").
+		AddRow(other, "Other", "https://bugcrowd.com/other", "https://shop.acme.com"))
+	mock.ExpectQuery("SELECT DISTINCT ON \\(program_id\\) \\* FROM program_scopes").
+		WithArgs(pq.Array([]uuid.UUID{acme, other})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "scan_id", "content_hash", "in_scope", "out_of_scope", "recorded_at"}).
+			AddRow(uuid.New(), acme, nil, "abc", "{*.acme.com}", "{\"source_code https://github.com/acme/app\",\"wildcard *.internal.acme.com\"}", time.Now()).
+			AddRow(uuid.New(), other, nil, "def", "{shop.acme.com}", "{}", time.Now()))
+
+	targets, err := s.loadCTLogTargets(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, 2, targets.matcher.Len())
+	require.Len(t, targets.programs["acme.com"], 1)
+	require.Len(t, targets.programs["shop.acme.com"], 1)
+	assert.Equal(t, other, targets.programs["shop.acme.com"][0].ProgramID)
+	assert.Equal(t, []*platforms.ScopeAsset{{URL: "*.internal.acme.com", Type: "wildcard"}}, targets.exclusions[acme])
+	assert.Empty(t, targets.exclusions[other])
+
+	// The closest root wins, so shop.acme.com hostnames go to its program
+	root, ok := targets.matcher.Match("cart.shop.acme.com")
+	assert.True(t, ok)
+	assert.Equal(t, "shop.acme.com", root)
+}
+
+func TestCTLogWatch(t *testing.T) {
+	targets := &ctlogTargets{matcher: ctlog.NewMatcher([]string{"acme.com"})}
+	watch := newCTLogWatch(targets, 2)
+
+	watch.add([]string{"*.WWW.acme.com", "www.acme.com", "unrelated.example", "security@acme.com", "api.acme.com", "mail.acme.com"})
+	pending, _, dropped := watch.take()
+	assert.Equal(t, map[string]string{"www.acme.com": "acme.com", "api.acme.com": "acme.com"}, pending)
+	assert.Equal(t, 1, dropped)
+
+	// A renewed certificate does not queue its hostnames again
+	watch.add([]string{"www.acme.com", "mail.acme.com"})
+	pending, _, dropped = watch.take()
+	assert.Equal(t, map[string]string{"mail.acme.com": "acme.com"}, pending)
+	assert.Zero(t, dropped)
+}
+
+func TestIngestCTLogHostnames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	s := &MonitorService{
+		config:        &config.Config{},
+		assetRepo:     database.NewAssetRepository(sqlxDB),
+		writeThrottle: database.NewWriteThrottle(0, 0),
+		urlProcessor:  utils.NewURLProcessor(),
+	}
+	batch := &ctlogBatch{
+		target:    &database.ScopeTarget{ProgramID: uuid.New(), ProgramName: "Acme", ProgramURL: "https://hackerone.com/acme"},
+		root:      "acme.com",
+		hostnames: []string{"admin.internal.acme.com", "new.acme.com", "www.acme.com"},
+	}
+	outOfScope := []*platforms.ScopeAsset{{URL: "internal.acme.com", Type: "wildcard", OriginalPattern: "*.internal.acme.com"}}
+
+	mock.ExpectQuery("SELECT host_key FROM assets").WithArgs(batch.target.ProgramID, pq.Array(batch.hostnames)).
+		WillReturnRows(sqlmock.NewRows([]string{"host_key"}).AddRow("www.acme.com"))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO hosts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO assets").WillReturnRows(sqlmock.NewRows([]string{"id", "host_key"}).AddRow(uuid.New(), "new.acme.com"))
+	mock.ExpectCommit()
+
+	// Without a prober, hostnames are saved unprobed as in passive scans
+	assets, err := s.ingestCTLogHostnames(context.Background(), batch, outOfScope)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, assets, 1)
+	assert.Equal(t, "https://new.acme.com", assets[0].URL)
+	assert.Equal(t, "secondary", assets[0].Source)
+	assert.Equal(t, ctlogSource, assets[0].FirstSource)
+	assert.Nil(t, assets[0].FirstScanID)
+}
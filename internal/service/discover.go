@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// DiscoverDomains runs subdomain discovery, HTTPX probing and storage for an
+// ad-hoc list of domains, storing the results under a synthetic program on the
+// manual platform. It returns the scan that was recorded.
+func (s *MonitorService) DiscoverDomains(ctx context.Context, programName string, domains []string) (*database.Scan, error) {
+	if err := s.checkWritable("discover domains"); err != nil {
+		return nil, err
+	}
+
+	platform, err := platforms.NewManualPlatform(domains)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.discoverySources()) == 0 {
+		utils.Log(ctx).Warn("No discovery sources configured, only the given domains will be stored")
+	}
+
+	manualProgram := platforms.ManualProgram(programName)
+	program, err := s.programRepo.GetProgramByPlatformAndProgramURL(ctx, manualProgram.Platform, manualProgram.ProgramURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing program: %w", err)
+	}
+
+	if program == nil {
+		program = manualProgram.ConvertToDatabaseProgram()
+		if err := s.programRepo.CreateProgram(ctx, program); err != nil {
+			return nil, fmt.Errorf("failed to create program: %w", err)
+		}
+		utils.Log(ctx).Infof("Created manual program: %s", program.Name)
+		s.events.Emit(ctx, events.TypeProgramCreated, program.ProgramURL, events.NewProgramData(program))
+	}
+
+	utils.Log(ctx).Infof("Discovering assets for %d domains under manual program %s", len(platform.Domains()), program.Name)
+
+	if err := s.discoverProgramAssets(ctx, program, platform); err != nil {
+		return nil, fmt.Errorf("failed to discover assets: %w", err)
+	}
+
+	scans, err := s.scanRepo.GetScansByProgramID(ctx, program.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scans) == 0 {
+		return nil, fmt.Errorf("no scan recorded for program %s", program.Name)
+	}
+
+	return scans[0], nil
+}
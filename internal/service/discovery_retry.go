@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DiscoveryRetryResult sums up a pass over the scope domains whose discovery
+// or probe failed
+type DiscoveryRetryResult struct {
+	Retried   int // domains whose retry was due
+	Recovered int // of them, processed without errors this time
+	Assets    int // assets saved for the retried domains
+	NewAssets int // of them, assets the programs did not have yet
+}
+
+// failure returns the failure to record for a processed domain, or nil when
+// its discovery sources and probe all succeeded
+func (d *discoveredDomain) failure() *database.DiscoveryFailure {
+	switch {
+	case d.probeErr != nil:
+		return &database.DiscoveryFailure{
+			Domain: d.domain,
+			Stage:  database.DiscoveryStageProbe,
+			Source: "httpx",
+			Error:  truncateProbeError(d.probeErr.Error()),
+		}
+	case len(d.failedSources) > 0:
+		return &database.DiscoveryFailure{
+			Domain: d.domain,
+			Stage:  database.DiscoveryStageDiscovery,
+			Source: strings.Join(d.failedSources, ","),
+			Error:  truncateProbeError(d.discoveryErr.Error()),
+		}
+	default:
+		return nil
+	}
+}
+
+// recordDiscoveryOutcome records a scope domain whose discovery or probe
+// failed, so that `scan --retry-failed` reprocesses it, or clears an earlier
+// failure of the domain once it was processed without errors. Domains cut
+// short by a cancelled or timed-out scan are left to the scan's continuation.
+func (s *MonitorService) recordDiscoveryOutcome(ctx context.Context, programID uuid.UUID, discovered *discoveredDomain) {
+	retry := s.config.Discovery.Retry
+	if s.failureRepo == nil || retry.MaxAttempts == 0 || discovered == nil || ctx.Err() != nil {
+		return
+	}
+
+	failure := discovered.failure()
+	if failure == nil {
+		if err := s.failureRepo.ResolveFailure(ctx, programID, discovered.domain); err != nil {
+			utils.Log(ctx).Warnf("Failed to clear the discovery failure of domain %s: %v", discovered.domain, err)
+		}
+		return
+	}
+
+	failure.ProgramID = programID
+	if err := s.failureRepo.RecordFailure(ctx, failure, retry.Backoff, retry.MaxBackoff); err != nil {
+		utils.Log(ctx).Warnf("Failed to record the discovery failure of domain %s: %v", discovered.domain, err)
+		return
+	}
+	if failure.Attempts >= retry.MaxAttempts {
+		utils.Log(ctx).Warnf("Domain %s failed %d times (%s), it is no longer retried", failure.Domain, failure.Attempts, failure.Source)
+		return
+	}
+	utils.Log(ctx).Infof("Domain %s failed (%s), retrying it after %s", failure.Domain, failure.Source, failure.NextRetryAt.Format("2006-01-02 15:04"))
+}
+
+// RetryFailedDiscoveries reprocesses the scope domains whose discovery or
+// probe failed once their retry is due: their subdomains are discovered,
+// probed and saved again, outside any scan, and the new ones are announced
+// with asset.discovered events
+func (s *MonitorService) RetryFailedDiscoveries(ctx context.Context) (*DiscoveryRetryResult, error) {
+	if err := s.checkWritable("retry failed discoveries"); err != nil {
+		return nil, err
+	}
+	if s.config.Discovery.Retry.MaxAttempts == 0 {
+		return nil, fmt.Errorf("failed discoveries are not recorded with DISCOVERY_RETRY_MAX_ATTEMPTS=0")
+	}
+	if len(s.discoverySources()) == 0 {
+		return nil, fmt.Errorf("no discovery sources configured")
+	}
+
+	failures, err := s.failureRepo.GetDueFailures(ctx, s.config.Discovery.Retry.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	var programIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, failure := range failures {
+		if !seen[failure.ProgramID] {
+			seen[failure.ProgramID] = true
+			programIDs = append(programIDs, failure.ProgramID)
+		}
+	}
+	exclusions, err := s.loadScopeExclusions(ctx, programIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.Log(ctx).Infof("Retrying %d failed domains", len(failures))
+
+	result := &DiscoveryRetryResult{}
+	for i, failure := range failures {
+		if ctx.Err() != nil {
+			break
+		}
+		domainCtx := utils.WithLogFields(ctx, logrus.Fields{"program": failure.ProgramURL, "program_id": failure.ProgramID})
+		utils.Log(domainCtx).Infof("Retrying domain %s of program %s after %d failed attempts (%s)", failure.Domain, failure.ProgramName, failure.Attempts, failure.Source)
+
+		assets, err := s.retryDomain(domainCtx, failure, i+1, len(failures), exclusions[failure.ProgramID], result)
+		if err != nil {
+			utils.Log(domainCtx).Warnf("Failed to retry domain %s: %v", failure.Domain, err)
+			continue
+		}
+		result.Assets += len(assets)
+	}
+
+	return result, ctx.Err()
+}
+
+// retryDomain discovers, probes and saves the subdomains of a failed domain
+// again, counting it as recovered when nothing failed this time
+func (s *MonitorService) retryDomain(ctx context.Context, failure *database.DiscoveryFailure, index, total int, outOfScope []*platforms.ScopeAsset, result *DiscoveryRetryResult) ([]*database.Asset, error) {
+	discovered := s.discoverDomain(ctx, failure.Domain, index, total, nil)
+	if discovered == nil {
+		return nil, fmt.Errorf("no discovery sources configured")
+	}
+	result.Retried++
+
+	// Remember which hosts the program already has, so only new ones are announced
+	var existing map[string]bool
+	if len(discovered.clean) > 0 {
+		hostKeys := make([]string, 0, len(discovered.clean))
+		for _, subdomain := range discovered.clean {
+			hostKeys = append(hostKeys, database.AssetHostKey("https://"+subdomain))
+		}
+		var err error
+		if existing, err = s.assetRepo.GetExistingHostKeys(ctx, failure.ProgramID, hostKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	assets, err := s.probeDiscoveredDomain(ctx, uuid.Nil, failure.ProgramID, failure.ProgramURL, discovered, outOfScope)
+	s.recordDiscoveryOutcome(ctx, failure.ProgramID, discovered)
+	if err != nil {
+		return nil, err
+	}
+	if discovered.failure() == nil {
+		result.Recovered++
+	}
+
+	var newAssets []*database.Asset
+	for _, asset := range assets {
+		if !existing[database.AssetHostKey(asset.URL)] {
+			newAssets = append(newAssets, asset)
+		}
+	}
+	result.NewAssets += len(newAssets)
+
+	if s.events.Enabled() {
+		for _, asset := range database.ExcludeSources(newAssets, s.config.Provenance.ExcludeSources) {
+			data := events.NewAssetData(asset)
+			data.ProgramName = failure.ProgramName
+			s.events.Emit(ctx, events.TypeAssetDiscovered, asset.URL, data)
+		}
+	}
+
+	return assets, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveredDomain_Failure(t *testing.T) {
+	assert.Nil(t, (&discoveredDomain{domain: "acme.com"}).failure())
+
+	discovered := &discoveredDomain{
+		domain:        "acme.com",
+		failedSources: []string{"chaosdb", "crtsh"},
+		discoveryErr:  errors.New("chaosdb: HTTP 502\ncrtsh: timeout"),
+	}
+	failure := discovered.failure()
+	require.NotNil(t, failure)
+	assert.Equal(t, database.DiscoveryStageDiscovery, failure.Stage)
+	assert.Equal(t, "chaosdb,crtsh", failure.Source)
+
+	// A failed probe loses every subdomain, so it is what gets recorded
+	discovered.probeErr = errors.New("context deadline exceeded")
+	failure = discovered.failure()
+	require.NotNil(t, failure)
+	assert.Equal(t, database.DiscoveryStageProbe, failure.Stage)
+	assert.Equal(t, "httpx", failure.Source)
+	assert.Equal(t, "context deadline exceeded", failure.Error)
+}
+
+func TestRecordDiscoveryOutcome(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	cfg := &config.Config{}
+	cfg.Discovery.Retry = config.DiscoveryRetryConfig{MaxAttempts: 3, Backoff: time.Hour, MaxBackoff: 4 * time.Hour}
+	s := &MonitorService{config: cfg, failureRepo: database.NewDiscoveryFailureRepository(sqlxDB)}
+	programID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO discovery_failures").
+		WithArgs(programID, "acme.com", "discovery", "chaosdb", "chaosdb: HTTP 502", 3600.0, 14400.0).
+		WillReturnRows(sqlmock.NewRows([]string{"attempts", "first_failed_at", "last_failed_at", "next_retry_at"}).
+			AddRow(1, now, now, now.Add(time.Hour)))
+	s.recordDiscoveryOutcome(context.Background(), programID, &discoveredDomain{
+		domain:        "acme.com",
+		failedSources: []string{"chaosdb"},
+		discoveryErr:  errors.New("chaosdb: HTTP 502"),
+	})
+
+	mock.ExpectExec("DELETE FROM discovery_failures").
+		WithArgs(programID, "acme.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.recordDiscoveryOutcome(context.Background(), programID, &discoveredDomain{domain: "acme.com"})
+
+	// A domain cut short by a cancelled scan is left alone
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.recordDiscoveryOutcome(ctx, programID, &discoveredDomain{domain: "acme.com", probeErr: context.Canceled})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/dns"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// newDNSClient creates the resolver of asset hostnames, returning nil when
+// resolution is disabled or no resolver is available
+func newDNSClient(cfg *config.Config) *dns.Client {
+	if !cfg.Discovery.DNS.Enabled {
+		return nil
+	}
+
+	client, err := dns.NewClient(&dns.ClientConfig{
+		Servers: cfg.Discovery.DNS.Resolvers,
+		Timeout: cfg.Discovery.DNS.Timeout,
+	})
+	if err != nil {
+		logrus.Warnf("DNS resolution of assets disabled: %v", err)
+		return nil
+	}
+
+	logrus.Infof("DNS resolution of assets configured with %s", strings.Join(client.Servers(), ", "))
+	return client
+}
+
+// resolveAssets resolves the hostnames of a program's assets, recording their
+// addresses and CNAME chains and flagging those that no longer resolve as
+// dns_dead. Hostnames the resolvers failed on keep what was recorded before.
+// Failures are logged and never fail the scan.
+func (s *MonitorService) resolveAssets(ctx context.Context, program *database.Program) {
+	if s.dnsClient == nil || s.dnsRepo == nil {
+		return
+	}
+
+	assets, err := s.dnsRepo.GetAssetsToResolve(ctx, program.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get assets to resolve for program %s: %v", program.Name, err)
+		return
+	}
+
+	// The http and https variants and every port of a host share its records
+	var hosts []string
+	assetIDs := make(map[string][]uuid.UUID)
+	for _, asset := range assets {
+		host := assetHostname(asset.URL)
+		if host == "" || strings.Contains(host, "*") || net.ParseIP(host) != nil {
+			continue
+		}
+		if _, ok := assetIDs[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		assetIDs[host] = append(assetIDs[host], asset.ID)
+	}
+	if len(hosts) == 0 {
+		return
+	}
+
+	var resolved, dead, failed int
+	for i, result := range s.dnsClient.ResolveAll(ctx, hosts, s.config.Discovery.DNS.Concurrency) {
+		if result.Err != nil {
+			failed++
+			utils.Log(ctx).Debugf("Failed to resolve %s: %v", hosts[i], result.Err)
+			continue
+		}
+
+		resolution := &database.AssetResolution{CNAMEs: result.CNAMEs, DNSDead: result.Dead}
+		if len(result.IPv4) > 0 {
+			resolution.IP = result.IPv4[0]
+		}
+		if len(result.IPv6) > 0 {
+			resolution.IPv6 = result.IPv6[0]
+		}
+
+		if err := s.dnsRepo.SaveResolution(ctx, assetIDs[hosts[i]], resolution); err != nil {
+			utils.Log(ctx).Warnf("Failed to save DNS resolution of %s: %v", hosts[i], err)
+			continue
+		}
+		if result.Dead {
+			dead++
+		} else {
+			resolved++
+		}
+	}
+
+	utils.Log(ctx).Infof("Resolved %d hostnames of program %s: %d resolve, %d are dns_dead, %d failed", len(hosts), program.Name, resolved, dead, failed)
+}
+
+// assetHostname returns the lowercase hostname of an asset URL, which may
+// have no scheme
+func assetHostname(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+}
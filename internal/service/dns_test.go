@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	miekgdns "github.com/miekg/dns"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startDNSServer runs a resolver answering api.acme.com with an address
+// behind a CNAME, NXDOMAIN for old.acme.com and SERVFAIL for anything else
+func startDNSServer(t *testing.T) string {
+	handler := miekgdns.HandlerFunc(func(w miekgdns.ResponseWriter, r *miekgdns.Msg) {
+		response := new(miekgdns.Msg)
+		response.SetReply(r)
+		question := r.Question[0]
+
+		switch question.Name {
+		case "api.acme.com.":
+			cname, _ := miekgdns.NewRR("api.acme.com. 60 IN CNAME edge.cdn.example.")
+			response.Answer = append(response.Answer, cname)
+			if question.Qtype == miekgdns.TypeA {
+				a, _ := miekgdns.NewRR("edge.cdn.example. 60 IN A 198.51.100.7")
+				response.Answer = append(response.Answer, a)
+			}
+		case "old.acme.com.":
+			response.Rcode = miekgdns.RcodeNameError
+		default:
+			response.Rcode = miekgdns.RcodeServerFailure
+		}
+		_ = w.WriteMsg(response)
+	})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &miekgdns.Server{PacketConn: conn, Handler: handler}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return conn.LocalAddr().String()
+}
+
+func TestResolveAssets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	client, err := dns.NewClient(&dns.ClientConfig{Servers: []string{startDNSServer(t)}, Timeout: time.Second})
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	cfg.Discovery.DNS.Concurrency = 2
+	s := &MonitorService{config: cfg, dnsClient: client, dnsRepo: database.NewDNSRepository(sqlxDB)}
+
+	program := &database.Program{ID: uuid.New(), Name: "Acme"}
+	https, http, old := uuid.New(), uuid.New(), uuid.New()
+	mock.ExpectQuery("SELECT id, url FROM assets").WithArgs(program.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url"}).
+			AddRow(https, "https://api.acme.com").
+			AddRow(http, "http://API.acme.com:8080").
+			AddRow(uuid.New(), "*.acme.com").
+			AddRow(uuid.New(), "https://192.0.2.1").
+			AddRow(uuid.New(), "https://flaky.acme.com").
+			AddRow(old, "old.acme.com"))
+
+	// Both variants of api.acme.com get its records; flaky.acme.com, which
+	// the resolver failed on, is left alone
+	mock.ExpectExec("UPDATE hosts SET").
+		WithArgs(pq.Array([]uuid.UUID{https, http}), "198.51.100.7", "", pq.StringArray{"edge.cdn.example"}, false).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE hosts SET").
+		WithArgs(pq.Array([]uuid.UUID{old}), "", "", pq.StringArray{}, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.resolveAssets(context.Background(), program)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssetHostname(t *testing.T) {
+	assert.Equal(t, "api.acme.com", assetHostname("https://API.acme.com:8443/login"))
+	assert.Equal(t, "api.acme.com", assetHostname("api.acme.com."))
+	assert.Equal(t, "2001:db8::1", assetHostname("https://[2001:db8::1]"))
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// newEventEmitter creates the event emitter with a publisher for each configured transport
+func newEventEmitter(cfg *config.Config) *events.Emitter {
+	var publishers []events.Publisher
+
+	if cfg.Events.WebhookURL != "" {
+		publishers = append(publishers, events.NewWebhookPublisher(&events.WebhookConfig{
+			URL:           cfg.Events.WebhookURL,
+			Secret:        cfg.Events.WebhookSecret,
+			Timeout:       cfg.HTTP.Timeout,
+			RetryAttempts: cfg.HTTP.RetryAttempts,
+			RetryDelay:    cfg.HTTP.RetryDelay,
+		}))
+		logrus.Info("Event webhook configured")
+	}
+
+	if len(cfg.Events.KafkaBrokers) > 0 {
+		publishers = append(publishers, events.NewKafkaPublisher(&events.KafkaConfig{
+			Brokers: cfg.Events.KafkaBrokers,
+			Topic:   cfg.Events.KafkaTopic,
+			Timeout: cfg.HTTP.Timeout,
+		}))
+		logrus.Infof("Kafka event publisher configured for topic %s", cfg.Events.KafkaTopic)
+	}
+
+	if cfg.Events.NATSURL != "" {
+		publisher, err := events.NewNATSPublisher(&events.NATSConfig{
+			URL:     cfg.Events.NATSURL,
+			Subject: cfg.Events.NATSSubject,
+			Timeout: cfg.HTTP.Timeout,
+		})
+		if err != nil {
+			logrus.Warnf("NATS event publisher disabled: %v", err)
+		} else {
+			publishers = append(publishers, publisher)
+			logrus.Infof("NATS event publisher configured for subject %s.>", cfg.Events.NATSSubject)
+		}
+	}
+
+	if cfg.Events.RoutesFile != "" {
+		routes, err := events.LoadRoutesFile(cfg.Events.RoutesFile)
+		if err != nil {
+			logrus.Errorf("Notification routing disabled: %v", err)
+		} else {
+			publishers = append(publishers, events.NewRouter(routes, events.WebhookConfig{
+				Timeout:       cfg.HTTP.Timeout,
+				RetryAttempts: cfg.HTTP.RetryAttempts,
+				RetryDelay:    cfg.HTTP.RetryDelay,
+			}))
+			logrus.Infof("Loaded %d notification routes to %d channels from %s", len(routes.Routes), len(routes.Channels), cfg.Events.RoutesFile)
+		}
+	}
+
+	if notifier := newNotifier(cfg); notifier != nil {
+		publishers = append(publishers, notifier)
+	}
+
+	return events.NewEmitter(cfg.Events.Source, publishers...)
+}
+
+// newNotifier creates the notifier for the enabled notification sinks, or
+// returns nil when none is enabled
+func newNotifier(cfg *config.Config) *events.Notifier {
+	notifierConfig := &events.NotifierConfig{
+		BatchSize:     cfg.Notify.BatchSize,
+		BatchInterval: cfg.Notify.BatchInterval,
+		Timeout:       cfg.HTTP.Timeout,
+		RetryAttempts: cfg.Notify.RetryAttempts,
+		RetryDelay:    cfg.Notify.RetryDelay,
+	}
+	if cfg.Notify.SlackEnabled {
+		notifierConfig.SlackWebhookURL = cfg.Notify.SlackWebhookURL
+	}
+	if cfg.Notify.DiscordEnabled {
+		notifierConfig.DiscordWebhookURL = cfg.Notify.DiscordWebhookURL
+	}
+	if cfg.Notify.WebhookEnabled {
+		notifierConfig.WebhookURL = cfg.Notify.WebhookURL
+		notifierConfig.WebhookSecret = cfg.Notify.WebhookSecret
+	}
+
+	notifier := events.NewNotifier(notifierConfig)
+	if len(notifier.Sinks()) == 0 {
+		return nil
+	}
+	logrus.Infof("Notifications of new programs and assets enabled for %s", strings.Join(notifier.Sinks(), ", "))
+	return notifier
+}
+
+// emitDiscoveredAssets emits an asset.discovered event for every asset a scan
+// found first, followed by a scan.digest event summarizing them. Assets only
+// sources in EXPORT_EXCLUDE_SOURCES found are left out.
+func (s *MonitorService) emitDiscoveredAssets(ctx context.Context, program *database.Program, scan *database.Scan) {
+	if !s.events.Enabled() {
+		return
+	}
+
+	assets, err := s.assetRepo.GetAssetsByFirstScanID(ctx, scan.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get new assets for scan %s: %v", scan.ID, err)
+		return
+	}
+	assets = database.ExcludeSources(assets, s.config.Provenance.ExcludeSources)
+
+	for _, asset := range assets {
+		data := events.NewAssetData(asset)
+		data.ProgramName = program.Name
+		s.events.Emit(ctx, events.TypeAssetDiscovered, asset.URL, data)
+	}
+
+	if len(assets) == 0 {
+		return
+	}
+
+	digest := events.ScanDigestData{
+		Program:     events.NewProgramData(program),
+		ScanID:      scan.ID,
+		Status:      scan.Status,
+		NewAssets:   len(assets),
+		AssetsSeen:  scan.AssetsSeen,
+		AssetsFound: scan.AssetsFound,
+	}
+	if s.config.Events.DigestAttachment != "" {
+		attachment, err := newDigestAttachment(&s.config.Events, digest, assets)
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to attach new assets to the digest of scan %s: %v", scan.ID, err)
+		} else {
+			digest.Attachment = attachment
+		}
+	}
+
+	s.events.Emit(ctx, events.TypeScanDigest, program.ProgramURL, digest)
+}
+
+// newDigestAttachment renders the new assets of a digest in the configured
+// format. With an attachment directory the file is written there and linked
+// under the attachment base URL; otherwise it is sent inline.
+func newDigestAttachment(cfg *config.EventsConfig, digest events.ScanDigestData, assets []*database.Asset) (*events.Attachment, error) {
+	content, contentType, err := events.EncodeAssets(cfg.DigestAttachment, assets)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &events.Attachment{
+		Filename:    events.DigestFilename(digest.Program, digest.ScanID, cfg.DigestAttachment),
+		ContentType: contentType,
+	}
+
+	if cfg.DigestAttachmentDir == "" {
+		attachment.Content = string(content)
+		return attachment, nil
+	}
+
+	if err := os.MkdirAll(cfg.DigestAttachmentDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.DigestAttachmentDir, attachment.Filename), content, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write attachment: %w", err)
+	}
+	attachment.URL = strings.TrimRight(cfg.DigestAttachmentURL, "/") + "/" + url.PathEscape(attachment.Filename)
+
+	return attachment, nil
+}
+
+// emitScopeChanged emits a scope.changed event when a program's in-scope
+// targets differ from its previous scope snapshot
+func (s *MonitorService) emitScopeChanged(ctx context.Context, program *database.Program, previous, current []string) {
+	added, removed := scopeChanges(previous, current)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	utils.Log(ctx).Infof("Scope of program %s changed: %d added, %d removed", program.Name, len(added), len(removed))
+	s.events.Emit(ctx, events.TypeScopeChanged, program.ProgramURL, events.ScopeChangedData{
+		Program: events.NewProgramData(program),
+		Added:   added,
+		Removed: removed,
+	})
+}
+
+// scopeChanges returns the sorted targets added to and removed from a program's scope
+func scopeChanges(previous, current []string) (added, removed []string) {
+	previousURLs := make(map[string]bool, len(previous))
+	for _, url := range previous {
+		previousURLs[url] = true
+	}
+
+	currentURLs := make(map[string]bool, len(current))
+	for _, url := range current {
+		if currentURLs[url] {
+			continue
+		}
+		currentURLs[url] = true
+		if !previousURLs[url] {
+			added = append(added, url)
+		}
+	}
+
+	for url := range previousURLs {
+		if !currentURLs[url] {
+			removed = append(removed, url)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// AddEventPublisher delivers the service's events to another publisher as
+// well, e.g. to stream them to gRPC clients. It must be called before a scan
+// starts.
+func (s *MonitorService) AddEventPublisher(publisher events.Publisher) {
+	s.events.AddPublisher(publisher)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeChanges(t *testing.T) {
+	previous := []string{"https://example.com", "https://*.example.com", "https://legacy.example.com"}
+	current := []string{"https://example.com", "https://*.example.com", "https://*.example.org", "https://*.example.org", "https://api.example.net"}
+
+	added, removed := scopeChanges(previous, current)
+
+	assert.Equal(t, []string{"https://*.example.org", "https://api.example.net"}, added)
+	assert.Equal(t, []string{"https://legacy.example.com"}, removed)
+}
+
+func TestScopeChanges_Unchanged(t *testing.T) {
+	targets := []string{"https://example.com"}
+
+	added, removed := scopeChanges(targets, targets)
+
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+func TestNewDigestAttachment(t *testing.T) {
+	assets := []*database.Asset{{URL: "https://api.example.com", Domain: "example.com", Status: "active"}}
+	digest := events.ScanDigestData{
+		Program: events.ProgramData{Platform: "hackerone", ProgramURL: "https://hackerone.com/acme"},
+		ScanID:  uuid.New(),
+	}
+
+	inline, err := newDigestAttachment(&config.EventsConfig{DigestAttachment: "csv"}, digest, assets)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", inline.ContentType)
+	assert.Contains(t, inline.Content, "https://api.example.com")
+	assert.Empty(t, inline.URL)
+
+	dir := t.TempDir()
+	linked, err := newDigestAttachment(&config.EventsConfig{
+		DigestAttachment:    "json",
+		DigestAttachmentDir: dir,
+		DigestAttachmentURL: "https://files.example.com/digests/",
+	}, digest, assets)
+	require.NoError(t, err)
+	assert.Empty(t, linked.Content)
+	assert.Equal(t, "https://files.example.com/digests/hackerone-acme-"+digest.ScanID.String()+".json", linked.URL)
+
+	written, err := os.ReadFile(filepath.Join(dir, linked.Filename))
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"url": "https://api.example.com"`)
+}
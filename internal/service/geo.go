@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/whois"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// unusualCountryShare is the share of a program's assets below which a
+// country other than the program's main one is flagged for a closer look
+const unusualCountryShare = 0.1
+
+// GeoSummary is the geographic distribution of a program's assets
+type GeoSummary struct {
+	ProgramID   uuid.UUID   `json:"program_id"`
+	ProgramName string      `json:"program_name"`
+	Assets      int         `json:"assets"` // assets with a known network
+	Countries   []*GeoShare `json:"countries"`
+	Providers   []*GeoShare `json:"providers"`
+}
+
+// GeoShare is the number of a program's assets in one country or at one provider
+type GeoShare struct {
+	Name    string `json:"name"`
+	Assets  int    `json:"assets"`
+	Unusual bool   `json:"unusual,omitempty"` // a minor country next to the program's main one
+}
+
+// enrichAssetNetworks looks up the network of the program's asset IPs that
+// were never looked up or whose lookup is older than the refresh interval.
+// Failures are logged and never fail the scan.
+func (s *MonitorService) enrichAssetNetworks(ctx context.Context, program *database.Program) {
+	if s.whoisClient == nil || !s.config.Whois.IPLookups {
+		return
+	}
+
+	ips, err := s.ipNetworks.GetIPsToEnrich(ctx, program.ID, time.Now().Add(-s.config.Whois.RefreshInterval))
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get IPs to enrich for program %s: %v", program.Name, err)
+		return
+	}
+
+	for _, ip := range ips {
+		if ctx.Err() != nil {
+			return
+		}
+
+		network := &database.IPNetwork{IP: ip}
+		looked, err := s.whoisClient.LookupIP(ctx, ip)
+		switch {
+		case errors.Is(err, whois.ErrNotFound):
+			network.LookupError = err.Error()
+		case err != nil:
+			// Keep the previous data and try again next scan
+			utils.Log(ctx).Warnf("Failed to get network data for %s: %v", ip, err)
+			continue
+		default:
+			network.Country = looked.Country
+			network.Provider = looked.Provider
+			network.NetworkName = looked.Name
+		}
+
+		if err := s.ipNetworks.SaveIPNetwork(ctx, network); err != nil {
+			utils.Log(ctx).Warnf("Failed to save network data for %s: %v", ip, err)
+		}
+	}
+
+	if len(ips) > 0 {
+		utils.Log(ctx).Infof("Looked up networks of %d IPs for program %s", len(ips), program.Name)
+	}
+}
+
+// summarizeGeo groups per-program country and provider counts into one
+// summary per program, largest shares first
+func summarizeGeo(counts []*database.GeoCount) []*GeoSummary {
+	var summaries []*GeoSummary
+	byProgram := make(map[uuid.UUID]*GeoSummary)
+	countries := make(map[uuid.UUID]map[string]int)
+	providers := make(map[uuid.UUID]map[string]int)
+
+	for _, count := range counts {
+		summary, ok := byProgram[count.ProgramID]
+		if !ok {
+			summary = &GeoSummary{ProgramID: count.ProgramID, ProgramName: count.ProgramName}
+			byProgram[count.ProgramID] = summary
+			countries[count.ProgramID] = make(map[string]int)
+			providers[count.ProgramID] = make(map[string]int)
+			summaries = append(summaries, summary)
+		}
+
+		summary.Assets += count.Assets
+		countries[count.ProgramID][geoName(count.Country)] += count.Assets
+		providers[count.ProgramID][geoName(count.Provider)] += count.Assets
+	}
+
+	for _, summary := range summaries {
+		summary.Countries = geoShares(countries[summary.ProgramID])
+		summary.Providers = geoShares(providers[summary.ProgramID])
+
+		for i, share := range summary.Countries {
+			if i > 0 && float64(share.Assets) < unusualCountryShare*float64(summary.Assets) {
+				share.Unusual = true
+			}
+		}
+	}
+
+	return summaries
+}
+
+// geoShares converts counts by name into shares, largest first
+func geoShares(counts map[string]int) []*GeoShare {
+	shares := make([]*GeoShare, 0, len(counts))
+	for name, assets := range counts {
+		shares = append(shares, &GeoShare{Name: name, Assets: assets})
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Assets != shares[j].Assets {
+			return shares[i].Assets > shares[j].Assets
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	return shares
+}
+
+// geoName returns the display name of a country or provider, which the
+// registry may not have published
+func geoName(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
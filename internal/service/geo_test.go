@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeGeo(t *testing.T) {
+	acme := uuid.New()
+	globex := uuid.New()
+
+	summaries := summarizeGeo([]*database.GeoCount{
+		{ProgramID: acme, ProgramName: "Acme", Country: "US", Provider: "Amazon.com, Inc.", Assets: 30},
+		{ProgramID: acme, ProgramName: "Acme", Country: "US", Provider: "Cloudflare, Inc.", Assets: 12},
+		{ProgramID: acme, ProgramName: "Acme", Country: "SG", Provider: "Example Hosting", Assets: 2},
+		{ProgramID: acme, ProgramName: "Acme", Country: "", Provider: "", Assets: 1},
+		{ProgramID: globex, ProgramName: "Globex", Country: "DE", Provider: "Hetzner Online GmbH", Assets: 3},
+		{ProgramID: globex, ProgramName: "Globex", Country: "FR", Provider: "OVH SAS", Assets: 3},
+	})
+	require.Len(t, summaries, 2)
+
+	summary := summaries[0]
+	assert.Equal(t, "Acme", summary.ProgramName)
+	assert.Equal(t, 45, summary.Assets)
+	require.Len(t, summary.Countries, 3)
+	assert.Equal(t, &GeoShare{Name: "US", Assets: 42}, summary.Countries[0])
+	assert.Equal(t, &GeoShare{Name: "SG", Assets: 2, Unusual: true}, summary.Countries[1])
+	assert.Equal(t, &GeoShare{Name: "unknown", Assets: 1, Unusual: true}, summary.Countries[2])
+	require.Len(t, summary.Providers, 4)
+	assert.Equal(t, "Amazon.com, Inc.", summary.Providers[0].Name)
+	assert.False(t, summary.Providers[2].Unusual, "only countries are flagged")
+
+	// An even split has no main country to stand out from
+	summary = summaries[1]
+	assert.Equal(t, 6, summary.Assets)
+	assert.Equal(t, "DE", summary.Countries[0].Name)
+	assert.False(t, summary.Countries[1].Unusual)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+)
+
+var (
+	// ErrProgramNotFound is returned when no active program matches a lookup
+	ErrProgramNotFound = errors.New("program not found")
+	// ErrAmbiguousProgram is returned when a lookup matches programs on several platforms
+	ErrAmbiguousProgram = errors.New("program is ambiguous")
+	// ErrInvalidDomain is returned when an asset lookup is not a valid domain
+	ErrInvalidDomain = errors.New("invalid domain")
+)
+
+// FindAssets returns up to limit assets of a host and its subdomains, or of
+// an apex domain. URLs are accepted and reduced to their host.
+func (s *MonitorService) FindAssets(ctx context.Context, host string, limit int) ([]*database.Asset, error) {
+	host = strings.TrimSpace(host)
+	if strings.Contains(host, "://") {
+		host = database.AssetHostKey(host)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if !s.urlProcessor.IsValidDomain(host) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDomain, host)
+	}
+
+	return s.assetRepo.FindAssetsByHost(ctx, host, limit)
+}
+
+// FindProgram returns the active program with the given name or program URL
+// handle. A handle shared by programs on several platforms can be qualified
+// with the platform, e.g. "hackerone/slack".
+func (s *MonitorService) FindProgram(ctx context.Context, handle string) (*database.Program, error) {
+	platform, name, qualified := strings.Cut(strings.TrimSpace(handle), "/")
+	if !qualified {
+		name = platform
+	}
+
+	programs, err := s.programRepo.FindProgramsByHandle(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*database.Program
+	for _, program := range programs {
+		if !qualified || strings.EqualFold(program.Platform, platform) {
+			matches = append(matches, program)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrProgramNotFound, handle)
+	case 1:
+		return matches[0], nil
+	default:
+		candidates := make([]string, len(matches))
+		for i, program := range matches {
+			candidates[i] = program.Platform + "/" + name
+		}
+		return nil, fmt.Errorf("%w: %q matches %d programs, use one of: %s", ErrAmbiguousProgram, handle, len(matches), strings.Join(candidates, ", "))
+	}
+}
+
+// RescanProgram runs asset discovery for a single program right away, within
+// the program's processing timeout, and returns the scan that was recorded. A
+// program that runs out of time returns its timed_out scan; its remaining
+// domains are continued by the next scan.
+func (s *MonitorService) RescanProgram(ctx context.Context, program *database.Program) (*database.Scan, error) {
+	if err := s.checkWritable("rescan program"); err != nil {
+		return nil, err
+	}
+
+	platform, err := s.platformFactory.GetPlatform(program.Platform)
+	if err != nil {
+		return nil, fmt.Errorf("cannot rescan program %s on platform %s: %w", program.Name, program.Platform, err)
+	}
+
+	timeouts := s.programTimeouts(ctx, program.Platform, []*platforms.Program{{Name: program.Name, ProgramURL: program.ProgramURL}})
+	ctx, cancel := context.WithTimeout(ctx, timeouts[program.ProgramURL])
+	defer cancel()
+
+	utils.Log(ctx).Infof("Rescanning program %s (%s)", program.Name, program.Platform)
+	if err := s.discoverProgramAssets(ctx, program, platform); err != nil && !errors.Is(err, ErrProgramTimedOut) {
+		return nil, fmt.Errorf("failed to rescan program %s: %w", program.Name, err)
+	}
+
+	scans, err := s.scanRepo.GetScansByProgramID(context.WithoutCancel(ctx), program.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scans) == 0 {
+		return nil, fmt.Errorf("no scan recorded for program %s", program.Name)
+	}
+
+	return scans[0], nil
+}
+
+// GetProgram returns a program by ID
+func (s *MonitorService) GetProgram(ctx context.Context, id uuid.UUID) (*database.Program, error) {
+	program, err := s.programRepo.GetProgramByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if program == nil {
+		return nil, fmt.Errorf("%w: %s", ErrProgramNotFound, id)
+	}
+	return program, nil
+}
+
+// ListScans returns up to limit of the most recent scans of a program, or of
+// all programs when programID is uuid.Nil
+func (s *MonitorService) ListScans(ctx context.Context, programID uuid.UUID, limit int) ([]*database.Scan, error) {
+	if programID == uuid.Nil {
+		return s.scanRepo.GetRecentScans(ctx, limit)
+	}
+	return s.scanRepo.GetRecentProgramScans(ctx, programID, limit)
+}
+
+// ListPrograms returns the active programs
+func (s *MonitorService) ListPrograms(ctx context.Context) ([]*database.Program, error) {
+	return s.programRepo.GetAllActivePrograms(ctx)
+}
+
+// QueryAssets returns up to limit assets matching an asset query, ordered by URL
+func (s *MonitorService) QueryAssets(ctx context.Context, query *database.AssetQuery, limit int) ([]*database.Asset, error) {
+	return s.assetRepo.FindAssetsByQuery(ctx, query, limit)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// activeMaintenance returns a platform's maintenance window recorded by an
+// earlier scan whose retry time has not passed yet
+func (s *MonitorService) activeMaintenance(ctx context.Context, platformName string) *database.PlatformMaintenance {
+	window, err := s.maintenanceRepo.GetActiveMaintenance(ctx, platformName)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to check maintenance window for %s: %v", platformName, err)
+		return nil
+	}
+	return window
+}
+
+// endMaintenance closes a platform's open maintenance window after it responded normally
+func (s *MonitorService) endMaintenance(ctx context.Context, platformName string) {
+	if err := s.maintenanceRepo.EndMaintenance(ctx, platformName); err != nil {
+		utils.Log(ctx).Warnf("Failed to end maintenance window for %s: %v", platformName, err)
+	}
+}
+
+// maintenanceDelay returns how long to wait before retrying a platform in maintenance
+func (s *MonitorService) maintenanceDelay(merr *utils.MaintenanceError) time.Duration {
+	if merr.RetryAfter > 0 {
+		return merr.RetryAfter
+	}
+	return s.config.Maintenance.RetryDelay
+}
+
+// pauseForMaintenance records a maintenance window and, if retries remain and
+// the wait is short enough, blocks until the platform should be retried. It
+// reports whether the caller should retry; when it returns false the platform
+// is deferred to the next scan, which skips it until the recorded retry time.
+func (s *MonitorService) pauseForMaintenance(ctx context.Context, merr *utils.MaintenanceError, attempt int) bool {
+	delay := s.maintenanceDelay(merr)
+	if err := s.maintenanceRepo.RecordMaintenance(ctx, merr.Platform, merr.Reason, merr.StatusCode, time.Now().Add(delay)); err != nil {
+		utils.Log(ctx).Warnf("Failed to record maintenance window for %s: %v", merr.Platform, err)
+	}
+
+	if attempt >= s.config.Maintenance.MaxRetries || delay > s.config.Maintenance.MaxWait {
+		utils.Log(ctx).Warnf("Platform %s is in maintenance (%s); deferring it to a scan after %v",
+			merr.Platform, merr.Reason, time.Now().Add(delay).Format(time.RFC3339))
+		return false
+	}
+
+	utils.Log(ctx).Warnf("Platform %s is in maintenance (%s); pausing its scan for %v (retry %d/%d)",
+		merr.Platform, merr.Reason, delay, attempt+1, s.config.Maintenance.MaxRetries)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// withMaintenanceRetry runs fn, pausing and retrying while the platform reports maintenance
+func (s *MonitorService) withMaintenanceRetry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		merr, ok := utils.AsMaintenanceError(err)
+		if !ok || !s.pauseForMaintenance(ctx, merr, attempt) {
+			return err
+		}
+	}
+}
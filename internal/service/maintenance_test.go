@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMaintenanceTestService(t *testing.T, maintenance config.MaintenanceConfig) (*MonitorService, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	return &MonitorService{
+		config:          &config.Config{Maintenance: maintenance},
+		maintenanceRepo: database.NewMaintenanceRepository(sqlxDB),
+	}, mock
+}
+
+func TestWithMaintenanceRetry_RecoversAfterPause(t *testing.T) {
+	s, mock := newMaintenanceTestService(t, config.MaintenanceConfig{
+		RetryDelay: time.Millisecond,
+		MaxRetries: 2,
+		MaxWait:    time.Second,
+	})
+
+	mock.ExpectExec("INSERT INTO platform_maintenance").
+		WithArgs(sqlmock.AnyArg(), "hackerone", "down for maintenance", 503, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	calls := 0
+	err := s.withMaintenanceRetry(context.Background(), func() error {
+		calls++
+		if calls == 1 {
+			return &utils.MaintenanceError{Platform: "hackerone", StatusCode: 503, Reason: "down for maintenance"}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithMaintenanceRetry_DefersWhenRetriesExhausted(t *testing.T) {
+	s, mock := newMaintenanceTestService(t, config.MaintenanceConfig{
+		RetryDelay: time.Millisecond,
+		MaxRetries: 1,
+		MaxWait:    time.Second,
+	})
+
+	mock.ExpectExec("INSERT INTO platform_maintenance").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO platform_maintenance").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	calls := 0
+	err := s.withMaintenanceRetry(context.Background(), func() error {
+		calls++
+		return &utils.MaintenanceError{Platform: "bugcrowd", StatusCode: 503, Reason: "maintenance"}
+	})
+
+	_, ok := utils.AsMaintenanceError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPauseForMaintenance_RetryAfterBeyondMaxWait(t *testing.T) {
+	s, mock := newMaintenanceTestService(t, config.MaintenanceConfig{
+		RetryDelay: time.Millisecond,
+		MaxRetries: 3,
+		MaxWait:    time.Minute,
+	})
+
+	mock.ExpectExec("INSERT INTO platform_maintenance").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	merr := &utils.MaintenanceError{Platform: "hackerone", StatusCode: 503, Reason: "maintenance", RetryAfter: 2 * time.Hour}
+	assert.False(t, s.pauseForMaintenance(context.Background(), merr, 0))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
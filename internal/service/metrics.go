@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/utils"
+)
+
+const (
+	// metricsCollectInterval is how often the gauges of the process and the
+	// database connection pool are refreshed
+	metricsCollectInterval = 15 * time.Second
+	// freshnessCollectInterval is how often the freshness SLO gauges are
+	// refreshed, which queries every active program
+	freshnessCollectInterval = 5 * time.Minute
+)
+
+// Metrics returns the metrics the service records, or nil when metrics are
+// disabled
+func (s *MonitorService) Metrics() *metrics.Metrics {
+	return s.metrics
+}
+
+// recordScanMetrics records the outcome of a program scan once it finished,
+// and the assets it found first by discovery source
+func (s *MonitorService) recordScanMetrics(ctx context.Context, platformName string, scan *database.Scan) {
+	if s.metrics == nil {
+		return
+	}
+
+	switch scan.Status {
+	case "completed":
+		s.metrics.RecordScanCompleted(platformName)
+		if scan.AssetsFound == 0 {
+			s.metrics.RecordZeroAssetScan(platformName)
+		}
+	case "failed", "timed_out":
+		s.metrics.RecordScanFailed(platformName, scan.Status)
+	}
+
+	yields, err := s.assetRepo.GetScanSourceYield(ctx, scan.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to count the new assets of scan %s for metrics: %v", scan.ID, err)
+		return
+	}
+	for source, count := range yields {
+		s.metrics.RecordAssetsDiscovered(platformName, source, count)
+	}
+}
+
+// RunMetricsCollection refreshes the gauges of the process, the database
+// connection pool and the freshness SLOs until ctx is done
+func (s *MonitorService) RunMetricsCollection(ctx context.Context) {
+	if s.metrics == nil {
+		return
+	}
+
+	ticker := time.NewTicker(metricsCollectInterval)
+	defer ticker.Stop()
+
+	var freshnessAt time.Time
+	for {
+		s.metrics.UpdateSystemMetrics()
+
+		stats := s.programRepo.GetDB().Stats()
+		s.metrics.UpdateConnectionPool(stats.OpenConnections, stats.Idle)
+		s.metrics.UpdateConnectionPoolLimit(stats.MaxOpenConnections)
+
+		if time.Since(freshnessAt) >= freshnessCollectInterval {
+			freshnessAt = time.Now()
+			s.updateFreshnessMetrics(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateFreshnessMetrics records the compliance with the enabled freshness SLOs
+func (s *MonitorService) updateFreshnessMetrics(ctx context.Context) {
+	if s.config.SLO.ProgramScanWithin <= 0 && s.config.SLO.AssetProbeWithin <= 0 {
+		return
+	}
+
+	report, err := s.GetFreshness(ctx)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to refresh freshness SLO metrics: %v", err)
+		return
+	}
+	if report.ProgramScanWithin > 0 {
+		s.metrics.UpdateFreshnessSLO("program_scan", report.Programs, report.ProgramsViolating)
+	}
+	if report.AssetProbeWithin > 0 {
+		s.metrics.UpdateFreshnessSLO("asset_probe", report.Assets, report.AssetsViolating)
+	}
+}
@@ -3,8 +3,11 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,9 +16,22 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/monitor-agent/internal/config"
 	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery"
 	"github.com/monitor-agent/internal/discovery/chaosdb"
+	"github.com/monitor-agent/internal/discovery/crtsh"
+	"github.com/monitor-agent/internal/discovery/dns"
 	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/discovery/probeworker"
+	"github.com/monitor-agent/internal/discovery/whois"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/monitor-agent/internal/objectstore"
 	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/probeauth"
+	"github.com/monitor-agent/internal/rules"
+	"github.com/monitor-agent/internal/scope"
+	"github.com/monitor-agent/internal/scoring"
+	"github.com/monitor-agent/internal/search"
 	"github.com/monitor-agent/internal/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -26,10 +42,45 @@ type MonitorService struct {
 	programRepo     *database.ProgramRepository
 	assetRepo       *database.AssetRepository
 	scanRepo        *database.ScanRepository
+	maintenanceRepo *database.MaintenanceRepository
+	schemaDriftRepo *database.SchemaDriftRepository
+	quotaRepo       *database.QuotaRepository
+	changeRepo      *database.AssetChangeRepository
+	apiSchemaRepo   *database.APISchemaRepository
+	tagRepo         *database.TagRepository
+	tlsFindingRepo  *database.TLSFindingRepository
+	registrations   *database.RegistrationRepository
+	ipNetworks      *database.IPNetworkRepository
+	continuations   *database.ContinuationRepository
+	scanRuns        *database.ScanRunRepository
+	scopeRepo       *database.ScopeRepository
+	coverageRepo    *database.CoverageRepository
+	failureRepo     *database.DiscoveryFailureRepository
+	probeAuthRepo   *database.ProbeAuthRepository
+	probeAuthSealer *probeauth.Sealer
+	watchlistRepo   *database.WatchlistRepository
+	writeThrottle   *database.WriteThrottle
 	platformFactory *platforms.PlatformFactory
 	chaosDBClient   *chaosdb.Client
+	extraSources    []discovery.Source // subdomain sources queried after ChaosDB, e.g. crt.sh
 	httpxClient     *httpx.Client
+	prober          probeworker.Prober
 	urlProcessor    *utils.URLProcessor
+	events          *events.Emitter
+	rules           *rules.Engine
+	scoring         *scoring.Model
+	scoreRepo       *database.ScoreRepository
+	clusterRepo     *database.ClusterRepository
+	freshnessRepo   *database.FreshnessRepository
+	searchIndexer   *search.Indexer
+	bodyStore       objectstore.Store // nil when bodies are stored in the database
+	whoisClient     *whois.Client
+	dnsClient       *dns.Client
+	dnsRepo         *database.DNSRepository
+	metrics         *metrics.Metrics                                             // nil unless METRICS_ENABLED
+	resolveHost     func(ctx context.Context, hostname string) ([]string, error) // overrides the system resolver in tests
+	runningScans    runningScans
+	programBudget   programBudget // programs processed at once across all platforms
 }
 
 // NewMonitorService creates a new monitor service
@@ -39,18 +90,33 @@ func NewMonitorService(cfg *config.Config, db *sqlx.DB) *MonitorService {
 	assetRepo := database.NewAssetRepository(db)
 	scanRepo := database.NewScanRepository(db)
 
+	// Record scans, platform requests and the scan's database writes when
+	// the metrics endpoint is enabled
+	var m *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		m = metrics.NewMetrics()
+		programRepo.SetMetrics(m)
+		assetRepo.SetMetrics(m)
+		scanRepo.SetMetrics(m)
+	}
+
 	// Initialize platform factory
 	platformFactory := platforms.NewPlatformFactory()
 
 	// Only register platforms that have API keys configured
 	if cfg.HasHackerOneConfig() {
 		platformFactory.RegisterPlatform("hackerone", &platforms.PlatformConfig{
-			APIKey:        cfg.APIs.HackerOne.APIKey,
-			Username:      cfg.APIs.HackerOne.Username,
-			RateLimit:     cfg.APIs.HackerOne.RateLimit,
-			Timeout:       cfg.HTTP.Timeout,
-			RetryAttempts: cfg.HTTP.RetryAttempts,
-			RetryDelay:    cfg.HTTP.RetryDelay,
+			APIKey:         cfg.APIs.HackerOne.APIKey,
+			Username:       cfg.APIs.HackerOne.Username,
+			RateLimit:      cfg.APIs.HackerOne.RateLimit,
+			RateBurst:      cfg.APIs.HackerOne.RateBurst,
+			Timeout:        cfg.HTTP.Timeout,
+			RetryAttempts:  cfg.HTTP.RetryAttempts,
+			RetryDelay:     cfg.HTTP.RetryDelay,
+			Credentials:    platformCredentials(cfg.APIs.HackerOne.Credentials),
+			BaseURL:        cfg.APIs.HackerOne.BaseURL,
+			IncludePrivate: cfg.APIs.HackerOne.IncludePrivate,
+			Metrics:        m,
 		})
 		logrus.Info("HackerOne platform configured")
 	} else {
@@ -61,33 +127,73 @@ func NewMonitorService(cfg *config.Config, db *sqlx.DB) *MonitorService {
 		platformFactory.RegisterPlatform("bugcrowd", &platforms.PlatformConfig{
 			APIKey:        cfg.APIs.BugCrowd.APIKey,
 			RateLimit:     cfg.APIs.BugCrowd.RateLimit,
+			RateBurst:     cfg.APIs.BugCrowd.RateBurst,
 			Timeout:       cfg.HTTP.Timeout,
 			RetryAttempts: cfg.HTTP.RetryAttempts,
 			RetryDelay:    cfg.HTTP.RetryDelay,
+			Credentials:   platformCredentials(cfg.APIs.BugCrowd.Credentials),
+			BaseURL:       cfg.APIs.BugCrowd.BaseURL,
+			Metrics:       m,
 		})
 		logrus.Info("BugCrowd platform configured")
 	} else {
 		logrus.Warn("BugCrowd API key not provided, skipping BugCrowd platform")
 	}
 
+	if cfg.HasIntigritiConfig() {
+		platformFactory.RegisterPlatform("intigriti", &platforms.PlatformConfig{
+			APIKey:        cfg.APIs.Intigriti.APIKey,
+			RateLimit:     cfg.APIs.Intigriti.RateLimit,
+			RateBurst:     cfg.APIs.Intigriti.RateBurst,
+			Timeout:       cfg.HTTP.Timeout,
+			RetryAttempts: cfg.HTTP.RetryAttempts,
+			RetryDelay:    cfg.HTTP.RetryDelay,
+			Credentials:   platformCredentials(cfg.APIs.Intigriti.Credentials),
+			BaseURL:       cfg.APIs.Intigriti.BaseURL,
+			Metrics:       m,
+		})
+		logrus.Info("Intigriti platform configured")
+	} else {
+		logrus.Warn("Intigriti API key not provided, skipping Intigriti platform")
+	}
+
 	// Initialize ChaosDB client (only if API key is provided)
 	var chaosDBClient *chaosdb.Client
 	if cfg.HasChaosDBConfig() {
 		chaosDBClient = chaosdb.NewClient(&chaosdb.ClientConfig{
-			APIKey:        cfg.APIs.ChaosDB.APIKey,
-			RateLimit:     cfg.APIs.ChaosDB.RateLimit,
-			Timeout:       cfg.HTTP.Timeout,
-			RetryAttempts: cfg.HTTP.RetryAttempts,
-			RetryDelay:    cfg.HTTP.RetryDelay,
+			APIKey:          cfg.APIs.ChaosDB.APIKey,
+			RateLimit:       cfg.APIs.ChaosDB.RateLimit,
+			RateBurst:       cfg.APIs.ChaosDB.RateBurst,
+			Timeout:         cfg.HTTP.Timeout,
+			RetryAttempts:   cfg.HTTP.RetryAttempts,
+			RetryDelay:      cfg.HTTP.RetryDelay,
+			BaseURL:         cfg.APIs.ChaosDB.BaseURL,
+			DatasetIndexURL: cfg.APIs.ChaosDB.DatasetIndexURL,
+			Metrics:         m,
 		})
 		logrus.Info("ChaosDB client configured")
 	} else {
 		logrus.Warn("ChaosDB API key not provided, ChaosDB discovery will be disabled")
 	}
 
+	// Initialize the discovery sources queried besides ChaosDB
+	var extraSources []discovery.Source
+	if cfg.APIs.CRTSh.Enabled {
+		extraSources = append(extraSources, crtsh.NewClient(&crtsh.ClientConfig{
+			BaseURL:       cfg.APIs.CRTSh.BaseURL,
+			Timeout:       cfg.HTTP.Timeout,
+			RetryAttempts: cfg.HTTP.RetryAttempts,
+			RetryDelay:    cfg.HTTP.RetryDelay,
+		}))
+		logrus.Info("crt.sh discovery configured")
+	}
+
 	// Initialize HTTPX client (only if enabled)
 	var httpxClient *httpx.Client
-	if cfg.Discovery.HTTPX.Enabled {
+	var prober probeworker.Prober
+	if cfg.App.Passive {
+		logrus.Info("Passive mode: HTTPX probing, TLS checks and remote probe workers are disabled, no packets are sent to targets")
+	} else if cfg.Discovery.HTTPX.Enabled {
 		httpxClient = httpx.NewClient(&httpx.ProbeConfig{
 			Timeout:         cfg.Discovery.HTTPX.Timeout,
 			Concurrency:     cfg.Discovery.HTTPX.Concurrency,
@@ -95,8 +201,25 @@ func NewMonitorService(cfg *config.Config, db *sqlx.DB) *MonitorService {
 			FollowRedirects: cfg.Discovery.HTTPX.FollowRedirects,
 			MaxRedirects:    cfg.Discovery.HTTPX.MaxRedirects,
 			Debug:           cfg.Discovery.HTTPX.Debug,
+			IPVersion:       cfg.Discovery.HTTPX.IPVersion,
+			TLSChecks:       cfg.Discovery.HTTPX.TLSChecks,
 		})
 		logrus.Info("HTTPX probe client configured")
+
+		prober = httpxClient
+		if len(cfg.Vantage.Workers) > 0 {
+			workers := make([]*probeworker.Client, 0, len(cfg.Vantage.Workers))
+			for _, worker := range cfg.Vantage.Workers {
+				workers = append(workers, probeworker.NewClient(&probeworker.ClientConfig{
+					Region:  worker.Region,
+					URL:     worker.URL,
+					Token:   cfg.Vantage.Token,
+					Timeout: cfg.Discovery.Timeouts.ChaosDiscovery,
+				}))
+			}
+			prober = probeworker.NewMultiProber(httpxClient, cfg.Vantage.Region, workers)
+			logrus.Infof("Probing from %d remote regions in addition to %s", len(workers), cfg.Vantage.Region)
+		}
 	} else {
 		logrus.Info("HTTPX probe disabled")
 	}
@@ -106,30 +229,136 @@ func NewMonitorService(cfg *config.Config, db *sqlx.DB) *MonitorService {
 		programRepo:     programRepo,
 		assetRepo:       assetRepo,
 		scanRepo:        scanRepo,
+		maintenanceRepo: database.NewMaintenanceRepository(db),
+		schemaDriftRepo: database.NewSchemaDriftRepository(db),
+		quotaRepo:       database.NewQuotaRepository(db),
+		changeRepo:      database.NewAssetChangeRepository(db),
+		apiSchemaRepo:   database.NewAPISchemaRepository(db),
+		tagRepo:         database.NewTagRepository(db),
+		tlsFindingRepo:  database.NewTLSFindingRepository(db),
+		registrations:   database.NewRegistrationRepository(db),
+		ipNetworks:      database.NewIPNetworkRepository(db),
+		continuations:   database.NewContinuationRepository(db),
+		scanRuns:        database.NewScanRunRepository(db),
+		scopeRepo:       database.NewScopeRepository(db),
+		coverageRepo:    database.NewCoverageRepository(db),
+		failureRepo:     database.NewDiscoveryFailureRepository(db),
+		probeAuthRepo:   database.NewProbeAuthRepository(db),
+		probeAuthSealer: newProbeAuthSealer(cfg),
+		watchlistRepo:   database.NewWatchlistRepository(db),
+		writeThrottle:   database.NewWriteThrottle(cfg.Database.WriteBatchSize, cfg.Database.WritesPerSecond),
 		platformFactory: platformFactory,
 		chaosDBClient:   chaosDBClient,
+		extraSources:    extraSources,
 		httpxClient:     httpxClient,
+		prober:          prober,
 		urlProcessor:    utils.NewURLProcessor(),
+		events:          newEventEmitter(cfg),
+		rules:           loadRules(cfg),
+		scoring:         loadScoringModel(cfg),
+		scoreRepo:       database.NewScoreRepository(db),
+		clusterRepo:     database.NewClusterRepository(db),
+		freshnessRepo:   database.NewFreshnessRepository(db),
+		searchIndexer:   newSearchIndexer(cfg),
+		bodyStore:       newBodyStore(cfg),
+		whoisClient:     newWhoisClient(cfg),
+		dnsClient:       newDNSClient(cfg),
+		dnsRepo:         database.NewDNSRepository(db),
+		metrics:         m,
+		programBudget:   newProgramBudget(cfg.Discovery.ProgramConcurrency),
 	}
 }
 
+// platformCredentials converts configured extra accounts into platform credentials
+func platformCredentials(configured []config.PlatformCredential) []platforms.Credential {
+	var credentials []platforms.Credential
+	for _, credential := range configured {
+		credentials = append(credentials, platforms.Credential{
+			Name:     credential.Name,
+			Username: credential.Username,
+			APIKey:   credential.APIKey,
+		})
+	}
+	return credentials
+}
+
 // GetConfig returns the service configuration
 func (s *MonitorService) GetConfig() *config.Config {
 	return s.config
 }
 
+// Close flushes and closes the event publishers
+func (s *MonitorService) Close() error {
+	return s.events.Close()
+}
+
+// FullScanOptions resumes or narrows a full scan
+type FullScanOptions struct {
+	// Resume continues the latest full scan when it did not complete,
+	// skipping the platforms and programs it already processed. Without such
+	// a scan a complete scan is performed.
+	Resume bool
+	// Platforms limits the scan to these configured platforms; all when empty
+	Platforms []string
+}
+
 // RunFullScan performs a complete scan of all platforms
 func (s *MonitorService) RunFullScan(ctx context.Context) error {
-	logrus.Info("Starting full scan of all bug bounty platforms")
+	return s.RunFullScanWith(ctx, FullScanOptions{})
+}
+
+// RunFullScanWith scans all platforms, or the given ones, recording its
+// progress as a scan run
+func (s *MonitorService) RunFullScanWith(ctx context.Context, opts FullScanOptions) error {
+	if err := s.checkWritable("scan"); err != nil {
+		return err
+	}
+	if opts.Resume && len(opts.Platforms) > 0 {
+		return fmt.Errorf("a resumed scan covers every platform of the scan it continues and cannot be limited to some")
+	}
 
-	// Get all platforms
-	platformList := s.platformFactory.GetAllPlatforms()
+	utils.Log(ctx).Info("Starting full scan of all bug bounty platforms")
+
+	// Bound the whole scan when an overall scan timeout is configured
+	if scanTimeout := s.config.Discovery.Timeouts.Scan; scanTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, scanTimeout, ErrScanTimedOut)
+		defer cancel()
+		utils.Log(ctx).Infof("Scan timeout set to %v", scanTimeout)
+	}
+
+	// Get all platforms, or the selected ones
+	platformList, err := s.selectPlatforms(opts.Platforms)
+	if err != nil {
+		return err
+	}
 	if len(platformList) == 0 {
-		logrus.Warn("No platforms configured with API keys. Please provide at least one API key (HACKERONE_USERNAME+HACKERONE_API_KEY, BUGCROWD_API_KEY, or CHAOSDB_API_KEY) to perform scans.")
+		utils.Log(ctx).Warn("No platforms configured with API keys. Please provide at least one API key (HACKERONE_USERNAME+HACKERONE_API_KEY, BUGCROWD_API_KEY, INTIGRITI_API_KEY, or CHAOSDB_API_KEY) to perform scans.")
 		return fmt.Errorf("no platforms configured with API keys")
 	}
 
-	logrus.Infof("Starting scan of %d platforms", len(platformList))
+	// Canaries confirm that DNS and probing work before any target is probed
+	if err := s.checkCanaries(ctx); err != nil {
+		return err
+	}
+
+	var checkpoint *scanCheckpoint
+	if opts.Resume {
+		if checkpoint, err = s.resumeScanRun(ctx); err != nil {
+			return fmt.Errorf("failed to resume scan: %w", err)
+		}
+		if checkpoint == nil {
+			utils.Log(ctx).Info("No interrupted scan to resume, starting a full scan")
+		}
+	}
+	if checkpoint == nil {
+		checkpoint = s.startScanRun(ctx)
+	}
+	if checkpoint != nil {
+		ctx = utils.WithLogFields(ctx, logrus.Fields{"scan_run_id": checkpoint.run.ID})
+	}
+
+	utils.Log(ctx).Infof("Starting scan of %d platforms", len(platformList))
 
 	var wg sync.WaitGroup
 	errors := make(chan error, len(platformList))
@@ -140,85 +369,255 @@ func (s *MonitorService) RunFullScan(ctx context.Context) error {
 		go func(p platforms.Platform, platformIndex int) {
 			defer func() {
 				if r := recover(); r != nil {
-					logrus.Errorf("Platform %s scan panicked: %v", p.GetName(), r)
+					utils.Log(ctx).Errorf("Platform %s scan panicked: %v", p.GetName(), r)
 					errors <- fmt.Errorf("platform %s scan panicked: %v", p.GetName(), r)
 				}
 				wg.Done()
 			}()
 
-			logrus.Infof("Starting scan of platform %d/%d: %s", platformIndex+1, len(platformList), p.GetName())
+			utils.Log(ctx).Infof("Starting scan of platform %d/%d: %s", platformIndex+1, len(platformList), p.GetName())
 
 			startTime := time.Now()
-			if err := s.scanPlatform(ctx, p); err != nil {
-				logrus.Errorf("Platform %s scan failed after %v: %v", p.GetName(), time.Since(startTime), err)
+			if err := s.scanPlatform(ctx, p, checkpoint); err != nil {
+				utils.Log(ctx).Errorf("Platform %s scan failed after %v: %v", p.GetName(), time.Since(startTime), err)
 				errors <- fmt.Errorf("failed to scan platform %s: %w", p.GetName(), err)
 			} else {
-				logrus.Infof("Platform %s scan completed successfully in %v", p.GetName(), time.Since(startTime))
+				utils.Log(ctx).Infof("Platform %s scan completed successfully in %v", p.GetName(), time.Since(startTime))
 			}
 		}(platform, i)
 	}
 
-	logrus.Info("Waiting for all platform scans to complete...")
+	utils.Log(ctx).Info("Waiting for all platform scans to complete...")
 	wg.Wait()
 	close(errors)
 
+	// Keep the payload changes the platform clients noticed
+	s.recordSchemaDrift(ctx)
+
+	// Watched hostnames are checked every cycle, whether or not they are alive
+	s.checkWatchlist(ctx)
+
+	// Regroup near-identical responses now that the scan stored new ones
+	s.clusterAfterScan(ctx)
+
+	// Drop the responses the new ones pushed out of the retention
+	s.pruneAfterScan(ctx)
+
 	// Collect errors
 	var errs []error
 	for err := range errors {
 		errs = append(errs, err)
 	}
 
+	switch {
+	case ctx.Err() != nil:
+		checkpoint.finish(ctx, database.ScanRunInterrupted)
+	case len(errs) > 0:
+		checkpoint.finish(ctx, database.ScanRunFailed)
+	default:
+		checkpoint.finish(ctx, database.ScanRunCompleted)
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("scan completed with %d errors: %v", len(errs), errs)
 	}
 
-	logrus.Info("Full scan completed successfully")
+	if waited := s.writeThrottle.Waited(); waited > 0 {
+		utils.Log(ctx).Infof("Database write throttle held back writes for %v", waited.Round(time.Second))
+	}
+
+	utils.Log(ctx).Info("Full scan completed successfully")
 	return nil
 }
 
-// scanPlatform scans a single platform
-func (s *MonitorService) scanPlatform(ctx context.Context, platform platforms.Platform) error {
+// selectPlatforms returns the named platforms, or every configured platform
+// when no names are given
+func (s *MonitorService) selectPlatforms(names []string) ([]platforms.Platform, error) {
+	if len(names) == 0 {
+		return s.platformFactory.GetAllPlatforms(), nil
+	}
+
+	var selected []platforms.Platform
+	var selectedNames []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(selectedNames, name) {
+			continue
+		}
+		selectedNames = append(selectedNames, name)
+
+		platform, err := s.platformFactory.GetPlatform(name)
+		if err != nil {
+			configured := strings.Join(s.platformFactory.PlatformNames(), ", ")
+			if configured == "" {
+				configured = "none"
+			}
+			return nil, fmt.Errorf("platform %q is not configured (configured platforms: %s)", name, configured)
+		}
+		selected = append(selected, platform)
+	}
+
+	logrus.Infof("Scanning only the selected platforms: %s", strings.Join(selectedNames, ", "))
+	return selected, nil
+}
+
+// scanPlatform scans a single platform, skipping the programs the checkpoint
+// shows were already processed
+func (s *MonitorService) scanPlatform(ctx context.Context, platform platforms.Platform, checkpoint *scanCheckpoint) error {
 	platformName := platform.GetName()
-	logrus.Infof("Scanning platform: %s", platformName)
+	ctx = utils.WithLogFields(ctx, logrus.Fields{"platform": platformName})
+	if checkpoint.platformDone(platformName) {
+		utils.Log(ctx).Infof("All programs on %s were processed before the scan was interrupted, skipping it", platformName)
+		return nil
+	}
+	utils.Log(ctx).Infof("Scanning platform: %s", platformName)
+
+	// Honor a maintenance window recorded by an earlier scan
+	if window := s.activeMaintenance(ctx, platformName); window != nil {
+		utils.Log(ctx).Infof("Platform %s is in maintenance (%s) until %s, skipping it this scan",
+			platformName, window.Reason, window.RetryAt.Format(time.RFC3339))
+		return nil
+	}
 
 	// Check platform health
-	if err := platform.IsHealthy(ctx); err != nil {
+	err := s.withMaintenanceRetry(ctx, func() error { return platform.IsHealthy(ctx) })
+	if _, ok := utils.AsMaintenanceError(err); ok {
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("platform %s is not healthy: %w", platformName, err)
 	}
+	s.endMaintenance(ctx, platformName)
 
 	// Get public programs from platform
-	programs, err := platform.GetPublicPrograms(ctx)
+	var programs []*platforms.Program
+	err = s.withMaintenanceRetry(ctx, func() error {
+		var err error
+		programs, err = platform.GetPublicPrograms(ctx)
+		return err
+	})
+	if _, ok := utils.AsMaintenanceError(err); ok {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get programs from %s: %w", platformName, err)
 	}
 
-	logrus.Infof("Found %d programs on platform %s", len(programs), platformName)
+	utils.Log(ctx).Infof("Found %d programs on platform %s", len(programs), platformName)
+	programs = s.selectPrograms(ctx, platformName, programs)
+
+	// Programs violating the scan freshness SLO go first
+	programs = s.prioritizeOverduePrograms(ctx, platformName, programs)
+	checkpoint.startPlatform(ctx, platformName, len(programs))
 
-	// Process each program with individual timeouts
+	// Programs whose recent scans ran long get more time
+	timeouts := s.programTimeouts(ctx, platformName, programs)
+
+	processed := 0
+	for _, program := range programs {
+		if checkpoint.programDone(platformName, program.ProgramURL) {
+			processed++
+		}
+	}
+	if processed > 0 {
+		utils.Log(ctx).Infof("Skipping %d programs on %s processed before the scan was interrupted", processed, platformName)
+	}
+
+	// Process the programs with individual timeouts, as many at once as the
+	// global program budget allows
+	var pending []int
 	for i, program := range programs {
-		logrus.Infof("Processing program %d/%d: %s", i+1, len(programs), program.Name)
+		if !checkpoint.programDone(platformName, program.ProgramURL) {
+			pending = append(pending, i)
+		}
+	}
 
-		// Create a timeout context for each program
-		programCtx, cancel := context.WithTimeout(ctx, s.config.Discovery.Timeouts.ProgramProcess)
+	var (
+		mu                 sync.Mutex
+		wg                 sync.WaitGroup
+		timedOut           []*platforms.Program
+		maintenance        *utils.MaintenanceError
+		interrupted        []int // programs stopped by maintenance, retried after the pause
+		maintenanceAttempt int
+	)
+	for {
+		for len(pending) > 0 {
+			mu.Lock()
+			paused := maintenance != nil
+			mu.Unlock()
+			if paused || !s.programBudget.acquire(ctx) {
+				break
+			}
 
-		// Process the program with timeout and panic recovery
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					logrus.Errorf("Program %s processing panicked: %v", program.Name, r)
+			i := pending[0]
+			pending = pending[1:]
+			program := programs[i]
+			utils.Log(ctx).Infof("Processing program %d/%d: %s", i+1, len(programs), program.Name)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer s.programBudget.release()
+
+				programErr := s.runProgram(ctx, platform, program, timeouts[program.ProgramURL])
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				// The platform went into maintenance mid-scan; pause and retry this program
+				if merr, ok := utils.AsMaintenanceError(programErr); ok {
+					if maintenance == nil {
+						maintenance = merr
+					}
+					interrupted = append(interrupted, i)
+					return
+				}
+				maintenanceAttempt = 0
+
+				if errors.Is(programErr, ErrProgramTimedOut) {
+					utils.Log(ctx).Warnf("Program %s timed out, continuing it after the other programs", program.Name)
+					timedOut = append(timedOut, program)
+				} else if programErr != nil {
+					utils.Log(ctx).Errorf("Failed to process program %s: %v", program.Name, programErr)
+					// Continue to next program instead of failing the entire scan
+				} else if ctx.Err() == nil {
+					checkpoint.programProcessed(ctx, platformName, program.ProgramURL)
+					processed++
 				}
 			}()
+		}
+		wg.Wait()
 
-			if err := s.processProgram(programCtx, platform, program); err != nil {
-				logrus.Errorf("Failed to process program %s: %v", program.Name, err)
-				// Continue to next program instead of failing the entire scan
-			}
-		}()
+		if maintenance == nil {
+			break
+		}
+		pending = append(interrupted, pending...)
+		merr := maintenance
+		maintenance, interrupted = nil, nil
+		if !s.pauseForMaintenance(ctx, merr, maintenanceAttempt) {
+			utils.Log(ctx).Warnf("Deferring the remaining %d programs on %s to the next scan", len(pending), platformName)
+			return nil
+		}
+		maintenanceAttempt++
+	}
 
-		cancel()
+	// Programs that ran out of time get a second chance at their remaining domains
+	for _, program := range timedOut {
+		if !s.programBudget.acquire(ctx) {
+			break
+		}
 
-		// Small delay between programs to avoid overwhelming the system
-		time.Sleep(100 * time.Millisecond)
+		utils.Log(ctx).Infof("Continuing timed-out program %s", program.Name)
+		programErr := s.runProgram(ctx, platform, program, timeouts[program.ProgramURL])
+		s.programBudget.release()
+		if errors.Is(programErr, ErrProgramTimedOut) {
+			utils.Log(ctx).Warnf("Program %s timed out again, its remaining domains are continued next scan", program.Name)
+		} else if programErr != nil {
+			utils.Log(ctx).Errorf("Failed to continue program %s: %v", program.Name, programErr)
+		} else if ctx.Err() == nil {
+			checkpoint.programProcessed(ctx, platformName, program.ProgramURL)
+			processed++
+		}
 	}
 
 	// Mark inactive programs
@@ -226,19 +625,35 @@ func (s *MonitorService) scanPlatform(ctx context.Context, platform platforms.Pl
 		return fmt.Errorf("failed to mark inactive programs for %s: %w", platformName, err)
 	}
 
+	// Programs that failed or timed out are processed again when the scan is resumed
+	if processed == len(programs) && ctx.Err() == nil {
+		checkpoint.completePlatform(ctx, platformName)
+	}
+
 	return nil
 }
 
-// processProgram processes a single program
-func (s *MonitorService) processProgram(ctx context.Context, platform platforms.Platform, program *platforms.Program) error {
-	// Add panic recovery
+// runProgram processes a program within its own timeout. A panic is
+// recovered and returned as the program's error, so the program is not
+// recorded as processed and a resumed scan processes it again.
+func (s *MonitorService) runProgram(ctx context.Context, platform platforms.Platform, program *platforms.Program, timeout time.Duration) (programErr error) {
+	// Create a timeout context for the program
+	programCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	defer func() {
 		if r := recover(); r != nil {
-			logrus.Errorf("processProgram panicked for program %s: %v", program.Name, r)
+			programErr = fmt.Errorf("program %s panicked: %v", program.Name, r)
 		}
 	}()
 
-	logrus.Infof("Processing program: %s (%s)", program.Name, program.Platform)
+	return s.processProgram(programCtx, platform, program)
+}
+
+// processProgram processes a single program
+func (s *MonitorService) processProgram(ctx context.Context, platform platforms.Platform, program *platforms.Program) error {
+	ctx = utils.WithLogFields(ctx, logrus.Fields{"platform": program.Platform, "program": program.ProgramURL})
+	utils.Log(ctx).Infof("Processing program: %s (%s)", program.Name, program.Platform)
 
 	// Check if program already exists in database using ProgramURL as the unique identifier
 	existingProgram, err := s.programRepo.GetProgramByPlatformAndProgramURL(ctx, program.Platform, program.ProgramURL)
@@ -246,27 +661,56 @@ func (s *MonitorService) processProgram(ctx context.Context, platform platforms.
 		return fmt.Errorf("failed to check existing program: %w", err)
 	}
 
+	// The handle may have changed; fall back to the stable platform ID
+	if existingProgram == nil && program.PlatformID != "" {
+		existingProgram, err = s.programRepo.GetProgramByPlatformID(ctx, program.Platform, program.PlatformID)
+		if err != nil {
+			return fmt.Errorf("failed to check existing program: %w", err)
+		}
+
+		if existingProgram != nil {
+			oldProgramURL := existingProgram.ProgramURL
+			if err := s.programRepo.RenameProgram(ctx, existingProgram, program.ProgramURL); err != nil {
+				return fmt.Errorf("failed to rename program: %w", err)
+			}
+			utils.Log(ctx).Infof("Program %s was renamed from %s to %s", program.Name, oldProgramURL, program.ProgramURL)
+		}
+	}
+
 	if existingProgram != nil {
 		// Update existing program
 		existingProgram.Name = program.Name
+		if program.PlatformID != "" {
+			existingProgram.PlatformID = program.PlatformID
+		}
 		existingProgram.ProgramURL = program.ProgramURL
 		existingProgram.IsActive = program.IsActive
+		if program.Visibility != "" {
+			existingProgram.Visibility = program.Visibility
+		}
 		existingProgram.LastUpdated = program.LastUpdated
 
 		if err := s.programRepo.UpdateProgram(ctx, existingProgram); err != nil {
 			return fmt.Errorf("failed to update program: %w", err)
 		}
 
-		logrus.Infof("Updated existing program: %s", program.Name)
+		utils.Log(ctx).Infof("Updated existing program: %s", program.Name)
 
-		// Check if there are new primary assets before running discovery
-		hasNewAssets, err := s.hasNewPrimaryAssets(ctx, existingProgram, platform)
-		if err != nil {
-			logrus.Warnf("Failed to check for new primary assets for program %s: %v", program.Name, err)
-			// Continue with discovery as fallback
-		} else if !hasNewAssets {
-			logrus.Infof("No new primary assets found for program %s, skipping asset discovery", program.Name)
-			return nil
+		// Check if there are new primary assets before running discovery; a
+		// program that timed out is continued regardless
+		if s.programContinuation(ctx, existingProgram) != nil {
+			utils.Log(ctx).Infof("Program %s has domains left from a timed-out attempt, continuing asset discovery", program.Name)
+		} else if s.programScanOverdue(ctx, existingProgram) {
+			utils.Log(ctx).Infof("Program %s was not scanned within %v, rediscovering its assets", program.Name, s.config.SLO.ProgramScanWithin)
+		} else {
+			hasNewAssets, err := s.hasNewPrimaryAssets(ctx, existingProgram, platform)
+			if err != nil {
+				utils.Log(ctx).Warnf("Failed to check for new primary assets for program %s: %v", program.Name, err)
+				// Continue with discovery as fallback
+			} else if !hasNewAssets {
+				utils.Log(ctx).Infof("No new primary assets found for program %s, skipping asset discovery", program.Name)
+				return nil
+			}
 		}
 
 		// Refresh assets for existing programs only if new primary assets are found
@@ -283,7 +727,9 @@ func (s *MonitorService) processProgram(ctx context.Context, platform platforms.
 		return fmt.Errorf("failed to create program: %w", err)
 	}
 
-	logrus.Infof("Created new program: %s", program.Name)
+	utils.Log(ctx).Infof("Created new program: %s", program.Name)
+	s.metrics.RecordProgramDiscovered(platform.GetName())
+	s.events.Emit(ctx, events.TypeProgramCreated, dbProgram.ProgramURL, events.NewProgramData(dbProgram))
 
 	// Get program scope and discover assets
 	if err := s.discoverProgramAssets(ctx, dbProgram, platform); err != nil {
@@ -298,7 +744,7 @@ func (s *MonitorService) hasNewPrimaryAssets(ctx context.Context, program *datab
 	// Add panic recovery
 	defer func() {
 		if r := recover(); r != nil {
-			logrus.Errorf("hasNewPrimaryAssets panicked for program %s: %v", program.Name, r)
+			utils.Log(ctx).Errorf("hasNewPrimaryAssets panicked for program %s: %v", program.Name, r)
 		}
 	}()
 
@@ -332,165 +778,290 @@ func (s *MonitorService) hasNewPrimaryAssets(ctx context.Context, program *datab
 
 // discoverProgramAssets discovers assets for a program
 func (s *MonitorService) discoverProgramAssets(ctx context.Context, program *database.Program, platform platforms.Platform) error {
-	logrus.Infof("Discovering assets for program: %s", program.Name)
+	utils.Log(ctx).Infof("Discovering assets for program: %s", program.Name)
 
 	// Create scan record
 	scan := &database.Scan{
 		ProgramID:   program.ID,
 		Status:      "running",
 		AssetsFound: 0,
+		ScheduledAt: scheduledRun(ctx),
 	}
 
 	if err := s.scanRepo.CreateScan(ctx, scan); err != nil {
 		return fmt.Errorf("failed to create scan record: %w", err)
 	}
+	ctx = utils.WithLogFields(ctx, logrus.Fields{
+		"platform":   program.Platform,
+		"program":    program.ProgramURL,
+		"program_id": program.ID,
+		"scan_id":    scan.ID,
+	})
+
+	// Let the scan be cancelled by ID from the CLI or API
+	ctx, stopWatching := s.watchScanCancel(ctx, scan.ID)
+	defer stopWatching()
+
+	// Probe the program's assets with its auth profile, if it has one
+	ctx = s.probeAuthContext(ctx, program.ID)
 
 	defer func() {
-		// Update scan status
-		scan.Status = "completed"
-		scan.CompletedAt = &time.Time{}
-		if err := s.scanRepo.UpdateScan(ctx, scan); err != nil {
-			logrus.Errorf("Failed to update scan status: %v", err)
+		// Update scan status; the scan context may already be cancelled
+		if errors.Is(context.Cause(ctx), ErrScanCancelled) {
+			scan.Status = "cancelled"
+			scan.Error = "cancelled by request"
+			utils.Log(ctx).Infof("Scan %s for program %s was cancelled", scan.ID, program.Name)
+		} else if reason := abortReason(ctx); reason != "" {
+			scan.Status = "aborted"
+			scan.Error = reason
+			utils.Log(ctx).Infof("Scan %s for program %s was aborted: %s", scan.ID, program.Name, reason)
+		} else if scan.Status == "running" {
+			scan.Status = "completed"
 		}
+		completedAt := time.Now()
+		scan.CompletedAt = &completedAt
+		if err := s.scanRepo.UpdateScan(context.WithoutCancel(ctx), scan); err != nil {
+			utils.Log(ctx).Errorf("Failed to update scan status: %v", err)
+		}
+		s.recordScanMetrics(context.WithoutCancel(ctx), platform.GetName(), scan)
 	}()
 
 	// Add panic recovery
 	defer func() {
 		if r := recover(); r != nil {
-			logrus.Errorf("Program %s asset discovery panicked: %v", program.Name, r)
+			utils.Log(ctx).Errorf("Program %s asset discovery panicked: %v", program.Name, r)
 			scan.Status = "failed"
 			scan.Error = fmt.Sprintf("Panic: %v", r)
 			// Try to update scan status even if we panicked
-			if err := s.scanRepo.UpdateScan(ctx, scan); err != nil {
-				logrus.Errorf("Failed to update scan status after panic: %v", err)
+			if err := s.scanRepo.UpdateScan(context.WithoutCancel(ctx), scan); err != nil {
+				utils.Log(ctx).Errorf("Failed to update scan status after panic: %v", err)
 			}
 		}
 	}()
 
-	// Get program scope from platform with timeout protection
-	scopeAssets, err := platform.GetProgramScope(ctx, program.ProgramURL)
-	if err != nil {
-		scan.Status = "failed"
-		scan.Error = err.Error()
-		logrus.Errorf("Failed to get program scope for %s: %v", program.Name, err)
-		return fmt.Errorf("failed to get program scope: %w", err)
+	// Pick up where a previous attempt that ran out of time stopped
+	continuation := s.programContinuation(ctx, program)
+	progress := newDiscoveryProgress()
+
+	// Remember what the previous scan saw, so this scan's changes can be
+	// recorded once it completes. A continuation only covers part of the
+	// program, so it is not compared.
+	var baseline *assetBaseline
+	if continuation == nil {
+		baseline = s.loadAssetBaseline(ctx, program, scan)
 	}
 
-	logrus.Infof("Found %d scope assets for program %s", len(scopeAssets), program.Name)
+	// The program's ChaosDB dataset does not depend on its scope, so it is
+	// downloaded while the scope is fetched
+	chaosDataset := s.prefetchChaosDataset(ctx, program.ProgramURL)
+
+	// Stream the program scope from the platform and save its primary assets
+	// a chunk at a time, so programs with thousands of scope entries keep
+	// memory flat and a failure part way through keeps the chunks saved so far
+	collector := newScopeCollector(program, platform.GetName(), scan.ID)
+	var saveErr error
+	err := platforms.StreamScope(ctx, platform, program.ProgramURL, s.config.Discovery.ScopeChunkSize, func(chunk []*platforms.ScopeAsset) error {
+		if collector.total == 0 {
+			// Log the first few scope assets for debugging
+			utils.Log(ctx).Debugf("Sample scope assets for program %s: %v", program.Name, chunk[:min(3, len(chunk))])
+		}
 
-	// Log the first few scope assets for debugging
-	if len(scopeAssets) > 0 {
-		sampleSize := 3
-		if len(scopeAssets) < sampleSize {
-			sampleSize = len(scopeAssets)
+		primary := collector.add(chunk)
+		collector.addDomains(s.extractUniqueDomains(chunk))
+		if len(primary) == 0 {
+			return nil
+		}
+		if err := s.createAssets(ctx, primary); err != nil {
+			saveErr = err
+			return err
 		}
-		logrus.Debugf("Sample scope assets for program %s: %v", program.Name, scopeAssets[:sampleSize])
+		utils.Log(ctx).Debugf("Saved %d primary assets for program %s (%d scope assets so far)", len(primary), program.Name, collector.total)
+		return nil
+	})
+	if saveErr != nil {
+		scan.Status = "failed"
+		scan.Error = saveErr.Error()
+		return fmt.Errorf("failed to save primary assets: %w", saveErr)
+	}
+	if _, ok := utils.AsMaintenanceError(err); ok {
+		// Not a failure of the program; it is retried once the platform is back
+		scan.Status = "deferred"
+		scan.Error = err.Error()
+		return fmt.Errorf("failed to get program scope: %w", err)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		// The chunks saved so far are kept and the whole program is retried
+		scan.Status = "timed_out"
+		scan.Error = fmt.Sprintf("timed out during %s: %v", database.StageScope, err)
+		utils.Log(ctx).Warnf("Program %s timed out fetching its scope after %d scope assets: %v", program.Name, collector.total, err)
+		return fmt.Errorf("%w: %s", ErrProgramTimedOut, scan.Error)
+	}
+	if err != nil {
+		scan.Status = "failed"
+		scan.Error = err.Error()
+		utils.Log(ctx).Errorf("Failed to get program scope for %s after %d scope assets: %v", program.Name, collector.total, err)
+		return fmt.Errorf("failed to get program scope: %w", err)
 	}
 
-	// Separate in-scope and out-of-scope assets
-	var inScopeAssets []*platforms.ScopeAsset
-	var outOfScopeAssets []*platforms.ScopeAsset
-	var primaryAssets []*database.Asset
+	utils.Log(ctx).Infof("Found %d scope assets for program %s", collector.total, program.Name)
 
-	for _, scopeAsset := range scopeAssets {
-		if scopeAsset.EligibleForSubmission {
-			inScopeAssets = append(inScopeAssets, scopeAsset)
-			// Only save domain and wildcard type assets as primary assets
-			if scopeAsset.Type == "url" || scopeAsset.Type == "wildcard" {
-				dbAsset := scopeAsset.ConvertToDatabaseAsset(program.ID.String(), program.ProgramURL)
-				dbAsset.Source = "primary" // Mark as primary asset
-				primaryAssets = append(primaryAssets, dbAsset)
-			} else {
-				logrus.Debugf("Skipping non-domain asset type '%s' for program %s: %s", scopeAsset.Type, program.Name, scopeAsset.URL)
-			}
-		} else {
-			// Only include URL and wildcard type assets for out-of-scope filtering
-			if scopeAsset.Type == "url" || scopeAsset.Type == "wildcard" {
-				outOfScopeAssets = append(outOfScopeAssets, scopeAsset)
-			}
-		}
-	}
+	// Keep the scope's history and announce targets the program added or
+	// removed; hostnames found outside a scan are checked against its
+	// out-of-scope entries
+	s.recordScopeSnapshot(ctx, program, scan.ID, collector)
 
-	// Save primary assets to database
+	primaryAssets := collector.primary
 	if len(primaryAssets) > 0 {
-		if err := s.assetRepo.CreateAssets(ctx, primaryAssets); err != nil {
-			scan.Status = "failed"
-			scan.Error = err.Error()
-			return fmt.Errorf("failed to save primary assets: %w", err)
-		}
-		logrus.Infof("Saved %d primary assets for program %s (filtered from %d total scope assets)", len(primaryAssets), program.Name, len(scopeAssets))
+		utils.Log(ctx).Infof("Saved %d primary assets for program %s (filtered from %d total scope assets)", len(primaryAssets), program.Name, collector.total)
 	} else {
-		logrus.Infof("No primary assets to save for program %s (filtered from %d total scope assets)", program.Name, len(scopeAssets))
+		utils.Log(ctx).Infof("No primary assets to save for program %s (filtered from %d total scope assets)", program.Name, collector.total)
 	}
 
-	logrus.Infof("Found %d in-scope assets and %d out-of-scope assets for program %s", len(inScopeAssets), len(outOfScopeAssets), program.Name)
+	inScopeAssets, outOfScopeAssets := collector.inScopeDomains, collector.outOfScope
+	utils.Log(ctx).Infof("Found %d in-scope assets and %d out-of-scope assets for program %s", collector.inScope, len(outOfScopeAssets), program.Name)
+
+	// Quarantine assets whose scope root the program removed; assets in an
+	// in-scope range stay in scope
+	s.quarantineOutOfScopeAssets(ctx, program, slices.Concat(inScopeAssets, collector.inScopeNetworks), outOfScopeAssets)
 
-	// Extract unique domains for ChaosDB discovery
-	domains := s.extractUniqueDomains(scopeAssets)
-	logrus.Infof("Extracted %d unique domains for ChaosDB discovery: %v", len(domains), domains)
+	// Unique domains for subdomain discovery, collected while the scope streamed
+	domains := collector.domains
+	utils.Log(ctx).Infof("Extracted %d unique domains for subdomain discovery: %v", len(domains), domains)
+
+	// Flag apex domains that were registered recently
+	s.enrichDomainRegistrations(ctx, program, domains, primaryAssets)
+
+	// Skip the domains a timed-out attempt already finished
+	if continuation != nil {
+		domains = resumeDomains(domains, continuation)
+		utils.Log(ctx).Infof("Continuing program %s from its %s of %s: %d domains left", program.Name, continuation.Stage, continuation.Domain, len(domains))
+	}
+	progress.setDomains(domains)
 
-	// Discover additional subdomains using ChaosDB (secondary assets)
+	// Discover additional subdomains using the discovery sources (secondary assets)
+	var discoveryErr error
 	if len(domains) > 0 {
-		secondaryAssets, err := s.discoverWithChaosDB(ctx, program.ID, program.ProgramURL, domains, outOfScopeAssets)
-		if err != nil {
-			logrus.Warnf("ChaosDB discovery failed for program %s: %v", program.Name, err)
-			// Continue processing even if ChaosDB fails
+		var secondaryAssets []*database.Asset
+		secondaryAssets, discoveryErr = s.discoverSubdomains(ctx, scan.ID, program.ID, program.ProgramURL, domains, outOfScopeAssets, chaosDataset(), progress)
+		if discoveryErr != nil {
+			utils.Log(ctx).Warnf("Subdomain discovery failed for program %s: %v", program.Name, discoveryErr)
+			// Continue processing even if discovery fails
 		} else {
-			logrus.Infof("ChaosDB discovered %d secondary assets for program %s", len(secondaryAssets), program.Name)
+			utils.Log(ctx).Infof("Discovered %d secondary assets for program %s", len(secondaryAssets), program.Name)
 		}
 	}
 
+	// Keep the remaining domains when the program ran out of time
+	timeoutErr := s.finishProgress(ctx, program, scan, progress, discoveryErr, continuation != nil)
+
+	// Resolve the program's hostnames, so their IPs are known to the network
+	// lookups and those that stopped resolving are flagged
+	s.resolveAssets(ctx, program)
+
+	// Record where the program's assets are hosted
+	s.enrichAssetNetworks(ctx, program)
+
+	// Score the program's assets on what this scan found
+	s.scoreProgramAssets(ctx, program)
+
 	// Update scan with final count
 	assetCount, err := s.assetRepo.GetAssetCountByProgramID(ctx, program.ID)
 	if err != nil {
-		logrus.Warnf("Failed to get asset count for program %s: %v", program.Name, err)
+		utils.Log(ctx).Warnf("Failed to get asset count for program %s: %v", program.Name, err)
 	} else {
 		scan.AssetsFound = assetCount
 	}
 
-	return nil
+	// Compare the assets this scan confirmed with the previous scan
+	if ctx.Err() == nil {
+		seenCount, err := s.assetRepo.GetAssetCountSeenSince(ctx, program.ID, scan.StartedAt)
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to get seen asset count for program %s: %v", program.Name, err)
+		} else {
+			scan.AssetsSeen = seenCount
+			s.checkAssetQuota(ctx, program, scan)
+		}
+
+		s.emitDiscoveredAssets(ctx, program, scan)
+
+		// Assets a failed discovery did not confirm would show up as removed
+		if baseline != nil && timeoutErr == nil && discoveryErr == nil {
+			s.recordAssetChanges(ctx, program, scan, baseline)
+		}
+	}
+
+	return timeoutErr
+}
+
+// discoverySources returns the configured subdomain sources, ChaosDB first
+func (s *MonitorService) discoverySources() []discovery.Source {
+	var sources []discovery.Source
+	if s.chaosDBClient != nil {
+		sources = append(sources, s.chaosDBClient)
+	}
+	return append(sources, s.extraSources...)
 }
 
-// discoverWithChaosDB discovers additional subdomains using ChaosDB and filters them with HTTPX probe
-func (s *MonitorService) discoverWithChaosDB(ctx context.Context, programID uuid.UUID, programURL string, domains []string, outOfScopeAssets []*platforms.ScopeAsset) ([]*database.Asset, error) {
+// discoverSubdomains discovers additional subdomains using the discovery
+// sources and filters them with HTTPX probe
+func (s *MonitorService) discoverSubdomains(ctx context.Context, scanID uuid.UUID, programID uuid.UUID, programURL string, domains []string, outOfScopeAssets []*platforms.ScopeAsset, dataset map[string][]string, progress *discoveryProgress) ([]*database.Asset, error) {
 	// Add panic recovery
 	defer func() {
 		if r := recover(); r != nil {
-			logrus.Errorf("discoverWithChaosDB panicked: %v", r)
+			utils.Log(ctx).Errorf("discoverSubdomains panicked: %v", r)
 		}
 	}()
 
-	if s.chaosDBClient == nil {
-		logrus.Warn("ChaosDB client not configured, skipping discovery")
+	if len(s.discoverySources()) == 0 {
+		utils.Log(ctx).Warn("No discovery sources configured, skipping discovery")
 		return nil, nil
 	}
 
-	// Create a timeout context for ChaosDB discovery and HTTPX probing
+	// Create a timeout context for subdomain discovery and HTTPX probing
 	// This prevents the discovery process from hanging indefinitely
 	discoveryTimeout := s.config.Discovery.Timeouts.ChaosDiscovery
 	discoveryCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
 	defer cancel()
 
-	logrus.Infof("Starting ChaosDB discovery for %d domains: %v", len(domains), domains)
+	utils.Log(ctx).Infof("Starting subdomain discovery for %d domains: %v", len(domains), domains)
+
+	var assets []*database.Asset
+	var err error
+	if depth := s.config.Discovery.PipelineDepth; depth > 0 && len(domains) > 1 {
+		// Overlap ChaosDB queries with probing when a pipeline depth is configured
+		assets, err = s.processDomainsPipelined(discoveryCtx, scanID, programID, programURL, domains, outOfScopeAssets, dataset, depth, progress)
+	} else {
+		// Process domains sequentially to respect ChaosDB rate limits
+		assets, err = s.processDomainsSequentially(discoveryCtx, scanID, programID, programURL, domains, outOfScopeAssets, dataset, progress)
+	}
 
-	// Process domains sequentially to respect ChaosDB rate limits
-	return s.processDomainsSequentially(discoveryCtx, programID, programURL, domains, outOfScopeAssets)
+	if err == nil && discoveryCtx.Err() != nil {
+		err = fmt.Errorf("discovery stopped before all domains were processed: %w", discoveryCtx.Err())
+	}
+	return assets, err
 }
 
 // processDomainsSequentially processes domains one by one to respect rate limits
-func (s *MonitorService) processDomainsSequentially(ctx context.Context, programID uuid.UUID, programURL string, domains []string, outOfScopeAssets []*platforms.ScopeAsset) ([]*database.Asset, error) {
+func (s *MonitorService) processDomainsSequentially(ctx context.Context, scanID uuid.UUID, programID uuid.UUID, programURL string, domains []string, outOfScopeAssets []*platforms.ScopeAsset, dataset map[string][]string, progress *discoveryProgress) ([]*database.Asset, error) {
 	var allAssets []*database.Asset
 	totalSubdomains := 0
 	successfulDomains := 0
 	errorCount := 0
 
 	for i, domain := range domains {
-		logrus.Infof("Processing domain %d/%d: %s", i+1, len(domains), domain)
+		if ctx.Err() != nil {
+			break
+		}
+
+		utils.Log(ctx).Infof("Processing domain %d/%d: %s", i+1, len(domains), domain)
 
 		// Process single domain with HTTPX probe
-		domainAssets, err := s.processSingleDomain(ctx, programID, programURL, domain, i+1, len(domains), outOfScopeAssets)
+		domainAssets, err := s.processSingleDomain(ctx, scanID, programID, programURL, domain, i+1, len(domains), outOfScopeAssets, dataset, progress)
+		if ctx.Err() == nil {
+			progress.complete(domain)
+		}
 		if err != nil {
-			logrus.Warnf("Failed to process domain %s: %v", domain, err)
+			utils.Log(ctx).Warnf("Failed to process domain %s: %v", domain, err)
 			errorCount++
 			continue
 		}
@@ -505,53 +1076,180 @@ func (s *MonitorService) processDomainsSequentially(ctx context.Context, program
 		}
 	}
 
-	logrus.Infof("ChaosDB discovery completed: %d domains, %d total subdomains, %d successful domains, %d errors",
+	utils.Log(ctx).Infof("Subdomain discovery completed: %d domains, %d total subdomains, %d successful domains, %d errors",
 		len(domains), totalSubdomains, successfulDomains, errorCount)
 
 	return allAssets, nil
 }
 
-// processSingleDomain processes a single domain using ChaosDB and HTTPX probe
-func (s *MonitorService) processSingleDomain(ctx context.Context, programID uuid.UUID, programURL string, domain string, domainIndex int, totalDomains int, outOfScopeAssets []*platforms.ScopeAsset) ([]*database.Asset, error) {
+// processDomainsPipelined discovers domains one by one, as the sequential
+// path does to respect ChaosDB rate limits, while earlier domains are probed.
+// At most depth discovered domains wait to be probed, so a slow probe holds
+// back discovery instead of piling up subdomains in memory.
+func (s *MonitorService) processDomainsPipelined(ctx context.Context, scanID uuid.UUID, programID uuid.UUID, programURL string, domains []string, outOfScopeAssets []*platforms.ScopeAsset, dataset map[string][]string, depth int, progress *discoveryProgress) ([]*database.Asset, error) {
+	queue := make(chan *discoveredDomain, depth)
+
+	go func() {
+		defer close(queue)
+		for i, domain := range domains {
+			if ctx.Err() != nil {
+				return
+			}
+
+			utils.Log(ctx).Infof("Discovering domain %d/%d: %s", i+1, len(domains), domain)
+			progress.enter(domain, database.StageDiscovery)
+			discovered := s.discoverDomain(ctx, domain, i+1, len(domains), dataset)
+			if discovered == nil {
+				if ctx.Err() == nil {
+					progress.complete(domain)
+				}
+				continue
+			}
+
+			select {
+			case queue <- discovered:
+			case <-ctx.Done():
+				return
+			}
+
+			// Small delay between domains to respect rate limits
+			if i < len(domains)-1 {
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+	}()
+
+	var allAssets []*database.Asset
+	probedDomains := 0
+	errorCount := 0
+
+	for discovered := range queue {
+		utils.Log(ctx).Infof("Probing domain %s (%d domains waiting)", discovered.domain, len(queue))
+
+		progress.enter(discovered.domain, database.StageProbe)
+		domainAssets, err := s.probeDiscoveredDomain(ctx, scanID, programID, programURL, discovered, outOfScopeAssets)
+		s.recordDiscoveryOutcome(ctx, programID, discovered)
+		if ctx.Err() == nil {
+			progress.complete(discovered.domain)
+		}
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to process domain %s: %v", discovered.domain, err)
+			errorCount++
+			continue
+		}
+
+		allAssets = append(allAssets, domainAssets...)
+		probedDomains++
+	}
+
+	utils.Log(ctx).Infof("Subdomain discovery completed: %d domains, %d total subdomains, %d successful domains, %d errors",
+		len(domains), len(allAssets), probedDomains, errorCount)
+
+	return allAssets, nil
+}
+
+// processSingleDomain processes a single domain using the discovery sources and HTTPX probe
+func (s *MonitorService) processSingleDomain(ctx context.Context, scanID uuid.UUID, programID uuid.UUID, programURL string, domain string, domainIndex int, totalDomains int, outOfScopeAssets []*platforms.ScopeAsset, dataset map[string][]string, progress *discoveryProgress) ([]*database.Asset, error) {
+	progress.enter(domain, database.StageDiscovery)
+	discovered := s.discoverDomain(ctx, domain, domainIndex, totalDomains, dataset)
+	if discovered == nil {
+		return nil, nil
+	}
+	progress.enter(domain, database.StageProbe)
+	assets, err := s.probeDiscoveredDomain(ctx, scanID, programID, programURL, discovered, outOfScopeAssets)
+	s.recordDiscoveryOutcome(ctx, programID, discovered)
+	return assets, err
+}
+
+// discoveredDomain holds the subdomains found for one in-scope domain,
+// waiting to be probed
+type discoveredDomain struct {
+	domain     string
+	subdomains []string          // as returned by the discovery sources
+	clean      []string          // wildcards removed and invalid names dropped
+	sources    map[string]string // lowercase subdomain, as returned and cleaned, to the source that found it first
+
+	failedSources []string // discovery sources that returned an error
+	discoveryErr  error    // their errors
+	probeErr      error    // set when the probe failed as a whole
+}
+
+// sourceOf returns the discovery source that found a subdomain first. Names
+// no source returned, e.g. hosts the probe reported in another form, are
+// credited to the first source that found anything for the domain.
+func (d *discoveredDomain) sourceOf(subdomain string) string {
+	if source, ok := d.sources[strings.ToLower(strings.TrimSpace(subdomain))]; ok {
+		return source
+	}
+	return d.sources[""]
+}
+
+// discoverDomain collects a domain's subdomains from every discovery source,
+// using the program's dataset in place of ChaosDB queries when there is one.
+// It returns nil when no source is configured.
+func (s *MonitorService) discoverDomain(ctx context.Context, domain string, domainIndex int, totalDomains int, dataset map[string][]string) *discoveredDomain {
+	ctx = utils.WithLogFields(ctx, logrus.Fields{"domain": domain})
+
 	// Add panic recovery
 	defer func() {
 		if r := recover(); r != nil {
-			logrus.Errorf("processSingleDomain panicked for domain %s: %v", domain, r)
+			utils.Log(ctx).Errorf("discoverDomain panicked for domain %s: %v", domain, r)
 		}
 	}()
 
-	if s.chaosDBClient == nil {
-		logrus.Warnf("ChaosDB client not configured, skipping domain %s", domain)
-		return nil, nil
+	sources := s.discoverySources()
+	if len(sources) == 0 {
+		utils.Log(ctx).Warnf("No discovery sources configured, skipping domain %s", domain)
+		return nil
 	}
 
-	// Create a timeout context for ChaosDB discovery and HTTPX probing
-	// This prevents the discovery process from hanging indefinitely
 	discoveryTimeout := s.config.Discovery.Timeouts.ChaosDiscovery
 	domainCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
 	defer cancel()
 
-	logrus.Infof("Starting ChaosDB discovery for domain %d/%d: %s", domainIndex, totalDomains, domain)
+	utils.Log(ctx).Infof("Starting subdomain discovery for domain %d/%d: %s", domainIndex, totalDomains, domain)
 
-	// Use bulk discovery for efficiency
-	bulkResult, err := s.chaosDBClient.DiscoverDomainsBulk(domainCtx, []string{domain})
-	if err != nil {
-		logrus.Warnf("ChaosDB bulk discovery failed for domain %s: %v", domain, err)
-		return nil, nil // Return empty result instead of error to continue processing
+	// Collect all subdomains, remembering the source that found each first
+	var allSubdomains []string
+	subdomainSources := make(map[string]string)
+	collect := func(source string, subdomains []string) {
+		for _, subdomain := range subdomains {
+			key := strings.ToLower(strings.TrimSpace(subdomain))
+			for _, name := range []string{"", key, s.urlProcessor.ConvertWildcardToDomain(key)} {
+				if _, ok := subdomainSources[name]; !ok {
+					subdomainSources[name] = source
+				}
+			}
+		}
+		allSubdomains = append(allSubdomains, subdomains...)
 	}
 
-	// Collect all subdomains from ChaosDB results
-	var allSubdomains []string
-	for _, result := range bulkResult.Results {
-		if result.Error != "" {
-			logrus.Warnf("ChaosDB error for domain %s: %s", result.Domain, result.Error)
+	var failedSources []string
+	var discoveryErrs []error
+	datasetSubdomains, fromDataset := chaosdb.DatasetSubdomains(dataset, domain)
+	if fromDataset {
+		utils.Log(ctx).Infof("Using ChaosDB dataset for domain %s", domain)
+		collect(s.chaosDBClient.Name(), datasetSubdomains)
+	}
+
+	for _, source := range sources {
+		if fromDataset && source == discovery.Source(s.chaosDBClient) {
 			continue
 		}
 
-		allSubdomains = append(allSubdomains, result.Subdomains...)
+		subdomains, err := source.Subdomains(domainCtx, domain)
+		if err != nil {
+			// Keep the other sources' results instead of failing the domain
+			utils.Log(ctx).Warnf("%s discovery failed for domain %s: %v", source.Name(), domain, err)
+			failedSources = append(failedSources, source.Name())
+			discoveryErrs = append(discoveryErrs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+		utils.Log(ctx).Infof("%s discovered %d subdomains for domain %s", source.Name(), len(subdomains), domain)
+		collect(source.Name(), subdomains)
 	}
 
-	logrus.Infof("ChaosDB discovered %d total subdomains for domain %s", len(allSubdomains), domain)
+	utils.Log(ctx).Infof("Discovered %d total subdomains for domain %s", len(allSubdomains), domain)
 
 	// Filter out wildcard subdomains and validate domains before HTTPX probing
 	var cleanSubdomains []string
@@ -569,7 +1267,7 @@ func (s *MonitorService) processSingleDomain(ctx context.Context, programID uuid
 		}
 	}
 
-	logrus.Infof("Filtered %d wildcard subdomains, %d clean subdomains, %d invalid subdomains for domain %s",
+	utils.Log(ctx).Infof("Filtered %d wildcard subdomains, %d clean subdomains, %d invalid subdomains for domain %s",
 		len(allSubdomains)-len(cleanSubdomains)-len(invalidSubdomains), len(cleanSubdomains), len(invalidSubdomains), domain)
 
 	// Log some examples of invalid subdomains for debugging
@@ -578,15 +1276,51 @@ func (s *MonitorService) processSingleDomain(ctx context.Context, programID uuid
 		if len(examples) > 5 {
 			examples = examples[:5]
 		}
-		logrus.Debugf("Examples of invalid subdomains filtered out: %v", examples)
+		utils.Log(ctx).Debugf("Examples of invalid subdomains filtered out: %v", examples)
 	}
 
+	return &discoveredDomain{
+		domain:        domain,
+		subdomains:    allSubdomains,
+		clean:         cleanSubdomains,
+		sources:       subdomainSources,
+		failedSources: failedSources,
+		discoveryErr:  errors.Join(discoveryErrs...),
+	}
+}
+
+// probeDiscoveredDomain probes a domain's discovered subdomains with HTTPX,
+// filters them against the program's out-of-scope assets and saves the
+// remaining ones with their probe responses. Subdomains found outside a scan
+// are passed with uuid.Nil as the scan ID.
+func (s *MonitorService) probeDiscoveredDomain(ctx context.Context, scanID uuid.UUID, programID uuid.UUID, programURL string, discovered *discoveredDomain, outOfScopeAssets []*platforms.ScopeAsset) ([]*database.Asset, error) {
+	// Add panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			utils.Log(ctx).Errorf("probeDiscoveredDomain panicked for domain %s: %v", discovered.domain, r)
+		}
+	}()
+
+	domain := discovered.domain
+	ctx = utils.WithLogFields(ctx, logrus.Fields{"domain": domain})
+	allSubdomains := discovered.subdomains
+	cleanSubdomains := discovered.clean
+	outOfScope := scope.NewMatcher(outOfScopeAssets)
+
+	// Create a timeout context for HTTPX probing
+	// This prevents the discovery process from hanging indefinitely
+	discoveryTimeout := s.config.Discovery.Timeouts.ChaosDiscovery
+	domainCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
 	// Filter subdomains using HTTPX probe if enabled and capture detailed responses
 	var filteredSubdomains []string
 	var detailedResults []httpx.DetailedProbeResult
-	if s.httpxClient != nil && len(cleanSubdomains) > 0 {
-		logrus.Infof("Starting detailed HTTPX probe to filter %d subdomains for domain %s", len(cleanSubdomains), domain)
-		logrus.Debugf("HTTPX probe timeout set to %v", discoveryTimeout)
+	var probeErr error
+	var stream *assetStream
+	if s.prober != nil && len(cleanSubdomains) > 0 {
+		utils.Log(ctx).Infof("Starting detailed HTTPX probe to filter %d subdomains for domain %s", len(cleanSubdomains), domain)
+		utils.Log(ctx).Debugf("HTTPX probe timeout set to %v", discoveryTimeout)
 
 		// Start HTTPX probe with progress logging
 		probeStart := time.Now()
@@ -594,26 +1328,62 @@ func (s *MonitorService) processSingleDomain(ctx context.Context, programID uuid
 		// Use a separate context for HTTPX probe with its own timeout
 		httpxCtx, httpxCancel := context.WithTimeout(domainCtx, discoveryTimeout)
 
+		// Domains with more subdomains than a write batch are saved in batches
+		// as hosts answer
+		if batchSize := s.writeThrottle.BatchSize(); batchSize > 0 && len(cleanSubdomains) > batchSize {
+			stream = s.newAssetStream(ctx, batchSize, func(result httpx.DetailedProbeResult) *database.Asset {
+				subdomain := s.httpxClient.ExtractDomainFromURL(result.URL)
+				if subdomain == "" || outOfScope.Match(subdomain, probedAddresses(&result)...) {
+					return nil
+				}
+				asset := s.newDiscoveredAsset(ctx, scanID, programID, programURL, discovered, subdomain)
+				if asset != nil {
+					applyProbeResult(asset, &result, time.Now())
+				}
+				return asset
+			})
+			httpxCtx = httpx.WithResultHandler(httpxCtx, stream.handle)
+		}
+
 		// Log the timeout being used
-		logrus.Infof("HTTPX probe timeout set to %v for domain %s", discoveryTimeout, domain)
+		utils.Log(ctx).Infof("HTTPX probe timeout set to %v for domain %s", discoveryTimeout, domain)
 
-		detailedResults, err = s.httpxClient.ProbeDomainsWithDetails(httpxCtx, cleanSubdomains)
+		var err error
+		detailedResults, err = s.prober.ProbeDomainsWithDetails(httpx.WithMethod(httpxCtx, s.config.Discovery.HTTPX.ScanMethod), cleanSubdomains)
 		httpxCancel()
+		if stream != nil {
+			if streamed := stream.close(); len(streamed) > 0 {
+				utils.Log(ctx).Infof("Saved %d assets for domain %s while probing", len(streamed), domain)
+			}
+		}
 
 		probeDuration := time.Since(probeStart)
 
 		if err != nil {
-			logrus.Warnf("Detailed HTTPX probe failed after %v for domain %s, using all subdomains: %v", probeDuration, domain, err)
+			utils.Log(ctx).Warnf("Detailed HTTPX probe failed after %v for domain %s, using all subdomains: %v", probeDuration, domain, err)
 			filteredSubdomains = allSubdomains
+			probeErr = err
 		} else {
 			// Log detailed results analysis
-			logrus.Infof("HTTPX probe returned %d results for %d subdomains", len(detailedResults), len(cleanSubdomains))
+			utils.Log(ctx).Infof("HTTPX probe returned %d results for %d subdomains", len(detailedResults), len(cleanSubdomains))
+
+			// Hosts probed over both http and https become one asset
+			detailedResults = mergeSchemeVariants(detailedResults)
 
-			// Extract existing subdomains from detailed results
+			// Extract existing subdomains from detailed results. Hosts that exist
+			// but did not answer (timed out, refused, DNS only) are kept too, so
+			// their liveness state is recorded instead of them looking dead.
 			existingCount := 0
+			livenessCounts := make(map[string]int)
 			for _, result := range detailedResults {
+				livenessCounts[result.Liveness]++
 				if result.Exists {
 					existingCount++
+					if len(result.ReachableFrom) > 0 && len(result.ReachableFrom) <= len(s.config.Vantage.Workers) {
+						utils.Log(ctx).Debugf("%s is only reachable from %s", result.URL, strings.Join(result.ReachableFrom, ", "))
+					}
+				}
+				if result.HostExists() {
 					// Extract domain from URL
 					resultDomain := s.httpxClient.ExtractDomainFromURL(result.URL)
 					if resultDomain != "" {
@@ -622,73 +1392,77 @@ func (s *MonitorService) processSingleDomain(ctx context.Context, programID uuid
 				}
 			}
 
-			logrus.Infof("Detailed HTTPX probe completed in %v for domain %s: %d/%d subdomains exist (captured %d detailed responses, %d existing)",
+			utils.Log(ctx).Infof("Detailed HTTPX probe completed in %v for domain %s: %d/%d subdomains exist (captured %d detailed responses, %d existing)",
 				probeDuration, domain, len(filteredSubdomains), len(allSubdomains), len(detailedResults), existingCount)
+			utils.Log(ctx).Debugf("Liveness for domain %s: %v", domain, livenessCounts)
 
 			// Warn if we got significantly fewer results than expected
 			if len(detailedResults) < len(cleanSubdomains) {
 				missingCount := len(cleanSubdomains) - len(detailedResults)
-				logrus.Warnf("HTTPX probe incomplete for domain %s: %d/%d subdomains processed, %d missing",
+				utils.Log(ctx).Warnf("HTTPX probe incomplete for domain %s: %d/%d subdomains processed, %d missing",
 					domain, len(detailedResults), len(cleanSubdomains), missingCount)
 			}
 		}
 	} else {
-		logrus.Infof("HTTPX probe not configured or no subdomains to probe for domain %s, using all subdomains", domain)
+		utils.Log(ctx).Infof("HTTPX probe not configured or no subdomains to probe for domain %s, using all subdomains", domain)
 		filteredSubdomains = allSubdomains
 	}
+	discovered.probeErr = probeErr
+	s.recordCoverage(ctx, scanID, programID, discovered, detailedResults, probeErr)
 
-	// Filter out subdomains that match out-of-scope assets
-	if len(outOfScopeAssets) > 0 {
-		filteredSubdomains = s.filterOutOfScopeSubdomains(filteredSubdomains, outOfScopeAssets)
-		logrus.Infof("After out-of-scope filtering: %d subdomains remain for domain %s", len(filteredSubdomains), domain)
+	// Index probe results by host so per-family reachability can be recorded on assets
+	probedAt := time.Now()
+	resultsByHost := make(map[string]httpx.DetailedProbeResult, len(detailedResults))
+	for _, result := range detailedResults {
+		resultsByHost[database.AssetHostKey(result.URL)] = result
 	}
 
-	// Convert filtered subdomains to assets
-	var assets []*database.Asset
-	for _, subdomain := range filteredSubdomains {
-		// Skip empty subdomains
-		if strings.TrimSpace(subdomain) == "" {
-			continue
-		}
-
-		// Create full URL
-		url := fmt.Sprintf("https://%s", subdomain)
+	// Filter out subdomains that match out-of-scope assets, or resolved into
+	// an out-of-scope range
+	if !outOfScope.Empty() {
+		filteredSubdomains = filterOutOfScope(filteredSubdomains, outOfScope, func(subdomain string) []string {
+			if result, ok := resultsByHost[database.AssetHostKey(subdomain)]; ok {
+				return probedAddresses(&result)
+			}
+			return nil
+		})
+		utils.Log(ctx).Infof("After out-of-scope filtering: %d subdomains remain for domain %s", len(filteredSubdomains), domain)
+	}
 
-		// Extract domain and subdomain
-		extractedDomain, err := s.urlProcessor.ExtractDomain(url)
-		if err != nil {
-			logrus.Warnf("Failed to extract domain from %s: %v", url, err)
+	// Convert filtered subdomains to assets; assets saved while probing are
+	// not saved again
+	var assets, unsaved []*database.Asset
+	for _, subdomain := range filteredSubdomains {
+		asset := s.newDiscoveredAsset(ctx, scanID, programID, programURL, discovered, subdomain)
+		if asset == nil {
 			continue
 		}
 
-		subdomainName, err := s.urlProcessor.ExtractSubdomain(url)
-		if err != nil {
-			logrus.Warnf("Failed to extract subdomain from %s: %v", url, err)
+		if stream != nil {
+			if saved, ok := stream.savedAsset(asset.URL); ok {
+				assets = append(assets, saved)
+				continue
+			}
 		}
 
-		asset := &database.Asset{
-			ProgramID:  programID,
-			ProgramURL: programURL,
-			URL:        url,
-			Domain:     extractedDomain,
-			Subdomain:  subdomainName,
-			Status:     "active",
-			Source:     "secondary", // Mark as secondary asset from ChaosDB
+		if result, ok := resultsByHost[database.AssetHostKey(asset.URL)]; ok {
+			applyProbeResult(asset, &result, probedAt)
 		}
 
 		assets = append(assets, asset)
+		unsaved = append(unsaved, asset)
 	}
 
-	// Save filtered ChaosDB assets to database
-	if len(assets) > 0 {
-		if err := s.assetRepo.CreateAssets(ctx, assets); err != nil {
-			logrus.Warnf("Failed to save ChaosDB assets for domain %s: %v", domain, err)
+	// Save filtered discovered assets to database
+	if len(unsaved) > 0 {
+		if err := s.createAssets(ctx, unsaved); err != nil {
+			utils.Log(ctx).Warnf("Failed to save discovered assets for domain %s: %v", domain, err)
 			// Don't return error, just log warning to continue processing
 			// Skip saving detailed responses since assets weren't saved
 		} else {
 			// Save detailed HTTPX responses if we have them and assets were successfully saved
 			if len(detailedResults) > 0 {
-				s.saveDetailedResponses(ctx, assets, detailedResults)
+				s.saveDetailedResponses(ctx, unsaved, detailedResults)
 			}
 		}
 	}
@@ -696,6 +1470,59 @@ func (s *MonitorService) processSingleDomain(ctx context.Context, programID uuid
 	return assets, nil
 }
 
+// newDiscoveredAsset builds the unsaved asset of a subdomain discovered for a
+// domain, or returns nil when the subdomain has no domain
+func (s *MonitorService) newDiscoveredAsset(ctx context.Context, scanID uuid.UUID, programID uuid.UUID, programURL string, discovered *discoveredDomain, subdomain string) *database.Asset {
+	// Skip empty subdomains
+	if strings.TrimSpace(subdomain) == "" {
+		return nil
+	}
+
+	// Create full URL
+	url := fmt.Sprintf("https://%s", subdomain)
+
+	// Extract domain and subdomain
+	extractedDomain, err := s.urlProcessor.ExtractDomain(url)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to extract domain from %s: %v", url, err)
+		return nil
+	}
+
+	subdomainName, err := s.urlProcessor.ExtractSubdomain(url)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to extract subdomain from %s: %v", url, err)
+	}
+
+	asset := &database.Asset{
+		ProgramID:   programID,
+		ProgramURL:  programURL,
+		URL:         url,
+		Domain:      extractedDomain,
+		Subdomain:   subdomainName,
+		Status:      "active",
+		Source:      "secondary", // Mark as secondary asset from a discovery source
+		FirstSource: discovered.sourceOf(subdomain),
+	}
+	if scanID != uuid.Nil {
+		asset.FirstScanID = &scanID
+	}
+
+	return asset
+}
+
+// maxProbeErrorLength caps the probe error stored on an asset; httpx error
+// chains can run to several kilobytes
+const maxProbeErrorLength = 500
+
+// truncateProbeError shortens a probe error for storage
+func truncateProbeError(probeErr string) string {
+	probeErr = strings.TrimSpace(probeErr)
+	if len(probeErr) <= maxProbeErrorLength {
+		return probeErr
+	}
+	return strings.ToValidUTF8(probeErr[:maxProbeErrorLength], "") + "..."
+}
+
 // extractUniqueDomains extracts unique domains from scope assets (only domain and wildcard types)
 func (s *MonitorService) extractUniqueDomains(scopeAssets []*platforms.ScopeAsset) []string {
 	// Add panic recovery
@@ -753,7 +1580,7 @@ func (s *MonitorService) markInactivePrograms(ctx context.Context, platformName
 	// Add panic recovery
 	defer func() {
 		if r := recover(); r != nil {
-			logrus.Errorf("markInactivePrograms panicked for platform %s: %v", platformName, r)
+			utils.Log(ctx).Errorf("markInactivePrograms panicked for platform %s: %v", platformName, r)
 		}
 	}()
 
@@ -773,10 +1600,10 @@ func (s *MonitorService) markInactivePrograms(ctx context.Context, platformName
 	for _, dbProgram := range dbPrograms {
 		if !currentProgramURLs[dbProgram.ProgramURL] {
 			if err := s.programRepo.MarkProgramInactive(ctx, dbProgram.ID); err != nil {
-				logrus.Errorf("Failed to mark program %s as inactive: %v", dbProgram.Name, err)
+				utils.Log(ctx).Errorf("Failed to mark program %s as inactive: %v", dbProgram.Name, err)
 				continue
 			}
-			logrus.Infof("Marked program %s as inactive", dbProgram.Name)
+			utils.Log(ctx).Infof("Marked program %s as inactive", dbProgram.Name)
 		}
 	}
 
@@ -797,19 +1624,123 @@ func (s *MonitorService) GetProgramStats(ctx context.Context) (*ProgramStats, er
 		return nil, fmt.Errorf("failed to get recent scans: %w", err)
 	}
 
+	// Get asset yield per discovery source
+	sourceYield, err := s.assetRepo.GetSourceYield(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source yield: %w", err)
+	}
+
+	// Get asset counts per liveness state
+	liveness, err := s.assetRepo.GetLivenessCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get liveness counts: %w", err)
+	}
+
+	// Get the most common probe errors of the last day
+	probeErrors, err := s.assetRepo.GetProbeErrorCounts(ctx, time.Now().Add(-24*time.Hour), 10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get probe error counts: %w", err)
+	}
+
+	// Get the programs with the most assets whose content changed since their latest scan
+	contentChanges, err := s.assetRepo.GetContentChangeCounts(ctx, 10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content change counts: %w", err)
+	}
+
+	// Get open TLS findings by check
+	tlsFindings, err := s.tlsFindingRepo.GetOpenTLSFindingCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TLS finding counts: %w", err)
+	}
+
+	// Get the country and provider breakdown of each program
+	geoCounts, err := s.ipNetworks.GetGeoDistribution(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get geo distribution: %w", err)
+	}
+
+	// Get recent platform maintenance windows
+	maintenance, err := s.maintenanceRepo.GetRecentMaintenance(ctx, 5)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent maintenance: %w", err)
+	}
+
+	// Get platform payload changes of the last week
+	schemaDrift, err := s.schemaDriftRepo.GetRecentSchemaDrift(ctx, time.Now().Add(-7*24*time.Hour), 10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema drift: %w", err)
+	}
+
+	// Get the breakdowns of each program over the stats window
+	windowStart := time.Now().Add(-statsWindow)
+	programSources, err := s.assetRepo.GetProgramSourceCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program source counts: %w", err)
+	}
+	scanOutcomes, err := s.scanRepo.GetProgramScanOutcomes(ctx, windowStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program scan outcomes: %w", err)
+	}
+	dailyAssets, err := s.assetRepo.GetProgramDailyAssets(ctx, windowStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program daily assets: %w", err)
+	}
+
+	// Check freshness against the SLOs, when any are configured
+	var freshness *FreshnessReport
+	if s.config.SLO.ProgramScanWithin > 0 || s.config.SLO.AssetProbeWithin > 0 {
+		freshness, err = s.GetFreshness(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get freshness: %w", err)
+		}
+	}
+
 	stats := &ProgramStats{
 		TotalPrograms:  len(programsWithCounts),
 		ActivePrograms: 0,
 		TotalAssets:    0,
 		RecentScans:    recentScans,
+		SourceYield:    sourceYield,
+		Liveness:       liveness,
+		ProbeErrors:    probeErrors,
+		ContentChanges: contentChanges,
+		TLSFindings:    tlsFindings,
+		Geo:            summarizeGeo(geoCounts),
+		Maintenance:    maintenance,
+		SchemaDrift:    schemaDrift,
+		Freshness:      freshness,
 	}
 
+	platforms := make(map[string]*PlatformCount)
 	for _, programWithCount := range programsWithCounts {
 		if programWithCount.Program.IsActive {
 			stats.ActivePrograms++
 		}
 		stats.TotalAssets += programWithCount.AssetCount
+
+		platform, ok := platforms[programWithCount.Program.Platform]
+		if !ok {
+			platform = &PlatformCount{Platform: programWithCount.Program.Platform, Sources: make(map[string]int)}
+			platforms[platform.Platform] = platform
+			stats.Platforms = append(stats.Platforms, platform)
+		}
+		platform.Programs++
+		platform.Assets += programWithCount.AssetCount
+
+		stats.Programs = append(stats.Programs, &ProgramBreakdown{
+			ID:         programWithCount.Program.ID,
+			Name:       programWithCount.Program.Name,
+			Platform:   programWithCount.Program.Platform,
+			ProgramURL: programWithCount.Program.ProgramURL,
+			Assets:     programWithCount.AssetCount,
+			Sources:    make(map[string]int),
+		})
 	}
+	sort.Slice(stats.Platforms, func(i, j int) bool {
+		return stats.Platforms[i].Platform < stats.Platforms[j].Platform
+	})
+	stats.addBreakdowns(programSources, scanOutcomes, dailyAssets)
 
 	return stats, nil
 }
@@ -826,7 +1757,7 @@ func (s *MonitorService) CheckDatabaseHealth(ctx context.Context) error {
 		return fmt.Errorf("database query test failed: %w", err)
 	}
 
-	logrus.Debug("Database health check passed")
+	utils.Log(ctx).Debug("Database health check passed")
 	return nil
 }
 
@@ -834,7 +1765,7 @@ func (s *MonitorService) CheckDatabaseHealth(ctx context.Context) error {
 func (s *MonitorService) CheckPlatformHealth(ctx context.Context) error {
 	platforms := s.platformFactory.GetAllPlatforms()
 	if len(platforms) == 0 {
-		logrus.Warn("No platforms configured with API keys, skipping platform health checks")
+		utils.Log(ctx).Warn("No platforms configured with API keys, skipping platform health checks")
 		return nil
 	}
 
@@ -843,7 +1774,7 @@ func (s *MonitorService) CheckPlatformHealth(ctx context.Context) error {
 		if err := platform.IsHealthy(ctx); err != nil {
 			return fmt.Errorf("platform %s health check failed: %w", platformName, err)
 		}
-		logrus.Debugf("Platform %s health check passed", platformName)
+		utils.Log(ctx).Debugf("Platform %s health check passed", platformName)
 	}
 
 	return nil
@@ -852,7 +1783,7 @@ func (s *MonitorService) CheckPlatformHealth(ctx context.Context) error {
 // CheckChaosDBHealth checks ChaosDB service health
 func (s *MonitorService) CheckChaosDBHealth(ctx context.Context) error {
 	if s.chaosDBClient == nil {
-		logrus.Warn("ChaosDB client not configured, skipping ChaosDB health check")
+		utils.Log(ctx).Warn("ChaosDB client not configured, skipping ChaosDB health check")
 		return nil
 	}
 
@@ -860,7 +1791,7 @@ func (s *MonitorService) CheckChaosDBHealth(ctx context.Context) error {
 		return fmt.Errorf("ChaosDB health check failed: %w", err)
 	}
 
-	logrus.Debug("ChaosDB health check passed")
+	utils.Log(ctx).Debug("ChaosDB health check passed")
 	return nil
 }
 
@@ -871,7 +1802,7 @@ func (s *MonitorService) CheckSystemHealth(ctx context.Context) error {
 	runtime.ReadMemStats(&m)
 
 	// Log memory usage for monitoring
-	logrus.Debugf("System memory - Alloc: %d MB, Sys: %d MB, NumGC: %d",
+	utils.Log(ctx).Debugf("System memory - Alloc: %d MB, Sys: %d MB, NumGC: %d",
 		m.Alloc/1024/1024, m.Sys/1024/1024, m.NumGC)
 
 	// Check if memory usage is reasonable (less than 1GB allocated)
@@ -885,7 +1816,7 @@ func (s *MonitorService) CheckSystemHealth(ctx context.Context) error {
 		return fmt.Errorf("high goroutine count: %d", numGoroutines)
 	}
 
-	logrus.Debugf("System health check passed - Goroutines: %d", numGoroutines)
+	utils.Log(ctx).Debugf("System health check passed - Goroutines: %d", numGoroutines)
 	return nil
 }
 
@@ -894,33 +1825,38 @@ func (s *MonitorService) saveDetailedResponses(ctx context.Context, assets []*da
 	// Add panic recovery
 	defer func() {
 		if r := recover(); r != nil {
-			logrus.Errorf("saveDetailedResponses panicked: %v", r)
+			utils.Log(ctx).Errorf("saveDetailedResponses panicked: %v", r)
 		}
 	}()
 
-	// Create a map of URL to Asset for quick lookup
-	urlToAsset := make(map[string]*database.Asset)
+	// Create a map of host to Asset for quick lookup; an http result belongs to
+	// the same asset as its https variant
+	hostToAsset := make(map[string]*database.Asset)
 	for _, asset := range assets {
-		urlToAsset[asset.URL] = asset
+		hostToAsset[database.AssetHostKey(asset.URL)] = asset
 	}
 
+	// New captures are compared with the previous ones to detect content changes
+	previousHashes := s.previousResponseHashes(ctx, assets)
+
 	// Save each detailed response
 	savedCount := 0
+	var searchDocs []*search.Document
 	for _, result := range detailedResults {
 		if !result.Exists {
 			continue // Skip non-existing domains
 		}
 
 		// Find the corresponding asset
-		asset, exists := urlToAsset[result.URL]
+		asset, exists := hostToAsset[database.AssetHostKey(result.URL)]
 		if !exists {
-			logrus.Debugf("No corresponding asset found for URL: %s", result.URL)
+			utils.Log(ctx).Debugf("No corresponding asset found for URL: %s", result.URL)
 			continue
 		}
 
 		// Skip if asset doesn't have a valid ID (wasn't saved to database)
 		if asset.ID == uuid.Nil {
-			logrus.Debugf("Asset for URL %s has no valid ID, skipping response save", result.URL)
+			utils.Log(ctx).Debugf("Asset for URL %s has no valid ID, skipping response save", result.URL)
 			continue
 		}
 
@@ -930,85 +1866,136 @@ func (s *MonitorService) saveDetailedResponses(ctx context.Context, assets []*da
 			if headersBytes, err := json.Marshal(result.Headers); err == nil {
 				headersJSON = string(headersBytes)
 			} else {
-				logrus.Warnf("Failed to marshal headers for %s: %v", result.URL, err)
+				utils.Log(ctx).Warnf("Failed to marshal headers for %s: %v", result.URL, err)
 				headersJSON = "{}"
 			}
 		} else {
 			headersJSON = "{}"
 		}
 
-		// Create AssetResponse record
+		// Create AssetResponse record; HEAD responses carry no body to fingerprint
 		assetResponse := &database.AssetResponse{
 			AssetID:      asset.ID,
+			Method:       result.Method,
 			StatusCode:   result.StatusCode,
 			Headers:      headersJSON,
 			Body:         result.Body,
 			ResponseTime: result.ResponseTime,
+
+			InitialStatusCode: result.InitialStatusCode,
+			RedirectHops:      result.RedirectHops,
+			FinalURL:          result.FinalURL,
+			RedirectStatus:    result.RedirectStatus,
+			MetaRefresh:       result.MetaRefresh,
 		}
+		if result.Method != httpx.MethodHEAD {
+			assetResponse.BodySimhash = bodySimhash(result.Body)
+		}
+		hashed := hashResponse(asset, assetResponse, &result)
 
-		// Save to database
-		if err := s.assetRepo.CreateAssetResponse(ctx, assetResponse); err != nil {
-			logrus.Warnf("Failed to save asset response for %s: %v", result.URL, err)
+		// Save to database, waiting for the write budget first
+		if err := s.writeThrottle.Wait(ctx, 1); err != nil {
+			utils.Log(ctx).Warnf("Stopped saving detailed responses: %v", err)
+			break
+		}
+		s.offloadBody(ctx, assetResponse)
+		err := s.assetRepo.CreateAssetResponse(ctx, assetResponse)
+		// Triage below reads the body from memory, not from the body store
+		assetResponse.Body = result.Body
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to save asset response for %s: %v", result.URL, err)
 		} else {
 			savedCount++
-			logrus.Debugf("Saved detailed response for %s (status: %d, body size: %d bytes)",
+			utils.Log(ctx).Debugf("Saved detailed response for %s (status: %d, body size: %d bytes)",
 				result.URL, result.StatusCode, len(result.Body))
+			s.recordTLSFindings(ctx, asset, &result)
+			if hashed {
+				s.recordContentChange(ctx, asset, previousHashes, assetResponse)
+			}
+			// Body-based triage needs a GET response; HEAD probes only refresh
+			// liveness, status and headers
+			if result.Method == httpx.MethodHEAD {
+				continue
+			}
+			s.saveAPISchema(ctx, asset, assetResponse, result.ContentType)
+			s.applyRules(ctx, asset, assetResponse, &result)
+			if s.searchIndexer != nil {
+				searchDocs = append(searchDocs, search.NewDocument(asset, assetResponse, &result, s.config.Search.BodyExcerptBytes))
+			}
 		}
 	}
 
-	logrus.Infof("Saved %d detailed HTTPX responses to database", savedCount)
+	s.mirrorResponses(ctx, searchDocs)
+
+	utils.Log(ctx).Infof("Saved %d detailed HTTPX responses to database", savedCount)
 }
 
-// filterOutOfScopeSubdomains filters out subdomains that match out-of-scope assets
+// filterOutOfScopeSubdomains filters out subdomains that match out-of-scope
+// assets. Only the hostnames are known, so IP ranges only exclude address
+// literals.
 func (s *MonitorService) filterOutOfScopeSubdomains(subdomains []string, outOfScopeAssets []*platforms.ScopeAsset) []string {
+	return filterOutOfScope(subdomains, scope.NewMatcher(outOfScopeAssets), nil)
+}
+
+// filterOutOfScope returns the subdomains out-of-scope entries do not cover.
+// addresses, when set, returns the addresses a subdomain resolved to, which
+// IP range entries are matched against.
+func filterOutOfScope(subdomains []string, outOfScope *scope.Matcher, addresses func(subdomain string) []string) []string {
 	var filteredSubdomains []string
 
 	for _, subdomain := range subdomains {
-		shouldExclude := false
-
-		// Create full URL for comparison
-		subdomainURL := fmt.Sprintf("https://%s", subdomain)
-
-		for _, outOfScopeAsset := range outOfScopeAssets {
-			if s.matchesOutOfScopeAsset(subdomainURL, outOfScopeAsset) {
-				logrus.Debugf("Excluding subdomain %s - matches out-of-scope asset: %s", subdomain, outOfScopeAsset.URL)
-				shouldExclude = true
-				break
-			}
+		var ips []string
+		if addresses != nil {
+			ips = addresses(subdomain)
 		}
 
-		if !shouldExclude {
-			filteredSubdomains = append(filteredSubdomains, subdomain)
+		if outOfScope.Match(subdomain, ips...) {
+			logrus.Debugf("Excluding subdomain %s - matches an out-of-scope asset", subdomain)
+			continue
 		}
+		filteredSubdomains = append(filteredSubdomains, subdomain)
 	}
 
 	return filteredSubdomains
 }
 
-// matchesOutOfScopeAsset checks if a subdomain URL matches an out-of-scope asset
-func (s *MonitorService) matchesOutOfScopeAsset(subdomainURL string, outOfScopeAsset *platforms.ScopeAsset) bool {
-	switch outOfScopeAsset.Type {
-	case "url":
-		// For URL assets, check if the subdomain URL exactly matches or is a subdomain of the out-of-scope URL
-		return s.urlProcessor.IsSubdomainOf(subdomainURL, outOfScopeAsset.URL)
-	case "wildcard":
-		// For wildcard assets, check if the subdomain matches the wildcard pattern
-		// Use OriginalPattern if available, otherwise fall back to URL
-		pattern := outOfScopeAsset.OriginalPattern
-		if pattern == "" {
-			pattern = outOfScopeAsset.URL
-		}
-		return s.urlProcessor.MatchesWildcard(subdomainURL, pattern)
-	default:
-		// For other types, don't filter
-		return false
+// probedAddresses returns the addresses a probe result's host resolved to
+func probedAddresses(result *httpx.DetailedProbeResult) []string {
+	var ips []string
+	for _, ip := range []string{result.IP, result.IPv4, result.IPv6} {
+		if ip != "" {
+			ips = append(ips, ip)
+		}
 	}
+	return ips
 }
 
 // ProgramStats represents program statistics
 type ProgramStats struct {
-	TotalPrograms  int              `json:"total_programs"`
-	ActivePrograms int              `json:"active_programs"`
-	TotalAssets    int              `json:"total_assets"`
-	RecentScans    []*database.Scan `json:"recent_scans"`
+	TotalPrograms  int                             `json:"total_programs"`
+	ActivePrograms int                             `json:"active_programs"`
+	TotalAssets    int                             `json:"total_assets"`
+	RecentScans    []*database.Scan                `json:"recent_scans"`
+	SourceYield    []*database.SourceYield         `json:"source_yield"`
+	Liveness       []*database.LivenessCount       `json:"liveness"`
+	ProbeErrors    []*database.ProbeErrorCount     `json:"probe_errors"`
+	ContentChanges []*database.ContentChangeCount  `json:"content_changes"` // assets whose content changed since their program's latest scan
+	TLSFindings    []*database.TLSFindingCount     `json:"tls_findings"`
+	Geo            []*GeoSummary                   `json:"geo"`
+	Maintenance    []*database.PlatformMaintenance `json:"maintenance"`
+	SchemaDrift    []*database.SchemaDrift         `json:"schema_drift"`
+	Platforms      []*PlatformCount                `json:"platforms"`
+	Programs       []*ProgramBreakdown             `json:"programs"`
+	Freshness      *FreshnessReport                `json:"freshness,omitempty"` // nil when no freshness SLO is configured
+}
+
+// PlatformCount is the number of active programs and their assets on a
+// platform, broken down like its programs
+type PlatformCount struct {
+	Platform  string         `json:"platform"`
+	Programs  int            `json:"programs"`
+	Assets    int            `json:"assets"`
+	Sources   map[string]int `json:"sources"`
+	Scans     ScanOutcomes   `json:"scans"`
+	NewAssets []*DailyAssets `json:"new_assets"`
 }
@@ -1,11 +1,23 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/discovery"
+	"github.com/monitor-agent/internal/discovery/chaosdb"
 	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/scope"
 	"github.com/monitor-agent/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMonitorService_ExtractUniqueDomains(t *testing.T) {
@@ -157,11 +169,7 @@ func TestMonitorService_filterOutOfScopeSubdomains(t *testing.T) {
 	assert.ElementsMatch(t, expected, filtered)
 }
 
-func TestMonitorService_matchesOutOfScopeAsset(t *testing.T) {
-	service := &MonitorService{
-		urlProcessor: utils.NewURLProcessor(),
-	}
-
+func TestOutOfScopeMatcher(t *testing.T) {
 	tests := []struct {
 		name            string
 		subdomainURL    string
@@ -238,7 +246,18 @@ func TestMonitorService_matchesOutOfScopeAsset(t *testing.T) {
 			expected: false,
 		},
 		{
-			name:         "CIDR asset - should not filter",
+			name:         "CIDR asset - address in range",
+			subdomainURL: "https://192.168.1.20",
+			outOfScopeAsset: &platforms.ScopeAsset{
+				URL:                   "192.168.1.0/24",
+				Domain:                "192.168.1.0/24",
+				Type:                  "cidr",
+				EligibleForSubmission: false,
+			},
+			expected: true,
+		},
+		{
+			name:         "CIDR asset - hostname without addresses",
 			subdomainURL: "https://example.com",
 			outOfScopeAsset: &platforms.ScopeAsset{
 				URL:                   "192.168.1.0/24",
@@ -252,12 +271,27 @@ func TestMonitorService_matchesOutOfScopeAsset(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := service.matchesOutOfScopeAsset(tt.subdomainURL, tt.outOfScopeAsset)
+			result := scope.NewMatcher([]*platforms.ScopeAsset{tt.outOfScopeAsset}).Match(tt.subdomainURL)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+func TestFilterOutOfScope_ResolvedAddresses(t *testing.T) {
+	outOfScope := scope.NewMatcher([]*platforms.ScopeAsset{
+		{URL: "192.0.2.0/24", Domain: "192.0.2.0/24", Type: "cidr"},
+	})
+	addresses := map[string][]string{
+		"vpn.example.com": {"192.0.2.15"},
+		"www.example.com": {"198.51.100.4"},
+	}
+
+	filtered := filterOutOfScope([]string{"vpn.example.com", "www.example.com", "new.example.com"}, outOfScope, func(subdomain string) []string {
+		return addresses[subdomain]
+	})
+	assert.Equal(t, []string{"www.example.com", "new.example.com"}, filtered)
+}
+
 func TestMonitorService_OutOfScopeAssetIsolation(t *testing.T) {
 	service := &MonitorService{
 		urlProcessor: utils.NewURLProcessor(),
@@ -320,3 +354,76 @@ func TestMonitorService_OutOfScopeAssetIsolation(t *testing.T) {
 	}
 	assert.ElementsMatch(t, expectedCombined, combinedFiltered, "Combined filtering should work correctly")
 }
+
+func TestMonitorService_RunProgramPanic(t *testing.T) {
+	// Without a program repository, processing the program panics
+	s := &MonitorService{config: &config.Config{}}
+	program := &platforms.Program{Name: "Acme", Platform: "hackerone", ProgramURL: "https://hackerone.com/acme"}
+
+	err := s.runProgram(context.Background(), nil, program, time.Minute)
+	require.Error(t, err, "a panic must fail the program so it is not checkpointed")
+	assert.Contains(t, err.Error(), "program Acme panicked")
+}
+
+func TestTruncateProbeError(t *testing.T) {
+	assert.Equal(t, "connection refused", truncateProbeError(" connection refused\n"))
+
+	long := strings.Repeat("x", maxProbeErrorLength+100)
+	truncated := truncateProbeError(long)
+	assert.Len(t, truncated, maxProbeErrorLength+3)
+	assert.True(t, strings.HasSuffix(truncated, "..."))
+}
+
+// staticSource is a discovery source answering with fixed subdomains
+type staticSource struct {
+	name       string
+	subdomains []string
+	err        error
+}
+
+func (s *staticSource) Name() string { return s.name }
+
+func (s *staticSource) Subdomains(ctx context.Context, domain string) ([]string, error) {
+	return s.subdomains, s.err
+}
+
+func TestMonitorService_DiscoverDomainMergesSources(t *testing.T) {
+	queries := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		_ = json.NewEncoder(w).Encode(chaosdb.ChaosDBResponse{Domain: "acme.com", Subdomains: []string{"www.acme.com", "*.cdn.acme.com"}, Count: 2})
+	}))
+	defer server.Close()
+
+	service := &MonitorService{
+		config:        &config.Config{Discovery: config.DiscoveryConfig{Timeouts: config.TimeoutConfig{ChaosDiscovery: time.Minute}}},
+		chaosDBClient: chaosdb.NewClient(&chaosdb.ClientConfig{APIKey: "key", RateLimit: 1000, Timeout: 5 * time.Second, BaseURL: server.URL}),
+		extraSources: []discovery.Source{
+			&staticSource{name: "crtsh", subdomains: []string{"WWW.acme.com", "api.acme.com", "cdn.acme.com"}},
+			&staticSource{name: "broken", err: errors.New("unavailable")},
+		},
+		urlProcessor: utils.NewURLProcessor(),
+	}
+
+	discovered := service.discoverDomain(context.Background(), "acme.com", 1, 1, nil)
+	require.NotNil(t, discovered)
+	assert.ElementsMatch(t, []string{"www.acme.com", "cdn.acme.com", "WWW.acme.com", "api.acme.com", "cdn.acme.com"}, discovered.clean)
+
+	// Each subdomain is credited to the first source that found it
+	assert.Equal(t, "chaosdb", discovered.sourceOf("www.acme.com"))
+	assert.Equal(t, "chaosdb", discovered.sourceOf("cdn.acme.com"))
+	assert.Equal(t, "crtsh", discovered.sourceOf("api.acme.com"))
+	assert.Equal(t, "chaosdb", discovered.sourceOf("unknown.acme.com"))
+
+	// A ChaosDB dataset stands in for ChaosDB queries, not for the other sources
+	dataset := map[string][]string{"acme.com": {"dataset.acme.com"}}
+	discovered = service.discoverDomain(context.Background(), "acme.com", 1, 1, dataset)
+	require.NotNil(t, discovered)
+	assert.Equal(t, "chaosdb", discovered.sourceOf("dataset.acme.com"))
+	assert.Equal(t, "crtsh", discovered.sourceOf("www.acme.com"))
+	assert.ElementsMatch(t, []string{"dataset.acme.com", "WWW.acme.com", "api.acme.com", "cdn.acme.com"}, discovered.clean)
+	assert.Equal(t, 1, queries)
+
+	// Without any source there is nothing to discover
+	assert.Nil(t, (&MonitorService{config: service.config}).discoverDomain(context.Background(), "acme.com", 1, 1, nil))
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/discovery/chaosdb"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingProber holds the probe of the first domain until ChaosDB has been
+// queried for the second one
+type blockingProber struct {
+	mu       sync.Mutex
+	probed   [][]string
+	release  <-chan struct{}
+	overlaps bool
+}
+
+func (p *blockingProber) ProbeDomainsWithDetails(ctx context.Context, domains []string) ([]httpx.DetailedProbeResult, error) {
+	p.mu.Lock()
+	first := len(p.probed) == 0
+	p.probed = append(p.probed, domains)
+	p.mu.Unlock()
+
+	if first {
+		select {
+		case <-p.release:
+			p.overlaps = true
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+		}
+	}
+	return nil, nil
+}
+
+func TestProcessDomainsPipelined(t *testing.T) {
+	secondQueried := make(chan struct{})
+	var once sync.Once
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		domain := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/subdomains")
+		if domain == "two.example.com" {
+			once.Do(func() { close(secondQueried) })
+		}
+		_ = json.NewEncoder(w).Encode(chaosdb.ChaosDBResponse{Domain: domain, Subdomains: []string{"www." + domain, "api." + domain}, Count: 2})
+	}))
+	defer server.Close()
+
+	prober := &blockingProber{release: secondQueried}
+	s := &MonitorService{
+		config: &config.Config{Discovery: config.DiscoveryConfig{
+			PipelineDepth: 1,
+			Timeouts:      config.TimeoutConfig{ChaosDiscovery: time.Minute},
+		}},
+		chaosDBClient: chaosdb.NewClient(&chaosdb.ClientConfig{APIKey: "key", RateLimit: 1000, Timeout: 5 * time.Second, BaseURL: server.URL}),
+		prober:        prober,
+		urlProcessor:  utils.NewURLProcessor(),
+	}
+
+	domains := []string{"one.example.com", "two.example.com", "three.example.com"}
+	_, err := s.processDomainsPipelined(context.Background(), uuid.New(), uuid.New(), "https://hackerone.com/acme", domains, nil, nil, 1, nil)
+	require.NoError(t, err)
+
+	assert.True(t, prober.overlaps, "the second domain should be queried while the first is probed")
+	require.Len(t, prober.probed, 3)
+	assert.ElementsMatch(t, []string{"www.one.example.com", "api.one.example.com"}, prober.probed[0])
+	assert.ElementsMatch(t, []string{"www.three.example.com", "api.three.example.com"}, prober.probed[2])
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPlatforms(t *testing.T) {
+	factory := platforms.NewPlatformFactory()
+	factory.RegisterPlatform("hackerone", &platforms.PlatformConfig{APIKey: "key", Username: "user", RateLimit: 60})
+	factory.RegisterPlatform("bugcrowd", &platforms.PlatformConfig{APIKey: "key", RateLimit: 60})
+	factory.RegisterPlatform("intigriti", &platforms.PlatformConfig{APIKey: "key", RateLimit: 55})
+	s := &MonitorService{platformFactory: factory}
+
+	all, err := s.selectPlatforms(nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	selected, err := s.selectPlatforms([]string{" BugCrowd", "hackerone", "bugcrowd", ""})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "bugcrowd", selected[0].GetName())
+	assert.Equal(t, "hackerone", selected[1].GetName())
+
+	_, err = s.selectPlatforms([]string{"yeswehack"})
+	assert.EqualError(t, err, `platform "yeswehack" is not configured (configured platforms: bugcrowd, hackerone, intigriti)`)
+}
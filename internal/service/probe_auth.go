@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/probeauth"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// newProbeAuthSealer creates the sealer probe auth profiles are opened with,
+// returning nil when no PROBE_AUTH_KEY is configured
+func newProbeAuthSealer(cfg *config.Config) *probeauth.Sealer {
+	if cfg.ProbeAuth.Key == "" {
+		return nil
+	}
+
+	key, err := probeauth.ParseKey(cfg.ProbeAuth.Key)
+	if err != nil {
+		logrus.Warnf("Invalid PROBE_AUTH_KEY, probing without auth profiles: %v", err)
+		return nil
+	}
+	sealer, err := probeauth.NewSealer(key)
+	if err != nil {
+		logrus.Warnf("Failed to create probe auth sealer, probing without auth profiles: %v", err)
+		return nil
+	}
+	return sealer
+}
+
+// probeAuthContext returns a context whose probes send the auth profile of a
+// program, or ctx itself when the program has none. A profile that cannot be
+// loaded is logged and the program is probed without it.
+func (s *MonitorService) probeAuthContext(ctx context.Context, programID uuid.UUID) context.Context {
+	if s.probeAuthRepo == nil || s.prober == nil {
+		return ctx
+	}
+
+	stored, err := s.probeAuthRepo.GetProbeAuthProfile(ctx, programID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to load probe auth profile of program %s, probing without it: %v", programID, err)
+		return ctx
+	}
+	if stored == nil {
+		return ctx
+	}
+	if s.probeAuthSealer == nil {
+		utils.Log(ctx).Warnf("Program %s has a probe auth profile but PROBE_AUTH_KEY is not set, probing without it", programID)
+		return ctx
+	}
+
+	profile, err := s.probeAuthSealer.Open(stored.SealedProfile)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to open probe auth profile of program %s, probing without it: %v", programID, err)
+		return ctx
+	}
+
+	utils.Log(ctx).Debugf("Probing program %s with its auth profile", programID)
+	return httpx.WithHeaders(ctx, profile.HeaderLines())
+}
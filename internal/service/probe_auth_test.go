@@ -0,0 +1,61 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/probeauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeAuthContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	sealer, err := probeauth.NewSealer(bytes.Repeat([]byte{1}, probeauth.KeySize))
+	require.NoError(t, err)
+	sealed, err := sealer.Seal(&probeauth.Profile{Headers: map[string]string{"X-Bug-Bounty": "researcher-42"}})
+	require.NoError(t, err)
+
+	s := &MonitorService{
+		probeAuthRepo:   database.NewProbeAuthRepository(sqlxDB),
+		probeAuthSealer: sealer,
+		prober:          &staticProber{},
+	}
+	columns := []string{"program_id", "sealed_profile", "created_at", "updated_at"}
+
+	withProfile, withoutProfile := uuid.New(), uuid.New()
+	mock.ExpectQuery("SELECT \\* FROM probe_auth_profiles").
+		WithArgs(withProfile).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(withProfile, sealed, time.Now(), time.Now()))
+	mock.ExpectQuery("SELECT \\* FROM probe_auth_profiles").
+		WithArgs(withoutProfile).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	ctx := s.probeAuthContext(context.Background(), withProfile)
+	assert.Equal(t, []string{"X-Bug-Bounty: researcher-42"}, httpx.HeadersFromContext(ctx))
+
+	ctx = s.probeAuthContext(context.Background(), withoutProfile)
+	assert.Nil(t, httpx.HeadersFromContext(ctx))
+
+	// Without the key the profile cannot be opened, so the program is probed
+	// without it
+	s.probeAuthSealer = nil
+	mock.ExpectQuery("SELECT \\* FROM probe_auth_profiles").
+		WithArgs(withProfile).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(withProfile, sealed, time.Now(), time.Now()))
+	ctx = s.probeAuthContext(context.Background(), withProfile)
+	assert.Nil(t, httpx.HeadersFromContext(ctx))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
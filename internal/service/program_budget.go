@@ -0,0 +1,35 @@
+package service
+
+import "context"
+
+// programBudget limits the programs processed at once across the platforms
+// of a scan, which are scanned concurrently. Each program holds one slot
+// while it runs; platform rate limits still apply through each platform's
+// client.
+type programBudget chan struct{}
+
+// newProgramBudget creates a budget of size programs, at least one
+func newProgramBudget(size int) programBudget {
+	if size < 1 {
+		size = 1
+	}
+	return make(programBudget, size)
+}
+
+// acquire waits for a free slot and reports false when ctx is done first
+func (b programBudget) acquire(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case b <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release frees a slot taken by acquire
+func (b programBudget) release() {
+	<-b
+}
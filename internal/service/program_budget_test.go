@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgramBudget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	budget := newProgramBudget(2)
+
+	assert.True(t, budget.acquire(ctx))
+	assert.True(t, budget.acquire(ctx))
+
+	// A full budget waits until a slot is released or the scan stops
+	cancel()
+	assert.False(t, budget.acquire(ctx))
+
+	budget.release()
+	assert.True(t, budget.acquire(context.Background()))
+}
+
+func TestNewProgramBudget_AtLeastOne(t *testing.T) {
+	assert.Equal(t, 1, cap(newProgramBudget(0)))
+	assert.Equal(t, 5, cap(newProgramBudget(5)))
+}
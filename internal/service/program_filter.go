@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// selectPrograms drops the programs of a platform that PROGRAM_INCLUDE and
+// PROGRAM_EXCLUDE leave out of scans
+func (s *MonitorService) selectPrograms(ctx context.Context, platformName string, programs []*platforms.Program) []*platforms.Program {
+	filter := s.config.Programs
+	if len(filter.Include) == 0 && len(filter.Exclude) == 0 {
+		return programs
+	}
+
+	selected := make([]*platforms.Program, 0, len(programs))
+	for _, program := range programs {
+		if filter.Selects(programNames(platformName, program)...) {
+			selected = append(selected, program)
+		}
+	}
+	if skipped := len(programs) - len(selected); skipped > 0 {
+		utils.Log(ctx).Infof("Skipping %d of %d programs on %s left out by the program include and exclude lists", skipped, len(programs), platformName)
+	}
+	return selected
+}
+
+// programNames returns the names program filter patterns match a program by:
+// its handle, platform/handle, name and program URL
+func programNames(platformName string, program *platforms.Program) []string {
+	handle := programHandle(program.ProgramURL)
+	return []string{handle, platformName + "/" + handle, program.Name, program.ProgramURL}
+}
+
+// programHandle returns the handle of a program, the last segment of its URL
+func programHandle(programURL string) string {
+	return path.Base(strings.TrimSuffix(programURL, "/"))
+}
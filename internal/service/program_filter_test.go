@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectPrograms(t *testing.T) {
+	programs := []*platforms.Program{
+		{Name: "Acme Corp", ProgramURL: "https://hackerone.com/acme"},
+		{Name: "Globex", ProgramURL: "https://hackerone.com/globex/"},
+		{Name: "Initech Huge", ProgramURL: "https://hackerone.com/initech"},
+		{Name: "Umbrella", ProgramURL: "https://hackerone.com/umbrella"},
+	}
+	names := func(programs []*platforms.Program) []string {
+		var names []string
+		for _, program := range programs {
+			names = append(names, program.Name)
+		}
+		return names
+	}
+
+	s := &MonitorService{config: &config.Config{}}
+	assert.Equal(t, programs, s.selectPrograms(context.Background(), "hackerone", programs))
+
+	s.config.Programs = config.ProgramFilterConfig{
+		Include: []string{"hackerone/acme", "GLOBEX", "*huge", "https://hackerone.com/umbrella"},
+		Exclude: []string{"initech"},
+	}
+	assert.Equal(t, []string{"Acme Corp", "Globex", "Umbrella"}, names(s.selectPrograms(context.Background(), "hackerone", programs)))
+
+	s.config.Programs = config.ProgramFilterConfig{Exclude: []string{"bugcrowd/*", "*e*"}}
+	assert.Empty(t, s.selectPrograms(context.Background(), "hackerone", programs))
+	s.config.Programs = config.ProgramFilterConfig{Exclude: []string{"bugcrowd/*"}}
+	assert.Len(t, s.selectPrograms(context.Background(), "hackerone", programs), 4)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// programTimeoutHistory is how many recent scans of a program its timeout is
+// extended from
+const programTimeoutHistory = 5
+
+// programTimeouts returns the processing timeout of each program of a
+// platform, keyed by program URL: PROGRAM_PROCESS_TIMEOUT extended for
+// programs whose recent scans ran long, or their PROGRAM_TIMEOUTS override
+func (s *MonitorService) programTimeouts(ctx context.Context, platformName string, programs []*platforms.Program) map[string]time.Duration {
+	timeouts := s.config.Discovery.Timeouts
+	var durations map[string]time.Duration
+	if timeouts.ProgramFactor > 0 && s.scanRepo != nil {
+		var err error
+		durations, err = s.scanRepo.GetLongestScanDurations(ctx, platformName, programTimeoutHistory)
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to get the scan durations of %s programs, using PROGRAM_PROCESS_TIMEOUT: %v", platformName, err)
+		}
+	}
+
+	programTimeouts := make(map[string]time.Duration, len(programs))
+	for _, program := range programs {
+		timeout := timeouts.ProgramTimeout(platformName, programHandle(program.ProgramURL), durations[program.ProgramURL])
+		if timeout != timeouts.ProgramProcess {
+			utils.Log(ctx).Debugf("Program %s gets a %v timeout", program.Name, timeout)
+		}
+		programTimeouts[program.ProgramURL] = timeout
+	}
+	return programTimeouts
+}
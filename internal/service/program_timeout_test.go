@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramTimeouts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	s := &MonitorService{
+		config: &config.Config{Discovery: config.DiscoveryConfig{Timeouts: config.TimeoutConfig{
+			ProgramProcess:   45 * time.Minute,
+			ProgramFactor:    1.5,
+			ProgramMax:       3 * time.Hour,
+			ProgramOverrides: map[string]time.Duration{"hackerone/initech": 4 * time.Hour},
+		}}},
+		scanRepo: database.NewScanRepository(sqlx.NewDb(db, "sqlmock")),
+	}
+	programs := []*platforms.Program{
+		{Name: "Acme", ProgramURL: "https://hackerone.com/acme"},
+		{Name: "Globex", ProgramURL: "https://hackerone.com/globex"},
+		{Name: "Initech", ProgramURL: "https://hackerone.com/initech"},
+		{Name: "Umbrella", ProgramURL: "https://hackerone.com/umbrella"},
+	}
+
+	mock.ExpectQuery("SELECT program_url, MAX\\(seconds\\)").WithArgs("hackerone", programTimeoutHistory).
+		WillReturnRows(sqlmock.NewRows([]string{"program_url", "seconds"}).
+			AddRow("https://hackerone.com/acme", 3600.0).
+			AddRow("https://hackerone.com/globex", 600.0).
+			AddRow("https://hackerone.com/initech", 36000.0))
+
+	assert.Equal(t, map[string]time.Duration{
+		"https://hackerone.com/acme":     90 * time.Minute,
+		"https://hackerone.com/globex":   45 * time.Minute,
+		"https://hackerone.com/initech":  4 * time.Hour,
+		"https://hackerone.com/umbrella": 45 * time.Minute,
+	}, s.programTimeouts(context.Background(), "hackerone", programs))
+
+	// Without the scan history every program keeps the configured timeout
+	mock.ExpectQuery("SELECT program_url, MAX\\(seconds\\)").WillReturnError(errors.New("connection reset"))
+	timeouts := s.programTimeouts(context.Background(), "hackerone", programs)
+	assert.Equal(t, 45*time.Minute, timeouts["https://hackerone.com/acme"])
+	assert.Equal(t, 4*time.Hour, timeouts["https://hackerone.com/initech"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
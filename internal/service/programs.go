@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// Outcomes of importing a program URL
+const (
+	ImportCreated  = "created"   // the program was found on its platform and created
+	ImportExists   = "exists"    // the program is already monitored
+	ImportRenamed  = "renamed"   // the URL is an old handle of a monitored program
+	ImportNotFound = "not_found" // the platform has no such program
+	ImportInvalid  = "invalid"   // the URL is not a program URL of a configured platform
+)
+
+// maxImportSuggestions is the number of similar handles suggested for a URL that was not found
+const maxImportSuggestions = 3
+
+// ProgramImport is the outcome of importing one program URL
+type ProgramImport struct {
+	Input       string
+	Status      string
+	Program     *database.Program // the created or existing program
+	Reason      string            // why the URL was invalid
+	Suggestions []string          // program URLs with a similar handle, when not found
+}
+
+// ImportPrograms adds programs by URL. Each URL is checked against its
+// platform's program list before anything is created, so a typo or an old
+// handle never creates a program that no scan would ever match. URLs of
+// programs that were renamed resolve to the program under its new handle, and
+// URLs that match nothing come with the closest handles on the platform.
+func (s *MonitorService) ImportPrograms(ctx context.Context, urls []string) ([]*ProgramImport, error) {
+	if err := s.checkWritable("import programs"); err != nil {
+		return nil, err
+	}
+
+	catalogs := make(map[string]map[string]*platforms.Program)
+	results := make([]*ProgramImport, 0, len(urls))
+
+	for _, raw := range urls {
+		result := &ProgramImport{Input: raw}
+		results = append(results, result)
+
+		platformName, handle, err := platforms.ParseProgramURL(raw)
+		if err != nil {
+			result.Status = ImportInvalid
+			result.Reason = err.Error()
+			continue
+		}
+		programURL := platforms.ProgramURL(platformName, handle)
+
+		// Old handles of renamed programs are only known locally
+		existing, err := s.programRepo.ResolveProgramByURL(ctx, programURL)
+		if err != nil {
+			return results, err
+		}
+		if existing != nil {
+			result.Program = existing
+			result.Status = ImportExists
+			if existing.ProgramURL != programURL {
+				result.Status = ImportRenamed
+			}
+			continue
+		}
+
+		catalog, ok := catalogs[platformName]
+		if !ok {
+			catalog, err = s.programCatalog(ctx, platformName)
+			if err != nil {
+				if errors.Is(err, platforms.ErrPlatformNotSupported) {
+					result.Status = ImportInvalid
+					result.Reason = fmt.Sprintf("platform %s is not configured", platformName)
+					continue
+				}
+				return results, err
+			}
+			catalogs[platformName] = catalog
+		}
+
+		program, found := catalog[strings.ToLower(handle)]
+		if !found {
+			result.Status = ImportNotFound
+			result.Suggestions = similarHandles(handle, catalog, maxImportSuggestions)
+			continue
+		}
+
+		// The handle may have been typed in another case than the platform uses
+		existing, err = s.programRepo.ResolveProgramByURL(ctx, program.ProgramURL)
+		if err != nil {
+			return results, err
+		}
+		if existing != nil {
+			result.Program = existing
+			result.Status = ImportExists
+			continue
+		}
+
+		dbProgram := program.ConvertToDatabaseProgram()
+		if err := s.programRepo.CreateProgram(ctx, dbProgram); err != nil {
+			return results, fmt.Errorf("failed to create program %s: %w", program.Name, err)
+		}
+		utils.Log(ctx).Infof("Imported program %s (%s)", dbProgram.Name, dbProgram.ProgramURL)
+		s.metrics.RecordProgramDiscovered(dbProgram.Platform)
+		s.events.Emit(ctx, events.TypeProgramCreated, dbProgram.ProgramURL, events.NewProgramData(dbProgram))
+
+		result.Program = dbProgram
+		result.Status = ImportCreated
+	}
+
+	return results, nil
+}
+
+// programCatalog fetches a platform's programs, keyed by lowercased handle
+func (s *MonitorService) programCatalog(ctx context.Context, platformName string) (map[string]*platforms.Program, error) {
+	platform, err := s.platformFactory.GetPlatform(platformName)
+	if err != nil {
+		return nil, err
+	}
+
+	programs, err := platform.GetPublicPrograms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s programs: %w", platformName, err)
+	}
+
+	catalog := make(map[string]*platforms.Program, len(programs))
+	for _, program := range programs {
+		_, handle, err := platforms.ParseProgramURL(program.ProgramURL)
+		if err != nil {
+			continue
+		}
+		catalog[strings.ToLower(handle)] = program
+	}
+
+	return catalog, nil
+}
+
+// similarHandles returns the program URLs of up to limit handles within a
+// small edit distance of handle, closest first
+func similarHandles(handle string, catalog map[string]*platforms.Program, limit int) []string {
+	handle = strings.ToLower(handle)
+	maxDistance := 1
+	if len(handle) >= 6 {
+		maxDistance = 2
+	}
+
+	type candidate struct {
+		url      string
+		distance int
+	}
+	var candidates []candidate
+	for key, program := range catalog {
+		distance := editDistance(handle, key)
+		// Handles extended with a suffix, e.g. acme -> acme-bbp, are likely
+		// the same program too
+		if distance > maxDistance && !strings.HasPrefix(key, handle+"-") && !strings.HasPrefix(key, handle+"_") {
+			continue
+		}
+		candidates = append(candidates, candidate{url: program.ProgramURL, distance: distance})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].url < candidates[j].url
+	})
+
+	var urls []string
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		urls = append(urls, candidates[i].url)
+	}
+	return urls
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
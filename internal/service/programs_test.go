@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("acme", "acme"))
+	assert.Equal(t, 1, editDistance("acme", "acne"))
+	assert.Equal(t, 1, editDistance("shopify", "shopfy"))
+	assert.Equal(t, 2, editDistance("gitlab", "gtilab"))
+	assert.Equal(t, 4, editDistance("", "acme"))
+}
+
+func TestSimilarHandles(t *testing.T) {
+	catalog := map[string]*platforms.Program{}
+	for _, handle := range []string{"shopify", "shopify-scripts", "spotify", "gitlab", "acme", "acme-bbp"} {
+		catalog[handle] = &platforms.Program{ProgramURL: platforms.ProgramURL("hackerone", handle)}
+	}
+
+	assert.Equal(t, []string{"https://hackerone.com/shopify"}, similarHandles("shopfy", catalog, 3))
+	assert.Equal(t, []string{"https://hackerone.com/gitlab"}, similarHandles("gtilab", catalog, 3))
+	assert.Equal(t, []string{"https://hackerone.com/acme-bbp"}, similarHandles("acme-bb", catalog, 3))
+	assert.Equal(t, []string{"https://hackerone.com/acme", "https://hackerone.com/acme-bbp"}, similarHandles("acme", catalog, 3)[:2])
+	assert.Empty(t, similarHandles("unrelated", catalog, 3))
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/scope"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// scopeQuarantinePlan is what a scan changes about assets whose scope root
+// left, or came back to, their program's scope
+type scopeQuarantinePlan struct {
+	Missing  []*database.Asset // assets whose scope root left the scope since the last scan
+	Due      []*database.Asset // assets out of scope for longer than the grace period and not yet quarantined
+	Restored []*database.Asset // assets marked missing whose scope root is back in scope
+}
+
+// planScopeQuarantine sorts a program's assets by whether they are still in
+// its scope. An asset that left the scope is due for quarantine once it has
+// been out of scope for the grace period.
+func planScopeQuarantine(assets []*database.Asset, inScope func(asset *database.Asset) bool, now time.Time, grace time.Duration) *scopeQuarantinePlan {
+	plan := &scopeQuarantinePlan{}
+
+	for _, asset := range assets {
+		if inScope(asset) {
+			if asset.ScopeMissingSince != nil {
+				plan.Restored = append(plan.Restored, asset)
+			}
+			continue
+		}
+
+		missingSince := now
+		if asset.ScopeMissingSince != nil {
+			missingSince = *asset.ScopeMissingSince
+		} else {
+			plan.Missing = append(plan.Missing, asset)
+		}
+
+		if asset.Status != database.AssetStatusQuarantined && !now.Before(missingSince.Add(grace)) {
+			plan.Due = append(plan.Due, asset)
+		}
+	}
+
+	return plan
+}
+
+// assetIDs returns the IDs of assets
+func assetIDs(assets []*database.Asset) []uuid.UUID {
+	ids := make([]uuid.UUID, len(assets))
+	for i, asset := range assets {
+		ids[i] = asset.ID
+	}
+	return ids
+}
+
+// quarantineOutOfScopeAssets marks the program's assets whose scope root is no
+// longer in its scope and, outside dry-run mode, quarantines the ones that
+// stayed out of scope for the grace period. A scope without any domain roots
+// is treated as a failed fetch and leaves the assets alone.
+func (s *MonitorService) quarantineOutOfScopeAssets(ctx context.Context, program *database.Program, inScopeAssets, outOfScopeAssets []*platforms.ScopeAsset) {
+	mode := s.config.Quarantine.Mode
+	if mode == "" || mode == config.QuarantineOff {
+		return
+	}
+
+	roots := s.extractUniqueDomains(inScopeAssets)
+	if len(roots) == 0 {
+		utils.Log(ctx).Debugf("Program %s has no in-scope domains, skipping scope quarantine", program.Name)
+		return
+	}
+
+	assets, err := s.assetRepo.GetAssetsByProgramID(ctx, program.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get assets of program %s for scope quarantine: %v", program.Name, err)
+		return
+	}
+
+	included, excluded := scope.NewInScopeMatcher(inScopeAssets), scope.NewMatcher(outOfScopeAssets)
+	inScope := func(asset *database.Asset) bool {
+		return included.Match(asset.URL, asset.IP, asset.IPv6) && !excluded.Match(asset.URL, asset.IP, asset.IPv6)
+	}
+	plan := planScopeQuarantine(assets, inScope, time.Now(), s.config.Quarantine.Grace)
+
+	if marked, err := s.assetRepo.MarkAssetsScopeMissing(ctx, assetIDs(plan.Missing)); err != nil {
+		utils.Log(ctx).Warnf("Failed to mark out-of-scope assets of program %s: %v", program.Name, err)
+	} else if marked > 0 {
+		utils.Log(ctx).Infof("%d assets of program %s left its scope and will be quarantined after %s", marked, program.Name, s.config.Quarantine.Grace)
+	}
+
+	if restored, err := s.assetRepo.ClearAssetsScopeMissing(ctx, assetIDs(plan.Restored)); err != nil {
+		utils.Log(ctx).Warnf("Failed to restore in-scope assets of program %s: %v", program.Name, err)
+	} else if restored > 0 {
+		utils.Log(ctx).Infof("%d assets of program %s are back in its scope", restored, program.Name)
+	}
+
+	if len(plan.Due) == 0 {
+		return
+	}
+	if mode == config.QuarantineDryRun {
+		for _, asset := range plan.Due {
+			utils.Log(ctx).Infof("[dry-run] Would quarantine %s of program %s: out of scope since %s", asset.URL, program.Name, scopeMissingSince(asset).Format(time.RFC3339))
+		}
+		return
+	}
+
+	quarantined, err := s.assetRepo.QuarantineAssets(ctx, assetIDs(plan.Due))
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to quarantine out-of-scope assets of program %s: %v", program.Name, err)
+		return
+	}
+	utils.Log(ctx).Infof("Quarantined %d assets of program %s that stayed out of its scope for %s", quarantined, program.Name, s.config.Quarantine.Grace)
+}
+
+// scopeMissingSince returns when an asset left its program's scope, or now
+// when it was only found missing by this scan
+func scopeMissingSince(asset *database.Asset) time.Time {
+	if asset.ScopeMissingSince != nil {
+		return *asset.ScopeMissingSince
+	}
+	return time.Now()
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanScopeQuarantine(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	grace := 72 * time.Hour
+	longAgo := now.Add(-96 * time.Hour)
+	recently := now.Add(-time.Hour)
+
+	inScope := &database.Asset{URL: "https://api.example.com", Status: "active"}
+	back := &database.Asset{URL: "https://www.example.com", Status: database.AssetStatusQuarantined, ScopeMissingSince: &longAgo}
+	newlyMissing := &database.Asset{URL: "https://legacy.old.com", Status: "active"}
+	waiting := &database.Asset{URL: "https://shop.old.com", Status: "active", ScopeMissingSince: &recently}
+	due := &database.Asset{URL: "https://blog.old.com", Status: "active", ScopeMissingSince: &longAgo}
+	quarantined := &database.Asset{URL: "https://mail.old.com", Status: database.AssetStatusQuarantined, ScopeMissingSince: &longAgo}
+
+	isInScope := func(asset *database.Asset) bool {
+		return asset == inScope || asset == back
+	}
+	plan := planScopeQuarantine([]*database.Asset{inScope, back, newlyMissing, waiting, due, quarantined}, isInScope, now, grace)
+
+	assert.Equal(t, []*database.Asset{newlyMissing}, plan.Missing)
+	assert.Equal(t, []*database.Asset{due}, plan.Due)
+	assert.Equal(t, []*database.Asset{back}, plan.Restored)
+
+	// Without a grace period assets are due as soon as they leave the scope
+	plan = planScopeQuarantine([]*database.Asset{newlyMissing}, isInScope, now, 0)
+	assert.Equal(t, []*database.Asset{newlyMissing}, plan.Missing)
+	assert.Equal(t, []*database.Asset{newlyMissing}, plan.Due)
+}
+
+func TestQuarantineOutOfScopeAssets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	program := &database.Program{ID: uuid.New(), Name: "Example"}
+	longAgo := time.Now().Add(-96 * time.Hour)
+	kept := &database.Asset{ID: uuid.New(), URL: "https://api.example.com", Status: "active"}
+	excluded := &database.Asset{ID: uuid.New(), URL: "https://admin.example.com", Status: "active"}
+	due := &database.Asset{ID: uuid.New(), URL: "https://legacy.old.com", Status: "active", ScopeMissingSince: &longAgo}
+	// Hosts outside the scope's domains stay in scope while they resolve into an in-scope range
+	ranged := &database.Asset{ID: uuid.New(), URL: "https://vpn.partner.net", IP: "10.1.2.3", Status: "active"}
+
+	inScopeAssets := []*platforms.ScopeAsset{
+		{URL: "*.example.com", Type: "wildcard", EligibleForSubmission: true},
+		{URL: "10.1.0.0/16", Type: "cidr", EligibleForSubmission: true},
+	}
+	outOfScopeAssets := []*platforms.ScopeAsset{{URL: "https://admin.example.com", Type: "url"}}
+
+	newService := func(mode string) *MonitorService {
+		return &MonitorService{
+			config:       &config.Config{Quarantine: config.QuarantineConfig{Mode: mode, Grace: 72 * time.Hour}},
+			assetRepo:    database.NewAssetRepository(sqlxDB),
+			urlProcessor: utils.NewURLProcessor(),
+		}
+	}
+	expectAssets := func() {
+		rows := sqlmock.NewRows([]string{"id", "url", "ip", "status", "scope_missing_since"})
+		for _, asset := range []*database.Asset{kept, excluded, due, ranged} {
+			rows.AddRow(asset.ID, asset.URL, asset.IP, asset.Status, asset.ScopeMissingSince)
+		}
+		mock.ExpectQuery("FROM assets a JOIN hosts h ON h.id = a.host_id WHERE a.program_id = \\$1").WithArgs(program.ID).WillReturnRows(rows)
+		mock.ExpectExec("UPDATE assets SET scope_missing_since = NOW\\(\\)").
+			WithArgs(pq.Array([]uuid.UUID{excluded.ID})).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	// Dry-run only records when assets left the scope
+	expectAssets()
+	newService(config.QuarantineDryRun).quarantineOutOfScopeAssets(context.Background(), program, inScopeAssets, outOfScopeAssets)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	expectAssets()
+	mock.ExpectExec("UPDATE assets SET status = \\$2").
+		WithArgs(pq.Array([]uuid.UUID{due.ID}), database.AssetStatusQuarantined).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	newService(config.QuarantineOn).quarantineOutOfScopeAssets(context.Background(), program, inScopeAssets, outOfScopeAssets)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// An empty scope looks like a failed fetch, so nothing is touched
+	newService(config.QuarantineOn).quarantineOutOfScopeAssets(context.Background(), program, nil, outOfScopeAssets)
+	newService(config.QuarantineOff).quarantineOutOfScopeAssets(context.Background(), program, inScopeAssets, outOfScopeAssets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// Asset quota alert kinds
+const (
+	QuotaAlertDrop   = "drop"
+	QuotaAlertGrowth = "growth"
+)
+
+// EffectiveBounds merges a program's overrides into the configured defaults
+func EffectiveBounds(defaults config.QuotaConfig, overrides *database.ProgramAssetBounds) (config.QuotaConfig, bool) {
+	bounds := defaults
+	if overrides == nil {
+		return bounds, true
+	}
+	if overrides.Disabled {
+		return bounds, false
+	}
+	if overrides.MaxDropPercent != nil {
+		bounds.MaxDropPercent = *overrides.MaxDropPercent
+	}
+	if overrides.MaxGrowth != nil {
+		bounds.MaxGrowth = *overrides.MaxGrowth
+	}
+	return bounds, true
+}
+
+// evaluateAssetQuota compares the assets seen by two consecutive scans against
+// the bounds and returns an alert for each bound that was exceeded
+func evaluateAssetQuota(previous, current int, bounds config.QuotaConfig) []*database.AssetQuotaAlert {
+	var alerts []*database.AssetQuotaAlert
+
+	if bounds.MaxDropPercent > 0 && previous > 0 && previous >= bounds.MinAssets && current < previous {
+		dropPercent := float64(previous-current) / float64(previous) * 100
+		if dropPercent > bounds.MaxDropPercent {
+			alerts = append(alerts, &database.AssetQuotaAlert{
+				Kind:          QuotaAlertDrop,
+				PreviousCount: previous,
+				CurrentCount:  current,
+				Threshold:     bounds.MaxDropPercent,
+				Message: fmt.Sprintf("assets seen dropped %.1f%% (%d -> %d), above the %.1f%% bound",
+					dropPercent, previous, current, bounds.MaxDropPercent),
+			})
+		}
+	}
+
+	if bounds.MaxGrowth > 0 && current-previous > bounds.MaxGrowth {
+		alerts = append(alerts, &database.AssetQuotaAlert{
+			Kind:          QuotaAlertGrowth,
+			PreviousCount: previous,
+			CurrentCount:  current,
+			Threshold:     float64(bounds.MaxGrowth),
+			Message: fmt.Sprintf("assets seen grew by %d (%d -> %d), above the %d bound",
+				current-previous, previous, current, bounds.MaxGrowth),
+		})
+	}
+
+	return alerts
+}
+
+// checkAssetQuota compares a finished scan with the program's previous completed
+// scan and records an alert for every quota bound the change exceeds
+func (s *MonitorService) checkAssetQuota(ctx context.Context, program *database.Program, scan *database.Scan) {
+	previous, err := s.scanRepo.GetPreviousCompletedScan(ctx, program.ID, scan.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get previous scan for quota check of %s: %v", program.Name, err)
+		return
+	}
+	if previous == nil {
+		return
+	}
+
+	overrides, err := s.quotaRepo.GetBounds(ctx, program.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get asset bounds for %s: %v", program.Name, err)
+		return
+	}
+
+	bounds, enabled := EffectiveBounds(s.config.Quota, overrides)
+	if !enabled {
+		return
+	}
+
+	for _, alert := range evaluateAssetQuota(previous.AssetsSeen, scan.AssetsSeen, bounds) {
+		alert.ProgramID = program.ID
+		alert.ScanID = &scan.ID
+		utils.Log(ctx).Warnf("Asset quota alert for program %s: %s", program.Name, alert.Message)
+		if err := s.quotaRepo.CreateAlert(ctx, alert); err != nil {
+			utils.Log(ctx).Errorf("Failed to record quota alert for %s: %v", program.Name, err)
+		}
+	}
+}
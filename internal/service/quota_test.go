@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateAssetQuota(t *testing.T) {
+	bounds := config.QuotaConfig{MaxDropPercent: 30, MaxGrowth: 500, MinAssets: 10}
+
+	tests := []struct {
+		name     string
+		previous int
+		current  int
+		bounds   config.QuotaConfig
+		want     []string
+	}{
+		{"within bounds", 100, 90, bounds, nil},
+		{"drop exactly at bound", 100, 70, bounds, nil},
+		{"drop above bound", 100, 60, bounds, []string{QuotaAlertDrop}},
+		{"everything dead", 200, 0, bounds, []string{QuotaAlertDrop}},
+		{"small program ignored", 5, 0, bounds, nil},
+		{"growth above bound", 100, 700, bounds, []string{QuotaAlertGrowth}},
+		{"first assets count as growth", 0, 800, bounds, []string{QuotaAlertGrowth}},
+		{"checks disabled", 100, 0, config.QuotaConfig{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := evaluateAssetQuota(tt.previous, tt.current, tt.bounds)
+			var kinds []string
+			for _, alert := range alerts {
+				kinds = append(kinds, alert.Kind)
+				assert.Equal(t, tt.previous, alert.PreviousCount)
+				assert.Equal(t, tt.current, alert.CurrentCount)
+				assert.NotEmpty(t, alert.Message)
+			}
+			assert.Equal(t, tt.want, kinds)
+		})
+	}
+}
+
+func TestEffectiveBounds(t *testing.T) {
+	defaults := config.QuotaConfig{MaxDropPercent: 30, MaxGrowth: 500, MinAssets: 10}
+
+	bounds, enabled := EffectiveBounds(defaults, nil)
+	assert.True(t, enabled)
+	assert.Equal(t, defaults, bounds)
+
+	drop := 50.0
+	bounds, enabled = EffectiveBounds(defaults, &database.ProgramAssetBounds{MaxDropPercent: &drop})
+	assert.True(t, enabled)
+	assert.Equal(t, 50.0, bounds.MaxDropPercent)
+	assert.Equal(t, 500, bounds.MaxGrowth)
+
+	_, enabled = EffectiveBounds(defaults, &database.ProgramAssetBounds{Disabled: true})
+	assert.False(t, enabled)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly is returned by operations that scan, probe targets or change
+// data when the agent runs in read-only mode
+var ErrReadOnly = errors.New("not allowed in read-only mode")
+
+// Writable returns ErrReadOnly when the agent runs in read-only mode
+// (--read-only or READ_ONLY), so callers can refuse an operation up front
+func (s *MonitorService) Writable() error {
+	if s.config != nil && s.config.App.ReadOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// checkWritable refuses operation in read-only mode
+func (s *MonitorService) checkWritable(operation string) error {
+	if err := s.Writable(); err != nil {
+		return fmt.Errorf("%s: %w", operation, err)
+	}
+	return nil
+}
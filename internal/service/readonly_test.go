@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	prober := &staticProber{}
+	s := &MonitorService{
+		config: &config.Config{App: config.AppConfig{ReadOnly: true}},
+		prober: prober,
+	}
+
+	assert.ErrorIs(t, s.Writable(), ErrReadOnly)
+	assert.ErrorIs(t, s.RunFullScan(context.Background()), ErrReadOnly)
+	assert.ErrorIs(t, s.CancelScan(context.Background(), uuid.New()), ErrReadOnly)
+	_, err := s.SweepOnce(context.Background(), 10)
+	assert.ErrorIs(t, err, ErrReadOnly)
+	_, err = s.PruneResponses(context.Background(), database.ResponseRetention{KeepPerAsset: 1})
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.Empty(t, prober.probed)
+
+	s.config.App.ReadOnly = false
+	assert.NoError(t, s.Writable())
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ResponseRetention returns the configured retention of asset responses
+func (s *MonitorService) ResponseRetention() database.ResponseRetention {
+	return database.ResponseRetention{
+		KeepPerAsset: s.config.Retention.ResponsesPerAsset,
+		MaxAge:       s.config.Retention.ResponseMaxAge,
+	}
+}
+
+// PruneResponses deletes the asset responses outside a retention and the
+// offloaded bodies only they referred to, and records the deleted rows in the
+// metrics
+func (s *MonitorService) PruneResponses(ctx context.Context, retention database.ResponseRetention) (int64, error) {
+	if err := s.checkWritable("prune"); err != nil {
+		return 0, err
+	}
+
+	pruned, bodyRefs, err := s.assetRepo.PruneResponses(ctx, retention)
+	if pruned > 0 {
+		s.metrics.RecordRowsPruned(database.TableAssetResponses, pruned)
+	}
+	s.deleteBodies(ctx, bodyRefs)
+	return pruned, err
+}
+
+// pruneAfterScan applies the configured response retention once a full scan
+// stored its responses. Failures are logged and never fail the scan.
+func (s *MonitorService) pruneAfterScan(ctx context.Context) {
+	retention := s.ResponseRetention()
+	if !retention.Enabled() {
+		return
+	}
+
+	pruned, err := s.PruneResponses(ctx, retention)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to prune asset responses after deleting %d: %v", pruned, err)
+		return
+	}
+	utils.Log(ctx).Infof("Pruned %d asset responses outside the retention", pruned)
+}
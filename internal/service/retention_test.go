@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneAfterScan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	s := &MonitorService{
+		config:    &config.Config{},
+		assetRepo: database.NewAssetRepository(sqlxDB),
+		metrics:   metrics.NewMetrics(),
+	}
+
+	// Without a retention every response is kept
+	s.pruneAfterScan(context.Background())
+
+	s.config.Retention = config.RetentionConfig{ResponsesPerAsset: 2, ResponseMaxAge: 720 * time.Hour}
+	mock.ExpectQuery("DELETE FROM asset_responses").WithArgs(2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "refs"}).AddRow(7, "{}"))
+	s.pruneAfterScan(context.Background())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	recorder := httptest.NewRecorder()
+	s.metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, recorder.Body.String(), `monitor_agent_db_rows_pruned_total{table="asset_responses"} 7`)
+}
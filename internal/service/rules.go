@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/rules"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// loadRules loads the configured triage rules, returning nil when rules are disabled
+func loadRules(cfg *config.Config) *rules.Engine {
+	if cfg.Rules.File == "" {
+		return nil
+	}
+
+	engine, err := rules.LoadFile(cfg.Rules.File)
+	if err != nil {
+		logrus.Errorf("Triage rules disabled: %v", err)
+		return nil
+	}
+
+	logrus.Infof("Loaded %d triage rules from %s", len(engine.Rules()), cfg.Rules.File)
+	return engine
+}
+
+// applyRules evaluates the triage rules on a saved asset response, tagging the
+// asset and emitting a rule.matched event for each matching rule
+func (s *MonitorService) applyRules(ctx context.Context, asset *database.Asset, response *database.AssetResponse, result *httpx.DetailedProbeResult) {
+	if s.rules == nil || s.tagRepo == nil {
+		return
+	}
+
+	matched := s.rules.Evaluate(&rules.Input{
+		URL:          asset.URL,
+		Source:       asset.Source,
+		StatusCode:   result.StatusCode,
+		Title:        result.Title,
+		Body:         result.Body,
+		Headers:      result.Headers,
+		Technologies: result.Technologies,
+	})
+
+	for _, rule := range matched {
+		utils.Log(ctx).Infof("Triage rule %s matched %s", rule.Name, asset.URL)
+
+		if len(rule.Then.Tags) > 0 {
+			if err := s.tagRepo.AddAssetTags(ctx, asset.ID, rule.Then.Tags, "rule:"+rule.Name); err != nil {
+				utils.Log(ctx).Warnf("Failed to tag %s for rule %s: %v", asset.URL, rule.Name, err)
+			}
+		}
+
+		match := &database.RuleMatch{
+			AssetID:    asset.ID,
+			ResponseID: &response.ID,
+			RuleName:   rule.Name,
+			Tags:       strings.Join(rule.Then.Tags, ","),
+			Notify:     rule.Then.Notify,
+			Enqueue:    strings.Join(rule.Then.Enqueue, ","),
+		}
+		if err := s.tagRepo.CreateRuleMatch(ctx, match); err != nil {
+			utils.Log(ctx).Warnf("Failed to record rule %s match for %s: %v", rule.Name, asset.URL, err)
+		}
+
+		s.events.Emit(ctx, events.TypeRuleMatched, asset.URL, events.RuleMatchedData{
+			Rule:       rule.Name,
+			Asset:      events.NewAssetData(asset),
+			StatusCode: result.StatusCode,
+			Title:      result.Title,
+			Tags:       slices.Clone(rule.Then.Tags),
+			Notify:     rule.Then.Notify,
+			Enqueue:    slices.Clone(rule.Then.Enqueue),
+		})
+	}
+}
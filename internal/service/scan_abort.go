@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ErrScanTimedOut is the context cause of scans stopped by SCAN_TIMEOUT
+var ErrScanTimedOut = errors.New("scan timed out")
+
+// staleScanAfter is how long a running scan may go without a heartbeat
+// before it is considered left behind by a process that stopped. Running
+// scans send one every scanCancelPollInterval.
+const staleScanAfter = 2 * time.Minute
+
+// abortReason returns why the scans of ctx were aborted: the agent shutting
+// down or SCAN_TIMEOUT passing. It is empty while they were not.
+func abortReason(ctx context.Context) string {
+	switch cause := context.Cause(ctx); {
+	case errors.Is(cause, ErrShutdown):
+		return "interrupted by shutdown"
+	case errors.Is(cause, ErrScanTimedOut):
+		return "stopped by SCAN_TIMEOUT"
+	default:
+		return ""
+	}
+}
+
+// AbortStaleScans marks the scans a previous process left running when it
+// crashed or was killed as aborted. Scans whose process still sends
+// heartbeats, e.g. those of another agent sharing the database, are left
+// alone. It returns how many scans were aborted.
+func (s *MonitorService) AbortStaleScans(ctx context.Context) (int, error) {
+	if err := s.checkWritable("abort stale scans"); err != nil {
+		return 0, err
+	}
+
+	scans, err := s.scanRepo.AbortStaleScans(ctx, time.Now().Add(-staleScanAfter), "the agent running the scan stopped before it finished")
+	if err != nil {
+		return 0, err
+	}
+	for _, scan := range scans {
+		utils.Log(ctx).Warnf("Scan %s of program %s was left running by an agent that stopped, marked it aborted", scan.ID, scan.ProgramID)
+	}
+	return len(scans), nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbortReason(t *testing.T) {
+	assert.Empty(t, abortReason(context.Background()))
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrShutdown)
+	assert.Equal(t, "interrupted by shutdown", abortReason(ctx))
+
+	// Program contexts derived from the scan's report why the scan stopped
+	ctx, cancel = context.WithCancelCause(context.Background())
+	programCtx, cancelProgram := context.WithTimeout(ctx, time.Hour)
+	defer cancelProgram()
+	cancel(fmt.Errorf("deadline: %w", ErrScanTimedOut))
+	assert.Equal(t, "stopped by SCAN_TIMEOUT", abortReason(programCtx))
+
+	// Cancel requests and program timeouts are not aborts
+	ctx, cancel = context.WithCancelCause(context.Background())
+	cancel(ErrScanCancelled)
+	assert.Empty(t, abortReason(ctx))
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), 0)
+	defer cancelTimeout()
+	assert.Empty(t, abortReason(ctx))
+}
+
+func TestAbortStaleScans(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	s := &MonitorService{
+		config:   &config.Config{},
+		scanRepo: database.NewScanRepository(sqlx.NewDb(db, "sqlmock")),
+	}
+
+	mock.ExpectQuery("UPDATE scans SET status = 'aborted'").
+		WithArgs(sqlmock.AnyArg(), "the agent running the scan stopped before it finished").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "status"}).
+			AddRow(uuid.New(), uuid.New(), "aborted").
+			AddRow(uuid.New(), uuid.New(), "aborted"))
+
+	aborted, err := s.AbortStaleScans(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, aborted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Read-only agents leave the scans alone
+	s.config.App.ReadOnly = true
+	_, err = s.AbortStaleScans(context.Background())
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ErrScanCancelled is the context cause of scans cancelled on request
+var ErrScanCancelled = errors.New("scan cancelled")
+
+// scanCancelPollInterval is how often a running scan checks the database for a
+// cancel request made by another process and sends a heartbeat
+const scanCancelPollInterval = 5 * time.Second
+
+// runningScans tracks the cancel functions of scans running in this process
+type runningScans struct {
+	mu      sync.Mutex
+	cancels map[uuid.UUID]context.CancelCauseFunc
+}
+
+// add registers a running scan
+func (r *runningScans) add(id uuid.UUID, cancel context.CancelCauseFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancels == nil {
+		r.cancels = make(map[uuid.UUID]context.CancelCauseFunc)
+	}
+	r.cancels[id] = cancel
+}
+
+// remove unregisters a finished scan
+func (r *runningScans) remove(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cancels, id)
+}
+
+// cancel cancels a scan running in this process, reporting whether it was found
+func (r *runningScans) cancel(id uuid.UUID) bool {
+	r.mu.Lock()
+	cancel, ok := r.cancels[id]
+	r.mu.Unlock()
+	if ok {
+		cancel(ErrScanCancelled)
+	}
+	return ok
+}
+
+// watchScanCancel derives a context for a scan that is cancelled when the scan
+// is cancelled by ID, either in this process or through the database. Every
+// poll of the database also records a heartbeat for the scan, so a scan left
+// running by a process that stopped can be told apart. The returned stop
+// function must be called once the scan finishes.
+func (s *MonitorService) watchScanCancel(ctx context.Context, scanID uuid.UUID) (context.Context, func()) {
+	scanCtx, cancel := context.WithCancelCause(ctx)
+	s.runningScans.add(scanID, cancel)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(scanCancelPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-scanCtx.Done():
+				return
+			case <-ticker.C:
+				requested, err := s.scanRepo.HeartbeatScan(scanCtx, scanID)
+				if err != nil {
+					utils.Log(ctx).Warnf("Failed to send heartbeat of scan %s: %v", scanID, err)
+					continue
+				}
+				if requested {
+					utils.Log(ctx).Infof("Cancel requested for scan %s, stopping it", scanID)
+					cancel(ErrScanCancelled)
+					return
+				}
+			}
+		}
+	}()
+
+	return scanCtx, func() {
+		close(done)
+		s.runningScans.remove(scanID)
+		cancel(nil)
+	}
+}
+
+// CancelScan cancels a running scan. The request is recorded in the database so
+// a scan running in another process stops within scanCancelPollInterval; a scan
+// running in this process is cancelled immediately.
+func (s *MonitorService) CancelScan(ctx context.Context, scanID uuid.UUID) error {
+	if err := s.checkWritable("cancel scan"); err != nil {
+		return err
+	}
+
+	if err := s.scanRepo.RequestScanCancel(ctx, scanID); err != nil {
+		return err
+	}
+
+	if s.runningScans.cancel(scanID) {
+		utils.Log(ctx).Infof("Cancelled scan %s", scanID)
+	} else {
+		utils.Log(ctx).Infof("Requested cancel of scan %s", scanID)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunningScans_Cancel(t *testing.T) {
+	var scans runningScans
+	scanID := uuid.New()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	scans.add(scanID, cancel)
+
+	assert.False(t, scans.cancel(uuid.New()))
+	assert.NoError(t, ctx.Err())
+
+	assert.True(t, scans.cancel(scanID))
+	assert.Error(t, ctx.Err())
+	assert.True(t, errors.Is(context.Cause(ctx), ErrScanCancelled))
+
+	scans.remove(scanID)
+	assert.False(t, scans.cancel(scanID))
+}
+
+func TestWatchScanCancel_LocalCancel(t *testing.T) {
+	s := &MonitorService{}
+	scanID := uuid.New()
+
+	ctx, stop := s.watchScanCancel(context.Background(), scanID)
+	assert.True(t, s.runningScans.cancel(scanID))
+
+	<-ctx.Done()
+	assert.True(t, errors.Is(context.Cause(ctx), ErrScanCancelled))
+
+	stop()
+	assert.False(t, s.runningScans.cancel(scanID))
+}
+
+func TestWatchScanCancel_StopWithoutCancel(t *testing.T) {
+	s := &MonitorService{}
+
+	ctx, stop := s.watchScanCancel(context.Background(), uuid.New())
+	stop()
+
+	<-ctx.Done()
+	assert.False(t, errors.Is(context.Cause(ctx), ErrScanCancelled))
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// scanCheckpoint records how far a full scan run got, so that a run that was
+// interrupted can be resumed without processing its programs again. A nil
+// checkpoint records and skips nothing; failing to record progress never
+// fails a scan.
+type scanCheckpoint struct {
+	repo *database.ScanRunRepository
+	run  *database.ScanRun
+
+	mu        sync.Mutex                 // platforms are scanned concurrently
+	platforms map[string]bool            // platforms whose programs were all processed
+	programs  map[string]map[string]bool // processed program URLs by platform
+}
+
+// startScanRun records the start of a full scan run
+func (s *MonitorService) startScanRun(ctx context.Context) *scanCheckpoint {
+	if s.scanRuns == nil {
+		return nil
+	}
+
+	run, err := s.scanRuns.CreateScanRun(ctx)
+	if err != nil {
+		utils.Log(ctx).Warnf("Scan progress will not be recorded, the scan cannot be resumed: %v", err)
+		return nil
+	}
+
+	utils.Log(ctx).Infof("Started scan run %s", run.ID)
+	return newScanCheckpoint(s.scanRuns, run)
+}
+
+// resumeScanRun picks up the latest scan run when it did not complete, with
+// the platforms and programs it already processed. It returns nil when there
+// is no run to resume.
+func (s *MonitorService) resumeScanRun(ctx context.Context) (*scanCheckpoint, error) {
+	if s.scanRuns == nil {
+		return nil, nil
+	}
+
+	run, err := s.scanRuns.GetLatestScanRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil || run.Status == database.ScanRunCompleted {
+		return nil, nil
+	}
+
+	platforms, err := s.scanRuns.GetPlatforms(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	programs, err := s.scanRuns.GetProcessedPrograms(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if run.Status == database.ScanRunRunning {
+		utils.Log(ctx).Warnf("Scan run %s is still marked running; assuming the agent running it stopped", run.ID)
+	}
+	if err := s.scanRuns.ResumeScanRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	checkpoint := newScanCheckpoint(s.scanRuns, run)
+	for _, platform := range platforms {
+		if platform.CompletedAt != nil {
+			checkpoint.platforms[platform.Platform] = true
+		}
+	}
+	for _, program := range programs {
+		checkpoint.markProcessed(program.Platform, program.ProgramURL)
+	}
+
+	utils.Log(ctx).Infof("Resuming scan run %s started at %s: %d programs on %d platforms were already processed",
+		run.ID, run.StartedAt.Local().Format("2006-01-02 15:04:05"), len(programs), len(checkpoint.platforms))
+	return checkpoint, nil
+}
+
+// newScanCheckpoint creates a checkpoint of a run with nothing processed yet
+func newScanCheckpoint(repo *database.ScanRunRepository, run *database.ScanRun) *scanCheckpoint {
+	return &scanCheckpoint{
+		repo:      repo,
+		run:       run,
+		platforms: make(map[string]bool),
+		programs:  make(map[string]map[string]bool),
+	}
+}
+
+// platformDone reports whether the run already processed every program of a platform
+func (c *scanCheckpoint) platformDone(platform string) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.platforms[platform]
+}
+
+// programDone reports whether the run already processed a program
+func (c *scanCheckpoint) programDone(platform, programURL string) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.programs[platform][programURL]
+}
+
+// markProcessed remembers a processed program
+func (c *scanCheckpoint) markProcessed(platform, programURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.programs[platform] == nil {
+		c.programs[platform] = make(map[string]bool)
+	}
+	c.programs[platform][programURL] = true
+}
+
+// startPlatform records how many programs a platform listed
+func (c *scanCheckpoint) startPlatform(ctx context.Context, platform string, programs int) {
+	if c == nil {
+		return
+	}
+
+	if err := c.repo.StartPlatform(ctx, c.run.ID, platform, programs); err != nil {
+		utils.Log(ctx).Warnf("Failed to record scan progress on %s: %v", platform, err)
+	}
+}
+
+// programProcessed records that the run processed a program
+func (c *scanCheckpoint) programProcessed(ctx context.Context, platform, programURL string) {
+	if c == nil {
+		return
+	}
+
+	c.markProcessed(platform, programURL)
+	if err := c.repo.MarkProgramProcessed(ctx, c.run.ID, platform, programURL); err != nil {
+		utils.Log(ctx).Warnf("Failed to record scan progress of %s: %v", programURL, err)
+	}
+}
+
+// completePlatform records that the run processed every program of a platform
+func (c *scanCheckpoint) completePlatform(ctx context.Context, platform string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.platforms[platform] = true
+	c.mu.Unlock()
+
+	if err := c.repo.CompletePlatform(ctx, c.run.ID, platform); err != nil {
+		utils.Log(ctx).Warnf("Failed to record scan progress on %s: %v", platform, err)
+	}
+}
+
+// finish records how the run ended, even when ctx was cancelled
+func (c *scanCheckpoint) finish(ctx context.Context, status string) {
+	if c == nil {
+		return
+	}
+
+	if err := c.repo.FinishScanRun(context.WithoutCancel(ctx), c.run, status); err != nil {
+		utils.Log(ctx).Warnf("Failed to record the end of scan run %s: %v", c.run.ID, err)
+		return
+	}
+	if status != database.ScanRunCompleted {
+		utils.Log(ctx).Infof("Scan run %s %s; run `monitor-agent scan --resume` to continue it", c.run.ID, status)
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scanRunColumns = []string{"id", "status", "resumes", "started_at", "updated_at", "completed_at"}
+
+func newScanRunService(t *testing.T) (*MonitorService, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	return &MonitorService{scanRuns: database.NewScanRunRepository(sqlxDB)}, mock
+}
+
+func TestResumeScanRun(t *testing.T) {
+	s, mock := newScanRunService(t)
+	runID := uuid.New()
+	startedAt := time.Now().Add(-time.Hour)
+	completedAt := time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM scan_runs").
+		WillReturnRows(sqlmock.NewRows(scanRunColumns).AddRow(runID, database.ScanRunInterrupted, 0, startedAt, startedAt, nil))
+	mock.ExpectQuery("SELECT \\* FROM scan_run_platforms").WithArgs(runID).
+		WillReturnRows(sqlmock.NewRows([]string{"run_id", "platform", "programs", "completed_at"}).
+			AddRow(runID, "bugcrowd", 3, completedAt).
+			AddRow(runID, "hackerone", 5, nil))
+	mock.ExpectQuery("SELECT \\* FROM scan_run_programs").WithArgs(runID).
+		WillReturnRows(sqlmock.NewRows([]string{"run_id", "platform", "program_url", "processed_at"}).
+			AddRow(runID, "hackerone", "https://hackerone.com/acme", completedAt))
+	mock.ExpectQuery("UPDATE scan_runs SET status").WithArgs(runID, database.ScanRunRunning).
+		WillReturnRows(sqlmock.NewRows([]string{"resumes", "updated_at"}).AddRow(1, time.Now()))
+
+	checkpoint, err := s.resumeScanRun(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, runID, checkpoint.run.ID)
+	assert.Equal(t, 1, checkpoint.run.Resumes)
+
+	assert.True(t, checkpoint.platformDone("bugcrowd"))
+	assert.False(t, checkpoint.platformDone("hackerone"))
+	assert.True(t, checkpoint.programDone("hackerone", "https://hackerone.com/acme"))
+	assert.False(t, checkpoint.programDone("hackerone", "https://hackerone.com/other"))
+	assert.False(t, checkpoint.programDone("bugcrowd", "https://hackerone.com/acme"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResumeScanRun_NothingToResume(t *testing.T) {
+	s, mock := newScanRunService(t)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT \\* FROM scan_runs").
+		WillReturnRows(sqlmock.NewRows(scanRunColumns).AddRow(uuid.New(), database.ScanRunCompleted, 0, now, now, now))
+	mock.ExpectQuery("SELECT \\* FROM scan_runs").
+		WillReturnRows(sqlmock.NewRows(scanRunColumns))
+
+	checkpoint, err := s.resumeScanRun(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint, "the latest run completed")
+
+	checkpoint, err = s.resumeScanRun(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint, "no run was recorded")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanCheckpoint_RecordsProgress(t *testing.T) {
+	s, mock := newScanRunService(t)
+	ctx := context.Background()
+
+	mock.ExpectExec("INSERT INTO scan_runs").WillReturnResult(sqlmock.NewResult(0, 1))
+	checkpoint := s.startScanRun(ctx)
+	require.NotNil(t, checkpoint)
+	runID := checkpoint.run.ID
+
+	mock.ExpectExec("INSERT INTO scan_run_programs").WithArgs(runID, "hackerone", "https://hackerone.com/acme").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO scan_run_platforms").WithArgs(runID, "hackerone").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE scan_runs SET status").WithArgs(runID, database.ScanRunInterrupted, sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	checkpoint.programProcessed(ctx, "hackerone", "https://hackerone.com/acme")
+	assert.True(t, checkpoint.programDone("hackerone", "https://hackerone.com/acme"))
+	checkpoint.completePlatform(ctx, "hackerone")
+	assert.True(t, checkpoint.platformDone("hackerone"))
+
+	// The run is recorded even after the scan's context was cancelled
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	checkpoint.finish(cancelled, database.ScanRunInterrupted)
+	assert.Equal(t, database.ScanRunInterrupted, checkpoint.run.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanCheckpoint_Nil(t *testing.T) {
+	var checkpoint *scanCheckpoint
+	ctx := context.Background()
+
+	assert.False(t, checkpoint.platformDone("hackerone"))
+	assert.False(t, checkpoint.programDone("hackerone", "https://hackerone.com/acme"))
+	checkpoint.startPlatform(ctx, "hackerone", 1)
+	checkpoint.programProcessed(ctx, "hackerone", "https://hackerone.com/acme")
+	checkpoint.completePlatform(ctx, "hackerone")
+	checkpoint.finish(ctx, database.ScanRunCompleted)
+
+	assert.Nil(t, (&MonitorService{}).startScanRun(ctx))
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/monitor-agent/internal/cron"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// ErrShutdown is the cause of cancelling the agent's context when it shuts
+// down; scans it interrupts are recorded as aborted, not completed
+var ErrShutdown = errors.New("agent shutting down")
+
+// scheduledRunKey is the context key of the schedule time of a scan run
+type scheduledRunKey struct{}
+
+// withScheduledRun marks the scans of ctx as started by the schedule at
+func withScheduledRun(ctx context.Context, at time.Time) context.Context {
+	return context.WithValue(ctx, scheduledRunKey{}, at)
+}
+
+// scheduledRun returns the schedule time that started the scans of ctx, or
+// nil for scans run on demand
+func scheduledRun(ctx context.Context) *time.Time {
+	if at, ok := ctx.Value(scheduledRunKey{}).(time.Time); ok {
+		return &at
+	}
+	return nil
+}
+
+// RunScheduledScans runs a full scan every time schedule fires until ctx is
+// cancelled. A run missed while no daemon was running is caught up on start.
+// Runs are never overlapped: one that comes while the previous run still
+// scans, or while another daemon sharing the database runs the schedule, is
+// skipped.
+func (s *MonitorService) RunScheduledScans(ctx context.Context, schedule *cron.Schedule) error {
+	if err := s.checkWritable("scheduled scans"); err != nil {
+		return err
+	}
+
+	last, err := s.scanRepo.GetLastScheduledRun(ctx)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get the last scheduled run, waiting for the next one: %v", err)
+	}
+
+	next, missed := nextScheduledRun(schedule, last, time.Now())
+	if missed {
+		utils.Log(ctx).Infof("Scheduled scans started: %s; catching up the run of %s missed since the last one", schedule, next.Format(time.RFC3339))
+	} else {
+		utils.Log(ctx).Infof("Scheduled scans started: %s; next run at %s", schedule, next.Format(time.RFC3339))
+	}
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Log(ctx).Info("Scheduled scans stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		s.runScheduledScan(ctx, next)
+		if ctx.Err() != nil {
+			utils.Log(ctx).Info("Scheduled scans stopped")
+			return nil
+		}
+
+		// Runs that came while this one scanned are skipped, not queued
+		now := time.Now()
+		if following := schedule.Next(next); following.Before(now) {
+			utils.Log(ctx).Warnf("Scheduled scan of %s ran past the run of %s, which is skipped", next.Format(time.RFC3339), following.Format(time.RFC3339))
+		}
+		next = schedule.Next(now)
+		utils.Log(ctx).Infof("Next scheduled scan at %s", next.Format(time.RFC3339))
+		timer.Reset(time.Until(next))
+	}
+}
+
+// nextScheduledRun returns when the schedule runs next. When it fired since
+// the last successful run, that run was missed and is returned to be run now,
+// as the latest time it fired.
+func nextScheduledRun(schedule *cron.Schedule, last *time.Time, now time.Time) (time.Time, bool) {
+	next := schedule.Next(now)
+	if last == nil {
+		return next, false
+	}
+
+	missed := schedule.Next(*last)
+	if !missed.Before(now) {
+		return next, false
+	}
+	for following := schedule.Next(missed); following.Before(now); following = schedule.Next(following) {
+		missed = following
+	}
+	return missed, true
+}
+
+// runScheduledScan runs the full scan the schedule started at, unless another
+// process holds the schedule
+func (s *MonitorService) runScheduledScan(ctx context.Context, at time.Time) {
+	run := at.Format(time.RFC3339)
+
+	unlock, locked, err := s.scanRepo.TryLockSchedule(ctx)
+	if err != nil {
+		utils.Log(ctx).Errorf("Scheduled scan of %s not started: %v", run, err)
+		return
+	}
+	if !locked {
+		utils.Log(ctx).Warnf("Skipping the scheduled scan of %s: another daemon is running the schedule", run)
+		return
+	}
+	defer unlock()
+
+	utils.Log(ctx).Infof("Starting the scheduled scan of %s", run)
+	start := time.Now()
+	if err := s.RunFullScan(withScheduledRun(ctx, at)); err != nil {
+		utils.Log(ctx).Errorf("Scheduled scan of %s failed after %v: %v", run, time.Since(start).Round(time.Second), err)
+		return
+	}
+	utils.Log(ctx).Infof("Scheduled scan of %s completed in %v", run, time.Since(start).Round(time.Second))
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/cron"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextScheduledRun(t *testing.T) {
+	schedule, err := cron.Parse("0 3 * * *")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+	tomorrow := time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)
+
+	// Never ran: wait for the schedule
+	next, missed := nextScheduledRun(schedule, nil, now)
+	assert.Equal(t, tomorrow, next)
+	assert.False(t, missed)
+
+	// Ran this morning
+	next, missed = nextScheduledRun(schedule, &today, now)
+	assert.Equal(t, tomorrow, next)
+	assert.False(t, missed)
+
+	// Down for three days: only the latest missed run is caught up
+	threeDaysAgo := today.AddDate(0, 0, -3)
+	next, missed = nextScheduledRun(schedule, &threeDaysAgo, now)
+	assert.Equal(t, today, next)
+	assert.True(t, missed)
+}
+
+func TestScheduledRun(t *testing.T) {
+	assert.Nil(t, scheduledRun(context.Background()))
+
+	at := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+	assert.Equal(t, at, *scheduledRun(withScheduledRun(context.Background(), at)))
+}
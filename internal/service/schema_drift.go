@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/schemadrift"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// recordSchemaDrift stores the payload drift the platform clients detected
+// since the last call, so it outlives the warnings in the log
+func (s *MonitorService) recordSchemaDrift(ctx context.Context) {
+	drifts := schemadrift.Default.Drain()
+	if len(drifts) == 0 || s.schemaDriftRepo == nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	for _, drift := range drifts {
+		if err := s.schemaDriftRepo.RecordSchemaDrift(ctx, &database.SchemaDrift{
+			Platform: drift.Platform,
+			Payload:  drift.Payload,
+			Field:    drift.Field,
+			Change:   drift.Change,
+		}); err != nil {
+			utils.Log(ctx).Warnf("Failed to record schema drift: %v", err)
+			return
+		}
+	}
+
+	utils.Log(ctx).Warnf("Platform payloads drifted from the expected schema in %d fields; run 'monitor-agent stats' for details", len(drifts))
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/scope"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// recordScopeSnapshot saves a program's fully fetched scope when it differs
+// from the last recorded snapshot and announces the in-scope targets that were
+// added or removed. The first snapshot of a program is only a baseline.
+// Failures are logged and never fail the scan.
+func (s *MonitorService) recordScopeSnapshot(ctx context.Context, program *database.Program, scanID uuid.UUID, scope *scopeCollector) {
+	if s.scopeRepo == nil {
+		return
+	}
+
+	snapshot := newScopeSnapshot(program.ID, scanID, scope.targets, scope.excluded)
+
+	previous, err := s.scopeRepo.GetLatestScope(ctx, program.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get the previous scope of program %s: %v", program.Name, err)
+		return
+	}
+	if previous != nil && previous.ContentHash == snapshot.ContentHash {
+		return
+	}
+
+	if err := s.scopeRepo.CreateScope(ctx, snapshot); err != nil {
+		utils.Log(ctx).Warnf("Failed to record the scope of program %s: %v", program.Name, err)
+		return
+	}
+
+	if previous == nil {
+		utils.Log(ctx).Debugf("Recorded the first scope snapshot of program %s with %d in-scope targets", program.Name, len(snapshot.InScope))
+		return
+	}
+
+	s.emitScopeChanged(ctx, program, previous.InScope, snapshot.InScope)
+}
+
+// newScopeSnapshot builds a scope snapshot from the sorted, unique in-scope
+// and out-of-scope targets, hashed so unchanged scopes are not stored twice
+func newScopeSnapshot(programID, scanID uuid.UUID, inScope, outOfScope []string) *database.ProgramScope {
+	inScope = sortedUnique(inScope)
+	outOfScope = sortedUnique(outOfScope)
+
+	hash := sha256.New()
+	hash.Write([]byte("in\n" + strings.Join(inScope, "\n")))
+	hash.Write([]byte("\nout\n" + strings.Join(outOfScope, "\n")))
+
+	return &database.ProgramScope{
+		ProgramID:   programID,
+		ScanID:      &scanID,
+		ContentHash: hex.EncodeToString(hash.Sum(nil)),
+		InScope:     inScope,
+		OutOfScope:  outOfScope,
+	}
+}
+
+// outOfScopeEntry records an out-of-scope asset as its type and target, e.g.
+// "wildcard *.internal.acme.com", so the scope matcher can be rebuilt from a
+// snapshot. A wildcard's target is its original pattern when the platform
+// gave one.
+func outOfScopeEntry(asset *platforms.ScopeAsset) string {
+	target := asset.URL
+	if asset.Type == scope.TypeWildcard && asset.OriginalPattern != "" {
+		target = asset.OriginalPattern
+	}
+	return asset.Type + " " + target
+}
+
+// parseOutOfScopeEntry reads an entry written by outOfScopeEntry back, for
+// the url, wildcard and IP range and address types the scope matcher
+// understands; other types are not read
+func parseOutOfScopeEntry(entry string) (*platforms.ScopeAsset, bool) {
+	assetType, target, ok := strings.Cut(entry, " ")
+	if !ok || target == "" {
+		return nil, false
+	}
+	switch assetType {
+	case scope.TypeURL, scope.TypeWildcard, scope.TypeCIDR, scope.TypeIP:
+		return &platforms.ScopeAsset{URL: target, Type: assetType}, true
+	default:
+		return nil, false
+	}
+}
+
+// sortedUnique returns the sorted values without duplicates
+func sortedUnique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	sort.Strings(unique)
+	return unique
+}
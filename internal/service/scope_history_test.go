@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/scope"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScopeSnapshot(t *testing.T) {
+	programID, scanID := uuid.New(), uuid.New()
+
+	snapshot := newScopeSnapshot(programID, scanID, []string{"b.acme.com", "*.acme.com", "b.acme.com"}, []string{"url internal.acme.com"})
+	assert.Equal(t, pq.StringArray{"*.acme.com", "b.acme.com"}, snapshot.InScope)
+	assert.Equal(t, pq.StringArray{"url internal.acme.com"}, snapshot.OutOfScope)
+	assert.Len(t, snapshot.ContentHash, 64)
+
+	// Order and duplicates do not change the hash, moving a target out of scope does
+	reordered := newScopeSnapshot(programID, scanID, []string{"*.acme.com", "b.acme.com"}, []string{"url internal.acme.com"})
+	assert.Equal(t, snapshot.ContentHash, reordered.ContentHash)
+	moved := newScopeSnapshot(programID, scanID, []string{"*.acme.com"}, []string{"b.acme.com", "url internal.acme.com"})
+	assert.NotEqual(t, snapshot.ContentHash, moved.ContentHash)
+}
+
+func TestOutOfScopeEntry(t *testing.T) {
+	assets := []*platforms.ScopeAsset{
+		{URL: "internal.acme.com", Type: scope.TypeWildcard, OriginalPattern: "*.internal.acme.com"},
+		{URL: "https://admin.acme.com", Type: scope.TypeURL},
+		{URL: "10.0.0.0/8", Type: scope.TypeCIDR},
+		{URL: "https://github.com/acme/app", Type: "source_code"},
+	}
+	var entries []string
+	for _, asset := range assets {
+		entries = append(entries, outOfScopeEntry(asset))
+	}
+	assert.Equal(t, []string{"wildcard *.internal.acme.com", "url https://admin.acme.com", "cidr 10.0.0.0/8", "source_code https://github.com/acme/app"}, entries)
+
+	// The matcher reads the entries back the same, other types are left out
+	var parsed []*platforms.ScopeAsset
+	for _, entry := range append(entries, "internal.acme.com") {
+		if asset, ok := parseOutOfScopeEntry(entry); ok {
+			parsed = append(parsed, asset)
+		}
+	}
+	require.Len(t, parsed, 3)
+	for _, host := range []string{"db.internal.acme.com", "admin.acme.com", "10.1.2.3"} {
+		assert.Equal(t, scope.NewMatcher(assets).Match(host), scope.NewMatcher(parsed).Match(host), host)
+	}
+	assert.False(t, scope.NewMatcher(parsed).Match("github.com"))
+}
+
+func TestRecordScopeSnapshot(t *testing.T) {
+	columns := []string{"id", "program_id", "scan_id", "content_hash", "in_scope", "out_of_scope", "recorded_at"}
+	program := &database.Program{ID: uuid.New(), Name: "Acme", ProgramURL: "https://hackerone.com/acme"}
+	scope := &scopeCollector{targets: []string{"*.acme.com", "api.acme.io"}, excluded: []string{"url internal.acme.com"}}
+	current := newScopeSnapshot(program.ID, uuid.New(), scope.targets, scope.excluded)
+
+	setup := func(t *testing.T) (*MonitorService, sqlmock.Sqlmock, *capturePublisher) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		sqlxDB := sqlx.NewDb(db, "sqlmock")
+		t.Cleanup(func() { sqlxDB.Close() })
+
+		publisher := &capturePublisher{}
+		return &MonitorService{
+			scopeRepo: database.NewScopeRepository(sqlxDB),
+			events:    events.NewEmitter("", publisher),
+		}, mock, publisher
+	}
+	expectCreate := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("INSERT INTO program_scopes").
+			WithArgs(program.ID, sqlmock.AnyArg(), current.ContentHash, current.InScope, current.OutOfScope).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "recorded_at"}).AddRow(uuid.New(), time.Now()))
+	}
+
+	t.Run("first snapshot is a silent baseline", func(t *testing.T) {
+		s, mock, publisher := setup(t)
+		mock.ExpectQuery("SELECT \\* FROM program_scopes").WithArgs(program.ID).
+			WillReturnRows(sqlmock.NewRows(columns))
+		expectCreate(mock)
+
+		s.recordScopeSnapshot(context.Background(), program, uuid.New(), scope)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Empty(t, publisher.events)
+	})
+
+	t.Run("unchanged scope is not stored again", func(t *testing.T) {
+		s, mock, publisher := setup(t)
+		mock.ExpectQuery("SELECT \\* FROM program_scopes").WithArgs(program.ID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(uuid.New(), program.ID, nil, current.ContentHash, current.InScope, current.OutOfScope, time.Now()))
+
+		s.recordScopeSnapshot(context.Background(), program, uuid.New(), scope)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Empty(t, publisher.events)
+	})
+
+	t.Run("changed scope announces added and removed targets", func(t *testing.T) {
+		s, mock, publisher := setup(t)
+		mock.ExpectQuery("SELECT \\* FROM program_scopes").WithArgs(program.ID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(uuid.New(), program.ID, nil, "previous", pq.StringArray{"*.acme.com", "legacy.acme.com"}, pq.StringArray{}, time.Now()))
+		expectCreate(mock)
+
+		s.recordScopeSnapshot(context.Background(), program, uuid.New(), scope)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, publisher.events, 1)
+		assert.Equal(t, events.TypeScopeChanged, publisher.events[0].Type)
+		data := publisher.events[0].Data.(events.ScopeChangedData)
+		assert.Equal(t, []string{"api.acme.io"}, data.Added)
+		assert.Equal(t, []string{"legacy.acme.com"}, data.Removed)
+	})
+}
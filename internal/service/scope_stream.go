@@ -0,0 +1,96 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/scope"
+	"github.com/sirupsen/logrus"
+)
+
+// scopeCollector classifies a program's scope chunk by chunk as it is
+// streamed from the platform. Only the url, wildcard and IP range assets
+// later stages work with are kept; everything else is counted and dropped.
+type scopeCollector struct {
+	program  *database.Program
+	platform string
+	scanID   uuid.UUID
+
+	total   int // scope assets streamed
+	inScope int // in-scope assets of any type
+
+	targets         []string                // in-scope targets of every type
+	excluded        []string                // out-of-scope entries of every type, as recorded in scope snapshots
+	inScopeDomains  []*platforms.ScopeAsset // in-scope url and wildcard assets
+	inScopeNetworks []*platforms.ScopeAsset // in-scope IP range and address assets
+	outOfScope      []*platforms.ScopeAsset // out-of-scope url, wildcard, IP range and address assets
+	primary         []*database.Asset       // primary assets of the in-scope domains
+
+	domains     []string
+	seenDomains map[string]bool
+}
+
+// newScopeCollector creates a collector for a scan of a program
+func newScopeCollector(program *database.Program, platform string, scanID uuid.UUID) *scopeCollector {
+	return &scopeCollector{
+		program:     program,
+		platform:    platform,
+		scanID:      scanID,
+		seenDomains: make(map[string]bool),
+	}
+}
+
+// add classifies a chunk of the scope and returns the primary assets it holds
+func (c *scopeCollector) add(chunk []*platforms.ScopeAsset) []*database.Asset {
+	var primary []*database.Asset
+	for _, scopeAsset := range chunk {
+		c.total++
+		isDomain := scopeAsset.Type == scope.TypeURL || scopeAsset.Type == scope.TypeWildcard
+		isNetwork := scopeAsset.Type == scope.TypeCIDR || scopeAsset.Type == scope.TypeIP
+
+		if !scopeAsset.EligibleForSubmission {
+			c.excluded = append(c.excluded, outOfScopeEntry(scopeAsset))
+			// Only include the types the scope matcher understands for out-of-scope filtering
+			if isDomain || isNetwork {
+				c.outOfScope = append(c.outOfScope, scopeAsset)
+			}
+			continue
+		}
+
+		c.inScope++
+		c.targets = append(c.targets, scopeAsset.URL)
+		if isNetwork {
+			c.inScopeNetworks = append(c.inScopeNetworks, scopeAsset)
+		}
+		// Only save domain and wildcard type assets as primary assets
+		if !isDomain {
+			logrus.Debugf("Skipping non-domain asset type '%s' for program %s: %s", scopeAsset.Type, c.program.Name, scopeAsset.URL)
+			continue
+		}
+		c.inScopeDomains = append(c.inScopeDomains, scopeAsset)
+
+		dbAsset := scopeAsset.ConvertToDatabaseAsset(c.program.ID.String(), c.program.ProgramURL)
+		dbAsset.Source = "primary" // Mark as primary asset
+		dbAsset.FirstSource = c.platform
+		if scopeAsset.Source != "" {
+			dbAsset.FirstSource = scopeAsset.Source
+		}
+		dbAsset.FirstScanID = &c.scanID
+		primary = append(primary, dbAsset)
+	}
+
+	c.primary = append(c.primary, primary...)
+	return primary
+}
+
+// addDomains records the unique domains of a chunk, keeping the order they
+// were first seen in
+func (c *scopeCollector) addDomains(domains []string) {
+	for _, domain := range domains {
+		if c.seenDomains[domain] {
+			continue
+		}
+		c.seenDomains[domain] = true
+		c.domains = append(c.domains, domain)
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeCollector(t *testing.T) {
+	program := &database.Program{ID: uuid.New(), Name: "Acme", ProgramURL: "https://hackerone.com/acme"}
+	scanID := uuid.New()
+	collector := newScopeCollector(program, "hackerone", scanID)
+
+	primary := collector.add([]*platforms.ScopeAsset{
+		{URL: "https://api.acme.example", Domain: "api.acme.example", Type: "url", EligibleForSubmission: true},
+		{URL: "10.0.0.0/8", Domain: "10.0.0.0/8", Type: "cidr", EligibleForSubmission: true},
+		{URL: "https://legacy.acme.example", Domain: "legacy.acme.example", Type: "url"},
+		{URL: "10.1.0.0/16", Domain: "10.1.0.0/16", Type: "cidr"},
+	})
+	require.Len(t, primary, 1)
+	assert.Equal(t, "primary", primary[0].Source)
+	assert.Equal(t, "hackerone", primary[0].FirstSource)
+	assert.Equal(t, &scanID, primary[0].FirstScanID)
+
+	primary = collector.add([]*platforms.ScopeAsset{
+		{URL: "https://acme.example", Domain: "acme.example", Type: "wildcard", EligibleForSubmission: true, Source: "hackerone-csv"},
+		{URL: "com.acme.app", Domain: "com.acme.app", Type: "GOOGLE_PLAY_APP_ID"},
+	})
+	require.Len(t, primary, 1)
+	assert.Equal(t, "hackerone-csv", primary[0].FirstSource)
+
+	assert.Equal(t, 6, collector.total)
+	assert.Equal(t, 3, collector.inScope)
+	assert.Len(t, collector.inScopeDomains, 2)
+	assert.Len(t, collector.inScopeNetworks, 1)
+	assert.Len(t, collector.outOfScope, 2, "only out-of-scope domains and ranges are kept")
+	assert.Len(t, collector.primary, 2)
+
+	collector.addDomains([]string{"acme.example", "api.acme.example"})
+	collector.addDomains([]string{"api.acme.example", "other.example"})
+	assert.Equal(t, []string{"acme.example", "api.acme.example", "other.example"}, collector.domains)
+}
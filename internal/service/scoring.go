@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/scoring"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// rescoreBatchSize is how many assets are scored per query
+const rescoreBatchSize = 500
+
+// RescoreResult summarizes a rescore of assets
+type RescoreResult struct {
+	Model   string // fingerprint of the scoring model
+	Assets  int    // assets scored
+	Changed int    // assets whose score or model changed
+}
+
+// loadScoringModel loads the configured scoring model, falling back to the
+// built-in weights when none is configured or it cannot be loaded
+func loadScoringModel(cfg *config.Config) *scoring.Model {
+	if cfg.Scoring.File == "" {
+		return scoring.Default()
+	}
+
+	model, err := scoring.LoadFile(cfg.Scoring.File)
+	if err != nil {
+		logrus.Errorf("Using the built-in scoring weights: %v", err)
+		return scoring.Default()
+	}
+
+	logrus.Infof("Loaded scoring model %s with %d overrides from %s", model.Fingerprint(), len(model.Overrides()), cfg.Scoring.File)
+	return model
+}
+
+// ScoringModel returns the model assets are scored with
+func (s *MonitorService) ScoringModel() *scoring.Model {
+	return s.scoring
+}
+
+// RescoreAssets recomputes the scores of every asset, or of one program's
+// assets, with the configured scoring model. Only scores that changed, or
+// were computed with another model, are written.
+func (s *MonitorService) RescoreAssets(ctx context.Context, programID *uuid.UUID) (*RescoreResult, error) {
+	if err := s.checkWritable("rescore assets"); err != nil {
+		return nil, err
+	}
+
+	result := &RescoreResult{Model: s.scoring.Fingerprint()}
+	now := time.Now()
+
+	afterID := uuid.Nil
+	for {
+		inputs, err := s.scoreRepo.GetAssetScoreInputs(ctx, programID, afterID, rescoreBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(inputs) == 0 {
+			return result, nil
+		}
+
+		changed := scoreAssets(s.scoring, inputs, now)
+		if err := s.scoreRepo.UpdateAssetScores(ctx, changed, result.Model); err != nil {
+			return result, err
+		}
+
+		result.Assets += len(inputs)
+		result.Changed += len(changed)
+		afterID = inputs[len(inputs)-1].AssetID
+	}
+}
+
+// scoreProgramAssets rescores a program's assets after a scan changed what
+// they are scored on
+func (s *MonitorService) scoreProgramAssets(ctx context.Context, program *database.Program) {
+	if s.scoring == nil || s.scoreRepo == nil {
+		return
+	}
+
+	result, err := s.RescoreAssets(ctx, &program.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to score assets of program %s: %v", program.Name, err)
+		return
+	}
+
+	utils.Log(ctx).Debugf("Scored %d assets of program %s, %d changed", result.Assets, program.Name, result.Changed)
+}
+
+// scoreAssets scores assets, returning the scores that differ from the stored
+// ones or were computed with another model
+func scoreAssets(model *scoring.Model, inputs []*database.AssetScoreInput, now time.Time) []database.AssetScore {
+	var changed []database.AssetScore
+	for _, input := range inputs {
+		score := model.Score(&scoring.Input{
+			ProgramURL:  input.ProgramURL,
+			Liveness:    input.Liveness,
+			StatusCode:  input.StatusCode,
+			Tags:        input.Tags,
+			TLSFindings: input.TLSFindings,
+			RuleMatches: input.RuleMatches,
+			APISchemas:  input.APISchemas,
+			AgeDays:     int(now.Sub(input.CreatedAt).Hours() / 24),
+			Ignored:     input.Ignored,
+		})
+
+		if score != input.Score || input.ScoreModel != model.Fingerprint() {
+			changed = append(changed, database.AssetScore{AssetID: input.AssetID, Score: score})
+		}
+	}
+	return changed
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreAssets(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	model, err := scoring.Parse([]byte(`
+overrides:
+  - name: fintech
+    programs: [acme-bank]
+    weights:
+      tag:admin-panel: 40
+`))
+	require.NoError(t, err)
+
+	monthAgo := now.Add(-30 * 24 * time.Hour)
+	unchanged := &database.AssetScoreInput{AssetID: uuid.New(), ProgramURL: "https://hackerone.com/shop", Liveness: "live",
+		CreatedAt: monthAgo, Score: 10, ScoreModel: model.Fingerprint()}
+	otherModel := &database.AssetScoreInput{AssetID: uuid.New(), ProgramURL: "https://hackerone.com/shop", Liveness: "live",
+		CreatedAt: monthAgo, Score: 10, ScoreModel: "0123456789abcdef"}
+	fintech := &database.AssetScoreInput{AssetID: uuid.New(), ProgramURL: "https://hackerone.com/acme-bank", Liveness: "live",
+		StatusCode: 401, Tags: []string{"admin-panel"}, CreatedAt: now.Add(-time.Hour), ScoreModel: model.Fingerprint()}
+
+	changed := scoreAssets(model, []*database.AssetScoreInput{unchanged, otherModel, fintech}, now)
+
+	assert.Equal(t, []database.AssetScore{
+		{AssetID: otherModel.AssetID, Score: 10},
+		{AssetID: fintech.AssetID, Score: 10 + 8 + 5 + 40},
+	}, changed)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/search"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// newSearchIndexer creates the search mirror, returning nil when it is disabled
+func newSearchIndexer(cfg *config.Config) *search.Indexer {
+	if cfg.Search.URL == "" {
+		return nil
+	}
+
+	indexer := search.NewIndexer(&search.Config{
+		URL:           cfg.Search.URL,
+		Index:         cfg.Search.Index,
+		Username:      cfg.Search.Username,
+		Password:      cfg.Search.Password,
+		Timeout:       cfg.HTTP.Timeout,
+		RetryAttempts: cfg.HTTP.RetryAttempts,
+		RetryDelay:    cfg.HTTP.RetryDelay,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.Timeout)
+	defer cancel()
+	if err := indexer.EnsureIndex(ctx); err != nil {
+		logrus.Warnf("Search mirror disabled: %v", err)
+		return nil
+	}
+
+	logrus.Infof("Search mirror configured for index %s", cfg.Search.Index)
+	return indexer
+}
+
+// mirrorResponses writes saved responses to the search mirror. Failures are
+// logged and never fail the scan, since Postgres is the source of truth.
+func (s *MonitorService) mirrorResponses(ctx context.Context, docs []*search.Document) {
+	if s.searchIndexer == nil || len(docs) == 0 {
+		return
+	}
+
+	if err := s.searchIndexer.IndexDocuments(ctx, docs); err != nil {
+		utils.Log(ctx).Warnf("Failed to mirror responses to search index %s: %v", s.searchIndexer.Index(), err)
+		return
+	}
+
+	utils.Log(ctx).Debugf("Mirrored %d responses to search index %s", len(docs), s.searchIndexer.Index())
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// maxFreshnessViolations is how many violating programs a freshness report lists
+const maxFreshnessViolations = 10
+
+// FreshnessReport is the compliance with the scan freshness SLOs
+type FreshnessReport struct {
+	ProgramScanWithin time.Duration                `json:"program_scan_within"` // 0 when the objective is disabled
+	AssetProbeWithin  time.Duration                `json:"asset_probe_within"`  // 0 when the objective is disabled
+	Programs          int                          `json:"programs"`            // active programs
+	ProgramsViolating int                          `json:"programs_violating"`  // of them, not scanned within ProgramScanWithin
+	Assets            int                          `json:"assets"`              // assets the liveness sweep probes
+	AssetsViolating   int                          `json:"assets_violating"`    // of them, not probed within AssetProbeWithin
+	Violations        []*database.ProgramFreshness `json:"violations"`          // violating programs, least recently scanned first
+}
+
+// ProgramCompliance is the share of active programs scanned within the SLO
+func (r *FreshnessReport) ProgramCompliance() float64 {
+	return compliance(r.Programs, r.ProgramsViolating)
+}
+
+// AssetCompliance is the share of swept assets probed within the SLO
+func (r *FreshnessReport) AssetCompliance() float64 {
+	return compliance(r.Assets, r.AssetsViolating)
+}
+
+// compliance is the share of total that is not violating; nothing to track
+// counts as compliant
+func compliance(total, violating int) float64 {
+	if total == 0 {
+		return 1
+	}
+	return float64(total-violating) / float64(total)
+}
+
+// GetFreshness reports how well programs and assets meet the configured
+// freshness SLOs
+func (s *MonitorService) GetFreshness(ctx context.Context) (*FreshnessReport, error) {
+	if s.freshnessRepo == nil {
+		return nil, fmt.Errorf("freshness tracking is not available")
+	}
+
+	now := time.Now()
+	freshness, err := s.freshnessRepo.GetProgramFreshness(ctx, now.Add(-s.config.SLO.AssetProbeWithin))
+	if err != nil {
+		return nil, err
+	}
+
+	return freshnessReport(s.config.SLO, freshness, now), nil
+}
+
+// freshnessReport checks each program's freshness against the objectives
+func freshnessReport(slo config.SLOConfig, freshness []*database.ProgramFreshness, now time.Time) *FreshnessReport {
+	report := &FreshnessReport{ProgramScanWithin: slo.ProgramScanWithin, AssetProbeWithin: slo.AssetProbeWithin}
+	for _, program := range freshness {
+		report.Programs++
+		report.Assets += program.Assets
+
+		violating := false
+		if slo.ProgramScanWithin > 0 && scanOverdue(program.LastScanAt, slo.ProgramScanWithin, now) {
+			report.ProgramsViolating++
+			violating = true
+		}
+		if slo.AssetProbeWithin > 0 && program.StaleAssets > 0 {
+			report.AssetsViolating += program.StaleAssets
+			violating = true
+		}
+
+		if violating && len(report.Violations) < maxFreshnessViolations {
+			report.Violations = append(report.Violations, program)
+		}
+	}
+	return report
+}
+
+// scanOverdue reports whether a program last scanned at lastScanAt, or never
+// when nil, has not been scanned within the objective
+func scanOverdue(lastScanAt *time.Time, within time.Duration, now time.Time) bool {
+	return lastScanAt == nil || now.Sub(*lastScanAt) > within
+}
+
+// prioritizeOverduePrograms moves the programs of a platform that violate the
+// program scan SLO to the front, so a scan that runs out of time leaves the
+// freshest programs behind rather than the stalest
+func (s *MonitorService) prioritizeOverduePrograms(ctx context.Context, platformName string, programs []*platforms.Program) []*platforms.Program {
+	within := s.config.SLO.ProgramScanWithin
+	if within <= 0 || s.freshnessRepo == nil {
+		return programs
+	}
+
+	lastScans, err := s.freshnessRepo.GetLastScanTimes(ctx, platformName)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get the last scans of %s programs, keeping the platform's order: %v", platformName, err)
+		return programs
+	}
+
+	ordered, overdue := orderByFreshness(programs, lastScans, within, time.Now())
+	if overdue > 0 {
+		utils.Log(ctx).Infof("%d of %d programs on %s were not scanned within %v, scanning them first", overdue, len(programs), platformName, within)
+	}
+	return ordered
+}
+
+// orderByFreshness puts the programs not scanned within the objective first,
+// never-scanned and least recently scanned first, and keeps the order of the
+// others. It returns the ordered programs and how many are overdue.
+func orderByFreshness(programs []*platforms.Program, lastScans map[string]time.Time, within time.Duration, now time.Time) ([]*platforms.Program, int) {
+	var overdue, onTime []*platforms.Program
+	for _, program := range programs {
+		lastScanAt, ok := lastScans[program.ProgramURL]
+		if !ok || now.Sub(lastScanAt) > within {
+			overdue = append(overdue, program)
+		} else {
+			onTime = append(onTime, program)
+		}
+	}
+
+	// Never-scanned programs have no entry and sort as the zero time
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return lastScans[overdue[i].ProgramURL].Before(lastScans[overdue[j].ProgramURL])
+	})
+
+	return append(overdue, onTime...), len(overdue)
+}
+
+// programScanOverdue reports whether a program has not completed a scan
+// within the program scan SLO, so its assets are rediscovered even when its
+// scope did not change
+func (s *MonitorService) programScanOverdue(ctx context.Context, program *database.Program) bool {
+	within := s.config.SLO.ProgramScanWithin
+	if within <= 0 || s.freshnessRepo == nil {
+		return false
+	}
+
+	lastScanAt, err := s.freshnessRepo.GetLastScanTime(ctx, program.ID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to get the last scan of program %s: %v", program.Name, err)
+		return false
+	}
+
+	return scanOverdue(lastScanAt, within, time.Now())
+}
+
+// checkSweepBudget warns when the liveness sweep's request budget is too
+// small to probe every swept asset within the asset probe SLO
+func (s *MonitorService) checkSweepBudget(ctx context.Context, requestsPerHour int) {
+	within := s.config.SLO.AssetProbeWithin
+	if within <= 0 || s.freshnessRepo == nil {
+		return
+	}
+
+	report, err := s.GetFreshness(ctx)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to check the sweep budget against the asset probe SLO: %v", err)
+		return
+	}
+
+	if capacity := int(float64(requestsPerHour) * within.Hours()); report.Assets > capacity {
+		utils.Log(ctx).Warnf("The liveness sweep probes at most %d assets every %v but %d are swept, so the asset probe SLO cannot be met; raise DAEMON_SWEEP_REQUESTS_PER_HOUR",
+			capacity, within, report.Assets)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/platforms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreshnessReport(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	recent, stale := now.Add(-2*time.Hour), now.Add(-30*time.Hour)
+	freshness := []*database.ProgramFreshness{
+		{ProgramName: "new"},
+		{ProgramName: "stale", LastScanAt: &stale, Assets: 10},
+		{ProgramName: "fresh", LastScanAt: &recent, Assets: 30, StaleAssets: 6},
+		{ProgramName: "clean", LastScanAt: &recent, Assets: 20},
+	}
+
+	report := freshnessReport(config.SLOConfig{ProgramScanWithin: 24 * time.Hour, AssetProbeWithin: 168 * time.Hour}, freshness, now)
+	assert.Equal(t, 4, report.Programs)
+	assert.Equal(t, 2, report.ProgramsViolating)
+	assert.Equal(t, 60, report.Assets)
+	assert.Equal(t, 6, report.AssetsViolating)
+	assert.Equal(t, 0.5, report.ProgramCompliance())
+	assert.Equal(t, 0.9, report.AssetCompliance())
+	require.Len(t, report.Violations, 3)
+	assert.Equal(t, "fresh", report.Violations[2].ProgramName)
+
+	// Disabled objectives report nothing as violating
+	report = freshnessReport(config.SLOConfig{}, freshness, now)
+	assert.Zero(t, report.ProgramsViolating)
+	assert.Zero(t, report.AssetsViolating)
+	assert.Empty(t, report.Violations)
+	assert.Equal(t, 1.0, (&FreshnessReport{}).AssetCompliance())
+}
+
+func TestOrderByFreshness(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	programs := []*platforms.Program{
+		{Name: "fresh", ProgramURL: "https://hackerone.com/fresh"},
+		{Name: "day-old", ProgramURL: "https://hackerone.com/day-old"},
+		{Name: "new", ProgramURL: "https://hackerone.com/new"},
+		{Name: "week-old", ProgramURL: "https://hackerone.com/week-old"},
+		{Name: "recent", ProgramURL: "https://hackerone.com/recent"},
+	}
+	lastScans := map[string]time.Time{
+		"https://hackerone.com/fresh":    now.Add(-time.Hour),
+		"https://hackerone.com/day-old":  now.Add(-26 * time.Hour),
+		"https://hackerone.com/week-old": now.Add(-7 * 24 * time.Hour),
+		"https://hackerone.com/recent":   now.Add(-20 * time.Hour),
+	}
+
+	ordered, overdue := orderByFreshness(programs, lastScans, 24*time.Hour, now)
+
+	names := make([]string, len(ordered))
+	for i, program := range ordered {
+		names[i] = program.Name
+	}
+	assert.Equal(t, []string{"new", "week-old", "day-old", "fresh", "recent"}, names)
+	assert.Equal(t, 3, overdue)
+}
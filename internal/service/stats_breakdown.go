@@ -0,0 +1,97 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+)
+
+// statsWindow is how far back stats break down scan outcomes and new assets
+const statsWindow = 30 * 24 * time.Hour
+
+// ScanOutcomes counts the scans of the stats window by how they ended
+type ScanOutcomes struct {
+	Scans       int     `json:"scans"`
+	Completed   int     `json:"completed"`
+	Failed      int     `json:"failed"`
+	TimedOut    int     `json:"timed_out"`
+	SuccessRate float64 `json:"success_rate"` // completed share of the scans that completed, failed or timed out; 0 without any
+}
+
+// DailyAssets is the number of assets found on a day
+type DailyAssets struct {
+	Day    time.Time `json:"day"`
+	Assets int       `json:"assets"`
+}
+
+// ProgramBreakdown is an active program's assets by discovery source, and
+// its scan outcomes and new assets per day over the stats window
+type ProgramBreakdown struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Platform   string         `json:"platform"`
+	ProgramURL string         `json:"program_url"`
+	Assets     int            `json:"assets"`
+	Sources    map[string]int `json:"sources"`
+	Scans      ScanOutcomes   `json:"scans"`
+	NewAssets  []*DailyAssets `json:"new_assets"`
+}
+
+// add counts the outcomes of more scans
+func (o *ScanOutcomes) add(outcomes *database.ProgramScanOutcomes) {
+	o.Scans += outcomes.Scans
+	o.Completed += outcomes.Completed
+	o.Failed += outcomes.Failed
+	o.TimedOut += outcomes.TimedOut
+	if finished := o.Completed + o.Failed + o.TimedOut; finished > 0 {
+		o.SuccessRate = float64(o.Completed) / float64(finished)
+	}
+}
+
+// addDailyAssets adds assets found on a day to days ordered by day
+func addDailyAssets(days []*DailyAssets, day time.Time, assets int) []*DailyAssets {
+	i, found := slices.BinarySearchFunc(days, day, func(d *DailyAssets, day time.Time) int {
+		return d.Day.Compare(day)
+	})
+	if found {
+		days[i].Assets += assets
+		return days
+	}
+	return slices.Insert(days, i, &DailyAssets{Day: day, Assets: assets})
+}
+
+// addBreakdowns fills in the breakdown of every program of the stats and
+// sums it up per platform. Counts of programs that are not in the stats are
+// left out.
+func (stats *ProgramStats) addBreakdowns(sources []*database.ProgramSourceCount, outcomes []*database.ProgramScanOutcomes, daily []*database.ProgramDailyAssets) {
+	programs := make(map[uuid.UUID]*ProgramBreakdown, len(stats.Programs))
+	for _, program := range stats.Programs {
+		programs[program.ID] = program
+	}
+	platforms := make(map[string]*PlatformCount, len(stats.Platforms))
+	for _, platform := range stats.Platforms {
+		platforms[platform.Platform] = platform
+	}
+
+	for _, count := range sources {
+		if program, ok := programs[count.ProgramID]; ok {
+			program.Sources[count.Source] += count.Assets
+			platforms[program.Platform].Sources[count.Source] += count.Assets
+		}
+	}
+	for _, outcome := range outcomes {
+		if program, ok := programs[outcome.ProgramID]; ok {
+			program.Scans.add(outcome)
+			platforms[program.Platform].Scans.add(outcome)
+		}
+	}
+	for _, count := range daily {
+		if program, ok := programs[count.ProgramID]; ok {
+			program.NewAssets = addDailyAssets(program.NewAssets, count.Day, count.Assets)
+			platform := platforms[program.Platform]
+			platform.NewAssets = addDailyAssets(platform.NewAssets, count.Day, count.Assets)
+		}
+	}
+}
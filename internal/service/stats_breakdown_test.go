@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramStats_AddBreakdowns(t *testing.T) {
+	acme, globex, inactive := uuid.New(), uuid.New(), uuid.New()
+	dayOne := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	dayTwo := dayOne.AddDate(0, 0, 1)
+
+	stats := &ProgramStats{
+		Platforms: []*PlatformCount{{Platform: "hackerone", Programs: 2, Assets: 50, Sources: map[string]int{}}},
+		Programs: []*ProgramBreakdown{
+			{ID: acme, Platform: "hackerone", Assets: 42, Sources: map[string]int{}},
+			{ID: globex, Platform: "hackerone", Assets: 8, Sources: map[string]int{}},
+		},
+	}
+	stats.addBreakdowns(
+		[]*database.ProgramSourceCount{
+			{ProgramID: acme, Source: "chaosdb", Assets: 40},
+			{ProgramID: acme, Source: "crtsh", Assets: 2},
+			{ProgramID: globex, Source: "chaosdb", Assets: 8},
+			{ProgramID: inactive, Source: "chaosdb", Assets: 100},
+		},
+		[]*database.ProgramScanOutcomes{
+			{ProgramID: acme, Scans: 5, Completed: 3, Failed: 1, TimedOut: 1},
+			{ProgramID: globex, Scans: 6, Completed: 5},
+		},
+		[]*database.ProgramDailyAssets{
+			{ProgramID: acme, Day: dayOne, Assets: 3},
+			{ProgramID: globex, Day: dayTwo, Assets: 1},
+			{ProgramID: acme, Day: dayTwo, Assets: 2},
+			{ProgramID: inactive, Day: dayOne, Assets: 9},
+		},
+	)
+
+	program := stats.Programs[0]
+	assert.Equal(t, map[string]int{"chaosdb": 40, "crtsh": 2}, program.Sources)
+	assert.Equal(t, ScanOutcomes{Scans: 5, Completed: 3, Failed: 1, TimedOut: 1, SuccessRate: 0.6}, program.Scans)
+	assert.Equal(t, []*DailyAssets{{Day: dayOne, Assets: 3}, {Day: dayTwo, Assets: 2}}, program.NewAssets)
+
+	platform := stats.Platforms[0]
+	assert.Equal(t, map[string]int{"chaosdb": 48, "crtsh": 2}, platform.Sources)
+	assert.Equal(t, 11, platform.Scans.Scans)
+	assert.InDelta(t, 0.8, platform.Scans.SuccessRate, 1e-9)
+	require.Len(t, platform.NewAssets, 2)
+	assert.Equal(t, 3, platform.NewAssets[0].Assets)
+	assert.Equal(t, 3, platform.NewAssets[1].Assets)
+}
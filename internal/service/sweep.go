@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// SweepResult summarizes one batch of an incremental liveness sweep
+type SweepResult struct {
+	Assets          int // assets re-probed
+	Answered        int // assets the probe returned a result for
+	LivenessChanged int // assets whose liveness state changed
+}
+
+// sweepInterval spreads batches of batchSize probes evenly over the hour so
+// the sweep spends at most requestsPerHour probes an hour
+func sweepInterval(requestsPerHour, batchSize int) time.Duration {
+	return time.Duration(int64(time.Hour) * int64(batchSize) / int64(requestsPerHour))
+}
+
+// RunSweep continuously re-probes the assets that were probed longest ago,
+// within the configured hourly request budget, until ctx is cancelled. This
+// keeps liveness data fresh without the load spike of a full scan.
+func (s *MonitorService) RunSweep(ctx context.Context, requestsPerHour, batchSize int) error {
+	if err := s.checkWritable("liveness sweep"); err != nil {
+		return err
+	}
+
+	if s.prober == nil {
+		return fmt.Errorf("liveness sweep requires HTTPX probing (HTTPX_ENABLED)")
+	}
+	if requestsPerHour <= 0 || batchSize <= 0 {
+		return fmt.Errorf("liveness sweep requires a positive request budget and batch size")
+	}
+
+	interval := sweepInterval(requestsPerHour, batchSize)
+	utils.Log(ctx).Infof("Liveness sweep started: %d assets every %v (%d probes/hour)", batchSize, interval.Round(time.Second), requestsPerHour)
+	s.checkSweepBudget(ctx, requestsPerHour)
+
+	// A batch must finish before the next one is due, but always gets at
+	// least one probe timeout
+	batchTimeout := max(interval, s.config.Discovery.HTTPX.Timeout)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		batchCtx, cancel := context.WithTimeout(ctx, batchTimeout)
+		result, err := s.SweepOnce(batchCtx, batchSize)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			utils.Log(ctx).Info("Liveness sweep stopped")
+			return nil
+		case err != nil:
+			utils.Log(ctx).Warnf("Liveness sweep batch failed: %v", err)
+		default:
+			utils.Log(ctx).Infof("Liveness sweep re-probed %d assets (%d answered, %d changed liveness)",
+				result.Assets, result.Answered, result.LivenessChanged)
+		}
+
+		select {
+		case <-ctx.Done():
+			utils.Log(ctx).Info("Liveness sweep stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// SweepOnce re-probes up to limit of the assets that were probed longest ago
+// and stores their refreshed liveness, reachability and responses
+func (s *MonitorService) SweepOnce(ctx context.Context, limit int) (*SweepResult, error) {
+	if err := s.checkWritable("liveness sweep"); err != nil {
+		return nil, err
+	}
+
+	if s.prober == nil {
+		return nil, fmt.Errorf("liveness sweep requires HTTPX probing (HTTPX_ENABLED)")
+	}
+
+	assets, err := s.assetRepo.GetStalestProbedAssets(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SweepResult{Assets: len(assets)}
+	if len(assets) == 0 {
+		return result, nil
+	}
+
+	// Each program's assets are probed with the program's auth profile
+	var programIDs []uuid.UUID
+	urlsByProgram := make(map[uuid.UUID][]string)
+	for _, asset := range assets {
+		if _, ok := urlsByProgram[asset.ProgramID]; !ok {
+			programIDs = append(programIDs, asset.ProgramID)
+		}
+		urlsByProgram[asset.ProgramID] = append(urlsByProgram[asset.ProgramID], asset.URL)
+	}
+
+	var detailedResults []httpx.DetailedProbeResult
+	for _, programID := range programIDs {
+		probeCtx := httpx.WithMethod(s.probeAuthContext(ctx, programID), s.config.Daemon.SweepMethod)
+		programResults, err := s.prober.ProbeDomainsWithDetails(probeCtx, urlsByProgram[programID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to probe sweep batch: %w", err)
+		}
+		detailedResults = append(detailedResults, programResults...)
+	}
+	detailedResults = mergeSchemeVariants(detailedResults)
+
+	resultsByHost := make(map[string]httpx.DetailedProbeResult, len(detailedResults))
+	for _, probeResult := range detailedResults {
+		resultsByHost[database.AssetHostKey(probeResult.URL)] = probeResult
+	}
+
+	// Every asset in the batch counts as probed, answered or not, so assets
+	// that return nothing do not hold the front of the queue
+	probedAt := time.Now()
+	for _, asset := range assets {
+		if probeResult, ok := resultsByHost[asset.HostKey]; ok {
+			result.Answered++
+			if asset.Liveness != probeResult.Liveness {
+				result.LivenessChanged++
+			}
+			applyProbeResult(asset, &probeResult, probedAt)
+		}
+		asset.LastProbedAt = &probedAt
+
+		if err := s.writeThrottle.Wait(ctx, 1); err != nil {
+			return result, err
+		}
+		if err := s.assetRepo.UpdateAssetProbe(ctx, asset); err != nil {
+			utils.Log(ctx).Warnf("Failed to update sweep probe of %s: %v", asset.URL, err)
+		}
+	}
+
+	s.saveDetailedResponses(ctx, assets, detailedResults)
+	return result, nil
+}
+
+// applyProbeResult records a probe's liveness, reachability and error on an asset
+func applyProbeResult(asset *database.Asset, result *httpx.DetailedProbeResult, probedAt time.Time) {
+	asset.IP = result.IPv4
+	asset.IPv6 = result.IPv6
+	asset.IPv4Reachable = result.IPv4Reachable
+	asset.IPv6Reachable = result.IPv6Reachable
+	asset.Liveness = result.Liveness
+	asset.LastProbedAt = &probedAt
+	asset.LastProbeErrorAt = nil
+	if result.Error != "" {
+		asset.LastProbeError = truncateProbeError(result.Error)
+		asset.LastProbeErrorAt = &probedAt
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticProber returns fixed probe results
+type staticProber struct {
+	results []httpx.DetailedProbeResult
+	probed  []string
+	methods []string
+}
+
+func (p *staticProber) ProbeDomainsWithDetails(ctx context.Context, domains []string) ([]httpx.DetailedProbeResult, error) {
+	p.probed = append(p.probed, domains...)
+	p.methods = append(p.methods, httpx.MethodFromContext(ctx))
+	return p.results, nil
+}
+
+func TestSweepInterval(t *testing.T) {
+	assert.Equal(t, 150*time.Second, sweepInterval(600, 25))
+	assert.Equal(t, time.Hour, sweepInterval(10, 10))
+}
+
+func TestSweepOnce(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	prober := &staticProber{results: []httpx.DetailedProbeResult{
+		{URL: "http://a.example.com", Liveness: httpx.LivenessTimedOut, Error: "context deadline exceeded"},
+	}}
+	s := &MonitorService{
+		config:    &config.Config{Daemon: config.DaemonConfig{SweepMethod: httpx.MethodHEAD}},
+		assetRepo: database.NewAssetRepository(sqlxDB),
+		prober:    prober,
+	}
+
+	changedID, silentID := uuid.New(), uuid.New()
+	mock.ExpectQuery("FROM assets a JOIN hosts h ON h.id = a.host_id JOIN programs p").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "host_key", "liveness"}).
+			AddRow(changedID, "https://a.example.com", "a.example.com", "live").
+			AddRow(silentID, "https://b.example.com", "b.example.com", "live"))
+	mock.ExpectExec("UPDATE hosts SET .* UPDATE assets SET updated_at = NOW\\(\\)").
+		WithArgs(changedID, "", "", nil, nil, httpx.LivenessTimedOut, "context deadline exceeded", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE hosts SET").
+		WithArgs(silentID, "", "", nil, nil, "live", "", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := s.SweepOnce(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, &SweepResult{Assets: 2, Answered: 1, LivenessChanged: 1}, result)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, prober.probed)
+	assert.Equal(t, []string{httpx.MethodHEAD}, prober.methods)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
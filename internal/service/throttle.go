@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// createAssets saves assets in batches, waiting on the write throttle before
+// each one so discovery spikes slow the pipeline down instead of flooding
+// Postgres. Each asset records the data terms of the source that found it.
+func (s *MonitorService) createAssets(ctx context.Context, assets []*database.Asset) error {
+	if s.config != nil {
+		for _, asset := range assets {
+			source := asset.FirstSource
+			if source == "" {
+				source = asset.Source
+			}
+			if terms := s.config.Provenance.TermsOf(source); terms != "" {
+				asset.DataTerms = pq.StringArray{terms}
+			}
+		}
+	}
+
+	batchSize := s.writeThrottle.BatchSize()
+	if batchSize <= 0 {
+		batchSize = len(assets)
+	}
+
+	for start := 0; start < len(assets); start += batchSize {
+		end := start + batchSize
+		if end > len(assets) {
+			end = len(assets)
+		}
+		batch := assets[start:end]
+
+		if err := s.writeThrottle.Wait(ctx, len(batch)); err != nil {
+			return err
+		}
+		if err := s.assetRepo.CreateAssets(ctx, batch); err != nil {
+			return err
+		}
+
+		if len(assets) > batchSize {
+			utils.Log(ctx).Debugf("Saved asset batch %d-%d of %d", start+1, end, len(assets))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAssets_Batches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	s := &MonitorService{
+		config:        &config.Config{Provenance: config.ProvenanceConfig{SourceTerms: map[string]string{"chaosdb": "chaos terms"}}},
+		assetRepo:     database.NewAssetRepository(sqlxDB),
+		writeThrottle: database.NewWriteThrottle(2, 0),
+	}
+
+	var assets []*database.Asset
+	for i := 0; i < 5; i++ {
+		assets = append(assets, &database.Asset{URL: fmt.Sprintf("https://a%d.example.com", i), Source: "chaosdb"})
+	}
+
+	// 5 assets in batches of 2 is three transactions
+	for start := 0; start < len(assets); start += 2 {
+		rows := sqlmock.NewRows([]string{"id", "host_key"})
+		for i := start; i < start+2 && i < len(assets); i++ {
+			rows.AddRow(uuid.New(), fmt.Sprintf("a%d.example.com", i))
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO hosts").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery("INSERT INTO assets").WillReturnRows(rows)
+		mock.ExpectCommit()
+	}
+
+	require.NoError(t, s.createAssets(context.Background(), assets))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, pq.StringArray{"chaos terms"}, assets[0].DataTerms)
+}
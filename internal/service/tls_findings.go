@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// recordTLSFindings stores the TLS misconfigurations of a probe result and
+// emits a tls.finding event for each one that was not already open. Results
+// without a TLS handshake (plain http, or TLS checks disabled) leave the
+// asset's findings untouched, so its http variant cannot resolve them.
+func (s *MonitorService) recordTLSFindings(ctx context.Context, asset *database.Asset, result *httpx.DetailedProbeResult) {
+	if s.tlsFindingRepo == nil || result.TLS == nil {
+		return
+	}
+
+	checks := httpx.CheckTLS(result.TLS)
+	findings := make([]*database.TLSFinding, 0, len(checks))
+	for _, check := range checks {
+		findings = append(findings, &database.TLSFinding{
+			CheckName: check.Check,
+			Severity:  check.Severity,
+			Detail:    check.Detail,
+			URL:       result.URL,
+		})
+	}
+
+	opened, err := s.tlsFindingRepo.RecordTLSFindings(ctx, asset.ID, findings)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to record TLS findings for %s: %v", result.URL, err)
+		return
+	}
+
+	for _, finding := range opened {
+		utils.Log(ctx).Infof("TLS finding %s (%s) on %s: %s", finding.CheckName, finding.Severity, result.URL, finding.Detail)
+
+		s.events.Emit(ctx, events.TypeTLSFinding, asset.URL, events.TLSFindingData{
+			Asset:    events.NewAssetData(asset),
+			URL:      finding.URL,
+			Check:    finding.CheckName,
+			Severity: finding.Severity,
+			Detail:   finding.Detail,
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/utils"
+)
+
+// WatchlistResult summarizes one check of the watchlist
+type WatchlistResult struct {
+	Checked    int // watched hostnames checked
+	Resolving  int // hostnames that resolve but do not answer HTTP
+	Responding int // hostnames that answer HTTP
+	CameAlive  int // hostnames that moved to a more alive state
+}
+
+// watchStateRank orders the watchlist states from least to most alive
+var watchStateRank = map[string]int{
+	database.WatchStateDead:       0,
+	database.WatchStateResolving:  1,
+	database.WatchStateResponding: 2,
+}
+
+// CheckWatchlist resolves every watched hostname and probes the ones that
+// resolve, announcing each hostname that starts resolving or responding with
+// a watchlist.alive event. Without a prober (passive mode or HTTPX disabled)
+// hostnames are only resolved.
+func (s *MonitorService) CheckWatchlist(ctx context.Context) (*WatchlistResult, error) {
+	if err := s.checkWritable("check watchlist"); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.watchlistRepo.GetWatchlist(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WatchlistResult{Checked: len(entries)}
+	if len(entries) == 0 {
+		return result, nil
+	}
+
+	// Resolve first, so only hostnames that exist are probed
+	ips := make(map[string]string, len(entries))
+	var resolving []string
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		addrs, err := s.lookupHost(ctx, entry.Hostname)
+		if err != nil || len(addrs) == 0 {
+			utils.Log(ctx).Debugf("Watched hostname %s does not resolve: %v", entry.Hostname, err)
+			continue
+		}
+		ips[entry.Hostname] = addrs[0]
+		resolving = append(resolving, entry.Hostname)
+	}
+
+	probes := make(map[string]httpx.DetailedProbeResult)
+	if s.prober != nil && len(resolving) > 0 {
+		probeResults, err := s.prober.ProbeDomainsWithDetails(ctx, resolving)
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to probe watched hostnames, recording resolution only: %v", err)
+		}
+		for _, probeResult := range mergeSchemeVariants(probeResults) {
+			probes[database.AssetHostKey(probeResult.URL)] = probeResult
+		}
+	}
+
+	checkedAt := time.Now()
+	for _, entry := range entries {
+		previous := entry.State
+		entry.State, entry.IP, entry.StatusCode = database.WatchStateDead, ips[entry.Hostname], 0
+		if entry.IP != "" {
+			entry.State = database.WatchStateResolving
+			result.Resolving++
+		}
+		if probe, ok := probes[database.AssetHostKey(entry.Hostname)]; ok && probe.Exists {
+			entry.State, entry.StatusCode = database.WatchStateResponding, probe.StatusCode
+			result.Resolving--
+			result.Responding++
+		}
+
+		entry.LastCheckedAt = &checkedAt
+		if entry.State != previous {
+			entry.StateChangedAt = &checkedAt
+			utils.Log(ctx).Infof("Watched hostname %s is now %s (was %s)", entry.Hostname, entry.State, previous)
+			if watchStateRank[entry.State] > watchStateRank[previous] {
+				result.CameAlive++
+				s.emitWatchlistAlive(ctx, entry, previous)
+			}
+		}
+
+		if err := s.watchlistRepo.UpdateWatchlistCheck(ctx, entry); err != nil {
+			utils.Log(ctx).Warnf("Failed to record watchlist check of %s: %v", entry.Hostname, err)
+		}
+	}
+
+	return result, nil
+}
+
+// checkWatchlist runs a watchlist check as part of a scan cycle, logging
+// rather than returning failures so they never fail the cycle
+func (s *MonitorService) checkWatchlist(ctx context.Context) {
+	if s.watchlistRepo == nil {
+		return
+	}
+
+	result, err := s.CheckWatchlist(ctx)
+	if err != nil {
+		utils.Log(ctx).Warnf("Watchlist check failed: %v", err)
+		return
+	}
+	if result.Checked > 0 {
+		utils.Log(ctx).Infof("Checked %d watched hostnames: %d responding, %d resolving, %d came alive",
+			result.Checked, result.Responding, result.Resolving, result.CameAlive)
+	}
+}
+
+// RunWatchlist checks the watchlist every interval until ctx is cancelled
+func (s *MonitorService) RunWatchlist(ctx context.Context, interval time.Duration) error {
+	if err := s.checkWritable("watchlist checks"); err != nil {
+		return err
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("watchlist checks require a positive interval")
+	}
+	utils.Log(ctx).Infof("Watchlist checks started: every %v", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.checkWatchlist(ctx)
+
+		select {
+		case <-ctx.Done():
+			utils.Log(ctx).Info("Watchlist checks stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// emitWatchlistAlive announces a watched hostname that became more alive
+func (s *MonitorService) emitWatchlistAlive(ctx context.Context, entry *database.WatchlistEntry, previous string) {
+	data := events.WatchlistAliveData{
+		Hostname:      entry.Hostname,
+		Note:          entry.Note,
+		PreviousState: previous,
+		State:         entry.State,
+		IP:            entry.IP,
+		StatusCode:    entry.StatusCode,
+	}
+	if entry.ProgramID != nil {
+		if program := s.watchlistProgram(ctx, *entry.ProgramID); program != nil {
+			programData := events.NewProgramData(program)
+			data.Program = &programData
+		}
+	}
+
+	s.events.Emit(ctx, events.TypeWatchlistAlive, entry.Hostname, data)
+}
+
+// watchlistProgram loads the program a hostname is watched for, or nil when
+// it cannot be loaded
+func (s *MonitorService) watchlistProgram(ctx context.Context, programID uuid.UUID) *database.Program {
+	if s.programRepo == nil {
+		return nil
+	}
+	program, err := s.programRepo.GetProgramByID(ctx, programID)
+	if err != nil {
+		utils.Log(ctx).Warnf("Failed to load program %s of a watched hostname: %v", programID, err)
+		return nil
+	}
+	return program
+}
+
+// lookupHost resolves a hostname with the service's resolver
+func (s *MonitorService) lookupHost(ctx context.Context, hostname string) ([]string, error) {
+	if s.resolveHost != nil {
+		return s.resolveHost(ctx, hostname)
+	}
+	return net.DefaultResolver.LookupHost(ctx, hostname)
+}
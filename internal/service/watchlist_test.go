@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/httpx"
+	"github.com/monitor-agent/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturePublisher keeps the events it is given
+type capturePublisher struct {
+	events []*events.Event
+}
+
+func (p *capturePublisher) Name() string { return "capture" }
+
+func (p *capturePublisher) Publish(_ context.Context, event *events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *capturePublisher) Close() error { return nil }
+
+func TestCheckWatchlist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	t.Cleanup(func() { sqlxDB.Close() })
+
+	publisher := &capturePublisher{}
+	prober := &staticProber{results: []httpx.DetailedProbeResult{
+		{URL: "https://admin.acme.example", StatusCode: 401, Exists: true},
+	}}
+	s := &MonitorService{
+		watchlistRepo: database.NewWatchlistRepository(sqlxDB),
+		prober:        prober,
+		events:        events.NewEmitter("", publisher),
+		resolveHost: func(_ context.Context, hostname string) ([]string, error) {
+			switch hostname {
+			case "admin.acme.example":
+				return []string{"192.0.2.10"}, nil
+			case "staging.acme.example":
+				return []string{"192.0.2.20"}, nil
+			}
+			return nil, errors.New("no such host")
+		},
+	}
+
+	columns := []string{"id", "hostname", "program_id", "note", "state", "status_code", "ip", "last_checked_at", "state_changed_at", "created_at", "updated_at"}
+	now := time.Now()
+	admin, staging, dead := uuid.New(), uuid.New(), uuid.New()
+	mock.ExpectQuery("SELECT \\* FROM watchlist ORDER BY hostname").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(admin, "admin.acme.example", nil, "expected after launch", database.WatchStateDead, 0, "", nil, nil, now, now).
+			AddRow(dead, "gone.acme.example", nil, "", database.WatchStateDead, 0, "", nil, nil, now, now).
+			AddRow(staging, "staging.acme.example", nil, "", database.WatchStateResponding, 200, "192.0.2.20", nil, now, now, now))
+
+	expectCheck := func(id uuid.UUID, state string, statusCode int, ip string) {
+		mock.ExpectExec("UPDATE watchlist SET").
+			WithArgs(id, state, statusCode, ip, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	expectCheck(admin, database.WatchStateResponding, 401, "192.0.2.10")
+	expectCheck(dead, database.WatchStateDead, 0, "")
+	expectCheck(staging, database.WatchStateResolving, 0, "192.0.2.20")
+
+	result, err := s.CheckWatchlist(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &WatchlistResult{Checked: 3, Resolving: 1, Responding: 1, CameAlive: 1}, result)
+	assert.ElementsMatch(t, []string{"admin.acme.example", "staging.acme.example"}, prober.probed, "only resolving hostnames are probed")
+
+	// Only the hostname that came alive is announced; staging going quiet is not
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, events.TypeWatchlistAlive, publisher.events[0].Type)
+	assert.Equal(t, events.WatchlistAliveData{
+		Hostname:      "admin.acme.example",
+		Note:          "expected after launch",
+		PreviousState: database.WatchStateDead,
+		State:         database.WatchStateResponding,
+		IP:            "192.0.2.10",
+		StatusCode:    401,
+	}, publisher.events[0].Data)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/monitor-agent/internal/config"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/discovery/whois"
+	"github.com/monitor-agent/internal/events"
+	"github.com/monitor-agent/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// NewlyRegisteredTag is attached to primary assets whose apex domain was registered recently
+const NewlyRegisteredTag = "newly-registered"
+
+// newWhoisClient creates the registration data client, returning nil when enrichment is disabled
+func newWhoisClient(cfg *config.Config) *whois.Client {
+	if !cfg.Whois.Enabled {
+		return nil
+	}
+
+	logrus.Infof("Domain registration enrichment configured with %s", cfg.Whois.ServerURL)
+	return whois.NewClient(&whois.ClientConfig{
+		ServerURL:     cfg.Whois.ServerURL,
+		Timeout:       cfg.HTTP.Timeout,
+		RetryAttempts: cfg.HTTP.RetryAttempts,
+		RetryDelay:    cfg.HTTP.RetryDelay,
+	})
+}
+
+// enrichDomainRegistrations records the registration data of a program's apex
+// domains and flags those registered within the configured window, since
+// brand-new infrastructure is both higher risk and higher opportunity.
+// Failures are logged and never fail the scan.
+func (s *MonitorService) enrichDomainRegistrations(ctx context.Context, program *database.Program, domains []string, primaryAssets []*database.Asset) {
+	if s.whoisClient == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, domain := range domains {
+		if ctx.Err() != nil {
+			return
+		}
+
+		apex, err := whois.ApexDomain(domain)
+		if err != nil || seen[apex] || s.urlProcessor.IsIPAddress(domain) {
+			continue
+		}
+		seen[apex] = true
+
+		registration, fresh, err := s.domainRegistration(ctx, apex)
+		if err != nil {
+			utils.Log(ctx).Warnf("Failed to get registration data for %s: %v", apex, err)
+			continue
+		}
+
+		if !newlyRegistered(registration, time.Now(), s.config.Whois.NewDomainDays) {
+			continue
+		}
+
+		ageDays := int(time.Since(*registration.RegisteredAt).Hours() / 24)
+		utils.Log(ctx).Warnf("In-scope domain %s of program %s was registered %d days ago (registrar: %s)",
+			apex, program.Name, ageDays, registration.Registrar)
+		s.tagApexAssets(ctx, apex, primaryAssets)
+
+		// Announce a domain once, when it is first found to be new
+		if fresh {
+			s.events.Emit(ctx, events.TypeDomainNew, apex, events.DomainRegistrationData{
+				Program:      events.NewProgramData(program),
+				Domain:       apex,
+				Registrar:    registration.Registrar,
+				RegisteredAt: *registration.RegisteredAt,
+				AgeDays:      ageDays,
+			})
+		}
+	}
+}
+
+// domainRegistration returns the stored registration data of an apex domain,
+// looking it up again once it is older than the refresh interval. fresh
+// reports whether the domain was looked up for the first time.
+func (s *MonitorService) domainRegistration(ctx context.Context, apex string) (registration *database.DomainRegistration, fresh bool, err error) {
+	stored, err := s.registrations.GetRegistration(ctx, apex)
+	if err != nil {
+		return nil, false, err
+	}
+	if stored != nil && time.Since(stored.CheckedAt) < s.config.Whois.RefreshInterval {
+		return stored, false, nil
+	}
+
+	registration = &database.DomainRegistration{Domain: apex}
+	looked, err := s.whoisClient.Lookup(ctx, apex)
+	switch {
+	case errors.Is(err, whois.ErrNotFound):
+		registration.LookupError = err.Error()
+	case err != nil:
+		// Keep the previous data and try again next scan
+		return stored, false, err
+	default:
+		registration.Registrar = looked.Registrar
+		registration.RegisteredAt = looked.RegisteredAt
+		registration.ExpiresAt = looked.ExpiresAt
+	}
+
+	if err := s.registrations.SaveRegistration(ctx, registration); err != nil {
+		return nil, false, err
+	}
+	return registration, stored == nil, nil
+}
+
+// tagApexAssets tags the primary assets under an apex domain as newly registered
+func (s *MonitorService) tagApexAssets(ctx context.Context, apex string, assets []*database.Asset) {
+	for _, asset := range assets {
+		host, err := s.urlProcessor.ExtractDomain(asset.URL)
+		if err != nil {
+			continue
+		}
+		if assetApex, err := whois.ApexDomain(host); err != nil || assetApex != apex {
+			continue
+		}
+
+		if err := s.tagRepo.AddAssetTags(ctx, asset.ID, []string{NewlyRegisteredTag}, "whois"); err != nil {
+			utils.Log(ctx).Warnf("Failed to tag %s as newly registered: %v", asset.URL, err)
+		}
+	}
+}
+
+// newlyRegistered reports whether a domain was registered within the last days days
+func newlyRegistered(registration *database.DomainRegistration, now time.Time, days int) bool {
+	if registration == nil || registration.RegisteredAt == nil || days <= 0 {
+		return false
+	}
+	return now.Sub(*registration.RegisteredAt) < time.Duration(days)*24*time.Hour
+}
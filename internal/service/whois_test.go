@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewlyRegistered(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	registeredAt := func(age time.Duration) *database.DomainRegistration {
+		at := now.Add(-age)
+		return &database.DomainRegistration{Domain: "example.com", RegisteredAt: &at}
+	}
+
+	assert.True(t, newlyRegistered(registeredAt(10*24*time.Hour), now, 30))
+	assert.False(t, newlyRegistered(registeredAt(30*24*time.Hour), now, 30))
+	assert.False(t, newlyRegistered(registeredAt(10*24*time.Hour), now, 0))
+	assert.False(t, newlyRegistered(&database.DomainRegistration{Domain: "example.com"}, now, 30))
+	assert.False(t, newlyRegistered(nil, now, 30))
+}
@@ -0,0 +1,322 @@
+package slackbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+// assetListLimit is the number of assets listed in one reply
+const assetListLimit = 25
+
+// recentScanLimit is the number of recent scans listed by the stats command
+const recentScanLimit = 5
+
+// Response types of a slash command reply
+const (
+	ResponseInChannel = "in_channel"
+	ResponseEphemeral = "ephemeral"
+)
+
+// Service is the part of the monitor service the bot bridges to
+type Service interface {
+	FindAssets(ctx context.Context, host string, limit int) ([]*database.Asset, error)
+	FindProgram(ctx context.Context, handle string) (*database.Program, error)
+	RescanProgram(ctx context.Context, program *database.Program) (*database.Scan, error)
+	GetProgramStats(ctx context.Context) (*service.ProgramStats, error)
+	Writable() error
+}
+
+// SlashCommand is the payload of a slash command invocation
+type SlashCommand struct {
+	Command     string `json:"command"`
+	Text        string `json:"text"`
+	UserID      string `json:"user_id"`
+	UserName    string `json:"user_name"`
+	ChannelID   string `json:"channel_id"`
+	ResponseURL string `json:"response_url"`
+}
+
+// Response is a reply to a slash command
+type Response struct {
+	ResponseType string `json:"response_type,omitempty"`
+	Text         string `json:"text"`
+}
+
+// Handler runs slash commands against the service
+type Handler struct {
+	service         Service
+	command         string
+	allowedUsers    map[string]bool
+	allowedChannels map[string]bool
+	httpClient      *resty.Client // posts follow-ups to response URLs
+
+	mu       sync.Mutex
+	rescans  map[string]bool // programs being rescanned, by ID
+	inFlight sync.WaitGroup
+}
+
+// NewHandler creates a slash command handler
+func NewHandler(config *Config, service Service, httpClient *resty.Client) *Handler {
+	return &Handler{
+		service:         service,
+		command:         config.Command,
+		allowedUsers:    toSet(config.AllowedUsers),
+		allowedChannels: toSet(config.AllowedChannels),
+		httpClient:      httpClient,
+		rescans:         make(map[string]bool),
+	}
+}
+
+// Wait blocks until running rescans have posted their results
+func (h *Handler) Wait() {
+	h.inFlight.Wait()
+}
+
+// Handle runs a slash command and returns the immediate reply. Commands that
+// take longer than Slack waits for reply right away and post their result to
+// the command's response URL when done.
+func (h *Handler) Handle(ctx context.Context, cmd *SlashCommand) *Response {
+	if cmd.Command != h.command {
+		return ephemeral("Unknown command %s", cmd.Command)
+	}
+	if !h.allowed(cmd) {
+		logrus.Warnf("Rejected Slack command from user %s in channel %s: %s %s", cmd.UserID, cmd.ChannelID, cmd.Command, cmd.Text)
+		return ephemeral("You are not allowed to use %s here", h.command)
+	}
+
+	logrus.Infof("Slack command from user %s in channel %s: %s %s", cmd.UserID, cmd.ChannelID, cmd.Command, cmd.Text)
+
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 {
+		return h.help()
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "assets":
+		if len(args) != 2 {
+			return ephemeral("Usage: %s assets <domain>", h.command)
+		}
+		return h.assets(ctx, args[1])
+	case "rescan":
+		if len(args) != 2 {
+			return ephemeral("Usage: %s rescan <program>", h.command)
+		}
+		return h.rescan(ctx, cmd, args[1])
+	case "stats":
+		return h.stats(ctx)
+	case "help":
+		return h.help()
+	default:
+		return ephemeral("Unknown command %q. Try `%s help`", args[0], h.command)
+	}
+}
+
+// allowed reports whether the user may run commands in the channel
+func (h *Handler) allowed(cmd *SlashCommand) bool {
+	if len(h.allowedUsers) > 0 && !h.allowedUsers[cmd.UserID] {
+		return false
+	}
+	if len(h.allowedChannels) > 0 && !h.allowedChannels[cmd.ChannelID] {
+		return false
+	}
+	return true
+}
+
+// assets lists the assets of a domain
+func (h *Handler) assets(ctx context.Context, domain string) *Response {
+	assets, err := h.service.FindAssets(ctx, domain, assetListLimit+1)
+	if errors.Is(err, service.ErrInvalidDomain) {
+		return ephemeral("%s is not a valid domain", domain)
+	}
+	if err != nil {
+		logrus.Errorf("Slack assets command failed for %s: %v", domain, err)
+		return ephemeral("Failed to look up assets for %s", domain)
+	}
+
+	if len(assets) == 0 {
+		return inChannel("No assets found for %s", domain)
+	}
+
+	heading := fmt.Sprintf("*%d assets for %s*", len(assets), domain)
+	if len(assets) > assetListLimit {
+		assets = assets[:assetListLimit]
+		heading = fmt.Sprintf("*First %d assets for %s*", assetListLimit, domain)
+	}
+
+	var lines []string
+	for _, asset := range assets {
+		liveness := asset.Liveness
+		if liveness == "" {
+			liveness = "unprobed"
+		}
+		lines = append(lines, fmt.Sprintf("%-50s %-12s %s", asset.URL, liveness, asset.Status))
+	}
+
+	return inChannel("%s\n```\n%s\n```", heading, strings.Join(lines, "\n"))
+}
+
+// rescan starts a rescan of a program in the background and posts its result
+// to the command's response URL
+func (h *Handler) rescan(ctx context.Context, cmd *SlashCommand, handle string) *Response {
+	if h.service.Writable() != nil {
+		return ephemeral("Rescans are disabled: the agent runs in read-only mode")
+	}
+
+	program, err := h.service.FindProgram(ctx, handle)
+	if errors.Is(err, service.ErrProgramNotFound) {
+		return ephemeral("No active program found for %s", handle)
+	}
+	if errors.Is(err, service.ErrAmbiguousProgram) {
+		return ephemeral("%v", err)
+	}
+	if err != nil {
+		logrus.Errorf("Slack rescan command failed for %s: %v", handle, err)
+		return ephemeral("Failed to look up program %s", handle)
+	}
+
+	h.mu.Lock()
+	if h.rescans[program.ID.String()] {
+		h.mu.Unlock()
+		return ephemeral("A rescan of %s is already running", program.Name)
+	}
+	h.rescans[program.ID.String()] = true
+	h.mu.Unlock()
+
+	h.inFlight.Add(1)
+	go func() {
+		defer h.inFlight.Done()
+		defer func() {
+			h.mu.Lock()
+			delete(h.rescans, program.ID.String())
+			h.mu.Unlock()
+		}()
+
+		var result *Response
+		scan, err := h.service.RescanProgram(ctx, program)
+		if err != nil {
+			logrus.Errorf("Slack rescan of %s failed: %v", program.Name, err)
+			result = inChannel("Rescan of %s failed: %v", program.Name, err)
+		} else {
+			result = inChannel("Rescan of %s %s: %d assets seen, %d assets in total%s",
+				program.Name, scan.Status, scan.AssetsSeen, scan.AssetsFound, scanError(scan))
+		}
+		h.followUp(context.WithoutCancel(ctx), cmd.ResponseURL, result)
+	}()
+
+	return inChannel("<@%s> started a rescan of %s (%s); the result will be posted here", cmd.UserID, program.Name, program.Platform)
+}
+
+// stats summarizes programs, assets and recent scans
+func (h *Handler) stats(ctx context.Context) *Response {
+	stats, err := h.service.GetProgramStats(ctx)
+	if err != nil {
+		logrus.Errorf("Slack stats command failed: %v", err)
+		return ephemeral("Failed to get statistics")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Monitor Agent statistics*\n")
+	fmt.Fprintf(&b, "Programs: %d (%d active), assets: %d\n", stats.TotalPrograms, stats.ActivePrograms, stats.TotalAssets)
+
+	if freshness := stats.Freshness; freshness != nil {
+		var objectives []string
+		if freshness.ProgramScanWithin > 0 {
+			objectives = append(objectives, fmt.Sprintf("programs scanned within %v %.1f%%", freshness.ProgramScanWithin, freshness.ProgramCompliance()*100))
+		}
+		if freshness.AssetProbeWithin > 0 {
+			objectives = append(objectives, fmt.Sprintf("assets probed within %v %.1f%%", freshness.AssetProbeWithin, freshness.AssetCompliance()*100))
+		}
+		fmt.Fprintf(&b, "Freshness SLOs: %s\n", strings.Join(objectives, ", "))
+	}
+
+	if len(stats.Liveness) > 0 {
+		counts := make([]string, len(stats.Liveness))
+		for i, count := range stats.Liveness {
+			counts[i] = fmt.Sprintf("%s %d", count.Liveness, count.Assets)
+		}
+		fmt.Fprintf(&b, "Liveness: %s\n", strings.Join(counts, ", "))
+	}
+
+	if len(stats.TLSFindings) > 0 {
+		counts := make([]string, len(stats.TLSFindings))
+		for i, count := range stats.TLSFindings {
+			counts[i] = fmt.Sprintf("%s (%s) %d", count.CheckName, count.Severity, count.Assets)
+		}
+		fmt.Fprintf(&b, "Open TLS findings: %s\n", strings.Join(counts, ", "))
+	}
+
+	if len(stats.RecentScans) > 0 {
+		scans := stats.RecentScans
+		if len(scans) > recentScanLimit {
+			scans = scans[:recentScanLimit]
+		}
+		fmt.Fprintf(&b, "Recent scans:\n")
+		for _, scan := range scans {
+			fmt.Fprintf(&b, "• %s %s, %d assets%s\n", scan.StartedAt.Format("2006-01-02 15:04"), scan.Status, scan.AssetsFound, scanError(scan))
+		}
+	}
+
+	return inChannel("%s", strings.TrimSuffix(b.String(), "\n"))
+}
+
+// help lists the available commands
+func (h *Handler) help() *Response {
+	return ephemeral("Usage:\n"+
+		"`%[1]s assets <domain>`: list the assets of a domain and its subdomains\n"+
+		"`%[1]s rescan <program>`: rescan a program by name or handle, e.g. `slack` or `hackerone/slack`\n"+
+		"`%[1]s stats`: show program, asset and scan statistics", h.command)
+}
+
+// followUp posts a delayed reply to a command's response URL
+func (h *Handler) followUp(ctx context.Context, responseURL string, response *Response) {
+	if responseURL == "" {
+		return
+	}
+
+	resp, err := h.httpClient.R().
+		SetContext(ctx).
+		SetBody(response).
+		Post(responseURL)
+	if err != nil {
+		logrus.Warnf("Failed to post Slack follow-up: %v", err)
+		return
+	}
+	if resp.IsError() {
+		logrus.Warnf("Slack follow-up returned status %d", resp.StatusCode())
+	}
+}
+
+// scanError formats a scan's error for appending to a summary
+func scanError(scan *database.Scan) string {
+	if scan.Error == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", scan.Error)
+}
+
+// inChannel creates a reply visible to everyone in the channel
+func inChannel(format string, args ...any) *Response {
+	return &Response{ResponseType: ResponseInChannel, Text: fmt.Sprintf(format, args...)}
+}
+
+// ephemeral creates a reply only visible to the user who ran the command
+func ephemeral(format string, args ...any) *Response {
+	return &Response{ResponseType: ResponseEphemeral, Text: fmt.Sprintf(format, args...)}
+}
+
+// toSet converts a list of IDs into a set
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
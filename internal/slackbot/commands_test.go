@@ -0,0 +1,189 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService is an in-memory Service
+type fakeService struct {
+	assets   []*database.Asset
+	program  *database.Program
+	scan     *database.Scan
+	rescan   chan struct{} // closed to let a rescan finish
+	readOnly bool
+}
+
+func (f *fakeService) FindAssets(ctx context.Context, host string, limit int) ([]*database.Asset, error) {
+	if host == "not_a_domain" {
+		return nil, service.ErrInvalidDomain
+	}
+	if len(f.assets) > limit {
+		return f.assets[:limit], nil
+	}
+	return f.assets, nil
+}
+
+func (f *fakeService) FindProgram(ctx context.Context, handle string) (*database.Program, error) {
+	if f.program == nil || handle != "slack" {
+		return nil, service.ErrProgramNotFound
+	}
+	return f.program, nil
+}
+
+func (f *fakeService) RescanProgram(ctx context.Context, program *database.Program) (*database.Scan, error) {
+	if f.rescan != nil {
+		<-f.rescan
+	}
+	if f.scan == nil {
+		return nil, errors.New("platform unavailable")
+	}
+	return f.scan, nil
+}
+
+func (f *fakeService) Writable() error {
+	if f.readOnly {
+		return service.ErrReadOnly
+	}
+	return nil
+}
+
+func (f *fakeService) GetProgramStats(ctx context.Context) (*service.ProgramStats, error) {
+	return &service.ProgramStats{
+		TotalPrograms:  3,
+		ActivePrograms: 2,
+		TotalAssets:    40,
+		Liveness:       []*database.LivenessCount{{Liveness: "live", Assets: 30}},
+		RecentScans:    []*database.Scan{{Status: "completed", AssetsFound: 12, StartedAt: time.Now()}},
+		Freshness:      &service.FreshnessReport{ProgramScanWithin: 24 * time.Hour, Programs: 2, ProgramsViolating: 1},
+	}, nil
+}
+
+func newTestHandler(svc Service, config *Config) *Handler {
+	if config.Command == "" {
+		config.Command = "/monitor"
+	}
+	return NewHandler(config, svc, resty.New())
+}
+
+func TestHandler_Assets(t *testing.T) {
+	svc := &fakeService{assets: []*database.Asset{
+		{URL: "https://example.com", Liveness: "live", Status: "active"},
+		{URL: "https://api.example.com", Status: "active"},
+	}}
+	h := newTestHandler(svc, &Config{})
+
+	response := h.Handle(context.Background(), &SlashCommand{Command: "/monitor", Text: "assets example.com"})
+	assert.Equal(t, ResponseInChannel, response.ResponseType)
+	assert.Contains(t, response.Text, "2 assets for example.com")
+	assert.Contains(t, response.Text, "https://api.example.com")
+	assert.Contains(t, response.Text, "unprobed")
+
+	response = h.Handle(context.Background(), &SlashCommand{Command: "/monitor", Text: "assets"})
+	assert.Equal(t, ResponseEphemeral, response.ResponseType)
+	assert.Contains(t, response.Text, "Usage")
+}
+
+func TestHandler_AssetsTruncated(t *testing.T) {
+	svc := &fakeService{}
+	for i := 0; i < assetListLimit+5; i++ {
+		svc.assets = append(svc.assets, &database.Asset{URL: "https://example.com", Liveness: "live"})
+	}
+	h := newTestHandler(svc, &Config{})
+
+	response := h.Handle(context.Background(), &SlashCommand{Command: "/monitor", Text: "assets example.com"})
+	assert.Contains(t, response.Text, "First 25 assets")
+}
+
+func TestHandler_Rejections(t *testing.T) {
+	h := newTestHandler(&fakeService{}, &Config{AllowedUsers: []string{"U1"}, AllowedChannels: []string{"C1"}})
+
+	tests := []struct {
+		name string
+		cmd  SlashCommand
+		want string
+	}{
+		{"other command", SlashCommand{Command: "/other", UserID: "U1", ChannelID: "C1"}, "Unknown command /other"},
+		{"other user", SlashCommand{Command: "/monitor", UserID: "U2", ChannelID: "C1", Text: "stats"}, "not allowed"},
+		{"other channel", SlashCommand{Command: "/monitor", UserID: "U1", ChannelID: "C2", Text: "stats"}, "not allowed"},
+		{"unknown subcommand", SlashCommand{Command: "/monitor", UserID: "U1", ChannelID: "C1", Text: "purge"}, "Unknown command \"purge\""},
+		{"too many arguments", SlashCommand{Command: "/monitor", UserID: "U1", ChannelID: "C1", Text: "assets a.com b.com"}, "Usage"},
+		{"invalid domain", SlashCommand{Command: "/monitor", UserID: "U1", ChannelID: "C1", Text: "assets not_a_domain"}, "not a valid domain"},
+		{"unknown program", SlashCommand{Command: "/monitor", UserID: "U1", ChannelID: "C1", Text: "rescan nope"}, "No active program"},
+		{"help", SlashCommand{Command: "/monitor", UserID: "U1", ChannelID: "C1"}, "/monitor assets <domain>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := h.Handle(context.Background(), &tt.cmd)
+			assert.Equal(t, ResponseEphemeral, response.ResponseType)
+			assert.Contains(t, response.Text, tt.want)
+		})
+	}
+}
+
+func TestHandler_Stats(t *testing.T) {
+	h := newTestHandler(&fakeService{}, &Config{})
+
+	response := h.Handle(context.Background(), &SlashCommand{Command: "/monitor", Text: "stats"})
+	assert.Equal(t, ResponseInChannel, response.ResponseType)
+	assert.Contains(t, response.Text, "Programs: 3 (2 active), assets: 40")
+	assert.Contains(t, response.Text, "live 30")
+	assert.Contains(t, response.Text, "programs scanned within 24h0m0s 50.0%")
+	assert.Contains(t, response.Text, "completed, 12 assets")
+}
+
+func TestHandler_Rescan(t *testing.T) {
+	followUps := make(chan Response, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response Response
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&response))
+		followUps <- response
+	}))
+	defer server.Close()
+
+	svc := &fakeService{
+		program: &database.Program{ID: uuid.New(), Name: "Slack", Platform: "hackerone"},
+		scan:    &database.Scan{Status: "completed", AssetsSeen: 8, AssetsFound: 10},
+		rescan:  make(chan struct{}),
+	}
+	h := newTestHandler(svc, &Config{})
+	cmd := &SlashCommand{Command: "/monitor", Text: "rescan slack", UserID: "U1", ResponseURL: server.URL}
+
+	response := h.Handle(context.Background(), cmd)
+	assert.Equal(t, ResponseInChannel, response.ResponseType)
+	assert.Contains(t, response.Text, "<@U1> started a rescan of Slack")
+
+	// A second rescan of the same program is refused while the first runs
+	response = h.Handle(context.Background(), cmd)
+	assert.Contains(t, response.Text, "already running")
+
+	close(svc.rescan)
+	h.Wait()
+
+	select {
+	case followUp := <-followUps:
+		assert.Equal(t, ResponseInChannel, followUp.ResponseType)
+		assert.Equal(t, "Rescan of Slack completed: 8 assets seen, 10 assets in total", followUp.Text)
+	default:
+		require.Fail(t, "no follow-up posted")
+	}
+
+	// Read-only mode refuses rescans
+	svc.readOnly = true
+	response = h.Handle(context.Background(), cmd)
+	assert.Equal(t, ResponseEphemeral, response.ResponseType)
+	assert.Contains(t, response.Text, "read-only mode")
+}
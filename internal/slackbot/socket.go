@@ -0,0 +1,184 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/monitor-agent/internal/version"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// DefaultAPIURL is the Slack Web API the socket mode connection is opened through
+const DefaultAPIURL = "https://slack.com/api"
+
+// reconnectDelay is the wait before reconnecting after a connection failed
+const reconnectDelay = 5 * time.Second
+
+// Config holds the Slack bot configuration
+type Config struct {
+	AppToken        string // app-level token (xapp-) with connections:write
+	Command         string // slash command the bot answers, e.g. /monitor
+	AllowedUsers    []string
+	AllowedChannels []string
+	APIURL          string // defaults to DefaultAPIURL
+	Timeout         time.Duration
+}
+
+// Bot answers slash commands received over a Slack socket mode connection.
+// Socket mode needs no public endpoint: the bot dials out to Slack and
+// commands arrive as envelopes on the websocket.
+type Bot struct {
+	httpClient *resty.Client
+	apiURL     string
+	appToken   string
+	handler    *Handler
+}
+
+// connectionsOpenResponse is the response of apps.connections.open
+type connectionsOpenResponse struct {
+	OK    bool   `json:"ok"`
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// envelope is a message received over the socket mode connection
+type envelope struct {
+	EnvelopeID string          `json:"envelope_id"`
+	Type       string          `json:"type"`
+	Reason     string          `json:"reason"` // set on disconnect messages
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// acknowledgement confirms an envelope; slash commands carry their reply in it
+type acknowledgement struct {
+	EnvelopeID string    `json:"envelope_id"`
+	Payload    *Response `json:"payload,omitempty"`
+}
+
+// NewBot creates a Slack bot that bridges slash commands to the service
+func NewBot(config *Config, service Service) *Bot {
+	client := resty.New()
+	client.SetTimeout(config.Timeout)
+	client.SetHeader("User-Agent", version.UserAgent())
+
+	apiURL := config.APIURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	return &Bot{
+		httpClient: client,
+		apiURL:     strings.TrimRight(apiURL, "/"),
+		appToken:   config.AppToken,
+		handler:    NewHandler(config, service, client),
+	}
+}
+
+// Run keeps a socket mode connection open and answers commands until the
+// context is cancelled, reconnecting whenever Slack closes the connection
+func (b *Bot) Run(ctx context.Context) error {
+	for {
+		err := b.serve(ctx)
+		if ctx.Err() != nil {
+			b.handler.Wait()
+			return nil
+		}
+		if err != nil {
+			logrus.Warnf("Slack connection lost, reconnecting in %v: %v", reconnectDelay, err)
+			select {
+			case <-time.After(reconnectDelay):
+			case <-ctx.Done():
+				b.handler.Wait()
+				return nil
+			}
+		}
+	}
+}
+
+// serve opens one socket mode connection and answers commands until Slack
+// asks the bot to reconnect or the connection fails
+func (b *Bot) serve(ctx context.Context) error {
+	wsURL, err := b.openConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	wsConfig, err := websocket.NewConfig(wsURL, "https://slack.com")
+	if err != nil {
+		return fmt.Errorf("invalid socket mode URL: %w", err)
+	}
+
+	conn, err := wsConfig.DialContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Slack: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock the read below once the bot is stopped
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for {
+		var msg envelope
+		if err := websocket.JSON.Receive(conn, &msg); err != nil {
+			return fmt.Errorf("failed to read from Slack: %w", err)
+		}
+
+		switch msg.Type {
+		case "hello":
+			logrus.Info("Connected to Slack, waiting for commands")
+		case "disconnect":
+			logrus.Debugf("Slack asked to reconnect: %s", msg.Reason)
+			return nil
+		case "slash_commands":
+			var command SlashCommand
+			if err := json.Unmarshal(msg.Payload, &command); err != nil {
+				logrus.Warnf("Failed to parse Slack command: %v", err)
+				command = SlashCommand{}
+			}
+
+			// Slack expects the acknowledgement within three seconds, so slow
+			// commands answer right away and follow up on the response URL
+			response := b.handler.Handle(ctx, &command)
+			if err := websocket.JSON.Send(conn, acknowledgement{EnvelopeID: msg.EnvelopeID, Payload: response}); err != nil {
+				return fmt.Errorf("failed to acknowledge Slack command: %w", err)
+			}
+		default:
+			if msg.EnvelopeID != "" {
+				if err := websocket.JSON.Send(conn, acknowledgement{EnvelopeID: msg.EnvelopeID}); err != nil {
+					return fmt.Errorf("failed to acknowledge Slack %s message: %w", msg.Type, err)
+				}
+			}
+		}
+	}
+}
+
+// openConnection requests a socket mode websocket URL
+func (b *Bot) openConnection(ctx context.Context) (string, error) {
+	resp, err := b.httpClient.R().
+		SetContext(ctx).
+		SetAuthToken(b.appToken).
+		Post(b.apiURL + "/apps.connections.open")
+	if err != nil {
+		return "", fmt.Errorf("failed to open Slack connection: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("Slack API returned status %d", resp.StatusCode())
+	}
+	var result connectionsOpenResponse
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return "", fmt.Errorf("failed to unmarshal Slack response: %w", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("Slack API error: %s", result.Error)
+	}
+
+	return result.URL, nil
+}
@@ -0,0 +1,83 @@
+package slackbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestBot_AnswersSlashCommands(t *testing.T) {
+	acks := make(chan acknowledgement, 1)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/api/apps.connections.open", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xapp-test", r.Header.Get("Authorization"))
+		fmt.Fprintf(w, `{"ok":true,"url":"ws%s/socket"}`, strings.TrimPrefix(server.URL, "http"))
+	})
+	mux.Handle("/socket", websocket.Handler(func(conn *websocket.Conn) {
+		websocket.Message.Send(conn, `{"type":"hello","num_connections":1}`)
+		websocket.Message.Send(conn, `{"envelope_id":"env-1","type":"slash_commands","accepts_response_payload":true,
+			"payload":{"command":"/monitor","text":"stats","user_id":"U1","channel_id":"C1"}}`)
+
+		var ack acknowledgement
+		if assert.NoError(t, websocket.JSON.Receive(conn, &ack)) {
+			acks <- ack
+		}
+
+		// Block until the bot hangs up
+		var discard string
+		websocket.Message.Receive(conn, &discard)
+	}))
+
+	bot := NewBot(&Config{
+		AppToken: "xapp-test",
+		Command:  "/monitor",
+		APIURL:   server.URL + "/api",
+		Timeout:  5 * time.Second,
+	}, &fakeService{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bot.Run(ctx) }()
+
+	select {
+	case ack := <-acks:
+		assert.Equal(t, "env-1", ack.EnvelopeID)
+		require.NotNil(t, ack.Payload)
+		assert.Equal(t, ResponseInChannel, ack.Payload.ResponseType)
+		assert.Contains(t, ack.Payload.Text, "Programs: 3")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "command was not acknowledged")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "bot did not stop")
+	}
+}
+
+func TestBot_OpenConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":false,"error":"invalid_auth"}`)
+	}))
+	defer server.Close()
+
+	bot := NewBot(&Config{AppToken: "xapp-bad", Command: "/monitor", APIURL: server.URL, Timeout: 5 * time.Second}, &fakeService{})
+
+	_, err := bot.openConnection(context.Background())
+	assert.ErrorContains(t, err, "invalid_auth")
+}
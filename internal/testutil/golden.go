@@ -0,0 +1,52 @@
+// Package testutil holds helpers shared by tests
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UpdateGoldenEnv is the environment variable that rewrites golden files with
+// the current output instead of comparing against them, e.g.
+// UPDATE_GOLDEN=1 go test ./internal/platforms/...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// ReadTestdata returns the contents of a file under the package's testdata
+// directory
+func ReadTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+// AssertGolden compares got, encoded as indented JSON, with the golden file
+// testdata/<name>.golden.json. With UPDATE_GOLDEN set the golden file is
+// written instead, so parser changes show up as a reviewable diff.
+func AssertGolden(t *testing.T, name string, got any) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	require.NoError(t, encoder.Encode(got))
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing, run the test with %s=1 to create it", UpdateGoldenEnv)
+	assert.Equal(t, string(want), buf.String(), "output differs from %s, run the test with %s=1 to update it", path, UpdateGoldenEnv)
+}
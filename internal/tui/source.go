@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/monitor-agent/internal/database"
+	"github.com/monitor-agent/internal/objectstore"
+	"github.com/monitor-agent/internal/service"
+)
+
+// ProgramRow is a program with its asset counts
+type ProgramRow struct {
+	Program   *database.Program
+	Assets    int
+	NewAssets int // first found by the program's latest completed scan
+}
+
+// AssetRow is an asset of a program
+type AssetRow struct {
+	Asset *database.Asset
+	New   bool // first found by the program's latest completed scan
+}
+
+// Response is an asset's latest stored response
+type Response struct {
+	*database.AssetResponse
+	BodyErr error // why an offloaded body could not be downloaded
+}
+
+// Source provides what the TUI browses
+type Source interface {
+	Programs(ctx context.Context) ([]*ProgramRow, error)
+	Assets(ctx context.Context, program *database.Program) ([]*AssetRow, error)
+	LatestResponse(ctx context.Context, asset *database.Asset) (*Response, error)
+}
+
+// RepositorySource reads programs, assets and responses through the database
+// repositories
+type RepositorySource struct {
+	programRepo *database.ProgramRepository
+	assetRepo   *database.AssetRepository
+	scanRepo    *database.ScanRepository
+	bodyStore   objectstore.Store // nil when bodies are stored in the database
+}
+
+// NewRepositorySource creates a source reading from the database, and from
+// the body store for offloaded response bodies
+func NewRepositorySource(db *sqlx.DB, bodyStore objectstore.Store) *RepositorySource {
+	return &RepositorySource{
+		programRepo: database.NewProgramRepository(db),
+		assetRepo:   database.NewAssetRepository(db),
+		scanRepo:    database.NewScanRepository(db),
+		bodyStore:   bodyStore,
+	}
+}
+
+// Programs returns the active programs, those with the most assets first
+func (s *RepositorySource) Programs(ctx context.Context) ([]*ProgramRow, error) {
+	programs, err := s.programRepo.GetProgramsWithAssetCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	newAssets, err := s.assetRepo.GetProgramNewAssetCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*ProgramRow, 0, len(programs))
+	for _, program := range programs {
+		rows = append(rows, &ProgramRow{
+			Program:   program.Program,
+			Assets:    program.AssetCount,
+			NewAssets: newAssets[program.Program.ID],
+		})
+	}
+	return rows, nil
+}
+
+// Assets returns a program's assets, the ones its latest completed scan found
+// first and then the newest
+func (s *RepositorySource) Assets(ctx context.Context, program *database.Program) ([]*AssetRow, error) {
+	latest, err := s.scanRepo.GetPreviousCompletedScan(ctx, program.ID, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := s.assetRepo.GetAssetsByProgramID(ctx, program.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*AssetRow, 0, len(assets))
+	for _, asset := range assets {
+		isNew := latest != nil && asset.FirstScanID != nil && *asset.FirstScanID == latest.ID
+		rows = append(rows, &AssetRow{Asset: asset, New: isNew})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].New && !rows[j].New
+	})
+	return rows, nil
+}
+
+// LatestResponse returns an asset's latest stored response, or nil when it
+// has none. A body that cannot be downloaded from the body store is reported
+// on the response rather than failing it.
+func (s *RepositorySource) LatestResponse(ctx context.Context, asset *database.Asset) (*Response, error) {
+	response, err := s.assetRepo.GetLatestAssetResponseByAssetID(ctx, asset.ID)
+	if err != nil || response == nil {
+		return nil, err
+	}
+
+	return &Response{
+		AssetResponse: response,
+		BodyErr:       service.LoadResponseBody(ctx, s.bodyStore, response),
+	}, nil
+}
@@ -0,0 +1,495 @@
+// Package tui is a terminal UI for browsing the monitored programs, their
+// assets and the latest response of each asset, highlighting what the latest
+// completed scan of each program found.
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+)
+
+// view is the screen being browsed
+type view int
+
+const (
+	programsView view = iota
+	assetsView
+	responseView
+)
+
+// defaultHeight is the terminal height assumed until the first resize
+const defaultHeight = 24
+
+// chromeLines are the lines the title, column header, status and help take
+const chromeLines = 5
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	headerStyle   = lipgloss.NewStyle().Faint(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	newStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	helpStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+// Messages carrying what a load command read. Assets and responses record
+// what they were loaded for, so results arriving after the user moved on are
+// dropped.
+type (
+	programsMsg struct{ programs []*ProgramRow }
+	assetsMsg   struct {
+		programID uuid.UUID
+		assets    []*AssetRow
+	}
+	responseMsg struct {
+		assetID  uuid.UUID
+		response *Response
+	}
+	errMsg struct{ err error }
+)
+
+// Model is the bubbletea model of the TUI
+type Model struct {
+	ctx    context.Context
+	source Source
+
+	view     view
+	programs []*ProgramRow
+	assets   []*AssetRow
+	response *Response
+	program  *ProgramRow // program whose assets are browsed
+	asset    *AssetRow   // asset whose response is shown
+
+	programCursor int
+	assetCursor   int
+	scroll        int  // first response line shown
+	newOnly       bool // only programs with new assets and new assets are listed
+	loading       bool
+	err           error
+	width, height int
+}
+
+// New creates the TUI model, reading from source; newOnly starts with only
+// new items listed
+func New(ctx context.Context, source Source, newOnly bool) Model {
+	return Model{ctx: ctx, source: source, newOnly: newOnly, loading: true}
+}
+
+// Run shows the TUI until the user quits or ctx is cancelled
+func Run(ctx context.Context, source Source, newOnly bool) error {
+	_, err := tea.NewProgram(New(ctx, source, newOnly), tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	return err
+}
+
+// Init loads the programs
+func (m Model) Init() tea.Cmd {
+	return m.loadPrograms()
+}
+
+func (m Model) loadPrograms() tea.Cmd {
+	return func() tea.Msg {
+		programs, err := m.source.Programs(m.ctx)
+		if err != nil {
+			return errMsg{err}
+		}
+		return programsMsg{programs}
+	}
+}
+
+func (m Model) loadAssets(program *database.Program) tea.Cmd {
+	return func() tea.Msg {
+		assets, err := m.source.Assets(m.ctx, program)
+		if err != nil {
+			return errMsg{err}
+		}
+		return assetsMsg{programID: program.ID, assets: assets}
+	}
+}
+
+func (m Model) loadResponse(asset *database.Asset) tea.Cmd {
+	return func() tea.Msg {
+		response, err := m.source.LatestResponse(m.ctx, asset)
+		if err != nil {
+			return errMsg{err}
+		}
+		return responseMsg{assetID: asset.ID, response: response}
+	}
+}
+
+// Update handles loaded data, resizes and key presses
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case programsMsg:
+		m.programs, m.loading, m.err = msg.programs, false, nil
+		m.programCursor = clamp(m.programCursor, len(m.visiblePrograms()))
+	case assetsMsg:
+		if m.program != nil && m.program.Program.ID == msg.programID {
+			m.assets, m.loading, m.err = msg.assets, false, nil
+			m.assetCursor = clamp(m.assetCursor, len(m.visibleAssets()))
+		}
+	case responseMsg:
+		if m.asset != nil && m.asset.Asset.ID == msg.assetID {
+			m.response, m.loading, m.err = msg.response, false, nil
+		}
+	case errMsg:
+		m.loading, m.err = false, msg.err
+	case tea.KeyMsg:
+		return m.handleKey(msg.String())
+	}
+	return m, nil
+}
+
+// handleKey moves through the lists, opens and closes views and toggles
+// the new-only filter
+func (m Model) handleKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.pageSize())
+	case "pgdown", " ":
+		m.move(m.pageSize())
+	case "home", "g":
+		m.move(-1 << 30)
+	case "end", "G":
+		m.move(1 << 30)
+	case "enter", "right", "l":
+		return m.open()
+	case "esc", "backspace", "left", "h":
+		m.back()
+	case "n":
+		m.newOnly = !m.newOnly
+		m.programCursor, m.assetCursor = 0, 0
+	case "r":
+		return m.reload()
+	}
+	return m, nil
+}
+
+// move moves the cursor of a list, or scrolls the response, by delta
+func (m *Model) move(delta int) {
+	switch m.view {
+	case programsView:
+		m.programCursor = clamp(m.programCursor+delta, len(m.visiblePrograms()))
+	case assetsView:
+		m.assetCursor = clamp(m.assetCursor+delta, len(m.visibleAssets()))
+	case responseView:
+		m.scroll = clamp(m.scroll+delta, len(m.responseLines()))
+	}
+}
+
+// open drills into the selected program or asset
+func (m Model) open() (tea.Model, tea.Cmd) {
+	switch m.view {
+	case programsView:
+		programs := m.visiblePrograms()
+		if len(programs) == 0 {
+			return m, nil
+		}
+		m.program = programs[m.programCursor]
+		m.view, m.assets, m.assetCursor, m.loading, m.err = assetsView, nil, 0, true, nil
+		return m, m.loadAssets(m.program.Program)
+	case assetsView:
+		assets := m.visibleAssets()
+		if len(assets) == 0 {
+			return m, nil
+		}
+		m.asset = assets[m.assetCursor]
+		m.view, m.response, m.scroll, m.loading, m.err = responseView, nil, 0, true, nil
+		return m, m.loadResponse(m.asset.Asset)
+	}
+	return m, nil
+}
+
+// back returns to the view above
+func (m *Model) back() {
+	switch m.view {
+	case assetsView:
+		m.view, m.program, m.assets = programsView, nil, nil
+	case responseView:
+		m.view, m.asset, m.response = assetsView, nil, nil
+	}
+	m.loading, m.err = false, nil
+}
+
+// reload reads the current view again
+func (m Model) reload() (tea.Model, tea.Cmd) {
+	m.loading, m.err = true, nil
+	switch m.view {
+	case assetsView:
+		return m, m.loadAssets(m.program.Program)
+	case responseView:
+		return m, m.loadResponse(m.asset.Asset)
+	}
+	return m, m.loadPrograms()
+}
+
+// visiblePrograms returns the listed programs
+func (m Model) visiblePrograms() []*ProgramRow {
+	if !m.newOnly {
+		return m.programs
+	}
+	var programs []*ProgramRow
+	for _, program := range m.programs {
+		if program.NewAssets > 0 {
+			programs = append(programs, program)
+		}
+	}
+	return programs
+}
+
+// visibleAssets returns the listed assets
+func (m Model) visibleAssets() []*AssetRow {
+	if !m.newOnly {
+		return m.assets
+	}
+	var assets []*AssetRow
+	for _, asset := range m.assets {
+		if asset.New {
+			assets = append(assets, asset)
+		}
+	}
+	return assets
+}
+
+// pageSize is the number of list rows or response lines that fit on screen
+func (m Model) pageSize() int {
+	height := m.height
+	if height <= 0 {
+		height = defaultHeight
+	}
+	return max(height-chromeLines, 1)
+}
+
+// View renders the current view
+func (m Model) View() string {
+	var b strings.Builder
+	switch m.view {
+	case programsView:
+		m.viewPrograms(&b)
+	case assetsView:
+		m.viewAssets(&b)
+	case responseView:
+		m.viewResponse(&b)
+	}
+
+	switch {
+	case m.err != nil:
+		b.WriteString(errorStyle.Render("Error: "+sanitize(m.err.Error())) + "\n")
+	case m.loading:
+		b.WriteString("Loading...\n")
+	default:
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render(m.help()))
+	return b.String()
+}
+
+func (m Model) viewPrograms(b *strings.Builder) {
+	programs := m.visiblePrograms()
+	withNew := 0
+	for _, program := range m.programs {
+		if program.NewAssets > 0 {
+			withNew++
+		}
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Programs (%d, %d with new assets)", len(m.programs), withNew)) + "\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-10s  %-40s  %8s  %6s", "PLATFORM", "PROGRAM", "ASSETS", "NEW")) + "\n")
+
+	start, end := window(m.programCursor, len(programs), m.pageSize())
+	for i := start; i < end; i++ {
+		program := programs[i]
+		newAssets := ""
+		if program.NewAssets > 0 {
+			newAssets = fmt.Sprintf("+%d", program.NewAssets)
+		}
+		line := fmt.Sprintf("%-10s  %-40s  %8d  %6s", program.Program.Platform, truncate(sanitize(program.Program.Name), 40), program.Assets, newAssets)
+		m.writeRow(b, line, i == m.programCursor, program.NewAssets > 0)
+	}
+	if len(programs) == 0 && !m.loading {
+		b.WriteString("  No programs\n")
+	}
+}
+
+func (m Model) viewAssets(b *strings.Builder) {
+	assets := m.visibleAssets()
+	newAssets := 0
+	for _, asset := range m.assets {
+		if asset.New {
+			newAssets++
+		}
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s (%s): %d assets, %d new since the last scan",
+		sanitize(m.program.Program.Name), m.program.Program.Platform, len(m.assets), newAssets)) + "\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-3s  %-50s  %-10s  %-10s  %-10s  %s", "", "URL", "LIVENESS", "STATUS", "SOURCE", "FOUND")) + "\n")
+
+	start, end := window(m.assetCursor, len(assets), m.pageSize())
+	for i := start; i < end; i++ {
+		asset := assets[i]
+		marker := ""
+		if asset.New {
+			marker = "NEW"
+		}
+		line := fmt.Sprintf("%-3s  %-50s  %-10s  %-10s  %-10s  %s", marker, truncate(sanitize(asset.Asset.URL), 50),
+			orDash(asset.Asset.Liveness), asset.Asset.Status, orDash(asset.Asset.FirstSource), asset.Asset.CreatedAt.Format("2006-01-02"))
+		m.writeRow(b, line, i == m.assetCursor, asset.New)
+	}
+	if len(assets) == 0 && !m.loading {
+		b.WriteString("  No assets\n")
+	}
+}
+
+func (m Model) viewResponse(b *strings.Builder) {
+	b.WriteString(titleStyle.Render(sanitize(m.asset.Asset.URL)) + "\n")
+	if m.response == nil {
+		if !m.loading {
+			b.WriteString(headerStyle.Render("No stored responses") + "\n")
+		}
+		return
+	}
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%s %d in %dms, captured %s", m.response.Method, m.response.StatusCode,
+		m.response.ResponseTime, m.response.CreatedAt.Format("2006-01-02 15:04:05"))) + "\n")
+
+	lines := m.responseLines()
+	start := clamp(m.scroll, len(lines))
+	end := min(start+m.pageSize(), len(lines))
+	for _, line := range lines[start:end] {
+		b.WriteString(truncate(line, m.width) + "\n")
+	}
+}
+
+// responseLines returns the redirects, headers and body of the response
+func (m Model) responseLines() []string {
+	if m.response == nil {
+		return nil
+	}
+	response := m.response
+
+	var lines []string
+	if response.RedirectStatus != "" {
+		lines = append(lines, fmt.Sprintf("Redirects: %d hops, %s, from status %d to %s",
+			response.RedirectHops, response.RedirectStatus, response.InitialStatusCode, sanitize(orDash(response.FinalURL))), "")
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(response.Headers), &headers); err == nil && len(headers) > 0 {
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			lines = append(lines, sanitize(name+": "+headers[name]))
+		}
+		lines = append(lines, "")
+	}
+
+	switch {
+	case response.Method == http.MethodHead:
+		lines = append(lines, "Body not captured by HEAD probes")
+	case response.BodyErr != nil:
+		lines = append(lines, "Body unavailable: "+sanitize(response.BodyErr.Error()))
+	default:
+		for _, line := range strings.Split(response.Body, "\n") {
+			lines = append(lines, sanitize(strings.TrimSuffix(line, "\r")))
+		}
+	}
+	return lines
+}
+
+// writeRow writes a list row, reversed when selected and highlighted when new
+func (m Model) writeRow(b *strings.Builder, line string, selected, isNew bool) {
+	prefix := "  "
+	if selected {
+		prefix = "> "
+	}
+	line = truncate(prefix+line, m.width)
+	switch {
+	case selected:
+		line = selectedStyle.Render(line)
+	case isNew:
+		line = newStyle.Render(line)
+	}
+	b.WriteString(line + "\n")
+}
+
+// help lists the keys of the current view
+func (m Model) help() string {
+	filter := "n: new only"
+	if m.newOnly {
+		filter = "n: show all"
+	}
+	switch m.view {
+	case assetsView:
+		return "↑/↓: move  enter: latest response  esc: programs  " + filter + "  r: reload  q: quit"
+	case responseView:
+		return "↑/↓: scroll  esc: assets  r: reload  q: quit"
+	}
+	return "↑/↓: move  enter: assets  " + filter + "  r: reload  q: quit"
+}
+
+// window returns the range of rows shown so that the cursor stays visible
+func window(cursor, total, rows int) (int, int) {
+	start := 0
+	if cursor >= rows {
+		start = cursor - rows + 1
+	}
+	return start, min(start+rows, total)
+}
+
+// clamp limits an index to a list of n items
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	return max(i, 0)
+}
+
+// truncate cuts a line to width runes; 0 leaves it whole
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// sanitize replaces control characters, so response bodies and headers
+// cannot send escape sequences to the terminal, and expands tabs
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return '�'
+		}
+		return r
+	}, s)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/monitor-agent/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves fixed programs, assets and responses
+type fakeSource struct {
+	programs  []*ProgramRow
+	assets    map[uuid.UUID][]*AssetRow
+	responses map[uuid.UUID]*Response
+	err       error
+}
+
+func (f *fakeSource) Programs(context.Context) ([]*ProgramRow, error) {
+	return f.programs, f.err
+}
+
+func (f *fakeSource) Assets(_ context.Context, program *database.Program) ([]*AssetRow, error) {
+	return f.assets[program.ID], f.err
+}
+
+func (f *fakeSource) LatestResponse(_ context.Context, asset *database.Asset) (*Response, error) {
+	return f.responses[asset.ID], f.err
+}
+
+func newFakeSource() *fakeSource {
+	acme := &database.Program{ID: uuid.New(), Name: "Acme", Platform: "hackerone"}
+	globex := &database.Program{ID: uuid.New(), Name: "Globex", Platform: "bugcrowd"}
+	api := &database.Asset{ID: uuid.New(), URL: "https://api.acme.com", Status: "active", Liveness: "live", CreatedAt: time.Now()}
+	www := &database.Asset{ID: uuid.New(), URL: "https://www.acme.com", Status: "active", CreatedAt: time.Now()}
+
+	return &fakeSource{
+		programs: []*ProgramRow{
+			{Program: acme, Assets: 2, NewAssets: 1},
+			{Program: globex, Assets: 7},
+		},
+		assets: map[uuid.UUID][]*AssetRow{
+			acme.ID: {{Asset: api, New: true}, {Asset: www}},
+		},
+		responses: map[uuid.UUID]*Response{
+			api.ID: {AssetResponse: &database.AssetResponse{
+				Method:     "GET",
+				StatusCode: 200,
+				Headers:    `{"Server":"nginx"}`,
+				Body:       "{\"ok\":true}\r\n\x1b[31mred\x1b[0m",
+			}},
+		},
+	}
+}
+
+// send applies a message and runs the commands it returns until none are left
+func send(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+
+	updated, cmd := m.Update(msg)
+	m = updated.(Model)
+	for cmd != nil {
+		next := cmd()
+		if _, quit := next.(tea.QuitMsg); quit {
+			return m
+		}
+		updated, cmd = m.Update(next)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func key(k string) tea.Msg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestModel_Browse(t *testing.T) {
+	source := newFakeSource()
+	m := New(context.Background(), source, false)
+	m = send(t, m, m.Init()())
+
+	view := m.View()
+	assert.Contains(t, view, "Programs (2, 1 with new assets)")
+	assert.Contains(t, view, "Acme")
+	assert.Contains(t, view, "+1")
+	assert.Contains(t, view, "Globex")
+
+	// Drill into Acme's assets; the new asset is marked
+	m = send(t, m, key("enter"))
+	require.Equal(t, assetsView, m.view)
+	view = m.View()
+	assert.Contains(t, view, "Acme (hackerone): 2 assets, 1 new since the last scan")
+	assert.Contains(t, view, "NEW  https://api.acme.com")
+
+	// And into the new asset's latest response, whose body cannot send escape
+	// sequences to the terminal
+	m = send(t, m, key("enter"))
+	require.Equal(t, responseView, m.view)
+	view = m.View()
+	assert.Contains(t, view, "GET 200")
+	assert.Contains(t, view, "Server: nginx")
+	assert.Contains(t, view, `{"ok":true}`)
+	assert.Contains(t, view, "�[31mred�[0m")
+	assert.NotContains(t, view, "\x1b[31m")
+
+	// Back up to the programs
+	m = send(t, m, key("esc"))
+	assert.Equal(t, assetsView, m.view)
+	m = send(t, m, key("esc"))
+	assert.Equal(t, programsView, m.view)
+}
+
+func TestModel_NewOnly(t *testing.T) {
+	source := newFakeSource()
+	m := New(context.Background(), source, true)
+	m = send(t, m, m.Init()())
+
+	assert.Len(t, m.visiblePrograms(), 1)
+	assert.NotContains(t, m.View(), "Globex")
+
+	m = send(t, m, key("enter"))
+	assert.Len(t, m.visibleAssets(), 1)
+	assert.NotContains(t, m.View(), "www.acme.com")
+
+	m = send(t, m, key("n"))
+	assert.Contains(t, m.View(), "www.acme.com")
+}
+
+func TestModel_NoResponse(t *testing.T) {
+	source := newFakeSource()
+	m := New(context.Background(), source, false)
+	m = send(t, m, m.Init()())
+
+	m = send(t, m, key("enter"))
+	m = send(t, m, key("down"))
+	m = send(t, m, key("enter"))
+	assert.Contains(t, m.View(), "https://www.acme.com")
+	assert.Contains(t, m.View(), "No stored responses")
+}
+
+func TestModel_StaleAndFailedLoads(t *testing.T) {
+	source := newFakeSource()
+	m := New(context.Background(), source, false)
+	m = send(t, m, m.Init()())
+
+	// Assets of a program the user already left are dropped
+	m = send(t, m, assetsMsg{programID: source.programs[0].Program.ID, assets: source.assets[source.programs[0].Program.ID]})
+	assert.Nil(t, m.assets)
+
+	source.err = errors.New("connection refused")
+	m = send(t, m, key("r"))
+	assert.Contains(t, m.View(), "Error: connection refused")
+	assert.Len(t, m.programs, 2, "the listed programs are kept")
+}
+
+func TestWindow(t *testing.T) {
+	start, end := window(0, 100, 10)
+	assert.Equal(t, []int{0, 10}, []int{start, end})
+
+	start, end = window(15, 100, 10)
+	assert.Equal(t, []int{6, 16}, []int{start, end})
+
+	start, end = window(2, 3, 10)
+	assert.Equal(t, []int{0, 3}, []int{start, end})
+}
+
+func TestSanitizeAndTruncate(t *testing.T) {
+	assert.Equal(t, "a b�c", sanitize("a\tb\x07c"))
+	assert.Equal(t, "abcd…", truncate("abcdefgh", 5))
+	assert.Equal(t, "abc", truncate("abc", 5))
+	assert.Equal(t, strings.Repeat("x", 8), truncate(strings.Repeat("x", 8), 0))
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CredentialError reports that a platform rejected the credential used for a
+// request, because it is invalid or has run out of quota. Callers holding
+// several credentials can rotate to another one.
+type CredentialError struct {
+	Platform   string
+	StatusCode int
+	RetryAfter time.Duration // from the Retry-After header, 0 if not given
+	Reason     string
+}
+
+// Error implements the error interface
+func (e *CredentialError) Error() string {
+	msg := fmt.Sprintf("%s rejected the credential (status %d): %s", e.Platform, e.StatusCode, e.Reason)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %v", e.RetryAfter)
+	}
+	return msg
+}
+
+// QuotaExceeded reports whether the credential is only temporarily unusable
+func (e *CredentialError) QuotaExceeded() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// AsCredentialError returns the CredentialError wrapped in err, if any
+func AsCredentialError(err error) (*CredentialError, bool) {
+	var cerr *CredentialError
+	if errors.As(err, &cerr) {
+		return cerr, true
+	}
+	return nil, false
+}
+
+// DetectCredentialFailure inspects a platform API response for a rejected
+// credential: 401 and 403 for invalid or revoked credentials, and 429 for an
+// exhausted quota. It returns nil for other responses.
+func DetectCredentialFailure(platform string, statusCode int, header http.Header) *CredentialError {
+	var reason string
+	switch statusCode {
+	case http.StatusUnauthorized:
+		reason = "unauthorized"
+	case http.StatusForbidden:
+		reason = "forbidden"
+	case http.StatusTooManyRequests:
+		reason = "quota exceeded"
+	default:
+		return nil
+	}
+
+	return &CredentialError{
+		Platform:   platform,
+		StatusCode: statusCode,
+		RetryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now()),
+		Reason:     reason,
+	}
+}
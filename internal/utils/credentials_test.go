@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCredentialFailure(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "120")
+
+	cerr := DetectCredentialFailure("hackerone", http.StatusTooManyRequests, header)
+	require.NotNil(t, cerr)
+	assert.True(t, cerr.QuotaExceeded())
+	assert.Equal(t, 2*time.Minute, cerr.RetryAfter)
+
+	cerr = DetectCredentialFailure("bugcrowd", http.StatusUnauthorized, http.Header{})
+	require.NotNil(t, cerr)
+	assert.False(t, cerr.QuotaExceeded())
+	assert.Equal(t, "unauthorized", cerr.Reason)
+
+	assert.Nil(t, DetectCredentialFailure("hackerone", http.StatusOK, http.Header{}))
+	assert.Nil(t, DetectCredentialFailure("hackerone", http.StatusNotFound, http.Header{}))
+}
+
+func TestAsCredentialError(t *testing.T) {
+	wrapped := fmt.Errorf("failed to get programs page 2: %w", &CredentialError{Platform: "hackerone", StatusCode: 401})
+
+	cerr, ok := AsCredentialError(wrapped)
+	require.True(t, ok)
+	assert.Equal(t, 401, cerr.StatusCode)
+
+	_, ok = AsCredentialError(fmt.Errorf("plain error"))
+	assert.False(t, ok)
+}
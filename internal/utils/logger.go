@@ -27,16 +27,34 @@ func NewLogger() *Logger {
 	logger.SetOutput(os.Stdout)
 
 	// Set JSON formatter for structured logging
-	logger.SetFormatter(&logrus.JSONFormatter{
+	logger.SetFormatter(newJSONFormatter())
+
+	return &Logger{logger: logger}
+}
+
+// newJSONFormatter formats log lines as JSON objects with timestamp, level
+// and message keys
+func newJSONFormatter() *logrus.JSONFormatter {
+	return &logrus.JSONFormatter{
 		TimestampFormat: time.RFC3339,
 		FieldMap: logrus.FieldMap{
 			logrus.FieldKeyTime:  "timestamp",
 			logrus.FieldKeyLevel: "level",
 			logrus.FieldKeyMsg:   "message",
 		},
-	})
+	}
+}
 
-	return &Logger{logger: logger}
+// SetLogFormat sets the format of the standard logger: text, the logrus
+// default, or json, one object per line carrying the fields of the line. An
+// empty format is text.
+func SetLogFormat(format string) {
+	switch format {
+	case "json":
+		logrus.SetFormatter(newJSONFormatter())
+	case "", "text":
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}
 }
 
 // WithCorrelationID creates a new log entry with correlation ID
@@ -83,8 +101,38 @@ type ContextKey string
 const (
 	CorrelationIDKey ContextKey = "correlation_id"
 	RequestIDKey     ContextKey = "request_id"
+	LogFieldsKey     ContextKey = "log_fields"
 )
 
+// WithLogFields adds fields, e.g. the scan, program or domain being worked
+// on, to the lines logged with Log for the returned context
+func WithLogFields(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := make(logrus.Fields, len(fields))
+	if existing, ok := ctx.Value(LogFieldsKey).(logrus.Fields); ok {
+		for key, value := range existing {
+			merged[key] = value
+		}
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, LogFieldsKey, merged)
+}
+
+// Log returns an entry of the standard logger carrying the fields and
+// correlation ID of the context, so the lines of concurrent scans can be
+// told apart
+func Log(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	if fields, ok := ctx.Value(LogFieldsKey).(logrus.Fields); ok {
+		entry = entry.WithFields(fields)
+	}
+	if correlationID := GetCorrelationID(ctx); correlationID != "" {
+		entry = entry.WithField("correlation_id", correlationID)
+	}
+	return entry
+}
+
 // WithCorrelationID adds correlation ID to context
 func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
 	return context.WithValue(ctx, CorrelationIDKey, correlationID)
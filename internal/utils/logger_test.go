@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLogFields(t *testing.T) {
+	base := WithLogFields(context.Background(), logrus.Fields{"platform": "hackerone"})
+	ctx := WithLogFields(base, logrus.Fields{"scan_id": "s1", "platform": "bugcrowd"})
+	ctx = WithCorrelationID(ctx, "c1")
+
+	entry := Log(ctx)
+	assert.Equal(t, "bugcrowd", entry.Data["platform"])
+	assert.Equal(t, "s1", entry.Data["scan_id"])
+	assert.Equal(t, "c1", entry.Data["correlation_id"])
+
+	// The parent context keeps its own fields
+	assert.Equal(t, logrus.Fields{"platform": "hackerone"}, Log(base).Data)
+	assert.Empty(t, Log(context.Background()).Data)
+}
+
+func TestSetLogFormat(t *testing.T) {
+	logger := logrus.StandardLogger()
+	formatter, out := logger.Formatter, logger.Out
+	defer func() {
+		logger.SetFormatter(formatter)
+		logger.SetOutput(out)
+	}()
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	SetLogFormat("json")
+
+	ctx := WithLogFields(context.Background(), logrus.Fields{"program_id": "p1", "domain": "example.com"})
+	Log(ctx).Info("Probing domain")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "Probing domain", line["message"])
+	assert.Equal(t, "info", line["level"])
+	assert.Equal(t, "p1", line["program_id"])
+	assert.Equal(t, "example.com", line["domain"])
+}
+
+func TestSetLogFormat_EmptyIsText(t *testing.T) {
+	logger := logrus.StandardLogger()
+	formatter := logger.Formatter
+	defer logger.SetFormatter(formatter)
+
+	SetLogFormat("json")
+	SetLogFormat("")
+	assert.IsType(t, &logrus.TextFormatter{}, logger.Formatter)
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maintenanceMarkers are phrases platforms use on maintenance and outage pages
+var maintenanceMarkers = []string{
+	"maintenance",
+	"scheduled downtime",
+	"be right back",
+	"temporarily unavailable",
+}
+
+var htmlTitlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// MaintenanceError reports that a platform API is down for maintenance or an outage
+type MaintenanceError struct {
+	Platform   string
+	StatusCode int
+	RetryAfter time.Duration // from the Retry-After header, 0 if not given
+	Reason     string
+}
+
+// Error implements the error interface
+func (e *MaintenanceError) Error() string {
+	msg := fmt.Sprintf("%s is in maintenance (status %d): %s", e.Platform, e.StatusCode, e.Reason)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %v", e.RetryAfter)
+	}
+	return msg
+}
+
+// AsMaintenanceError returns the MaintenanceError wrapped in err, if any
+func AsMaintenanceError(err error) (*MaintenanceError, bool) {
+	var merr *MaintenanceError
+	if errors.As(err, &merr) {
+		return merr, true
+	}
+	return nil, false
+}
+
+// DetectMaintenance inspects a platform API response for signs of a maintenance
+// window: any 503, or an HTML page mentioning maintenance where JSON was expected.
+// It returns nil for ordinary responses.
+func DetectMaintenance(platform string, statusCode int, header http.Header, body []byte) *MaintenanceError {
+	isHTML := strings.Contains(strings.ToLower(header.Get("Content-Type")), "text/html")
+	if statusCode != http.StatusServiceUnavailable && !isHTML {
+		return nil
+	}
+
+	lowerBody := strings.ToLower(string(body))
+	marker := ""
+	for _, m := range maintenanceMarkers {
+		if strings.Contains(lowerBody, m) {
+			marker = m
+			break
+		}
+	}
+
+	if statusCode != http.StatusServiceUnavailable && marker == "" {
+		return nil
+	}
+
+	reason := "service unavailable"
+	if match := htmlTitlePattern.FindSubmatch(body); match != nil {
+		if title := strings.TrimSpace(string(match[1])); title != "" {
+			reason = title
+		}
+	} else if marker != "" {
+		reason = marker
+	}
+
+	return &MaintenanceError{
+		Platform:   platform,
+		StatusCode: statusCode,
+		RetryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now()),
+		Reason:     reason,
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
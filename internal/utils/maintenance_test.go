@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectMaintenance(t *testing.T) {
+	htmlHeader := http.Header{"Content-Type": []string{"text/html; charset=utf-8"}}
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		body       string
+		want       bool
+		reason     string
+	}{
+		{"json ok", 200, jsonHeader, `{"data":[]}`, false, ""},
+		{"json not found", 404, jsonHeader, `{"errors":[{"detail":"not found"}]}`, false, ""},
+		{"503 banner", 503, htmlHeader, "<html><title>HackerOne is under maintenance</title></html>", true, "HackerOne is under maintenance"},
+		{"503 json", 503, jsonHeader, `{"message":"unavailable"}`, true, "service unavailable"},
+		{"maintenance page with 200", 200, htmlHeader, "<html><body>We're down for scheduled maintenance</body></html>", true, "maintenance"},
+		{"unrelated html", 200, htmlHeader, "<html><title>Login</title></html>", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merr := DetectMaintenance("hackerone", tt.statusCode, tt.header, []byte(tt.body))
+			if !tt.want {
+				assert.Nil(t, merr)
+				return
+			}
+			if assert.NotNil(t, merr) {
+				assert.Equal(t, tt.reason, merr.Reason)
+				assert.Equal(t, tt.statusCode, merr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestDetectMaintenance_RetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"120"}}
+	merr := DetectMaintenance("bugcrowd", 503, header, nil)
+	if assert.NotNil(t, merr) {
+		assert.Equal(t, 2*time.Minute, merr.RetryAfter)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-5", now))
+	assert.Equal(t, time.Hour, parseRetryAfter(now.Add(time.Hour).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Hour).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
+
+func TestAsMaintenanceError(t *testing.T) {
+	merr := &MaintenanceError{Platform: "hackerone", StatusCode: 503, Reason: "down"}
+	wrapped := fmt.Errorf("failed to get programs: %w", merr)
+
+	got, ok := AsMaintenanceError(wrapped)
+	assert.True(t, ok)
+	assert.Same(t, merr, got)
+
+	_, ok = AsMaintenanceError(fmt.Errorf("other"))
+	assert.False(t, ok)
+}
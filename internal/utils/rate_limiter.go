@@ -1,83 +1,341 @@
 package utils
 
 import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/go-resty/resty/v2"
 )
 
-// RateLimiter provides rate limiting functionality
+// epochThreshold separates rate limit reset headers given as a unix
+// timestamp from those given as seconds until the reset
+const epochThreshold = 1e9
+
+// RateLimiter is a token bucket: it holds up to burst tokens, refills rate
+// tokens per interval and takes one for every request. It adapts to the rate
+// limit headers of the responses it observes, pausing on Retry-After and
+// spacing an endpoint's requests out over what remains of its window.
 type RateLimiter struct {
+	mu         sync.Mutex
 	rate       int
 	interval   time.Duration
-	tokens     chan struct{}
-	mu         sync.Mutex
+	burst      int
+	tokens     float64
 	lastRefill time.Time
+	pausedTill time.Time
+	waited     time.Duration
+	endpoints  map[string]*endpointLimit
+	now        func() time.Time
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(rate int, interval time.Duration) *RateLimiter {
-	rl := &RateLimiter{
-		rate:       rate,
-		interval:   interval,
-		tokens:     make(chan struct{}, rate),
-		lastRefill: time.Now(),
-	}
+// endpointLimit is what an endpoint's last response reported of its limit
+type endpointLimit struct {
+	remaining  int
+	reset      time.Time
+	spacing    time.Duration // minimum gap between requests until reset
+	pausedTill time.Time
+}
 
-	// Fill the token bucket initially
-	for i := 0; i < rate; i++ {
-		rl.tokens <- struct{}{}
-	}
+// RateLimiterState is a snapshot of a rate limiter
+type RateLimiterState struct {
+	Rate      float64       // tokens refilled per second
+	Burst     int           // most tokens the bucket holds
+	Tokens    float64       // tokens available now
+	PausedFor time.Duration // until a Retry-After ends, 0 if not paused
+	Waited    time.Duration // total time callers spent waiting
+	Endpoints map[string]EndpointLimitState
+}
 
-	// Start the refill goroutine
-	go rl.refill()
+// EndpointLimitState is the limit an endpoint last reported
+type EndpointLimitState struct {
+	Remaining int
+	ResetIn   time.Duration
+	PausedFor time.Duration
+}
 
+// NewRateLimiter creates a rate limiter allowing rate requests per interval,
+// in bursts of up to rate requests. A rate of 0 or less disables limiting.
+func NewRateLimiter(rate int, interval time.Duration) *RateLimiter {
+	rl := &RateLimiter{
+		rate:      rate,
+		interval:  interval,
+		burst:     rate,
+		endpoints: make(map[string]*endpointLimit),
+		now:       time.Now,
+	}
+	rl.tokens = float64(rl.burst)
+	rl.lastRefill = rl.now()
 	return rl
 }
 
 // Wait blocks until a token is available
 func (rl *RateLimiter) Wait() {
-	<-rl.tokens
+	_ = rl.WaitContext(context.Background())
+}
+
+// WaitContext blocks until a token is available or the context is done
+func (rl *RateLimiter) WaitContext(ctx context.Context) error {
+	return rl.WaitEndpoint(ctx, "")
+}
+
+// WaitEndpoint blocks until a token is available and the endpoint is not
+// paused or spaced out by its rate limit headers, or the context is done
+func (rl *RateLimiter) WaitEndpoint(ctx context.Context, endpoint string) error {
+	start := time.Now()
+	defer func() {
+		if waited := time.Since(start); waited > time.Millisecond {
+			rl.mu.Lock()
+			rl.waited += waited
+			rl.mu.Unlock()
+		}
+	}()
+
+	for {
+		rl.mu.Lock()
+		delay := rl.reserve(endpoint)
+		rl.mu.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
 }
 
 // TryWait attempts to get a token without blocking
 func (rl *RateLimiter) TryWait() bool {
-	select {
-	case <-rl.tokens:
-		return true
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.reserve("") <= 0
+}
+
+// reserve takes a token for a request to the endpoint, or returns how long to
+// wait before trying again. The caller holds the lock.
+func (rl *RateLimiter) reserve(endpoint string) time.Duration {
+	now := rl.now()
+	rl.refill(now)
+
+	if now.Before(rl.pausedTill) {
+		return rl.pausedTill.Sub(now)
+	}
+
+	limit := rl.endpoints[endpoint]
+	if limit != nil {
+		if !limit.reset.IsZero() && !now.Before(limit.reset) {
+			// The endpoint's window has reset, so its last report no longer applies
+			delete(rl.endpoints, endpoint)
+			limit = nil
+		} else if now.Before(limit.pausedTill) {
+			return limit.pausedTill.Sub(now)
+		}
+	}
+
+	if rl.rate > 0 {
+		if rl.tokens < 1 {
+			return time.Duration((1 - rl.tokens) / rl.perSecond() * float64(time.Second))
+		}
+		rl.tokens--
+	}
+
+	if limit != nil && limit.spacing > 0 {
+		limit.pausedTill = now.Add(limit.spacing)
+	}
+	return 0
+}
+
+// refill adds the tokens accrued since the last refill. The caller holds the lock.
+func (rl *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(rl.lastRefill)
+	rl.lastRefill = now
+	if elapsed <= 0 || rl.rate <= 0 {
+		return
+	}
+	rl.tokens = math.Min(float64(rl.burst), rl.tokens+elapsed.Seconds()*rl.perSecond())
+}
+
+// perSecond returns the refill rate in tokens per second
+func (rl *RateLimiter) perSecond() float64 {
+	if rl.interval <= 0 {
+		return float64(rl.rate)
+	}
+	return float64(rl.rate) / rl.interval.Seconds()
+}
+
+// Observe adapts the limiter to a response from the endpoint: a 429 or 503
+// pauses every endpoint for its Retry-After, and the X-RateLimit-Remaining
+// and X-RateLimit-Reset headers (or their unprefixed RateLimit-* forms) pause
+// the endpoint once nothing remains and otherwise spread what remains evenly
+// over the rest of the window
+func (rl *RateLimiter) Observe(endpoint string, statusCode int, header http.Header) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.refill(now)
+
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable {
+		if retryAfter := parseRetryAfter(header.Get("Retry-After"), now); retryAfter > 0 {
+			if till := now.Add(retryAfter); till.After(rl.pausedTill) {
+				rl.pausedTill = till
+			}
+			rl.tokens = 0
+		} else if statusCode == http.StatusTooManyRequests {
+			// Throttled without a hint; give the bucket a full refill cycle
+			rl.tokens = 0
+		}
+	}
+
+	remaining, ok := rateLimitHeader(header, "Remaining")
+	if !ok {
+		return
+	}
+	limit := &endpointLimit{remaining: int(remaining)}
+	if reset, ok := rateLimitHeader(header, "Reset"); ok {
+		limit.reset = resetTime(reset, now)
+	}
+
+	switch {
+	case limit.reset.IsZero() || !limit.reset.After(now):
+		// Without a window to spread over, just keep the bucket below what remains
+		rl.tokens = math.Min(rl.tokens, float64(limit.remaining))
+		limit.reset = time.Time{}
+	case limit.remaining <= 0:
+		limit.pausedTill = limit.reset
 	default:
-		return false
+		window := limit.reset.Sub(now)
+		if spacing := window / time.Duration(limit.remaining); rl.rate <= 0 || spacing.Seconds() > 1/rl.perSecond() {
+			limit.spacing = spacing
+		}
 	}
+	rl.endpoints[endpoint] = limit
 }
 
-// refill refills the token bucket at the specified rate
-func (rl *RateLimiter) refill() {
-	ticker := time.NewTicker(rl.interval / time.Duration(rl.rate))
-	defer ticker.Stop()
+// Instrument makes every request of the client, retries included, wait for
+// the limiter and adapts the limiter to each response. endpoint maps a
+// request path to the endpoint whose headers it shares; nil treats every
+// path as its own endpoint.
+func (rl *RateLimiter) Instrument(client *resty.Client, endpoint func(path string) string) {
+	if endpoint == nil {
+		endpoint = func(path string) string { return path }
+	}
 
-	for range ticker.C {
-		select {
-		case rl.tokens <- struct{}{}:
-			// Token added successfully
-		default:
-			// Bucket is full, skip
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		return rl.WaitEndpoint(req.Context(), endpoint(requestPath(req.URL)))
+	})
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		if resp.Request.RawRequest == nil {
+			return nil
+		}
+		rl.Observe(endpoint(resp.Request.RawRequest.URL.Path), resp.StatusCode(), resp.Header())
+		return nil
+	})
+}
+
+// requestPath returns the path of a request URL, which is still the raw
+// string the request was made with before resty parses it
+func requestPath(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return parsed.Path
+}
+
+// rateLimitHeader reads a numeric X-RateLimit-<name> or RateLimit-<name> header
+func rateLimitHeader(header http.Header, name string) (float64, bool) {
+	for _, key := range []string{"X-RateLimit-" + name, "RateLimit-" + name, "X-Rate-Limit-" + name} {
+		value := strings.TrimSpace(header.Get(key))
+		if value == "" {
+			continue
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil && number >= 0 {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
+// resetTime turns a reset header into a time; large values are unix
+// timestamps, smaller ones seconds from now
+func resetTime(value float64, now time.Time) time.Time {
+	if value >= epochThreshold {
+		return time.Unix(0, int64(value*float64(time.Second)))
+	}
+	return now.Add(time.Duration(value * float64(time.Second)))
+}
+
+// State returns a snapshot of the limiter
+func (rl *RateLimiter) State() RateLimiterState {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.refill(now)
+
+	state := RateLimiterState{
+		Rate:      rl.perSecond(),
+		Burst:     rl.burst,
+		Tokens:    rl.tokens,
+		Waited:    rl.waited,
+		Endpoints: make(map[string]EndpointLimitState, len(rl.endpoints)),
+	}
+	if now.Before(rl.pausedTill) {
+		state.PausedFor = rl.pausedTill.Sub(now)
+	}
+	for endpoint, limit := range rl.endpoints {
+		if !limit.reset.IsZero() && !now.Before(limit.reset) {
+			continue
+		}
+		endpointState := EndpointLimitState{Remaining: limit.remaining}
+		if !limit.reset.IsZero() {
+			endpointState.ResetIn = limit.reset.Sub(now)
 		}
+		if now.Before(limit.pausedTill) {
+			endpointState.PausedFor = limit.pausedTill.Sub(now)
+		}
+		state.Endpoints[endpoint] = endpointState
 	}
+	return state
 }
 
 // GetRate returns the current rate limit
 func (rl *RateLimiter) GetRate() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
 	return rl.rate
 }
 
 // GetInterval returns the current interval
 func (rl *RateLimiter) GetInterval() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
 	return rl.interval
 }
 
+// GetBurst returns the most tokens the bucket holds
+func (rl *RateLimiter) GetBurst() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.burst
+}
+
 // UpdateRate updates the rate limit
 func (rl *RateLimiter) UpdateRate(newRate int) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
+	rl.refill(rl.now())
 	rl.rate = newRate
 }
 
@@ -85,5 +343,22 @@ func (rl *RateLimiter) UpdateRate(newRate int) {
 func (rl *RateLimiter) UpdateInterval(newInterval time.Duration) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
+	rl.refill(rl.now())
 	rl.interval = newInterval
 }
+
+// SetBurst sets the most tokens the bucket holds; 0 or less resets it to the rate
+func (rl *RateLimiter) SetBurst(burst int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.refill(rl.now())
+	if burst <= 0 {
+		burst = rl.rate
+	}
+	if rl.tokens >= float64(rl.burst) {
+		// A full bucket stays full
+		rl.tokens = float64(burst)
+	}
+	rl.burst = burst
+	rl.tokens = math.Min(rl.tokens, float64(burst))
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter returns a limiter whose clock only moves when advanced
+func newTestLimiter(rate int, interval time.Duration) (*RateLimiter, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(rate, interval)
+	rl.now = func() time.Time { return now }
+	rl.lastRefill = now
+	return rl, &now
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	rl, now := newTestLimiter(60, time.Minute)
+	rl.SetBurst(3)
+
+	for i := 0; i < 3; i++ {
+		assert.Zero(t, rl.reserve(""), "request %d fits the burst", i)
+	}
+	assert.Equal(t, time.Second, rl.reserve(""))
+	assert.False(t, rl.TryWait())
+
+	*now = now.Add(time.Second)
+	assert.True(t, rl.TryWait())
+
+	// Idle time refills no further than the burst
+	*now = now.Add(time.Hour)
+	assert.InDelta(t, 3, rl.State().Tokens, 0.001)
+}
+
+func TestRateLimiter_SetBurstKeepsFullBucketFull(t *testing.T) {
+	rl, _ := newTestLimiter(10, time.Minute)
+	rl.SetBurst(25)
+	assert.Equal(t, 25, rl.GetBurst())
+	assert.InDelta(t, 25, rl.State().Tokens, 0.001)
+
+	rl.SetBurst(0)
+	assert.Equal(t, 10, rl.GetBurst())
+	assert.InDelta(t, 10, rl.State().Tokens, 0.001)
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	rl, _ := newTestLimiter(0, time.Minute)
+	for i := 0; i < 100; i++ {
+		assert.Zero(t, rl.reserve(""))
+	}
+}
+
+func TestRateLimiter_RetryAfterPausesEveryEndpoint(t *testing.T) {
+	rl, now := newTestLimiter(60, time.Minute)
+	rl.Observe("/programs", http.StatusTooManyRequests, http.Header{"Retry-After": []string{"30"}})
+
+	assert.Equal(t, 30*time.Second, rl.reserve("/programs"))
+	assert.Equal(t, 30*time.Second, rl.reserve("/other"))
+	assert.Equal(t, 30*time.Second, rl.State().PausedFor)
+
+	*now = now.Add(31 * time.Second)
+	assert.Zero(t, rl.reserve("/programs"))
+}
+
+func TestRateLimiter_TooManyRequestsDrainsBucket(t *testing.T) {
+	rl, _ := newTestLimiter(60, time.Minute)
+	rl.Observe("/programs", http.StatusTooManyRequests, http.Header{})
+	assert.Equal(t, time.Second, rl.reserve("/programs"))
+}
+
+func TestRateLimiter_RemainingExhaustedPausesEndpoint(t *testing.T) {
+	rl, now := newTestLimiter(60, time.Minute)
+	rl.Observe("/programs", http.StatusOK, http.Header{
+		"X-Ratelimit-Remaining": []string{"0"},
+		"X-Ratelimit-Reset":     []string{"20"},
+	})
+
+	assert.Equal(t, 20*time.Second, rl.reserve("/programs"))
+	assert.Zero(t, rl.reserve("/scopes"), "other endpoints keep their own limit")
+
+	state := rl.State().Endpoints["/programs"]
+	assert.Equal(t, 0, state.Remaining)
+	assert.Equal(t, 20*time.Second, state.PausedFor)
+
+	*now = now.Add(20 * time.Second)
+	assert.Zero(t, rl.reserve("/programs"))
+	assert.NotContains(t, rl.State().Endpoints, "/programs", "a reset window is forgotten")
+}
+
+func TestRateLimiter_RemainingSpreadOverWindow(t *testing.T) {
+	rl, now := newTestLimiter(600, time.Minute)
+	reset := now.Add(50 * time.Second).Unix()
+	rl.Observe("/programs", http.StatusOK, http.Header{
+		"Ratelimit-Remaining": []string{"5"},
+		"Ratelimit-Reset":     []string{strconv.FormatInt(reset, 10)},
+	})
+
+	assert.Zero(t, rl.reserve("/programs"))
+	assert.Equal(t, 10*time.Second, rl.reserve("/programs"))
+
+	*now = now.Add(10 * time.Second)
+	assert.Zero(t, rl.reserve("/programs"))
+}
+
+func TestRateLimiter_RemainingWithoutResetCapsTokens(t *testing.T) {
+	rl, _ := newTestLimiter(60, time.Minute)
+	rl.Observe("/programs", http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"1"}})
+
+	assert.InDelta(t, 1, rl.State().Tokens, 0.001)
+	assert.Zero(t, rl.reserve("/programs"))
+	assert.Equal(t, time.Second, rl.reserve("/programs"))
+}
+
+func TestRateLimiter_WaitContextCancelled(t *testing.T) {
+	rl := NewRateLimiter(1, time.Hour)
+	require.NoError(t, rl.WaitContext(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, rl.WaitContext(ctx), context.DeadlineExceeded)
+	assert.Positive(t, rl.State().Waited)
+}
+
+func TestRateLimiter_Instrument(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "3600")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rl := NewRateLimiter(600, time.Minute)
+	client := resty.New().SetBaseURL(server.URL)
+	rl.Instrument(client, func(path string) string { return "programs" })
+
+	_, err := client.R().Get("/programs/acme")
+	require.NoError(t, err)
+	assert.Equal(t, 0, rl.State().Endpoints["programs"].Remaining)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.R().SetContext(ctx).Get("/programs/other")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), requests.Load(), "the exhausted endpoint is not requested again")
+}
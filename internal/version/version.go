@@ -0,0 +1,131 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Build information, set at build time with
+// -ldflags "-X github.com/monitor-agent/internal/version.Version=v1.2.3 ..."
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// ReleasesURL is the GitHub API endpoint for the latest release
+const ReleasesURL = "https://api.github.com/repos/osm6495/Monitor-Agent/releases/latest"
+
+func init() {
+	// Fall back to the VCS revision Go embeds when ldflags were not used
+	if Commit != "unknown" {
+		return
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				Commit = setting.Value
+			}
+		}
+	}
+}
+
+// UserAgent returns the User-Agent header sent with outgoing requests
+func UserAgent() string {
+	return "Monitor-Agent/" + Version
+}
+
+// String returns a one-line description of the build
+func String() string {
+	commit := Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return fmt.Sprintf("%s (commit %s, built %s)", Version, commit, BuildDate)
+}
+
+// Release is a published GitHub release
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// LatestRelease fetches the latest published release from the given GitHub API URL
+func LatestRelease(ctx context.Context, url string, timeout time.Duration) (*Release, error) {
+	client := resty.New()
+	client.SetTimeout(timeout)
+
+	resp, err := client.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/vnd.github+json").
+		SetHeader("User-Agent", UserAgent()).
+		Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("release check returned status %d", resp.StatusCode())
+	}
+
+	var release Release
+	if err := json.Unmarshal(resp.Body(), &release); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal release: %w", err)
+	}
+
+	return &release, nil
+}
+
+// IsNewer reports whether latest is a newer semantic version than current.
+// Development builds and unparseable versions are never considered outdated.
+func IsNewer(latest, current string) bool {
+	latestParts, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	currentParts, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+
+	for i := range latestParts {
+		if latestParts[i] != currentParts[i] {
+			return latestParts[i] > currentParts[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses a version like v1.2.3 or 1.2.3-rc1 into its numeric parts
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.IndexAny(version, "-+"); idx != -1 {
+		version = version[:idx]
+	}
+
+	fields := strings.Split(version, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+
+	return parts, true
+}
@@ -0,0 +1,58 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest  string
+		current string
+		want    bool
+	}{
+		{"v1.3.0", "v1.2.9", true},
+		{"v1.2.10", "v1.2.9", true},
+		{"v2.0", "1.9.9", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.2.3", "v1.3.0", false},
+		{"v1.2.3", "v1.2.3-rc1", false},
+		{"v1.2.3", "dev", false},
+		{"nightly", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.latest+"_vs_"+tt.current, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsNewer(tt.latest, tt.current))
+		})
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, UserAgent(), r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "html_url": "https://github.com/osm6495/Monitor-Agent/releases/tag/v1.4.0"}`))
+	}))
+	defer server.Close()
+
+	release, err := LatestRelease(context.Background(), server.URL, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.4.0", release.TagName)
+}
+
+func TestLatestRelease_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := LatestRelease(context.Background(), server.URL, time.Second)
+	assert.Error(t, err)
+}
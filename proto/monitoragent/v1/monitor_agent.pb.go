@@ -0,0 +1,1516 @@
+// gRPC API of the monitor agent for internal services. Regenerate the Go code
+// with `make proto` after changing this file; other languages can generate
+// their clients from it directly.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: monitoragent/v1/monitor_agent.proto
+
+package monitoragentv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Program struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Platform      string                 `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	ProgramUrl    string                 `protobuf:"bytes,4,opt,name=program_url,json=programUrl,proto3" json:"program_url,omitempty"`
+	IsActive      bool                   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	LastUpdated   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Program) Reset() {
+	*x = Program{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Program) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Program) ProtoMessage() {}
+
+func (x *Program) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Program.ProtoReflect.Descriptor instead.
+func (*Program) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Program) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Program) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Program) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *Program) GetProgramUrl() string {
+	if x != nil {
+		return x.ProgramUrl
+	}
+	return ""
+}
+
+func (x *Program) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Program) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+type Asset struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProgramId  string                 `protobuf:"bytes,2,opt,name=program_id,json=programId,proto3" json:"program_id,omitempty"`
+	ProgramUrl string                 `protobuf:"bytes,3,opt,name=program_url,json=programUrl,proto3" json:"program_url,omitempty"`
+	Url        string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Domain     string                 `protobuf:"bytes,5,opt,name=domain,proto3" json:"domain,omitempty"`
+	Subdomain  string                 `protobuf:"bytes,6,opt,name=subdomain,proto3" json:"subdomain,omitempty"`
+	Ip         string                 `protobuf:"bytes,7,opt,name=ip,proto3" json:"ip,omitempty"`
+	Ipv6       string                 `protobuf:"bytes,8,opt,name=ipv6,proto3" json:"ipv6,omitempty"`
+	// State of the latest probe, e.g. live or waf-blocked; empty when never probed
+	Liveness     string                 `protobuf:"bytes,9,opt,name=liveness,proto3" json:"liveness,omitempty"`
+	Status       string                 `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	FirstSource  string                 `protobuf:"bytes,11,opt,name=first_source,json=firstSource,proto3" json:"first_source,omitempty"`
+	Ignored      bool                   `protobuf:"varint,12,opt,name=ignored,proto3" json:"ignored,omitempty"`
+	Score        float64                `protobuf:"fixed64,13,opt,name=score,proto3" json:"score,omitempty"`
+	LastProbedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=last_probed_at,json=lastProbedAt,proto3" json:"last_probed_at,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Cluster of near-identical responses the asset belongs to; empty when unclustered
+	ClusterId string `protobuf:"bytes,16,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Every discovery source that found the asset, e.g. hackerone or chaosdb
+	Provenance []string `protobuf:"bytes,17,rep,name=provenance,proto3" json:"provenance,omitempty"`
+	// Usage terms the data of those sources comes with
+	DataTerms     []string `protobuf:"bytes,18,rep,name=data_terms,json=dataTerms,proto3" json:"data_terms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Asset) Reset() {
+	*x = Asset{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Asset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Asset) ProtoMessage() {}
+
+func (x *Asset) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Asset.ProtoReflect.Descriptor instead.
+func (*Asset) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Asset) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Asset) GetProgramId() string {
+	if x != nil {
+		return x.ProgramId
+	}
+	return ""
+}
+
+func (x *Asset) GetProgramUrl() string {
+	if x != nil {
+		return x.ProgramUrl
+	}
+	return ""
+}
+
+func (x *Asset) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Asset) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Asset) GetSubdomain() string {
+	if x != nil {
+		return x.Subdomain
+	}
+	return ""
+}
+
+func (x *Asset) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Asset) GetIpv6() string {
+	if x != nil {
+		return x.Ipv6
+	}
+	return ""
+}
+
+func (x *Asset) GetLiveness() string {
+	if x != nil {
+		return x.Liveness
+	}
+	return ""
+}
+
+func (x *Asset) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Asset) GetFirstSource() string {
+	if x != nil {
+		return x.FirstSource
+	}
+	return ""
+}
+
+func (x *Asset) GetIgnored() bool {
+	if x != nil {
+		return x.Ignored
+	}
+	return false
+}
+
+func (x *Asset) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Asset) GetLastProbedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastProbedAt
+	}
+	return nil
+}
+
+func (x *Asset) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Asset) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *Asset) GetProvenance() []string {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *Asset) GetDataTerms() []string {
+	if x != nil {
+		return x.DataTerms
+	}
+	return nil
+}
+
+// ResponseCluster is a group of live assets whose latest responses are
+// near-identical, reviewed through its representative asset
+type ResponseCluster struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RepresentativeAssetId string                 `protobuf:"bytes,2,opt,name=representative_asset_id,json=representativeAssetId,proto3" json:"representative_asset_id,omitempty"`
+	RepresentativeUrl     string                 `protobuf:"bytes,3,opt,name=representative_url,json=representativeUrl,proto3" json:"representative_url,omitempty"`
+	Size                  int32                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Programs              int32                  `protobuf:"varint,5,opt,name=programs,proto3" json:"programs,omitempty"`
+	StatusCode            int32                  `protobuf:"varint,6,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	CreatedAt             *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ResponseCluster) Reset() {
+	*x = ResponseCluster{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseCluster) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseCluster) ProtoMessage() {}
+
+func (x *ResponseCluster) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseCluster.ProtoReflect.Descriptor instead.
+func (*ResponseCluster) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResponseCluster) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ResponseCluster) GetRepresentativeAssetId() string {
+	if x != nil {
+		return x.RepresentativeAssetId
+	}
+	return ""
+}
+
+func (x *ResponseCluster) GetRepresentativeUrl() string {
+	if x != nil {
+		return x.RepresentativeUrl
+	}
+	return ""
+}
+
+func (x *ResponseCluster) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ResponseCluster) GetPrograms() int32 {
+	if x != nil {
+		return x.Programs
+	}
+	return 0
+}
+
+func (x *ResponseCluster) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *ResponseCluster) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Scan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProgramId     string                 `protobuf:"bytes,2,opt,name=program_id,json=programId,proto3" json:"program_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	AssetsFound   int32                  `protobuf:"varint,4,opt,name=assets_found,json=assetsFound,proto3" json:"assets_found,omitempty"`
+	AssetsSeen    int32                  `protobuf:"varint,5,opt,name=assets_seen,json=assetsSeen,proto3" json:"assets_seen,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scan) Reset() {
+	*x = Scan{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scan) ProtoMessage() {}
+
+func (x *Scan) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scan.ProtoReflect.Descriptor instead.
+func (*Scan) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Scan) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Scan) GetProgramId() string {
+	if x != nil {
+		return x.ProgramId
+	}
+	return ""
+}
+
+func (x *Scan) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Scan) GetAssetsFound() int32 {
+	if x != nil {
+		return x.AssetsFound
+	}
+	return 0
+}
+
+func (x *Scan) GetAssetsSeen() int32 {
+	if x != nil {
+		return x.AssetsSeen
+	}
+	return 0
+}
+
+func (x *Scan) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Scan) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Scan) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type ListProgramsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProgramsRequest) Reset() {
+	*x = ListProgramsRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProgramsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProgramsRequest) ProtoMessage() {}
+
+func (x *ListProgramsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProgramsRequest.ProtoReflect.Descriptor instead.
+func (*ListProgramsRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{4}
+}
+
+type ListProgramsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Programs      []*Program             `protobuf:"bytes,1,rep,name=programs,proto3" json:"programs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProgramsResponse) Reset() {
+	*x = ListProgramsResponse{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProgramsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProgramsResponse) ProtoMessage() {}
+
+func (x *ListProgramsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProgramsResponse.ProtoReflect.Descriptor instead.
+func (*ListProgramsResponse) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListProgramsResponse) GetPrograms() []*Program {
+	if x != nil {
+		return x.Programs
+	}
+	return nil
+}
+
+type ListAssetsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Space-separated field:value terms, as taken by `assets update --query`
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of assets returned; defaults to 100 and is capped at 1000
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAssetsRequest) Reset() {
+	*x = ListAssetsRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAssetsRequest) ProtoMessage() {}
+
+func (x *ListAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAssetsRequest.ProtoReflect.Descriptor instead.
+func (*ListAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListAssetsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListAssetsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FindAssetsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Host or URL; its subdomains are included
+	Host          string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Limit         int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindAssetsRequest) Reset() {
+	*x = FindAssetsRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindAssetsRequest) ProtoMessage() {}
+
+func (x *FindAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindAssetsRequest.ProtoReflect.Descriptor instead.
+func (*FindAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FindAssetsRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *FindAssetsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListAssetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assets        []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAssetsResponse) Reset() {
+	*x = ListAssetsResponse{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAssetsResponse) ProtoMessage() {}
+
+func (x *ListAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAssetsResponse.ProtoReflect.Descriptor instead.
+func (*ListAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListAssetsResponse) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+type ListClustersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only clusters of at least this many assets; defaults to 2
+	MinSize int32 `protobuf:"varint,1,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	// Maximum number of clusters returned; defaults to 100 and is capped at 1000
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClustersRequest) Reset() {
+	*x = ListClustersRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClustersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClustersRequest) ProtoMessage() {}
+
+func (x *ListClustersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClustersRequest.ProtoReflect.Descriptor instead.
+func (*ListClustersRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListClustersRequest) GetMinSize() int32 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *ListClustersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListClustersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clusters      []*ResponseCluster     `protobuf:"bytes,1,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListClustersResponse) Reset() {
+	*x = ListClustersResponse{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListClustersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClustersResponse) ProtoMessage() {}
+
+func (x *ListClustersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClustersResponse.ProtoReflect.Descriptor instead.
+func (*ListClustersResponse) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListClustersResponse) GetClusters() []*ResponseCluster {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{11}
+}
+
+type LivenessCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Liveness      string                 `protobuf:"bytes,1,opt,name=liveness,proto3" json:"liveness,omitempty"`
+	Assets        int32                  `protobuf:"varint,2,opt,name=assets,proto3" json:"assets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LivenessCount) Reset() {
+	*x = LivenessCount{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LivenessCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LivenessCount) ProtoMessage() {}
+
+func (x *LivenessCount) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LivenessCount.ProtoReflect.Descriptor instead.
+func (*LivenessCount) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LivenessCount) GetLiveness() string {
+	if x != nil {
+		return x.Liveness
+	}
+	return ""
+}
+
+func (x *LivenessCount) GetAssets() int32 {
+	if x != nil {
+		return x.Assets
+	}
+	return 0
+}
+
+type Stats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TotalPrograms  int32                  `protobuf:"varint,1,opt,name=total_programs,json=totalPrograms,proto3" json:"total_programs,omitempty"`
+	ActivePrograms int32                  `protobuf:"varint,2,opt,name=active_programs,json=activePrograms,proto3" json:"active_programs,omitempty"`
+	TotalAssets    int32                  `protobuf:"varint,3,opt,name=total_assets,json=totalAssets,proto3" json:"total_assets,omitempty"`
+	Liveness       []*LivenessCount       `protobuf:"bytes,4,rep,name=liveness,proto3" json:"liveness,omitempty"`
+	RecentScans    []*Scan                `protobuf:"bytes,5,rep,name=recent_scans,json=recentScans,proto3" json:"recent_scans,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Stats) GetTotalPrograms() int32 {
+	if x != nil {
+		return x.TotalPrograms
+	}
+	return 0
+}
+
+func (x *Stats) GetActivePrograms() int32 {
+	if x != nil {
+		return x.ActivePrograms
+	}
+	return 0
+}
+
+func (x *Stats) GetTotalAssets() int32 {
+	if x != nil {
+		return x.TotalAssets
+	}
+	return 0
+}
+
+func (x *Stats) GetLiveness() []*LivenessCount {
+	if x != nil {
+		return x.Liveness
+	}
+	return nil
+}
+
+func (x *Stats) GetRecentScans() []*Scan {
+	if x != nil {
+		return x.RecentScans
+	}
+	return nil
+}
+
+type TriggerScanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Program name or handle, optionally qualified with its platform, e.g. "hackerone/acme"
+	Program       string `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerScanRequest) Reset() {
+	*x = TriggerScanRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerScanRequest) ProtoMessage() {}
+
+func (x *TriggerScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerScanRequest.ProtoReflect.Descriptor instead.
+func (*TriggerScanRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TriggerScanRequest) GetProgram() string {
+	if x != nil {
+		return x.Program
+	}
+	return ""
+}
+
+type TriggerScanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Program       *Program               `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerScanResponse) Reset() {
+	*x = TriggerScanResponse{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerScanResponse) ProtoMessage() {}
+
+func (x *TriggerScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerScanResponse.ProtoReflect.Descriptor instead.
+func (*TriggerScanResponse) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TriggerScanResponse) GetProgram() *Program {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+type CancelScanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScanId        string                 `protobuf:"bytes,1,opt,name=scan_id,json=scanId,proto3" json:"scan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScanRequest) Reset() {
+	*x = CancelScanRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScanRequest) ProtoMessage() {}
+
+func (x *CancelScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScanRequest.ProtoReflect.Descriptor instead.
+func (*CancelScanRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelScanRequest) GetScanId() string {
+	if x != nil {
+		return x.ScanId
+	}
+	return ""
+}
+
+type CancelScanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScanId        string                 `protobuf:"bytes,1,opt,name=scan_id,json=scanId,proto3" json:"scan_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScanResponse) Reset() {
+	*x = CancelScanResponse{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScanResponse) ProtoMessage() {}
+
+func (x *CancelScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScanResponse.ProtoReflect.Descriptor instead.
+func (*CancelScanResponse) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelScanResponse) GetScanId() string {
+	if x != nil {
+		return x.ScanId
+	}
+	return ""
+}
+
+func (x *CancelScanResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type StreamEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Event types to stream, e.g. asset.discovered; all types when empty
+	Types         []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StreamEventsRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+// Event is a CloudEvents event as delivered to the other transports
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Subject       string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_monitoragent_v1_monitor_agent_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_monitoragent_v1_monitor_agent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_monitoragent_v1_monitor_agent_proto protoreflect.FileDescriptor
+
+const file_monitoragent_v1_monitor_agent_proto_rawDesc = "" +
+	"\n" +
+	"#monitoragent/v1/monitor_agent.proto\x12\x0fmonitoragent.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc6\x01\n" +
+	"\aProgram\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bplatform\x18\x03 \x01(\tR\bplatform\x12\x1f\n" +
+	"\vprogram_url\x18\x04 \x01(\tR\n" +
+	"programUrl\x12\x1b\n" +
+	"\tis_active\x18\x05 \x01(\bR\bisActive\x12=\n" +
+	"\flast_updated\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\"\xa5\x04\n" +
+	"\x05Asset\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"program_id\x18\x02 \x01(\tR\tprogramId\x12\x1f\n" +
+	"\vprogram_url\x18\x03 \x01(\tR\n" +
+	"programUrl\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x16\n" +
+	"\x06domain\x18\x05 \x01(\tR\x06domain\x12\x1c\n" +
+	"\tsubdomain\x18\x06 \x01(\tR\tsubdomain\x12\x0e\n" +
+	"\x02ip\x18\a \x01(\tR\x02ip\x12\x12\n" +
+	"\x04ipv6\x18\b \x01(\tR\x04ipv6\x12\x1a\n" +
+	"\bliveness\x18\t \x01(\tR\bliveness\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x12!\n" +
+	"\ffirst_source\x18\v \x01(\tR\vfirstSource\x12\x18\n" +
+	"\aignored\x18\f \x01(\bR\aignored\x12\x14\n" +
+	"\x05score\x18\r \x01(\x01R\x05score\x12@\n" +
+	"\x0elast_probed_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\flastProbedAt\x129\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x10 \x01(\tR\tclusterId\x12\x1e\n" +
+	"\n" +
+	"provenance\x18\x11 \x03(\tR\n" +
+	"provenance\x12\x1d\n" +
+	"\n" +
+	"data_terms\x18\x12 \x03(\tR\tdataTerms\"\x94\x02\n" +
+	"\x0fResponseCluster\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\x17representative_asset_id\x18\x02 \x01(\tR\x15representativeAssetId\x12-\n" +
+	"\x12representative_url\x18\x03 \x01(\tR\x11representativeUrl\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x05R\x04size\x12\x1a\n" +
+	"\bprograms\x18\x05 \x01(\x05R\bprograms\x12\x1f\n" +
+	"\vstatus_code\x18\x06 \x01(\x05R\n" +
+	"statusCode\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa1\x02\n" +
+	"\x04Scan\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"program_id\x18\x02 \x01(\tR\tprogramId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12!\n" +
+	"\fassets_found\x18\x04 \x01(\x05R\vassetsFound\x12\x1f\n" +
+	"\vassets_seen\x18\x05 \x01(\x05R\n" +
+	"assetsSeen\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"started_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\x15\n" +
+	"\x13ListProgramsRequest\"L\n" +
+	"\x14ListProgramsResponse\x124\n" +
+	"\bprograms\x18\x01 \x03(\v2\x18.monitoragent.v1.ProgramR\bprograms\"?\n" +
+	"\x11ListAssetsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"=\n" +
+	"\x11FindAssetsRequest\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"D\n" +
+	"\x12ListAssetsResponse\x12.\n" +
+	"\x06assets\x18\x01 \x03(\v2\x16.monitoragent.v1.AssetR\x06assets\"F\n" +
+	"\x13ListClustersRequest\x12\x19\n" +
+	"\bmin_size\x18\x01 \x01(\x05R\aminSize\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"T\n" +
+	"\x14ListClustersResponse\x12<\n" +
+	"\bclusters\x18\x01 \x03(\v2 .monitoragent.v1.ResponseClusterR\bclusters\"\x11\n" +
+	"\x0fGetStatsRequest\"C\n" +
+	"\rLivenessCount\x12\x1a\n" +
+	"\bliveness\x18\x01 \x01(\tR\bliveness\x12\x16\n" +
+	"\x06assets\x18\x02 \x01(\x05R\x06assets\"\xf0\x01\n" +
+	"\x05Stats\x12%\n" +
+	"\x0etotal_programs\x18\x01 \x01(\x05R\rtotalPrograms\x12'\n" +
+	"\x0factive_programs\x18\x02 \x01(\x05R\x0eactivePrograms\x12!\n" +
+	"\ftotal_assets\x18\x03 \x01(\x05R\vtotalAssets\x12:\n" +
+	"\bliveness\x18\x04 \x03(\v2\x1e.monitoragent.v1.LivenessCountR\bliveness\x128\n" +
+	"\frecent_scans\x18\x05 \x03(\v2\x15.monitoragent.v1.ScanR\vrecentScans\".\n" +
+	"\x12TriggerScanRequest\x12\x18\n" +
+	"\aprogram\x18\x01 \x01(\tR\aprogram\"I\n" +
+	"\x13TriggerScanResponse\x122\n" +
+	"\aprogram\x18\x01 \x01(\v2\x18.monitoragent.v1.ProgramR\aprogram\",\n" +
+	"\x11CancelScanRequest\x12\x17\n" +
+	"\ascan_id\x18\x01 \x01(\tR\x06scanId\"E\n" +
+	"\x12CancelScanResponse\x12\x17\n" +
+	"\ascan_id\x18\x01 \x01(\tR\x06scanId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"+\n" +
+	"\x13StreamEventsRequest\x12\x14\n" +
+	"\x05types\x18\x01 \x03(\tR\x05types\"\xba\x01\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12.\n" +
+	"\x04time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12+\n" +
+	"\x04data\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x04data2\xbd\x05\n" +
+	"\fMonitorAgent\x12[\n" +
+	"\fListPrograms\x12$.monitoragent.v1.ListProgramsRequest\x1a%.monitoragent.v1.ListProgramsResponse\x12U\n" +
+	"\n" +
+	"ListAssets\x12\".monitoragent.v1.ListAssetsRequest\x1a#.monitoragent.v1.ListAssetsResponse\x12U\n" +
+	"\n" +
+	"FindAssets\x12\".monitoragent.v1.FindAssetsRequest\x1a#.monitoragent.v1.ListAssetsResponse\x12[\n" +
+	"\fListClusters\x12$.monitoragent.v1.ListClustersRequest\x1a%.monitoragent.v1.ListClustersResponse\x12D\n" +
+	"\bGetStats\x12 .monitoragent.v1.GetStatsRequest\x1a\x16.monitoragent.v1.Stats\x12X\n" +
+	"\vTriggerScan\x12#.monitoragent.v1.TriggerScanRequest\x1a$.monitoragent.v1.TriggerScanResponse\x12U\n" +
+	"\n" +
+	"CancelScan\x12\".monitoragent.v1.CancelScanRequest\x1a#.monitoragent.v1.CancelScanResponse\x12N\n" +
+	"\fStreamEvents\x12$.monitoragent.v1.StreamEventsRequest\x1a\x16.monitoragent.v1.Event0\x01B?Z=github.com/monitor-agent/proto/monitoragent/v1;monitoragentv1b\x06proto3"
+
+var (
+	file_monitoragent_v1_monitor_agent_proto_rawDescOnce sync.Once
+	file_monitoragent_v1_monitor_agent_proto_rawDescData []byte
+)
+
+func file_monitoragent_v1_monitor_agent_proto_rawDescGZIP() []byte {
+	file_monitoragent_v1_monitor_agent_proto_rawDescOnce.Do(func() {
+		file_monitoragent_v1_monitor_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_monitoragent_v1_monitor_agent_proto_rawDesc), len(file_monitoragent_v1_monitor_agent_proto_rawDesc)))
+	})
+	return file_monitoragent_v1_monitor_agent_proto_rawDescData
+}
+
+var file_monitoragent_v1_monitor_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_monitoragent_v1_monitor_agent_proto_goTypes = []any{
+	(*Program)(nil),               // 0: monitoragent.v1.Program
+	(*Asset)(nil),                 // 1: monitoragent.v1.Asset
+	(*ResponseCluster)(nil),       // 2: monitoragent.v1.ResponseCluster
+	(*Scan)(nil),                  // 3: monitoragent.v1.Scan
+	(*ListProgramsRequest)(nil),   // 4: monitoragent.v1.ListProgramsRequest
+	(*ListProgramsResponse)(nil),  // 5: monitoragent.v1.ListProgramsResponse
+	(*ListAssetsRequest)(nil),     // 6: monitoragent.v1.ListAssetsRequest
+	(*FindAssetsRequest)(nil),     // 7: monitoragent.v1.FindAssetsRequest
+	(*ListAssetsResponse)(nil),    // 8: monitoragent.v1.ListAssetsResponse
+	(*ListClustersRequest)(nil),   // 9: monitoragent.v1.ListClustersRequest
+	(*ListClustersResponse)(nil),  // 10: monitoragent.v1.ListClustersResponse
+	(*GetStatsRequest)(nil),       // 11: monitoragent.v1.GetStatsRequest
+	(*LivenessCount)(nil),         // 12: monitoragent.v1.LivenessCount
+	(*Stats)(nil),                 // 13: monitoragent.v1.Stats
+	(*TriggerScanRequest)(nil),    // 14: monitoragent.v1.TriggerScanRequest
+	(*TriggerScanResponse)(nil),   // 15: monitoragent.v1.TriggerScanResponse
+	(*CancelScanRequest)(nil),     // 16: monitoragent.v1.CancelScanRequest
+	(*CancelScanResponse)(nil),    // 17: monitoragent.v1.CancelScanResponse
+	(*StreamEventsRequest)(nil),   // 18: monitoragent.v1.StreamEventsRequest
+	(*Event)(nil),                 // 19: monitoragent.v1.Event
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 21: google.protobuf.Struct
+}
+var file_monitoragent_v1_monitor_agent_proto_depIdxs = []int32{
+	20, // 0: monitoragent.v1.Program.last_updated:type_name -> google.protobuf.Timestamp
+	20, // 1: monitoragent.v1.Asset.last_probed_at:type_name -> google.protobuf.Timestamp
+	20, // 2: monitoragent.v1.Asset.created_at:type_name -> google.protobuf.Timestamp
+	20, // 3: monitoragent.v1.ResponseCluster.created_at:type_name -> google.protobuf.Timestamp
+	20, // 4: monitoragent.v1.Scan.started_at:type_name -> google.protobuf.Timestamp
+	20, // 5: monitoragent.v1.Scan.completed_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: monitoragent.v1.ListProgramsResponse.programs:type_name -> monitoragent.v1.Program
+	1,  // 7: monitoragent.v1.ListAssetsResponse.assets:type_name -> monitoragent.v1.Asset
+	2,  // 8: monitoragent.v1.ListClustersResponse.clusters:type_name -> monitoragent.v1.ResponseCluster
+	12, // 9: monitoragent.v1.Stats.liveness:type_name -> monitoragent.v1.LivenessCount
+	3,  // 10: monitoragent.v1.Stats.recent_scans:type_name -> monitoragent.v1.Scan
+	0,  // 11: monitoragent.v1.TriggerScanResponse.program:type_name -> monitoragent.v1.Program
+	20, // 12: monitoragent.v1.Event.time:type_name -> google.protobuf.Timestamp
+	21, // 13: monitoragent.v1.Event.data:type_name -> google.protobuf.Struct
+	4,  // 14: monitoragent.v1.MonitorAgent.ListPrograms:input_type -> monitoragent.v1.ListProgramsRequest
+	6,  // 15: monitoragent.v1.MonitorAgent.ListAssets:input_type -> monitoragent.v1.ListAssetsRequest
+	7,  // 16: monitoragent.v1.MonitorAgent.FindAssets:input_type -> monitoragent.v1.FindAssetsRequest
+	9,  // 17: monitoragent.v1.MonitorAgent.ListClusters:input_type -> monitoragent.v1.ListClustersRequest
+	11, // 18: monitoragent.v1.MonitorAgent.GetStats:input_type -> monitoragent.v1.GetStatsRequest
+	14, // 19: monitoragent.v1.MonitorAgent.TriggerScan:input_type -> monitoragent.v1.TriggerScanRequest
+	16, // 20: monitoragent.v1.MonitorAgent.CancelScan:input_type -> monitoragent.v1.CancelScanRequest
+	18, // 21: monitoragent.v1.MonitorAgent.StreamEvents:input_type -> monitoragent.v1.StreamEventsRequest
+	5,  // 22: monitoragent.v1.MonitorAgent.ListPrograms:output_type -> monitoragent.v1.ListProgramsResponse
+	8,  // 23: monitoragent.v1.MonitorAgent.ListAssets:output_type -> monitoragent.v1.ListAssetsResponse
+	8,  // 24: monitoragent.v1.MonitorAgent.FindAssets:output_type -> monitoragent.v1.ListAssetsResponse
+	10, // 25: monitoragent.v1.MonitorAgent.ListClusters:output_type -> monitoragent.v1.ListClustersResponse
+	13, // 26: monitoragent.v1.MonitorAgent.GetStats:output_type -> monitoragent.v1.Stats
+	15, // 27: monitoragent.v1.MonitorAgent.TriggerScan:output_type -> monitoragent.v1.TriggerScanResponse
+	17, // 28: monitoragent.v1.MonitorAgent.CancelScan:output_type -> monitoragent.v1.CancelScanResponse
+	19, // 29: monitoragent.v1.MonitorAgent.StreamEvents:output_type -> monitoragent.v1.Event
+	22, // [22:30] is the sub-list for method output_type
+	14, // [14:22] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_monitoragent_v1_monitor_agent_proto_init() }
+func file_monitoragent_v1_monitor_agent_proto_init() {
+	if File_monitoragent_v1_monitor_agent_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_monitoragent_v1_monitor_agent_proto_rawDesc), len(file_monitoragent_v1_monitor_agent_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_monitoragent_v1_monitor_agent_proto_goTypes,
+		DependencyIndexes: file_monitoragent_v1_monitor_agent_proto_depIdxs,
+		MessageInfos:      file_monitoragent_v1_monitor_agent_proto_msgTypes,
+	}.Build()
+	File_monitoragent_v1_monitor_agent_proto = out.File
+	file_monitoragent_v1_monitor_agent_proto_goTypes = nil
+	file_monitoragent_v1_monitor_agent_proto_depIdxs = nil
+}
@@ -0,0 +1,423 @@
+// gRPC API of the monitor agent for internal services. Regenerate the Go code
+// with `make proto` after changing this file; other languages can generate
+// their clients from it directly.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: monitoragent/v1/monitor_agent.proto
+
+package monitoragentv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MonitorAgent_ListPrograms_FullMethodName = "/monitoragent.v1.MonitorAgent/ListPrograms"
+	MonitorAgent_ListAssets_FullMethodName   = "/monitoragent.v1.MonitorAgent/ListAssets"
+	MonitorAgent_FindAssets_FullMethodName   = "/monitoragent.v1.MonitorAgent/FindAssets"
+	MonitorAgent_ListClusters_FullMethodName = "/monitoragent.v1.MonitorAgent/ListClusters"
+	MonitorAgent_GetStats_FullMethodName     = "/monitoragent.v1.MonitorAgent/GetStats"
+	MonitorAgent_TriggerScan_FullMethodName  = "/monitoragent.v1.MonitorAgent/TriggerScan"
+	MonitorAgent_CancelScan_FullMethodName   = "/monitoragent.v1.MonitorAgent/CancelScan"
+	MonitorAgent_StreamEvents_FullMethodName = "/monitoragent.v1.MonitorAgent/StreamEvents"
+)
+
+// MonitorAgentClient is the client API for MonitorAgent service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MonitorAgent queries programs and assets, triggers and cancels scans, and
+// streams the events scans emit. Every call needs an `authorization: Bearer
+// <GRPC_TOKEN>` metadata entry.
+type MonitorAgentClient interface {
+	// ListPrograms lists the active programs
+	ListPrograms(ctx context.Context, in *ListProgramsRequest, opts ...grpc.CallOption) (*ListProgramsResponse, error)
+	// ListAssets lists assets matching an asset query, e.g. "program:acme liveness:live"
+	ListAssets(ctx context.Context, in *ListAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error)
+	// FindAssets lists the assets of a host and its subdomains
+	FindAssets(ctx context.Context, in *FindAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error)
+	// ListClusters lists groups of live assets with near-identical responses,
+	// largest first; ListAssets with "cluster:<id>" lists a group's assets
+	ListClusters(ctx context.Context, in *ListClustersRequest, opts ...grpc.CallOption) (*ListClustersResponse, error)
+	// GetStats summarizes programs, assets and recent scans
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error)
+	// TriggerScan starts a scan of one program and returns once it is started
+	TriggerScan(ctx context.Context, in *TriggerScanRequest, opts ...grpc.CallOption) (*TriggerScanResponse, error)
+	// CancelScan cancels a running scan
+	CancelScan(ctx context.Context, in *CancelScanRequest, opts ...grpc.CallOption) (*CancelScanResponse, error)
+	// StreamEvents streams the events emitted by this agent from now on, e.g.
+	// asset.discovered for every new asset
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type monitorAgentClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMonitorAgentClient(cc grpc.ClientConnInterface) MonitorAgentClient {
+	return &monitorAgentClient{cc}
+}
+
+func (c *monitorAgentClient) ListPrograms(ctx context.Context, in *ListProgramsRequest, opts ...grpc.CallOption) (*ListProgramsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProgramsResponse)
+	err := c.cc.Invoke(ctx, MonitorAgent_ListPrograms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorAgentClient) ListAssets(ctx context.Context, in *ListAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAssetsResponse)
+	err := c.cc.Invoke(ctx, MonitorAgent_ListAssets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorAgentClient) FindAssets(ctx context.Context, in *FindAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAssetsResponse)
+	err := c.cc.Invoke(ctx, MonitorAgent_FindAssets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorAgentClient) ListClusters(ctx context.Context, in *ListClustersRequest, opts ...grpc.CallOption) (*ListClustersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClustersResponse)
+	err := c.cc.Invoke(ctx, MonitorAgent_ListClusters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorAgentClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, MonitorAgent_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorAgentClient) TriggerScan(ctx context.Context, in *TriggerScanRequest, opts ...grpc.CallOption) (*TriggerScanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerScanResponse)
+	err := c.cc.Invoke(ctx, MonitorAgent_TriggerScan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorAgentClient) CancelScan(ctx context.Context, in *CancelScanRequest, opts ...grpc.CallOption) (*CancelScanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelScanResponse)
+	err := c.cc.Invoke(ctx, MonitorAgent_CancelScan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monitorAgentClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MonitorAgent_ServiceDesc.Streams[0], MonitorAgent_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MonitorAgent_StreamEventsClient = grpc.ServerStreamingClient[Event]
+
+// MonitorAgentServer is the server API for MonitorAgent service.
+// All implementations must embed UnimplementedMonitorAgentServer
+// for forward compatibility.
+//
+// MonitorAgent queries programs and assets, triggers and cancels scans, and
+// streams the events scans emit. Every call needs an `authorization: Bearer
+// <GRPC_TOKEN>` metadata entry.
+type MonitorAgentServer interface {
+	// ListPrograms lists the active programs
+	ListPrograms(context.Context, *ListProgramsRequest) (*ListProgramsResponse, error)
+	// ListAssets lists assets matching an asset query, e.g. "program:acme liveness:live"
+	ListAssets(context.Context, *ListAssetsRequest) (*ListAssetsResponse, error)
+	// FindAssets lists the assets of a host and its subdomains
+	FindAssets(context.Context, *FindAssetsRequest) (*ListAssetsResponse, error)
+	// ListClusters lists groups of live assets with near-identical responses,
+	// largest first; ListAssets with "cluster:<id>" lists a group's assets
+	ListClusters(context.Context, *ListClustersRequest) (*ListClustersResponse, error)
+	// GetStats summarizes programs, assets and recent scans
+	GetStats(context.Context, *GetStatsRequest) (*Stats, error)
+	// TriggerScan starts a scan of one program and returns once it is started
+	TriggerScan(context.Context, *TriggerScanRequest) (*TriggerScanResponse, error)
+	// CancelScan cancels a running scan
+	CancelScan(context.Context, *CancelScanRequest) (*CancelScanResponse, error)
+	// StreamEvents streams the events emitted by this agent from now on, e.g.
+	// asset.discovered for every new asset
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedMonitorAgentServer()
+}
+
+// UnimplementedMonitorAgentServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMonitorAgentServer struct{}
+
+func (UnimplementedMonitorAgentServer) ListPrograms(context.Context, *ListProgramsRequest) (*ListProgramsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPrograms not implemented")
+}
+func (UnimplementedMonitorAgentServer) ListAssets(context.Context, *ListAssetsRequest) (*ListAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAssets not implemented")
+}
+func (UnimplementedMonitorAgentServer) FindAssets(context.Context, *FindAssetsRequest) (*ListAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindAssets not implemented")
+}
+func (UnimplementedMonitorAgentServer) ListClusters(context.Context, *ListClustersRequest) (*ListClustersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClusters not implemented")
+}
+func (UnimplementedMonitorAgentServer) GetStats(context.Context, *GetStatsRequest) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedMonitorAgentServer) TriggerScan(context.Context, *TriggerScanRequest) (*TriggerScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerScan not implemented")
+}
+func (UnimplementedMonitorAgentServer) CancelScan(context.Context, *CancelScanRequest) (*CancelScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScan not implemented")
+}
+func (UnimplementedMonitorAgentServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedMonitorAgentServer) mustEmbedUnimplementedMonitorAgentServer() {}
+func (UnimplementedMonitorAgentServer) testEmbeddedByValue()                      {}
+
+// UnsafeMonitorAgentServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MonitorAgentServer will
+// result in compilation errors.
+type UnsafeMonitorAgentServer interface {
+	mustEmbedUnimplementedMonitorAgentServer()
+}
+
+func RegisterMonitorAgentServer(s grpc.ServiceRegistrar, srv MonitorAgentServer) {
+	// If the following call pancis, it indicates UnimplementedMonitorAgentServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MonitorAgent_ServiceDesc, srv)
+}
+
+func _MonitorAgent_ListPrograms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProgramsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorAgentServer).ListPrograms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorAgent_ListPrograms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorAgentServer).ListPrograms(ctx, req.(*ListProgramsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorAgent_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorAgentServer).ListAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorAgent_ListAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorAgentServer).ListAssets(ctx, req.(*ListAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorAgent_FindAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorAgentServer).FindAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorAgent_FindAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorAgentServer).FindAssets(ctx, req.(*FindAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorAgent_ListClusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClustersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorAgentServer).ListClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorAgent_ListClusters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorAgentServer).ListClusters(ctx, req.(*ListClustersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorAgent_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorAgentServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorAgent_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorAgentServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorAgent_TriggerScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorAgentServer).TriggerScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorAgent_TriggerScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorAgentServer).TriggerScan(ctx, req.(*TriggerScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorAgent_CancelScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorAgentServer).CancelScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonitorAgent_CancelScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorAgentServer).CancelScan(ctx, req.(*CancelScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonitorAgent_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MonitorAgentServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MonitorAgent_StreamEventsServer = grpc.ServerStreamingServer[Event]
+
+// MonitorAgent_ServiceDesc is the grpc.ServiceDesc for MonitorAgent service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MonitorAgent_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "monitoragent.v1.MonitorAgent",
+	HandlerType: (*MonitorAgentServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPrograms",
+			Handler:    _MonitorAgent_ListPrograms_Handler,
+		},
+		{
+			MethodName: "ListAssets",
+			Handler:    _MonitorAgent_ListAssets_Handler,
+		},
+		{
+			MethodName: "FindAssets",
+			Handler:    _MonitorAgent_FindAssets_Handler,
+		},
+		{
+			MethodName: "ListClusters",
+			Handler:    _MonitorAgent_ListClusters_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _MonitorAgent_GetStats_Handler,
+		},
+		{
+			MethodName: "TriggerScan",
+			Handler:    _MonitorAgent_TriggerScan_Handler,
+		},
+		{
+			MethodName: "CancelScan",
+			Handler:    _MonitorAgent_CancelScan_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _MonitorAgent_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "monitoragent/v1/monitor_agent.proto",
+}
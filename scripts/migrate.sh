@@ -73,15 +73,18 @@ validate_connection() {
 run_migrations() {
     print_status "Running database migrations..."
     
-    # Check if migration file exists
-    MIGRATION_FILE="internal/database/migrations/001_initial_schema.sql"
-    if [ ! -f "$MIGRATION_FILE" ]; then
-        print_error "Migration file not found: $MIGRATION_FILE"
+    # Apply every pending migration with the agent's own migration runner,
+    # which records them in schema_migrations and holds the migration lock
+    if [ -x "build/monitor-agent" ]; then
+        MIGRATE_CMD=(build/monitor-agent migrate up)
+    elif command_exists go; then
+        MIGRATE_CMD=(go run ./cmd/monitor-agent migrate up)
+    else
+        print_error "Build the agent with 'make build' or install Go to run migrations"
         exit 1
     fi
     
-    # Run migration
-    if psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -f "$MIGRATION_FILE"; then
+    if "${MIGRATE_CMD[@]}"; then
         print_success "Database migrations completed successfully"
     else
         print_error "Failed to run database migrations"